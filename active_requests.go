@@ -0,0 +1,232 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	nhttp "net/http"
+	"runtime/pprof"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultActiveRequestsPath = "/debug/requests"
+
+	// RequestCancelledReason is the reason of errors returned by requests cancelled with CancelRequest.
+	RequestCancelledReason = "REQUEST_CANCELLED"
+)
+
+// errRequestCancelled is the cancellation cause of requests cancelled with CancelRequest.
+var errRequestCancelled = stderrors.New("request cancelled by operator")
+
+// ActiveRequest describes an in-flight request.
+type ActiveRequest struct {
+	// ID identifies the request for CancelRequest.
+	ID        string
+	Operation string
+	Method    string
+	Path      string
+	ClientIP  string
+	TraceID   string
+	StartedAt time.Time
+	// Duration is how long the request had been running when it was listed.
+	Duration time.Duration
+}
+
+// activeRequest is a tracked request and the function cancelling its context.
+type activeRequest struct {
+	info   ActiveRequest
+	cancel context.CancelCauseFunc
+	// timeout is the request's deadline budget, 0 when it has none; stuck is set once the watchdog
+	// reported it. Both are guarded by the set's mutex.
+	timeout time.Duration
+	stuck   bool
+}
+
+// activeRequestSet holds the tracked requests of the server.
+type activeRequestSet struct {
+	nextID atomic.Uint64
+
+	mu       sync.Mutex
+	requests map[string]*activeRequest
+}
+
+func (s *activeRequestSet) add(info ActiveRequest, timeout time.Duration, cancel context.CancelCauseFunc) string {
+	info.ID = strconv.FormatUint(s.nextID.Add(1), 10)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.requests == nil {
+		s.requests = make(map[string]*activeRequest)
+	}
+	s.requests[info.ID] = &activeRequest{info: info, cancel: cancel, timeout: timeout}
+	return info.ID
+}
+
+func (s *activeRequestSet) remove(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.requests, id)
+}
+
+// ActiveRequests returns the in-flight requests, longest running first. It is empty unless
+// monitoring.active_requests is enabled.
+func (h *ServiceHttp) ActiveRequests() []ActiveRequest {
+	now := time.Now()
+	h.activeRequests.mu.Lock()
+	out := make([]ActiveRequest, 0, len(h.activeRequests.requests))
+	for _, r := range h.activeRequests.requests {
+		info := r.info
+		info.Duration = now.Sub(info.StartedAt)
+		out = append(out, info)
+	}
+	h.activeRequests.mu.Unlock()
+	slices.SortFunc(out, func(a, b ActiveRequest) int { return a.StartedAt.Compare(b.StartedAt) })
+	return out
+}
+
+// CancelRequest cancels the context of the in-flight request with the given ID, e.g. a runaway export. The
+// request fails with REQUEST_CANCELLED once its handler observes the cancellation. It reports whether the
+// request was found.
+func (h *ServiceHttp) CancelRequest(id string) bool {
+	h.activeRequests.mu.Lock()
+	r, ok := h.activeRequests.requests[id]
+	h.activeRequests.mu.Unlock()
+	if !ok {
+		return false
+	}
+	log.Warnf("Cancelling request %s: %s from %s", id, r.info.Operation, r.info.ClientIP)
+	r.cancel(errRequestCancelled)
+	return true
+}
+
+// activeRequestsMiddleware tracks each request until its handler returns and gives it a context that
+// CancelRequest can cancel. With the stuck request watchdog, handlers run with a goroutine label naming the
+// request, which goroutines they start inherit, so the watchdog can find their stacks.
+func (h *ServiceHttp) activeRequestsMiddleware() middleware.Middleware {
+	labelled := h.conf.GetMonitoring().GetActiveRequests().GetStuck().GetEnabled()
+	serverTimeout := h.conf.GetTimeout().AsDuration()
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			info := ActiveRequest{StartedAt: time.Now()}
+			if tr, ok := transport.FromServerContext(ctx); ok {
+				info.Operation = tr.Operation()
+				info.ClientIP = getClientIP(ctx, tr.RequestHeader())
+			}
+			if r, ok := http.RequestFromServerContext(ctx); ok && r != nil {
+				info.Method, info.Path = r.Method, r.URL.Path
+			}
+			if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+				info.TraceID = sc.TraceID().String()
+			}
+			timeout := serverTimeout
+			if deadline, ok := ctx.Deadline(); ok {
+				timeout = deadline.Sub(info.StartedAt)
+			}
+
+			ctx, cancel := context.WithCancelCause(ctx)
+			id := h.activeRequests.add(info, timeout, cancel)
+			defer func() {
+				h.activeRequests.remove(id)
+				cancel(nil)
+			}()
+
+			var (
+				reply any
+				err   error
+			)
+			if labelled {
+				pprof.Do(ctx, pprof.Labels(stuckRequestLabel, id), func(ctx context.Context) {
+					reply, err = handler(ctx, req)
+				})
+			} else {
+				reply, err = handler(ctx, req)
+			}
+			if err != nil {
+				switch cause := context.Cause(ctx); {
+				case stderrors.Is(cause, errRequestCancelled):
+					return nil, errors.ServiceUnavailable(RequestCancelledReason, "request cancelled")
+				case stderrors.Is(cause, errRequestStuck):
+					return nil, errors.GatewayTimeout(RequestStuckReason, "request cancelled by the stuck request watchdog")
+				}
+			}
+			return reply, err
+		}
+	}
+}
+
+// mountActiveRequests serves the active requests admin endpoint when it is enabled.
+func (h *ServiceHttp) mountActiveRequests() {
+	cfg := h.conf.GetMonitoring().GetActiveRequests()
+	if !cfg.GetEnabled() || !cfg.GetEndpointEnabled() {
+		return
+	}
+	path := strings.TrimSpace(cfg.GetPath())
+	if path == "" {
+		path = defaultActiveRequestsPath
+	}
+	h.server.HandlePrefix(path, &netHTTPToKratosHandlerAdapter{handler: h.activeRequestsHandler()})
+	log.Infof("Active requests endpoint mounted at %s", path)
+}
+
+// activeRequestBody is the JSON form of an active request on the admin endpoint.
+type activeRequestBody struct {
+	ID        string `json:"id"`
+	Operation string `json:"operation"`
+	Method    string `json:"method,omitempty"`
+	Path      string `json:"path,omitempty"`
+	ClientIP  string `json:"client_ip,omitempty"`
+	TraceID   string `json:"trace_id,omitempty"`
+	StartedAt string `json:"started_at"`
+	Duration  string `json:"duration"`
+}
+
+// activeRequestsHandler lists (GET) in-flight requests and cancels (DELETE ?id=) one of them.
+func (h *ServiceHttp) activeRequestsHandler() nhttp.Handler {
+	return nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case nhttp.MethodGet:
+			active := h.ActiveRequests()
+			out := make([]activeRequestBody, 0, len(active))
+			for _, a := range active {
+				out = append(out, activeRequestBody{
+					ID:        a.ID,
+					Operation: a.Operation,
+					Method:    a.Method,
+					Path:      a.Path,
+					ClientIP:  a.ClientIP,
+					TraceID:   a.TraceID,
+					StartedAt: a.StartedAt.UTC().Format(time.RFC3339Nano),
+					Duration:  a.Duration.Round(time.Millisecond).String(),
+				})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"requests": out})
+		case nhttp.MethodDelete:
+			id := r.URL.Query().Get("id")
+			if id == "" {
+				writeLogBoostError(w, nhttp.StatusBadRequest, "id is required")
+				return
+			}
+			if !h.CancelRequest(id) {
+				writeLogBoostError(w, nhttp.StatusNotFound, "request not found")
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"cancelled": id})
+		default:
+			w.Header().Set("Allow", "GET, DELETE")
+			writeLogBoostError(w, nhttp.StatusMethodNotAllowed, "method not allowed")
+		}
+	})
+}
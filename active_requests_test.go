@@ -0,0 +1,64 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	nhttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActiveRequests_ListAndCancel(t *testing.T) {
+	h := &ServiceHttp{}
+	started := make(chan struct{})
+	done := make(chan error, 1)
+	handler := h.activeRequestsMiddleware()(func(ctx context.Context, _ any) (any, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	go func() {
+		ctx := transport.NewServerContext(context.Background(), newFakeTransport("/api.v1.Reports/Export", nil))
+		_, err := handler(ctx, nil)
+		done <- err
+	}()
+	<-started
+
+	admin := h.activeRequestsHandler()
+	rec := httptest.NewRecorder()
+	admin.ServeHTTP(rec, httptest.NewRequest(nhttp.MethodGet, "/debug/requests", nil))
+	var listed struct {
+		Requests []activeRequestBody `json:"requests"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listed))
+	require.Len(t, listed.Requests, 1)
+	assert.Equal(t, "/api.v1.Reports/Export", listed.Requests[0].Operation)
+
+	rec = httptest.NewRecorder()
+	admin.ServeHTTP(rec, httptest.NewRequest(nhttp.MethodDelete, "/debug/requests?id=missing", nil))
+	assert.Equal(t, nhttp.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	admin.ServeHTTP(rec, httptest.NewRequest(nhttp.MethodDelete, "/debug/requests?id="+listed.Requests[0].ID, nil))
+	assert.Equal(t, nhttp.StatusOK, rec.Code)
+
+	err := <-done
+	assert.Equal(t, RequestCancelledReason, errors.Reason(err))
+	assert.Empty(t, h.ActiveRequests(), "finished requests are no longer listed")
+}
+
+func TestActiveRequests_NotCancelledKeepsError(t *testing.T) {
+	h := &ServiceHttp{}
+	want := errors.NotFound("NOT_FOUND", "")
+	_, err := h.activeRequestsMiddleware()(func(context.Context, any) (any, error) {
+		assert.Len(t, h.ActiveRequests(), 1)
+		return nil, want
+	})(context.Background(), nil)
+	assert.Equal(t, want, err)
+	assert.False(t, h.CancelRequest("1"))
+}
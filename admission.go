@@ -0,0 +1,201 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-lynx/lynx-http/conf"
+)
+
+const (
+	defaultAdmissionMaxWait = 100 * time.Millisecond
+
+	admissionReasonQueueFull = "queue_full"
+	admissionReasonTimeout   = "timeout"
+
+	// busyReason is the Kratos error reason returned when a request is shed by an admission queue.
+	busyReason = "SERVER_BUSY"
+)
+
+// admissionQueue bounds the concurrency of an operation group with a limited wait queue.
+type admissionQueue struct {
+	name     string
+	slots    chan struct{}
+	maxQueue int32
+	maxWait  time.Duration
+	waiting  atomic.Int32
+}
+
+// acquire takes a slot, waiting in the queue when the group is saturated. The returned reason is
+// non-empty when the request was shed.
+func (q *admissionQueue) acquire(ctx context.Context, onWait func(delta int)) (string, error) {
+	select {
+	case q.slots <- struct{}{}:
+		return "", nil
+	default:
+	}
+	if q.waiting.Add(1) > q.maxQueue {
+		q.waiting.Add(-1)
+		return admissionReasonQueueFull, nil
+	}
+	onWait(1)
+	defer func() {
+		q.waiting.Add(-1)
+		onWait(-1)
+	}()
+
+	timer := time.NewTimer(q.maxWait)
+	defer timer.Stop()
+	select {
+	case q.slots <- struct{}{}:
+		return "", nil
+	case <-timer.C:
+		return admissionReasonTimeout, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func (q *admissionQueue) release() {
+	<-q.slots
+}
+
+type admissionPrefix struct {
+	prefix string
+	queue  *admissionQueue
+}
+
+// admissionQueues resolves an operation to its group queue: exact names first, then the longest prefix.
+type admissionQueues struct {
+	exact    map[string]*admissionQueue
+	prefixes []admissionPrefix
+}
+
+func newAdmissionQueues(cfgs []*conf.AdmissionQueueConfig) *admissionQueues {
+	qs := &admissionQueues{exact: make(map[string]*admissionQueue)}
+	for _, cfg := range cfgs {
+		q := &admissionQueue{
+			name:     strings.TrimSpace(cfg.Name),
+			slots:    make(chan struct{}, cfg.MaxConcurrency),
+			maxQueue: cfg.MaxQueue,
+			maxWait:  defaultAdmissionMaxWait,
+		}
+		if d := cfg.GetMaxWait().AsDuration(); d > 0 {
+			q.maxWait = d
+		}
+		for _, op := range cfg.Operations {
+			op = strings.TrimSpace(op)
+			if prefix, ok := strings.CutSuffix(op, "*"); ok {
+				qs.prefixes = append(qs.prefixes, admissionPrefix{prefix: prefix, queue: q})
+				continue
+			}
+			qs.exact[op] = q
+		}
+	}
+	sort.SliceStable(qs.prefixes, func(i, j int) bool {
+		return len(qs.prefixes[i].prefix) > len(qs.prefixes[j].prefix)
+	})
+	return qs
+}
+
+func (qs *admissionQueues) queueFor(operation string) *admissionQueue {
+	if qs == nil {
+		return nil
+	}
+	if q, ok := qs.exact[operation]; ok {
+		return q
+	}
+	for _, p := range qs.prefixes {
+		if strings.HasPrefix(operation, p.prefix) {
+			return p.queue
+		}
+	}
+	return nil
+}
+
+// ensureAdmissionQueues builds the admission queues from configuration once; Configure resets them.
+func (h *ServiceHttp) ensureAdmissionQueues() *admissionQueues {
+	h.confMu.RLock()
+	qs := h.admissionQueues
+	h.confMu.RUnlock()
+	if qs != nil {
+		return qs
+	}
+
+	h.confMu.Lock()
+	defer h.confMu.Unlock()
+	if h.admissionQueues == nil {
+		var cfgs []*conf.AdmissionQueueConfig
+		if h.conf != nil && h.conf.Performance != nil {
+			cfgs = h.conf.Performance.AdmissionQueues
+		}
+		h.admissionQueues = newAdmissionQueues(cfgs)
+	}
+	return h.admissionQueues
+}
+
+// admissionQueueMiddleware sheds requests of saturated operation groups with a busy error instead of
+// letting them pile up unbounded.
+func (h *ServiceHttp) admissionQueueMiddleware() middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (reply any, err error) {
+			method, path := requestMetadata(ctx)
+			q := h.ensureAdmissionQueues().queueFor(path)
+			if q == nil {
+				return handler(ctx, req)
+			}
+
+			reason, err := q.acquire(ctx, func(delta int) {
+				if h.admissionQueueDepth != nil {
+					h.admissionQueueDepth.WithLabelValues(q.name).Add(float64(delta))
+				}
+			})
+			if err != nil {
+				return nil, err
+			}
+			if reason != "" {
+				if h.admissionRejections != nil {
+					h.admissionRejections.WithLabelValues(q.name, reason).Inc()
+				}
+				h.recordErrorMetric(method, path, "admission_"+reason)
+				return nil, errors.ServiceUnavailable(busyReason, fmt.Sprintf("server busy: %s admission queue %s", q.name, reason))
+			}
+			defer q.release()
+			return handler(ctx, req)
+		}
+	}
+}
+
+// validateAdmissionQueues checks that every group is named, bounded and owns at least one operation.
+func validateAdmissionQueues(cfgs []*conf.AdmissionQueueConfig) error {
+	names := make(map[string]struct{}, len(cfgs))
+	for i, cfg := range cfgs {
+		name := strings.TrimSpace(cfg.Name)
+		if name == "" {
+			return fmt.Errorf("admission queue %d: name is required", i)
+		}
+		if _, dup := names[name]; dup {
+			return fmt.Errorf("admission queue %s: duplicate name", name)
+		}
+		names[name] = struct{}{}
+		if len(cfg.Operations) == 0 {
+			return fmt.Errorf("admission queue %s: at least one operation is required", name)
+		}
+		if cfg.MaxConcurrency <= 0 {
+			return fmt.Errorf("admission queue %s: max concurrency must be positive", name)
+		}
+		if cfg.MaxQueue < 0 {
+			return fmt.Errorf("admission queue %s: max queue cannot be negative", name)
+		}
+		if cfg.MaxWait != nil && cfg.MaxWait.AsDuration() < 0 {
+			return fmt.Errorf("admission queue %s: max wait cannot be negative", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package http
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestAdmissionQueues_QueueFor(t *testing.T) {
+	qs := newAdmissionQueues([]*conf.AdmissionQueueConfig{
+		{Name: "reports", Operations: []string{"/api.v1.Reports/*"}, MaxConcurrency: 1},
+		{Name: "export", Operations: []string{"/api.v1.Reports/Export*", "/api.v1.Users/Dump"}, MaxConcurrency: 1},
+	})
+
+	assert.Equal(t, "reports", qs.queueFor("/api.v1.Reports/List").name)
+	assert.Equal(t, "export", qs.queueFor("/api.v1.Reports/ExportCSV").name, "longest prefix wins")
+	assert.Equal(t, "export", qs.queueFor("/api.v1.Users/Dump").name)
+	assert.Nil(t, qs.queueFor("/api.v1.Users/Get"))
+	assert.Nil(t, (*admissionQueues)(nil).queueFor("/any"))
+}
+
+func TestAdmissionQueue_Acquire(t *testing.T) {
+	q := newAdmissionQueues([]*conf.AdmissionQueueConfig{{
+		Name: "g", Operations: []string{"/op"}, MaxConcurrency: 1, MaxQueue: 1,
+		MaxWait: durationpb.New(20 * time.Millisecond),
+	}}).queueFor("/op")
+	noop := func(int) {}
+
+	reason, err := q.acquire(context.Background(), noop)
+	require.NoError(t, err)
+	assert.Empty(t, reason)
+
+	// Saturated: the next request waits and times out.
+	reason, err = q.acquire(context.Background(), noop)
+	require.NoError(t, err)
+	assert.Equal(t, admissionReasonTimeout, reason)
+
+	// A waiter is admitted once the slot is released.
+	done := make(chan string)
+	go func() {
+		r, _ := q.acquire(context.Background(), noop)
+		done <- r
+	}()
+	require.Eventually(t, func() bool { return q.waiting.Load() == 1 }, time.Second, time.Millisecond)
+
+	// The queue holds one waiter, so a further request fails fast.
+	reason, err = q.acquire(context.Background(), noop)
+	require.NoError(t, err)
+	assert.Equal(t, admissionReasonQueueFull, reason)
+
+	q.release()
+	assert.Empty(t, <-done)
+	q.release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _ = q.acquire(context.Background(), noop)
+	_, err = q.acquire(ctx, noop)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestAdmissionQueueMiddleware_Busy(t *testing.T) {
+	svc := NewServiceHttp()
+	svc.conf = &conf.Http{Performance: &conf.PerformanceConfig{AdmissionQueues: []*conf.AdmissionQueueConfig{
+		{Name: "slow", Operations: []string{"/api.v1.Slow/*"}, MaxConcurrency: 1},
+	}}}
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	handler := svc.admissionQueueMiddleware()(func(context.Context, any) (any, error) {
+		close(started)
+		<-release
+		return "ok", nil
+	})
+	ctx := transport.NewServerContext(context.Background(), newFakeTransport("/api.v1.Slow/Run", nil))
+
+	go func() { _, _ = handler(ctx, nil) }()
+	<-started
+	_, err := handler(ctx, nil)
+	require.Error(t, err)
+	assert.Equal(t, 503, int(errors.Code(err)))
+	assert.Equal(t, busyReason, errors.Reason(err))
+	close(release)
+
+	// Operations outside any group pass straight through.
+	other := transport.NewServerContext(context.Background(), newFakeTransport("/api.v1.Fast/Run", nil))
+	reply, err := svc.admissionQueueMiddleware()(func(context.Context, any) (any, error) { return "ok", nil })(other, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", reply)
+}
+
+func TestValidateAdmissionQueues(t *testing.T) {
+	require.NoError(t, validateAdmissionQueues(nil))
+	bad := [][]*conf.AdmissionQueueConfig{
+		{{Operations: []string{"/op"}, MaxConcurrency: 1}},
+		{{Name: "a", MaxConcurrency: 1}},
+		{{Name: "a", Operations: []string{"/op"}}},
+		{{Name: "a", Operations: []string{"/op"}, MaxConcurrency: 1, MaxQueue: -1}},
+		{{Name: "a", Operations: []string{"/a"}, MaxConcurrency: 1}, {Name: "a", Operations: []string{"/b"}, MaxConcurrency: 1}},
+	}
+	for i, cfgs := range bad {
+		assert.Error(t, validateAdmissionQueues(cfgs), "case %d", i)
+	}
+}
@@ -0,0 +1,254 @@
+package http
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+const (
+	defaultAnomalyInterval          = time.Minute
+	defaultAnomalySigma             = 3
+	defaultAnomalyBaselineIntervals = 30
+	defaultAnomalyMinSamples        = 10
+	defaultAnomalyMinRequests       = 20
+	defaultAnomalyMaxOperations     = 1000
+
+	anomalySignalRequests  = "requests"
+	anomalySignalErrorRate = "error_rate"
+	anomalyDirectionSpike  = "spike"
+	anomalyDirectionDrop   = "drop"
+)
+
+// anomalyBaseline is an exponentially weighted mean and variance.
+type anomalyBaseline struct {
+	mean     float64
+	variance float64
+	samples  int
+}
+
+func (b *anomalyBaseline) update(x, alpha float64) {
+	if b.samples == 0 {
+		b.mean = x
+	} else {
+		diff := x - b.mean
+		incr := alpha * diff
+		b.mean += incr
+		b.variance = (1 - alpha) * (b.variance + diff*incr)
+	}
+	b.samples++
+}
+
+// anomalyOperation counts the current interval of an operation and keeps its baselines.
+type anomalyOperation struct {
+	mu        sync.Mutex
+	start     time.Time
+	requests  uint64
+	errors    uint64
+	rate      anomalyBaseline
+	errorRate anomalyBaseline
+}
+
+// anomaly is one interval deviating from its baseline.
+type anomaly struct {
+	operation string
+	signal    string
+	direction string
+	value     float64
+	baseline  float64
+	stddev    float64
+	score     float64
+}
+
+// anomalyDetector is the resolved AnomalyConfig together with the per-operation baselines. It is built per
+// configuration and cached in the monitoring snapshot.
+type anomalyDetector struct {
+	interval      time.Duration
+	sigma         float64
+	alpha         float64
+	maxGap        int
+	minSamples    int
+	minRequests   uint64
+	maxOperations int
+	operations    []string
+	// isError reports whether an error counts towards the error rate; nil uses sloUnavailable.
+	isError func(error) bool
+	now     func() time.Time
+
+	mu  sync.Mutex
+	ops map[string]*anomalyOperation
+}
+
+// newAnomalyDetector returns nil when anomaly detection is disabled.
+func newAnomalyDetector(cfg *conf.AnomalyConfig) *anomalyDetector {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	d := &anomalyDetector{
+		interval:      defaultAnomalyInterval,
+		sigma:         defaultAnomalySigma,
+		maxGap:        defaultAnomalyBaselineIntervals,
+		minSamples:    defaultAnomalyMinSamples,
+		minRequests:   defaultAnomalyMinRequests,
+		maxOperations: defaultAnomalyMaxOperations,
+		now:           time.Now,
+		ops:           make(map[string]*anomalyOperation),
+	}
+	if v := cfg.GetInterval().AsDuration(); v > 0 {
+		d.interval = v
+	}
+	if cfg.Sigma > 0 {
+		d.sigma = cfg.Sigma
+	}
+	if cfg.BaselineIntervals > 0 {
+		d.maxGap = int(cfg.BaselineIntervals)
+	}
+	d.alpha = 2 / float64(d.maxGap+1)
+	if cfg.MinSamples > 0 {
+		d.minSamples = int(cfg.MinSamples)
+	}
+	if cfg.MinRequests > 0 {
+		d.minRequests = uint64(cfg.MinRequests)
+	}
+	if cfg.MaxOperations > 0 {
+		d.maxOperations = int(cfg.MaxOperations)
+	}
+	for _, op := range cfg.GetOperations() {
+		if op = strings.TrimSpace(op); op != "" {
+			d.operations = append(d.operations, op)
+		}
+	}
+	return d
+}
+
+// operation returns the state of a watched operation, nil when it is not watched or the table is full.
+func (d *anomalyDetector) operation(name string) *anomalyOperation {
+	if len(d.operations) > 0 && !routeMatchesAny(d.operations, name, "") {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	op := d.ops[name]
+	if op == nil && len(d.ops) < d.maxOperations {
+		op = &anomalyOperation{}
+		d.ops[name] = op
+	}
+	return op
+}
+
+// record counts one finished request. When it starts a new interval, the finished ones are evaluated
+// against the baselines before they are folded in, and the deviations are returned.
+func (d *anomalyDetector) record(operation string, err error) []anomaly {
+	op := d.operation(operation)
+	if op == nil {
+		return nil
+	}
+	now := d.now()
+	op.mu.Lock()
+	defer op.mu.Unlock()
+
+	var found []anomaly
+	switch elapsed := now.Sub(op.start); {
+	case op.start.IsZero():
+		op.start = now.Truncate(d.interval)
+	case elapsed >= d.interval:
+		n := int(elapsed / d.interval)
+		found = d.closeLocked(operation, op, op.requests, op.errors)
+		// Intervals without requests are samples too; the first one reports a drop.
+		for i := range min(n-1, d.maxGap) {
+			drops := d.closeLocked(operation, op, 0, 0)
+			if i == 0 {
+				found = append(found, drops...)
+			}
+		}
+		op.start = op.start.Add(time.Duration(n) * d.interval)
+		op.requests, op.errors = 0, 0
+	}
+	op.requests++
+	isError := d.isError
+	if isError == nil {
+		isError = sloUnavailable
+	}
+	if isError(err) {
+		op.errors++
+	}
+	return found
+}
+
+// closeLocked evaluates a finished interval and folds it into the baselines.
+func (d *anomalyDetector) closeLocked(operation string, op *anomalyOperation, requests, errs uint64) []anomaly {
+	var found []anomaly
+	count := float64(requests)
+	if op.rate.samples >= d.minSamples {
+		// Request counts are at least Poisson noisy.
+		stddev := max(math.Sqrt(op.rate.variance), math.Sqrt(op.rate.mean), 1)
+		if score := (count - op.rate.mean) / stddev; math.Abs(score) > d.sigma {
+			direction := anomalyDirectionSpike
+			if score < 0 {
+				direction = anomalyDirectionDrop
+			}
+			found = append(found, anomaly{operation: operation, signal: anomalySignalRequests, direction: direction,
+				value: count, baseline: op.rate.mean, stddev: stddev, score: score})
+		}
+	}
+	op.rate.update(count, d.alpha)
+
+	if requests < d.minRequests {
+		return found
+	}
+	rate := float64(errs) / count
+	if op.errorRate.samples >= d.minSamples {
+		// Error rates are at least binomially noisy, and one error is never an anomaly on its own.
+		mean := op.errorRate.mean
+		stddev := max(math.Sqrt(op.errorRate.variance), math.Sqrt(mean*(1-mean)/count), 1/count)
+		if score := (rate - mean) / stddev; score > d.sigma {
+			found = append(found, anomaly{operation: operation, signal: anomalySignalErrorRate,
+				direction: anomalyDirectionSpike, value: rate, baseline: mean, stddev: stddev, score: score})
+		}
+	}
+	op.errorRate.update(rate, d.alpha)
+	return found
+}
+
+// recordAnomaly feeds a finished request into the anomaly detector and reports deviating intervals.
+func (h *ServiceHttp) recordAnomaly(operation string, err error) {
+	detector := h.monitoringSnapshotOrDefault().anomaly
+	if detector == nil {
+		return
+	}
+	for _, a := range detector.record(operation, err) {
+		if h.anomalies != nil {
+			h.anomalies.WithLabelValues(a.operation, a.signal, a.direction).Inc()
+		}
+		log.Warnw("msg", "[HTTP Anomaly]", "api", a.operation, "signal", a.signal, "direction", a.direction,
+			"value", a.value, "baseline", a.baseline, "stddev", a.stddev, "sigma", a.score,
+			"interval", detector.interval.String())
+	}
+}
+
+// validateAnomalyConfig rejects negative settings and empty operations.
+func validateAnomalyConfig(cfg *conf.AnomalyConfig) error {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	if cfg.GetInterval().AsDuration() < 0 {
+		return fmt.Errorf("interval cannot be negative")
+	}
+	if cfg.Sigma < 0 || math.IsNaN(cfg.Sigma) || math.IsInf(cfg.Sigma, 0) {
+		return fmt.Errorf("sigma must be a positive number")
+	}
+	if cfg.BaselineIntervals < 0 || cfg.MinSamples < 0 || cfg.MinRequests < 0 || cfg.MaxOperations < 0 {
+		return fmt.Errorf("baseline intervals, min samples, min requests and max operations cannot be negative")
+	}
+	for i, op := range cfg.GetOperations() {
+		if strings.TrimSpace(op) == "" {
+			return fmt.Errorf("operations[%d] is empty", i)
+		}
+	}
+	return nil
+}
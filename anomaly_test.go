@@ -0,0 +1,111 @@
+package http
+
+import (
+	"testing"
+	"time"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// anomalyTraffic drives d with a fake clock: interval sends requests to op, the first failing of which fail, and
+// returns what closing the previous interval found.
+func anomalyTraffic(d *anomalyDetector, op string) (interval func(requests, failing int) []anomaly, idle func(time.Duration)) {
+	now := time.Unix(1_700_000_000, 0).Truncate(time.Minute)
+	d.now = func() time.Time { return now }
+	interval = func(requests, failing int) []anomaly {
+		var found []anomaly
+		for i := range requests {
+			var err error
+			if i < failing {
+				err = kerrors.InternalServer("BOOM", "")
+			}
+			found = append(found, d.record(op, err)...)
+		}
+		now = now.Add(time.Minute)
+		return found
+	}
+	return interval, func(gap time.Duration) { now = now.Add(gap) }
+}
+
+func TestAnomalyDetector(t *testing.T) {
+	cfg := &conf.AnomalyConfig{Enabled: true, Interval: durationpb.New(time.Minute), MinSamples: 5}
+	const op = "/api.v1.Orders/List"
+	interval, idle := anomalyTraffic(newAnomalyDetector(cfg), op)
+	for i := range 10 {
+		assert.Empty(t, interval(100+i%3, 1))
+	}
+
+	// A burst of traffic is a request spike; the errors in it are an error-rate spike.
+	assert.Empty(t, interval(400, 80))
+	found := interval(100, 1)
+	require.Len(t, found, 2)
+	assert.Equal(t, anomalySignalRequests, found[0].signal)
+	assert.Equal(t, anomalyDirectionSpike, found[0].direction)
+	assert.Equal(t, 400.0, found[0].value)
+	assert.Equal(t, anomalySignalErrorRate, found[1].signal)
+	assert.InDelta(t, 0.2, found[1].value, 1e-9)
+	assert.Greater(t, found[1].score, 3.0)
+
+	// Silence is reported as one drop when traffic resumes.
+	interval, idle = anomalyTraffic(newAnomalyDetector(cfg), op)
+	for i := range 10 {
+		interval(100+i%3, 0)
+	}
+	idle(5 * time.Minute)
+	found = interval(100, 0)
+	require.Len(t, found, 1)
+	assert.Equal(t, anomalyDirectionDrop, found[0].direction)
+	assert.Zero(t, found[0].value)
+
+	assert.Nil(t, newAnomalyDetector(&conf.AnomalyConfig{Sigma: 2}))
+}
+
+func TestAnomalyDetector_Operations(t *testing.T) {
+	d := newAnomalyDetector(&conf.AnomalyConfig{Enabled: true, Operations: []string{"/api.v1.Orders/*"}, MaxOperations: 1})
+	assert.NotNil(t, d.operation("/api.v1.Orders/List"))
+	assert.Nil(t, d.operation("/api.v1.Orders/Get"))
+	assert.Nil(t, d.operation("/api.v1.Users/List"))
+}
+
+func TestRecordAnomaly(t *testing.T) {
+	h := &ServiceHttp{conf: &conf.Http{Monitoring: &conf.MonitoringConfig{
+		Anomaly:      &conf.AnomalyConfig{Enabled: true, MinSamples: 3, MinRequests: 1},
+		ErrorClasses: &conf.ErrorClassesConfig{Enabled: true, AlertClasses: []string{"dependency"}},
+	}}}
+	h.anomalies = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_anomalies_total"}, []string{"route", "signal", "direction"})
+	d := h.monitoringSnapshotOrDefault().anomaly
+	require.NotNil(t, d)
+	now := time.Unix(1_700_000_000, 0).Truncate(time.Minute)
+	d.now = func() time.Time { return now }
+
+	// Only the alerting classes feed the error rate.
+	const op = "/api.v1.Users/Get"
+	for range 4 {
+		for range 50 {
+			h.recordAnomaly(op, kerrors.InternalServer("BOOM", ""))
+		}
+		now = now.Add(time.Minute)
+	}
+	for range 50 {
+		h.recordAnomaly(op, kerrors.ServiceUnavailable("DB_DOWN", ""))
+	}
+	now = now.Add(time.Minute)
+	h.recordAnomaly(op, nil)
+	assert.Equal(t, 1.0, testutil.ToFloat64(h.anomalies.WithLabelValues(op, anomalySignalErrorRate, anomalyDirectionSpike)))
+	assert.Equal(t, 1, testutil.CollectAndCount(h.anomalies))
+}
+
+func TestValidateAnomalyConfig(t *testing.T) {
+	require.NoError(t, validateAnomalyConfig(nil))
+	require.NoError(t, validateAnomalyConfig(&conf.AnomalyConfig{Enabled: true, Sigma: 4, Operations: []string{"/api.v1.Orders/*"}}))
+	assert.Error(t, validateAnomalyConfig(&conf.AnomalyConfig{Enabled: true, Interval: durationpb.New(-time.Second)}))
+	assert.Error(t, validateAnomalyConfig(&conf.AnomalyConfig{Enabled: true, Sigma: -1}))
+	assert.Error(t, validateAnomalyConfig(&conf.AnomalyConfig{Enabled: true, MinRequests: -1}))
+	assert.Error(t, validateAnomalyConfig(&conf.AnomalyConfig{Enabled: true, Operations: []string{""}}))
+}
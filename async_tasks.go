@@ -0,0 +1,538 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	nhttp "net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/encoding"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+const (
+	defaultAsyncTasksPath        = "/tasks"
+	defaultAsyncTaskWorkers      = 4
+	defaultAsyncTaskMaxPending   = 1000
+	defaultAsyncTaskResultTTL    = time.Hour
+	defaultAsyncTaskMaxBodyBytes = 1 << 20
+	memoryTaskQueueName          = "memory"
+	memoryTaskQueueSweepInterval = time.Minute
+	asyncTaskStatusOperation     = "status"
+
+	// TaskAcceptedCode is the envelope code of submitted tasks.
+	TaskAcceptedCode = nhttp.StatusAccepted
+
+	taskNotFoundReason       = "TASK_NOT_FOUND"
+	taskKindNotFoundReason   = "TASK_KIND_NOT_FOUND"
+	taskQueueFullReason      = "TASK_QUEUE_FULL"
+	taskQueueMissingReason   = "TASK_QUEUE_UNAVAILABLE"
+	invalidTaskPayloadReason = "INVALID_TASK_PAYLOAD"
+	taskPanicReason          = "TASK_PANIC"
+)
+
+var (
+	// ErrTaskNotFound is returned by TaskQueue.Task for unknown or expired tasks.
+	ErrTaskNotFound = stderrors.New("task not found")
+	// ErrTaskQueueFull is returned by TaskQueue.Enqueue when the queue cannot take more tasks.
+	ErrTaskQueueFull = stderrors.New("task queue full")
+)
+
+// TaskStatus is the processing state of a task.
+type TaskStatus string
+
+const (
+	TaskPending   TaskStatus = "pending"
+	TaskRunning   TaskStatus = "running"
+	TaskSucceeded TaskStatus = "succeeded"
+	TaskFailed    TaskStatus = "failed"
+)
+
+// Task is a submitted task as persisted by the task queue.
+type Task struct {
+	ID      string          `json:"id"`
+	Kind    string          `json:"kind"`
+	Status  TaskStatus      `json:"status"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+	// Result is the JSON-encoded result of a succeeded task.
+	Result json.RawMessage `json:"result,omitempty"`
+	// Error is the error of a failed task.
+	Error *TaskError `json:"error,omitempty"`
+	// Owner is the "sub" claim of the submitter; only the same subject can read the task's status.
+	Owner     string    `json:"owner,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TaskError is the error of a failed task: the body code and the Kratos reason, as in error envelopes.
+type TaskError struct {
+	Code   int    `json:"code"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// TaskQueue persists tasks. Enqueue stores a pending task; queues other than the built-in memory queue hand it
+// to consumers that run it with ProcessTask and store the returned task, which Task then reports.
+type TaskQueue interface {
+	Enqueue(ctx context.Context, task Task) error
+	Task(ctx context.Context, id string) (Task, error)
+}
+
+// AsyncTask is a task kind registered with RegisterAsyncTask.
+type AsyncTask struct {
+	// Validate checks the payload on submission; its error is returned to the client and nothing is queued.
+	// Optional.
+	Validate func(ctx context.Context, payload json.RawMessage) error
+	// Process runs the task. The result is encoded with the JSON codec, so proto messages are supported.
+	Process func(ctx context.Context, payload json.RawMessage) (any, error)
+}
+
+// TaskAccepted is the reply to a task submission. Returned from a handler, it is written as
+// {"code":202,"data":{...}} with a Location header pointing at the status URL.
+type TaskAccepted struct {
+	TaskID    string     `json:"task_id"`
+	Status    TaskStatus `json:"status"`
+	StatusURL string     `json:"status_url"`
+}
+
+// TaskStatusReply is the data of a task status response.
+type TaskStatusReply struct {
+	ID        string          `json:"id"`
+	Kind      string          `json:"kind"`
+	Status    TaskStatus      `json:"status"`
+	Result    json.RawMessage `json:"result,omitempty"`
+	Error     *TaskError      `json:"error,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// RegisterAsyncTask registers a task kind, submitted with POST <async_tasks.path>/<kind>.
+func (h *ServiceHttp) RegisterAsyncTask(kind string, task AsyncTask) error {
+	if kind == "" || strings.ContainsAny(kind, "/?#") {
+		return fmt.Errorf("invalid async task kind %q", kind)
+	}
+	if task.Process == nil {
+		return fmt.Errorf("async task %q: Process is nil", kind)
+	}
+	h.asyncTaskMu.Lock()
+	defer h.asyncTaskMu.Unlock()
+	if _, dup := h.asyncTasks[kind]; dup {
+		return fmt.Errorf("async task %q already registered", kind)
+	}
+	if h.asyncTasks == nil {
+		h.asyncTasks = make(map[string]AsyncTask)
+	}
+	h.asyncTasks[kind] = task
+	return nil
+}
+
+// RegisterTaskQueue registers a queue under the name used in async_tasks.queue. "memory" is built in.
+func (h *ServiceHttp) RegisterTaskQueue(name string, queue TaskQueue) error {
+	name = strings.TrimSpace(name)
+	if name == "" || name == memoryTaskQueueName {
+		return fmt.Errorf("invalid task queue name %q", name)
+	}
+	if queue == nil {
+		return fmt.Errorf("task queue %q: queue is nil", name)
+	}
+	h.asyncTaskMu.Lock()
+	defer h.asyncTaskMu.Unlock()
+	if _, dup := h.taskQueues[name]; dup {
+		return fmt.Errorf("task queue %q already registered", name)
+	}
+	if h.taskQueues == nil {
+		h.taskQueues = make(map[string]TaskQueue)
+	}
+	h.taskQueues[name] = queue
+	return nil
+}
+
+func (h *ServiceHttp) asyncTask(kind string) (AsyncTask, bool) {
+	h.asyncTaskMu.RLock()
+	defer h.asyncTaskMu.RUnlock()
+	task, ok := h.asyncTasks[kind]
+	return task, ok
+}
+
+// taskQueue returns the configured queue, creating the built-in memory queue on first use.
+func (h *ServiceHttp) taskQueue() (TaskQueue, string, bool) {
+	h.confMu.RLock()
+	cfg := h.conf.GetAsyncTasks()
+	h.confMu.RUnlock()
+	name := strings.TrimSpace(cfg.GetQueue())
+	if name == "" {
+		name = memoryTaskQueueName
+	}
+	h.asyncTaskMu.RLock()
+	queue, ok := h.taskQueues[name]
+	h.asyncTaskMu.RUnlock()
+	if ok || name != memoryTaskQueueName {
+		return queue, name, ok
+	}
+	h.asyncTaskMu.Lock()
+	defer h.asyncTaskMu.Unlock()
+	if queue, ok = h.taskQueues[name]; !ok {
+		queue = newMemoryTaskQueue(cfg, h.ProcessTask)
+		if h.taskQueues == nil {
+			h.taskQueues = make(map[string]TaskQueue)
+		}
+		h.taskQueues[name] = queue
+	}
+	return queue, name, true
+}
+
+// SubmitTask validates payload for the task kind and enqueues it. It is what the submission endpoint runs, for
+// handlers that accept tasks on their own routes. payload may be raw JSON or a value encoded with the JSON codec.
+// Queues may keep ctx for processing, so Kratos handlers pass ctx.Request().Context() rather than the pooled
+// http.Context.
+func (h *ServiceHttp) SubmitTask(ctx context.Context, kind string, payload any) (*TaskAccepted, error) {
+	task, ok := h.asyncTask(kind)
+	if !ok {
+		return nil, errors.NotFound(taskKindNotFoundReason, "unknown task kind")
+	}
+	raw, ok := payload.(json.RawMessage)
+	if !ok && payload != nil {
+		data, err := encoding.GetCodec("json").Marshal(payload)
+		if err != nil {
+			return nil, errors.BadRequest(invalidTaskPayloadReason, err.Error())
+		}
+		raw = data
+	}
+	if task.Validate != nil {
+		if err := task.Validate(ctx, raw); err != nil {
+			h.countAsyncTask(kind, "rejected")
+			return nil, err
+		}
+	}
+	queue, name, ok := h.taskQueue()
+	if !ok {
+		return nil, errors.ServiceUnavailable(taskQueueMissingReason, fmt.Sprintf("task queue %q is not registered", name))
+	}
+	now := time.Now()
+	t := Task{ID: newTaskID(), Kind: kind, Status: TaskPending, Payload: raw, CreatedAt: now, UpdatedAt: now}
+	if claims, ok := AuthClaimsFromContext(ctx); ok {
+		t.Owner = claims.Subject()
+	}
+	t.RequestID, _ = RequestIDFromContext(ctx)
+	if err := queue.Enqueue(ctx, t); err != nil {
+		h.countAsyncTask(kind, "rejected")
+		if stderrors.Is(err, ErrTaskQueueFull) {
+			return nil, errors.ServiceUnavailable(taskQueueFullReason, "task queue is full")
+		}
+		return nil, errors.InternalServer("TASK_ENQUEUE_FAILED", err.Error())
+	}
+	h.countAsyncTask(kind, "accepted")
+	return &TaskAccepted{TaskID: t.ID, Status: TaskPending, StatusURL: h.asyncTasksPath() + "/" + t.ID}, nil
+}
+
+// ProcessTask runs the registered Process of task.Kind and returns the task with its outcome. Queue consumers
+// call it and store the result; the memory queue calls it itself.
+func (h *ServiceHttp) ProcessTask(ctx context.Context, task Task) (done Task) {
+	done = task
+	fail := func(code int, reason string) {
+		done.Status, done.Result, done.Error = TaskFailed, nil, &TaskError{Code: code, Reason: reason}
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("Async task %s (%s) panicked: %v", task.ID, task.Kind, r)
+			fail(BodyCodeSystemFailure, taskPanicReason)
+		}
+		done.UpdatedAt = time.Now()
+		h.countAsyncTask(task.Kind, string(done.Status))
+	}()
+	handler, ok := h.asyncTask(task.Kind)
+	if !ok {
+		fail(BodyCodeSystemFailure, taskKindNotFoundReason)
+		return done
+	}
+	result, err := handler.Process(ctx, task.Payload)
+	if err != nil {
+		fail(h.responseBodyCodeFromError(err), errors.FromError(err).Reason)
+		return done
+	}
+	data, err := encoding.GetCodec("json").Marshal(result)
+	if err != nil {
+		log.Errorf("Failed to encode the result of async task %s (%s): %v", task.ID, task.Kind, err)
+		fail(BodyCodeSystemFailure, "TASK_RESULT_ENCODE")
+		return done
+	}
+	done.Status, done.Result, done.Error = TaskSucceeded, data, nil
+	return done
+}
+
+func (h *ServiceHttp) countAsyncTask(kind, outcome string) {
+	if h.asyncTaskCounter != nil {
+		h.asyncTaskCounter.WithLabelValues(kind, outcome).Inc()
+	}
+}
+
+func (h *ServiceHttp) asyncTasksPath() string {
+	h.confMu.RLock()
+	defer h.confMu.RUnlock()
+	if p := strings.TrimSpace(h.conf.GetAsyncTasks().GetPath()); p != "" {
+		return strings.TrimSuffix(p, "/")
+	}
+	return defaultAsyncTasksPath
+}
+
+// newTaskID returns a random 128-bit task ID.
+func newTaskID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// mountAsyncTasks registers the submission and status endpoints on the server.
+func (h *ServiceHttp) mountAsyncTasks() {
+	path := h.asyncTasksPath()
+	maxBody := h.conf.GetAsyncTasks().GetMaxBodyBytes()
+	if maxBody <= 0 {
+		maxBody = defaultAsyncTaskMaxBodyBytes
+	}
+	r := h.server.Route("/")
+	r.POST(path+"/{kind}", h.submitTaskHandler(path, maxBody))
+	r.GET(path+"/{id}", h.taskStatusHandler(path))
+	log.Infof("Async task endpoints mounted at %s", path)
+}
+
+// submitTaskHandler reads the JSON payload and submits it through the middleware chain under the operation
+// "<path>/<kind>".
+func (h *ServiceHttp) submitTaskHandler(path string, maxBody int64) http.HandlerFunc {
+	return func(ctx http.Context) error {
+		kind := ctx.Vars().Get("kind")
+		if _, ok := h.asyncTask(kind); !ok {
+			return errors.NotFound(taskKindNotFoundReason, "unknown task kind")
+		}
+		body, err := io.ReadAll(nhttp.MaxBytesReader(ctx.Response(), ctx.Request().Body, maxBody))
+		if err != nil {
+			var tooLarge *nhttp.MaxBytesError
+			if stderrors.As(err, &tooLarge) {
+				return errors.New(nhttp.StatusRequestEntityTooLarge, invalidTaskPayloadReason, "task payload too large")
+			}
+			return errors.BadRequest(invalidTaskPayloadReason, "failed to read task payload")
+		}
+		var payload json.RawMessage
+		if len(strings.TrimSpace(string(body))) > 0 {
+			if !json.Valid(body) {
+				return errors.BadRequest(invalidTaskPayloadReason, "task payload is not valid JSON")
+			}
+			payload = body
+		}
+		http.SetOperation(ctx, path+"/"+kind)
+		// The chain starts from the request context: ctx is pooled and reused once the handler returns, while
+		// queues may keep the context for processing.
+		reply, err := ctx.Middleware(func(c context.Context, req any) (any, error) {
+			return h.SubmitTask(c, kind, req)
+		})(ctx.Request().Context(), payload)
+		if err != nil {
+			return err
+		}
+		return ctx.Result(nhttp.StatusOK, reply)
+	}
+}
+
+// taskStatusHandler reports a task under the operation "<path>/status". Tasks of another subject are reported as
+// not found.
+func (h *ServiceHttp) taskStatusHandler(path string) http.HandlerFunc {
+	return func(ctx http.Context) error {
+		id := ctx.Vars().Get("id")
+		http.SetOperation(ctx, path+"/"+asyncTaskStatusOperation)
+		reply, err := ctx.Middleware(func(c context.Context, _ any) (any, error) {
+			queue, name, ok := h.taskQueue()
+			if !ok {
+				return nil, errors.ServiceUnavailable(taskQueueMissingReason, fmt.Sprintf("task queue %q is not registered", name))
+			}
+			task, err := queue.Task(c, id)
+			if stderrors.Is(err, ErrTaskNotFound) {
+				return nil, errors.NotFound(taskNotFoundReason, "task not found")
+			}
+			if err != nil {
+				return nil, errors.InternalServer("TASK_LOOKUP_FAILED", err.Error())
+			}
+			if task.Owner != "" {
+				if claims, ok := AuthClaimsFromContext(c); !ok || claims.Subject() != task.Owner {
+					return nil, errors.NotFound(taskNotFoundReason, "task not found")
+				}
+			}
+			return &TaskStatusReply{
+				ID: task.ID, Kind: task.Kind, Status: task.Status, Result: task.Result, Error: task.Error,
+				CreatedAt: task.CreatedAt, UpdatedAt: task.UpdatedAt,
+			}, nil
+		})(ctx, id)
+		if err != nil {
+			return err
+		}
+		return ctx.Result(nhttp.StatusOK, reply)
+	}
+}
+
+// withAcceptedTasks writes *TaskAccepted replies with TaskAcceptedCode and a Location header, and hands other
+// replies to encode.
+func withAcceptedTasks(encode http.EncodeResponseFunc) http.EncodeResponseFunc {
+	return func(w http.ResponseWriter, r *http.Request, data any) error {
+		accepted, ok := data.(*TaskAccepted)
+		if !ok {
+			return encode(w, r, data)
+		}
+		w.Header().Set("Location", accepted.StatusURL)
+		return encodeResponse(w, r, accepted, TaskAcceptedCode)
+	}
+}
+
+// memoryTaskQueue keeps tasks in process memory and runs them in submission order with a fixed pool of workers
+// goroutines, started on the first Enqueue. Tasks are lost on restart and not shared between instances.
+type memoryTaskQueue struct {
+	process    func(context.Context, Task) Task
+	workers    int
+	maxPending int
+	ttl        time.Duration
+	now        func() time.Time
+	// queued holds the tasks waiting for a worker; its capacity is maxPending, so sends never block.
+	queued chan queuedTask
+	start  sync.Once
+	// finished is called after a task is stored as done; tests use it to wait for completion.
+	finished func(id string)
+
+	mu        sync.Mutex
+	tasks     map[string]*memoryTask
+	pending   int
+	nextSweep time.Time
+}
+
+type memoryTask struct {
+	task Task
+	// expires is zero until the task is done.
+	expires time.Time
+}
+
+// queuedTask is a task waiting for a worker with the context it runs with.
+type queuedTask struct {
+	ctx  context.Context
+	task Task
+}
+
+func newMemoryTaskQueue(cfg *conf.AsyncTasksConfig, process func(context.Context, Task) Task) *memoryTaskQueue {
+	workers, maxPending := defaultAsyncTaskWorkers, defaultAsyncTaskMaxPending
+	if cfg.GetWorkers() > 0 {
+		workers = int(cfg.GetWorkers())
+	}
+	if cfg.GetMaxPending() > 0 {
+		maxPending = int(cfg.GetMaxPending())
+	}
+	return &memoryTaskQueue{
+		process:    process,
+		workers:    workers,
+		maxPending: maxPending,
+		ttl:        durationOrDefault(cfg.GetResultTtl().AsDuration(), defaultAsyncTaskResultTTL),
+		now:        time.Now,
+		queued:     make(chan queuedTask, maxPending),
+		tasks:      make(map[string]*memoryTask),
+	}
+}
+
+// Enqueue stores the task and queues it to run in the background with the values, but not the cancellation, of
+// ctx.
+func (q *memoryTaskQueue) Enqueue(ctx context.Context, task Task) error {
+	q.start.Do(func() {
+		for range q.workers {
+			go q.work()
+		}
+	})
+	q.mu.Lock()
+	now := q.now()
+	if now.After(q.nextSweep) {
+		for id, t := range q.tasks {
+			if !t.expires.IsZero() && !now.Before(t.expires) {
+				delete(q.tasks, id)
+			}
+		}
+		q.nextSweep = now.Add(memoryTaskQueueSweepInterval)
+	}
+	if q.pending >= q.maxPending {
+		q.mu.Unlock()
+		return ErrTaskQueueFull
+	}
+	q.tasks[task.ID] = &memoryTask{task: task}
+	q.pending++
+	// Sending under the lock keeps the channel in submission order; pending bounds it below its capacity.
+	q.queued <- queuedTask{ctx: context.WithoutCancel(ctx), task: task}
+	q.mu.Unlock()
+	return nil
+}
+
+// work runs queued tasks one at a time, in the order they were enqueued.
+func (q *memoryTaskQueue) work() {
+	for qt := range q.queued {
+		q.run(qt.ctx, qt.task)
+	}
+}
+
+func (q *memoryTaskQueue) run(ctx context.Context, task Task) {
+	q.update(task.ID, func(t *memoryTask) {
+		t.task.Status, t.task.UpdatedAt = TaskRunning, q.now()
+	})
+	done := q.process(ctx, task)
+	q.update(task.ID, func(t *memoryTask) {
+		t.task, t.expires = done, q.now().Add(q.ttl)
+		q.pending--
+	})
+	if q.finished != nil {
+		q.finished(task.ID)
+	}
+}
+
+func (q *memoryTaskQueue) update(id string, fn func(*memoryTask)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if t, ok := q.tasks[id]; ok {
+		fn(t)
+	}
+}
+
+// Task returns the stored task; finished tasks expire after the result TTL.
+func (q *memoryTaskQueue) Task(_ context.Context, id string) (Task, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	t, ok := q.tasks[id]
+	if !ok || !t.expires.IsZero() && !q.now().Before(t.expires) {
+		return Task{}, ErrTaskNotFound
+	}
+	return t.task, nil
+}
+
+// warnUnregisteredTaskQueue logs a configured queue that no RegisterTaskQueue call provided.
+func (h *ServiceHttp) warnUnregisteredTaskQueue() {
+	if !h.conf.GetAsyncTasks().GetEnabled() {
+		return
+	}
+	if _, name, ok := h.taskQueue(); !ok {
+		log.Warnf("Task queue %q is configured but not registered; task submissions will fail", name)
+	}
+}
+
+// validateAsyncTasksConfig checks the path and rejects negative limits.
+func validateAsyncTasksConfig(cfg *conf.AsyncTasksConfig) error {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	if p := strings.TrimSpace(cfg.Path); p != "" && !strings.HasPrefix(p, "/") {
+		return fmt.Errorf("path %q must start with /", p)
+	}
+	if cfg.Workers < 0 || cfg.MaxPending < 0 || cfg.MaxBodyBytes < 0 {
+		return fmt.Errorf("workers, max_pending and max_body_bytes cannot be negative")
+	}
+	if cfg.ResultTtl != nil && cfg.ResultTtl.AsDuration() < 0 {
+		return fmt.Errorf("result_ttl cannot be negative")
+	}
+	return nil
+}
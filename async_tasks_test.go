@@ -0,0 +1,202 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type exportRequest struct {
+	Format string `json:"format"`
+}
+
+func TestAsyncTasks_TestService(t *testing.T) {
+	svc, err := NewTestService(&conf.Http{AsyncTasks: &conf.AsyncTasksConfig{Enabled: true}})
+	require.NoError(t, err)
+	defer func() { _ = svc.Close() }()
+	require.NoError(t, svc.RegisterAsyncTask("export", AsyncTask{
+		Validate: func(_ context.Context, payload json.RawMessage) error {
+			var req exportRequest
+			if err := json.Unmarshal(payload, &req); err != nil || req.Format == "" {
+				return kerrors.BadRequest("FORMAT_REQUIRED", "format is required")
+			}
+			return nil
+		},
+		Process: func(_ context.Context, payload json.RawMessage) (any, error) {
+			var req exportRequest
+			_ = json.Unmarshal(payload, &req)
+			if req.Format == "xml" {
+				return nil, kerrors.BadRequest("UNSUPPORTED_FORMAT", "")
+			}
+			return map[string]string{"url": "https://files.example.com/export." + req.Format}, nil
+		},
+	}))
+
+	submit := func(format string) *TaskAccepted {
+		res, err := svc.PostJSON("/tasks/export", exportRequest{Format: format})
+		require.NoError(t, err)
+		assert.Equal(t, TaskAcceptedCode, res.Code())
+		var accepted TaskAccepted
+		require.NoError(t, res.Decode(&accepted, WithSuccessCodes(200, TaskAcceptedCode)))
+		assert.Equal(t, TaskPending, accepted.Status)
+		assert.Equal(t, "/tasks/"+accepted.TaskID, accepted.StatusURL)
+		assert.Equal(t, accepted.StatusURL, res.Header.Get("Location"))
+		return &accepted
+	}
+	status := func(url string, want TaskStatus) TaskStatusReply {
+		var reply TaskStatusReply
+		require.Eventually(t, func() bool {
+			res, err := svc.Get(url)
+			require.NoError(t, err)
+			require.NoError(t, res.Decode(&reply))
+			return reply.Status == want
+		}, time.Second, 5*time.Millisecond)
+		return reply
+	}
+
+	done := status(submit("csv").StatusURL, TaskSucceeded)
+	assert.Equal(t, "export", done.Kind)
+	assert.JSONEq(t, `{"url":"https://files.example.com/export.csv"}`, string(done.Result))
+	failed := status(submit("xml").StatusURL, TaskFailed)
+	assert.Equal(t, &TaskError{Code: 400, Reason: "UNSUPPORTED_FORMAT"}, failed.Error)
+
+	// Invalid payloads are rejected before anything is queued.
+	res, err := svc.PostJSON("/tasks/export", exportRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, 400, res.Code())
+	res, err = svc.PostJSON("/tasks/import", exportRequest{Format: "csv"})
+	require.NoError(t, err)
+	assert.Equal(t, 404, res.Code())
+	res, err = svc.Get("/tasks/0123456789abcdef")
+	require.NoError(t, err)
+	assert.Equal(t, 404, res.Code())
+}
+
+// recordingTaskQueue stores tasks for an outbox-style consumer.
+type recordingTaskQueue struct {
+	mu    sync.Mutex
+	tasks map[string]Task
+}
+
+func (q *recordingTaskQueue) Enqueue(_ context.Context, task Task) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.tasks[task.ID] = task
+	return nil
+}
+
+func (q *recordingTaskQueue) Task(_ context.Context, id string) (Task, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	task, ok := q.tasks[id]
+	if !ok {
+		return Task{}, ErrTaskNotFound
+	}
+	return task, nil
+}
+
+func TestAsyncTasks_RegisteredQueue(t *testing.T) {
+	svc, err := NewTestService(&conf.Http{AsyncTasks: &conf.AsyncTasksConfig{Enabled: true, Queue: "outbox"}})
+	require.NoError(t, err)
+	defer func() { _ = svc.Close() }()
+	queue := &recordingTaskQueue{tasks: make(map[string]Task)}
+	require.NoError(t, svc.RegisterTaskQueue("outbox", queue))
+	require.Error(t, svc.RegisterTaskQueue("memory", queue))
+	require.NoError(t, svc.RegisterAsyncTask("reindex", AsyncTask{
+		Process: func(context.Context, json.RawMessage) (any, error) { panic("index missing") },
+	}))
+
+	res, err := svc.PostJSON("/tasks/reindex", map[string]int{"shard": 3})
+	require.NoError(t, err)
+	var accepted TaskAccepted
+	require.NoError(t, res.Decode(&accepted, WithSuccessCodes(TaskAcceptedCode)))
+	task, err := queue.Task(context.Background(), accepted.TaskID)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"shard":3}`, string(task.Payload))
+
+	// The consumer runs the task and stores the outcome; panics fail the task.
+	done := svc.ProcessTask(context.Background(), task)
+	assert.Equal(t, TaskFailed, done.Status)
+	assert.Equal(t, &TaskError{Code: BodyCodeSystemFailure, Reason: taskPanicReason}, done.Error)
+	require.NoError(t, queue.Enqueue(context.Background(), done))
+	res, err = svc.Get(accepted.StatusURL)
+	require.NoError(t, err)
+	var reply TaskStatusReply
+	require.NoError(t, res.Decode(&reply))
+	assert.Equal(t, TaskFailed, reply.Status)
+
+	// Tasks submitted by a subject are hidden from other callers.
+	task.ID, task.Owner = "owned", "user-7"
+	require.NoError(t, queue.Enqueue(context.Background(), task))
+	res, err = svc.Get("/tasks/owned")
+	require.NoError(t, err)
+	assert.Equal(t, 404, res.Code())
+}
+
+func TestMemoryTaskQueue(t *testing.T) {
+	started := make(chan string)
+	release := make(chan struct{})
+	q := newMemoryTaskQueue(&conf.AsyncTasksConfig{Workers: 1, MaxPending: 2, ResultTtl: durationpb.New(time.Minute)},
+		func(_ context.Context, task Task) Task {
+			started <- task.ID
+			<-release
+			task.Status = TaskSucceeded
+			return task
+		})
+	finished := make(chan string, 3)
+	q.finished = func(id string) { finished <- id }
+	now := time.Unix(1_700_000_000, 0)
+	var mu sync.Mutex
+	q.now = func() time.Time { mu.Lock(); defer mu.Unlock(); return now }
+	ctx := context.Background()
+
+	require.NoError(t, q.Enqueue(ctx, Task{ID: "a", Status: TaskPending}))
+	require.NoError(t, q.Enqueue(ctx, Task{ID: "b", Status: TaskPending}))
+	assert.ErrorIs(t, q.Enqueue(ctx, Task{ID: "c"}), ErrTaskQueueFull)
+
+	// One worker runs the tasks in submission order: a runs while b waits.
+	assert.Equal(t, "a", <-started)
+	a, _ := q.Task(ctx, "a")
+	b, _ := q.Task(ctx, "b")
+	assert.Equal(t, TaskRunning, a.Status)
+	assert.Equal(t, TaskPending, b.Status)
+	release <- struct{}{}
+	assert.Equal(t, "a", <-finished)
+	assert.Equal(t, "b", <-started)
+	b, _ = q.Task(ctx, "b")
+	assert.Equal(t, TaskRunning, b.Status)
+	release <- struct{}{}
+	assert.Equal(t, "b", <-finished)
+	b, _ = q.Task(ctx, "b")
+	assert.Equal(t, TaskSucceeded, b.Status)
+	require.NoError(t, q.Enqueue(ctx, Task{ID: "c"}))
+	assert.Equal(t, "c", <-started)
+	close(release)
+
+	// Finished tasks expire after the result TTL.
+	mu.Lock()
+	now = now.Add(time.Minute)
+	mu.Unlock()
+	_, err := q.Task(ctx, "a")
+	assert.ErrorIs(t, err, ErrTaskNotFound)
+}
+
+func TestValidateAsyncTasksConfig(t *testing.T) {
+	require.NoError(t, validateAsyncTasksConfig(nil))
+	require.NoError(t, validateAsyncTasksConfig(&conf.AsyncTasksConfig{Enabled: true, Path: "/jobs", Workers: 8}))
+	assert.Error(t, validateAsyncTasksConfig(&conf.AsyncTasksConfig{Enabled: true, Path: "jobs"}))
+	assert.Error(t, validateAsyncTasksConfig(&conf.AsyncTasksConfig{Enabled: true, MaxPending: -1}))
+	assert.Error(t, validateAsyncTasksConfig(&conf.AsyncTasksConfig{Enabled: true, ResultTtl: durationpb.New(-time.Second)}))
+
+	h := &ServiceHttp{}
+	assert.Error(t, h.RegisterAsyncTask("a/b", AsyncTask{Process: func(context.Context, json.RawMessage) (any, error) { return nil, nil }}))
+	assert.Error(t, h.RegisterAsyncTask("export", AsyncTask{}))
+}
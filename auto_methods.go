@@ -0,0 +1,166 @@
+package http
+
+import (
+	nhttp "net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/gorilla/mux"
+)
+
+// methodOrder is the order of methods in Allow headers; other methods follow alphabetically.
+var methodOrder = []string{
+	nhttp.MethodGet, nhttp.MethodHead, nhttp.MethodPost, nhttp.MethodPut, nhttp.MethodPatch,
+	nhttp.MethodDelete, nhttp.MethodConnect, nhttp.MethodOptions, nhttp.MethodTrace,
+}
+
+// routeTable mirrors the server's routes in a router without handlers, so requests can be matched against
+// every method. It is rebuilt when routes are added.
+type routeTable struct {
+	mu      sync.Mutex
+	routes  []http.RouteInfo
+	router  *mux.Router
+	methods []string
+}
+
+// methodsFor returns the methods registered for the path of r.
+func (t *routeTable) methodsFor(srv *http.Server, r *nhttp.Request) []string {
+	var routes []http.RouteInfo
+	if err := srv.WalkRoute(func(ri http.RouteInfo) error {
+		routes = append(routes, ri)
+		return nil
+	}); err != nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	if !slices.Equal(routes, t.routes) {
+		t.routes = routes
+		t.router = mux.NewRouter()
+		t.methods = nil
+		for _, ri := range routes {
+			t.router.NewRoute().Path(ri.Path).Methods(ri.Method)
+			if !slices.Contains(t.methods, ri.Method) {
+				t.methods = append(t.methods, ri.Method)
+			}
+		}
+	}
+	router, methods := t.router, t.methods
+	t.mu.Unlock()
+
+	var allowed []string
+	probe := *r
+	for _, method := range methods {
+		probe.Method = method
+		var match mux.RouteMatch
+		if router.Match(&probe, &match) {
+			allowed = append(allowed, method)
+		}
+	}
+	return allowed
+}
+
+// allowHeader lists the registered methods plus the ones answered automatically, in methodOrder.
+func allowHeader(registered []string, autoHead, autoOptions bool) string {
+	allowed := slices.Clone(registered)
+	if autoHead && slices.Contains(allowed, nhttp.MethodGet) && !slices.Contains(allowed, nhttp.MethodHead) {
+		allowed = append(allowed, nhttp.MethodHead)
+	}
+	if autoOptions && !slices.Contains(allowed, nhttp.MethodOptions) {
+		allowed = append(allowed, nhttp.MethodOptions)
+	}
+	slices.SortFunc(allowed, func(a, b string) int {
+		ia, ib := slices.Index(methodOrder, a), slices.Index(methodOrder, b)
+		switch {
+		case ia >= 0 && ib >= 0:
+			return ia - ib
+		case ia >= 0:
+			return -1
+		case ib >= 0:
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+	return strings.Join(allowed, ", ")
+}
+
+// headResponseWriter sends the headers of a GET reply without its body. The status is held back until the
+// handler finishes, so Content-Length can be set from the bytes it wrote.
+type headResponseWriter struct {
+	nhttp.ResponseWriter
+	status int
+	n      int64
+}
+
+func (w *headResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *headResponseWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
+
+func (w *headResponseWriter) Unwrap() nhttp.ResponseWriter { return w.ResponseWriter }
+
+func (w *headResponseWriter) finish() {
+	if w.status == 0 {
+		w.status = nhttp.StatusOK
+	}
+	if w.ResponseWriter.Header().Get("Content-Length") == "" && w.n > 0 {
+		w.ResponseWriter.Header().Set("Content-Length", strconv.FormatInt(w.n, 10))
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+// autoMethodsFilter answers HEAD requests to GET routes with the GET handler's headers, and OPTIONS requests
+// to routes without an OPTIONS handler with their Allow header. Requests to routes that handle the method
+// themselves are passed through.
+func (h *ServiceHttp) autoMethodsFilter(cfg *conf.RoutingConfig) http.FilterFunc {
+	autoHead, autoOptions := !cfg.GetDisableAutoHead(), !cfg.GetDisableAutoOptions()
+	return func(next nhttp.Handler) nhttp.Handler {
+		return nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+			if h.server == nil || (r.Method != nhttp.MethodHead || !autoHead) && (r.Method != nhttp.MethodOptions || !autoOptions) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			registered := h.routes.methodsFor(h.server, r)
+			if len(registered) == 0 || slices.Contains(registered, r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if r.Method == nhttp.MethodOptions {
+				w.Header().Set("Allow", allowHeader(registered, autoHead, autoOptions))
+				w.WriteHeader(nhttp.StatusNoContent)
+				return
+			}
+			if !slices.Contains(registered, nhttp.MethodGet) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			get := r.Clone(r.Context())
+			get.Method = nhttp.MethodGet
+			hw := &headResponseWriter{ResponseWriter: w}
+			next.ServeHTTP(hw, get)
+			hw.finish()
+		})
+	}
+}
+
+// setAllowHeader fills the Allow header of a 405 response from the route table.
+func (h *ServiceHttp) setAllowHeader(w nhttp.ResponseWriter, r *nhttp.Request) {
+	if h.server == nil {
+		return
+	}
+	if registered := h.routes.methodsFor(h.server, r); len(registered) > 0 {
+		cfg := h.routingConfig()
+		w.Header().Set("Allow", allowHeader(registered, !cfg.GetDisableAutoHead(), !cfg.GetDisableAutoOptions()))
+	}
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	nhttp "net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoMethods(t *testing.T) {
+	h := &ServiceHttp{conf: &conf.Http{}}
+	h.server = http.NewServer(
+		http.Filter(h.autoMethodsFilter(nil)),
+		http.MethodNotAllowedHandler(h.methodNotAllowedHandler()),
+		http.ResponseEncoder(ResponseEncoder),
+		http.ErrorEncoder(h.enhancedErrorEncoder),
+	)
+	getCalls := 0
+	route := h.server.Route("/")
+	route.GET("/users/{id}", func(ctx http.Context) error {
+		getCalls++
+		ctx.Response().Header().Set("ETag", `"v1"`)
+		return ctx.Result(nhttp.StatusOK, map[string]string{"id": ctx.Vars().Get("id")})
+	})
+	route.DELETE("/users/{id}", func(ctx http.Context) error { return ctx.Result(nhttp.StatusOK, nil) })
+	route.POST("/orders", func(ctx http.Context) error { return ctx.Result(nhttp.StatusOK, nil) })
+	route.OPTIONS("/orders", func(ctx http.Context) error {
+		ctx.Response().Header().Set("X-Custom", "1")
+		return ctx.Result(nhttp.StatusOK, nil)
+	})
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.server.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		return rec
+	}
+
+	get := do(nhttp.MethodGet, "/users/7")
+	head := do(nhttp.MethodHead, "/users/7")
+	assert.Equal(t, nhttp.StatusOK, head.Code)
+	assert.Empty(t, head.Body.String())
+	assert.Equal(t, `"v1"`, head.Header().Get("ETag"))
+	assert.Equal(t, head.Header().Get("Content-Length"), strconv.Itoa(get.Body.Len()))
+	assert.Equal(t, 2, getCalls)
+
+	rec := do(nhttp.MethodOptions, "/users/7")
+	assert.Equal(t, nhttp.StatusNoContent, rec.Code)
+	assert.Equal(t, "GET, HEAD, DELETE, OPTIONS", rec.Header().Get("Allow"))
+
+	rec = do(nhttp.MethodOptions, "/orders")
+	assert.Equal(t, "1", rec.Header().Get("X-Custom"), "registered OPTIONS handlers are kept")
+
+	rec = do(nhttp.MethodHead, "/orders")
+	assert.Equal(t, nhttp.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "POST, OPTIONS", rec.Header().Get("Allow"))
+
+	rec = do(nhttp.MethodPut, "/users/7")
+	assert.Equal(t, nhttp.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "GET, HEAD, DELETE, OPTIONS", rec.Header().Get("Allow"))
+
+	assert.Equal(t, nhttp.StatusNotFound, do(nhttp.MethodOptions, "/missing").Code)
+}
+
+func TestAutoMethods_Disabled(t *testing.T) {
+	cfg := &conf.RoutingConfig{DisableAutoHead: true, DisableAutoOptions: true}
+	h := &ServiceHttp{conf: &conf.Http{Routing: cfg}}
+	h.server = http.NewServer(
+		http.Filter(h.autoMethodsFilter(cfg)),
+		http.MethodNotAllowedHandler(h.methodNotAllowedHandler()),
+	)
+	h.server.Route("/").GET("/users", func(ctx http.Context) error { return ctx.String(nhttp.StatusOK, "ok") })
+
+	for _, method := range []string{nhttp.MethodHead, nhttp.MethodOptions} {
+		rec := httptest.NewRecorder()
+		h.server.ServeHTTP(rec, httptest.NewRequest(method, "/users", nil))
+		assert.Equal(t, nhttp.StatusMethodNotAllowed, rec.Code)
+		assert.Equal(t, "GET", rec.Header().Get("Allow"))
+	}
+}
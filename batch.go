@@ -0,0 +1,223 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	nhttp "net/http"
+	"strings"
+	"sync"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+const (
+	defaultBatchPath           = "/batch"
+	defaultBatchMaxRequests    = 20
+	defaultBatchMaxConcurrency = 4
+	defaultBatchMaxBodyBytes   = 1 << 20
+
+	// invalidBatchRequestReason is the Kratos error reason for malformed batch requests.
+	invalidBatchRequestReason = "INVALID_BATCH_REQUEST"
+)
+
+// BatchRequestItem is one sub-request of a batch.
+type BatchRequestItem struct {
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+// BatchResponseItem is the result of one sub-request. Code is the envelope code of the sub-response, so
+// business errors can be told apart per item; it falls back to the HTTP status for non-envelope bodies.
+type BatchResponseItem struct {
+	Status  int               `json:"status"`
+	Code    int               `json:"code"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+// batchSettings is the resolved BatchConfig.
+type batchSettings struct {
+	path           string
+	maxRequests    int
+	maxConcurrency int
+	maxBody        int64
+}
+
+func newBatchSettings(cfg *conf.BatchConfig) batchSettings {
+	s := batchSettings{
+		path:           strings.TrimSpace(cfg.GetPath()),
+		maxRequests:    int(cfg.GetMaxRequests()),
+		maxConcurrency: int(cfg.GetMaxConcurrency()),
+		maxBody:        cfg.GetMaxBodyBytes(),
+	}
+	if s.path == "" {
+		s.path = defaultBatchPath
+	}
+	if s.maxRequests <= 0 {
+		s.maxRequests = defaultBatchMaxRequests
+	}
+	if s.maxConcurrency <= 0 {
+		s.maxConcurrency = defaultBatchMaxConcurrency
+	}
+	if s.maxBody <= 0 {
+		s.maxBody = defaultBatchMaxBodyBytes
+	}
+	return s
+}
+
+// mountBatch registers the batch endpoint on the server. The endpoint itself bypasses the middleware
+// chain; each sub-request goes through it instead, so limits and auth apply per item.
+func (h *ServiceHttp) mountBatch() {
+	s := newBatchSettings(h.conf.GetBatch())
+	h.server.Route("/").POST(s.path, h.batchHandler(s))
+	log.Infof("Batch endpoint mounted at %s (max %d requests, concurrency %d)", s.path, s.maxRequests, s.maxConcurrency)
+}
+
+func (h *ServiceHttp) batchHandler(s batchSettings) http.HandlerFunc {
+	return func(ctx http.Context) error {
+		body, err := io.ReadAll(nhttp.MaxBytesReader(ctx.Response(), ctx.Request().Body, s.maxBody))
+		if err != nil {
+			return errors.BadRequest(invalidBatchRequestReason, err.Error())
+		}
+		var items []BatchRequestItem
+		if err := json.Unmarshal(body, &items); err != nil {
+			return errors.BadRequest(invalidBatchRequestReason, "body must be an array of sub-requests")
+		}
+		if len(items) == 0 || len(items) > s.maxRequests {
+			return errors.BadRequest(invalidBatchRequestReason,
+				fmt.Sprintf("batch must contain between 1 and %d requests", s.maxRequests))
+		}
+
+		results := make([]*BatchResponseItem, len(items))
+		sem := make(chan struct{}, s.maxConcurrency)
+		var wg sync.WaitGroup
+		for i := range items {
+			sem <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer func() { <-sem; wg.Done() }()
+				results[i] = h.serveBatchItem(ctx.Request(), s.path, &items[i])
+			}()
+		}
+		wg.Wait()
+		return ctx.Result(nhttp.StatusOK, results)
+	}
+}
+
+// serveBatchItem dispatches one sub-request through the server. It inherits the headers of the batch
+// request, such as Authorization, with the item's headers taking precedence.
+func (h *ServiceHttp) serveBatchItem(outer *nhttp.Request, batchPath string, item *BatchRequestItem) *BatchResponseItem {
+	method := strings.ToUpper(strings.TrimSpace(item.Method))
+	if method == "" {
+		method = nhttp.MethodGet
+	}
+	if !strings.HasPrefix(item.Path, "/") || strings.HasPrefix(item.Path, "//") {
+		return batchItemError(nhttp.StatusBadRequest, "path must be an absolute path")
+	}
+	if p, _, _ := strings.Cut(item.Path, "?"); p == batchPath {
+		return batchItemError(nhttp.StatusBadRequest, "batch requests cannot be nested")
+	}
+
+	req, err := nhttp.NewRequestWithContext(outer.Context(), method, item.Path, bytes.NewReader(item.Body))
+	if err != nil {
+		return batchItemError(nhttp.StatusBadRequest, err.Error())
+	}
+	req.Header = outer.Header.Clone()
+	// Sub-responses are embedded as JSON, so they must not be compressed.
+	req.Header.Del("Accept-Encoding")
+	req.Header.Del("Content-Length")
+	req.Header.Del("Content-Type")
+	if len(item.Body) > 0 {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range item.Headers {
+		req.Header.Set(k, v)
+	}
+	req.RemoteAddr = outer.RemoteAddr
+	req.Host = outer.Host
+	req.TLS = outer.TLS
+
+	rec := newBatchRecorder()
+	h.server.ServeHTTP(rec, req)
+	return rec.result()
+}
+
+func batchItemError(status int, message string) *BatchResponseItem {
+	body, _ := json.Marshal(map[string]any{"code": status, "message": message})
+	return &BatchResponseItem{Status: status, Code: status, Body: body}
+}
+
+// batchRecorder captures a sub-response in memory.
+type batchRecorder struct {
+	header nhttp.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newBatchRecorder() *batchRecorder {
+	return &batchRecorder{header: make(nhttp.Header)}
+}
+
+func (r *batchRecorder) Header() nhttp.Header { return r.header }
+
+func (r *batchRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *batchRecorder) Write(p []byte) (int, error) {
+	r.WriteHeader(nhttp.StatusOK)
+	return r.body.Write(p)
+}
+
+func (r *batchRecorder) result() *BatchResponseItem {
+	status := r.status
+	if status == 0 {
+		status = nhttp.StatusOK
+	}
+	item := &BatchResponseItem{Status: status, Code: status}
+	if len(r.header) > 0 {
+		item.Headers = make(map[string]string, len(r.header))
+		for k := range r.header {
+			item.Headers[k] = r.header.Get(k)
+		}
+	}
+	raw := bytes.TrimSpace(r.body.Bytes())
+	if len(raw) == 0 {
+		return item
+	}
+	if json.Valid(raw) {
+		item.Body = raw
+		var env struct {
+			Code *int `json:"code"`
+		}
+		if json.Unmarshal(raw, &env) == nil && env.Code != nil {
+			item.Code = *env.Code
+		}
+		return item
+	}
+	item.Body, _ = json.Marshal(string(raw))
+	return item
+}
+
+// validateBatchConfig rejects relative paths and negative limits.
+func validateBatchConfig(cfg *conf.BatchConfig) error {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	if p := strings.TrimSpace(cfg.Path); p != "" && !strings.HasPrefix(p, "/") {
+		return fmt.Errorf("path %q must start with /", p)
+	}
+	if cfg.MaxRequests < 0 || cfg.MaxConcurrency < 0 || cfg.MaxBodyBytes < 0 {
+		return fmt.Errorf("limits cannot be negative")
+	}
+	return nil
+}
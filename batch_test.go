@@ -0,0 +1,120 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	nhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBatchTestService(t *testing.T, cfg *conf.BatchConfig) (*ServiceHttp, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	h := &ServiceHttp{conf: &conf.Http{Batch: cfg}}
+	auth := func(next middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			calls.Add(1)
+			if tr, ok := transport.FromServerContext(ctx); ok && tr.RequestHeader().Get("Authorization") != "Bearer ok" {
+				return nil, errors.Unauthorized("UNAUTHORIZED", "")
+			}
+			return next(ctx, req)
+		}
+	}
+	h.server = http.NewServer(http.Middleware(auth), http.ResponseEncoder(ResponseEncoder),
+		http.ErrorEncoder(h.enhancedErrorEncoder))
+	route := h.server.Route("/")
+	route.GET("/users/{id}", func(ctx http.Context) error {
+		reply, err := ctx.Middleware(func(context.Context, any) (any, error) {
+			if ctx.Vars().Get("id") == "404" {
+				return nil, errors.NotFound("USER_NOT_FOUND", "")
+			}
+			time.Sleep(10 * time.Millisecond)
+			return map[string]string{"id": ctx.Vars().Get("id")}, nil
+		})(ctx, nil)
+		if err != nil {
+			return err
+		}
+		return ctx.Result(200, reply)
+	})
+	route.POST("/echo", func(ctx http.Context) error {
+		var in map[string]any
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		return ctx.Result(200, in)
+	})
+	h.mountBatch()
+	return h, &calls
+}
+
+func postBatch(h *ServiceHttp, body string, auth string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(nhttp.MethodPost, "/batch", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	if auth != "" {
+		r.Header.Set("Authorization", auth)
+	}
+	h.server.ServeHTTP(w, r)
+	return w
+}
+
+func TestBatchEndpoint(t *testing.T) {
+	h, calls := newBatchTestService(t, &conf.BatchConfig{Enabled: true, MaxConcurrency: 2})
+
+	w := postBatch(h, `[
+		{"method":"GET","path":"/users/1"},
+		{"method":"GET","path":"/users/404"},
+		{"method":"POST","path":"/echo","body":{"a":1}},
+		{"method":"GET","path":"/users/2","headers":{"Authorization":"Bearer bad"}},
+		{"method":"POST","path":"/batch"},
+		{"path":"relative"}
+	]`, "Bearer ok")
+	require.Equal(t, nhttp.StatusOK, w.Code)
+
+	var res struct {
+		Code int                 `json:"code"`
+		Data []BatchResponseItem `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, 200, res.Code)
+	require.Len(t, res.Data, 6)
+
+	assert.Equal(t, 200, res.Data[0].Code)
+	assert.JSONEq(t, `{"code":200,"data":{"id":"1"}}`, string(res.Data[0].Body))
+	assert.Equal(t, "application/json", res.Data[0].Headers["Content-Type"])
+	assert.Equal(t, 404, res.Data[1].Code)
+	assert.Equal(t, nhttp.StatusOK, res.Data[1].Status, "business errors keep HTTP 200")
+	assert.JSONEq(t, `{"code":200,"data":{"a":1}}`, string(res.Data[2].Body))
+	assert.Equal(t, 401, res.Data[3].Code, "item headers override inherited ones")
+	assert.Equal(t, 400, res.Data[4].Code)
+	assert.Equal(t, 400, res.Data[5].Code)
+	assert.Equal(t, int32(3), calls.Load(), "GET sub-requests run through the middleware chain")
+}
+
+func TestBatchEndpoint_Limits(t *testing.T) {
+	h, _ := newBatchTestService(t, &conf.BatchConfig{Enabled: true, MaxRequests: 2})
+
+	w := postBatch(h, `[{"path":"/users/1"},{"path":"/users/2"},{"path":"/users/3"}]`, "Bearer ok")
+	assert.JSONEq(t, `{"code":400}`, w.Body.String())
+
+	w = postBatch(h, `{"path":"/users/1"}`, "Bearer ok")
+	assert.JSONEq(t, `{"code":400}`, w.Body.String())
+}
+
+func TestValidateBatchConfig(t *testing.T) {
+	require.NoError(t, validateBatchConfig(nil))
+	assert.Error(t, validateBatchConfig(&conf.BatchConfig{Enabled: true, Path: "batch"}))
+	assert.Error(t, validateBatchConfig(&conf.BatchConfig{Enabled: true, MaxConcurrency: -1}))
+}
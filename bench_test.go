@@ -0,0 +1,134 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	nhttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Reproducible benchmarks of the request path. Run them with
+//
+//	go test -run '^$' -bench 'TracerLogPack|Encoder|ErrorMapping' -benchmem -count 6
+//
+// and compare runs with benchstat. Reference numbers on linux/amd64 with go1.26; the reply is a 50-item
+// listing of about 9 KB as a structpb.Struct:
+//
+//	BenchmarkTracerLogPack/default              130µs/op     7.5 KB/op    388 allocs/op
+//	BenchmarkTracerLogPack/default_json_bodies  880µs/op     100 KB/op   2663 allocs/op
+//	BenchmarkTracerLogPack/perf_mode              7µs/op     1.8 KB/op     31 allocs/op
+//	BenchmarkResponseEncoder                    680µs/op     103 KB/op   2290 allocs/op
+//	BenchmarkErrorMapping                       3.4µs/op     1.7 KB/op     22 allocs/op
+//
+// Before the size histograms used proto.Size, marshaling request and reply a second time made the default
+// case 225µs/op, 23 KB/op and 745 allocs/op. perf_mode removes the remaining size walk and body rendering.
+
+// benchListing returns a reply shaped like a typical listing endpoint.
+func benchListing(b *testing.B, n int) *structpb.Struct {
+	b.Helper()
+	items := make([]any, 0, n)
+	for i := range n {
+		items = append(items, map[string]any{
+			"id":         fmt.Sprintf("usr_%06d", i),
+			"name":       fmt.Sprintf("User %d", i),
+			"email":      fmt.Sprintf("user%d@example.com", i),
+			"active":     i%3 != 0,
+			"score":      float64(i) * 1.5,
+			"tags":       []any{"beta", "eu-west", "premium"},
+			"created_at": "2026-01-02T03:04:05Z",
+		})
+	}
+	reply, err := structpb.NewStruct(map[string]any{"items": items, "total": float64(n), "next_cursor": "c_50"})
+	if err != nil {
+		b.Fatal(err)
+	}
+	return reply
+}
+
+// benchService returns a service with metrics recorders and request logging routed away from the console,
+// so the numbers cover building the records rather than terminal I/O.
+func benchService(perfMode bool, replyPolicy string) *ServiceHttp {
+	h := NewServiceHttp()
+	h.conf = &conf.Http{
+		Monitoring: &conf.MonitoringConfig{
+			EnableMetrics:        true,
+			EnableRequestLogging: true,
+			EnableErrorLogging:   true,
+			BodyLogging:          &conf.BodyLoggingConfig{ReplyPolicy: replyPolicy},
+			LogSinks:             []*conf.LogSinkConfig{{Name: lynxLogSink, Level: "fatal"}},
+		},
+		Performance: &conf.PerformanceConfig{PerfMode: perfMode},
+	}
+	h.refreshMonitoringSnapshotLocked()
+	h.requestCounter = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "bench_requests"}, []string{"method", "path", "status"})
+	h.requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "bench_duration"}, []string{"method", "path"})
+	h.requestSize = prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "bench_request_size"}, []string{"method", "path"})
+	h.responseSize = prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "bench_response_size"}, []string{"method", "path"})
+	h.inflightRequests = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "bench_inflight"}, []string{"operation"})
+	return h
+}
+
+func BenchmarkTracerLogPack(b *testing.B) {
+	reply := benchListing(b, 50)
+	req, _ := structpb.NewStruct(map[string]any{"page_size": float64(50), "filter": "active = true"})
+	for _, bc := range []struct {
+		name string
+		svc  *ServiceHttp
+	}{
+		{"default", benchService(false, "")},
+		{"default_json_bodies", benchService(false, "json")},
+		{"perf_mode", benchService(true, "json")},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			handler := TracerLogPackWithMetrics(bc.svc)(func(context.Context, any) (any, error) {
+				return reply, nil
+			})
+			tr := newFakeTransport("/api.v1.Users/List", map[string]string{"User-Agent": "bench", "X-Forwarded-For": "203.0.113.7"})
+			ctx := transport.NewServerContext(context.Background(), tr)
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := handler(ctx, req); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkResponseEncoder(b *testing.B) {
+	reply := benchListing(b, 50)
+	r := httptest.NewRequest(nhttp.MethodGet, "/v1/users", nil)
+	r.Header.Set("Accept", "application/json")
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := ResponseEncoder(httptest.NewRecorder(), r, reply); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkErrorMapping(b *testing.B) {
+	codes, err := NewBusinessCodeMapper(
+		BusinessCode{Code: 100004, Reason: "USER_NOT_FOUND", Module: "user", Status: 404},
+		BusinessCode{Code: 200004, Reason: "NOT_FOUND", Module: "order", Status: 404},
+		BusinessCode{Code: 300004, Reason: "NOT_FOUND", Module: "stock", Status: 404},
+	)
+	if err != nil {
+		b.Fatal(err)
+	}
+	h := benchService(false, "")
+	h.ErrorCodeMapper = codes.ErrorCode
+	se := errors.NotFound("NOT_FOUND", "order 42 not found").WithMetadata(map[string]string{ErrorModuleMetadataKey: "order"})
+	r := httptest.NewRequest(nhttp.MethodGet, "/v1/orders/42", nil)
+	b.ReportAllocs()
+	for b.Loop() {
+		h.enhancedErrorEncoder(httptest.NewRecorder(), r, se)
+	}
+}
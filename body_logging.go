@@ -0,0 +1,325 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// replyLogPolicy controls how reply payloads are rendered in request logs.
+type replyLogPolicy string
+
+const (
+	// replyLogPolicyTypeName logs only the Go type of the reply (e.g. "<*v1.UserReply>").
+	replyLogPolicyTypeName replyLogPolicy = "type_name"
+	// replyLogPolicyOff omits the reply body entirely.
+	replyLogPolicyOff replyLogPolicy = "off"
+	// replyLogPolicyTruncated logs the %+v rendering, cut at the configured body size.
+	replyLogPolicyTruncated replyLogPolicy = "truncated"
+	// replyLogPolicyJSON logs the JSON encoding when the reply is marshalable, falling back to the type name.
+	replyLogPolicyJSON replyLogPolicy = "json"
+
+	omittedLogBody = "<omitted>"
+)
+
+// bodyTruncation selects how bodies larger than the configured cap are shortened.
+type bodyTruncation string
+
+const (
+	// bodyTruncationCut keeps the first maxSize bytes and appends an ellipsis marker.
+	bodyTruncationCut bodyTruncation = "cut"
+	// bodyTruncationJSONPrefix cuts JSON at the last complete value and closes open objects/arrays,
+	// so the logged prefix still parses. Non-JSON bodies fall back to a hard cut.
+	bodyTruncationJSONPrefix bodyTruncation = "json_prefix"
+	// bodyTruncationHash replaces oversized bodies with a sha256 digest and their length.
+	bodyTruncationHash bodyTruncation = "hash"
+
+	// defaultMaxLogBodySize caps the rendered reply body written to logs when max_body_size is unset.
+	defaultMaxLogBodySize = 1 << 20
+
+	truncatedLogBodySuffix = "...(truncated)"
+)
+
+// bodyLogOptions is the resolved, immutable body logging configuration cached in the monitoring snapshot.
+// Building it once per configuration keeps protojson options and limits off the per-request path.
+type bodyLogOptions struct {
+	marshal    protojson.MarshalOptions
+	maxSize    int
+	truncation bodyTruncation
+}
+
+// defaultBodyLogOptions is used when no plugin configuration applies.
+var defaultBodyLogOptions = bodyLogOptions{
+	marshal:    protojson.MarshalOptions{},
+	maxSize:    defaultMaxLogBodySize,
+	truncation: bodyTruncationCut,
+}
+
+// bodyLogOptionsFromConfig builds the marshal options and truncation limits used to log replies.
+func bodyLogOptionsFromConfig(cfg *conf.BodyLoggingConfig) bodyLogOptions {
+	if cfg == nil {
+		return defaultBodyLogOptions
+	}
+	opts := bodyLogOptions{
+		marshal: protojson.MarshalOptions{
+			UseProtoNames:   cfg.UseProtoNames,
+			EmitUnpopulated: cfg.EmitUnpopulated,
+			UseEnumNumbers:  cfg.UseEnumNumbers,
+		},
+		maxSize:    defaultMaxLogBodySize,
+		truncation: bodyTruncationCut,
+	}
+	if cfg.MaxBodySize > 0 {
+		opts.maxSize = int(cfg.MaxBodySize)
+	}
+	if t, err := parseBodyTruncation(cfg.Truncation); err == nil {
+		opts.truncation = t
+	}
+	return opts
+}
+
+// parseReplyLogPolicy normalizes a configured policy name; empty selects the type-name default.
+func parseReplyLogPolicy(name string) (replyLogPolicy, error) {
+	switch p := replyLogPolicy(strings.ToLower(strings.TrimSpace(name))); p {
+	case "":
+		return replyLogPolicyTypeName, nil
+	case replyLogPolicyTypeName, replyLogPolicyOff, replyLogPolicyTruncated, replyLogPolicyJSON:
+		return p, nil
+	default:
+		return "", fmt.Errorf("invalid reply log policy %q, valid options: %v", name,
+			[]replyLogPolicy{replyLogPolicyTypeName, replyLogPolicyOff, replyLogPolicyTruncated, replyLogPolicyJSON})
+	}
+}
+
+// parseBodyTruncation normalizes a configured truncation strategy; empty selects a hard cut.
+func parseBodyTruncation(name string) (bodyTruncation, error) {
+	switch t := bodyTruncation(strings.ToLower(strings.TrimSpace(name))); t {
+	case "":
+		return bodyTruncationCut, nil
+	case bodyTruncationCut, bodyTruncationJSONPrefix, bodyTruncationHash:
+		return t, nil
+	default:
+		return "", fmt.Errorf("invalid body truncation %q, valid options: %v", name,
+			[]bodyTruncation{bodyTruncationCut, bodyTruncationJSONPrefix, bodyTruncationHash})
+	}
+}
+
+// validateBodyLoggingConfig rejects unknown policy or truncation names and negative size caps.
+func validateBodyLoggingConfig(cfg *conf.BodyLoggingConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	if _, err := parseReplyLogPolicy(cfg.ReplyPolicy); err != nil {
+		return err
+	}
+	for route, name := range cfg.RouteReplyPolicies {
+		if _, err := parseReplyLogPolicy(name); err != nil {
+			return fmt.Errorf("route %s: %w", route, err)
+		}
+	}
+	if cfg.MaxBodySize < 0 {
+		return fmt.Errorf("max body size cannot be negative")
+	}
+	if _, err := parseBodyTruncation(cfg.Truncation); err != nil {
+		return err
+	}
+	return nil
+}
+
+// replyLogPoliciesFromConfig resolves the global policy and per-route overrides for the monitoring snapshot.
+// Invalid names fall back to the type-name default; validateConfig reports them before they get here.
+func replyLogPoliciesFromConfig(cfg *conf.BodyLoggingConfig) (replyLogPolicy, map[string]replyLogPolicy) {
+	if cfg == nil {
+		return replyLogPolicyTypeName, nil
+	}
+	global, err := parseReplyLogPolicy(cfg.ReplyPolicy)
+	if err != nil {
+		global = replyLogPolicyTypeName
+	}
+	var routes map[string]replyLogPolicy
+	for route, name := range cfg.RouteReplyPolicies {
+		p, err := parseReplyLogPolicy(name)
+		if err != nil {
+			continue
+		}
+		if routes == nil {
+			routes = make(map[string]replyLogPolicy, len(cfg.RouteReplyPolicies))
+		}
+		routes[strings.TrimSpace(route)] = p
+	}
+	return global, routes
+}
+
+// replyLogPolicyFor returns the policy for an operation, preferring a per-route override.
+func (h *ServiceHttp) replyLogPolicyFor(operation string) replyLogPolicy {
+	snap := h.monitoringSnapshotOrDefault()
+	if p, ok := snap.routeReplyLogPolicies[operation]; ok {
+		return p
+	}
+	if snap.replyLogPolicy == "" {
+		return replyLogPolicyTypeName
+	}
+	return snap.replyLogPolicy
+}
+
+// replyLogBody renders a reply for the response log according to the policy configured for the operation.
+func replyLogBody(service *ServiceHttp, operation string, reply any) string {
+	if service == nil {
+		return formatReplyForLog(replyLogPolicyTypeName, reply)
+	}
+	return formatReplyForLogWith(service.replyLogPolicyFor(operation),
+		service.monitoringSnapshotOrDefault().bodyLog, reply)
+}
+
+func formatReplyForLog(policy replyLogPolicy, reply any) string {
+	return formatReplyForLogWith(policy, defaultBodyLogOptions, reply)
+}
+
+func formatReplyForLogWith(policy replyLogPolicy, opts bodyLogOptions, reply any) string {
+	switch policy {
+	case replyLogPolicyOff:
+		return omittedLogBody
+	case replyLogPolicyTruncated:
+		if reply == nil {
+			return summarizePayload(reply)
+		}
+		return opts.truncate(fmt.Sprintf("%+v", reply))
+	case replyLogPolicyJSON:
+		if opts.truncation == bodyTruncationHash {
+			if summary, ok := hashOversizedProto(opts, reply); ok {
+				return summary
+			}
+		}
+		if body, ok := safeProtoToJSON(opts, reply); ok {
+			return opts.truncate(body)
+		}
+		return summarizePayload(reply)
+	default:
+		return summarizePayload(reply)
+	}
+}
+
+// safeProtoToJSON encodes a reply as JSON for logging: protojson for proto messages, encoding/json otherwise.
+// It reports false when the value cannot be marshaled so callers can fall back to the type name. Oversized
+// output is returned whole; callers apply the configured truncation.
+func safeProtoToJSON(opts bodyLogOptions, v any) (string, bool) {
+	if v == nil {
+		return "", false
+	}
+	if msg, ok := v.(proto.Message); ok {
+		data, err := opts.marshal.Marshal(msg)
+		if err != nil {
+			return "", false
+		}
+		return string(data), true
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// hashOversizedProto summarizes a proto reply that exceeds the cap from its wire bytes, which is
+// cheaper than producing JSON only to hash it.
+func hashOversizedProto(opts bodyLogOptions, v any) (string, bool) {
+	msg, ok := v.(proto.Message)
+	if !ok || proto.Size(msg) <= opts.maxSize {
+		return "", false
+	}
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return "", false
+	}
+	return hashLogBody(data), true
+}
+
+// truncate applies the configured strategy to a body larger than maxSize.
+func (o bodyLogOptions) truncate(body string) string {
+	if len(body) <= o.maxSize {
+		return body
+	}
+	switch o.truncation {
+	case bodyTruncationHash:
+		return hashLogBody([]byte(body))
+	case bodyTruncationJSONPrefix:
+		if prefix, ok := truncateJSONPrefix(body, o.maxSize); ok {
+			return prefix
+		}
+	}
+	return body[:o.maxSize] + truncatedLogBodySuffix
+}
+
+func hashLogBody(body []byte) string {
+	sum := sha256.Sum256(body)
+	return fmt.Sprintf("<sha256:%s len=%d>", hex.EncodeToString(sum[:]), len(body))
+}
+
+// truncateJSONPrefix shortens a JSON object or array to at most limit bytes (plus closing brackets) by
+// cutting after the last complete member and closing every open container. It reports false for
+// non-JSON input or when no member fits, so callers can fall back to a hard cut.
+func truncateJSONPrefix(body string, limit int) (string, bool) {
+	trimmed := strings.TrimLeft(body, " \t\r\n")
+	if trimmed == "" || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return "", false
+	}
+
+	var (
+		stack    []byte // open containers
+		inString bool
+		escaped  bool
+		cut      = -1   // end offset (exclusive) of the last safe cut point
+		cutStack []byte // containers open at the cut point
+	)
+	for i := 0; i < len(trimmed) && i < limit; i++ {
+		c := trimmed[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			stack = append(stack, c)
+		case '}', ']':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			cut = i + 1
+			cutStack = append(cutStack[:0], stack...)
+		case ',':
+			// Everything before a top-level separator inside a container is a complete member.
+			cut = i
+			cutStack = append(cutStack[:0], stack...)
+		}
+	}
+	if cut <= 0 {
+		return "", false
+	}
+
+	var b strings.Builder
+	b.Grow(cut + len(cutStack))
+	b.WriteString(trimmed[:cut])
+	for i := len(cutStack) - 1; i >= 0; i-- {
+		if cutStack[i] == '{' {
+			b.WriteByte('}')
+		} else {
+			b.WriteByte(']')
+		}
+	}
+	return b.String(), true
+}
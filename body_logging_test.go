@@ -0,0 +1,131 @@
+package http
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestParseReplyLogPolicy(t *testing.T) {
+	p, err := parseReplyLogPolicy("")
+	require.NoError(t, err)
+	assert.Equal(t, replyLogPolicyTypeName, p)
+
+	p, err = parseReplyLogPolicy(" JSON ")
+	require.NoError(t, err)
+	assert.Equal(t, replyLogPolicyJSON, p)
+
+	_, err = parseReplyLogPolicy("everything")
+	require.Error(t, err)
+}
+
+func TestFormatReplyForLog(t *testing.T) {
+	type reply struct {
+		Name string `json:"name"`
+	}
+
+	assert.Equal(t, omittedLogBody, formatReplyForLog(replyLogPolicyOff, reply{Name: "a"}))
+	assert.Equal(t, "<http.reply>", formatReplyForLog(replyLogPolicyTypeName, reply{Name: "a"}))
+	assert.Equal(t, `{"name":"a"}`, formatReplyForLog(replyLogPolicyJSON, reply{Name: "a"}))
+	assert.Equal(t, `"x"`, formatReplyForLog(replyLogPolicyJSON, wrapperspb.String("x")))
+	assert.Equal(t, "<chan int>", formatReplyForLog(replyLogPolicyJSON, make(chan int)))
+
+	long := strings.Repeat("a", defaultMaxLogBodySize+10)
+	out := formatReplyForLog(replyLogPolicyTruncated, long)
+	assert.True(t, strings.HasSuffix(out, "...(truncated)"))
+	assert.Len(t, out, defaultMaxLogBodySize+len("...(truncated)"))
+}
+
+func TestReplyLogPolicyFor_RouteOverride(t *testing.T) {
+	svc := NewServiceHttp()
+	svc.conf = &conf.Http{Monitoring: &conf.MonitoringConfig{
+		BodyLogging: &conf.BodyLoggingConfig{
+			ReplyPolicy:        "off",
+			RouteReplyPolicies: map[string]string{"/api.v1.Users/Get": "json"},
+		},
+	}}
+	svc.refreshMonitoringSnapshotLocked()
+
+	assert.Equal(t, replyLogPolicyOff, svc.replyLogPolicyFor("/api.v1.Users/List"))
+	assert.Equal(t, replyLogPolicyJSON, svc.replyLogPolicyFor("/api.v1.Users/Get"))
+	assert.Equal(t, replyLogPolicyTypeName, NewServiceHttp().replyLogPolicyFor("/any"))
+}
+
+func TestValidateConfig_InvalidReplyLogPolicy(t *testing.T) {
+	svc := NewServiceHttp()
+	svc.conf = &conf.Http{Monitoring: &conf.MonitoringConfig{
+		BodyLogging: &conf.BodyLoggingConfig{RouteReplyPolicies: map[string]string{"/op": "dump"}},
+	}}
+	err := svc.validateConfigLocked()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid body logging configuration")
+}
+
+func TestReplyLogBody_UsesConfiguredMarshalOptions(t *testing.T) {
+	svc := NewServiceHttp()
+	svc.conf = &conf.Http{Monitoring: &conf.MonitoringConfig{
+		BodyLogging: &conf.BodyLoggingConfig{ReplyPolicy: "json", EmitUnpopulated: true, UseProtoNames: true},
+	}}
+	svc.refreshMonitoringSnapshotLocked()
+
+	// protojson output is not byte-stable, so compare on the field names only.
+	out := replyLogBody(svc, "/op", &conf.CorsConfig{})
+	assert.Contains(t, out, `"allowed_origins"`)
+	assert.Contains(t, out, `"max_age"`)
+}
+
+func TestFormatReplyForLog_TruncatesOversizedProto(t *testing.T) {
+	big := wrapperspb.String(strings.Repeat("x", defaultMaxLogBodySize+1))
+	out := formatReplyForLog(replyLogPolicyJSON, big)
+	assert.True(t, strings.HasPrefix(out, `"xxx`), out)
+	assert.True(t, strings.HasSuffix(out, truncatedLogBodySuffix), out)
+	assert.Len(t, out, defaultMaxLogBodySize+len(truncatedLogBodySuffix))
+
+	list := &structpb.ListValue{}
+	for i := 0; i < defaultMaxLogBodySize; i++ {
+		list.Values = append(list.Values, structpb.NewNumberValue(float64(i)))
+	}
+	prefix := bodyLogOptions{marshal: defaultBodyLogOptions.marshal, maxSize: 64, truncation: bodyTruncationJSONPrefix}
+	out = formatReplyForLogWith(replyLogPolicyJSON, prefix, list)
+	assert.True(t, json.Valid([]byte(out)), out)
+	assert.True(t, strings.HasPrefix(out, "[0"), out)
+	assert.LessOrEqual(t, len(out), 65)
+}
+
+func TestBodyLogOptions_Truncation(t *testing.T) {
+	body := `{"items":[{"id":1},{"id":2},{"id":3}],"total":3}`
+
+	cut := bodyLogOptions{maxSize: 10, truncation: bodyTruncationCut}
+	assert.Equal(t, body[:10]+truncatedLogBodySuffix, cut.truncate(body))
+
+	prefix := bodyLogOptions{maxSize: 30, truncation: bodyTruncationJSONPrefix}
+	out := prefix.truncate(body)
+	assert.Equal(t, `{"items":[{"id":1},{"id":2}]}`, out)
+	assert.True(t, json.Valid([]byte(out)))
+
+	// Non-JSON input falls back to a hard cut.
+	plain := strings.Repeat("p", 40)
+	assert.Equal(t, plain[:30]+truncatedLogBodySuffix, prefix.truncate(plain))
+
+	hash := bodyLogOptions{maxSize: 10, truncation: bodyTruncationHash}
+	assert.Regexp(t, `^<sha256:[0-9a-f]{64} len=48>$`, hash.truncate(body))
+
+	// Bodies within the cap are left untouched.
+	assert.Equal(t, body, bodyLogOptions{maxSize: len(body), truncation: bodyTruncationHash}.truncate(body))
+}
+
+func TestValidateBodyLoggingConfig_LimitsAndTruncation(t *testing.T) {
+	require.Error(t, validateBodyLoggingConfig(&conf.BodyLoggingConfig{MaxBodySize: -1}))
+	require.Error(t, validateBodyLoggingConfig(&conf.BodyLoggingConfig{Truncation: "zip"}))
+	require.NoError(t, validateBodyLoggingConfig(&conf.BodyLoggingConfig{MaxBodySize: 512, Truncation: "json_prefix"}))
+
+	opts := bodyLogOptionsFromConfig(&conf.BodyLoggingConfig{MaxBodySize: 512, Truncation: "hash"})
+	assert.Equal(t, 512, opts.maxSize)
+	assert.Equal(t, bodyTruncationHash, opts.truncation)
+}
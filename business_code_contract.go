@@ -0,0 +1,152 @@
+package http
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// BusinessCodeContractUpdateEnv makes VerifyContractFile rewrite the golden file instead of checking it.
+// Set it from a go:generate directive next to the contract test:
+//
+//	//go:generate env LYNX_UPDATE_BUSINESS_CODES=1 go test -run TestBusinessCodeContract .
+const BusinessCodeContractUpdateEnv = "LYNX_UPDATE_BUSINESS_CODES"
+
+// businessCodeContractHeader is the first line of contract files.
+const businessCodeContractHeader = "# code module reason status"
+
+// Codes returns the registered mappings ordered by code.
+func (m *BusinessCodeMapper) Codes() []BusinessCode {
+	m.mu.RLock()
+	out := make([]BusinessCode, 0, len(m.byCode))
+	for _, c := range m.byCode {
+		out = append(out, c)
+	}
+	m.mu.RUnlock()
+	slices.SortFunc(out, func(a, b BusinessCode) int { return cmp.Compare(a.Code, b.Code) })
+	return out
+}
+
+// WriteContract writes the mappings as a contract: one "code module reason status" line per code, ordered
+// by code, with "-" for an empty module.
+func (m *BusinessCodeMapper) WriteContract(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, businessCodeContractHeader)
+	for _, c := range m.Codes() {
+		module := c.Module
+		if module == "" {
+			module = "-"
+		}
+		fmt.Fprintf(bw, "%d %s %s %d\n", c.Code, module, c.Reason, c.Status)
+	}
+	return bw.Flush()
+}
+
+// VerifyContract compares the mappings with a contract written by WriteContract. It reports every reason
+// whose code or status changed, every recorded reason that is no longer registered, and every registered
+// reason missing from the contract; the first two break API consumers decoding the codes.
+func (m *BusinessCodeMapper) VerifyContract(r io.Reader) error {
+	recorded, err := readBusinessCodeContract(r)
+	if err != nil {
+		return err
+	}
+	current := make(map[string]BusinessCode)
+	for _, c := range m.Codes() {
+		current[businessCodeKey(c.Module, c.Reason)] = c
+	}
+
+	var problems []string
+	for _, want := range recorded {
+		key := businessCodeKey(want.Module, want.Reason)
+		got, ok := current[key]
+		delete(current, key)
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("breaking: %s (code %d) is no longer registered", contractName(want), want.Code))
+		case got.Code != want.Code:
+			problems = append(problems, fmt.Sprintf("breaking: %s changed code %d to %d", contractName(want), want.Code, got.Code))
+		case got.Status != want.Status:
+			problems = append(problems, fmt.Sprintf("breaking: %s (code %d) changed status %d to %d", contractName(want), want.Code, want.Status, got.Status))
+		}
+	}
+	added := make([]BusinessCode, 0, len(current))
+	for _, c := range current {
+		added = append(added, c)
+	}
+	slices.SortFunc(added, func(a, b BusinessCode) int { return cmp.Compare(a.Code, b.Code) })
+	for _, c := range added {
+		problems = append(problems, fmt.Sprintf("not recorded: %s (code %d)", contractName(c), c.Code))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("business code contract mismatch; set %s=1 to record intended changes:\n  %s",
+			BusinessCodeContractUpdateEnv, strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// VerifyContractFile verifies the mappings against the golden file at path, or rewrites the file when
+// BusinessCodeContractUpdateEnv is set.
+func (m *BusinessCodeMapper) VerifyContractFile(path string) error {
+	if os.Getenv(BusinessCodeContractUpdateEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		if err := m.WriteContract(f); err != nil {
+			_ = f.Close()
+			return err
+		}
+		return f.Close()
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("business code contract: %w (set %s=1 to create it)", err, BusinessCodeContractUpdateEnv)
+	}
+	defer f.Close()
+	return m.VerifyContract(f)
+}
+
+func contractName(c BusinessCode) string {
+	if c.Module == "" {
+		return c.Reason
+	}
+	return c.Module + "/" + c.Reason
+}
+
+func readBusinessCodeContract(r io.Reader) ([]BusinessCode, error) {
+	var out []BusinessCode
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("business code contract line %d: want 4 fields, got %d", n, len(fields))
+		}
+		code, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("business code contract line %d: invalid code %q", n, fields[0])
+		}
+		status, err := strconv.Atoi(fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("business code contract line %d: invalid status %q", n, fields[3])
+		}
+		c := BusinessCode{Code: code, Module: fields[1], Reason: fields[2], Status: status}
+		if c.Module == "-" {
+			c.Module = ""
+		}
+		out = append(out, c)
+	}
+	return out, sc.Err()
+}
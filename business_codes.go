@@ -0,0 +1,140 @@
+package http
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/go-kratos/kratos/v2/errors"
+)
+
+// ErrorModuleMetadataKey is the Kratos error metadata key naming the module that owns an error reason.
+const ErrorModuleMetadataKey = "module"
+
+// BusinessCode is one registered mapping between a Kratos error and a response body code.
+type BusinessCode struct {
+	// Code is the body code written in the response envelope.
+	Code int
+	// Reason is the Kratos error reason.
+	Reason string
+	// Module scopes the reason; it travels in the error metadata under ErrorModuleMetadataKey. Optional.
+	Module string
+	// Status is the Kratos code of reconstructed errors. Default: 500.
+	Status int
+}
+
+// BusinessCodeMapper maps Kratos errors to body codes on the server and body codes back to Kratos errors
+// on the client, from one shared table. Use ErrorCode as ServiceHttp.ErrorCodeMapper and the mapper itself
+// as a CodeErrorMapper so service-to-service calls keep the original reason and module.
+type BusinessCodeMapper struct {
+	mu       sync.RWMutex
+	byCode   map[int]BusinessCode
+	byKey    map[string]BusinessCode
+	byReason map[string][]BusinessCode
+}
+
+// NewBusinessCodeMapper returns a mapper with the given codes registered.
+func NewBusinessCodeMapper(codes ...BusinessCode) (*BusinessCodeMapper, error) {
+	m := &BusinessCodeMapper{
+		byCode:   make(map[int]BusinessCode),
+		byKey:    make(map[string]BusinessCode),
+		byReason: make(map[string][]BusinessCode),
+	}
+	if err := m.Register(codes...); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func businessCodeKey(module, reason string) string {
+	return module + "\x00" + reason
+}
+
+// Register adds mappings. Codes and module/reason pairs must be unique, and success or system failure
+// codes cannot be reused for business errors.
+func (m *BusinessCodeMapper) Register(codes ...BusinessCode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, c := range codes {
+		if c.Reason == "" {
+			return fmt.Errorf("business code %d: reason is required", c.Code)
+		}
+		if c.Code == 0 || c.Code == 200 || c.Code == BodyCodeSystemFailure {
+			return fmt.Errorf("business code %d is reserved", c.Code)
+		}
+		if prev, dup := m.byCode[c.Code]; dup {
+			return fmt.Errorf("business code %d already registered for %s/%s", c.Code, prev.Module, prev.Reason)
+		}
+		key := businessCodeKey(c.Module, c.Reason)
+		if prev, dup := m.byKey[key]; dup {
+			return fmt.Errorf("reason %s/%s already registered as code %d", c.Module, c.Reason, prev.Code)
+		}
+		if c.Status == 0 {
+			c.Status = errors.UnknownCode
+		}
+		m.byCode[c.Code] = c
+		m.byKey[key] = c
+		m.byReason[c.Reason] = append(m.byReason[c.Reason], c)
+	}
+	return nil
+}
+
+// Validate checks the registered codes against the envelope codes of a server, as returned by
+// ServiceHttp.CodeSpace: no code may equal the success code, codes of a module with a namespace must lie in
+// it, and no module may use a code from another module's namespace.
+func (m *BusinessCodeMapper) Validate(space CodeSpace) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	codes := make([]int, 0, len(m.byCode))
+	for code := range m.byCode {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+	for _, code := range codes {
+		c := m.byCode[code]
+		if code == space.SuccessCode {
+			return fmt.Errorf("business code %d of %s/%s is the success code", code, c.Module, c.Reason)
+		}
+		if n, ok := space.namespaceOf(code); ok && n.Module != c.Module {
+			return fmt.Errorf("business code %d of %s/%s is in the namespace of %q", code, c.Module, c.Reason, n.Module)
+		}
+		for _, n := range space.Namespaces {
+			if n.Module == c.Module && !n.Contains(code) {
+				return fmt.Errorf("business code %d of %s/%s is outside its namespace [%d, %d]", code, c.Module, c.Reason, n.Min, n.Max)
+			}
+		}
+	}
+	return nil
+}
+
+// ErrorCode returns the body code for a Kratos error: the mapping for its module and reason, or for the
+// reason alone when only one module registers it. Unmapped errors keep the default code.
+func (m *BusinessCodeMapper) ErrorCode(se *errors.Error) int {
+	if se == nil {
+		return defaultErrorCode(se)
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if c, ok := m.byKey[businessCodeKey(se.Metadata[ErrorModuleMetadataKey], se.Reason)]; ok {
+		return c.Code
+	}
+	if cs := m.byReason[se.Reason]; len(cs) == 1 {
+		return cs[0].Code
+	}
+	return defaultErrorCode(se)
+}
+
+// ErrorFromCode reconstructs the Kratos error registered for code, or returns nil when code is unknown.
+func (m *BusinessCodeMapper) ErrorFromCode(code int, message string) error {
+	m.mu.RLock()
+	c, ok := m.byCode[code]
+	m.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	err := errors.New(c.Status, c.Reason, message)
+	if c.Module != "" {
+		err = err.WithMetadata(map[string]string{ErrorModuleMetadataKey: c.Module})
+	}
+	return err
+}
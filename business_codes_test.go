@@ -0,0 +1,116 @@
+package http
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBusinessCodeMapper_RoundTrip(t *testing.T) {
+	m, err := NewBusinessCodeMapper(
+		BusinessCode{Code: 100004, Reason: "USER_NOT_FOUND", Module: "user", Status: 404},
+		BusinessCode{Code: 200004, Reason: "NOT_FOUND", Module: "order", Status: 404},
+		BusinessCode{Code: 300004, Reason: "NOT_FOUND", Module: "stock", Status: 404},
+	)
+	require.NoError(t, err)
+
+	se := errors.NotFound("USER_NOT_FOUND", "no such user")
+	code := m.ErrorCode(se)
+	assert.Equal(t, 100004, code, "a reason registered once maps without module metadata")
+
+	rebuilt := errors.FromError(m.ErrorFromCode(code, "no such user"))
+	assert.Equal(t, int32(404), rebuilt.Code)
+	assert.Equal(t, "USER_NOT_FOUND", rebuilt.Reason)
+	assert.Equal(t, "user", rebuilt.Metadata[ErrorModuleMetadataKey])
+	assert.True(t, errors.Is(rebuilt, se))
+
+	scoped := errors.NotFound("NOT_FOUND", "").WithMetadata(map[string]string{ErrorModuleMetadataKey: "stock"})
+	assert.Equal(t, 300004, m.ErrorCode(scoped))
+	assert.Equal(t, 404, m.ErrorCode(errors.NotFound("NOT_FOUND", "")), "ambiguous reasons keep the default code")
+
+	assert.Nil(t, m.ErrorFromCode(999999, ""))
+}
+
+func TestBusinessCodeMapper_Register(t *testing.T) {
+	m, err := NewBusinessCodeMapper(BusinessCode{Code: 100001, Reason: "A"})
+	require.NoError(t, err)
+	assert.Error(t, m.Register(BusinessCode{Code: 100001, Reason: "B"}), "duplicate code")
+	assert.Error(t, m.Register(BusinessCode{Code: 100002, Reason: "A"}), "duplicate reason")
+	assert.Error(t, m.Register(BusinessCode{Code: BodyCodeSystemFailure, Reason: "C"}), "reserved code")
+	assert.Error(t, m.Register(BusinessCode{Code: 100003}), "missing reason")
+
+	assert.Equal(t, int32(errors.UnknownCode), errors.FromError(m.ErrorFromCode(100001, "")).Code)
+}
+
+func TestBusinessCodeMapper_Validate(t *testing.T) {
+	m, err := NewBusinessCodeMapper(
+		BusinessCode{Code: 100004, Reason: "USER_NOT_FOUND", Module: "user"},
+		BusinessCode{Code: 200004, Reason: "ORDER_NOT_FOUND", Module: "order"},
+	)
+	require.NoError(t, err)
+	space := CodeSpace{SuccessCode: 0, Namespaces: []CodeNamespace{
+		{Module: "user", Min: 100000, Max: 199999},
+		{Module: "order", Min: 200000, Max: 299999},
+	}}
+	require.NoError(t, m.Validate(space))
+
+	require.NoError(t, m.Register(BusinessCode{Code: 300001, Reason: "USER_LOCKED", Module: "user"}))
+	assert.ErrorContains(t, m.Validate(space), "outside its namespace")
+	m, err = NewBusinessCodeMapper(BusinessCode{Code: 100009, Reason: "OUT_OF_STOCK", Module: "stock"})
+	require.NoError(t, err)
+	assert.ErrorContains(t, m.Validate(space), `in the namespace of "user"`)
+	assert.ErrorContains(t, m.Validate(CodeSpace{SuccessCode: 100009}), "is the success code")
+}
+
+func TestBusinessCodeMapper_DecodeResponse(t *testing.T) {
+	m, err := NewBusinessCodeMapper(BusinessCode{Code: 100004, Reason: "USER_NOT_FOUND", Module: "user", Status: 404})
+	require.NoError(t, err)
+
+	_, err = DecodeBody[decodeUser]("", []byte(`{"code":100004,"message":"gone"}`), WithCodeErrorMapper(m))
+	assert.Equal(t, "USER_NOT_FOUND", errors.Reason(err))
+	assert.Equal(t, 404, int(errors.Code(err)))
+}
+
+func TestBusinessCodeMapper_Contract(t *testing.T) {
+	m, err := NewBusinessCodeMapper(
+		BusinessCode{Code: 200004, Reason: "NOT_FOUND", Module: "order", Status: 404},
+		BusinessCode{Code: 100004, Reason: "USER_NOT_FOUND", Module: "user", Status: 404},
+		BusinessCode{Code: 100009, Reason: "LEGACY"},
+	)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, m.WriteContract(&buf))
+	assert.Equal(t, "# code module reason status\n100004 user USER_NOT_FOUND 404\n100009 - LEGACY 500\n200004 order NOT_FOUND 404\n", buf.String())
+	require.NoError(t, m.VerifyContract(bytes.NewReader(buf.Bytes())))
+
+	// A module moved to another base and a reason dropped break consumers; a new code only needs recording.
+	changed, err := NewBusinessCodeMapper(
+		BusinessCode{Code: 300004, Reason: "NOT_FOUND", Module: "order", Status: 404},
+		BusinessCode{Code: 100004, Reason: "USER_NOT_FOUND", Module: "user", Status: 404},
+		BusinessCode{Code: 100010, Reason: "USER_LOCKED", Module: "user", Status: 403},
+	)
+	require.NoError(t, err)
+	err = changed.VerifyContract(bytes.NewReader(buf.Bytes()))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "breaking: LEGACY (code 100009) is no longer registered")
+	assert.Contains(t, err.Error(), "breaking: order/NOT_FOUND changed code 200004 to 300004")
+	assert.Contains(t, err.Error(), "not recorded: user/USER_LOCKED (code 100010)")
+	assert.Error(t, m.VerifyContract(bytes.NewReader([]byte("100004 user\n"))))
+}
+
+func TestBusinessCodeMapper_VerifyContractFile(t *testing.T) {
+	m, err := NewBusinessCodeMapper(BusinessCode{Code: 100004, Reason: "USER_NOT_FOUND", Module: "user", Status: 404})
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "testdata", "business_codes.golden")
+
+	assert.ErrorContains(t, m.VerifyContractFile(path), BusinessCodeContractUpdateEnv)
+	t.Setenv(BusinessCodeContractUpdateEnv, "1")
+	require.NoError(t, m.VerifyContractFile(path))
+	t.Setenv(BusinessCodeContractUpdateEnv, "")
+	assert.NoError(t, m.VerifyContractFile(path))
+}
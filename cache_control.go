@@ -0,0 +1,226 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	nhttp "net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	cacheControlHeader = "Cache-Control"
+	varyHeader         = "Vary"
+
+	cacheVisibilityPublic  = "public"
+	cacheVisibilityPrivate = "private"
+)
+
+// cacheControlRule is a resolved CacheControlRule.
+type cacheControlRule struct {
+	operation string
+	methods   []string
+	// header is the Cache-Control value, "" when the rule sets none.
+	header string
+	vary   []string
+}
+
+// cacheControlPolicy is the resolved CacheControlConfig.
+type cacheControlPolicy struct {
+	rules []cacheControlRule
+	// fallback applies to routes no rule matches; nil sets no headers.
+	fallback *cacheControlRule
+}
+
+// newCacheControlPolicy returns nil without rules or a default.
+func newCacheControlPolicy(cfg *conf.CacheControlConfig) *cacheControlPolicy {
+	if len(cfg.GetRules()) == 0 && cfg.GetDefault() == nil {
+		return nil
+	}
+	p := &cacheControlPolicy{}
+	for _, rule := range cfg.GetRules() {
+		if op := strings.TrimSpace(rule.GetOperation()); op != "" {
+			r := newCacheControlRule(rule)
+			r.operation = op
+			p.rules = append(p.rules, r)
+		}
+	}
+	if cfg.GetDefault() != nil {
+		r := newCacheControlRule(cfg.GetDefault())
+		p.fallback = &r
+	}
+	return p
+}
+
+func newCacheControlRule(rule *conf.CacheControlRule) cacheControlRule {
+	r := cacheControlRule{methods: []string{nhttp.MethodGet, nhttp.MethodHead}, header: cacheControlValue(rule)}
+	if len(rule.GetMethods()) > 0 {
+		r.methods = nil
+		for _, m := range rule.GetMethods() {
+			r.methods = append(r.methods, strings.ToUpper(strings.TrimSpace(m)))
+		}
+	}
+	for _, v := range rule.GetVary() {
+		if v = nhttp.CanonicalHeaderKey(strings.TrimSpace(v)); v != "" {
+			r.vary = append(r.vary, v)
+		}
+	}
+	return r
+}
+
+// cacheControlValue renders the directives of rule in their conventional order.
+func cacheControlValue(rule *conf.CacheControlRule) string {
+	if rule.GetNoStore() {
+		return "no-store"
+	}
+	var directives []string
+	if v := strings.ToLower(strings.TrimSpace(rule.GetVisibility())); v != "" {
+		directives = append(directives, v)
+	}
+	if rule.GetNoCache() {
+		directives = append(directives, "no-cache")
+	}
+	seconds := func(name string, d time.Duration) {
+		directives = append(directives, name+"="+strconv.FormatInt(int64(d/time.Second), 10))
+	}
+	if rule.GetMaxAge() != nil {
+		seconds("max-age", rule.GetMaxAge().AsDuration())
+	}
+	if rule.GetSMaxage() != nil {
+		seconds("s-maxage", rule.GetSMaxage().AsDuration())
+	}
+	if rule.GetStaleWhileRevalidate() != nil {
+		seconds("stale-while-revalidate", rule.GetStaleWhileRevalidate().AsDuration())
+	}
+	if rule.GetMustRevalidate() {
+		directives = append(directives, "must-revalidate")
+	}
+	if rule.GetImmutable() {
+		directives = append(directives, "immutable")
+	}
+	return strings.Join(directives, ", ")
+}
+
+// ruleFor returns the rule of a request, or nil.
+func (p *cacheControlPolicy) ruleFor(method, operation, path string) *cacheControlRule {
+	rule := p.fallback
+	for i := range p.rules {
+		if wildcardMatches(p.rules[i].operation, operation) || wildcardMatches(p.rules[i].operation, path) {
+			rule = &p.rules[i]
+			break
+		}
+	}
+	if rule == nil || !slices.Contains(rule.methods, method) {
+		return nil
+	}
+	return rule
+}
+
+// cacheControlMiddleware sets the Cache-Control and Vary headers of successful replies.
+func cacheControlMiddleware(p *cacheControlPolicy) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			reply, err := handler(ctx, req)
+			if err != nil {
+				return reply, err
+			}
+			tr, ok := transport.FromServerContext(ctx)
+			if !ok {
+				return reply, err
+			}
+			var method, path string
+			if r, ok := http.RequestFromServerContext(ctx); ok && r != nil {
+				method, path = r.Method, r.URL.Path
+			}
+			rule := p.ruleFor(method, tr.Operation(), path)
+			if rule == nil {
+				return reply, err
+			}
+			header := tr.ReplyHeader()
+			if rule.header != "" && header.Get(cacheControlHeader) == "" {
+				header.Set(cacheControlHeader, rule.header)
+			}
+			if len(rule.vary) > 0 {
+				header.Set(varyHeader, mergeVary(header.Get(varyHeader), rule.vary))
+			}
+			return reply, err
+		}
+	}
+}
+
+// mergeVary adds the headers of vary missing from the Vary value current.
+func mergeVary(current string, vary []string) string {
+	var values []string
+	for _, v := range strings.Split(current, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	for _, v := range vary {
+		if !slices.ContainsFunc(values, func(s string) bool { return strings.EqualFold(s, v) }) {
+			values = append(values, v)
+		}
+	}
+	return strings.Join(values, ", ")
+}
+
+// validateCacheControlConfig requires operations on rules, known visibilities and non-negative ages, and
+// rejects no-store combined with directives it overrides.
+func validateCacheControlConfig(cfg *conf.CacheControlConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	for i, rule := range cfg.GetRules() {
+		if strings.TrimSpace(rule.GetOperation()) == "" {
+			return fmt.Errorf("rules[%d]: operation is required", i)
+		}
+		if err := validateCacheControlRule(rule); err != nil {
+			return fmt.Errorf("rules[%d]: %w", i, err)
+		}
+	}
+	if cfg.GetDefault() != nil {
+		if err := validateCacheControlRule(cfg.GetDefault()); err != nil {
+			return fmt.Errorf("default: %w", err)
+		}
+	}
+	return nil
+}
+
+func validateCacheControlRule(rule *conf.CacheControlRule) error {
+	switch v := strings.ToLower(strings.TrimSpace(rule.GetVisibility())); v {
+	case "", cacheVisibilityPublic, cacheVisibilityPrivate:
+	default:
+		return fmt.Errorf("unknown visibility %q, valid options: %s, %s", v, cacheVisibilityPublic, cacheVisibilityPrivate)
+	}
+	ages := []struct {
+		name string
+		age  *durationpb.Duration
+	}{
+		{"max_age", rule.GetMaxAge()},
+		{"s_maxage", rule.GetSMaxage()},
+		{"stale_while_revalidate", rule.GetStaleWhileRevalidate()},
+	}
+	hasAge := false
+	for _, a := range ages {
+		if a.age == nil {
+			continue
+		}
+		hasAge = true
+		if a.age.AsDuration() < 0 {
+			return fmt.Errorf("%s must not be negative", a.name)
+		}
+	}
+	if rule.GetNoStore() && (hasAge || rule.GetVisibility() != "" || rule.GetNoCache() ||
+		rule.GetMustRevalidate() || rule.GetImmutable()) {
+		return fmt.Errorf("no_store cannot be combined with other directives")
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package http
+
+import (
+	"context"
+	nhttp "net/http"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx-http/httptesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestCacheControlValue(t *testing.T) {
+	assert.Equal(t, "public, max-age=60, s-maxage=300, stale-while-revalidate=30, immutable",
+		cacheControlValue(&conf.CacheControlRule{
+			Visibility:           "Public",
+			MaxAge:               durationpb.New(time.Minute),
+			SMaxage:              durationpb.New(5 * time.Minute),
+			StaleWhileRevalidate: durationpb.New(30 * time.Second),
+			Immutable:            true,
+		}))
+	assert.Equal(t, "private, no-cache, must-revalidate",
+		cacheControlValue(&conf.CacheControlRule{Visibility: "private", NoCache: true, MustRevalidate: true}))
+	assert.Equal(t, "no-store", cacheControlValue(&conf.CacheControlRule{NoStore: true}))
+	assert.Equal(t, "max-age=0", cacheControlValue(&conf.CacheControlRule{MaxAge: durationpb.New(0)}))
+}
+
+func TestCacheControlMiddleware(t *testing.T) {
+	p := newCacheControlPolicy(&conf.CacheControlConfig{
+		Rules: []*conf.CacheControlRule{
+			{Operation: "/api.v1.Catalog/*", Visibility: "public", MaxAge: durationpb.New(time.Minute), Vary: []string{"accept-language"}},
+			{Operation: "/api.v1.Me/Get", Visibility: "private", NoCache: true, Vary: []string{"Authorization"}},
+		},
+		Default: &conf.CacheControlRule{NoStore: true},
+	})
+	tester := httptesting.NewMiddlewareTester(t, cacheControlMiddleware(p))
+
+	res := tester.Run(httptesting.Request{Operation: "/api.v1.Catalog/List"})
+	res.AssertNoError()
+	assert.Equal(t, "public, max-age=60", res.Transport.ReplyHeader().Get(cacheControlHeader))
+	assert.Equal(t, "Accept-Language", res.Transport.ReplyHeader().Get(varyHeader))
+
+	res = tester.Run(httptesting.Request{Operation: "/api.v1.Orders/List"})
+	assert.Equal(t, "no-store", res.Transport.ReplyHeader().Get(cacheControlHeader), "default")
+
+	res = tester.Run(httptesting.Request{Operation: "/api.v1.Catalog/List", Method: nhttp.MethodPost})
+	assert.Empty(t, res.Transport.ReplyHeader().Get(cacheControlHeader), "GET and HEAD only by default")
+
+	// Handlers keep their own Cache-Control and add to Vary.
+	own := func(next middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			tr, _ := transport.FromServerContext(ctx)
+			tr.ReplyHeader().Set(cacheControlHeader, "max-age=5")
+			tr.ReplyHeader().Set(varyHeader, "Accept-Encoding, authorization")
+			return next(ctx, req)
+		}
+	}
+	res = httptesting.NewMiddlewareTester(t, cacheControlMiddleware(p), own).Run(httptesting.Request{Operation: "/api.v1.Me/Get"})
+	assert.Equal(t, "max-age=5", res.Transport.ReplyHeader().Get(cacheControlHeader))
+	assert.Equal(t, "Accept-Encoding, authorization", res.Transport.ReplyHeader().Get(varyHeader))
+
+	failing := httptesting.NewMiddlewareTester(t, cacheControlMiddleware(p)).
+		WithHandler(func(context.Context, any) (any, error) { return nil, errors.NotFound("NOT_FOUND", "missing") })
+	res = failing.Run(httptesting.Request{Operation: "/api.v1.Catalog/Get"})
+	assert.Empty(t, res.Transport.ReplyHeader().Get(cacheControlHeader), "errors are not cached")
+}
+
+func TestValidateCacheControlConfig(t *testing.T) {
+	require.NoError(t, validateCacheControlConfig(nil))
+	require.NoError(t, validateCacheControlConfig(&conf.CacheControlConfig{
+		Rules: []*conf.CacheControlRule{{Operation: "/api.v1.Catalog/*", Visibility: "public", MaxAge: durationpb.New(time.Minute)}},
+	}))
+	assert.ErrorContains(t, validateCacheControlConfig(&conf.CacheControlConfig{
+		Rules: []*conf.CacheControlRule{{Visibility: "public"}}}), "rules[0]: operation is required")
+	assert.ErrorContains(t, validateCacheControlConfig(&conf.CacheControlConfig{
+		Default: &conf.CacheControlRule{Visibility: "shared"}}), `default: unknown visibility "shared"`)
+	assert.ErrorContains(t, validateCacheControlConfig(&conf.CacheControlConfig{
+		Default: &conf.CacheControlRule{SMaxage: durationpb.New(-time.Second)}}), "s_maxage must not be negative")
+	assert.ErrorContains(t, validateCacheControlConfig(&conf.CacheControlConfig{
+		Default: &conf.CacheControlRule{NoStore: true, MaxAge: durationpb.New(time.Minute)}}), "cannot be combined")
+}
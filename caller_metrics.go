@@ -0,0 +1,137 @@
+package http
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+)
+
+const (
+	defaultCallerHeader = "X-Caller-Service"
+	defaultMaxCallers   = 100
+	// maxCallerLabelLength bounds a single caller value so a hostile header cannot create huge labels.
+	maxCallerLabelLength = 64
+
+	callerUnknown = "unknown"
+	callerOther   = "other"
+)
+
+// callerLabeler resolves the caller of a request to a bounded set of metric label values.
+// It is built per configuration and cached in the monitoring snapshot, so the set of
+// admitted callers starts over after a reconfiguration.
+type callerLabeler struct {
+	header     string
+	useTLS     bool
+	maxCallers int
+	allowed    map[string]struct{}
+
+	mu   sync.RWMutex
+	seen map[string]struct{}
+}
+
+// newCallerLabeler returns nil when caller metrics are disabled.
+func newCallerLabeler(cfg *conf.CallerMetricsConfig) *callerLabeler {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	l := &callerLabeler{
+		header:     strings.TrimSpace(cfg.Header),
+		useTLS:     cfg.UseTlsIdentity,
+		maxCallers: defaultMaxCallers,
+		seen:       make(map[string]struct{}),
+	}
+	if l.header == "" {
+		l.header = defaultCallerHeader
+	}
+	if cfg.MaxCallers > 0 {
+		l.maxCallers = int(cfg.MaxCallers)
+	}
+	for _, name := range cfg.AllowedCallers {
+		if name = strings.TrimSpace(name); name != "" {
+			if l.allowed == nil {
+				l.allowed = make(map[string]struct{}, len(cfg.AllowedCallers))
+			}
+			l.allowed[name] = struct{}{}
+		}
+	}
+	return l
+}
+
+// callerIdentity extracts the raw caller name from the configured header or, optionally,
+// the common name of the verified mTLS client certificate.
+func (l *callerLabeler) callerIdentity(ctx context.Context) string {
+	if tr, ok := transport.FromServerContext(ctx); ok {
+		if v := strings.TrimSpace(tr.RequestHeader().Get(l.header)); v != "" {
+			return v
+		}
+	}
+	if l.useTLS {
+		if req, ok := http.RequestFromServerContext(ctx); ok && req != nil && req.TLS != nil && len(req.TLS.PeerCertificates) > 0 {
+			return strings.TrimSpace(req.TLS.PeerCertificates[0].Subject.CommonName)
+		}
+	}
+	return ""
+}
+
+// label maps a caller name to its metric label: "unknown" when absent, "other" when it is not
+// allowed or the distinct-caller cap has been reached.
+func (l *callerLabeler) label(caller string) string {
+	if caller == "" {
+		return callerUnknown
+	}
+	if len(caller) > maxCallerLabelLength {
+		caller = caller[:maxCallerLabelLength]
+	}
+	if l.allowed != nil {
+		if _, ok := l.allowed[caller]; !ok {
+			return callerOther
+		}
+		return caller
+	}
+
+	l.mu.RLock()
+	_, known := l.seen[caller]
+	l.mu.RUnlock()
+	if known {
+		return caller
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, known = l.seen[caller]; known {
+		return caller
+	}
+	if len(l.seen) >= l.maxCallers {
+		return callerOther
+	}
+	l.seen[caller] = struct{}{}
+	return caller
+}
+
+// callerLabel returns the caller label for the request and whether caller metrics are enabled.
+func (h *ServiceHttp) callerLabel(ctx context.Context) (string, bool) {
+	l := h.monitoringSnapshotOrDefault().callers
+	if l == nil {
+		return "", false
+	}
+	return l.label(l.callerIdentity(ctx)), true
+}
+
+// recordCallerMetrics records the per-caller request counter and duration when caller metrics are enabled.
+func (h *ServiceHttp) recordCallerMetrics(ctx context.Context, route, method, status string, seconds float64) {
+	if h == nil || h.callerRequestCounter == nil {
+		return
+	}
+	caller, ok := h.callerLabel(ctx)
+	if !ok {
+		return
+	}
+	h.callerRequestCounter.WithLabelValues(caller, route, method, status).Inc()
+	if h.callerRequestDuration != nil {
+		h.callerRequestDuration.WithLabelValues(caller, route).Observe(seconds)
+	}
+}
@@ -0,0 +1,117 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	nhttp "net/http"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCallerLabeler_Disabled(t *testing.T) {
+	assert.Nil(t, newCallerLabeler(nil))
+	assert.Nil(t, newCallerLabeler(&conf.CallerMetricsConfig{}))
+
+	_, ok := NewServiceHttp().callerLabel(context.Background())
+	assert.False(t, ok)
+}
+
+func TestCallerLabeler_CapsCardinality(t *testing.T) {
+	l := newCallerLabeler(&conf.CallerMetricsConfig{Enabled: true, MaxCallers: 2})
+	require.NotNil(t, l)
+	assert.Equal(t, defaultCallerHeader, l.header)
+
+	assert.Equal(t, callerUnknown, l.label(""))
+	assert.Equal(t, "orders", l.label("orders"))
+	assert.Equal(t, "billing", l.label("billing"))
+	assert.Equal(t, callerOther, l.label("search"))
+	// Admitted callers keep their label once the cap is reached.
+	assert.Equal(t, "orders", l.label("orders"))
+}
+
+func TestCallerLabeler_AllowList(t *testing.T) {
+	l := newCallerLabeler(&conf.CallerMetricsConfig{Enabled: true, AllowedCallers: []string{"orders", " "}})
+	assert.Equal(t, "orders", l.label("orders"))
+	assert.Equal(t, callerOther, l.label("billing"))
+}
+
+// fakeHTTPTransport exposes an *http.Request so mTLS identity lookups can be exercised.
+type fakeHTTPTransport struct {
+	*fakeTransport
+	request *nhttp.Request
+}
+
+func (f *fakeHTTPTransport) Request() *nhttp.Request { return f.request }
+func (f *fakeHTTPTransport) PathTemplate() string    { return "" }
+
+func TestCallerLabeler_Identity(t *testing.T) {
+	l := newCallerLabeler(&conf.CallerMetricsConfig{Enabled: true, Header: "X-Client", UseTlsIdentity: true})
+
+	req, err := nhttp.NewRequest(nhttp.MethodGet, "/", nil)
+	require.NoError(t, err)
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "billing"}}}}
+
+	// The header wins over the certificate.
+	tr := &fakeHTTPTransport{fakeTransport: newFakeTransport("/op", map[string]string{"X-Client": "orders"}), request: req}
+	assert.Equal(t, "orders", l.callerIdentity(transport.NewServerContext(context.Background(), tr)))
+
+	tr = &fakeHTTPTransport{fakeTransport: newFakeTransport("/op", nil), request: req}
+	ctx := transport.NewServerContext(context.Background(), tr)
+	assert.Equal(t, "billing", l.callerIdentity(ctx))
+
+	l.useTLS = false
+	assert.Equal(t, "", l.callerIdentity(ctx))
+}
+
+func TestMetricsMiddleware_RecordsCaller(t *testing.T) {
+	svc := NewServiceHttp()
+	svc.conf = &conf.Http{Monitoring: &conf.MonitoringConfig{
+		EnableMetrics:      true,
+		EnableRouteMetrics: true,
+		CallerMetrics:      &conf.CallerMetricsConfig{Enabled: true},
+	}}
+	svc.refreshMonitoringSnapshotLocked()
+	svc.initMetrics()
+	defer svc.stopMetricsLoop()
+
+	const op = "/caller.v1.Test/Record"
+	ctx := transport.NewServerContext(context.Background(),
+		newFakeTransport(op, map[string]string{defaultCallerHeader: "orders"}))
+	// The counter is package-level, so only the increment is asserted.
+	counter := svc.callerRequestCounter.WithLabelValues("orders", op, "unknown", "success")
+	before := testutil.ToFloat64(counter)
+	handler := svc.metricsMiddleware()(func(context.Context, any) (any, error) { return "ok", nil })
+	_, err := handler(ctx, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(counter)-before)
+}
+
+func TestTracerLogPackWithMetrics_RecordsCaller(t *testing.T) {
+	svc := NewServiceHttp()
+	svc.conf = &conf.Http{Monitoring: &conf.MonitoringConfig{
+		EnableMetrics: true,
+		CallerMetrics: &conf.CallerMetricsConfig{Enabled: true},
+	}}
+	svc.refreshMonitoringSnapshotLocked()
+	svc.initMetrics()
+	defer svc.stopMetricsLoop()
+
+	const op = "/caller.v1.Test/Tracer"
+	ctx := transport.NewServerContext(context.Background(),
+		newFakeTransport(op, map[string]string{defaultCallerHeader: "billing"}))
+	// The counter is package-level, so only the increment is asserted.
+	counter := svc.callerRequestCounter.WithLabelValues("billing", op, "unknown", "success")
+	before := testutil.ToFloat64(counter)
+	_, err := TracerLogPackWithMetrics(svc)(func(context.Context, any) (any, error) { return "ok", nil })(ctx, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(counter)-before)
+}
@@ -51,6 +51,9 @@ type CircuitBreaker struct {
 	// exceeds a cap derived from MaxFailures, so stale history does not
 	// permanently dilute the rate.
 	windowRequests int32
+
+	// onOpen, when set, is called each time the circuit opens.
+	onOpen func(failures, windowRequests int32)
 }
 
 // RequestGuard captures whether a request was admitted by the circuit breaker.
@@ -161,10 +164,18 @@ func (cb *CircuitBreaker) RecordFailure(guard RequestGuard) {
 	if !guard.Allowed() {
 		return
 	}
+	if failures, windowRequests, opened := cb.recordFailure(); opened && cb.onOpen != nil {
+		// Outside the lock, so the callback may read the breaker.
+		cb.onOpen(failures, windowRequests)
+	}
+}
 
+// recordFailure counts a failure and reports whether it opened the circuit, with the counters at that time.
+func (cb *CircuitBreaker) recordFailure() (failures, windowRequests int32, opened bool) {
 	cb.mutex.Lock()
 	defer cb.mutex.Unlock()
 
+	previous := cb.state
 	cb.failures++
 	cb.lastFailTime = time.Now()
 
@@ -195,6 +206,7 @@ func (cb *CircuitBreaker) RecordFailure(guard RequestGuard) {
 		log.Warnf("Circuit breaker returned to open state - failure in half-open")
 	default:
 	}
+	return cb.failures, cb.windowRequests, previous != CircuitBreakerOpen && cb.state == CircuitBreakerOpen
 }
 
 // GetState returns the current state (closed, open, or half-open).
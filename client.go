@@ -0,0 +1,381 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	nhttp "net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx/observability/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultClientTimeout      = 10 * time.Second
+	defaultClientRetryBackoff = 50 * time.Millisecond
+	maxClientRetryBackoff     = time.Second
+	clientTracerName          = "lynx-http-client"
+
+	// envelopeErrorReason is the Kratos error reason for non-success codes decoded from a response envelope.
+	envelopeErrorReason = "REMOTE_ERROR"
+
+	clientStatusError       = "error"
+	clientStatusCircuitOpen = "circuit_open"
+)
+
+// ErrClientCircuitOpen is returned by client transports while the circuit breaker rejects calls.
+var ErrClientCircuitOpen = errors.ServiceUnavailable("CIRCUIT_OPEN", "client circuit breaker is open")
+
+// ClientConfig configures clients built by NewClient and NewKratosClient.
+type ClientConfig struct {
+	// Target names the downstream service in metrics ("target" label) and client spans.
+	Target string
+	// Endpoint is the address Kratos clients call, e.g. "http://127.0.0.1:8000" or "discovery:///user".
+	Endpoint string
+	// Timeout bounds a call including retries. Default: 10s.
+	Timeout time.Duration
+	// MaxAttempts is the total number of attempts for idempotent requests. Default: 1 (no retries).
+	MaxAttempts int
+	// RetryBackoff is the base delay before a retry; it doubles per attempt with jitter, up to 1s. Default: 50ms.
+	RetryBackoff time.Duration
+	// CircuitBreaker enables a breaker for this client when non-nil; zero fields use the breaker defaults.
+	CircuitBreaker *CircuitBreakerConfig
+	// Transport is the underlying round tripper; configure TLS here. Default: net/http's DefaultTransport.
+	Transport nhttp.RoundTripper
+	// CodeErrorMapper turns envelope codes into typed errors in Kratos clients. Default: generic Kratos errors.
+	CodeErrorMapper CodeErrorMapper
+	// SuccessCodes are the envelope codes Kratos clients treat as success, matching the server's
+	// response.codes.success_code. Default: 200.
+	SuccessCodes []int
+	// Protobuf asks for binary protobuf replies (Accept: application/x-protobuf, JSON as fallback) on requests
+	// without an Accept header. DecodeEnvelope and DecodeResponse read both. Default: false.
+	Protobuf bool
+	// Dependency attributes the errors of Kratos client calls to the named dependency (see WithDependency),
+	// so handlers returning them are counted per dependency. Default: none.
+	Dependency string
+}
+
+// clientTransport adds trace propagation, deadline and retry headers, retries, circuit breaking and
+// metrics to an underlying round tripper.
+type clientTransport struct {
+	base        nhttp.RoundTripper
+	target      string
+	maxAttempts int
+	backoff     time.Duration
+	breaker     *CircuitBreaker
+	tracer      trace.Tracer
+	protobuf    bool
+}
+
+// NewClientTransport returns the instrumented round tripper used by NewClient.
+func NewClientTransport(cfg ClientConfig) nhttp.RoundTripper {
+	ensureClientMetrics()
+	t := &clientTransport{
+		base:        cfg.Transport,
+		target:      cfg.Target,
+		maxAttempts: max(cfg.MaxAttempts, 1),
+		backoff:     cfg.RetryBackoff,
+		tracer:      otel.Tracer(clientTracerName),
+		protobuf:    cfg.Protobuf,
+	}
+	if t.base == nil {
+		t.base = nhttp.DefaultTransport
+	}
+	if t.target == "" {
+		t.target = "unknown"
+	}
+	if t.backoff <= 0 {
+		t.backoff = defaultClientRetryBackoff
+	}
+	if cfg.CircuitBreaker != nil {
+		t.breaker = NewCircuitBreaker(*cfg.CircuitBreaker)
+	}
+	return t
+}
+
+// NewClient returns a net/http client wired with the instrumented transport.
+func NewClient(cfg ClientConfig) *nhttp.Client {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultClientTimeout
+	}
+	return &nhttp.Client{Transport: NewClientTransport(cfg), Timeout: timeout}
+}
+
+// NewKratosClient returns a Kratos HTTP client wired with the instrumented transport and the envelope
+// decoders matching this plugin's server encoders. Later opts override the defaults.
+func NewKratosClient(ctx context.Context, cfg ClientConfig, opts ...http.ClientOption) (*http.Client, error) {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultClientTimeout
+	}
+	defaults := []http.ClientOption{
+		http.WithEndpoint(cfg.Endpoint),
+		http.WithTimeout(timeout),
+		http.WithTransport(NewClientTransport(cfg)),
+		http.WithResponseDecoder(func(_ context.Context, res *nhttp.Response, v any) error {
+			opts := []DecodeOption{WithCodeErrorMapper(cfg.CodeErrorMapper)}
+			if len(cfg.SuccessCodes) > 0 {
+				opts = append(opts, WithSuccessCodes(cfg.SuccessCodes...))
+			}
+			return DecodeEnvelope(res, v, opts...)
+		}),
+		http.WithErrorDecoder(envelopeErrorDecoder(cfg.CodeErrorMapper)),
+	}
+	if cfg.Dependency != "" {
+		defaults = append(defaults, http.WithMiddleware(dependencyClientMiddleware(cfg.Dependency)))
+	}
+	return http.NewClient(ctx, append(defaults, opts...)...)
+}
+
+func (t *clientTransport) RoundTrip(req *nhttp.Request) (*nhttp.Response, error) {
+	ctx, span := t.tracer.Start(req.Context(), "HTTP "+req.Method, trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	attempts := t.maxAttempts
+	if !retryableRequest(req) {
+		attempts = 1
+	}
+	var (
+		res *nhttp.Response
+		err error
+	)
+	for attempt := 1; ; attempt++ {
+		res, err = t.attempt(ctx, req, attempt, attempts)
+		if attempt >= attempts || !retryableResult(res, err) || attemptBudgetSpent(res, attempt) {
+			break
+		}
+		if res != nil {
+			_, _ = io.Copy(io.Discard, res.Body)
+			_ = res.Body.Close()
+		}
+		clientRetries.WithLabelValues(t.target).Inc()
+		if !sleepCtx(ctx, t.retryDelay(attempt)) {
+			return nil, ctx.Err()
+		}
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else if res.StatusCode >= 500 {
+		span.SetStatus(codes.Error, res.Status)
+	}
+	return res, err
+}
+
+// attempt performs one try on a fresh clone of the request.
+func (t *clientTransport) attempt(ctx context.Context, orig *nhttp.Request, attempt, attempts int) (*nhttp.Response, error) {
+	var guard RequestGuard
+	if t.breaker != nil {
+		if guard = t.breaker.Allow(); !guard.Allowed() {
+			clientRequests.WithLabelValues(t.target, orig.Method, clientStatusCircuitOpen).Inc()
+			return nil, ErrClientCircuitOpen
+		}
+	}
+
+	req := orig.Clone(ctx)
+	if attempt > 1 && orig.GetBody != nil {
+		body, err := orig.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = body
+	}
+	tracePropagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+	if t.protobuf && req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", protobufAccept)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		req.Header.Set(RequestTimeoutHeader, strconv.FormatInt(max(time.Until(deadline), 0).Milliseconds(), 10))
+	}
+	if attempts > 1 {
+		req.Header.Set(RetryAttemptHeader, strconv.Itoa(attempt))
+		req.Header.Set(RetryMaxAttemptsHeader, strconv.Itoa(attempts))
+	}
+
+	start := time.Now()
+	res, err := t.base.RoundTrip(req)
+	clientDuration.WithLabelValues(t.target, orig.Method).Observe(time.Since(start).Seconds())
+
+	status := clientStatusError
+	if err == nil {
+		status = strconv.Itoa(res.StatusCode)
+	}
+	clientRequests.WithLabelValues(t.target, orig.Method, status).Inc()
+	if t.breaker != nil {
+		if err != nil || res.StatusCode >= 500 {
+			t.breaker.RecordFailure(guard)
+		} else {
+			t.breaker.RecordSuccess(guard)
+		}
+	}
+	return res, err
+}
+
+func (t *clientTransport) retryDelay(attempt int) time.Duration {
+	d := min(t.backoff<<(attempt-1), maxClientRetryBackoff)
+	// Equal jitter: half fixed, half random, so concurrent clients do not retry in lockstep.
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryableRequest reports whether a request may be sent again: idempotent methods whose body can be replayed.
+func retryableRequest(req *nhttp.Request) bool {
+	switch req.Method {
+	case nhttp.MethodGet, nhttp.MethodHead, nhttp.MethodOptions, nhttp.MethodPut, nhttp.MethodDelete:
+	default:
+		return false
+	}
+	return req.Body == nil || req.Body == nhttp.NoBody || req.GetBody != nil
+}
+
+// retryableResult retries transport errors and gateway-style failures, but not an open circuit.
+func retryableResult(res *nhttp.Response, err error) bool {
+	if err != nil {
+		return err != ErrClientCircuitOpen
+	}
+	switch res.StatusCode {
+	case nhttp.StatusBadGateway, nhttp.StatusServiceUnavailable, nhttp.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// attemptBudgetSpent honours the effective budget a Lynx server echoes in X-Retry-Max-Attempts.
+func attemptBudgetSpent(res *nhttp.Response, attempt int) bool {
+	if res == nil {
+		return false
+	}
+	budget, err := strconv.Atoi(res.Header.Get(RetryMaxAttemptsHeader))
+	return err == nil && budget > 0 && attempt >= budget
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// envelope mirrors Response for decoding: data is kept raw so it can be unmarshalled into the caller's type.
+type envelope struct {
+	Code     int             `json:"code"`
+	Message  string          `json:"message"`
+	Data     json.RawMessage `json:"data"`
+	Warnings []Warning       `json:"warnings"`
+}
+
+// DecodeEnvelope reads a {"code":…,"data":…} response body as written by ResponseEncoder and the error
+// encoders. A code other than 200 is returned as a Kratos error carrying that code; otherwise data is
+// unmarshalled into v with the codec matching the response content type. Protobuf replies, enveloped or not,
+// are unmarshalled into v, which must then be a proto.Message. See DecodeResponse for a typed variant.
+func DecodeEnvelope(res *nhttp.Response, v any, opts ...DecodeOption) error {
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if messageType, ok := protobufMessageType(res.Header.Get("Content-Type")); ok {
+		data, dataType, err := unwrapProtobuf(messageType, body, newDecodeOptions(opts))
+		if err != nil || data == nil || v == nil {
+			return err
+		}
+		return unmarshalProtobufData(data, dataType, v)
+	}
+	data, err := unwrapEnvelope(body, newDecodeOptions(opts))
+	if err != nil || data == nil || v == nil {
+		return err
+	}
+	return http.CodecForResponse(res).Unmarshal(data, v)
+}
+
+// EnvelopeResponseDecoder is a Kratos response decoder for envelope responses.
+func EnvelopeResponseDecoder(_ context.Context, res *nhttp.Response, v any) error {
+	return DecodeEnvelope(res, v)
+}
+
+// EnvelopeErrorDecoder is a Kratos error decoder for non-2xx envelope responses; the body code wins over
+// the HTTP status when present.
+func EnvelopeErrorDecoder(ctx context.Context, res *nhttp.Response) error {
+	return envelopeErrorDecoder(nil)(ctx, res)
+}
+
+func envelopeErrorDecoder(mapper CodeErrorMapper) http.DecodeErrorFunc {
+	return func(_ context.Context, res *nhttp.Response) error {
+		if res.StatusCode >= 200 && res.StatusCode <= 299 {
+			return nil
+		}
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		if err == nil {
+			var env envelope
+			if json.Unmarshal(body, &env) == nil && env.Code != 0 {
+				if mapper != nil {
+					if err := mapper.ErrorFromCode(env.Code, env.Message); err != nil {
+						return err
+					}
+				}
+				return errors.New(env.Code, envelopeErrorReason, env.Message)
+			}
+		}
+		return errors.New(res.StatusCode, errors.UnknownReason, fmt.Sprintf("unexpected HTTP status %d", res.StatusCode))
+	}
+}
+
+var (
+	clientMetricsOnce sync.Once
+	clientRequests    *prometheus.CounterVec
+	clientDuration    *prometheus.HistogramVec
+	clientRetries     *prometheus.CounterVec
+)
+
+// ensureClientMetrics registers the client collectors once; they are independent of the server plugin so
+// clients work in processes that do not serve HTTP.
+func ensureClientMetrics() {
+	clientMetricsOnce.Do(func() {
+		clientRequests = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "client_requests_total",
+				Help:      "Total number of outbound HTTP request attempts by target, method and status",
+			},
+			[]string{"target", "method", "status"},
+		)
+		clientDuration = prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "client_request_duration_seconds",
+				Help:      "Outbound HTTP request attempt duration in seconds",
+				Buckets:   defaultDurationBuckets,
+			},
+			[]string{"target", "method"},
+		)
+		clientRetries = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "client_retries_total",
+				Help:      "Total number of outbound HTTP retries by target",
+			},
+			[]string{"target"},
+		)
+		metrics.MustRegister(clientRequests, clientDuration, clientRetries)
+	})
+}
@@ -0,0 +1,284 @@
+package http
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/conf"
+)
+
+const (
+	defaultAppVersionHeader  = "X-App-Version"
+	defaultPlatformHeader    = "X-App-Platform"
+	defaultMaxClientVersions = 20
+	// maxAppVersionLength bounds a version taken from a header or User-Agent.
+	maxAppVersionLength = 32
+)
+
+// Client platforms reported by ClientInfo.
+const (
+	PlatformIOS     = "ios"
+	PlatformAndroid = "android"
+	PlatformWindows = "windows"
+	PlatformMacOS   = "macos"
+	PlatformLinux   = "linux"
+	PlatformOther   = "other"
+)
+
+// Client device classes reported by ClientInfo.
+const (
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceDesktop = "desktop"
+	DeviceBot     = "bot"
+	DeviceUnknown = "unknown"
+)
+
+// ClientInfo describes the client of a request, as classified from its User-Agent and app headers.
+type ClientInfo struct {
+	// Platform is one of the Platform* constants.
+	Platform string
+	// Device is one of the Device* constants.
+	Device string
+	// App is the configured app name found in the User-Agent, if any.
+	App string
+	// AppVersion is the version from the app version header or the app's User-Agent token; empty when unknown.
+	AppVersion string
+	// UserAgent is the raw User-Agent header.
+	UserAgent string
+
+	// versionLabel is the bounded metric label of AppVersion; empty unless client metrics are enabled.
+	versionLabel string
+}
+
+type clientInfoKey struct{}
+
+// ClientInfoFromContext returns the client of the current request when client_info is enabled.
+func ClientInfoFromContext(ctx context.Context) (ClientInfo, bool) {
+	info, ok := ctx.Value(clientInfoKey{}).(*ClientInfo)
+	if !ok {
+		return ClientInfo{}, false
+	}
+	return *info, true
+}
+
+// clientClassifier is the resolved ClientInfoConfig.
+type clientClassifier struct {
+	versionHeader  string
+	platformHeader string
+	appNames       []string
+	// versions is nil unless client metrics are enabled.
+	versions *versionLabeler
+}
+
+// newClientClassifier returns nil when client detection is disabled.
+func newClientClassifier(cfg *conf.ClientInfoConfig) *clientClassifier {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	c := &clientClassifier{
+		versionHeader:  strings.TrimSpace(cfg.AppVersionHeader),
+		platformHeader: strings.TrimSpace(cfg.PlatformHeader),
+	}
+	if c.versionHeader == "" {
+		c.versionHeader = defaultAppVersionHeader
+	}
+	if c.platformHeader == "" {
+		c.platformHeader = defaultPlatformHeader
+	}
+	for _, name := range cfg.AppNames {
+		if name = strings.TrimSpace(name); name != "" {
+			c.appNames = append(c.appNames, name)
+		}
+	}
+	if cfg.Metrics {
+		c.versions = &versionLabeler{max: defaultMaxClientVersions, seen: make(map[string]struct{})}
+		if cfg.MaxVersions > 0 {
+			c.versions.max = int(cfg.MaxVersions)
+		}
+	}
+	return c
+}
+
+// classify builds the ClientInfo of a request from its headers.
+func (c *clientClassifier) classify(header transport.Header) *ClientInfo {
+	info := parseUserAgent(header.Get("User-Agent"), c.appNames)
+	if p := normalizePlatform(header.Get(c.platformHeader)); p != "" {
+		info.Platform = p
+	}
+	if v := cleanAppVersion(header.Get(c.versionHeader)); v != "" {
+		info.AppVersion = v
+	}
+	if c.versions != nil {
+		info.versionLabel = c.versions.label(info.AppVersion)
+	}
+	return &info
+}
+
+// clientInfoMiddleware stores the ClientInfo of each request in its context.
+func clientInfoMiddleware(c *clientClassifier) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			if tr, ok := transport.FromServerContext(ctx); ok {
+				ctx = context.WithValue(ctx, clientInfoKey{}, c.classify(tr.RequestHeader()))
+			}
+			return handler(ctx, req)
+		}
+	}
+}
+
+// parseUserAgent classifies a User-Agent. appNames are product tokens whose version is the app version.
+func parseUserAgent(ua string, appNames []string) ClientInfo {
+	info := ClientInfo{Platform: PlatformOther, Device: DeviceUnknown, UserAgent: ua}
+	lower := strings.ToLower(ua)
+	for _, name := range appNames {
+		if v, ok := productVersion(lower, strings.ToLower(name)); ok {
+			info.App, info.AppVersion = name, v
+			break
+		}
+	}
+
+	switch {
+	case strings.Contains(lower, "android"):
+		info.Platform = PlatformAndroid
+	case containsAny(lower, "iphone", "ipad", "ipod", "ios", "cfnetwork"):
+		info.Platform = PlatformIOS
+	case strings.Contains(lower, "windows"):
+		info.Platform = PlatformWindows
+	case containsAny(lower, "macintosh", "mac os x"):
+		info.Platform = PlatformMacOS
+	case containsAny(lower, "linux", "x11", "cros"):
+		info.Platform = PlatformLinux
+	}
+
+	switch {
+	case containsAny(lower, "bot", "crawler", "spider", "slurp", "curl/", "wget/", "python-requests", "go-http-client"):
+		info.Device = DeviceBot
+	// Android browsers mark phones with "Mobile"; native app User-Agents usually do not, so only browsers
+	// without it are taken for tablets.
+	case containsAny(lower, "ipad", "tablet") ||
+		(info.Platform == PlatformAndroid && strings.HasPrefix(lower, "mozilla/") && !strings.Contains(lower, "mobile")):
+		info.Device = DeviceTablet
+	case containsAny(lower, "mobi", "iphone", "ipod") || info.Platform == PlatformAndroid || info.Platform == PlatformIOS:
+		info.Device = DeviceMobile
+	case info.Platform == PlatformWindows || info.Platform == PlatformMacOS || info.Platform == PlatformLinux:
+		info.Device = DeviceDesktop
+	}
+	return info
+}
+
+// productVersion finds "name/version" in a lower-cased User-Agent.
+func productVersion(ua, name string) (string, bool) {
+	for rest := ua; ; {
+		i := strings.Index(rest, name+"/")
+		if i < 0 {
+			return "", false
+		}
+		// The token must start the string or follow a separator.
+		if i == 0 || strings.ContainsRune(" ;(", rune(rest[i-1])) {
+			v := rest[i+len(name)+1:]
+			if end := strings.IndexAny(v, " ;()"); end >= 0 {
+				v = v[:end]
+			}
+			if v = cleanAppVersion(v); v != "" {
+				return v, true
+			}
+		}
+		rest = rest[i+len(name)+1:]
+	}
+}
+
+// cleanAppVersion keeps a plausible version string: digits, letters, dots, dashes and plus signs.
+func cleanAppVersion(v string) string {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if v == "" || len(v) > maxAppVersionLength {
+		return ""
+	}
+	for _, r := range v {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '.' || r == '-' || r == '+') {
+			return ""
+		}
+	}
+	return v
+}
+
+// normalizePlatform maps a platform header value to a Platform constant; empty when absent.
+func normalizePlatform(v string) string {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "":
+		return ""
+	case "ios", "ipados", "iphone", "ipad":
+		return PlatformIOS
+	case "android":
+		return PlatformAndroid
+	case "windows", "win":
+		return PlatformWindows
+	case "macos", "mac", "osx", "darwin":
+		return PlatformMacOS
+	case "linux":
+		return PlatformLinux
+	default:
+		return PlatformOther
+	}
+}
+
+func containsAny(s string, subs ...string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// versionLabeler maps app versions to a bounded set of "major.minor" metric labels.
+type versionLabeler struct {
+	max int
+
+	mu   sync.RWMutex
+	seen map[string]struct{}
+}
+
+func (l *versionLabeler) label(version string) string {
+	if version == "" {
+		return callerUnknown
+	}
+	parts := strings.SplitN(version, ".", 3)
+	v := parts[0]
+	if len(parts) > 1 {
+		v += "." + parts[1]
+	}
+
+	l.mu.RLock()
+	_, known := l.seen[v]
+	l.mu.RUnlock()
+	if known {
+		return v
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, known = l.seen[v]; known {
+		return v
+	}
+	if len(l.seen) >= l.max {
+		return callerOther
+	}
+	l.seen[v] = struct{}{}
+	return v
+}
+
+// recordClientMetrics counts the request under the client's platform and app version when client metrics
+// are enabled.
+func (h *ServiceHttp) recordClientMetrics(ctx context.Context, status string) {
+	if h == nil || h.clientAppRequests == nil {
+		return
+	}
+	info, ok := ctx.Value(clientInfoKey{}).(*ClientInfo)
+	if !ok || info.versionLabel == "" {
+		return
+	}
+	h.clientAppRequests.WithLabelValues(info.Platform, info.versionLabel, status).Inc()
+}
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUserAgent(t *testing.T) {
+	apps := []string{"ShopApp"}
+	tests := []struct {
+		ua       string
+		platform string
+		device   string
+		version  string
+	}{
+		{"ShopApp/3.2.1 (iPhone; iOS 17.1; Scale/3.00)", PlatformIOS, DeviceMobile, "3.2.1"},
+		{"ShopApp/2.9.0 (Linux; Android 14; Pixel 8) okhttp/4.12.0", PlatformAndroid, DeviceMobile, "2.9.0"},
+		{"Mozilla/5.0 (Linux; Android 14; SM-X710) AppleWebKit/537.36 Chrome/120.0 Safari/537.36",
+			PlatformAndroid, DeviceTablet, ""},
+		{"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 Chrome/120.0 Mobile Safari/537.36",
+			PlatformAndroid, DeviceMobile, ""},
+		{"Mozilla/5.0 (iPad; CPU OS 17_1 like Mac OS X) AppleWebKit/605.1.15", PlatformIOS, DeviceTablet, ""},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) Chrome/120.0", PlatformWindows, DeviceDesktop, ""},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 14_1) Safari/605.1.15", PlatformMacOS, DeviceDesktop, ""},
+		{"Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:121.0) Firefox/121.0", PlatformLinux, DeviceDesktop, ""},
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", PlatformOther, DeviceBot, ""},
+		{"curl/8.4.0", PlatformOther, DeviceBot, ""},
+		{"", PlatformOther, DeviceUnknown, ""},
+		{"NotShopApp/1.0", PlatformOther, DeviceUnknown, ""},
+	}
+	for _, tt := range tests {
+		info := parseUserAgent(tt.ua, apps)
+		assert.Equal(t, tt.platform, info.Platform, tt.ua)
+		assert.Equal(t, tt.device, info.Device, tt.ua)
+		assert.Equal(t, tt.version, info.AppVersion, tt.ua)
+	}
+	assert.Equal(t, "ShopApp", parseUserAgent("shopapp/1.0", apps).App)
+}
+
+func TestClientClassifier_HeadersTakePrecedence(t *testing.T) {
+	assert.Nil(t, newClientClassifier(nil))
+	assert.Nil(t, newClientClassifier(&conf.ClientInfoConfig{}))
+
+	c := newClientClassifier(&conf.ClientInfoConfig{Enabled: true, AppNames: []string{"ShopApp"}})
+	info := c.classify(newFakeHeader(map[string]string{
+		"User-Agent":     "ShopApp/3.2.1 (iPhone; iOS 17.1)",
+		"X-App-Version":  "v3.3.0",
+		"X-App-Platform": "Android",
+	}))
+	assert.Equal(t, PlatformAndroid, info.Platform)
+	assert.Equal(t, "3.3.0", info.AppVersion)
+	assert.Empty(t, info.versionLabel)
+
+	// Implausible header values are ignored.
+	info = c.classify(newFakeHeader(map[string]string{
+		"User-Agent":    "ShopApp/3.2.1 (iPhone; iOS 17.1)",
+		"X-App-Version": "<script>",
+	}))
+	assert.Equal(t, "3.2.1", info.AppVersion)
+}
+
+func TestVersionLabeler(t *testing.T) {
+	l := &versionLabeler{max: 2, seen: make(map[string]struct{})}
+	assert.Equal(t, "unknown", l.label(""))
+	assert.Equal(t, "3.2", l.label("3.2.1"))
+	assert.Equal(t, "3.2", l.label("3.2.7"))
+	assert.Equal(t, "4", l.label("4"))
+	assert.Equal(t, "other", l.label("5.0.0"))
+}
+
+func TestClientInfoMiddleware_ContextAndMetrics(t *testing.T) {
+	svc := NewServiceHttp()
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_client_app_requests_total"},
+		[]string{"platform", "app_version", "status"})
+	svc.clientAppRequests = counter
+	c := newClientClassifier(&conf.ClientInfoConfig{Enabled: true, Metrics: true, AppNames: []string{"ShopApp"}})
+
+	tr := newFakeTransport("/api.v1.Users/Get", map[string]string{"User-Agent": "ShopApp/3.2.1 (iPhone; iOS 17.1)"})
+	ctx := transport.NewServerContext(context.Background(), tr)
+	var seen ClientInfo
+	_, err := clientInfoMiddleware(c)(func(ctx context.Context, req any) (any, error) {
+		var ok bool
+		seen, ok = ClientInfoFromContext(ctx)
+		require.True(t, ok)
+		svc.recordClientMetrics(ctx, "success")
+		return nil, nil
+	})(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, PlatformIOS, seen.Platform)
+	assert.Equal(t, DeviceMobile, seen.Device)
+	assert.Equal(t, "ShopApp", seen.App)
+	assert.Equal(t, 1.0, testutil.ToFloat64(counter.WithLabelValues(PlatformIOS, "3.2", "success")))
+
+	_, ok := ClientInfoFromContext(context.Background())
+	assert.False(t, ok)
+}
@@ -0,0 +1,326 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// ClientSDKUpdateEnv makes WriteClientSDKFile rewrite the generated file instead of checking it. Set it from a
+// go:generate directive next to the test calling WriteClientSDKFile:
+//
+//	//go:generate env LYNX_UPDATE_CLIENT_SDK=1 go test -run TestClientSDK .
+const ClientSDKUpdateEnv = "LYNX_UPDATE_CLIENT_SDK"
+
+// ClientSDKOptions configures the generated client SDKs.
+type ClientSDKOptions struct {
+	// Package is the package name of the Go client. Default: "client".
+	Package string
+	// Services are the full names of the proto services to generate clients for. Default: every registered
+	// service with an HTTP binding served by this server.
+	Services []string
+	// BusinessCodes are written as code constants, and the Go client turns them back into typed errors. Optional.
+	BusinessCodes *BusinessCodeMapper
+}
+
+// sdkService is a proto service with the methods the SDK calls over HTTP.
+type sdkService struct {
+	desc    protoreflect.ServiceDescriptor
+	methods []sdkMethod
+}
+
+// sdkMethod is a unary method and its first HTTP binding.
+type sdkMethod struct {
+	desc      protoreflect.MethodDescriptor
+	operation string
+	route     httpRoute
+}
+
+// clientSDKServices resolves the services of opts in files. Streaming methods and methods without an HTTP binding
+// are left out.
+func (h *ServiceHttp) clientSDKServices(opts ClientSDKOptions, files *protoregistry.Files) []sdkService {
+	var services []sdkService
+	for _, s := range h.protoServices(&conf.GrpcParityConfig{Services: opts.Services}, files) {
+		d, err := files.FindDescriptorByName(protoreflect.FullName(s.Name))
+		sd, ok := d.(protoreflect.ServiceDescriptor)
+		if err != nil || !ok {
+			continue
+		}
+		svc := sdkService{desc: sd}
+		for i := 0; i < sd.Methods().Len(); i++ {
+			md := sd.Methods().Get(i)
+			routes := httpRoutes(md)
+			if len(routes) == 0 || md.IsStreamingClient() || md.IsStreamingServer() {
+				continue
+			}
+			svc.methods = append(svc.methods, sdkMethod{
+				desc:      md,
+				operation: fmt.Sprintf("/%s/%s", sd.FullName(), md.Name()),
+				route:     routes[0],
+			})
+		}
+		if len(svc.methods) > 0 {
+			services = append(services, svc)
+		}
+	}
+	return services
+}
+
+// sdkBusinessCode is a business code with the identifier it is generated under.
+type sdkBusinessCode struct {
+	BusinessCode
+	ident string
+}
+
+// sdkBusinessCodes names the codes of m "Code" + module + reason in CamelCase, e.g. CodeOrdersOrderNotFound.
+func sdkBusinessCodes(m *BusinessCodeMapper) []sdkBusinessCode {
+	if m == nil {
+		return nil
+	}
+	var codes []sdkBusinessCode
+	seen := make(map[string]bool)
+	for _, c := range m.Codes() {
+		ident := "Code" + camelCase(c.Module) + camelCase(c.Reason)
+		if seen[ident] {
+			ident += strconv.Itoa(c.Code)
+		}
+		seen[ident] = true
+		codes = append(codes, sdkBusinessCode{BusinessCode: c, ident: ident})
+	}
+	return codes
+}
+
+// camelCase turns snake, kebab or SCREAMING_CASE names into CamelCase.
+func camelCase(s string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		b.WriteString(strings.ToUpper(part[:1]) + strings.ToLower(part[1:]))
+	}
+	return b.String()
+}
+
+// goMessage is the Go type generated for a proto message.
+type goMessage struct {
+	pkgPath, name string
+	typ           reflect.Type
+}
+
+// goMessageType returns the Go type registered for md.
+func goMessageType(md protoreflect.MessageDescriptor) (goMessage, error) {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(md.FullName())
+	if err != nil {
+		return goMessage{}, fmt.Errorf("no Go type registered for %s: %w", md.FullName(), err)
+	}
+	t := reflect.TypeOf(mt.Zero().Interface())
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.PkgPath() == "" || t.Name() == "" {
+		return goMessage{}, fmt.Errorf("%s has no generated Go type", md.FullName())
+	}
+	return goMessage{pkgPath: t.PkgPath(), name: t.Name(), typ: t}, nil
+}
+
+// goFieldName returns the Go struct field of the proto field named name.
+func (m goMessage) goFieldName(name string) (string, error) {
+	for i := 0; i < m.typ.NumField(); i++ {
+		f := m.typ.Field(i)
+		if slices.Contains(strings.Split(f.Tag.Get("protobuf"), ","), "name="+name) {
+			return f.Name, nil
+		}
+	}
+	return "", fmt.Errorf("%s.%s has no Go field for %q", m.pkgPath, m.name, name)
+}
+
+// goImports assigns package aliases, avoiding the packages every client imports.
+type goImports map[string]string
+
+func (imports goImports) alias(pkgPath string) string {
+	if alias, ok := imports[pkgPath]; ok {
+		return alias
+	}
+	base := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, path.Base(pkgPath))
+	if unicode.IsDigit(rune(base[0])) {
+		base = "pb" + base
+	}
+	taken := func(a string) bool {
+		if a == "context" || a == "http" || a == "binding" || a == "lynxhttp" {
+			return true
+		}
+		for _, other := range imports {
+			if other == a {
+				return true
+			}
+		}
+		return false
+	}
+	alias := base
+	for i := 2; taken(alias); i++ {
+		alias = base + strconv.Itoa(i)
+	}
+	imports[pkgPath] = alias
+	return alias
+}
+
+// WriteGoClientSDK writes a Go client with one typed client per proto service, calling the routes of the service
+// through a Kratos client built by the generated NewHTTPClient, which decodes the response envelope and business
+// codes of this server.
+func (h *ServiceHttp) WriteGoClientSDK(w io.Writer, opts ClientSDKOptions) error {
+	return h.writeGoClientSDK(w, opts, protoregistry.GlobalFiles)
+}
+
+func (h *ServiceHttp) writeGoClientSDK(w io.Writer, opts ClientSDKOptions, files *protoregistry.Files) error {
+	pkg := opts.Package
+	if pkg == "" {
+		pkg = "client"
+	}
+	imports := goImports{}
+	var body bytes.Buffer
+	codes := sdkBusinessCodes(opts.BusinessCodes)
+	fmt.Fprintf(&body, "// SuccessCode is the envelope code of successful replies.\nconst SuccessCode = %d\n\n", h.CodeSpace().SuccessCode)
+	if len(codes) > 0 {
+		body.WriteString("// Business codes written in the envelope of error replies.\nconst (\n")
+		for _, c := range codes {
+			fmt.Fprintf(&body, "\t%s = %d // %s, status %d\n", c.ident, c.Code, contractName(c.BusinessCode), c.Status)
+		}
+		body.WriteString(")\n\n")
+	}
+	body.WriteString("// BusinessCodes are the business codes registered with the server.\nvar BusinessCodes = []lynxhttp.BusinessCode{\n")
+	for _, c := range codes {
+		fmt.Fprintf(&body, "\t{Code: %s, Reason: %q, Module: %q, Status: %d},\n", c.ident, c.Reason, c.Module, c.Status)
+	}
+	body.WriteString(`}
+
+// NewHTTPClient returns a Kratos client for the service clients. Unless set in cfg, envelopes are decoded with
+// SuccessCode and error codes are mapped back to the errors of BusinessCodes.
+func NewHTTPClient(ctx context.Context, cfg lynxhttp.ClientConfig, opts ...http.ClientOption) (*http.Client, error) {
+	if len(cfg.SuccessCodes) == 0 {
+		cfg.SuccessCodes = []int{SuccessCode}
+	}
+	if cfg.CodeErrorMapper == nil && len(BusinessCodes) > 0 {
+		mapper, err := lynxhttp.NewBusinessCodeMapper(BusinessCodes...)
+		if err != nil {
+			return nil, err
+		}
+		cfg.CodeErrorMapper = mapper
+	}
+	return lynxhttp.NewKratosClient(ctx, cfg, opts...)
+}
+`)
+
+	for _, svc := range h.clientSDKServices(opts, files) {
+		name := string(svc.desc.Name()) + "Client"
+		fmt.Fprintf(&body, `
+// %[1]s calls %[2]s over HTTP.
+type %[1]s struct {
+	cc *http.Client
+}
+
+// New%[1]s returns a client for %[2]s; build cc with NewHTTPClient.
+func New%[1]s(cc *http.Client) *%[1]s {
+	return &%[1]s{cc: cc}
+}
+`, name, svc.desc.FullName())
+		for _, m := range svc.methods {
+			in, err := goMessageType(m.desc.Input())
+			if err != nil {
+				return err
+			}
+			out, err := goMessageType(m.desc.Output())
+			if err != nil {
+				return err
+			}
+			args := "nil"
+			switch m.route.body {
+			case "":
+			case "*":
+				args = "in"
+			default:
+				field, err := in.goFieldName(m.route.body)
+				if err != nil {
+					return err
+				}
+				args = "in." + field
+			}
+			fmt.Fprintf(&body, `
+// %[2]s calls %[3]s %[4]s.
+func (c *%[1]s) %[2]s(ctx context.Context, in *%[5]s.%[6]s, opts ...http.CallOption) (*%[7]s.%[8]s, error) {
+	var out %[7]s.%[8]s
+	path := binding.EncodeURL(%[4]q, in, %[9]t)
+	opts = append(opts, http.Operation(%[10]q), http.PathTemplate(%[4]q))
+	if err := c.cc.Invoke(ctx, %[3]q, path, %[11]s, &out, opts...); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+`, name, m.desc.Name(), m.route.Method, m.route.Path, imports.alias(in.pkgPath), in.name,
+				imports.alias(out.pkgPath), out.name, m.route.body == "", m.operation, args)
+		}
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by lynx-http WriteGoClientSDK. DO NOT EDIT.\n\npackage %s\n\nimport (\n", pkg)
+	src.WriteString("\t\"context\"\n\n\tlynxhttp \"github.com/go-lynx/lynx-http\"\n")
+	src.WriteString("\t\"github.com/go-kratos/kratos/v2/transport/http\"\n\t\"github.com/go-kratos/kratos/v2/transport/http/binding\"\n")
+	paths := make([]string, 0, len(imports))
+	for p := range imports {
+		paths = append(paths, p)
+	}
+	slices.Sort(paths)
+	for _, p := range paths {
+		fmt.Fprintf(&src, "\t%s %q\n", imports[p], p)
+	}
+	src.WriteString(")\n\n")
+	src.Write(body.Bytes())
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return fmt.Errorf("format generated client: %w", err)
+	}
+	_, err = w.Write(formatted)
+	return err
+}
+
+// WriteClientSDKFile checks that the client SDK at path is up to date, or rewrites it when ClientSDKUpdateEnv is
+// set. Files ending in ".ts" get the TypeScript client, others the Go client.
+func (h *ServiceHttp) WriteClientSDKFile(path string, opts ClientSDKOptions) error {
+	var generated bytes.Buffer
+	write := h.WriteGoClientSDK
+	if strings.HasSuffix(path, ".ts") {
+		write = h.WriteTypeScriptClientSDK
+	}
+	if err := write(&generated, opts); err != nil {
+		return err
+	}
+	if os.Getenv(ClientSDKUpdateEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(path, generated.Bytes(), 0o644)
+	}
+	current, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("client SDK: %w (set %s=1 to create it)", err, ClientSDKUpdateEnv)
+	}
+	if !bytes.Equal(current, generated.Bytes()) {
+		return fmt.Errorf("client SDK %s is out of date with the registered routes (set %s=1 to regenerate it)", path, ClientSDKUpdateEnv)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package http
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/apipb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// sdkTestFiles returns a registry with the service catalog.v1.Apis over google.protobuf.Api, whose Go types are
+// compiled in: GetApi (GET /v1/apis/{name}), CreateApi (POST /v1/apis, body "*"), UpdateSource (PATCH
+// /v1/apis/{name}/source, body "source_context"), a streaming WatchApis and an unbound Sync.
+func sdkTestFiles(t *testing.T) *protoregistry.Files {
+	t.Helper()
+	method := func(name, in, out string, rule *annotations.HttpRule) *descriptorpb.MethodDescriptorProto {
+		md := &descriptorpb.MethodDescriptorProto{Name: proto.String(name), InputType: proto.String(in), OutputType: proto.String(out)}
+		if rule != nil {
+			md.Options = &descriptorpb.MethodOptions{}
+			proto.SetExtension(md.Options, annotations.E_Http, rule)
+		}
+		return md
+	}
+	watch := method("WatchApis", ".google.protobuf.Empty", ".google.protobuf.Api",
+		&annotations.HttpRule{Pattern: &annotations.HttpRule_Get{Get: "/v1/apis:watch"}})
+	watch.ServerStreaming = proto.Bool(true)
+	fdp := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("catalog/v1/apis.proto"),
+		Package:    proto.String("catalog.v1"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/api.proto", "google/protobuf/empty.proto"},
+		Service: []*descriptorpb.ServiceDescriptorProto{{Name: proto.String("Apis"), Method: []*descriptorpb.MethodDescriptorProto{
+			method("GetApi", ".google.protobuf.Api", ".google.protobuf.Api",
+				&annotations.HttpRule{Pattern: &annotations.HttpRule_Get{Get: "/v1/apis/{name}"}}),
+			method("CreateApi", ".google.protobuf.Api", ".google.protobuf.Empty",
+				&annotations.HttpRule{Pattern: &annotations.HttpRule_Post{Post: "/v1/apis"}, Body: "*"}),
+			method("UpdateSource", ".google.protobuf.Api", ".google.protobuf.Api",
+				&annotations.HttpRule{Pattern: &annotations.HttpRule_Patch{Patch: "/v1/apis/{name}/source"}, Body: "source_context"}),
+			watch,
+			method("Sync", ".google.protobuf.Empty", ".google.protobuf.Empty", nil),
+		}}},
+	}
+	files := &protoregistry.Files{}
+	for _, fd := range []protoreflect.FileDescriptor{apipb.File_google_protobuf_api_proto, emptypb.File_google_protobuf_empty_proto} {
+		require.NoError(t, files.RegisterFile(fd))
+	}
+	fd, err := protodesc.NewFile(fdp, files)
+	require.NoError(t, err)
+	require.NoError(t, files.RegisterFile(fd))
+	return files
+}
+
+func sdkTestService(t *testing.T, cfg *conf.Http) (*ServiceHttp, ClientSDKOptions) {
+	t.Helper()
+	h := NewServiceHttp()
+	h.conf = cfg
+	h.server = http.NewServer()
+	h.server.Route("/").GET("/v1/apis/{name}", func(http.Context) error { return nil })
+	codes, err := NewBusinessCodeMapper(
+		BusinessCode{Code: 40401, Reason: "API_NOT_FOUND", Module: "catalog", Status: 404},
+		BusinessCode{Code: 40901, Reason: "API_EXISTS", Status: 409},
+	)
+	require.NoError(t, err)
+	return h, ClientSDKOptions{Package: "catalog", BusinessCodes: codes}
+}
+
+func TestGoClientSDK(t *testing.T) {
+	h, opts := sdkTestService(t, &conf.Http{})
+	var out bytes.Buffer
+	require.NoError(t, h.writeGoClientSDK(&out, opts, sdkTestFiles(t)))
+	src := out.String()
+
+	assert.Contains(t, src, "package catalog\n")
+	assert.Contains(t, src, `apipb "google.golang.org/protobuf/types/known/apipb"`)
+	assert.Contains(t, src, "const SuccessCode = 200")
+	assert.Contains(t, src, "CodeCatalogApiNotFound = 40401 // catalog/API_NOT_FOUND, status 404")
+	assert.Contains(t, src, `{Code: CodeApiExists, Reason: "API_EXISTS", Module: "", Status: 409}`)
+	assert.Contains(t, src, "func NewApisClient(cc *http.Client) *ApisClient")
+	assert.Contains(t, src, "func (c *ApisClient) GetApi(ctx context.Context, in *apipb.Api, opts ...http.CallOption) (*apipb.Api, error)")
+	assert.Contains(t, src, `path := binding.EncodeURL("/v1/apis/{name}", in, true)`)
+	assert.Contains(t, src, `http.Operation("/catalog.v1.Apis/GetApi")`)
+	assert.Contains(t, src, `c.cc.Invoke(ctx, "GET", path, nil, &out, opts...)`)
+	assert.Contains(t, src, `c.cc.Invoke(ctx, "POST", path, in, &out, opts...)`)
+	assert.Contains(t, src, `c.cc.Invoke(ctx, "PATCH", path, in.SourceContext, &out, opts...)`)
+	assert.Contains(t, src, "(*emptypb.Empty, error)")
+	assert.NotContains(t, src, "WatchApis", "streaming methods are left out")
+	assert.NotContains(t, src, "Sync", "methods without an HTTP binding are left out")
+}
+
+func TestTypeScriptClientSDK(t *testing.T) {
+	h, opts := sdkTestService(t, &conf.Http{})
+	var out bytes.Buffer
+	require.NoError(t, h.writeTypeScriptClientSDK(&out, opts, sdkTestFiles(t)))
+	src := out.String()
+
+	// Without proto_json replies are written by encoding/json: proto names, numbers and plain structs.
+	assert.Contains(t, src, "export const SUCCESS_CODE = 200;")
+	assert.Contains(t, src, `  "catalog/API_NOT_FOUND": 40401,`)
+	assert.Contains(t, src, "export interface Envelope<T> {")
+	assert.Contains(t, src, "export interface Api {\n  name?: string;\n  methods?: Method[];\n  options?: Option[];")
+	assert.Contains(t, src, "  source_context?: SourceContext;\n")
+	assert.Contains(t, src, "  syntax?: number;\n")
+	assert.Contains(t, src, "export interface Any {")
+	assert.Contains(t, src, "export class ApisClient {")
+	assert.Contains(t, src, `  getApi(req: Api, init?: RequestInit): Promise<Api> {
+    return call<Api>(this.options, "GET", "/v1/apis/{name}", req, "", init);`)
+	assert.Contains(t, src, `call<Api>(this.options, "PATCH", "/v1/apis/{name}/source", req, "source_context", init)`)
+	assert.NotContains(t, src, "watchApis")
+
+	// With proto_json the JSON names, enum names and well-known type forms of protojson are used.
+	h.conf = &conf.Http{Response: &conf.ResponseConfig{ProtoJson: &conf.ProtoJSONConfig{Enabled: true, UseJsonNames: true}}}
+	out.Reset()
+	require.NoError(t, h.writeTypeScriptClientSDK(&out, opts, sdkTestFiles(t)))
+	src = out.String()
+	assert.Contains(t, src, "  sourceContext?: SourceContext;\n")
+	assert.Contains(t, src, `export type Syntax = "SYNTAX_PROTO2" | "SYNTAX_PROTO3" | "SYNTAX_EDITIONS";`)
+	assert.Contains(t, src, `  value?: { "@type": string; [key: string]: unknown };`)
+	assert.Contains(t, src, `createApi(req: Api, init?: RequestInit): Promise<Record<string, never>>`)
+	assert.Contains(t, src, `"PATCH", "/v1/apis/{name}/source", req, "sourceContext", init)`)
+	assert.NotContains(t, src, "export interface Any {")
+}
+
+func TestWriteClientSDKFile(t *testing.T) {
+	h, opts := sdkTestService(t, &conf.Http{})
+	opts.Services = []string{"google.protobuf.Missing"}
+	path := filepath.Join(t.TempDir(), "sdk", "client.ts")
+
+	require.Error(t, h.WriteClientSDKFile(path, opts), "missing file")
+	t.Setenv(ClientSDKUpdateEnv, "1")
+	require.NoError(t, h.WriteClientSDKFile(path, opts))
+	written, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(written), "WriteTypeScriptClientSDK")
+
+	t.Setenv(ClientSDKUpdateEnv, "")
+	require.NoError(t, h.WriteClientSDKFile(path, opts))
+	require.NoError(t, opts.BusinessCodes.Register(BusinessCode{Code: 42201, Reason: "API_INVALID", Status: 422}))
+	assert.ErrorContains(t, h.WriteClientSDKFile(path, opts), "out of date")
+}
@@ -0,0 +1,337 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// tsRuntime is the envelope handling shared by the generated TypeScript service clients.
+const tsRuntime = `/** Response envelope written by the server. */
+export interface Envelope<T> {
+  code: number;
+  message?: string;
+  data?: T;
+  warnings?: Warning[];
+}
+
+/** Non-fatal notice attached to a reply. */
+export interface Warning {
+  code: string;
+  message?: string;
+}
+
+/** Error thrown for envelopes whose code is not SUCCESS_CODE. */
+export class ApiError extends Error {
+  constructor(readonly code: number, message: string, readonly status: number) {
+    super(message || ` + "`request failed with code ${code}`" + `);
+    this.name = "ApiError";
+  }
+}
+
+export interface ClientOptions {
+  /** Server address, e.g. "https://api.example.com". */
+  baseUrl: string;
+  /** Headers sent with every request, e.g. Authorization. */
+  headers?: Record<string, string>;
+  /** Fetch implementation. Default: the global fetch. */
+  fetch?: typeof fetch;
+}
+
+function take(fields: Record<string, unknown>, name: string): unknown {
+  const [head, ...rest] = name.split(".");
+  const value = fields[head];
+  if (rest.length === 0) {
+    delete fields[head];
+    return value;
+  }
+  return value === undefined || value === null ? undefined : take({ ...(value as Record<string, unknown>) }, rest.join("."));
+}
+
+async function call<T>(options: ClientOptions, method: string, template: string, req: object, body: string, init?: RequestInit): Promise<T> {
+  const fields: Record<string, unknown> = { ...(req as Record<string, unknown>) };
+  let path = template.replace(/\{([^}=:]+)([^}]*)\}/g, (_, name: string, pattern: string) => {
+    const value = String(take(fields, name) ?? "");
+    return pattern ? encodeURI(value) : encodeURIComponent(value);
+  });
+  let payload: unknown;
+  if (body === "*") {
+    payload = fields;
+  } else if (body !== "") {
+    payload = fields[body];
+  } else {
+    const query = new URLSearchParams();
+    for (const [key, value] of Object.entries(fields)) {
+      for (const v of Array.isArray(value) ? value : [value]) {
+        if (v !== undefined && v !== null && typeof v !== "object") {
+          query.append(key, String(v));
+        }
+      }
+    }
+    const encoded = query.toString();
+    if (encoded) {
+      path += "?" + encoded;
+    }
+  }
+  const headers: Record<string, string> = { Accept: "application/json", ...options.headers };
+  if (payload !== undefined) {
+    headers["Content-Type"] = "application/json";
+  }
+  const res = await (options.fetch ?? fetch)(options.baseUrl.replace(/\/+$/, "") + path, {
+    ...init,
+    method,
+    headers,
+    body: payload === undefined ? undefined : JSON.stringify(payload),
+  });
+  const envelope = (await res.json()) as Envelope<T>;
+  if (envelope.code !== SUCCESS_CODE) {
+    throw new ApiError(envelope.code, envelope.message ?? "", res.status);
+  }
+  return (envelope.data ?? {}) as T;
+}
+`
+
+// tsWellKnownTypes are the protojson forms of the well-known types.
+var tsWellKnownTypes = map[protoreflect.FullName]string{
+	"google.protobuf.Timestamp":   "string",
+	"google.protobuf.Duration":    "string",
+	"google.protobuf.FieldMask":   "string",
+	"google.protobuf.Struct":      "Record<string, unknown>",
+	"google.protobuf.Value":       "unknown",
+	"google.protobuf.ListValue":   "unknown[]",
+	"google.protobuf.Empty":       "Record<string, never>",
+	"google.protobuf.Any":         `{ "@type": string; [key: string]: unknown }`,
+	"google.protobuf.DoubleValue": "number",
+	"google.protobuf.FloatValue":  "number",
+	"google.protobuf.Int32Value":  "number",
+	"google.protobuf.UInt32Value": "number",
+	"google.protobuf.Int64Value":  "string",
+	"google.protobuf.UInt64Value": "string",
+	"google.protobuf.BoolValue":   "boolean",
+	"google.protobuf.StringValue": "string",
+	"google.protobuf.BytesValue":  "string",
+}
+
+// tsTypes collects the messages and enums a TypeScript client refers to. The JSON form follows the server: with
+// response.proto_json, protojson with proto or JSON names, 64-bit integers as strings and enums as names; without
+// it, encoding/json of the generated Go structs with proto names and numbers.
+type tsTypes struct {
+	protoJSON, jsonNames, enumNames bool
+	messages                        map[protoreflect.FullName]protoreflect.MessageDescriptor
+	enums                           map[protoreflect.FullName]protoreflect.EnumDescriptor
+	names                           map[protoreflect.FullName]string
+}
+
+// name returns the TypeScript name of a message or enum: its name within the proto package, nested names joined
+// with "_", or its full name when that clashes with another package.
+func (t *tsTypes) name(d protoreflect.Descriptor) string {
+	if name, ok := t.names[d.FullName()]; ok {
+		return name
+	}
+	return strings.ReplaceAll(string(d.FullName()), ".", "_")
+}
+
+func (t *tsTypes) message(md protoreflect.MessageDescriptor) string {
+	if t.protoJSON {
+		if wkt, ok := tsWellKnownTypes[md.FullName()]; ok {
+			return wkt
+		}
+	}
+	if _, ok := t.messages[md.FullName()]; !ok {
+		t.messages[md.FullName()] = md
+		for i := 0; i < md.Fields().Len(); i++ {
+			t.field(md.Fields().Get(i))
+		}
+	}
+	return t.name(md)
+}
+
+func (t *tsTypes) field(fd protoreflect.FieldDescriptor) string {
+	if fd.IsMap() {
+		return "Record<string, " + t.singular(fd.MapValue()) + ">"
+	}
+	typ := t.singular(fd)
+	if fd.IsList() {
+		if strings.Contains(typ, " | ") || strings.HasPrefix(typ, "{") {
+			typ = "(" + typ + ")"
+		}
+		return typ + "[]"
+	}
+	return typ
+}
+
+func (t *tsTypes) singular(fd protoreflect.FieldDescriptor) string {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return "boolean"
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		return "string"
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind, protoreflect.Sfixed64Kind:
+		if t.protoJSON {
+			return "string"
+		}
+		return "number"
+	case protoreflect.EnumKind:
+		if !t.enumNames {
+			return "number"
+		}
+		t.enums[fd.Enum().FullName()] = fd.Enum()
+		return t.name(fd.Enum())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return t.message(fd.Message())
+	default:
+		return "number"
+	}
+}
+
+func (t *tsTypes) jsonName(fd protoreflect.FieldDescriptor) string {
+	if t.jsonNames {
+		return fd.JSONName()
+	}
+	return string(fd.Name())
+}
+
+// pathTemplate renames the variables of a google.api.http path to the JSON names of the request fields, so the
+// client finds them in the request object.
+func (t *tsTypes) pathTemplate(path string, md protoreflect.MessageDescriptor) string {
+	if !t.jsonNames {
+		return path
+	}
+	return routeVariable.ReplaceAllStringFunc(path, func(v string) string {
+		inner := v[1 : len(v)-1]
+		name, pattern := inner, ""
+		if i := strings.IndexAny(inner, "=:"); i >= 0 {
+			name, pattern = inner[:i], inner[i:]
+		}
+		parts := strings.Split(name, ".")
+		for i, part := range parts {
+			fd := md.Fields().ByName(protoreflect.Name(part))
+			if fd == nil {
+				return v
+			}
+			parts[i] = fd.JSONName()
+			if md = fd.Message(); md == nil && i < len(parts)-1 {
+				return v
+			}
+		}
+		return "{" + strings.Join(parts, ".") + pattern + "}"
+	})
+}
+
+// assignNames names the collected types after their proto names, falling back to full names on clashes.
+func (t *tsTypes) assignNames() {
+	count := make(map[string]int)
+	local := func(d protoreflect.Descriptor) string {
+		name := strings.TrimPrefix(string(d.FullName()), string(d.ParentFile().Package())+".")
+		return strings.ReplaceAll(name, ".", "_")
+	}
+	for _, md := range t.messages {
+		count[local(md)]++
+	}
+	for _, ed := range t.enums {
+		count[local(ed)]++
+	}
+	for name, md := range t.messages {
+		if count[local(md)] == 1 {
+			t.names[name] = local(md)
+		}
+	}
+	for name, ed := range t.enums {
+		if count[local(ed)] == 1 {
+			t.names[name] = local(ed)
+		}
+	}
+}
+
+// WriteTypeScriptClientSDK writes a TypeScript client using fetch, with interfaces for the messages of the proto
+// services, the envelope and business codes of this server, and one client class per service.
+func (h *ServiceHttp) WriteTypeScriptClientSDK(w io.Writer, opts ClientSDKOptions) error {
+	return h.writeTypeScriptClientSDK(w, opts, protoregistry.GlobalFiles)
+}
+
+func (h *ServiceHttp) writeTypeScriptClientSDK(w io.Writer, opts ClientSDKOptions, files *protoregistry.Files) error {
+	pj := h.conf.GetResponse().GetProtoJson()
+	types := &tsTypes{
+		protoJSON: pj.GetEnabled(),
+		jsonNames: pj.GetEnabled() && pj.GetUseJsonNames(),
+		enumNames: pj.GetEnabled() && !pj.GetUseEnumNumbers(),
+		messages:  make(map[protoreflect.FullName]protoreflect.MessageDescriptor),
+		enums:     make(map[protoreflect.FullName]protoreflect.EnumDescriptor),
+		names:     make(map[protoreflect.FullName]string),
+	}
+	services := h.clientSDKServices(opts, files)
+	for _, svc := range services {
+		for _, m := range svc.methods {
+			types.message(m.desc.Input())
+			types.message(m.desc.Output())
+		}
+	}
+	types.assignNames()
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by lynx-http WriteTypeScriptClientSDK. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "/** Envelope code of successful replies. */\nexport const SUCCESS_CODE = %d;\n\n", h.CodeSpace().SuccessCode)
+	b.WriteString("/** Business codes written in the envelope of error replies, by module/reason. */\nexport const BusinessCodes = {\n")
+	for _, c := range sdkBusinessCodes(opts.BusinessCodes) {
+		fmt.Fprintf(&b, "  %q: %d,\n", contractName(c.BusinessCode), c.Code)
+	}
+	b.WriteString("} as const;\n\n")
+	b.WriteString(tsRuntime)
+
+	enums := make([]protoreflect.FullName, 0, len(types.enums))
+	for name := range types.enums {
+		enums = append(enums, name)
+	}
+	slices.Sort(enums)
+	for _, name := range enums {
+		ed := types.enums[name]
+		values := make([]string, 0, ed.Values().Len())
+		for i := 0; i < ed.Values().Len(); i++ {
+			values = append(values, fmt.Sprintf("%q", ed.Values().Get(i).Name()))
+		}
+		fmt.Fprintf(&b, "\n/** %s */\nexport type %s = %s;\n", name, types.name(ed), strings.Join(values, " | "))
+	}
+	messages := make([]protoreflect.FullName, 0, len(types.messages))
+	for name := range types.messages {
+		messages = append(messages, name)
+	}
+	slices.Sort(messages)
+	for _, name := range messages {
+		md := types.messages[name]
+		if md.Fields().Len() == 0 {
+			fmt.Fprintf(&b, "\n/** %s */\nexport interface %s {}\n", name, types.name(md))
+			continue
+		}
+		fmt.Fprintf(&b, "\n/** %s */\nexport interface %s {\n", name, types.name(md))
+		for i := 0; i < md.Fields().Len(); i++ {
+			fd := md.Fields().Get(i)
+			fmt.Fprintf(&b, "  %s?: %s;\n", types.jsonName(fd), types.field(fd))
+		}
+		b.WriteString("}\n")
+	}
+
+	for _, svc := range services {
+		fmt.Fprintf(&b, "\n/** Calls %s over HTTP. */\nexport class %sClient {\n", svc.desc.FullName(), svc.desc.Name())
+		b.WriteString("  constructor(private readonly options: ClientOptions) {}\n")
+		for _, m := range svc.methods {
+			in, out := types.message(m.desc.Input()), types.message(m.desc.Output())
+			body := m.route.body
+			if fd := m.desc.Input().Fields().ByName(protoreflect.Name(body)); fd != nil {
+				body = types.jsonName(fd)
+			}
+			name := string(m.desc.Name())
+			fmt.Fprintf(&b, "\n  /** %s %s */\n  %s(req: %s, init?: RequestInit): Promise<%s> {\n",
+				m.route.Method, m.route.Path, strings.ToLower(name[:1])+name[1:], in, out)
+			fmt.Fprintf(&b, "    return call<%s>(this.options, %q, %q, req, %q, init);\n  }\n",
+				out, m.route.Method, types.pathTemplate(m.route.Path, m.desc.Input()), body)
+		}
+		b.WriteString("}\n")
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
@@ -0,0 +1,164 @@
+package http
+
+import (
+	"context"
+	"io"
+	nhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_RetriesIdempotentRequests(t *testing.T) {
+	var calls atomic.Int32
+	var lastAttempt, lastMax string
+	srv := httptest.NewServer(nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		lastAttempt, lastMax = r.Header.Get(RetryAttemptHeader), r.Header.Get(RetryMaxAttemptsHeader)
+		if calls.Add(1) < 3 {
+			w.WriteHeader(nhttp.StatusServiceUnavailable)
+			return
+		}
+		_, _ = io.WriteString(w, `{"code":200}`)
+	}))
+	defer srv.Close()
+
+	client := NewClient(ClientConfig{Target: "client-retry", MaxAttempts: 3, RetryBackoff: time.Millisecond})
+	// The counters are package-level, so only their increments are asserted.
+	retries := clientRetries.WithLabelValues("client-retry")
+	unavailable := clientRequests.WithLabelValues("client-retry", nhttp.MethodGet, "503")
+	retriesBefore, unavailableBefore := testutil.ToFloat64(retries), testutil.ToFloat64(unavailable)
+	res, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, nhttp.StatusOK, res.StatusCode)
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Equal(t, "3", lastAttempt)
+	assert.Equal(t, "3", lastMax)
+	assert.Equal(t, 2.0, testutil.ToFloat64(retries)-retriesBefore)
+	assert.Equal(t, 2.0, testutil.ToFloat64(unavailable)-unavailableBefore)
+
+	// Non-idempotent requests are sent once.
+	calls.Store(0)
+	res, err = client.Post(srv.URL, "application/json", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	assert.Equal(t, nhttp.StatusServiceUnavailable, res.StatusCode)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestClient_HonoursServerRetryBudget(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		calls.Add(1)
+		w.Header().Set(RetryMaxAttemptsHeader, "2")
+		w.WriteHeader(nhttp.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	res, err := NewClient(ClientConfig{Target: "client-budget", MaxAttempts: 5, RetryBackoff: time.Millisecond}).Get(srv.URL)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestClient_CircuitBreaker(t *testing.T) {
+	srv := httptest.NewServer(nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		w.WriteHeader(nhttp.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	client := NewClient(ClientConfig{Target: "client-breaker", CircuitBreaker: &CircuitBreakerConfig{MaxFailures: 2, Timeout: time.Minute}})
+	rejected := clientRequests.WithLabelValues("client-breaker", nhttp.MethodGet, clientStatusCircuitOpen)
+	before := testutil.ToFloat64(rejected)
+	for range 2 {
+		res, err := client.Get(srv.URL)
+		require.NoError(t, err)
+		_ = res.Body.Close()
+	}
+	_, err := client.Get(srv.URL)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrClientCircuitOpen))
+	assert.Equal(t, 1.0, testutil.ToFloat64(rejected)-before)
+}
+
+func TestClient_PropagatesDeadline(t *testing.T) {
+	var timeout string
+	srv := httptest.NewServer(nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		timeout = r.Header.Get(RequestTimeoutHeader)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	req, _ := nhttp.NewRequestWithContext(ctx, nhttp.MethodGet, srv.URL, nil)
+	res, err := NewClient(ClientConfig{Target: "client-deadline"}).Do(req)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	assert.NotEmpty(t, timeout)
+}
+
+func envelopeResponse(status int, body string) *nhttp.Response {
+	return &nhttp.Response{
+		StatusCode: status,
+		Header:     nhttp.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestDecodeEnvelope(t *testing.T) {
+	var out struct {
+		Name string `json:"name"`
+	}
+	require.NoError(t, DecodeEnvelope(envelopeResponse(200, `{"code":200,"data":{"name":"lynx"}}`), &out))
+	assert.Equal(t, "lynx", out.Name)
+
+	require.NoError(t, DecodeEnvelope(envelopeResponse(200, `{"code":200}`), &out))
+
+	err := DecodeEnvelope(envelopeResponse(200, `{"code":100004}`), &out)
+	require.Error(t, err)
+	assert.Equal(t, 100004, int(errors.Code(err)))
+
+	assert.Error(t, DecodeEnvelope(envelopeResponse(200, `not json`), &out))
+}
+
+func TestEnvelopeErrorDecoder(t *testing.T) {
+	assert.NoError(t, EnvelopeErrorDecoder(context.Background(), envelopeResponse(200, `{"code":100004}`)))
+
+	err := EnvelopeErrorDecoder(context.Background(), envelopeResponse(500, `{"code":500}`))
+	assert.Equal(t, 500, int(errors.Code(err)))
+
+	err = EnvelopeErrorDecoder(context.Background(), envelopeResponse(502, `bad gateway`))
+	assert.Equal(t, 502, int(errors.Code(err)))
+}
+
+func TestNewKratosClient_DecodesEnvelope(t *testing.T) {
+	srv := httptest.NewServer(nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/missing" {
+			_, _ = io.WriteString(w, `{"code":100004}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"code":200,"data":{"name":"lynx"}}`)
+	}))
+	defer srv.Close()
+
+	client, err := NewKratosClient(context.Background(), ClientConfig{Target: "kratos-client", Endpoint: srv.URL})
+	require.NoError(t, err)
+	defer client.Close()
+
+	var out struct {
+		Name string `json:"name"`
+	}
+	require.NoError(t, client.Invoke(context.Background(), nhttp.MethodGet, "/user", nil, &out))
+	assert.Equal(t, "lynx", out.Name)
+
+	err = client.Invoke(context.Background(), nhttp.MethodGet, "/missing", nil, &out)
+	assert.Equal(t, 100004, int(errors.Code(err)))
+}
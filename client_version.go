@@ -0,0 +1,180 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/conf"
+)
+
+const (
+	// UpgradeRequiredReason is the Kratos error reason returned to clients below their minimum app version.
+	UpgradeRequiredReason = "UPGRADE_REQUIRED"
+
+	defaultUpgradeRequiredCode = 426
+)
+
+// clientVersionPolicy is the resolved minimum-version configuration.
+type clientVersionPolicy struct {
+	minimums map[string]*conf.MinClientVersion
+	code     int32
+	exempt   []string
+}
+
+// newClientVersionPolicy returns nil when no minimum version is configured.
+func newClientVersionPolicy(cfg *conf.ClientInfoConfig) *clientVersionPolicy {
+	if cfg == nil || !cfg.Enabled || len(cfg.MinVersions) == 0 {
+		return nil
+	}
+	p := &clientVersionPolicy{
+		minimums: make(map[string]*conf.MinClientVersion, len(cfg.MinVersions)),
+		code:     defaultUpgradeRequiredCode,
+	}
+	for _, m := range cfg.MinVersions {
+		p.minimums[normalizePlatform(m.Platform)] = m
+	}
+	if cfg.UpgradeRequiredCode != 0 {
+		p.code = cfg.UpgradeRequiredCode
+	}
+	for _, op := range cfg.ExemptOperations {
+		if op = strings.TrimSpace(op); op != "" {
+			p.exempt = append(p.exempt, op)
+		}
+	}
+	return p
+}
+
+func (p *clientVersionPolicy) exempted(operation string) bool {
+	for _, pattern := range p.exempt {
+		if wildcardMatches(pattern, operation) {
+			return true
+		}
+	}
+	return false
+}
+
+// check returns the upgrade-required error for clients below the minimum version of their platform.
+func (p *clientVersionPolicy) check(info ClientInfo) error {
+	m, ok := p.minimums[info.Platform]
+	if !ok || info.AppVersion == "" || compareVersions(info.AppVersion, m.MinVersion) >= 0 {
+		return nil
+	}
+	md := map[string]string{"platform": info.Platform, "min_version": m.MinVersion}
+	if m.StoreUrl != "" {
+		md["store_url"] = m.StoreUrl
+	}
+	return errors.New(int(p.code), UpgradeRequiredReason, "client upgrade required").WithMetadata(md)
+}
+
+// clientVersionMiddleware rejects clients below their platform's minimum app version. It runs after
+// clientInfoMiddleware, which provides the client.
+func clientVersionMiddleware(p *clientVersionPolicy) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			info, ok := ClientInfoFromContext(ctx)
+			if !ok {
+				return handler(ctx, req)
+			}
+			if tr, ok := transport.FromServerContext(ctx); ok && p.exempted(tr.Operation()) {
+				return handler(ctx, req)
+			}
+			if err := p.check(info); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}
+	}
+}
+
+// upgradeRequiredData returns the envelope data of an upgrade-required error, or nil for other errors.
+func upgradeRequiredData(se *errors.Error) map[string]string {
+	if se == nil || se.Reason != UpgradeRequiredReason {
+		return nil
+	}
+	data := make(map[string]string, 3)
+	for _, key := range []string{"platform", "min_version", "store_url"} {
+		if v := se.Metadata[key]; v != "" {
+			data[key] = v
+		}
+	}
+	return data
+}
+
+// compareVersions compares dotted numeric versions such as "3.2.10" and "3.10"; missing components count
+// as zero, and a pre-release suffix ("3.2.0-beta") sorts before the release.
+func compareVersions(a, b string) int {
+	an, apre := splitVersion(a)
+	bn, bpre := splitVersion(b)
+	for i := range max(len(an), len(bn)) {
+		var x, y int
+		if i < len(an) {
+			x = an[i]
+		}
+		if i < len(bn) {
+			y = bn[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case apre == bpre:
+		return 0
+	case apre:
+		return -1
+	case bpre:
+		return 1
+	}
+	return 0
+}
+
+func splitVersion(v string) ([]int, bool) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	v, _, _ = strings.Cut(v, "+")
+	v, pre, hasPre := strings.Cut(v, "-")
+	var nums []int
+	for _, part := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			break
+		}
+		nums = append(nums, n)
+	}
+	return nums, hasPre && pre != ""
+}
+
+// validateClientInfoConfig checks minimum versions and requires client detection for them.
+func validateClientInfoConfig(cfg *conf.ClientInfoConfig) error {
+	if cfg == nil || len(cfg.MinVersions) == 0 {
+		return nil
+	}
+	if !cfg.Enabled {
+		return fmt.Errorf("min versions require client_info.enabled")
+	}
+	seen := make(map[string]struct{}, len(cfg.MinVersions))
+	for _, m := range cfg.MinVersions {
+		platform := normalizePlatform(m.Platform)
+		if platform == "" {
+			return fmt.Errorf("min version %q: platform is required", m.MinVersion)
+		}
+		if _, dup := seen[platform]; dup {
+			return fmt.Errorf("platform %q has more than one min version", platform)
+		}
+		seen[platform] = struct{}{}
+		if nums, _ := splitVersion(m.MinVersion); len(nums) == 0 {
+			return fmt.Errorf("platform %q: invalid min version %q", platform, m.MinVersion)
+		}
+	}
+	if cfg.UpgradeRequiredCode < 0 || cfg.UpgradeRequiredCode == 200 || cfg.UpgradeRequiredCode == BodyCodeSystemFailure {
+		return fmt.Errorf("upgrade required code %d is reserved", cfg.UpgradeRequiredCode)
+	}
+	return nil
+}
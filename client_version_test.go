@@ -0,0 +1,149 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	nhttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareVersions(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"3.2.0", "3.2.0", 0},
+		{"3.2", "3.2.0", 0},
+		{"v3.2.1", "3.2.0", 1},
+		{"3.10.0", "3.9.9", 1},
+		{"3.2.0-beta.1", "3.2.0", -1},
+		{"3.2.0", "3.2.0-rc1", 1},
+		{"3.2.0+build7", "3.2.0", 0},
+		{"2", "10", -1},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, compareVersions(c.a, c.b), "%s vs %s", c.a, c.b)
+	}
+}
+
+func clientVersionTestConfig() *conf.ClientInfoConfig {
+	return &conf.ClientInfoConfig{
+		Enabled:  true,
+		AppNames: []string{"ShopApp"},
+		MinVersions: []*conf.MinClientVersion{
+			{Platform: "ios", MinVersion: "3.2.0", StoreUrl: "https://apps.apple.com/app/id123"},
+			{Platform: "Android", MinVersion: "3.0"},
+		},
+		ExemptOperations: []string{"/api.v1.Config/*"},
+	}
+}
+
+func TestClientVersionPolicy_Check(t *testing.T) {
+	assert.Nil(t, newClientVersionPolicy(&conf.ClientInfoConfig{Enabled: true}))
+	p := newClientVersionPolicy(clientVersionTestConfig())
+	require.NotNil(t, p)
+
+	assert.NoError(t, p.check(ClientInfo{Platform: PlatformIOS, AppVersion: "3.2.0"}))
+	assert.NoError(t, p.check(ClientInfo{Platform: PlatformIOS}), "unknown versions are not checked")
+	assert.NoError(t, p.check(ClientInfo{Platform: PlatformWindows, AppVersion: "1.0"}))
+	assert.NoError(t, p.check(ClientInfo{Platform: PlatformAndroid, AppVersion: "3.0.1"}))
+
+	err := p.check(ClientInfo{Platform: PlatformIOS, AppVersion: "3.1.9"})
+	se := errors.FromError(err)
+	require.NotNil(t, se)
+	assert.Equal(t, UpgradeRequiredReason, se.Reason)
+	assert.Equal(t, int32(defaultUpgradeRequiredCode), se.Code)
+	assert.Equal(t, map[string]string{
+		"platform":    PlatformIOS,
+		"min_version": "3.2.0",
+		"store_url":   "https://apps.apple.com/app/id123",
+	}, upgradeRequiredData(se))
+
+	assert.Nil(t, upgradeRequiredData(errors.BadRequest("OTHER", "")))
+}
+
+func TestClientVersionMiddleware_Envelope(t *testing.T) {
+	cfg := clientVersionTestConfig()
+	cfg.UpgradeRequiredCode = 40026
+	h := &ServiceHttp{conf: &conf.Http{ClientInfo: cfg}}
+	// Application mappers do not remap the upgrade-required code.
+	h.ErrorCodeMapper = func(se *errors.Error) int { return 1 }
+	h.server = http.NewServer(
+		http.Middleware(clientInfoMiddleware(newClientClassifier(cfg)), clientVersionMiddleware(newClientVersionPolicy(cfg))),
+		http.ErrorEncoder(h.enhancedErrorEncoder),
+		http.ResponseEncoder(ResponseEncoder),
+	)
+	serve := func(operation string) http.HandlerFunc {
+		return func(ctx http.Context) error {
+			http.SetOperation(ctx, operation)
+			out, err := ctx.Middleware(func(context.Context, any) (any, error) {
+				return map[string]string{"ok": "1"}, nil
+			})(ctx, nil)
+			if err != nil {
+				return err
+			}
+			return ctx.Result(nhttp.StatusOK, out)
+		}
+	}
+	route := h.server.Route("/")
+	route.GET("/orders", serve("/api.v1.Orders/List"))
+	route.GET("/config", serve("/api.v1.Config/Get"))
+
+	do := func(path, ua string) map[string]any {
+		req := httptest.NewRequest(nhttp.MethodGet, path, nil)
+		req.Header.Set("User-Agent", ua)
+		rec := httptest.NewRecorder()
+		h.server.ServeHTTP(rec, req)
+		assert.Equal(t, nhttp.StatusOK, rec.Code)
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		return body
+	}
+
+	body := do("/orders", "ShopApp/3.1.0 (iPhone; iOS 17.1)")
+	assert.Equal(t, float64(40026), body["code"])
+	assert.Equal(t, map[string]any{
+		"platform":    "ios",
+		"min_version": "3.2.0",
+		"store_url":   "https://apps.apple.com/app/id123",
+	}, body["data"])
+
+	body = do("/orders", "ShopApp/3.2.0 (iPhone; iOS 17.1)")
+	assert.Equal(t, float64(200), body["code"])
+
+	// Exempt operations serve outdated clients.
+	body = do("/config", "ShopApp/3.1.0 (iPhone; iOS 17.1)")
+	assert.Equal(t, float64(200), body["code"])
+}
+
+func TestValidateClientInfoConfig(t *testing.T) {
+	assert.NoError(t, validateClientInfoConfig(nil))
+	assert.NoError(t, validateClientInfoConfig(clientVersionTestConfig()))
+
+	disabled := clientVersionTestConfig()
+	disabled.Enabled = false
+	assert.Error(t, validateClientInfoConfig(disabled))
+
+	dup := clientVersionTestConfig()
+	dup.MinVersions = append(dup.MinVersions, &conf.MinClientVersion{Platform: "iOS", MinVersion: "4"})
+	assert.Error(t, validateClientInfoConfig(dup))
+
+	bad := clientVersionTestConfig()
+	bad.MinVersions[0].MinVersion = "latest"
+	assert.Error(t, validateClientInfoConfig(bad))
+
+	noPlatform := clientVersionTestConfig()
+	noPlatform.MinVersions[0].Platform = ""
+	assert.Error(t, validateClientInfoConfig(noPlatform))
+
+	reserved := clientVersionTestConfig()
+	reserved.UpgradeRequiredCode = BodyCodeSystemFailure
+	assert.Error(t, validateClientInfoConfig(reserved))
+}
@@ -0,0 +1,216 @@
+package http
+
+import (
+	"context"
+	"crypto/sha256"
+	stderrors "errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+)
+
+var defaultCoalesceIdentityHeaders = []string{"Authorization", "Cookie"}
+
+// coalesceKey identifies a request by method, host, tenant, path, query and caller identity.
+type coalesceKey [sha256.Size]byte
+
+// coalesceCall is a running request. done is closed once reply, err and header are set.
+type coalesceCall struct {
+	done  chan struct{}
+	reply any
+	err   error
+	// header holds the reply headers the handler set.
+	header map[string][]string
+}
+
+// coalescePolicy is the resolved CoalesceConfig together with the running requests.
+type coalescePolicy struct {
+	methods         map[string]struct{}
+	identityHeaders []string
+	exempt          []string
+
+	mu    sync.Mutex
+	calls map[coalesceKey]*coalesceCall
+}
+
+// newCoalescePolicy returns nil when coalescing is disabled.
+func newCoalescePolicy(cfg *conf.CoalesceConfig) *coalescePolicy {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	p := &coalescePolicy{
+		methods:         map[string]struct{}{"GET": {}},
+		identityHeaders: defaultCoalesceIdentityHeaders,
+		calls:           make(map[coalesceKey]*coalesceCall),
+	}
+	if len(cfg.GetMethods()) > 0 {
+		p.methods = make(map[string]struct{}, len(cfg.GetMethods()))
+		for _, m := range cfg.GetMethods() {
+			p.methods[strings.ToUpper(strings.TrimSpace(m))] = struct{}{}
+		}
+	}
+	if len(cfg.GetIdentityHeaders()) > 0 {
+		p.identityHeaders = nil
+		for _, name := range cfg.GetIdentityHeaders() {
+			if name = strings.TrimSpace(name); name != "" {
+				p.identityHeaders = append(p.identityHeaders, name)
+			}
+		}
+	}
+	for _, op := range cfg.GetExemptOperations() {
+		if op = strings.TrimSpace(op); op != "" {
+			p.exempt = append(p.exempt, op)
+		}
+	}
+	return p
+}
+
+// key hashes the request, including the host and the tenant and virtual host resolved earlier in the chain,
+// so requests for different tenants never share a reply. ok is false for methods that are not coalesced and
+// exempt operations.
+func (p *coalescePolicy) key(ctx context.Context, r *http.Request, operation string) (coalesceKey, bool) {
+	if _, ok := p.methods[r.Method]; !ok || routeMatchesAny(p.exempt, operation, r.URL.Path) {
+		return coalesceKey{}, false
+	}
+	tenant, _ := TenantFromContext(ctx)
+	vhost, _ := VirtualHostFromContext(ctx)
+	h := sha256.New()
+	// Encode sorts the parameters, so their order does not matter.
+	for _, part := range []string{r.Method, strings.ToLower(r.Host), tenant, vhost, r.URL.Path, r.URL.Query().Encode()} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	for _, name := range p.identityHeaders {
+		for _, v := range r.Header.Values(name) {
+			h.Write([]byte(v))
+			h.Write([]byte{0})
+		}
+		h.Write([]byte{1})
+	}
+	var k coalesceKey
+	h.Sum(k[:0])
+	return k, true
+}
+
+// join returns the running call for k, or registers a new one and reports leader.
+func (p *coalescePolicy) join(k coalesceKey) (c *coalesceCall, leader bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if c := p.calls[k]; c != nil {
+		return c, false
+	}
+	c = &coalesceCall{done: make(chan struct{})}
+	p.calls[k] = c
+	return c, true
+}
+
+// finish publishes the result of the leader to the waiting requests and forgets the call.
+func (p *coalescePolicy) finish(k coalesceKey, c *coalesceCall, reply any, err error, header map[string][]string) {
+	p.mu.Lock()
+	c.reply, c.err, c.header = reply, err, header
+	delete(p.calls, k)
+	p.mu.Unlock()
+	close(c.done)
+}
+
+// coalesceMiddleware runs the first of identical concurrent requests and answers the others that arrive
+// while it runs with its reply or error, together with the reply headers the handler set. Requests waiting
+// on a leader that was canceled run the handler themselves.
+func (h *ServiceHttp) coalesceMiddleware(p *coalescePolicy) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (reply any, err error) {
+			tr, ok := transport.FromServerContext(ctx)
+			if !ok {
+				return handler(ctx, req)
+			}
+			r, ok := http.RequestFromServerContext(ctx)
+			if !ok || r == nil {
+				return handler(ctx, req)
+			}
+			k, ok := p.key(ctx, r, tr.Operation())
+			if !ok {
+				return handler(ctx, req)
+			}
+			c, leader := p.join(k)
+			if !leader {
+				select {
+				case <-c.done:
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+				if stderrors.Is(c.err, context.Canceled) || stderrors.Is(c.err, context.DeadlineExceeded) {
+					return handler(ctx, req)
+				}
+				for key, values := range c.header {
+					if tr.ReplyHeader().Get(key) == "" {
+						for _, v := range values {
+							tr.ReplyHeader().Add(key, v)
+						}
+					}
+				}
+				if h.coalescedRequests != nil {
+					_, route := requestMetadata(ctx)
+					h.coalescedRequests.WithLabelValues(route).Inc()
+				}
+				return c.reply, c.err
+			}
+
+			before := headerSnapshot(tr.ReplyHeader())
+			completed := false
+			defer func() {
+				if !completed {
+					// The handler panicked: release the waiting requests with an error.
+					p.finish(k, c, nil, errors.InternalServer("COALESCE_ABORTED", "coalesced with a request that failed"), nil)
+				}
+			}()
+			reply, err = handler(ctx, req)
+			completed = true
+			p.finish(k, c, reply, err, headerChanges(before, headerSnapshot(tr.ReplyHeader())))
+			return reply, err
+		}
+	}
+}
+
+func headerSnapshot(header transport.Header) map[string][]string {
+	snapshot := make(map[string][]string)
+	for _, key := range header.Keys() {
+		snapshot[key] = slices.Clone(header.Values(key))
+	}
+	return snapshot
+}
+
+// headerChanges returns the headers of after that are missing from before or have other values.
+func headerChanges(before, after map[string][]string) map[string][]string {
+	changed := make(map[string][]string)
+	for key, values := range after {
+		if !slices.Equal(before[key], values) {
+			changed[key] = values
+		}
+	}
+	return changed
+}
+
+// validateCoalesceConfig rejects empty method and header names.
+func validateCoalesceConfig(cfg *conf.CoalesceConfig) error {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	for _, m := range cfg.GetMethods() {
+		if strings.TrimSpace(m) == "" {
+			return fmt.Errorf("methods contains an empty method")
+		}
+	}
+	for _, name := range cfg.GetIdentityHeaders() {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("identity_headers contains an empty header name")
+		}
+	}
+	return nil
+}
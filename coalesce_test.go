@@ -0,0 +1,185 @@
+package http
+
+import (
+	"context"
+	nhttp "net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx-http/httptesting"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoalesceMiddleware_SharesConcurrentReplies(t *testing.T) {
+	svc := NewServiceHttp()
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_coalesced_total"}, []string{"route"})
+	svc.coalescedRequests = counter
+	p := newCoalescePolicy(&conf.CoalesceConfig{Enabled: true, ExemptOperations: []string{"/api.v1.Events/*"}})
+
+	var runs atomic.Int32
+	release := make(chan struct{})
+	tester := httptesting.NewMiddlewareTester(t, svc.coalesceMiddleware(p)).
+		WithHandler(func(ctx context.Context, req any) (any, error) {
+			n := runs.Add(1)
+			if n == 1 {
+				<-release
+			}
+			tr, _ := transport.FromServerContext(ctx)
+			tr.ReplyHeader().Set("ETag", `"v1"`)
+			return n, nil
+		})
+	get := func(path, identity string) *httptesting.Result {
+		return tester.Run(httptesting.Request{
+			Operation: "/api.v1.Catalog/List",
+			Path:      path,
+			Header:    map[string]string{"Authorization": identity},
+		})
+	}
+
+	var wg sync.WaitGroup
+	results := make([]*httptesting.Result, 3)
+	paths := []string{"/catalog?a=1&b=2", "/catalog?b=2&a=1", "/catalog?a=1&b=2"}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0] = get(paths[0], "Bearer a")
+	}()
+	require.Eventually(t, func() bool { return runs.Load() == 1 }, time.Second, time.Millisecond)
+	for i := 1; i < len(results); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = get(paths[i], "Bearer a")
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	for _, res := range results {
+		res.AssertNoError()
+		assert.Equal(t, int32(1), res.Reply)
+		assert.Equal(t, `"v1"`, res.Transport.ReplyHeader().Get("ETag"))
+	}
+	assert.Equal(t, 2.0, testutil.ToFloat64(counter.WithLabelValues("/api.v1.Catalog/List")))
+	assert.Empty(t, p.calls, "nothing is kept once the handler returns")
+
+	// Completed requests, other callers and exempt operations run the handler.
+	assert.Equal(t, int32(2), get(paths[0], "Bearer a").Reply)
+	assert.Equal(t, int32(3), get(paths[0], "Bearer b").Reply)
+	res := tester.Run(httptesting.Request{Operation: "/api.v1.Catalog/List", Method: nhttp.MethodPost, Path: paths[0]})
+	assert.Equal(t, int32(4), res.Reply)
+}
+
+func TestCoalesceMiddleware_SeparatesHostsAndTenants(t *testing.T) {
+	p := newCoalescePolicy(&conf.CoalesceConfig{Enabled: true})
+	// Stands in for the tenant middleware, which runs earlier in the chain.
+	tenant := func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			if tr, ok := transport.FromServerContext(ctx); ok && tr.RequestHeader().Get("X-Tenant-ID") != "" {
+				ctx = WithTenant(ctx, tr.RequestHeader().Get("X-Tenant-ID"))
+			}
+			return handler(ctx, req)
+		}
+	}
+	var runs atomic.Int32
+	release := make(chan struct{})
+	tester := httptesting.NewMiddlewareTester(t, tenant, NewServiceHttp().coalesceMiddleware(p)).
+		WithHandler(func(ctx context.Context, req any) (any, error) {
+			runs.Add(1)
+			<-release
+			tenant, _ := TenantFromContext(ctx)
+			r, _ := http.RequestFromServerContext(ctx)
+			return r.Host + "/" + tenant, nil
+		})
+
+	requests := []httptesting.Request{
+		{Operation: "/home", Path: "http://acme.example.com/home"},
+		{Operation: "/home", Path: "http://globex.example.com/home"},
+		{Operation: "/home", Path: "http://acme.example.com/home", Header: map[string]string{"X-Tenant-ID": "globex"}},
+	}
+	results := make([]*httptesting.Result, len(requests))
+	var wg sync.WaitGroup
+	for i, req := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = tester.Run(req)
+		}()
+	}
+	require.Eventually(t, func() bool { return runs.Load() == 3 }, time.Second, time.Millisecond,
+		"each host and tenant runs its own handler")
+	close(release)
+	wg.Wait()
+	assert.Equal(t, "acme.example.com/", results[0].Reply)
+	assert.Equal(t, "globex.example.com/", results[1].Reply)
+	assert.Equal(t, "acme.example.com/globex", results[2].Reply)
+}
+
+func TestCoalesceMiddleware_CanceledLeader(t *testing.T) {
+	p := newCoalescePolicy(&conf.CoalesceConfig{Enabled: true})
+	started, release := make(chan struct{}), make(chan struct{})
+	var runs atomic.Int32
+	tester := httptesting.NewMiddlewareTester(t, NewServiceHttp().coalesceMiddleware(p)).
+		WithHandler(func(ctx context.Context, req any) (any, error) {
+			if runs.Add(1) == 1 {
+				close(started)
+				<-release
+				return nil, context.Canceled
+			}
+			return "fresh", nil
+		})
+	run := func() *httptesting.Result {
+		return tester.Run(httptesting.Request{Operation: "/api.v1.Catalog/List", Path: "/catalog"})
+	}
+
+	done := make(chan *httptesting.Result)
+	go func() { done <- run() }()
+	<-started
+	waiter := make(chan *httptesting.Result)
+	go func() { waiter <- run() }()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	assert.ErrorIs(t, (<-done).Err, context.Canceled)
+	res := <-waiter
+	res.AssertNoError()
+	assert.Equal(t, "fresh", res.Reply, "waiting requests run the handler themselves")
+}
+
+func TestCoalesceMiddleware_PanicReleasesWaiters(t *testing.T) {
+	p := newCoalescePolicy(&conf.CoalesceConfig{Enabled: true})
+	started, release := make(chan struct{}), make(chan struct{})
+	tester := httptesting.NewMiddlewareTester(t, NewServiceHttp().coalesceMiddleware(p)).
+		WithHandler(func(ctx context.Context, req any) (any, error) {
+			close(started)
+			<-release
+			panic("boom")
+		})
+
+	go func() {
+		defer func() { _ = recover() }()
+		tester.Run(httptesting.Request{Operation: "/api.v1.Catalog/List", Path: "/catalog"})
+	}()
+	<-started
+	waiter := make(chan *httptesting.Result)
+	go func() { waiter <- tester.Run(httptesting.Request{Operation: "/api.v1.Catalog/List", Path: "/catalog"}) }()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	(<-waiter).AssertError(nhttp.StatusInternalServerError, "COALESCE_ABORTED")
+}
+
+func TestValidateCoalesceConfig(t *testing.T) {
+	assert.Nil(t, newCoalescePolicy(&conf.CoalesceConfig{Methods: []string{"GET"}}))
+	require.NoError(t, validateCoalesceConfig(nil))
+	require.NoError(t, validateCoalesceConfig(&conf.CoalesceConfig{Enabled: true, Methods: []string{"get", "HEAD"}}))
+	assert.ErrorContains(t, validateCoalesceConfig(&conf.CoalesceConfig{Enabled: true, Methods: []string{" "}}), "empty method")
+	assert.ErrorContains(t, validateCoalesceConfig(&conf.CoalesceConfig{Enabled: true, IdentityHeaders: []string{""}}), "empty header")
+}
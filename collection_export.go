@@ -0,0 +1,485 @@
+package http
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	nhttp "net/http"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+const (
+	defaultCollectionExportPath = "/debug/collection"
+	defaultCollectionName       = "lynx-http"
+
+	postmanCollectionSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+	// exampleMaxDepth bounds the nesting of example messages, so recursive messages end.
+	exampleMaxDepth = 3
+)
+
+// exampleWellKnownTypes are example JSON values of the well-known types.
+var exampleWellKnownTypes = map[protoreflect.FullName]any{
+	"google.protobuf.Timestamp":   "2024-01-01T00:00:00Z",
+	"google.protobuf.Duration":    "1s",
+	"google.protobuf.FieldMask":   "",
+	"google.protobuf.Struct":      map[string]any{},
+	"google.protobuf.Value":       nil,
+	"google.protobuf.ListValue":   []any{},
+	"google.protobuf.Empty":       map[string]any{},
+	"google.protobuf.DoubleValue": 0,
+	"google.protobuf.FloatValue":  0,
+	"google.protobuf.Int32Value":  0,
+	"google.protobuf.UInt32Value": 0,
+	"google.protobuf.Int64Value":  0,
+	"google.protobuf.UInt64Value": 0,
+	"google.protobuf.BoolValue":   false,
+	"google.protobuf.StringValue": "",
+	"google.protobuf.BytesValue":  "",
+}
+
+// exampleField is a field of an example message.
+type exampleField struct {
+	name  string
+	value any
+}
+
+// exampleObject is an example message; its fields are written in declaration order.
+type exampleObject []exampleField
+
+func (o exampleObject) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, f := range o {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		name, _ := json.Marshal(f.name)
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(name)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// examples builds example request values from proto descriptors, with the field names the server decodes.
+type examples struct {
+	jsonNames bool
+}
+
+func (e examples) fieldName(fd protoreflect.FieldDescriptor) string {
+	if e.jsonNames {
+		return fd.JSONName()
+	}
+	return string(fd.Name())
+}
+
+// message returns an example of md without the fields in skip; ok is false past exampleMaxDepth or for Any,
+// whose example depends on the packed type.
+func (e examples) message(md protoreflect.MessageDescriptor, skip map[string]bool, depth int) (any, bool) {
+	if v, ok := exampleWellKnownTypes[md.FullName()]; ok {
+		return v, true
+	}
+	if depth >= exampleMaxDepth || md.FullName() == "google.protobuf.Any" {
+		return nil, false
+	}
+	obj := exampleObject{}
+	for i := 0; i < md.Fields().Len(); i++ {
+		fd := md.Fields().Get(i)
+		if skip[string(fd.Name())] {
+			continue
+		}
+		if v, ok := e.field(fd, depth); ok {
+			obj = append(obj, exampleField{e.fieldName(fd), v})
+		}
+	}
+	return obj, true
+}
+
+func (e examples) field(fd protoreflect.FieldDescriptor, depth int) (any, bool) {
+	if fd.IsMap() {
+		v, ok := e.singular(fd.MapValue(), depth)
+		key := "key"
+		if fd.MapKey().Kind() != protoreflect.StringKind {
+			key = fmt.Sprint(e.scalar(fd.MapKey()))
+		}
+		return map[string]any{key: v}, ok
+	}
+	v, ok := e.singular(fd, depth)
+	if fd.IsList() {
+		return []any{v}, ok
+	}
+	return v, ok
+}
+
+func (e examples) singular(fd protoreflect.FieldDescriptor, depth int) (any, bool) {
+	if fd.Message() != nil {
+		return e.message(fd.Message(), nil, depth+1)
+	}
+	return e.scalar(fd), true
+}
+
+// scalar returns the default of fd, or an example of its kind: the field name for strings, zero for numbers and
+// the first value after the zero value for enums.
+func (e examples) scalar(fd protoreflect.FieldDescriptor) any {
+	if fd.Kind() == protoreflect.EnumKind {
+		values := fd.Enum().Values()
+		if fd.HasDefault() {
+			return string(values.ByNumber(fd.Default().Enum()).Name())
+		}
+		if values.Len() > 1 {
+			return string(values.Get(1).Name())
+		}
+		return string(values.Get(0).Name())
+	}
+	if fd.HasDefault() {
+		if fd.Kind() == protoreflect.BytesKind {
+			return base64.StdEncoding.EncodeToString(fd.Default().Bytes())
+		}
+		return fd.Default().Interface()
+	}
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return false
+	case protoreflect.StringKind:
+		return string(fd.Name())
+	case protoreflect.BytesKind:
+		return ""
+	default:
+		return 0
+	}
+}
+
+// pathField returns the field a path variable such as "shelf.name" refers to.
+func pathField(md protoreflect.MessageDescriptor, name string) protoreflect.FieldDescriptor {
+	var fd protoreflect.FieldDescriptor
+	for _, part := range strings.Split(name, ".") {
+		if md == nil {
+			return nil
+		}
+		if fd = md.Fields().ByName(protoreflect.Name(part)); fd == nil {
+			return nil
+		}
+		md = fd.Message()
+	}
+	return fd
+}
+
+// collectionParam is a path variable or query parameter of an example request.
+type collectionParam struct {
+	key, value string
+}
+
+// collectionRequest is an example request for a route of the server.
+type collectionRequest struct {
+	// folder is the proto service of the route, "" for routes without one.
+	folder    string
+	name      string
+	operation string
+	method    string
+	// path has Postman path variables, e.g. "/v1/apis/:name".
+	path  string
+	vars  []collectionParam
+	query []collectionParam
+	// body is the indented JSON body, "" without one.
+	body string
+}
+
+// collectionRequests returns an example request per route, in route order.
+func (h *ServiceHttp) collectionRequests(files *protoregistry.Files) []collectionRequest {
+	if h.server == nil {
+		return nil
+	}
+	type boundMethod struct {
+		desc  protoreflect.MethodDescriptor
+		route httpRoute
+	}
+	bound := make(map[httpBinding]boundMethod)
+	for _, s := range h.protoServices(&conf.GrpcParityConfig{}, files) {
+		d, _ := files.FindDescriptorByName(protoreflect.FullName(s.Name))
+		sd, ok := d.(protoreflect.ServiceDescriptor)
+		if !ok {
+			continue
+		}
+		for i := 0; i < sd.Methods().Len(); i++ {
+			md := sd.Methods().Get(i)
+			for _, r := range httpRoutes(md) {
+				bound[httpBinding{Method: r.Method, Path: normalizeRouteTemplate(r.Path)}] = boundMethod{md, r}
+			}
+		}
+	}
+	pj := h.conf.GetResponse().GetProtoJson()
+	ex := examples{jsonNames: pj.GetEnabled() && pj.GetUseJsonNames()}
+
+	var requests []collectionRequest
+	_ = h.server.WalkRoute(func(ri http.RouteInfo) error {
+		req := collectionRequest{name: ri.Method + " " + ri.Path, method: ri.Method}
+		var names []string
+		req.path = routeVariable.ReplaceAllStringFunc(ri.Path, func(v string) string {
+			name, _, _ := strings.Cut(strings.Trim(v, "{}"), ":")
+			name, _, _ = strings.Cut(name, "=")
+			names = append(names, name)
+			return ":" + name
+		})
+		m, ok := bound[httpBinding{Method: ri.Method, Path: normalizeRouteTemplate(ri.Path)}]
+		if !ok {
+			for _, name := range names {
+				req.vars = append(req.vars, collectionParam{key: name})
+			}
+			requests = append(requests, req)
+			return nil
+		}
+		sd := m.desc.Parent().(protoreflect.ServiceDescriptor)
+		req.folder, req.name = string(sd.FullName()), string(m.desc.Name())
+		req.operation = fmt.Sprintf("/%s/%s", sd.FullName(), m.desc.Name())
+		in := m.desc.Input()
+		skip := make(map[string]bool)
+		for _, name := range names {
+			value := ""
+			if fd := pathField(in, name); fd != nil && fd.Message() == nil {
+				value = fmt.Sprint(ex.scalar(fd))
+			}
+			req.vars = append(req.vars, collectionParam{key: name, value: value})
+			if !strings.Contains(name, ".") {
+				skip[name] = true
+			}
+		}
+		var body any
+		switch m.route.body {
+		case "":
+			for i := 0; i < in.Fields().Len(); i++ {
+				fd := in.Fields().Get(i)
+				if !skip[string(fd.Name())] && fd.Message() == nil && !fd.IsMap() {
+					req.query = append(req.query, collectionParam{key: ex.fieldName(fd), value: fmt.Sprint(ex.scalar(fd))})
+				}
+			}
+		case "*":
+			body, _ = ex.message(in, skip, 0)
+		default:
+			if fd := in.Fields().ByName(protoreflect.Name(m.route.body)); fd != nil {
+				body, _ = ex.field(fd, 0)
+			}
+		}
+		if body != nil {
+			b, err := json.MarshalIndent(body, "", "  ")
+			if err == nil {
+				req.body = string(b)
+			}
+		}
+		requests = append(requests, req)
+		return nil
+	})
+	return requests
+}
+
+// collectionSettings returns the collection name and base URL.
+func (h *ServiceHttp) collectionSettings() (name, baseURL string) {
+	cfg := h.conf.GetCollectionExport()
+	name = configuredPath(cfg.GetName(), defaultCollectionName)
+	if baseURL = strings.TrimSpace(cfg.GetBaseUrl()); baseURL != "" {
+		return name, baseURL
+	}
+	scheme, port := "http", "8080"
+	if h.conf.GetTlsEnable() {
+		scheme = "https"
+	}
+	if _, p, err := net.SplitHostPort(h.conf.GetAddr()); err == nil && p != "" {
+		port = p
+	}
+	return name, scheme + "://localhost:" + port
+}
+
+type postmanCollection struct {
+	Info     postmanInfo       `json:"info"`
+	Variable []postmanVariable `json:"variable"`
+	Item     []postmanItem     `json:"item"`
+}
+
+type postmanInfo struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+}
+
+type postmanVariable struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+// postmanItem is a folder (with Item) or a request.
+type postmanItem struct {
+	Name    string          `json:"name"`
+	Item    []postmanItem   `json:"item,omitempty"`
+	Request *postmanRequest `json:"request,omitempty"`
+}
+
+type postmanRequest struct {
+	Method      string            `json:"method"`
+	Header      []postmanVariable `json:"header"`
+	URL         postmanURL        `json:"url"`
+	Body        *postmanBody      `json:"body,omitempty"`
+	Description string            `json:"description,omitempty"`
+}
+
+type postmanURL struct {
+	Raw      string            `json:"raw"`
+	Host     []string          `json:"host"`
+	Path     []string          `json:"path"`
+	Query    []postmanVariable `json:"query,omitempty"`
+	Variable []postmanVariable `json:"variable,omitempty"`
+}
+
+type postmanBody struct {
+	Mode    string         `json:"mode"`
+	Raw     string         `json:"raw"`
+	Options map[string]any `json:"options"`
+}
+
+// WritePostmanCollection writes the routes of the server as a Postman collection (v2.1), with example requests
+// for routes bound by proto services. It serves the collection export endpoint and can be called from a CLI.
+func (h *ServiceHttp) WritePostmanCollection(w io.Writer) error {
+	return h.writePostmanCollection(w, protoregistry.GlobalFiles)
+}
+
+func (h *ServiceHttp) writePostmanCollection(w io.Writer, files *protoregistry.Files) error {
+	name, baseURL := h.collectionSettings()
+	c := postmanCollection{
+		Info:     postmanInfo{Name: name, Schema: postmanCollectionSchema},
+		Variable: []postmanVariable{{Key: "baseUrl", Value: baseURL}},
+		Item:     []postmanItem{},
+	}
+	folders := make(map[string]int)
+	for _, req := range h.collectionRequests(files) {
+		pr := &postmanRequest{
+			Method:      req.method,
+			Header:      []postmanVariable{},
+			URL:         postmanURL{Raw: "{{baseUrl}}" + req.path, Host: []string{"{{baseUrl}}"}},
+			Description: req.operation,
+		}
+		pr.URL.Path = strings.Split(strings.Trim(req.path, "/"), "/")
+		for _, v := range req.vars {
+			pr.URL.Variable = append(pr.URL.Variable, postmanVariable{Key: v.key, Value: v.value})
+		}
+		for _, q := range req.query {
+			pr.URL.Query = append(pr.URL.Query, postmanVariable{Key: q.key, Value: q.value, Disabled: true})
+		}
+		if req.body != "" {
+			pr.Header = append(pr.Header, postmanVariable{Key: "Content-Type", Value: "application/json"})
+			pr.Body = &postmanBody{Mode: "raw", Raw: req.body, Options: map[string]any{"raw": map[string]string{"language": "json"}}}
+		}
+		item := postmanItem{Name: req.name, Request: pr}
+		if req.folder == "" {
+			c.Item = append(c.Item, item)
+			continue
+		}
+		i, ok := folders[req.folder]
+		if !ok {
+			i = len(c.Item)
+			folders[req.folder] = i
+			c.Item = append(c.Item, postmanItem{Name: req.folder})
+		}
+		c.Item[i].Item = append(c.Item[i].Item, item)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c)
+}
+
+// WriteHTTPieScript writes the routes of the server as a shell script of HTTPie commands against $BASE_URL,
+// with the example requests of WritePostmanCollection.
+func (h *ServiceHttp) WriteHTTPieScript(w io.Writer) error {
+	return h.writeHTTPieScript(w, protoregistry.GlobalFiles)
+}
+
+func (h *ServiceHttp) writeHTTPieScript(w io.Writer, files *protoregistry.Files) error {
+	name, baseURL := h.collectionSettings()
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "#!/bin/sh\n# %s\nBASE_URL=\"${BASE_URL:-%s}\"\n", name, baseURL)
+	for _, req := range h.collectionRequests(files) {
+		path := req.path
+		for _, v := range req.vars {
+			value := v.value
+			if value == "" {
+				value = v.key
+			}
+			path = strings.Replace(path, ":"+v.key, value, 1)
+		}
+		title := req.name
+		if req.operation != "" {
+			title = req.operation
+		}
+		fmt.Fprintf(&b, "\n# %s\nhttp --ignore-stdin %s \"$BASE_URL%s\"", title, req.method, path)
+		if req.body != "" {
+			fmt.Fprintf(&b, " --raw '%s'", strings.ReplaceAll(req.body, "'", `'\''`))
+		}
+		b.WriteByte('\n')
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// collectionExportHandler serves the Postman collection, or the HTTPie script with ?format=httpie.
+func (h *ServiceHttp) collectionExportHandler() nhttp.Handler {
+	return nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		if r.Method != nhttp.MethodGet && r.Method != nhttp.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			nhttp.Error(w, "method not allowed", nhttp.StatusMethodNotAllowed)
+			return
+		}
+		var b bytes.Buffer
+		name, _ := h.collectionSettings()
+		switch format := r.URL.Query().Get("format"); format {
+		case "", "postman":
+			_ = h.WritePostmanCollection(&b)
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+".postman_collection.json"))
+		case "httpie":
+			_ = h.WriteHTTPieScript(&b)
+			w.Header().Set("Content-Type", "text/x-shellscript; charset=utf-8")
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+".sh"))
+		default:
+			nhttp.Error(w, fmt.Sprintf("unknown format %q: use postman or httpie", format), nhttp.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write(b.Bytes())
+	})
+}
+
+// mountCollectionExport serves the collection export endpoint when enabled.
+func (h *ServiceHttp) mountCollectionExport() {
+	cfg := h.conf.GetCollectionExport()
+	if !cfg.GetEnabled() {
+		return
+	}
+	path := configuredPath(cfg.GetPath(), defaultCollectionExportPath)
+	h.server.Handle(path, &netHTTPToKratosHandlerAdapter{handler: h.collectionExportHandler()})
+	log.Infof("Collection export endpoint mounted at %s", path)
+}
+
+// validateCollectionExportConfig requires an absolute endpoint path and an http(s) base URL.
+func validateCollectionExportConfig(cfg *conf.CollectionExportConfig) error {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	if path := configuredPath(cfg.GetPath(), defaultCollectionExportPath); !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("path %q must start with /", path)
+	}
+	if u := strings.TrimSpace(cfg.GetBaseUrl()); u != "" && !strings.HasPrefix(u, "http://") && !strings.HasPrefix(u, "https://") {
+		return fmt.Errorf("base_url %q must be an http or https URL", u)
+	}
+	return nil
+}
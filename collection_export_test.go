@@ -0,0 +1,110 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func collectionTestService(t *testing.T, cfg *conf.Http) *ServiceHttp {
+	t.Helper()
+	h := NewServiceHttp()
+	h.conf = cfg
+	h.server = http.NewServer()
+	r := h.server.Route("/")
+	r.GET("/v1/apis/{name}", func(http.Context) error { return nil })
+	r.POST("/v1/apis", func(http.Context) error { return nil })
+	r.PATCH("/v1/apis/{name}/source", func(http.Context) error { return nil })
+	r.POST("/reports/{id}", func(http.Context) error { return nil })
+	return h
+}
+
+func TestPostmanCollection(t *testing.T) {
+	h := collectionTestService(t, &conf.Http{Addr: ":9000", CollectionExport: &conf.CollectionExportConfig{Name: "catalog"}})
+	var out bytes.Buffer
+	require.NoError(t, h.writePostmanCollection(&out, sdkTestFiles(t)))
+	var c postmanCollection
+	require.NoError(t, json.Unmarshal(out.Bytes(), &c))
+
+	assert.Equal(t, postmanInfo{Name: "catalog", Schema: postmanCollectionSchema}, c.Info)
+	assert.Equal(t, []postmanVariable{{Key: "baseUrl", Value: "http://localhost:9000"}}, c.Variable)
+	require.Len(t, c.Item, 2)
+	folder, report := c.Item[0], c.Item[1]
+	assert.Equal(t, "catalog.v1.Apis", folder.Name)
+	require.Len(t, folder.Item, 3)
+
+	get := folder.Item[0]
+	assert.Equal(t, "GetApi", get.Name)
+	assert.Equal(t, "/catalog.v1.Apis/GetApi", get.Request.Description)
+	assert.Equal(t, "{{baseUrl}}/v1/apis/:name", get.Request.URL.Raw)
+	assert.Equal(t, []string{"v1", "apis", ":name"}, get.Request.URL.Path)
+	assert.Equal(t, []postmanVariable{{Key: "name", Value: "name"}}, get.Request.URL.Variable)
+	assert.Contains(t, get.Request.URL.Query, postmanVariable{Key: "version", Value: "version", Disabled: true})
+	assert.Contains(t, get.Request.URL.Query, postmanVariable{Key: "syntax", Value: "SYNTAX_PROTO3", Disabled: true})
+	assert.Nil(t, get.Request.Body)
+
+	// Bodies carry every field but the path variables, in declaration order.
+	create := folder.Item[1]
+	require.NotNil(t, create.Request.Body)
+	assert.Equal(t, []postmanVariable{{Key: "Content-Type", Value: "application/json"}}, create.Request.Header)
+	assert.Regexp(t, `(?s)^\{\s+"name": "name",\s+"methods": \[`, create.Request.Body.Raw)
+	assert.Contains(t, create.Request.Body.Raw, `"source_context": {`)
+	assert.Contains(t, create.Request.Body.Raw, `"file_name": "file_name"`)
+	assert.NotContains(t, create.Request.Body.Raw, `"value"`, "Any values are left out")
+	update := folder.Item[2]
+	assert.JSONEq(t, `{"file_name":"file_name"}`, update.Request.Body.Raw)
+
+	assert.Equal(t, "POST /reports/{id}", report.Name)
+	assert.Equal(t, "{{baseUrl}}/reports/:id", report.Request.URL.Raw)
+	assert.Equal(t, []postmanVariable{{Key: "id"}}, report.Request.URL.Variable)
+	assert.Empty(t, report.Request.Description)
+}
+
+func TestHTTPieScript(t *testing.T) {
+	h := collectionTestService(t, &conf.Http{TlsEnable: true, Response: &conf.ResponseConfig{
+		ProtoJson: &conf.ProtoJSONConfig{Enabled: true, UseJsonNames: true},
+	}})
+	var out bytes.Buffer
+	require.NoError(t, h.writeHTTPieScript(&out, sdkTestFiles(t)))
+	script := out.String()
+	assert.Contains(t, script, "#!/bin/sh\n# lynx-http\nBASE_URL=\"${BASE_URL:-https://localhost:8080}\"\n")
+	assert.Contains(t, script, "\n# /catalog.v1.Apis/GetApi\nhttp --ignore-stdin GET \"$BASE_URL/v1/apis/name\"\n")
+	assert.Contains(t, script, "http --ignore-stdin PATCH \"$BASE_URL/v1/apis/name/source\" --raw '{\n  \"fileName\": \"file_name\"\n}'\n")
+	assert.Contains(t, script, "\"sourceContext\": {")
+	assert.Contains(t, script, "\n# POST /reports/{id}\nhttp --ignore-stdin POST \"$BASE_URL/reports/id\"\n")
+}
+
+func TestCollectionExportEndpoint(t *testing.T) {
+	svc, err := NewTestService(&conf.Http{CollectionExport: &conf.CollectionExportConfig{Enabled: true, BaseUrl: "https://staging.example.com"}})
+	require.NoError(t, err)
+	defer func() { _ = svc.Close() }()
+	svc.Server().Route("/").GET("/v1/ping", func(ctx http.Context) error { return ctx.String(200, "pong") })
+
+	res, err := svc.Get(defaultCollectionExportPath)
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+	assert.Contains(t, res.Header.Get("Content-Disposition"), "lynx-http.postman_collection.json")
+	var c postmanCollection
+	require.NoError(t, json.Unmarshal(res.Body, &c))
+	assert.Equal(t, "https://staging.example.com", c.Variable[0].Value)
+	assert.Contains(t, string(res.Body), `"raw": "{{baseUrl}}/v1/ping"`)
+
+	res, err = svc.Get(defaultCollectionExportPath + "?format=httpie")
+	require.NoError(t, err)
+	assert.Contains(t, string(res.Body), `http --ignore-stdin GET "$BASE_URL/v1/ping"`)
+	res, err = svc.Get(defaultCollectionExportPath + "?format=insomnia")
+	require.NoError(t, err)
+	assert.Equal(t, 400, res.StatusCode)
+}
+
+func TestValidateCollectionExportConfig(t *testing.T) {
+	require.NoError(t, validateCollectionExportConfig(nil))
+	require.NoError(t, validateCollectionExportConfig(&conf.CollectionExportConfig{Enabled: true, BaseUrl: "http://qa:8000"}))
+	assert.Error(t, validateCollectionExportConfig(&conf.CollectionExportConfig{Enabled: true, Path: "collection"}))
+	assert.Error(t, validateCollectionExportConfig(&conf.CollectionExportConfig{Enabled: true, BaseUrl: "qa:8000"}))
+}
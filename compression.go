@@ -0,0 +1,287 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	nhttp "net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+)
+
+const (
+	defaultCompressionMinSize    = 1024
+	defaultCompressionCacheTTL   = 10 * time.Second
+	defaultCompressionCacheBytes = 16 << 20
+)
+
+// compressionPolicy is the resolved CompressionConfig.
+type compressionPolicy struct {
+	minSize int
+	exempt  []string
+	writers sync.Pool
+	// cache is nil unless the compressed body cache is enabled.
+	cache *compressedBodyCache
+}
+
+// newCompressionPolicy returns nil when compression is disabled.
+func newCompressionPolicy(cfg *conf.CompressionConfig) *compressionPolicy {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	p := &compressionPolicy{minSize: defaultCompressionMinSize}
+	if cfg.GetMinSize() > 0 {
+		p.minSize = int(cfg.GetMinSize())
+	}
+	level := gzip.DefaultCompression
+	if cfg.GetLevel() != 0 {
+		level = int(cfg.GetLevel())
+	}
+	p.writers.New = func() any {
+		zw, _ := gzip.NewWriterLevel(nil, level)
+		return zw
+	}
+	for _, op := range cfg.GetExemptOperations() {
+		if op = strings.TrimSpace(op); op != "" {
+			p.exempt = append(p.exempt, op)
+		}
+	}
+	if c := cfg.GetCache(); c.GetEnabled() {
+		ttl, maxBytes := defaultCompressionCacheTTL, int64(defaultCompressionCacheBytes)
+		if c.GetTtl() != nil {
+			ttl = c.GetTtl().AsDuration()
+		}
+		if c.GetMaxBytes() > 0 {
+			maxBytes = c.GetMaxBytes()
+		}
+		p.cache = newCompressedBodyCache(ttl, maxBytes)
+	}
+	return p
+}
+
+// compress returns body gzipped.
+func (p *compressionPolicy) compress(body []byte) ([]byte, error) {
+	var out bytes.Buffer
+	zw := p.writers.Get().(*gzip.Writer)
+	defer p.writers.Put(zw)
+	zw.Reset(&out)
+	if _, err := zw.Write(body); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// acceptsGzip reports whether an Accept-Encoding header value accepts gzip.
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if coding = strings.TrimSpace(coding); coding != "gzip" && coding != "*" {
+			continue
+		}
+		q := strings.TrimSpace(params)
+		if v, ok := strings.CutPrefix(q, "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil && f == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// withCompression gzips the replies of encode for clients accepting gzip, serving identical bodies from the
+// policy's cache when it is enabled.
+func (h *ServiceHttp) withCompression(p *compressionPolicy, encode http.EncodeResponseFunc) http.EncodeResponseFunc {
+	return func(w http.ResponseWriter, r *http.Request, data any) error {
+		var operation string
+		if tr, ok := transport.FromServerContext(r.Context()); ok {
+			operation = tr.Operation()
+		}
+		if !acceptsGzip(r.Header.Get("Accept-Encoding")) || routeMatchesAny(p.exempt, operation, r.URL.Path) {
+			return encode(w, r, data)
+		}
+		buf := &bufferedResponseWriter{w: w}
+		if err := encode(buf, r, data); err != nil {
+			return err
+		}
+		body := buf.body.Bytes()
+		header := w.Header()
+		if header.Get("Content-Encoding") != "" || buf.status != 0 && buf.status != nhttp.StatusOK ||
+			len(body) < p.minSize {
+			if buf.status != 0 {
+				w.WriteHeader(buf.status)
+			}
+			_, err := w.Write(body)
+			return err
+		}
+
+		compressed, err := h.compressedBody(p, operation, body)
+		if err != nil {
+			return err
+		}
+		header.Set("Content-Encoding", "gzip")
+		header.Set(varyHeader, mergeVary(header.Get(varyHeader), []string{"Accept-Encoding"}))
+		header.Del("Content-Length")
+		if buf.status != 0 {
+			w.WriteHeader(buf.status)
+		}
+		_, err = w.Write(compressed)
+		return err
+	}
+}
+
+// compressedBody returns body gzipped, from the cache when an identical body of operation is cached.
+func (h *ServiceHttp) compressedBody(p *compressionPolicy, operation string, body []byte) ([]byte, error) {
+	if p.cache == nil {
+		return p.compress(body)
+	}
+	key := compressedBodyKey(operation, body)
+	if compressed, ok := p.cache.get(key); ok {
+		h.recordCompressionCache("hit", p.cache)
+		return compressed, nil
+	}
+	compressed, err := p.compress(body)
+	if err != nil {
+		return nil, err
+	}
+	p.cache.put(key, compressed)
+	h.recordCompressionCache("miss", p.cache)
+	return compressed, nil
+}
+
+func (h *ServiceHttp) recordCompressionCache(result string, c *compressedBodyCache) {
+	if h.compressionCacheRequests != nil {
+		h.compressionCacheRequests.WithLabelValues(result).Inc()
+	}
+	if h.compressionCacheBytes != nil {
+		h.compressionCacheBytes.Set(float64(c.size()))
+	}
+}
+
+func compressedBodyKey(operation string, body []byte) [sha256.Size]byte {
+	hash := sha256.New()
+	hash.Write([]byte(operation))
+	hash.Write([]byte{0})
+	hash.Write(body)
+	var key [sha256.Size]byte
+	hash.Sum(key[:0])
+	return key
+}
+
+// compressedBodyCache holds compressed bodies for ttl, dropping the oldest to stay within maxBytes.
+type compressedBodyCache struct {
+	ttl      time.Duration
+	maxBytes int64
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]compressedBody
+	// order lists the puts oldest first; it may hold puts already replaced or dropped, told apart by gen.
+	order []compressedBodyRef
+	bytes int64
+	gen   uint64
+}
+
+type compressedBody struct {
+	data    []byte
+	expires time.Time
+	// gen identifies the put that stored the body.
+	gen uint64
+}
+
+// compressedBodyRef is an entry of the eviction order; it is stale once its key is put again or dropped.
+type compressedBodyRef struct {
+	key [sha256.Size]byte
+	gen uint64
+}
+
+func newCompressedBodyCache(ttl time.Duration, maxBytes int64) *compressedBodyCache {
+	return &compressedBodyCache{
+		ttl:      ttl,
+		maxBytes: maxBytes,
+		now:      time.Now,
+		entries:  make(map[[sha256.Size]byte]compressedBody),
+	}
+}
+
+func (c *compressedBodyCache) get(key [sha256.Size]byte) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok || !c.now().Before(e.expires) {
+		return nil, false
+	}
+	return e.data, true
+}
+
+// put caches data under key; bodies larger than the whole cache are not cached.
+func (c *compressedBodyCache) put(key [sha256.Size]byte, data []byte) {
+	size := int64(len(data))
+	if size > c.maxBytes {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if old, ok := c.entries[key]; ok {
+		c.bytes -= int64(len(old.data))
+		delete(c.entries, key)
+	}
+	now := c.now()
+	// Drop entries oldest first while they are expired or the new body does not fit, skipping stale refs.
+	for len(c.order) > 0 {
+		oldest := c.order[0]
+		e, ok := c.entries[oldest.key]
+		live := ok && e.gen == oldest.gen
+		if live && now.Before(e.expires) && c.bytes+size <= c.maxBytes {
+			break
+		}
+		c.order = c.order[1:]
+		if live {
+			delete(c.entries, oldest.key)
+			c.bytes -= int64(len(e.data))
+		}
+	}
+	c.gen++
+	c.entries[key] = compressedBody{data: data, expires: now.Add(c.ttl), gen: c.gen}
+	c.order = append(c.order, compressedBodyRef{key: key, gen: c.gen})
+	c.bytes += size
+}
+
+// size returns the bytes of the cached bodies.
+func (c *compressedBodyCache) size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.bytes
+}
+
+// validateCompressionConfig checks the gzip level and the cache bounds.
+func validateCompressionConfig(cfg *conf.CompressionConfig) error {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	if l := cfg.GetLevel(); l != 0 && (l < gzip.BestSpeed || l > gzip.BestCompression) {
+		return fmt.Errorf("level %d must be between %d and %d", l, gzip.BestSpeed, gzip.BestCompression)
+	}
+	if cfg.GetMinSize() < 0 {
+		return fmt.Errorf("min_size must not be negative")
+	}
+	if c := cfg.GetCache(); c.GetEnabled() {
+		if c.GetTtl() != nil && c.GetTtl().AsDuration() <= 0 {
+			return fmt.Errorf("cache ttl must be positive")
+		}
+		if c.GetMaxBytes() < 0 {
+			return fmt.Errorf("cache max_bytes must not be negative")
+		}
+	}
+	return nil
+}
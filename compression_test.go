@@ -0,0 +1,134 @@
+package http
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"io"
+	nhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestAcceptsGzip(t *testing.T) {
+	assert.True(t, acceptsGzip("gzip"))
+	assert.True(t, acceptsGzip("br, gzip;q=0.8"))
+	assert.True(t, acceptsGzip("*"))
+	assert.False(t, acceptsGzip(""))
+	assert.False(t, acceptsGzip("br, deflate"))
+	assert.False(t, acceptsGzip("gzip;q=0, br"))
+}
+
+func TestCompression(t *testing.T) {
+	h := &ServiceHttp{
+		compressionCacheRequests: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "compression_cache"}, []string{"result"}),
+		compressionCacheBytes:    prometheus.NewGauge(prometheus.GaugeOpts{Name: "compression_cache_bytes"}),
+	}
+	policy := newCompressionPolicy(&conf.CompressionConfig{
+		Enabled:          true,
+		MinSize:          100,
+		ExemptOperations: []string{"/api.v1.Events/*"},
+		Cache:            &conf.CompressionCacheConfig{Enabled: true},
+	})
+	require.NotNil(t, policy)
+	h.server = http.NewServer(http.ResponseEncoder(h.withCompression(policy, ResponseEncoder)))
+	serve := func(operation string, n int) http.HandlerFunc {
+		return func(ctx http.Context) error {
+			http.SetOperation(ctx, operation)
+			return ctx.Result(nhttp.StatusOK, map[string]string{"items": strings.Repeat("x", n)})
+		}
+	}
+	route := h.server.Route("/")
+	route.GET("/small", serve("/api.v1.Items/Get", 10))
+	route.GET("/large", serve("/api.v1.Items/List", 500))
+	route.GET("/events", serve("/api.v1.Events/Stream", 500))
+
+	do := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(nhttp.MethodGet, path, nil)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		rec := httptest.NewRecorder()
+		h.server.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for range 2 {
+		rec := do("/large", "gzip")
+		require.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", rec.Header().Get(varyHeader))
+		zr, err := gzip.NewReader(rec.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(zr)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"code":200,"data":{"items":"`+strings.Repeat("x", 500)+`"}}`, string(body))
+	}
+	assert.Equal(t, float64(1), testutil.ToFloat64(h.compressionCacheRequests.WithLabelValues("miss")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(h.compressionCacheRequests.WithLabelValues("hit")))
+	assert.Positive(t, testutil.ToFloat64(h.compressionCacheBytes))
+
+	assert.Empty(t, do("/small", "gzip").Header().Get("Content-Encoding"), "below min_size")
+	assert.Empty(t, do("/large", "identity").Header().Get("Content-Encoding"), "gzip not accepted")
+	assert.Empty(t, do("/events", "gzip").Header().Get("Content-Encoding"), "exempt operations")
+}
+
+func TestCompressedBodyCache(t *testing.T) {
+	c := newCompressedBodyCache(time.Minute, 10)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	a, b, d := compressedBodyKey("op", []byte("a")), compressedBodyKey("op", []byte("b")), compressedBodyKey("op", []byte("d"))
+
+	c.put(a, []byte("aaaa"))
+	c.put(b, []byte("bbbb"))
+	c.put(b, []byte("bbbb"))
+	assert.Equal(t, int64(8), c.size(), "replacing an entry does not count it twice")
+	c.put(d, []byte("dddd"))
+	_, ok := c.get(a)
+	assert.False(t, ok, "the oldest entry makes room")
+	_, ok = c.get(d)
+	assert.True(t, ok)
+	assert.Equal(t, int64(8), c.size())
+
+	c.put(compressedBodyKey("op", []byte("big")), make([]byte, 11))
+	assert.Equal(t, int64(8), c.size(), "bodies larger than the cache are not cached")
+
+	now = now.Add(2 * time.Minute)
+	_, ok = c.get(d)
+	assert.False(t, ok, "expired")
+	assert.NotEqual(t, a, compressedBodyKey("other", []byte("a")), "keys include the operation")
+}
+
+func TestCompressedBodyCache_ReplacedEntryKeepsItsPlace(t *testing.T) {
+	c := newCompressedBodyCache(time.Minute, 12)
+	key := func(s string) [sha256.Size]byte { return compressedBodyKey("op", []byte(s)) }
+
+	c.put(key("x"), []byte("xxxx"))
+	c.put(key("a"), []byte("aaaa"))
+	c.put(key("b"), []byte("bbbb"))
+	c.put(key("a"), []byte("AAAA"))
+	c.put(key("d"), []byte("dddd"))
+	c.put(key("e"), []byte("eeee"))
+	_, ok := c.get(key("b"))
+	assert.False(t, ok, "b is the oldest entry once a is replaced")
+	data, ok := c.get(key("a"))
+	assert.True(t, ok, "the replaced entry is evicted by its new position, not its first one")
+	assert.Equal(t, "AAAA", string(data))
+	assert.Equal(t, int64(12), c.size())
+}
+
+func TestValidateCompressionConfig(t *testing.T) {
+	assert.Nil(t, newCompressionPolicy(&conf.CompressionConfig{MinSize: 10}))
+	require.NoError(t, validateCompressionConfig(nil))
+	require.NoError(t, validateCompressionConfig(&conf.CompressionConfig{Enabled: true, Level: 9}))
+	assert.ErrorContains(t, validateCompressionConfig(&conf.CompressionConfig{Enabled: true, Level: 10}), "must be between")
+	assert.ErrorContains(t, validateCompressionConfig(&conf.CompressionConfig{Enabled: true, MinSize: -1}), "min_size")
+	assert.ErrorContains(t, validateCompressionConfig(&conf.CompressionConfig{Enabled: true,
+		Cache: &conf.CompressionCacheConfig{Enabled: true, Ttl: durationpb.New(0)}}), "ttl must be positive")
+}
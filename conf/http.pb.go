@@ -63,8 +63,75 @@ type Http struct {
 	// Circuit breaker configuration
 	// Default: circuit breaker enabled with default settings
 	CircuitBreaker *CircuitBreakerConfig `protobuf:"bytes,11,opt,name=circuit_breaker,json=circuitBreaker,proto3" json:"circuit_breaker,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// Systemd integration (socket activation and sd_notify)
+	// Default: disabled
+	Systemd *SystemdConfig `protobuf:"bytes,12,opt,name=systemd,proto3" json:"systemd,omitempty"`
+	// PROXY protocol (v1/v2) support on the listener
+	// Default: disabled
+	ProxyProtocol *ProxyProtocolConfig `protobuf:"bytes,13,opt,name=proxy_protocol,json=proxyProtocol,proto3" json:"proxy_protocol,omitempty"`
+	// Response encoding options
+	// Default: standard envelope only
+	Response *ResponseConfig `protobuf:"bytes,14,opt,name=response,proto3" json:"response,omitempty"`
+	// GraphQL endpoint options; the endpoint is mounted when the application sets ServiceHttp.GraphQL
+	// Default: POST /graphql
+	Graphql *GraphQLConfig `protobuf:"bytes,15,opt,name=graphql,proto3" json:"graphql,omitempty"`
+	// JSON-RPC 2.0 endpoint options
+	// Default: disabled
+	Jsonrpc *JSONRPCConfig `protobuf:"bytes,16,opt,name=jsonrpc,proto3" json:"jsonrpc,omitempty"`
+	// Batch endpoint options
+	// Default: disabled
+	Batch *BatchConfig `protobuf:"bytes,17,opt,name=batch,proto3" json:"batch,omitempty"`
+	// Request decoding options
+	// Default: plain Kratos decoding
+	Request *RequestConfig `protobuf:"bytes,18,opt,name=request,proto3" json:"request,omitempty"`
+	// Client platform and app version detection from User-Agent and app headers
+	// Default: disabled
+	ClientInfo *ClientInfoConfig `protobuf:"bytes,19,opt,name=client_info,json=clientInfo,proto3" json:"client_info,omitempty"`
+	// Signature checks for time-limited signed URLs (download links, callbacks)
+	// Default: disabled
+	SignedUrls *SignedURLConfig `protobuf:"bytes,20,opt,name=signed_urls,json=signedUrls,proto3" json:"signed_urls,omitempty"`
+	// Cookie sessions for server-rendered and backend-for-frontend services
+	// Default: disabled
+	Session *SessionConfig `protobuf:"bytes,21,opt,name=session,proto3" json:"session,omitempty"`
+	// Automatic HEAD and OPTIONS responses derived from the route table
+	// Default: both enabled
+	Routing *RoutingConfig `protobuf:"bytes,22,opt,name=routing,proto3" json:"routing,omitempty"`
+	// Response headers and health answers that tell load balancers to stop routing new traffic here
+	// Default: disabled
+	LoadBalancerHints *LoadBalancerHintsConfig `protobuf:"bytes,23,opt,name=load_balancer_hints,json=loadBalancerHints,proto3" json:"load_balancer_hints,omitempty"`
+	// Environment preset the rest of this configuration is applied on top of: "dev", "staging" or "prod".
+	// Settings given here override the preset's, explicit false and 0 included
+	// Default: "" (no preset)
+	Profile string `protobuf:"bytes,24,opt,name=profile,proto3" json:"profile,omitempty"`
+	// Kubernetes probe endpoints, preStop drain hook and Downward API pod labels
+	// Default: disabled
+	Kubernetes *KubernetesConfig `protobuf:"bytes,25,opt,name=kubernetes,proto3" json:"kubernetes,omitempty"`
+	// Virtual hosts serving their own route groups, middleware and TLS certificates by Host header
+	// Default: none (every host serves every route)
+	VirtualHosts *VirtualHostsConfig `protobuf:"bytes,26,opt,name=virtual_hosts,json=virtualHosts,proto3" json:"virtual_hosts,omitempty"`
+	// Tenant extraction from subdomains or path prefixes into the request context and baggage
+	// Default: disabled
+	Tenant *TenantConfig `protobuf:"bytes,27,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	// Serving region tagging and tenant data residency enforcement
+	// Default: disabled
+	Residency *ResidencyConfig `protobuf:"bytes,28,opt,name=residency,proto3" json:"residency,omitempty"`
+	// HTTP equivalents of the gRPC health check and a listing of the proto services served
+	// Default: disabled
+	GrpcParity *GrpcParityConfig `protobuf:"bytes,29,opt,name=grpc_parity,json=grpcParity,proto3" json:"grpc_parity,omitempty"`
+	// "Accept now, process later" task submission and status endpoints
+	// Default: disabled
+	AsyncTasks *AsyncTasksConfig `protobuf:"bytes,30,opt,name=async_tasks,json=asyncTasks,proto3" json:"async_tasks,omitempty"`
+	// google.longrunning-style operations resource for long-running handlers
+	// Default: disabled
+	Operations *OperationsConfig `protobuf:"bytes,31,opt,name=operations,proto3" json:"operations,omitempty"`
+	// Admin endpoint exporting the routes as a Postman collection or HTTPie script with example requests
+	// Default: disabled
+	CollectionExport *CollectionExportConfig `protobuf:"bytes,32,opt,name=collection_export,json=collectionExport,proto3" json:"collection_export,omitempty"`
+	// Example responses for operations whose handlers are not implemented yet
+	// Default: disabled
+	Mock          *MockConfig `protobuf:"bytes,33,opt,name=mock,proto3" json:"mock,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Http) Reset() {
@@ -174,253 +241,197 @@ func (x *Http) GetCircuitBreaker() *CircuitBreakerConfig {
 	return nil
 }
 
-// Monitoring configuration
-type MonitoringConfig struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Whether to enable Prometheus metrics
-	// Default: true
-	EnableMetrics bool `protobuf:"varint,1,opt,name=enable_metrics,json=enableMetrics,proto3" json:"enable_metrics,omitempty"`
-	// Metrics endpoint path
-	// Default: "/metrics"
-	MetricsPath string `protobuf:"bytes,2,opt,name=metrics_path,json=metricsPath,proto3" json:"metrics_path,omitempty"`
-	// Health check endpoint path
-	// Default: "/health"
-	HealthPath string `protobuf:"bytes,3,opt,name=health_path,json=healthPath,proto3" json:"health_path,omitempty"`
-	// Whether to enable request logging
-	// Default: true
-	EnableRequestLogging bool `protobuf:"varint,4,opt,name=enable_request_logging,json=enableRequestLogging,proto3" json:"enable_request_logging,omitempty"`
-	// Whether to enable error logging
-	// Default: true
-	EnableErrorLogging bool `protobuf:"varint,5,opt,name=enable_error_logging,json=enableErrorLogging,proto3" json:"enable_error_logging,omitempty"`
-	// Whether to enable detailed route metrics
-	// Default: true
-	EnableRouteMetrics bool `protobuf:"varint,6,opt,name=enable_route_metrics,json=enableRouteMetrics,proto3" json:"enable_route_metrics,omitempty"`
-	// Whether to enable connection metrics
-	// Default: true
-	EnableConnectionMetrics bool `protobuf:"varint,7,opt,name=enable_connection_metrics,json=enableConnectionMetrics,proto3" json:"enable_connection_metrics,omitempty"`
-	// Whether to enable queue metrics
-	// Default: true
-	EnableQueueMetrics bool `protobuf:"varint,8,opt,name=enable_queue_metrics,json=enableQueueMetrics,proto3" json:"enable_queue_metrics,omitempty"`
-	// Whether to enable error type metrics
-	// Default: true
-	EnableErrorTypeMetrics bool `protobuf:"varint,9,opt,name=enable_error_type_metrics,json=enableErrorTypeMetrics,proto3" json:"enable_error_type_metrics,omitempty"`
-	unknownFields          protoimpl.UnknownFields
-	sizeCache              protoimpl.SizeCache
-}
-
-func (x *MonitoringConfig) Reset() {
-	*x = MonitoringConfig{}
-	mi := &file_http_proto_msgTypes[1]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *Http) GetSystemd() *SystemdConfig {
+	if x != nil {
+		return x.Systemd
+	}
+	return nil
 }
 
-func (x *MonitoringConfig) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *Http) GetProxyProtocol() *ProxyProtocolConfig {
+	if x != nil {
+		return x.ProxyProtocol
+	}
+	return nil
 }
 
-func (*MonitoringConfig) ProtoMessage() {}
-
-func (x *MonitoringConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_http_proto_msgTypes[1]
+func (x *Http) GetResponse() *ResponseConfig {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Response
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use MonitoringConfig.ProtoReflect.Descriptor instead.
-func (*MonitoringConfig) Descriptor() ([]byte, []int) {
-	return file_http_proto_rawDescGZIP(), []int{1}
+func (x *Http) GetGraphql() *GraphQLConfig {
+	if x != nil {
+		return x.Graphql
+	}
+	return nil
 }
 
-func (x *MonitoringConfig) GetEnableMetrics() bool {
+func (x *Http) GetJsonrpc() *JSONRPCConfig {
 	if x != nil {
-		return x.EnableMetrics
+		return x.Jsonrpc
 	}
-	return false
+	return nil
 }
 
-func (x *MonitoringConfig) GetMetricsPath() string {
+func (x *Http) GetBatch() *BatchConfig {
 	if x != nil {
-		return x.MetricsPath
+		return x.Batch
 	}
-	return ""
+	return nil
 }
 
-func (x *MonitoringConfig) GetHealthPath() string {
+func (x *Http) GetRequest() *RequestConfig {
 	if x != nil {
-		return x.HealthPath
+		return x.Request
 	}
-	return ""
+	return nil
 }
 
-func (x *MonitoringConfig) GetEnableRequestLogging() bool {
+func (x *Http) GetClientInfo() *ClientInfoConfig {
 	if x != nil {
-		return x.EnableRequestLogging
+		return x.ClientInfo
 	}
-	return false
+	return nil
 }
 
-func (x *MonitoringConfig) GetEnableErrorLogging() bool {
+func (x *Http) GetSignedUrls() *SignedURLConfig {
 	if x != nil {
-		return x.EnableErrorLogging
+		return x.SignedUrls
 	}
-	return false
+	return nil
 }
 
-func (x *MonitoringConfig) GetEnableRouteMetrics() bool {
+func (x *Http) GetSession() *SessionConfig {
 	if x != nil {
-		return x.EnableRouteMetrics
+		return x.Session
 	}
-	return false
+	return nil
 }
 
-func (x *MonitoringConfig) GetEnableConnectionMetrics() bool {
+func (x *Http) GetRouting() *RoutingConfig {
 	if x != nil {
-		return x.EnableConnectionMetrics
+		return x.Routing
 	}
-	return false
+	return nil
 }
 
-func (x *MonitoringConfig) GetEnableQueueMetrics() bool {
+func (x *Http) GetLoadBalancerHints() *LoadBalancerHintsConfig {
 	if x != nil {
-		return x.EnableQueueMetrics
+		return x.LoadBalancerHints
 	}
-	return false
+	return nil
 }
 
-func (x *MonitoringConfig) GetEnableErrorTypeMetrics() bool {
+func (x *Http) GetProfile() string {
 	if x != nil {
-		return x.EnableErrorTypeMetrics
+		return x.Profile
 	}
-	return false
+	return ""
 }
 
-// Security configuration
-type SecurityConfig struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// CORS configuration
-	// Default: CORS disabled
-	Cors *CorsConfig `protobuf:"bytes,1,opt,name=cors,proto3" json:"cors,omitempty"`
-	// Request size limit in bytes
-	// Default: 10MB (10485760 bytes)
-	MaxRequestSize int64 `protobuf:"varint,2,opt,name=max_request_size,json=maxRequestSize,proto3" json:"max_request_size,omitempty"`
-	// Rate limiting configuration
-	// Default: 100 req/s, burst 200
-	RateLimit *RateLimitConfig `protobuf:"bytes,3,opt,name=rate_limit,json=rateLimit,proto3" json:"rate_limit,omitempty"`
-	// Security headers configuration
-	// Default: security headers disabled
-	SecurityHeaders *SecurityHeadersConfig `protobuf:"bytes,4,opt,name=security_headers,json=securityHeaders,proto3" json:"security_headers,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+func (x *Http) GetKubernetes() *KubernetesConfig {
+	if x != nil {
+		return x.Kubernetes
+	}
+	return nil
 }
 
-func (x *SecurityConfig) Reset() {
-	*x = SecurityConfig{}
-	mi := &file_http_proto_msgTypes[2]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *Http) GetVirtualHosts() *VirtualHostsConfig {
+	if x != nil {
+		return x.VirtualHosts
+	}
+	return nil
 }
 
-func (x *SecurityConfig) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *Http) GetTenant() *TenantConfig {
+	if x != nil {
+		return x.Tenant
+	}
+	return nil
 }
 
-func (*SecurityConfig) ProtoMessage() {}
-
-func (x *SecurityConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_http_proto_msgTypes[2]
+func (x *Http) GetResidency() *ResidencyConfig {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Residency
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use SecurityConfig.ProtoReflect.Descriptor instead.
-func (*SecurityConfig) Descriptor() ([]byte, []int) {
-	return file_http_proto_rawDescGZIP(), []int{2}
+func (x *Http) GetGrpcParity() *GrpcParityConfig {
+	if x != nil {
+		return x.GrpcParity
+	}
+	return nil
 }
 
-func (x *SecurityConfig) GetCors() *CorsConfig {
+func (x *Http) GetAsyncTasks() *AsyncTasksConfig {
 	if x != nil {
-		return x.Cors
+		return x.AsyncTasks
 	}
 	return nil
 }
 
-func (x *SecurityConfig) GetMaxRequestSize() int64 {
+func (x *Http) GetOperations() *OperationsConfig {
 	if x != nil {
-		return x.MaxRequestSize
+		return x.Operations
 	}
-	return 0
+	return nil
 }
 
-func (x *SecurityConfig) GetRateLimit() *RateLimitConfig {
+func (x *Http) GetCollectionExport() *CollectionExportConfig {
 	if x != nil {
-		return x.RateLimit
+		return x.CollectionExport
 	}
 	return nil
 }
 
-func (x *SecurityConfig) GetSecurityHeaders() *SecurityHeadersConfig {
+func (x *Http) GetMock() *MockConfig {
 	if x != nil {
-		return x.SecurityHeaders
+		return x.Mock
 	}
 	return nil
 }
 
-// CORS configuration
-type CorsConfig struct {
+// MockConfig answers requests to mocked operations with a generated example reply in the standard envelope,
+// without running their handler. Examples are built from the output message of the proto method: strings hold
+// their field name, numbers zero, enums their first value after zero and repeated fields one element. Replies
+// carry the X-Mock header.
+type MockConfig struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// Whether to enable CORS
+	// Whether to mock operations
 	// Default: false
 	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
-	// Allowed origins
-	// Default: ["*"] (when enabled)
-	AllowedOrigins []string `protobuf:"bytes,2,rep,name=allowed_origins,json=allowedOrigins,proto3" json:"allowed_origins,omitempty"`
-	// Allowed methods
-	// Default: ["GET", "POST", "PUT", "DELETE", "OPTIONS"]
-	AllowedMethods []string `protobuf:"bytes,3,rep,name=allowed_methods,json=allowedMethods,proto3" json:"allowed_methods,omitempty"`
-	// Allowed headers
-	// Default: ["*"]
-	AllowedHeaders []string `protobuf:"bytes,4,rep,name=allowed_headers,json=allowedHeaders,proto3" json:"allowed_headers,omitempty"`
-	// Exposed headers
-	// Default: []
-	ExposedHeaders []string `protobuf:"bytes,5,rep,name=exposed_headers,json=exposedHeaders,proto3" json:"exposed_headers,omitempty"`
-	// Whether to allow credentials
+	// Operations or paths to mock, wildcards allowed
+	// Default: empty (only those of responses)
+	Operations []string `protobuf:"bytes,2,rep,name=operations,proto3" json:"operations,omitempty"`
+	// Also mock operations whose handler returns 501 Not Implemented, such as the generated Unimplemented servers
 	// Default: false
-	AllowCredentials bool `protobuf:"varint,6,opt,name=allow_credentials,json=allowCredentials,proto3" json:"allow_credentials,omitempty"`
-	// Max age for preflight requests in seconds
-	// Default: 86400 (24 hours)
-	MaxAge        int32 `protobuf:"varint,7,opt,name=max_age,json=maxAge,proto3" json:"max_age,omitempty"`
+	Unimplemented bool `protobuf:"varint,3,opt,name=unimplemented,proto3" json:"unimplemented,omitempty"`
+	// Replies replacing parts of the generated examples; an operation listed here is mocked
+	// Default: empty
+	Responses     []*MockResponse `protobuf:"bytes,4,rep,name=responses,proto3" json:"responses,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CorsConfig) Reset() {
-	*x = CorsConfig{}
-	mi := &file_http_proto_msgTypes[3]
+func (x *MockConfig) Reset() {
+	*x = MockConfig{}
+	mi := &file_http_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CorsConfig) String() string {
+func (x *MockConfig) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CorsConfig) ProtoMessage() {}
+func (*MockConfig) ProtoMessage() {}
 
-func (x *CorsConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_http_proto_msgTypes[3]
+func (x *MockConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -431,91 +442,76 @@ func (x *CorsConfig) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CorsConfig.ProtoReflect.Descriptor instead.
-func (*CorsConfig) Descriptor() ([]byte, []int) {
-	return file_http_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use MockConfig.ProtoReflect.Descriptor instead.
+func (*MockConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *CorsConfig) GetEnabled() bool {
+func (x *MockConfig) GetEnabled() bool {
 	if x != nil {
 		return x.Enabled
 	}
 	return false
 }
 
-func (x *CorsConfig) GetAllowedOrigins() []string {
-	if x != nil {
-		return x.AllowedOrigins
-	}
-	return nil
-}
-
-func (x *CorsConfig) GetAllowedMethods() []string {
-	if x != nil {
-		return x.AllowedMethods
-	}
-	return nil
-}
-
-func (x *CorsConfig) GetAllowedHeaders() []string {
-	if x != nil {
-		return x.AllowedHeaders
-	}
-	return nil
-}
-
-func (x *CorsConfig) GetExposedHeaders() []string {
+func (x *MockConfig) GetOperations() []string {
 	if x != nil {
-		return x.ExposedHeaders
+		return x.Operations
 	}
 	return nil
 }
 
-func (x *CorsConfig) GetAllowCredentials() bool {
+func (x *MockConfig) GetUnimplemented() bool {
 	if x != nil {
-		return x.AllowCredentials
+		return x.Unimplemented
 	}
 	return false
 }
 
-func (x *CorsConfig) GetMaxAge() int32 {
+func (x *MockConfig) GetResponses() []*MockResponse {
 	if x != nil {
-		return x.MaxAge
+		return x.Responses
 	}
-	return 0
+	return nil
 }
 
-// Rate limiting configuration
-type RateLimitConfig struct {
+// MockResponse overrides the mocked reply of the operations it matches. The first matching entry applies.
+type MockResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// Whether to enable rate limiting
-	// Default: true
-	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
-	// Rate limit per second
-	// Default: 100
-	RatePerSecond int32 `protobuf:"varint,2,opt,name=rate_per_second,json=ratePerSecond,proto3" json:"rate_per_second,omitempty"`
-	// Burst limit
-	// Default: 200
-	BurstLimit    int32 `protobuf:"varint,3,opt,name=burst_limit,json=burstLimit,proto3" json:"burst_limit,omitempty"`
+	// Operation or path, wildcards allowed
+	Operation string `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	// JSON object whose fields replace those of the generated example; null clears a field. Operations without a
+	// proto method reply with data as is
+	// Default: "" (the generated example)
+	Data string `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	// When 400 or above, the operation fails with this status, reason and message instead
+	// Default: 0
+	Status int32 `protobuf:"varint,3,opt,name=status,proto3" json:"status,omitempty"`
+	// Error reason used with status
+	// Default: ""
+	Reason string `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Error message used with status
+	// Default: ""
+	Message       string `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RateLimitConfig) Reset() {
-	*x = RateLimitConfig{}
-	mi := &file_http_proto_msgTypes[4]
+func (x *MockResponse) Reset() {
+	*x = MockResponse{}
+	mi := &file_http_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RateLimitConfig) String() string {
+func (x *MockResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RateLimitConfig) ProtoMessage() {}
+func (*MockResponse) ProtoMessage() {}
 
-func (x *RateLimitConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_http_proto_msgTypes[4]
+func (x *MockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -526,68 +522,262 @@ func (x *RateLimitConfig) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RateLimitConfig.ProtoReflect.Descriptor instead.
-func (*RateLimitConfig) Descriptor() ([]byte, []int) {
-	return file_http_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use MockResponse.ProtoReflect.Descriptor instead.
+func (*MockResponse) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *RateLimitConfig) GetEnabled() bool {
+func (x *MockResponse) GetOperation() string {
 	if x != nil {
-		return x.Enabled
+		return x.Operation
 	}
-	return false
+	return ""
 }
 
-func (x *RateLimitConfig) GetRatePerSecond() int32 {
+func (x *MockResponse) GetData() string {
 	if x != nil {
-		return x.RatePerSecond
+		return x.Data
+	}
+	return ""
+}
+
+func (x *MockResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
 	}
 	return 0
 }
 
-func (x *RateLimitConfig) GetBurstLimit() int32 {
+func (x *MockResponse) GetReason() string {
 	if x != nil {
-		return x.BurstLimit
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *MockResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// CollectionExportConfig serves the route table as a Postman collection (v2.1), or with ?format=httpie as a shell
+// script of HTTPie commands. Routes bound by proto services get example requests built from the request message:
+// path variables, optional query parameters and a JSON body with the default or an example value of each field.
+// Other routes are listed with their method and path.
+type CollectionExportConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to serve the export endpoint
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Endpoint path
+	// Default: "/debug/collection"
+	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// Collection name
+	// Default: "lynx-http"
+	Name string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	// Initial value of the baseUrl variable the requests are relative to
+	// Default: "http://localhost" followed by the port of addr
+	BaseUrl       string `protobuf:"bytes,4,opt,name=base_url,json=baseUrl,proto3" json:"base_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CollectionExportConfig) Reset() {
+	*x = CollectionExportConfig{}
+	mi := &file_http_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectionExportConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectionExportConfig) ProtoMessage() {}
+
+func (x *CollectionExportConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectionExportConfig.ProtoReflect.Descriptor instead.
+func (*CollectionExportConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CollectionExportConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *CollectionExportConfig) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *CollectionExportConfig) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CollectionExportConfig) GetBaseUrl() string {
+	if x != nil {
+		return x.BaseUrl
+	}
+	return ""
+}
+
+// OperationsConfig mounts GET <path>/<id>, which reports operations created with CreateOperation in the
+// google.longrunning shape: name, done, metadata and, once done, result or error. The route runs through the server
+// middleware chain with the operation "<path>/get". Operations are kept in the configured store: "memory" is built
+// in, others are registered with RegisterOperationStore.
+type OperationsConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to mount the operations route
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Path prefix of the operations route
+	// Default: "/operations"
+	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// Name of the operation store
+	// Default: "memory"
+	Store string `protobuf:"bytes,3,opt,name=store,proto3" json:"store,omitempty"`
+	// How long the memory store keeps finished operations
+	// Default: 24h
+	Ttl *durationpb.Duration `protobuf:"bytes,4,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	// Maximum number of operations in the memory store; creating more fails with 503 OPERATION_STORE_FULL
+	// Default: 10000
+	MaxOperations int32 `protobuf:"varint,5,opt,name=max_operations,json=maxOperations,proto3" json:"max_operations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OperationsConfig) Reset() {
+	*x = OperationsConfig{}
+	mi := &file_http_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OperationsConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OperationsConfig) ProtoMessage() {}
+
+func (x *OperationsConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OperationsConfig.ProtoReflect.Descriptor instead.
+func (*OperationsConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *OperationsConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *OperationsConfig) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *OperationsConfig) GetStore() string {
+	if x != nil {
+		return x.Store
+	}
+	return ""
+}
+
+func (x *OperationsConfig) GetTtl() *durationpb.Duration {
+	if x != nil {
+		return x.Ttl
+	}
+	return nil
+}
+
+func (x *OperationsConfig) GetMaxOperations() int32 {
+	if x != nil {
+		return x.MaxOperations
 	}
 	return 0
 }
 
-// Security headers configuration
-type SecurityHeadersConfig struct {
+// AsyncTasksConfig mounts endpoints for tasks registered with RegisterAsyncTask. POST <path>/<kind> validates the
+// JSON body, persists it to the task queue and answers at once with code 202 and the task ID; GET <path>/<id>
+// reports the task's status and, once done, its result or error. Both run through the server middleware chain, with
+// the operations "<path>/<kind>" and "<path>/status". The built-in "memory" queue processes tasks in-process;
+// queues registered with RegisterTaskQueue persist them for consumers that call ProcessTask, such as an outbox relay.
+type AsyncTasksConfig struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// Whether to enable security headers
+	// Whether to mount the endpoints
 	// Default: false
 	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
-	// Content Security Policy
-	// Default: "default-src 'self'"
-	ContentSecurityPolicy string `protobuf:"bytes,2,opt,name=content_security_policy,json=contentSecurityPolicy,proto3" json:"content_security_policy,omitempty"`
-	// X-Frame-Options
-	// Default: "DENY"
-	XFrameOptions string `protobuf:"bytes,3,opt,name=x_frame_options,json=xFrameOptions,proto3" json:"x_frame_options,omitempty"`
-	// X-Content-Type-Options
-	// Default: "nosniff"
-	XContentTypeOptions string `protobuf:"bytes,4,opt,name=x_content_type_options,json=xContentTypeOptions,proto3" json:"x_content_type_options,omitempty"`
-	// X-XSS-Protection
-	// Default: "1; mode=block"
-	XXssProtection string `protobuf:"bytes,5,opt,name=x_xss_protection,json=xXssProtection,proto3" json:"x_xss_protection,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// Path prefix of the endpoints
+	// Default: "/tasks"
+	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// Name of the task queue; "memory" is built in, others are registered with RegisterTaskQueue
+	// Default: "memory"
+	Queue string `protobuf:"bytes,3,opt,name=queue,proto3" json:"queue,omitempty"`
+	// Tasks the memory queue processes concurrently
+	// Default: 4
+	Workers int32 `protobuf:"varint,4,opt,name=workers,proto3" json:"workers,omitempty"`
+	// Unfinished tasks the memory queue holds; further submissions are rejected with 503 TASK_QUEUE_FULL
+	// Default: 1000
+	MaxPending int32 `protobuf:"varint,5,opt,name=max_pending,json=maxPending,proto3" json:"max_pending,omitempty"`
+	// How long the memory queue keeps finished tasks
+	// Default: 1h
+	ResultTtl *durationpb.Duration `protobuf:"bytes,6,opt,name=result_ttl,json=resultTtl,proto3" json:"result_ttl,omitempty"`
+	// Maximum submission body size in bytes
+	// Default: 1MB
+	MaxBodyBytes  int64 `protobuf:"varint,7,opt,name=max_body_bytes,json=maxBodyBytes,proto3" json:"max_body_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SecurityHeadersConfig) Reset() {
-	*x = SecurityHeadersConfig{}
+func (x *AsyncTasksConfig) Reset() {
+	*x = AsyncTasksConfig{}
 	mi := &file_http_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SecurityHeadersConfig) String() string {
+func (x *AsyncTasksConfig) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SecurityHeadersConfig) ProtoMessage() {}
+func (*AsyncTasksConfig) ProtoMessage() {}
 
-func (x *SecurityHeadersConfig) ProtoReflect() protoreflect.Message {
+func (x *AsyncTasksConfig) ProtoReflect() protoreflect.Message {
 	mi := &file_http_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -599,95 +789,7794 @@ func (x *SecurityHeadersConfig) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SecurityHeadersConfig.ProtoReflect.Descriptor instead.
-func (*SecurityHeadersConfig) Descriptor() ([]byte, []int) {
+// Deprecated: Use AsyncTasksConfig.ProtoReflect.Descriptor instead.
+func (*AsyncTasksConfig) Descriptor() ([]byte, []int) {
 	return file_http_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *SecurityHeadersConfig) GetEnabled() bool {
+func (x *AsyncTasksConfig) GetEnabled() bool {
 	if x != nil {
 		return x.Enabled
 	}
 	return false
 }
 
-func (x *SecurityHeadersConfig) GetContentSecurityPolicy() string {
+func (x *AsyncTasksConfig) GetPath() string {
 	if x != nil {
-		return x.ContentSecurityPolicy
+		return x.Path
+	}
+	return ""
+}
+
+func (x *AsyncTasksConfig) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *AsyncTasksConfig) GetWorkers() int32 {
+	if x != nil {
+		return x.Workers
+	}
+	return 0
+}
+
+func (x *AsyncTasksConfig) GetMaxPending() int32 {
+	if x != nil {
+		return x.MaxPending
+	}
+	return 0
+}
+
+func (x *AsyncTasksConfig) GetResultTtl() *durationpb.Duration {
+	if x != nil {
+		return x.ResultTtl
+	}
+	return nil
+}
+
+func (x *AsyncTasksConfig) GetMaxBodyBytes() int64 {
+	if x != nil {
+		return x.MaxBodyBytes
+	}
+	return 0
+}
+
+// GrpcParityConfig mounts HTTP counterparts of the grpc.health.v1 health check and of service reflection, so
+// gateway automation can discover HTTP and gRPC services the same way.
+type GrpcParityConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to mount the endpoints
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Health check path; GET with ?service=<name> or POST with a HealthCheckRequest body
+	// Default: "/grpc.health.v1.Health/Check"
+	HealthPath string `protobuf:"bytes,2,opt,name=health_path,json=healthPath,proto3" json:"health_path,omitempty"`
+	// Path listing the proto services, their methods and HTTP bindings
+	// Default: "/grpc/services"
+	ServicesPath string `protobuf:"bytes,3,opt,name=services_path,json=servicesPath,proto3" json:"services_path,omitempty"`
+	// Fully qualified proto services to list
+	// Default: every registered proto service with an HTTP binding this server routes
+	Services      []string `protobuf:"bytes,4,rep,name=services,proto3" json:"services,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GrpcParityConfig) Reset() {
+	*x = GrpcParityConfig{}
+	mi := &file_http_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GrpcParityConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GrpcParityConfig) ProtoMessage() {}
+
+func (x *GrpcParityConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GrpcParityConfig.ProtoReflect.Descriptor instead.
+func (*GrpcParityConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GrpcParityConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *GrpcParityConfig) GetHealthPath() string {
+	if x != nil {
+		return x.HealthPath
+	}
+	return ""
+}
+
+func (x *GrpcParityConfig) GetServicesPath() string {
+	if x != nil {
+		return x.ServicesPath
 	}
 	return ""
 }
 
-func (x *SecurityHeadersConfig) GetXFrameOptions() string {
+func (x *GrpcParityConfig) GetServices() []string {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
+// ResidencyConfig tags requests with the region serving them and compares it with the home region of the
+// request's tenant. Home regions come from tenant_regions, the tenant's "region" attribute or a registered
+// ResidencyResolver. Requests without a tenant are always served.
+type ResidencyConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to tag and check regions
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Region serving the requests, e.g. "eu-west-1"
+	// Default: the value of region_env
+	Region string `protobuf:"bytes,2,opt,name=region,proto3" json:"region,omitempty"`
+	// Environment variable read when region is empty
+	// Default: "LYNX_REGION"
+	RegionEnv string `protobuf:"bytes,3,opt,name=region_env,json=regionEnv,proto3" json:"region_env,omitempty"`
+	// Reply header carrying the serving region
+	// Default: "X-Served-Region"
+	Header string `protobuf:"bytes,4,opt,name=header,proto3" json:"header,omitempty"`
+	// What to do with requests of tenants homed elsewhere: "off" serves them, "reject" fails them with
+	// WRONG_REGION, "redirect" fails them with REGION_REDIRECT and a Location header on the home region endpoint
+	// Default: "off"
+	Enforcement string `protobuf:"bytes,5,opt,name=enforcement,proto3" json:"enforcement,omitempty"`
+	// Name of a resolver registered with RegisterResidencyResolver, consulted for tenants without a static region
+	// Default: empty
+	Resolver string `protobuf:"bytes,6,opt,name=resolver,proto3" json:"resolver,omitempty"`
+	// Home region by tenant ID
+	// Default: empty
+	TenantRegions map[string]string `protobuf:"bytes,7,rep,name=tenant_regions,json=tenantRegions,proto3" json:"tenant_regions,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Base URL by region, e.g. "eu-west-1": "https://eu.api.example.com"; required per region for redirects
+	// Default: empty
+	RegionEndpoints map[string]string `protobuf:"bytes,8,rep,name=region_endpoints,json=regionEndpoints,proto3" json:"region_endpoints,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Operations or paths served regardless of the tenant's home region; exact names or prefixes ending in "*"
+	// Default: empty
+	ExemptOperations []string `protobuf:"bytes,9,rep,name=exempt_operations,json=exemptOperations,proto3" json:"exempt_operations,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ResidencyConfig) Reset() {
+	*x = ResidencyConfig{}
+	mi := &file_http_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResidencyConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResidencyConfig) ProtoMessage() {}
+
+func (x *ResidencyConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResidencyConfig.ProtoReflect.Descriptor instead.
+func (*ResidencyConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ResidencyConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *ResidencyConfig) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *ResidencyConfig) GetRegionEnv() string {
+	if x != nil {
+		return x.RegionEnv
+	}
+	return ""
+}
+
+func (x *ResidencyConfig) GetHeader() string {
+	if x != nil {
+		return x.Header
+	}
+	return ""
+}
+
+func (x *ResidencyConfig) GetEnforcement() string {
+	if x != nil {
+		return x.Enforcement
+	}
+	return ""
+}
+
+func (x *ResidencyConfig) GetResolver() string {
+	if x != nil {
+		return x.Resolver
+	}
+	return ""
+}
+
+func (x *ResidencyConfig) GetTenantRegions() map[string]string {
+	if x != nil {
+		return x.TenantRegions
+	}
+	return nil
+}
+
+func (x *ResidencyConfig) GetRegionEndpoints() map[string]string {
+	if x != nil {
+		return x.RegionEndpoints
+	}
+	return nil
+}
+
+func (x *ResidencyConfig) GetExemptOperations() []string {
+	if x != nil {
+		return x.ExemptOperations
+	}
+	return nil
+}
+
+// TenantConfig reads a tenant identifier from the Host header or the path, resolves it to a tenant and puts
+// the tenant ID in the request context, where TenantFromContext returns it, and in the OpenTelemetry baggage.
+type TenantConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Enable tenant extraction
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Where the identifier is read: "subdomain" or "path"
+	// Default: "subdomain"
+	Source string `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	// Parent domains of the tenant subdomains; the label left of a matching parent is the identifier, so
+	// "acme.example.com" is "acme" with "example.com". Without them, the first label of hosts with at least
+	// three labels is used
+	// Default: none
+	BaseDomains []string `protobuf:"bytes,3,rep,name=base_domains,json=baseDomains,proto3" json:"base_domains,omitempty"`
+	// Path prefix followed by the identifier, so "/t/acme/orders" is "acme" with "/t/"
+	// Default: "/"
+	PathPrefix string `protobuf:"bytes,4,opt,name=path_prefix,json=pathPrefix,proto3" json:"path_prefix,omitempty"`
+	// Answer requests without an identifier with 400 TENANT_REQUIRED
+	// Default: false (they continue without a tenant)
+	Required bool `protobuf:"varint,5,opt,name=required,proto3" json:"required,omitempty"`
+	// Identifiers mapped to tenant IDs; other identifiers answer 404 TENANT_NOT_FOUND. Ignored with resolver
+	// Default: none (the identifier is the tenant ID)
+	Static map[string]string `protobuf:"bytes,6,rep,name=static,proto3" json:"static,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Name of a resolver registered with RegisterTenantResolver, e.g. a database lookup
+	// Default: "" (static, or the identifier itself)
+	Resolver string `protobuf:"bytes,7,opt,name=resolver,proto3" json:"resolver,omitempty"`
+	// Time resolver results, unknown identifiers included, are cached
+	// Default: 1m
+	CacheTtl *durationpb.Duration `protobuf:"bytes,8,opt,name=cache_ttl,json=cacheTtl,proto3" json:"cache_ttl,omitempty"`
+	// Maximum number of cached resolver results
+	// Default: 10000
+	CacheSize int32 `protobuf:"varint,9,opt,name=cache_size,json=cacheSize,proto3" json:"cache_size,omitempty"`
+	// Baggage member carrying the tenant ID to downstream services
+	// Default: "tenant.id"
+	BaggageKey string `protobuf:"bytes,10,opt,name=baggage_key,json=baggageKey,proto3" json:"baggage_key,omitempty"`
+	// Operations or paths, exact or ending in "*", served without tenant extraction
+	// Default: none
+	ExemptOperations []string `protobuf:"bytes,11,rep,name=exempt_operations,json=exemptOperations,proto3" json:"exempt_operations,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *TenantConfig) Reset() {
+	*x = TenantConfig{}
+	mi := &file_http_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TenantConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TenantConfig) ProtoMessage() {}
+
+func (x *TenantConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TenantConfig.ProtoReflect.Descriptor instead.
+func (*TenantConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TenantConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *TenantConfig) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *TenantConfig) GetBaseDomains() []string {
+	if x != nil {
+		return x.BaseDomains
+	}
+	return nil
+}
+
+func (x *TenantConfig) GetPathPrefix() string {
+	if x != nil {
+		return x.PathPrefix
+	}
+	return ""
+}
+
+func (x *TenantConfig) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+func (x *TenantConfig) GetStatic() map[string]string {
+	if x != nil {
+		return x.Static
+	}
+	return nil
+}
+
+func (x *TenantConfig) GetResolver() string {
+	if x != nil {
+		return x.Resolver
+	}
+	return ""
+}
+
+func (x *TenantConfig) GetCacheTtl() *durationpb.Duration {
+	if x != nil {
+		return x.CacheTtl
+	}
+	return nil
+}
+
+func (x *TenantConfig) GetCacheSize() int32 {
+	if x != nil {
+		return x.CacheSize
+	}
+	return 0
+}
+
+func (x *TenantConfig) GetBaggageKey() string {
+	if x != nil {
+		return x.BaggageKey
+	}
+	return ""
+}
+
+func (x *TenantConfig) GetExemptOperations() []string {
+	if x != nil {
+		return x.ExemptOperations
+	}
+	return nil
+}
+
+// VirtualHostsConfig routes requests by Host header to virtual hosts, e.g. one per tenant domain, within one
+// server. A virtual host serves only its operations, runs the middleware registered for it with
+// UseVirtualHostMiddleware, presents its own certificate to TLS clients asking for its domains (SNI), and is
+// counted under its name in lynx_http_virtual_host_requests_total.
+type VirtualHostsConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Virtual hosts, matched in order; the first whose domains match the Host header serves the request
+	// Default: none
+	Hosts []*VirtualHostConfig `protobuf:"bytes,1,rep,name=hosts,proto3" json:"hosts,omitempty"`
+	// Answer requests whose Host header matches no virtual host with 421 Misdirected Request
+	// Default: false (they are served by every route, without virtual host middleware)
+	RejectUnknownHosts bool `protobuf:"varint,2,opt,name=reject_unknown_hosts,json=rejectUnknownHosts,proto3" json:"reject_unknown_hosts,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *VirtualHostsConfig) Reset() {
+	*x = VirtualHostsConfig{}
+	mi := &file_http_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VirtualHostsConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VirtualHostsConfig) ProtoMessage() {}
+
+func (x *VirtualHostsConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VirtualHostsConfig.ProtoReflect.Descriptor instead.
+func (*VirtualHostsConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *VirtualHostsConfig) GetHosts() []*VirtualHostConfig {
+	if x != nil {
+		return x.Hosts
+	}
+	return nil
+}
+
+func (x *VirtualHostsConfig) GetRejectUnknownHosts() bool {
+	if x != nil {
+		return x.RejectUnknownHosts
+	}
+	return false
+}
+
+// VirtualHostConfig is one virtual host.
+type VirtualHostConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unique name, used as the metrics label and by UseVirtualHostMiddleware
+	// Default: none (required)
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Host names served: exact names such as "api.tenant-a.com", or "*.tenant-a.com" for every subdomain.
+	// Matching ignores case and the port
+	// Default: none (required)
+	Domains []string `protobuf:"bytes,2,rep,name=domains,proto3" json:"domains,omitempty"`
+	// Operations or paths served, exact or ending in "*"; other routes answer 404 on this host
+	// Default: none (every route)
+	Operations []string `protobuf:"bytes,3,rep,name=operations,proto3" json:"operations,omitempty"`
+	// PEM certificate file presented to TLS clients asking for one of the domains; requires tls_enable.
+	// Read at startup
+	// Default: "" (the server certificate)
+	TlsCertFile string `protobuf:"bytes,4,opt,name=tls_cert_file,json=tlsCertFile,proto3" json:"tls_cert_file,omitempty"`
+	// PEM private key file of tls_cert_file
+	// Default: ""
+	TlsKeyFile    string `protobuf:"bytes,5,opt,name=tls_key_file,json=tlsKeyFile,proto3" json:"tls_key_file,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VirtualHostConfig) Reset() {
+	*x = VirtualHostConfig{}
+	mi := &file_http_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VirtualHostConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VirtualHostConfig) ProtoMessage() {}
+
+func (x *VirtualHostConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VirtualHostConfig.ProtoReflect.Descriptor instead.
+func (*VirtualHostConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *VirtualHostConfig) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *VirtualHostConfig) GetDomains() []string {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+func (x *VirtualHostConfig) GetOperations() []string {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+func (x *VirtualHostConfig) GetTlsCertFile() string {
+	if x != nil {
+		return x.TlsCertFile
+	}
+	return ""
+}
+
+func (x *VirtualHostConfig) GetTlsKeyFile() string {
+	if x != nil {
+		return x.TlsKeyFile
+	}
+	return ""
+}
+
+// KubernetesConfig adds conveniences for running in a Kubernetes pod. Pod, namespace and node names are read
+// from environment variables populated by the Downward API, added to request logs and exported as the
+// lynx_http_pod_info metric. Probe endpoints answer in the form the kubelet expects, and the preStop endpoint
+// starts draining so the pod leaves the Service endpoints before it receives SIGTERM.
+type KubernetesConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Mount the probe and preStop endpoints and add the pod labels
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Environment variable holding the pod name (fieldRef metadata.name)
+	// Default: "POD_NAME"
+	PodNameEnv string `protobuf:"bytes,2,opt,name=pod_name_env,json=podNameEnv,proto3" json:"pod_name_env,omitempty"`
+	// Environment variable holding the pod namespace (fieldRef metadata.namespace)
+	// Default: "POD_NAMESPACE"
+	PodNamespaceEnv string `protobuf:"bytes,3,opt,name=pod_namespace_env,json=podNamespaceEnv,proto3" json:"pod_namespace_env,omitempty"`
+	// Environment variable holding the node name (fieldRef spec.nodeName)
+	// Default: "NODE_NAME"
+	NodeNameEnv string `protobuf:"bytes,4,opt,name=node_name_env,json=nodeNameEnv,proto3" json:"node_name_env,omitempty"`
+	// Liveness probe path, answered with 200 while the process serves requests
+	// Default: "/livez"
+	LivenessPath string `protobuf:"bytes,5,opt,name=liveness_path,json=livenessPath,proto3" json:"liveness_path,omitempty"`
+	// Readiness probe path, answered with 503 until startup completes, while draining and when the health
+	// check fails
+	// Default: "/readyz"
+	ReadinessPath string `protobuf:"bytes,6,opt,name=readiness_path,json=readinessPath,proto3" json:"readiness_path,omitempty"`
+	// Startup probe path, answered with 503 until startup completes
+	// Default: "/startupz"
+	StartupPath string `protobuf:"bytes,7,opt,name=startup_path,json=startupPath,proto3" json:"startup_path,omitempty"`
+	// preStop hook path. A request starts draining and is answered once prestop_delay has passed; the drain
+	// delay on shutdown only waits for what is left of load_balancer_hints.drain_delay
+	// Default: "/prestop"
+	PrestopPath string `protobuf:"bytes,8,opt,name=prestop_path,json=prestopPath,proto3" json:"prestop_path,omitempty"`
+	// Time the preStop hook waits before answering, for the endpoint removal to reach kube-proxy and ingress
+	// controllers. Keep it below terminationGracePeriodSeconds
+	// Default: load_balancer_hints.drain_delay, or 5s when that is unset
+	PrestopDelay  *durationpb.Duration `protobuf:"bytes,9,opt,name=prestop_delay,json=prestopDelay,proto3" json:"prestop_delay,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KubernetesConfig) Reset() {
+	*x = KubernetesConfig{}
+	mi := &file_http_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KubernetesConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KubernetesConfig) ProtoMessage() {}
+
+func (x *KubernetesConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KubernetesConfig.ProtoReflect.Descriptor instead.
+func (*KubernetesConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *KubernetesConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *KubernetesConfig) GetPodNameEnv() string {
+	if x != nil {
+		return x.PodNameEnv
+	}
+	return ""
+}
+
+func (x *KubernetesConfig) GetPodNamespaceEnv() string {
+	if x != nil {
+		return x.PodNamespaceEnv
+	}
+	return ""
+}
+
+func (x *KubernetesConfig) GetNodeNameEnv() string {
+	if x != nil {
+		return x.NodeNameEnv
+	}
+	return ""
+}
+
+func (x *KubernetesConfig) GetLivenessPath() string {
+	if x != nil {
+		return x.LivenessPath
+	}
+	return ""
+}
+
+func (x *KubernetesConfig) GetReadinessPath() string {
+	if x != nil {
+		return x.ReadinessPath
+	}
+	return ""
+}
+
+func (x *KubernetesConfig) GetStartupPath() string {
+	if x != nil {
+		return x.StartupPath
+	}
+	return ""
+}
+
+func (x *KubernetesConfig) GetPrestopPath() string {
+	if x != nil {
+		return x.PrestopPath
+	}
+	return ""
+}
+
+func (x *KubernetesConfig) GetPrestopDelay() *durationpb.Duration {
+	if x != nil {
+		return x.PrestopDelay
+	}
+	return nil
+}
+
+// LoadBalancerHintsConfig signals drain and degraded states to L7 load balancers. The server drains during
+// shutdown or after StartDraining; the application marks it degraded with SetDegraded.
+type LoadBalancerHintsConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Add "Connection: close" and "X-Server-Draining: true" to responses while draining, and
+	// "X-Server-Degraded: true" while degraded
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Time to keep serving in the draining state before the server stops, so load balancers see the failing
+	// health check and the headers before connections are refused
+	// Default: 0 (stop immediately)
+	DrainDelay *durationpb.Duration `protobuf:"bytes,2,opt,name=drain_delay,json=drainDelay,proto3" json:"drain_delay,omitempty"`
+	// Answer the health endpoint with 503 while draining
+	// Default: false
+	FailHealthWhenDraining bool `protobuf:"varint,3,opt,name=fail_health_when_draining,json=failHealthWhenDraining,proto3" json:"fail_health_when_draining,omitempty"`
+	// Answer the health endpoint with 503 while degraded
+	// Default: false
+	FailHealthWhenDegraded bool `protobuf:"varint,4,opt,name=fail_health_when_degraded,json=failHealthWhenDegraded,proto3" json:"fail_health_when_degraded,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *LoadBalancerHintsConfig) Reset() {
+	*x = LoadBalancerHintsConfig{}
+	mi := &file_http_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoadBalancerHintsConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadBalancerHintsConfig) ProtoMessage() {}
+
+func (x *LoadBalancerHintsConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadBalancerHintsConfig.ProtoReflect.Descriptor instead.
+func (*LoadBalancerHintsConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *LoadBalancerHintsConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *LoadBalancerHintsConfig) GetDrainDelay() *durationpb.Duration {
+	if x != nil {
+		return x.DrainDelay
+	}
+	return nil
+}
+
+func (x *LoadBalancerHintsConfig) GetFailHealthWhenDraining() bool {
+	if x != nil {
+		return x.FailHealthWhenDraining
+	}
+	return false
+}
+
+func (x *LoadBalancerHintsConfig) GetFailHealthWhenDegraded() bool {
+	if x != nil {
+		return x.FailHealthWhenDegraded
+	}
+	return false
+}
+
+// RoutingConfig controls responses the plugin derives from the registered routes. HEAD requests to GET routes
+// run the GET handler and send its headers without the body. OPTIONS requests to routes without an OPTIONS
+// handler are answered with 204 and an Allow header. 405 responses always carry the Allow header.
+type RoutingConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Stop answering HEAD requests from GET routes
+	// Default: false
+	DisableAutoHead bool `protobuf:"varint,1,opt,name=disable_auto_head,json=disableAutoHead,proto3" json:"disable_auto_head,omitempty"`
+	// Stop answering OPTIONS requests from the route table
+	// Default: false
+	DisableAutoOptions bool `protobuf:"varint,2,opt,name=disable_auto_options,json=disableAutoOptions,proto3" json:"disable_auto_options,omitempty"`
+	// Response for paths without a route
+	// Default: {"code":404} with HTTP 404
+	NotFound *FallbackResponse `protobuf:"bytes,3,opt,name=not_found,json=notFound,proto3" json:"not_found,omitempty"`
+	// Response for routes without a handler for the method
+	// Default: {"code":405} with HTTP 405
+	MethodNotAllowed *FallbackResponse `protobuf:"bytes,4,opt,name=method_not_allowed,json=methodNotAllowed,proto3" json:"method_not_allowed,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RoutingConfig) Reset() {
+	*x = RoutingConfig{}
+	mi := &file_http_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoutingConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoutingConfig) ProtoMessage() {}
+
+func (x *RoutingConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoutingConfig.ProtoReflect.Descriptor instead.
+func (*RoutingConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RoutingConfig) GetDisableAutoHead() bool {
+	if x != nil {
+		return x.DisableAutoHead
+	}
+	return false
+}
+
+func (x *RoutingConfig) GetDisableAutoOptions() bool {
+	if x != nil {
+		return x.DisableAutoOptions
+	}
+	return false
+}
+
+func (x *RoutingConfig) GetNotFound() *FallbackResponse {
+	if x != nil {
+		return x.NotFound
+	}
+	return nil
+}
+
+func (x *RoutingConfig) GetMethodNotAllowed() *FallbackResponse {
+	if x != nil {
+		return x.MethodNotAllowed
+	}
+	return nil
+}
+
+// FallbackResponse replaces the built-in 404 or 405 response. redirect takes precedence over file, and file
+// over body. Paths under exclude_prefixes keep the built-in response, e.g. API paths next to an SPA index.
+type FallbackResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// HTTP status
+	// Default: 302 with redirect, 200 with file, otherwise 404 or 405
+	Status int32 `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	// Response body
+	// Default: the built-in JSON body
+	Body string `protobuf:"bytes,2,opt,name=body,proto3" json:"body,omitempty"`
+	// Content type of body
+	// Default: "application/json"
+	ContentType string `protobuf:"bytes,3,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	// Redirect target, e.g. "/" or "https://example.com/missing"
+	// Default: none
+	Redirect string `protobuf:"bytes,4,opt,name=redirect,proto3" json:"redirect,omitempty"`
+	// File served for GET and HEAD requests, e.g. the index.html of a single-page app
+	// Default: none
+	File string `protobuf:"bytes,5,opt,name=file,proto3" json:"file,omitempty"`
+	// Path prefixes that keep the built-in response, e.g. "/api/"
+	// Default: empty
+	ExcludePrefixes []string `protobuf:"bytes,6,rep,name=exclude_prefixes,json=excludePrefixes,proto3" json:"exclude_prefixes,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *FallbackResponse) Reset() {
+	*x = FallbackResponse{}
+	mi := &file_http_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FallbackResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FallbackResponse) ProtoMessage() {}
+
+func (x *FallbackResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FallbackResponse.ProtoReflect.Descriptor instead.
+func (*FallbackResponse) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *FallbackResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *FallbackResponse) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *FallbackResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *FallbackResponse) GetRedirect() string {
+	if x != nil {
+		return x.Redirect
+	}
+	return ""
+}
+
+func (x *FallbackResponse) GetFile() string {
+	if x != nil {
+		return x.File
+	}
+	return ""
+}
+
+func (x *FallbackResponse) GetExcludePrefixes() []string {
+	if x != nil {
+		return x.ExcludePrefixes
+	}
+	return nil
+}
+
+// SessionConfig issues an encrypted session cookie and keeps session data in a session store. Handlers
+// read and modify the session with SessionFromContext.
+type SessionConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to manage sessions
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Cookie encryption key, at least 32 bytes
+	Secret string `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	// Earlier keys still accepted for decryption during key rotation
+	// Default: empty
+	PreviousSecrets []string `protobuf:"bytes,3,rep,name=previous_secrets,json=previousSecrets,proto3" json:"previous_secrets,omitempty"`
+	// Session cookie name
+	// Default: "lynx_session"
+	CookieName string `protobuf:"bytes,4,opt,name=cookie_name,json=cookieName,proto3" json:"cookie_name,omitempty"`
+	// Cookie Domain attribute
+	// Default: empty (host-only cookie)
+	CookieDomain string `protobuf:"bytes,5,opt,name=cookie_domain,json=cookieDomain,proto3" json:"cookie_domain,omitempty"`
+	// Cookie Path attribute
+	// Default: "/"
+	CookiePath string `protobuf:"bytes,6,opt,name=cookie_path,json=cookiePath,proto3" json:"cookie_path,omitempty"`
+	// Issue the cookie without the Secure attribute, for local development over plain HTTP
+	// Default: false
+	AllowInsecureCookie bool `protobuf:"varint,7,opt,name=allow_insecure_cookie,json=allowInsecureCookie,proto3" json:"allow_insecure_cookie,omitempty"`
+	// Cookie SameSite attribute: "lax", "strict" or "none"
+	// Default: "lax"
+	SameSite string `protobuf:"bytes,8,opt,name=same_site,json=sameSite,proto3" json:"same_site,omitempty"`
+	// Sessions expire after this long without a request
+	// Default: 30m
+	IdleTimeout *durationpb.Duration `protobuf:"bytes,9,opt,name=idle_timeout,json=idleTimeout,proto3" json:"idle_timeout,omitempty"`
+	// Sessions expire this long after creation regardless of activity
+	// Default: 24h
+	AbsoluteTimeout *durationpb.Duration `protobuf:"bytes,10,opt,name=absolute_timeout,json=absoluteTimeout,proto3" json:"absolute_timeout,omitempty"`
+	// Session store name: "memory" or a store registered with RegisterSessionStore
+	// Default: "memory"
+	Store         string `protobuf:"bytes,11,opt,name=store,proto3" json:"store,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionConfig) Reset() {
+	*x = SessionConfig{}
+	mi := &file_http_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionConfig) ProtoMessage() {}
+
+func (x *SessionConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionConfig.ProtoReflect.Descriptor instead.
+func (*SessionConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SessionConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *SessionConfig) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *SessionConfig) GetPreviousSecrets() []string {
+	if x != nil {
+		return x.PreviousSecrets
+	}
+	return nil
+}
+
+func (x *SessionConfig) GetCookieName() string {
+	if x != nil {
+		return x.CookieName
+	}
+	return ""
+}
+
+func (x *SessionConfig) GetCookieDomain() string {
+	if x != nil {
+		return x.CookieDomain
+	}
+	return ""
+}
+
+func (x *SessionConfig) GetCookiePath() string {
+	if x != nil {
+		return x.CookiePath
+	}
+	return ""
+}
+
+func (x *SessionConfig) GetAllowInsecureCookie() bool {
+	if x != nil {
+		return x.AllowInsecureCookie
+	}
+	return false
+}
+
+func (x *SessionConfig) GetSameSite() string {
+	if x != nil {
+		return x.SameSite
+	}
+	return ""
+}
+
+func (x *SessionConfig) GetIdleTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.IdleTimeout
+	}
+	return nil
+}
+
+func (x *SessionConfig) GetAbsoluteTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.AbsoluteTimeout
+	}
+	return nil
+}
+
+func (x *SessionConfig) GetStore() string {
+	if x != nil {
+		return x.Store
+	}
+	return ""
+}
+
+// SignedURLConfig requires a valid, unexpired URL signature on the listed operations. Signatures are an
+// HMAC-SHA256 over the path, the query and the expiry, minted with ServiceHttp.URLSigner or NewURLSigner.
+type SignedURLConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to verify signatures
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Signing key, at least 32 bytes
+	Secret string `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	// Earlier keys still accepted for verification during key rotation
+	// Default: empty
+	PreviousSecrets []string `protobuf:"bytes,3,rep,name=previous_secrets,json=previousSecrets,proto3" json:"previous_secrets,omitempty"`
+	// Operations that require a signature; a trailing "*" matches a prefix
+	Operations []string `protobuf:"bytes,4,rep,name=operations,proto3" json:"operations,omitempty"`
+	// Query parameter carrying the expiry as Unix seconds
+	// Default: "expires"
+	ExpiresParam string `protobuf:"bytes,5,opt,name=expires_param,json=expiresParam,proto3" json:"expires_param,omitempty"`
+	// Query parameter carrying the signature
+	// Default: "signature"
+	SignatureParam string `protobuf:"bytes,6,opt,name=signature_param,json=signatureParam,proto3" json:"signature_param,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SignedURLConfig) Reset() {
+	*x = SignedURLConfig{}
+	mi := &file_http_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignedURLConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignedURLConfig) ProtoMessage() {}
+
+func (x *SignedURLConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignedURLConfig.ProtoReflect.Descriptor instead.
+func (*SignedURLConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SignedURLConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *SignedURLConfig) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *SignedURLConfig) GetPreviousSecrets() []string {
+	if x != nil {
+		return x.PreviousSecrets
+	}
+	return nil
+}
+
+func (x *SignedURLConfig) GetOperations() []string {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+func (x *SignedURLConfig) GetExpiresParam() string {
+	if x != nil {
+		return x.ExpiresParam
+	}
+	return ""
+}
+
+func (x *SignedURLConfig) GetSignatureParam() string {
+	if x != nil {
+		return x.SignatureParam
+	}
+	return ""
+}
+
+// Client detection configuration. The parsed client is available to handlers through
+// ClientInfoFromContext and, optionally, as metric labels.
+type ClientInfoConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to classify clients
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Request header carrying the app version; takes precedence over the User-Agent
+	// Default: "X-App-Version"
+	AppVersionHeader string `protobuf:"bytes,2,opt,name=app_version_header,json=appVersionHeader,proto3" json:"app_version_header,omitempty"`
+	// Request header carrying the client platform (e.g. "ios"); takes precedence over the User-Agent
+	// Default: "X-App-Platform"
+	PlatformHeader string `protobuf:"bytes,3,opt,name=platform_header,json=platformHeader,proto3" json:"platform_header,omitempty"`
+	// User-Agent product tokens that identify the application (e.g. "MyApp" for "MyApp/3.2.1 (iPhone; iOS 17.1)");
+	// the token's version becomes the app version
+	// Default: empty
+	AppNames []string `protobuf:"bytes,4,rep,name=app_names,json=appNames,proto3" json:"app_names,omitempty"`
+	// Whether to record lynx_http_client_app_requests_total by platform and app version
+	// Default: false
+	Metrics bool `protobuf:"varint,5,opt,name=metrics,proto3" json:"metrics,omitempty"`
+	// Maximum number of distinct app version label values; further versions are reported as "other"
+	// Default: 20
+	MaxVersions uint32 `protobuf:"varint,6,opt,name=max_versions,json=maxVersions,proto3" json:"max_versions,omitempty"`
+	// Minimum app versions per platform; older clients get the upgrade-required code. Requests without an
+	// app version are not checked.
+	// Default: empty (no enforcement)
+	MinVersions []*MinClientVersion `protobuf:"bytes,7,rep,name=min_versions,json=minVersions,proto3" json:"min_versions,omitempty"`
+	// Body code returned to clients below their minimum version
+	// Default: 426
+	UpgradeRequiredCode int32 `protobuf:"varint,8,opt,name=upgrade_required_code,json=upgradeRequiredCode,proto3" json:"upgrade_required_code,omitempty"`
+	// Operations never checked (e.g. the endpoint serving app configuration); exact names or prefixes ending in "*"
+	// Default: empty
+	ExemptOperations []string `protobuf:"bytes,9,rep,name=exempt_operations,json=exemptOperations,proto3" json:"exempt_operations,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ClientInfoConfig) Reset() {
+	*x = ClientInfoConfig{}
+	mi := &file_http_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClientInfoConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientInfoConfig) ProtoMessage() {}
+
+func (x *ClientInfoConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientInfoConfig.ProtoReflect.Descriptor instead.
+func (*ClientInfoConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ClientInfoConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *ClientInfoConfig) GetAppVersionHeader() string {
+	if x != nil {
+		return x.AppVersionHeader
+	}
+	return ""
+}
+
+func (x *ClientInfoConfig) GetPlatformHeader() string {
+	if x != nil {
+		return x.PlatformHeader
+	}
+	return ""
+}
+
+func (x *ClientInfoConfig) GetAppNames() []string {
+	if x != nil {
+		return x.AppNames
+	}
+	return nil
+}
+
+func (x *ClientInfoConfig) GetMetrics() bool {
+	if x != nil {
+		return x.Metrics
+	}
+	return false
+}
+
+func (x *ClientInfoConfig) GetMaxVersions() uint32 {
+	if x != nil {
+		return x.MaxVersions
+	}
+	return 0
+}
+
+func (x *ClientInfoConfig) GetMinVersions() []*MinClientVersion {
+	if x != nil {
+		return x.MinVersions
+	}
+	return nil
+}
+
+func (x *ClientInfoConfig) GetUpgradeRequiredCode() int32 {
+	if x != nil {
+		return x.UpgradeRequiredCode
+	}
+	return 0
+}
+
+func (x *ClientInfoConfig) GetExemptOperations() []string {
+	if x != nil {
+		return x.ExemptOperations
+	}
+	return nil
+}
+
+// Minimum app version for one platform.
+type MinClientVersion struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Platform: "ios", "android", "windows", "macos", "linux" or "other"
+	Platform string `protobuf:"bytes,1,opt,name=platform,proto3" json:"platform,omitempty"`
+	// Oldest accepted version (e.g. "3.2.0")
+	MinVersion string `protobuf:"bytes,2,opt,name=min_version,json=minVersion,proto3" json:"min_version,omitempty"`
+	// Store or download URL returned with the upgrade-required code
+	// Default: empty
+	StoreUrl      string `protobuf:"bytes,3,opt,name=store_url,json=storeUrl,proto3" json:"store_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MinClientVersion) Reset() {
+	*x = MinClientVersion{}
+	mi := &file_http_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MinClientVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MinClientVersion) ProtoMessage() {}
+
+func (x *MinClientVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MinClientVersion.ProtoReflect.Descriptor instead.
+func (*MinClientVersion) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *MinClientVersion) GetPlatform() string {
+	if x != nil {
+		return x.Platform
+	}
+	return ""
+}
+
+func (x *MinClientVersion) GetMinVersion() string {
+	if x != nil {
+		return x.MinVersion
+	}
+	return ""
+}
+
+func (x *MinClientVersion) GetStoreUrl() string {
+	if x != nil {
+		return x.StoreUrl
+	}
+	return ""
+}
+
+// RequestConfig controls optional request decoding features.
+type RequestConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Fill unset google.protobuf.FieldMask fields of decoded messages from the JSON keys present in the body,
+	// so update handlers can tell omitted fields from fields set to their zero value
+	// Default: false
+	PopulateFieldMasks bool `protobuf:"varint,1,opt,name=populate_field_masks,json=populateFieldMasks,proto3" json:"populate_field_masks,omitempty"`
+	// Keep a copy of the raw request body, as read by request decoding, for RawRequestBody (HMAC
+	// verification, auditing, debugging)
+	// Default: false
+	CaptureRawBody bool `protobuf:"varint,2,opt,name=capture_raw_body,json=captureRawBody,proto3" json:"capture_raw_body,omitempty"`
+	// Maximum number of body bytes kept per request; longer bodies are marked incomplete
+	// Default: 64KB
+	RawBodyMaxBytes int64 `protobuf:"varint,3,opt,name=raw_body_max_bytes,json=rawBodyMaxBytes,proto3" json:"raw_body_max_bytes,omitempty"`
+	// Accepted request body Content-Types; other bodies fail with UNSUPPORTED_MEDIA_TYPE before decoding
+	// Default: disabled (unknown Content-Types fail decoding with 400)
+	ContentTypes *ContentTypeConfig `protobuf:"bytes,4,opt,name=content_types,json=contentTypes,proto3" json:"content_types,omitempty"`
+	// Business codes for request bodies that fail to decode, by cause
+	// Default: every cause answered with 400
+	DecodeErrors *DecodeErrorConfig `protobuf:"bytes,5,opt,name=decode_errors,json=decodeErrors,proto3" json:"decode_errors,omitempty"`
+	// Query parameter binding conventions; invalid parameters fail with INVALID_QUERY_PARAMETER
+	// Default: lists from repeated keys only, invalid parameters answered with 400
+	Query *QueryConfig `protobuf:"bytes,6,opt,name=query,proto3" json:"query,omitempty"`
+	// Defaults filled into unset fields of decoded request messages, before validation and the handler
+	// Default: none
+	Defaults      []*RequestDefaultsRule `protobuf:"bytes,7,rep,name=defaults,proto3" json:"defaults,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestConfig) Reset() {
+	*x = RequestConfig{}
+	mi := &file_http_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestConfig) ProtoMessage() {}
+
+func (x *RequestConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestConfig.ProtoReflect.Descriptor instead.
+func (*RequestConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *RequestConfig) GetPopulateFieldMasks() bool {
+	if x != nil {
+		return x.PopulateFieldMasks
+	}
+	return false
+}
+
+func (x *RequestConfig) GetCaptureRawBody() bool {
+	if x != nil {
+		return x.CaptureRawBody
+	}
+	return false
+}
+
+func (x *RequestConfig) GetRawBodyMaxBytes() int64 {
+	if x != nil {
+		return x.RawBodyMaxBytes
+	}
+	return 0
+}
+
+func (x *RequestConfig) GetContentTypes() *ContentTypeConfig {
+	if x != nil {
+		return x.ContentTypes
+	}
+	return nil
+}
+
+func (x *RequestConfig) GetDecodeErrors() *DecodeErrorConfig {
+	if x != nil {
+		return x.DecodeErrors
+	}
+	return nil
+}
+
+func (x *RequestConfig) GetQuery() *QueryConfig {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+func (x *RequestConfig) GetDefaults() []*RequestDefaultsRule {
+	if x != nil {
+		return x.Defaults
+	}
+	return nil
+}
+
+// RequestDefaultsRule fills unset fields of the requests of matching operations. Fields are named by proto
+// or JSON name, with dots for nested fields. A field is unset when it holds its zero value or, for fields
+// with presence, when it is absent; the first matching rule that sets a field wins.
+type RequestDefaultsRule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Operation the rule applies to, e.g. "/api.v1.Orders/List"; a trailing * matches a prefix
+	Operation string `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	// Values of unset fields, parsed like query parameters, e.g. page_size: "20"
+	Values map[string]string `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Request headers unset fields are filled from, e.g. lang: "Accept-Language"; Accept-Language yields its
+	// preferred language tag. Fields stay unset when the header is missing or does not fit the field.
+	Headers       map[string]string `protobuf:"bytes,3,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestDefaultsRule) Reset() {
+	*x = RequestDefaultsRule{}
+	mi := &file_http_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestDefaultsRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestDefaultsRule) ProtoMessage() {}
+
+func (x *RequestDefaultsRule) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestDefaultsRule.ProtoReflect.Descriptor instead.
+func (*RequestDefaultsRule) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RequestDefaultsRule) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *RequestDefaultsRule) GetValues() map[string]string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *RequestDefaultsRule) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+// QueryConfig sets how query parameters bind into request messages. Lists bind from repeated keys, enums by
+// name or number, and timestamps from RFC3339 values.
+type QueryConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Also split list parameters on commas, binding ?ids=1,2,3 like ?ids=1&ids=2&ids=3
+	// Default: false
+	CommaLists bool `protobuf:"varint,1,opt,name=comma_lists,json=commaLists,proto3" json:"comma_lists,omitempty"`
+	// Code of INVALID_QUERY_PARAMETER errors
+	// Default: 400
+	Code          int32 `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryConfig) Reset() {
+	*x = QueryConfig{}
+	mi := &file_http_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryConfig) ProtoMessage() {}
+
+func (x *QueryConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryConfig.ProtoReflect.Descriptor instead.
+func (*QueryConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *QueryConfig) GetCommaLists() bool {
+	if x != nil {
+		return x.CommaLists
+	}
+	return false
+}
+
+func (x *QueryConfig) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+// DecodeErrorConfig maps the causes of request decoding failures to business codes. Failures are classified
+// as DECODE_SYNTAX_ERROR, DECODE_UNKNOWN_FIELD, DECODE_TYPE_MISMATCH or DECODE_EMPTY_BODY.
+type DecodeErrorConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Code of malformed bodies (invalid JSON, truncated input)
+	// Default: 400
+	SyntaxErrorCode int32 `protobuf:"varint,1,opt,name=syntax_error_code,json=syntaxErrorCode,proto3" json:"syntax_error_code,omitempty"`
+	// Code of bodies naming fields the request message does not have, on strict_operations
+	// Default: 400
+	UnknownFieldCode int32 `protobuf:"varint,2,opt,name=unknown_field_code,json=unknownFieldCode,proto3" json:"unknown_field_code,omitempty"`
+	// Code of bodies whose values do not fit the field types
+	// Default: 400
+	TypeMismatchCode int32 `protobuf:"varint,3,opt,name=type_mismatch_code,json=typeMismatchCode,proto3" json:"type_mismatch_code,omitempty"`
+	// Code of empty or whitespace-only bodies
+	// Default: 400
+	EmptyBodyCode int32 `protobuf:"varint,4,opt,name=empty_body_code,json=emptyBodyCode,proto3" json:"empty_body_code,omitempty"`
+	// Whether to reject zero-length bodies of routes that bind one; otherwise they decode to an empty message
+	// Default: false
+	RejectEmptyBody bool `protobuf:"varint,5,opt,name=reject_empty_body,json=rejectEmptyBody,proto3" json:"reject_empty_body,omitempty"`
+	// Internal mode: add the cause and a safe detail (field name, expected type, position; never the value)
+	// to the data of the error response. Meant for services not exposed to the public.
+	// Default: false
+	ExposeDetail bool `protobuf:"varint,6,opt,name=expose_detail,json=exposeDetail,proto3" json:"expose_detail,omitempty"`
+	// Operations or paths (wildcards allowed, "*" for every route) whose JSON bodies may not name fields the
+	// request message does not have; such bodies fail with DECODE_UNKNOWN_FIELD instead of being accepted
+	// Default: none (unknown fields are ignored)
+	StrictOperations []string `protobuf:"bytes,7,rep,name=strict_operations,json=strictOperations,proto3" json:"strict_operations,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *DecodeErrorConfig) Reset() {
+	*x = DecodeErrorConfig{}
+	mi := &file_http_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecodeErrorConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecodeErrorConfig) ProtoMessage() {}
+
+func (x *DecodeErrorConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecodeErrorConfig.ProtoReflect.Descriptor instead.
+func (*DecodeErrorConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *DecodeErrorConfig) GetSyntaxErrorCode() int32 {
+	if x != nil {
+		return x.SyntaxErrorCode
+	}
+	return 0
+}
+
+func (x *DecodeErrorConfig) GetUnknownFieldCode() int32 {
+	if x != nil {
+		return x.UnknownFieldCode
+	}
+	return 0
+}
+
+func (x *DecodeErrorConfig) GetTypeMismatchCode() int32 {
+	if x != nil {
+		return x.TypeMismatchCode
+	}
+	return 0
+}
+
+func (x *DecodeErrorConfig) GetEmptyBodyCode() int32 {
+	if x != nil {
+		return x.EmptyBodyCode
+	}
+	return 0
+}
+
+func (x *DecodeErrorConfig) GetRejectEmptyBody() bool {
+	if x != nil {
+		return x.RejectEmptyBody
+	}
+	return false
+}
+
+func (x *DecodeErrorConfig) GetExposeDetail() bool {
+	if x != nil {
+		return x.ExposeDetail
+	}
+	return false
+}
+
+func (x *DecodeErrorConfig) GetStrictOperations() []string {
+	if x != nil {
+		return x.StrictOperations
+	}
+	return nil
+}
+
+// ContentTypeConfig restricts the Content-Types of request bodies. Requests without a body are not checked.
+type ContentTypeConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to check request Content-Types
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Media types accepted by every route without a matching rule, e.g. ["application/json"]; a trailing "*"
+	// matches a prefix, e.g. "application/*"
+	// Default: every media type with a registered codec
+	Allowed []string `protobuf:"bytes,2,rep,name=allowed,proto3" json:"allowed,omitempty"`
+	// Per-route media types; the first rule whose operation matches wins
+	// Default: none
+	Rules []*ContentTypeRule `protobuf:"bytes,3,rep,name=rules,proto3" json:"rules,omitempty"`
+	// Business code of rejected requests
+	// Default: 415
+	Code          int32 `protobuf:"varint,4,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ContentTypeConfig) Reset() {
+	*x = ContentTypeConfig{}
+	mi := &file_http_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContentTypeConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContentTypeConfig) ProtoMessage() {}
+
+func (x *ContentTypeConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContentTypeConfig.ProtoReflect.Descriptor instead.
+func (*ContentTypeConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ContentTypeConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *ContentTypeConfig) GetAllowed() []string {
+	if x != nil {
+		return x.Allowed
+	}
+	return nil
+}
+
+func (x *ContentTypeConfig) GetRules() []*ContentTypeRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *ContentTypeConfig) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+// ContentTypeRule lists the media types accepted by a group of routes.
+type ContentTypeRule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Operation or request path; a trailing "*" matches a prefix, e.g. "/api.v1.Uploads/*"
+	Operation string `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	// Accepted media types, in the form of ContentTypeConfig.allowed
+	Allowed       []string `protobuf:"bytes,2,rep,name=allowed,proto3" json:"allowed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ContentTypeRule) Reset() {
+	*x = ContentTypeRule{}
+	mi := &file_http_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContentTypeRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContentTypeRule) ProtoMessage() {}
+
+func (x *ContentTypeRule) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContentTypeRule.ProtoReflect.Descriptor instead.
+func (*ContentTypeRule) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ContentTypeRule) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *ContentTypeRule) GetAllowed() []string {
+	if x != nil {
+		return x.Allowed
+	}
+	return nil
+}
+
+// BatchConfig controls the batch endpoint, which runs an array of sub-requests through the regular
+// routing and middleware chain and returns their results in one response.
+type BatchConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to mount the endpoint
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Path the endpoint is mounted at
+	// Default: "/batch"
+	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// Maximum number of sub-requests per batch
+	// Default: 20
+	MaxRequests int32 `protobuf:"varint,3,opt,name=max_requests,json=maxRequests,proto3" json:"max_requests,omitempty"`
+	// Maximum number of sub-requests executed concurrently
+	// Default: 4
+	MaxConcurrency int32 `protobuf:"varint,4,opt,name=max_concurrency,json=maxConcurrency,proto3" json:"max_concurrency,omitempty"`
+	// Maximum request body size in bytes
+	// Default: 1MB
+	MaxBodyBytes  int64 `protobuf:"varint,5,opt,name=max_body_bytes,json=maxBodyBytes,proto3" json:"max_body_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchConfig) Reset() {
+	*x = BatchConfig{}
+	mi := &file_http_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchConfig) ProtoMessage() {}
+
+func (x *BatchConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchConfig.ProtoReflect.Descriptor instead.
+func (*BatchConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *BatchConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *BatchConfig) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *BatchConfig) GetMaxRequests() int32 {
+	if x != nil {
+		return x.MaxRequests
+	}
+	return 0
+}
+
+func (x *BatchConfig) GetMaxConcurrency() int32 {
+	if x != nil {
+		return x.MaxConcurrency
+	}
+	return 0
+}
+
+func (x *BatchConfig) GetMaxBodyBytes() int64 {
+	if x != nil {
+		return x.MaxBodyBytes
+	}
+	return 0
+}
+
+// JSONRPCConfig controls the JSON-RPC 2.0 endpoint. Each call runs through the server middleware chain
+// with the operation "<path>/<method>", so per-method selectors and metrics work as for REST routes.
+type JSONRPCConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to mount the endpoint
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Path the endpoint is mounted at
+	// Default: "/jsonrpc"
+	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// Maximum number of calls in a batch request
+	// Default: 100
+	MaxBatchSize int32 `protobuf:"varint,3,opt,name=max_batch_size,json=maxBatchSize,proto3" json:"max_batch_size,omitempty"`
+	// Maximum request body size in bytes
+	// Default: 1MB
+	MaxBodyBytes  int64 `protobuf:"varint,4,opt,name=max_body_bytes,json=maxBodyBytes,proto3" json:"max_body_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JSONRPCConfig) Reset() {
+	*x = JSONRPCConfig{}
+	mi := &file_http_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JSONRPCConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JSONRPCConfig) ProtoMessage() {}
+
+func (x *JSONRPCConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JSONRPCConfig.ProtoReflect.Descriptor instead.
+func (*JSONRPCConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *JSONRPCConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *JSONRPCConfig) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *JSONRPCConfig) GetMaxBatchSize() int32 {
+	if x != nil {
+		return x.MaxBatchSize
+	}
+	return 0
+}
+
+func (x *JSONRPCConfig) GetMaxBodyBytes() int64 {
+	if x != nil {
+		return x.MaxBodyBytes
+	}
+	return 0
+}
+
+// GraphQLConfig controls the GraphQL endpoint. Requests run through the server middleware chain, so
+// tracing, metrics, rate limiting and application middleware such as auth apply as for other routes.
+type GraphQLConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Path the endpoint is mounted at
+	// Default: "/graphql"
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// Whether to also accept queries over GET (mutations are always rejected on GET)
+	// Default: false
+	EnableGet bool `protobuf:"varint,2,opt,name=enable_get,json=enableGet,proto3" json:"enable_get,omitempty"`
+	// Whether ObserveGraphQLResolver records per-resolver latency
+	// Default: false
+	ResolverMetrics bool `protobuf:"varint,3,opt,name=resolver_metrics,json=resolverMetrics,proto3" json:"resolver_metrics,omitempty"`
+	// Maximum request body size in bytes
+	// Default: 1MB
+	MaxBodyBytes  int64 `protobuf:"varint,4,opt,name=max_body_bytes,json=maxBodyBytes,proto3" json:"max_body_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GraphQLConfig) Reset() {
+	*x = GraphQLConfig{}
+	mi := &file_http_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GraphQLConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GraphQLConfig) ProtoMessage() {}
+
+func (x *GraphQLConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GraphQLConfig.ProtoReflect.Descriptor instead.
+func (*GraphQLConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GraphQLConfig) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *GraphQLConfig) GetEnableGet() bool {
+	if x != nil {
+		return x.EnableGet
+	}
+	return false
+}
+
+func (x *GraphQLConfig) GetResolverMetrics() bool {
+	if x != nil {
+		return x.ResolverMetrics
+	}
+	return false
+}
+
+func (x *GraphQLConfig) GetMaxBodyBytes() int64 {
+	if x != nil {
+		return x.MaxBodyBytes
+	}
+	return 0
+}
+
+// ResponseConfig controls optional response encoding features.
+type ResponseConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Prune proto replies to the fields listed in the field filter query parameter, e.g.
+	// "?fields=id,name,address.city" or "?fields=id,address{city,zip}".
+	// Default: false
+	EnableFieldFiltering bool `protobuf:"varint,1,opt,name=enable_field_filtering,json=enableFieldFiltering,proto3" json:"enable_field_filtering,omitempty"`
+	// Query parameter carrying the field selection
+	// Default: "fields"
+	FieldFilterParam string `protobuf:"bytes,2,opt,name=field_filter_param,json=fieldFilterParam,proto3" json:"field_filter_param,omitempty"`
+	// Encode replies and errors as JSON:API documents for requests that accept
+	// "application/vnd.api+json"; other requests keep the standard envelope
+	// Default: false
+	EnableJsonapi bool `protobuf:"varint,3,opt,name=enable_jsonapi,json=enableJsonapi,proto3" json:"enable_jsonapi,omitempty"`
+	// Maximum encoded size of successful replies, guarding clients and egress bills against runaway list endpoints
+	// Default: disabled
+	SizeLimit *ResponseSizeLimitConfig `protobuf:"bytes,4,opt,name=size_limit,json=sizeLimit,proto3" json:"size_limit,omitempty"`
+	// Cache-Control and Vary headers of successful replies by route
+	// Default: disabled
+	CacheControl *CacheControlConfig `protobuf:"bytes,5,opt,name=cache_control,json=cacheControl,proto3" json:"cache_control,omitempty"`
+	// gzip compression of successful replies for clients accepting it
+	// Default: disabled
+	Compression *CompressionConfig `protobuf:"bytes,6,opt,name=compression,proto3" json:"compression,omitempty"`
+	// Signature header over the body of successful replies
+	// Default: disabled
+	Signing *ResponseSigningConfig `protobuf:"bytes,7,opt,name=signing,proto3" json:"signing,omitempty"`
+	// Encryption of sensitive fields of successful proto replies before encoding
+	// Default: disabled
+	FieldEncryption *FieldEncryptionConfig `protobuf:"bytes,8,opt,name=field_encryption,json=fieldEncryption,proto3" json:"field_encryption,omitempty"`
+	// CSV and XLSX exports of list replies, selected by the Accept header or the format query parameter
+	// Default: disabled
+	Export *ExportConfig `protobuf:"bytes,9,opt,name=export,proto3" json:"export,omitempty"`
+	// Binary protobuf replies for internal callers sending Accept: application/x-protobuf
+	// Default: disabled
+	Protobuf *ProtobufResponseConfig `protobuf:"bytes,10,opt,name=protobuf,proto3" json:"protobuf,omitempty"`
+	// Envelope code of successful replies and the business error code ranges of modules
+	// Default: success code 200, no namespaces
+	Codes *EnvelopeCodesConfig `protobuf:"bytes,11,opt,name=codes,proto3" json:"codes,omitempty"`
+	// Non-fatal notices handlers attach to successful replies with AddWarning
+	// Default: disabled
+	Warnings *WarningsConfig `protobuf:"bytes,12,opt,name=warnings,proto3" json:"warnings,omitempty"`
+	// Envelope code of MultiStatus replies in which some items failed
+	// Default: partial success code 207
+	MultiStatus *MultiStatusConfig `protobuf:"bytes,13,opt,name=multi_status,json=multiStatus,proto3" json:"multi_status,omitempty"`
+	// protojson encoding of proto messages in reply data, for canonical well-known type output
+	// Default: disabled
+	ProtoJson *ProtoJSONConfig `protobuf:"bytes,14,opt,name=proto_json,json=protoJson,proto3" json:"proto_json,omitempty"`
+	// Strip or rewrite response headers, such as Server or internal routing headers, before responses leave
+	// Default: disabled
+	HeaderScrubbing *HeaderScrubbingConfig `protobuf:"bytes,15,opt,name=header_scrubbing,json=headerScrubbing,proto3" json:"header_scrubbing,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ResponseConfig) Reset() {
+	*x = ResponseConfig{}
+	mi := &file_http_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResponseConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseConfig) ProtoMessage() {}
+
+func (x *ResponseConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseConfig.ProtoReflect.Descriptor instead.
+func (*ResponseConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ResponseConfig) GetEnableFieldFiltering() bool {
+	if x != nil {
+		return x.EnableFieldFiltering
+	}
+	return false
+}
+
+func (x *ResponseConfig) GetFieldFilterParam() string {
+	if x != nil {
+		return x.FieldFilterParam
+	}
+	return ""
+}
+
+func (x *ResponseConfig) GetEnableJsonapi() bool {
+	if x != nil {
+		return x.EnableJsonapi
+	}
+	return false
+}
+
+func (x *ResponseConfig) GetSizeLimit() *ResponseSizeLimitConfig {
+	if x != nil {
+		return x.SizeLimit
+	}
+	return nil
+}
+
+func (x *ResponseConfig) GetCacheControl() *CacheControlConfig {
+	if x != nil {
+		return x.CacheControl
+	}
+	return nil
+}
+
+func (x *ResponseConfig) GetCompression() *CompressionConfig {
+	if x != nil {
+		return x.Compression
+	}
+	return nil
+}
+
+func (x *ResponseConfig) GetSigning() *ResponseSigningConfig {
+	if x != nil {
+		return x.Signing
+	}
+	return nil
+}
+
+func (x *ResponseConfig) GetFieldEncryption() *FieldEncryptionConfig {
+	if x != nil {
+		return x.FieldEncryption
+	}
+	return nil
+}
+
+func (x *ResponseConfig) GetExport() *ExportConfig {
+	if x != nil {
+		return x.Export
+	}
+	return nil
+}
+
+func (x *ResponseConfig) GetProtobuf() *ProtobufResponseConfig {
+	if x != nil {
+		return x.Protobuf
+	}
+	return nil
+}
+
+func (x *ResponseConfig) GetCodes() *EnvelopeCodesConfig {
+	if x != nil {
+		return x.Codes
+	}
+	return nil
+}
+
+func (x *ResponseConfig) GetWarnings() *WarningsConfig {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+func (x *ResponseConfig) GetMultiStatus() *MultiStatusConfig {
+	if x != nil {
+		return x.MultiStatus
+	}
+	return nil
+}
+
+func (x *ResponseConfig) GetProtoJson() *ProtoJSONConfig {
+	if x != nil {
+		return x.ProtoJson
+	}
+	return nil
+}
+
+func (x *ResponseConfig) GetHeaderScrubbing() *HeaderScrubbingConfig {
+	if x != nil {
+		return x.HeaderScrubbing
+	}
+	return nil
+}
+
+// HeaderScrubbingConfig removes and rewrites response headers set by handlers, filters or proxied upstreams, on
+// every response, including errors, streams and HEAD and OPTIONS answers. Names are case-insensitive. Headers
+// listed in rewrite are rewritten rather than removed.
+type HeaderScrubbingConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to scrub response headers
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Headers to remove; a trailing "*" matches a prefix (e.g. "X-Internal-*")
+	// Default: ["Server", "X-Powered-By", "X-AspNet-Version", "X-AspNetMvc-Version"]
+	Remove []string `protobuf:"bytes,2,rep,name=remove,proto3" json:"remove,omitempty"`
+	// New values of headers, applied when the header is present (e.g. {"Server": "lynx"})
+	// Default: empty
+	Rewrite       map[string]string `protobuf:"bytes,3,rep,name=rewrite,proto3" json:"rewrite,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeaderScrubbingConfig) Reset() {
+	*x = HeaderScrubbingConfig{}
+	mi := &file_http_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeaderScrubbingConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeaderScrubbingConfig) ProtoMessage() {}
+
+func (x *HeaderScrubbingConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeaderScrubbingConfig.ProtoReflect.Descriptor instead.
+func (*HeaderScrubbingConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *HeaderScrubbingConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *HeaderScrubbingConfig) GetRemove() []string {
+	if x != nil {
+		return x.Remove
+	}
+	return nil
+}
+
+func (x *HeaderScrubbingConfig) GetRewrite() map[string]string {
+	if x != nil {
+		return x.Rewrite
+	}
+	return nil
+}
+
+// ProtoJSONConfig encodes proto messages in reply data with protojson, at the top level or inside maps, slices and
+// structs, so well-known types get their canonical JSON form: Timestamp and Duration as strings, wrappers as plain
+// values, Struct as an object and Any with its "@type". Without it, proto data is written by encoding/json as
+// plain Go structs.
+type ProtoJSONConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Enable protojson encoding of proto data
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Use lowerCamelCase JSON names instead of the proto field names
+	// Default: false
+	UseJsonNames bool `protobuf:"varint,2,opt,name=use_json_names,json=useJsonNames,proto3" json:"use_json_names,omitempty"`
+	// Write fields holding their zero value
+	// Default: false
+	EmitUnpopulated bool `protobuf:"varint,3,opt,name=emit_unpopulated,json=emitUnpopulated,proto3" json:"emit_unpopulated,omitempty"`
+	// Write enums as numbers instead of names
+	// Default: false
+	UseEnumNumbers bool `protobuf:"varint,4,opt,name=use_enum_numbers,json=useEnumNumbers,proto3" json:"use_enum_numbers,omitempty"`
+	// FileDescriptorSet files (protoc --include_imports --descriptor_set_out) whose messages resolve the type URLs
+	// of Any values not compiled into the binary
+	// Default: none
+	DescriptorSets []string `protobuf:"bytes,5,rep,name=descriptor_sets,json=descriptorSets,proto3" json:"descriptor_sets,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ProtoJSONConfig) Reset() {
+	*x = ProtoJSONConfig{}
+	mi := &file_http_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProtoJSONConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProtoJSONConfig) ProtoMessage() {}
+
+func (x *ProtoJSONConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProtoJSONConfig.ProtoReflect.Descriptor instead.
+func (*ProtoJSONConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ProtoJSONConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *ProtoJSONConfig) GetUseJsonNames() bool {
+	if x != nil {
+		return x.UseJsonNames
+	}
+	return false
+}
+
+func (x *ProtoJSONConfig) GetEmitUnpopulated() bool {
+	if x != nil {
+		return x.EmitUnpopulated
+	}
+	return false
+}
+
+func (x *ProtoJSONConfig) GetUseEnumNumbers() bool {
+	if x != nil {
+		return x.UseEnumNumbers
+	}
+	return false
+}
+
+func (x *ProtoJSONConfig) GetDescriptorSets() []string {
+	if x != nil {
+		return x.DescriptorSets
+	}
+	return nil
+}
+
+// MultiStatusConfig sets the top-level code of MultiStatus replies, whose items each carry their own code.
+type MultiStatusConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Envelope code of replies with both succeeded and failed items, or failed items with different codes;
+	// replies whose items all succeeded get the success code, and replies whose items all failed with one code get that code
+	// Default: 207
+	PartialSuccessCode int32 `protobuf:"varint,1,opt,name=partial_success_code,json=partialSuccessCode,proto3" json:"partial_success_code,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *MultiStatusConfig) Reset() {
+	*x = MultiStatusConfig{}
+	mi := &file_http_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MultiStatusConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MultiStatusConfig) ProtoMessage() {}
+
+func (x *MultiStatusConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MultiStatusConfig.ProtoReflect.Descriptor instead.
+func (*MultiStatusConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *MultiStatusConfig) GetPartialSuccessCode() int32 {
+	if x != nil {
+		return x.PartialSuccessCode
+	}
+	return 0
+}
+
+// WarningsConfig adds a "warnings" array to successful envelopes, listing the notices handlers attached with
+// AddWarning, such as a deprecated parameter being used or partial data being returned.
+type WarningsConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Enable warnings
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Maximum number of warnings per reply; further warnings are dropped
+	// Default: 20
+	MaxWarnings   int32 `protobuf:"varint,2,opt,name=max_warnings,json=maxWarnings,proto3" json:"max_warnings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WarningsConfig) Reset() {
+	*x = WarningsConfig{}
+	mi := &file_http_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WarningsConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WarningsConfig) ProtoMessage() {}
+
+func (x *WarningsConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WarningsConfig.ProtoReflect.Descriptor instead.
+func (*WarningsConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *WarningsConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *WarningsConfig) GetMaxWarnings() int32 {
+	if x != nil {
+		return x.MaxWarnings
+	}
+	return 0
+}
+
+// EnvelopeCodesConfig sets the body code of successful replies and reserves a range of business error codes for
+// each module, so modules cannot hand out colliding codes. Error codes configured elsewhere (size limit, decode
+// errors, ...) must stay outside both; BusinessCodeMapper.Validate checks registered codes the same way.
+type EnvelopeCodesConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Body code of successful replies, e.g. 0. HTTP error statuses (400-599) are reserved for unmapped errors
+	// Default: 200
+	SuccessCode *int32 `protobuf:"varint,1,opt,name=success_code,json=successCode,proto3,oneof" json:"success_code,omitempty"`
+	// Business error code ranges by module; ranges must not overlap or contain the success code or 500
+	// Default: none
+	Namespaces    []*CodeNamespace `protobuf:"bytes,2,rep,name=namespaces,proto3" json:"namespaces,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnvelopeCodesConfig) Reset() {
+	*x = EnvelopeCodesConfig{}
+	mi := &file_http_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnvelopeCodesConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnvelopeCodesConfig) ProtoMessage() {}
+
+func (x *EnvelopeCodesConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnvelopeCodesConfig.ProtoReflect.Descriptor instead.
+func (*EnvelopeCodesConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *EnvelopeCodesConfig) GetSuccessCode() int32 {
+	if x != nil && x.SuccessCode != nil {
+		return *x.SuccessCode
+	}
+	return 0
+}
+
+func (x *EnvelopeCodesConfig) GetNamespaces() []*CodeNamespace {
+	if x != nil {
+		return x.Namespaces
+	}
+	return nil
+}
+
+// CodeNamespace is the range of business error codes owned by one module.
+type CodeNamespace struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Module owning the range, as carried in the "module" error metadata
+	Module string `protobuf:"bytes,1,opt,name=module,proto3" json:"module,omitempty"`
+	// Lowest code of the range
+	Min int32 `protobuf:"varint,2,opt,name=min,proto3" json:"min,omitempty"`
+	// Highest code of the range, inclusive
+	Max           int32 `protobuf:"varint,3,opt,name=max,proto3" json:"max,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CodeNamespace) Reset() {
+	*x = CodeNamespace{}
+	mi := &file_http_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CodeNamespace) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CodeNamespace) ProtoMessage() {}
+
+func (x *CodeNamespace) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CodeNamespace.ProtoReflect.Descriptor instead.
+func (*CodeNamespace) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *CodeNamespace) GetModule() string {
+	if x != nil {
+		return x.Module
+	}
+	return ""
+}
+
+func (x *CodeNamespace) GetMin() int32 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *CodeNamespace) GetMax() int32 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+// ProtobufResponseConfig answers requests accepting "application/x-protobuf" with the proto-encoded reply instead
+// of JSON, sparing service-to-service calls the size and CPU cost of protojson. The Content-Type names the encoded
+// message type, e.g. application/x-protobuf; messageType="api.v1.User". Errors and replies that are not proto
+// messages keep the JSON envelope.
+type ProtobufResponseConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Enable protobuf replies
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Wrap replies in the lynx.http.Response envelope (code = 1, message = 2, data = 3 as google.protobuf.Any)
+	// instead of sending the bare reply message
+	// Default: false
+	Envelope bool `protobuf:"varint,2,opt,name=envelope,proto3" json:"envelope,omitempty"`
+	// Operations that may answer in protobuf ("/pkg.Service/Method", "/pkg.Service/*" or a path pattern)
+	// Default: all operations
+	Operations    []string `protobuf:"bytes,3,rep,name=operations,proto3" json:"operations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProtobufResponseConfig) Reset() {
+	*x = ProtobufResponseConfig{}
+	mi := &file_http_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProtobufResponseConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProtobufResponseConfig) ProtoMessage() {}
+
+func (x *ProtobufResponseConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProtobufResponseConfig.ProtoReflect.Descriptor instead.
+func (*ProtobufResponseConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ProtobufResponseConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *ProtobufResponseConfig) GetEnvelope() bool {
+	if x != nil {
+		return x.Envelope
+	}
+	return false
+}
+
+func (x *ProtobufResponseConfig) GetOperations() []string {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+// ExportConfig writes the rows of list replies of the listed operations as CSV (RFC 4180) or XLSX instead of
+// JSON when the client asks for it with Accept: text/csv or ?format=csv. Rows are written as they are read, so
+// ListStream replies are exported without holding them in memory.
+type ExportConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to enable exports
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Query parameter selecting the format: "csv", "xlsx" or "json"
+	// Default: "format"
+	FormatParam string `protobuf:"bytes,2,opt,name=format_param,json=formatParam,proto3" json:"format_param,omitempty"`
+	// Whether XLSX exports are offered besides CSV
+	// Default: false
+	EnableXlsx bool `protobuf:"varint,3,opt,name=enable_xlsx,json=enableXlsx,proto3" json:"enable_xlsx,omitempty"`
+	// Operations that can be exported; the first matching rule applies
+	// Default: empty
+	Rules         []*ExportRule `protobuf:"bytes,4,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportConfig) Reset() {
+	*x = ExportConfig{}
+	mi := &file_http_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportConfig) ProtoMessage() {}
+
+func (x *ExportConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportConfig.ProtoReflect.Descriptor instead.
+func (*ExportConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ExportConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *ExportConfig) GetFormatParam() string {
+	if x != nil {
+		return x.FormatParam
+	}
+	return ""
+}
+
+func (x *ExportConfig) GetEnableXlsx() bool {
+	if x != nil {
+		return x.EnableXlsx
+	}
+	return false
+}
+
+func (x *ExportConfig) GetRules() []*ExportRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+// ExportRule maps the rows of an operation's replies to columns.
+type ExportRule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Operation or path; exact name or prefix ending in "*"
+	Operation string `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	// Repeated message field of the reply holding the rows; replies without one are exported as a single row
+	// Default: the first repeated message field
+	ListField string `protobuf:"bytes,2,opt,name=list_field,json=listField,proto3" json:"list_field,omitempty"`
+	// Columns in order; field paths may name nested fields, e.g. "customer.name"
+	// Default: every field of the row message, headed by its name
+	Columns []*ExportColumn `protobuf:"bytes,3,rep,name=columns,proto3" json:"columns,omitempty"`
+	// Download file name without extension, sent in Content-Disposition
+	// Default: "export"
+	Filename      string `protobuf:"bytes,4,opt,name=filename,proto3" json:"filename,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportRule) Reset() {
+	*x = ExportRule{}
+	mi := &file_http_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportRule) ProtoMessage() {}
+
+func (x *ExportRule) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportRule.ProtoReflect.Descriptor instead.
+func (*ExportRule) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ExportRule) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *ExportRule) GetListField() string {
+	if x != nil {
+		return x.ListField
+	}
+	return ""
+}
+
+func (x *ExportRule) GetColumns() []*ExportColumn {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+func (x *ExportRule) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+// ExportColumn is one column of an export.
+type ExportColumn struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Field path in the row message, by proto or JSON name
+	Field string `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	// Column header
+	// Default: the field path
+	Header        string `protobuf:"bytes,2,opt,name=header,proto3" json:"header,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportColumn) Reset() {
+	*x = ExportColumn{}
+	mi := &file_http_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportColumn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportColumn) ProtoMessage() {}
+
+func (x *ExportColumn) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportColumn.ProtoReflect.Descriptor instead.
+func (*ExportColumn) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ExportColumn) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *ExportColumn) GetHeader() string {
+	if x != nil {
+		return x.Header
+	}
+	return ""
+}
+
+// FieldEncryptionConfig replaces configured string and bytes fields of replies with AES-256-GCM envelopes
+// "enc:v1:<key id>:<base64url nonce and ciphertext>". The field path is authenticated with the ciphertext, so
+// an envelope cannot be moved to another field. Replies whose configured fields cannot be encrypted fail
+// instead of leaking them.
+type FieldEncryptionConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to encrypt fields
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Fields to encrypt per operation; every matching rule applies
+	Rules []*FieldEncryptionRule `protobuf:"bytes,2,rep,name=rules,proto3" json:"rules,omitempty"`
+	// Pre-shared keys; keep older keys listed while clients may still hold envelopes encrypted with them
+	// Default: empty
+	Keys []*FieldEncryptionKey `protobuf:"bytes,3,rep,name=keys,proto3" json:"keys,omitempty"`
+	// Key used to encrypt
+	// Default: the first key
+	ActiveKeyId string `protobuf:"bytes,4,opt,name=active_key_id,json=activeKeyId,proto3" json:"active_key_id,omitempty"`
+	// Request header carrying a base64 AES-256 key chosen by the client, used instead of the pre-shared keys.
+	// Only set it for TLS listeners.
+	// Default: empty (pre-shared keys only)
+	ClientKeyHeader string `protobuf:"bytes,5,opt,name=client_key_header,json=clientKeyHeader,proto3" json:"client_key_header,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *FieldEncryptionConfig) Reset() {
+	*x = FieldEncryptionConfig{}
+	mi := &file_http_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldEncryptionConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldEncryptionConfig) ProtoMessage() {}
+
+func (x *FieldEncryptionConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldEncryptionConfig.ProtoReflect.Descriptor instead.
+func (*FieldEncryptionConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *FieldEncryptionConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *FieldEncryptionConfig) GetRules() []*FieldEncryptionRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *FieldEncryptionConfig) GetKeys() []*FieldEncryptionKey {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *FieldEncryptionConfig) GetActiveKeyId() string {
+	if x != nil {
+		return x.ActiveKeyId
+	}
+	return ""
+}
+
+func (x *FieldEncryptionConfig) GetClientKeyHeader() string {
+	if x != nil {
+		return x.ClientKeyHeader
+	}
+	return ""
+}
+
+type FieldEncryptionRule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Operation or path; exact name or prefix ending in "*"
+	Operation string `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	// Dotted field paths, e.g. "card.pan"; paths through repeated messages encrypt the field of every element
+	Fields        []string `protobuf:"bytes,2,rep,name=fields,proto3" json:"fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldEncryptionRule) Reset() {
+	*x = FieldEncryptionRule{}
+	mi := &file_http_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldEncryptionRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldEncryptionRule) ProtoMessage() {}
+
+func (x *FieldEncryptionRule) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldEncryptionRule.ProtoReflect.Descriptor instead.
+func (*FieldEncryptionRule) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *FieldEncryptionRule) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *FieldEncryptionRule) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type FieldEncryptionKey struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Key identifier written into envelopes; must not contain ":"
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Base64 32-byte AES-256 key
+	Key           string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldEncryptionKey) Reset() {
+	*x = FieldEncryptionKey{}
+	mi := &file_http_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldEncryptionKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldEncryptionKey) ProtoMessage() {}
+
+func (x *FieldEncryptionKey) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldEncryptionKey.ProtoReflect.Descriptor instead.
+func (*FieldEncryptionKey) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *FieldEncryptionKey) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *FieldEncryptionKey) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+// ResponseSigningConfig signs the body of successful replies, before compression, so gateways and clients can
+// verify it was not altered. Keys come from configuration, so any Lynx config source holding secrets can supply
+// and rotate them.
+type ResponseSigningConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to sign replies
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Signature algorithm: "hmac-sha256" or "ed25519"
+	// Default: "hmac-sha256"
+	Algorithm string `protobuf:"bytes,2,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	// Signing key: at least 32 bytes for hmac-sha256; the base64 32-byte seed or 64-byte private key for ed25519
+	Key string `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	// Key identifier sent with the signature so verifiers can pick the key during rotation
+	// Default: empty
+	KeyId string `protobuf:"bytes,4,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	// Reply header carrying the signature
+	// Default: "X-Response-Signature"
+	Header string `protobuf:"bytes,5,opt,name=header,proto3" json:"header,omitempty"`
+	// Operations or paths whose replies are not signed; exact names or prefixes ending in "*"
+	// Default: empty
+	ExemptOperations []string `protobuf:"bytes,6,rep,name=exempt_operations,json=exemptOperations,proto3" json:"exempt_operations,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ResponseSigningConfig) Reset() {
+	*x = ResponseSigningConfig{}
+	mi := &file_http_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResponseSigningConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseSigningConfig) ProtoMessage() {}
+
+func (x *ResponseSigningConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseSigningConfig.ProtoReflect.Descriptor instead.
+func (*ResponseSigningConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ResponseSigningConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *ResponseSigningConfig) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+func (x *ResponseSigningConfig) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ResponseSigningConfig) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *ResponseSigningConfig) GetHeader() string {
+	if x != nil {
+		return x.Header
+	}
+	return ""
+}
+
+func (x *ResponseSigningConfig) GetExemptOperations() []string {
+	if x != nil {
+		return x.ExemptOperations
+	}
+	return nil
+}
+
+// CompressionConfig gzips successful replies of clients sending "Accept-Encoding: gzip". Error replies and
+// replies the handler already encoded are sent as they are.
+type CompressionConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Compress replies
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Smallest encoded body compressed, in bytes; smaller ones gain too little to be worth it
+	// Default: 1024
+	MinSize int32 `protobuf:"varint,2,opt,name=min_size,json=minSize,proto3" json:"min_size,omitempty"`
+	// gzip level from 1 (fastest) to 9 (smallest)
+	// Default: 0 (the gzip default, 6)
+	Level int32 `protobuf:"varint,3,opt,name=level,proto3" json:"level,omitempty"`
+	// Operations or paths, exact or ending in "*", never compressed
+	// Default: none
+	ExemptOperations []string `protobuf:"bytes,4,rep,name=exempt_operations,json=exemptOperations,proto3" json:"exempt_operations,omitempty"`
+	// Cache of compressed bodies, for hot endpoints returning identical replies
+	// Default: disabled
+	Cache         *CompressionCacheConfig `protobuf:"bytes,5,opt,name=cache,proto3" json:"cache,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompressionConfig) Reset() {
+	*x = CompressionConfig{}
+	mi := &file_http_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompressionConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompressionConfig) ProtoMessage() {}
+
+func (x *CompressionConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompressionConfig.ProtoReflect.Descriptor instead.
+func (*CompressionConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *CompressionConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *CompressionConfig) GetMinSize() int32 {
+	if x != nil {
+		return x.MinSize
+	}
+	return 0
+}
+
+func (x *CompressionConfig) GetLevel() int32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *CompressionConfig) GetExemptOperations() []string {
+	if x != nil {
+		return x.ExemptOperations
+	}
+	return nil
+}
+
+func (x *CompressionConfig) GetCache() *CompressionCacheConfig {
+	if x != nil {
+		return x.Cache
+	}
+	return nil
+}
+
+// CompressionCacheConfig keeps the compressed bytes of recent replies, keyed by operation and a hash of the
+// encoded body, so identical replies are compressed once per TTL. Hits and misses are counted in
+// lynx_http_compression_cache_requests_total and the cached bytes in lynx_http_compression_cache_bytes.
+type CompressionCacheConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Cache compressed bodies
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Time a compressed body is reused
+	// Default: 10s
+	Ttl *durationpb.Duration `protobuf:"bytes,2,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	// Memory cap of the cached compressed bodies in bytes; the oldest entries are dropped to stay below it
+	// Default: 16777216 (16 MiB)
+	MaxBytes      int64 `protobuf:"varint,3,opt,name=max_bytes,json=maxBytes,proto3" json:"max_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompressionCacheConfig) Reset() {
+	*x = CompressionCacheConfig{}
+	mi := &file_http_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompressionCacheConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompressionCacheConfig) ProtoMessage() {}
+
+func (x *CompressionCacheConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompressionCacheConfig.ProtoReflect.Descriptor instead.
+func (*CompressionCacheConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *CompressionCacheConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *CompressionCacheConfig) GetTtl() *durationpb.Duration {
+	if x != nil {
+		return x.Ttl
+	}
+	return nil
+}
+
+func (x *CompressionCacheConfig) GetMaxBytes() int64 {
+	if x != nil {
+		return x.MaxBytes
+	}
+	return 0
+}
+
+// CacheControlConfig sets the caching headers of successful replies from configuration instead of handlers.
+// Error replies get no Cache-Control header, and a Cache-Control header set by the handler is kept.
+type CacheControlConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Per-route policies; the first rule whose operation matches wins
+	// Default: none
+	Rules []*CacheControlRule `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	// Policy of routes no rule matches, e.g. {no_store: true}
+	// Default: none (no header)
+	Default       *CacheControlRule `protobuf:"bytes,2,opt,name=default,proto3" json:"default,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CacheControlConfig) Reset() {
+	*x = CacheControlConfig{}
+	mi := &file_http_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CacheControlConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CacheControlConfig) ProtoMessage() {}
+
+func (x *CacheControlConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CacheControlConfig.ProtoReflect.Descriptor instead.
+func (*CacheControlConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *CacheControlConfig) GetRules() []*CacheControlRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *CacheControlConfig) GetDefault() *CacheControlRule {
+	if x != nil {
+		return x.Default
+	}
+	return nil
+}
+
+// CacheControlRule is the caching policy of a group of routes. Its directives form the Cache-Control header,
+// e.g. "public, max-age=60, s-maxage=300".
+type CacheControlRule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Operation or request path; a trailing "*" matches a prefix, e.g. "/api.v1.Catalog/*". Ignored in default
+	Operation string `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	// HTTP methods the rule applies to
+	// Default: ["GET", "HEAD"]
+	Methods []string `protobuf:"bytes,2,rep,name=methods,proto3" json:"methods,omitempty"`
+	// "public" or "private"
+	// Default: "" (neither)
+	Visibility string `protobuf:"bytes,3,opt,name=visibility,proto3" json:"visibility,omitempty"`
+	// max-age, the time browsers and shared caches may serve the reply
+	// Default: not sent
+	MaxAge *durationpb.Duration `protobuf:"bytes,4,opt,name=max_age,json=maxAge,proto3" json:"max_age,omitempty"`
+	// s-maxage, the time shared caches such as CDNs may serve the reply, overriding max_age for them
+	// Default: not sent
+	SMaxage *durationpb.Duration `protobuf:"bytes,5,opt,name=s_maxage,json=sMaxage,proto3" json:"s_maxage,omitempty"`
+	// no-store: the reply must not be cached; the other directives are then not sent
+	// Default: false
+	NoStore bool `protobuf:"varint,6,opt,name=no_store,json=noStore,proto3" json:"no_store,omitempty"`
+	// no-cache: caches must revalidate before serving the reply
+	// Default: false
+	NoCache bool `protobuf:"varint,7,opt,name=no_cache,json=noCache,proto3" json:"no_cache,omitempty"`
+	// must-revalidate: stale replies must not be served without revalidation
+	// Default: false
+	MustRevalidate bool `protobuf:"varint,8,opt,name=must_revalidate,json=mustRevalidate,proto3" json:"must_revalidate,omitempty"`
+	// immutable: the reply will not change while fresh
+	// Default: false
+	Immutable bool `protobuf:"varint,9,opt,name=immutable,proto3" json:"immutable,omitempty"`
+	// stale-while-revalidate, the time a stale reply may be served while it is revalidated in the background
+	// Default: not sent
+	StaleWhileRevalidate *durationpb.Duration `protobuf:"bytes,10,opt,name=stale_while_revalidate,json=staleWhileRevalidate,proto3" json:"stale_while_revalidate,omitempty"`
+	// Request headers the reply varies by, added to the Vary header, e.g. ["Accept-Encoding", "Authorization"]
+	// Default: none
+	Vary          []string `protobuf:"bytes,11,rep,name=vary,proto3" json:"vary,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CacheControlRule) Reset() {
+	*x = CacheControlRule{}
+	mi := &file_http_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CacheControlRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CacheControlRule) ProtoMessage() {}
+
+func (x *CacheControlRule) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CacheControlRule.ProtoReflect.Descriptor instead.
+func (*CacheControlRule) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *CacheControlRule) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *CacheControlRule) GetMethods() []string {
+	if x != nil {
+		return x.Methods
+	}
+	return nil
+}
+
+func (x *CacheControlRule) GetVisibility() string {
+	if x != nil {
+		return x.Visibility
+	}
+	return ""
+}
+
+func (x *CacheControlRule) GetMaxAge() *durationpb.Duration {
+	if x != nil {
+		return x.MaxAge
+	}
+	return nil
+}
+
+func (x *CacheControlRule) GetSMaxage() *durationpb.Duration {
+	if x != nil {
+		return x.SMaxage
+	}
+	return nil
+}
+
+func (x *CacheControlRule) GetNoStore() bool {
+	if x != nil {
+		return x.NoStore
+	}
+	return false
+}
+
+func (x *CacheControlRule) GetNoCache() bool {
+	if x != nil {
+		return x.NoCache
+	}
+	return false
+}
+
+func (x *CacheControlRule) GetMustRevalidate() bool {
+	if x != nil {
+		return x.MustRevalidate
+	}
+	return false
+}
+
+func (x *CacheControlRule) GetImmutable() bool {
+	if x != nil {
+		return x.Immutable
+	}
+	return false
+}
+
+func (x *CacheControlRule) GetStaleWhileRevalidate() *durationpb.Duration {
+	if x != nil {
+		return x.StaleWhileRevalidate
+	}
+	return nil
+}
+
+func (x *CacheControlRule) GetVary() []string {
+	if x != nil {
+		return x.Vary
+	}
+	return nil
+}
+
+// ResponseSizeLimitConfig flags successful replies whose encoded body exceeds max_bytes. Oversized replies are
+// logged with their operation and caller and counted in lynx_http_oversized_responses_total.
+type ResponseSizeLimitConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Maximum encoded body size in bytes
+	// Default: 0 (disabled)
+	MaxBytes int64 `protobuf:"varint,1,opt,name=max_bytes,json=maxBytes,proto3" json:"max_bytes,omitempty"`
+	// Replace oversized replies with a RESPONSE_TOO_LARGE error instead of only reporting them. Replies are then
+	// buffered before they are written.
+	// Default: false
+	Replace bool `protobuf:"varint,2,opt,name=replace,proto3" json:"replace,omitempty"`
+	// Code of the RESPONSE_TOO_LARGE error
+	// Default: 413
+	Code int32 `protobuf:"varint,3,opt,name=code,proto3" json:"code,omitempty"`
+	// Operations exempt from the limit, e.g. exports; a trailing "*" matches a prefix
+	// Default: empty
+	ExemptOperations []string `protobuf:"bytes,4,rep,name=exempt_operations,json=exemptOperations,proto3" json:"exempt_operations,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ResponseSizeLimitConfig) Reset() {
+	*x = ResponseSizeLimitConfig{}
+	mi := &file_http_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResponseSizeLimitConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseSizeLimitConfig) ProtoMessage() {}
+
+func (x *ResponseSizeLimitConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseSizeLimitConfig.ProtoReflect.Descriptor instead.
+func (*ResponseSizeLimitConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ResponseSizeLimitConfig) GetMaxBytes() int64 {
+	if x != nil {
+		return x.MaxBytes
+	}
+	return 0
+}
+
+func (x *ResponseSizeLimitConfig) GetReplace() bool {
+	if x != nil {
+		return x.Replace
+	}
+	return false
+}
+
+func (x *ResponseSizeLimitConfig) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *ResponseSizeLimitConfig) GetExemptOperations() []string {
+	if x != nil {
+		return x.ExemptOperations
+	}
+	return nil
+}
+
+// ProxyProtocolConfig accepts HAProxy PROXY protocol headers from TCP load balancers so the real
+// client address is used for logging, per-IP limits and connection metrics.
+type ProxyProtocolConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to parse PROXY protocol headers on accepted connections
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Load balancer networks allowed to send PROXY headers (CIDR notation). Required when enabled.
+	// Connections from other peers are served as-is, so a forged header fails as a malformed request.
+	TrustedCidrs []string `protobuf:"bytes,2,rep,name=trusted_cidrs,json=trustedCidrs,proto3" json:"trusted_cidrs,omitempty"`
+	// Reject connections from trusted peers that do not start with a PROXY header.
+	// Default: false (headerless connections keep the peer address)
+	Required bool `protobuf:"varint,3,opt,name=required,proto3" json:"required,omitempty"`
+	// Maximum time to wait for the PROXY header after accepting a connection.
+	// Default: 5s
+	HeaderTimeout *durationpb.Duration `protobuf:"bytes,4,opt,name=header_timeout,json=headerTimeout,proto3" json:"header_timeout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProxyProtocolConfig) Reset() {
+	*x = ProxyProtocolConfig{}
+	mi := &file_http_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProxyProtocolConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProxyProtocolConfig) ProtoMessage() {}
+
+func (x *ProxyProtocolConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProxyProtocolConfig.ProtoReflect.Descriptor instead.
+func (*ProxyProtocolConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ProxyProtocolConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *ProxyProtocolConfig) GetTrustedCidrs() []string {
+	if x != nil {
+		return x.TrustedCidrs
+	}
+	return nil
+}
+
+func (x *ProxyProtocolConfig) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+func (x *ProxyProtocolConfig) GetHeaderTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.HeaderTimeout
+	}
+	return nil
+}
+
+// SystemdConfig integrates the server with systemd-managed deployments.
+type SystemdConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Serve on a socket passed by systemd (LISTEN_FDS) instead of binding addr.
+	// Falls back to binding addr when the process was not socket activated.
+	// Default: false
+	SocketActivation bool `protobuf:"varint,1,opt,name=socket_activation,json=socketActivation,proto3" json:"socket_activation,omitempty"`
+	// Name of the inherited socket to serve on (FileDescriptorName= in the socket unit).
+	// Default: the first passed socket
+	ListenFdName string `protobuf:"bytes,2,opt,name=listen_fd_name,json=listenFdName,proto3" json:"listen_fd_name,omitempty"`
+	// Send READY=1, STATUS= and STOPPING=1 notifications to NOTIFY_SOCKET (Type=notify units).
+	// Default: false
+	Notify bool `protobuf:"varint,3,opt,name=notify,proto3" json:"notify,omitempty"`
+	// Send WATCHDOG=1 keep-alives at half of WATCHDOG_USEC while the runtime health check passes.
+	// Has no effect unless the unit sets WatchdogSec=.
+	// Default: false
+	Watchdog      bool `protobuf:"varint,4,opt,name=watchdog,proto3" json:"watchdog,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SystemdConfig) Reset() {
+	*x = SystemdConfig{}
+	mi := &file_http_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SystemdConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SystemdConfig) ProtoMessage() {}
+
+func (x *SystemdConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SystemdConfig.ProtoReflect.Descriptor instead.
+func (*SystemdConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *SystemdConfig) GetSocketActivation() bool {
+	if x != nil {
+		return x.SocketActivation
+	}
+	return false
+}
+
+func (x *SystemdConfig) GetListenFdName() string {
+	if x != nil {
+		return x.ListenFdName
+	}
+	return ""
+}
+
+func (x *SystemdConfig) GetNotify() bool {
+	if x != nil {
+		return x.Notify
+	}
+	return false
+}
+
+func (x *SystemdConfig) GetWatchdog() bool {
+	if x != nil {
+		return x.Watchdog
+	}
+	return false
+}
+
+// Monitoring configuration
+type MonitoringConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to enable Prometheus metrics
+	// Default: true
+	EnableMetrics bool `protobuf:"varint,1,opt,name=enable_metrics,json=enableMetrics,proto3" json:"enable_metrics,omitempty"`
+	// Metrics endpoint path
+	// Default: "/metrics"
+	MetricsPath string `protobuf:"bytes,2,opt,name=metrics_path,json=metricsPath,proto3" json:"metrics_path,omitempty"`
+	// Health check endpoint path
+	// Default: "/health"
+	HealthPath string `protobuf:"bytes,3,opt,name=health_path,json=healthPath,proto3" json:"health_path,omitempty"`
+	// Whether to enable request logging
+	// Default: true
+	EnableRequestLogging bool `protobuf:"varint,4,opt,name=enable_request_logging,json=enableRequestLogging,proto3" json:"enable_request_logging,omitempty"`
+	// Whether to enable error logging
+	// Default: true
+	EnableErrorLogging bool `protobuf:"varint,5,opt,name=enable_error_logging,json=enableErrorLogging,proto3" json:"enable_error_logging,omitempty"`
+	// Whether to enable detailed route metrics
+	// Default: true
+	EnableRouteMetrics bool `protobuf:"varint,6,opt,name=enable_route_metrics,json=enableRouteMetrics,proto3" json:"enable_route_metrics,omitempty"`
+	// Whether to enable connection metrics
+	// Default: true
+	EnableConnectionMetrics bool `protobuf:"varint,7,opt,name=enable_connection_metrics,json=enableConnectionMetrics,proto3" json:"enable_connection_metrics,omitempty"`
+	// Whether to enable queue metrics
+	// Default: true
+	EnableQueueMetrics bool `protobuf:"varint,8,opt,name=enable_queue_metrics,json=enableQueueMetrics,proto3" json:"enable_queue_metrics,omitempty"`
+	// Whether to enable error type metrics
+	// Default: true
+	EnableErrorTypeMetrics bool `protobuf:"varint,9,opt,name=enable_error_type_metrics,json=enableErrorTypeMetrics,proto3" json:"enable_error_type_metrics,omitempty"`
+	// Whether to emit request/response logs as legacy printf-style lines instead of structured fields
+	// Default: false (structured fields)
+	LegacyLogFormat bool `protobuf:"varint,10,opt,name=legacy_log_format,json=legacyLogFormat,proto3" json:"legacy_log_format,omitempty"`
+	// Reply body logging policy
+	// Default: type name only for every route
+	BodyLogging *BodyLoggingConfig `protobuf:"bytes,11,opt,name=body_logging,json=bodyLogging,proto3" json:"body_logging,omitempty"`
+	// Histogram bucket configuration for duration and size metrics
+	// Default: built-in buckets, classic histograms only
+	Histograms *HistogramConfig `protobuf:"bytes,12,opt,name=histograms,proto3" json:"histograms,omitempty"`
+	// OpenTelemetry metrics recording (exported via the application's OTLP MeterProvider)
+	// Default: disabled
+	OtelMetrics *OtelMetricsConfig `protobuf:"bytes,13,opt,name=otel_metrics,json=otelMetrics,proto3" json:"otel_metrics,omitempty"`
+	// Per-caller request metrics (caller service identified by header or mTLS identity)
+	// Default: disabled
+	CallerMetrics *CallerMetricsConfig `protobuf:"bytes,14,opt,name=caller_metrics,json=callerMetrics,proto3" json:"caller_metrics,omitempty"`
+	// Service level objectives with error-budget burn-rate tracking
+	// Default: disabled
+	Slo *SLOConfig `protobuf:"bytes,15,opt,name=slo,proto3" json:"slo,omitempty"`
+	// JSON stats endpoint for quick inspection without a Prometheus stack
+	// Default: disabled
+	StatsEndpoint *StatsEndpointConfig `protobuf:"bytes,16,opt,name=stats_endpoint,json=statsEndpoint,proto3" json:"stats_endpoint,omitempty"`
+	// Additional sinks that receive the request/response log records alongside the Lynx logger
+	// Default: none (Lynx logger only)
+	LogSinks []*LogSinkConfig `protobuf:"bytes,17,rep,name=log_sinks,json=logSinks,proto3" json:"log_sinks,omitempty"`
+	// Temporary verbose logging for selected operations or trace IDs
+	// Default: no boosts, admin endpoint disabled
+	LogBoost *LogBoostConfig `protobuf:"bytes,18,opt,name=log_boost,json=logBoost,proto3" json:"log_boost,omitempty"`
+	// Which request/response headers appear in request logs
+	// Default: every header, with credentials redacted
+	HeaderLogging *HeaderLoggingConfig `protobuf:"bytes,19,opt,name=header_logging,json=headerLogging,proto3" json:"header_logging,omitempty"`
+	// Server-Timing response headers with per-phase durations
+	// Default: disabled
+	ServerTiming *ServerTimingConfig `protobuf:"bytes,20,opt,name=server_timing,json=serverTiming,proto3" json:"server_timing,omitempty"`
+	// Per-request cost accounting from units recorded with AddRequestCost
+	// Default: disabled
+	RequestCost *RequestCostConfig `protobuf:"bytes,21,opt,name=request_cost,json=requestCost,proto3" json:"request_cost,omitempty"`
+	// Labels and logging of 404 and 405 requests, whose raw paths are unbounded
+	// Default: every unmatched path counted as "unmatched", every request logged
+	UnmatchedPaths *UnmatchedPathsConfig `protobuf:"bytes,22,opt,name=unmatched_paths,json=unmatchedPaths,proto3" json:"unmatched_paths,omitempty"`
+	// Operations or request paths excluded from request logging and metrics, e.g. probe routes such as
+	// "/healthz" or "/grpc.health.v1.Health/*"; a trailing "*" matches a prefix
+	// Default: empty
+	ExcludedRoutes []string `protobuf:"bytes,23,rep,name=excluded_routes,json=excludedRoutes,proto3" json:"excluded_routes,omitempty"`
+	// Tracking of in-flight requests for ActiveRequests and CancelRequest, with an optional admin endpoint
+	// Default: disabled
+	ActiveRequests *ActiveRequestsConfig `protobuf:"bytes,24,opt,name=active_requests,json=activeRequests,proto3" json:"active_requests,omitempty"`
+	// Capture of the wrapped cause chain of handler errors in error logs and, in internal mode, error responses
+	// Default: disabled
+	ErrorChain *ErrorChainConfig `protobuf:"bytes,25,opt,name=error_chain,json=errorChain,proto3" json:"error_chain,omitempty"`
+	// Classification of handler errors into client, auth, validation, dependency, internal and timeout
+	// Default: disabled
+	ErrorClasses *ErrorClassesConfig `protobuf:"bytes,26,opt,name=error_classes,json=errorClasses,proto3" json:"error_classes,omitempty"`
+	// Detection of request-rate and error-rate deviations from rolling per-operation baselines
+	// Default: disabled
+	Anomaly       *AnomalyConfig `protobuf:"bytes,27,opt,name=anomaly,proto3" json:"anomaly,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MonitoringConfig) Reset() {
+	*x = MonitoringConfig{}
+	mi := &file_http_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MonitoringConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MonitoringConfig) ProtoMessage() {}
+
+func (x *MonitoringConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MonitoringConfig.ProtoReflect.Descriptor instead.
+func (*MonitoringConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *MonitoringConfig) GetEnableMetrics() bool {
+	if x != nil {
+		return x.EnableMetrics
+	}
+	return false
+}
+
+func (x *MonitoringConfig) GetMetricsPath() string {
+	if x != nil {
+		return x.MetricsPath
+	}
+	return ""
+}
+
+func (x *MonitoringConfig) GetHealthPath() string {
+	if x != nil {
+		return x.HealthPath
+	}
+	return ""
+}
+
+func (x *MonitoringConfig) GetEnableRequestLogging() bool {
+	if x != nil {
+		return x.EnableRequestLogging
+	}
+	return false
+}
+
+func (x *MonitoringConfig) GetEnableErrorLogging() bool {
+	if x != nil {
+		return x.EnableErrorLogging
+	}
+	return false
+}
+
+func (x *MonitoringConfig) GetEnableRouteMetrics() bool {
+	if x != nil {
+		return x.EnableRouteMetrics
+	}
+	return false
+}
+
+func (x *MonitoringConfig) GetEnableConnectionMetrics() bool {
+	if x != nil {
+		return x.EnableConnectionMetrics
+	}
+	return false
+}
+
+func (x *MonitoringConfig) GetEnableQueueMetrics() bool {
+	if x != nil {
+		return x.EnableQueueMetrics
+	}
+	return false
+}
+
+func (x *MonitoringConfig) GetEnableErrorTypeMetrics() bool {
+	if x != nil {
+		return x.EnableErrorTypeMetrics
+	}
+	return false
+}
+
+func (x *MonitoringConfig) GetLegacyLogFormat() bool {
+	if x != nil {
+		return x.LegacyLogFormat
+	}
+	return false
+}
+
+func (x *MonitoringConfig) GetBodyLogging() *BodyLoggingConfig {
+	if x != nil {
+		return x.BodyLogging
+	}
+	return nil
+}
+
+func (x *MonitoringConfig) GetHistograms() *HistogramConfig {
+	if x != nil {
+		return x.Histograms
+	}
+	return nil
+}
+
+func (x *MonitoringConfig) GetOtelMetrics() *OtelMetricsConfig {
+	if x != nil {
+		return x.OtelMetrics
+	}
+	return nil
+}
+
+func (x *MonitoringConfig) GetCallerMetrics() *CallerMetricsConfig {
+	if x != nil {
+		return x.CallerMetrics
+	}
+	return nil
+}
+
+func (x *MonitoringConfig) GetSlo() *SLOConfig {
+	if x != nil {
+		return x.Slo
+	}
+	return nil
+}
+
+func (x *MonitoringConfig) GetStatsEndpoint() *StatsEndpointConfig {
+	if x != nil {
+		return x.StatsEndpoint
+	}
+	return nil
+}
+
+func (x *MonitoringConfig) GetLogSinks() []*LogSinkConfig {
+	if x != nil {
+		return x.LogSinks
+	}
+	return nil
+}
+
+func (x *MonitoringConfig) GetLogBoost() *LogBoostConfig {
+	if x != nil {
+		return x.LogBoost
+	}
+	return nil
+}
+
+func (x *MonitoringConfig) GetHeaderLogging() *HeaderLoggingConfig {
+	if x != nil {
+		return x.HeaderLogging
+	}
+	return nil
+}
+
+func (x *MonitoringConfig) GetServerTiming() *ServerTimingConfig {
+	if x != nil {
+		return x.ServerTiming
+	}
+	return nil
+}
+
+func (x *MonitoringConfig) GetRequestCost() *RequestCostConfig {
+	if x != nil {
+		return x.RequestCost
+	}
+	return nil
+}
+
+func (x *MonitoringConfig) GetUnmatchedPaths() *UnmatchedPathsConfig {
+	if x != nil {
+		return x.UnmatchedPaths
+	}
+	return nil
+}
+
+func (x *MonitoringConfig) GetExcludedRoutes() []string {
+	if x != nil {
+		return x.ExcludedRoutes
+	}
+	return nil
+}
+
+func (x *MonitoringConfig) GetActiveRequests() *ActiveRequestsConfig {
+	if x != nil {
+		return x.ActiveRequests
+	}
+	return nil
+}
+
+func (x *MonitoringConfig) GetErrorChain() *ErrorChainConfig {
+	if x != nil {
+		return x.ErrorChain
+	}
+	return nil
+}
+
+func (x *MonitoringConfig) GetErrorClasses() *ErrorClassesConfig {
+	if x != nil {
+		return x.ErrorClasses
+	}
+	return nil
+}
+
+func (x *MonitoringConfig) GetAnomaly() *AnomalyConfig {
+	if x != nil {
+		return x.Anomaly
+	}
+	return nil
+}
+
+// AnomalyConfig keeps a rolling baseline (exponentially weighted mean and variance) of the request count and the
+// error rate of each operation per interval. When a finished interval deviates from the baseline by more than
+// sigma standard deviations, a structured "[HTTP Anomaly]" warning is logged and
+// lynx_http_anomalies_total{route,signal,direction} is incremented. Errors are 5xx responses, or the alerting
+// classes when monitoring.error_classes is enabled. Intervals are closed by the next request of the operation,
+// so a drop to no traffic is reported when traffic resumes.
+type AnomalyConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Enable anomaly detection
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Length of one sample
+	// Default: 1m
+	Interval *durationpb.Duration `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	// Deviation from the baseline, in standard deviations, that is reported
+	// Default: 3
+	Sigma float64 `protobuf:"fixed64,3,opt,name=sigma,proto3" json:"sigma,omitempty"`
+	// Number of intervals the baseline effectively averages over
+	// Default: 30
+	BaselineIntervals int32 `protobuf:"varint,4,opt,name=baseline_intervals,json=baselineIntervals,proto3" json:"baseline_intervals,omitempty"`
+	// Intervals observed before an operation is evaluated
+	// Default: 10
+	MinSamples int32 `protobuf:"varint,5,opt,name=min_samples,json=minSamples,proto3" json:"min_samples,omitempty"`
+	// Requests an interval needs for its error rate to be evaluated
+	// Default: 20
+	MinRequests int32 `protobuf:"varint,6,opt,name=min_requests,json=minRequests,proto3" json:"min_requests,omitempty"`
+	// Operations or paths (wildcards allowed) to watch
+	// Default: all
+	Operations []string `protobuf:"bytes,7,rep,name=operations,proto3" json:"operations,omitempty"`
+	// Maximum number of tracked operations; further operations are not watched
+	// Default: 1000
+	MaxOperations int32 `protobuf:"varint,8,opt,name=max_operations,json=maxOperations,proto3" json:"max_operations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnomalyConfig) Reset() {
+	*x = AnomalyConfig{}
+	mi := &file_http_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnomalyConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnomalyConfig) ProtoMessage() {}
+
+func (x *AnomalyConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnomalyConfig.ProtoReflect.Descriptor instead.
+func (*AnomalyConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *AnomalyConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *AnomalyConfig) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+func (x *AnomalyConfig) GetSigma() float64 {
+	if x != nil {
+		return x.Sigma
+	}
+	return 0
+}
+
+func (x *AnomalyConfig) GetBaselineIntervals() int32 {
+	if x != nil {
+		return x.BaselineIntervals
+	}
+	return 0
+}
+
+func (x *AnomalyConfig) GetMinSamples() int32 {
+	if x != nil {
+		return x.MinSamples
+	}
+	return 0
+}
+
+func (x *AnomalyConfig) GetMinRequests() int32 {
+	if x != nil {
+		return x.MinRequests
+	}
+	return 0
+}
+
+func (x *AnomalyConfig) GetOperations() []string {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+func (x *AnomalyConfig) GetMaxOperations() int32 {
+	if x != nil {
+		return x.MaxOperations
+	}
+	return 0
+}
+
+// ErrorClassesConfig buckets handler errors by Kratos code and reason. Client-side classes (client, auth,
+// validation) are logged at warn instead of error, the class becomes the error_type label of
+// lynx_http_errors_total, and only alerting classes count against SLO availability.
+type ErrorClassesConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Enable error classification
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Class by Kratos reason, overriding the classification by code, e.g. {"USER_STORE_DOWN": "dependency"}
+	// Default: none
+	Reasons map[string]string `protobuf:"bytes,2,rep,name=reasons,proto3" json:"reasons,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Classes counted against SLO availability
+	// Default: ["internal", "dependency", "timeout"]
+	AlertClasses  []string `protobuf:"bytes,3,rep,name=alert_classes,json=alertClasses,proto3" json:"alert_classes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ErrorClassesConfig) Reset() {
+	*x = ErrorClassesConfig{}
+	mi := &file_http_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ErrorClassesConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorClassesConfig) ProtoMessage() {}
+
+func (x *ErrorClassesConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorClassesConfig.ProtoReflect.Descriptor instead.
+func (*ErrorClassesConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *ErrorClassesConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *ErrorClassesConfig) GetReasons() map[string]string {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+func (x *ErrorClassesConfig) GetAlertClasses() []string {
+	if x != nil {
+		return x.AlertClasses
+	}
+	return nil
+}
+
+// ErrorChainConfig records the full cause chain of handler errors, one entry per error reached through Unwrap,
+// with its type, message, Kratos code and reason, and its stack frames when it carries them (StackTrace(), as
+// github.com/pkg/errors provides). Without it only the top-level error and the root cause are logged.
+type ErrorChainConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Add the chain to error log records as "error_chain"
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Maximum number of chain entries
+	// Default: 16
+	MaxDepth int32 `protobuf:"varint,2,opt,name=max_depth,json=maxDepth,proto3" json:"max_depth,omitempty"`
+	// Maximum stack frames per entry
+	// Default: 8
+	MaxFrames int32 `protobuf:"varint,3,opt,name=max_frames,json=maxFrames,proto3" json:"max_frames,omitempty"`
+	// Internal mode: add the chain to error responses as "error_chain". Meant for services not exposed to
+	// the public; external responses stay code-only.
+	// Default: false
+	Expose        bool `protobuf:"varint,4,opt,name=expose,proto3" json:"expose,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ErrorChainConfig) Reset() {
+	*x = ErrorChainConfig{}
+	mi := &file_http_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ErrorChainConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorChainConfig) ProtoMessage() {}
+
+func (x *ErrorChainConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorChainConfig.ProtoReflect.Descriptor instead.
+func (*ErrorChainConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *ErrorChainConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *ErrorChainConfig) GetMaxDepth() int32 {
+	if x != nil {
+		return x.MaxDepth
+	}
+	return 0
+}
+
+func (x *ErrorChainConfig) GetMaxFrames() int32 {
+	if x != nil {
+		return x.MaxFrames
+	}
+	return 0
+}
+
+func (x *ErrorChainConfig) GetExpose() bool {
+	if x != nil {
+		return x.Expose
+	}
+	return false
+}
+
+// ActiveRequestsConfig tracks in-flight requests so operators can list them and cancel runaway ones.
+type ActiveRequestsConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to track in-flight requests
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Whether to serve the admin endpoint that lists (GET) and cancels (DELETE ?id=) requests
+	// Default: false
+	EndpointEnabled bool `protobuf:"varint,2,opt,name=endpoint_enabled,json=endpointEnabled,proto3" json:"endpoint_enabled,omitempty"`
+	// Admin endpoint path
+	// Default: "/debug/requests"
+	Path string `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	// Watchdog reporting requests that run far past their timeout, such as handlers stuck on a lock
+	// Default: disabled
+	Stuck         *StuckRequestsConfig `protobuf:"bytes,4,opt,name=stuck,proto3" json:"stuck,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ActiveRequestsConfig) Reset() {
+	*x = ActiveRequestsConfig{}
+	mi := &file_http_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActiveRequestsConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActiveRequestsConfig) ProtoMessage() {}
+
+func (x *ActiveRequestsConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActiveRequestsConfig.ProtoReflect.Descriptor instead.
+func (*ActiveRequestsConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *ActiveRequestsConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *ActiveRequestsConfig) GetEndpointEnabled() bool {
+	if x != nil {
+		return x.EndpointEnabled
+	}
+	return false
+}
+
+func (x *ActiveRequestsConfig) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ActiveRequestsConfig) GetStuck() *StuckRequestsConfig {
+	if x != nil {
+		return x.Stuck
+	}
+	return nil
+}
+
+// StuckRequestsConfig reports tracked requests running longer than timeout_multiplier times their timeout:
+// the context deadline, or the server timeout when the context has none. Each stuck request is logged once
+// with the stacks of the goroutines it started and counted in lynx_http_stuck_requests_total.
+type StuckRequestsConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to run the watchdog; requires active_requests.enabled
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Multiple of the request timeout after which a request is stuck
+	// Default: 2
+	TimeoutMultiplier float64 `protobuf:"fixed64,2,opt,name=timeout_multiplier,json=timeoutMultiplier,proto3" json:"timeout_multiplier,omitempty"`
+	// How often in-flight requests are checked
+	// Default: 5s
+	CheckInterval *durationpb.Duration `protobuf:"bytes,3,opt,name=check_interval,json=checkInterval,proto3" json:"check_interval,omitempty"`
+	// Whether to cancel stuck requests; they fail with REQUEST_STUCK once their handler observes it
+	// Default: false
+	Cancel bool `protobuf:"varint,4,opt,name=cancel,proto3" json:"cancel,omitempty"`
+	// Maximum bytes of goroutine stacks logged per stuck request
+	// Default: 64KB
+	MaxStackBytes uint32 `protobuf:"varint,5,opt,name=max_stack_bytes,json=maxStackBytes,proto3" json:"max_stack_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StuckRequestsConfig) Reset() {
+	*x = StuckRequestsConfig{}
+	mi := &file_http_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StuckRequestsConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StuckRequestsConfig) ProtoMessage() {}
+
+func (x *StuckRequestsConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StuckRequestsConfig.ProtoReflect.Descriptor instead.
+func (*StuckRequestsConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *StuckRequestsConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *StuckRequestsConfig) GetTimeoutMultiplier() float64 {
+	if x != nil {
+		return x.TimeoutMultiplier
+	}
+	return 0
+}
+
+func (x *StuckRequestsConfig) GetCheckInterval() *durationpb.Duration {
+	if x != nil {
+		return x.CheckInterval
+	}
+	return nil
+}
+
+func (x *StuckRequestsConfig) GetCancel() bool {
+	if x != nil {
+		return x.Cancel
+	}
+	return false
+}
+
+func (x *StuckRequestsConfig) GetMaxStackBytes() uint32 {
+	if x != nil {
+		return x.MaxStackBytes
+	}
+	return 0
+}
+
+// UnmatchedPathsConfig bounds the path label of 404 and 405 error metrics, which scanners would otherwise
+// explode with random paths. Raw paths appear only in the (sampled) warning logs.
+type UnmatchedPathsConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Distinct unmatched paths reported under their own path label; later ones are reported as "unmatched"
+	// Default: 0 (all reported as "unmatched")
+	TrackedPaths uint32 `protobuf:"varint,1,opt,name=tracked_paths,json=trackedPaths,proto3" json:"tracked_paths,omitempty"`
+	// Paths neither counted nor logged, e.g. scanner probes like "/wp-admin*" or "/.env"; a trailing "*"
+	// matches a prefix
+	// Default: empty
+	Suppress []string `protobuf:"bytes,2,rep,name=suppress,proto3" json:"suppress,omitempty"`
+	// Log one of every log_every unmatched requests
+	// Default: 1 (every request)
+	LogEvery      uint32 `protobuf:"varint,3,opt,name=log_every,json=logEvery,proto3" json:"log_every,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnmatchedPathsConfig) Reset() {
+	*x = UnmatchedPathsConfig{}
+	mi := &file_http_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnmatchedPathsConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnmatchedPathsConfig) ProtoMessage() {}
+
+func (x *UnmatchedPathsConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnmatchedPathsConfig.ProtoReflect.Descriptor instead.
+func (*UnmatchedPathsConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *UnmatchedPathsConfig) GetTrackedPaths() uint32 {
+	if x != nil {
+		return x.TrackedPaths
+	}
+	return 0
+}
+
+func (x *UnmatchedPathsConfig) GetSuppress() []string {
+	if x != nil {
+		return x.Suppress
+	}
+	return nil
+}
+
+func (x *UnmatchedPathsConfig) GetLogEvery() uint32 {
+	if x != nil {
+		return x.LogEvery
+	}
+	return 0
+}
+
+// Request cost configuration. Handlers record cost units (database queries, bytes scanned, CPU hints) with
+// AddRequestCost; the plugin sums them per request, counts them per route and unit, and optionally logs them
+// and returns them to the client.
+type RequestCostConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to account request costs
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Log the costs of each request that recorded any
+	// Default: false
+	LogCosts bool `protobuf:"varint,2,opt,name=log_costs,json=logCosts,proto3" json:"log_costs,omitempty"`
+	// Return X-Request-Cost (weighted total) and X-Request-Cost-Units (per unit) headers for quota-aware clients
+	// Default: false
+	ResponseHeaders bool `protobuf:"varint,3,opt,name=response_headers,json=responseHeaders,proto3" json:"response_headers,omitempty"`
+	// Weight of each unit in the total cost; unlisted units weigh 1
+	// Default: empty
+	Weights       map[string]float64 `protobuf:"bytes,4,rep,name=weights,proto3" json:"weights,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestCostConfig) Reset() {
+	*x = RequestCostConfig{}
+	mi := &file_http_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestCostConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestCostConfig) ProtoMessage() {}
+
+func (x *RequestCostConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestCostConfig.ProtoReflect.Descriptor instead.
+func (*RequestCostConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *RequestCostConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *RequestCostConfig) GetLogCosts() bool {
+	if x != nil {
+		return x.LogCosts
+	}
+	return false
+}
+
+func (x *RequestCostConfig) GetResponseHeaders() bool {
+	if x != nil {
+		return x.ResponseHeaders
+	}
+	return false
+}
+
+func (x *RequestCostConfig) GetWeights() map[string]float64 {
+	if x != nil {
+		return x.Weights
+	}
+	return nil
+}
+
+// Server-Timing configuration. Responses carry the handler, encode and total durations plus phases recorded
+// with RecordServerTiming, for browser devtools and RUM tooling.
+type ServerTimingConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to emit Server-Timing headers
+	// Default: false
+	Enabled       bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServerTimingConfig) Reset() {
+	*x = ServerTimingConfig{}
+	mi := &file_http_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerTimingConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerTimingConfig) ProtoMessage() {}
+
+func (x *ServerTimingConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerTimingConfig.ProtoReflect.Descriptor instead.
+func (*ServerTimingConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *ServerTimingConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+// Header logging configuration. Names are case-insensitive. Redaction applies after the allow and deny
+// lists, so an allowed credential header is still masked.
+type HeaderLoggingConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Headers to log; when set, all other headers are left out (e.g. ["User-Agent", "Content-Type", "X-Request-Id"])
+	// Default: empty (every header)
+	Allow []string `protobuf:"bytes,1,rep,name=allow,proto3" json:"allow,omitempty"`
+	// Headers never logged
+	// Default: empty
+	Deny []string `protobuf:"bytes,2,rep,name=deny,proto3" json:"deny,omitempty"`
+	// Headers logged as "<redacted>", in addition to Authorization, Cookie, Set-Cookie, X-Api-Key and X-Auth-Token
+	// Default: empty
+	Redact        []string `protobuf:"bytes,3,rep,name=redact,proto3" json:"redact,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeaderLoggingConfig) Reset() {
+	*x = HeaderLoggingConfig{}
+	mi := &file_http_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeaderLoggingConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeaderLoggingConfig) ProtoMessage() {}
+
+func (x *HeaderLoggingConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeaderLoggingConfig.ProtoReflect.Descriptor instead.
+func (*HeaderLoggingConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *HeaderLoggingConfig) GetAllow() []string {
+	if x != nil {
+		return x.Allow
+	}
+	return nil
+}
+
+func (x *HeaderLoggingConfig) GetDeny() []string {
+	if x != nil {
+		return x.Deny
+	}
+	return nil
+}
+
+func (x *HeaderLoggingConfig) GetRedact() []string {
+	if x != nil {
+		return x.Redact
+	}
+	return nil
+}
+
+// Log boost configuration. A boost logs matching requests in full (even with request logging disabled)
+// until it expires, then logging reverts on its own.
+type LogBoostConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to serve the admin endpoint that lists (GET), adds (POST) and clears (DELETE) boosts
+	// Default: false
+	EndpointEnabled bool `protobuf:"varint,1,opt,name=endpoint_enabled,json=endpointEnabled,proto3" json:"endpoint_enabled,omitempty"`
+	// Admin endpoint path
+	// Default: "/debug/log-boost"
+	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// Longest duration accepted for a boost
+	// Default: 1h
+	MaxDuration *durationpb.Duration `protobuf:"bytes,3,opt,name=max_duration,json=maxDuration,proto3" json:"max_duration,omitempty"`
+	// Boosts applied when the server starts
+	Boosts        []*LogBoostRule `protobuf:"bytes,4,rep,name=boosts,proto3" json:"boosts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogBoostConfig) Reset() {
+	*x = LogBoostConfig{}
+	mi := &file_http_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogBoostConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogBoostConfig) ProtoMessage() {}
+
+func (x *LogBoostConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogBoostConfig.ProtoReflect.Descriptor instead.
+func (*LogBoostConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *LogBoostConfig) GetEndpointEnabled() bool {
+	if x != nil {
+		return x.EndpointEnabled
+	}
+	return false
+}
+
+func (x *LogBoostConfig) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *LogBoostConfig) GetMaxDuration() *durationpb.Duration {
+	if x != nil {
+		return x.MaxDuration
+	}
+	return nil
+}
+
+func (x *LogBoostConfig) GetBoosts() []*LogBoostRule {
+	if x != nil {
+		return x.Boosts
+	}
+	return nil
+}
+
+// A single log boost. At least one of operation or trace_id is required; both must match when set.
+type LogBoostRule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Operation to boost; exact name or prefix ending in "*" (e.g. "/api.v1.Orders/*")
+	Operation string `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	// Trace ID to boost; exact ID or prefix ending in "*"
+	TraceId string `protobuf:"bytes,2,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	// Reply and request body policy for boosted requests: "type_name", "off", "truncated" or "json"
+	// Default: "json"
+	ReplyPolicy string `protobuf:"bytes,3,opt,name=reply_policy,json=replyPolicy,proto3" json:"reply_policy,omitempty"`
+	// Level of boosted request/response records; failures stay at error
+	// Default: "info"
+	Level string `protobuf:"bytes,4,opt,name=level,proto3" json:"level,omitempty"`
+	// How long the boost stays active
+	// Default: 10m
+	Duration      *durationpb.Duration `protobuf:"bytes,5,opt,name=duration,proto3" json:"duration,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogBoostRule) Reset() {
+	*x = LogBoostRule{}
+	mi := &file_http_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogBoostRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogBoostRule) ProtoMessage() {}
+
+func (x *LogBoostRule) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogBoostRule.ProtoReflect.Descriptor instead.
+func (*LogBoostRule) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *LogBoostRule) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *LogBoostRule) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+func (x *LogBoostRule) GetReplyPolicy() string {
+	if x != nil {
+		return x.ReplyPolicy
+	}
+	return ""
+}
+
+func (x *LogBoostRule) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *LogBoostRule) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+// Request log sink configuration. Sinks other than "lynx" are registered by the application with
+// ServiceHttp.RegisterLogSink (e.g. an OTLP log exporter or a Kafka producer).
+type LogSinkConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Sink name; "lynx" configures the built-in Lynx logger
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Minimum level forwarded to the sink: "debug", "info", "warn" or "error"
+	// Default: "info"
+	Level string `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`
+	// Fraction of records below warn level forwarded to the sink, between 0 and 1; warnings and errors
+	// are always forwarded
+	// Default: 1 (every record)
+	SampleRate    float64 `protobuf:"fixed64,3,opt,name=sample_rate,json=sampleRate,proto3" json:"sample_rate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogSinkConfig) Reset() {
+	*x = LogSinkConfig{}
+	mi := &file_http_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogSinkConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogSinkConfig) ProtoMessage() {}
+
+func (x *LogSinkConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogSinkConfig.ProtoReflect.Descriptor instead.
+func (*LogSinkConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *LogSinkConfig) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LogSinkConfig) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *LogSinkConfig) GetSampleRate() float64 {
+	if x != nil {
+		return x.SampleRate
+	}
+	return 0
+}
+
+// Stats endpoint configuration. Stats are kept in memory over a sliding window.
+type StatsEndpointConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to serve the stats endpoint
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Endpoint path
+	// Default: "/debug/stats"
+	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// Sliding window for QPS, latency percentiles and slow operations (1s to 10m)
+	// Default: 60s
+	Window *durationpb.Duration `protobuf:"bytes,3,opt,name=window,proto3" json:"window,omitempty"`
+	// Number of slowest operations (by average latency) to report
+	// Default: 10
+	TopN uint32 `protobuf:"varint,4,opt,name=top_n,json=topN,proto3" json:"top_n,omitempty"`
+	// Top-K heaviest client IPs, identities and operations
+	// Default: disabled
+	HeavyHitters  *HeavyHittersConfig `protobuf:"bytes,5,opt,name=heavy_hitters,json=heavyHitters,proto3" json:"heavy_hitters,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatsEndpointConfig) Reset() {
+	*x = StatsEndpointConfig{}
+	mi := &file_http_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsEndpointConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsEndpointConfig) ProtoMessage() {}
+
+func (x *StatsEndpointConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsEndpointConfig.ProtoReflect.Descriptor instead.
+func (*StatsEndpointConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *StatsEndpointConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *StatsEndpointConfig) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *StatsEndpointConfig) GetWindow() *durationpb.Duration {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+func (x *StatsEndpointConfig) GetTopN() uint32 {
+	if x != nil {
+		return x.TopN
+	}
+	return 0
+}
+
+func (x *StatsEndpointConfig) GetHeavyHitters() *HeavyHittersConfig {
+	if x != nil {
+		return x.HeavyHitters
+	}
+	return nil
+}
+
+// HeavyHittersConfig tracks the clients, identities and operations with the most requests in count-min
+// sketches, whose memory does not grow with the number of distinct keys, and reports the top_k of each under
+// "heavy_hitters" in the stats report. Counts cover the current and the previous stats window and may
+// overestimate by a small fraction of the total. The identity is the "sub" claim set with WithAuthClaims before
+// the middleware chain, otherwise the identity_header value.
+type HeavyHittersConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to track heavy hitters
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Number of keys reported per dimension (at most 1000)
+	// Default: 10
+	TopK uint32 `protobuf:"varint,2,opt,name=top_k,json=topK,proto3" json:"top_k,omitempty"`
+	// Counters per sketch row; wider sketches overestimate less (at most 1048576)
+	// Default: 2048
+	Width uint32 `protobuf:"varint,3,opt,name=width,proto3" json:"width,omitempty"`
+	// Sketch rows; deeper sketches overestimate less often (at most 16)
+	// Default: 4
+	Depth uint32 `protobuf:"varint,4,opt,name=depth,proto3" json:"depth,omitempty"`
+	// Header identifying the caller when no auth claims are set; do not use a credential header
+	// Default: "X-Caller-Service"
+	IdentityHeader string `protobuf:"bytes,5,opt,name=identity_header,json=identityHeader,proto3" json:"identity_header,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *HeavyHittersConfig) Reset() {
+	*x = HeavyHittersConfig{}
+	mi := &file_http_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeavyHittersConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeavyHittersConfig) ProtoMessage() {}
+
+func (x *HeavyHittersConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeavyHittersConfig.ProtoReflect.Descriptor instead.
+func (*HeavyHittersConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *HeavyHittersConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *HeavyHittersConfig) GetTopK() uint32 {
+	if x != nil {
+		return x.TopK
+	}
+	return 0
+}
+
+func (x *HeavyHittersConfig) GetWidth() uint32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *HeavyHittersConfig) GetDepth() uint32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+func (x *HeavyHittersConfig) GetIdentityHeader() string {
+	if x != nil {
+		return x.IdentityHeader
+	}
+	return ""
+}
+
+// SLO configuration. Each objective tracks good/bad requests for an operation over the
+// configured burn windows and exposes lynx_http_slo_* gauges.
+type SLOConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to track SLO burn rates
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Objectives, one per operation
+	Objectives []*SLOObjective `protobuf:"bytes,2,rep,name=objectives,proto3" json:"objectives,omitempty"`
+	// Burn-rate windows and their alert thresholds
+	// Default: 1h at 14.4 and 6h at 6
+	BurnWindows []*SLOBurnWindow `protobuf:"bytes,3,rep,name=burn_windows,json=burnWindows,proto3" json:"burn_windows,omitempty"`
+	// How often burn rates are recomputed from request traffic
+	// Default: 10s
+	EvaluationInterval *durationpb.Duration `protobuf:"bytes,4,opt,name=evaluation_interval,json=evaluationInterval,proto3" json:"evaluation_interval,omitempty"`
+	// Whether to log a warning when a window's burn rate exceeds its alert threshold
+	// Default: false
+	LogAlerts     bool `protobuf:"varint,5,opt,name=log_alerts,json=logAlerts,proto3" json:"log_alerts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SLOConfig) Reset() {
+	*x = SLOConfig{}
+	mi := &file_http_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SLOConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SLOConfig) ProtoMessage() {}
+
+func (x *SLOConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SLOConfig.ProtoReflect.Descriptor instead.
+func (*SLOConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *SLOConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *SLOConfig) GetObjectives() []*SLOObjective {
+	if x != nil {
+		return x.Objectives
+	}
+	return nil
+}
+
+func (x *SLOConfig) GetBurnWindows() []*SLOBurnWindow {
+	if x != nil {
+		return x.BurnWindows
+	}
+	return nil
+}
+
+func (x *SLOConfig) GetEvaluationInterval() *durationpb.Duration {
+	if x != nil {
+		return x.EvaluationInterval
+	}
+	return nil
+}
+
+func (x *SLOConfig) GetLogAlerts() bool {
+	if x != nil {
+		return x.LogAlerts
+	}
+	return false
+}
+
+// A single service level objective.
+type SLOObjective struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Operation the objective applies to (e.g. "/api.v1.Users/Get"); "*" matches operations
+	// without a dedicated objective
+	Operation string `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	// Fraction of requests that must not fail with a server error (e.g. 0.999); 0 disables
+	AvailabilityTarget float64 `protobuf:"fixed64,2,opt,name=availability_target,json=availabilityTarget,proto3" json:"availability_target,omitempty"`
+	// Requests slower than this count against the latency objective
+	LatencyThreshold *durationpb.Duration `protobuf:"bytes,3,opt,name=latency_threshold,json=latencyThreshold,proto3" json:"latency_threshold,omitempty"`
+	// Fraction of requests that must complete within latency_threshold (e.g. 0.99); 0 disables
+	LatencyTarget float64 `protobuf:"fixed64,4,opt,name=latency_target,json=latencyTarget,proto3" json:"latency_target,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SLOObjective) Reset() {
+	*x = SLOObjective{}
+	mi := &file_http_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SLOObjective) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SLOObjective) ProtoMessage() {}
+
+func (x *SLOObjective) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SLOObjective.ProtoReflect.Descriptor instead.
+func (*SLOObjective) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *SLOObjective) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *SLOObjective) GetAvailabilityTarget() float64 {
+	if x != nil {
+		return x.AvailabilityTarget
+	}
+	return 0
+}
+
+func (x *SLOObjective) GetLatencyThreshold() *durationpb.Duration {
+	if x != nil {
+		return x.LatencyThreshold
+	}
+	return nil
+}
+
+func (x *SLOObjective) GetLatencyTarget() float64 {
+	if x != nil {
+		return x.LatencyTarget
+	}
+	return 0
+}
+
+// A burn-rate evaluation window.
+type SLOBurnWindow struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Window length, between 1m and 24h
+	Window *durationpb.Duration `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"`
+	// Burn rate above which an alert is logged
+	AlertThreshold float64 `protobuf:"fixed64,2,opt,name=alert_threshold,json=alertThreshold,proto3" json:"alert_threshold,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SLOBurnWindow) Reset() {
+	*x = SLOBurnWindow{}
+	mi := &file_http_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SLOBurnWindow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SLOBurnWindow) ProtoMessage() {}
+
+func (x *SLOBurnWindow) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SLOBurnWindow.ProtoReflect.Descriptor instead.
+func (*SLOBurnWindow) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *SLOBurnWindow) GetWindow() *durationpb.Duration {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+func (x *SLOBurnWindow) GetAlertThreshold() float64 {
+	if x != nil {
+		return x.AlertThreshold
+	}
+	return 0
+}
+
+// Caller metrics configuration. The caller label is capped so an unbounded set of client
+// identities cannot explode metric cardinality.
+type CallerMetricsConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to record lynx_http_caller_* metrics
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Request header carrying the caller service name
+	// Default: "X-Caller-Service"
+	Header string `protobuf:"bytes,2,opt,name=header,proto3" json:"header,omitempty"`
+	// Whether to fall back to the mTLS client certificate common name when the header is absent
+	// Default: false
+	UseTlsIdentity bool `protobuf:"varint,3,opt,name=use_tls_identity,json=useTlsIdentity,proto3" json:"use_tls_identity,omitempty"`
+	// Maximum number of distinct caller label values; further callers are reported as "other"
+	// Default: 100
+	MaxCallers uint32 `protobuf:"varint,4,opt,name=max_callers,json=maxCallers,proto3" json:"max_callers,omitempty"`
+	// Callers reported under their own name; when set, every other caller is reported as "other"
+	// Default: empty (any caller, up to max_callers)
+	AllowedCallers []string `protobuf:"bytes,5,rep,name=allowed_callers,json=allowedCallers,proto3" json:"allowed_callers,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CallerMetricsConfig) Reset() {
+	*x = CallerMetricsConfig{}
+	mi := &file_http_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CallerMetricsConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CallerMetricsConfig) ProtoMessage() {}
+
+func (x *CallerMetricsConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CallerMetricsConfig.ProtoReflect.Descriptor instead.
+func (*CallerMetricsConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *CallerMetricsConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *CallerMetricsConfig) GetHeader() string {
+	if x != nil {
+		return x.Header
+	}
+	return ""
+}
+
+func (x *CallerMetricsConfig) GetUseTlsIdentity() bool {
+	if x != nil {
+		return x.UseTlsIdentity
+	}
+	return false
+}
+
+func (x *CallerMetricsConfig) GetMaxCallers() uint32 {
+	if x != nil {
+		return x.MaxCallers
+	}
+	return 0
+}
+
+func (x *CallerMetricsConfig) GetAllowedCallers() []string {
+	if x != nil {
+		return x.AllowedCallers
+	}
+	return nil
+}
+
+// OpenTelemetry metrics configuration. Instruments mirror the Prometheus request metrics and
+// are recorded through the global (or injected) MeterProvider, which owns the OTLP exporter.
+type OtelMetricsConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to record request metrics through the OpenTelemetry metrics API
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Instrumentation scope (meter) name
+	// Default: "github.com/go-lynx/lynx-http"
+	MeterName string `protobuf:"bytes,2,opt,name=meter_name,json=meterName,proto3" json:"meter_name,omitempty"`
+	// Whether to stop recording the Prometheus request metrics when OpenTelemetry is enabled
+	// Default: false (record both)
+	DisablePrometheus bool `protobuf:"varint,3,opt,name=disable_prometheus,json=disablePrometheus,proto3" json:"disable_prometheus,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *OtelMetricsConfig) Reset() {
+	*x = OtelMetricsConfig{}
+	mi := &file_http_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OtelMetricsConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OtelMetricsConfig) ProtoMessage() {}
+
+func (x *OtelMetricsConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OtelMetricsConfig.ProtoReflect.Descriptor instead.
+func (*OtelMetricsConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *OtelMetricsConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *OtelMetricsConfig) GetMeterName() string {
+	if x != nil {
+		return x.MeterName
+	}
+	return ""
+}
+
+func (x *OtelMetricsConfig) GetDisablePrometheus() bool {
+	if x != nil {
+		return x.DisablePrometheus
+	}
+	return false
+}
+
+// Histogram configuration. Metrics are registered once per process, so bucket
+// changes take effect on the next process start rather than on Configure.
+type HistogramConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Request duration buckets in seconds (also used for route duration)
+	// Default: [0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5]
+	DurationBuckets []float64 `protobuf:"fixed64,1,rep,packed,name=duration_buckets,json=durationBuckets,proto3" json:"duration_buckets,omitempty"`
+	// Request size buckets in bytes
+	// Default: exponential 100 * 10^n, 8 buckets
+	RequestSizeBuckets []float64 `protobuf:"fixed64,2,rep,packed,name=request_size_buckets,json=requestSizeBuckets,proto3" json:"request_size_buckets,omitempty"`
+	// Response size buckets in bytes
+	// Default: exponential 100 * 10^n, 8 buckets
+	ResponseSizeBuckets []float64 `protobuf:"fixed64,3,rep,packed,name=response_size_buckets,json=responseSizeBuckets,proto3" json:"response_size_buckets,omitempty"`
+	// Whether to additionally expose Prometheus native (sparse) histograms
+	// Default: false
+	NativeHistograms bool `protobuf:"varint,4,opt,name=native_histograms,json=nativeHistograms,proto3" json:"native_histograms,omitempty"`
+	// Native histogram bucket growth factor (must be > 1)
+	// Default: 1.1
+	NativeBucketFactor float64 `protobuf:"fixed64,5,opt,name=native_bucket_factor,json=nativeBucketFactor,proto3" json:"native_bucket_factor,omitempty"`
+	// Maximum number of native histogram buckets before resolution is reduced
+	// Default: 160
+	NativeMaxBucketNumber uint32 `protobuf:"varint,6,opt,name=native_max_bucket_number,json=nativeMaxBucketNumber,proto3" json:"native_max_bucket_number,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *HistogramConfig) Reset() {
+	*x = HistogramConfig{}
+	mi := &file_http_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistogramConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistogramConfig) ProtoMessage() {}
+
+func (x *HistogramConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistogramConfig.ProtoReflect.Descriptor instead.
+func (*HistogramConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *HistogramConfig) GetDurationBuckets() []float64 {
+	if x != nil {
+		return x.DurationBuckets
+	}
+	return nil
+}
+
+func (x *HistogramConfig) GetRequestSizeBuckets() []float64 {
+	if x != nil {
+		return x.RequestSizeBuckets
+	}
+	return nil
+}
+
+func (x *HistogramConfig) GetResponseSizeBuckets() []float64 {
+	if x != nil {
+		return x.ResponseSizeBuckets
+	}
+	return nil
+}
+
+func (x *HistogramConfig) GetNativeHistograms() bool {
+	if x != nil {
+		return x.NativeHistograms
+	}
+	return false
+}
+
+func (x *HistogramConfig) GetNativeBucketFactor() float64 {
+	if x != nil {
+		return x.NativeBucketFactor
+	}
+	return 0
+}
+
+func (x *HistogramConfig) GetNativeMaxBucketNumber() uint32 {
+	if x != nil {
+		return x.NativeMaxBucketNumber
+	}
+	return 0
+}
+
+// Reply body logging configuration
+type BodyLoggingConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Policy applied to logged replies: "type_name", "off", "truncated" or "json"
+	// Default: "type_name"
+	ReplyPolicy string `protobuf:"bytes,1,opt,name=reply_policy,json=replyPolicy,proto3" json:"reply_policy,omitempty"`
+	// Per-route policy overrides keyed by operation (e.g. "/api.v1.Users/Get")
+	// Default: empty
+	RouteReplyPolicies map[string]string `protobuf:"bytes,2,rep,name=route_reply_policies,json=routeReplyPolicies,proto3" json:"route_reply_policies,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Use proto field names instead of lowerCamelCase JSON names when logging proto replies as JSON
+	// Default: false
+	UseProtoNames bool `protobuf:"varint,3,opt,name=use_proto_names,json=useProtoNames,proto3" json:"use_proto_names,omitempty"`
+	// Emit unpopulated fields when logging proto replies as JSON
+	// Default: false
+	EmitUnpopulated bool `protobuf:"varint,4,opt,name=emit_unpopulated,json=emitUnpopulated,proto3" json:"emit_unpopulated,omitempty"`
+	// Emit enum values as numbers when logging proto replies as JSON
+	// Default: false
+	UseEnumNumbers bool `protobuf:"varint,5,opt,name=use_enum_numbers,json=useEnumNumbers,proto3" json:"use_enum_numbers,omitempty"`
+	// Maximum rendered reply body size in bytes before truncation applies
+	// Default: 1048576 (1MB)
+	MaxBodySize int32 `protobuf:"varint,6,opt,name=max_body_size,json=maxBodySize,proto3" json:"max_body_size,omitempty"`
+	// Truncation strategy for oversized bodies: "cut" (hard cut with ellipsis),
+	// "json_prefix" (cut at a value boundary and close open JSON brackets) or "hash" (sha256 summary only)
+	// Default: "cut"
+	Truncation    string `protobuf:"bytes,7,opt,name=truncation,proto3" json:"truncation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BodyLoggingConfig) Reset() {
+	*x = BodyLoggingConfig{}
+	mi := &file_http_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BodyLoggingConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BodyLoggingConfig) ProtoMessage() {}
+
+func (x *BodyLoggingConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BodyLoggingConfig.ProtoReflect.Descriptor instead.
+func (*BodyLoggingConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *BodyLoggingConfig) GetReplyPolicy() string {
+	if x != nil {
+		return x.ReplyPolicy
+	}
+	return ""
+}
+
+func (x *BodyLoggingConfig) GetRouteReplyPolicies() map[string]string {
+	if x != nil {
+		return x.RouteReplyPolicies
+	}
+	return nil
+}
+
+func (x *BodyLoggingConfig) GetUseProtoNames() bool {
+	if x != nil {
+		return x.UseProtoNames
+	}
+	return false
+}
+
+func (x *BodyLoggingConfig) GetEmitUnpopulated() bool {
+	if x != nil {
+		return x.EmitUnpopulated
+	}
+	return false
+}
+
+func (x *BodyLoggingConfig) GetUseEnumNumbers() bool {
+	if x != nil {
+		return x.UseEnumNumbers
+	}
+	return false
+}
+
+func (x *BodyLoggingConfig) GetMaxBodySize() int32 {
+	if x != nil {
+		return x.MaxBodySize
+	}
+	return 0
+}
+
+func (x *BodyLoggingConfig) GetTruncation() string {
+	if x != nil {
+		return x.Truncation
+	}
+	return ""
+}
+
+// Security configuration
+type SecurityConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// CORS configuration
+	// Default: CORS disabled
+	Cors *CorsConfig `protobuf:"bytes,1,opt,name=cors,proto3" json:"cors,omitempty"`
+	// Request size limit in bytes
+	// Default: 10MB (10485760 bytes)
+	MaxRequestSize int64 `protobuf:"varint,2,opt,name=max_request_size,json=maxRequestSize,proto3" json:"max_request_size,omitempty"`
+	// Rate limiting configuration
+	// Default: 100 req/s, burst 200
+	RateLimit *RateLimitConfig `protobuf:"bytes,3,opt,name=rate_limit,json=rateLimit,proto3" json:"rate_limit,omitempty"`
+	// Security headers configuration
+	// Default: security headers disabled
+	SecurityHeaders *SecurityHeadersConfig `protobuf:"bytes,4,opt,name=security_headers,json=securityHeaders,proto3" json:"security_headers,omitempty"`
+	// Slowloris / slow-body protection
+	// Default: disabled
+	SlowClientProtection *SlowClientProtectionConfig `protobuf:"bytes,5,opt,name=slow_client_protection,json=slowClientProtection,proto3" json:"slow_client_protection,omitempty"`
+	// Request fingerprints for abuse detection and per-fingerprint rate limits
+	// Default: disabled
+	Fingerprint *FingerprintConfig `protobuf:"bytes,6,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"`
+	// Decoy routes that flag the clients probing them
+	// Default: disabled
+	Honeypot      *HoneypotConfig `protobuf:"bytes,7,opt,name=honeypot,proto3" json:"honeypot,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SecurityConfig) Reset() {
+	*x = SecurityConfig{}
+	mi := &file_http_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SecurityConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecurityConfig) ProtoMessage() {}
+
+func (x *SecurityConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecurityConfig.ProtoReflect.Descriptor instead.
+func (*SecurityConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *SecurityConfig) GetCors() *CorsConfig {
+	if x != nil {
+		return x.Cors
+	}
+	return nil
+}
+
+func (x *SecurityConfig) GetMaxRequestSize() int64 {
+	if x != nil {
+		return x.MaxRequestSize
+	}
+	return 0
+}
+
+func (x *SecurityConfig) GetRateLimit() *RateLimitConfig {
+	if x != nil {
+		return x.RateLimit
+	}
+	return nil
+}
+
+func (x *SecurityConfig) GetSecurityHeaders() *SecurityHeadersConfig {
+	if x != nil {
+		return x.SecurityHeaders
+	}
+	return nil
+}
+
+func (x *SecurityConfig) GetSlowClientProtection() *SlowClientProtectionConfig {
+	if x != nil {
+		return x.SlowClientProtection
+	}
+	return nil
+}
+
+func (x *SecurityConfig) GetFingerprint() *FingerprintConfig {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return nil
+}
+
+func (x *SecurityConfig) GetHoneypot() *HoneypotConfig {
+	if x != nil {
+		return x.Honeypot
+	}
+	return nil
+}
+
+// HoneypotConfig serves decoy routes, such as a fake /admin/login or /api/v1/users/export, before routing and
+// middleware. They always answer 200 with the configured fake body, and flag the caller's IP and fingerprint (with
+// security.fingerprint) for flag_duration. Hits are logged as "[HTTP Honeypot]" warnings and counted in
+// lynx_http_honeypot_hits_total{route}. With action "monitor" flagged callers are served normally, marked in
+// request logs and in HoneypotFlaggedFromContext; with "block" their requests are answered 403, and with
+// slow_client_protection enabled their IP is also banned there, so new connections are refused.
+type HoneypotConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to serve honeypot routes
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Decoy routes
+	// Default: none
+	Routes []*HoneypotRoute `protobuf:"bytes,2,rep,name=routes,proto3" json:"routes,omitempty"`
+	// What happens to flagged callers: "monitor" or "block"
+	// Default: "monitor"
+	Action string `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	// How long a caller stays flagged
+	// Default: 1h
+	FlagDuration *durationpb.Duration `protobuf:"bytes,4,opt,name=flag_duration,json=flagDuration,proto3" json:"flag_duration,omitempty"`
+	// Maximum number of flagged IPs and fingerprints; further callers are not flagged while it is reached
+	// Default: 10000
+	MaxFlagged int32 `protobuf:"varint,5,opt,name=max_flagged,json=maxFlagged,proto3" json:"max_flagged,omitempty"`
+	// Reverse proxy networks (CIDR) whose X-Forwarded-For and X-Real-IP headers are trusted for the flagged IP.
+	// Trusted proxies are never banned.
+	// Default: none (the PROXY protocol address or the peer address)
+	TrustedProxies []string `protobuf:"bytes,6,rep,name=trusted_proxies,json=trustedProxies,proto3" json:"trusted_proxies,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *HoneypotConfig) Reset() {
+	*x = HoneypotConfig{}
+	mi := &file_http_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HoneypotConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HoneypotConfig) ProtoMessage() {}
+
+func (x *HoneypotConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HoneypotConfig.ProtoReflect.Descriptor instead.
+func (*HoneypotConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *HoneypotConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *HoneypotConfig) GetRoutes() []*HoneypotRoute {
+	if x != nil {
+		return x.Routes
+	}
+	return nil
+}
+
+func (x *HoneypotConfig) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *HoneypotConfig) GetFlagDuration() *durationpb.Duration {
+	if x != nil {
+		return x.FlagDuration
+	}
+	return nil
+}
+
+func (x *HoneypotConfig) GetMaxFlagged() int32 {
+	if x != nil {
+		return x.MaxFlagged
+	}
+	return 0
+}
+
+func (x *HoneypotConfig) GetTrustedProxies() []string {
+	if x != nil {
+		return x.TrustedProxies
+	}
+	return nil
+}
+
+// HoneypotRoute is one decoy route.
+type HoneypotRoute struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Request path; a trailing "*" matches a prefix
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// Methods the decoy answers
+	// Default: all
+	Methods []string `protobuf:"bytes,2,rep,name=methods,proto3" json:"methods,omitempty"`
+	// Fake response body
+	// Default: {"code":200,"data":{}}
+	Body string `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	// Content-Type of the body
+	// Default: "application/json"
+	ContentType   string `protobuf:"bytes,4,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HoneypotRoute) Reset() {
+	*x = HoneypotRoute{}
+	mi := &file_http_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HoneypotRoute) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HoneypotRoute) ProtoMessage() {}
+
+func (x *HoneypotRoute) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HoneypotRoute.ProtoReflect.Descriptor instead.
+func (*HoneypotRoute) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *HoneypotRoute) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *HoneypotRoute) GetMethods() []string {
+	if x != nil {
+		return x.Methods
+	}
+	return nil
+}
+
+func (x *HoneypotRoute) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *HoneypotRoute) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+// FingerprintConfig derives a stable fingerprint of each request's client from the selected components: "ip"
+// (the client IP), "user_agent", "header_shape" (the sorted names of the request headers, without request-specific
+// ones such as Content-Type, Cookie or Authorization) and "ja3" (the JA3 hash of the TLS ClientHello, on TLS
+// listeners). The fingerprint is available with FingerprintFromContext and logged as "fingerprint" in request logs.
+type FingerprintConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to fingerprint requests
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Components of the fingerprint: "ip", "user_agent", "header_shape", "ja3"
+	// Default: all
+	Components []string `protobuf:"bytes,2,rep,name=components,proto3" json:"components,omitempty"`
+	// Further header names left out of header_shape
+	// Default: none
+	IgnoreHeaders []string `protobuf:"bytes,3,rep,name=ignore_headers,json=ignoreHeaders,proto3" json:"ignore_headers,omitempty"`
+	// Per-fingerprint rate limit
+	// Default: disabled
+	RateLimit *FingerprintRateLimitConfig `protobuf:"bytes,4,opt,name=rate_limit,json=rateLimit,proto3" json:"rate_limit,omitempty"`
+	// Reverse proxy networks (CIDR) whose X-Forwarded-For and X-Real-IP headers are trusted for the "ip" component.
+	// Default: none (the PROXY protocol address or the peer address)
+	TrustedProxies []string `protobuf:"bytes,5,rep,name=trusted_proxies,json=trustedProxies,proto3" json:"trusted_proxies,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *FingerprintConfig) Reset() {
+	*x = FingerprintConfig{}
+	mi := &file_http_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FingerprintConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FingerprintConfig) ProtoMessage() {}
+
+func (x *FingerprintConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FingerprintConfig.ProtoReflect.Descriptor instead.
+func (*FingerprintConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *FingerprintConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *FingerprintConfig) GetComponents() []string {
+	if x != nil {
+		return x.Components
+	}
+	return nil
+}
+
+func (x *FingerprintConfig) GetIgnoreHeaders() []string {
+	if x != nil {
+		return x.IgnoreHeaders
+	}
+	return nil
+}
+
+func (x *FingerprintConfig) GetRateLimit() *FingerprintRateLimitConfig {
+	if x != nil {
+		return x.RateLimit
+	}
+	return nil
+}
+
+func (x *FingerprintConfig) GetTrustedProxies() []string {
+	if x != nil {
+		return x.TrustedProxies
+	}
+	return nil
+}
+
+// FingerprintRateLimitConfig limits the request rate of each fingerprint with a token bucket. Requests over the
+// limit are rejected with 429 FINGERPRINT_RATE_LIMITED.
+type FingerprintRateLimitConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to limit the rate per fingerprint
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Requests per second per fingerprint
+	// Default: 10
+	RatePerSecond float64 `protobuf:"fixed64,2,opt,name=rate_per_second,json=ratePerSecond,proto3" json:"rate_per_second,omitempty"`
+	// Burst per fingerprint
+	// Default: 20
+	Burst int32 `protobuf:"varint,3,opt,name=burst,proto3" json:"burst,omitempty"`
+	// Maximum number of tracked fingerprints; the least recently seen are forgotten beyond it
+	// Default: 10000
+	MaxTracked    int32 `protobuf:"varint,4,opt,name=max_tracked,json=maxTracked,proto3" json:"max_tracked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FingerprintRateLimitConfig) Reset() {
+	*x = FingerprintRateLimitConfig{}
+	mi := &file_http_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FingerprintRateLimitConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FingerprintRateLimitConfig) ProtoMessage() {}
+
+func (x *FingerprintRateLimitConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FingerprintRateLimitConfig.ProtoReflect.Descriptor instead.
+func (*FingerprintRateLimitConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *FingerprintRateLimitConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *FingerprintRateLimitConfig) GetRatePerSecond() float64 {
+	if x != nil {
+		return x.RatePerSecond
+	}
+	return 0
+}
+
+func (x *FingerprintRateLimitConfig) GetBurst() int32 {
+	if x != nil {
+		return x.Burst
+	}
+	return 0
+}
+
+func (x *FingerprintRateLimitConfig) GetMaxTracked() int32 {
+	if x != nil {
+		return x.MaxTracked
+	}
+	return 0
+}
+
+// Slow client protection configuration. Header read time is bounded by performance.read_header_timeout;
+// clients that repeatedly hit it, stall request bodies or exceed the per-IP connection cap are banned
+// temporarily.
+type SlowClientProtectionConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to enable slow client protection
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Maximum concurrent connections per client IP; 0 disables the cap
+	// Default: 100
+	MaxConnsPerIp uint32 `protobuf:"varint,2,opt,name=max_conns_per_ip,json=maxConnsPerIp,proto3" json:"max_conns_per_ip,omitempty"`
+	// Maximum time to read a request body
+	// Default: 30s
+	BodyReadTimeout *durationpb.Duration `protobuf:"bytes,3,opt,name=body_read_timeout,json=bodyReadTimeout,proto3" json:"body_read_timeout,omitempty"`
+	// Minimum average body upload rate in bytes per second once body_rate_grace has passed; 0 disables
+	// Default: 0
+	MinBodyRate int64 `protobuf:"varint,4,opt,name=min_body_rate,json=minBodyRate,proto3" json:"min_body_rate,omitempty"`
+	// Time before min_body_rate is enforced
+	// Default: 5s
+	BodyRateGrace *durationpb.Duration `protobuf:"bytes,5,opt,name=body_rate_grace,json=bodyRateGrace,proto3" json:"body_rate_grace,omitempty"`
+	// Violations within ban_window that trigger a ban
+	// Default: 10
+	BanThreshold uint32 `protobuf:"varint,6,opt,name=ban_threshold,json=banThreshold,proto3" json:"ban_threshold,omitempty"`
+	// Window in which violations are counted
+	// Default: 1m
+	BanWindow *durationpb.Duration `protobuf:"bytes,7,opt,name=ban_window,json=banWindow,proto3" json:"ban_window,omitempty"`
+	// How long a banned IP's connections are refused
+	// Default: 5m
+	BanDuration *durationpb.Duration `protobuf:"bytes,8,opt,name=ban_duration,json=banDuration,proto3" json:"ban_duration,omitempty"`
+	// Client networks (CIDR) exempt from connection caps and bans, e.g. load balancers
+	// Default: empty
+	TrustedCidrs  []string `protobuf:"bytes,9,rep,name=trusted_cidrs,json=trustedCidrs,proto3" json:"trusted_cidrs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SlowClientProtectionConfig) Reset() {
+	*x = SlowClientProtectionConfig{}
+	mi := &file_http_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SlowClientProtectionConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SlowClientProtectionConfig) ProtoMessage() {}
+
+func (x *SlowClientProtectionConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SlowClientProtectionConfig.ProtoReflect.Descriptor instead.
+func (*SlowClientProtectionConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *SlowClientProtectionConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *SlowClientProtectionConfig) GetMaxConnsPerIp() uint32 {
+	if x != nil {
+		return x.MaxConnsPerIp
+	}
+	return 0
+}
+
+func (x *SlowClientProtectionConfig) GetBodyReadTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.BodyReadTimeout
+	}
+	return nil
+}
+
+func (x *SlowClientProtectionConfig) GetMinBodyRate() int64 {
+	if x != nil {
+		return x.MinBodyRate
+	}
+	return 0
+}
+
+func (x *SlowClientProtectionConfig) GetBodyRateGrace() *durationpb.Duration {
+	if x != nil {
+		return x.BodyRateGrace
+	}
+	return nil
+}
+
+func (x *SlowClientProtectionConfig) GetBanThreshold() uint32 {
+	if x != nil {
+		return x.BanThreshold
+	}
+	return 0
+}
+
+func (x *SlowClientProtectionConfig) GetBanWindow() *durationpb.Duration {
+	if x != nil {
+		return x.BanWindow
+	}
+	return nil
+}
+
+func (x *SlowClientProtectionConfig) GetBanDuration() *durationpb.Duration {
+	if x != nil {
+		return x.BanDuration
+	}
+	return nil
+}
+
+func (x *SlowClientProtectionConfig) GetTrustedCidrs() []string {
+	if x != nil {
+		return x.TrustedCidrs
+	}
+	return nil
+}
+
+// CORS configuration
+type CorsConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to enable CORS
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Allowed origins
+	// Default: ["*"] (when enabled)
+	AllowedOrigins []string `protobuf:"bytes,2,rep,name=allowed_origins,json=allowedOrigins,proto3" json:"allowed_origins,omitempty"`
+	// Allowed methods
+	// Default: ["GET", "POST", "PUT", "DELETE", "OPTIONS"]
+	AllowedMethods []string `protobuf:"bytes,3,rep,name=allowed_methods,json=allowedMethods,proto3" json:"allowed_methods,omitempty"`
+	// Allowed headers
+	// Default: ["*"]
+	AllowedHeaders []string `protobuf:"bytes,4,rep,name=allowed_headers,json=allowedHeaders,proto3" json:"allowed_headers,omitempty"`
+	// Exposed headers
+	// Default: []
+	ExposedHeaders []string `protobuf:"bytes,5,rep,name=exposed_headers,json=exposedHeaders,proto3" json:"exposed_headers,omitempty"`
+	// Whether to allow credentials
+	// Default: false
+	AllowCredentials bool `protobuf:"varint,6,opt,name=allow_credentials,json=allowCredentials,proto3" json:"allow_credentials,omitempty"`
+	// Max age for preflight requests in seconds
+	// Default: 86400 (24 hours)
+	MaxAge        int32 `protobuf:"varint,7,opt,name=max_age,json=maxAge,proto3" json:"max_age,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CorsConfig) Reset() {
+	*x = CorsConfig{}
+	mi := &file_http_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CorsConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CorsConfig) ProtoMessage() {}
+
+func (x *CorsConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CorsConfig.ProtoReflect.Descriptor instead.
+func (*CorsConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *CorsConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *CorsConfig) GetAllowedOrigins() []string {
+	if x != nil {
+		return x.AllowedOrigins
+	}
+	return nil
+}
+
+func (x *CorsConfig) GetAllowedMethods() []string {
+	if x != nil {
+		return x.AllowedMethods
+	}
+	return nil
+}
+
+func (x *CorsConfig) GetAllowedHeaders() []string {
+	if x != nil {
+		return x.AllowedHeaders
+	}
+	return nil
+}
+
+func (x *CorsConfig) GetExposedHeaders() []string {
+	if x != nil {
+		return x.ExposedHeaders
+	}
+	return nil
+}
+
+func (x *CorsConfig) GetAllowCredentials() bool {
+	if x != nil {
+		return x.AllowCredentials
+	}
+	return false
+}
+
+func (x *CorsConfig) GetMaxAge() int32 {
+	if x != nil {
+		return x.MaxAge
+	}
+	return 0
+}
+
+// Rate limiting configuration
+type RateLimitConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to enable rate limiting
+	// Default: true
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Rate limit per second
+	// Default: 100
+	RatePerSecond int32 `protobuf:"varint,2,opt,name=rate_per_second,json=ratePerSecond,proto3" json:"rate_per_second,omitempty"`
+	// Burst limit
+	// Default: 200
+	BurstLimit int32 `protobuf:"varint,3,opt,name=burst_limit,json=burstLimit,proto3" json:"burst_limit,omitempty"`
+	// Tokens consumed by requests to expensive routes, e.g. searches; the first matching rule applies and other
+	// requests consume one token
+	// Default: empty
+	Costs []*RateLimitCost `protobuf:"bytes,4,rep,name=costs,proto3" json:"costs,omitempty"`
+	// Quota headers telling clients how many requests they have left
+	Headers       *RateLimitHeadersConfig `protobuf:"bytes,5,opt,name=headers,proto3" json:"headers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateLimitConfig) Reset() {
+	*x = RateLimitConfig{}
+	mi := &file_http_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateLimitConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateLimitConfig) ProtoMessage() {}
+
+func (x *RateLimitConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateLimitConfig.ProtoReflect.Descriptor instead.
+func (*RateLimitConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *RateLimitConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *RateLimitConfig) GetRatePerSecond() int32 {
+	if x != nil {
+		return x.RatePerSecond
+	}
+	return 0
+}
+
+func (x *RateLimitConfig) GetBurstLimit() int32 {
+	if x != nil {
+		return x.BurstLimit
+	}
+	return 0
+}
+
+func (x *RateLimitConfig) GetCosts() []*RateLimitCost {
+	if x != nil {
+		return x.Costs
+	}
+	return nil
+}
+
+func (x *RateLimitConfig) GetHeaders() *RateLimitHeadersConfig {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+// RateLimitHeadersConfig adds quota headers to replies of rate limited routes: the burst as the limit, the tokens
+// left after the request and the seconds until the bucket is full again. Rejected requests also get Retry-After.
+type RateLimitHeadersConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to send quota headers
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// "x" for X-RateLimit-Limit/Remaining/Reset, "draft" for the IETF draft RateLimit-Limit/Remaining/Reset, or
+	// "both"
+	// Default: "both"
+	Style string `protobuf:"bytes,2,opt,name=style,proto3" json:"style,omitempty"`
+	// Operations or request paths whose replies carry no quota headers, e.g. routes exposed to third parties; a
+	// trailing "*" matches a prefix
+	// Default: empty
+	ExemptOperations []string `protobuf:"bytes,3,rep,name=exempt_operations,json=exemptOperations,proto3" json:"exempt_operations,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RateLimitHeadersConfig) Reset() {
+	*x = RateLimitHeadersConfig{}
+	mi := &file_http_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateLimitHeadersConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateLimitHeadersConfig) ProtoMessage() {}
+
+func (x *RateLimitHeadersConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateLimitHeadersConfig.ProtoReflect.Descriptor instead.
+func (*RateLimitHeadersConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *RateLimitHeadersConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *RateLimitHeadersConfig) GetStyle() string {
+	if x != nil {
+		return x.Style
+	}
+	return ""
+}
+
+func (x *RateLimitHeadersConfig) GetExemptOperations() []string {
+	if x != nil {
+		return x.ExemptOperations
+	}
+	return nil
+}
+
+// RateLimitCost is the number of rate limit tokens each request to a group of routes consumes, so a route costing
+// 5 tokens is admitted a fifth as often as one costing 1.
+type RateLimitCost struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Operation or request path; a trailing "*" matches a prefix, e.g. "/api.v1.Search/*"
+	Operation string `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	// Tokens consumed per request; at most burst_limit, or the route could never be admitted
+	Tokens        int32 `protobuf:"varint,2,opt,name=tokens,proto3" json:"tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateLimitCost) Reset() {
+	*x = RateLimitCost{}
+	mi := &file_http_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateLimitCost) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateLimitCost) ProtoMessage() {}
+
+func (x *RateLimitCost) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateLimitCost.ProtoReflect.Descriptor instead.
+func (*RateLimitCost) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *RateLimitCost) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *RateLimitCost) GetTokens() int32 {
+	if x != nil {
+		return x.Tokens
+	}
+	return 0
+}
+
+// Security headers configuration
+type SecurityHeadersConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to enable security headers
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Content Security Policy
+	// Default: "default-src 'self'"
+	ContentSecurityPolicy string `protobuf:"bytes,2,opt,name=content_security_policy,json=contentSecurityPolicy,proto3" json:"content_security_policy,omitempty"`
+	// X-Frame-Options
+	// Default: "DENY"
+	XFrameOptions string `protobuf:"bytes,3,opt,name=x_frame_options,json=xFrameOptions,proto3" json:"x_frame_options,omitempty"`
+	// X-Content-Type-Options
+	// Default: "nosniff"
+	XContentTypeOptions string `protobuf:"bytes,4,opt,name=x_content_type_options,json=xContentTypeOptions,proto3" json:"x_content_type_options,omitempty"`
+	// X-XSS-Protection
+	// Default: "1; mode=block"
+	XXssProtection string `protobuf:"bytes,5,opt,name=x_xss_protection,json=xXssProtection,proto3" json:"x_xss_protection,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SecurityHeadersConfig) Reset() {
+	*x = SecurityHeadersConfig{}
+	mi := &file_http_proto_msgTypes[82]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SecurityHeadersConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecurityHeadersConfig) ProtoMessage() {}
+
+func (x *SecurityHeadersConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[82]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecurityHeadersConfig.ProtoReflect.Descriptor instead.
+func (*SecurityHeadersConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *SecurityHeadersConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *SecurityHeadersConfig) GetContentSecurityPolicy() string {
+	if x != nil {
+		return x.ContentSecurityPolicy
+	}
+	return ""
+}
+
+func (x *SecurityHeadersConfig) GetXFrameOptions() string {
+	if x != nil {
+		return x.XFrameOptions
+	}
+	return ""
+}
+
+func (x *SecurityHeadersConfig) GetXContentTypeOptions() string {
+	if x != nil {
+		return x.XContentTypeOptions
+	}
+	return ""
+}
+
+func (x *SecurityHeadersConfig) GetXXssProtection() string {
+	if x != nil {
+		return x.XXssProtection
+	}
+	return ""
+}
+
+// Performance configuration
+type PerformanceConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Maximum number of concurrent connections
+	// Default: 1000
+	MaxConnections int32 `protobuf:"varint,1,opt,name=max_connections,json=maxConnections,proto3" json:"max_connections,omitempty"`
+	// Maximum number of concurrent requests
+	// Default: 500
+	MaxConcurrentRequests int32 `protobuf:"varint,2,opt,name=max_concurrent_requests,json=maxConcurrentRequests,proto3" json:"max_concurrent_requests,omitempty"`
+	// Read buffer size in bytes
+	// Default: 4096 (4KB)
+	ReadBufferSize int32 `protobuf:"varint,3,opt,name=read_buffer_size,json=readBufferSize,proto3" json:"read_buffer_size,omitempty"`
+	// Write buffer size in bytes
+	// Default: 4096 (4KB)
+	WriteBufferSize int32 `protobuf:"varint,4,opt,name=write_buffer_size,json=writeBufferSize,proto3" json:"write_buffer_size,omitempty"`
+	// Connection pool configuration
+	// Default: optimized connection pool settings
+	ConnectionPool *ConnectionPoolConfig `protobuf:"bytes,5,opt,name=connection_pool,json=connectionPool,proto3" json:"connection_pool,omitempty"`
+	// Read timeout
+	// Default: 30s
+	ReadTimeout *durationpb.Duration `protobuf:"bytes,6,opt,name=read_timeout,json=readTimeout,proto3" json:"read_timeout,omitempty"`
+	// Write timeout
+	// Default: 30s
+	WriteTimeout *durationpb.Duration `protobuf:"bytes,7,opt,name=write_timeout,json=writeTimeout,proto3" json:"write_timeout,omitempty"`
+	// Idle timeout
+	// Default: 60s
+	IdleTimeout *durationpb.Duration `protobuf:"bytes,8,opt,name=idle_timeout,json=idleTimeout,proto3" json:"idle_timeout,omitempty"`
+	// Header read timeout
+	// Default: 20s
+	ReadHeaderTimeout *durationpb.Duration `protobuf:"bytes,9,opt,name=read_header_timeout,json=readHeaderTimeout,proto3" json:"read_header_timeout,omitempty"`
+	// Maximum size of request headers in bytes
+	// Default: 1048576 (net/http default)
+	MaxHeaderBytes int32 `protobuf:"varint,10,opt,name=max_header_bytes,json=maxHeaderBytes,proto3" json:"max_header_bytes,omitempty"`
+	// Whether to disable HTTP keep-alives (every request closes its connection)
+	// Default: false
+	DisableKeepAlives bool `protobuf:"varint,11,opt,name=disable_keep_alives,json=disableKeepAlives,proto3" json:"disable_keep_alives,omitempty"`
+	// TCP keep-alive probe period for accepted connections
+	// Default: Go runtime default (15s)
+	TcpKeepAlivePeriod *durationpb.Duration `protobuf:"bytes,12,opt,name=tcp_keep_alive_period,json=tcpKeepAlivePeriod,proto3" json:"tcp_keep_alive_period,omitempty"`
+	// Bounded admission queues per operation group
+	// Default: none (requests are admitted immediately)
+	AdmissionQueues []*AdmissionQueueConfig `protobuf:"bytes,13,rep,name=admission_queues,json=admissionQueues,proto3" json:"admission_queues,omitempty"`
+	// Throughput preset: request and reply bodies are left out of request logs (log boosts still render
+	// them) and the request/response size histograms are not recorded
+	// Default: false
+	PerfMode bool `protobuf:"varint,14,opt,name=perf_mode,json=perfMode,proto3" json:"perf_mode,omitempty"`
+	// Sheds low-priority requests while heap usage or the goroutine count is above its threshold
+	// Default: disabled
+	ResourceGuard *ResourceGuardConfig `protobuf:"bytes,15,opt,name=resource_guard,json=resourceGuard,proto3" json:"resource_guard,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PerformanceConfig) Reset() {
+	*x = PerformanceConfig{}
+	mi := &file_http_proto_msgTypes[83]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PerformanceConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PerformanceConfig) ProtoMessage() {}
+
+func (x *PerformanceConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[83]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PerformanceConfig.ProtoReflect.Descriptor instead.
+func (*PerformanceConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *PerformanceConfig) GetMaxConnections() int32 {
+	if x != nil {
+		return x.MaxConnections
+	}
+	return 0
+}
+
+func (x *PerformanceConfig) GetMaxConcurrentRequests() int32 {
+	if x != nil {
+		return x.MaxConcurrentRequests
+	}
+	return 0
+}
+
+func (x *PerformanceConfig) GetReadBufferSize() int32 {
+	if x != nil {
+		return x.ReadBufferSize
+	}
+	return 0
+}
+
+func (x *PerformanceConfig) GetWriteBufferSize() int32 {
+	if x != nil {
+		return x.WriteBufferSize
+	}
+	return 0
+}
+
+func (x *PerformanceConfig) GetConnectionPool() *ConnectionPoolConfig {
+	if x != nil {
+		return x.ConnectionPool
+	}
+	return nil
+}
+
+func (x *PerformanceConfig) GetReadTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.ReadTimeout
+	}
+	return nil
+}
+
+func (x *PerformanceConfig) GetWriteTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.WriteTimeout
+	}
+	return nil
+}
+
+func (x *PerformanceConfig) GetIdleTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.IdleTimeout
+	}
+	return nil
+}
+
+func (x *PerformanceConfig) GetReadHeaderTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.ReadHeaderTimeout
+	}
+	return nil
+}
+
+func (x *PerformanceConfig) GetMaxHeaderBytes() int32 {
+	if x != nil {
+		return x.MaxHeaderBytes
+	}
+	return 0
+}
+
+func (x *PerformanceConfig) GetDisableKeepAlives() bool {
+	if x != nil {
+		return x.DisableKeepAlives
+	}
+	return false
+}
+
+func (x *PerformanceConfig) GetTcpKeepAlivePeriod() *durationpb.Duration {
+	if x != nil {
+		return x.TcpKeepAlivePeriod
+	}
+	return nil
+}
+
+func (x *PerformanceConfig) GetAdmissionQueues() []*AdmissionQueueConfig {
+	if x != nil {
+		return x.AdmissionQueues
+	}
+	return nil
+}
+
+func (x *PerformanceConfig) GetPerfMode() bool {
+	if x != nil {
+		return x.PerfMode
+	}
+	return false
+}
+
+func (x *PerformanceConfig) GetResourceGuard() *ResourceGuardConfig {
+	if x != nil {
+		return x.ResourceGuard
+	}
+	return nil
+}
+
+// ResourceGuardConfig sheds low-priority traffic before the process runs out of memory. While a threshold is
+// exceeded, requests of shed_operations and of the shed_admission_groups fail with SERVER_BUSY (503); the guard
+// releases once usage falls below 90% of every threshold.
+type ResourceGuardConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to run the guard
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Heap bytes in use above which traffic is shed; 0 disables the check
+	// Default: 0
+	MaxHeapBytes uint64 `protobuf:"varint,2,opt,name=max_heap_bytes,json=maxHeapBytes,proto3" json:"max_heap_bytes,omitempty"`
+	// Goroutine count above which traffic is shed; 0 disables the check
+	// Default: 0
+	MaxGoroutines uint32 `protobuf:"varint,3,opt,name=max_goroutines,json=maxGoroutines,proto3" json:"max_goroutines,omitempty"`
+	// How often usage is sampled
+	// Default: 1s
+	CheckInterval *durationpb.Duration `protobuf:"bytes,4,opt,name=check_interval,json=checkInterval,proto3" json:"check_interval,omitempty"`
+	// Low-priority operations shed under pressure; exact names or prefixes ending in "*"
+	// Default: empty
+	ShedOperations []string `protobuf:"bytes,5,rep,name=shed_operations,json=shedOperations,proto3" json:"shed_operations,omitempty"`
+	// Admission queue groups whose operations are shed under pressure
+	// Default: empty
+	ShedAdmissionGroups []string `protobuf:"bytes,6,rep,name=shed_admission_groups,json=shedAdmissionGroups,proto3" json:"shed_admission_groups,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *ResourceGuardConfig) Reset() {
+	*x = ResourceGuardConfig{}
+	mi := &file_http_proto_msgTypes[84]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResourceGuardConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResourceGuardConfig) ProtoMessage() {}
+
+func (x *ResourceGuardConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[84]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResourceGuardConfig.ProtoReflect.Descriptor instead.
+func (*ResourceGuardConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *ResourceGuardConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *ResourceGuardConfig) GetMaxHeapBytes() uint64 {
+	if x != nil {
+		return x.MaxHeapBytes
+	}
+	return 0
+}
+
+func (x *ResourceGuardConfig) GetMaxGoroutines() uint32 {
+	if x != nil {
+		return x.MaxGoroutines
+	}
+	return 0
+}
+
+func (x *ResourceGuardConfig) GetCheckInterval() *durationpb.Duration {
+	if x != nil {
+		return x.CheckInterval
+	}
+	return nil
+}
+
+func (x *ResourceGuardConfig) GetShedOperations() []string {
+	if x != nil {
+		return x.ShedOperations
+	}
+	return nil
+}
+
+func (x *ResourceGuardConfig) GetShedAdmissionGroups() []string {
+	if x != nil {
+		return x.ShedAdmissionGroups
+	}
+	return nil
+}
+
+// Admission queue for a group of operations. Requests beyond max_concurrency wait in a bounded
+// queue for up to max_wait and then fail fast with a busy (503) error.
+type AdmissionQueueConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Group name used in metrics and logs
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Operations in the group; exact names or prefixes ending in "*" (e.g. "/api.v1.Reports/*")
+	Operations []string `protobuf:"bytes,2,rep,name=operations,proto3" json:"operations,omitempty"`
+	// Maximum requests of the group handled concurrently
+	MaxConcurrency int32 `protobuf:"varint,3,opt,name=max_concurrency,json=maxConcurrency,proto3" json:"max_concurrency,omitempty"`
+	// Maximum requests waiting for a slot; 0 fails fast as soon as the group is saturated
+	MaxQueue int32 `protobuf:"varint,4,opt,name=max_queue,json=maxQueue,proto3" json:"max_queue,omitempty"`
+	// Maximum time a request waits in the queue
+	// Default: 100ms
+	MaxWait       *durationpb.Duration `protobuf:"bytes,5,opt,name=max_wait,json=maxWait,proto3" json:"max_wait,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdmissionQueueConfig) Reset() {
+	*x = AdmissionQueueConfig{}
+	mi := &file_http_proto_msgTypes[85]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdmissionQueueConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdmissionQueueConfig) ProtoMessage() {}
+
+func (x *AdmissionQueueConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[85]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdmissionQueueConfig.ProtoReflect.Descriptor instead.
+func (*AdmissionQueueConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{85}
+}
+
+func (x *AdmissionQueueConfig) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AdmissionQueueConfig) GetOperations() []string {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+func (x *AdmissionQueueConfig) GetMaxConcurrency() int32 {
+	if x != nil {
+		return x.MaxConcurrency
+	}
+	return 0
+}
+
+func (x *AdmissionQueueConfig) GetMaxQueue() int32 {
+	if x != nil {
+		return x.MaxQueue
+	}
+	return 0
+}
+
+func (x *AdmissionQueueConfig) GetMaxWait() *durationpb.Duration {
+	if x != nil {
+		return x.MaxWait
+	}
+	return nil
+}
+
+// Connection pool configuration
+type ConnectionPoolConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Maximum number of idle connections
+	// Default: 100
+	MaxIdleConns int32 `protobuf:"varint,1,opt,name=max_idle_conns,json=maxIdleConns,proto3" json:"max_idle_conns,omitempty"`
+	// Maximum number of idle connections per host
+	// Default: 10
+	MaxIdleConnsPerHost int32 `protobuf:"varint,2,opt,name=max_idle_conns_per_host,json=maxIdleConnsPerHost,proto3" json:"max_idle_conns_per_host,omitempty"`
+	// Maximum number of connections per host
+	// Default: 100
+	MaxConnsPerHost int32 `protobuf:"varint,3,opt,name=max_conns_per_host,json=maxConnsPerHost,proto3" json:"max_conns_per_host,omitempty"`
+	// Connection reuse duration
+	// Default: 30s
+	KeepAliveDuration *durationpb.Duration `protobuf:"bytes,4,opt,name=keep_alive_duration,json=keepAliveDuration,proto3" json:"keep_alive_duration,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ConnectionPoolConfig) Reset() {
+	*x = ConnectionPoolConfig{}
+	mi := &file_http_proto_msgTypes[86]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConnectionPoolConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConnectionPoolConfig) ProtoMessage() {}
+
+func (x *ConnectionPoolConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[86]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConnectionPoolConfig.ProtoReflect.Descriptor instead.
+func (*ConnectionPoolConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *ConnectionPoolConfig) GetMaxIdleConns() int32 {
+	if x != nil {
+		return x.MaxIdleConns
+	}
+	return 0
+}
+
+func (x *ConnectionPoolConfig) GetMaxIdleConnsPerHost() int32 {
+	if x != nil {
+		return x.MaxIdleConnsPerHost
+	}
+	return 0
+}
+
+func (x *ConnectionPoolConfig) GetMaxConnsPerHost() int32 {
+	if x != nil {
+		return x.MaxConnsPerHost
+	}
+	return 0
+}
+
+func (x *ConnectionPoolConfig) GetKeepAliveDuration() *durationpb.Duration {
+	if x != nil {
+		return x.KeepAliveDuration
+	}
+	return nil
+}
+
+// Middleware configuration
+type MiddlewareConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to enable tracing middleware
+	// Default: true
+	EnableTracing bool `protobuf:"varint,1,opt,name=enable_tracing,json=enableTracing,proto3" json:"enable_tracing,omitempty"`
+	// Whether to enable logging middleware
+	// Default: true
+	EnableLogging bool `protobuf:"varint,2,opt,name=enable_logging,json=enableLogging,proto3" json:"enable_logging,omitempty"`
+	// Whether to enable recovery middleware
+	// Default: true
+	EnableRecovery bool `protobuf:"varint,3,opt,name=enable_recovery,json=enableRecovery,proto3" json:"enable_recovery,omitempty"`
+	// Whether to enable validation middleware
+	// Default: true
+	EnableValidation bool `protobuf:"varint,4,opt,name=enable_validation,json=enableValidation,proto3" json:"enable_validation,omitempty"`
+	// Whether to enable rate limiting middleware
+	// Default: true
+	EnableRateLimit bool `protobuf:"varint,5,opt,name=enable_rate_limit,json=enableRateLimit,proto3" json:"enable_rate_limit,omitempty"`
+	// Whether to enable metrics middleware
+	// Default: true
+	EnableMetrics bool `protobuf:"varint,6,opt,name=enable_metrics,json=enableMetrics,proto3" json:"enable_metrics,omitempty"`
+	// Custom middleware configuration (key-value pairs)
+	// Default: empty
+	CustomMiddleware map[string]string `protobuf:"bytes,7,rep,name=custom_middleware,json=customMiddleware,proto3" json:"custom_middleware,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Derive the request deadline from a client-declared timeout header
+	// Default: disabled
+	DeadlinePropagation *DeadlinePropagationConfig `protobuf:"bytes,8,opt,name=deadline_propagation,json=deadlinePropagation,proto3" json:"deadline_propagation,omitempty"`
+	// Retry budget headers: reject retries beyond the attempt budget and split latency by attempt
+	// Default: disabled
+	RetryBudget *RetryBudgetConfig `protobuf:"bytes,9,opt,name=retry_budget,json=retryBudget,proto3" json:"retry_budget,omitempty"`
+	// Collapse identical rapid-fire submissions (UI double taps) into a single execution
+	// Default: disabled
+	Dedup *DedupConfig `protobuf:"bytes,10,opt,name=dedup,proto3" json:"dedup,omitempty"`
+	// Run identical concurrent GETs once and share the reply (thundering-herd protection)
+	// Default: disabled
+	Coalesce *CoalesceConfig `protobuf:"bytes,11,opt,name=coalesce,proto3" json:"coalesce,omitempty"`
+	// Retry idempotent operations on the client's behalf when the handler returns a transient error
+	// Default: disabled
+	HandlerRetry *HandlerRetryConfig `protobuf:"bytes,12,opt,name=handler_retry,json=handlerRetry,proto3" json:"handler_retry,omitempty"`
+	// Detect clients hammering a failing operation with retries, alert and optionally throttle them
+	// Default: disabled
+	RetryStorm    *RetryStormConfig `protobuf:"bytes,13,opt,name=retry_storm,json=retryStorm,proto3" json:"retry_storm,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MiddlewareConfig) Reset() {
+	*x = MiddlewareConfig{}
+	mi := &file_http_proto_msgTypes[87]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MiddlewareConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MiddlewareConfig) ProtoMessage() {}
+
+func (x *MiddlewareConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[87]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MiddlewareConfig.ProtoReflect.Descriptor instead.
+func (*MiddlewareConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{87}
+}
+
+func (x *MiddlewareConfig) GetEnableTracing() bool {
+	if x != nil {
+		return x.EnableTracing
+	}
+	return false
+}
+
+func (x *MiddlewareConfig) GetEnableLogging() bool {
+	if x != nil {
+		return x.EnableLogging
+	}
+	return false
+}
+
+func (x *MiddlewareConfig) GetEnableRecovery() bool {
+	if x != nil {
+		return x.EnableRecovery
+	}
+	return false
+}
+
+func (x *MiddlewareConfig) GetEnableValidation() bool {
+	if x != nil {
+		return x.EnableValidation
+	}
+	return false
+}
+
+func (x *MiddlewareConfig) GetEnableRateLimit() bool {
+	if x != nil {
+		return x.EnableRateLimit
+	}
+	return false
+}
+
+func (x *MiddlewareConfig) GetEnableMetrics() bool {
+	if x != nil {
+		return x.EnableMetrics
+	}
+	return false
+}
+
+func (x *MiddlewareConfig) GetCustomMiddleware() map[string]string {
+	if x != nil {
+		return x.CustomMiddleware
+	}
+	return nil
+}
+
+func (x *MiddlewareConfig) GetDeadlinePropagation() *DeadlinePropagationConfig {
+	if x != nil {
+		return x.DeadlinePropagation
+	}
+	return nil
+}
+
+func (x *MiddlewareConfig) GetRetryBudget() *RetryBudgetConfig {
+	if x != nil {
+		return x.RetryBudget
+	}
+	return nil
+}
+
+func (x *MiddlewareConfig) GetDedup() *DedupConfig {
+	if x != nil {
+		return x.Dedup
+	}
+	return nil
+}
+
+func (x *MiddlewareConfig) GetCoalesce() *CoalesceConfig {
+	if x != nil {
+		return x.Coalesce
+	}
+	return nil
+}
+
+func (x *MiddlewareConfig) GetHandlerRetry() *HandlerRetryConfig {
+	if x != nil {
+		return x.HandlerRetry
+	}
+	return nil
+}
+
+func (x *MiddlewareConfig) GetRetryStorm() *RetryStormConfig {
+	if x != nil {
+		return x.RetryStorm
+	}
+	return nil
+}
+
+// RetryStormConfig detects error-driven retry storms: one client (the caller service when caller metrics are
+// enabled, otherwise the client IP) failing on one operation at least threshold times within window. Each storm
+// is logged once as an aggregated warning and counted in lynx_http_retry_storms_total; with block set, the
+// client's requests to that operation are rejected with 429 RETRY_STORM for block_duration.
+type RetryStormConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to detect retry storms
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Failures of one client on one operation within window that make a storm
+	// Default: 20
+	Threshold int32 `protobuf:"varint,2,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	// Counting window
+	// Default: 10s
+	Window *durationpb.Duration `protobuf:"bytes,3,opt,name=window,proto3" json:"window,omitempty"`
+	// Reject the storming client's requests to the operation instead of only alerting
+	// Default: false
+	Block bool `protobuf:"varint,4,opt,name=block,proto3" json:"block,omitempty"`
+	// How long a storming client is rejected
+	// Default: 30s
+	BlockDuration *durationpb.Duration `protobuf:"bytes,5,opt,name=block_duration,json=blockDuration,proto3" json:"block_duration,omitempty"`
+	// Maximum number of tracked client and operation pairs; new pairs are not tracked while it is reached
+	// Default: 10000
+	MaxTracked int32 `protobuf:"varint,6,opt,name=max_tracked,json=maxTracked,proto3" json:"max_tracked,omitempty"`
+	// Operations or paths (wildcards allowed) that are never tracked
+	// Default: none
+	ExemptOperations []string `protobuf:"bytes,7,rep,name=exempt_operations,json=exemptOperations,proto3" json:"exempt_operations,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RetryStormConfig) Reset() {
+	*x = RetryStormConfig{}
+	mi := &file_http_proto_msgTypes[88]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetryStormConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryStormConfig) ProtoMessage() {}
+
+func (x *RetryStormConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[88]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryStormConfig.ProtoReflect.Descriptor instead.
+func (*RetryStormConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{88}
+}
+
+func (x *RetryStormConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *RetryStormConfig) GetThreshold() int32 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *RetryStormConfig) GetWindow() *durationpb.Duration {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+func (x *RetryStormConfig) GetBlock() bool {
+	if x != nil {
+		return x.Block
+	}
+	return false
+}
+
+func (x *RetryStormConfig) GetBlockDuration() *durationpb.Duration {
+	if x != nil {
+		return x.BlockDuration
+	}
+	return nil
+}
+
+func (x *RetryStormConfig) GetMaxTracked() int32 {
+	if x != nil {
+		return x.MaxTracked
+	}
+	return 0
+}
+
+func (x *RetryStormConfig) GetExemptOperations() []string {
+	if x != nil {
+		return x.ExemptOperations
+	}
+	return nil
+}
+
+// DedupConfig collapses requests with the same method, operation, host, tenant, virtual host, path and query,
+// caller identity and body that arrive within a short window. The first request runs the handler; duplicates wait for it and share its reply.
+type DedupConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to deduplicate requests
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// How long a completed request keeps answering its duplicates
+	// Default: 2s
+	Window *durationpb.Duration `protobuf:"bytes,2,opt,name=window,proto3" json:"window,omitempty"`
+	// HTTP methods to deduplicate
+	// Default: ["POST"]
+	Methods []string `protobuf:"bytes,3,rep,name=methods,proto3" json:"methods,omitempty"`
+	// Header identifying the caller; the client IP is used when it is absent
+	// Default: "Authorization"
+	IdentityHeader string `protobuf:"bytes,4,opt,name=identity_header,json=identityHeader,proto3" json:"identity_header,omitempty"`
+	// Maximum number of tracked requests; beyond it requests run without deduplication
+	// Default: 10000
+	MaxEntries    int32 `protobuf:"varint,5,opt,name=max_entries,json=maxEntries,proto3" json:"max_entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DedupConfig) Reset() {
+	*x = DedupConfig{}
+	mi := &file_http_proto_msgTypes[89]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DedupConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DedupConfig) ProtoMessage() {}
+
+func (x *DedupConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[89]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DedupConfig.ProtoReflect.Descriptor instead.
+func (*DedupConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{89}
+}
+
+func (x *DedupConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *DedupConfig) GetWindow() *durationpb.Duration {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+func (x *DedupConfig) GetMethods() []string {
+	if x != nil {
+		return x.Methods
+	}
+	return nil
+}
+
+func (x *DedupConfig) GetIdentityHeader() string {
+	if x != nil {
+		return x.IdentityHeader
+	}
+	return ""
+}
+
+func (x *DedupConfig) GetMaxEntries() int32 {
+	if x != nil {
+		return x.MaxEntries
+	}
+	return 0
+}
+
+// CoalesceConfig runs concurrent requests with the same method, host, tenant, path, query and caller identity
+// once: the first runs the handler and the others wait for it and share its reply or error. Unlike dedup,
+// nothing is kept once the handler returns.
+type CoalesceConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether to coalesce requests
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// HTTP methods to coalesce; only use safe methods
+	// Default: ["GET"]
+	Methods []string `protobuf:"bytes,2,rep,name=methods,proto3" json:"methods,omitempty"`
+	// Headers identifying the caller; requests coalesce only when all of them match
+	// Default: ["Authorization", "Cookie"]
+	IdentityHeaders []string `protobuf:"bytes,3,rep,name=identity_headers,json=identityHeaders,proto3" json:"identity_headers,omitempty"`
+	// Operations or paths never coalesced; exact names or prefixes ending in "*"
+	// Default: empty
+	ExemptOperations []string `protobuf:"bytes,4,rep,name=exempt_operations,json=exemptOperations,proto3" json:"exempt_operations,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CoalesceConfig) Reset() {
+	*x = CoalesceConfig{}
+	mi := &file_http_proto_msgTypes[90]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CoalesceConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CoalesceConfig) ProtoMessage() {}
+
+func (x *CoalesceConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[90]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CoalesceConfig.ProtoReflect.Descriptor instead.
+func (*CoalesceConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{90}
+}
+
+func (x *CoalesceConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *CoalesceConfig) GetMethods() []string {
 	if x != nil {
-		return x.XFrameOptions
+		return x.Methods
 	}
-	return ""
+	return nil
 }
 
-func (x *SecurityHeadersConfig) GetXContentTypeOptions() string {
+func (x *CoalesceConfig) GetIdentityHeaders() []string {
 	if x != nil {
-		return x.XContentTypeOptions
+		return x.IdentityHeaders
 	}
-	return ""
+	return nil
 }
 
-func (x *SecurityHeadersConfig) GetXXssProtection() string {
+func (x *CoalesceConfig) GetExemptOperations() []string {
 	if x != nil {
-		return x.XXssProtection
+		return x.ExemptOperations
 	}
-	return ""
+	return nil
 }
 
-// Performance configuration
-type PerformanceConfig struct {
+// HandlerRetryConfig runs the handler again when it returns a designated transient error, such as an
+// optimistic-lock conflict, so the client does not have to. Only list operations that are safe to run twice.
+type HandlerRetryConfig struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// Maximum number of concurrent connections
-	// Default: 1000
-	MaxConnections int32 `protobuf:"varint,1,opt,name=max_connections,json=maxConnections,proto3" json:"max_connections,omitempty"`
-	// Maximum number of concurrent requests
-	// Default: 500
-	MaxConcurrentRequests int32 `protobuf:"varint,2,opt,name=max_concurrent_requests,json=maxConcurrentRequests,proto3" json:"max_concurrent_requests,omitempty"`
-	// Read buffer size in bytes
-	// Default: 4096 (4KB)
-	ReadBufferSize int32 `protobuf:"varint,3,opt,name=read_buffer_size,json=readBufferSize,proto3" json:"read_buffer_size,omitempty"`
-	// Write buffer size in bytes
-	// Default: 4096 (4KB)
-	WriteBufferSize int32 `protobuf:"varint,4,opt,name=write_buffer_size,json=writeBufferSize,proto3" json:"write_buffer_size,omitempty"`
-	// Connection pool configuration
-	// Default: optimized connection pool settings
-	ConnectionPool *ConnectionPoolConfig `protobuf:"bytes,5,opt,name=connection_pool,json=connectionPool,proto3" json:"connection_pool,omitempty"`
-	// Read timeout
-	// Default: 30s
-	ReadTimeout *durationpb.Duration `protobuf:"bytes,6,opt,name=read_timeout,json=readTimeout,proto3" json:"read_timeout,omitempty"`
-	// Write timeout
-	// Default: 30s
-	WriteTimeout *durationpb.Duration `protobuf:"bytes,7,opt,name=write_timeout,json=writeTimeout,proto3" json:"write_timeout,omitempty"`
-	// Idle timeout
-	// Default: 60s
-	IdleTimeout *durationpb.Duration `protobuf:"bytes,8,opt,name=idle_timeout,json=idleTimeout,proto3" json:"idle_timeout,omitempty"`
-	// Header read timeout
-	// Default: 20s
-	ReadHeaderTimeout *durationpb.Duration `protobuf:"bytes,9,opt,name=read_header_timeout,json=readHeaderTimeout,proto3" json:"read_header_timeout,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+	// Whether to retry handlers
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Operations or paths retried; exact names or prefixes ending in "*" (required)
+	// Default: empty
+	Operations []string `protobuf:"bytes,2,rep,name=operations,proto3" json:"operations,omitempty"`
+	// HTTP methods retried; only idempotent methods are accepted
+	// Default: ["GET", "HEAD", "OPTIONS", "PUT", "DELETE"]
+	Methods []string `protobuf:"bytes,3,rep,name=methods,proto3" json:"methods,omitempty"`
+	// Kratos error reasons that are transient, e.g. "OPTIMISTIC_LOCK_CONFLICT"
+	// Default: empty
+	Reasons []string `protobuf:"bytes,4,rep,name=reasons,proto3" json:"reasons,omitempty"`
+	// Error codes that are transient, e.g. 409; at least one reason or code is required
+	// Default: empty
+	Codes []int32 `protobuf:"varint,5,rep,packed,name=codes,proto3" json:"codes,omitempty"`
+	// Total number of handler runs, the first one included; at most 10
+	// Default: 3
+	MaxAttempts int32 `protobuf:"varint,6,opt,name=max_attempts,json=maxAttempts,proto3" json:"max_attempts,omitempty"`
+	// Delay before the first retry; it doubles per retry with jitter
+	// Default: 10ms
+	InitialBackoff *durationpb.Duration `protobuf:"bytes,7,opt,name=initial_backoff,json=initialBackoff,proto3" json:"initial_backoff,omitempty"`
+	// Upper bound of the delay between retries
+	// Default: 200ms
+	MaxBackoff    *durationpb.Duration `protobuf:"bytes,8,opt,name=max_backoff,json=maxBackoff,proto3" json:"max_backoff,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PerformanceConfig) Reset() {
-	*x = PerformanceConfig{}
-	mi := &file_http_proto_msgTypes[6]
+func (x *HandlerRetryConfig) Reset() {
+	*x = HandlerRetryConfig{}
+	mi := &file_http_proto_msgTypes[91]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PerformanceConfig) String() string {
+func (x *HandlerRetryConfig) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PerformanceConfig) ProtoMessage() {}
+func (*HandlerRetryConfig) ProtoMessage() {}
 
-func (x *PerformanceConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_http_proto_msgTypes[6]
+func (x *HandlerRetryConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[91]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -698,108 +8587,102 @@ func (x *PerformanceConfig) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PerformanceConfig.ProtoReflect.Descriptor instead.
-func (*PerformanceConfig) Descriptor() ([]byte, []int) {
-	return file_http_proto_rawDescGZIP(), []int{6}
-}
-
-func (x *PerformanceConfig) GetMaxConnections() int32 {
-	if x != nil {
-		return x.MaxConnections
-	}
-	return 0
+// Deprecated: Use HandlerRetryConfig.ProtoReflect.Descriptor instead.
+func (*HandlerRetryConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{91}
 }
 
-func (x *PerformanceConfig) GetMaxConcurrentRequests() int32 {
+func (x *HandlerRetryConfig) GetEnabled() bool {
 	if x != nil {
-		return x.MaxConcurrentRequests
+		return x.Enabled
 	}
-	return 0
+	return false
 }
 
-func (x *PerformanceConfig) GetReadBufferSize() int32 {
+func (x *HandlerRetryConfig) GetOperations() []string {
 	if x != nil {
-		return x.ReadBufferSize
+		return x.Operations
 	}
-	return 0
+	return nil
 }
 
-func (x *PerformanceConfig) GetWriteBufferSize() int32 {
+func (x *HandlerRetryConfig) GetMethods() []string {
 	if x != nil {
-		return x.WriteBufferSize
+		return x.Methods
 	}
-	return 0
+	return nil
 }
 
-func (x *PerformanceConfig) GetConnectionPool() *ConnectionPoolConfig {
+func (x *HandlerRetryConfig) GetReasons() []string {
 	if x != nil {
-		return x.ConnectionPool
+		return x.Reasons
 	}
 	return nil
 }
 
-func (x *PerformanceConfig) GetReadTimeout() *durationpb.Duration {
+func (x *HandlerRetryConfig) GetCodes() []int32 {
 	if x != nil {
-		return x.ReadTimeout
+		return x.Codes
 	}
 	return nil
 }
 
-func (x *PerformanceConfig) GetWriteTimeout() *durationpb.Duration {
+func (x *HandlerRetryConfig) GetMaxAttempts() int32 {
 	if x != nil {
-		return x.WriteTimeout
+		return x.MaxAttempts
 	}
-	return nil
+	return 0
 }
 
-func (x *PerformanceConfig) GetIdleTimeout() *durationpb.Duration {
+func (x *HandlerRetryConfig) GetInitialBackoff() *durationpb.Duration {
 	if x != nil {
-		return x.IdleTimeout
+		return x.InitialBackoff
 	}
 	return nil
 }
 
-func (x *PerformanceConfig) GetReadHeaderTimeout() *durationpb.Duration {
+func (x *HandlerRetryConfig) GetMaxBackoff() *durationpb.Duration {
 	if x != nil {
-		return x.ReadHeaderTimeout
+		return x.MaxBackoff
 	}
 	return nil
 }
 
-// Connection pool configuration
-type ConnectionPoolConfig struct {
+// RetryBudgetConfig reads the caller's attempt number and attempt budget from request headers.
+// Attempts are numbered from 1; a request without the attempt header is a first attempt.
+type RetryBudgetConfig struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// Maximum number of idle connections
-	// Default: 100
-	MaxIdleConns int32 `protobuf:"varint,1,opt,name=max_idle_conns,json=maxIdleConns,proto3" json:"max_idle_conns,omitempty"`
-	// Maximum number of idle connections per host
-	// Default: 10
-	MaxIdleConnsPerHost int32 `protobuf:"varint,2,opt,name=max_idle_conns_per_host,json=maxIdleConnsPerHost,proto3" json:"max_idle_conns_per_host,omitempty"`
-	// Maximum number of connections per host
-	// Default: 100
-	MaxConnsPerHost int32 `protobuf:"varint,3,opt,name=max_conns_per_host,json=maxConnsPerHost,proto3" json:"max_conns_per_host,omitempty"`
-	// Connection reuse duration
-	// Default: 30s
-	KeepAliveDuration *durationpb.Duration `protobuf:"bytes,4,opt,name=keep_alive_duration,json=keepAliveDuration,proto3" json:"keep_alive_duration,omitempty"`
+	// Whether to enforce retry budgets and record per-attempt latency
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Server-side cap on the attempt number, applied even when the caller declares a larger budget.
+	// Default: 0 (only the caller-declared budget applies)
+	MaxAttempts int32 `protobuf:"varint,2,opt,name=max_attempts,json=maxAttempts,proto3" json:"max_attempts,omitempty"`
+	// Header carrying the attempt number
+	// Default: "X-Retry-Attempt"
+	AttemptHeader string `protobuf:"bytes,3,opt,name=attempt_header,json=attemptHeader,proto3" json:"attempt_header,omitempty"`
+	// Header carrying the caller's maximum number of attempts
+	// Default: "X-Retry-Max-Attempts"
+	MaxAttemptsHeader string `protobuf:"bytes,4,opt,name=max_attempts_header,json=maxAttemptsHeader,proto3" json:"max_attempts_header,omitempty"`
 	unknownFields     protoimpl.UnknownFields
 	sizeCache         protoimpl.SizeCache
 }
 
-func (x *ConnectionPoolConfig) Reset() {
-	*x = ConnectionPoolConfig{}
-	mi := &file_http_proto_msgTypes[7]
+func (x *RetryBudgetConfig) Reset() {
+	*x = RetryBudgetConfig{}
+	mi := &file_http_proto_msgTypes[92]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ConnectionPoolConfig) String() string {
+func (x *RetryBudgetConfig) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ConnectionPoolConfig) ProtoMessage() {}
+func (*RetryBudgetConfig) ProtoMessage() {}
 
-func (x *ConnectionPoolConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_http_proto_msgTypes[7]
+func (x *RetryBudgetConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[92]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -810,82 +8693,75 @@ func (x *ConnectionPoolConfig) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ConnectionPoolConfig.ProtoReflect.Descriptor instead.
-func (*ConnectionPoolConfig) Descriptor() ([]byte, []int) {
-	return file_http_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use RetryBudgetConfig.ProtoReflect.Descriptor instead.
+func (*RetryBudgetConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{92}
 }
 
-func (x *ConnectionPoolConfig) GetMaxIdleConns() int32 {
+func (x *RetryBudgetConfig) GetEnabled() bool {
 	if x != nil {
-		return x.MaxIdleConns
+		return x.Enabled
 	}
-	return 0
+	return false
 }
 
-func (x *ConnectionPoolConfig) GetMaxIdleConnsPerHost() int32 {
+func (x *RetryBudgetConfig) GetMaxAttempts() int32 {
 	if x != nil {
-		return x.MaxIdleConnsPerHost
+		return x.MaxAttempts
 	}
 	return 0
 }
 
-func (x *ConnectionPoolConfig) GetMaxConnsPerHost() int32 {
+func (x *RetryBudgetConfig) GetAttemptHeader() string {
 	if x != nil {
-		return x.MaxConnsPerHost
+		return x.AttemptHeader
 	}
-	return 0
+	return ""
 }
 
-func (x *ConnectionPoolConfig) GetKeepAliveDuration() *durationpb.Duration {
+func (x *RetryBudgetConfig) GetMaxAttemptsHeader() string {
 	if x != nil {
-		return x.KeepAliveDuration
+		return x.MaxAttemptsHeader
 	}
-	return nil
+	return ""
 }
 
-// Middleware configuration
-type MiddlewareConfig struct {
+// DeadlinePropagationConfig derives the request context deadline from the remaining budget the caller
+// declares, so chains of services share one deadline instead of stacking static per-hop timeouts.
+type DeadlinePropagationConfig struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// Whether to enable tracing middleware
-	// Default: true
-	EnableTracing bool `protobuf:"varint,1,opt,name=enable_tracing,json=enableTracing,proto3" json:"enable_tracing,omitempty"`
-	// Whether to enable logging middleware
-	// Default: true
-	EnableLogging bool `protobuf:"varint,2,opt,name=enable_logging,json=enableLogging,proto3" json:"enable_logging,omitempty"`
-	// Whether to enable recovery middleware
-	// Default: true
-	EnableRecovery bool `protobuf:"varint,3,opt,name=enable_recovery,json=enableRecovery,proto3" json:"enable_recovery,omitempty"`
-	// Whether to enable validation middleware
-	// Default: true
-	EnableValidation bool `protobuf:"varint,4,opt,name=enable_validation,json=enableValidation,proto3" json:"enable_validation,omitempty"`
-	// Whether to enable rate limiting middleware
-	// Default: true
-	EnableRateLimit bool `protobuf:"varint,5,opt,name=enable_rate_limit,json=enableRateLimit,proto3" json:"enable_rate_limit,omitempty"`
-	// Whether to enable metrics middleware
-	// Default: true
-	EnableMetrics bool `protobuf:"varint,6,opt,name=enable_metrics,json=enableMetrics,proto3" json:"enable_metrics,omitempty"`
-	// Custom middleware configuration (key-value pairs)
-	// Default: empty
-	CustomMiddleware map[string]string `protobuf:"bytes,7,rep,name=custom_middleware,json=customMiddleware,proto3" json:"custom_middleware,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	// Whether to honour client timeout headers
+	// Default: false
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Headers to read, in order of precedence. X-Request-Timeout accepts a Go duration ("1.5s") or
+	// integer milliseconds; grpc-timeout uses the gRPC wire format ("100m", "2S").
+	// Default: ["X-Request-Timeout", "grpc-timeout"]
+	Headers []string `protobuf:"bytes,2,rep,name=headers,proto3" json:"headers,omitempty"`
+	// Upper bound for a declared timeout. The server timeout still applies when it is shorter.
+	// Default: no extra cap
+	MaxTimeout *durationpb.Duration `protobuf:"bytes,3,opt,name=max_timeout,json=maxTimeout,proto3" json:"max_timeout,omitempty"`
+	// Requests declaring less remaining budget than this are rejected with 504 without running the handler.
+	// Default: 0 (only exhausted budgets are rejected)
+	MinTimeout    *durationpb.Duration `protobuf:"bytes,4,opt,name=min_timeout,json=minTimeout,proto3" json:"min_timeout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *MiddlewareConfig) Reset() {
-	*x = MiddlewareConfig{}
-	mi := &file_http_proto_msgTypes[8]
+func (x *DeadlinePropagationConfig) Reset() {
+	*x = DeadlinePropagationConfig{}
+	mi := &file_http_proto_msgTypes[93]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MiddlewareConfig) String() string {
+func (x *DeadlinePropagationConfig) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MiddlewareConfig) ProtoMessage() {}
+func (*DeadlinePropagationConfig) ProtoMessage() {}
 
-func (x *MiddlewareConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_http_proto_msgTypes[8]
+func (x *DeadlinePropagationConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_http_proto_msgTypes[93]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -896,56 +8772,35 @@ func (x *MiddlewareConfig) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MiddlewareConfig.ProtoReflect.Descriptor instead.
-func (*MiddlewareConfig) Descriptor() ([]byte, []int) {
-	return file_http_proto_rawDescGZIP(), []int{8}
-}
-
-func (x *MiddlewareConfig) GetEnableTracing() bool {
-	if x != nil {
-		return x.EnableTracing
-	}
-	return false
-}
-
-func (x *MiddlewareConfig) GetEnableLogging() bool {
-	if x != nil {
-		return x.EnableLogging
-	}
-	return false
-}
-
-func (x *MiddlewareConfig) GetEnableRecovery() bool {
-	if x != nil {
-		return x.EnableRecovery
-	}
-	return false
+// Deprecated: Use DeadlinePropagationConfig.ProtoReflect.Descriptor instead.
+func (*DeadlinePropagationConfig) Descriptor() ([]byte, []int) {
+	return file_http_proto_rawDescGZIP(), []int{93}
 }
 
-func (x *MiddlewareConfig) GetEnableValidation() bool {
+func (x *DeadlinePropagationConfig) GetEnabled() bool {
 	if x != nil {
-		return x.EnableValidation
+		return x.Enabled
 	}
 	return false
 }
 
-func (x *MiddlewareConfig) GetEnableRateLimit() bool {
+func (x *DeadlinePropagationConfig) GetHeaders() []string {
 	if x != nil {
-		return x.EnableRateLimit
+		return x.Headers
 	}
-	return false
+	return nil
 }
 
-func (x *MiddlewareConfig) GetEnableMetrics() bool {
+func (x *DeadlinePropagationConfig) GetMaxTimeout() *durationpb.Duration {
 	if x != nil {
-		return x.EnableMetrics
+		return x.MaxTimeout
 	}
-	return false
+	return nil
 }
 
-func (x *MiddlewareConfig) GetCustomMiddleware() map[string]string {
+func (x *DeadlinePropagationConfig) GetMinTimeout() *durationpb.Duration {
 	if x != nil {
-		return x.CustomMiddleware
+		return x.MinTimeout
 	}
 	return nil
 }
@@ -968,7 +8823,7 @@ type GracefulShutdownConfig struct {
 
 func (x *GracefulShutdownConfig) Reset() {
 	*x = GracefulShutdownConfig{}
-	mi := &file_http_proto_msgTypes[9]
+	mi := &file_http_proto_msgTypes[94]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -980,7 +8835,7 @@ func (x *GracefulShutdownConfig) String() string {
 func (*GracefulShutdownConfig) ProtoMessage() {}
 
 func (x *GracefulShutdownConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_http_proto_msgTypes[9]
+	mi := &file_http_proto_msgTypes[94]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -993,7 +8848,7 @@ func (x *GracefulShutdownConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GracefulShutdownConfig.ProtoReflect.Descriptor instead.
 func (*GracefulShutdownConfig) Descriptor() ([]byte, []int) {
-	return file_http_proto_rawDescGZIP(), []int{9}
+	return file_http_proto_rawDescGZIP(), []int{94}
 }
 
 func (x *GracefulShutdownConfig) GetShutdownTimeout() *durationpb.Duration {
@@ -1041,7 +8896,7 @@ type CircuitBreakerConfig struct {
 
 func (x *CircuitBreakerConfig) Reset() {
 	*x = CircuitBreakerConfig{}
-	mi := &file_http_proto_msgTypes[10]
+	mi := &file_http_proto_msgTypes[95]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1053,7 +8908,7 @@ func (x *CircuitBreakerConfig) String() string {
 func (*CircuitBreakerConfig) ProtoMessage() {}
 
 func (x *CircuitBreakerConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_http_proto_msgTypes[10]
+	mi := &file_http_proto_msgTypes[95]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1066,7 +8921,7 @@ func (x *CircuitBreakerConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CircuitBreakerConfig.ProtoReflect.Descriptor instead.
 func (*CircuitBreakerConfig) Descriptor() ([]byte, []int) {
-	return file_http_proto_rawDescGZIP(), []int{10}
+	return file_http_proto_rawDescGZIP(), []int{95}
 }
 
 func (x *CircuitBreakerConfig) GetEnabled() bool {
@@ -1109,7 +8964,7 @@ var File_http_proto protoreflect.FileDescriptor
 const file_http_proto_rawDesc = "" +
 	"\n" +
 	"\n" +
-	"http.proto\x12\x19lynx.protobuf.plugin.http\x1a\x1egoogle/protobuf/duration.proto\"\x97\x05\n" +
+	"http.proto\x12\x19lynx.protobuf.plugin.http\x1a\x1egoogle/protobuf/duration.proto\"\xd4\x11\n" +
 	"\x04http\x12\x18\n" +
 	"\anetwork\x18\x01 \x01(\tR\anetwork\x12\x12\n" +
 	"\x04addr\x18\x02 \x01(\tR\x04addr\x12\x1d\n" +
@@ -1127,7 +8982,380 @@ const file_http_proto_rawDesc = "" +
 	"middleware\x12^\n" +
 	"\x11graceful_shutdown\x18\n" +
 	" \x01(\v21.lynx.protobuf.plugin.http.GracefulShutdownConfigR\x10gracefulShutdown\x12X\n" +
-	"\x0fcircuit_breaker\x18\v \x01(\v2/.lynx.protobuf.plugin.http.CircuitBreakerConfigR\x0ecircuitBreaker\"\xc0\x03\n" +
+	"\x0fcircuit_breaker\x18\v \x01(\v2/.lynx.protobuf.plugin.http.CircuitBreakerConfigR\x0ecircuitBreaker\x12B\n" +
+	"\asystemd\x18\f \x01(\v2(.lynx.protobuf.plugin.http.SystemdConfigR\asystemd\x12U\n" +
+	"\x0eproxy_protocol\x18\r \x01(\v2..lynx.protobuf.plugin.http.ProxyProtocolConfigR\rproxyProtocol\x12E\n" +
+	"\bresponse\x18\x0e \x01(\v2).lynx.protobuf.plugin.http.ResponseConfigR\bresponse\x12B\n" +
+	"\agraphql\x18\x0f \x01(\v2(.lynx.protobuf.plugin.http.GraphQLConfigR\agraphql\x12B\n" +
+	"\ajsonrpc\x18\x10 \x01(\v2(.lynx.protobuf.plugin.http.JSONRPCConfigR\ajsonrpc\x12<\n" +
+	"\x05batch\x18\x11 \x01(\v2&.lynx.protobuf.plugin.http.BatchConfigR\x05batch\x12B\n" +
+	"\arequest\x18\x12 \x01(\v2(.lynx.protobuf.plugin.http.RequestConfigR\arequest\x12L\n" +
+	"\vclient_info\x18\x13 \x01(\v2+.lynx.protobuf.plugin.http.ClientInfoConfigR\n" +
+	"clientInfo\x12K\n" +
+	"\vsigned_urls\x18\x14 \x01(\v2*.lynx.protobuf.plugin.http.SignedURLConfigR\n" +
+	"signedUrls\x12B\n" +
+	"\asession\x18\x15 \x01(\v2(.lynx.protobuf.plugin.http.SessionConfigR\asession\x12B\n" +
+	"\arouting\x18\x16 \x01(\v2(.lynx.protobuf.plugin.http.RoutingConfigR\arouting\x12b\n" +
+	"\x13load_balancer_hints\x18\x17 \x01(\v22.lynx.protobuf.plugin.http.LoadBalancerHintsConfigR\x11loadBalancerHints\x12\x18\n" +
+	"\aprofile\x18\x18 \x01(\tR\aprofile\x12K\n" +
+	"\n" +
+	"kubernetes\x18\x19 \x01(\v2+.lynx.protobuf.plugin.http.KubernetesConfigR\n" +
+	"kubernetes\x12R\n" +
+	"\rvirtual_hosts\x18\x1a \x01(\v2-.lynx.protobuf.plugin.http.VirtualHostsConfigR\fvirtualHosts\x12?\n" +
+	"\x06tenant\x18\x1b \x01(\v2'.lynx.protobuf.plugin.http.TenantConfigR\x06tenant\x12H\n" +
+	"\tresidency\x18\x1c \x01(\v2*.lynx.protobuf.plugin.http.ResidencyConfigR\tresidency\x12L\n" +
+	"\vgrpc_parity\x18\x1d \x01(\v2+.lynx.protobuf.plugin.http.GrpcParityConfigR\n" +
+	"grpcParity\x12L\n" +
+	"\vasync_tasks\x18\x1e \x01(\v2+.lynx.protobuf.plugin.http.AsyncTasksConfigR\n" +
+	"asyncTasks\x12K\n" +
+	"\n" +
+	"operations\x18\x1f \x01(\v2+.lynx.protobuf.plugin.http.OperationsConfigR\n" +
+	"operations\x12^\n" +
+	"\x11collection_export\x18  \x01(\v21.lynx.protobuf.plugin.http.CollectionExportConfigR\x10collectionExport\x129\n" +
+	"\x04mock\x18! \x01(\v2%.lynx.protobuf.plugin.http.MockConfigR\x04mock\"\xb3\x01\n" +
+	"\n" +
+	"MockConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x1e\n" +
+	"\n" +
+	"operations\x18\x02 \x03(\tR\n" +
+	"operations\x12$\n" +
+	"\runimplemented\x18\x03 \x01(\bR\runimplemented\x12E\n" +
+	"\tresponses\x18\x04 \x03(\v2'.lynx.protobuf.plugin.http.MockResponseR\tresponses\"\x8a\x01\n" +
+	"\fMockResponse\x12\x1c\n" +
+	"\toperation\x18\x01 \x01(\tR\toperation\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\tR\x04data\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\x05R\x06status\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12\x18\n" +
+	"\amessage\x18\x05 \x01(\tR\amessage\"u\n" +
+	"\x16CollectionExportConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x19\n" +
+	"\bbase_url\x18\x04 \x01(\tR\abaseUrl\"\xaa\x01\n" +
+	"\x10OperationsConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x14\n" +
+	"\x05store\x18\x03 \x01(\tR\x05store\x12+\n" +
+	"\x03ttl\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\x03ttl\x12%\n" +
+	"\x0emax_operations\x18\x05 \x01(\x05R\rmaxOperations\"\xf1\x01\n" +
+	"\x10AsyncTasksConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x14\n" +
+	"\x05queue\x18\x03 \x01(\tR\x05queue\x12\x18\n" +
+	"\aworkers\x18\x04 \x01(\x05R\aworkers\x12\x1f\n" +
+	"\vmax_pending\x18\x05 \x01(\x05R\n" +
+	"maxPending\x128\n" +
+	"\n" +
+	"result_ttl\x18\x06 \x01(\v2\x19.google.protobuf.DurationR\tresultTtl\x12$\n" +
+	"\x0emax_body_bytes\x18\a \x01(\x03R\fmaxBodyBytes\"\x8e\x01\n" +
+	"\x10GrpcParityConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x1f\n" +
+	"\vhealth_path\x18\x02 \x01(\tR\n" +
+	"healthPath\x12#\n" +
+	"\rservices_path\x18\x03 \x01(\tR\fservicesPath\x12\x1a\n" +
+	"\bservices\x18\x04 \x03(\tR\bservices\"\xbd\x04\n" +
+	"\x0fResidencyConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x16\n" +
+	"\x06region\x18\x02 \x01(\tR\x06region\x12\x1d\n" +
+	"\n" +
+	"region_env\x18\x03 \x01(\tR\tregionEnv\x12\x16\n" +
+	"\x06header\x18\x04 \x01(\tR\x06header\x12 \n" +
+	"\venforcement\x18\x05 \x01(\tR\venforcement\x12\x1a\n" +
+	"\bresolver\x18\x06 \x01(\tR\bresolver\x12d\n" +
+	"\x0etenant_regions\x18\a \x03(\v2=.lynx.protobuf.plugin.http.ResidencyConfig.TenantRegionsEntryR\rtenantRegions\x12j\n" +
+	"\x10region_endpoints\x18\b \x03(\v2?.lynx.protobuf.plugin.http.ResidencyConfig.RegionEndpointsEntryR\x0fregionEndpoints\x12+\n" +
+	"\x11exempt_operations\x18\t \x03(\tR\x10exemptOperations\x1a@\n" +
+	"\x12TenantRegionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1aB\n" +
+	"\x14RegionEndpointsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xe9\x03\n" +
+	"\fTenantConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x12!\n" +
+	"\fbase_domains\x18\x03 \x03(\tR\vbaseDomains\x12\x1f\n" +
+	"\vpath_prefix\x18\x04 \x01(\tR\n" +
+	"pathPrefix\x12\x1a\n" +
+	"\brequired\x18\x05 \x01(\bR\brequired\x12K\n" +
+	"\x06static\x18\x06 \x03(\v23.lynx.protobuf.plugin.http.TenantConfig.StaticEntryR\x06static\x12\x1a\n" +
+	"\bresolver\x18\a \x01(\tR\bresolver\x126\n" +
+	"\tcache_ttl\x18\b \x01(\v2\x19.google.protobuf.DurationR\bcacheTtl\x12\x1d\n" +
+	"\n" +
+	"cache_size\x18\t \x01(\x05R\tcacheSize\x12\x1f\n" +
+	"\vbaggage_key\x18\n" +
+	" \x01(\tR\n" +
+	"baggageKey\x12+\n" +
+	"\x11exempt_operations\x18\v \x03(\tR\x10exemptOperations\x1a9\n" +
+	"\vStaticEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x8a\x01\n" +
+	"\x12VirtualHostsConfig\x12B\n" +
+	"\x05hosts\x18\x01 \x03(\v2,.lynx.protobuf.plugin.http.VirtualHostConfigR\x05hosts\x120\n" +
+	"\x14reject_unknown_hosts\x18\x02 \x01(\bR\x12rejectUnknownHosts\"\xa7\x01\n" +
+	"\x11VirtualHostConfig\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\adomains\x18\x02 \x03(\tR\adomains\x12\x1e\n" +
+	"\n" +
+	"operations\x18\x03 \x03(\tR\n" +
+	"operations\x12\"\n" +
+	"\rtls_cert_file\x18\x04 \x01(\tR\vtlsCertFile\x12 \n" +
+	"\ftls_key_file\x18\x05 \x01(\tR\n" +
+	"tlsKeyFile\"\xf0\x02\n" +
+	"\x10KubernetesConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12 \n" +
+	"\fpod_name_env\x18\x02 \x01(\tR\n" +
+	"podNameEnv\x12*\n" +
+	"\x11pod_namespace_env\x18\x03 \x01(\tR\x0fpodNamespaceEnv\x12\"\n" +
+	"\rnode_name_env\x18\x04 \x01(\tR\vnodeNameEnv\x12#\n" +
+	"\rliveness_path\x18\x05 \x01(\tR\flivenessPath\x12%\n" +
+	"\x0ereadiness_path\x18\x06 \x01(\tR\rreadinessPath\x12!\n" +
+	"\fstartup_path\x18\a \x01(\tR\vstartupPath\x12!\n" +
+	"\fprestop_path\x18\b \x01(\tR\vprestopPath\x12>\n" +
+	"\rprestop_delay\x18\t \x01(\v2\x19.google.protobuf.DurationR\fprestopDelay\"\xe5\x01\n" +
+	"\x17LoadBalancerHintsConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12:\n" +
+	"\vdrain_delay\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"drainDelay\x129\n" +
+	"\x19fail_health_when_draining\x18\x03 \x01(\bR\x16failHealthWhenDraining\x129\n" +
+	"\x19fail_health_when_degraded\x18\x04 \x01(\bR\x16failHealthWhenDegraded\"\x92\x02\n" +
+	"\rRoutingConfig\x12*\n" +
+	"\x11disable_auto_head\x18\x01 \x01(\bR\x0fdisableAutoHead\x120\n" +
+	"\x14disable_auto_options\x18\x02 \x01(\bR\x12disableAutoOptions\x12H\n" +
+	"\tnot_found\x18\x03 \x01(\v2+.lynx.protobuf.plugin.http.FallbackResponseR\bnotFound\x12Y\n" +
+	"\x12method_not_allowed\x18\x04 \x01(\v2+.lynx.protobuf.plugin.http.FallbackResponseR\x10methodNotAllowed\"\xbc\x01\n" +
+	"\x10FallbackResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\x12\x12\n" +
+	"\x04body\x18\x02 \x01(\tR\x04body\x12!\n" +
+	"\fcontent_type\x18\x03 \x01(\tR\vcontentType\x12\x1a\n" +
+	"\bredirect\x18\x04 \x01(\tR\bredirect\x12\x12\n" +
+	"\x04file\x18\x05 \x01(\tR\x04file\x12)\n" +
+	"\x10exclude_prefixes\x18\x06 \x03(\tR\x0fexcludePrefixes\"\xbe\x03\n" +
+	"\rSessionConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x16\n" +
+	"\x06secret\x18\x02 \x01(\tR\x06secret\x12)\n" +
+	"\x10previous_secrets\x18\x03 \x03(\tR\x0fpreviousSecrets\x12\x1f\n" +
+	"\vcookie_name\x18\x04 \x01(\tR\n" +
+	"cookieName\x12#\n" +
+	"\rcookie_domain\x18\x05 \x01(\tR\fcookieDomain\x12\x1f\n" +
+	"\vcookie_path\x18\x06 \x01(\tR\n" +
+	"cookiePath\x122\n" +
+	"\x15allow_insecure_cookie\x18\a \x01(\bR\x13allowInsecureCookie\x12\x1b\n" +
+	"\tsame_site\x18\b \x01(\tR\bsameSite\x12<\n" +
+	"\fidle_timeout\x18\t \x01(\v2\x19.google.protobuf.DurationR\vidleTimeout\x12D\n" +
+	"\x10absolute_timeout\x18\n" +
+	" \x01(\v2\x19.google.protobuf.DurationR\x0fabsoluteTimeout\x12\x14\n" +
+	"\x05store\x18\v \x01(\tR\x05store\"\xdc\x01\n" +
+	"\x0fSignedURLConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x16\n" +
+	"\x06secret\x18\x02 \x01(\tR\x06secret\x12)\n" +
+	"\x10previous_secrets\x18\x03 \x03(\tR\x0fpreviousSecrets\x12\x1e\n" +
+	"\n" +
+	"operations\x18\x04 \x03(\tR\n" +
+	"operations\x12#\n" +
+	"\rexpires_param\x18\x05 \x01(\tR\fexpiresParam\x12'\n" +
+	"\x0fsignature_param\x18\x06 \x01(\tR\x0esignatureParam\"\x8e\x03\n" +
+	"\x10ClientInfoConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12,\n" +
+	"\x12app_version_header\x18\x02 \x01(\tR\x10appVersionHeader\x12'\n" +
+	"\x0fplatform_header\x18\x03 \x01(\tR\x0eplatformHeader\x12\x1b\n" +
+	"\tapp_names\x18\x04 \x03(\tR\bappNames\x12\x18\n" +
+	"\ametrics\x18\x05 \x01(\bR\ametrics\x12!\n" +
+	"\fmax_versions\x18\x06 \x01(\rR\vmaxVersions\x12N\n" +
+	"\fmin_versions\x18\a \x03(\v2+.lynx.protobuf.plugin.http.MinClientVersionR\vminVersions\x122\n" +
+	"\x15upgrade_required_code\x18\b \x01(\x05R\x13upgradeRequiredCode\x12+\n" +
+	"\x11exempt_operations\x18\t \x03(\tR\x10exemptOperations\"l\n" +
+	"\x10MinClientVersion\x12\x1a\n" +
+	"\bplatform\x18\x01 \x01(\tR\bplatform\x12\x1f\n" +
+	"\vmin_version\x18\x02 \x01(\tR\n" +
+	"minVersion\x12\x1b\n" +
+	"\tstore_url\x18\x03 \x01(\tR\bstoreUrl\"\xc8\x03\n" +
+	"\rRequestConfig\x120\n" +
+	"\x14populate_field_masks\x18\x01 \x01(\bR\x12populateFieldMasks\x12(\n" +
+	"\x10capture_raw_body\x18\x02 \x01(\bR\x0ecaptureRawBody\x12+\n" +
+	"\x12raw_body_max_bytes\x18\x03 \x01(\x03R\x0frawBodyMaxBytes\x12Q\n" +
+	"\rcontent_types\x18\x04 \x01(\v2,.lynx.protobuf.plugin.http.ContentTypeConfigR\fcontentTypes\x12Q\n" +
+	"\rdecode_errors\x18\x05 \x01(\v2,.lynx.protobuf.plugin.http.DecodeErrorConfigR\fdecodeErrors\x12<\n" +
+	"\x05query\x18\x06 \x01(\v2&.lynx.protobuf.plugin.http.QueryConfigR\x05query\x12J\n" +
+	"\bdefaults\x18\a \x03(\v2..lynx.protobuf.plugin.http.RequestDefaultsRuleR\bdefaults\"\xd5\x02\n" +
+	"\x13RequestDefaultsRule\x12\x1c\n" +
+	"\toperation\x18\x01 \x01(\tR\toperation\x12R\n" +
+	"\x06values\x18\x02 \x03(\v2:.lynx.protobuf.plugin.http.RequestDefaultsRule.ValuesEntryR\x06values\x12U\n" +
+	"\aheaders\x18\x03 \x03(\v2;.lynx.protobuf.plugin.http.RequestDefaultsRule.HeadersEntryR\aheaders\x1a9\n" +
+	"\vValuesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a:\n" +
+	"\fHeadersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"B\n" +
+	"\vQueryConfig\x12\x1f\n" +
+	"\vcomma_lists\x18\x01 \x01(\bR\n" +
+	"commaLists\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\x05R\x04code\"\xc1\x02\n" +
+	"\x11DecodeErrorConfig\x12*\n" +
+	"\x11syntax_error_code\x18\x01 \x01(\x05R\x0fsyntaxErrorCode\x12,\n" +
+	"\x12unknown_field_code\x18\x02 \x01(\x05R\x10unknownFieldCode\x12,\n" +
+	"\x12type_mismatch_code\x18\x03 \x01(\x05R\x10typeMismatchCode\x12&\n" +
+	"\x0fempty_body_code\x18\x04 \x01(\x05R\remptyBodyCode\x12*\n" +
+	"\x11reject_empty_body\x18\x05 \x01(\bR\x0frejectEmptyBody\x12#\n" +
+	"\rexpose_detail\x18\x06 \x01(\bR\fexposeDetail\x12+\n" +
+	"\x11strict_operations\x18\a \x03(\tR\x10strictOperations\"\x9d\x01\n" +
+	"\x11ContentTypeConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x18\n" +
+	"\aallowed\x18\x02 \x03(\tR\aallowed\x12@\n" +
+	"\x05rules\x18\x03 \x03(\v2*.lynx.protobuf.plugin.http.ContentTypeRuleR\x05rules\x12\x12\n" +
+	"\x04code\x18\x04 \x01(\x05R\x04code\"I\n" +
+	"\x0fContentTypeRule\x12\x1c\n" +
+	"\toperation\x18\x01 \x01(\tR\toperation\x12\x18\n" +
+	"\aallowed\x18\x02 \x03(\tR\aallowed\"\xad\x01\n" +
+	"\vBatchConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12!\n" +
+	"\fmax_requests\x18\x03 \x01(\x05R\vmaxRequests\x12'\n" +
+	"\x0fmax_concurrency\x18\x04 \x01(\x05R\x0emaxConcurrency\x12$\n" +
+	"\x0emax_body_bytes\x18\x05 \x01(\x03R\fmaxBodyBytes\"\x89\x01\n" +
+	"\rJSONRPCConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12$\n" +
+	"\x0emax_batch_size\x18\x03 \x01(\x05R\fmaxBatchSize\x12$\n" +
+	"\x0emax_body_bytes\x18\x04 \x01(\x03R\fmaxBodyBytes\"\x93\x01\n" +
+	"\rGraphQLConfig\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x1d\n" +
+	"\n" +
+	"enable_get\x18\x02 \x01(\bR\tenableGet\x12)\n" +
+	"\x10resolver_metrics\x18\x03 \x01(\bR\x0fresolverMetrics\x12$\n" +
+	"\x0emax_body_bytes\x18\x04 \x01(\x03R\fmaxBodyBytes\"\xd1\b\n" +
+	"\x0eResponseConfig\x124\n" +
+	"\x16enable_field_filtering\x18\x01 \x01(\bR\x14enableFieldFiltering\x12,\n" +
+	"\x12field_filter_param\x18\x02 \x01(\tR\x10fieldFilterParam\x12%\n" +
+	"\x0eenable_jsonapi\x18\x03 \x01(\bR\renableJsonapi\x12Q\n" +
+	"\n" +
+	"size_limit\x18\x04 \x01(\v22.lynx.protobuf.plugin.http.ResponseSizeLimitConfigR\tsizeLimit\x12R\n" +
+	"\rcache_control\x18\x05 \x01(\v2-.lynx.protobuf.plugin.http.CacheControlConfigR\fcacheControl\x12N\n" +
+	"\vcompression\x18\x06 \x01(\v2,.lynx.protobuf.plugin.http.CompressionConfigR\vcompression\x12J\n" +
+	"\asigning\x18\a \x01(\v20.lynx.protobuf.plugin.http.ResponseSigningConfigR\asigning\x12[\n" +
+	"\x10field_encryption\x18\b \x01(\v20.lynx.protobuf.plugin.http.FieldEncryptionConfigR\x0ffieldEncryption\x12?\n" +
+	"\x06export\x18\t \x01(\v2'.lynx.protobuf.plugin.http.ExportConfigR\x06export\x12M\n" +
+	"\bprotobuf\x18\n" +
+	" \x01(\v21.lynx.protobuf.plugin.http.ProtobufResponseConfigR\bprotobuf\x12D\n" +
+	"\x05codes\x18\v \x01(\v2..lynx.protobuf.plugin.http.EnvelopeCodesConfigR\x05codes\x12E\n" +
+	"\bwarnings\x18\f \x01(\v2).lynx.protobuf.plugin.http.WarningsConfigR\bwarnings\x12O\n" +
+	"\fmulti_status\x18\r \x01(\v2,.lynx.protobuf.plugin.http.MultiStatusConfigR\vmultiStatus\x12I\n" +
+	"\n" +
+	"proto_json\x18\x0e \x01(\v2*.lynx.protobuf.plugin.http.ProtoJSONConfigR\tprotoJson\x12[\n" +
+	"\x10header_scrubbing\x18\x0f \x01(\v20.lynx.protobuf.plugin.http.HeaderScrubbingConfigR\x0fheaderScrubbing\"\xde\x01\n" +
+	"\x15HeaderScrubbingConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x16\n" +
+	"\x06remove\x18\x02 \x03(\tR\x06remove\x12W\n" +
+	"\arewrite\x18\x03 \x03(\v2=.lynx.protobuf.plugin.http.HeaderScrubbingConfig.RewriteEntryR\arewrite\x1a:\n" +
+	"\fRewriteEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xcf\x01\n" +
+	"\x0fProtoJSONConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12$\n" +
+	"\x0euse_json_names\x18\x02 \x01(\bR\fuseJsonNames\x12)\n" +
+	"\x10emit_unpopulated\x18\x03 \x01(\bR\x0femitUnpopulated\x12(\n" +
+	"\x10use_enum_numbers\x18\x04 \x01(\bR\x0euseEnumNumbers\x12'\n" +
+	"\x0fdescriptor_sets\x18\x05 \x03(\tR\x0edescriptorSets\"E\n" +
+	"\x11MultiStatusConfig\x120\n" +
+	"\x14partial_success_code\x18\x01 \x01(\x05R\x12partialSuccessCode\"M\n" +
+	"\x0eWarningsConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12!\n" +
+	"\fmax_warnings\x18\x02 \x01(\x05R\vmaxWarnings\"\x98\x01\n" +
+	"\x13EnvelopeCodesConfig\x12&\n" +
+	"\fsuccess_code\x18\x01 \x01(\x05H\x00R\vsuccessCode\x88\x01\x01\x12H\n" +
+	"\n" +
+	"namespaces\x18\x02 \x03(\v2(.lynx.protobuf.plugin.http.CodeNamespaceR\n" +
+	"namespacesB\x0f\n" +
+	"\r_success_code\"K\n" +
+	"\rCodeNamespace\x12\x16\n" +
+	"\x06module\x18\x01 \x01(\tR\x06module\x12\x10\n" +
+	"\x03min\x18\x02 \x01(\x05R\x03min\x12\x10\n" +
+	"\x03max\x18\x03 \x01(\x05R\x03max\"n\n" +
+	"\x16ProtobufResponseConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x1a\n" +
+	"\benvelope\x18\x02 \x01(\bR\benvelope\x12\x1e\n" +
+	"\n" +
+	"operations\x18\x03 \x03(\tR\n" +
+	"operations\"\xa9\x01\n" +
+	"\fExportConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12!\n" +
+	"\fformat_param\x18\x02 \x01(\tR\vformatParam\x12\x1f\n" +
+	"\venable_xlsx\x18\x03 \x01(\bR\n" +
+	"enableXlsx\x12;\n" +
+	"\x05rules\x18\x04 \x03(\v2%.lynx.protobuf.plugin.http.ExportRuleR\x05rules\"\xa8\x01\n" +
+	"\n" +
+	"ExportRule\x12\x1c\n" +
+	"\toperation\x18\x01 \x01(\tR\toperation\x12\x1d\n" +
+	"\n" +
+	"list_field\x18\x02 \x01(\tR\tlistField\x12A\n" +
+	"\acolumns\x18\x03 \x03(\v2'.lynx.protobuf.plugin.http.ExportColumnR\acolumns\x12\x1a\n" +
+	"\bfilename\x18\x04 \x01(\tR\bfilename\"<\n" +
+	"\fExportColumn\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x16\n" +
+	"\x06header\x18\x02 \x01(\tR\x06header\"\x8a\x02\n" +
+	"\x15FieldEncryptionConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12D\n" +
+	"\x05rules\x18\x02 \x03(\v2..lynx.protobuf.plugin.http.FieldEncryptionRuleR\x05rules\x12A\n" +
+	"\x04keys\x18\x03 \x03(\v2-.lynx.protobuf.plugin.http.FieldEncryptionKeyR\x04keys\x12\"\n" +
+	"\ractive_key_id\x18\x04 \x01(\tR\vactiveKeyId\x12*\n" +
+	"\x11client_key_header\x18\x05 \x01(\tR\x0fclientKeyHeader\"K\n" +
+	"\x13FieldEncryptionRule\x12\x1c\n" +
+	"\toperation\x18\x01 \x01(\tR\toperation\x12\x16\n" +
+	"\x06fields\x18\x02 \x03(\tR\x06fields\"6\n" +
+	"\x12FieldEncryptionKey\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\"\xbd\x01\n" +
+	"\x15ResponseSigningConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x1c\n" +
+	"\talgorithm\x18\x02 \x01(\tR\talgorithm\x12\x10\n" +
+	"\x03key\x18\x03 \x01(\tR\x03key\x12\x15\n" +
+	"\x06key_id\x18\x04 \x01(\tR\x05keyId\x12\x16\n" +
+	"\x06header\x18\x05 \x01(\tR\x06header\x12+\n" +
+	"\x11exempt_operations\x18\x06 \x03(\tR\x10exemptOperations\"\xd4\x01\n" +
+	"\x11CompressionConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x19\n" +
+	"\bmin_size\x18\x02 \x01(\x05R\aminSize\x12\x14\n" +
+	"\x05level\x18\x03 \x01(\x05R\x05level\x12+\n" +
+	"\x11exempt_operations\x18\x04 \x03(\tR\x10exemptOperations\x12G\n" +
+	"\x05cache\x18\x05 \x01(\v21.lynx.protobuf.plugin.http.CompressionCacheConfigR\x05cache\"|\n" +
+	"\x16CompressionCacheConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12+\n" +
+	"\x03ttl\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\x03ttl\x12\x1b\n" +
+	"\tmax_bytes\x18\x03 \x01(\x03R\bmaxBytes\"\x9e\x01\n" +
+	"\x12CacheControlConfig\x12A\n" +
+	"\x05rules\x18\x01 \x03(\v2+.lynx.protobuf.plugin.http.CacheControlRuleR\x05rules\x12E\n" +
+	"\adefault\x18\x02 \x01(\v2+.lynx.protobuf.plugin.http.CacheControlRuleR\adefault\"\xb6\x03\n" +
+	"\x10CacheControlRule\x12\x1c\n" +
+	"\toperation\x18\x01 \x01(\tR\toperation\x12\x18\n" +
+	"\amethods\x18\x02 \x03(\tR\amethods\x12\x1e\n" +
+	"\n" +
+	"visibility\x18\x03 \x01(\tR\n" +
+	"visibility\x122\n" +
+	"\amax_age\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\x06maxAge\x124\n" +
+	"\bs_maxage\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\asMaxage\x12\x19\n" +
+	"\bno_store\x18\x06 \x01(\bR\anoStore\x12\x19\n" +
+	"\bno_cache\x18\a \x01(\bR\anoCache\x12'\n" +
+	"\x0fmust_revalidate\x18\b \x01(\bR\x0emustRevalidate\x12\x1c\n" +
+	"\timmutable\x18\t \x01(\bR\timmutable\x12O\n" +
+	"\x16stale_while_revalidate\x18\n" +
+	" \x01(\v2\x19.google.protobuf.DurationR\x14staleWhileRevalidate\x12\x12\n" +
+	"\x04vary\x18\v \x03(\tR\x04vary\"\x91\x01\n" +
+	"\x17ResponseSizeLimitConfig\x12\x1b\n" +
+	"\tmax_bytes\x18\x01 \x01(\x03R\bmaxBytes\x12\x18\n" +
+	"\areplace\x18\x02 \x01(\bR\areplace\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\x05R\x04code\x12+\n" +
+	"\x11exempt_operations\x18\x04 \x03(\tR\x10exemptOperations\"\xb2\x01\n" +
+	"\x13ProxyProtocolConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12#\n" +
+	"\rtrusted_cidrs\x18\x02 \x03(\tR\ftrustedCidrs\x12\x1a\n" +
+	"\brequired\x18\x03 \x01(\bR\brequired\x12@\n" +
+	"\x0eheader_timeout\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\rheaderTimeout\"\x96\x01\n" +
+	"\rSystemdConfig\x12+\n" +
+	"\x11socket_activation\x18\x01 \x01(\bR\x10socketActivation\x12$\n" +
+	"\x0elisten_fd_name\x18\x02 \x01(\tR\flistenFdName\x12\x16\n" +
+	"\x06notify\x18\x03 \x01(\bR\x06notify\x12\x1a\n" +
+	"\bwatchdog\x18\x04 \x01(\bR\bwatchdog\"\x8e\x0e\n" +
 	"\x10MonitoringConfig\x12%\n" +
 	"\x0eenable_metrics\x18\x01 \x01(\bR\renableMetrics\x12!\n" +
 	"\fmetrics_path\x18\x02 \x01(\tR\vmetricsPath\x12\x1f\n" +
@@ -1138,13 +9366,208 @@ const file_http_proto_rawDesc = "" +
 	"\x14enable_route_metrics\x18\x06 \x01(\bR\x12enableRouteMetrics\x12:\n" +
 	"\x19enable_connection_metrics\x18\a \x01(\bR\x17enableConnectionMetrics\x120\n" +
 	"\x14enable_queue_metrics\x18\b \x01(\bR\x12enableQueueMetrics\x129\n" +
-	"\x19enable_error_type_metrics\x18\t \x01(\bR\x16enableErrorTypeMetrics\"\x9d\x02\n" +
+	"\x19enable_error_type_metrics\x18\t \x01(\bR\x16enableErrorTypeMetrics\x12*\n" +
+	"\x11legacy_log_format\x18\n" +
+	" \x01(\bR\x0flegacyLogFormat\x12O\n" +
+	"\fbody_logging\x18\v \x01(\v2,.lynx.protobuf.plugin.http.BodyLoggingConfigR\vbodyLogging\x12J\n" +
+	"\n" +
+	"histograms\x18\f \x01(\v2*.lynx.protobuf.plugin.http.HistogramConfigR\n" +
+	"histograms\x12O\n" +
+	"\fotel_metrics\x18\r \x01(\v2,.lynx.protobuf.plugin.http.OtelMetricsConfigR\votelMetrics\x12U\n" +
+	"\x0ecaller_metrics\x18\x0e \x01(\v2..lynx.protobuf.plugin.http.CallerMetricsConfigR\rcallerMetrics\x126\n" +
+	"\x03slo\x18\x0f \x01(\v2$.lynx.protobuf.plugin.http.SLOConfigR\x03slo\x12U\n" +
+	"\x0estats_endpoint\x18\x10 \x01(\v2..lynx.protobuf.plugin.http.StatsEndpointConfigR\rstatsEndpoint\x12E\n" +
+	"\tlog_sinks\x18\x11 \x03(\v2(.lynx.protobuf.plugin.http.LogSinkConfigR\blogSinks\x12F\n" +
+	"\tlog_boost\x18\x12 \x01(\v2).lynx.protobuf.plugin.http.LogBoostConfigR\blogBoost\x12U\n" +
+	"\x0eheader_logging\x18\x13 \x01(\v2..lynx.protobuf.plugin.http.HeaderLoggingConfigR\rheaderLogging\x12R\n" +
+	"\rserver_timing\x18\x14 \x01(\v2-.lynx.protobuf.plugin.http.ServerTimingConfigR\fserverTiming\x12O\n" +
+	"\frequest_cost\x18\x15 \x01(\v2,.lynx.protobuf.plugin.http.RequestCostConfigR\vrequestCost\x12X\n" +
+	"\x0funmatched_paths\x18\x16 \x01(\v2/.lynx.protobuf.plugin.http.UnmatchedPathsConfigR\x0eunmatchedPaths\x12'\n" +
+	"\x0fexcluded_routes\x18\x17 \x03(\tR\x0eexcludedRoutes\x12X\n" +
+	"\x0factive_requests\x18\x18 \x01(\v2/.lynx.protobuf.plugin.http.ActiveRequestsConfigR\x0eactiveRequests\x12L\n" +
+	"\verror_chain\x18\x19 \x01(\v2+.lynx.protobuf.plugin.http.ErrorChainConfigR\n" +
+	"errorChain\x12R\n" +
+	"\rerror_classes\x18\x1a \x01(\v2-.lynx.protobuf.plugin.http.ErrorClassesConfigR\ferrorClasses\x12B\n" +
+	"\aanomaly\x18\x1b \x01(\v2(.lynx.protobuf.plugin.http.AnomalyConfigR\aanomaly\"\xb0\x02\n" +
+	"\rAnomalyConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x125\n" +
+	"\binterval\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\binterval\x12\x14\n" +
+	"\x05sigma\x18\x03 \x01(\x01R\x05sigma\x12-\n" +
+	"\x12baseline_intervals\x18\x04 \x01(\x05R\x11baselineIntervals\x12\x1f\n" +
+	"\vmin_samples\x18\x05 \x01(\x05R\n" +
+	"minSamples\x12!\n" +
+	"\fmin_requests\x18\x06 \x01(\x05R\vminRequests\x12\x1e\n" +
+	"\n" +
+	"operations\x18\a \x03(\tR\n" +
+	"operations\x12%\n" +
+	"\x0emax_operations\x18\b \x01(\x05R\rmaxOperations\"\xe5\x01\n" +
+	"\x12ErrorClassesConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12T\n" +
+	"\areasons\x18\x02 \x03(\v2:.lynx.protobuf.plugin.http.ErrorClassesConfig.ReasonsEntryR\areasons\x12#\n" +
+	"\ralert_classes\x18\x03 \x03(\tR\falertClasses\x1a:\n" +
+	"\fReasonsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x80\x01\n" +
+	"\x10ErrorChainConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x1b\n" +
+	"\tmax_depth\x18\x02 \x01(\x05R\bmaxDepth\x12\x1d\n" +
+	"\n" +
+	"max_frames\x18\x03 \x01(\x05R\tmaxFrames\x12\x16\n" +
+	"\x06expose\x18\x04 \x01(\bR\x06expose\"\xb5\x01\n" +
+	"\x14ActiveRequestsConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12)\n" +
+	"\x10endpoint_enabled\x18\x02 \x01(\bR\x0fendpointEnabled\x12\x12\n" +
+	"\x04path\x18\x03 \x01(\tR\x04path\x12D\n" +
+	"\x05stuck\x18\x04 \x01(\v2..lynx.protobuf.plugin.http.StuckRequestsConfigR\x05stuck\"\xe0\x01\n" +
+	"\x13StuckRequestsConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12-\n" +
+	"\x12timeout_multiplier\x18\x02 \x01(\x01R\x11timeoutMultiplier\x12@\n" +
+	"\x0echeck_interval\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\rcheckInterval\x12\x16\n" +
+	"\x06cancel\x18\x04 \x01(\bR\x06cancel\x12&\n" +
+	"\x0fmax_stack_bytes\x18\x05 \x01(\rR\rmaxStackBytes\"t\n" +
+	"\x14UnmatchedPathsConfig\x12#\n" +
+	"\rtracked_paths\x18\x01 \x01(\rR\ftrackedPaths\x12\x1a\n" +
+	"\bsuppress\x18\x02 \x03(\tR\bsuppress\x12\x1b\n" +
+	"\tlog_every\x18\x03 \x01(\rR\blogEvery\"\x86\x02\n" +
+	"\x11RequestCostConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x1b\n" +
+	"\tlog_costs\x18\x02 \x01(\bR\blogCosts\x12)\n" +
+	"\x10response_headers\x18\x03 \x01(\bR\x0fresponseHeaders\x12S\n" +
+	"\aweights\x18\x04 \x03(\v29.lynx.protobuf.plugin.http.RequestCostConfig.WeightsEntryR\aweights\x1a:\n" +
+	"\fWeightsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\".\n" +
+	"\x12ServerTimingConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\"W\n" +
+	"\x13HeaderLoggingConfig\x12\x14\n" +
+	"\x05allow\x18\x01 \x03(\tR\x05allow\x12\x12\n" +
+	"\x04deny\x18\x02 \x03(\tR\x04deny\x12\x16\n" +
+	"\x06redact\x18\x03 \x03(\tR\x06redact\"\xce\x01\n" +
+	"\x0eLogBoostConfig\x12)\n" +
+	"\x10endpoint_enabled\x18\x01 \x01(\bR\x0fendpointEnabled\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12<\n" +
+	"\fmax_duration\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\vmaxDuration\x12?\n" +
+	"\x06boosts\x18\x04 \x03(\v2'.lynx.protobuf.plugin.http.LogBoostRuleR\x06boosts\"\xb7\x01\n" +
+	"\fLogBoostRule\x12\x1c\n" +
+	"\toperation\x18\x01 \x01(\tR\toperation\x12\x19\n" +
+	"\btrace_id\x18\x02 \x01(\tR\atraceId\x12!\n" +
+	"\freply_policy\x18\x03 \x01(\tR\vreplyPolicy\x12\x14\n" +
+	"\x05level\x18\x04 \x01(\tR\x05level\x125\n" +
+	"\bduration\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\bduration\"Z\n" +
+	"\rLogSinkConfig\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\tR\x05level\x12\x1f\n" +
+	"\vsample_rate\x18\x03 \x01(\x01R\n" +
+	"sampleRate\"\xdf\x01\n" +
+	"\x13StatsEndpointConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x121\n" +
+	"\x06window\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x06window\x12\x13\n" +
+	"\x05top_n\x18\x04 \x01(\rR\x04topN\x12R\n" +
+	"\rheavy_hitters\x18\x05 \x01(\v2-.lynx.protobuf.plugin.http.HeavyHittersConfigR\fheavyHitters\"\x98\x01\n" +
+	"\x12HeavyHittersConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x13\n" +
+	"\x05top_k\x18\x02 \x01(\rR\x04topK\x12\x14\n" +
+	"\x05width\x18\x03 \x01(\rR\x05width\x12\x14\n" +
+	"\x05depth\x18\x04 \x01(\rR\x05depth\x12'\n" +
+	"\x0fidentity_header\x18\x05 \x01(\tR\x0eidentityHeader\"\xa6\x02\n" +
+	"\tSLOConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12G\n" +
+	"\n" +
+	"objectives\x18\x02 \x03(\v2'.lynx.protobuf.plugin.http.SLOObjectiveR\n" +
+	"objectives\x12K\n" +
+	"\fburn_windows\x18\x03 \x03(\v2(.lynx.protobuf.plugin.http.SLOBurnWindowR\vburnWindows\x12J\n" +
+	"\x13evaluation_interval\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\x12evaluationInterval\x12\x1d\n" +
+	"\n" +
+	"log_alerts\x18\x05 \x01(\bR\tlogAlerts\"\xcc\x01\n" +
+	"\fSLOObjective\x12\x1c\n" +
+	"\toperation\x18\x01 \x01(\tR\toperation\x12/\n" +
+	"\x13availability_target\x18\x02 \x01(\x01R\x12availabilityTarget\x12F\n" +
+	"\x11latency_threshold\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x10latencyThreshold\x12%\n" +
+	"\x0elatency_target\x18\x04 \x01(\x01R\rlatencyTarget\"k\n" +
+	"\rSLOBurnWindow\x121\n" +
+	"\x06window\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\x06window\x12'\n" +
+	"\x0falert_threshold\x18\x02 \x01(\x01R\x0ealertThreshold\"\xbb\x01\n" +
+	"\x13CallerMetricsConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x16\n" +
+	"\x06header\x18\x02 \x01(\tR\x06header\x12(\n" +
+	"\x10use_tls_identity\x18\x03 \x01(\bR\x0euseTlsIdentity\x12\x1f\n" +
+	"\vmax_callers\x18\x04 \x01(\rR\n" +
+	"maxCallers\x12'\n" +
+	"\x0fallowed_callers\x18\x05 \x03(\tR\x0eallowedCallers\"{\n" +
+	"\x11OtelMetricsConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x1d\n" +
+	"\n" +
+	"meter_name\x18\x02 \x01(\tR\tmeterName\x12-\n" +
+	"\x12disable_prometheus\x18\x03 \x01(\bR\x11disablePrometheus\"\xba\x02\n" +
+	"\x0fHistogramConfig\x12)\n" +
+	"\x10duration_buckets\x18\x01 \x03(\x01R\x0fdurationBuckets\x120\n" +
+	"\x14request_size_buckets\x18\x02 \x03(\x01R\x12requestSizeBuckets\x122\n" +
+	"\x15response_size_buckets\x18\x03 \x03(\x01R\x13responseSizeBuckets\x12+\n" +
+	"\x11native_histograms\x18\x04 \x01(\bR\x10nativeHistograms\x120\n" +
+	"\x14native_bucket_factor\x18\x05 \x01(\x01R\x12nativeBucketFactor\x127\n" +
+	"\x18native_max_bucket_number\x18\x06 \x01(\rR\x15nativeMaxBucketNumber\"\xb6\x03\n" +
+	"\x11BodyLoggingConfig\x12!\n" +
+	"\freply_policy\x18\x01 \x01(\tR\vreplyPolicy\x12v\n" +
+	"\x14route_reply_policies\x18\x02 \x03(\v2D.lynx.protobuf.plugin.http.BodyLoggingConfig.RouteReplyPoliciesEntryR\x12routeReplyPolicies\x12&\n" +
+	"\x0fuse_proto_names\x18\x03 \x01(\bR\ruseProtoNames\x12)\n" +
+	"\x10emit_unpopulated\x18\x04 \x01(\bR\x0femitUnpopulated\x12(\n" +
+	"\x10use_enum_numbers\x18\x05 \x01(\bR\x0euseEnumNumbers\x12\"\n" +
+	"\rmax_body_size\x18\x06 \x01(\x05R\vmaxBodySize\x12\x1e\n" +
+	"\n" +
+	"truncation\x18\a \x01(\tR\n" +
+	"truncation\x1aE\n" +
+	"\x17RouteReplyPoliciesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xa1\x04\n" +
 	"\x0eSecurityConfig\x129\n" +
 	"\x04cors\x18\x01 \x01(\v2%.lynx.protobuf.plugin.http.CorsConfigR\x04cors\x12(\n" +
 	"\x10max_request_size\x18\x02 \x01(\x03R\x0emaxRequestSize\x12I\n" +
 	"\n" +
 	"rate_limit\x18\x03 \x01(\v2*.lynx.protobuf.plugin.http.RateLimitConfigR\trateLimit\x12[\n" +
-	"\x10security_headers\x18\x04 \x01(\v20.lynx.protobuf.plugin.http.SecurityHeadersConfigR\x0fsecurityHeaders\"\x90\x02\n" +
+	"\x10security_headers\x18\x04 \x01(\v20.lynx.protobuf.plugin.http.SecurityHeadersConfigR\x0fsecurityHeaders\x12k\n" +
+	"\x16slow_client_protection\x18\x05 \x01(\v25.lynx.protobuf.plugin.http.SlowClientProtectionConfigR\x14slowClientProtection\x12N\n" +
+	"\vfingerprint\x18\x06 \x01(\v2,.lynx.protobuf.plugin.http.FingerprintConfigR\vfingerprint\x12E\n" +
+	"\bhoneypot\x18\a \x01(\v2).lynx.protobuf.plugin.http.HoneypotConfigR\bhoneypot\"\x8e\x02\n" +
+	"\x0eHoneypotConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12@\n" +
+	"\x06routes\x18\x02 \x03(\v2(.lynx.protobuf.plugin.http.HoneypotRouteR\x06routes\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x12>\n" +
+	"\rflag_duration\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\fflagDuration\x12\x1f\n" +
+	"\vmax_flagged\x18\x05 \x01(\x05R\n" +
+	"maxFlagged\x12'\n" +
+	"\x0ftrusted_proxies\x18\x06 \x03(\tR\x0etrustedProxies\"t\n" +
+	"\rHoneypotRoute\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x18\n" +
+	"\amethods\x18\x02 \x03(\tR\amethods\x12\x12\n" +
+	"\x04body\x18\x03 \x01(\tR\x04body\x12!\n" +
+	"\fcontent_type\x18\x04 \x01(\tR\vcontentType\"\xf3\x01\n" +
+	"\x11FingerprintConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x1e\n" +
+	"\n" +
+	"components\x18\x02 \x03(\tR\n" +
+	"components\x12%\n" +
+	"\x0eignore_headers\x18\x03 \x03(\tR\rignoreHeaders\x12T\n" +
+	"\n" +
+	"rate_limit\x18\x04 \x01(\v25.lynx.protobuf.plugin.http.FingerprintRateLimitConfigR\trateLimit\x12'\n" +
+	"\x0ftrusted_proxies\x18\x05 \x03(\tR\x0etrustedProxies\"\x95\x01\n" +
+	"\x1aFingerprintRateLimitConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12&\n" +
+	"\x0frate_per_second\x18\x02 \x01(\x01R\rratePerSecond\x12\x14\n" +
+	"\x05burst\x18\x03 \x01(\x05R\x05burst\x12\x1f\n" +
+	"\vmax_tracked\x18\x04 \x01(\x05R\n" +
+	"maxTracked\"\xcf\x03\n" +
+	"\x1aSlowClientProtectionConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12'\n" +
+	"\x10max_conns_per_ip\x18\x02 \x01(\rR\rmaxConnsPerIp\x12E\n" +
+	"\x11body_read_timeout\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x0fbodyReadTimeout\x12\"\n" +
+	"\rmin_body_rate\x18\x04 \x01(\x03R\vminBodyRate\x12A\n" +
+	"\x0fbody_rate_grace\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\rbodyRateGrace\x12#\n" +
+	"\rban_threshold\x18\x06 \x01(\rR\fbanThreshold\x128\n" +
+	"\n" +
+	"ban_window\x18\a \x01(\v2\x19.google.protobuf.DurationR\tbanWindow\x12<\n" +
+	"\fban_duration\x18\b \x01(\v2\x19.google.protobuf.DurationR\vbanDuration\x12#\n" +
+	"\rtrusted_cidrs\x18\t \x03(\tR\ftrustedCidrs\"\x90\x02\n" +
 	"\n" +
 	"CorsConfig\x12\x18\n" +
 	"\aenabled\x18\x01 \x01(\bR\aenabled\x12'\n" +
@@ -1153,18 +9576,27 @@ const file_http_proto_rawDesc = "" +
 	"\x0fallowed_headers\x18\x04 \x03(\tR\x0eallowedHeaders\x12'\n" +
 	"\x0fexposed_headers\x18\x05 \x03(\tR\x0eexposedHeaders\x12+\n" +
 	"\x11allow_credentials\x18\x06 \x01(\bR\x10allowCredentials\x12\x17\n" +
-	"\amax_age\x18\a \x01(\x05R\x06maxAge\"t\n" +
+	"\amax_age\x18\a \x01(\x05R\x06maxAge\"\x81\x02\n" +
 	"\x0fRateLimitConfig\x12\x18\n" +
 	"\aenabled\x18\x01 \x01(\bR\aenabled\x12&\n" +
 	"\x0frate_per_second\x18\x02 \x01(\x05R\rratePerSecond\x12\x1f\n" +
 	"\vburst_limit\x18\x03 \x01(\x05R\n" +
-	"burstLimit\"\xf0\x01\n" +
+	"burstLimit\x12>\n" +
+	"\x05costs\x18\x04 \x03(\v2(.lynx.protobuf.plugin.http.RateLimitCostR\x05costs\x12K\n" +
+	"\aheaders\x18\x05 \x01(\v21.lynx.protobuf.plugin.http.RateLimitHeadersConfigR\aheaders\"u\n" +
+	"\x16RateLimitHeadersConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x14\n" +
+	"\x05style\x18\x02 \x01(\tR\x05style\x12+\n" +
+	"\x11exempt_operations\x18\x03 \x03(\tR\x10exemptOperations\"E\n" +
+	"\rRateLimitCost\x12\x1c\n" +
+	"\toperation\x18\x01 \x01(\tR\toperation\x12\x16\n" +
+	"\x06tokens\x18\x02 \x01(\x05R\x06tokens\"\xf0\x01\n" +
 	"\x15SecurityHeadersConfig\x12\x18\n" +
 	"\aenabled\x18\x01 \x01(\bR\aenabled\x126\n" +
 	"\x17content_security_policy\x18\x02 \x01(\tR\x15contentSecurityPolicy\x12&\n" +
 	"\x0fx_frame_options\x18\x03 \x01(\tR\rxFrameOptions\x123\n" +
 	"\x16x_content_type_options\x18\x04 \x01(\tR\x13xContentTypeOptions\x12(\n" +
-	"\x10x_xss_protection\x18\x05 \x01(\tR\x0exXssProtection\"\xab\x04\n" +
+	"\x10x_xss_protection\x18\x05 \x01(\tR\x0exXssProtection\"\xa3\a\n" +
 	"\x11PerformanceConfig\x12'\n" +
 	"\x0fmax_connections\x18\x01 \x01(\x05R\x0emaxConnections\x126\n" +
 	"\x17max_concurrent_requests\x18\x02 \x01(\x05R\x15maxConcurrentRequests\x12(\n" +
@@ -1174,12 +9606,34 @@ const file_http_proto_rawDesc = "" +
 	"\fread_timeout\x18\x06 \x01(\v2\x19.google.protobuf.DurationR\vreadTimeout\x12>\n" +
 	"\rwrite_timeout\x18\a \x01(\v2\x19.google.protobuf.DurationR\fwriteTimeout\x12<\n" +
 	"\fidle_timeout\x18\b \x01(\v2\x19.google.protobuf.DurationR\vidleTimeout\x12I\n" +
-	"\x13read_header_timeout\x18\t \x01(\v2\x19.google.protobuf.DurationR\x11readHeaderTimeout\"\xea\x01\n" +
+	"\x13read_header_timeout\x18\t \x01(\v2\x19.google.protobuf.DurationR\x11readHeaderTimeout\x12(\n" +
+	"\x10max_header_bytes\x18\n" +
+	" \x01(\x05R\x0emaxHeaderBytes\x12.\n" +
+	"\x13disable_keep_alives\x18\v \x01(\bR\x11disableKeepAlives\x12L\n" +
+	"\x15tcp_keep_alive_period\x18\f \x01(\v2\x19.google.protobuf.DurationR\x12tcpKeepAlivePeriod\x12Z\n" +
+	"\x10admission_queues\x18\r \x03(\v2/.lynx.protobuf.plugin.http.AdmissionQueueConfigR\x0fadmissionQueues\x12\x1b\n" +
+	"\tperf_mode\x18\x0e \x01(\bR\bperfMode\x12U\n" +
+	"\x0eresource_guard\x18\x0f \x01(\v2..lynx.protobuf.plugin.http.ResourceGuardConfigR\rresourceGuard\"\x9b\x02\n" +
+	"\x13ResourceGuardConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12$\n" +
+	"\x0emax_heap_bytes\x18\x02 \x01(\x04R\fmaxHeapBytes\x12%\n" +
+	"\x0emax_goroutines\x18\x03 \x01(\rR\rmaxGoroutines\x12@\n" +
+	"\x0echeck_interval\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\rcheckInterval\x12'\n" +
+	"\x0fshed_operations\x18\x05 \x03(\tR\x0eshedOperations\x122\n" +
+	"\x15shed_admission_groups\x18\x06 \x03(\tR\x13shedAdmissionGroups\"\xc6\x01\n" +
+	"\x14AdmissionQueueConfig\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1e\n" +
+	"\n" +
+	"operations\x18\x02 \x03(\tR\n" +
+	"operations\x12'\n" +
+	"\x0fmax_concurrency\x18\x03 \x01(\x05R\x0emaxConcurrency\x12\x1b\n" +
+	"\tmax_queue\x18\x04 \x01(\x05R\bmaxQueue\x124\n" +
+	"\bmax_wait\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\amaxWait\"\xea\x01\n" +
 	"\x14ConnectionPoolConfig\x12$\n" +
 	"\x0emax_idle_conns\x18\x01 \x01(\x05R\fmaxIdleConns\x124\n" +
 	"\x17max_idle_conns_per_host\x18\x02 \x01(\x05R\x13maxIdleConnsPerHost\x12+\n" +
 	"\x12max_conns_per_host\x18\x03 \x01(\x05R\x0fmaxConnsPerHost\x12I\n" +
-	"\x13keep_alive_duration\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\x11keepAliveDuration\"\xbe\x03\n" +
+	"\x13keep_alive_duration\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\x11keepAliveDuration\"\x9f\a\n" +
 	"\x10MiddlewareConfig\x12%\n" +
 	"\x0eenable_tracing\x18\x01 \x01(\bR\renableTracing\x12%\n" +
 	"\x0eenable_logging\x18\x02 \x01(\bR\renableLogging\x12'\n" +
@@ -1187,10 +9641,63 @@ const file_http_proto_rawDesc = "" +
 	"\x11enable_validation\x18\x04 \x01(\bR\x10enableValidation\x12*\n" +
 	"\x11enable_rate_limit\x18\x05 \x01(\bR\x0fenableRateLimit\x12%\n" +
 	"\x0eenable_metrics\x18\x06 \x01(\bR\renableMetrics\x12n\n" +
-	"\x11custom_middleware\x18\a \x03(\v2A.lynx.protobuf.plugin.http.MiddlewareConfig.CustomMiddlewareEntryR\x10customMiddleware\x1aC\n" +
+	"\x11custom_middleware\x18\a \x03(\v2A.lynx.protobuf.plugin.http.MiddlewareConfig.CustomMiddlewareEntryR\x10customMiddleware\x12g\n" +
+	"\x14deadline_propagation\x18\b \x01(\v24.lynx.protobuf.plugin.http.DeadlinePropagationConfigR\x13deadlinePropagation\x12O\n" +
+	"\fretry_budget\x18\t \x01(\v2,.lynx.protobuf.plugin.http.RetryBudgetConfigR\vretryBudget\x12<\n" +
+	"\x05dedup\x18\n" +
+	" \x01(\v2&.lynx.protobuf.plugin.http.DedupConfigR\x05dedup\x12E\n" +
+	"\bcoalesce\x18\v \x01(\v2).lynx.protobuf.plugin.http.CoalesceConfigR\bcoalesce\x12R\n" +
+	"\rhandler_retry\x18\f \x01(\v2-.lynx.protobuf.plugin.http.HandlerRetryConfigR\fhandlerRetry\x12L\n" +
+	"\vretry_storm\x18\r \x01(\v2+.lynx.protobuf.plugin.http.RetryStormConfigR\n" +
+	"retryStorm\x1aC\n" +
 	"\x15CustomMiddlewareEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xd8\x01\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xa3\x02\n" +
+	"\x10RetryStormConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x1c\n" +
+	"\tthreshold\x18\x02 \x01(\x05R\tthreshold\x121\n" +
+	"\x06window\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x06window\x12\x14\n" +
+	"\x05block\x18\x04 \x01(\bR\x05block\x12@\n" +
+	"\x0eblock_duration\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\rblockDuration\x12\x1f\n" +
+	"\vmax_tracked\x18\x06 \x01(\x05R\n" +
+	"maxTracked\x12+\n" +
+	"\x11exempt_operations\x18\a \x03(\tR\x10exemptOperations\"\xbe\x01\n" +
+	"\vDedupConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x121\n" +
+	"\x06window\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\x06window\x12\x18\n" +
+	"\amethods\x18\x03 \x03(\tR\amethods\x12'\n" +
+	"\x0fidentity_header\x18\x04 \x01(\tR\x0eidentityHeader\x12\x1f\n" +
+	"\vmax_entries\x18\x05 \x01(\x05R\n" +
+	"maxEntries\"\x9c\x01\n" +
+	"\x0eCoalesceConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x18\n" +
+	"\amethods\x18\x02 \x03(\tR\amethods\x12)\n" +
+	"\x10identity_headers\x18\x03 \x03(\tR\x0fidentityHeaders\x12+\n" +
+	"\x11exempt_operations\x18\x04 \x03(\tR\x10exemptOperations\"\xbb\x02\n" +
+	"\x12HandlerRetryConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x1e\n" +
+	"\n" +
+	"operations\x18\x02 \x03(\tR\n" +
+	"operations\x12\x18\n" +
+	"\amethods\x18\x03 \x03(\tR\amethods\x12\x18\n" +
+	"\areasons\x18\x04 \x03(\tR\areasons\x12\x14\n" +
+	"\x05codes\x18\x05 \x03(\x05R\x05codes\x12!\n" +
+	"\fmax_attempts\x18\x06 \x01(\x05R\vmaxAttempts\x12B\n" +
+	"\x0finitial_backoff\x18\a \x01(\v2\x19.google.protobuf.DurationR\x0einitialBackoff\x12:\n" +
+	"\vmax_backoff\x18\b \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"maxBackoff\"\xa7\x01\n" +
+	"\x11RetryBudgetConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12!\n" +
+	"\fmax_attempts\x18\x02 \x01(\x05R\vmaxAttempts\x12%\n" +
+	"\x0eattempt_header\x18\x03 \x01(\tR\rattemptHeader\x12.\n" +
+	"\x13max_attempts_header\x18\x04 \x01(\tR\x11maxAttemptsHeader\"\xc7\x01\n" +
+	"\x19DeadlinePropagationConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x18\n" +
+	"\aheaders\x18\x02 \x03(\tR\aheaders\x12:\n" +
+	"\vmax_timeout\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"maxTimeout\x12:\n" +
+	"\vmin_timeout\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"minTimeout\"\xd8\x01\n" +
 	"\x16GracefulShutdownConfig\x12D\n" +
 	"\x10shutdown_timeout\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\x0fshutdownTimeout\x129\n" +
 	"\x19wait_for_ongoing_requests\x18\x02 \x01(\bR\x16waitForOngoingRequests\x12=\n" +
@@ -1214,48 +9721,273 @@ func file_http_proto_rawDescGZIP() []byte {
 	return file_http_proto_rawDescData
 }
 
-var file_http_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_http_proto_msgTypes = make([]protoimpl.MessageInfo, 106)
 var file_http_proto_goTypes = []any{
-	(*Http)(nil),                   // 0: lynx.protobuf.plugin.http.http
-	(*MonitoringConfig)(nil),       // 1: lynx.protobuf.plugin.http.MonitoringConfig
-	(*SecurityConfig)(nil),         // 2: lynx.protobuf.plugin.http.SecurityConfig
-	(*CorsConfig)(nil),             // 3: lynx.protobuf.plugin.http.CorsConfig
-	(*RateLimitConfig)(nil),        // 4: lynx.protobuf.plugin.http.RateLimitConfig
-	(*SecurityHeadersConfig)(nil),  // 5: lynx.protobuf.plugin.http.SecurityHeadersConfig
-	(*PerformanceConfig)(nil),      // 6: lynx.protobuf.plugin.http.PerformanceConfig
-	(*ConnectionPoolConfig)(nil),   // 7: lynx.protobuf.plugin.http.ConnectionPoolConfig
-	(*MiddlewareConfig)(nil),       // 8: lynx.protobuf.plugin.http.MiddlewareConfig
-	(*GracefulShutdownConfig)(nil), // 9: lynx.protobuf.plugin.http.GracefulShutdownConfig
-	(*CircuitBreakerConfig)(nil),   // 10: lynx.protobuf.plugin.http.CircuitBreakerConfig
-	nil,                            // 11: lynx.protobuf.plugin.http.MiddlewareConfig.CustomMiddlewareEntry
-	(*durationpb.Duration)(nil),    // 12: google.protobuf.Duration
+	(*Http)(nil),                       // 0: lynx.protobuf.plugin.http.http
+	(*MockConfig)(nil),                 // 1: lynx.protobuf.plugin.http.MockConfig
+	(*MockResponse)(nil),               // 2: lynx.protobuf.plugin.http.MockResponse
+	(*CollectionExportConfig)(nil),     // 3: lynx.protobuf.plugin.http.CollectionExportConfig
+	(*OperationsConfig)(nil),           // 4: lynx.protobuf.plugin.http.OperationsConfig
+	(*AsyncTasksConfig)(nil),           // 5: lynx.protobuf.plugin.http.AsyncTasksConfig
+	(*GrpcParityConfig)(nil),           // 6: lynx.protobuf.plugin.http.GrpcParityConfig
+	(*ResidencyConfig)(nil),            // 7: lynx.protobuf.plugin.http.ResidencyConfig
+	(*TenantConfig)(nil),               // 8: lynx.protobuf.plugin.http.TenantConfig
+	(*VirtualHostsConfig)(nil),         // 9: lynx.protobuf.plugin.http.VirtualHostsConfig
+	(*VirtualHostConfig)(nil),          // 10: lynx.protobuf.plugin.http.VirtualHostConfig
+	(*KubernetesConfig)(nil),           // 11: lynx.protobuf.plugin.http.KubernetesConfig
+	(*LoadBalancerHintsConfig)(nil),    // 12: lynx.protobuf.plugin.http.LoadBalancerHintsConfig
+	(*RoutingConfig)(nil),              // 13: lynx.protobuf.plugin.http.RoutingConfig
+	(*FallbackResponse)(nil),           // 14: lynx.protobuf.plugin.http.FallbackResponse
+	(*SessionConfig)(nil),              // 15: lynx.protobuf.plugin.http.SessionConfig
+	(*SignedURLConfig)(nil),            // 16: lynx.protobuf.plugin.http.SignedURLConfig
+	(*ClientInfoConfig)(nil),           // 17: lynx.protobuf.plugin.http.ClientInfoConfig
+	(*MinClientVersion)(nil),           // 18: lynx.protobuf.plugin.http.MinClientVersion
+	(*RequestConfig)(nil),              // 19: lynx.protobuf.plugin.http.RequestConfig
+	(*RequestDefaultsRule)(nil),        // 20: lynx.protobuf.plugin.http.RequestDefaultsRule
+	(*QueryConfig)(nil),                // 21: lynx.protobuf.plugin.http.QueryConfig
+	(*DecodeErrorConfig)(nil),          // 22: lynx.protobuf.plugin.http.DecodeErrorConfig
+	(*ContentTypeConfig)(nil),          // 23: lynx.protobuf.plugin.http.ContentTypeConfig
+	(*ContentTypeRule)(nil),            // 24: lynx.protobuf.plugin.http.ContentTypeRule
+	(*BatchConfig)(nil),                // 25: lynx.protobuf.plugin.http.BatchConfig
+	(*JSONRPCConfig)(nil),              // 26: lynx.protobuf.plugin.http.JSONRPCConfig
+	(*GraphQLConfig)(nil),              // 27: lynx.protobuf.plugin.http.GraphQLConfig
+	(*ResponseConfig)(nil),             // 28: lynx.protobuf.plugin.http.ResponseConfig
+	(*HeaderScrubbingConfig)(nil),      // 29: lynx.protobuf.plugin.http.HeaderScrubbingConfig
+	(*ProtoJSONConfig)(nil),            // 30: lynx.protobuf.plugin.http.ProtoJSONConfig
+	(*MultiStatusConfig)(nil),          // 31: lynx.protobuf.plugin.http.MultiStatusConfig
+	(*WarningsConfig)(nil),             // 32: lynx.protobuf.plugin.http.WarningsConfig
+	(*EnvelopeCodesConfig)(nil),        // 33: lynx.protobuf.plugin.http.EnvelopeCodesConfig
+	(*CodeNamespace)(nil),              // 34: lynx.protobuf.plugin.http.CodeNamespace
+	(*ProtobufResponseConfig)(nil),     // 35: lynx.protobuf.plugin.http.ProtobufResponseConfig
+	(*ExportConfig)(nil),               // 36: lynx.protobuf.plugin.http.ExportConfig
+	(*ExportRule)(nil),                 // 37: lynx.protobuf.plugin.http.ExportRule
+	(*ExportColumn)(nil),               // 38: lynx.protobuf.plugin.http.ExportColumn
+	(*FieldEncryptionConfig)(nil),      // 39: lynx.protobuf.plugin.http.FieldEncryptionConfig
+	(*FieldEncryptionRule)(nil),        // 40: lynx.protobuf.plugin.http.FieldEncryptionRule
+	(*FieldEncryptionKey)(nil),         // 41: lynx.protobuf.plugin.http.FieldEncryptionKey
+	(*ResponseSigningConfig)(nil),      // 42: lynx.protobuf.plugin.http.ResponseSigningConfig
+	(*CompressionConfig)(nil),          // 43: lynx.protobuf.plugin.http.CompressionConfig
+	(*CompressionCacheConfig)(nil),     // 44: lynx.protobuf.plugin.http.CompressionCacheConfig
+	(*CacheControlConfig)(nil),         // 45: lynx.protobuf.plugin.http.CacheControlConfig
+	(*CacheControlRule)(nil),           // 46: lynx.protobuf.plugin.http.CacheControlRule
+	(*ResponseSizeLimitConfig)(nil),    // 47: lynx.protobuf.plugin.http.ResponseSizeLimitConfig
+	(*ProxyProtocolConfig)(nil),        // 48: lynx.protobuf.plugin.http.ProxyProtocolConfig
+	(*SystemdConfig)(nil),              // 49: lynx.protobuf.plugin.http.SystemdConfig
+	(*MonitoringConfig)(nil),           // 50: lynx.protobuf.plugin.http.MonitoringConfig
+	(*AnomalyConfig)(nil),              // 51: lynx.protobuf.plugin.http.AnomalyConfig
+	(*ErrorClassesConfig)(nil),         // 52: lynx.protobuf.plugin.http.ErrorClassesConfig
+	(*ErrorChainConfig)(nil),           // 53: lynx.protobuf.plugin.http.ErrorChainConfig
+	(*ActiveRequestsConfig)(nil),       // 54: lynx.protobuf.plugin.http.ActiveRequestsConfig
+	(*StuckRequestsConfig)(nil),        // 55: lynx.protobuf.plugin.http.StuckRequestsConfig
+	(*UnmatchedPathsConfig)(nil),       // 56: lynx.protobuf.plugin.http.UnmatchedPathsConfig
+	(*RequestCostConfig)(nil),          // 57: lynx.protobuf.plugin.http.RequestCostConfig
+	(*ServerTimingConfig)(nil),         // 58: lynx.protobuf.plugin.http.ServerTimingConfig
+	(*HeaderLoggingConfig)(nil),        // 59: lynx.protobuf.plugin.http.HeaderLoggingConfig
+	(*LogBoostConfig)(nil),             // 60: lynx.protobuf.plugin.http.LogBoostConfig
+	(*LogBoostRule)(nil),               // 61: lynx.protobuf.plugin.http.LogBoostRule
+	(*LogSinkConfig)(nil),              // 62: lynx.protobuf.plugin.http.LogSinkConfig
+	(*StatsEndpointConfig)(nil),        // 63: lynx.protobuf.plugin.http.StatsEndpointConfig
+	(*HeavyHittersConfig)(nil),         // 64: lynx.protobuf.plugin.http.HeavyHittersConfig
+	(*SLOConfig)(nil),                  // 65: lynx.protobuf.plugin.http.SLOConfig
+	(*SLOObjective)(nil),               // 66: lynx.protobuf.plugin.http.SLOObjective
+	(*SLOBurnWindow)(nil),              // 67: lynx.protobuf.plugin.http.SLOBurnWindow
+	(*CallerMetricsConfig)(nil),        // 68: lynx.protobuf.plugin.http.CallerMetricsConfig
+	(*OtelMetricsConfig)(nil),          // 69: lynx.protobuf.plugin.http.OtelMetricsConfig
+	(*HistogramConfig)(nil),            // 70: lynx.protobuf.plugin.http.HistogramConfig
+	(*BodyLoggingConfig)(nil),          // 71: lynx.protobuf.plugin.http.BodyLoggingConfig
+	(*SecurityConfig)(nil),             // 72: lynx.protobuf.plugin.http.SecurityConfig
+	(*HoneypotConfig)(nil),             // 73: lynx.protobuf.plugin.http.HoneypotConfig
+	(*HoneypotRoute)(nil),              // 74: lynx.protobuf.plugin.http.HoneypotRoute
+	(*FingerprintConfig)(nil),          // 75: lynx.protobuf.plugin.http.FingerprintConfig
+	(*FingerprintRateLimitConfig)(nil), // 76: lynx.protobuf.plugin.http.FingerprintRateLimitConfig
+	(*SlowClientProtectionConfig)(nil), // 77: lynx.protobuf.plugin.http.SlowClientProtectionConfig
+	(*CorsConfig)(nil),                 // 78: lynx.protobuf.plugin.http.CorsConfig
+	(*RateLimitConfig)(nil),            // 79: lynx.protobuf.plugin.http.RateLimitConfig
+	(*RateLimitHeadersConfig)(nil),     // 80: lynx.protobuf.plugin.http.RateLimitHeadersConfig
+	(*RateLimitCost)(nil),              // 81: lynx.protobuf.plugin.http.RateLimitCost
+	(*SecurityHeadersConfig)(nil),      // 82: lynx.protobuf.plugin.http.SecurityHeadersConfig
+	(*PerformanceConfig)(nil),          // 83: lynx.protobuf.plugin.http.PerformanceConfig
+	(*ResourceGuardConfig)(nil),        // 84: lynx.protobuf.plugin.http.ResourceGuardConfig
+	(*AdmissionQueueConfig)(nil),       // 85: lynx.protobuf.plugin.http.AdmissionQueueConfig
+	(*ConnectionPoolConfig)(nil),       // 86: lynx.protobuf.plugin.http.ConnectionPoolConfig
+	(*MiddlewareConfig)(nil),           // 87: lynx.protobuf.plugin.http.MiddlewareConfig
+	(*RetryStormConfig)(nil),           // 88: lynx.protobuf.plugin.http.RetryStormConfig
+	(*DedupConfig)(nil),                // 89: lynx.protobuf.plugin.http.DedupConfig
+	(*CoalesceConfig)(nil),             // 90: lynx.protobuf.plugin.http.CoalesceConfig
+	(*HandlerRetryConfig)(nil),         // 91: lynx.protobuf.plugin.http.HandlerRetryConfig
+	(*RetryBudgetConfig)(nil),          // 92: lynx.protobuf.plugin.http.RetryBudgetConfig
+	(*DeadlinePropagationConfig)(nil),  // 93: lynx.protobuf.plugin.http.DeadlinePropagationConfig
+	(*GracefulShutdownConfig)(nil),     // 94: lynx.protobuf.plugin.http.GracefulShutdownConfig
+	(*CircuitBreakerConfig)(nil),       // 95: lynx.protobuf.plugin.http.CircuitBreakerConfig
+	nil,                                // 96: lynx.protobuf.plugin.http.ResidencyConfig.TenantRegionsEntry
+	nil,                                // 97: lynx.protobuf.plugin.http.ResidencyConfig.RegionEndpointsEntry
+	nil,                                // 98: lynx.protobuf.plugin.http.TenantConfig.StaticEntry
+	nil,                                // 99: lynx.protobuf.plugin.http.RequestDefaultsRule.ValuesEntry
+	nil,                                // 100: lynx.protobuf.plugin.http.RequestDefaultsRule.HeadersEntry
+	nil,                                // 101: lynx.protobuf.plugin.http.HeaderScrubbingConfig.RewriteEntry
+	nil,                                // 102: lynx.protobuf.plugin.http.ErrorClassesConfig.ReasonsEntry
+	nil,                                // 103: lynx.protobuf.plugin.http.RequestCostConfig.WeightsEntry
+	nil,                                // 104: lynx.protobuf.plugin.http.BodyLoggingConfig.RouteReplyPoliciesEntry
+	nil,                                // 105: lynx.protobuf.plugin.http.MiddlewareConfig.CustomMiddlewareEntry
+	(*durationpb.Duration)(nil),        // 106: google.protobuf.Duration
 }
 var file_http_proto_depIdxs = []int32{
-	12, // 0: lynx.protobuf.plugin.http.http.timeout:type_name -> google.protobuf.Duration
-	1,  // 1: lynx.protobuf.plugin.http.http.monitoring:type_name -> lynx.protobuf.plugin.http.MonitoringConfig
-	2,  // 2: lynx.protobuf.plugin.http.http.security:type_name -> lynx.protobuf.plugin.http.SecurityConfig
-	6,  // 3: lynx.protobuf.plugin.http.http.performance:type_name -> lynx.protobuf.plugin.http.PerformanceConfig
-	8,  // 4: lynx.protobuf.plugin.http.http.middleware:type_name -> lynx.protobuf.plugin.http.MiddlewareConfig
-	9,  // 5: lynx.protobuf.plugin.http.http.graceful_shutdown:type_name -> lynx.protobuf.plugin.http.GracefulShutdownConfig
-	10, // 6: lynx.protobuf.plugin.http.http.circuit_breaker:type_name -> lynx.protobuf.plugin.http.CircuitBreakerConfig
-	3,  // 7: lynx.protobuf.plugin.http.SecurityConfig.cors:type_name -> lynx.protobuf.plugin.http.CorsConfig
-	4,  // 8: lynx.protobuf.plugin.http.SecurityConfig.rate_limit:type_name -> lynx.protobuf.plugin.http.RateLimitConfig
-	5,  // 9: lynx.protobuf.plugin.http.SecurityConfig.security_headers:type_name -> lynx.protobuf.plugin.http.SecurityHeadersConfig
-	7,  // 10: lynx.protobuf.plugin.http.PerformanceConfig.connection_pool:type_name -> lynx.protobuf.plugin.http.ConnectionPoolConfig
-	12, // 11: lynx.protobuf.plugin.http.PerformanceConfig.read_timeout:type_name -> google.protobuf.Duration
-	12, // 12: lynx.protobuf.plugin.http.PerformanceConfig.write_timeout:type_name -> google.protobuf.Duration
-	12, // 13: lynx.protobuf.plugin.http.PerformanceConfig.idle_timeout:type_name -> google.protobuf.Duration
-	12, // 14: lynx.protobuf.plugin.http.PerformanceConfig.read_header_timeout:type_name -> google.protobuf.Duration
-	12, // 15: lynx.protobuf.plugin.http.ConnectionPoolConfig.keep_alive_duration:type_name -> google.protobuf.Duration
-	11, // 16: lynx.protobuf.plugin.http.MiddlewareConfig.custom_middleware:type_name -> lynx.protobuf.plugin.http.MiddlewareConfig.CustomMiddlewareEntry
-	12, // 17: lynx.protobuf.plugin.http.GracefulShutdownConfig.shutdown_timeout:type_name -> google.protobuf.Duration
-	12, // 18: lynx.protobuf.plugin.http.GracefulShutdownConfig.max_wait_time:type_name -> google.protobuf.Duration
-	12, // 19: lynx.protobuf.plugin.http.CircuitBreakerConfig.timeout:type_name -> google.protobuf.Duration
-	20, // [20:20] is the sub-list for method output_type
-	20, // [20:20] is the sub-list for method input_type
-	20, // [20:20] is the sub-list for extension type_name
-	20, // [20:20] is the sub-list for extension extendee
-	0,  // [0:20] is the sub-list for field type_name
+	106, // 0: lynx.protobuf.plugin.http.http.timeout:type_name -> google.protobuf.Duration
+	50,  // 1: lynx.protobuf.plugin.http.http.monitoring:type_name -> lynx.protobuf.plugin.http.MonitoringConfig
+	72,  // 2: lynx.protobuf.plugin.http.http.security:type_name -> lynx.protobuf.plugin.http.SecurityConfig
+	83,  // 3: lynx.protobuf.plugin.http.http.performance:type_name -> lynx.protobuf.plugin.http.PerformanceConfig
+	87,  // 4: lynx.protobuf.plugin.http.http.middleware:type_name -> lynx.protobuf.plugin.http.MiddlewareConfig
+	94,  // 5: lynx.protobuf.plugin.http.http.graceful_shutdown:type_name -> lynx.protobuf.plugin.http.GracefulShutdownConfig
+	95,  // 6: lynx.protobuf.plugin.http.http.circuit_breaker:type_name -> lynx.protobuf.plugin.http.CircuitBreakerConfig
+	49,  // 7: lynx.protobuf.plugin.http.http.systemd:type_name -> lynx.protobuf.plugin.http.SystemdConfig
+	48,  // 8: lynx.protobuf.plugin.http.http.proxy_protocol:type_name -> lynx.protobuf.plugin.http.ProxyProtocolConfig
+	28,  // 9: lynx.protobuf.plugin.http.http.response:type_name -> lynx.protobuf.plugin.http.ResponseConfig
+	27,  // 10: lynx.protobuf.plugin.http.http.graphql:type_name -> lynx.protobuf.plugin.http.GraphQLConfig
+	26,  // 11: lynx.protobuf.plugin.http.http.jsonrpc:type_name -> lynx.protobuf.plugin.http.JSONRPCConfig
+	25,  // 12: lynx.protobuf.plugin.http.http.batch:type_name -> lynx.protobuf.plugin.http.BatchConfig
+	19,  // 13: lynx.protobuf.plugin.http.http.request:type_name -> lynx.protobuf.plugin.http.RequestConfig
+	17,  // 14: lynx.protobuf.plugin.http.http.client_info:type_name -> lynx.protobuf.plugin.http.ClientInfoConfig
+	16,  // 15: lynx.protobuf.plugin.http.http.signed_urls:type_name -> lynx.protobuf.plugin.http.SignedURLConfig
+	15,  // 16: lynx.protobuf.plugin.http.http.session:type_name -> lynx.protobuf.plugin.http.SessionConfig
+	13,  // 17: lynx.protobuf.plugin.http.http.routing:type_name -> lynx.protobuf.plugin.http.RoutingConfig
+	12,  // 18: lynx.protobuf.plugin.http.http.load_balancer_hints:type_name -> lynx.protobuf.plugin.http.LoadBalancerHintsConfig
+	11,  // 19: lynx.protobuf.plugin.http.http.kubernetes:type_name -> lynx.protobuf.plugin.http.KubernetesConfig
+	9,   // 20: lynx.protobuf.plugin.http.http.virtual_hosts:type_name -> lynx.protobuf.plugin.http.VirtualHostsConfig
+	8,   // 21: lynx.protobuf.plugin.http.http.tenant:type_name -> lynx.protobuf.plugin.http.TenantConfig
+	7,   // 22: lynx.protobuf.plugin.http.http.residency:type_name -> lynx.protobuf.plugin.http.ResidencyConfig
+	6,   // 23: lynx.protobuf.plugin.http.http.grpc_parity:type_name -> lynx.protobuf.plugin.http.GrpcParityConfig
+	5,   // 24: lynx.protobuf.plugin.http.http.async_tasks:type_name -> lynx.protobuf.plugin.http.AsyncTasksConfig
+	4,   // 25: lynx.protobuf.plugin.http.http.operations:type_name -> lynx.protobuf.plugin.http.OperationsConfig
+	3,   // 26: lynx.protobuf.plugin.http.http.collection_export:type_name -> lynx.protobuf.plugin.http.CollectionExportConfig
+	1,   // 27: lynx.protobuf.plugin.http.http.mock:type_name -> lynx.protobuf.plugin.http.MockConfig
+	2,   // 28: lynx.protobuf.plugin.http.MockConfig.responses:type_name -> lynx.protobuf.plugin.http.MockResponse
+	106, // 29: lynx.protobuf.plugin.http.OperationsConfig.ttl:type_name -> google.protobuf.Duration
+	106, // 30: lynx.protobuf.plugin.http.AsyncTasksConfig.result_ttl:type_name -> google.protobuf.Duration
+	96,  // 31: lynx.protobuf.plugin.http.ResidencyConfig.tenant_regions:type_name -> lynx.protobuf.plugin.http.ResidencyConfig.TenantRegionsEntry
+	97,  // 32: lynx.protobuf.plugin.http.ResidencyConfig.region_endpoints:type_name -> lynx.protobuf.plugin.http.ResidencyConfig.RegionEndpointsEntry
+	98,  // 33: lynx.protobuf.plugin.http.TenantConfig.static:type_name -> lynx.protobuf.plugin.http.TenantConfig.StaticEntry
+	106, // 34: lynx.protobuf.plugin.http.TenantConfig.cache_ttl:type_name -> google.protobuf.Duration
+	10,  // 35: lynx.protobuf.plugin.http.VirtualHostsConfig.hosts:type_name -> lynx.protobuf.plugin.http.VirtualHostConfig
+	106, // 36: lynx.protobuf.plugin.http.KubernetesConfig.prestop_delay:type_name -> google.protobuf.Duration
+	106, // 37: lynx.protobuf.plugin.http.LoadBalancerHintsConfig.drain_delay:type_name -> google.protobuf.Duration
+	14,  // 38: lynx.protobuf.plugin.http.RoutingConfig.not_found:type_name -> lynx.protobuf.plugin.http.FallbackResponse
+	14,  // 39: lynx.protobuf.plugin.http.RoutingConfig.method_not_allowed:type_name -> lynx.protobuf.plugin.http.FallbackResponse
+	106, // 40: lynx.protobuf.plugin.http.SessionConfig.idle_timeout:type_name -> google.protobuf.Duration
+	106, // 41: lynx.protobuf.plugin.http.SessionConfig.absolute_timeout:type_name -> google.protobuf.Duration
+	18,  // 42: lynx.protobuf.plugin.http.ClientInfoConfig.min_versions:type_name -> lynx.protobuf.plugin.http.MinClientVersion
+	23,  // 43: lynx.protobuf.plugin.http.RequestConfig.content_types:type_name -> lynx.protobuf.plugin.http.ContentTypeConfig
+	22,  // 44: lynx.protobuf.plugin.http.RequestConfig.decode_errors:type_name -> lynx.protobuf.plugin.http.DecodeErrorConfig
+	21,  // 45: lynx.protobuf.plugin.http.RequestConfig.query:type_name -> lynx.protobuf.plugin.http.QueryConfig
+	20,  // 46: lynx.protobuf.plugin.http.RequestConfig.defaults:type_name -> lynx.protobuf.plugin.http.RequestDefaultsRule
+	99,  // 47: lynx.protobuf.plugin.http.RequestDefaultsRule.values:type_name -> lynx.protobuf.plugin.http.RequestDefaultsRule.ValuesEntry
+	100, // 48: lynx.protobuf.plugin.http.RequestDefaultsRule.headers:type_name -> lynx.protobuf.plugin.http.RequestDefaultsRule.HeadersEntry
+	24,  // 49: lynx.protobuf.plugin.http.ContentTypeConfig.rules:type_name -> lynx.protobuf.plugin.http.ContentTypeRule
+	47,  // 50: lynx.protobuf.plugin.http.ResponseConfig.size_limit:type_name -> lynx.protobuf.plugin.http.ResponseSizeLimitConfig
+	45,  // 51: lynx.protobuf.plugin.http.ResponseConfig.cache_control:type_name -> lynx.protobuf.plugin.http.CacheControlConfig
+	43,  // 52: lynx.protobuf.plugin.http.ResponseConfig.compression:type_name -> lynx.protobuf.plugin.http.CompressionConfig
+	42,  // 53: lynx.protobuf.plugin.http.ResponseConfig.signing:type_name -> lynx.protobuf.plugin.http.ResponseSigningConfig
+	39,  // 54: lynx.protobuf.plugin.http.ResponseConfig.field_encryption:type_name -> lynx.protobuf.plugin.http.FieldEncryptionConfig
+	36,  // 55: lynx.protobuf.plugin.http.ResponseConfig.export:type_name -> lynx.protobuf.plugin.http.ExportConfig
+	35,  // 56: lynx.protobuf.plugin.http.ResponseConfig.protobuf:type_name -> lynx.protobuf.plugin.http.ProtobufResponseConfig
+	33,  // 57: lynx.protobuf.plugin.http.ResponseConfig.codes:type_name -> lynx.protobuf.plugin.http.EnvelopeCodesConfig
+	32,  // 58: lynx.protobuf.plugin.http.ResponseConfig.warnings:type_name -> lynx.protobuf.plugin.http.WarningsConfig
+	31,  // 59: lynx.protobuf.plugin.http.ResponseConfig.multi_status:type_name -> lynx.protobuf.plugin.http.MultiStatusConfig
+	30,  // 60: lynx.protobuf.plugin.http.ResponseConfig.proto_json:type_name -> lynx.protobuf.plugin.http.ProtoJSONConfig
+	29,  // 61: lynx.protobuf.plugin.http.ResponseConfig.header_scrubbing:type_name -> lynx.protobuf.plugin.http.HeaderScrubbingConfig
+	101, // 62: lynx.protobuf.plugin.http.HeaderScrubbingConfig.rewrite:type_name -> lynx.protobuf.plugin.http.HeaderScrubbingConfig.RewriteEntry
+	34,  // 63: lynx.protobuf.plugin.http.EnvelopeCodesConfig.namespaces:type_name -> lynx.protobuf.plugin.http.CodeNamespace
+	37,  // 64: lynx.protobuf.plugin.http.ExportConfig.rules:type_name -> lynx.protobuf.plugin.http.ExportRule
+	38,  // 65: lynx.protobuf.plugin.http.ExportRule.columns:type_name -> lynx.protobuf.plugin.http.ExportColumn
+	40,  // 66: lynx.protobuf.plugin.http.FieldEncryptionConfig.rules:type_name -> lynx.protobuf.plugin.http.FieldEncryptionRule
+	41,  // 67: lynx.protobuf.plugin.http.FieldEncryptionConfig.keys:type_name -> lynx.protobuf.plugin.http.FieldEncryptionKey
+	44,  // 68: lynx.protobuf.plugin.http.CompressionConfig.cache:type_name -> lynx.protobuf.plugin.http.CompressionCacheConfig
+	106, // 69: lynx.protobuf.plugin.http.CompressionCacheConfig.ttl:type_name -> google.protobuf.Duration
+	46,  // 70: lynx.protobuf.plugin.http.CacheControlConfig.rules:type_name -> lynx.protobuf.plugin.http.CacheControlRule
+	46,  // 71: lynx.protobuf.plugin.http.CacheControlConfig.default:type_name -> lynx.protobuf.plugin.http.CacheControlRule
+	106, // 72: lynx.protobuf.plugin.http.CacheControlRule.max_age:type_name -> google.protobuf.Duration
+	106, // 73: lynx.protobuf.plugin.http.CacheControlRule.s_maxage:type_name -> google.protobuf.Duration
+	106, // 74: lynx.protobuf.plugin.http.CacheControlRule.stale_while_revalidate:type_name -> google.protobuf.Duration
+	106, // 75: lynx.protobuf.plugin.http.ProxyProtocolConfig.header_timeout:type_name -> google.protobuf.Duration
+	71,  // 76: lynx.protobuf.plugin.http.MonitoringConfig.body_logging:type_name -> lynx.protobuf.plugin.http.BodyLoggingConfig
+	70,  // 77: lynx.protobuf.plugin.http.MonitoringConfig.histograms:type_name -> lynx.protobuf.plugin.http.HistogramConfig
+	69,  // 78: lynx.protobuf.plugin.http.MonitoringConfig.otel_metrics:type_name -> lynx.protobuf.plugin.http.OtelMetricsConfig
+	68,  // 79: lynx.protobuf.plugin.http.MonitoringConfig.caller_metrics:type_name -> lynx.protobuf.plugin.http.CallerMetricsConfig
+	65,  // 80: lynx.protobuf.plugin.http.MonitoringConfig.slo:type_name -> lynx.protobuf.plugin.http.SLOConfig
+	63,  // 81: lynx.protobuf.plugin.http.MonitoringConfig.stats_endpoint:type_name -> lynx.protobuf.plugin.http.StatsEndpointConfig
+	62,  // 82: lynx.protobuf.plugin.http.MonitoringConfig.log_sinks:type_name -> lynx.protobuf.plugin.http.LogSinkConfig
+	60,  // 83: lynx.protobuf.plugin.http.MonitoringConfig.log_boost:type_name -> lynx.protobuf.plugin.http.LogBoostConfig
+	59,  // 84: lynx.protobuf.plugin.http.MonitoringConfig.header_logging:type_name -> lynx.protobuf.plugin.http.HeaderLoggingConfig
+	58,  // 85: lynx.protobuf.plugin.http.MonitoringConfig.server_timing:type_name -> lynx.protobuf.plugin.http.ServerTimingConfig
+	57,  // 86: lynx.protobuf.plugin.http.MonitoringConfig.request_cost:type_name -> lynx.protobuf.plugin.http.RequestCostConfig
+	56,  // 87: lynx.protobuf.plugin.http.MonitoringConfig.unmatched_paths:type_name -> lynx.protobuf.plugin.http.UnmatchedPathsConfig
+	54,  // 88: lynx.protobuf.plugin.http.MonitoringConfig.active_requests:type_name -> lynx.protobuf.plugin.http.ActiveRequestsConfig
+	53,  // 89: lynx.protobuf.plugin.http.MonitoringConfig.error_chain:type_name -> lynx.protobuf.plugin.http.ErrorChainConfig
+	52,  // 90: lynx.protobuf.plugin.http.MonitoringConfig.error_classes:type_name -> lynx.protobuf.plugin.http.ErrorClassesConfig
+	51,  // 91: lynx.protobuf.plugin.http.MonitoringConfig.anomaly:type_name -> lynx.protobuf.plugin.http.AnomalyConfig
+	106, // 92: lynx.protobuf.plugin.http.AnomalyConfig.interval:type_name -> google.protobuf.Duration
+	102, // 93: lynx.protobuf.plugin.http.ErrorClassesConfig.reasons:type_name -> lynx.protobuf.plugin.http.ErrorClassesConfig.ReasonsEntry
+	55,  // 94: lynx.protobuf.plugin.http.ActiveRequestsConfig.stuck:type_name -> lynx.protobuf.plugin.http.StuckRequestsConfig
+	106, // 95: lynx.protobuf.plugin.http.StuckRequestsConfig.check_interval:type_name -> google.protobuf.Duration
+	103, // 96: lynx.protobuf.plugin.http.RequestCostConfig.weights:type_name -> lynx.protobuf.plugin.http.RequestCostConfig.WeightsEntry
+	106, // 97: lynx.protobuf.plugin.http.LogBoostConfig.max_duration:type_name -> google.protobuf.Duration
+	61,  // 98: lynx.protobuf.plugin.http.LogBoostConfig.boosts:type_name -> lynx.protobuf.plugin.http.LogBoostRule
+	106, // 99: lynx.protobuf.plugin.http.LogBoostRule.duration:type_name -> google.protobuf.Duration
+	106, // 100: lynx.protobuf.plugin.http.StatsEndpointConfig.window:type_name -> google.protobuf.Duration
+	64,  // 101: lynx.protobuf.plugin.http.StatsEndpointConfig.heavy_hitters:type_name -> lynx.protobuf.plugin.http.HeavyHittersConfig
+	66,  // 102: lynx.protobuf.plugin.http.SLOConfig.objectives:type_name -> lynx.protobuf.plugin.http.SLOObjective
+	67,  // 103: lynx.protobuf.plugin.http.SLOConfig.burn_windows:type_name -> lynx.protobuf.plugin.http.SLOBurnWindow
+	106, // 104: lynx.protobuf.plugin.http.SLOConfig.evaluation_interval:type_name -> google.protobuf.Duration
+	106, // 105: lynx.protobuf.plugin.http.SLOObjective.latency_threshold:type_name -> google.protobuf.Duration
+	106, // 106: lynx.protobuf.plugin.http.SLOBurnWindow.window:type_name -> google.protobuf.Duration
+	104, // 107: lynx.protobuf.plugin.http.BodyLoggingConfig.route_reply_policies:type_name -> lynx.protobuf.plugin.http.BodyLoggingConfig.RouteReplyPoliciesEntry
+	78,  // 108: lynx.protobuf.plugin.http.SecurityConfig.cors:type_name -> lynx.protobuf.plugin.http.CorsConfig
+	79,  // 109: lynx.protobuf.plugin.http.SecurityConfig.rate_limit:type_name -> lynx.protobuf.plugin.http.RateLimitConfig
+	82,  // 110: lynx.protobuf.plugin.http.SecurityConfig.security_headers:type_name -> lynx.protobuf.plugin.http.SecurityHeadersConfig
+	77,  // 111: lynx.protobuf.plugin.http.SecurityConfig.slow_client_protection:type_name -> lynx.protobuf.plugin.http.SlowClientProtectionConfig
+	75,  // 112: lynx.protobuf.plugin.http.SecurityConfig.fingerprint:type_name -> lynx.protobuf.plugin.http.FingerprintConfig
+	73,  // 113: lynx.protobuf.plugin.http.SecurityConfig.honeypot:type_name -> lynx.protobuf.plugin.http.HoneypotConfig
+	74,  // 114: lynx.protobuf.plugin.http.HoneypotConfig.routes:type_name -> lynx.protobuf.plugin.http.HoneypotRoute
+	106, // 115: lynx.protobuf.plugin.http.HoneypotConfig.flag_duration:type_name -> google.protobuf.Duration
+	76,  // 116: lynx.protobuf.plugin.http.FingerprintConfig.rate_limit:type_name -> lynx.protobuf.plugin.http.FingerprintRateLimitConfig
+	106, // 117: lynx.protobuf.plugin.http.SlowClientProtectionConfig.body_read_timeout:type_name -> google.protobuf.Duration
+	106, // 118: lynx.protobuf.plugin.http.SlowClientProtectionConfig.body_rate_grace:type_name -> google.protobuf.Duration
+	106, // 119: lynx.protobuf.plugin.http.SlowClientProtectionConfig.ban_window:type_name -> google.protobuf.Duration
+	106, // 120: lynx.protobuf.plugin.http.SlowClientProtectionConfig.ban_duration:type_name -> google.protobuf.Duration
+	81,  // 121: lynx.protobuf.plugin.http.RateLimitConfig.costs:type_name -> lynx.protobuf.plugin.http.RateLimitCost
+	80,  // 122: lynx.protobuf.plugin.http.RateLimitConfig.headers:type_name -> lynx.protobuf.plugin.http.RateLimitHeadersConfig
+	86,  // 123: lynx.protobuf.plugin.http.PerformanceConfig.connection_pool:type_name -> lynx.protobuf.plugin.http.ConnectionPoolConfig
+	106, // 124: lynx.protobuf.plugin.http.PerformanceConfig.read_timeout:type_name -> google.protobuf.Duration
+	106, // 125: lynx.protobuf.plugin.http.PerformanceConfig.write_timeout:type_name -> google.protobuf.Duration
+	106, // 126: lynx.protobuf.plugin.http.PerformanceConfig.idle_timeout:type_name -> google.protobuf.Duration
+	106, // 127: lynx.protobuf.plugin.http.PerformanceConfig.read_header_timeout:type_name -> google.protobuf.Duration
+	106, // 128: lynx.protobuf.plugin.http.PerformanceConfig.tcp_keep_alive_period:type_name -> google.protobuf.Duration
+	85,  // 129: lynx.protobuf.plugin.http.PerformanceConfig.admission_queues:type_name -> lynx.protobuf.plugin.http.AdmissionQueueConfig
+	84,  // 130: lynx.protobuf.plugin.http.PerformanceConfig.resource_guard:type_name -> lynx.protobuf.plugin.http.ResourceGuardConfig
+	106, // 131: lynx.protobuf.plugin.http.ResourceGuardConfig.check_interval:type_name -> google.protobuf.Duration
+	106, // 132: lynx.protobuf.plugin.http.AdmissionQueueConfig.max_wait:type_name -> google.protobuf.Duration
+	106, // 133: lynx.protobuf.plugin.http.ConnectionPoolConfig.keep_alive_duration:type_name -> google.protobuf.Duration
+	105, // 134: lynx.protobuf.plugin.http.MiddlewareConfig.custom_middleware:type_name -> lynx.protobuf.plugin.http.MiddlewareConfig.CustomMiddlewareEntry
+	93,  // 135: lynx.protobuf.plugin.http.MiddlewareConfig.deadline_propagation:type_name -> lynx.protobuf.plugin.http.DeadlinePropagationConfig
+	92,  // 136: lynx.protobuf.plugin.http.MiddlewareConfig.retry_budget:type_name -> lynx.protobuf.plugin.http.RetryBudgetConfig
+	89,  // 137: lynx.protobuf.plugin.http.MiddlewareConfig.dedup:type_name -> lynx.protobuf.plugin.http.DedupConfig
+	90,  // 138: lynx.protobuf.plugin.http.MiddlewareConfig.coalesce:type_name -> lynx.protobuf.plugin.http.CoalesceConfig
+	91,  // 139: lynx.protobuf.plugin.http.MiddlewareConfig.handler_retry:type_name -> lynx.protobuf.plugin.http.HandlerRetryConfig
+	88,  // 140: lynx.protobuf.plugin.http.MiddlewareConfig.retry_storm:type_name -> lynx.protobuf.plugin.http.RetryStormConfig
+	106, // 141: lynx.protobuf.plugin.http.RetryStormConfig.window:type_name -> google.protobuf.Duration
+	106, // 142: lynx.protobuf.plugin.http.RetryStormConfig.block_duration:type_name -> google.protobuf.Duration
+	106, // 143: lynx.protobuf.plugin.http.DedupConfig.window:type_name -> google.protobuf.Duration
+	106, // 144: lynx.protobuf.plugin.http.HandlerRetryConfig.initial_backoff:type_name -> google.protobuf.Duration
+	106, // 145: lynx.protobuf.plugin.http.HandlerRetryConfig.max_backoff:type_name -> google.protobuf.Duration
+	106, // 146: lynx.protobuf.plugin.http.DeadlinePropagationConfig.max_timeout:type_name -> google.protobuf.Duration
+	106, // 147: lynx.protobuf.plugin.http.DeadlinePropagationConfig.min_timeout:type_name -> google.protobuf.Duration
+	106, // 148: lynx.protobuf.plugin.http.GracefulShutdownConfig.shutdown_timeout:type_name -> google.protobuf.Duration
+	106, // 149: lynx.protobuf.plugin.http.GracefulShutdownConfig.max_wait_time:type_name -> google.protobuf.Duration
+	106, // 150: lynx.protobuf.plugin.http.CircuitBreakerConfig.timeout:type_name -> google.protobuf.Duration
+	151, // [151:151] is the sub-list for method output_type
+	151, // [151:151] is the sub-list for method input_type
+	151, // [151:151] is the sub-list for extension type_name
+	151, // [151:151] is the sub-list for extension extendee
+	0,   // [0:151] is the sub-list for field type_name
 }
 
 func init() { file_http_proto_init() }
@@ -1263,13 +9995,14 @@ func file_http_proto_init() {
 	if File_http_proto != nil {
 		return
 	}
+	file_http_proto_msgTypes[33].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_http_proto_rawDesc), len(file_http_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   12,
+			NumMessages:   106,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
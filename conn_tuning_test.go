@@ -0,0 +1,71 @@
+package http
+
+import (
+	"net"
+	nhttp "net/http"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestApplyPerformanceConfig_TuningKnobs(t *testing.T) {
+	svc := NewServiceHttp()
+	svc.conf = &conf.Http{Performance: &conf.PerformanceConfig{
+		MaxHeaderBytes:     64 << 10,
+		DisableKeepAlives:  true,
+		TcpKeepAlivePeriod: durationpb.New(45 * time.Second),
+	}}
+	svc.initPerformanceDefaults()
+	svc.server = http.NewServer()
+
+	svc.applyPerformanceConfig()
+	assert.Equal(t, 64<<10, svc.server.Server.MaxHeaderBytes)
+	assert.Equal(t, 45*time.Second, svc.tcpKeepAlivePeriod)
+	require.NotNil(t, svc.server.Server.ConnState)
+}
+
+func TestValidateConfig_TuningKnobs(t *testing.T) {
+	svc := NewServiceHttp()
+	svc.conf = &conf.Http{Performance: &conf.PerformanceConfig{MaxHeaderBytes: -1}}
+	require.ErrorContains(t, svc.validateConfigLocked(), "max header bytes")
+
+	svc.conf = &conf.Http{}
+	svc.tcpKeepAlivePeriod = -time.Second
+	require.ErrorContains(t, svc.validateConfigLocked(), "tcp keep alive period")
+}
+
+func TestRecordConnState(t *testing.T) {
+	svc := NewServiceHttp()
+	svc.initMetrics()
+	defer svc.stopMetricsLoop()
+
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+
+	current := func(state string) float64 { return testutil.ToFloat64(svc.connStateCurrent.WithLabelValues(state)) }
+	transitions := func(state string) float64 {
+		return testutil.ToFloat64(svc.connStateTransitions.WithLabelValues(state))
+	}
+	baseActive, baseIdle, baseHijacked := current("active"), current("idle"), transitions("hijacked")
+
+	svc.recordConnState(c1, nhttp.StateNew)
+	svc.recordConnState(c1, nhttp.StateActive)
+	assert.Equal(t, baseActive+1, current("active"))
+	svc.recordConnState(c1, nhttp.StateIdle)
+	assert.Equal(t, baseActive, current("active"))
+	assert.Equal(t, baseIdle+1, current("idle"))
+	svc.recordConnState(c1, nhttp.StateHijacked)
+	assert.Equal(t, baseIdle, current("idle"))
+	assert.Equal(t, baseHijacked+1, transitions("hijacked"))
+
+	// Connections first seen after StateNew are not tracked.
+	svc.recordConnState(c2, nhttp.StateActive)
+	assert.Equal(t, baseActive, current("active"))
+}
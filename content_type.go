@@ -0,0 +1,143 @@
+package http
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+const (
+	// UnsupportedMediaTypeReason is the reason of errors rejecting request bodies whose Content-Type is not
+	// accepted by request.content_types.
+	UnsupportedMediaTypeReason = "UNSUPPORTED_MEDIA_TYPE"
+
+	defaultUnsupportedMediaTypeCode = 415
+)
+
+// contentTypeRule is a resolved ContentTypeRule.
+type contentTypeRule struct {
+	operation string
+	allowed   []string
+}
+
+// contentTypePolicy is the resolved ContentTypeConfig.
+type contentTypePolicy struct {
+	// allowed is nil when any media type with a registered codec is accepted.
+	allowed []string
+	rules   []contentTypeRule
+	code    int
+}
+
+// newContentTypePolicy returns nil when Content-Type checks are disabled.
+func newContentTypePolicy(cfg *conf.ContentTypeConfig) *contentTypePolicy {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	p := &contentTypePolicy{allowed: normalizeMediaTypes(cfg.Allowed), code: defaultUnsupportedMediaTypeCode}
+	if cfg.Code != 0 {
+		p.code = int(cfg.Code)
+	}
+	for _, rule := range cfg.Rules {
+		if op := strings.TrimSpace(rule.GetOperation()); op != "" {
+			p.rules = append(p.rules, contentTypeRule{operation: op, allowed: normalizeMediaTypes(rule.Allowed)})
+		}
+	}
+	return p
+}
+
+func normalizeMediaTypes(types []string) []string {
+	var out []string
+	for _, t := range types {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// allowedFor returns the media types accepted for a request, nil meaning any with a registered codec.
+func (p *contentTypePolicy) allowedFor(operation, path string) []string {
+	for _, rule := range p.rules {
+		if wildcardMatches(rule.operation, operation) || wildcardMatches(rule.operation, path) {
+			return rule.allowed
+		}
+	}
+	return p.allowed
+}
+
+// accepts reports whether mediaType is accepted by allowed.
+func (p *contentTypePolicy) accepts(allowed []string, mediaType string, r *http.Request) bool {
+	if mediaType == "" {
+		return false
+	}
+	if allowed == nil {
+		_, ok := http.CodecForRequest(r, "Content-Type")
+		return ok
+	}
+	for _, pattern := range allowed {
+		if wildcardMatches(pattern, mediaType) {
+			return true
+		}
+	}
+	return false
+}
+
+// withContentTypeCheck rejects request bodies with a Content-Type the policy does not accept before decode
+// runs, so clients get UNSUPPORTED_MEDIA_TYPE instead of a codec error. Empty bodies are not checked.
+func (h *ServiceHttp) withContentTypeCheck(p *contentTypePolicy, decode http.DecodeRequestFunc) http.DecodeRequestFunc {
+	return func(r *http.Request, v any) error {
+		if r.ContentLength == 0 {
+			return decode(r, v)
+		}
+		var operation string
+		if tr, ok := transport.FromServerContext(r.Context()); ok {
+			operation = tr.Operation()
+		}
+		allowed := p.allowedFor(operation, r.URL.Path)
+		header := r.Header.Get("Content-Type")
+		mediaType, _, err := mime.ParseMediaType(header)
+		if err != nil {
+			mediaType = ""
+		}
+		if p.accepts(allowed, mediaType, r) {
+			return decode(r, v)
+		}
+
+		if h.unsupportedContentTypes != nil {
+			h.unsupportedContentTypes.WithLabelValues(operation).Inc()
+		}
+		log.WarnwCtx(r.Context(), "msg", "[HTTP Content-Type]", "api", operation, "content_type", header,
+			"caller", h.requestCaller(r.Context()))
+		metadata := map[string]string{}
+		if len(allowed) > 0 {
+			metadata["allowed"] = strings.Join(allowed, ", ")
+		}
+		return errors.New(p.code, UnsupportedMediaTypeReason, fmt.Sprintf("unsupported content type %q", header)).
+			WithMetadata(metadata)
+	}
+}
+
+// validateContentTypeConfig rejects rules without media types and reserved error codes.
+func validateContentTypeConfig(cfg *conf.ContentTypeConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	for i, rule := range cfg.Rules {
+		if strings.TrimSpace(rule.GetOperation()) == "" {
+			return fmt.Errorf("rules[%d]: operation is required", i)
+		}
+		if len(normalizeMediaTypes(rule.Allowed)) == 0 {
+			return fmt.Errorf("rules[%d]: allowed must list at least one media type", i)
+		}
+	}
+	if cfg.Code < 0 || cfg.Code == 200 || cfg.Code == BodyCodeSystemFailure {
+		return fmt.Errorf("code %d is reserved", cfg.Code)
+	}
+	return nil
+}
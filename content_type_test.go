@@ -0,0 +1,71 @@
+package http
+
+import (
+	nhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentTypeCheck(t *testing.T) {
+	h := &ServiceHttp{
+		conf: &conf.Http{Request: &conf.RequestConfig{ContentTypes: &conf.ContentTypeConfig{
+			Enabled: true,
+			Rules: []*conf.ContentTypeRule{
+				{Operation: "/orders", Allowed: []string{"application/json"}},
+			},
+		}}},
+		unsupportedContentTypes: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "unsupported"}, []string{"route"}),
+	}
+	srv := http.NewServer(
+		http.RequestDecoder(h.requestDecoder()),
+		http.ErrorEncoder(h.enhancedErrorEncoder),
+		http.ResponseEncoder(ResponseEncoder),
+	)
+	bind := func(ctx http.Context) error {
+		var in map[string]any
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		return ctx.Result(nhttp.StatusOK, nil)
+	}
+	route := srv.Route("/")
+	route.POST("/orders", bind)
+	route.POST("/notes", bind)
+
+	post := func(path, contentType, body string) string {
+		req := httptest.NewRequest(nhttp.MethodPost, path, strings.NewReader(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		require.Equal(t, nhttp.StatusOK, rec.Code)
+		return rec.Body.String()
+	}
+
+	assert.JSONEq(t, `{"code":200}`, post("/orders", "application/json; charset=utf-8", `{"id":1}`))
+	assert.JSONEq(t, `{"code":415}`, post("/orders", "application/yaml", "id: 1"), "rules restrict the media types")
+	assert.Equal(t, float64(1), testutil.ToFloat64(h.unsupportedContentTypes.WithLabelValues("/orders")))
+
+	// Without a rule, any media type with a registered codec is accepted.
+	assert.JSONEq(t, `{"code":200}`, post("/notes", "application/yaml", "id: 1"))
+	assert.JSONEq(t, `{"code":415}`, post("/notes", "text/csv", "id\n1"))
+	assert.JSONEq(t, `{"code":415}`, post("/notes", "", `{"id":1}`), "bodies need a Content-Type")
+	assert.JSONEq(t, `{"code":200}`, post("/notes", "", ""), "empty bodies are not checked")
+}
+
+func TestValidateContentTypeConfig(t *testing.T) {
+	assert.NoError(t, validateContentTypeConfig(nil))
+	assert.Error(t, validateContentTypeConfig(&conf.ContentTypeConfig{Rules: []*conf.ContentTypeRule{{Operation: "/a"}}}))
+	assert.Error(t, validateContentTypeConfig(&conf.ContentTypeConfig{Rules: []*conf.ContentTypeRule{{Allowed: []string{"a/b"}}}}))
+	assert.Error(t, validateContentTypeConfig(&conf.ContentTypeConfig{Code: BodyCodeSystemFailure}))
+	assert.Nil(t, newContentTypePolicy(&conf.ContentTypeConfig{Allowed: []string{"application/json"}}), "disabled")
+}
@@ -66,19 +66,19 @@ func TestTraceIDAndSpanIDFromSpan_Invalid(t *testing.T) {
 
 func TestGetClientIP_ForwardedFor(t *testing.T) {
 	h := newFakeHeader(map[string]string{"X-Forwarded-For": "1.2.3.4"})
-	ip := getClientIP(h)
+	ip := getClientIP(context.Background(), h)
 	assert.Equal(t, "1.2.3.4", ip)
 }
 
 func TestGetClientIP_RealIP(t *testing.T) {
 	h := newFakeHeader(map[string]string{"X-Real-IP": "5.6.7.8"})
-	ip := getClientIP(h)
+	ip := getClientIP(context.Background(), h)
 	assert.Equal(t, "5.6.7.8", ip)
 }
 
 func TestGetClientIP_Unknown(t *testing.T) {
 	h := newFakeHeader(nil)
-	ip := getClientIP(h)
+	ip := getClientIP(context.Background(), h)
 	assert.Equal(t, "unknown", ip)
 }
 
@@ -0,0 +1,173 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/conf"
+)
+
+const (
+	// RequestTimeoutHeader carries the caller's remaining time budget in milliseconds.
+	RequestTimeoutHeader = "X-Request-Timeout"
+	grpcTimeoutHeader    = "grpc-timeout"
+
+	// deadlineExceededReason is the Kratos error reason for requests that arrive with no budget left.
+	deadlineExceededReason = "DEADLINE_EXCEEDED"
+)
+
+var defaultDeadlineHeaders = []string{RequestTimeoutHeader, grpcTimeoutHeader}
+
+// deadlinePolicy is the immutable view of DeadlinePropagationConfig used by the middleware.
+type deadlinePolicy struct {
+	headers    []string
+	maxTimeout time.Duration
+	minTimeout time.Duration
+}
+
+func newDeadlinePolicy(cfg *conf.DeadlinePropagationConfig) *deadlinePolicy {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	p := &deadlinePolicy{
+		headers:    defaultDeadlineHeaders,
+		maxTimeout: cfg.GetMaxTimeout().AsDuration(),
+		minTimeout: cfg.GetMinTimeout().AsDuration(),
+	}
+	if len(cfg.Headers) > 0 {
+		p.headers = cfg.Headers
+	}
+	return p
+}
+
+// declaredTimeout returns the first parseable timeout among the configured headers.
+func (p *deadlinePolicy) declaredTimeout(header transport.Header) (time.Duration, bool) {
+	for _, name := range p.headers {
+		v := strings.TrimSpace(header.Get(name))
+		if v == "" {
+			continue
+		}
+		var (
+			d  time.Duration
+			ok bool
+		)
+		if strings.EqualFold(name, grpcTimeoutHeader) {
+			d, ok = parseGRPCTimeout(v)
+		} else {
+			d, ok = parseRequestTimeout(v)
+		}
+		if ok {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// parseRequestTimeout accepts integer milliseconds or a Go duration string.
+func parseRequestTimeout(v string) (time.Duration, bool) {
+	if ms, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.Duration(ms) * time.Millisecond, true
+	}
+	d, err := time.ParseDuration(v)
+	return d, err == nil
+}
+
+// parseGRPCTimeout parses the gRPC wire format: up to 8 digits followed by a unit (H, M, S, m, u, n).
+func parseGRPCTimeout(v string) (time.Duration, bool) {
+	if len(v) < 2 || len(v) > 9 {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(v[:len(v)-1], 10, 64)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	var unit time.Duration
+	switch v[len(v)-1] {
+	case 'H':
+		unit = time.Hour
+	case 'M':
+		unit = time.Minute
+	case 'S':
+		unit = time.Second
+	case 'm':
+		unit = time.Millisecond
+	case 'u':
+		unit = time.Microsecond
+	case 'n':
+		unit = time.Nanosecond
+	default:
+		return 0, false
+	}
+	return time.Duration(n) * unit, true
+}
+
+// deadlinePropagationMiddleware narrows the request context to the caller's declared budget. A deadline
+// already on the context (the server timeout) is kept when it is earlier.
+func (h *ServiceHttp) deadlinePropagationMiddleware(p *deadlinePolicy) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			tr, ok := transport.FromServerContext(ctx)
+			if !ok {
+				return handler(ctx, req)
+			}
+			timeout, ok := p.declaredTimeout(tr.RequestHeader())
+			if !ok {
+				return handler(ctx, req)
+			}
+			if timeout <= 0 || timeout < p.minTimeout {
+				method, path := requestMetadata(ctx)
+				h.recordErrorMetric(method, path, "deadline_exhausted")
+				return nil, errors.GatewayTimeout(deadlineExceededReason,
+					fmt.Sprintf("request timeout budget %v is exhausted", timeout))
+			}
+			if p.maxTimeout > 0 && timeout > p.maxTimeout {
+				timeout = p.maxTimeout
+			}
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return handler(ctx, req)
+		}
+	}
+}
+
+// DeadlinePropagation is a client middleware that forwards the remaining time of the context deadline as
+// X-Request-Timeout, so downstream Lynx services inherit the caller's budget.
+func DeadlinePropagation() middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			if tr, ok := transport.FromClientContext(ctx); ok {
+				if deadline, ok := ctx.Deadline(); ok {
+					remaining := max(time.Until(deadline), 0)
+					tr.RequestHeader().Set(RequestTimeoutHeader, strconv.FormatInt(remaining.Milliseconds(), 10))
+				}
+			}
+			return handler(ctx, req)
+		}
+	}
+}
+
+// validateDeadlinePropagationConfig rejects negative bounds and an inverted min/max pair.
+func validateDeadlinePropagationConfig(cfg *conf.DeadlinePropagationConfig) error {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	maxTimeout, minTimeout := cfg.GetMaxTimeout().AsDuration(), cfg.GetMinTimeout().AsDuration()
+	if maxTimeout < 0 || minTimeout < 0 {
+		return fmt.Errorf("timeouts cannot be negative")
+	}
+	if maxTimeout > 0 && minTimeout > maxTimeout {
+		return fmt.Errorf("min timeout %v exceeds max timeout %v", minTimeout, maxTimeout)
+	}
+	for _, name := range cfg.Headers {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("header names cannot be empty")
+		}
+	}
+	return nil
+}
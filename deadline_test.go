@@ -0,0 +1,106 @@
+package http
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestParseTimeoutHeaders(t *testing.T) {
+	for v, want := range map[string]time.Duration{"250": 250 * time.Millisecond, "1.5s": 1500 * time.Millisecond, "0": 0} {
+		d, ok := parseRequestTimeout(v)
+		assert.True(t, ok, v)
+		assert.Equal(t, want, d, v)
+	}
+	_, ok := parseRequestTimeout("soon")
+	assert.False(t, ok)
+
+	for v, want := range map[string]time.Duration{"100m": 100 * time.Millisecond, "2S": 2 * time.Second, "1H": time.Hour, "5u": 5 * time.Microsecond} {
+		d, ok := parseGRPCTimeout(v)
+		assert.True(t, ok, v)
+		assert.Equal(t, want, d, v)
+	}
+	for _, v := range []string{"", "m", "10x", "123456789S", "-1S"} {
+		_, ok := parseGRPCTimeout(v)
+		assert.False(t, ok, v)
+	}
+}
+
+func runDeadlineMiddleware(t *testing.T, cfg *conf.DeadlinePropagationConfig, ctx context.Context, headers map[string]string) (time.Duration, bool, error) {
+	t.Helper()
+	var (
+		remaining   time.Duration
+		hasDeadline bool
+	)
+	mw := NewServiceHttp().deadlinePropagationMiddleware(newDeadlinePolicy(cfg))
+	ctx = transport.NewServerContext(ctx, newFakeTransport("/api.v1.Test/Get", headers))
+	_, err := mw(func(ctx context.Context, _ any) (any, error) {
+		var deadline time.Time
+		deadline, hasDeadline = ctx.Deadline()
+		remaining = time.Until(deadline)
+		return nil, nil
+	})(ctx, nil)
+	return remaining, hasDeadline, err
+}
+
+func TestDeadlinePropagationMiddleware(t *testing.T) {
+	cfg := &conf.DeadlinePropagationConfig{Enabled: true, MaxTimeout: durationpb.New(2 * time.Second)}
+
+	_, has, err := runDeadlineMiddleware(t, cfg, context.Background(), nil)
+	require.NoError(t, err)
+	assert.False(t, has, "no header leaves the context untouched")
+
+	remaining, has, err := runDeadlineMiddleware(t, cfg, context.Background(), map[string]string{RequestTimeoutHeader: "500"})
+	require.NoError(t, err)
+	require.True(t, has)
+	assert.InDelta(t, 500*time.Millisecond, remaining, float64(50*time.Millisecond))
+
+	remaining, _, err = runDeadlineMiddleware(t, cfg, context.Background(), map[string]string{grpcTimeoutHeader: "1H"})
+	require.NoError(t, err)
+	assert.LessOrEqual(t, remaining, 2*time.Second, "declared timeout is capped")
+
+	// An earlier server deadline wins over a longer declared budget.
+	serverCtx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	remaining, _, err = runDeadlineMiddleware(t, cfg, serverCtx, map[string]string{RequestTimeoutHeader: "1500"})
+	require.NoError(t, err)
+	assert.LessOrEqual(t, remaining, 100*time.Millisecond)
+}
+
+func TestDeadlinePropagationMiddleware_ExhaustedBudget(t *testing.T) {
+	cfg := &conf.DeadlinePropagationConfig{Enabled: true, MinTimeout: durationpb.New(10 * time.Millisecond)}
+	for _, v := range []string{"0", "5"} {
+		_, _, err := runDeadlineMiddleware(t, cfg, context.Background(), map[string]string{RequestTimeoutHeader: v})
+		require.Error(t, err, v)
+		assert.Equal(t, 504, int(errors.Code(err)))
+		assert.Equal(t, deadlineExceededReason, errors.Reason(err))
+	}
+}
+
+func TestDeadlinePropagationClientMiddleware(t *testing.T) {
+	tr := newFakeTransport("/api.v1.Test/Get", nil)
+	ctx, cancel := context.WithTimeout(transport.NewClientContext(context.Background(), tr), time.Second)
+	defer cancel()
+
+	_, err := DeadlinePropagation()(func(context.Context, any) (any, error) { return nil, nil })(ctx, nil)
+	require.NoError(t, err)
+	ms, err := strconv.Atoi(tr.RequestHeader().Get(RequestTimeoutHeader))
+	require.NoError(t, err)
+	assert.InDelta(t, 1000, ms, 50)
+}
+
+func TestValidateDeadlinePropagationConfig(t *testing.T) {
+	assert.NoError(t, validateDeadlinePropagationConfig(&conf.DeadlinePropagationConfig{Enabled: true}))
+	assert.Error(t, validateDeadlinePropagationConfig(&conf.DeadlinePropagationConfig{Enabled: true, MaxTimeout: durationpb.New(-time.Second)}))
+	assert.Error(t, validateDeadlinePropagationConfig(&conf.DeadlinePropagationConfig{Enabled: true,
+		MaxTimeout: durationpb.New(time.Second), MinTimeout: durationpb.New(2 * time.Second)}))
+	assert.Error(t, validateDeadlinePropagationConfig(&conf.DeadlinePropagationConfig{Enabled: true, Headers: []string{" "}}))
+}
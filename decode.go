@@ -0,0 +1,167 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	nhttp "net/http"
+	"reflect"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport/http"
+)
+
+// invalidEnvelopeReason is the Kratos error reason for bodies that are not a valid response envelope.
+const invalidEnvelopeReason = "INVALID_ENVELOPE"
+
+// CodeErrorMapper rebuilds a typed error from a non-success envelope code, reversing the mapping the
+// server applied through ServiceHttp.ErrorCodeMapper. It returns nil to fall back to the generic error.
+type CodeErrorMapper interface {
+	ErrorFromCode(code int, message string) error
+}
+
+// CodeErrorMapperFunc adapts a function to CodeErrorMapper.
+type CodeErrorMapperFunc func(code int, message string) error
+
+// ErrorFromCode calls f.
+func (f CodeErrorMapperFunc) ErrorFromCode(code int, message string) error {
+	return f(code, message)
+}
+
+// DecodeOption configures DecodeResponse and DecodeBody.
+type DecodeOption func(*decodeOptions)
+
+type decodeOptions struct {
+	mapper       CodeErrorMapper
+	successCodes []int
+	minCode      int
+	maxCode      int
+	warnings     *[]Warning
+}
+
+// WithCodeErrorMapper maps non-success codes to typed errors.
+func WithCodeErrorMapper(m CodeErrorMapper) DecodeOption {
+	return func(o *decodeOptions) { o.mapper = m }
+}
+
+// WithSuccessCodes replaces the codes treated as success. Default: 200.
+func WithSuccessCodes(codes ...int) DecodeOption {
+	return func(o *decodeOptions) { o.successCodes = codes }
+}
+
+// WithWarnings stores the warnings of a successful envelope in *warnings.
+func WithWarnings(warnings *[]Warning) DecodeOption {
+	return func(o *decodeOptions) { o.warnings = warnings }
+}
+
+// WithCodeRange rejects envelopes whose code falls outside [minCode, maxCode] as invalid, catching
+// responses from services that do not follow the envelope convention.
+func WithCodeRange(minCode, maxCode int) DecodeOption {
+	return func(o *decodeOptions) { o.minCode, o.maxCode = minCode, maxCode }
+}
+
+// DecodeResponse reads and closes the response body and unwraps the {code,message,data} envelope into T.
+// A non-success code becomes the mapper's typed error, or a Kratos error carrying the code.
+func DecodeResponse[T any](res *nhttp.Response, opts ...DecodeOption) (T, error) {
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return decodeEnvelope[T](res, body, opts)
+}
+
+// DecodeBody unwraps an envelope already read into memory; contentType selects the codec for data
+// (JSON when empty or unknown).
+func DecodeBody[T any](contentType string, body []byte, opts ...DecodeOption) (T, error) {
+	res := &nhttp.Response{Header: nhttp.Header{"Content-Type": []string{contentType}}}
+	return decodeEnvelope[T](res, body, opts)
+}
+
+func decodeEnvelope[T any](res *nhttp.Response, body []byte, opts []DecodeOption) (T, error) {
+	var out T
+	messageType, isProtobuf := protobufMessageType(res.Header.Get("Content-Type"))
+	var (
+		data []byte
+		err  error
+	)
+	if isProtobuf {
+		data, messageType, err = unwrapProtobuf(messageType, body, newDecodeOptions(opts))
+	} else {
+		data, err = unwrapEnvelope(body, newDecodeOptions(opts))
+	}
+	if err != nil || data == nil {
+		return out, err
+	}
+	// Allocate pointer targets so proto messages reach the codec as proto.Message.
+	target := any(&out)
+	if rt := reflect.TypeFor[T](); rt.Kind() == reflect.Pointer {
+		out = reflect.New(rt.Elem()).Interface().(T)
+		target = out
+	}
+	if isProtobuf {
+		return out, unmarshalProtobufData(data, messageType, target)
+	}
+	if err := http.CodecForResponse(res).Unmarshal(data, target); err != nil {
+		return out, errors.New(nhttp.StatusBadGateway, invalidEnvelopeReason, "malformed response data").WithCause(err)
+	}
+	return out, nil
+}
+
+func newDecodeOptions(opts []DecodeOption) decodeOptions {
+	o := decodeOptions{successCodes: []int{nhttp.StatusOK}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// unwrapEnvelope validates the envelope and returns its raw data, nil when there is none.
+func unwrapEnvelope(body []byte, o decodeOptions) (json.RawMessage, error) {
+	var env envelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		return nil, errors.New(nhttp.StatusBadGateway, invalidEnvelopeReason, "malformed response envelope").WithCause(err)
+	}
+	if err := checkEnvelopeCode(env.Code, env.Message, o); err != nil {
+		return nil, err
+	}
+	if o.warnings != nil {
+		*o.warnings = env.Warnings
+	}
+	if len(env.Data) == 0 || bytes.Equal(env.Data, []byte("null")) {
+		return nil, nil
+	}
+	return env.Data, nil
+}
+
+// checkEnvelopeCode returns the error a non-success envelope code stands for, nil for success codes.
+func checkEnvelopeCode(code int, message string, o decodeOptions) error {
+	if code == 0 && !containsCode(o.successCodes, 0) {
+		// Older encoders omit the code on success.
+		code = nhttp.StatusOK
+	}
+	if o.maxCode > 0 && (code < o.minCode || code > o.maxCode) {
+		return errors.New(nhttp.StatusBadGateway, invalidEnvelopeReason,
+			fmt.Sprintf("response code %d outside [%d, %d]", code, o.minCode, o.maxCode))
+	}
+	if !containsCode(o.successCodes, code) {
+		if o.mapper != nil {
+			if err := o.mapper.ErrorFromCode(code, message); err != nil {
+				return err
+			}
+		}
+		return errors.New(code, envelopeErrorReason, message)
+	}
+	return nil
+}
+
+func containsCode(codes []int, code int) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,248 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Reasons of request decoding failures, by cause.
+const (
+	// DecodeSyntaxErrorReason is the reason of bodies that are not well-formed, e.g. truncated JSON.
+	DecodeSyntaxErrorReason = "DECODE_SYNTAX_ERROR"
+	// DecodeUnknownFieldReason is the reason of bodies naming a field the request message does not have.
+	DecodeUnknownFieldReason = "DECODE_UNKNOWN_FIELD"
+	// DecodeTypeMismatchReason is the reason of bodies with a value that does not fit the field type.
+	DecodeTypeMismatchReason = "DECODE_TYPE_MISMATCH"
+	// DecodeEmptyBodyReason is the reason of empty or whitespace-only bodies.
+	DecodeEmptyBodyReason = "DECODE_EMPTY_BODY"
+
+	defaultDecodeErrorCode = 400
+)
+
+var (
+	// Patterns of protojson errors, which only exist as strings.
+	decodePositionPattern     = regexp.MustCompile(`\(line (\d+:\d+)\)`)
+	decodeUnknownFieldPattern = regexp.MustCompile(`unknown field "([^"]+)"`)
+	decodeInvalidValuePattern = regexp.MustCompile(`invalid value for (\S+) field (\S+):`)
+	decodeWellKnownPattern    = regexp.MustCompile(`invalid (google\.protobuf\.\w+) value`)
+)
+
+// decodeErrorPolicy is the resolved DecodeErrorConfig.
+type decodeErrorPolicy struct {
+	codes       map[string]int
+	rejectEmpty bool
+	// strict lists the operation or path patterns whose bodies may not name unknown fields.
+	strict []string
+}
+
+// newDecodeErrorPolicy never returns nil; a nil cfg answers every cause with 400.
+func newDecodeErrorPolicy(cfg *conf.DecodeErrorConfig) *decodeErrorPolicy {
+	p := &decodeErrorPolicy{
+		codes: map[string]int{
+			DecodeSyntaxErrorReason:  defaultDecodeErrorCode,
+			DecodeUnknownFieldReason: defaultDecodeErrorCode,
+			DecodeTypeMismatchReason: defaultDecodeErrorCode,
+			DecodeEmptyBodyReason:    defaultDecodeErrorCode,
+		},
+		rejectEmpty: cfg.GetRejectEmptyBody(),
+	}
+	for _, op := range cfg.GetStrictOperations() {
+		if op = strings.TrimSpace(op); op != "" {
+			p.strict = append(p.strict, op)
+		}
+	}
+	for reason, code := range map[string]int32{
+		DecodeSyntaxErrorReason:  cfg.GetSyntaxErrorCode(),
+		DecodeUnknownFieldReason: cfg.GetUnknownFieldCode(),
+		DecodeTypeMismatchReason: cfg.GetTypeMismatchCode(),
+		DecodeEmptyBodyReason:    cfg.GetEmptyBodyCode(),
+	} {
+		if code != 0 {
+			p.codes[reason] = int(code)
+		}
+	}
+	return p
+}
+
+// decode works like the Kratos default decoder, but classifies unmarshal failures by cause. Zero-length
+// bodies decode to an empty message, whatever their Content-Type, unless reject_empty_body is set.
+func (p *decodeErrorPolicy) decode(r *http.Request, v any) error {
+	data, err := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(data))
+	if err != nil {
+		return errors.BadRequest("CODEC", err.Error())
+	}
+	if len(data) == 0 {
+		if p.rejectEmpty {
+			return p.newError(DecodeEmptyBodyReason, "request body is empty", nil, nil)
+		}
+		return nil
+	}
+	codec, ok := http.CodecForRequest(r, "Content-Type")
+	if !ok {
+		return errors.BadRequest("CODEC", fmt.Sprintf("unregister Content-Type: %s", r.Header.Get("Content-Type")))
+	}
+	if codec.Name() == "json" && p.strictFor(r) {
+		err = unmarshalStrict(data, v)
+	} else {
+		err = codec.Unmarshal(data, v)
+	}
+	if err != nil {
+		return p.classify(data, err)
+	}
+	return nil
+}
+
+// strictFor reports whether the body of r may not name unknown fields.
+func (p *decodeErrorPolicy) strictFor(r *http.Request) bool {
+	if len(p.strict) == 0 {
+		return false
+	}
+	var operation string
+	if tr, ok := transport.FromServerContext(r.Context()); ok {
+		operation = tr.Operation()
+	}
+	for _, pattern := range p.strict {
+		if wildcardMatches(pattern, operation) || wildcardMatches(pattern, r.URL.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+// unmarshalStrict decodes JSON like the Kratos json codec, but fails on fields v does not have.
+func unmarshalStrict(data []byte, v any) error {
+	switch m := v.(type) {
+	case json.Unmarshaler:
+		return m.UnmarshalJSON(data)
+	case proto.Message:
+		return protojson.UnmarshalOptions{}.Unmarshal(data, m)
+	default:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(v); err != nil {
+			return err
+		}
+		if dec.More() {
+			return stderrors.New("syntax error: data after top-level value")
+		}
+		return nil
+	}
+}
+
+// classify maps an unmarshal error to its cause. The message and metadata name fields, types and
+// positions but never values, which may be sensitive. Unrecognized errors keep the Kratos CODEC error.
+func (p *decodeErrorPolicy) classify(data []byte, err error) error {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return p.newError(DecodeEmptyBodyReason, "request body is empty", nil, err)
+	}
+	msg := err.Error()
+	position := ""
+	if m := decodePositionPattern.FindStringSubmatch(msg); m != nil {
+		position = m[1]
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case stderrors.As(err, &syntaxErr):
+		return p.newError(DecodeSyntaxErrorReason, "request body is malformed",
+			map[string]string{"position": strconv.FormatInt(syntaxErr.Offset, 10)}, err)
+	case stderrors.As(err, &typeErr):
+		field := typeErr.Field
+		return p.newError(DecodeTypeMismatchReason, fmt.Sprintf("field %q expects %s", field, typeErr.Type),
+			map[string]string{"field": field, "expected": typeErr.Type.String()}, err)
+	case stderrors.Is(err, io.ErrUnexpectedEOF), strings.Contains(msg, "syntax error"),
+		strings.Contains(msg, "unexpected EOF"):
+		return p.newError(DecodeSyntaxErrorReason, "request body is malformed", positionMetadata(position), err)
+	}
+	if m := decodeUnknownFieldPattern.FindStringSubmatch(msg); m != nil {
+		md := positionMetadata(position)
+		md["field"] = m[1]
+		return p.newError(DecodeUnknownFieldReason, fmt.Sprintf("unknown field %q", m[1]), md, err)
+	}
+	if m := decodeInvalidValuePattern.FindStringSubmatch(msg); m != nil {
+		md := positionMetadata(position)
+		md["field"], md["expected"] = m[2], m[1]
+		return p.newError(DecodeTypeMismatchReason, fmt.Sprintf("field %q expects %s", m[2], m[1]), md, err)
+	}
+	if m := decodeWellKnownPattern.FindStringSubmatch(msg); m != nil {
+		md := positionMetadata(position)
+		md["expected"] = m[1]
+		return p.newError(DecodeTypeMismatchReason, fmt.Sprintf("value expects %s", m[1]), md, err)
+	}
+	return errors.BadRequest("CODEC", fmt.Sprintf("body unmarshal %s", msg))
+}
+
+func positionMetadata(position string) map[string]string {
+	md := make(map[string]string, 3)
+	if position != "" {
+		md["position"] = position
+	}
+	return md
+}
+
+func (p *decodeErrorPolicy) newError(reason, message string, metadata map[string]string, cause error) error {
+	e := errors.New(p.codes[reason], reason, message)
+	if len(metadata) > 0 {
+		e = e.WithMetadata(metadata)
+	}
+	if cause != nil {
+		e = e.WithCause(cause)
+	}
+	return e
+}
+
+// decodeErrorData returns the envelope data of a classified decode error, or nil for other errors.
+func decodeErrorData(se *errors.Error) map[string]string {
+	if se == nil {
+		return nil
+	}
+	switch se.Reason {
+	case DecodeSyntaxErrorReason, DecodeUnknownFieldReason, DecodeTypeMismatchReason, DecodeEmptyBodyReason:
+	default:
+		return nil
+	}
+	data := map[string]string{"reason": se.Reason, "message": se.Message}
+	for _, key := range []string{"field", "expected", "position"} {
+		if v := se.Metadata[key]; v != "" {
+			data[key] = v
+		}
+	}
+	return data
+}
+
+// validateDecodeErrorConfig rejects reserved error codes.
+func validateDecodeErrorConfig(cfg *conf.DecodeErrorConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	codes := []struct {
+		name string
+		code int32
+	}{
+		{"syntax_error_code", cfg.SyntaxErrorCode},
+		{"unknown_field_code", cfg.UnknownFieldCode},
+		{"type_mismatch_code", cfg.TypeMismatchCode},
+		{"empty_body_code", cfg.EmptyBodyCode},
+	}
+	for _, c := range codes {
+		if c.code < 0 || c.code == 200 || c.code == BodyCodeSystemFailure {
+			return fmt.Errorf("%s %d is reserved", c.name, c.code)
+		}
+	}
+	return nil
+}
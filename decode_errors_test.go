@@ -0,0 +1,174 @@
+package http
+
+import (
+	"encoding/json"
+	nhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeErrorPolicy_Classify(t *testing.T) {
+	p := newDecodeErrorPolicy(&conf.DecodeErrorConfig{
+		SyntaxErrorCode:  40001,
+		TypeMismatchCode: 40003,
+		EmptyBodyCode:    40004,
+	})
+	decode := func(body string, v any) *errors.Error {
+		req := httptest.NewRequest(nhttp.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		err := p.decode(req, v)
+		if err == nil {
+			return nil
+		}
+		return errors.FromError(err)
+	}
+
+	tests := []struct {
+		name   string
+		body   string
+		v      any
+		reason string
+		code   int32
+		md     map[string]string
+	}{
+		{"truncated proto", `{"addr":`, &conf.Http{}, DecodeSyntaxErrorReason, 40001, nil},
+		{"bad character proto", `{"addr" x}`, &conf.Http{}, DecodeSyntaxErrorReason, 40001, map[string]string{"position": "1:9"}},
+		{"truncated map", `{"addr":`, &map[string]any{}, DecodeSyntaxErrorReason, 40001, map[string]string{"position": "8"}},
+		{"wrong type proto", `{"addr":1}`, &conf.Http{}, DecodeTypeMismatchReason, 40003,
+			map[string]string{"field": "addr", "expected": "string", "position": "1:9"}},
+		{"wrong duration", `{"timeout":"abc"}`, &conf.Http{}, DecodeTypeMismatchReason, 40003,
+			map[string]string{"expected": "google.protobuf.Duration", "position": "1:12"}},
+		{"wrong type struct", `{"age":"x"}`, &struct{ Age int }{}, DecodeTypeMismatchReason, 40003,
+			map[string]string{"field": "age", "expected": "int"}},
+		{"whitespace", "  \n", &conf.Http{}, DecodeEmptyBodyReason, 40004, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			se := decode(tt.body, tt.v)
+			require.NotNil(t, se)
+			assert.Equal(t, tt.reason, se.Reason)
+			assert.Equal(t, tt.code, se.Code)
+			for k, v := range tt.md {
+				assert.Equal(t, v, se.Metadata[k], k)
+			}
+			assert.NotContains(t, se.Message, `"x"`, "values are never echoed")
+		})
+	}
+
+	assert.Nil(t, decode("", &conf.Http{}), "empty bodies decode to an empty message by default")
+	assert.Nil(t, decode(`{"addr":":80"}`, &conf.Http{}))
+
+	strict := newDecodeErrorPolicy(&conf.DecodeErrorConfig{RejectEmptyBody: true})
+	req := httptest.NewRequest(nhttp.MethodPost, "/", nil)
+	se := errors.FromError(strict.decode(req, &conf.Http{}))
+	assert.Equal(t, DecodeEmptyBodyReason, se.Reason)
+	assert.Equal(t, int32(400), se.Code)
+
+	unknown := p.classify([]byte(`{"adr":1}`), stdError(`proto: (line 1:2): unknown field "adr"`))
+	assert.Equal(t, DecodeUnknownFieldReason, errors.Reason(unknown))
+	assert.Equal(t, "adr", errors.FromError(unknown).Metadata["field"])
+
+	other := p.classify([]byte("<a/>"), stdError("xml: unsupported"))
+	assert.Equal(t, "CODEC", errors.Reason(other), "unrecognized failures keep the generic error")
+}
+
+func TestDecodeErrorPolicy_StrictOperations(t *testing.T) {
+	p := newDecodeErrorPolicy(&conf.DecodeErrorConfig{
+		UnknownFieldCode: 40002,
+		StrictOperations: []string{"/api.v1.Users/*", "/signup"},
+	})
+	decode := func(operation, path, body string, v any) error {
+		req := httptest.NewRequest(nhttp.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if operation != "" {
+			ctx := transport.NewServerContext(req.Context(), newFakeTransport(operation, nil))
+			req = req.WithContext(ctx)
+		}
+		return p.decode(req, v)
+	}
+
+	err := decode("/api.v1.Users/Create", "/v1/users", `{"addr":":80","adr":":81"}`, &conf.Http{})
+	se := errors.FromError(err)
+	require.NotNil(t, se)
+	assert.Equal(t, DecodeUnknownFieldReason, se.Reason)
+	assert.Equal(t, int32(40002), se.Code)
+	assert.Equal(t, "adr", se.Metadata["field"])
+	assert.Equal(t, "1:15", se.Metadata["position"])
+
+	var form struct {
+		Username string `json:"username"`
+	}
+	se = errors.FromError(decode("", "/signup", `{"usernme":"bob"}`, &form))
+	assert.Equal(t, DecodeUnknownFieldReason, se.Reason)
+	assert.Equal(t, "usernme", se.Metadata["field"])
+	assert.NotContains(t, se.Message, "bob")
+	assert.Equal(t, DecodeSyntaxErrorReason, errors.Reason(decode("", "/signup", `{} {}`, &form)))
+
+	assert.NoError(t, decode("", "/signup", `{"username":"bob"}`, &form))
+	assert.NoError(t, decode("/api.v1.Orders/Create", "/v1/orders", `{"adr":":81"}`, &conf.Http{}),
+		"other routes discard unknown fields")
+}
+
+type stdError string
+
+func (e stdError) Error() string { return string(e) }
+
+func TestDecodeErrors_ExposeDetail(t *testing.T) {
+	newServer := func(expose bool) *http.Server {
+		h := &ServiceHttp{conf: &conf.Http{Request: &conf.RequestConfig{DecodeErrors: &conf.DecodeErrorConfig{
+			TypeMismatchCode: 40003,
+			ExposeDetail:     expose,
+		}}}}
+		srv := http.NewServer(
+			http.RequestDecoder(h.requestDecoder()),
+			http.ErrorEncoder(h.enhancedErrorEncoder),
+			http.ResponseEncoder(ResponseEncoder),
+		)
+		srv.Route("/").POST("/servers", func(ctx http.Context) error {
+			var in conf.Http
+			if err := ctx.Bind(&in); err != nil {
+				return err
+			}
+			return ctx.Result(nhttp.StatusOK, nil)
+		})
+		return srv
+	}
+	post := func(srv *http.Server) map[string]any {
+		req := httptest.NewRequest(nhttp.MethodPost, "/servers", strings.NewReader(`{"addr":12345}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		require.Equal(t, nhttp.StatusOK, rec.Code)
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		return body
+	}
+
+	assert.Equal(t, map[string]any{"code": float64(40003)}, post(newServer(false)))
+
+	body := post(newServer(true))
+	assert.Equal(t, float64(40003), body["code"])
+	assert.Equal(t, map[string]any{
+		"reason":   DecodeTypeMismatchReason,
+		"message":  `field "addr" expects string`,
+		"field":    "addr",
+		"expected": "string",
+		"position": "1:9",
+	}, body["data"])
+}
+
+func TestValidateDecodeErrorConfig(t *testing.T) {
+	assert.NoError(t, validateDecodeErrorConfig(nil))
+	assert.NoError(t, validateDecodeErrorConfig(&conf.DecodeErrorConfig{SyntaxErrorCode: 40001}))
+	assert.Error(t, validateDecodeErrorConfig(&conf.DecodeErrorConfig{EmptyBodyCode: 200}))
+	assert.Error(t, validateDecodeErrorConfig(&conf.DecodeErrorConfig{TypeMismatchCode: BodyCodeSystemFailure}))
+	assert.Error(t, validateDecodeErrorConfig(&conf.DecodeErrorConfig{UnknownFieldCode: -1}))
+}
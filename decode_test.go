@@ -0,0 +1,67 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type decodeUser struct {
+	Name string `json:"name"`
+}
+
+func TestDecodeResponse_Struct(t *testing.T) {
+	user, err := DecodeResponse[decodeUser](envelopeResponse(200, `{"code":200,"data":{"name":"lynx"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "lynx", user.Name)
+
+	users, err := DecodeBody[[]decodeUser]("application/json", []byte(`{"code":200,"data":[{"name":"a"},{"name":"b"}]}`))
+	require.NoError(t, err)
+	assert.Len(t, users, 2)
+
+	empty, err := DecodeBody[*decodeUser]("", []byte(`{"code":200}`))
+	require.NoError(t, err)
+	assert.Nil(t, empty)
+}
+
+func TestDecodeResponse_ProtoPointer(t *testing.T) {
+	cfg, err := DecodeBody[*conf.RateLimitConfig]("application/json", []byte(`{"code":200,"data":{"ratePerSecond":50}}`))
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+	assert.Equal(t, int32(50), cfg.GetRatePerSecond())
+}
+
+func TestDecodeResponse_Errors(t *testing.T) {
+	_, err := DecodeBody[decodeUser]("", []byte(`{"code":100004,"message":"user not found"}`))
+	require.Error(t, err)
+	assert.Equal(t, 100004, int(errors.Code(err)))
+	assert.Equal(t, envelopeErrorReason, errors.Reason(err))
+
+	notFound := errors.NotFound("USER_NOT_FOUND", "user not found")
+	mapper := CodeErrorMapperFunc(func(code int, _ string) error {
+		if code == 100004 {
+			return notFound
+		}
+		return nil
+	})
+	_, err = DecodeBody[decodeUser]("", []byte(`{"code":100004}`), WithCodeErrorMapper(mapper))
+	assert.ErrorIs(t, err, notFound)
+	_, err = DecodeBody[decodeUser]("", []byte(`{"code":100005}`), WithCodeErrorMapper(mapper))
+	assert.Equal(t, 100005, int(errors.Code(err)), "unmapped codes fall back to the generic error")
+
+	user, err := DecodeBody[decodeUser]("", []byte(`{"code":0,"data":{"name":"x"}}`), WithSuccessCodes(0, 200))
+	require.NoError(t, err)
+	assert.Equal(t, "x", user.Name)
+
+	_, err = DecodeBody[decodeUser]("", []byte(`{"code":99}`), WithCodeRange(100, 999999))
+	assert.Equal(t, invalidEnvelopeReason, errors.Reason(err))
+
+	_, err = DecodeBody[decodeUser]("", []byte(`<html>`))
+	assert.Equal(t, invalidEnvelopeReason, errors.Reason(err))
+
+	_, err = DecodeBody[decodeUser]("", []byte(`{"code":200,"data":"not an object"}`))
+	assert.Equal(t, invalidEnvelopeReason, errors.Reason(err))
+}
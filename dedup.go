@@ -0,0 +1,235 @@
+package http
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultDedupWindow         = 2 * time.Second
+	defaultDedupIdentityHeader = "Authorization"
+	defaultDedupMaxEntries     = 10000
+)
+
+// dedupKey identifies a submission by method, operation, caller identity and body.
+type dedupKey [sha256.Size]byte
+
+// dedupEntry is a tracked submission. done is closed once reply and err are set.
+type dedupEntry struct {
+	done  chan struct{}
+	reply any
+	err   error
+	// expires is zero while the first request is still running.
+	expires time.Time
+}
+
+// dedupPolicy is the resolved DedupConfig together with the tracked submissions. It is built per
+// middleware chain, so a reconfiguration starts with an empty window.
+type dedupPolicy struct {
+	window         time.Duration
+	methods        map[string]struct{}
+	identityHeader string
+	maxEntries     int
+	now            func() time.Time
+
+	mu      sync.Mutex
+	entries map[dedupKey]*dedupEntry
+}
+
+// newDedupPolicy returns nil when deduplication is disabled.
+func newDedupPolicy(cfg *conf.DedupConfig) *dedupPolicy {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	p := &dedupPolicy{
+		window:         defaultDedupWindow,
+		methods:        map[string]struct{}{"POST": {}},
+		identityHeader: defaultDedupIdentityHeader,
+		maxEntries:     defaultDedupMaxEntries,
+		now:            time.Now,
+		entries:        make(map[dedupKey]*dedupEntry),
+	}
+	if d := cfg.GetWindow().AsDuration(); d > 0 {
+		p.window = d
+	}
+	if len(cfg.Methods) > 0 {
+		p.methods = make(map[string]struct{}, len(cfg.Methods))
+		for _, m := range cfg.Methods {
+			p.methods[strings.ToUpper(strings.TrimSpace(m))] = struct{}{}
+		}
+	}
+	if v := strings.TrimSpace(cfg.IdentityHeader); v != "" {
+		p.identityHeader = v
+	}
+	if cfg.MaxEntries > 0 {
+		p.maxEntries = int(cfg.MaxEntries)
+	}
+	return p
+}
+
+// key hashes the submission: method, operation, host, tenant, virtual host, path, query, caller identity and
+// body. ok is false for methods that are not deduplicated and for bodies that cannot be serialized.
+func (p *dedupPolicy) key(ctx context.Context, tr transport.Transporter, req any) (dedupKey, bool) {
+	method, _ := requestMetadata(ctx)
+	var host, path, query string
+	if r, ok := http.RequestFromServerContext(ctx); ok && r != nil {
+		method, host, path, query = r.Method, strings.ToLower(r.Host), r.URL.Path, r.URL.RawQuery
+	}
+	if _, ok := p.methods[strings.ToUpper(method)]; !ok {
+		return dedupKey{}, false
+	}
+	body, ok := dedupBody(ctx, req)
+	if !ok {
+		return dedupKey{}, false
+	}
+	identity := tr.RequestHeader().Get(p.identityHeader)
+	if identity == "" {
+		identity = getClientIP(ctx, tr.RequestHeader())
+	}
+	tenant, _ := TenantFromContext(ctx)
+	vhost, _ := VirtualHostFromContext(ctx)
+	h := sha256.New()
+	// The path and query carry the parameters of the operation that are not in the body; the host, tenant and
+	// virtual host keep callers sharing an IP in different tenants apart.
+	for _, part := range []string{method, tr.Operation(), host, tenant, vhost, path, query, identity} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	h.Write(body)
+	var k dedupKey
+	h.Sum(k[:0])
+	return k, true
+}
+
+// dedupBody returns the request body bytes: the captured raw body when it is complete, otherwise the
+// deterministic encoding of the decoded request.
+func dedupBody(ctx context.Context, req any) ([]byte, bool) {
+	if raw, ok := RawRequestBody(ctx); ok && raw.Complete {
+		return raw.Bytes, true
+	}
+	if msg, ok := req.(proto.Message); ok {
+		b, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+		return b, err == nil
+	}
+	b, err := json.Marshal(req)
+	return b, err == nil
+}
+
+// claim returns the live entry for k, or registers a new one and reports first. ok is false when the
+// table is full.
+func (p *dedupPolicy) claim(k dedupKey) (e *dedupEntry, first, ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	if e := p.entries[k]; e != nil && (e.expires.IsZero() || now.Before(e.expires)) {
+		return e, false, true
+	}
+	if len(p.entries) >= p.maxEntries {
+		p.pruneLocked(now)
+		if len(p.entries) >= p.maxEntries {
+			return nil, false, false
+		}
+	}
+	e = &dedupEntry{done: make(chan struct{})}
+	p.entries[k] = e
+	return e, true, true
+}
+
+// pruneLocked drops completed entries whose window has passed.
+func (p *dedupPolicy) pruneLocked(now time.Time) {
+	for k, e := range p.entries {
+		if !e.expires.IsZero() && !now.Before(e.expires) {
+			delete(p.entries, k)
+		}
+	}
+}
+
+// complete publishes the result of the first request to its duplicates.
+func (p *dedupPolicy) complete(k dedupKey, e *dedupEntry, reply any, err error, keep bool) {
+	p.mu.Lock()
+	e.reply, e.err = reply, err
+	if keep {
+		e.expires = p.now().Add(p.window)
+	} else if p.entries[k] == e {
+		delete(p.entries, k)
+	}
+	p.mu.Unlock()
+	close(e.done)
+}
+
+// dedupMiddleware runs the first of identical submissions and answers the duplicates that arrive while it
+// runs, or within the window after it completes, with its reply or error. Reply headers set by the
+// handler are not replayed.
+func (h *ServiceHttp) dedupMiddleware(p *dedupPolicy) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (reply any, err error) {
+			tr, ok := transport.FromServerContext(ctx)
+			if !ok {
+				return handler(ctx, req)
+			}
+			k, ok := p.key(ctx, tr, req)
+			if !ok {
+				return handler(ctx, req)
+			}
+			e, first, ok := p.claim(k)
+			if !ok {
+				return handler(ctx, req)
+			}
+			if !first {
+				select {
+				case <-e.done:
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+				if h.dedupCollapsed != nil {
+					_, route := requestMetadata(ctx)
+					h.dedupCollapsed.WithLabelValues(route).Inc()
+				}
+				return e.reply, e.err
+			}
+
+			completed := false
+			defer func() {
+				if !completed {
+					// The handler panicked: release waiting duplicates and let the next submission run.
+					p.complete(k, e, nil, errors.InternalServer("DEDUP_ABORTED", "duplicate of a request that failed"), false)
+				}
+			}()
+			reply, err = handler(ctx, req)
+			completed = true
+			p.complete(k, e, reply, err, true)
+			return reply, err
+		}
+	}
+}
+
+// validateDedupConfig rejects negative windows and limits and empty method names.
+func validateDedupConfig(cfg *conf.DedupConfig) error {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	if cfg.GetWindow().AsDuration() < 0 {
+		return fmt.Errorf("window cannot be negative")
+	}
+	if cfg.MaxEntries < 0 {
+		return fmt.Errorf("max entries cannot be negative")
+	}
+	for _, m := range cfg.Methods {
+		if strings.TrimSpace(m) == "" {
+			return fmt.Errorf("methods contains an empty method")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package http
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx-http/httptesting"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const dedupRoute = "/api.v1.Orders/Create"
+
+func dedupCall(mw func(context.Context, any) (any, error), method, identity, item string) (any, error) {
+	tr := newFakeTransport(dedupRoute, map[string]string{"X-HTTP-Method": method, "Authorization": identity})
+	req, _ := structpb.NewStruct(map[string]any{"item": item})
+	return mw(transport.NewServerContext(context.Background(), tr), req)
+}
+
+func TestDedupMiddleware_CollapsesIdenticalSubmissions(t *testing.T) {
+	svc := NewServiceHttp()
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_dedup_total"}, []string{"route"})
+	svc.dedupCollapsed = counter
+	p := newDedupPolicy(&conf.DedupConfig{Enabled: true})
+	now := time.Unix(1_700_000_000, 0)
+	p.now = func() time.Time { return now }
+
+	var runs atomic.Int32
+	release := make(chan struct{})
+	mw := svc.dedupMiddleware(p)(func(ctx context.Context, req any) (any, error) {
+		n := runs.Add(1)
+		<-release
+		return n, nil
+	})
+
+	var wg sync.WaitGroup
+	replies := make([]any, 3)
+	for i := range replies {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			replies[i], _ = dedupCall(mw, "POST", "Bearer a", "book")
+		}()
+	}
+	require.Eventually(t, func() bool { return runs.Load() == 1 }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, []any{int32(1), int32(1), int32(1)}, replies)
+	assert.Equal(t, 2.0, testutil.ToFloat64(counter.WithLabelValues(dedupRoute)))
+
+	// Within the window the completed reply is replayed; other bodies, callers and methods run.
+	reply, _ := dedupCall(mw, "POST", "Bearer a", "book")
+	assert.Equal(t, int32(1), reply)
+	reply, _ = dedupCall(mw, "POST", "Bearer a", "pen")
+	assert.Equal(t, int32(2), reply)
+	reply, _ = dedupCall(mw, "POST", "Bearer b", "book")
+	assert.Equal(t, int32(3), reply)
+	reply, _ = dedupCall(mw, "GET", "Bearer a", "book")
+	assert.Equal(t, int32(4), reply)
+
+	now = now.Add(3 * time.Second)
+	reply, _ = dedupCall(mw, "POST", "Bearer a", "book")
+	assert.Equal(t, int32(5), reply)
+}
+
+func TestDedupMiddleware_SeparatesPathsOfOneOperation(t *testing.T) {
+	p := newDedupPolicy(&conf.DedupConfig{Enabled: true})
+	var runs atomic.Int32
+	tester := httptesting.NewMiddlewareTester(t, NewServiceHttp().dedupMiddleware(p)).
+		WithHandler(func(ctx context.Context, req any) (any, error) {
+			return runs.Add(1), nil
+		})
+
+	body, _ := structpb.NewStruct(map[string]any{"reason": "abuse"})
+	header := map[string]string{"Authorization": "Bearer a"}
+	for _, path := range []string{"/v1/users/1/disable", "/v1/users/2/disable", "/v1/users/2/disable?notify=true"} {
+		res := tester.Run(httptesting.Request{Operation: "/api.v1.Users/Disable", Method: "POST", Path: path, Header: header,
+			Message: body})
+		res.AssertNoError()
+		res.AssertHandlerCalled(true)
+	}
+	assert.Equal(t, int32(3), runs.Load(), "the path and query are part of the submission")
+
+	res := tester.Run(httptesting.Request{Operation: "/api.v1.Users/Disable", Method: "POST", Path: "/v1/users/1/disable",
+		Header: header, Message: body})
+	assert.Equal(t, int32(1), res.Reply, "repeating a path replays its reply")
+}
+
+func TestDedupMiddleware_SeparatesTenants(t *testing.T) {
+	p := newDedupPolicy(&conf.DedupConfig{Enabled: true})
+	// Stands in for the tenant middleware, which runs earlier in the chain.
+	tenant := func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			if tr, ok := transport.FromServerContext(ctx); ok && tr.RequestHeader().Get("X-Tenant-ID") != "" {
+				ctx = WithTenant(ctx, tr.RequestHeader().Get("X-Tenant-ID"))
+			}
+			return handler(ctx, req)
+		}
+	}
+	tester := httptesting.NewMiddlewareTester(t, tenant, NewServiceHttp().dedupMiddleware(p)).
+		WithHandler(func(ctx context.Context, req any) (any, error) {
+			tenant, _ := TenantFromContext(ctx)
+			return "order of " + tenant, nil
+		})
+
+	// Anonymous callers behind one NAT share the client IP; the same body must not share a reply across tenants.
+	body, _ := structpb.NewStruct(map[string]any{"item": "book"})
+	for _, tenant := range []string{"acme", "globex"} {
+		res := tester.Run(httptesting.Request{Operation: dedupRoute, Method: "POST", Path: "/v1/orders",
+			Header: map[string]string{"X-Tenant-ID": tenant, "X-Forwarded-For": "203.0.113.7"}, Message: body})
+		res.AssertNoError()
+		assert.Equal(t, "order of "+tenant, res.Reply)
+	}
+	res := tester.Run(httptesting.Request{Operation: dedupRoute, Method: "POST", Path: "http://globex.example.com/v1/orders",
+		Header: map[string]string{"X-Tenant-ID": "acme", "X-Forwarded-For": "203.0.113.7"}, Message: body})
+	res.AssertHandlerCalled(true)
+}
+
+func TestDedupMiddleware_PanicReleasesDuplicates(t *testing.T) {
+	p := newDedupPolicy(&conf.DedupConfig{Enabled: true, Methods: []string{"put"}, MaxEntries: 1})
+	started, release := make(chan struct{}), make(chan struct{})
+	var runs atomic.Int32
+	mw := NewServiceHttp().dedupMiddleware(p)(func(ctx context.Context, req any) (any, error) {
+		if runs.Add(1) == 1 {
+			close(started)
+			<-release
+			panic("boom")
+		}
+		return "ok", nil
+	})
+
+	go func() {
+		defer func() { _ = recover() }()
+		_, _ = dedupCall(mw, "PUT", "", "x")
+	}()
+	<-started
+	p.mu.Lock()
+	require.Len(t, p.entries, 1)
+	var pending *dedupEntry
+	for _, e := range p.entries {
+		pending = e
+	}
+	p.mu.Unlock()
+
+	// A different submission finds the table full and runs without deduplication.
+	reply, err := dedupCall(mw, "PUT", "", "y")
+	require.NoError(t, err)
+	assert.Equal(t, "ok", reply)
+
+	// Duplicates waiting on the panicking request receive an error, and the next submission runs.
+	close(release)
+	<-pending.done
+	assert.Error(t, pending.err)
+	reply, err = dedupCall(mw, "PUT", "", "x")
+	require.NoError(t, err)
+	assert.Equal(t, "ok", reply)
+}
+
+func TestValidateDedupConfig(t *testing.T) {
+	assert.NoError(t, validateDedupConfig(nil))
+	assert.NoError(t, validateDedupConfig(&conf.DedupConfig{Enabled: true, Window: durationpb.New(time.Second)}))
+	assert.Error(t, validateDedupConfig(&conf.DedupConfig{Enabled: true, Window: durationpb.New(-time.Second)}))
+	assert.Error(t, validateDedupConfig(&conf.DedupConfig{Enabled: true, MaxEntries: -1}))
+	assert.Error(t, validateDedupConfig(&conf.DedupConfig{Enabled: true, Methods: []string{" "}}))
+}
@@ -0,0 +1,66 @@
+package http
+
+import (
+	"context"
+	"maps"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+)
+
+// ErrorDependencyMetadataKey is the Kratos error metadata key naming the downstream dependency (database,
+// cache, service) an error came from.
+const ErrorDependencyMetadataKey = "dependency"
+
+// WithDependency attributes err to the named dependency, e.g.
+// return nil, http.WithDependency(err, "postgres"). Kratos errors keep their code and reason; other errors
+// become 500 errors with err as their cause. The error encoder counts attributed errors in
+// lynx_http_dependency_errors_total{route,dependency}, and error logs carry the name. An error already
+// attributed keeps its dependency, so the innermost attribution wins.
+func WithDependency(err error, name string) error {
+	name = strings.TrimSpace(name)
+	if err == nil || name == "" || DependencyOf(err) != "" {
+		return err
+	}
+	se := errors.FromError(err)
+	md := maps.Clone(se.Metadata)
+	if md == nil {
+		md = make(map[string]string, 1)
+	}
+	md[ErrorDependencyMetadataKey] = name
+	if error(se) != err {
+		// Keep wrapping errors in the chain.
+		se = errors.New(int(se.Code), se.Reason, se.Message).WithCause(err)
+	}
+	return se.WithMetadata(md)
+}
+
+// DependencyOf returns the dependency err is attributed to, "" when it is not.
+func DependencyOf(err error) string {
+	if err == nil {
+		return ""
+	}
+	return errors.FromError(err).Metadata[ErrorDependencyMetadataKey]
+}
+
+// dependencyClientMiddleware attributes the errors of client calls to name.
+func dependencyClientMiddleware(name string) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			reply, err := handler(ctx, req)
+			return reply, WithDependency(err, name)
+		}
+	}
+}
+
+// recordDependencyError counts an error response attributed to a dependency.
+func (h *ServiceHttp) recordDependencyError(ctx context.Context, err error) {
+	if h.dependencyErrors == nil {
+		return
+	}
+	if dependency := DependencyOf(err); dependency != "" {
+		_, route := requestMetadata(ctx)
+		h.dependencyErrors.WithLabelValues(route, dependency).Inc()
+	}
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	nhttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDependency(t *testing.T) {
+	assert.Nil(t, WithDependency(nil, "postgres"))
+	plain := errors.New("connection refused")
+	assert.Equal(t, plain, WithDependency(plain, " "))
+
+	// Kratos errors keep their code, reason and metadata.
+	notFound := kerrors.NotFound("USER_NOT_FOUND", "user 42").WithMetadata(map[string]string{ErrorModuleMetadataKey: "user"})
+	err := WithDependency(notFound, "user-service")
+	se := kerrors.FromError(err)
+	assert.Equal(t, int32(404), se.Code)
+	assert.Equal(t, "USER_NOT_FOUND", se.Reason)
+	assert.Equal(t, map[string]string{ErrorModuleMetadataKey: "user", ErrorDependencyMetadataKey: "user-service"}, se.Metadata)
+	assert.Empty(t, DependencyOf(notFound))
+
+	// Other errors become 500s wrapping the original, and the innermost attribution wins.
+	err = WithDependency(fmt.Errorf("query users: %w", plain), "postgres")
+	assert.Equal(t, 500, kerrors.Code(err))
+	assert.ErrorIs(t, err, plain)
+	assert.Equal(t, "postgres", DependencyOf(fmt.Errorf("load: %w", err)))
+	assert.Equal(t, "postgres", DependencyOf(WithDependency(err, "redis")))
+	assert.Equal(t, ErrorClassDependency, ClassifyError(err))
+	assert.Equal(t, ErrorClassClient, ClassifyError(WithDependency(notFound, "user-service")))
+	assert.Contains(t, errorLogFields(err), "dependency")
+}
+
+func TestEnhancedErrorEncoder_DependencyMetric(t *testing.T) {
+	h := &ServiceHttp{conf: &conf.Http{}}
+	h.dependencyErrors = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_dependency_errors_total"}, []string{"route", "dependency"})
+	ctx := transport.NewServerContext(context.Background(), newFakeTransport("/api.v1.Users/Get", nil))
+	r := httptest.NewRequest(nhttp.MethodGet, "/users/42", nil).WithContext(ctx)
+
+	h.enhancedErrorEncoder(httptest.NewRecorder(), r, WithDependency(errors.New("timeout"), "redis"))
+	h.enhancedErrorEncoder(httptest.NewRecorder(), r, kerrors.InternalServer("BOOM", ""))
+	assert.Equal(t, 1.0, testutil.ToFloat64(h.dependencyErrors.WithLabelValues("/api.v1.Users/Get", "redis")))
+	assert.Equal(t, 1, testutil.CollectAndCount(h.dependencyErrors))
+}
+
+func TestNewKratosClient_Dependency(t *testing.T) {
+	srv := httptest.NewServer(nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"code":503}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewKratosClient(context.Background(), ClientConfig{Target: "users", Endpoint: srv.URL, Dependency: "user-service"})
+	require.NoError(t, err)
+	defer client.Close()
+
+	err = client.Invoke(context.Background(), nhttp.MethodGet, "/users/42", nil, &struct{}{})
+	require.Error(t, err)
+	assert.Equal(t, "user-service", DependencyOf(err))
+	assert.Equal(t, 503, kerrors.Code(err))
+}
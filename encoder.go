@@ -10,25 +10,21 @@ import (
 	"github.com/go-kratos/kratos/v2/transport/http"
 	"github.com/go-lynx/lynx/log"
 	"google.golang.org/protobuf/proto"
-	"google.golang.org/protobuf/runtime/protoimpl"
 )
 
 // Response represents a standardized HTTP response structure.
-// It contains the status code, message, and an optional data payload.
+// It contains the status code, message, and an optional data payload. It is not a proto message: it is written
+// with the negotiated codec, or as the ProtoEnvelopeType message for the proto codec (see marshalEnvelope).
+// Code is always written, so envelopes with a success_code of 0 carry "code":0.
 type Response struct {
-	// state is the status of the protobuf message for internal handling.
-	state protoimpl.MessageState
-	// sizeCache caches the message size to optimize serialization performance.
-	sizeCache protoimpl.SizeCache
-	// unknownFields stores unknown fields encountered during parsing.
-	unknownFields protoimpl.UnknownFields
-
 	// Code is the response status code.
-	Code int `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Code int `json:"code"`
 	// Message is the descriptive message of the response.
-	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Message string `json:"message,omitempty"`
 	// Data is the payload carried by the response.
-	Data any `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	Data any `json:"data,omitempty"`
+	// Warnings are the non-fatal notices attached with AddWarning.
+	Warnings []Warning `json:"warnings,omitempty"`
 }
 
 // shouldOmitSuccessData 为 true 时不输出 data 字段（例如 LogoutReply 等空 proto、nil、空 map/slice）。
@@ -55,36 +51,64 @@ func shouldOmitSuccessData(data any) bool {
 
 // ResponseEncoder wraps data in the standard {code,data} envelope and writes it as JSON.
 // Success uses code=200; an empty payload omits the data field to avoid emitting "data":{}.
+// Page replies add page, page_size, total and next_cursor to the envelope and set a Link header.
 // 成功时 code=200；无载荷时不输出 data 字段（避免出现 "data":{}）。
 func ResponseEncoder(w http.ResponseWriter, r *http.Request, data any) error {
+	return encodeResponse(w, r, data, nhttp.StatusOK)
+}
+
+// successEncoder returns ResponseEncoder with code as the envelope code of successful replies.
+func successEncoder(code int) http.EncodeResponseFunc {
+	if code == nhttp.StatusOK {
+		return ResponseEncoder
+	}
+	return func(w http.ResponseWriter, r *http.Request, data any) error {
+		return encodeResponse(w, r, data, code)
+	}
+}
+
+func encodeResponse(w http.ResponseWriter, r *http.Request, data any, code int) error {
+	if p, ok := data.(pager); ok {
+		return encodePage(w, r, p, code)
+	}
 	res := &Response{
-		Code: 200,
+		Code:     code,
+		Warnings: Warnings(r.Context()),
 	}
 	if !shouldOmitSuccessData(data) {
 		res.Data = data
 	}
-	codec, ok := http.CodecForRequest(r, "Accept")
-	if !ok || codec == nil {
-		// Fallback to JSON when codec is unavailable or unsupported Accept
-		body, marshalErr := json.Marshal(res)
-		if marshalErr != nil {
-			w.WriteHeader(nhttp.StatusInternalServerError)
-			return marshalErr
-		}
-		w.Header().Set("Content-Type", "application/json")
-		_, wErr := w.Write(body)
-		return wErr
-	}
-	body, marshalErr := codec.Marshal(res)
+	body, contentType, marshalErr := marshalEnvelope(r, res)
 	if marshalErr != nil {
 		w.WriteHeader(nhttp.StatusInternalServerError)
 		return marshalErr
 	}
+	w.Header().Set("Content-Type", contentType)
 	_, wErr := w.Write(body)
-	if wErr != nil {
-		return wErr
+	return wErr
+}
+
+// marshalEnvelope encodes an envelope with the codec named by the Accept header of r. Requests for the proto
+// codec get *Response envelopes with proto or empty data as a ProtoEnvelopeType message, and JSON for other
+// envelopes, which the proto codec cannot carry. Other codecs, such as xml or yaml, marshal the envelope
+// themselves; unknown Accept types fall back to JSON.
+func marshalEnvelope(r *http.Request, env any) ([]byte, string, error) {
+	codec, _ := http.CodecForRequest(r, "Accept")
+	switch {
+	case codec == nil || codec.Name() == "json":
+	case codec.Name() == "proto":
+		if res, ok := env.(*Response); ok {
+			if msg, isProto := res.Data.(proto.Message); isProto || res.Data == nil {
+				body, err := marshalProtoEnvelope(int32(res.Code), res.Message, msg, res.Warnings)
+				return body, ProtobufContentType + `; messageType="` + ProtoEnvelopeType + `"`, err
+			}
+		}
+	default:
+		body, err := codec.Marshal(env)
+		return body, "application/" + codec.Name(), err
 	}
-	return nil
+	body, err := json.Marshal(env)
+	return body, "application/json", err
 }
 
 // EncodeErrorFunc encodes a Kratos error to a generic JSON response with "code" (Kratos Code or 500).
@@ -95,20 +119,12 @@ func EncodeErrorFunc(w http.ResponseWriter, r *http.Request, err error) {
 	res := &Response{
 		Code: code,
 	}
-	codec, ok := http.CodecForRequest(r, "Accept")
-	if !ok || codec == nil {
-		body, _ := json.Marshal(res)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(nhttp.StatusOK)
-		_, _ = w.Write(body)
-		return
-	}
-	body, marshalErr := codec.Marshal(res)
+	body, contentType, marshalErr := marshalEnvelope(r, res)
 	if marshalErr != nil {
 		w.WriteHeader(nhttp.StatusOK)
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Type", contentType)
 	// For security, return 200 for all errors to avoid exposing error information in HTTP status
 	w.WriteHeader(nhttp.StatusOK)
 	_, wErr := w.Write(body)
@@ -0,0 +1,126 @@
+package http
+
+import (
+	"fmt"
+	nhttp "net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-lynx/lynx-http/conf"
+)
+
+// CodeNamespace is the range of business error codes owned by one module.
+type CodeNamespace struct {
+	// Module is the module owning the range, as carried under ErrorModuleMetadataKey.
+	Module string
+	// Min and Max bound the range, inclusive.
+	Min, Max int
+}
+
+// Contains reports whether code falls in the namespace.
+func (n CodeNamespace) Contains(code int) bool {
+	return code >= n.Min && code <= n.Max
+}
+
+// CodeSpace describes the envelope codes of a server: the code of successful replies and the code ranges modules
+// draw their business error codes from.
+type CodeSpace struct {
+	SuccessCode int
+	Namespaces  []CodeNamespace
+}
+
+// namespaceOf returns the namespace containing code, if any.
+func (s CodeSpace) namespaceOf(code int) (CodeNamespace, bool) {
+	for _, n := range s.Namespaces {
+		if n.Contains(code) {
+			return n, true
+		}
+	}
+	return CodeNamespace{}, false
+}
+
+// CodeSpace returns the envelope codes configured under response.codes.
+func (h *ServiceHttp) CodeSpace() CodeSpace {
+	return newCodeSpace(h.conf.GetResponse().GetCodes())
+}
+
+func newCodeSpace(cfg *conf.EnvelopeCodesConfig) CodeSpace {
+	s := CodeSpace{SuccessCode: successCode(cfg)}
+	for _, n := range cfg.GetNamespaces() {
+		s.Namespaces = append(s.Namespaces, CodeNamespace{
+			Module: strings.TrimSpace(n.GetModule()),
+			Min:    int(n.GetMin()),
+			Max:    int(n.GetMax()),
+		})
+	}
+	return s
+}
+
+// successCode returns the envelope code of successful replies. Default: 200.
+func successCode(cfg *conf.EnvelopeCodesConfig) int {
+	if cfg == nil || cfg.SuccessCode == nil {
+		return nhttp.StatusOK
+	}
+	return int(cfg.GetSuccessCode())
+}
+
+// validateEnvelopeCodesConfig checks the success code and the namespaces, and that the error codes configured
+// for the plugin's own errors stay out of both.
+func validateEnvelopeCodesConfig(cfg *conf.Http) error {
+	space := newCodeSpace(cfg.GetResponse().GetCodes())
+	if c := space.SuccessCode; c < 0 || c >= 400 && c <= 599 {
+		return fmt.Errorf("success_code %d is reserved for errors", c)
+	}
+	seen := make(map[string]bool)
+	for i, n := range space.Namespaces {
+		switch {
+		case n.Module == "":
+			return fmt.Errorf("namespaces[%d]: module is required", i)
+		case seen[n.Module]:
+			return fmt.Errorf("namespaces[%d]: module %q has more than one namespace", i, n.Module)
+		case n.Min <= 0 || n.Max < n.Min:
+			return fmt.Errorf("namespaces[%d]: invalid range [%d, %d]", i, n.Min, n.Max)
+		case n.Contains(space.SuccessCode):
+			return fmt.Errorf("namespaces[%d]: range [%d, %d] contains the success code", i, n.Min, n.Max)
+		case n.Contains(BodyCodeSystemFailure):
+			return fmt.Errorf("namespaces[%d]: range [%d, %d] contains the system failure code", i, n.Min, n.Max)
+		}
+		seen[n.Module] = true
+	}
+	sorted := slices.Clone(space.Namespaces)
+	slices.SortFunc(sorted, func(a, b CodeNamespace) int { return a.Min - b.Min })
+	for i := 1; i < len(sorted); i++ {
+		if prev := sorted[i-1]; sorted[i].Min <= prev.Max {
+			return fmt.Errorf("namespaces of %q and %q overlap", prev.Module, sorted[i].Module)
+		}
+	}
+
+	decode := cfg.GetRequest().GetDecodeErrors()
+	errorCodes := []struct {
+		name string
+		code int32
+	}{
+		{"response.size_limit.code", cfg.GetResponse().GetSizeLimit().GetCode()},
+		{"response.multi_status.partial_success_code", cfg.GetResponse().GetMultiStatus().GetPartialSuccessCode()},
+		{"request.query.code", cfg.GetRequest().GetQuery().GetCode()},
+		{"request.content_types.code", cfg.GetRequest().GetContentTypes().GetCode()},
+		{"request.decode_errors.syntax_error_code", decode.GetSyntaxErrorCode()},
+		{"request.decode_errors.unknown_field_code", decode.GetUnknownFieldCode()},
+		{"request.decode_errors.type_mismatch_code", decode.GetTypeMismatchCode()},
+		{"request.decode_errors.empty_body_code", decode.GetEmptyBodyCode()},
+		{"client_info.upgrade_required_code", cfg.GetClientInfo().GetUpgradeRequiredCode()},
+	}
+	for _, c := range errorCodes {
+		if c.code == 0 {
+			// Unset: the plugin uses its default code.
+			continue
+		}
+		if int(c.code) == space.SuccessCode {
+			return fmt.Errorf("%s %d is the success code", c.name, c.code)
+		}
+		if n, ok := space.namespaceOf(int(c.code)); ok {
+			return fmt.Errorf("%s %d is in the namespace of %q", c.name, c.code, n.Module)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package http
+
+import (
+	nhttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/apipb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestResponseEncoder_SuccessCode(t *testing.T) {
+	h := &ServiceHttp{conf: &conf.Http{Response: &conf.ResponseConfig{
+		Codes:    &conf.EnvelopeCodesConfig{SuccessCode: proto.Int32(0)},
+		Protobuf: &conf.ProtobufResponseConfig{Enabled: true, Envelope: true},
+	}}}
+	enc := h.responseEncoder()
+
+	w := httptest.NewRecorder()
+	require.NoError(t, enc(w, httptest.NewRequest(nhttp.MethodGet, "/users/1", nil), &apipb.Api{Name: "lynx"}))
+	assert.JSONEq(t, `{"code":0,"data":{"name":"lynx"}}`, w.Body.String())
+	api, err := DecodeBody[*apipb.Api]("application/json", w.Body.Bytes(), WithSuccessCodes(0))
+	require.NoError(t, err)
+	assert.Equal(t, "lynx", api.GetName())
+
+	w = httptest.NewRecorder()
+	page := NewPage([]string{"a"}, PageRequest{Page: 1, PageSize: 10}, 1)
+	require.NoError(t, enc(w, httptest.NewRequest(nhttp.MethodGet, "/users", nil), page))
+	assert.Contains(t, w.Body.String(), `"code":0,`)
+
+	w = httptest.NewRecorder()
+	require.NoError(t, enc(w, protobufRequest("/users/1", ProtobufContentType), wrapperspb.String("lynx")))
+	out, err := DecodeBody[*wrapperspb.StringValue](w.Header().Get("Content-Type"), w.Body.Bytes(), WithSuccessCodes(0))
+	require.NoError(t, err)
+	assert.Equal(t, "lynx", out.GetValue())
+
+	// Errors mapped to the success code are answered as system failures instead.
+	h.ErrorCodeMapper = func(*kerrors.Error) int { return 0 }
+	assert.Equal(t, BodyCodeSystemFailure, h.responseBodyCodeFromError(kerrors.NotFound("USER_NOT_FOUND", "")))
+	h.ErrorCodeMapper = nil
+	assert.Equal(t, nhttp.StatusNotFound, h.responseBodyCodeFromError(kerrors.NotFound("USER_NOT_FOUND", "")))
+	assert.Equal(t, CodeSpace{SuccessCode: 0}, h.CodeSpace())
+}
+
+func TestValidateEnvelopeCodesConfig(t *testing.T) {
+	valid := func() *conf.Http {
+		return &conf.Http{Response: &conf.ResponseConfig{Codes: &conf.EnvelopeCodesConfig{
+			SuccessCode: proto.Int32(0),
+			Namespaces: []*conf.CodeNamespace{
+				{Module: "user", Min: 100000, Max: 199999},
+				{Module: "order", Min: 200000, Max: 299999},
+			},
+		}}}
+	}
+	require.NoError(t, validateEnvelopeCodesConfig(&conf.Http{}))
+	require.NoError(t, validateEnvelopeCodesConfig(valid()))
+
+	cases := map[string]func(*conf.Http){
+		"reserved for errors": func(c *conf.Http) { c.Response.Codes.SuccessCode = proto.Int32(404) },
+		"module is required":  func(c *conf.Http) { c.Response.Codes.Namespaces[0].Module = "" },
+		"more than one":       func(c *conf.Http) { c.Response.Codes.Namespaces[1].Module = "user" },
+		"invalid range":       func(c *conf.Http) { c.Response.Codes.Namespaces[0].Max = 1 },
+		"overlap":             func(c *conf.Http) { c.Response.Codes.Namespaces[1].Min = 199999 },
+		"contains the success code": func(c *conf.Http) {
+			c.Response.Codes.SuccessCode = proto.Int32(100000)
+		},
+		"contains the system failure code": func(c *conf.Http) {
+			c.Response.Codes.Namespaces = append(c.Response.Codes.Namespaces, &conf.CodeNamespace{Module: "core", Min: 1, Max: 999})
+		},
+		"response.size_limit.code 7 is the success code": func(c *conf.Http) {
+			c.Response.SizeLimit = &conf.ResponseSizeLimitConfig{Code: 7}
+			c.Response.Codes.SuccessCode = proto.Int32(7)
+		},
+		`request.decode_errors.syntax_error_code 100400 is in the namespace of "user"`: func(c *conf.Http) {
+			c.Request = &conf.RequestConfig{DecodeErrors: &conf.DecodeErrorConfig{SyntaxErrorCode: 100400}}
+		},
+	}
+	for want, mutate := range cases {
+		cfg := valid()
+		mutate(cfg)
+		assert.ErrorContains(t, validateEnvelopeCodesConfig(cfg), want)
+	}
+}
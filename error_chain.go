@@ -0,0 +1,150 @@
+package http
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-lynx/lynx-http/conf"
+)
+
+const (
+	defaultErrorChainDepth  = 16
+	defaultErrorChainFrames = 8
+	maxErrorChainLimit      = 256
+)
+
+// ErrorChainEntry is one error of a wrapped error chain.
+type ErrorChainEntry struct {
+	// Type is the Go type of the error, e.g. "*fmt.wrapError".
+	Type string `json:"type"`
+	// Message is the Kratos message of Kratos errors and the Error() text of the others.
+	Message string `json:"message,omitempty"`
+	// Code and Reason are set for Kratos errors.
+	Code   int32  `json:"code,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	// Frames are the "function file:line" stack frames of errors carrying a StackTrace().
+	Frames []string `json:"frames,omitempty"`
+}
+
+// errorChainOptions bounds the captured chain.
+type errorChainOptions struct {
+	maxDepth  int
+	maxFrames int
+	log       bool
+	expose    bool
+}
+
+func newErrorChainOptions(cfg *conf.ErrorChainConfig) *errorChainOptions {
+	if !cfg.GetEnabled() && !cfg.GetExpose() {
+		return nil
+	}
+	o := &errorChainOptions{
+		maxDepth:  int(cfg.GetMaxDepth()),
+		maxFrames: int(cfg.GetMaxFrames()),
+		log:       cfg.GetEnabled(),
+		expose:    cfg.GetExpose(),
+	}
+	if o.maxDepth <= 0 {
+		o.maxDepth = defaultErrorChainDepth
+	}
+	if o.maxFrames <= 0 {
+		o.maxFrames = defaultErrorChainFrames
+	}
+	return o
+}
+
+// ErrorChain returns the errors of err's chain in Unwrap order, depth first through errors joining several
+// (errors.Join), with their stack frames. It is what monitoring.error_chain logs, for handlers writing their
+// own audit records.
+func ErrorChain(err error) []ErrorChainEntry {
+	return errorChain(err, &errorChainOptions{maxDepth: defaultErrorChainDepth, maxFrames: defaultErrorChainFrames})
+}
+
+func errorChain(err error, o *errorChainOptions) []ErrorChainEntry {
+	var chain []ErrorChainEntry
+	var walk func(error)
+	walk = func(err error) {
+		if err == nil || len(chain) >= o.maxDepth {
+			return
+		}
+		entry := ErrorChainEntry{Type: fmt.Sprintf("%T", err), Frames: stackFrames(err, o.maxFrames)}
+		if se, ok := err.(*errors.Error); ok {
+			entry.Message, entry.Code, entry.Reason = se.Message, se.Code, se.Reason
+		} else {
+			entry.Message = err.Error()
+		}
+		chain = append(chain, entry)
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			walk(u.Unwrap())
+		case interface{ Unwrap() []error }:
+			for _, e := range u.Unwrap() {
+				walk(e)
+			}
+		}
+	}
+	walk(err)
+	return chain
+}
+
+// stackFrames renders the StackTrace() of err, if it has one returning program counters such as
+// github.com/pkg/errors.StackTrace. Reflection keeps the plugin free of a dependency on any error package.
+func stackFrames(err error, limit int) []string {
+	m := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return nil
+	}
+	trace := m.Call(nil)[0]
+	if trace.Kind() != reflect.Slice || trace.Type().Elem().Kind() != reflect.Uintptr || trace.Len() == 0 {
+		return nil
+	}
+	pcs := make([]uintptr, 0, min(trace.Len(), limit))
+	for i := 0; i < trace.Len() && len(pcs) < limit; i++ {
+		pcs = append(pcs, uintptr(trace.Index(i).Uint()))
+	}
+	var out []string
+	frames := runtime.CallersFrames(pcs)
+	for {
+		f, more := frames.Next()
+		if f.Function != "" || f.File != "" {
+			out = append(out, fmt.Sprintf("%s %s:%d", f.Function, f.File, f.Line))
+		}
+		if !more || len(out) >= limit {
+			return out
+		}
+	}
+}
+
+// errorChainLogFields returns the "error_chain" field of error log records when capture is enabled.
+func errorChainLogFields(service *ServiceHttp, err error) []any {
+	if err == nil || service == nil {
+		return nil
+	}
+	o := service.monitoringSnapshotOrDefault().errorChain
+	if o == nil || !o.log {
+		return nil
+	}
+	return []any{"error_chain", errorChain(err, o)}
+}
+
+// exposedErrorChain returns the chain added to error responses in internal mode, nil otherwise.
+func (h *ServiceHttp) exposedErrorChain(err error) []ErrorChainEntry {
+	o := h.monitoringSnapshotOrDefault().errorChain
+	if o == nil || !o.expose {
+		return nil
+	}
+	return errorChain(err, o)
+}
+
+// validateErrorChainConfig bounds the chain limits.
+func validateErrorChainConfig(cfg *conf.ErrorChainConfig) error {
+	if d := cfg.GetMaxDepth(); d < 0 || d > maxErrorChainLimit {
+		return fmt.Errorf("max_depth must be between 0 and %d", maxErrorChainLimit)
+	}
+	if f := cfg.GetMaxFrames(); f < 0 || f > maxErrorChainLimit {
+		return fmt.Errorf("max_frames must be between 0 and %d", maxErrorChainLimit)
+	}
+	return nil
+}
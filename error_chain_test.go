@@ -0,0 +1,97 @@
+package http
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	nhttp "net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stackFrame and stackError mimic github.com/pkg/errors, whose StackTrace is a slice of program counters.
+type stackFrame uintptr
+
+type stackError struct {
+	msg   string
+	stack []uintptr
+}
+
+func newStackError(msg string) *stackError {
+	pcs := make([]uintptr, 16)
+	return &stackError{msg: msg, stack: pcs[:runtime.Callers(1, pcs)]}
+}
+
+func (e *stackError) Error() string { return e.msg }
+
+func (e *stackError) StackTrace() []stackFrame {
+	frames := make([]stackFrame, len(e.stack))
+	for i, pc := range e.stack {
+		frames[i] = stackFrame(pc)
+	}
+	return frames
+}
+
+func TestErrorChain(t *testing.T) {
+	root := newStackError("connection refused")
+	err := fmt.Errorf("load user: %w", kerrors.InternalServer("USER_STORE", "user store unavailable").WithCause(root))
+
+	chain := ErrorChain(err)
+	require.Len(t, chain, 3)
+	assert.Equal(t, "*fmt.wrapError", chain[0].Type)
+	assert.Equal(t, ErrorChainEntry{Type: "*errors.Error", Message: "user store unavailable", Code: 500, Reason: "USER_STORE"}, chain[1])
+	assert.Equal(t, "connection refused", chain[2].Message)
+	require.NotEmpty(t, chain[2].Frames)
+	assert.Contains(t, chain[2].Frames[0], "newStackError")
+	assert.LessOrEqual(t, len(chain[2].Frames), defaultErrorChainFrames)
+
+	// Joined errors are walked depth first, and the depth is bounded.
+	joined := stderrors.Join(stderrors.New("a"), fmt.Errorf("b: %w", stderrors.New("c")))
+	var messages []string
+	for _, e := range ErrorChain(joined)[1:] {
+		messages = append(messages, e.Message)
+	}
+	assert.Equal(t, []string{"a", "b: c", "c"}, messages)
+	assert.Len(t, errorChain(err, &errorChainOptions{maxDepth: 2, maxFrames: 1}), 2)
+}
+
+func TestEnhancedErrorEncoder_ErrorChain(t *testing.T) {
+	err := fmt.Errorf("load user: %w", kerrors.InternalServer("USER_STORE", "user store unavailable"))
+	encode := func(cfg *conf.ErrorChainConfig) map[string]any {
+		h := &ServiceHttp{conf: &conf.Http{Monitoring: &conf.MonitoringConfig{ErrorChain: cfg}}}
+		w := httptest.NewRecorder()
+		h.enhancedErrorEncoder(w, httptest.NewRequest(nhttp.MethodGet, "/users/1", nil), err)
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return body
+	}
+
+	// External responses stay code-only, even with the chain logged.
+	assert.Equal(t, map[string]any{"code": float64(500)}, encode(nil))
+	assert.Equal(t, map[string]any{"code": float64(500)}, encode(&conf.ErrorChainConfig{Enabled: true}))
+
+	chain, ok := encode(&conf.ErrorChainConfig{Expose: true})["error_chain"].([]any)
+	require.True(t, ok)
+	require.Len(t, chain, 2)
+	assert.Equal(t, "*fmt.wrapError", chain[0].(map[string]any)["type"])
+	assert.Equal(t, map[string]any{"type": "*errors.Error", "message": "user store unavailable", "code": float64(500), "reason": "USER_STORE"}, chain[1])
+}
+
+func TestErrorChainLogFields(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", stderrors.New("root"))
+	h := &ServiceHttp{conf: &conf.Http{Monitoring: &conf.MonitoringConfig{ErrorChain: &conf.ErrorChainConfig{Enabled: true, MaxDepth: 1}}}}
+	assert.Equal(t, []any{"error_chain", []ErrorChainEntry{{Type: "*fmt.wrapError", Message: "wrapped: root"}}}, errorChainLogFields(h, err))
+	assert.Nil(t, errorChainLogFields(h, nil))
+	assert.Nil(t, errorChainLogFields(&ServiceHttp{conf: &conf.Http{}}, err))
+	assert.Nil(t, errorChainLogFields(nil, err))
+
+	require.NoError(t, validateErrorChainConfig(&conf.ErrorChainConfig{MaxDepth: 32, MaxFrames: 4}))
+	assert.Error(t, validateErrorChainConfig(&conf.ErrorChainConfig{MaxDepth: -1}))
+	assert.Error(t, validateErrorChainConfig(&conf.ErrorChainConfig{MaxFrames: maxErrorChainLimit + 1}))
+}
@@ -0,0 +1,171 @@
+package http
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	nhttp "net/http"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	kratoslog "github.com/go-kratos/kratos/v2/log"
+	"github.com/go-lynx/lynx-http/conf"
+)
+
+// ErrorClass buckets handler errors by who has to act on them.
+type ErrorClass string
+
+const (
+	// ErrorClassClient is a request the caller got wrong or may not make: not found, conflict, rate limited,
+	// business errors.
+	ErrorClassClient ErrorClass = "client"
+	// ErrorClassAuth is a missing or refused credential (401, 403).
+	ErrorClassAuth ErrorClass = "auth"
+	// ErrorClassValidation is a malformed or invalid request (400, 422).
+	ErrorClassValidation ErrorClass = "validation"
+	// ErrorClassDependency is a failing downstream (502, 503, 5xx attributed with WithDependency).
+	ErrorClassDependency ErrorClass = "dependency"
+	// ErrorClassInternal is a failure of the service itself (other 5xx, errors that are not Kratos errors).
+	ErrorClassInternal ErrorClass = "internal"
+	// ErrorClassTimeout is an exceeded deadline (408, 504, context.DeadlineExceeded).
+	ErrorClassTimeout ErrorClass = "timeout"
+)
+
+var (
+	errorClasses = []ErrorClass{
+		ErrorClassClient, ErrorClassAuth, ErrorClassValidation, ErrorClassDependency, ErrorClassInternal, ErrorClassTimeout,
+	}
+	defaultAlertClasses = []ErrorClass{ErrorClassInternal, ErrorClassDependency, ErrorClassTimeout}
+)
+
+// ClientSide reports whether the class is the caller's problem rather than the service's.
+func (c ErrorClass) ClientSide() bool {
+	return c == ErrorClassClient || c == ErrorClassAuth || c == ErrorClassValidation
+}
+
+// ClassifyError buckets err by its Kratos code. Cancelled requests are client errors; codes outside the HTTP
+// error range, as business codes are, count as client errors too.
+func ClassifyError(err error) ErrorClass {
+	switch {
+	case err == nil:
+		return ""
+	case stderrors.Is(err, context.DeadlineExceeded):
+		return ErrorClassTimeout
+	case stderrors.Is(err, context.Canceled):
+		return ErrorClassClient
+	}
+	switch code := int(errors.FromError(err).Code); {
+	case code == nhttp.StatusRequestTimeout || code == nhttp.StatusGatewayTimeout:
+		return ErrorClassTimeout
+	case code == nhttp.StatusUnauthorized || code == nhttp.StatusForbidden:
+		return ErrorClassAuth
+	case code == nhttp.StatusBadRequest || code == nhttp.StatusUnprocessableEntity:
+		return ErrorClassValidation
+	case code == nhttp.StatusBadGateway || code == nhttp.StatusServiceUnavailable:
+		return ErrorClassDependency
+	case code >= 500 && code <= 599 && DependencyOf(err) != "":
+		return ErrorClassDependency
+	case code >= 500 && code <= 599:
+		return ErrorClassInternal
+	default:
+		return ErrorClassClient
+	}
+}
+
+// errorClassifier applies the configured reason overrides and alerting classes.
+type errorClassifier struct {
+	reasons map[string]ErrorClass
+	alert   map[ErrorClass]bool
+}
+
+func newErrorClassifier(cfg *conf.ErrorClassesConfig) *errorClassifier {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	c := &errorClassifier{reasons: make(map[string]ErrorClass, len(cfg.GetReasons())), alert: make(map[ErrorClass]bool)}
+	for reason, class := range cfg.GetReasons() {
+		c.reasons[reason] = ErrorClass(strings.TrimSpace(class))
+	}
+	alert := defaultAlertClasses
+	if len(cfg.GetAlertClasses()) > 0 {
+		alert = nil
+		for _, class := range cfg.GetAlertClasses() {
+			alert = append(alert, ErrorClass(strings.TrimSpace(class)))
+		}
+	}
+	for _, class := range alert {
+		c.alert[class] = true
+	}
+	return c
+}
+
+func (c *errorClassifier) classify(err error) ErrorClass {
+	if err == nil {
+		return ""
+	}
+	if class, ok := c.reasons[errors.Reason(err)]; ok {
+		return class
+	}
+	return ClassifyError(err)
+}
+
+// alerting reports whether err counts against SLO availability.
+func (c *errorClassifier) alerting(err error) bool {
+	return err != nil && c.alert[c.classify(err)]
+}
+
+// ErrorClass classifies err with the reason overrides of monitoring.error_classes, for handlers and hooks
+// feeding their own alerting.
+func (h *ServiceHttp) ErrorClass(err error) ErrorClass {
+	if c := h.monitoringSnapshotOrDefault().errorClasses; c != nil {
+		return c.classify(err)
+	}
+	return ClassifyError(err)
+}
+
+// errorLogLevel is the level of the log record of a failed request: warn for client-side classes when
+// classification is enabled, error otherwise.
+func errorLogLevel(service *ServiceHttp, err error) kratoslog.Level {
+	if service == nil {
+		return kratoslog.LevelError
+	}
+	if c := service.monitoringSnapshotOrDefault().errorClasses; c != nil && c.classify(err).ClientSide() {
+		return kratoslog.LevelWarn
+	}
+	return kratoslog.LevelError
+}
+
+// errorMetricType is the error_type label of an error answered by the error encoder: its class when
+// classification is enabled, otherwise kind.
+func (h *ServiceHttp) errorMetricType(err error, kind string) string {
+	if c := h.monitoringSnapshotOrDefault().errorClasses; c != nil {
+		return string(c.classify(err))
+	}
+	return kind
+}
+
+// validateErrorClassesConfig rejects unknown classes.
+func validateErrorClassesConfig(cfg *conf.ErrorClassesConfig) error {
+	known := func(class string) bool {
+		for _, c := range errorClasses {
+			if ErrorClass(strings.TrimSpace(class)) == c {
+				return true
+			}
+		}
+		return false
+	}
+	for reason, class := range cfg.GetReasons() {
+		if strings.TrimSpace(reason) == "" {
+			return fmt.Errorf("reasons: empty reason")
+		}
+		if !known(class) {
+			return fmt.Errorf("reasons[%s]: unknown class %q", reason, class)
+		}
+	}
+	for i, class := range cfg.GetAlertClasses() {
+		if !known(class) {
+			return fmt.Errorf("alert_classes[%d]: unknown class %q", i, class)
+		}
+	}
+	return nil
+}
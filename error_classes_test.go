@@ -0,0 +1,93 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	kratoslog "github.com/go-kratos/kratos/v2/log"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyError(t *testing.T) {
+	for _, c := range []struct {
+		err  error
+		want ErrorClass
+	}{
+		{nil, ""},
+		{kerrors.NotFound("USER_NOT_FOUND", ""), ErrorClassClient},
+		{kerrors.Conflict("DUPLICATE", ""), ErrorClassClient},
+		{kerrors.New(100004, "USER_LOCKED", ""), ErrorClassClient},
+		{context.Canceled, ErrorClassClient},
+		{kerrors.Unauthorized("TOKEN_EXPIRED", ""), ErrorClassAuth},
+		{kerrors.Forbidden("DENIED", ""), ErrorClassAuth},
+		{kerrors.BadRequest(DecodeSyntaxErrorReason, ""), ErrorClassValidation},
+		{kerrors.New(422, "INVALID", ""), ErrorClassValidation},
+		{kerrors.ServiceUnavailable("DB_DOWN", ""), ErrorClassDependency},
+		{kerrors.New(502, "UPSTREAM", ""), ErrorClassDependency},
+		{kerrors.GatewayTimeout("SLOW", ""), ErrorClassTimeout},
+		{fmt.Errorf("query: %w", context.DeadlineExceeded), ErrorClassTimeout},
+		{kerrors.InternalServer("BOOM", ""), ErrorClassInternal},
+		{errors.New("plain"), ErrorClassInternal},
+	} {
+		assert.Equal(t, c.want, ClassifyError(c.err), "%v", c.err)
+	}
+	assert.True(t, ErrorClassValidation.ClientSide())
+	assert.False(t, ErrorClassTimeout.ClientSide())
+}
+
+func TestErrorClasses(t *testing.T) {
+	service := func(cfg *conf.ErrorClassesConfig) *ServiceHttp {
+		return &ServiceHttp{conf: &conf.Http{Monitoring: &conf.MonitoringConfig{ErrorClasses: cfg, Slo: testSLOConfig()}}}
+	}
+	notFound := kerrors.NotFound("USER_NOT_FOUND", "")
+	storeDown := kerrors.InternalServer("USER_STORE_DOWN", "")
+
+	// Disabled: every failure logs at error with the encoder's kind label.
+	h := service(nil)
+	assert.Equal(t, kratoslog.LevelError, errorLogLevel(h, notFound))
+	assert.Equal(t, "business_error", h.errorMetricType(notFound, "business_error"))
+	assert.Equal(t, ErrorClassInternal, h.ErrorClass(storeDown))
+
+	h = service(&conf.ErrorClassesConfig{Enabled: true, Reasons: map[string]string{"USER_STORE_DOWN": "dependency"}})
+	assert.Equal(t, kratoslog.LevelWarn, errorLogLevel(h, notFound))
+	assert.Equal(t, kratoslog.LevelError, errorLogLevel(h, storeDown))
+	assert.Equal(t, kratoslog.LevelError, errorLogLevel(nil, notFound))
+	assert.Equal(t, "client", h.errorMetricType(notFound, "business_error"))
+	assert.Equal(t, ErrorClassDependency, h.ErrorClass(storeDown))
+
+	// Only alerting classes spend the SLO error budget.
+	snap := h.monitoringSnapshotOrDefault()
+	require.NotNil(t, snap.slo)
+	assert.True(t, snap.slo.unavailable(storeDown))
+	assert.False(t, snap.slo.unavailable(notFound))
+	snap = service(&conf.ErrorClassesConfig{Enabled: true, AlertClasses: []string{"auth"}}).monitoringSnapshotOrDefault()
+	assert.True(t, snap.slo.unavailable(kerrors.Forbidden("DENIED", "")))
+	assert.False(t, snap.slo.unavailable(storeDown))
+
+	tracker := snap.slo
+	now := time.Unix(1_700_000_000, 0)
+	tracker.now = func() time.Time { return now }
+	tracker.record("/api.v1.Users/Get", 0, kerrors.Forbidden("DENIED", ""))
+	now = now.Add(defaultSLOEvaluationInterval)
+	for _, b := range tracker.record("/api.v1.Users/Get", 0, nil) {
+		if b.objective == sloObjectiveAvailability && b.window.label() == "5m" {
+			assert.InDelta(t, 0.5/0.01, b.rate, 1e-9)
+		}
+	}
+}
+
+func TestValidateErrorClassesConfig(t *testing.T) {
+	require.NoError(t, validateErrorClassesConfig(nil))
+	require.NoError(t, validateErrorClassesConfig(&conf.ErrorClassesConfig{
+		Reasons: map[string]string{"DB_DOWN": "dependency"}, AlertClasses: []string{"internal", "timeout"},
+	}))
+	assert.Error(t, validateErrorClassesConfig(&conf.ErrorClassesConfig{Reasons: map[string]string{"DB_DOWN": "database"}}))
+	assert.Error(t, validateErrorClassesConfig(&conf.ErrorClassesConfig{Reasons: map[string]string{" ": "client"}}))
+	assert.Error(t, validateErrorClassesConfig(&conf.ErrorClassesConfig{AlertClasses: []string{"fatal"}}))
+}
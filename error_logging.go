@@ -42,9 +42,10 @@ func (h *ServiceHttp) loggingMiddleware() middleware.Middleware {
 				"latency", time.Since(startTime).Seconds(),
 			}
 			keyvals = append(keyvals, errorLogFields(err)...)
+			keyvals = append(keyvals, errorChainLogFields(h, err)...)
 
 			if err != nil {
-				log.ErrorwCtx(ctx, keyvals...)
+				logwCtx(ctx, errorLogLevel(h, err), keyvals...)
 			} else {
 				log.InfowCtx(ctx, keyvals...)
 			}
@@ -95,6 +96,9 @@ func errorLogFields(err error) []any {
 		if len(se.Metadata) > 0 {
 			fields = append(fields, "error_metadata", se.Metadata)
 		}
+		if dependency := se.Metadata[ErrorDependencyMetadataKey]; dependency != "" {
+			fields = append(fields, "dependency", dependency)
+		}
 	}
 
 	if cause := rootCause(err); cause != nil && cause.Error() != err.Error() {
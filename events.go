@@ -0,0 +1,77 @@
+package http
+
+import (
+	"crypto/tls"
+	"time"
+
+	"github.com/go-lynx/lynx/log"
+	"github.com/go-lynx/lynx/plugins"
+)
+
+// Events the plugin emits to the Lynx plugin event bus, on top of the framework's lifecycle events. Their
+// Metadata keys are listed with each type.
+const (
+	// EventServerStarted is emitted once the server is built and ready: "addr", "network", "tls".
+	EventServerStarted plugins.EventType = "http.server.started"
+	// EventDrainStarted is emitted when the server enters the draining state: "drain_delay".
+	EventDrainStarted plugins.EventType = "http.drain.started"
+	// EventCertificateReloaded is emitted when the certificate provider hands out a new server certificate:
+	// "subject", "not_after".
+	EventCertificateReloaded plugins.EventType = "http.tls.certificate_reloaded"
+	// EventCircuitOpened is emitted when the server circuit breaker opens: "failures", "window_requests".
+	EventCircuitOpened plugins.EventType = "http.circuit_breaker.opened"
+	// EventRateLimitTripped is emitted when the rate limiter starts rejecting requests, at most once per
+	// rateLimitEventInterval: "operation", "rate", "burst".
+	EventRateLimitTripped plugins.EventType = "http.rate_limit.tripped"
+)
+
+// rateLimitEventInterval bounds how often EventRateLimitTripped is emitted while the limiter keeps rejecting.
+const rateLimitEventInterval = time.Minute
+
+// emitEvent publishes an event to the runtime event bus. Events are dropped before the plugin is initialized.
+func (h *ServiceHttp) emitEvent(eventType plugins.EventType, priority int, category string, metadata map[string]any) {
+	if h == nil || h.BasePlugin == nil {
+		return
+	}
+	h.EmitEvent(plugins.PluginEvent{
+		Type:     eventType,
+		Priority: priority,
+		Source:   pluginName,
+		Category: category,
+		Metadata: metadata,
+	})
+}
+
+// emitCertificateReloaded emits EventCertificateReloaded for the newly served certificate.
+func (h *ServiceHttp) emitCertificateReloaded(cert *tls.Certificate) {
+	metadata := map[string]any{}
+	if leaf := cert.Leaf; leaf != nil {
+		metadata["subject"] = leaf.Subject.String()
+		metadata["not_after"] = leaf.NotAfter
+	}
+	log.Infof("Serving reloaded TLS certificate %v", metadata["subject"])
+	h.emitEvent(EventCertificateReloaded, plugins.PriorityNormal, "security", metadata)
+}
+
+// emitCircuitOpened emits EventCircuitOpened; it is the onOpen callback of the server circuit breaker.
+func (h *ServiceHttp) emitCircuitOpened(failures, windowRequests int32) {
+	h.emitEvent(EventCircuitOpened, plugins.PriorityHigh, "health", map[string]any{
+		"failures":        failures,
+		"window_requests": windowRequests,
+	})
+}
+
+// emitRateLimitTripped emits EventRateLimitTripped unless one was emitted within rateLimitEventInterval.
+func (h *ServiceHttp) emitRateLimitTripped(operation string) {
+	now := time.Now().UnixNano()
+	last := h.rateLimitEventAt.Load()
+	if now-last < int64(rateLimitEventInterval) || !h.rateLimitEventAt.CompareAndSwap(last, now) {
+		return
+	}
+	metadata := map[string]any{"operation": operation}
+	if limiter := h.rateLimiter; limiter != nil {
+		metadata["rate"] = float64(limiter.Limit())
+		metadata["burst"] = limiter.Burst()
+	}
+	h.emitEvent(EventRateLimitTripped, plugins.PriorityHigh, "security", metadata)
+}
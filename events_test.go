@@ -0,0 +1,95 @@
+package http
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx-http/httptesting"
+	"github.com/go-lynx/lynx/plugins"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+// eventRecorder is a runtime that keeps the emitted events.
+type eventRecorder struct {
+	plugins.Runtime
+	mu     sync.Mutex
+	events []plugins.PluginEvent
+}
+
+func (r *eventRecorder) EmitEvent(event plugins.PluginEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *eventRecorder) ofType(eventType plugins.EventType) []plugins.PluginEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var events []plugins.PluginEvent
+	for _, e := range r.events {
+		if e.Type == eventType {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+// keyPair is a mutable keyPairSource.
+type keyPair struct{ cert, key []byte }
+
+func (k *keyPair) GetCertificate() []byte { return k.cert }
+func (k *keyPair) GetPrivateKey() []byte  { return k.key }
+
+func TestEvents(t *testing.T) {
+	rt := &eventRecorder{Runtime: plugins.NewSimpleRuntime()}
+	h := NewServiceHttp()
+	require.NoError(t, h.BasePlugin.Initialize(h, rt))
+
+	h.StartDraining()
+	h.StartDraining()
+	require.Len(t, rt.ofType(EventDrainStarted), 1)
+	assert.Equal(t, pluginName, rt.ofType(EventDrainStarted)[0].Source)
+
+	h.conf.CircuitBreaker = &conf.CircuitBreakerConfig{Enabled: true, MaxFailures: 1, FailureThreshold: 1}
+	cb := h.ensureCircuitBreaker()
+	cb.RecordFailure(cb.Allow())
+	opened := rt.ofType(EventCircuitOpened)
+	require.Len(t, opened, 1)
+	assert.Equal(t, int32(1), opened[0].Metadata["failures"])
+	assert.Equal(t, plugins.PriorityHigh, opened[0].Priority)
+
+	h.rateLimiter = rate.NewLimiter(0, 0)
+	tester := httptesting.NewMiddlewareTester(t, h.rateLimitMiddleware())
+	tester.Run(httptesting.Request{Operation: "/api.v1.Orders/List"}).AssertHandlerCalled(false)
+	tester.Run(httptesting.Request{Operation: "/api.v1.Orders/List"}).AssertHandlerCalled(false)
+	tripped := rt.ofType(EventRateLimitTripped)
+	require.Len(t, tripped, 1, "emitted at most once per interval")
+	assert.Equal(t, "/api.v1.Orders/List", tripped[0].Metadata["operation"])
+
+	now := time.Now()
+	source := &keyPair{}
+	source.cert, source.key = selfSignedPair(t, now.Add(-time.Hour), now.Add(time.Hour))
+	get := h.certificateGetter(source)
+	first, err := get(nil)
+	require.NoError(t, err)
+	again, err := get(nil)
+	require.NoError(t, err)
+	assert.Same(t, first, again, "unchanged PEM data is not parsed again")
+	assert.Empty(t, rt.ofType(EventCertificateReloaded))
+
+	source.cert, source.key = selfSignedPair(t, now.Add(-time.Hour), now.Add(2*time.Hour))
+	_, err = get(nil)
+	require.NoError(t, err)
+	reloaded := rt.ofType(EventCertificateReloaded)
+	require.Len(t, reloaded, 1)
+	assert.Equal(t, "CN=preflight", reloaded[0].Metadata["subject"])
+}
+
+func TestEvents_DroppedWithoutPlugin(t *testing.T) {
+	h := &ServiceHttp{}
+	assert.NotPanics(t, func() { h.emitEvent(EventServerStarted, plugins.PriorityNormal, "lifecycle", nil) })
+}
@@ -0,0 +1,531 @@
+package http
+
+import (
+	"archive/zip"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"iter"
+	"mime"
+	nhttp "net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// CSVContentType and XLSXContentType select an export in the Accept header.
+	CSVContentType  = "text/csv"
+	XLSXContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+	// UnsupportedExportFormatReason is the Kratos error reason for a format parameter that is not offered.
+	UnsupportedExportFormatReason = "UNSUPPORTED_EXPORT_FORMAT"
+
+	exportFormatCSV  = "csv"
+	exportFormatXLSX = "xlsx"
+	exportFormatJSON = "json"
+
+	defaultExportFormatParam = "format"
+	defaultExportFilename    = "export"
+	exportFlushInterval      = 100 * time.Millisecond
+	exportFailedReason       = "EXPORT_FAILED"
+)
+
+// exportPolicy is the resolved ExportConfig.
+type exportPolicy struct {
+	param string
+	xlsx  bool
+	rules []exportRule
+}
+
+type exportRule struct {
+	operation string
+	listField string
+	columns   []exportColumn
+	filename  string
+}
+
+type exportColumn struct {
+	path   []string
+	header string
+}
+
+// newExportPolicy returns nil when exports are disabled or no operation is exportable.
+func newExportPolicy(cfg *conf.ExportConfig) *exportPolicy {
+	if !cfg.GetEnabled() || len(cfg.GetRules()) == 0 {
+		return nil
+	}
+	p := &exportPolicy{
+		param: configuredPath(cfg.GetFormatParam(), defaultExportFormatParam),
+		xlsx:  cfg.GetEnableXlsx(),
+	}
+	for _, r := range cfg.GetRules() {
+		rule := exportRule{
+			operation: strings.TrimSpace(r.GetOperation()),
+			listField: strings.TrimSpace(r.GetListField()),
+			filename:  configuredPath(r.GetFilename(), defaultExportFilename),
+		}
+		for _, c := range r.GetColumns() {
+			field := strings.TrimSpace(c.GetField())
+			rule.columns = append(rule.columns, exportColumn{
+				path:   strings.Split(field, "."),
+				header: configuredPath(c.GetHeader(), field),
+			})
+		}
+		p.rules = append(p.rules, rule)
+	}
+	return p
+}
+
+// selection returns the rule and format of an export of r, or a nil rule when r is not exported. A format
+// parameter that is not offered is an error for exportable operations.
+func (p *exportPolicy) selection(r *http.Request) (*exportRule, string, error) {
+	var operation string
+	if tr, ok := transport.FromServerContext(r.Context()); ok {
+		operation = tr.Operation()
+	}
+	var rule *exportRule
+	for i := range p.rules {
+		if routeMatchesAny([]string{p.rules[i].operation}, operation, r.URL.Path) {
+			rule = &p.rules[i]
+			break
+		}
+	}
+	if rule == nil {
+		return nil, "", nil
+	}
+	if format := strings.ToLower(strings.TrimSpace(r.URL.Query().Get(p.param))); format != "" {
+		switch {
+		case format == exportFormatJSON:
+			return nil, "", nil
+		case format == exportFormatCSV, format == exportFormatXLSX && p.xlsx:
+			return rule, format, nil
+		}
+		offered := exportFormatCSV + ", " + exportFormatJSON
+		if p.xlsx {
+			offered = exportFormatCSV + ", " + exportFormatXLSX + ", " + exportFormatJSON
+		}
+		return nil, "", errors.BadRequest(UnsupportedExportFormatReason,
+			fmt.Sprintf("unsupported %s %q, valid options: %s", p.param, format, offered))
+	}
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		switch {
+		case mediaType == CSVContentType:
+			return rule, exportFormatCSV, nil
+		case mediaType == XLSXContentType && p.xlsx:
+			return rule, exportFormatXLSX, nil
+		}
+	}
+	return nil, "", nil
+}
+
+// withExports answers requests for an export of an exportable operation with export, and others with encode.
+// Replies of exportable operations vary by Accept.
+func withExports(p *exportPolicy, export, encode http.EncodeResponseFunc) http.EncodeResponseFunc {
+	return func(w http.ResponseWriter, r *http.Request, data any) error {
+		rule, _, err := p.selection(r)
+		if err != nil {
+			return err
+		}
+		if rule == nil {
+			return encode(w, r, data)
+		}
+		w.Header().Set(varyHeader, mergeVary(w.Header().Get(varyHeader), []string{"Accept"}))
+		return export(w, r, data)
+	}
+}
+
+// encode writes the rows of data in the format selected for r. Rows are read one at a time and flushed
+// periodically. An error after the first row aborts the response, so clients see an incomplete transfer
+// rather than a short file.
+func (p *exportPolicy) encode(w http.ResponseWriter, r *http.Request, data any) error {
+	rule, format, err := p.selection(r)
+	if err != nil {
+		return err
+	}
+	if rule == nil {
+		return ResponseEncoder(w, r, data)
+	}
+	rows, rowType, err := exportRows(rule, data)
+	if err != nil {
+		log.Errorf("Export of %s: %v", streamOperation(r), err)
+		return errors.InternalServer(exportFailedReason, "response could not be exported")
+	}
+	next, stop := iter.Pull2(rows)
+	defer stop()
+	row, err, more := next()
+	if err != nil {
+		return err
+	}
+	columns := rule.columns
+	if len(columns) == 0 {
+		if more {
+			rowType = row.ProtoReflect().Descriptor()
+		}
+		columns = defaultExportColumns(rowType)
+	}
+
+	contentType := CSVContentType + "; charset=utf-8"
+	if format == exportFormatXLSX {
+		contentType = XLSXContentType
+	}
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment",
+		map[string]string{"filename": rule.filename + "." + format}))
+	out, err := startStream(w, contentType)
+	if err != nil {
+		return err
+	}
+	flusher := nhttp.NewResponseController(out)
+	var sheet exportWriter
+	if format == exportFormatXLSX {
+		sheet, err = newXLSXExportWriter(out)
+		if err != nil {
+			return err
+		}
+	} else {
+		sheet = newCSVExportWriter(out)
+	}
+
+	cells := make([]string, len(columns))
+	for i, c := range columns {
+		cells[i] = c.header
+	}
+	if err := sheet.writeRow(cells); err != nil {
+		return err
+	}
+	count := 0
+	lastFlush := time.Now()
+	for ; more; row, err, more = next() {
+		if err == nil {
+			err = r.Context().Err()
+		}
+		if err != nil {
+			log.Errorf("Export of %s aborted after %d rows: %v", streamOperation(r), count, err)
+			panic(nhttp.ErrAbortHandler)
+		}
+		m := row.ProtoReflect()
+		for i, c := range columns {
+			cells[i] = exportCell(m, c.path)
+		}
+		if err := sheet.writeRow(cells); err != nil {
+			return err
+		}
+		count++
+		if time.Since(lastFlush) >= exportFlushInterval {
+			if err := sheet.flush(); err != nil {
+				return err
+			}
+			_ = flusher.Flush()
+			lastFlush = time.Now()
+		}
+	}
+	return sheet.close()
+}
+
+// exportRows returns the rows of data and their message type, when known before the first row: the items of
+// list streams and pages, the list field of proto replies, or the reply itself.
+func exportRows(rule *exportRule, data any) (iter.Seq2[proto.Message, error], protoreflect.MessageDescriptor, error) {
+	switch v := data.(type) {
+	case *ListStream:
+		return v.items, nil, nil
+	case pager:
+		items := reflect.ValueOf(v.pageItems())
+		return func(yield func(proto.Message, error) bool) {
+			for i := 0; i < items.Len(); i++ {
+				m, ok := items.Index(i).Interface().(proto.Message)
+				if !ok {
+					yield(nil, fmt.Errorf("page item %T is not a proto message", items.Index(i).Interface()))
+					return
+				}
+				if !yield(m, nil) {
+					return
+				}
+			}
+		}, nil, nil
+	case proto.Message:
+		m := v.ProtoReflect()
+		fd := exportListField(m.Descriptor(), rule.listField)
+		if fd == nil {
+			if rule.listField != "" {
+				return nil, nil, fmt.Errorf("%s has no repeated message field %q", m.Descriptor().FullName(), rule.listField)
+			}
+			return func(yield func(proto.Message, error) bool) { yield(v, nil) }, m.Descriptor(), nil
+		}
+		list := m.Get(fd).List()
+		return func(yield func(proto.Message, error) bool) {
+			for i := 0; i < list.Len(); i++ {
+				if !yield(list.Get(i).Message().Interface(), nil) {
+					return
+				}
+			}
+		}, fd.Message(), nil
+	}
+	return nil, nil, fmt.Errorf("reply %T is not a proto message", data)
+}
+
+// exportListField returns the repeated message field name of md, or its first one when name is empty.
+func exportListField(md protoreflect.MessageDescriptor, name string) protoreflect.FieldDescriptor {
+	fields := md.Fields()
+	if name != "" {
+		fd := fields.ByName(protoreflect.Name(name))
+		if fd == nil {
+			fd = fields.ByJSONName(name)
+		}
+		if fd == nil || !fd.IsList() || fd.Message() == nil {
+			return nil
+		}
+		return fd
+	}
+	for i := 0; i < fields.Len(); i++ {
+		if fd := fields.Get(i); fd.IsList() && fd.Message() != nil {
+			return fd
+		}
+	}
+	return nil
+}
+
+// defaultExportColumns returns a column per field of md, headed by the field name.
+func defaultExportColumns(md protoreflect.MessageDescriptor) []exportColumn {
+	if md == nil {
+		return nil
+	}
+	columns := make([]exportColumn, 0, md.Fields().Len())
+	for i := 0; i < md.Fields().Len(); i++ {
+		name := string(md.Fields().Get(i).Name())
+		columns = append(columns, exportColumn{path: []string{name}, header: name})
+	}
+	return columns
+}
+
+// exportCell formats the field at path below m. Unset and unknown fields are empty, list elements are
+// joined with ";", and messages are written as their JSON, unquoted for well-known types such as timestamps.
+func exportCell(m protoreflect.Message, path []string) string {
+	fields := m.Descriptor().Fields()
+	fd := fields.ByName(protoreflect.Name(path[0]))
+	if fd == nil {
+		fd = fields.ByJSONName(path[0])
+	}
+	if fd == nil || fd.HasPresence() && !m.Has(fd) {
+		return ""
+	}
+	if len(path) > 1 {
+		if fd.Message() == nil || fd.IsList() || fd.IsMap() {
+			return ""
+		}
+		return exportCell(m.Get(fd).Message(), path[1:])
+	}
+	switch {
+	case fd.IsList():
+		list := m.Get(fd).List()
+		values := make([]string, list.Len())
+		for i := range values {
+			values[i] = exportValue(fd, list.Get(i))
+		}
+		return strings.Join(values, ";")
+	case fd.IsMap():
+		var entries []string
+		m.Get(fd).Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			entries = append(entries, k.String()+"="+exportValue(fd.MapValue(), v))
+			return true
+		})
+		slices.Sort(entries)
+		return strings.Join(entries, ";")
+	}
+	return exportValue(fd, m.Get(fd))
+}
+
+func exportValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return strconv.FormatBool(v.Bool())
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return strconv.Itoa(int(v.Enum()))
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return strconv.FormatInt(v.Int(), 10)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return strconv.FormatUint(v.Uint(), 10)
+	case protoreflect.FloatKind:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 32)
+	case protoreflect.DoubleKind:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	case protoreflect.StringKind:
+		return v.String()
+	case protoreflect.BytesKind:
+		return base64.StdEncoding.EncodeToString(v.Bytes())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		b, err := protojson.Marshal(v.Message().Interface())
+		if err != nil {
+			return ""
+		}
+		if s, err := strconv.Unquote(strings.TrimSpace(string(b))); err == nil {
+			return s
+		}
+		return strings.TrimSpace(string(b))
+	}
+	return ""
+}
+
+// exportWriter writes the rows of an export.
+type exportWriter interface {
+	writeRow(cells []string) error
+	// flush sends buffered rows on to the response.
+	flush() error
+	close() error
+}
+
+// csvExportWriter writes RFC 4180 CSV: CRLF line ends, and fields with commas, quotes or line breaks quoted.
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func newCSVExportWriter(out io.Writer) *csvExportWriter {
+	w := csv.NewWriter(out)
+	w.UseCRLF = true
+	return &csvExportWriter{w: w}
+}
+
+func (c *csvExportWriter) writeRow(cells []string) error { return c.w.Write(cells) }
+
+func (c *csvExportWriter) flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvExportWriter) close() error { return c.flush() }
+
+// xlsxExportWriter writes a workbook with a single sheet of text cells, streaming the sheet into the zip.
+type xlsxExportWriter struct {
+	zip   *zip.Writer
+	sheet io.Writer
+	rows  int
+}
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`
+	xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Export" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+func newXLSXExportWriter(out io.Writer) (*xlsxExportWriter, error) {
+	x := &xlsxExportWriter{zip: zip.NewWriter(out)}
+	for _, part := range []struct{ name, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	} {
+		f, err := x.zip.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, part.body); err != nil {
+			return nil, err
+		}
+	}
+	sheet, err := x.zip.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	x.sheet = sheet
+	_, err = io.WriteString(sheet, xlsxSheetStart)
+	return x, err
+}
+
+func (x *xlsxExportWriter) writeRow(cells []string) error {
+	x.rows++
+	var b strings.Builder
+	fmt.Fprintf(&b, `<row r="%d">`, x.rows)
+	for i, cell := range cells {
+		if cell == "" {
+			continue
+		}
+		fmt.Fprintf(&b, `<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">`, xlsxColumn(i), x.rows)
+		_ = xml.EscapeText(&b, []byte(cell))
+		b.WriteString(`</t></is></c>`)
+	}
+	b.WriteString(`</row>`)
+	_, err := io.WriteString(x.sheet, b.String())
+	return err
+}
+
+func (x *xlsxExportWriter) flush() error { return x.zip.Flush() }
+
+func (x *xlsxExportWriter) close() error {
+	if _, err := io.WriteString(x.sheet, xlsxSheetEnd); err != nil {
+		return err
+	}
+	return x.zip.Close()
+}
+
+// xlsxColumn returns the letters of the 0-based column i: A, B, ..., Z, AA, AB, ...
+func xlsxColumn(i int) string {
+	var letters []byte
+	for i++; i > 0; i = (i - 1) / 26 {
+		letters = append([]byte{byte('A' + (i-1)%26)}, letters...)
+	}
+	return string(letters)
+}
+
+// validateExportConfig requires an operation per rule, field paths for columns and plain file names.
+func validateExportConfig(cfg *conf.ExportConfig) error {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	if strings.ContainsAny(cfg.GetFormatParam(), "&=?# ") {
+		return fmt.Errorf("invalid format_param %q", cfg.GetFormatParam())
+	}
+	for i, rule := range cfg.GetRules() {
+		if strings.TrimSpace(rule.GetOperation()) == "" {
+			return fmt.Errorf("rules[%d]: operation is required", i)
+		}
+		if strings.ContainsAny(rule.GetFilename(), `/\"`) {
+			return fmt.Errorf("rules[%d]: filename %q must not contain slashes or quotes", i, rule.GetFilename())
+		}
+		for j, c := range rule.GetColumns() {
+			field := strings.TrimSpace(c.GetField())
+			if field == "" || slices.Contains(strings.Split(field, "."), "") {
+				return fmt.Errorf("rules[%d].columns[%d]: invalid field path %q", i, j, c.GetField())
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package http
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io"
+	nhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/apipb"
+	"google.golang.org/protobuf/types/known/sourcecontextpb"
+	"google.golang.org/protobuf/types/known/typepb"
+)
+
+var exportTestReply = &apipb.Api{
+	Name: "orders",
+	Methods: []*apipb.Method{
+		{Name: "List, all", RequestStreaming: true, Syntax: typepb.Syntax_SYNTAX_PROTO3},
+		{Name: "Say \"hi\"\nthen go"},
+	},
+	SourceContext: &sourcecontextpb.SourceContext{FileName: "orders.proto"},
+}
+
+// exportServer serves reply on GET /export, which is exportable, and on GET /other, which is not.
+func exportServer(t *testing.T, cfg *conf.ExportConfig, reply func() any) func(target string, header map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	require.NoError(t, validateExportConfig(cfg))
+	p := newExportPolicy(cfg)
+	require.NotNil(t, p)
+	var h ServiceHttp
+	srv := http.NewServer(
+		http.ResponseEncoder(withExports(p, p.encode, ResponseEncoder)),
+		http.ErrorEncoder(h.enhancedErrorEncoder),
+	)
+	srv.Route("/").GET("/export", func(ctx http.Context) error {
+		http.SetOperation(ctx, "/api.v1.Export/List")
+		return ctx.Result(nhttp.StatusOK, reply())
+	})
+	srv.Route("/").GET("/other", func(ctx http.Context) error {
+		http.SetOperation(ctx, "/api.v1.Other/Get")
+		return ctx.Result(nhttp.StatusOK, reply())
+	})
+	return func(target string, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(nhttp.MethodGet, target, nil)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		return rec
+	}
+}
+
+func TestExport_CSV(t *testing.T) {
+	get := exportServer(t, &conf.ExportConfig{
+		Enabled: true,
+		Rules: []*conf.ExportRule{{
+			Operation: "/api.v1.Export/*",
+			Columns: []*conf.ExportColumn{
+				{Field: "name", Header: "Method"},
+				{Field: "requestStreaming"},
+				{Field: "syntax"},
+			},
+			Filename: "methods",
+		}},
+	}, func() any { return exportTestReply })
+
+	rec := get("/export", map[string]string{"Accept": "text/csv;q=0.9, application/json"})
+	assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename=methods.csv`, rec.Header().Get("Content-Disposition"))
+	assert.Equal(t, "Accept", rec.Header().Get("Vary"))
+	assert.Equal(t, "Method,requestStreaming,syntax\r\n"+
+		"\"List, all\",true,SYNTAX_PROTO3\r\n"+
+		"\"Say \"\"hi\"\"\r\nthen go\",false,SYNTAX_PROTO2\r\n", rec.Body.String())
+	records, err := csv.NewReader(strings.NewReader(rec.Body.String())).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Say \"hi\"\nthen go", "false", "SYNTAX_PROTO2"}, records[2], "CRLF in fields reads back as LF")
+
+	assert.Equal(t, rec.Body.String(), get("/export?format=CSV", nil).Body.String())
+	assert.Contains(t, get("/export?format=json", map[string]string{"Accept": "text/csv"}).Body.String(), `"code":200`)
+	assert.Contains(t, get("/export", nil).Body.String(), `"code":200`)
+	assert.JSONEq(t, `{"code":400}`, get("/export?format=xlsx", nil).Body.String(), "XLSX is not enabled")
+
+	other := get("/other?format=csv", map[string]string{"Accept": "text/csv"})
+	assert.Contains(t, other.Body.String(), `"code":200`, "operations without a rule are not exported")
+	assert.Empty(t, other.Header().Get("Vary"))
+}
+
+func TestExport_Rows(t *testing.T) {
+	cfg := &conf.ExportConfig{Enabled: true, Rules: []*conf.ExportRule{{Operation: "/api.v1.Export/List"}}}
+	var reply any
+	get := exportServer(t, cfg, func() any { return reply })
+	csvRows := func() [][]string {
+		records, err := csv.NewReader(get("/export?format=csv", nil).Body).ReadAll()
+		require.NoError(t, err)
+		return records
+	}
+
+	// Without columns, every field of the row message is a column.
+	reply = exportTestReply
+	rows := csvRows()
+	assert.Equal(t, []string{"name", "request_type_url", "request_streaming", "response_type_url", "response_streaming",
+		"options", "syntax", "edition"}, rows[0])
+	assert.Equal(t, []string{"List, all", "", "true", "", "false", "", "SYNTAX_PROTO3", ""}, rows[1])
+
+	// Replies without a list field are a single row.
+	reply = exportTestReply.GetSourceContext()
+	assert.Equal(t, [][]string{{"file_name"}, {"orders.proto"}}, csvRows())
+
+	// A named list field; fields are found by proto or JSON name.
+	cfg.Rules[0].ListField = "mixins"
+	cfg.Rules[0].Columns = []*conf.ExportColumn{{Field: "name"}, {Field: "root"}}
+	get = exportServer(t, cfg, func() any { return reply })
+	reply = &apipb.Api{Methods: []*apipb.Method{{Name: "m"}}, Mixins: []*apipb.Mixin{{Name: "a", Root: "/v1"}, {Name: "b"}}}
+	assert.Equal(t, [][]string{{"name", "root"}, {"a", "/v1"}, {"b", ""}}, csvRows())
+
+	// Nested fields are reached by path, lists are joined with ";" and messages are written as JSON.
+	cfg.Rules[0].ListField = ""
+	cfg.Rules[0].Columns = []*conf.ExportColumn{{Field: "sourceContext.file_name"}, {Field: "oneofs"}, {Field: "source_context"}, {Field: "missing"}}
+	get = exportServer(t, cfg, func() any { return reply })
+	reply = NewListStream(func(yield func(*typepb.Type, error) bool) {
+		_ = yield(&typepb.Type{Oneofs: []string{"a", "b"}, SourceContext: &sourcecontextpb.SourceContext{FileName: "o.proto"}}, nil) &&
+			yield(&typepb.Type{}, nil)
+	})
+	rows = csvRows()
+	require.Len(t, rows, 3)
+	assert.Equal(t, "o.proto", rows[1][0])
+	assert.Equal(t, "a;b", rows[1][1])
+	assert.JSONEq(t, `{"fileName":"o.proto"}`, rows[1][2])
+	assert.Empty(t, rows[1][3], "unknown fields are empty")
+	assert.Equal(t, []string{"", "", "", ""}, rows[2])
+
+	// Pages and list streams export their items.
+	cfg.Rules[0].Columns = []*conf.ExportColumn{{Field: "name"}}
+	get = exportServer(t, cfg, func() any { return reply })
+	reply = NewPage(exportTestReply.GetMethods(), PageRequest{Page: 1, PageSize: 20}, 2)
+	assert.Equal(t, [][]string{{"name"}, {"List, all"}, {"Say \"hi\"\nthen go"}}, csvRows())
+	reply = NewListStream(func(yield func(*apipb.Method, error) bool) {
+		_ = yield(&apipb.Method{Name: "a"}, nil) && yield(&apipb.Method{Name: "b"}, nil)
+	})
+	assert.Equal(t, [][]string{{"name"}, {"a"}, {"b"}}, csvRows())
+
+	// Errors before the first row are answered like handler errors.
+	reply = NewListStream(func(yield func(*apipb.Method, error) bool) {
+		yield(nil, kerrors.Forbidden("EXPORT_DENIED", "no access"))
+	})
+	assert.JSONEq(t, `{"code":403}`, get("/export?format=csv", nil).Body.String())
+}
+
+func TestExport_XLSX(t *testing.T) {
+	get := exportServer(t, &conf.ExportConfig{
+		Enabled:    true,
+		EnableXlsx: true,
+		Rules: []*conf.ExportRule{{
+			Operation: "/api.v1.Export/List",
+			Columns:   []*conf.ExportColumn{{Field: "name", Header: "Name <1>"}, {Field: "syntax"}},
+		}},
+	}, func() any { return exportTestReply })
+
+	rec := get("/export", map[string]string{"Accept": XLSXContentType})
+	assert.Equal(t, XLSXContentType, rec.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename=export.xlsx`, rec.Header().Get("Content-Disposition"))
+	archive, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+	require.NoError(t, err)
+	parts := map[string]string{}
+	for _, f := range archive.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		parts[f.Name] = string(b)
+	}
+	assert.Contains(t, parts, "[Content_Types].xml")
+	assert.Contains(t, parts, "xl/workbook.xml")
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<row r="1"><c r="A1" t="inlineStr"><is><t xml:space="preserve">Name &lt;1&gt;</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="B2" t="inlineStr"><is><t xml:space="preserve">SYNTAX_PROTO3</t></is></c>`)
+	assert.Contains(t, sheet, `<t xml:space="preserve">Say &#34;hi&#34;&#xA;then go</t>`)
+	assert.True(t, strings.HasSuffix(sheet, `</sheetData></worksheet>`))
+}
+
+func TestExport_AbortsOnLateErrors(t *testing.T) {
+	p := newExportPolicy(&conf.ExportConfig{Enabled: true, Rules: []*conf.ExportRule{{Operation: "/api.v1.Export/List"}}})
+	srv := http.NewServer(http.ResponseEncoder(withExports(p, p.encode, ResponseEncoder)))
+	srv.Route("/").GET("/export", func(ctx http.Context) error {
+		http.SetOperation(ctx, "/api.v1.Export/List")
+		return ctx.Result(nhttp.StatusOK, NewListStream(func(yield func(*apipb.Method, error) bool) {
+			_ = yield(&apipb.Method{Name: "a"}, nil) && yield(nil, kerrors.ServiceUnavailable("DB_UNAVAILABLE", "connection lost"))
+		}))
+	})
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	// The connection is dropped before the response ends, whether or not the first rows were flushed.
+	res, err := ts.Client().Get(ts.URL + "/export?format=csv")
+	if err == nil {
+		defer res.Body.Close()
+		_, err = io.ReadAll(res.Body)
+	}
+	assert.Error(t, err, "a cut-off export is not mistaken for a complete file")
+}
+
+func TestXLSXColumn(t *testing.T) {
+	for i, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 701: "ZZ", 702: "AAA"} {
+		assert.Equal(t, want, xlsxColumn(i))
+	}
+}
+
+func TestValidateExportConfig(t *testing.T) {
+	require.NoError(t, validateExportConfig(nil))
+	assert.Nil(t, newExportPolicy(&conf.ExportConfig{Enabled: true}))
+	assert.ErrorContains(t, validateExportConfig(&conf.ExportConfig{Enabled: true, Rules: []*conf.ExportRule{{}}}),
+		"operation is required")
+	assert.ErrorContains(t, validateExportConfig(&conf.ExportConfig{Enabled: true, Rules: []*conf.ExportRule{
+		{Operation: "/a", Filename: "../x"}}}), "filename")
+	assert.ErrorContains(t, validateExportConfig(&conf.ExportConfig{Enabled: true, Rules: []*conf.ExportRule{
+		{Operation: "/a", Columns: []*conf.ExportColumn{{Field: "customer..name"}}}}}), "invalid field path")
+	assert.ErrorContains(t, validateExportConfig(&conf.ExportConfig{Enabled: true, FormatParam: "a=b"}), "format_param")
+}
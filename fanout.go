@@ -0,0 +1,173 @@
+package http
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	fanOutTracerName = "lynx-http-fanout"
+
+	// FanOutTimeoutReason is the Kratos error reason for downstream calls that exceed their timeout.
+	FanOutTimeoutReason = "FANOUT_TIMEOUT"
+	// fanOutPanicReason is the Kratos error reason for downstream calls that panic.
+	fanOutPanicReason = "FANOUT_PANIC"
+)
+
+// FanOutCall is one downstream call of a fan-out.
+type FanOutCall struct {
+	// Name keys the result in FanOutResult and names the call's span. Names must be unique.
+	Name string
+	// Call performs the downstream request. It must honour ctx cancellation.
+	Call func(ctx context.Context) (any, error)
+	// Timeout bounds the call; zero leaves only the parent deadline.
+	Timeout time.Duration
+	// Required fails the whole fan-out when the call fails, cancelling the calls still running. Failed
+	// optional calls are reported in FanOutResult.Errors instead.
+	Required bool
+	// Fallback, when set, is used as the result of a failed optional call. The failure is still reported.
+	Fallback any
+}
+
+// FanOutError describes a failed downstream call with the code it would have in the response envelope.
+type FanOutError struct {
+	Code   int    `json:"code"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// FanOutResult holds the merged results of a fan-out. Returned from a handler, it is written in the standard
+// envelope as {"code":200,"data":{"results":{...},"errors":{...},"partial":true}}.
+type FanOutResult struct {
+	Results map[string]any          `json:"results"`
+	Errors  map[string]*FanOutError `json:"errors,omitempty"`
+	// Partial reports that at least one optional call failed.
+	Partial bool `json:"partial,omitempty"`
+}
+
+// FanOutOption configures FanOut.
+type FanOutOption func(*fanOutOptions)
+
+type fanOutOptions struct {
+	concurrency int
+}
+
+// WithFanOutConcurrency limits how many calls run at once. Default: all calls run in parallel.
+func WithFanOutConcurrency(n int) FanOutOption {
+	return func(o *fanOutOptions) {
+		o.concurrency = n
+	}
+}
+
+// FanOut runs calls in parallel for backend-for-frontend handlers and merges their results. Each call gets
+// its own child span and timeout. The first failing required call cancels the others and its error is
+// returned; failed optional calls are reported in the result, which is then marked partial.
+func FanOut(ctx context.Context, calls []FanOutCall, opts ...FanOutOption) (*FanOutResult, error) {
+	var o fanOutOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	seen := make(map[string]struct{}, len(calls))
+	for _, c := range calls {
+		if c.Name == "" || c.Call == nil {
+			return nil, fmt.Errorf("fan-out call %q: name and call are required", c.Name)
+		}
+		if _, dup := seen[c.Name]; dup {
+			return nil, fmt.Errorf("fan-out call %q: duplicate name", c.Name)
+		}
+		seen[c.Name] = struct{}{}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var sem chan struct{}
+	if o.concurrency > 0 {
+		sem = make(chan struct{}, o.concurrency)
+	}
+
+	replies := make([]any, len(calls))
+	errs := make([]error, len(calls))
+	var (
+		wg          sync.WaitGroup
+		failOnce    sync.Once
+		requiredErr error
+	)
+	for i, c := range calls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if sem != nil {
+				select {
+				case sem <- struct{}{}:
+					defer func() { <-sem }()
+				case <-ctx.Done():
+					errs[i] = ctx.Err()
+					return
+				}
+			}
+			replies[i], errs[i] = runFanOutCall(ctx, c)
+			if errs[i] != nil && c.Required {
+				failOnce.Do(func() {
+					requiredErr = errs[i]
+					cancel()
+				})
+			}
+		}()
+	}
+	wg.Wait()
+	if requiredErr != nil {
+		return nil, requiredErr
+	}
+
+	res := &FanOutResult{Results: make(map[string]any, len(calls))}
+	for i, c := range calls {
+		if errs[i] == nil {
+			res.Results[c.Name] = replies[i]
+			continue
+		}
+		if res.Errors == nil {
+			res.Errors = make(map[string]*FanOutError)
+		}
+		se := errors.FromError(errs[i])
+		res.Errors[c.Name] = &FanOutError{Code: defaultErrorCode(se), Reason: se.Reason}
+		res.Partial = true
+		if c.Fallback != nil {
+			res.Results[c.Name] = c.Fallback
+		}
+	}
+	return res, nil
+}
+
+// runFanOutCall runs one call under its span and timeout, turning timeouts and panics into Kratos errors.
+func runFanOutCall(ctx context.Context, c FanOutCall) (reply any, err error) {
+	ctx, span := otel.Tracer(fanOutTracerName).Start(ctx, "fanout "+c.Name,
+		trace.WithAttributes(attribute.String("fanout.call", c.Name), attribute.Bool("fanout.required", c.Required)))
+	defer span.End()
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			reply, err = nil, errors.InternalServer(fanOutPanicReason, fmt.Sprintf("fan-out call %s panicked: %v", c.Name, r))
+		}
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}()
+	reply, err = c.Call(ctx)
+	if err != nil && stderrors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = errors.GatewayTimeout(FanOutTimeoutReason, fmt.Sprintf("fan-out call %s timed out", c.Name)).WithCause(err)
+	}
+	return reply, err
+}
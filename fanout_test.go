@@ -0,0 +1,137 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// spanNameRecorder is a tracer provider that records the names of started spans.
+type spanNameRecorder struct {
+	embedded.TracerProvider
+	mu    sync.Mutex
+	names []string
+}
+
+func (r *spanNameRecorder) Tracer(string, ...trace.TracerOption) trace.Tracer {
+	return &spanNameTracer{rec: r}
+}
+
+type spanNameTracer struct {
+	embedded.Tracer
+	rec *spanNameRecorder
+}
+
+func (t *spanNameTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	t.rec.mu.Lock()
+	t.rec.names = append(t.rec.names, name)
+	t.rec.mu.Unlock()
+	return noop.NewTracerProvider().Tracer("").Start(ctx, name, opts...)
+}
+
+func fanOutValue(v any) func(context.Context) (any, error) {
+	return func(context.Context) (any, error) { return v, nil }
+}
+
+func TestFanOut_MergesResultsAndPartialFailures(t *testing.T) {
+	rec := &spanNameRecorder{}
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(rec)
+	defer otel.SetTracerProvider(prev)
+
+	res, err := FanOut(context.Background(), []FanOutCall{
+		{Name: "profile", Call: fanOutValue(map[string]string{"name": "alice"}), Required: true},
+		{Name: "orders", Call: fanOutValue([]int{1, 2})},
+		{Name: "recommendations", Call: func(context.Context) (any, error) {
+			return nil, errors.ServiceUnavailable("RECS_DOWN", "")
+		}, Fallback: []string{}},
+		{Name: "slow", Timeout: 10 * time.Millisecond, Call: func(ctx context.Context) (any, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}},
+		{Name: "broken", Call: func(context.Context) (any, error) { panic("nil map") }},
+	})
+	require.NoError(t, err)
+	assert.True(t, res.Partial)
+	assert.Equal(t, map[string]any{
+		"profile":         map[string]string{"name": "alice"},
+		"orders":          []int{1, 2},
+		"recommendations": []string{},
+	}, res.Results)
+	assert.Equal(t, map[string]*FanOutError{
+		"recommendations": {Code: 503, Reason: "RECS_DOWN"},
+		"slow":            {Code: 504, Reason: FanOutTimeoutReason},
+		"broken":          {Code: 500, Reason: fanOutPanicReason},
+	}, res.Errors)
+
+	sort.Strings(rec.names)
+	assert.Equal(t, []string{"fanout broken", "fanout orders", "fanout profile", "fanout recommendations", "fanout slow"},
+		rec.names)
+
+	body, err := json.Marshal(res)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"results":{"profile":{"name":"alice"},"orders":[1,2],"recommendations":[]},
+		"errors":{"recommendations":{"code":503,"reason":"RECS_DOWN"},"slow":{"code":504,"reason":"FANOUT_TIMEOUT"},
+		"broken":{"code":500,"reason":"FANOUT_PANIC"}},"partial":true}`, string(body))
+}
+
+func TestFanOut_RequiredFailureCancelsOthers(t *testing.T) {
+	cancelled := make(chan struct{})
+	_, err := FanOut(context.Background(), []FanOutCall{
+		{Name: "profile", Required: true, Call: func(context.Context) (any, error) {
+			return nil, errors.NotFound("USER_NOT_FOUND", "")
+		}},
+		{Name: "feed", Call: func(ctx context.Context) (any, error) {
+			<-ctx.Done()
+			close(cancelled)
+			return nil, ctx.Err()
+		}},
+	})
+	assert.Equal(t, "USER_NOT_FOUND", errors.Reason(err))
+	select {
+	case <-cancelled:
+	default:
+		t.Fatal("optional call was not cancelled")
+	}
+}
+
+func TestFanOut_Concurrency(t *testing.T) {
+	var running, peak atomic.Int32
+	call := func(context.Context) (any, error) {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+		return nil, nil
+	}
+	calls := []FanOutCall{{Name: "a", Call: call}, {Name: "b", Call: call}, {Name: "c", Call: call}, {Name: "d", Call: call}}
+	res, err := FanOut(context.Background(), calls, WithFanOutConcurrency(2))
+	require.NoError(t, err)
+	assert.Len(t, res.Results, 4)
+	assert.False(t, res.Partial)
+	assert.LessOrEqual(t, peak.Load(), int32(2))
+}
+
+func TestFanOut_InvalidCalls(t *testing.T) {
+	_, err := FanOut(context.Background(), []FanOutCall{{Name: "a", Call: fanOutValue(1)}, {Name: "a", Call: fanOutValue(2)}})
+	assert.Error(t, err)
+	_, err = FanOut(context.Background(), []FanOutCall{{Name: "a"}})
+	assert.Error(t, err)
+}
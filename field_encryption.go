@@ -0,0 +1,344 @@
+package http
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// EncryptedFieldPrefix starts the envelopes that replace encrypted reply fields.
+	EncryptedFieldPrefix = "enc:v1:"
+
+	// EncryptionKeyRequiredReason is the Kratos error reason for requests to operations with encrypted fields
+	// that carry no client key while no pre-shared key is configured.
+	EncryptionKeyRequiredReason = "ENCRYPTION_KEY_REQUIRED"
+	// InvalidEncryptionKeyReason is the Kratos error reason for client keys that are not base64 AES-256 keys.
+	InvalidEncryptionKeyReason = "INVALID_ENCRYPTION_KEY"
+	// FieldEncryptionFailedReason is the Kratos error reason for replies whose configured fields cannot be
+	// encrypted; the reply is not sent.
+	FieldEncryptionFailedReason = "FIELD_ENCRYPTION_FAILED"
+
+	// clientFieldEncryptionKeyID is the key id of envelopes encrypted with a client key.
+	clientFieldEncryptionKeyID = "client"
+)
+
+// fieldEncryptionKey is a resolved FieldEncryptionKey.
+type fieldEncryptionKey struct {
+	id   string
+	aead cipher.AEAD
+}
+
+func newFieldEncryptionKey(id string, raw []byte) (*fieldEncryptionKey, error) {
+	if len(raw) != 32 {
+		return nil, fmt.Errorf("key must be 32 bytes, got %d", len(raw))
+	}
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &fieldEncryptionKey{id: id, aead: aead}, nil
+}
+
+// seal returns the envelope of plain for the field at path.
+func (k *fieldEncryptionKey) seal(plain []byte, path string) (string, error) {
+	nonce := make([]byte, k.aead.NonceSize(), k.aead.NonceSize()+len(plain)+k.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := k.aead.Seal(nonce, nonce, plain, []byte(path))
+	return EncryptedFieldPrefix + k.id + ":" + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptField opens an envelope of the field at path, e.g. "card.pan", with the key its key id names in keys.
+// Keys are raw 32-byte AES-256 keys; envelopes encrypted with a client key use the id "client".
+func DecryptField(envelope, path string, keys map[string][]byte) ([]byte, error) {
+	rest, ok := strings.CutPrefix(envelope, EncryptedFieldPrefix)
+	if !ok {
+		return nil, fmt.Errorf("not an encrypted field")
+	}
+	id, data, ok := strings.Cut(rest, ":")
+	if !ok {
+		return nil, fmt.Errorf("malformed envelope")
+	}
+	raw, ok := keys[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", id)
+	}
+	k, err := newFieldEncryptionKey(id, raw)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(data)
+	if err != nil || len(sealed) < k.aead.NonceSize() {
+		return nil, fmt.Errorf("malformed envelope")
+	}
+	nonce, ciphertext := sealed[:k.aead.NonceSize()], sealed[k.aead.NonceSize():]
+	return k.aead.Open(nil, nonce, ciphertext, []byte(path))
+}
+
+// fieldEncryptionRule is a resolved FieldEncryptionRule.
+type fieldEncryptionRule struct {
+	operation string
+	fields    []string
+}
+
+// fieldEncryptionPolicy is the resolved FieldEncryptionConfig.
+type fieldEncryptionPolicy struct {
+	rules []fieldEncryptionRule
+	// active encrypts when the request carries no client key; nil without pre-shared keys.
+	active       *fieldEncryptionKey
+	clientHeader string
+}
+
+// newFieldEncryptionPolicy returns nil when field encryption is disabled or misconfigured; validation reports
+// the latter.
+func newFieldEncryptionPolicy(cfg *conf.FieldEncryptionConfig) *fieldEncryptionPolicy {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	p := &fieldEncryptionPolicy{clientHeader: strings.TrimSpace(cfg.GetClientKeyHeader())}
+	for _, rule := range cfg.GetRules() {
+		r := fieldEncryptionRule{operation: strings.TrimSpace(rule.GetOperation())}
+		for _, field := range rule.GetFields() {
+			if field = strings.TrimSpace(field); field != "" {
+				r.fields = append(r.fields, field)
+			}
+		}
+		p.rules = append(p.rules, r)
+	}
+	active := strings.TrimSpace(cfg.GetActiveKeyId())
+	for i, key := range cfg.GetKeys() {
+		if active != "" && key.GetId() != active || active == "" && i > 0 {
+			continue
+		}
+		raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(key.GetKey()))
+		if err != nil {
+			return nil
+		}
+		if p.active, err = newFieldEncryptionKey(key.GetId(), raw); err != nil {
+			return nil
+		}
+	}
+	return p
+}
+
+// fieldsFor returns the fields to encrypt in replies of the route with operation or path.
+func (p *fieldEncryptionPolicy) fieldsFor(operation, path string) []string {
+	var fields []string
+	for _, r := range p.rules {
+		if routeMatchesAny([]string{r.operation}, operation, path) {
+			fields = append(fields, r.fields...)
+		}
+	}
+	return fields
+}
+
+// keyFor returns the key encrypting the reply of r: the client key when the request carries one, otherwise
+// the active pre-shared key.
+func (p *fieldEncryptionPolicy) keyFor(r *http.Request) (*fieldEncryptionKey, error) {
+	if p.clientHeader != "" {
+		if v := strings.TrimSpace(r.Header.Get(p.clientHeader)); v != "" {
+			raw, err := base64.StdEncoding.DecodeString(v)
+			if err != nil {
+				return nil, errors.BadRequest(InvalidEncryptionKeyReason, "encryption key is not base64")
+			}
+			k, err := newFieldEncryptionKey(clientFieldEncryptionKeyID, raw)
+			if err != nil {
+				return nil, errors.BadRequest(InvalidEncryptionKeyReason, err.Error())
+			}
+			return k, nil
+		}
+	}
+	if p.active == nil {
+		return nil, errors.BadRequest(EncryptionKeyRequiredReason,
+			fmt.Sprintf("the %s header is required", p.clientHeader))
+	}
+	return p.active, nil
+}
+
+// withFieldEncryption encrypts the configured fields of a copy of the reply before encode runs. The items of
+// list streams are encrypted one by one as they are written.
+func withFieldEncryption(p *fieldEncryptionPolicy, encode http.EncodeResponseFunc) http.EncodeResponseFunc {
+	return func(w http.ResponseWriter, r *http.Request, data any) error {
+		var operation string
+		if tr, ok := transport.FromServerContext(r.Context()); ok {
+			operation = tr.Operation()
+		}
+		fields := p.fieldsFor(operation, r.URL.Path)
+		if len(fields) == 0 {
+			return encode(w, r, data)
+		}
+		key, err := p.keyFor(r)
+		if err != nil {
+			return err
+		}
+		if s, ok := data.(*ListStream); ok {
+			return encode(w, r, s.mapItems(func(item proto.Message) (proto.Message, error) {
+				return encryptFields(operation, item, fields, key)
+			}))
+		}
+		msg, ok := data.(proto.Message)
+		if !ok {
+			log.Errorf("Field encryption of %s: reply %T is not a proto message", operation, data)
+			return errors.InternalServer(FieldEncryptionFailedReason, "response could not be encrypted")
+		}
+		encrypted, err := encryptFields(operation, msg, fields, key)
+		if err != nil {
+			return err
+		}
+		return encode(w, r, encrypted)
+	}
+}
+
+// encryptFields returns a copy of msg with fields encrypted.
+func encryptFields(operation string, msg proto.Message, fields []string, key *fieldEncryptionKey) (proto.Message, error) {
+	encrypted := proto.Clone(msg)
+	for _, field := range fields {
+		if err := encryptField(encrypted.ProtoReflect(), strings.Split(field, "."), field, key); err != nil {
+			log.Errorf("Field encryption of %s: %s: %v", operation, field, err)
+			return nil, errors.InternalServer(FieldEncryptionFailedReason, "response could not be encrypted")
+		}
+	}
+	return encrypted, nil
+}
+
+// encryptField replaces the string or bytes field at path below m with envelopes. Unset fields stay unset.
+func encryptField(m protoreflect.Message, path []string, full string, key *fieldEncryptionKey) error {
+	fields := m.Descriptor().Fields()
+	fd := fields.ByName(protoreflect.Name(path[0]))
+	if fd == nil {
+		fd = fields.ByJSONName(path[0])
+	}
+	if fd == nil {
+		return fmt.Errorf("%s has no field %q", m.Descriptor().FullName(), path[0])
+	}
+	if fd.IsMap() {
+		return fmt.Errorf("map field %q is not supported", path[0])
+	}
+	if !m.Has(fd) {
+		return nil
+	}
+
+	if len(path) > 1 {
+		if fd.Kind() != protoreflect.MessageKind && fd.Kind() != protoreflect.GroupKind {
+			return fmt.Errorf("field %q is not a message", path[0])
+		}
+		if fd.IsList() {
+			list := m.Mutable(fd).List()
+			for i := 0; i < list.Len(); i++ {
+				if err := encryptField(list.Get(i).Message(), path[1:], full, key); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return encryptField(m.Mutable(fd).Message(), path[1:], full, key)
+	}
+
+	seal := func(v protoreflect.Value) (protoreflect.Value, error) {
+		var plain []byte
+		switch fd.Kind() {
+		case protoreflect.StringKind:
+			plain = []byte(v.String())
+		case protoreflect.BytesKind:
+			plain = v.Bytes()
+		default:
+			return protoreflect.Value{}, fmt.Errorf("field %q is %s; only string and bytes fields can be encrypted",
+				path[0], fd.Kind())
+		}
+		envelope, err := key.seal(plain, full)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		if fd.Kind() == protoreflect.BytesKind {
+			return protoreflect.ValueOfBytes([]byte(envelope)), nil
+		}
+		return protoreflect.ValueOfString(envelope), nil
+	}
+	if fd.IsList() {
+		list := m.Mutable(fd).List()
+		for i := 0; i < list.Len(); i++ {
+			v, err := seal(list.Get(i))
+			if err != nil {
+				return err
+			}
+			list.Set(i, v)
+		}
+		return nil
+	}
+	v, err := seal(m.Get(fd))
+	if err != nil {
+		return err
+	}
+	m.Set(fd, v)
+	return nil
+}
+
+// validateFieldEncryptionConfig requires rules with operations and fields, well-formed unique keys, a known
+// active key, and a key source.
+func validateFieldEncryptionConfig(cfg *conf.FieldEncryptionConfig) error {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	if len(cfg.GetRules()) == 0 {
+		return fmt.Errorf("at least one rule is required")
+	}
+	for i, rule := range cfg.GetRules() {
+		if strings.TrimSpace(rule.GetOperation()) == "" {
+			return fmt.Errorf("rules[%d]: operation is required", i)
+		}
+		if len(rule.GetFields()) == 0 {
+			return fmt.Errorf("rules[%d]: at least one field is required", i)
+		}
+		for _, field := range rule.GetFields() {
+			for _, segment := range strings.Split(strings.TrimSpace(field), ".") {
+				if segment == "" {
+					return fmt.Errorf("rules[%d]: invalid field path %q", i, field)
+				}
+			}
+		}
+	}
+	ids := make(map[string]struct{})
+	for i, key := range cfg.GetKeys() {
+		id := key.GetId()
+		if id == "" || strings.ContainsAny(id, ": ") || id == clientFieldEncryptionKeyID {
+			return fmt.Errorf("keys[%d]: invalid id %q", i, id)
+		}
+		if _, dup := ids[id]; dup {
+			return fmt.Errorf("keys[%d]: duplicate id %q", i, id)
+		}
+		ids[id] = struct{}{}
+		raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(key.GetKey()))
+		if err != nil {
+			return fmt.Errorf("key %q is not base64: %w", id, err)
+		}
+		if _, err := newFieldEncryptionKey(id, raw); err != nil {
+			return fmt.Errorf("key %q: %w", id, err)
+		}
+	}
+	if active := strings.TrimSpace(cfg.GetActiveKeyId()); active != "" {
+		if _, ok := ids[active]; !ok {
+			return fmt.Errorf("active_key_id %q is not one of the keys", active)
+		}
+	}
+	if len(cfg.GetKeys()) == 0 && strings.TrimSpace(cfg.GetClientKeyHeader()) == "" {
+		return fmt.Errorf("keys or client_key_header is required")
+	}
+	return nil
+}
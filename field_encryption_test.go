@@ -0,0 +1,151 @@
+package http
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	nhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/apipb"
+	"google.golang.org/protobuf/types/known/sourcecontextpb"
+)
+
+var (
+	testFieldKeyOld = bytes.Repeat([]byte{1}, 32)
+	testFieldKeyNew = bytes.Repeat([]byte{2}, 32)
+)
+
+func fieldEncryptionServer(t *testing.T, cfg *conf.FieldEncryptionConfig, reply *apipb.Api) func(header map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	require.NoError(t, validateFieldEncryptionConfig(cfg))
+	policy := newFieldEncryptionPolicy(cfg)
+	require.NotNil(t, policy)
+	var h ServiceHttp
+	srv := http.NewServer(
+		http.ResponseEncoder(withFieldEncryption(policy, ResponseEncoder)),
+		http.ErrorEncoder(h.enhancedErrorEncoder),
+	)
+	srv.Route("/").GET("/cards", func(ctx http.Context) error {
+		http.SetOperation(ctx, "/api.v1.Cards/Get")
+		return ctx.Result(nhttp.StatusOK, reply)
+	})
+	return func(header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(nhttp.MethodGet, "/cards", nil)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		return rec
+	}
+}
+
+// decodeAPI reads the data of a {"code":200,"data":...} body.
+func decodeAPI(t *testing.T, body []byte) *apipb.Api {
+	t.Helper()
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &envelope))
+	api := &apipb.Api{}
+	require.NoError(t, protojson.Unmarshal(envelope.Data, api))
+	return api
+}
+
+func TestFieldEncryption(t *testing.T) {
+	reply := &apipb.Api{
+		Name:          "4111111111111111",
+		Version:       "v1",
+		Methods:       []*apipb.Method{{Name: "1234"}, {Name: "5678"}},
+		SourceContext: &sourcecontextpb.SourceContext{FileName: "secret.proto"},
+	}
+	do := fieldEncryptionServer(t, &conf.FieldEncryptionConfig{
+		Enabled: true,
+		Rules:   []*conf.FieldEncryptionRule{{Operation: "/api.v1.Cards/*", Fields: []string{"name", "methods.name", "sourceContext.file_name"}}},
+		Keys: []*conf.FieldEncryptionKey{
+			{Id: "k1", Key: base64.StdEncoding.EncodeToString(testFieldKeyOld)},
+			{Id: "k2", Key: base64.StdEncoding.EncodeToString(testFieldKeyNew)},
+		},
+		ActiveKeyId: "k2",
+	}, reply)
+
+	rec := do(nil)
+	require.Equal(t, nhttp.StatusOK, rec.Code, rec.Body.String())
+	api := decodeAPI(t, rec.Body.Bytes())
+	assert.Equal(t, "v1", api.Version, "other fields are left alone")
+	assert.True(t, strings.HasPrefix(api.Name, EncryptedFieldPrefix+"k2:"), api.Name)
+	assert.Equal(t, "4111111111111111", reply.Name, "the handler's reply is not modified")
+
+	keys := map[string][]byte{"k1": testFieldKeyOld, "k2": testFieldKeyNew}
+	plain, err := DecryptField(api.Name, "name", keys)
+	require.NoError(t, err)
+	assert.Equal(t, "4111111111111111", string(plain))
+	plain, err = DecryptField(api.Methods[1].Name, "methods.name", keys)
+	require.NoError(t, err)
+	assert.Equal(t, "5678", string(plain))
+	plain, err = DecryptField(api.SourceContext.FileName, "sourceContext.file_name", keys)
+	require.NoError(t, err)
+	assert.Equal(t, "secret.proto", string(plain))
+
+	_, err = DecryptField(api.Name, "methods.name", keys)
+	assert.Error(t, err, "envelopes are bound to their field")
+	_, err = DecryptField(api.Name, "name", map[string][]byte{"k1": testFieldKeyOld})
+	assert.ErrorContains(t, err, `unknown key id "k2"`)
+}
+
+func TestFieldEncryption_ClientKey(t *testing.T) {
+	do := fieldEncryptionServer(t, &conf.FieldEncryptionConfig{
+		Enabled:         true,
+		Rules:           []*conf.FieldEncryptionRule{{Operation: "/api.v1.Cards/Get", Fields: []string{"name"}}},
+		ClientKeyHeader: "X-Encryption-Key",
+	}, &apipb.Api{Name: "4111111111111111"})
+
+	rec := do(map[string]string{"X-Encryption-Key": base64.StdEncoding.EncodeToString(testFieldKeyNew)})
+	require.Equal(t, nhttp.StatusOK, rec.Code, rec.Body.String())
+	plain, err := DecryptField(decodeAPI(t, rec.Body.Bytes()).Name, "name", map[string][]byte{"client": testFieldKeyNew})
+	require.NoError(t, err)
+	assert.Equal(t, "4111111111111111", string(plain))
+
+	// Client errors are reported in the body.
+	assert.JSONEq(t, `{"code":400}`, do(nil).Body.String())
+	assert.JSONEq(t, `{"code":400}`, do(map[string]string{"X-Encryption-Key": "c2hvcnQ="}).Body.String())
+}
+
+func TestFieldEncryption_FailsClosed(t *testing.T) {
+	for _, field := range []string{"pan", "syntax", "version.x"} {
+		do := fieldEncryptionServer(t, &conf.FieldEncryptionConfig{
+			Enabled: true,
+			Rules:   []*conf.FieldEncryptionRule{{Operation: "/api.v1.Cards/Get", Fields: []string{field}}},
+			Keys:    []*conf.FieldEncryptionKey{{Id: "k1", Key: base64.StdEncoding.EncodeToString(testFieldKeyOld)}},
+		}, &apipb.Api{Name: "4111111111111111", Version: "v1", Syntax: 1})
+		rec := do(nil)
+		assert.Equal(t, nhttp.StatusInternalServerError, rec.Code, field)
+		assert.NotContains(t, rec.Body.String(), "4111111111111111", field)
+	}
+}
+
+func TestValidateFieldEncryptionConfig(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(testFieldKeyOld)
+	rules := []*conf.FieldEncryptionRule{{Operation: "/api.v1.Cards/*", Fields: []string{"card.pan"}}}
+	require.NoError(t, validateFieldEncryptionConfig(nil))
+	for cfg, want := range map[*conf.FieldEncryptionConfig]string{
+		{Enabled: true, Keys: []*conf.FieldEncryptionKey{{Id: "k1", Key: key}}}:                                  "at least one rule",
+		{Enabled: true, Rules: []*conf.FieldEncryptionRule{{Fields: []string{"pan"}}}, ClientKeyHeader: "X-Key"}: "operation is required",
+		{Enabled: true, Rules: []*conf.FieldEncryptionRule{{Operation: "/a", Fields: []string{"card..pan"}}}}:    "invalid field path",
+		{Enabled: true, Rules: rules}: "keys or client_key_header",
+		{Enabled: true, Rules: rules, Keys: []*conf.FieldEncryptionKey{{Id: "a:b", Key: key}}}:                      "invalid id",
+		{Enabled: true, Rules: rules, Keys: []*conf.FieldEncryptionKey{{Id: "k1", Key: key}, {Id: "k1", Key: key}}}: "duplicate id",
+		{Enabled: true, Rules: rules, Keys: []*conf.FieldEncryptionKey{{Id: "k1", Key: "c2hvcnQ="}}}:                "must be 32 bytes",
+		{Enabled: true, Rules: rules, Keys: []*conf.FieldEncryptionKey{{Id: "k1", Key: key}}, ActiveKeyId: "k2"}:    "not one of the keys",
+	} {
+		assert.ErrorContains(t, validateFieldEncryptionConfig(cfg), want)
+	}
+}
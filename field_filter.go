@@ -0,0 +1,264 @@
+package http
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx/log"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	defaultFieldFilterParam = "fields"
+
+	// invalidFieldsReason is the Kratos error reason for field selections that do not match the reply.
+	invalidFieldsReason = "INVALID_FIELDS"
+)
+
+// fieldTree is a parsed field selection. A nil subtree keeps the whole field.
+type fieldTree map[string]fieldTree
+
+// insert adds a dotted path; selecting a field whole wins over selecting some of its subfields.
+func (t fieldTree) insert(path []string, sub fieldTree) {
+	head := path[0]
+	if len(path) == 1 {
+		if sub == nil {
+			t[head] = nil
+			return
+		}
+		existing, seen := t[head]
+		if seen && existing == nil {
+			return
+		}
+		if existing == nil {
+			existing = fieldTree{}
+			t[head] = existing
+		}
+		for k, v := range sub {
+			existing.insert([]string{k}, v)
+		}
+		return
+	}
+	existing, seen := t[head]
+	if seen && existing == nil {
+		return
+	}
+	if existing == nil {
+		existing = fieldTree{}
+		t[head] = existing
+	}
+	existing.insert(path[1:], sub)
+}
+
+// parseFieldSelection parses FieldMask-style paths ("a,b.c") and GraphQL-lite groups ("a,b{c,d}"),
+// which may be mixed.
+func parseFieldSelection(s string) (fieldTree, error) {
+	p := &fieldParser{s: strings.ReplaceAll(s, " ", "")}
+	tree, err := p.list()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.s) {
+		return nil, fmt.Errorf("unexpected %q at offset %d", p.s[p.pos], p.pos)
+	}
+	return tree, nil
+}
+
+type fieldParser struct {
+	s   string
+	pos int
+}
+
+func (p *fieldParser) list() (fieldTree, error) {
+	tree := fieldTree{}
+	for {
+		path, err := p.path()
+		if err != nil {
+			return nil, err
+		}
+		var sub fieldTree
+		if p.peek() == '{' {
+			p.pos++
+			if sub, err = p.list(); err != nil {
+				return nil, err
+			}
+			if p.peek() != '}' {
+				return nil, fmt.Errorf("missing '}' at offset %d", p.pos)
+			}
+			p.pos++
+		}
+		tree.insert(path, sub)
+		if p.peek() != ',' {
+			return tree, nil
+		}
+		p.pos++
+	}
+}
+
+func (p *fieldParser) path() ([]string, error) {
+	var path []string
+	for {
+		start := p.pos
+		for p.pos < len(p.s) && isFieldNameByte(p.s[p.pos]) {
+			p.pos++
+		}
+		if p.pos == start {
+			return nil, fmt.Errorf("expected field name at offset %d", p.pos)
+		}
+		path = append(path, p.s[start:p.pos])
+		if p.peek() != '.' {
+			return path, nil
+		}
+		p.pos++
+	}
+}
+
+func (p *fieldParser) peek() byte {
+	if p.pos < len(p.s) {
+		return p.s[p.pos]
+	}
+	return 0
+}
+
+func isFieldNameByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// PruneFields returns a copy of msg keeping only the selected fields. Field names may use the proto or
+// JSON spelling; selecting into repeated and map fields applies to every element. msg is not modified.
+func PruneFields(msg proto.Message, selection string) (proto.Message, error) {
+	tree, err := parseFieldSelection(selection)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateFieldTree(msg.ProtoReflect().Descriptor(), tree, ""); err != nil {
+		return nil, err
+	}
+	out := proto.Clone(msg)
+	pruneMessage(out.ProtoReflect(), tree)
+	return out, nil
+}
+
+func lookupField(md protoreflect.MessageDescriptor, name string) protoreflect.FieldDescriptor {
+	if fd := md.Fields().ByName(protoreflect.Name(name)); fd != nil {
+		return fd
+	}
+	return md.Fields().ByJSONName(name)
+}
+
+// fieldMessage returns the message type selections descend into: the field's own message, the element
+// message of a repeated field, or the value message of a map.
+func fieldMessage(fd protoreflect.FieldDescriptor) protoreflect.MessageDescriptor {
+	if fd.IsMap() {
+		return fd.MapValue().Message()
+	}
+	return fd.Message()
+}
+
+func validateFieldTree(md protoreflect.MessageDescriptor, tree fieldTree, prefix string) error {
+	for name, sub := range tree {
+		fd := lookupField(md, name)
+		if fd == nil {
+			return fmt.Errorf("unknown field %q in %s", prefix+name, md.FullName())
+		}
+		if sub == nil {
+			continue
+		}
+		sm := fieldMessage(fd)
+		if sm == nil {
+			return fmt.Errorf("field %q is not a message", prefix+name)
+		}
+		if err := validateFieldTree(sm, sub, prefix+name+"."); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func selectedSubtree(tree fieldTree, fd protoreflect.FieldDescriptor) (fieldTree, bool) {
+	if sub, ok := tree[string(fd.Name())]; ok {
+		return sub, true
+	}
+	sub, ok := tree[fd.JSONName()]
+	return sub, ok
+}
+
+func pruneMessage(m protoreflect.Message, tree fieldTree) {
+	var drop []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		sub, ok := selectedSubtree(tree, fd)
+		switch {
+		case !ok:
+			drop = append(drop, fd)
+		case sub == nil:
+		case fd.IsMap():
+			v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+				pruneMessage(mv.Message(), sub)
+				return true
+			})
+		case fd.IsList():
+			list := v.List()
+			for i := range list.Len() {
+				pruneMessage(list.Get(i).Message(), sub)
+			}
+		default:
+			pruneMessage(v.Message(), sub)
+		}
+		return true
+	})
+	for _, fd := range drop {
+		m.Clear(fd)
+	}
+}
+
+// responseEncoder returns ResponseEncoder, with multi-status replies, accepted tasks, and JSON:API output, protobuf replies and
+// field filtering layered on when they are enabled.
+func (h *ServiceHttp) responseEncoder() http.EncodeResponseFunc {
+	cfg := h.conf.GetResponse()
+	code := successCode(cfg.GetCodes())
+	envelope := successEncoder(code)
+	protoJSON, err := newProtoJSONEncoder(cfg.GetProtoJson())
+	if err != nil {
+		// Checked by validateProtoJSONConfig; keep the plain envelope if the files changed since.
+		log.Errorf("response.proto_json: %v", err)
+	} else if protoJSON != nil {
+		envelope = withProtoJSON(protoJSON, envelope)
+	}
+	encode := envelope
+	if cfg.GetEnableJsonapi() {
+		encode = func(w http.ResponseWriter, r *http.Request, data any) error {
+			if acceptsJSONAPI(r) {
+				return encodeJSONAPI(w, r, data)
+			}
+			return envelope(w, r, data)
+		}
+	}
+	if policy := newProtobufPolicy(cfg.GetProtobuf(), code); policy != nil {
+		encode = withProtobuf(policy, encode)
+	}
+	// Multi-status replies and accepted tasks always use the JSON envelope.
+	encode = h.withMultiStatus(cfg.GetMultiStatus(), code, protoJSON, encode)
+	encode = withAcceptedTasks(encode)
+	if !cfg.GetEnableFieldFiltering() {
+		return encode
+	}
+	param := strings.TrimSpace(cfg.GetFieldFilterParam())
+	if param == "" {
+		param = defaultFieldFilterParam
+	}
+	return func(w http.ResponseWriter, r *http.Request, data any) error {
+		if sel := r.URL.Query().Get(param); sel != "" {
+			if msg, ok := data.(proto.Message); ok {
+				pruned, err := PruneFields(msg, sel)
+				if err != nil {
+					return errors.BadRequest(invalidFieldsReason, err.Error())
+				}
+				data = pruned
+			}
+		}
+		return encode(w, r, data)
+	}
+}
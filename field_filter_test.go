@@ -0,0 +1,105 @@
+package http
+
+import (
+	"encoding/json"
+	nhttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func fieldFilterSample() *conf.Http {
+	return &conf.Http{
+		Network: "tcp",
+		Addr:    ":8080",
+		Timeout: durationpb.New(time.Second),
+		Monitoring: &conf.MonitoringConfig{
+			EnableMetrics: true,
+			MetricsPath:   "/metrics",
+			Slo: &conf.SLOConfig{
+				Enabled: true,
+				Objectives: []*conf.SLOObjective{
+					{Operation: "/a", AvailabilityTarget: 0.99, LatencyTarget: 0.9},
+					{Operation: "/b", AvailabilityTarget: 0.999},
+				},
+			},
+		},
+	}
+}
+
+func TestParseFieldSelection(t *testing.T) {
+	tree, err := parseFieldSelection("addr, monitoring.metrics_path,monitoring{slo{enabled}},monitoring.slo")
+	require.NoError(t, err)
+	assert.Equal(t, fieldTree{
+		"addr":       nil,
+		"monitoring": fieldTree{"metrics_path": nil, "slo": nil},
+	}, tree)
+
+	for _, bad := range []string{"", "a,", "a{b", "a.}", "a}b", "a-b"} {
+		_, err := parseFieldSelection(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestPruneFields(t *testing.T) {
+	in := fieldFilterSample()
+	out, err := PruneFields(in, "addr,monitoring{metricsPath,slo.objectives{operation}}")
+	require.NoError(t, err)
+
+	got := out.(*conf.Http)
+	assert.Equal(t, ":8080", got.Addr)
+	assert.Empty(t, got.Network)
+	assert.Nil(t, got.Timeout)
+	assert.Equal(t, "/metrics", got.Monitoring.MetricsPath)
+	assert.False(t, got.Monitoring.EnableMetrics)
+	assert.False(t, got.Monitoring.Slo.Enabled)
+	require.Len(t, got.Monitoring.Slo.Objectives, 2)
+	assert.Equal(t, "/a", got.Monitoring.Slo.Objectives[0].Operation)
+	assert.Zero(t, got.Monitoring.Slo.Objectives[0].AvailabilityTarget)
+
+	// The input is left untouched.
+	assert.Equal(t, "tcp", in.Network)
+	assert.Equal(t, 0.99, in.Monitoring.Slo.Objectives[0].AvailabilityTarget)
+}
+
+func TestPruneFields_InvalidSelection(t *testing.T) {
+	_, err := PruneFields(fieldFilterSample(), "addr,nope")
+	assert.ErrorContains(t, err, `unknown field "nope"`)
+
+	_, err = PruneFields(fieldFilterSample(), "monitoring.bogus")
+	assert.ErrorContains(t, err, `unknown field "monitoring.bogus"`)
+
+	_, err = PruneFields(fieldFilterSample(), "addr.port")
+	assert.ErrorContains(t, err, "not a message")
+}
+
+func TestResponseEncoder_FieldFiltering(t *testing.T) {
+	h := &ServiceHttp{conf: &conf.Http{Response: &conf.ResponseConfig{EnableFieldFiltering: true, FieldFilterParam: "only"}}}
+	enc := h.responseEncoder()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(nhttp.MethodGet, "/config?only=addr", nil)
+	require.NoError(t, enc(w, r, fieldFilterSample()))
+	var body struct {
+		Code int            `json:"code"`
+		Data map[string]any `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 200, body.Code)
+	assert.Equal(t, map[string]any{"addr": ":8080"}, body.Data)
+
+	err := enc(httptest.NewRecorder(), httptest.NewRequest(nhttp.MethodGet, "/config?only=missing", nil), fieldFilterSample())
+	assert.Equal(t, invalidFieldsReason, errors.FromError(err).Reason)
+	assert.Equal(t, int32(400), errors.FromError(err).Code)
+
+	// Without the parameter the reply is encoded in full.
+	w = httptest.NewRecorder()
+	require.NoError(t, enc(w, httptest.NewRequest(nhttp.MethodGet, "/config", nil), fieldFilterSample()))
+	assert.Contains(t, w.Body.String(), `"network"`)
+}
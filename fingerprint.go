@@ -0,0 +1,390 @@
+package http
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"net"
+	nhttp "net/http"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-lynx/lynx-http/conf"
+	"golang.org/x/time/rate"
+)
+
+const (
+	fingerprintComponentIP          = "ip"
+	fingerprintComponentUserAgent   = "user_agent"
+	fingerprintComponentHeaderShape = "header_shape"
+	fingerprintComponentJA3         = "ja3"
+
+	defaultFingerprintRatePerSecond = 10
+	defaultFingerprintBurst         = 20
+	defaultFingerprintMaxTracked    = 10000
+
+	// fingerprintRateLimitedReason is the Kratos error reason for requests over the per-fingerprint limit.
+	fingerprintRateLimitedReason = "FINGERPRINT_RATE_LIMITED"
+)
+
+var (
+	fingerprintComponents = []string{
+		fingerprintComponentIP, fingerprintComponentUserAgent, fingerprintComponentHeaderShape, fingerprintComponentJA3,
+	}
+	// fingerprintRequestHeaders vary between the requests of one client, so they are left out of header_shape.
+	fingerprintRequestHeaders = []string{
+		"Authorization", "Cache-Control", "Content-Encoding", "Content-Length", "Content-Type", "Cookie", "Expect",
+		"If-Match", "If-Modified-Since", "If-None-Match", "If-Range", "If-Unmodified-Since", "Origin", "Pragma",
+		"Range", "Referer", "Traceparent", "Tracestate", RequestIDHeader,
+	}
+)
+
+// FingerprintFromContext returns the fingerprint of the current request's client; ok is false when
+// security.fingerprint is disabled.
+func FingerprintFromContext(ctx context.Context) (string, bool) {
+	return FingerprintKey.Value(ctx)
+}
+
+// fingerprinter computes request fingerprints from the configured components.
+type fingerprinter struct {
+	components []string
+	ignore     map[string]struct{}
+	// hellos maps the remote address of TLS connections to the JA3 hash of their ClientHello.
+	hellos  *sync.Map
+	proxies trustedProxies
+}
+
+// newFingerprinter returns nil when fingerprinting is disabled.
+func newFingerprinter(cfg *conf.FingerprintConfig, hellos *sync.Map) *fingerprinter {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	f := &fingerprinter{
+		components: fingerprintComponents,
+		ignore:     make(map[string]struct{}),
+		hellos:     hellos,
+		proxies:    parseTrustedProxies(cfg.GetTrustedProxies()),
+	}
+	if len(cfg.GetComponents()) > 0 {
+		f.components = nil
+		for _, c := range cfg.GetComponents() {
+			f.components = append(f.components, strings.ToLower(strings.TrimSpace(c)))
+		}
+	}
+	for _, name := range append(slices.Clone(fingerprintRequestHeaders), cfg.GetIgnoreHeaders()...) {
+		f.ignore[nhttp.CanonicalHeaderKey(strings.TrimSpace(name))] = struct{}{}
+	}
+	return f
+}
+
+// fingerprint hashes the components of r into 32 hex characters. Missing components hash as empty values.
+func (f *fingerprinter) fingerprint(r *nhttp.Request) string {
+	h := sha256.New()
+	for _, c := range f.components {
+		var v string
+		switch c {
+		case fingerprintComponentIP:
+			v = requestClientIP(r, f.proxies)
+		case fingerprintComponentUserAgent:
+			v = r.UserAgent()
+		case fingerprintComponentHeaderShape:
+			v = f.headerShape(r.Header)
+		case fingerprintComponentJA3:
+			if r.TLS != nil && f.hellos != nil {
+				if ja3, ok := f.hellos.Load(r.RemoteAddr); ok {
+					v = ja3.(string)
+				}
+			}
+		}
+		h.Write([]byte(c))
+		h.Write([]byte{'='})
+		h.Write([]byte(v))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// headerShape is the sorted, lower-cased names of the headers that are not request specific.
+func (f *fingerprinter) headerShape(header nhttp.Header) string {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		if _, ok := f.ignore[nhttp.CanonicalHeaderKey(name)]; !ok {
+			names = append(names, strings.ToLower(name))
+		}
+	}
+	slices.Sort(names)
+	return strings.Join(names, ",")
+}
+
+// filter stores the fingerprint in the request context for the middleware chain and the handlers.
+func (f *fingerprinter) filter(next nhttp.Handler) nhttp.Handler {
+	return nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		next.ServeHTTP(w, r.WithContext(FingerprintKey.WithValue(r.Context(), f.fingerprint(r))))
+	})
+}
+
+// trustedProxies are the reverse proxy networks whose forwarding headers are trusted.
+type trustedProxies []netip.Prefix
+
+// parseTrustedProxies parses validated CIDRs.
+func parseTrustedProxies(cidrs []string) trustedProxies {
+	var proxies trustedProxies
+	for _, cidr := range cidrs {
+		if prefix, err := netip.ParsePrefix(strings.TrimSpace(cidr)); err == nil {
+			proxies = append(proxies, prefix)
+		}
+	}
+	return proxies
+}
+
+// contains reports whether ip is in a trusted network.
+func (t trustedProxies) contains(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range t {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestClientIP is the client IP of r before routing: the value set under ClientIPKey, the PROXY protocol
+// address or the peer address. Forwarding headers, which the client controls, are only read when the peer is a
+// trusted proxy; the client is then the last X-Forwarded-For address not added by a trusted proxy.
+func requestClientIP(r *nhttp.Request, proxies trustedProxies) string {
+	if ip, ok := ClientIPKey.Value(r.Context()); ok {
+		return ip
+	}
+	if ip := proxyClientAddr(r.Context()); ip != "" {
+		return ip
+	}
+	peer := remoteHost(r.RemoteAddr)
+	if !proxies.contains(peer) {
+		return peer
+	}
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	first := ""
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		ip := strings.TrimSpace(forwarded[i])
+		if ip == "" {
+			continue
+		}
+		if !proxies.contains(ip) {
+			return ip
+		}
+		first = ip
+	}
+	if first != "" {
+		return first
+	}
+	if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); ip != "" {
+		return ip
+	}
+	return peer
+}
+
+// fingerprintLogFields are the request log fields of the fingerprint, nil without one.
+func fingerprintLogFields(ctx context.Context) []any {
+	if fp, ok := FingerprintFromContext(ctx); ok {
+		return []any{"fingerprint", fp}
+	}
+	return nil
+}
+
+// fingerprintUsesJA3 reports whether ClientHellos have to be captured for cfg.
+func fingerprintUsesJA3(cfg *conf.FingerprintConfig) bool {
+	if !cfg.GetEnabled() {
+		return false
+	}
+	if len(cfg.GetComponents()) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(cfg.GetComponents(), func(c string) bool {
+		return strings.ToLower(strings.TrimSpace(c)) == fingerprintComponentJA3
+	})
+}
+
+// captureClientHello is the GetConfigForClient callback recording the JA3 hash of each connection. It keeps
+// the server configuration unchanged.
+func (h *ServiceHttp) captureClientHello(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+	if hello.Conn != nil {
+		h.clientHellos.Store(hello.Conn.RemoteAddr().String(), ja3Hash(hello))
+	}
+	return nil, nil
+}
+
+// forgetClientHello drops the JA3 hash of a closed connection.
+func (h *ServiceHttp) forgetClientHello(conn net.Conn, state nhttp.ConnState) {
+	if state == nhttp.StateClosed || state == nhttp.StateHijacked {
+		h.clientHellos.Delete(conn.RemoteAddr().String())
+	}
+}
+
+// ja3Hash is the MD5 of the JA3 string "version,ciphers,extensions,curves,point formats" without GREASE values.
+// The ClientHello's legacy version is not exposed, so it is derived from the supported versions: TLS 1.3
+// clients announce TLS 1.2 there.
+func ja3Hash(hello *tls.ClientHelloInfo) string {
+	version := uint16(tls.VersionTLS12)
+	if len(hello.SupportedVersions) > 0 {
+		version = min(slices.Max(hello.SupportedVersions), tls.VersionTLS12)
+	}
+	curves := make([]uint16, len(hello.SupportedCurves))
+	for i, c := range hello.SupportedCurves {
+		curves[i] = uint16(c)
+	}
+	points := make([]uint16, len(hello.SupportedPoints))
+	for i, p := range hello.SupportedPoints {
+		points[i] = uint16(p)
+	}
+	s := strconv.Itoa(int(version)) + "," + ja3List(hello.CipherSuites) + "," + ja3List(hello.Extensions) + "," +
+		ja3List(curves) + "," + ja3List(points)
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// ja3List joins values with "-", skipping GREASE values (0x0a0a, 0x1a1a, ... 0xfafa).
+func ja3List(values []uint16) string {
+	parts := make([]string, 0, len(values))
+	for _, v := range values {
+		if v&0x0f0f == 0x0a0a && v>>8 == v&0xff {
+			continue
+		}
+		parts = append(parts, strconv.Itoa(int(v)))
+	}
+	return strings.Join(parts, "-")
+}
+
+type fingerprintBucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// fingerprintLimiter keeps a token bucket per fingerprint. It is built per middleware chain.
+type fingerprintLimiter struct {
+	rate       rate.Limit
+	burst      int
+	maxTracked int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*fingerprintBucket
+}
+
+// newFingerprintLimiter returns nil when fingerprinting or its rate limit is disabled.
+func newFingerprintLimiter(cfg *conf.FingerprintConfig) *fingerprintLimiter {
+	rl := cfg.GetRateLimit()
+	if !cfg.GetEnabled() || !rl.GetEnabled() {
+		return nil
+	}
+	l := &fingerprintLimiter{
+		rate:       defaultFingerprintRatePerSecond,
+		burst:      defaultFingerprintBurst,
+		maxTracked: defaultFingerprintMaxTracked,
+		now:        time.Now,
+		buckets:    make(map[string]*fingerprintBucket),
+	}
+	if rl.RatePerSecond > 0 {
+		l.rate = rate.Limit(rl.RatePerSecond)
+	}
+	if rl.Burst > 0 {
+		l.burst = int(rl.Burst)
+	}
+	if rl.MaxTracked > 0 {
+		l.maxTracked = int(rl.MaxTracked)
+	}
+	return l
+}
+
+func (l *fingerprintLimiter) allow(fp string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	b := l.buckets[fp]
+	if b == nil {
+		if len(l.buckets) >= l.maxTracked {
+			l.evictLocked(now)
+		}
+		b = &fingerprintBucket{limiter: rate.NewLimiter(l.rate, l.burst)}
+		l.buckets[fp] = b
+	}
+	b.lastSeen = now
+	return b.limiter.AllowN(now, 1)
+}
+
+// evictLocked drops the buckets that have refilled, which behave like new ones, and then the least recently
+// seen bucket if the table is still full.
+func (l *fingerprintLimiter) evictLocked(now time.Time) {
+	refill := time.Duration(float64(l.burst) / float64(l.rate) * float64(time.Second))
+	var (
+		oldest     string
+		oldestSeen time.Time
+	)
+	for fp, b := range l.buckets {
+		if now.Sub(b.lastSeen) >= refill {
+			delete(l.buckets, fp)
+			continue
+		}
+		if oldest == "" || b.lastSeen.Before(oldestSeen) {
+			oldest, oldestSeen = fp, b.lastSeen
+		}
+	}
+	if len(l.buckets) >= l.maxTracked {
+		delete(l.buckets, oldest)
+	}
+}
+
+// fingerprintRateLimitMiddleware rejects requests of fingerprints over their rate with 429
+// FINGERPRINT_RATE_LIMITED.
+func (h *ServiceHttp) fingerprintRateLimitMiddleware(l *fingerprintLimiter) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			fp, ok := FingerprintFromContext(ctx)
+			if !ok || l.allow(fp) {
+				return handler(ctx, req)
+			}
+			method, path := requestMetadata(ctx)
+			h.recordErrorMetric(method, path, "fingerprint_rate_limited")
+			return nil, errors.New(429, fingerprintRateLimitedReason, "too many requests from this client")
+		}
+	}
+}
+
+// validateFingerprintConfig rejects unknown components, empty header names, malformed trusted proxies and
+// negative limits.
+func validateFingerprintConfig(cfg *conf.FingerprintConfig) error {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	for i, c := range cfg.GetComponents() {
+		if !slices.Contains(fingerprintComponents, strings.ToLower(strings.TrimSpace(c))) {
+			return fmt.Errorf("components[%d]: unknown component %q", i, c)
+		}
+	}
+	for i, name := range cfg.GetIgnoreHeaders() {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("ignore_headers[%d] is empty", i)
+		}
+	}
+	if err := validateCIDRs(cfg.GetTrustedProxies()); err != nil {
+		return err
+	}
+	if rl := cfg.GetRateLimit(); rl.GetEnabled() {
+		if rl.RatePerSecond < 0 || rl.Burst < 0 || rl.MaxTracked < 0 {
+			return fmt.Errorf("rate limit values cannot be negative")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package http
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/tls"
+	"encoding/hex"
+	"io"
+	nhttp "net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprinter(t *testing.T) {
+	f := newFingerprinter(&conf.FingerprintConfig{Enabled: true, IgnoreHeaders: []string{"x-debug"}}, &sync.Map{})
+	request := func(ip, ua string, header map[string]string) *nhttp.Request {
+		r := httptest.NewRequest(nhttp.MethodGet, "/users", nil)
+		r.RemoteAddr = ip + ":51234"
+		r.Header.Set("User-Agent", ua)
+		r.Header.Set("Accept", "application/json")
+		for k, v := range header {
+			r.Header.Set(k, v)
+		}
+		return r
+	}
+	base := f.fingerprint(request("203.0.113.7", "curl/8.5", nil))
+	assert.Len(t, base, 32)
+
+	// Request-specific headers and ignored headers leave the fingerprint unchanged.
+	assert.Equal(t, base, f.fingerprint(request("203.0.113.7", "curl/8.5", map[string]string{
+		"Authorization": "Bearer x", "Content-Type": "application/json", "X-Request-Id": "1", "X-Debug": "1",
+	})))
+	assert.NotEqual(t, base, f.fingerprint(request("203.0.113.8", "curl/8.5", nil)))
+	assert.NotEqual(t, base, f.fingerprint(request("203.0.113.7", "python-requests/2.31", nil)))
+	assert.NotEqual(t, base, f.fingerprint(request("203.0.113.7", "curl/8.5", map[string]string{"Accept-Language": "en"})))
+
+	// Only the configured components count.
+	uaOnly := newFingerprinter(&conf.FingerprintConfig{Enabled: true, Components: []string{"user_agent"}}, nil)
+	assert.Equal(t, uaOnly.fingerprint(request("203.0.113.7", "curl/8.5", nil)),
+		uaOnly.fingerprint(request("198.51.100.1", "curl/8.5", map[string]string{"X-Client": "1"})))
+
+	assert.Nil(t, newFingerprinter(&conf.FingerprintConfig{}, nil))
+	assert.False(t, fingerprintUsesJA3(&conf.FingerprintConfig{Enabled: true, Components: []string{"ip"}}))
+	assert.True(t, fingerprintUsesJA3(&conf.FingerprintConfig{Enabled: true}))
+}
+
+func TestRequestClientIP(t *testing.T) {
+	proxies := parseTrustedProxies([]string{"10.0.0.0/8", " 2001:db8::/32 "})
+	request := func(peer string, header map[string]string) *nhttp.Request {
+		r := httptest.NewRequest(nhttp.MethodGet, "/", nil)
+		r.RemoteAddr = peer + ":51234"
+		for k, v := range header {
+			r.Header.Set(k, v)
+		}
+		return r
+	}
+
+	// Forwarding headers of untrusted peers are ignored.
+	spoofed := map[string]string{"X-Forwarded-For": "198.51.100.9", "X-Real-IP": "198.51.100.9"}
+	assert.Equal(t, "203.0.113.7", requestClientIP(request("203.0.113.7", spoofed), proxies))
+	assert.Equal(t, "10.0.0.1", requestClientIP(request("10.0.0.1", spoofed), nil))
+
+	// Behind trusted proxies the client is the last address they did not add.
+	assert.Equal(t, "198.51.100.9", requestClientIP(request("10.0.0.1", spoofed), proxies))
+	assert.Equal(t, "203.0.113.7", requestClientIP(request("10.0.0.1", map[string]string{
+		"X-Forwarded-For": "198.51.100.9, 203.0.113.7, 10.1.2.3",
+	}), proxies))
+	assert.Equal(t, "10.1.2.3", requestClientIP(request("10.0.0.1", map[string]string{"X-Forwarded-For": "10.1.2.3"}), proxies))
+	assert.Equal(t, "198.51.100.9", requestClientIP(request("10.0.0.1", map[string]string{"X-Real-IP": "198.51.100.9"}), proxies))
+	assert.Equal(t, "10.0.0.1", requestClientIP(request("10.0.0.1", nil), proxies))
+
+	// ClientIPKey takes precedence.
+	r := request("10.0.0.1", spoofed)
+	r = r.WithContext(ClientIPKey.WithValue(r.Context(), "192.0.2.1"))
+	assert.Equal(t, "192.0.2.1", requestClientIP(r, proxies))
+}
+
+func TestJA3Hash(t *testing.T) {
+	hello := &tls.ClientHelloInfo{
+		CipherSuites:      []uint16{0x0a0a, tls.TLS_AES_128_GCM_SHA256, tls.TLS_AES_256_GCM_SHA384},
+		Extensions:        []uint16{0x1a1a, 0, 23, 65281},
+		SupportedCurves:   []tls.CurveID{tls.X25519, tls.CurveP256},
+		SupportedPoints:   []uint8{0},
+		SupportedVersions: []uint16{0x2a2a, tls.VersionTLS13, tls.VersionTLS12},
+	}
+	sum := md5.Sum([]byte("771,4865-4866,0-23-65281,29-23,0"))
+	assert.Equal(t, hex.EncodeToString(sum[:]), ja3Hash(hello))
+}
+
+func TestFingerprintFilter_TLS(t *testing.T) {
+	h := &ServiceHttp{}
+	f := newFingerprinter(&conf.FingerprintConfig{Enabled: true, Components: []string{"ja3"}}, &h.clientHellos)
+	noJA3 := f.fingerprint(httptest.NewRequest(nhttp.MethodGet, "/", nil))
+
+	var seen string
+	srv := httptest.NewUnstartedServer(f.filter(nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		seen, _ = FingerprintFromContext(r.Context())
+		ja3, _ := h.clientHellos.Load(r.RemoteAddr)
+		_, _ = io.WriteString(w, ja3.(string))
+	})))
+	srv.TLS = &tls.Config{GetConfigForClient: h.captureClientHello}
+	srv.Config.ConnState = h.forgetClientHello
+	srv.StartTLS()
+	defer srv.Close()
+
+	client := srv.Client()
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	assert.Len(t, string(body), 32)
+	assert.Len(t, seen, 32)
+	assert.NotEqual(t, noJA3, seen)
+
+	// Closed connections are forgotten.
+	client.CloseIdleConnections()
+	assert.Eventually(t, func() bool {
+		n := 0
+		h.clientHellos.Range(func(any, any) bool { n++; return true })
+		return n == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestFingerprintRateLimit(t *testing.T) {
+	l := newFingerprintLimiter(&conf.FingerprintConfig{Enabled: true,
+		RateLimit: &conf.FingerprintRateLimitConfig{Enabled: true, RatePerSecond: 1, Burst: 2, MaxTracked: 2}})
+	require.NotNil(t, l)
+	now := time.Unix(1_700_000_000, 0)
+	l.now = func() time.Time { return now }
+
+	h := &ServiceHttp{conf: &conf.Http{}}
+	mw := h.fingerprintRateLimitMiddleware(l)
+	call := func(fp string) error {
+		ctx := context.Background()
+		if fp != "" {
+			ctx = FingerprintKey.WithValue(ctx, fp)
+		}
+		_, err := mw(func(context.Context, any) (any, error) { return "ok", nil })(ctx, nil)
+		return err
+	}
+	require.NoError(t, call("a"))
+	require.NoError(t, call("a"))
+	err := call("a")
+	assert.Equal(t, 429, kerrors.Code(err))
+	assert.Equal(t, fingerprintRateLimitedReason, kerrors.Reason(err))
+	now = now.Add(100 * time.Millisecond)
+	require.NoError(t, call("b"))
+	require.NoError(t, call(""))
+
+	// A full table forgets the least recently seen fingerprint.
+	now = now.Add(time.Second)
+	require.NoError(t, call("c"))
+	assert.Len(t, l.buckets, 2)
+	assert.NotContains(t, l.buckets, "a")
+
+	assert.Nil(t, newFingerprintLimiter(&conf.FingerprintConfig{Enabled: true}))
+}
+
+func TestValidateFingerprintConfig(t *testing.T) {
+	require.NoError(t, validateFingerprintConfig(nil))
+	require.NoError(t, validateFingerprintConfig(&conf.FingerprintConfig{Enabled: true, Components: []string{"IP", "ja3"}}))
+	assert.Error(t, validateFingerprintConfig(&conf.FingerprintConfig{Enabled: true, Components: []string{"cookie"}}))
+	assert.Error(t, validateFingerprintConfig(&conf.FingerprintConfig{Enabled: true, IgnoreHeaders: []string{" "}}))
+	assert.Error(t, validateFingerprintConfig(&conf.FingerprintConfig{Enabled: true, TrustedProxies: []string{"10.0.0.0"}}))
+	assert.Error(t, validateFingerprintConfig(&conf.FingerprintConfig{Enabled: true,
+		RateLimit: &conf.FingerprintRateLimitConfig{Enabled: true, Burst: -1}}))
+}
+
+func TestFingerprint_TestService(t *testing.T) {
+	svc, err := NewTestService(&conf.Http{Security: &conf.SecurityConfig{Fingerprint: &conf.FingerprintConfig{Enabled: true}}})
+	require.NoError(t, err)
+	defer func() { _ = svc.Close() }()
+	svc.Server().Route("/").GET("/whoami", func(ctx http.Context) error {
+		fp, _ := FingerprintFromContext(ctx)
+		return ctx.Result(nhttp.StatusOK, map[string]string{"fingerprint": fp})
+	})
+
+	res, err := svc.Get("/whoami")
+	require.NoError(t, err)
+	var out struct {
+		Fingerprint string `json:"fingerprint"`
+	}
+	require.NoError(t, res.Decode(&out))
+	assert.Len(t, out.Fingerprint, 32)
+
+	// The other server filters still run: HEAD is answered from the GET route.
+	req, err := nhttp.NewRequest(nhttp.MethodHead, svc.URL()+"/whoami", nil)
+	require.NoError(t, err)
+	head, err := svc.Do(req)
+	require.NoError(t, err)
+	assert.Equal(t, nhttp.StatusOK, head.StatusCode)
+}
@@ -0,0 +1,336 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	nhttp "net/http"
+	"strings"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+const (
+	defaultGraphQLPath         = "/graphql"
+	defaultGraphQLMaxBodyBytes = 1 << 20
+
+	// invalidGraphQLRequestReason is the Kratos error reason for requests that carry no usable operation.
+	invalidGraphQLRequestReason = "INVALID_GRAPHQL_REQUEST"
+	// graphQLMutationOnGetReason is the Kratos error reason for mutations sent over GET.
+	graphQLMutationOnGetReason = "GRAPHQL_MUTATION_NOT_ALLOWED"
+
+	graphQLOpQuery        = "query"
+	graphQLOpMutation     = "mutation"
+	graphQLOpSubscription = "subscription"
+	graphQLOpUnknown      = "unknown"
+)
+
+// GraphQLRequest is a GraphQL-over-HTTP request.
+type GraphQLRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+	Extensions    map[string]any `json:"extensions,omitempty"`
+}
+
+// GraphQLError is one entry of the errors list of a GraphQL response.
+type GraphQLError struct {
+	Message    string         `json:"message"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+	// Err is the resolver error, if any. Kratos errors get their body code and reason added to the
+	// extensions, using the same ErrorCodeMapper as REST responses.
+	Err error `json:"-"`
+}
+
+// GraphQLResponse is a GraphQL execution result.
+type GraphQLResponse struct {
+	Data       json.RawMessage `json:"data,omitempty"`
+	Errors     []GraphQLError  `json:"errors,omitempty"`
+	Extensions map[string]any  `json:"extensions,omitempty"`
+}
+
+// GraphQLExecutor runs GraphQL operations against the application's schema. Adapt the executor of any
+// GraphQL library to it; the plugin handles transport, middleware and observability.
+type GraphQLExecutor interface {
+	ExecuteGraphQL(ctx context.Context, req *GraphQLRequest) *GraphQLResponse
+}
+
+// GraphQLExecutorFunc adapts a function to GraphQLExecutor.
+type GraphQLExecutorFunc func(ctx context.Context, req *GraphQLRequest) *GraphQLResponse
+
+// ExecuteGraphQL calls f(ctx, req).
+func (f GraphQLExecutorFunc) ExecuteGraphQL(ctx context.Context, req *GraphQLRequest) *GraphQLResponse {
+	return f(ctx, req)
+}
+
+// mountGraphQL registers the GraphQL endpoint on the server.
+func (h *ServiceHttp) mountGraphQL() {
+	cfg := h.conf.GetGraphql()
+	path := strings.TrimSpace(cfg.GetPath())
+	if path == "" {
+		path = defaultGraphQLPath
+	}
+	maxBody := cfg.GetMaxBodyBytes()
+	if maxBody <= 0 {
+		maxBody = defaultGraphQLMaxBodyBytes
+	}
+	handler := h.graphQLHandler(h.GraphQL, maxBody)
+	route := h.server.Route("/")
+	route.POST(path, handler)
+	if cfg.GetEnableGet() {
+		route.GET(path, handler)
+	}
+	log.Infof("GraphQL endpoint mounted at %s", path)
+}
+
+// graphQLHandler runs each request through the server middleware chain, so the operation is traced,
+// measured and authorized like any other route, and writes the result in GraphQL response format.
+func (h *ServiceHttp) graphQLHandler(exec GraphQLExecutor, maxBody int64) http.HandlerFunc {
+	return func(ctx http.Context) error {
+		r := ctx.Request()
+		next := ctx.Middleware(func(c context.Context, _ any) (any, error) {
+			req, err := readGraphQLRequest(ctx.Response(), r, maxBody)
+			if err != nil {
+				h.recordGraphQLOperation(graphQLOpUnknown, "rejected")
+				return nil, err
+			}
+			kind := graphQLOperationType(req.Query, req.OperationName)
+			if r.Method == nhttp.MethodGet && kind == graphQLOpMutation {
+				h.recordGraphQLOperation(kind, "rejected")
+				return nil, errors.New(nhttp.StatusMethodNotAllowed, graphQLMutationOnGetReason, "mutations must use POST")
+			}
+			res := exec.ExecuteGraphQL(c, req)
+			if res == nil {
+				h.recordGraphQLOperation(kind, "error")
+				return nil, errors.InternalServer("GRAPHQL_NO_RESULT", "executor returned no result")
+			}
+			h.mapGraphQLErrors(res)
+			result := "success"
+			if len(res.Errors) > 0 {
+				result = "error"
+			}
+			h.recordGraphQLOperation(kind, result)
+			return res, nil
+		})
+		reply, err := next(ctx, nil)
+		if err != nil {
+			return err
+		}
+		return ctx.JSON(nhttp.StatusOK, reply)
+	}
+}
+
+// readGraphQLRequest parses a POST body (application/json or application/graphql) or GET query parameters.
+func readGraphQLRequest(w nhttp.ResponseWriter, r *nhttp.Request, maxBody int64) (*GraphQLRequest, error) {
+	req := &GraphQLRequest{}
+	if r.Method == nhttp.MethodGet {
+		q := r.URL.Query()
+		req.Query, req.OperationName = q.Get("query"), q.Get("operationName")
+		for name, dst := range map[string]*map[string]any{"variables": &req.Variables, "extensions": &req.Extensions} {
+			if v := q.Get(name); v != "" {
+				if err := json.Unmarshal([]byte(v), dst); err != nil {
+					return nil, errors.BadRequest(invalidGraphQLRequestReason, fmt.Sprintf("invalid %s: %v", name, err))
+				}
+			}
+		}
+	} else {
+		body, err := io.ReadAll(nhttp.MaxBytesReader(w, r.Body, maxBody))
+		if err != nil {
+			return nil, errors.BadRequest(invalidGraphQLRequestReason, err.Error())
+		}
+		mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if mt == "application/graphql" {
+			req.Query = string(body)
+		} else if err := json.Unmarshal(body, req); err != nil {
+			return nil, errors.BadRequest(invalidGraphQLRequestReason, err.Error())
+		}
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		return nil, errors.BadRequest(invalidGraphQLRequestReason, "query is required")
+	}
+	return req, nil
+}
+
+// mapGraphQLErrors adds the body code and reason of Kratos resolver errors to their extensions.
+func (h *ServiceHttp) mapGraphQLErrors(res *GraphQLResponse) {
+	for i := range res.Errors {
+		e := &res.Errors[i]
+		if e.Err == nil {
+			continue
+		}
+		se := errors.FromError(e.Err)
+		if e.Extensions == nil {
+			e.Extensions = make(map[string]any, 2)
+		}
+		e.Extensions["code"] = h.responseBodyCodeFromError(e.Err)
+		if se.Reason != "" {
+			e.Extensions["reason"] = se.Reason
+		}
+		if e.Message == "" {
+			e.Message = se.Message
+		}
+	}
+}
+
+func (h *ServiceHttp) recordGraphQLOperation(kind, result string) {
+	if h.graphQLOperations != nil {
+		h.graphQLOperations.WithLabelValues(kind, result).Inc()
+	}
+}
+
+// ObserveGraphQLResolver records the latency of one resolver call when graphql.resolver_metrics is enabled.
+// Call it from the field middleware of the GraphQL library with a "Type.field" name; field names come from
+// the schema, which keeps the label set bounded.
+func (h *ServiceHttp) ObserveGraphQLResolver(field string, duration time.Duration, err error) {
+	if h.graphQLResolverDuration == nil || !h.conf.GetGraphql().GetResolverMetrics() {
+		return
+	}
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	h.graphQLResolverDuration.WithLabelValues(field, result).Observe(duration.Seconds())
+}
+
+// graphQLOperationType returns the type of the operation named opName, or of the only operation when
+// opName is empty. Documents that cannot be classified yield "unknown".
+func graphQLOperationType(query, opName string) string {
+	type operation struct{ kind, name string }
+	var ops []operation
+	lex := graphQLLexer{src: query}
+	depth := 0
+	inHeader, expectName := false, false
+	var cur *operation
+	for tok, ok := lex.next(); ok; tok, ok = lex.next() {
+		if depth > 0 {
+			switch tok {
+			case "{", "(", "[":
+				depth++
+			case "}", ")", "]":
+				depth--
+			}
+			continue
+		}
+		switch {
+		case tok == "{":
+			if !inHeader {
+				ops = append(ops, operation{kind: graphQLOpQuery})
+			} else if cur != nil {
+				ops = append(ops, *cur)
+			}
+			inHeader, cur = false, nil
+			depth++
+		case tok == "(" || tok == "[":
+			expectName = false
+			depth++
+		case !inHeader && (tok == graphQLOpQuery || tok == graphQLOpMutation || tok == graphQLOpSubscription):
+			cur = &operation{kind: tok}
+			inHeader, expectName = true, true
+		case !inHeader && tok == "fragment":
+			inHeader, expectName = true, false
+		case inHeader && expectName && isGraphQLName(tok):
+			cur.name = tok
+			expectName = false
+		default:
+			expectName = false
+		}
+	}
+	for _, op := range ops {
+		if (opName == "" && len(ops) == 1) || (opName != "" && op.name == opName) {
+			return op.kind
+		}
+	}
+	return graphQLOpUnknown
+}
+
+// graphQLLexer yields names and punctuators of a GraphQL document, skipping comments, strings and numbers.
+type graphQLLexer struct {
+	src string
+	pos int
+}
+
+func (l *graphQLLexer) next() (string, bool) {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			l.pos++
+		case c == '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+		case c == '"':
+			l.skipString()
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			start := l.pos
+			for l.pos < len(l.src) && isFieldNameByte(l.src[l.pos]) {
+				l.pos++
+			}
+			return l.src[start:l.pos], true
+		case c == '-' || c >= '0' && c <= '9':
+			l.pos++
+			for l.pos < len(l.src) && strings.IndexByte("0123456789.eE+-", l.src[l.pos]) >= 0 {
+				l.pos++
+			}
+		default:
+			l.pos++
+			return string(c), true
+		}
+	}
+	return "", false
+}
+
+func (l *graphQLLexer) skipString() {
+	if strings.HasPrefix(l.src[l.pos:], `"""`) {
+		l.pos += 3
+		for l.pos < len(l.src) {
+			if strings.HasPrefix(l.src[l.pos:], `\"""`) {
+				l.pos += 4
+				continue
+			}
+			if strings.HasPrefix(l.src[l.pos:], `"""`) {
+				l.pos += 3
+				return
+			}
+			l.pos++
+		}
+		return
+	}
+	l.pos++
+	for l.pos < len(l.src) {
+		switch l.src[l.pos] {
+		case '\\':
+			l.pos += 2
+		case '"', '\n':
+			l.pos++
+			return
+		default:
+			l.pos++
+		}
+	}
+}
+
+func isGraphQLName(tok string) bool {
+	return tok != "" && (tok[0] == '_' || tok[0] >= 'a' && tok[0] <= 'z' || tok[0] >= 'A' && tok[0] <= 'Z')
+}
+
+// validateGraphQLConfig rejects relative paths and a negative body limit.
+func validateGraphQLConfig(cfg *conf.GraphQLConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	if p := strings.TrimSpace(cfg.Path); p != "" && !strings.HasPrefix(p, "/") {
+		return fmt.Errorf("path %q must start with /", p)
+	}
+	if cfg.MaxBodyBytes < 0 {
+		return fmt.Errorf("max body bytes cannot be negative")
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	nhttp "net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphQLOperationType(t *testing.T) {
+	cases := []struct {
+		query, op, want string
+	}{
+		{`{ me { id } }`, "", graphQLOpQuery},
+		{`query Me { me { id } }`, "", graphQLOpQuery},
+		{`mutation($in: In = {a: "}"}) @x { save(in: $in) { id } }`, "", graphQLOpMutation},
+		{"# mutation\nsubscription S { events }", "", graphQLOpSubscription},
+		{`fragment F on mutation { id } query A { ...F } mutation B { x(s: """ { """) }`, "B", graphQLOpMutation},
+		{`query A { a } mutation B { b }`, "", graphQLOpUnknown},
+		{`query A { a }`, "Missing", graphQLOpUnknown},
+		{`not graphql`, "", graphQLOpUnknown},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, graphQLOperationType(c.query, c.op), c.query)
+	}
+}
+
+func newGraphQLTestService(t *testing.T, exec GraphQLExecutor) (*ServiceHttp, *[]string) {
+	t.Helper()
+	var ops []string
+	h := &ServiceHttp{
+		conf:    &conf.Http{Graphql: &conf.GraphQLConfig{EnableGet: true, ResolverMetrics: true}},
+		GraphQL: exec,
+		graphQLOperations: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "graphql_ops_test"},
+			[]string{"type", "result"}),
+		graphQLResolverDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "graphql_resolver_test"},
+			[]string{"field", "result"}),
+	}
+	record := func(next middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			_, op := requestMetadata(ctx)
+			ops = append(ops, op)
+			return next(ctx, req)
+		}
+	}
+	h.server = http.NewServer(http.Middleware(record), http.ErrorEncoder(h.enhancedErrorEncoder))
+	h.mountGraphQL()
+	return h, &ops
+}
+
+func TestGraphQLEndpoint(t *testing.T) {
+	exec := GraphQLExecutorFunc(func(_ context.Context, req *GraphQLRequest) *GraphQLResponse {
+		if req.Variables["fail"] == true {
+			return &GraphQLResponse{
+				Data:   json.RawMessage(`{"user":null}`),
+				Errors: []GraphQLError{{Path: []any{"user"}, Err: errors.NotFound("USER_NOT_FOUND", "no such user")}},
+			}
+		}
+		return &GraphQLResponse{Data: json.RawMessage(`{"user":{"id":"1"}}`)}
+	})
+	h, ops := newGraphQLTestService(t, exec)
+	h.ErrorCodeMapper = func(se *errors.Error) int {
+		if se.Reason == "USER_NOT_FOUND" {
+			return 100404
+		}
+		return defaultErrorCode(se)
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(nhttp.MethodPost, "/graphql", strings.NewReader(`{"query":"{ user { id } }"}`))
+	r.Header.Set("Content-Type", "application/json")
+	h.server.ServeHTTP(w, r)
+	assert.Equal(t, nhttp.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":{"user":{"id":"1"}}}`, w.Body.String())
+	assert.Equal(t, []string{"/graphql"}, *ops, "request runs through the middleware chain")
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(nhttp.MethodPost, "/graphql",
+		strings.NewReader(`{"query":"query U { user { id } }","variables":{"fail":true}}`))
+	r.Header.Set("Content-Type", "application/json")
+	h.server.ServeHTTP(w, r)
+	assert.JSONEq(t, `{"data":{"user":null},"errors":[{"message":"no such user","path":["user"],
+		"extensions":{"code":100404,"reason":"USER_NOT_FOUND"}}]}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	h.server.ServeHTTP(w, httptest.NewRequest(nhttp.MethodGet, "/graphql?query="+url.QueryEscape("{ user { id } }"), nil))
+	assert.JSONEq(t, `{"data":{"user":{"id":"1"}}}`, w.Body.String())
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(h.graphQLOperations.WithLabelValues(graphQLOpQuery, "success")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(h.graphQLOperations.WithLabelValues(graphQLOpQuery, "error")))
+}
+
+func TestGraphQLEndpoint_Rejections(t *testing.T) {
+	called := false
+	h, _ := newGraphQLTestService(t, GraphQLExecutorFunc(func(context.Context, *GraphQLRequest) *GraphQLResponse {
+		called = true
+		return &GraphQLResponse{}
+	}))
+
+	w := httptest.NewRecorder()
+	h.server.ServeHTTP(w, httptest.NewRequest(nhttp.MethodGet, "/graphql?query="+url.QueryEscape("mutation { x }"), nil))
+	assert.JSONEq(t, `{"code":405}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	h.server.ServeHTTP(w, httptest.NewRequest(nhttp.MethodPost, "/graphql", strings.NewReader(`{"query":""}`)))
+	assert.JSONEq(t, `{"code":400}`, w.Body.String())
+
+	assert.False(t, called)
+	assert.Equal(t, float64(1), testutil.ToFloat64(h.graphQLOperations.WithLabelValues(graphQLOpMutation, "rejected")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(h.graphQLOperations.WithLabelValues(graphQLOpUnknown, "rejected")))
+}
+
+func TestObserveGraphQLResolver(t *testing.T) {
+	h, _ := newGraphQLTestService(t, GraphQLExecutorFunc(func(context.Context, *GraphQLRequest) *GraphQLResponse { return nil }))
+	h.ObserveGraphQLResolver("Query.user", 5*time.Millisecond, nil)
+	h.ObserveGraphQLResolver("Query.user", 5*time.Millisecond, errors.NotFound("X", ""))
+	assert.Equal(t, 2, testutil.CollectAndCount(h.graphQLResolverDuration))
+
+	h.conf.Graphql.ResolverMetrics = false
+	h.ObserveGraphQLResolver("Query.other", time.Millisecond, nil)
+	assert.Equal(t, 2, testutil.CollectAndCount(h.graphQLResolverDuration))
+}
+
+func TestValidateGraphQLConfig(t *testing.T) {
+	require.NoError(t, validateGraphQLConfig(nil))
+	assert.Error(t, validateGraphQLConfig(&conf.GraphQLConfig{Path: "graphql"}))
+	assert.Error(t, validateGraphQLConfig(&conf.GraphQLConfig{MaxBodyBytes: -1}))
+}
@@ -0,0 +1,285 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	nhttp "net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+const (
+	defaultGrpcHealthPath   = "/grpc.health.v1.Health/Check"
+	defaultGrpcServicesPath = "/grpc/services"
+)
+
+// SetServingStatus marks a proto service, e.g. "helloworld.v1.Greeter", as serving or not for the gRPC parity
+// health check, like the grpc.health.v1 server's SetServingStatus. Services without a status follow the
+// server's health.
+func (h *ServiceHttp) SetServingStatus(service string, serving bool) {
+	h.servingStatusMu.Lock()
+	defer h.servingStatusMu.Unlock()
+	if h.servingStatus == nil {
+		h.servingStatus = make(map[string]bool)
+	}
+	h.servingStatus[service] = serving
+}
+
+// grpcParityEndpoints returns the health and services paths, or nil when the endpoints are disabled.
+func grpcParityEndpoints(cfg *conf.GrpcParityConfig) []builtinEndpoint {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	return []builtinEndpoint{
+		{"gRPC health", configuredPath(cfg.GetHealthPath(), defaultGrpcHealthPath), false},
+		{"gRPC services", configuredPath(cfg.GetServicesPath(), defaultGrpcServicesPath), false},
+	}
+}
+
+// mountGrpcParity registers the gRPC parity endpoints.
+func (h *ServiceHttp) mountGrpcParity() {
+	cfg := h.conf.GetGrpcParity()
+	if !cfg.GetEnabled() {
+		return
+	}
+	endpoints := grpcParityEndpoints(cfg)
+	h.server.Handle(endpoints[0].path, &netHTTPToKratosHandlerAdapter{handler: h.grpcHealthHandler(cfg)})
+	h.server.Handle(endpoints[1].path, &netHTTPToKratosHandlerAdapter{handler: h.grpcServicesHandler(cfg)})
+	log.Infof("gRPC parity endpoints mounted: health %s, services %s", endpoints[0].path, endpoints[1].path)
+}
+
+// grpcHealthHandler answers a grpc.health.v1 HealthCheckResponse as JSON, with 200 for SERVING, 503 for
+// NOT_SERVING and 404 for SERVICE_UNKNOWN. The empty service is the server as a whole.
+func (h *ServiceHttp) grpcHealthHandler(cfg *conf.GrpcParityConfig) nhttp.Handler {
+	return nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		req := &grpc_health_v1.HealthCheckRequest{Service: r.URL.Query().Get("service")}
+		switch r.Method {
+		case nhttp.MethodGet, nhttp.MethodHead:
+		case nhttp.MethodPost:
+			body, err := io.ReadAll(io.LimitReader(r.Body, 1<<16))
+			if err == nil && len(body) > 0 {
+				err = protojson.Unmarshal(body, req)
+			}
+			if err != nil {
+				nhttp.Error(w, "invalid HealthCheckRequest: "+err.Error(), nhttp.StatusBadRequest)
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, HEAD, POST")
+			nhttp.Error(w, "method not allowed", nhttp.StatusMethodNotAllowed)
+			return
+		}
+
+		status := h.grpcServingStatus(cfg, req.GetService())
+		code := nhttp.StatusOK
+		switch status {
+		case grpc_health_v1.HealthCheckResponse_NOT_SERVING:
+			code = nhttp.StatusServiceUnavailable
+		case grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN:
+			code = nhttp.StatusNotFound
+		}
+		body, _ := protojson.Marshal(&grpc_health_v1.HealthCheckResponse{Status: status})
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(code)
+		_, _ = w.Write(body)
+	})
+}
+
+// grpcServingStatus returns the status of service: NOT_SERVING while the server is draining or unhealthy,
+// otherwise the status set with SetServingStatus, and SERVICE_UNKNOWN for services the server does not list.
+func (h *ServiceHttp) grpcServingStatus(cfg *conf.GrpcParityConfig, service string) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	if service != "" {
+		h.servingStatusMu.RLock()
+		serving, set := h.servingStatus[service]
+		h.servingStatusMu.RUnlock()
+		if !set {
+			if !slices.ContainsFunc(h.protoServices(cfg, protoregistry.GlobalFiles), func(s protoService) bool {
+				return s.Name == service
+			}) {
+				return grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN
+			}
+			serving = true
+		}
+		if !serving {
+			return grpc_health_v1.HealthCheckResponse_NOT_SERVING
+		}
+	}
+	if h.draining.Load() || h.CheckRuntimeHealth() != nil {
+		return grpc_health_v1.HealthCheckResponse_NOT_SERVING
+	}
+	return grpc_health_v1.HealthCheckResponse_SERVING
+}
+
+// protoService is a proto service as listed by the services endpoint.
+type protoService struct {
+	Name    string        `json:"name"`
+	File    string        `json:"file"`
+	Methods []protoMethod `json:"methods"`
+}
+
+type protoMethod struct {
+	Name            string        `json:"name"`
+	Operation       string        `json:"operation"`
+	InputType       string        `json:"input_type"`
+	OutputType      string        `json:"output_type"`
+	ClientStreaming bool          `json:"client_streaming,omitempty"`
+	ServerStreaming bool          `json:"server_streaming,omitempty"`
+	HTTP            []httpBinding `json:"http,omitempty"`
+}
+
+type httpBinding struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
+
+// grpcServicesHandler lists the proto services of the server.
+func (h *ServiceHttp) grpcServicesHandler(cfg *conf.GrpcParityConfig) nhttp.Handler {
+	return nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		if r.Method != nhttp.MethodGet && r.Method != nhttp.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			nhttp.Error(w, "method not allowed", nhttp.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"services": h.protoServices(cfg, protoregistry.GlobalFiles)})
+	})
+}
+
+// protoServices returns the services of files named in cfg, or else those with an HTTP binding that matches a
+// route of the server, sorted by name.
+func (h *ServiceHttp) protoServices(cfg *conf.GrpcParityConfig, files *protoregistry.Files) []protoService {
+	if names := cfg.GetServices(); len(names) > 0 {
+		var services []protoService
+		for _, name := range names {
+			d, err := files.FindDescriptorByName(protoreflect.FullName(name))
+			if sd, ok := d.(protoreflect.ServiceDescriptor); err == nil && ok {
+				services = append(services, describeProtoService(sd))
+			}
+		}
+		return services
+	}
+
+	routes := make(map[httpBinding]struct{})
+	if h.server != nil {
+		_ = h.server.WalkRoute(func(ri http.RouteInfo) error {
+			routes[httpBinding{Method: ri.Method, Path: normalizeRouteTemplate(ri.Path)}] = struct{}{}
+			return nil
+		})
+	}
+	var services []protoService
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		for i := 0; i < fd.Services().Len(); i++ {
+			s := describeProtoService(fd.Services().Get(i))
+			if slices.ContainsFunc(s.Methods, func(m protoMethod) bool {
+				return slices.ContainsFunc(m.HTTP, func(b httpBinding) bool {
+					_, ok := routes[httpBinding{Method: b.Method, Path: normalizeRouteTemplate(b.Path)}]
+					return ok
+				})
+			}) {
+				services = append(services, s)
+			}
+		}
+		return true
+	})
+	slices.SortFunc(services, func(a, b protoService) int { return strings.Compare(a.Name, b.Name) })
+	return services
+}
+
+func describeProtoService(sd protoreflect.ServiceDescriptor) protoService {
+	s := protoService{Name: string(sd.FullName()), File: sd.ParentFile().Path(), Methods: []protoMethod{}}
+	for i := 0; i < sd.Methods().Len(); i++ {
+		md := sd.Methods().Get(i)
+		s.Methods = append(s.Methods, protoMethod{
+			Name:            string(md.Name()),
+			Operation:       fmt.Sprintf("/%s/%s", sd.FullName(), md.Name()),
+			InputType:       string(md.Input().FullName()),
+			OutputType:      string(md.Output().FullName()),
+			ClientStreaming: md.IsStreamingClient(),
+			ServerStreaming: md.IsStreamingServer(),
+			HTTP:            httpBindings(md),
+		})
+	}
+	return s
+}
+
+// httpBindings returns the google.api.http bindings of a method, additional bindings included.
+func httpBindings(md protoreflect.MethodDescriptor) []httpBinding {
+	var bindings []httpBinding
+	for _, r := range httpRoutes(md) {
+		bindings = append(bindings, r.httpBinding)
+	}
+	return bindings
+}
+
+// httpRoute is a google.api.http binding with its body.
+type httpRoute struct {
+	httpBinding
+	// body is "*" for the whole request message, a field name, or "" when the request travels in the URL.
+	body string
+}
+
+// httpRoutes returns the google.api.http bindings of a method with their bodies, additional bindings included.
+func httpRoutes(md protoreflect.MethodDescriptor) []httpRoute {
+	rule, _ := proto.GetExtension(md.Options(), annotations.E_Http).(*annotations.HttpRule)
+	if rule == nil {
+		return nil
+	}
+	var routes []httpRoute
+	for _, r := range append([]*annotations.HttpRule{rule}, rule.GetAdditionalBindings()...) {
+		var b httpBinding
+		switch p := r.GetPattern().(type) {
+		case *annotations.HttpRule_Get:
+			b = httpBinding{nhttp.MethodGet, p.Get}
+		case *annotations.HttpRule_Put:
+			b = httpBinding{nhttp.MethodPut, p.Put}
+		case *annotations.HttpRule_Post:
+			b = httpBinding{nhttp.MethodPost, p.Post}
+		case *annotations.HttpRule_Delete:
+			b = httpBinding{nhttp.MethodDelete, p.Delete}
+		case *annotations.HttpRule_Patch:
+			b = httpBinding{nhttp.MethodPatch, p.Patch}
+		case *annotations.HttpRule_Custom:
+			b = httpBinding{p.Custom.GetKind(), p.Custom.GetPath()}
+		default:
+			continue
+		}
+		routes = append(routes, httpRoute{httpBinding: b, body: r.GetBody()})
+	}
+	return routes
+}
+
+// routeVariable matches path variables of google.api.http templates ({name=shelves/*}) and of the server's
+// routes ({name:shelves/.*}).
+var routeVariable = regexp.MustCompile(`\{[^}]*\}`)
+
+// normalizeRouteTemplate replaces path variables with "{}", so bindings and routes compare equal.
+func normalizeRouteTemplate(path string) string {
+	return routeVariable.ReplaceAllString(path, "{}")
+}
+
+// validateGrpcParityConfig requires absolute, distinct endpoint paths.
+func validateGrpcParityConfig(cfg *conf.GrpcParityConfig) error {
+	endpoints := grpcParityEndpoints(cfg)
+	for _, endpoint := range endpoints {
+		if !strings.HasPrefix(endpoint.path, "/") {
+			return fmt.Errorf("%s path %q must start with /", endpoint.name, endpoint.path)
+		}
+	}
+	if len(endpoints) == 2 && endpoints[0].path == endpoints[1].path {
+		return fmt.Errorf("health_path and services_path must differ")
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package http
+
+import (
+	"encoding/json"
+	nhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// parityTestFiles returns a registry with the services shop.v1.Orders, bound to GET /v1/orders/{id} and
+// POST /v1/orders, and shop.v1.Internal, without HTTP bindings.
+func parityTestFiles(t *testing.T) *protoregistry.Files {
+	t.Helper()
+	method := func(name string, rule *annotations.HttpRule) *descriptorpb.MethodDescriptorProto {
+		md := &descriptorpb.MethodDescriptorProto{
+			Name:       proto.String(name),
+			InputType:  proto.String(".google.protobuf.Empty"),
+			OutputType: proto.String(".google.protobuf.Empty"),
+		}
+		if rule != nil {
+			md.Options = &descriptorpb.MethodOptions{}
+			proto.SetExtension(md.Options, annotations.E_Http, rule)
+		}
+		return md
+	}
+	fdp := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("shop/v1/orders.proto"),
+		Package:    proto.String("shop.v1"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/empty.proto"},
+		Service: []*descriptorpb.ServiceDescriptorProto{
+			{Name: proto.String("Orders"), Method: []*descriptorpb.MethodDescriptorProto{
+				method("GetOrder", &annotations.HttpRule{Pattern: &annotations.HttpRule_Get{Get: "/v1/orders/{id}"}}),
+				method("CreateOrder", &annotations.HttpRule{
+					Pattern:            &annotations.HttpRule_Post{Post: "/v1/orders"},
+					AdditionalBindings: []*annotations.HttpRule{{Pattern: &annotations.HttpRule_Put{Put: "/v1/orders"}}},
+				}),
+			}},
+			{Name: proto.String("Internal"), Method: []*descriptorpb.MethodDescriptorProto{method("Sync", nil)}},
+		},
+	}
+	files := &protoregistry.Files{}
+	require.NoError(t, files.RegisterFile(emptypb.File_google_protobuf_empty_proto))
+	fd, err := protodesc.NewFile(fdp, files)
+	require.NoError(t, err)
+	require.NoError(t, files.RegisterFile(fd))
+	return files
+}
+
+func TestProtoServices(t *testing.T) {
+	files := parityTestFiles(t)
+	h := NewServiceHttp()
+	h.server = http.NewServer()
+	assert.Empty(t, h.protoServices(&conf.GrpcParityConfig{}, files), "no matching routes")
+
+	h.server.Route("/").GET("/v1/orders/{id}", func(http.Context) error { return nil })
+	services := h.protoServices(&conf.GrpcParityConfig{}, files)
+	require.Len(t, services, 1)
+	assert.Equal(t, "shop.v1.Orders", services[0].Name)
+	assert.Equal(t, "shop/v1/orders.proto", services[0].File)
+	assert.Equal(t, protoMethod{
+		Name:       "CreateOrder",
+		Operation:  "/shop.v1.Orders/CreateOrder",
+		InputType:  "google.protobuf.Empty",
+		OutputType: "google.protobuf.Empty",
+		HTTP:       []httpBinding{{"POST", "/v1/orders"}, {"PUT", "/v1/orders"}},
+	}, services[0].Methods[1])
+
+	services = h.protoServices(&conf.GrpcParityConfig{Services: []string{"shop.v1.Internal", "shop.v1.Missing"}}, files)
+	require.Len(t, services, 1, "configured services are listed as given")
+	assert.Equal(t, "shop.v1.Internal", services[0].Name)
+	assert.Empty(t, services[0].Methods[0].HTTP)
+}
+
+func TestGrpcParityEndpoints(t *testing.T) {
+	h := NewServiceHttp()
+	h.server = http.NewServer()
+	cfg := &conf.GrpcParityConfig{Enabled: true, Services: []string{"grpc.health.v1.Health"}}
+	health := h.grpcHealthHandler(cfg)
+	check := func(method, target, body string) (int, string) {
+		rec := httptest.NewRecorder()
+		health.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return rec.Code, strings.TrimSpace(rec.Body.String())
+	}
+
+	code, body := check(nhttp.MethodGet, "/grpc.health.v1.Health/Check", "")
+	assert.Equal(t, nhttp.StatusOK, code)
+	assert.JSONEq(t, `{"status":"SERVING"}`, body)
+	code, body = check(nhttp.MethodPost, "/grpc.health.v1.Health/Check", `{"service":"grpc.health.v1.Health"}`)
+	assert.Equal(t, nhttp.StatusOK, code)
+	assert.JSONEq(t, `{"status":"SERVING"}`, body)
+	code, body = check(nhttp.MethodGet, "/grpc.health.v1.Health/Check?service=shop.v1.Unknown", "")
+	assert.Equal(t, nhttp.StatusNotFound, code)
+	assert.JSONEq(t, `{"status":"SERVICE_UNKNOWN"}`, body)
+	code, _ = check(nhttp.MethodPost, "/grpc.health.v1.Health/Check", `{"service":`)
+	assert.Equal(t, nhttp.StatusBadRequest, code)
+
+	h.SetServingStatus("grpc.health.v1.Health", false)
+	code, body = check(nhttp.MethodGet, "/grpc.health.v1.Health/Check?service=grpc.health.v1.Health", "")
+	assert.Equal(t, nhttp.StatusServiceUnavailable, code)
+	assert.JSONEq(t, `{"status":"NOT_SERVING"}`, body)
+	h.SetServingStatus("shop.v1.Custom", true)
+	code, _ = check(nhttp.MethodGet, "/grpc.health.v1.Health/Check?service=shop.v1.Custom", "")
+	assert.Equal(t, nhttp.StatusOK, code, "services with a status are known")
+
+	h.draining.Store(true)
+	code, body = check(nhttp.MethodGet, "/grpc.health.v1.Health/Check", "")
+	assert.Equal(t, nhttp.StatusServiceUnavailable, code)
+	assert.JSONEq(t, `{"status":"NOT_SERVING"}`, body)
+
+	rec := httptest.NewRecorder()
+	h.grpcServicesHandler(cfg).ServeHTTP(rec, httptest.NewRequest(nhttp.MethodGet, "/grpc/services", nil))
+	var listing struct {
+		Services []protoService `json:"services"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listing))
+	require.Len(t, listing.Services, 1)
+	assert.Equal(t, "grpc.health.v1.Health", listing.Services[0].Name)
+	assert.Equal(t, "/grpc.health.v1.Health/Check", listing.Services[0].Methods[0].Operation)
+}
+
+func TestValidateGrpcParityConfig(t *testing.T) {
+	require.NoError(t, validateGrpcParityConfig(nil))
+	require.NoError(t, validateGrpcParityConfig(&conf.GrpcParityConfig{Enabled: true}))
+	assert.ErrorContains(t, validateGrpcParityConfig(&conf.GrpcParityConfig{Enabled: true, HealthPath: "health"}),
+		"must start with /")
+	assert.ErrorContains(t, validateGrpcParityConfig(&conf.GrpcParityConfig{Enabled: true, ServicesPath: defaultGrpcHealthPath}),
+		"must differ")
+}
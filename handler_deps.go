@@ -0,0 +1,142 @@
+package http
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx/log"
+	"github.com/go-lynx/lynx/plugins"
+)
+
+// PluginDeps are the Lynx plugins a handler declared in HandlerRegistration.Plugins, resolved by name.
+type PluginDeps struct {
+	plugins map[string]plugins.Plugin
+}
+
+// Plugin returns the declared plugin name, or nil when it was not declared.
+func (d PluginDeps) Plugin(name string) plugins.Plugin {
+	return d.plugins[name]
+}
+
+// PluginAs returns the declared plugin name as T, e.g. PluginAs[*redis.PlugRedis](deps, "redis.client").
+func PluginAs[T any](deps PluginDeps, name string) (T, error) {
+	p := deps.Plugin(name)
+	if p == nil {
+		var zero T
+		return zero, fmt.Errorf("plugin %q was not declared", name)
+	}
+	v, ok := p.(T)
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("plugin %q is a %T, not a %T", name, p, zero)
+	}
+	return v, nil
+}
+
+// HandlerRegistration registers the routes of a handler that needs other Lynx plugins, such as a database or
+// cache client, so the handler receives them instead of reaching for package globals.
+type HandlerRegistration struct {
+	// Name identifies the handler in errors and logs.
+	Name string
+	// Plugins are the names of the Lynx plugins the handler needs, e.g. "mysql.client".
+	Plugins []string
+	// Register registers the routes of the handler on srv, with the declared plugins resolved.
+	Register func(srv *http.Server, deps PluginDeps) error
+}
+
+// RegisterHandler registers a handler whose routes need other Lynx plugins. Before the server starts, the
+// plugins become required dependencies of the HTTP plugin, so Lynx starts them first, and the handler is
+// registered during startup, which fails if a plugin is missing. Afterwards the handler is registered at once
+// and a missing plugin is returned as an error.
+func (h *ServiceHttp) RegisterHandler(reg HandlerRegistration) error {
+	reg.Name = strings.TrimSpace(reg.Name)
+	if reg.Name == "" {
+		return fmt.Errorf("handler name is required")
+	}
+	if reg.Register == nil {
+		return fmt.Errorf("handler %q: Register is nil", reg.Name)
+	}
+	for _, name := range reg.Plugins {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("handler %q: empty plugin name", reg.Name)
+		}
+	}
+
+	h.handlerMu.Lock()
+	defer h.handlerMu.Unlock()
+	if slices.ContainsFunc(h.handlers, func(r HandlerRegistration) bool { return r.Name == reg.Name }) {
+		return fmt.Errorf("handler %q already registered", reg.Name)
+	}
+	if h.handlersMounted {
+		if err := h.mountHandler(reg); err != nil {
+			return err
+		}
+	} else if h.BasePlugin != nil {
+		for _, name := range reg.Plugins {
+			if !slices.ContainsFunc(h.GetDependencies(), func(d plugins.Dependency) bool { return d.Name == name }) {
+				h.AddDependency(plugins.Dependency{
+					ID:          name,
+					Name:        name,
+					Type:        plugins.DependencyTypeRequired,
+					Required:    true,
+					Description: "required by HTTP handler " + reg.Name,
+				})
+			}
+		}
+	}
+	h.handlers = append(h.handlers, reg)
+	return nil
+}
+
+// mountHandlers registers the handlers added with RegisterHandler before startup. The caller has created the
+// server.
+func (h *ServiceHttp) mountHandlers() error {
+	h.handlerMu.Lock()
+	defer h.handlerMu.Unlock()
+	for _, reg := range h.handlers {
+		if err := h.mountHandler(reg); err != nil {
+			return err
+		}
+	}
+	h.handlersMounted = true
+	return nil
+}
+
+// mountHandler resolves the plugins of reg and registers its routes. The caller holds handlerMu.
+func (h *ServiceHttp) mountHandler(reg HandlerRegistration) error {
+	deps := PluginDeps{plugins: make(map[string]plugins.Plugin, len(reg.Plugins))}
+	var missing []string
+	for _, name := range reg.Plugins {
+		if p := h.lookupPlugin(name); p != nil {
+			deps.plugins[name] = p
+		} else {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("handler %q: plugins not loaded: %s", reg.Name, strings.Join(missing, ", "))
+	}
+	if err := reg.Register(h.server, deps); err != nil {
+		return fmt.Errorf("handler %q: %w", reg.Name, err)
+	}
+	log.Infof("HTTP handler %s registered (plugins: %s)", reg.Name, strings.Join(reg.Plugins, ", "))
+	return nil
+}
+
+// lookupPlugin returns the loaded Lynx plugin name, or nil.
+func (h *ServiceHttp) lookupPlugin(name string) plugins.Plugin {
+	if h.pluginLookup != nil {
+		return h.pluginLookup(name)
+	}
+	app := currentLynxApp()
+	if app == nil {
+		return nil
+	}
+	m := app.GetPluginManager()
+	if m == nil {
+		return nil
+	}
+	return m.GetPlugin(name)
+}
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx/plugins"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fakePlugins(names ...string) func(string) plugins.Plugin {
+	loaded := make(map[string]plugins.Plugin)
+	for _, name := range names {
+		loaded[name] = plugins.NewBasePlugin(plugins.GeneratePluginID("", name, "v1.0.0"), name, "", "v1.0.0", name, 10)
+	}
+	return func(name string) plugins.Plugin { return loaded[name] }
+}
+
+func TestRegisterHandler_BeforeStartup(t *testing.T) {
+	h := NewServiceHttp()
+	var (
+		got    PluginDeps
+		called bool
+	)
+	reg := HandlerRegistration{
+		Name:    "orders",
+		Plugins: []string{"mysql.client", "redis.client"},
+		Register: func(srv *http.Server, deps PluginDeps) error {
+			got, called = deps, true
+			srv.Route("/").GET("/v1/orders", func(http.Context) error { return nil })
+			return nil
+		},
+	}
+	require.NoError(t, h.RegisterHandler(reg))
+	assert.ErrorContains(t, h.RegisterHandler(reg), "already registered")
+
+	deps := h.GetDependencies()
+	require.Len(t, deps, 2)
+	assert.Equal(t, "mysql.client", deps[0].Name)
+	assert.Equal(t, plugins.DependencyTypeRequired, deps[0].Type)
+	assert.False(t, called, "registration waits for startup")
+
+	h.server = http.NewServer()
+	h.pluginLookup = fakePlugins("mysql.client")
+	assert.EqualError(t, h.mountHandlers(), `handler "orders": plugins not loaded: redis.client`)
+
+	h.pluginLookup = fakePlugins("mysql.client", "redis.client")
+	require.NoError(t, h.mountHandlers())
+	require.NotNil(t, got.Plugin("redis.client"))
+	assert.Equal(t, "redis.client", got.Plugin("redis.client").Name())
+	assert.Nil(t, got.Plugin("kafka.client"))
+	var routes []string
+	_ = h.server.WalkRoute(func(ri http.RouteInfo) error {
+		routes = append(routes, ri.Method+" "+ri.Path)
+		return nil
+	})
+	assert.Contains(t, routes, "GET /v1/orders")
+}
+
+func TestRegisterHandler_AfterStartup(t *testing.T) {
+	svc, err := NewTestService(nil)
+	require.NoError(t, err)
+	defer func() { _ = svc.Close() }()
+	svc.pluginLookup = fakePlugins("redis.client")
+
+	err = svc.RegisterHandler(HandlerRegistration{
+		Name:     "reports",
+		Plugins:  []string{"mysql.client"},
+		Register: func(*http.Server, PluginDeps) error { t.Fatal("registered without its plugins"); return nil },
+	})
+	assert.EqualError(t, err, `handler "reports": plugins not loaded: mysql.client`)
+	assert.Empty(t, svc.GetDependencies(), "dependencies only order startup")
+
+	require.NoError(t, svc.RegisterHandler(HandlerRegistration{
+		Name:    "cache",
+		Plugins: []string{"redis.client"},
+		Register: func(srv *http.Server, deps PluginDeps) error {
+			p, err := PluginAs[*plugins.BasePlugin](deps, "redis.client")
+			if err != nil {
+				return err
+			}
+			srv.Route("/").GET("/v1/cache", func(ctx http.Context) error { return ctx.String(200, p.Name()) })
+			return nil
+		},
+	}))
+	res, err := svc.Get("/v1/cache")
+	require.NoError(t, err)
+	assert.Equal(t, "redis.client", string(res.Body))
+}
+
+func TestRegisterHandler_Invalid(t *testing.T) {
+	h := NewServiceHttp()
+	assert.ErrorContains(t, h.RegisterHandler(HandlerRegistration{Register: func(*http.Server, PluginDeps) error { return nil }}), "name is required")
+	assert.ErrorContains(t, h.RegisterHandler(HandlerRegistration{Name: "a"}), "Register is nil")
+	assert.ErrorContains(t, h.RegisterHandler(HandlerRegistration{Name: "a", Plugins: []string{" "},
+		Register: func(*http.Server, PluginDeps) error { return nil }}), "empty plugin name")
+}
+
+func TestPluginAs(t *testing.T) {
+	deps := PluginDeps{plugins: map[string]plugins.Plugin{"redis.client": fakePlugins("redis.client")("redis.client")}}
+	p, err := PluginAs[*plugins.BasePlugin](deps, "redis.client")
+	require.NoError(t, err)
+	assert.Equal(t, "redis.client", p.Name())
+	_, err = PluginAs[*ServiceHttp](deps, "redis.client")
+	assert.ErrorContains(t, err, "not a *http.ServiceHttp")
+	_, err = PluginAs[*plugins.BasePlugin](deps, "mysql.client")
+	assert.ErrorContains(t, err, "was not declared")
+}
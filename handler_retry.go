@@ -0,0 +1,206 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	nhttp "net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultHandlerRetryAttempts = 3
+	maxHandlerRetryAttempts     = 10
+	defaultHandlerRetryBackoff  = 10 * time.Millisecond
+	defaultHandlerRetryMaxDelay = 200 * time.Millisecond
+
+	handlerRetryRecovered = "recovered"
+	handlerRetryExhausted = "exhausted"
+	handlerRetryFailed    = "failed"
+	handlerRetryCanceled  = "canceled"
+)
+
+var defaultHandlerRetryMethods = []string{
+	nhttp.MethodGet, nhttp.MethodHead, nhttp.MethodOptions, nhttp.MethodPut, nhttp.MethodDelete,
+}
+
+// handlerAttemptKey stores the 1-based handler run of the current request in its context.
+type handlerAttemptKey struct{}
+
+// HandlerAttemptFromContext returns which run of the handler this is, starting at 1. ok is false when the
+// operation is not retried by the handler retry middleware.
+func HandlerAttemptFromContext(ctx context.Context) (attempt int, ok bool) {
+	attempt, ok = ctx.Value(handlerAttemptKey{}).(int)
+	return attempt, ok
+}
+
+// handlerRetryPolicy is the resolved HandlerRetryConfig.
+type handlerRetryPolicy struct {
+	operations  []string
+	methods     []string
+	reasons     []string
+	codes       []int32
+	maxAttempts int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+}
+
+// newHandlerRetryPolicy returns nil when retries are disabled or no operation or transient error is listed.
+func newHandlerRetryPolicy(cfg *conf.HandlerRetryConfig) *handlerRetryPolicy {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	p := &handlerRetryPolicy{
+		methods:     defaultHandlerRetryMethods,
+		codes:       cfg.GetCodes(),
+		maxAttempts: defaultHandlerRetryAttempts,
+		backoff:     durationOrDefault(cfg.GetInitialBackoff().AsDuration(), defaultHandlerRetryBackoff),
+		maxBackoff:  durationOrDefault(cfg.GetMaxBackoff().AsDuration(), defaultHandlerRetryMaxDelay),
+	}
+	for _, op := range cfg.GetOperations() {
+		if op = strings.TrimSpace(op); op != "" {
+			p.operations = append(p.operations, op)
+		}
+	}
+	if len(cfg.GetMethods()) > 0 {
+		p.methods = nil
+		for _, m := range cfg.GetMethods() {
+			p.methods = append(p.methods, strings.ToUpper(strings.TrimSpace(m)))
+		}
+	}
+	for _, reason := range cfg.GetReasons() {
+		if reason = strings.TrimSpace(reason); reason != "" {
+			p.reasons = append(p.reasons, reason)
+		}
+	}
+	if n := int(cfg.GetMaxAttempts()); n > 0 {
+		p.maxAttempts = min(n, maxHandlerRetryAttempts)
+	}
+	if len(p.operations) == 0 || len(p.reasons) == 0 && len(p.codes) == 0 {
+		return nil
+	}
+	return p
+}
+
+// transient reports whether err is one of the designated transient errors.
+func (p *handlerRetryPolicy) transient(err error) (reason string, ok bool) {
+	if err == nil {
+		return "", false
+	}
+	e := errors.FromError(err)
+	return e.Reason, slices.Contains(p.reasons, e.Reason) || slices.Contains(p.codes, e.Code)
+}
+
+// delay returns the wait before the given retry, 1-based: the backoff doubled per retry up to maxBackoff,
+// with equal jitter like the outbound clients.
+func (p *handlerRetryPolicy) delay(retry int) time.Duration {
+	d := min(p.backoff<<(retry-1), p.maxBackoff)
+	return d/2 + rand.N(d/2+1)
+}
+
+// handlerRetryMiddleware runs the handler again for requests to the listed operations while it returns a
+// transient error, up to maxAttempts runs. Each run gets a fresh copy of a proto request, so a run that
+// modified it does not leak into the next. A retry that would not finish before the request deadline is not
+// started, and the last error is returned.
+func (h *ServiceHttp) handlerRetryMiddleware(p *handlerRetryPolicy) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			tr, ok := transport.FromServerContext(ctx)
+			if !ok {
+				return handler(ctx, req)
+			}
+			r, ok := http.RequestFromServerContext(ctx)
+			if !ok || r == nil || !slices.Contains(p.methods, r.Method) || !routeMatchesAny(p.operations, tr.Operation(), r.URL.Path) {
+				return handler(ctx, req)
+			}
+
+			var original proto.Message
+			if m, ok := req.(proto.Message); ok {
+				original = proto.Clone(m)
+			}
+			_, route := requestMetadata(ctx)
+			for attempt := 1; ; attempt++ {
+				if attempt > 1 && original != nil {
+					req = proto.Clone(original)
+				}
+				reply, err := handler(context.WithValue(ctx, handlerAttemptKey{}, attempt), req)
+				reason, transient := p.transient(err)
+				if !transient || attempt >= p.maxAttempts {
+					if attempt > 1 {
+						outcome := handlerRetryRecovered
+						switch {
+						case transient:
+							outcome = handlerRetryExhausted
+						case err != nil:
+							outcome = handlerRetryFailed
+						}
+						h.recordHandlerRetryOutcome(route, outcome)
+					}
+					return reply, err
+				}
+
+				wait := p.delay(attempt)
+				if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+					h.recordHandlerRetryOutcome(route, handlerRetryCanceled)
+					return reply, err
+				}
+				timer := time.NewTimer(wait)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					h.recordHandlerRetryOutcome(route, handlerRetryCanceled)
+					return reply, err
+				}
+				if h.handlerRetries != nil {
+					h.handlerRetries.WithLabelValues(route, reason).Inc()
+				}
+			}
+		}
+	}
+}
+
+func (h *ServiceHttp) recordHandlerRetryOutcome(route, outcome string) {
+	if h.handlerRetryOutcomes != nil {
+		h.handlerRetryOutcomes.WithLabelValues(route, outcome).Inc()
+	}
+}
+
+// validateHandlerRetryConfig requires operations and transient errors to be listed, and rejects methods that
+// are not idempotent and backoffs out of range.
+func validateHandlerRetryConfig(cfg *conf.HandlerRetryConfig) error {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	if len(cfg.GetOperations()) == 0 {
+		return fmt.Errorf("operations is required")
+	}
+	if len(cfg.GetReasons()) == 0 && len(cfg.GetCodes()) == 0 {
+		return fmt.Errorf("at least one of reasons or codes is required")
+	}
+	for _, m := range cfg.GetMethods() {
+		if !slices.Contains(defaultHandlerRetryMethods, strings.ToUpper(strings.TrimSpace(m))) {
+			return fmt.Errorf("method %q is not idempotent", m)
+		}
+	}
+	if n := cfg.GetMaxAttempts(); n < 0 || n > maxHandlerRetryAttempts {
+		return fmt.Errorf("max_attempts must be between 1 and %d, got %d", maxHandlerRetryAttempts, n)
+	}
+	initial, maximum := cfg.GetInitialBackoff().AsDuration(), cfg.GetMaxBackoff().AsDuration()
+	if initial < 0 || maximum < 0 {
+		return fmt.Errorf("backoffs must not be negative")
+	}
+	if durationOrDefault(maximum, defaultHandlerRetryMaxDelay) < durationOrDefault(initial, defaultHandlerRetryBackoff) {
+		return fmt.Errorf("max_backoff must not be less than initial_backoff")
+	}
+	return nil
+}
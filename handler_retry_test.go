@@ -0,0 +1,144 @@
+package http
+
+import (
+	"context"
+	nhttp "net/http"
+	"testing"
+	"time"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx-http/httptesting"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestHandlerRetryMiddleware(t *testing.T) {
+	h := NewServiceHttp()
+	h.handlerRetries = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_handler_retries_total"}, []string{"route", "reason"})
+	h.handlerRetryOutcomes = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_handler_retry_outcomes_total"}, []string{"route", "outcome"})
+	p := newHandlerRetryPolicy(&conf.HandlerRetryConfig{
+		Enabled:        true,
+		Operations:     []string{"/api.v1.Orders/*"},
+		Reasons:        []string{"OPTIMISTIC_LOCK_CONFLICT"},
+		Codes:          []int32{nhttp.StatusServiceUnavailable},
+		InitialBackoff: durationpb.New(time.Millisecond),
+	})
+	conflict := kerrors.Conflict("OPTIMISTIC_LOCK_CONFLICT", "version changed")
+
+	// The handler fails until its third run and modifies its request, which later runs must not see.
+	var attempts []int
+	tester := httptesting.NewMiddlewareTester(t, h.handlerRetryMiddleware(p)).
+		WithHandler(func(ctx context.Context, req any) (any, error) {
+			attempt, ok := HandlerAttemptFromContext(ctx)
+			require.True(t, ok)
+			attempts = append(attempts, attempt)
+			msg := req.(*wrapperspb.StringValue)
+			assert.Equal(t, "order", msg.Value)
+			msg.Value = "modified"
+			if attempt < 3 {
+				return nil, conflict
+			}
+			return "saved", nil
+		})
+	res := tester.Run(httptesting.Request{Operation: "/api.v1.Orders/Update", Method: nhttp.MethodPut,
+		Message: wrapperspb.String("order")}).AssertNoError()
+	assert.Equal(t, "saved", res.Reply)
+	assert.Equal(t, []int{1, 2, 3}, attempts)
+	assert.Equal(t, 2.0, testutil.ToFloat64(h.handlerRetries.WithLabelValues("/api.v1.Orders/Update", "OPTIMISTIC_LOCK_CONFLICT")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(h.handlerRetryOutcomes.WithLabelValues("/api.v1.Orders/Update", handlerRetryRecovered)))
+
+	// Attempts are capped, and the last transient error is returned.
+	runs := 0
+	tester.WithHandler(func(context.Context, any) (any, error) {
+		runs++
+		return nil, kerrors.ServiceUnavailable("DB_BUSY", "try again")
+	})
+	tester.Run(httptesting.Request{Operation: "/api.v1.Orders/Get"}).AssertError(nhttp.StatusServiceUnavailable, "DB_BUSY")
+	assert.Equal(t, 3, runs)
+	assert.Equal(t, 1.0, testutil.ToFloat64(h.handlerRetryOutcomes.WithLabelValues("/api.v1.Orders/Get", handlerRetryExhausted)))
+
+	// Other errors, methods that are not idempotent and other operations run once.
+	runs = 0
+	tester.WithHandler(func(context.Context, any) (any, error) {
+		runs++
+		if runs == 1 {
+			return nil, conflict
+		}
+		return nil, kerrors.NotFound("ORDER_NOT_FOUND", "no such order")
+	})
+	tester.Run(httptesting.Request{Operation: "/api.v1.Orders/Get"}).AssertError(nhttp.StatusNotFound, "ORDER_NOT_FOUND")
+	assert.Equal(t, 2, runs)
+	assert.Equal(t, 1.0, testutil.ToFloat64(h.handlerRetryOutcomes.WithLabelValues("/api.v1.Orders/Get", handlerRetryFailed)))
+
+	runs = 0
+	tester.WithHandler(func(ctx context.Context, _ any) (any, error) {
+		runs++
+		_, ok := HandlerAttemptFromContext(ctx)
+		assert.False(t, ok)
+		return nil, conflict
+	})
+	tester.Run(httptesting.Request{Operation: "/api.v1.Orders/Create", Method: nhttp.MethodPost}).AssertError(nhttp.StatusConflict, "OPTIMISTIC_LOCK_CONFLICT")
+	tester.Run(httptesting.Request{Operation: "/api.v1.Users/Get"}).AssertError(nhttp.StatusConflict, "OPTIMISTIC_LOCK_CONFLICT")
+	assert.Equal(t, 2, runs)
+}
+
+func TestHandlerRetryMiddleware_Deadline(t *testing.T) {
+	h := NewServiceHttp()
+	p := newHandlerRetryPolicy(&conf.HandlerRetryConfig{
+		Enabled:        true,
+		Operations:     []string{"/api.v1.Orders/Get"},
+		Codes:          []int32{nhttp.StatusConflict},
+		MaxAttempts:    5,
+		InitialBackoff: durationpb.New(time.Second),
+		MaxBackoff:     durationpb.New(time.Second),
+	})
+	withTimeout := func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+			defer cancel()
+			return handler(ctx, req)
+		}
+	}
+	runs := 0
+	start := time.Now()
+	httptesting.NewMiddlewareTester(t, withTimeout, h.handlerRetryMiddleware(p)).
+		WithHandler(func(context.Context, any) (any, error) {
+			runs++
+			return nil, kerrors.Conflict("VERSION_CONFLICT", "version changed")
+		}).
+		Run(httptesting.Request{Operation: "/api.v1.Orders/Get"}).AssertError(nhttp.StatusConflict, "VERSION_CONFLICT")
+	assert.Equal(t, 1, runs, "a retry that cannot finish before the deadline is not started")
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestValidateHandlerRetryConfig(t *testing.T) {
+	valid := func() *conf.HandlerRetryConfig {
+		return &conf.HandlerRetryConfig{Enabled: true, Operations: []string{"/api.v1.Orders/*"}, Reasons: []string{"CONFLICT"}}
+	}
+	require.NoError(t, validateHandlerRetryConfig(nil))
+	require.NoError(t, validateHandlerRetryConfig(valid()))
+	assert.Equal(t, defaultHandlerRetryAttempts, newHandlerRetryPolicy(valid()).maxAttempts)
+
+	cfg := valid()
+	cfg.Operations = nil
+	assert.ErrorContains(t, validateHandlerRetryConfig(cfg), "operations is required")
+	assert.Nil(t, newHandlerRetryPolicy(cfg))
+	cfg = valid()
+	cfg.Reasons = nil
+	assert.ErrorContains(t, validateHandlerRetryConfig(cfg), "reasons or codes")
+	cfg = valid()
+	cfg.Methods = []string{"get", "POST"}
+	assert.ErrorContains(t, validateHandlerRetryConfig(cfg), `"POST" is not idempotent`)
+	cfg = valid()
+	cfg.MaxAttempts = 11
+	assert.ErrorContains(t, validateHandlerRetryConfig(cfg), "max_attempts")
+	cfg = valid()
+	cfg.InitialBackoff = durationpb.New(time.Second)
+	assert.ErrorContains(t, validateHandlerRetryConfig(cfg), "max_backoff")
+}
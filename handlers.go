@@ -28,71 +28,48 @@ const BodyCodeSystemFailure = 500
 // responseBodyCodeFromError 与 enhancedErrorEncoder 共用：决定写入 body 的 code 数字（供熔断器等同源判断）。
 func (h *ServiceHttp) responseBodyCodeFromError(err error) int {
 	se := errors.FromError(err)
+	// Upgrade-required errors carry the configured code, which application mappers must not remap.
+	if se != nil && se.Reason == UpgradeRequiredReason {
+		return int(se.Code)
+	}
+	code := defaultErrorCode(se)
 	if h.ErrorCodeMapper != nil {
-		return h.ErrorCodeMapper(se)
+		code = h.ErrorCodeMapper(se)
+	}
+	if code == successCode(h.conf.GetResponse().GetCodes()) {
+		// An error must never read as success to clients.
+		log.Errorf("Error %v maps to the success code %d; answering %d", err, code, BodyCodeSystemFailure)
+		return BodyCodeSystemFailure
 	}
-	return defaultErrorCode(se)
+	return code
 }
 
-// notFoundHandler returns a 404 handler.
+// notFoundHandler returns a 404 handler. The response comes from SetNotFoundHandler or routing.not_found,
+// otherwise it is {"code":404}: only the code, not a message, to avoid exposing information to the frontend.
 func (h *ServiceHttp) notFoundHandler() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-
-		// Only return code, not message, to avoid exposing sensitive information to frontend
-		response := map[string]any{
-			"code": 404,
-			// Message field removed for security reasons
-		}
+		h.recordUnmatchedRequest(r, http.StatusNotFound, "not_found")
 
-		if data, err := json.Marshal(response); err == nil {
-			_, writeErr := w.Write(data)
-			if writeErr != nil {
-				return
-			}
-		} else {
-			log.Errorf("Failed to marshal 404 response: %v", err)
-			_, writeErr := w.Write([]byte(`{"code": 404}`))
-			if writeErr != nil {
-				return
-			}
+		if override, _ := h.fallbackHandlers(); override != nil {
+			override.ServeHTTP(w, r)
+			return
 		}
-
-		h.recordErrorMetric(r.Method, r.URL.Path, "not_found")
-
-		log.Warnf("404 not found: %s %s", r.Method, r.URL.Path)
+		writeFallbackResponse(w, r, h.routingConfig().GetNotFound(), http.StatusNotFound)
 	})
 }
 
-// methodNotAllowedHandler returns a 405 handler.
+// methodNotAllowedHandler returns a 405 handler. The response comes from SetMethodNotAllowedHandler or
+// routing.method_not_allowed, otherwise it is {"code":405}; the Allow header is set either way.
 func (h *ServiceHttp) methodNotAllowedHandler() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusMethodNotAllowed)
+		h.recordUnmatchedRequest(r, http.StatusMethodNotAllowed, "method_not_allowed")
 
-		// Only return code, not message, to avoid exposing sensitive information to frontend
-		response := map[string]any{
-			"code": 405,
-			// Message field removed for security reasons
+		h.setAllowHeader(w, r)
+		if _, override := h.fallbackHandlers(); override != nil {
+			override.ServeHTTP(w, r)
+			return
 		}
-
-		if data, err := json.Marshal(response); err == nil {
-			_, writeErr := w.Write(data)
-			if writeErr != nil {
-				return
-			}
-		} else {
-			log.Errorf("Failed to marshal 405 response: %v", err)
-			_, writeErr := w.Write([]byte(`{"code": 405}`))
-			if writeErr != nil {
-				return
-			}
-		}
-
-		h.recordErrorMetric(r.Method, r.URL.Path, "method_not_allowed")
-
-		log.Warnf("405 method not allowed: %s %s", r.Method, r.URL.Path)
+		writeFallbackResponse(w, r, h.routingConfig().GetMethodNotAllowed(), http.StatusMethodNotAllowed)
 	})
 }
 
@@ -111,11 +88,35 @@ func (h *ServiceHttp) enhancedErrorEncoder(w http.ResponseWriter, r *http.Reques
 	if bodyCode == BodyCodeSystemFailure {
 		kind = "server_error"
 	}
-	h.recordErrorMetric(r.Method, r.URL.Path, kind)
+	if !h.routeExcluded(r.Context()) {
+		h.recordErrorMetric(r.Method, r.URL.Path, h.errorMetricType(err, kind))
+		h.recordDependencyError(r.Context(), err)
+	}
+	setServerTimingHeader(r, w.Header())
+
+	if h.jsonAPIEnabled() && acceptsJSONAPI(r) {
+		writeJSONAPIError(w, httpStatus, errors.FromError(err), bodyCode)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(httpStatus)
 	response := map[string]any{"code": bodyCode}
+	se := errors.FromError(err)
+	if upgrade := upgradeRequiredData(se); upgrade != nil {
+		response["data"] = upgrade
+	} else if region := wrongRegionData(se); region != nil {
+		response["data"] = region
+	} else if query := invalidQueryParameterData(se); query != nil {
+		response["data"] = query
+	} else if h.conf.GetRequest().GetDecodeErrors().GetExposeDetail() {
+		if detail := decodeErrorData(se); detail != nil {
+			response["data"] = detail
+		}
+	}
+	if chain := h.exposedErrorChain(err); chain != nil {
+		response["error_chain"] = chain
+	}
 	data, marshalErr := json.Marshal(response)
 	if marshalErr != nil {
 		log.Errorf("Failed to encode error response: %v", marshalErr)
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/conf"
+)
+
+const redactedHeaderValue = "<redacted>"
+
+// headerLogFilter selects and redacts the headers written to request logs. Keys are lower-cased.
+type headerLogFilter struct {
+	// allow is nil when every header is logged.
+	allow  map[string]struct{}
+	deny   map[string]struct{}
+	redact map[string]struct{}
+}
+
+// defaultHeaderLogFilter logs every header and redacts the built-in sensitive ones.
+var defaultHeaderLogFilter = headerLogFilter{redact: sensitiveHeaderKeys}
+
+// headerLogFilterFromConfig resolves header_logging for the monitoring snapshot.
+func headerLogFilterFromConfig(cfg *conf.HeaderLoggingConfig) headerLogFilter {
+	if cfg == nil {
+		return defaultHeaderLogFilter
+	}
+	f := headerLogFilter{
+		allow:  headerNameSet(cfg.Allow),
+		deny:   headerNameSet(cfg.Deny),
+		redact: make(map[string]struct{}, len(sensitiveHeaderKeys)+len(cfg.Redact)),
+	}
+	for key := range sensitiveHeaderKeys {
+		f.redact[key] = struct{}{}
+	}
+	for key := range headerNameSet(cfg.Redact) {
+		f.redact[key] = struct{}{}
+	}
+	return f
+}
+
+func headerNameSet(names []string) map[string]struct{} {
+	if len(names) == 0 {
+		return nil
+	}
+	set := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		set[strings.ToLower(strings.TrimSpace(name))] = struct{}{}
+	}
+	return set
+}
+
+// sanitize returns the headers to log, applying the allow and deny lists before redaction.
+func (f headerLogFilter) sanitize(header transport.Header) map[string]string {
+	if header == nil {
+		return nil
+	}
+	headers := make(map[string]string, len(header.Keys()))
+	for _, key := range header.Keys() {
+		lower := strings.ToLower(key)
+		if f.allow != nil {
+			if _, ok := f.allow[lower]; !ok {
+				continue
+			}
+		}
+		if _, denied := f.deny[lower]; denied {
+			continue
+		}
+		if _, sensitive := f.redact[lower]; sensitive {
+			headers[key] = redactedHeaderValue
+			continue
+		}
+		headers[key] = header.Get(key)
+	}
+	return headers
+}
+
+// logHeaders returns the headers of a request or reply as configured for request logs.
+func logHeaders(service *ServiceHttp, header transport.Header) map[string]string {
+	if service == nil {
+		return sanitizeHeaders(header)
+	}
+	return service.monitoringSnapshotOrDefault().headerLog.sanitize(header)
+}
+
+// validateHeaderLoggingConfig rejects empty header names.
+func validateHeaderLoggingConfig(cfg *conf.HeaderLoggingConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	for list, names := range map[string][]string{"allow": cfg.Allow, "deny": cfg.Deny, "redact": cfg.Redact} {
+		for _, name := range names {
+			if strings.TrimSpace(name) == "" {
+				return fmt.Errorf("%s list contains an empty header name", list)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+)
+
+func headerLoggingSample() *fakeHeader {
+	return newFakeHeader(map[string]string{
+		"Authorization": "Bearer token",
+		"Content-Type":  "application/json",
+		"User-Agent":    "curl/8.0",
+		"X-Request-Id":  "req-1",
+		"X-Session":     "s3cr3t",
+		"Accept":        "*/*",
+	})
+}
+
+func TestHeaderLogFilter_Default(t *testing.T) {
+	headers := headerLogFilterFromConfig(nil).sanitize(headerLoggingSample())
+	assert.Len(t, headers, 6)
+	assert.Equal(t, redactedHeaderValue, headers["Authorization"])
+	assert.Equal(t, "s3cr3t", headers["X-Session"])
+}
+
+func TestHeaderLogFilter_AllowDenyRedact(t *testing.T) {
+	f := headerLogFilterFromConfig(&conf.HeaderLoggingConfig{
+		Allow:  []string{"user-agent", "Content-Type", "X-REQUEST-ID", "Authorization", "X-Session"},
+		Deny:   []string{"content-type"},
+		Redact: []string{"x-session"},
+	})
+	assert.Equal(t, map[string]string{
+		"User-Agent":    "curl/8.0",
+		"X-Request-Id":  "req-1",
+		"Authorization": redactedHeaderValue,
+		"X-Session":     redactedHeaderValue,
+	}, f.sanitize(headerLoggingSample()))
+
+	denyOnly := headerLogFilterFromConfig(&conf.HeaderLoggingConfig{Deny: []string{"Accept", "X-Session"}})
+	headers := denyOnly.sanitize(headerLoggingSample())
+	assert.Len(t, headers, 4)
+	assert.NotContains(t, headers, "Accept")
+	assert.Equal(t, redactedHeaderValue, headers["Authorization"])
+}
+
+func TestLogHeaders_UsesSnapshot(t *testing.T) {
+	assert.Len(t, logHeaders(nil, headerLoggingSample()), 6)
+
+	svc := NewServiceHttp()
+	svc.conf = &conf.Http{Monitoring: &conf.MonitoringConfig{
+		HeaderLogging: &conf.HeaderLoggingConfig{Allow: []string{"X-Request-Id"}},
+	}}
+	svc.refreshMonitoringSnapshotLocked()
+	assert.Equal(t, map[string]string{"X-Request-Id": "req-1"}, logHeaders(svc, headerLoggingSample()))
+}
+
+func TestValidateHeaderLoggingConfig(t *testing.T) {
+	assert.NoError(t, validateHeaderLoggingConfig(nil))
+	assert.NoError(t, validateHeaderLoggingConfig(&conf.HeaderLoggingConfig{Allow: []string{"User-Agent"}}))
+	assert.Error(t, validateHeaderLoggingConfig(&conf.HeaderLoggingConfig{Deny: []string{" "}}))
+}
@@ -0,0 +1,121 @@
+package http
+
+import (
+	"fmt"
+	nhttp "net/http"
+	"strings"
+
+	"github.com/go-lynx/lynx-http/conf"
+)
+
+// defaultScrubbedHeaders reveal the server software, which only helps attackers pick exploits.
+var defaultScrubbedHeaders = []string{"Server", "X-Powered-By", "X-AspNet-Version", "X-AspNetMvc-Version"}
+
+// headerScrubPolicy removes and rewrites response headers.
+type headerScrubPolicy struct {
+	// remove holds lower-cased names; names ending in "*" match a prefix.
+	remove []string
+	// rewrite maps canonical header names to their new value.
+	rewrite map[string]string
+}
+
+// newHeaderScrubPolicy returns nil when header scrubbing is disabled.
+func newHeaderScrubPolicy(cfg *conf.HeaderScrubbingConfig) *headerScrubPolicy {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	p := &headerScrubPolicy{rewrite: make(map[string]string, len(cfg.Rewrite))}
+	remove := cfg.Remove
+	if len(remove) == 0 {
+		remove = defaultScrubbedHeaders
+	}
+	for _, name := range remove {
+		p.remove = append(p.remove, strings.ToLower(strings.TrimSpace(name)))
+	}
+	for name, value := range cfg.Rewrite {
+		p.rewrite[nhttp.CanonicalHeaderKey(strings.TrimSpace(name))] = value
+	}
+	return p
+}
+
+// scrub applies the policy to header in place.
+func (p *headerScrubPolicy) scrub(header nhttp.Header) {
+	for name := range header {
+		if value, ok := p.rewrite[nhttp.CanonicalHeaderKey(name)]; ok {
+			header[name] = []string{value}
+			continue
+		}
+		lower := strings.ToLower(name)
+		for _, pattern := range p.remove {
+			if wildcardMatches(pattern, lower) {
+				delete(header, name)
+				break
+			}
+		}
+	}
+}
+
+// filter scrubs the headers of every response as they are sent, so headers set by inner filters, handlers and
+// encoders are covered. It is the outermost filter.
+func (p *headerScrubPolicy) filter(next nhttp.Handler) nhttp.Handler {
+	return nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		next.ServeHTTP(&scrubbingResponseWriter{ResponseWriter: w, policy: p}, r)
+	})
+}
+
+// scrubbingResponseWriter scrubs the headers right before they are written.
+type scrubbingResponseWriter struct {
+	nhttp.ResponseWriter
+	policy      *headerScrubPolicy
+	wroteHeader bool
+}
+
+// WriteHeader scrubs informational responses as well; their headers are sent too.
+func (w *scrubbingResponseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.policy.scrub(w.Header())
+		w.wroteHeader = status >= nhttp.StatusOK
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *scrubbingResponseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.policy.scrub(w.Header())
+		w.wroteHeader = true
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush sends the headers when nothing was written yet, so they are scrubbed first.
+func (w *scrubbingResponseWriter) Flush() {
+	if !w.wroteHeader {
+		w.policy.scrub(w.Header())
+		w.wroteHeader = true
+	}
+	_ = nhttp.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *scrubbingResponseWriter) Unwrap() nhttp.ResponseWriter { return w.ResponseWriter }
+
+// validateHeaderScrubbingConfig rejects empty and malformed header names.
+func validateHeaderScrubbingConfig(cfg *conf.HeaderScrubbingConfig) error {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	for i, name := range cfg.Remove {
+		name = strings.TrimSpace(name)
+		if strings.TrimSuffix(name, "*") == "" {
+			return fmt.Errorf("remove[%d] is empty", i)
+		}
+		if strings.Contains(strings.TrimSuffix(name, "*"), "*") {
+			return fmt.Errorf("remove[%d]: %q has a \"*\" before its end; only a trailing \"*\" is supported", i, name)
+		}
+	}
+	for name := range cfg.Rewrite {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("rewrite has an empty header name")
+		}
+	}
+	return nil
+}
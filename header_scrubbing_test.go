@@ -0,0 +1,94 @@
+package http
+
+import (
+	nhttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderScrubPolicy(t *testing.T) {
+	p := newHeaderScrubPolicy(&conf.HeaderScrubbingConfig{
+		Enabled: true,
+		Remove:  []string{"x-powered-by", "X-Internal-*"},
+		Rewrite: map[string]string{"server": "lynx"},
+	})
+	require.NotNil(t, p)
+	handler := p.filter(nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		w.Header().Set("Server", "nginx/1.25.3")
+		w.Header().Set("X-Powered-By", "Express")
+		w.Header().Set("X-Internal-Upstream", "10.0.3.7:8080")
+		w.Header().Set("X-Request-Id", "abc")
+		_, _ = w.Write([]byte("ok"))
+		// Headers changed after the body are not sent, so they are left alone.
+		w.Header().Set("X-Internal-Late", "1")
+	}))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(nhttp.MethodGet, "/", nil))
+	assert.Equal(t, "lynx", w.Result().Header.Get("Server"))
+	assert.Empty(t, w.Result().Header.Get("X-Powered-By"))
+	assert.Empty(t, w.Result().Header.Get("X-Internal-Upstream"))
+	assert.Equal(t, "abc", w.Result().Header.Get("X-Request-Id"))
+
+	// Rewrites only apply to headers that are present.
+	w = httptest.NewRecorder()
+	p.filter(nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		w.WriteHeader(nhttp.StatusNoContent)
+	})).ServeHTTP(w, httptest.NewRequest(nhttp.MethodGet, "/", nil))
+	assert.Empty(t, w.Result().Header.Values("Server"))
+
+	defaults := newHeaderScrubPolicy(&conf.HeaderScrubbingConfig{Enabled: true})
+	header := nhttp.Header{"Server": {"Apache"}, "X-Aspnet-Version": {"4.0"}, "Content-Type": {"text/plain"}}
+	defaults.scrub(header)
+	assert.Equal(t, nhttp.Header{"Content-Type": {"text/plain"}}, header)
+
+	assert.Nil(t, newHeaderScrubPolicy(&conf.HeaderScrubbingConfig{Remove: []string{"Server"}}))
+}
+
+func TestHeaderScrubbing_TestService(t *testing.T) {
+	svc, err := NewTestService(&conf.Http{Response: &conf.ResponseConfig{
+		HeaderScrubbing: &conf.HeaderScrubbingConfig{Enabled: true, Remove: []string{"X-Upstream-*"}},
+	}})
+	require.NoError(t, err)
+	defer func() { _ = svc.Close() }()
+	svc.Server().Route("/").GET("/proxied", func(ctx http.Context) error {
+		ctx.Response().Header().Set("X-Upstream-Host", "orders-7f9c.internal")
+		return ctx.Result(nhttp.StatusOK, map[string]string{"ok": "yes"})
+	})
+	svc.Server().Route("/").GET("/failing", func(ctx http.Context) error {
+		ctx.Response().Header().Set("X-Upstream-Host", "orders-7f9c.internal")
+		return kerrors.ServiceUnavailable("UPSTREAM_DOWN", "")
+	})
+
+	res, err := svc.Get("/proxied")
+	require.NoError(t, err)
+	assert.Equal(t, nhttp.StatusOK, res.StatusCode)
+	assert.Empty(t, res.Header.Get("X-Upstream-Host"))
+
+	// Error replies and HEAD answers are scrubbed too.
+	req, err := nhttp.NewRequest(nhttp.MethodGet, svc.URL()+"/failing", nil)
+	require.NoError(t, err)
+	failed, err := svc.Do(req)
+	require.NoError(t, err)
+	assert.Empty(t, failed.Header.Get("X-Upstream-Host"))
+	req, err = nhttp.NewRequest(nhttp.MethodHead, svc.URL()+"/proxied", nil)
+	require.NoError(t, err)
+	head, err := svc.Do(req)
+	require.NoError(t, err)
+	assert.Equal(t, nhttp.StatusOK, head.StatusCode)
+	assert.Empty(t, head.Header.Get("X-Upstream-Host"))
+}
+
+func TestValidateHeaderScrubbingConfig(t *testing.T) {
+	require.NoError(t, validateHeaderScrubbingConfig(nil))
+	require.NoError(t, validateHeaderScrubbingConfig(&conf.HeaderScrubbingConfig{Enabled: true,
+		Remove: []string{"Server", "X-Internal-*"}, Rewrite: map[string]string{"Via": "lynx"}}))
+	assert.Error(t, validateHeaderScrubbingConfig(&conf.HeaderScrubbingConfig{Enabled: true, Remove: []string{"*"}}))
+	assert.Error(t, validateHeaderScrubbingConfig(&conf.HeaderScrubbingConfig{Enabled: true, Remove: []string{"X-*-Id"}}))
+	assert.Error(t, validateHeaderScrubbingConfig(&conf.HeaderScrubbingConfig{Enabled: true, Rewrite: map[string]string{" ": "x"}}))
+}
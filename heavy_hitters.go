@@ -0,0 +1,266 @@
+package http
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"hash/maphash"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/conf"
+)
+
+const (
+	defaultHeavyHittersTopK           = 10
+	defaultHeavyHittersWidth          = 2048
+	defaultHeavyHittersDepth          = 4
+	defaultHeavyHittersIdentityHeader = "X-Caller-Service"
+	maxHeavyHittersTopK               = 1000
+	maxHeavyHittersWidth              = 1 << 20
+	maxHeavyHittersDepth              = 16
+)
+
+// countMinSketch estimates key counts in width x depth counters. Estimates never undercount.
+type countMinSketch struct {
+	width  uint64
+	depth  int
+	counts []uint32
+}
+
+func newCountMinSketch(width, depth int) *countMinSketch {
+	return &countMinSketch{width: uint64(width), depth: depth, counts: make([]uint32, width*depth)}
+}
+
+// cell is the counter of key hash h in row i, with the rows' hashes derived from h as h1 + i*h2.
+func (s *countMinSketch) cell(h uint64, i int) *uint32 {
+	h1, h2 := h&0xffffffff, h>>32|1
+	return &s.counts[uint64(i)*s.width+(h1+uint64(i)*h2)%s.width]
+}
+
+func (s *countMinSketch) estimate(h uint64) uint32 {
+	est := *s.cell(h, 0)
+	for i := 1; i < s.depth; i++ {
+		est = min(est, *s.cell(h, i))
+	}
+	return est
+}
+
+// add counts one occurrence with conservative update, raising only the counters below the new estimate,
+// and returns the new estimate.
+func (s *countMinSketch) add(h uint64) uint32 {
+	est := s.estimate(h) + 1
+	for i := range s.depth {
+		if c := s.cell(h, i); *c < est {
+			*c = est
+		}
+	}
+	return est
+}
+
+// hitterGeneration is the sketch of one stats window with the candidates for its top keys.
+type hitterGeneration struct {
+	sketch     *countMinSketch
+	candidates map[string]uint32
+}
+
+func (g *hitterGeneration) reset() {
+	clear(g.sketch.counts)
+	clear(g.candidates)
+}
+
+// add counts key and keeps it among the k candidates when its estimate beats the smallest one.
+func (g *hitterGeneration) add(key string, h uint64, k int) {
+	est := g.sketch.add(h)
+	if _, ok := g.candidates[key]; ok || len(g.candidates) < k {
+		g.candidates[key] = est
+		return
+	}
+	var (
+		minKey string
+		minEst uint32
+	)
+	for candidate, n := range g.candidates {
+		if minKey == "" || n < minEst {
+			minKey, minEst = candidate, n
+		}
+	}
+	if est > minEst {
+		delete(g.candidates, minKey)
+		g.candidates[key] = est
+	}
+}
+
+// hitterDimension tracks one kind of key over the current and the previous window.
+type hitterDimension struct {
+	cur, prev *hitterGeneration
+}
+
+// heavyHitters tracks the top client IPs, identities and operations of the stats endpoint.
+type heavyHitters struct {
+	topK           int
+	identityHeader string
+	window         time.Duration
+	seed           maphash.Seed
+
+	mu         sync.Mutex
+	epoch      int64
+	clients    hitterDimension
+	identities hitterDimension
+	operations hitterDimension
+}
+
+// newHeavyHitters returns nil when heavy hitter tracking is disabled.
+func newHeavyHitters(cfg *conf.HeavyHittersConfig, window time.Duration) *heavyHitters {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	h := &heavyHitters{
+		topK:           defaultHeavyHittersTopK,
+		identityHeader: defaultHeavyHittersIdentityHeader,
+		window:         window,
+		seed:           maphash.MakeSeed(),
+	}
+	if cfg.TopK > 0 {
+		h.topK = int(cfg.TopK)
+	}
+	if v := strings.TrimSpace(cfg.IdentityHeader); v != "" {
+		h.identityHeader = v
+	}
+	width, depth := defaultHeavyHittersWidth, defaultHeavyHittersDepth
+	if cfg.Width > 0 {
+		width = int(cfg.Width)
+	}
+	if cfg.Depth > 0 {
+		depth = int(cfg.Depth)
+	}
+	for _, d := range []*hitterDimension{&h.clients, &h.identities, &h.operations} {
+		for _, g := range []**hitterGeneration{&d.cur, &d.prev} {
+			*g = &hitterGeneration{sketch: newCountMinSketch(width, depth), candidates: make(map[string]uint32)}
+		}
+	}
+	return h
+}
+
+// rotateLocked starts a new generation when now is in a later window than the current one.
+func (h *heavyHitters) rotateLocked(now time.Time) {
+	epoch := now.UnixNano() / int64(h.window)
+	if epoch == h.epoch {
+		return
+	}
+	for _, d := range []*hitterDimension{&h.clients, &h.identities, &h.operations} {
+		d.cur, d.prev = d.prev, d.cur
+		d.cur.reset()
+		if epoch != h.epoch+1 {
+			d.prev.reset()
+		}
+	}
+	h.epoch = epoch
+}
+
+// observe counts a request of the client, identity and operation; empty keys are not counted.
+func (h *heavyHitters) observe(now time.Time, client, identity, operation string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.rotateLocked(now)
+	for _, o := range []struct {
+		d   *hitterDimension
+		key string
+	}{{&h.clients, client}, {&h.identities, identity}, {&h.operations, operation}} {
+		if o.key != "" {
+			o.d.cur.add(o.key, maphash.String(h.seed, o.key), h.topK)
+		}
+	}
+}
+
+// statsHeavyHitters is the heavy_hitters section of the stats report.
+type statsHeavyHitters struct {
+	Clients    []statsHitter `json:"clients"`
+	Identities []statsHitter `json:"identities"`
+	Operations []statsHitter `json:"operations"`
+}
+
+type statsHitter struct {
+	Key      string `json:"key"`
+	Requests uint64 `json:"requests"`
+}
+
+func (h *heavyHitters) report(now time.Time) *statsHeavyHitters {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.rotateLocked(now)
+	return &statsHeavyHitters{
+		Clients:    h.top(&h.clients),
+		Identities: h.top(&h.identities),
+		Operations: h.top(&h.operations),
+	}
+}
+
+// top ranks the candidates of both generations by their estimated count over both windows.
+func (h *heavyHitters) top(d *hitterDimension) []statsHitter {
+	hitters := make([]statsHitter, 0, len(d.cur.candidates)+len(d.prev.candidates))
+	seen := make(map[string]struct{}, cap(hitters))
+	for _, g := range []*hitterGeneration{d.cur, d.prev} {
+		for key := range g.candidates {
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			hash := maphash.String(h.seed, key)
+			hitters = append(hitters, statsHitter{
+				Key:      key,
+				Requests: uint64(d.cur.sketch.estimate(hash)) + uint64(d.prev.sketch.estimate(hash)),
+			})
+		}
+	}
+	slices.SortFunc(hitters, func(a, b statsHitter) int {
+		if a.Requests != b.Requests {
+			return cmp.Compare(b.Requests, a.Requests)
+		}
+		return strings.Compare(a.Key, b.Key)
+	})
+	return hitters[:min(len(hitters), h.topK)]
+}
+
+// identity is the "sub" claim of the request, otherwise its identity header.
+func (h *heavyHitters) identity(ctx context.Context, header transport.Header) string {
+	if claims, ok := AuthClaimsFromContext(ctx); ok {
+		if sub := claims.Subject(); sub != "" {
+			return sub
+		}
+	}
+	return strings.TrimSpace(header.Get(h.identityHeader))
+}
+
+// countHitters counts a request towards the heavy hitters. It is nil-safe like finish.
+func (s *requestStats) countHitters(ctx context.Context, operation string) {
+	if s == nil || s.hitters == nil {
+		return
+	}
+	tr, ok := transport.FromServerContext(ctx)
+	if !ok {
+		return
+	}
+	client, _ := ClientIPFromContext(ctx)
+	s.hitters.observe(s.now(), client, s.hitters.identity(ctx, tr.RequestHeader()), operation)
+}
+
+// validateHeavyHittersConfig bounds the reported keys and the sketch size.
+func validateHeavyHittersConfig(cfg *conf.HeavyHittersConfig) error {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	if cfg.TopK > maxHeavyHittersTopK {
+		return fmt.Errorf("heavy hitters top_k %d exceeds %d", cfg.TopK, maxHeavyHittersTopK)
+	}
+	if cfg.Width > maxHeavyHittersWidth {
+		return fmt.Errorf("heavy hitters width %d exceeds %d", cfg.Width, maxHeavyHittersWidth)
+	}
+	if cfg.Depth > maxHeavyHittersDepth {
+		return fmt.Errorf("heavy hitters depth %d exceeds %d", cfg.Depth, maxHeavyHittersDepth)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"hash/maphash"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestCountMinSketch(t *testing.T) {
+	s := newCountMinSketch(64, 4)
+	seed := maphash.MakeSeed()
+	for i := range 500 {
+		key := fmt.Sprintf("key-%d", i%50)
+		s.add(maphash.String(seed, key))
+	}
+	for i := range 50 {
+		est := s.estimate(maphash.String(seed, fmt.Sprintf("key-%d", i)))
+		assert.GreaterOrEqual(t, est, uint32(10))
+	}
+	// Estimates never undercount; collisions in a narrow sketch may overcount.
+	assert.GreaterOrEqual(t, s.add(maphash.String(seed, "key-0")), uint32(11))
+
+	wide := newCountMinSketch(1<<16, 4)
+	for range 3 {
+		wide.add(maphash.String(seed, "key-0"))
+	}
+	wide.add(maphash.String(seed, "key-1"))
+	assert.Equal(t, uint32(3), wide.estimate(maphash.String(seed, "key-0")))
+}
+
+func TestHeavyHitters(t *testing.T) {
+	s := newRequestStats(&conf.StatsEndpointConfig{
+		Enabled: true, Window: durationpb.New(10 * time.Second),
+		HeavyHitters: &conf.HeavyHittersConfig{Enabled: true, TopK: 2, IdentityHeader: "X-Api-Key-Id"},
+	})
+	require.NotNil(t, s.hitters)
+	now := time.Unix(1_700_000_000, 0)
+	s.now = func() time.Time { return now }
+
+	request := func(ip, keyID, operation string, claims AuthClaims) {
+		ctx := transport.NewServerContext(context.Background(), newFakeTransport(operation, map[string]string{
+			"X-Real-IP": ip, "X-Api-Key-Id": keyID,
+		}))
+		if claims != nil {
+			ctx = WithAuthClaims(ctx, claims)
+		}
+		s.countHitters(ctx, operation)
+	}
+	for range 30 {
+		request("10.0.0.1", "key-a", "/api.v1.Search/Query", nil)
+	}
+	for range 20 {
+		request("10.0.0.2", "", "/api.v1.Users/Get", AuthClaims{"sub": "user-7"})
+	}
+	for i := range 10 {
+		request(fmt.Sprintf("10.0.1.%d", i), "key-b", "/api.v1.Users/Get", nil)
+	}
+
+	rep := s.report().HeavyHitters
+	require.NotNil(t, rep)
+	assert.Equal(t, []statsHitter{{"10.0.0.1", 30}, {"10.0.0.2", 20}}, rep.Clients)
+	assert.Equal(t, []statsHitter{{"key-a", 30}, {"user-7", 20}}, rep.Identities)
+	assert.Equal(t, []statsHitter{{"/api.v1.Search/Query", 30}, {"/api.v1.Users/Get", 30}}, rep.Operations)
+
+	// Counts cover the current and the previous window.
+	now = now.Add(10 * time.Second)
+	request("10.0.0.2", "", "/api.v1.Users/Get", nil)
+	assert.Equal(t, []statsHitter{{"10.0.0.1", 30}, {"10.0.0.2", 21}}, s.report().HeavyHitters.Clients)
+	now = now.Add(20 * time.Second)
+	assert.Empty(t, s.report().HeavyHitters.Clients)
+
+	// Without heavy hitters the section is left out and counting is a no-op.
+	s = newRequestStats(&conf.StatsEndpointConfig{Enabled: true})
+	assert.NotPanics(t, func() { s.countHitters(context.Background(), "/op") })
+	assert.Nil(t, s.report().HeavyHitters)
+}
+
+func TestValidateHeavyHittersConfig(t *testing.T) {
+	require.NoError(t, validateStatsEndpointConfig(&conf.StatsEndpointConfig{Enabled: true,
+		HeavyHitters: &conf.HeavyHittersConfig{Enabled: true, TopK: 20, Width: 4096, Depth: 5}}))
+	for _, cfg := range []*conf.HeavyHittersConfig{
+		{Enabled: true, TopK: maxHeavyHittersTopK + 1},
+		{Enabled: true, Width: maxHeavyHittersWidth + 1},
+		{Enabled: true, Depth: maxHeavyHittersDepth + 1},
+	} {
+		assert.Error(t, validateStatsEndpointConfig(&conf.StatsEndpointConfig{Enabled: true, HeavyHitters: cfg}))
+	}
+}
@@ -0,0 +1,110 @@
+package http
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	defaultNativeBucketFactor    = 1.1
+	defaultNativeMaxBucketNumber = 160
+)
+
+var (
+	defaultDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+	defaultSizeBuckets     = prometheus.ExponentialBuckets(100, 10, 8)
+)
+
+// histogramOptions holds the resolved bucket layout for the duration and size histograms.
+type histogramOptions struct {
+	durationBuckets       []float64
+	requestSizeBuckets    []float64
+	responseSizeBuckets   []float64
+	nativeHistograms      bool
+	nativeBucketFactor    float64
+	nativeMaxBucketNumber uint32
+}
+
+func defaultHistogramOptions() histogramOptions {
+	return histogramOptions{
+		durationBuckets:     defaultDurationBuckets,
+		requestSizeBuckets:  defaultSizeBuckets,
+		responseSizeBuckets: defaultSizeBuckets,
+	}
+}
+
+// histogramOptionsFromConfig resolves configured buckets, keeping defaults for unset lists.
+func histogramOptionsFromConfig(cfg *conf.HistogramConfig) histogramOptions {
+	opts := defaultHistogramOptions()
+	if cfg == nil {
+		return opts
+	}
+	if len(cfg.DurationBuckets) > 0 {
+		opts.durationBuckets = slices.Clone(cfg.DurationBuckets)
+	}
+	if len(cfg.RequestSizeBuckets) > 0 {
+		opts.requestSizeBuckets = slices.Clone(cfg.RequestSizeBuckets)
+	}
+	if len(cfg.ResponseSizeBuckets) > 0 {
+		opts.responseSizeBuckets = slices.Clone(cfg.ResponseSizeBuckets)
+	}
+	if cfg.NativeHistograms {
+		opts.nativeHistograms = true
+		opts.nativeBucketFactor = defaultNativeBucketFactor
+		opts.nativeMaxBucketNumber = defaultNativeMaxBucketNumber
+		if cfg.NativeBucketFactor > 0 {
+			opts.nativeBucketFactor = cfg.NativeBucketFactor
+		}
+		if cfg.NativeMaxBucketNumber > 0 {
+			opts.nativeMaxBucketNumber = cfg.NativeMaxBucketNumber
+		}
+	}
+	return opts
+}
+
+// histogramOpts returns base with the given classic buckets and, when enabled, native histogram settings.
+func (o histogramOptions) histogramOpts(base prometheus.HistogramOpts, buckets []float64) prometheus.HistogramOpts {
+	base.Buckets = buckets
+	if o.nativeHistograms {
+		base.NativeHistogramBucketFactor = o.nativeBucketFactor
+		base.NativeHistogramMaxBucketNumber = o.nativeMaxBucketNumber
+	}
+	return base
+}
+
+func (o histogramOptions) equal(other histogramOptions) bool {
+	return slices.Equal(o.durationBuckets, other.durationBuckets) &&
+		slices.Equal(o.requestSizeBuckets, other.requestSizeBuckets) &&
+		slices.Equal(o.responseSizeBuckets, other.responseSizeBuckets) &&
+		o.nativeHistograms == other.nativeHistograms &&
+		o.nativeBucketFactor == other.nativeBucketFactor &&
+		o.nativeMaxBucketNumber == other.nativeMaxBucketNumber
+}
+
+// validateHistogramConfig checks that bucket lists are positive and strictly increasing.
+func validateHistogramConfig(cfg *conf.HistogramConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	for name, buckets := range map[string][]float64{
+		"duration":      cfg.DurationBuckets,
+		"request size":  cfg.RequestSizeBuckets,
+		"response size": cfg.ResponseSizeBuckets,
+	} {
+		for i, b := range buckets {
+			if b <= 0 {
+				return fmt.Errorf("%s histogram buckets must be positive, got %v", name, b)
+			}
+			if i > 0 && b <= buckets[i-1] {
+				return fmt.Errorf("%s histogram buckets must be strictly increasing", name)
+			}
+		}
+	}
+	if cfg.NativeBucketFactor != 0 && cfg.NativeBucketFactor <= 1 {
+		return fmt.Errorf("native histogram bucket factor must be greater than 1")
+	}
+	return nil
+}
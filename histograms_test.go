@@ -0,0 +1,42 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogramOptionsFromConfig_Defaults(t *testing.T) {
+	opts := histogramOptionsFromConfig(nil)
+	assert.Equal(t, defaultDurationBuckets, opts.durationBuckets)
+	assert.Equal(t, defaultSizeBuckets, opts.requestSizeBuckets)
+	assert.False(t, opts.nativeHistograms)
+	assert.True(t, opts.equal(defaultHistogramOptions()))
+}
+
+func TestHistogramOptionsFromConfig_CustomAndNative(t *testing.T) {
+	opts := histogramOptionsFromConfig(&conf.HistogramConfig{
+		DurationBuckets:  []float64{0.001, 0.005, 30},
+		NativeHistograms: true,
+	})
+	assert.Equal(t, []float64{0.001, 0.005, 30}, opts.durationBuckets)
+	assert.Equal(t, defaultSizeBuckets, opts.responseSizeBuckets)
+	assert.Equal(t, defaultNativeBucketFactor, opts.nativeBucketFactor)
+	assert.Equal(t, uint32(defaultNativeMaxBucketNumber), opts.nativeMaxBucketNumber)
+
+	ho := opts.histogramOpts(prometheus.HistogramOpts{Name: "x"}, opts.durationBuckets)
+	assert.Equal(t, opts.durationBuckets, ho.Buckets)
+	assert.Equal(t, defaultNativeBucketFactor, ho.NativeHistogramBucketFactor)
+	assert.False(t, opts.equal(defaultHistogramOptions()))
+}
+
+func TestValidateHistogramConfig(t *testing.T) {
+	require.NoError(t, validateHistogramConfig(nil))
+	require.NoError(t, validateHistogramConfig(&conf.HistogramConfig{DurationBuckets: []float64{0.1, 1, 10}}))
+	require.Error(t, validateHistogramConfig(&conf.HistogramConfig{DurationBuckets: []float64{1, 1}}))
+	require.Error(t, validateHistogramConfig(&conf.HistogramConfig{ResponseSizeBuckets: []float64{-5}}))
+	require.Error(t, validateHistogramConfig(&conf.HistogramConfig{NativeBucketFactor: 1}))
+}
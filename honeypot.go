@@ -0,0 +1,267 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	nhttp "net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+const (
+	honeypotActionMonitor = "monitor"
+	honeypotActionBlock   = "block"
+
+	defaultHoneypotFlagDuration = time.Hour
+	defaultHoneypotMaxFlagged   = 10000
+	defaultHoneypotBody         = `{"code":200,"data":{}}`
+	defaultHoneypotContentType  = "application/json"
+)
+
+// HoneypotFlaggedFromContext reports whether the current request's client hit a honeypot route within
+// security.honeypot.flag_duration. Only set in monitor mode; in block mode such requests are rejected.
+func HoneypotFlaggedFromContext(ctx context.Context) bool {
+	flagged, _ := HoneypotFlaggedKey.Value(ctx)
+	return flagged
+}
+
+// honeypotRoute is a decoy route with its fake response.
+type honeypotRoute struct {
+	path        string
+	methods     map[string]struct{}
+	body        []byte
+	contentType string
+}
+
+func (r *honeypotRoute) matches(req *nhttp.Request) bool {
+	if !wildcardMatches(r.path, req.URL.Path) {
+		return false
+	}
+	if len(r.methods) == 0 {
+		return true
+	}
+	_, ok := r.methods[req.Method]
+	return ok
+}
+
+// honeypotPolicy serves the decoy routes and keeps the IPs and fingerprints that hit them.
+type honeypotPolicy struct {
+	routes       []*honeypotRoute
+	block        bool
+	flagDuration time.Duration
+	maxFlagged   int
+	proxies      trustedProxies
+	// guard is slow client protection, whose bans refuse the connections of blocked IPs; nil when disabled.
+	guard *slowClientGuard
+	now   func() time.Time
+
+	mu sync.Mutex
+	// flagged maps "ip:" and "fp:" prefixed keys to the end of their flag.
+	flagged map[string]time.Time
+}
+
+// newHoneypotPolicy returns nil when the honeypot is disabled. Configuration is assumed validated.
+func newHoneypotPolicy(cfg *conf.HoneypotConfig, guard *slowClientGuard) *honeypotPolicy {
+	if !cfg.GetEnabled() || len(cfg.GetRoutes()) == 0 {
+		return nil
+	}
+	p := &honeypotPolicy{
+		block:        strings.EqualFold(strings.TrimSpace(cfg.Action), honeypotActionBlock),
+		flagDuration: durationOrDefault(cfg.FlagDuration.AsDuration(), defaultHoneypotFlagDuration),
+		maxFlagged:   defaultHoneypotMaxFlagged,
+		proxies:      parseTrustedProxies(cfg.TrustedProxies),
+		guard:        guard,
+		now:          time.Now,
+		flagged:      make(map[string]time.Time),
+	}
+	if cfg.MaxFlagged > 0 {
+		p.maxFlagged = int(cfg.MaxFlagged)
+	}
+	for _, rc := range cfg.Routes {
+		r := &honeypotRoute{
+			path:        strings.TrimSpace(rc.Path),
+			body:        []byte(defaultHoneypotBody),
+			contentType: defaultHoneypotContentType,
+		}
+		if rc.Body != "" {
+			r.body = []byte(rc.Body)
+		}
+		if v := strings.TrimSpace(rc.ContentType); v != "" {
+			r.contentType = v
+		}
+		for _, m := range rc.Methods {
+			if r.methods == nil {
+				r.methods = make(map[string]struct{})
+			}
+			r.methods[strings.ToUpper(strings.TrimSpace(m))] = struct{}{}
+		}
+		p.routes = append(p.routes, r)
+	}
+	return p
+}
+
+func (p *honeypotPolicy) action() string {
+	if p.block {
+		return honeypotActionBlock
+	}
+	return honeypotActionMonitor
+}
+
+func (p *honeypotPolicy) route(r *nhttp.Request) *honeypotRoute {
+	for _, route := range p.routes {
+		if route.matches(r) {
+			return route
+		}
+	}
+	return nil
+}
+
+// flag marks ip and fp, when set, for the flag duration. New keys are dropped while the table is full.
+func (p *honeypotPolicy) flag(ip, fp string) {
+	now := p.now()
+	until := now.Add(p.flagDuration)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, key := range []string{honeypotIPKey(ip), honeypotFingerprintKey(fp)} {
+		if key == "" {
+			continue
+		}
+		if _, ok := p.flagged[key]; !ok && len(p.flagged) >= p.maxFlagged {
+			p.pruneLocked(now)
+			if len(p.flagged) >= p.maxFlagged {
+				continue
+			}
+		}
+		p.flagged[key] = until
+	}
+}
+
+// isFlagged reports whether ip or fp is flagged.
+func (p *honeypotPolicy) isFlagged(ip, fp string) bool {
+	now := p.now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, key := range []string{honeypotIPKey(ip), honeypotFingerprintKey(fp)} {
+		until, ok := p.flagged[key]
+		if !ok {
+			continue
+		}
+		if now.Before(until) {
+			return true
+		}
+		delete(p.flagged, key)
+	}
+	return false
+}
+
+func (p *honeypotPolicy) pruneLocked(now time.Time) {
+	for key, until := range p.flagged {
+		if !now.Before(until) {
+			delete(p.flagged, key)
+		}
+	}
+}
+
+func honeypotIPKey(ip string) string {
+	if ip == "" {
+		return ""
+	}
+	return "ip:" + ip
+}
+
+func honeypotFingerprintKey(fp string) string {
+	if fp == "" {
+		return ""
+	}
+	return "fp:" + fp
+}
+
+// honeypotFilter answers decoy routes with their fake response and flags the caller. Requests of flagged callers
+// are rejected with 403 in block mode and marked in their context in monitor mode. It runs after the fingerprint
+// filter so fingerprints are flagged too.
+func (h *ServiceHttp) honeypotFilter(p *honeypotPolicy) func(nhttp.Handler) nhttp.Handler {
+	return func(next nhttp.Handler) nhttp.Handler {
+		return nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+			ip := requestClientIP(r, p.proxies)
+			fp, _ := FingerprintFromContext(r.Context())
+			if route := p.route(r); route != nil {
+				h.honeypotHit(p, route, r, ip, fp)
+				w.Header().Set("Content-Type", route.contentType)
+				w.WriteHeader(nhttp.StatusOK)
+				_, _ = w.Write(route.body)
+				return
+			}
+			if !p.isFlagged(ip, fp) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if p.block {
+				if h.honeypotRejections != nil {
+					h.honeypotRejections.Inc()
+				}
+				nhttp.Error(w, nhttp.StatusText(nhttp.StatusForbidden), nhttp.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(HoneypotFlaggedKey.WithValue(r.Context(), true)))
+		})
+	}
+}
+
+// honeypotHit flags the caller of a decoy route, and in block mode bans its peer address with slow client
+// protection so its new connections are refused. Trusted proxies are not banned, as they carry every client.
+func (h *ServiceHttp) honeypotHit(p *honeypotPolicy, route *honeypotRoute, r *nhttp.Request, ip, fp string) {
+	p.flag(ip, fp)
+	if peer := remoteHost(r.RemoteAddr); p.block && p.guard != nil && !p.proxies.contains(peer) {
+		p.guard.ban(peer, p.flagDuration)
+	}
+	if h.honeypotHits != nil {
+		h.honeypotHits.WithLabelValues(route.path).Inc()
+	}
+	log.WarnwCtx(r.Context(), "msg", "[HTTP Honeypot]",
+		"route", route.path, "method", r.Method, "path", r.URL.Path, "client_ip", ip, "fingerprint", fp,
+		"user_agent", r.UserAgent(), "action", p.action(), "flag_duration", p.flagDuration.String())
+}
+
+// honeypotLogFields are the request log fields of clients flagged by the honeypot, nil otherwise.
+func honeypotLogFields(ctx context.Context) []any {
+	if HoneypotFlaggedFromContext(ctx) {
+		return []any{"honeypot_flagged", true}
+	}
+	return nil
+}
+
+// validateHoneypotConfig requires absolute route paths, a known action and valid trusted proxies.
+func validateHoneypotConfig(cfg *conf.HoneypotConfig) error {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	if len(cfg.Routes) == 0 {
+		return fmt.Errorf("at least one route is required")
+	}
+	for i, r := range cfg.Routes {
+		if !strings.HasPrefix(strings.TrimSpace(r.GetPath()), "/") {
+			return fmt.Errorf("routes[%d]: path %q must start with /", i, r.GetPath())
+		}
+		for j, m := range r.GetMethods() {
+			if strings.TrimSpace(m) == "" {
+				return fmt.Errorf("routes[%d]: methods[%d] is empty", i, j)
+			}
+		}
+	}
+	switch strings.ToLower(strings.TrimSpace(cfg.Action)) {
+	case "", honeypotActionMonitor, honeypotActionBlock:
+	default:
+		return fmt.Errorf("unknown action %q (expected %q or %q)", cfg.Action, honeypotActionMonitor, honeypotActionBlock)
+	}
+	if cfg.FlagDuration != nil && cfg.FlagDuration.AsDuration() < 0 {
+		return fmt.Errorf("flag_duration cannot be negative")
+	}
+	if cfg.MaxFlagged < 0 {
+		return fmt.Errorf("max_flagged cannot be negative")
+	}
+	return validateCIDRs(cfg.TrustedProxies)
+}
@@ -0,0 +1,164 @@
+package http
+
+import (
+	"io"
+	nhttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestHoneypotFilter(t *testing.T) {
+	guard := newSlowClientGuard(&conf.SlowClientProtectionConfig{Enabled: true})
+	p := newHoneypotPolicy(&conf.HoneypotConfig{
+		Enabled: true,
+		Action:  "block",
+		Routes: []*conf.HoneypotRoute{
+			{Path: "/admin/login", Methods: []string{"post"}},
+			{Path: "/wp-*", Body: "<html></html>", ContentType: "text/html"},
+		},
+		FlagDuration: durationpb.New(time.Minute),
+	}, guard)
+	require.NotNil(t, p)
+	now := time.Unix(1_700_000_000, 0)
+	p.now = func() time.Time { return now }
+	guard.now = p.now
+
+	h := &ServiceHttp{}
+	h.honeypotHits = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_honeypot_hits_total"}, []string{"route"})
+	h.honeypotRejections = prometheus.NewCounter(prometheus.CounterOpts{Name: "test_honeypot_rejections_total"})
+	handler := h.honeypotFilter(p)(nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		_, _ = io.WriteString(w, "real")
+	}))
+	serve := func(method, path, ip string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, nil)
+		r.RemoteAddr = ip + ":40000"
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	// Only the configured methods are decoys.
+	assert.Equal(t, "real", serve(nhttp.MethodGet, "/admin/login", "203.0.113.9").Body.String())
+	w := serve(nhttp.MethodPost, "/admin/login", "203.0.113.9")
+	assert.Equal(t, nhttp.StatusOK, w.Code)
+	assert.Equal(t, defaultHoneypotBody, w.Body.String())
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, 1.0, testutil.ToFloat64(h.honeypotHits.WithLabelValues("/admin/login")))
+
+	// The flagged caller is rejected and banned at the connection level; others are served.
+	assert.Equal(t, nhttp.StatusForbidden, serve(nhttp.MethodGet, "/users", "203.0.113.9").Code)
+	assert.Equal(t, 1.0, testutil.ToFloat64(h.honeypotRejections))
+	assert.Equal(t, slowClientReasonBanned, guard.admit(nil, "203.0.113.9"))
+	assert.Equal(t, "real", serve(nhttp.MethodGet, "/users", "198.51.100.1").Body.String())
+	assert.Equal(t, "<html></html>", serve(nhttp.MethodGet, "/wp-login.php", "198.51.100.2").Body.String())
+
+	// Flags expire.
+	now = now.Add(time.Minute)
+	assert.Equal(t, "real", serve(nhttp.MethodGet, "/users", "203.0.113.9").Body.String())
+
+	assert.Nil(t, newHoneypotPolicy(&conf.HoneypotConfig{Routes: []*conf.HoneypotRoute{{Path: "/x"}}}, nil))
+}
+
+func TestHoneypotFilter_TrustedProxies(t *testing.T) {
+	guard := newSlowClientGuard(&conf.SlowClientProtectionConfig{Enabled: true})
+	p := newHoneypotPolicy(&conf.HoneypotConfig{
+		Enabled:        true,
+		Action:         "block",
+		Routes:         []*conf.HoneypotRoute{{Path: "/admin/login"}},
+		TrustedProxies: []string{"10.0.0.0/8"},
+	}, guard)
+	handler := (&ServiceHttp{}).honeypotFilter(p)(nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		_, _ = io.WriteString(w, "real")
+	}))
+	serve := func(path, peer, forwardedFor string) string {
+		r := httptest.NewRequest(nhttp.MethodGet, path, nil)
+		r.RemoteAddr = peer + ":40000"
+		if forwardedFor != "" {
+			r.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Body.String()
+	}
+
+	// A direct client cannot get another IP flagged by forging X-Forwarded-For; its own IP is flagged and banned.
+	serve("/admin/login", "203.0.113.9", "198.51.100.1")
+	assert.Equal(t, "real", serve("/users", "10.0.0.1", "198.51.100.1"))
+	assert.Equal(t, "Forbidden\n", serve("/users", "203.0.113.9", ""))
+	assert.Equal(t, slowClientReasonBanned, guard.admit(nil, "203.0.113.9"))
+
+	// Behind a trusted proxy the forwarded client is flagged and the proxy is not banned.
+	serve("/admin/login", "10.0.0.1", "198.51.100.2, 10.0.0.2")
+	assert.Equal(t, "Forbidden\n", serve("/users", "10.0.0.1", "198.51.100.2"))
+	assert.Equal(t, "real", serve("/users", "10.0.0.1", "198.51.100.3"))
+	assert.Empty(t, guard.admit(nil, "10.0.0.1"))
+}
+
+func TestHoneypotPolicy_Flagging(t *testing.T) {
+	p := newHoneypotPolicy(&conf.HoneypotConfig{Enabled: true, Routes: []*conf.HoneypotRoute{{Path: "/x"}}, MaxFlagged: 2}, nil)
+	now := time.Unix(1_700_000_000, 0)
+	p.now = func() time.Time { return now }
+
+	// Fingerprints are flagged with the IP, so a client moving to another IP stays flagged.
+	p.flag("10.0.0.1", "fp-a")
+	assert.True(t, p.isFlagged("10.0.0.2", "fp-a"))
+	assert.True(t, p.isFlagged("10.0.0.1", ""))
+
+	// A full table drops new keys until flags expire.
+	p.flag("10.0.0.3", "")
+	assert.False(t, p.isFlagged("10.0.0.3", ""))
+	now = now.Add(defaultHoneypotFlagDuration)
+	p.flag("10.0.0.3", "")
+	assert.True(t, p.isFlagged("10.0.0.3", ""))
+	assert.False(t, p.isFlagged("10.0.0.1", "fp-a"))
+}
+
+func TestHoneypot_TestService(t *testing.T) {
+	svc, err := NewTestService(&conf.Http{Security: &conf.SecurityConfig{
+		Fingerprint: &conf.FingerprintConfig{Enabled: true},
+		Honeypot:    &conf.HoneypotConfig{Enabled: true, Routes: []*conf.HoneypotRoute{{Path: "/api/v1/users/export"}}},
+	}})
+	require.NoError(t, err)
+	defer func() { _ = svc.Close() }()
+	svc.Server().Route("/").GET("/me", func(ctx http.Context) error {
+		return ctx.Result(nhttp.StatusOK, map[string]bool{"flagged": HoneypotFlaggedFromContext(ctx)})
+	})
+	flagged := func() bool {
+		res, err := svc.Get("/me")
+		require.NoError(t, err)
+		var out struct {
+			Flagged bool `json:"flagged"`
+		}
+		require.NoError(t, res.Decode(&out))
+		return out.Flagged
+	}
+
+	assert.False(t, flagged())
+	res, err := svc.Get("/api/v1/users/export")
+	require.NoError(t, err)
+	assert.Equal(t, nhttp.StatusOK, res.StatusCode)
+	// Monitor mode serves the caller and marks it.
+	assert.True(t, flagged())
+}
+
+func TestValidateHoneypotConfig(t *testing.T) {
+	require.NoError(t, validateHoneypotConfig(nil))
+	require.NoError(t, validateHoneypotConfig(&conf.HoneypotConfig{Enabled: true, Action: "Block",
+		Routes: []*conf.HoneypotRoute{{Path: "/admin/*", Methods: []string{"GET"}}}}))
+	assert.Error(t, validateHoneypotConfig(&conf.HoneypotConfig{Enabled: true}))
+	assert.Error(t, validateHoneypotConfig(&conf.HoneypotConfig{Enabled: true, Routes: []*conf.HoneypotRoute{{Path: "admin"}}}))
+	assert.Error(t, validateHoneypotConfig(&conf.HoneypotConfig{Enabled: true, Routes: []*conf.HoneypotRoute{{Path: "/a", Methods: []string{""}}}}))
+	assert.Error(t, validateHoneypotConfig(&conf.HoneypotConfig{Enabled: true, Action: "drop", Routes: []*conf.HoneypotRoute{{Path: "/a"}}}))
+	assert.Error(t, validateHoneypotConfig(&conf.HoneypotConfig{Enabled: true, MaxFlagged: -1, Routes: []*conf.HoneypotRoute{{Path: "/a"}}}))
+	assert.Error(t, validateHoneypotConfig(&conf.HoneypotConfig{Enabled: true, TrustedProxies: []string{"proxy"},
+		Routes: []*conf.HoneypotRoute{{Path: "/a"}}}))
+}
@@ -13,12 +13,14 @@ import (
 	"time"
 
 	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
 	"github.com/go-kratos/kratos/v2/transport/http"
 	"github.com/go-lynx/lynx-http/conf"
 	"github.com/go-lynx/lynx/log"
 	"github.com/go-lynx/lynx/observability/metrics"
 	"github.com/go-lynx/lynx/plugins"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/metric"
 	"golang.org/x/time/rate"
 	"google.golang.org/protobuf/types/known/durationpb"
 )
@@ -55,9 +57,86 @@ type ServiceHttp struct {
 	requestQueueLength   *prometheus.GaugeVec
 	routeRequestCounter  *prometheus.CounterVec
 	routeRequestDuration *prometheus.HistogramVec
+	// Per-caller metrics; recorded only when monitoring.caller_metrics is enabled.
+	callerRequestCounter  *prometheus.CounterVec
+	callerRequestDuration *prometheus.HistogramVec
+	// SLO burn-rate gauges; updated only when monitoring.slo is enabled.
+	sloBurnRate        *prometheus.GaugeVec
+	sloBudgetRemaining *prometheus.GaugeVec
+	// Anomaly counter; updated only when monitoring.anomaly is enabled.
+	anomalies *prometheus.CounterVec
+	// Honeypot counters; updated only when security.honeypot is enabled.
+	honeypotHits       *prometheus.CounterVec
+	honeypotRejections prometheus.Counter
+	// Async task counter; updated only when async_tasks is enabled.
+	asyncTaskCounter *prometheus.CounterVec
+	// OpenTelemetry request instruments; nil unless monitoring.otel_metrics is enabled.
+	otelMetrics *otelInstruments
+	// Connection state metrics fed by the net/http ConnState hook.
+	connStateTransitions *prometheus.CounterVec
+	connStateCurrent     *prometheus.GaugeVec
+	// Slow client protection metrics.
+	slowClientProtections *prometheus.CounterVec
+	slowClientBannedIPs   prometheus.Gauge
+	// Admission queue metrics.
+	admissionQueueDepth *prometheus.GaugeVec
+	admissionRejections *prometheus.CounterVec
+	proxyProtocolConns  *prometheus.CounterVec
+	// Retry budget metrics
+	attemptRequestDuration *prometheus.HistogramVec
+	retryBudgetRejections  *prometheus.CounterVec
+	// Request deduplication metrics
+	dedupCollapsed *prometheus.CounterVec
+	// Request coalescing metrics
+	coalescedRequests *prometheus.CounterVec
+	// Handler retry metrics
+	handlerRetries       *prometheus.CounterVec
+	handlerRetryOutcomes *prometheus.CounterVec
+	// Multi-status reply metrics
+	multiStatusReplies *prometheus.CounterVec
+	// Dependency failure attribution metrics
+	dependencyErrors *prometheus.CounterVec
+	// Retry storm metrics
+	retryStorms          *prometheus.CounterVec
+	retryStormRejections *prometheus.CounterVec
+	// GraphQL metrics
+	graphQLOperations       *prometheus.CounterVec
+	graphQLResolverDuration *prometheus.HistogramVec
+	// Log sink metrics
+	logSinkErrors *prometheus.CounterVec
+	// Client platform/app version metrics
+	clientAppRequests *prometheus.CounterVec
+	// Stuck request watchdog metrics
+	stuckRequests *prometheus.CounterVec
+	// Resource guard metrics
+	resourceGuardShed *prometheus.CounterVec
+	podInfo           *prometheus.GaugeVec
+	// Virtual host metrics
+	virtualHostRequests *prometheus.CounterVec
+	// Compression cache metrics
+	compressionCacheRequests *prometheus.CounterVec
+	compressionCacheBytes    prometheus.Gauge
+	// Residency metrics
+	residencyRequests *prometheus.CounterVec
+	// Session metrics
+	sessionEvents  *prometheus.CounterVec
+	sessionsActive prometheus.Gauge
+	// Request cost metrics
+	requestCostUnits *prometheus.CounterVec
+	requestCostTotal *prometheus.CounterVec
+	// Response size limit metrics
+	oversizedResponses *prometheus.CounterVec
+	// Content-Type check metrics
+	unsupportedContentTypes *prometheus.CounterVec
 
 	// Rate limiter
 	rateLimiter *rate.Limiter
+	// Tokens consumed per route; nil consumes one token per request
+	rateLimitCosts *rateLimitCostPolicy
+	// Quota headers on rate limited replies; nil sends none
+	rateLimitHeaders *rateLimitHeadersPolicy
+	// Unix nanoseconds of the last EventRateLimitTripped
+	rateLimitEventAt atomic.Int64
 
 	// Connection timeout configuration
 	idleTimeout       time.Duration
@@ -70,6 +149,7 @@ type ServiceHttp struct {
 	maxConcurrentRequests int
 	readBufferSize        int
 	writeBufferSize       int
+	tcpKeepAlivePeriod    time.Duration
 
 	// Circuit breaker instance (initialized once via circuitBreakerOnce)
 	circuitBreaker     *CircuitBreaker
@@ -78,10 +158,19 @@ type ServiceHttp struct {
 	// Concurrent request limit semaphores (initialized once)
 	connectionSem chan struct{}
 	requestSem    chan struct{}
-	semInitOnce   sync.Once
+	// Per-group admission queues, built lazily from performance.admission_queues and reset by Configure.
+	admissionQueues *admissionQueues
+	semInitOnce     sync.Once
 
 	// Active connection tracking for metrics
 	activeConnectionsCount int32
+	// connStates maps each tracked net.Conn to its last reported state label.
+	connStates sync.Map
+	// slowClientGuard enforces per-IP connection caps, body limits and bans; nil when disabled.
+	slowClientGuard *slowClientGuard
+	// clientHellos maps the remote address of TLS connections to the JA3 hash of their ClientHello, for
+	// security.fingerprint.
+	clientHellos sync.Map
 
 	// Shutdown signal channel
 	shutdownChan chan struct{}
@@ -89,6 +178,17 @@ type ServiceHttp struct {
 	// Whether shutting down (protected by shutdownMu)
 	shutdownMu     sync.RWMutex
 	isShuttingDown bool
+	// Load balancer hint states set by StartDraining (or shutdown) and SetDegraded
+	draining atomic.Bool
+	degraded atomic.Bool
+	// Unix nanoseconds when draining started, so the shutdown drain delay counts time spent draining before
+	drainStartedAt atomic.Int64
+	// Set once startup completes, for the readiness and startup probes
+	startupComplete atomic.Bool
+
+	// Middleware registered per virtual host name with UseVirtualHostMiddleware
+	virtualHostMu          sync.RWMutex
+	virtualHostMiddlewares map[string][]middleware.Middleware
 	// Shutdown timeout
 	shutdownTimeout time.Duration
 	// Context for stopping background goroutines
@@ -98,6 +198,16 @@ type ServiceHttp struct {
 	metricsCancel     context.CancelFunc
 	// closed by the metrics goroutine when it exits; nil when no goroutine is running
 	metricsLoopDone chan struct{}
+	// systemd watchdog keep-alive goroutine; nil when not running
+	watchdogCancel context.CancelFunc
+	watchdogDone   chan struct{}
+	// stuck request watchdog goroutine; nil when not running
+	stuckWatchdogCancel context.CancelFunc
+	stuckWatchdogDone   chan struct{}
+	// resource guard sampling goroutine and the guard it updates; nil when not running
+	resourceGuard       atomic.Pointer[resourceGuard]
+	resourceGuardCancel context.CancelFunc
+	resourceGuardDone   chan struct{}
 	// Port availability check cache to avoid hammering local ports from health probes.
 	// Failures and successes are cached briefly.
 	portCheckCache struct {
@@ -116,6 +226,75 @@ type ServiceHttp struct {
 	// ErrorCodeMapper is an optional hook to map Kratos errors to a response "code" in the JSON body.
 	// When nil, the plugin uses Kratos se.Code or 500. Set by the application for business-specific codes.
 	ErrorCodeMapper func(se *errors.Error) int
+
+	// MeterProvider is an optional OpenTelemetry meter provider used when monitoring.otel_metrics is enabled.
+	// When nil, the global provider from otel.GetMeterProvider() is used.
+	MeterProvider metric.MeterProvider
+
+	// GraphQL is an optional executor for the application's GraphQL schema. When set before start, the
+	// endpoint configured under graphql is mounted behind the server middleware chain.
+	GraphQL GraphQLExecutor
+
+	// JSON-RPC methods registered with RegisterJSONRPCMethod.
+	jsonRPCMu      sync.RWMutex
+	jsonRPCMethods map[string]JSONRPCHandler
+	// Async task kinds registered with RegisterAsyncTask and queues registered with RegisterTaskQueue.
+	asyncTaskMu sync.RWMutex
+	asyncTasks  map[string]AsyncTask
+	taskQueues  map[string]TaskQueue
+	// Operation stores registered with RegisterOperationStore.
+	operationStoreMu sync.RWMutex
+	operationStores  map[string]OperationStore
+
+	// Handlers registered with RegisterHandler; handlersMounted is set once startup registered them.
+	handlerMu       sync.Mutex
+	handlers        []HandlerRegistration
+	handlersMounted bool
+	// pluginLookup replaces the Lynx plugin manager when resolving handler plugins (tests).
+	pluginLookup func(name string) plugins.Plugin
+
+	// Request log sinks registered with RegisterLogSink.
+	logSinkMu sync.RWMutex
+	logSinks  map[string]LogSink
+
+	// Active log boosts added with BoostLogging or from log_boost configuration.
+	logBoosts logBoostSet
+
+	// Session stores registered with RegisterSessionStore, plus the built-in memory store once used.
+	sessionStoreMu sync.RWMutex
+	sessionStores  map[string]SessionStore
+
+	// Tenant resolvers registered with RegisterTenantResolver
+	tenantResolverMu sync.RWMutex
+	tenantResolvers  map[string]TenantResolver
+	// Residency resolvers registered for residency.resolver
+	residencyResolverMu sync.RWMutex
+	residencyResolvers  map[string]ResidencyResolver
+	// Per-service statuses of the gRPC parity health check
+	servingStatusMu sync.RWMutex
+	servingStatus   map[string]bool
+
+	// Response filters registered with RegisterResponseFilter.
+	responseFilterMu sync.RWMutex
+	responseFilters  []registeredResponseFilter
+
+	// Request filters registered with RegisterRequestFilter.
+	requestFilterMu sync.RWMutex
+	requestFilters  []registeredRequestFilter
+
+	// In-flight requests tracked for ActiveRequests and CancelRequest.
+	activeRequests activeRequestSet
+
+	// Routes matched to answer HEAD and OPTIONS requests and fill Allow headers.
+	routes routeTable
+
+	// listener, when set, is served instead of binding the configured address (used by NewTestService).
+	listener net.Listener
+
+	// Handlers set with SetNotFoundHandler and SetMethodNotAllowedHandler.
+	fallbackMu               sync.RWMutex
+	notFoundOverride         nhttp.Handler
+	methodNotAllowedOverride nhttp.Handler
 }
 
 // netHTTPToKratosHandlerAdapter adapts a net/http.Handler to a kratos http.HandlerFunc
@@ -171,8 +350,10 @@ func (h *ServiceHttp) InitializeResources(rt plugins.Runtime) error {
 	h.conf = &conf.Http{}
 
 	if cfg := rt.GetConfig(); cfg != nil {
-		if err := cfg.Value(confPrefix).Scan(h.conf); err != nil {
+		if c, err := loadConfig(cfg.Value(confPrefix)); err != nil {
 			log.Warnf("Failed to load HTTP configuration, using defaults: %v", err)
+		} else {
+			h.conf = c
 		}
 	}
 
@@ -183,8 +364,8 @@ func (h *ServiceHttp) InitializeResources(rt plugins.Runtime) error {
 		return fmt.Errorf("HTTP configuration validation failed: %w", err)
 	}
 
-	log.Infof("HTTP configuration loaded: network=%s, addr=%s, tls=%v",
-		h.conf.Network, h.conf.Addr, h.conf.GetTlsEnable())
+	log.Infof("HTTP configuration loaded: network=%s, addr=%s, tls=%v, profile=%s",
+		h.conf.Network, h.conf.Addr, h.conf.GetTlsEnable(), h.conf.GetProfile())
 	return nil
 }
 
@@ -238,6 +419,9 @@ func (h *ServiceHttp) validateConfigLocked() error {
 	if h.conf == nil {
 		return fmt.Errorf("configuration is nil")
 	}
+	if err := validateProfile(h.conf); err != nil {
+		return fmt.Errorf("invalid profile: %w", err)
+	}
 	validNetworks := []string{"tcp", "tcp4", "tcp6", "unix", "unixpacket"}
 	networkValid := h.conf.Network == ""
 	for _, n := range validNetworks {
@@ -266,7 +450,8 @@ func (h *ServiceHttp) validateConfigLocked() error {
 			if port == "" {
 				return fmt.Errorf("invalid address format (missing port): %s", h.conf.Addr)
 			}
-			if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+			// Port 0 binds an ephemeral port, e.g. in tests.
+			if p, err := strconv.Atoi(port); err != nil || p < 0 || p > 65535 {
 				return fmt.Errorf("invalid port number: %s", port)
 			}
 			_ = host // host may be empty for ":8080" or "[::]:8080"
@@ -283,6 +468,80 @@ func (h *ServiceHttp) validateConfigLocked() error {
 		}
 	}
 
+	// Validate body and header logging, histogram, SLO, stats endpoint, log sink and log boost settings
+	if h.conf.Monitoring != nil {
+		if err := validateBodyLoggingConfig(h.conf.Monitoring.BodyLogging); err != nil {
+			return fmt.Errorf("invalid body logging configuration: %w", err)
+		}
+		if err := validateHistogramConfig(h.conf.Monitoring.Histograms); err != nil {
+			return fmt.Errorf("invalid histogram configuration: %w", err)
+		}
+		if err := validateSLOConfig(h.conf.Monitoring.Slo); err != nil {
+			return fmt.Errorf("invalid SLO configuration: %w", err)
+		}
+		if err := validateStatsEndpointConfig(h.conf.Monitoring.StatsEndpoint); err != nil {
+			return fmt.Errorf("invalid stats endpoint configuration: %w", err)
+		}
+		if err := validateLogSinkConfigs(h.conf.Monitoring.LogSinks); err != nil {
+			return fmt.Errorf("invalid log sink configuration: %w", err)
+		}
+		if err := validateLogBoostConfig(h.conf.Monitoring.LogBoost); err != nil {
+			return fmt.Errorf("invalid log boost configuration: %w", err)
+		}
+		if err := validateHeaderLoggingConfig(h.conf.Monitoring.HeaderLogging); err != nil {
+			return fmt.Errorf("invalid header logging configuration: %w", err)
+		}
+		if err := validateRequestCostConfig(h.conf.Monitoring.RequestCost); err != nil {
+			return fmt.Errorf("invalid request cost configuration: %w", err)
+		}
+		if err := validateStuckRequestsConfig(h.conf.Monitoring.ActiveRequests); err != nil {
+			return fmt.Errorf("invalid stuck requests configuration: %w", err)
+		}
+		if err := validateErrorChainConfig(h.conf.Monitoring.ErrorChain); err != nil {
+			return fmt.Errorf("invalid error chain configuration: %w", err)
+		}
+		if err := validateErrorClassesConfig(h.conf.Monitoring.ErrorClasses); err != nil {
+			return fmt.Errorf("invalid error classes configuration: %w", err)
+		}
+		if err := validateAnomalyConfig(h.conf.Monitoring.Anomaly); err != nil {
+			return fmt.Errorf("invalid anomaly configuration: %w", err)
+		}
+	}
+	if h.conf.Security != nil {
+		if err := validateSlowClientProtectionConfig(h.conf.Security.SlowClientProtection); err != nil {
+			return fmt.Errorf("invalid slow client protection configuration: %w", err)
+		}
+		if err := validateFingerprintConfig(h.conf.Security.Fingerprint); err != nil {
+			return fmt.Errorf("invalid fingerprint configuration: %w", err)
+		}
+		if err := validateHoneypotConfig(h.conf.Security.Honeypot); err != nil {
+			return fmt.Errorf("invalid honeypot configuration: %w", err)
+		}
+	}
+	if h.conf.Middleware != nil {
+		if err := validateDeadlinePropagationConfig(h.conf.Middleware.DeadlinePropagation); err != nil {
+			return fmt.Errorf("invalid deadline propagation configuration: %w", err)
+		}
+		if err := validateRetryBudgetConfig(h.conf.Middleware.RetryBudget); err != nil {
+			return fmt.Errorf("invalid retry budget configuration: %w", err)
+		}
+		if err := validateDedupConfig(h.conf.Middleware.Dedup); err != nil {
+			return fmt.Errorf("invalid dedup configuration: %w", err)
+		}
+		if err := validateCoalesceConfig(h.conf.Middleware.Coalesce); err != nil {
+			return fmt.Errorf("invalid coalesce configuration: %w", err)
+		}
+		if err := validateHandlerRetryConfig(h.conf.Middleware.HandlerRetry); err != nil {
+			return fmt.Errorf("invalid handler retry configuration: %w", err)
+		}
+		if err := validateRetryStormConfig(h.conf.Middleware.RetryStorm); err != nil {
+			return fmt.Errorf("invalid retry storm configuration: %w", err)
+		}
+	}
+	if err := validateProxyProtocolConfig(h.conf.ProxyProtocol); err != nil {
+		return fmt.Errorf("invalid PROXY protocol configuration: %w", err)
+	}
+
 	// Validate request size limit
 	if h.maxRequestSize < 0 {
 		return fmt.Errorf("max request size cannot be negative")
@@ -334,6 +593,20 @@ func (h *ServiceHttp) validateConfigLocked() error {
 	if h.writeBufferSize < 0 {
 		return fmt.Errorf("write buffer size cannot be negative")
 	}
+	if h.conf.Performance != nil {
+		if err := validateAdmissionQueues(h.conf.Performance.AdmissionQueues); err != nil {
+			return fmt.Errorf("invalid admission queue configuration: %w", err)
+		}
+		if err := validateResourceGuardConfig(h.conf.Performance.ResourceGuard, h.conf.Performance.AdmissionQueues); err != nil {
+			return fmt.Errorf("invalid resource guard configuration: %w", err)
+		}
+	}
+	if h.tcpKeepAlivePeriod < 0 {
+		return fmt.Errorf("tcp keep alive period cannot be negative")
+	}
+	if h.conf.Performance != nil && h.conf.Performance.MaxHeaderBytes < 0 {
+		return fmt.Errorf("max header bytes cannot be negative")
+	}
 
 	// Validate circuit breaker configuration
 	if h.conf.CircuitBreaker != nil {
@@ -351,6 +624,97 @@ func (h *ServiceHttp) validateConfigLocked() error {
 		}
 	}
 
+	if err := validateGraphQLConfig(h.conf.Graphql); err != nil {
+		return fmt.Errorf("invalid graphql configuration: %w", err)
+	}
+	if err := validateJSONRPCConfig(h.conf.Jsonrpc); err != nil {
+		return fmt.Errorf("invalid jsonrpc configuration: %w", err)
+	}
+	if err := validateAsyncTasksConfig(h.conf.AsyncTasks); err != nil {
+		return fmt.Errorf("invalid async tasks configuration: %w", err)
+	}
+	if err := validateOperationsConfig(h.conf.Operations); err != nil {
+		return fmt.Errorf("invalid operations configuration: %w", err)
+	}
+	if err := validateBatchConfig(h.conf.Batch); err != nil {
+		return fmt.Errorf("invalid batch configuration: %w", err)
+	}
+	if err := validateRequestConfig(h.conf.Request); err != nil {
+		return fmt.Errorf("invalid request configuration: %w", err)
+	}
+	if err := validateCacheControlConfig(h.conf.GetResponse().GetCacheControl()); err != nil {
+		return fmt.Errorf("invalid cache control configuration: %w", err)
+	}
+	if err := validateFieldEncryptionConfig(h.conf.GetResponse().GetFieldEncryption()); err != nil {
+		return fmt.Errorf("invalid field encryption configuration: %w", err)
+	}
+	if err := validateResponseSigningConfig(h.conf.GetResponse().GetSigning()); err != nil {
+		return fmt.Errorf("invalid response signing configuration: %w", err)
+	}
+	if err := validateCompressionConfig(h.conf.GetResponse().GetCompression()); err != nil {
+		return fmt.Errorf("invalid compression configuration: %w", err)
+	}
+	if err := validateExportConfig(h.conf.GetResponse().GetExport()); err != nil {
+		return fmt.Errorf("invalid export configuration: %w", err)
+	}
+	if err := validateResponseSizeLimitConfig(h.conf.GetResponse().GetSizeLimit()); err != nil {
+		return fmt.Errorf("invalid response size limit configuration: %w", err)
+	}
+	if err := validateEnvelopeCodesConfig(h.conf); err != nil {
+		return fmt.Errorf("invalid envelope codes configuration: %w", err)
+	}
+	if err := validateWarningsConfig(h.conf.GetResponse().GetWarnings()); err != nil {
+		return fmt.Errorf("invalid warnings configuration: %w", err)
+	}
+	if err := validateMultiStatusConfig(h.conf.GetResponse().GetMultiStatus()); err != nil {
+		return fmt.Errorf("invalid multi-status configuration: %w", err)
+	}
+	if err := validateProtoJSONConfig(h.conf.GetResponse().GetProtoJson()); err != nil {
+		return fmt.Errorf("invalid proto JSON configuration: %w", err)
+	}
+	if err := validateHeaderScrubbingConfig(h.conf.GetResponse().GetHeaderScrubbing()); err != nil {
+		return fmt.Errorf("invalid header scrubbing configuration: %w", err)
+	}
+	if err := validateClientInfoConfig(h.conf.ClientInfo); err != nil {
+		return fmt.Errorf("invalid client info configuration: %w", err)
+	}
+	if err := validateSignedURLConfig(h.conf.SignedUrls); err != nil {
+		return fmt.Errorf("invalid signed URL configuration: %w", err)
+	}
+	if err := validateSessionConfig(h.conf.Session); err != nil {
+		return fmt.Errorf("invalid session configuration: %w", err)
+	}
+	if err := validateFallbackResponse(h.conf.GetRouting().GetNotFound()); err != nil {
+		return fmt.Errorf("invalid not found configuration: %w", err)
+	}
+	if err := validateFallbackResponse(h.conf.GetRouting().GetMethodNotAllowed()); err != nil {
+		return fmt.Errorf("invalid method not allowed configuration: %w", err)
+	}
+	if err := validateLoadBalancerHintsConfig(h.conf.LoadBalancerHints); err != nil {
+		return fmt.Errorf("invalid load balancer hints configuration: %w", err)
+	}
+	if err := validateKubernetesConfig(h.conf.Kubernetes); err != nil {
+		return fmt.Errorf("invalid kubernetes configuration: %w", err)
+	}
+	if err := validateVirtualHostsConfig(h.conf.VirtualHosts, h.conf.GetTlsEnable()); err != nil {
+		return fmt.Errorf("invalid virtual hosts configuration: %w", err)
+	}
+	if err := validateTenantConfig(h.conf.Tenant); err != nil {
+		return fmt.Errorf("invalid tenant configuration: %w", err)
+	}
+	if err := validateResidencyConfig(h.conf.Residency); err != nil {
+		return fmt.Errorf("invalid residency configuration: %w", err)
+	}
+	if err := validateGrpcParityConfig(h.conf.GrpcParity); err != nil {
+		return fmt.Errorf("invalid gRPC parity configuration: %w", err)
+	}
+	if err := validateCollectionExportConfig(h.conf.CollectionExport); err != nil {
+		return fmt.Errorf("invalid collection export configuration: %w", err)
+	}
+	if err := validateMockConfig(h.conf.Mock); err != nil {
+		return fmt.Errorf("invalid mock configuration: %w", err)
+	}
+
 	// Validate rate limit configuration
 	if h.rateLimiter != nil {
 		if h.rateLimiter.Limit() <= 0 {
@@ -362,6 +726,12 @@ func (h *ServiceHttp) validateConfigLocked() error {
 		if h.rateLimiter.Limit() > 10000 { // 10k req/s
 			return fmt.Errorf("rate limit cannot exceed 10,000 requests per second")
 		}
+		if err := validateRateLimitCosts(h.conf.GetSecurity().GetRateLimit().GetCosts(), h.rateLimiter.Burst()); err != nil {
+			return fmt.Errorf("invalid rate limit configuration: %w", err)
+		}
+		if err := validateRateLimitHeadersConfig(h.conf.GetSecurity().GetRateLimit().GetHeaders()); err != nil {
+			return fmt.Errorf("invalid rate limit configuration: %w", err)
+		}
 	}
 
 	// Configuration validated successfully
@@ -383,11 +753,14 @@ func (h *ServiceHttp) initSecurityDefaults() {
 	// Rate limiting: from conf.Security.RateLimit if present, else 100 req/s, burst 200
 	ratePerSec := 100
 	burst := 200
+	h.rateLimitCosts, h.rateLimitHeaders = nil, nil
 	if h.conf.Security != nil && h.conf.Security.RateLimit != nil {
 		if !h.conf.Security.RateLimit.Enabled {
 			h.rateLimiter = nil
 			return
 		}
+		h.rateLimitCosts = newRateLimitCostPolicy(h.conf.Security.RateLimit)
+		h.rateLimitHeaders = newRateLimitHeadersPolicy(h.conf.Security.RateLimit.GetHeaders())
 		if r := int(h.conf.Security.RateLimit.GetRatePerSecond()); r > 0 {
 			ratePerSec = r
 		}
@@ -442,6 +815,7 @@ func (h *ServiceHttp) initPerformanceDefaults() {
 	h.maxConcurrentRequests = int(h.conf.Performance.MaxConcurrentRequests)
 	h.readBufferSize = int(h.conf.Performance.ReadBufferSize)
 	h.writeBufferSize = int(h.conf.Performance.WriteBufferSize)
+	h.tcpKeepAlivePeriod = h.conf.Performance.GetTcpKeepAlivePeriod().AsDuration()
 }
 
 // initGracefulShutdownDefaults initializes graceful shutdown defaults from conf when present.
@@ -507,6 +881,12 @@ func (h *ServiceHttp) startupWithContext(ctx context.Context) error {
 		return fmt.Errorf("HTTP configuration not initialized")
 	}
 	h.publishRuntimeContract(false, false)
+	h.draining.Store(false)
+
+	// Report every misconfiguration at once, before anything is initialized or bound
+	if err := h.Preflight(); err != nil {
+		return fmt.Errorf("HTTP preflight checks failed: %w", err)
+	}
 
 	log.Infof("Starting HTTP service on %s", h.conf.Addr)
 
@@ -531,6 +911,7 @@ func (h *ServiceHttp) startupWithContext(ctx context.Context) error {
 
 	// Initialize rate limiter
 	h.initRateLimiter()
+	h.initSlowClientGuard()
 
 	// Build middlewares
 	middlewares := h.buildMiddlewares()
@@ -543,10 +924,67 @@ func (h *ServiceHttp) startupWithContext(ctx context.Context) error {
 		http.NotFoundHandler(h.notFoundHandler()),
 		// 405 Method Not Allowed handler
 		http.MethodNotAllowedHandler(h.methodNotAllowedHandler()),
-		// Success: {"code":200,"data":...}; error: {"code":...} (no data); business code mapping via ErrorCodeMapper
-		http.ResponseEncoder(ResponseEncoder),
+		http.RequestDecoder(h.requestDecoder()),
+		http.RequestQueryDecoder(h.requestQueryDecoder()),
+		// Error: {"code":...} (no data); business code mapping via ErrorCodeMapper
 		http.ErrorEncoder(h.enhancedErrorEncoder),
 	}
+	// http.Filter replaces earlier filters, so they are collected here and passed once, outermost first.
+	var filters []http.FilterFunc
+	if p := newHeaderScrubPolicy(h.conf.GetResponse().GetHeaderScrubbing()); p != nil {
+		// Outermost, so the responses of every other filter are scrubbed too.
+		filters = append(filters, p.filter)
+	}
+	// HEAD and OPTIONS answers derived from the route table
+	filters = append(filters, h.autoMethodsFilter(h.conf.Routing))
+	if h.conf.GetLoadBalancerHints().GetEnabled() {
+		filters = append(filters, h.loadBalancerHintsFilter)
+	}
+	// Success: {"code":200,"data":...}, or response.codes.success_code
+	encode := h.responseEncoder()
+	// List streams: NDJSON written while the handler produces the items
+	stream := http.EncodeResponseFunc(ListStreamEncoder)
+	// Exports: CSV or XLSX rows of list replies, written as they are read
+	exportPolicy := newExportPolicy(h.conf.GetResponse().GetExport())
+	var export http.EncodeResponseFunc
+	if exportPolicy != nil {
+		export = exportPolicy.encode
+	}
+	if policy := newFieldEncryptionPolicy(h.conf.GetResponse().GetFieldEncryption()); policy != nil {
+		// Fields are encrypted in the reply message, before field filtering and encoding.
+		encode = withFieldEncryption(policy, encode)
+		stream = withFieldEncryption(policy, stream)
+		if export != nil {
+			export = withFieldEncryption(policy, export)
+		}
+	}
+	encode = h.withResponseFilters(encode)
+	if policy := newResponseSizePolicy(h.conf.GetResponse().GetSizeLimit()); policy != nil {
+		// The limit applies to the final body, after response filters.
+		encode = h.withResponseSizeLimit(policy, encode)
+	}
+	if policy := newResponseSigningPolicy(h.conf.GetResponse().GetSigning()); policy != nil {
+		// Signatures cover the uncompressed body, which is what clients see after decoding.
+		encode = withResponseSigning(policy, encode)
+	}
+	if policy := newCompressionPolicy(h.conf.GetResponse().GetCompression()); policy != nil {
+		// Size limits apply to the uncompressed body.
+		encode = h.withCompression(policy, encode)
+	}
+	if cfg := h.conf.GetResponse().GetWarnings(); cfg.GetEnabled() {
+		filters = append(filters, warningsFilter(cfg))
+	}
+	if h.conf.GetMonitoring().GetServerTiming().GetEnabled() {
+		// Timings start before routing so the encoders can report them.
+		encode = withServerTiming(encode)
+		filters = append(filters, serverTimingFilter)
+	}
+	// Streams are not buffered, so the filters, size limit, signing, compression and timings above skip them.
+	encode = withListStreams(stream, encode)
+	if exportPolicy != nil {
+		encode = withExports(exportPolicy, export, encode)
+	}
+	opts = append(opts, http.ResponseEncoder(encode))
 
 	// Append additional server options based on configuration
 	if h.conf.Network != "" {
@@ -558,6 +996,65 @@ func (h *ServiceHttp) startupWithContext(ctx context.Context) error {
 	if h.conf.Timeout != nil {
 		opts = append(opts, http.Timeout(h.conf.Timeout.AsDuration()))
 	}
+	lis := h.listener
+	if sd := h.conf.GetSystemd(); lis == nil && sd.GetSocketActivation() {
+		var err error
+		if lis, err = systemdListener(sd.GetListenFdName()); err != nil {
+			return fmt.Errorf("failed to use systemd socket activation: %w", err)
+		}
+		if lis != nil {
+			log.Infof("Serving HTTP on systemd-activated socket %s", lis.Addr())
+		} else {
+			log.Infof("systemd socket activation enabled but no socket was passed; binding %s", h.conf.Addr)
+		}
+	}
+	if h.conf.GetProxyProtocol().GetEnabled() || h.slowClientGuard != nil {
+		// PROXY headers are parsed and header timeouts observed on the listener, so bind it here instead of
+		// leaving it to the server.
+		if lis == nil {
+			network, addr := h.listenConfigSnapshot()
+			if network == "" {
+				network = "tcp"
+			}
+			var err error
+			if lis, err = net.Listen(network, addr); err != nil {
+				return fmt.Errorf("failed to listen on %s: %w", addr, err)
+			}
+		}
+		if h.slowClientGuard != nil {
+			lis = timeoutListener{Listener: lis}
+		}
+		if h.conf.GetProxyProtocol().GetEnabled() {
+			lis = h.wrapProxyProtocol(lis)
+			log.Infof("PROXY protocol enabled on %s", lis.Addr())
+		}
+	}
+	if lis != nil {
+		opts = append(opts, http.Listener(lis))
+		stopMetrics := cleanup
+		cleanup = func() {
+			_ = lis.Close()
+			if stopMetrics != nil {
+				stopMetrics()
+			}
+		}
+	}
+	if h.slowClientGuard != nil {
+		filters = append(filters, h.slowClientGuard.filter)
+	}
+	if f := newFingerprinter(h.conf.GetSecurity().GetFingerprint(), &h.clientHellos); f != nil {
+		filters = append(filters, f.filter)
+	}
+	if p := newHoneypotPolicy(h.conf.GetSecurity().GetHoneypot(), h.slowClientGuard); p != nil {
+		filters = append(filters, h.honeypotFilter(p))
+		log.Infof("Honeypot enabled with %d decoy routes (action %s)", len(p.routes), p.action())
+	}
+	if h.conf.GetRequest().GetPopulateFieldMasks() {
+		filters = append(filters, fieldMaskFilter)
+	}
+	if h.conf.GetRequest().GetCaptureRawBody() {
+		filters = append(filters, rawBodyFilter(rawBodyMaxBytes(h.conf.GetRequest())))
+	}
 	if h.conf.GetTlsEnable() {
 		if err := ctx.Err(); err != nil {
 			return fmt.Errorf("HTTP startup canceled before TLS initialization: %w", err)
@@ -569,6 +1066,8 @@ func (h *ServiceHttp) startupWithContext(ctx context.Context) error {
 		opts = append(opts, tlsOption)
 	}
 
+	opts = append(opts, http.Filter(filters...))
+
 	if err := ctx.Err(); err != nil {
 		return fmt.Errorf("HTTP startup canceled before server creation: %w", err)
 	}
@@ -621,16 +1120,58 @@ func (h *ServiceHttp) startupWithContext(ctx context.Context) error {
 	}
 	// Adapt net/http.Handler to kratos http.HandlerFunc
 	h.server.HandlePrefix(h.healthPath(), &netHTTPToKratosHandlerAdapter{handler: h.healthCheckHandler()})
+	if path := h.statsEndpointPath(); path != "" {
+		h.server.HandlePrefix(path, &netHTTPToKratosHandlerAdapter{handler: h.statsHandler()})
+	}
+	if h.GraphQL != nil {
+		h.mountGraphQL()
+	}
+	if h.conf.GetJsonrpc().GetEnabled() {
+		h.mountJSONRPC()
+	}
+	if h.conf.GetBatch().GetEnabled() {
+		h.mountBatch()
+	}
+	if h.conf.GetAsyncTasks().GetEnabled() {
+		h.mountAsyncTasks()
+	}
+	if h.conf.GetOperations().GetEnabled() {
+		h.mountOperations()
+	}
+	h.mountLogBoost()
+	h.applyConfiguredLogBoosts()
+	h.mountActiveRequests()
+	h.mountKubernetes()
+	h.mountGrpcParity()
+	h.mountCollectionExport()
+	if err := h.mountHandlers(); err != nil {
+		return fmt.Errorf("HTTP handler registration failed: %w", err)
+	}
+	h.warnUnregisteredLogSinks()
+	h.warnUnregisteredSessionStore()
+	h.warnUnregisteredTaskQueue()
+	h.warnUnregisteredOperationStore()
 
 	if err := h.CheckHealth(); err != nil {
 		h.publishRuntimeContract(false, false)
 		return err
 	}
 	h.publishRuntimeContract(true, true)
+	h.startupComplete.Store(true)
 
 	// Startup succeeded; disarm the failure cleanup.
 	cleanup = nil
 
+	h.systemdNotify(fmt.Sprintf("READY=1\nSTATUS=Serving HTTP on %s", h.conf.Addr))
+	h.startSystemdWatchdog(context.WithoutCancel(ctx))
+	h.startStuckRequestWatchdog(context.WithoutCancel(ctx))
+	h.startResourceGuard(context.WithoutCancel(ctx))
+	h.emitEvent(EventServerStarted, plugins.PriorityNormal, "lifecycle", map[string]any{
+		"addr":    h.conf.Addr,
+		"network": h.conf.Network,
+		"tls":     h.conf.GetTlsEnable(),
+	})
+
 	log.Infof("HTTP service successfully started with monitoring endpoints and performance optimizations")
 	return nil
 }
@@ -678,6 +1219,10 @@ func (h *ServiceHttp) applyPerformanceConfig() {
 				log.Infof("Applied ReadHeaderTimeout: %v", v)
 			}
 		}
+		if v := h.conf.Performance.MaxHeaderBytes; v > 0 {
+			httpServer.MaxHeaderBytes = int(v)
+			log.Infof("Applied MaxHeaderBytes: %d", v)
+		}
 
 		// Buffer sizes & max connections require listener-level or middleware control; log intent
 		if h.conf.Performance.ReadBufferSize > 0 {
@@ -687,9 +1232,15 @@ func (h *ServiceHttp) applyPerformanceConfig() {
 			log.Infof("Configured WriteBufferSize: %d bytes (apply via listener/middleware)", h.conf.Performance.WriteBufferSize)
 		}
 		if h.conf.Performance.MaxConnections > 0 {
-			httpServer.SetKeepAlivesEnabled(true)
 			log.Infof("Configured MaxConnections: %d (enforced via accept limit/middleware)", h.conf.Performance.MaxConnections)
 		}
+		httpServer.SetKeepAlivesEnabled(!h.conf.Performance.DisableKeepAlives)
+		if h.conf.Performance.DisableKeepAlives {
+			log.Infof("HTTP keep-alives disabled")
+		}
+		if h.tcpKeepAlivePeriod > 0 {
+			log.Infof("Applied TCP keep-alive period: %v", h.tcpKeepAlivePeriod)
+		}
 		log.Infof("Performance optimizations applied to net/http.Server")
 	}
 
@@ -729,17 +1280,26 @@ func (h *ServiceHttp) applyConnectionLimits() {
 	}
 }
 
-// installConnStateHook configures a ConnState hook to apply TCP buffer settings for each new connection.
+// installConnStateHook configures a ConnState hook to apply TCP settings for each new connection
+// and to record connection state metrics.
 func (h *ServiceHttp) installConnStateHook(httpServer *nhttp.Server) {
 	if httpServer == nil {
 		return
 	}
 
 	prevHook := httpServer.ConnState
+	if h.conf.GetProxyProtocol().GetEnabled() {
+		httpServer.ConnContext = proxyConnContext
+	}
 	httpServer.ConnState = func(conn net.Conn, state nhttp.ConnState) {
 		if state == nhttp.StateNew {
 			h.applyTCPBufferSettings(conn)
 		}
+		h.recordConnState(conn, state)
+		if h.slowClientGuard != nil {
+			h.slowClientGuard.connState(conn, state)
+		}
+		h.forgetClientHello(conn, state)
 		if prevHook != nil {
 			prevHook(conn, state)
 		}
@@ -748,7 +1308,7 @@ func (h *ServiceHttp) installConnStateHook(httpServer *nhttp.Server) {
 
 // applyTCPBufferSettings applies read/write buffer sizes to a TCP connection if configured.
 func (h *ServiceHttp) applyTCPBufferSettings(conn net.Conn) {
-	tcpConn, ok := conn.(*net.TCPConn)
+	tcpConn, ok := acceptedConn(conn).(*net.TCPConn)
 	if !ok {
 		return
 	}
@@ -763,6 +1323,36 @@ func (h *ServiceHttp) applyTCPBufferSettings(conn net.Conn) {
 			log.Warnf("Failed to set TCP write buffer (%d bytes): %v", h.writeBufferSize, err)
 		}
 	}
+	if h.tcpKeepAlivePeriod > 0 {
+		if err := tcpConn.SetKeepAlivePeriod(h.tcpKeepAlivePeriod); err != nil {
+			log.Warnf("Failed to set TCP keep-alive period (%v): %v", h.tcpKeepAlivePeriod, err)
+		}
+	}
+}
+
+// recordConnState updates connection state metrics. Connections are admitted only while connection
+// metrics are enabled, but already tracked ones are always followed to close so the gauge stays balanced.
+func (h *ServiceHttp) recordConnState(conn net.Conn, state nhttp.ConnState) {
+	if h.connStateTransitions == nil || h.connStateCurrent == nil {
+		return
+	}
+	prev, tracked := h.connStates.Load(conn)
+	if !tracked && (state != nhttp.StateNew || !h.connectionMetricsEnabled()) {
+		return
+	}
+
+	label := strings.ToLower(state.String())
+	h.connStateTransitions.WithLabelValues(label).Inc()
+	if tracked {
+		h.connStateCurrent.WithLabelValues(prev.(string)).Dec()
+	}
+	switch state {
+	case nhttp.StateHijacked, nhttp.StateClosed:
+		h.connStates.Delete(conn)
+	default:
+		h.connStates.Store(conn, label)
+		h.connStateCurrent.WithLabelValues(label).Inc()
+	}
 }
 
 // CleanupTasks implements custom cleanup logic for the HTTP plugin.
@@ -777,6 +1367,8 @@ func (h *ServiceHttp) cleanupWithContext(parentCtx context.Context) error {
 	}
 
 	log.Infof("Starting graceful shutdown of HTTP service")
+	// Let load balancers see the draining state before connections are refused.
+	h.drainBeforeStop(parentCtx)
 	h.publishRuntimeContract(false, false)
 
 	h.shutdownMu.Lock()
@@ -787,6 +1379,10 @@ func (h *ServiceHttp) cleanupWithContext(parentCtx context.Context) error {
 		close(h.shutdownChan)
 	})
 
+	h.systemdNotify("STOPPING=1")
+	h.stopSystemdWatchdog()
+	h.stopStuckRequestWatchdog()
+	h.stopResourceGuard()
 	h.stopMetricsLoop()
 
 	ctx, cancel := h.createShutdownContext(parentCtx)
@@ -832,6 +1428,10 @@ func (h *ServiceHttp) Configure(c any) error {
 	if !ok {
 		return fmt.Errorf("invalid configuration type: expected *conf.Http, got %T", c)
 	}
+	httpConf, err := applyProfile(httpConf)
+	if err != nil {
+		return fmt.Errorf("failed to apply configuration profile: %w", err)
+	}
 
 	h.confMu.Lock()
 	oldConf := h.conf
@@ -847,6 +1447,7 @@ func (h *ServiceHttp) Configure(c any) error {
 	}
 	h.connectionSem = nil
 	h.requestSem = nil
+	h.admissionQueues = nil
 	h.circuitBreaker = nil
 	resetOnce(&h.semInitOnce)
 	resetOnce(&h.circuitBreakerOnce)
@@ -0,0 +1,199 @@
+package httptesting
+
+import (
+	"context"
+	"fmt"
+	"io"
+	nhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	kratoslog "github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// Request is a synthetic request run through a middleware chain.
+type Request struct {
+	// Operation is the Kratos operation, e.g. "/api.v1.Users/Get".
+	Operation string
+	// Method defaults to GET.
+	Method string
+	// Path defaults to "/"; it may carry a query string.
+	Path string
+	// PathTemplate is the matched route template, e.g. "/v1/users/{id}".
+	PathTemplate string
+	Header       map[string]string
+	// Body is the raw HTTP body; Message is the decoded request passed to the handler.
+	Body    string
+	Message any
+}
+
+// MiddlewareTester runs a middleware chain against synthetic requests.
+type MiddlewareTester struct {
+	t       testing.TB
+	chain   []middleware.Middleware
+	handler middleware.Handler
+}
+
+// NewMiddlewareTester returns a tester running the middleware in the given order, outermost first. The
+// handler echoes the request message unless replaced with WithHandler.
+func NewMiddlewareTester(t testing.TB, m ...middleware.Middleware) *MiddlewareTester {
+	return &MiddlewareTester{
+		t:     t,
+		chain: m,
+		handler: func(_ context.Context, req any) (any, error) {
+			return req, nil
+		},
+	}
+}
+
+// WithHandler replaces the handler at the end of the chain.
+func (m *MiddlewareTester) WithHandler(h middleware.Handler) *MiddlewareTester {
+	m.handler = h
+	return m
+}
+
+// Run sends req through the chain and returns the outcome.
+func (m *MiddlewareTester) Run(req Request) *Result {
+	m.t.Helper()
+	method := req.Method
+	if method == "" {
+		method = nhttp.MethodGet
+	}
+	path := req.Path
+	if path == "" {
+		path = "/"
+	}
+	var body io.Reader
+	if req.Body != "" {
+		body = strings.NewReader(req.Body)
+	}
+	r := httptest.NewRequest(method, path, body)
+	for k, v := range req.Header {
+		r.Header.Set(k, v)
+	}
+	tr := NewTransport(req.Operation, r).SetPathTemplate(req.PathTemplate)
+
+	res := &Result{t: m.t, Transport: tr}
+	handler := middleware.Chain(m.chain...)(func(ctx context.Context, msg any) (any, error) {
+		res.HandlerCalled = true
+		res.HandlerContext = ctx
+		return m.handler(ctx, msg)
+	})
+	res.Reply, res.Err = handler(transport.NewServerContext(context.Background(), tr), req.Message)
+	return res
+}
+
+// Result is the outcome of a request run by MiddlewareTester.
+type Result struct {
+	t testing.TB
+
+	Reply any
+	Err   error
+	// HandlerCalled reports whether the request reached the handler, and HandlerContext is the context it
+	// received.
+	HandlerCalled  bool
+	HandlerContext context.Context
+	Transport      *Transport
+}
+
+// AssertNoError fails the test if the chain returned an error.
+func (r *Result) AssertNoError() *Result {
+	r.t.Helper()
+	if r.Err != nil {
+		r.t.Errorf("unexpected error: %v", r.Err)
+	}
+	return r
+}
+
+// AssertError fails the test unless the chain returned a Kratos error with the given code and reason.
+func (r *Result) AssertError(code int, reason string) *Result {
+	r.t.Helper()
+	if r.Err == nil {
+		r.t.Errorf("expected error %d %s, got none", code, reason)
+		return r
+	}
+	if got := errors.FromError(r.Err); int(got.Code) != code || got.Reason != reason {
+		r.t.Errorf("expected error %d %s, got %d %s: %v", code, reason, got.Code, got.Reason, r.Err)
+	}
+	return r
+}
+
+// AssertHandlerCalled fails the test unless whether the handler ran matches called.
+func (r *Result) AssertHandlerCalled(called bool) *Result {
+	r.t.Helper()
+	if r.HandlerCalled != called {
+		r.t.Errorf("handler called = %t, want %t", r.HandlerCalled, called)
+	}
+	return r
+}
+
+// AssertReplyHeader fails the test unless the reply header key has the value want; an empty want asserts
+// the header is absent.
+func (r *Result) AssertReplyHeader(key, want string) *Result {
+	r.t.Helper()
+	if got := r.Transport.ReplyHeader().Get(key); got != want {
+		r.t.Errorf("reply header %s = %q, want %q", key, got, want)
+	}
+	return r
+}
+
+// AssertMetric fails the test unless the collector, typically a counter or gauge selected with
+// WithLabelValues, has the value want.
+func AssertMetric(t testing.TB, c prometheus.Collector, want float64) {
+	t.Helper()
+	if got := testutil.ToFloat64(c); got != want {
+		t.Errorf("metric = %v, want %v", got, want)
+	}
+}
+
+// LogRecord is a log entry captured by LogRecorder.
+type LogRecord struct {
+	Level  kratoslog.Level
+	Fields map[string]string
+}
+
+// LogRecorder is a Kratos logger that keeps its records. It can be passed to middleware taking a logger or
+// registered as a log sink of the HTTP plugin.
+type LogRecorder struct {
+	mu      sync.Mutex
+	records []LogRecord
+}
+
+var _ kratoslog.Logger = (*LogRecorder)(nil)
+
+// Log records keyvals; values are kept in their fmt form.
+func (l *LogRecorder) Log(level kratoslog.Level, keyvals ...any) error {
+	rec := LogRecord{Level: level, Fields: make(map[string]string, len(keyvals)/2)}
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		rec.Fields[fmt.Sprint(keyvals[i])] = fmt.Sprint(keyvals[i+1])
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.records = append(l.records, rec)
+	return nil
+}
+
+// Records returns the captured records.
+func (l *LogRecorder) Records() []LogRecord {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]LogRecord(nil), l.records...)
+}
+
+// AssertLogged fails the test unless a record has the field key with the value want.
+func (l *LogRecorder) AssertLogged(t testing.TB, key, want string) {
+	t.Helper()
+	for _, rec := range l.Records() {
+		if v, ok := rec.Fields[key]; ok && v == want {
+			return
+		}
+	}
+	t.Errorf("no log record with %s=%q", key, want)
+}
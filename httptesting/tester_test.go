@@ -0,0 +1,60 @@
+package httptesting
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	kratoslog "github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+// tenantMiddleware is a typical custom middleware: it rejects requests without a tenant header and tags
+// accepted ones in the reply header, the logs and a counter.
+func tenantMiddleware(logger kratoslog.Logger, requests *prometheus.CounterVec) middleware.Middleware {
+	return func(next middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			tr, _ := transport.FromServerContext(ctx)
+			tenant := tr.RequestHeader().Get("X-Tenant")
+			if tenant == "" {
+				return nil, errors.Forbidden("TENANT_REQUIRED", "missing tenant")
+			}
+			r, _ := http.RequestFromServerContext(ctx)
+			_ = logger.Log(kratoslog.LevelInfo, "tenant", tenant, "path", r.URL.Path)
+			requests.WithLabelValues(tenant).Inc()
+			tr.ReplyHeader().Set("X-Tenant", tenant)
+			return next(ctx, req)
+		}
+	}
+}
+
+func TestMiddlewareTester(t *testing.T) {
+	logs := &LogRecorder{}
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "tenant_requests"}, []string{"tenant"})
+	tester := NewMiddlewareTester(t, tenantMiddleware(logs, requests))
+
+	res := tester.Run(Request{Operation: "/api.v1.Users/Get", Path: "/v1/users/7", Header: map[string]string{"x-tenant": "acme"}, Message: "req"})
+	res.AssertNoError().AssertHandlerCalled(true).AssertReplyHeader("X-Tenant", "acme")
+	assert.Equal(t, "req", res.Reply)
+	logs.AssertLogged(t, "path", "/v1/users/7")
+	AssertMetric(t, requests.WithLabelValues("acme"), 1)
+
+	tester.Run(Request{Operation: "/api.v1.Users/Get"}).
+		AssertError(403, "TENANT_REQUIRED").
+		AssertHandlerCalled(false).
+		AssertReplyHeader("X-Tenant", "")
+}
+
+func TestTransport(t *testing.T) {
+	tr := NewTransport("/api.v1.Users/Get", nil).SetPathTemplate("/v1/users/{id}")
+	assert.Equal(t, "/v1/users/{id}", tr.PathTemplate())
+	assert.Equal(t, "GET", tr.Request().Method)
+
+	tr.RequestHeader().Add("x-forwarded-for", "203.0.113.7")
+	assert.Equal(t, "203.0.113.7", tr.Request().Header.Get("X-Forwarded-For"), "the request header backs the transport header")
+	assert.Equal(t, []string{"X-Forwarded-For"}, tr.RequestHeader().Keys())
+}
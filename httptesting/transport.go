@@ -0,0 +1,90 @@
+// Package httptesting provides fakes and a harness for unit-testing Kratos middleware, including the
+// middleware of the Lynx HTTP plugin, without starting a server.
+package httptesting
+
+import (
+	nhttp "net/http"
+	"net/http/httptest"
+
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+)
+
+// Header is a transport.Header backed by an http.Header, so keys are canonicalized like real requests.
+type Header nhttp.Header
+
+var _ transport.Header = Header(nil)
+
+// Get returns the first value of key.
+func (h Header) Get(key string) string { return nhttp.Header(h).Get(key) }
+
+// Set replaces the values of key.
+func (h Header) Set(key, value string) { nhttp.Header(h).Set(key, value) }
+
+// Add appends a value to key.
+func (h Header) Add(key, value string) { nhttp.Header(h).Add(key, value) }
+
+// Keys returns the header names.
+func (h Header) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Values returns all values of key.
+func (h Header) Values(key string) []string { return nhttp.Header(h).Values(key) }
+
+// Transport is a fake server transport. It implements the Kratos http.Transporter, so middleware reading
+// the request or the route template through the transport sees them.
+type Transport struct {
+	operation    string
+	endpoint     string
+	pathTemplate string
+	request      *nhttp.Request
+	replyHeader  Header
+}
+
+var _ http.Transporter = (*Transport)(nil)
+
+// NewTransport returns a transport for the given operation, e.g. "/api.v1.Users/Get", and request. A nil
+// request is replaced by a GET of "/". The request's header is the transport's request header.
+func NewTransport(operation string, r *nhttp.Request) *Transport {
+	if r == nil {
+		r = httptest.NewRequest(nhttp.MethodGet, "/", nil)
+	}
+	return &Transport{
+		operation:   operation,
+		endpoint:    "http://127.0.0.1:8000",
+		request:     r,
+		replyHeader: Header{},
+	}
+}
+
+// SetPathTemplate sets the route template, e.g. "/v1/users/{id}".
+func (t *Transport) SetPathTemplate(tmpl string) *Transport {
+	t.pathTemplate = tmpl
+	return t
+}
+
+// Kind returns transport.KindHTTP.
+func (t *Transport) Kind() transport.Kind { return transport.KindHTTP }
+
+// Endpoint returns the server endpoint.
+func (t *Transport) Endpoint() string { return t.endpoint }
+
+// Operation returns the operation name.
+func (t *Transport) Operation() string { return t.operation }
+
+// RequestHeader returns the request header.
+func (t *Transport) RequestHeader() transport.Header { return Header(t.request.Header) }
+
+// ReplyHeader returns the headers set by middleware for the response.
+func (t *Transport) ReplyHeader() transport.Header { return t.replyHeader }
+
+// Request returns the HTTP request.
+func (t *Transport) Request() *nhttp.Request { return t.request }
+
+// PathTemplate returns the route template.
+func (t *Transport) PathTemplate() string { return t.pathTemplate }
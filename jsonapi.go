@@ -0,0 +1,192 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	nhttp "net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/encoding"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"google.golang.org/protobuf/proto"
+)
+
+// JSONAPIMediaType is the Accept and Content-Type value of JSON:API documents.
+const JSONAPIMediaType = "application/vnd.api+json"
+
+// JSONAPIResource lets a reply choose its JSON:API type and id. Without it the type is the proto message
+// or Go type name and the id is taken from the "id" field.
+type JSONAPIResource interface {
+	JSONAPIType() string
+	JSONAPIID() string
+}
+
+// JSONAPIRelationshipProvider lets a reply expose relationships; fields named by a relationship are left
+// out of the attributes.
+type JSONAPIRelationshipProvider interface {
+	JSONAPIRelationships() map[string]JSONAPIRelationship
+}
+
+// JSONAPIRelationship is a relationship object. Data is a *JSONAPIResourceIdentifier for to-one
+// relationships (nil when empty) or a []JSONAPIResourceIdentifier for to-many relationships.
+type JSONAPIRelationship struct {
+	Data  any               `json:"data"`
+	Links map[string]string `json:"links,omitempty"`
+}
+
+// JSONAPIResourceIdentifier identifies a related resource.
+type JSONAPIResourceIdentifier struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+type jsonAPIResourceObject struct {
+	Type          string                         `json:"type"`
+	ID            string                         `json:"id,omitempty"`
+	Attributes    map[string]json.RawMessage     `json:"attributes,omitempty"`
+	Relationships map[string]JSONAPIRelationship `json:"relationships,omitempty"`
+}
+
+type jsonAPIDocument struct {
+	Data  any               `json:"data"`
+	Meta  any               `json:"meta,omitempty"`
+	Links map[string]string `json:"links,omitempty"`
+}
+
+type jsonAPIErrorObject struct {
+	Status string `json:"status"`
+	Code   string `json:"code"`
+}
+
+type jsonAPIErrorDocument struct {
+	Errors []jsonAPIErrorObject `json:"errors"`
+}
+
+// acceptsJSONAPI reports whether the Accept header lists the JSON:API media type.
+func acceptsJSONAPI(r *nhttp.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		if mt, _, err := mime.ParseMediaType(strings.TrimSpace(part)); err == nil && mt == JSONAPIMediaType {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *ServiceHttp) jsonAPIEnabled() bool {
+	return h.conf.GetResponse().GetEnableJsonapi()
+}
+
+// encodeJSONAPI writes data as a JSON:API document. Slices and Page replies become resource arrays; Page
+// replies also carry their pagination fields in meta and their navigation links in links.
+func encodeJSONAPI(w http.ResponseWriter, r *http.Request, data any) error {
+	doc := &jsonAPIDocument{}
+	var err error
+	if p, ok := data.(pager); ok {
+		info := p.pageInfo()
+		doc.Meta = info
+		for _, l := range pageLinks(r.URL, info) {
+			if doc.Links == nil {
+				doc.Links = make(map[string]string)
+			}
+			doc.Links[l.rel] = l.href
+		}
+		doc.Data, err = jsonAPIResourceList(reflect.ValueOf(p.pageItems()))
+	} else if rv := reflect.ValueOf(data); rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8 {
+		doc.Data, err = jsonAPIResourceList(rv)
+	} else if !shouldOmitSuccessData(data) {
+		doc.Data, err = newJSONAPIResourceObject(data)
+	}
+	if err != nil {
+		return errors.InternalServer("JSONAPI_ENCODE", err.Error())
+	}
+	body, err := json.Marshal(doc)
+	if err != nil {
+		w.WriteHeader(nhttp.StatusInternalServerError)
+		return err
+	}
+	w.Header().Set("Content-Type", JSONAPIMediaType)
+	_, err = w.Write(body)
+	return err
+}
+
+func jsonAPIResourceList(rv reflect.Value) ([]*jsonAPIResourceObject, error) {
+	out := make([]*jsonAPIResourceObject, 0, rv.Len())
+	for i := range rv.Len() {
+		obj, err := newJSONAPIResourceObject(rv.Index(i).Interface())
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+		out = append(out, obj)
+	}
+	return out, nil
+}
+
+// newJSONAPIResourceObject encodes v with the JSON codec and splits the result into type, id, attributes
+// and relationships. The "id" and "type" members never appear in attributes, as the spec reserves them.
+func newJSONAPIResourceObject(v any) (*jsonAPIResourceObject, error) {
+	body, err := encoding.GetCodec("json").Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var attrs map[string]json.RawMessage
+	if err := json.Unmarshal(body, &attrs); err != nil || attrs == nil {
+		return nil, fmt.Errorf("%T does not encode to a JSON object", v)
+	}
+
+	obj := &jsonAPIResourceObject{Type: jsonAPITypeName(v), ID: jsonAPIIDValue(attrs["id"])}
+	if res, ok := v.(JSONAPIResource); ok {
+		obj.Type, obj.ID = res.JSONAPIType(), res.JSONAPIID()
+	}
+	delete(attrs, "id")
+	delete(attrs, "type")
+	if rp, ok := v.(JSONAPIRelationshipProvider); ok {
+		obj.Relationships = rp.JSONAPIRelationships()
+		for name := range obj.Relationships {
+			delete(attrs, name)
+		}
+	}
+	if len(attrs) > 0 {
+		obj.Attributes = attrs
+	}
+	return obj, nil
+}
+
+func jsonAPITypeName(v any) string {
+	if m, ok := v.(proto.Message); ok {
+		return string(m.ProtoReflect().Descriptor().Name())
+	}
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// jsonAPIIDValue renders a JSON id member as a string; numbers keep their literal form.
+func jsonAPIIDValue(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	return string(raw)
+}
+
+// writeJSONAPIError writes an errors document. status is the Kratos code of the error and code the body
+// code, so JSON:API clients see the same values as envelope clients; messages stay out of the response.
+func writeJSONAPIError(w http.ResponseWriter, httpStatus int, se *errors.Error, bodyCode int) {
+	doc := jsonAPIErrorDocument{Errors: []jsonAPIErrorObject{{
+		Status: strconv.Itoa(int(se.Code)),
+		Code:   strconv.Itoa(bodyCode),
+	}}}
+	body, _ := json.Marshal(doc)
+	w.Header().Set("Content-Type", JSONAPIMediaType)
+	w.WriteHeader(httpStatus)
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"encoding/json"
+	nhttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type jsonAPIArticle struct {
+	ID       int    `json:"id"`
+	Title    string `json:"title"`
+	AuthorID string `json:"author"`
+}
+
+func (a jsonAPIArticle) JSONAPIRelationships() map[string]JSONAPIRelationship {
+	return map[string]JSONAPIRelationship{
+		"author": {Data: &JSONAPIResourceIdentifier{Type: "people", ID: a.AuthorID}},
+	}
+}
+
+func jsonAPIRequest(target string) *nhttp.Request {
+	r := httptest.NewRequest(nhttp.MethodGet, target, nil)
+	r.Header.Set("Accept", JSONAPIMediaType)
+	return r
+}
+
+func newJSONAPIService() *ServiceHttp {
+	return &ServiceHttp{conf: &conf.Http{Response: &conf.ResponseConfig{EnableJsonapi: true}}}
+}
+
+func TestAcceptsJSONAPI(t *testing.T) {
+	r := httptest.NewRequest(nhttp.MethodGet, "/", nil)
+	assert.False(t, acceptsJSONAPI(r))
+	r.Header.Set("Accept", "application/json, application/vnd.api+json; ext=bulk")
+	assert.True(t, acceptsJSONAPI(r))
+}
+
+func TestResponseEncoder_JSONAPIResource(t *testing.T) {
+	enc := newJSONAPIService().responseEncoder()
+	w := httptest.NewRecorder()
+	require.NoError(t, enc(w, jsonAPIRequest("/articles/1"), jsonAPIArticle{ID: 1, Title: "Hello", AuthorID: "9"}))
+
+	assert.Equal(t, JSONAPIMediaType, w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"data":{"type":"jsonAPIArticle","id":"1","attributes":{"title":"Hello"},
+		"relationships":{"author":{"data":{"type":"people","id":"9"}}}}}`, w.Body.String())
+
+	// Without the media type the standard envelope is kept.
+	w = httptest.NewRecorder()
+	require.NoError(t, enc(w, httptest.NewRequest(nhttp.MethodGet, "/articles/1", nil), jsonAPIArticle{ID: 1}))
+	assert.Contains(t, w.Body.String(), `"code":200`)
+}
+
+func TestResponseEncoder_JSONAPIProtoAndPage(t *testing.T) {
+	enc := newJSONAPIService().responseEncoder()
+
+	w := httptest.NewRecorder()
+	require.NoError(t, enc(w, jsonAPIRequest("/config"), &conf.ResponseConfig{FieldFilterParam: "f"}))
+	var doc struct {
+		Data jsonAPIResourceObject `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, "ResponseConfig", doc.Data.Type)
+	assert.JSONEq(t, `"f"`, string(doc.Data.Attributes["fieldFilterParam"]))
+
+	w = httptest.NewRecorder()
+	page := NewPage([]jsonAPIArticle{{ID: 1}, {ID: 2}}, PageRequest{Page: 1, PageSize: 2}, 3)
+	require.NoError(t, enc(w, jsonAPIRequest("/articles?page=1&page_size=2"), page))
+	var list struct {
+		Data  []jsonAPIResourceObject `json:"data"`
+		Meta  pageInfo                `json:"meta"`
+		Links map[string]string       `json:"links"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Data, 2)
+	assert.Equal(t, "2", list.Data[1].ID)
+	assert.Equal(t, int64(3), list.Meta.Total)
+	assert.Equal(t, "/articles?page=2&page_size=2", list.Links["next"])
+
+	w = httptest.NewRecorder()
+	require.NoError(t, enc(w, jsonAPIRequest("/articles"), []jsonAPIArticle{}))
+	assert.JSONEq(t, `{"data":[]}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	require.NoError(t, enc(w, jsonAPIRequest("/logout"), nil))
+	assert.JSONEq(t, `{"data":null}`, w.Body.String())
+
+	assert.Error(t, enc(httptest.NewRecorder(), jsonAPIRequest("/count"), 42))
+}
+
+func TestEnhancedErrorEncoder_JSONAPI(t *testing.T) {
+	h := newJSONAPIService()
+	w := httptest.NewRecorder()
+	h.enhancedErrorEncoder(w, jsonAPIRequest("/articles/404"), errors.NotFound("ARTICLE_NOT_FOUND", "secret detail"))
+
+	assert.Equal(t, nhttp.StatusOK, w.Code)
+	assert.Equal(t, JSONAPIMediaType, w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"errors":[{"status":"404","code":"404"}]}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	h.enhancedErrorEncoder(w, jsonAPIRequest("/articles"), errors.InternalServer("BOOM", ""))
+	assert.Equal(t, nhttp.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"errors":[{"status":"500","code":"500"}]}`, w.Body.String())
+}
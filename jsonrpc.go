@@ -0,0 +1,292 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	nhttp "net/http"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/encoding"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+// Error codes reserved by the JSON-RPC 2.0 specification.
+const (
+	JSONRPCParseError     = -32700
+	JSONRPCInvalidRequest = -32600
+	JSONRPCMethodNotFound = -32601
+	JSONRPCInvalidParams  = -32602
+	JSONRPCInternalError  = -32603
+)
+
+const (
+	jsonRPCVersion             = "2.0"
+	defaultJSONRPCPath         = "/jsonrpc"
+	defaultJSONRPCMaxBatchSize = 100
+	defaultJSONRPCMaxBodyBytes = 1 << 20
+
+	// jsonRPCReason is the Kratos error reason of errors rebuilt from reserved JSON-RPC codes.
+	jsonRPCReason = "JSONRPC_ERROR"
+)
+
+// JSONRPCHandler handles one JSON-RPC method. params is the raw params member (nil when absent); the
+// result is encoded with the JSON codec, so proto messages are supported.
+type JSONRPCHandler func(ctx context.Context, params json.RawMessage) (any, error)
+
+// JSONRPCError is a JSON-RPC error object. Handlers may return one to control the code and data.
+type JSONRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+// Error implements error.
+func (e *JSONRPCError) Error() string {
+	return fmt.Sprintf("jsonrpc error %d: %s", e.Code, e.Message)
+}
+
+type jsonRPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	// ID is nil for notifications, which get no response.
+	ID json.RawMessage `json:"id,omitempty"`
+}
+
+type jsonRPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *JSONRPCError   `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+// RegisterJSONRPCMethod registers the handler for a JSON-RPC method. Names starting with "rpc." are
+// reserved by the specification.
+func (h *ServiceHttp) RegisterJSONRPCMethod(name string, handler JSONRPCHandler) error {
+	if name == "" || strings.HasPrefix(name, "rpc.") {
+		return fmt.Errorf("invalid JSON-RPC method name %q", name)
+	}
+	if handler == nil {
+		return fmt.Errorf("JSON-RPC method %q: handler is nil", name)
+	}
+	h.jsonRPCMu.Lock()
+	defer h.jsonRPCMu.Unlock()
+	if _, dup := h.jsonRPCMethods[name]; dup {
+		return fmt.Errorf("JSON-RPC method %q already registered", name)
+	}
+	if h.jsonRPCMethods == nil {
+		h.jsonRPCMethods = make(map[string]JSONRPCHandler)
+	}
+	h.jsonRPCMethods[name] = handler
+	return nil
+}
+
+func (h *ServiceHttp) jsonRPCMethod(name string) (JSONRPCHandler, bool) {
+	h.jsonRPCMu.RLock()
+	defer h.jsonRPCMu.RUnlock()
+	fn, ok := h.jsonRPCMethods[name]
+	return fn, ok
+}
+
+// mountJSONRPC registers the JSON-RPC endpoint on the server.
+func (h *ServiceHttp) mountJSONRPC() {
+	cfg := h.conf.GetJsonrpc()
+	path := strings.TrimSpace(cfg.GetPath())
+	if path == "" {
+		path = defaultJSONRPCPath
+	}
+	maxBatch := int(cfg.GetMaxBatchSize())
+	if maxBatch <= 0 {
+		maxBatch = defaultJSONRPCMaxBatchSize
+	}
+	maxBody := cfg.GetMaxBodyBytes()
+	if maxBody <= 0 {
+		maxBody = defaultJSONRPCMaxBodyBytes
+	}
+	h.server.Route("/").POST(path, h.jsonRPCHandler(path, maxBatch, maxBody))
+	log.Infof("JSON-RPC endpoint mounted at %s", path)
+}
+
+// jsonRPCHandler serves single and batch requests. Batch calls run in order; notifications get no entry
+// in the response, and a request made only of notifications is answered with 204.
+func (h *ServiceHttp) jsonRPCHandler(path string, maxBatch int, maxBody int64) http.HandlerFunc {
+	return func(ctx http.Context) error {
+		body, err := io.ReadAll(nhttp.MaxBytesReader(ctx.Response(), ctx.Request().Body, maxBody))
+		if err != nil {
+			return ctx.JSON(nhttp.StatusOK, jsonRPCErrorResponse(nil, JSONRPCParseError, "Parse error"))
+		}
+		body = bytes.TrimSpace(body)
+		if len(body) == 0 || body[0] != '[' {
+			var req jsonRPCRequest
+			if err := json.Unmarshal(body, &req); err != nil {
+				return ctx.JSON(nhttp.StatusOK, jsonRPCErrorResponse(nil, JSONRPCParseError, "Parse error"))
+			}
+			res := h.callJSONRPC(ctx, path, &req)
+			if res == nil {
+				ctx.Response().WriteHeader(nhttp.StatusNoContent)
+				return nil
+			}
+			return ctx.JSON(nhttp.StatusOK, res)
+		}
+
+		var batch []json.RawMessage
+		if err := json.Unmarshal(body, &batch); err != nil {
+			return ctx.JSON(nhttp.StatusOK, jsonRPCErrorResponse(nil, JSONRPCParseError, "Parse error"))
+		}
+		if len(batch) == 0 || len(batch) > maxBatch {
+			return ctx.JSON(nhttp.StatusOK, jsonRPCErrorResponse(nil, JSONRPCInvalidRequest,
+				fmt.Sprintf("batch must contain between 1 and %d calls", maxBatch)))
+		}
+		out := make([]*jsonRPCResponse, 0, len(batch))
+		for _, raw := range batch {
+			var req jsonRPCRequest
+			if err := json.Unmarshal(raw, &req); err != nil {
+				out = append(out, jsonRPCErrorResponse(nil, JSONRPCInvalidRequest, "Invalid Request"))
+				continue
+			}
+			if res := h.callJSONRPC(ctx, path, &req); res != nil {
+				out = append(out, res)
+			}
+		}
+		if len(out) == 0 {
+			ctx.Response().WriteHeader(nhttp.StatusNoContent)
+			return nil
+		}
+		return ctx.JSON(nhttp.StatusOK, out)
+	}
+}
+
+// callJSONRPC runs one call through the middleware chain under the operation "<path>/<method>" and
+// returns its response, or nil for a notification.
+func (h *ServiceHttp) callJSONRPC(ctx http.Context, path string, req *jsonRPCRequest) *jsonRPCResponse {
+	notify := req.ID == nil
+	respond := func(res *jsonRPCResponse) *jsonRPCResponse {
+		if notify {
+			return nil
+		}
+		return res
+	}
+	if req.JSONRPC != jsonRPCVersion || req.Method == "" || !validJSONRPCID(req.ID) || !validJSONRPCParams(req.Params) {
+		// Invalid requests are answered even without an id, as the spec requires.
+		return jsonRPCErrorResponse(req.ID, JSONRPCInvalidRequest, "Invalid Request")
+	}
+	fn, ok := h.jsonRPCMethod(req.Method)
+	if !ok {
+		return respond(jsonRPCErrorResponse(req.ID, JSONRPCMethodNotFound, "Method not found"))
+	}
+
+	http.SetOperation(ctx, path+"/"+req.Method)
+	result, err := ctx.Middleware(func(c context.Context, _ any) (any, error) {
+		return fn(c, req.Params)
+	})(ctx, req.Params)
+	if err != nil {
+		return respond(&jsonRPCResponse{JSONRPC: jsonRPCVersion, Error: h.jsonRPCErrorFrom(err), ID: req.ID})
+	}
+	data, err := encoding.GetCodec("json").Marshal(result)
+	if err != nil {
+		log.Errorf("Failed to encode JSON-RPC result of %s: %v", req.Method, err)
+		return respond(jsonRPCErrorResponse(req.ID, JSONRPCInternalError, "Internal error"))
+	}
+	return respond(&jsonRPCResponse{JSONRPC: jsonRPCVersion, Result: data, ID: req.ID})
+}
+
+// jsonRPCErrorFrom translates a handler error. JSONRPCError values pass through; Kratos errors use the
+// business code from ErrorCodeMapper, except bad requests and system failures, which map to the reserved
+// invalid params and internal error codes. As with REST responses, messages are not exposed; the Kratos
+// reason is sent as the message and in data.
+func (h *ServiceHttp) jsonRPCErrorFrom(err error) *JSONRPCError {
+	var rpcErr *JSONRPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr
+	}
+	se := errors.FromError(err)
+	code := h.responseBodyCodeFromError(err)
+	switch {
+	case code == BodyCodeSystemFailure:
+		return &JSONRPCError{Code: JSONRPCInternalError, Message: "Internal error"}
+	case code == nhttp.StatusBadRequest:
+		code = JSONRPCInvalidParams
+	}
+	e := &JSONRPCError{Code: code, Message: se.Reason}
+	if se.Reason != "" {
+		e.Data = map[string]string{"reason": se.Reason}
+	} else {
+		e.Message = nhttp.StatusText(int(se.Code))
+	}
+	return e
+}
+
+func jsonRPCErrorResponse(id json.RawMessage, code int, message string) *jsonRPCResponse {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	return &jsonRPCResponse{JSONRPC: jsonRPCVersion, Error: &JSONRPCError{Code: code, Message: message}, ID: id}
+}
+
+// validJSONRPCID accepts absent, null, string and number ids.
+func validJSONRPCID(id json.RawMessage) bool {
+	if id == nil {
+		return true
+	}
+	var v any
+	if err := json.Unmarshal(id, &v); err != nil {
+		return false
+	}
+	switch v.(type) {
+	case nil, string, float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// validJSONRPCParams accepts absent, object and array params.
+func validJSONRPCParams(params json.RawMessage) bool {
+	return len(params) == 0 || params[0] == '{' || params[0] == '['
+}
+
+// ErrorFromJSONRPC rebuilds an error from a JSON-RPC error object on the client side. Business codes go
+// through the WithCodeErrorMapper mapper, as for envelope codes; reserved codes become Kratos errors with the
+// matching status.
+func ErrorFromJSONRPC(e *JSONRPCError, opts ...DecodeOption) error {
+	if e == nil {
+		return nil
+	}
+	switch e.Code {
+	case JSONRPCParseError, JSONRPCInvalidRequest, JSONRPCInvalidParams:
+		return errors.New(nhttp.StatusBadRequest, jsonRPCReason, e.Message)
+	case JSONRPCMethodNotFound:
+		return errors.New(nhttp.StatusNotFound, jsonRPCReason, e.Message)
+	case JSONRPCInternalError:
+		return errors.New(nhttp.StatusInternalServerError, jsonRPCReason, e.Message)
+	}
+	if o := newDecodeOptions(opts); o.mapper != nil {
+		if err := o.mapper.ErrorFromCode(e.Code, e.Message); err != nil {
+			return err
+		}
+	}
+	return errors.New(e.Code, envelopeErrorReason, e.Message)
+}
+
+// validateJSONRPCConfig rejects relative paths and negative limits.
+func validateJSONRPCConfig(cfg *conf.JSONRPCConfig) error {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	if p := strings.TrimSpace(cfg.Path); p != "" && !strings.HasPrefix(p, "/") {
+		return fmt.Errorf("path %q must start with /", p)
+	}
+	if cfg.MaxBatchSize < 0 {
+		return fmt.Errorf("max batch size cannot be negative")
+	}
+	if cfg.MaxBodyBytes < 0 {
+		return fmt.Errorf("max body bytes cannot be negative")
+	}
+	return nil
+}
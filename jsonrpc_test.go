@@ -0,0 +1,154 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	nhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newJSONRPCTestService(t *testing.T) (*ServiceHttp, *[]string) {
+	t.Helper()
+	var ops []string
+	h := &ServiceHttp{conf: &conf.Http{Jsonrpc: &conf.JSONRPCConfig{Enabled: true, MaxBatchSize: 3}}}
+	h.ErrorCodeMapper = func(se *errors.Error) int {
+		if se.Reason == "ACCOUNT_NOT_FOUND" {
+			return 100404
+		}
+		return defaultErrorCode(se)
+	}
+	record := func(next middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			_, op := requestMetadata(ctx)
+			ops = append(ops, op)
+			return next(ctx, req)
+		}
+	}
+	h.server = http.NewServer(http.Middleware(record))
+
+	require.NoError(t, h.RegisterJSONRPCMethod("sum", func(_ context.Context, params json.RawMessage) (any, error) {
+		var nums []int
+		if err := json.Unmarshal(params, &nums); err != nil {
+			return nil, errors.BadRequest("BAD_PARAMS", err.Error())
+		}
+		total := 0
+		for _, n := range nums {
+			total += n
+		}
+		return total, nil
+	}))
+	require.NoError(t, h.RegisterJSONRPCMethod("balance", func(context.Context, json.RawMessage) (any, error) {
+		return nil, errors.NotFound("ACCOUNT_NOT_FOUND", "account 7 does not exist")
+	}))
+	require.NoError(t, h.RegisterJSONRPCMethod("boom", func(context.Context, json.RawMessage) (any, error) {
+		return nil, &JSONRPCError{Code: -32001, Message: "custom", Data: "x"}
+	}))
+	h.mountJSONRPC()
+	return h, &ops
+}
+
+func postJSONRPC(h *ServiceHttp, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(nhttp.MethodPost, "/jsonrpc", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	h.server.ServeHTTP(w, r)
+	return w
+}
+
+func TestRegisterJSONRPCMethod(t *testing.T) {
+	h := &ServiceHttp{}
+	noop := func(context.Context, json.RawMessage) (any, error) { return nil, nil }
+	require.NoError(t, h.RegisterJSONRPCMethod("a", noop))
+	assert.Error(t, h.RegisterJSONRPCMethod("a", noop))
+	assert.Error(t, h.RegisterJSONRPCMethod("rpc.discover", noop))
+	assert.Error(t, h.RegisterJSONRPCMethod("", noop))
+	assert.Error(t, h.RegisterJSONRPCMethod("b", nil))
+}
+
+func TestJSONRPCEndpoint_Single(t *testing.T) {
+	h, ops := newJSONRPCTestService(t)
+
+	w := postJSONRPC(h, `{"jsonrpc":"2.0","method":"sum","params":[1,2,3],"id":1}`)
+	assert.Equal(t, nhttp.StatusOK, w.Code)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","result":6,"id":1}`, w.Body.String())
+	assert.Equal(t, []string{"/jsonrpc/sum"}, *ops)
+
+	w = postJSONRPC(h, `{"jsonrpc":"2.0","method":"balance","id":"a"}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","error":{"code":100404,"message":"ACCOUNT_NOT_FOUND",
+		"data":{"reason":"ACCOUNT_NOT_FOUND"}},"id":"a"}`, w.Body.String())
+
+	w = postJSONRPC(h, `{"jsonrpc":"2.0","method":"sum","params":{"a":1},"id":2}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","error":{"code":-32602,"message":"BAD_PARAMS",
+		"data":{"reason":"BAD_PARAMS"}},"id":2}`, w.Body.String())
+
+	w = postJSONRPC(h, `{"jsonrpc":"2.0","method":"boom","id":3}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","error":{"code":-32001,"message":"custom","data":"x"},"id":3}`, w.Body.String())
+
+	w = postJSONRPC(h, `{"jsonrpc":"2.0","method":"missing","id":4}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","error":{"code":-32601,"message":"Method not found"},"id":4}`, w.Body.String())
+
+	w = postJSONRPC(h, `{"jsonrpc":"1.0","method":"sum","id":5}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request"},"id":5}`, w.Body.String())
+
+	w = postJSONRPC(h, `{"jsonrpc":`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","error":{"code":-32700,"message":"Parse error"},"id":null}`, w.Body.String())
+
+	w = postJSONRPC(h, `{"jsonrpc":"2.0","method":"sum","params":[1]}`)
+	assert.Equal(t, nhttp.StatusNoContent, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestJSONRPCEndpoint_Batch(t *testing.T) {
+	h, ops := newJSONRPCTestService(t)
+
+	w := postJSONRPC(h, `[
+		{"jsonrpc":"2.0","method":"sum","params":[1,1],"id":1},
+		{"jsonrpc":"2.0","method":"sum","params":[5]},
+		1
+	]`)
+	assert.JSONEq(t, `[
+		{"jsonrpc":"2.0","result":2,"id":1},
+		{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request"},"id":null}
+	]`, w.Body.String())
+	assert.Equal(t, []string{"/jsonrpc/sum", "/jsonrpc/sum"}, *ops)
+
+	w = postJSONRPC(h, `[]`)
+	assert.Contains(t, w.Body.String(), `"code":-32600`)
+
+	w = postJSONRPC(h, `[{"jsonrpc":"2.0","method":"sum"},{"jsonrpc":"2.0","method":"sum"},`+
+		`{"jsonrpc":"2.0","method":"sum"},{"jsonrpc":"2.0","method":"sum"}]`)
+	assert.Contains(t, w.Body.String(), `"code":-32600`, "batch above max_batch_size")
+
+	w = postJSONRPC(h, `[{"jsonrpc":"2.0","method":"sum","params":[1]}]`)
+	assert.Equal(t, nhttp.StatusNoContent, w.Code)
+}
+
+func TestErrorFromJSONRPC(t *testing.T) {
+	assert.Nil(t, ErrorFromJSONRPC(nil))
+
+	err := ErrorFromJSONRPC(&JSONRPCError{Code: JSONRPCMethodNotFound, Message: "Method not found"})
+	assert.Equal(t, int32(404), errors.FromError(err).Code)
+
+	mapper, mErr := NewBusinessCodeMapper(BusinessCode{Code: 100404, Reason: "ACCOUNT_NOT_FOUND", Status: 404})
+	require.NoError(t, mErr)
+	err = ErrorFromJSONRPC(&JSONRPCError{Code: 100404, Message: "gone"}, WithCodeErrorMapper(mapper))
+	assert.Equal(t, "ACCOUNT_NOT_FOUND", errors.FromError(err).Reason)
+
+	err = ErrorFromJSONRPC(&JSONRPCError{Code: 100500, Message: "other"})
+	assert.Equal(t, int32(100500), errors.FromError(err).Code)
+}
+
+func TestValidateJSONRPCConfig(t *testing.T) {
+	require.NoError(t, validateJSONRPCConfig(&conf.JSONRPCConfig{Path: "rpc"}))
+	assert.Error(t, validateJSONRPCConfig(&conf.JSONRPCConfig{Enabled: true, Path: "rpc"}))
+	assert.Error(t, validateJSONRPCConfig(&conf.JSONRPCConfig{Enabled: true, MaxBatchSize: -1}))
+}
@@ -0,0 +1,194 @@
+package http
+
+import (
+	"fmt"
+	nhttp "net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+const (
+	defaultPodNameEnv      = "POD_NAME"
+	defaultPodNamespaceEnv = "POD_NAMESPACE"
+	defaultNodeNameEnv     = "NODE_NAME"
+
+	defaultLivenessPath  = "/livez"
+	defaultReadinessPath = "/readyz"
+	defaultStartupPath   = "/startupz"
+	defaultPreStopPath   = "/prestop"
+
+	// defaultPreStopDelay is the preStop wait without prestop_delay or a load balancer drain delay.
+	defaultPreStopDelay = 5 * time.Second
+)
+
+// podInfo is the pod identity published by the Downward API.
+type podInfo struct {
+	pod, namespace, node string
+}
+
+// podInfoFromEnv reads the pod identity from the environment variables named by cfg. It is empty unless the
+// Kubernetes integration is enabled.
+func podInfoFromEnv(cfg *conf.KubernetesConfig) podInfo {
+	if !cfg.GetEnabled() {
+		return podInfo{}
+	}
+	return podInfo{
+		pod:       os.Getenv(configuredPath(cfg.GetPodNameEnv(), defaultPodNameEnv)),
+		namespace: os.Getenv(configuredPath(cfg.GetPodNamespaceEnv(), defaultPodNamespaceEnv)),
+		node:      os.Getenv(configuredPath(cfg.GetNodeNameEnv(), defaultNodeNameEnv)),
+	}
+}
+
+func (p podInfo) empty() bool {
+	return p == podInfo{}
+}
+
+// logFields returns the request log fields of the known names.
+func (p podInfo) logFields() []any {
+	var fields []any
+	for _, f := range []struct{ key, value string }{{"pod", p.pod}, {"namespace", p.namespace}, {"node", p.node}} {
+		if f.value != "" {
+			fields = append(fields, f.key, f.value)
+		}
+	}
+	return fields
+}
+
+// kubernetesEndpoints returns the probe and preStop paths, or nil when the integration is disabled.
+func kubernetesEndpoints(cfg *conf.KubernetesConfig) []builtinEndpoint {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	return []builtinEndpoint{
+		{"liveness probe", configuredPath(cfg.GetLivenessPath(), defaultLivenessPath), false},
+		{"readiness probe", configuredPath(cfg.GetReadinessPath(), defaultReadinessPath), false},
+		{"startup probe", configuredPath(cfg.GetStartupPath(), defaultStartupPath), false},
+		{"preStop hook", configuredPath(cfg.GetPrestopPath(), defaultPreStopPath), false},
+	}
+}
+
+// mountKubernetes registers the probe and preStop endpoints and publishes the pod info metric.
+func (h *ServiceHttp) mountKubernetes() {
+	cfg := h.conf.GetKubernetes()
+	if !cfg.GetEnabled() {
+		return
+	}
+	endpoints := kubernetesEndpoints(cfg)
+	handlers := []nhttp.Handler{
+		h.livenessHandler(),
+		h.readinessHandler(),
+		h.startupHandler(),
+		h.preStopHandler(preStopDelay(cfg, h.conf.GetLoadBalancerHints())),
+	}
+	for i, endpoint := range endpoints {
+		h.server.Handle(endpoint.path, &netHTTPToKratosHandlerAdapter{handler: handlers[i]})
+	}
+	log.Infof("Kubernetes endpoints mounted: liveness %s, readiness %s, startup %s, preStop %s",
+		endpoints[0].path, endpoints[1].path, endpoints[2].path, endpoints[3].path)
+
+	if info := podInfoFromEnv(cfg); !info.empty() && h.podInfo != nil {
+		h.podInfo.WithLabelValues(info.pod, info.namespace, info.node).Set(1)
+	}
+}
+
+// preStopDelay is prestop_delay, else the load balancer drain delay, else defaultPreStopDelay.
+func preStopDelay(cfg *conf.KubernetesConfig, hints *conf.LoadBalancerHintsConfig) time.Duration {
+	if cfg.GetPrestopDelay() != nil {
+		return cfg.GetPrestopDelay().AsDuration()
+	}
+	if d := hints.GetDrainDelay().AsDuration(); d > 0 {
+		return d
+	}
+	return defaultPreStopDelay
+}
+
+// livenessHandler answers 200 while the process can serve requests; it never checks dependencies, so a
+// failing dependency does not get the container restarted.
+func (h *ServiceHttp) livenessHandler() nhttp.Handler {
+	return nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		writeProbe(w, nil)
+	})
+}
+
+// readinessHandler fails until startup completes, while draining, and when the health endpoint would.
+func (h *ServiceHttp) readinessHandler() nhttp.Handler {
+	return nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		var err error
+		switch {
+		case !h.startupComplete.Load():
+			err = fmt.Errorf("HTTP server is starting")
+		case h.draining.Load():
+			err = fmt.Errorf("HTTP server is draining")
+		default:
+			if err = h.CheckRuntimeHealth(); err == nil {
+				err = h.healthHintError()
+			}
+		}
+		writeProbe(w, err)
+	})
+}
+
+// startupHandler fails until startup completes.
+func (h *ServiceHttp) startupHandler() nhttp.Handler {
+	return nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		var err error
+		if !h.startupComplete.Load() {
+			err = fmt.Errorf("HTTP server is starting")
+		}
+		writeProbe(w, err)
+	})
+}
+
+// preStopHandler starts draining and answers after delay, which the kubelet waits for before sending SIGTERM.
+func (h *ServiceHttp) preStopHandler(delay time.Duration) nhttp.Handler {
+	return nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		h.StartDraining()
+		log.Infof("preStop hook received, waiting %s before the pod is signalled", delay)
+		t := time.NewTimer(delay)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-r.Context().Done():
+			return
+		}
+		writeProbe(w, nil)
+	})
+}
+
+// writeProbe answers a probe with "ok" and 200, or the error text and 503.
+func writeProbe(w nhttp.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if err != nil {
+		w.WriteHeader(nhttp.StatusServiceUnavailable)
+		_, _ = fmt.Fprintln(w, err.Error())
+		return
+	}
+	w.WriteHeader(nhttp.StatusOK)
+	_, _ = fmt.Fprintln(w, "ok")
+}
+
+// validateKubernetesConfig requires absolute, distinct endpoint paths and a non-negative preStop delay.
+func validateKubernetesConfig(cfg *conf.KubernetesConfig) error {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	seen := make(map[string]string)
+	for _, endpoint := range kubernetesEndpoints(cfg) {
+		if !strings.HasPrefix(endpoint.path, "/") {
+			return fmt.Errorf("%s path %q must start with /", endpoint.name, endpoint.path)
+		}
+		if other, ok := seen[endpoint.path]; ok {
+			return fmt.Errorf("%s and %s share the path %q", other, endpoint.name, endpoint.path)
+		}
+		seen[endpoint.path] = endpoint.name
+	}
+	if cfg.GetPrestopDelay() != nil && cfg.GetPrestopDelay().AsDuration() < 0 {
+		return fmt.Errorf("prestop_delay must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package http
+
+import (
+	"context"
+	nhttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func probe(t *testing.T, handler nhttp.Handler) (int, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(nhttp.MethodGet, "/", nil))
+	return rec.Code, rec.Body.String()
+}
+
+func TestKubernetesProbes(t *testing.T) {
+	h := NewServiceHttp()
+	h.server = http.NewServer()
+
+	code, _ := probe(t, h.livenessHandler())
+	assert.Equal(t, nhttp.StatusOK, code)
+	code, body := probe(t, h.readinessHandler())
+	assert.Equal(t, nhttp.StatusServiceUnavailable, code)
+	assert.Equal(t, "HTTP server is starting\n", body)
+	code, _ = probe(t, h.startupHandler())
+	assert.Equal(t, nhttp.StatusServiceUnavailable, code)
+
+	h.startupComplete.Store(true)
+	code, body = probe(t, h.readinessHandler())
+	assert.Equal(t, nhttp.StatusOK, code)
+	assert.Equal(t, "ok\n", body)
+	code, _ = probe(t, h.startupHandler())
+	assert.Equal(t, nhttp.StatusOK, code)
+
+	h.StartDraining()
+	code, body = probe(t, h.readinessHandler())
+	assert.Equal(t, nhttp.StatusServiceUnavailable, code, "readiness fails while draining without load balancer hints")
+	assert.Equal(t, "HTTP server is draining\n", body)
+	code, _ = probe(t, h.livenessHandler())
+	assert.Equal(t, nhttp.StatusOK, code)
+}
+
+func TestKubernetesPreStop(t *testing.T) {
+	h := NewServiceHttp()
+	start := time.Now()
+	code, _ := probe(t, h.preStopHandler(20*time.Millisecond))
+	assert.Equal(t, nhttp.StatusOK, code)
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	assert.True(t, h.Draining())
+
+	// The shutdown drain delay only waits for what the preStop hook has not.
+	h.conf = &conf.Http{LoadBalancerHints: &conf.LoadBalancerHintsConfig{DrainDelay: durationpb.New(20 * time.Millisecond)}}
+	start = time.Now()
+	h.drainBeforeStop(context.Background())
+	assert.Less(t, time.Since(start), 15*time.Millisecond)
+
+	assert.Equal(t, time.Second, preStopDelay(&conf.KubernetesConfig{PrestopDelay: durationpb.New(time.Second)}, nil))
+	assert.Equal(t, 20*time.Millisecond, preStopDelay(&conf.KubernetesConfig{}, h.conf.LoadBalancerHints))
+	assert.Equal(t, defaultPreStopDelay, preStopDelay(&conf.KubernetesConfig{}, nil))
+}
+
+func TestKubernetesPodInfo(t *testing.T) {
+	t.Setenv("POD_NAME", "orders-7d9f")
+	t.Setenv("POD_NAMESPACE", "shop")
+	t.Setenv("MY_NODE", "node-a")
+
+	assert.Empty(t, podInfoFromEnv(&conf.KubernetesConfig{}).logFields(), "disabled")
+	info := podInfoFromEnv(&conf.KubernetesConfig{Enabled: true})
+	assert.Equal(t, []any{"pod", "orders-7d9f", "namespace", "shop"}, info.logFields())
+	info = podInfoFromEnv(&conf.KubernetesConfig{Enabled: true, NodeNameEnv: "MY_NODE"})
+	assert.Equal(t, podInfo{pod: "orders-7d9f", namespace: "shop", node: "node-a"}, info)
+
+	snap := monitoringSnapshotForConfig(&conf.Http{Kubernetes: &conf.KubernetesConfig{Enabled: true}})
+	assert.Equal(t, []any{"pod", "orders-7d9f", "namespace", "shop"}, snap.podLogFields)
+}
+
+func TestValidateKubernetesConfig(t *testing.T) {
+	require.NoError(t, validateKubernetesConfig(nil))
+	require.NoError(t, validateKubernetesConfig(&conf.KubernetesConfig{Enabled: true}))
+	assert.ErrorContains(t, validateKubernetesConfig(&conf.KubernetesConfig{Enabled: true, LivenessPath: "livez"}),
+		"must start with /")
+	assert.ErrorContains(t, validateKubernetesConfig(&conf.KubernetesConfig{Enabled: true, StartupPath: "/readyz"}),
+		`readiness probe and startup probe share the path "/readyz"`)
+	assert.ErrorContains(t, validateKubernetesConfig(&conf.KubernetesConfig{Enabled: true,
+		PrestopDelay: durationpb.New(-time.Second)}), "must not be negative")
+}
@@ -0,0 +1,114 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	nhttp "net/http"
+	"time"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+	"github.com/go-lynx/lynx/plugins"
+)
+
+const (
+	serverDrainingHeader = "X-Server-Draining"
+	serverDegradedHeader = "X-Server-Degraded"
+)
+
+// StartDraining puts the server in the draining state, which it also enters on shutdown. With
+// load_balancer_hints enabled, responses then close their connections and carry X-Server-Draining, and the
+// health endpoint fails when fail_health_when_draining is set. Draining lasts until the server stops.
+func (h *ServiceHttp) StartDraining() {
+	if h.draining.CompareAndSwap(false, true) {
+		h.drainStartedAt.Store(time.Now().UnixNano())
+		log.Infof("HTTP service draining")
+		h.publishRuntimeContract(false, !h.degraded.Load())
+		h.emitEvent(EventDrainStarted, plugins.PriorityNormal, "lifecycle", map[string]any{
+			"drain_delay": h.loadBalancerHintsConfig().GetDrainDelay().AsDuration().String(),
+		})
+	}
+}
+
+// Draining reports whether the server is draining.
+func (h *ServiceHttp) Draining() bool {
+	return h.draining.Load()
+}
+
+// SetDegraded marks the server as degraded, for example while a dependency is failing. With
+// load_balancer_hints enabled, responses carry X-Server-Degraded and the health endpoint fails when
+// fail_health_when_degraded is set.
+func (h *ServiceHttp) SetDegraded(degraded bool) {
+	if h.degraded.Swap(degraded) != degraded {
+		log.Infof("HTTP service degraded: %t", degraded)
+	}
+}
+
+// Degraded reports whether the server is marked degraded.
+func (h *ServiceHttp) Degraded() bool {
+	return h.degraded.Load()
+}
+
+// loadBalancerHintsConfig returns the current hints configuration, which may be nil.
+func (h *ServiceHttp) loadBalancerHintsConfig() *conf.LoadBalancerHintsConfig {
+	h.confMu.RLock()
+	defer h.confMu.RUnlock()
+	return h.conf.GetLoadBalancerHints()
+}
+
+// loadBalancerHintsFilter adds the draining and degraded headers to every response.
+func (h *ServiceHttp) loadBalancerHintsFilter(next nhttp.Handler) nhttp.Handler {
+	return nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		if h.draining.Load() {
+			w.Header().Set("Connection", "close")
+			w.Header().Set(serverDrainingHeader, "true")
+		}
+		if h.degraded.Load() {
+			w.Header().Set(serverDegradedHeader, "true")
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// healthHintError returns the error the health endpoint reports for the draining or degraded state, or nil.
+func (h *ServiceHttp) healthHintError() error {
+	cfg := h.loadBalancerHintsConfig()
+	switch {
+	case cfg.GetFailHealthWhenDraining() && h.draining.Load():
+		return fmt.Errorf("HTTP server is draining")
+	case cfg.GetFailHealthWhenDegraded() && h.degraded.Load():
+		return fmt.Errorf("HTTP server is degraded")
+	}
+	return nil
+}
+
+// drainBeforeStop enters the draining state and waits until it has lasted drain_delay, or until ctx ends,
+// before the server stops. Time spent draining earlier, e.g. from a preStop hook, counts toward the delay.
+func (h *ServiceHttp) drainBeforeStop(ctx context.Context) {
+	h.StartDraining()
+	delay := h.loadBalancerHintsConfig().GetDrainDelay().AsDuration()
+	if started := h.drainStartedAt.Load(); started > 0 {
+		delay -= time.Since(time.Unix(0, started))
+	}
+	if delay <= 0 {
+		return
+	}
+	log.Infof("Draining HTTP service for %s before stopping", delay)
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+	}
+}
+
+// validateLoadBalancerHintsConfig rejects negative drain delays.
+func validateLoadBalancerHintsConfig(cfg *conf.LoadBalancerHintsConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	if cfg.DrainDelay != nil && cfg.DrainDelay.AsDuration() < 0 {
+		return fmt.Errorf("drain_delay must not be negative")
+	}
+	return nil
+}
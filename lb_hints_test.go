@@ -0,0 +1,68 @@
+package http
+
+import (
+	"context"
+	nhttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestLoadBalancerHintsFilter(t *testing.T) {
+	h := &ServiceHttp{conf: &conf.Http{}}
+	handler := h.loadBalancerHintsFilter(nhttp.HandlerFunc(func(w nhttp.ResponseWriter, _ *nhttp.Request) {
+		w.WriteHeader(nhttp.StatusOK)
+	}))
+	serve := func() nhttp.Header {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(nhttp.MethodGet, "/", nil))
+		return rec.Header()
+	}
+
+	header := serve()
+	assert.Empty(t, header.Get("Connection"))
+	assert.Empty(t, header.Get(serverDrainingHeader))
+
+	h.SetDegraded(true)
+	assert.Equal(t, "true", serve().Get(serverDegradedHeader))
+	h.SetDegraded(false)
+
+	h.StartDraining()
+	assert.True(t, h.Draining())
+	header = serve()
+	assert.Equal(t, "close", header.Get("Connection"))
+	assert.Equal(t, "true", header.Get(serverDrainingHeader))
+	assert.Empty(t, header.Get(serverDegradedHeader))
+}
+
+func TestLoadBalancerHints_Health(t *testing.T) {
+	h := &ServiceHttp{conf: &conf.Http{LoadBalancerHints: &conf.LoadBalancerHintsConfig{FailHealthWhenDegraded: true}}}
+	assert.NoError(t, h.healthHintError())
+
+	h.StartDraining()
+	assert.NoError(t, h.healthHintError(), "draining only fails health when configured")
+	h.SetDegraded(true)
+	assert.EqualError(t, h.healthHintError(), "HTTP server is degraded")
+
+	h.conf.LoadBalancerHints.FailHealthWhenDraining = true
+	assert.EqualError(t, h.healthHintError(), "HTTP server is draining")
+}
+
+func TestDrainBeforeStop(t *testing.T) {
+	h := &ServiceHttp{conf: &conf.Http{LoadBalancerHints: &conf.LoadBalancerHintsConfig{
+		DrainDelay: durationpb.New(time.Hour),
+	}}}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	h.drainBeforeStop(ctx)
+	assert.True(t, h.Draining())
+	assert.Less(t, time.Since(start), time.Minute, "the shutdown context bounds the drain delay")
+
+	assert.Error(t, validateLoadBalancerHintsConfig(&conf.LoadBalancerHintsConfig{DrainDelay: durationpb.New(-time.Second)}))
+}
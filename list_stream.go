@@ -0,0 +1,185 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"iter"
+	nhttp "net/http"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/encoding"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx/log"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// NDJSONContentType is the content type of list streams.
+	NDJSONContentType = "application/x-ndjson"
+
+	defaultListStreamFlushInterval = 100 * time.Millisecond
+)
+
+// ListStream is a reply whose items are written as newline-delimited JSON while the handler's iterator
+// produces them, instead of being collected into one response body. Return it from a handler for exports too
+// large to hold in memory.
+type ListStream struct {
+	items         iter.Seq2[proto.Message, error]
+	flushInterval time.Duration
+}
+
+// NewListStream returns a reply streaming the messages of items. An error from items ends the stream; when
+// it comes before the first message, it is answered like an error returned by the handler.
+func NewListStream[T proto.Message](items iter.Seq2[T, error]) *ListStream {
+	return &ListStream{
+		items: func(yield func(proto.Message, error) bool) {
+			for item, err := range items {
+				if !yield(item, err) {
+					return
+				}
+			}
+		},
+		flushInterval: defaultListStreamFlushInterval,
+	}
+}
+
+// FlushEvery sets how often written lines are flushed to the client. Default: 100ms.
+func (s *ListStream) FlushEvery(d time.Duration) *ListStream {
+	if d > 0 {
+		s.flushInterval = d
+	}
+	return s
+}
+
+// mapItems returns a stream of the items of s passed through fn, for encoders that transform replies.
+func (s *ListStream) mapItems(fn func(proto.Message) (proto.Message, error)) *ListStream {
+	return &ListStream{
+		items: func(yield func(proto.Message, error) bool) {
+			for item, err := range s.items {
+				if err == nil {
+					item, err = fn(item)
+				}
+				if !yield(item, err) {
+					return
+				}
+			}
+		},
+		flushInterval: s.flushInterval,
+	}
+}
+
+// ListStreamTrailer is the last line of a list stream, as {"trailer":{...}}. Count is the number of item
+// lines and Checksum is "sha256:" followed by the hex SHA-256 of those lines, newlines included. Error holds
+// the error code when the stream ended early; a stream without a trailer was cut off.
+type ListStreamTrailer struct {
+	Count    int64            `json:"count"`
+	Checksum string           `json:"checksum"`
+	Error    *ListStreamError `json:"error,omitempty"`
+}
+
+// ListStreamError is the error that ended a list stream.
+type ListStreamError struct {
+	Code int32 `json:"code"`
+}
+
+// ListStreamEncoder writes *ListStream replies as NDJSON, one JSON item per line followed by a
+// ListStreamTrailer line, and hands other replies to ResponseEncoder. Lines are flushed at the stream's flush
+// interval, so clients process the items while later ones are produced.
+func ListStreamEncoder(w http.ResponseWriter, r *http.Request, data any) error {
+	s, ok := data.(*ListStream)
+	if !ok {
+		return ResponseEncoder(w, r, data)
+	}
+	next, stop := iter.Pull2(s.items)
+	defer stop()
+
+	// Errors before the first item are answered by the error encoder.
+	item, err, more := next()
+	if err != nil {
+		return err
+	}
+	codec := encoding.GetCodec("json")
+	out, err := startStream(w, NDJSONContentType)
+	if err != nil {
+		return err
+	}
+	flusher := nhttp.NewResponseController(out)
+
+	checksum := sha256.New()
+	trailer := ListStreamTrailer{}
+	lastFlush := time.Now()
+	for ; more; item, err, more = next() {
+		if err == nil {
+			err = r.Context().Err()
+		}
+		if err != nil {
+			se := errors.FromError(err)
+			log.Errorf("List stream of %s ended after %d items: %v", streamOperation(r), trailer.Count, err)
+			trailer.Error = &ListStreamError{Code: se.Code}
+			break
+		}
+		line, err := codec.Marshal(item)
+		if err != nil {
+			log.Errorf("List stream of %s: marshal item %d: %v", streamOperation(r), trailer.Count, err)
+			trailer.Error = &ListStreamError{Code: nhttp.StatusInternalServerError}
+			break
+		}
+		line = append(line, '\n')
+		if _, err := out.Write(line); err != nil {
+			// The client is gone; there is nobody to send a trailer to.
+			return err
+		}
+		checksum.Write(line)
+		trailer.Count++
+		if time.Since(lastFlush) >= s.flushInterval {
+			_ = flusher.Flush()
+			lastFlush = time.Now()
+		}
+	}
+
+	trailer.Checksum = "sha256:" + hex.EncodeToString(checksum.Sum(nil))
+	line, err := json.Marshal(map[string]ListStreamTrailer{"trailer": trailer})
+	if err != nil {
+		return err
+	}
+	if _, err := out.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	_ = flusher.Flush()
+	return nil
+}
+
+// startStream sends the status line and headers of a streamed reply and returns the writer for its body.
+// Kratos' response writer repeats the status line on every Write, so the body goes to the writer below it.
+func startStream(w nhttp.ResponseWriter, contentType string) (nhttp.ResponseWriter, error) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Del("Content-Length")
+	w.WriteHeader(nhttp.StatusOK)
+	if _, err := w.Write(nil); err != nil {
+		return nil, err
+	}
+	if u, ok := w.(interface{ Unwrap() nhttp.ResponseWriter }); ok {
+		return u.Unwrap(), nil
+	}
+	return w, nil
+}
+
+func streamOperation(r *http.Request) string {
+	if tr, ok := transport.FromServerContext(r.Context()); ok {
+		return tr.Operation()
+	}
+	return r.URL.Path
+}
+
+// withListStreams sends *ListStream replies to stream, past the encoders that buffer the body.
+func withListStreams(stream, encode http.EncodeResponseFunc) http.EncodeResponseFunc {
+	return func(w http.ResponseWriter, r *http.Request, data any) error {
+		if _, ok := data.(*ListStream); ok {
+			return stream(w, r, data)
+		}
+		return encode(w, r, data)
+	}
+}
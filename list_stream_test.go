@@ -0,0 +1,164 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"iter"
+	stdlog "log"
+	nhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/apipb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// listStreamServer serves reply on GET /export through encode.
+func listStreamServer(encode http.EncodeResponseFunc, reply func() any) *http.Server {
+	var h ServiceHttp
+	srv := http.NewServer(http.ResponseEncoder(encode), http.ErrorEncoder(h.enhancedErrorEncoder))
+	srv.Route("/").GET("/export", func(ctx http.Context) error {
+		http.SetOperation(ctx, "/api.v1.Export/List")
+		return ctx.Result(nhttp.StatusOK, reply())
+	})
+	return srv
+}
+
+func stringSeq(values []string, err error) iter.Seq2[*wrapperspb.StringValue, error] {
+	return func(yield func(*wrapperspb.StringValue, error) bool) {
+		for _, v := range values {
+			if !yield(wrapperspb.String(v), nil) {
+				return
+			}
+		}
+		if err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// readListStream splits an NDJSON body into its item lines and trailer.
+func readListStream(t *testing.T, body string) ([]string, ListStreamTrailer) {
+	t.Helper()
+	lines := strings.SplitAfter(body, "\n")
+	require.Equal(t, "", lines[len(lines)-1], "the body ends with a newline")
+	var trailer struct {
+		Trailer ListStreamTrailer `json:"trailer"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(lines[len(lines)-2]), &trailer))
+	return lines[:len(lines)-2], trailer.Trailer
+}
+
+func TestListStreamEncoder(t *testing.T) {
+	var replyErr error
+	srv := listStreamServer(withListStreams(ListStreamEncoder, ResponseEncoder), func() any {
+		return NewListStream(stringSeq([]string{"a", "b", "c"}, replyErr))
+	})
+	get := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(nhttp.MethodGet, "/export", nil))
+		return rec
+	}
+
+	rec := get()
+	assert.Equal(t, NDJSONContentType, rec.Header().Get("Content-Type"))
+	items, trailer := readListStream(t, rec.Body.String())
+	assert.Equal(t, []string{"\"a\"\n", "\"b\"\n", "\"c\"\n"}, items)
+	sum := sha256.Sum256([]byte(strings.Join(items, "")))
+	assert.Equal(t, ListStreamTrailer{Count: 3, Checksum: "sha256:" + hex.EncodeToString(sum[:])}, trailer)
+
+	// Errors after the first item end the stream with the error code in the trailer.
+	replyErr = kerrors.ServiceUnavailable("DB_UNAVAILABLE", "connection lost")
+	items, trailer = readListStream(t, get().Body.String())
+	assert.Len(t, items, 3)
+	assert.Equal(t, int64(3), trailer.Count)
+	assert.Equal(t, &ListStreamError{Code: nhttp.StatusServiceUnavailable}, trailer.Error)
+
+	// Errors before it are encoded like handler errors.
+	srv = listStreamServer(withListStreams(ListStreamEncoder, ResponseEncoder), func() any {
+		return NewListStream(stringSeq(nil, kerrors.Forbidden("EXPORT_DENIED", "no access")))
+	})
+	rec = get()
+	assert.JSONEq(t, `{"code":403}`, rec.Body.String())
+
+	// Other replies keep the envelope.
+	srv = listStreamServer(ListStreamEncoder, func() any { return wrapperspb.String("a") })
+	assert.JSONEq(t, `{"code":200,"data":{"value":"a"}}`, get().Body.String())
+}
+
+func TestListStreamEncoder_Flushes(t *testing.T) {
+	produced := make(chan struct{})
+	srv := listStreamServer(withListStreams(ListStreamEncoder, ResponseEncoder), func() any {
+		return NewListStream(func(yield func(*wrapperspb.Int64Value, error) bool) {
+			if !yield(wrapperspb.Int64(1), nil) {
+				return
+			}
+			// The second item is only produced once the client has read the first one.
+			<-produced
+			yield(wrapperspb.Int64(2), nil)
+		}).FlushEvery(time.Nanosecond)
+	})
+	var serverLog bytes.Buffer
+	ts := httptest.NewUnstartedServer(srv)
+	ts.Config.ErrorLog = stdlog.New(&serverLog, "", 0)
+	ts.Start()
+	defer ts.Close()
+
+	res, err := ts.Client().Get(ts.URL + "/export")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	reader := bufio.NewReader(res.Body)
+	line, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "\"1\"\n", line)
+	close(produced)
+	line, err = reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "\"2\"\n", line)
+	line, err = reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.Contains(t, line, `"count":2`)
+	assert.NotContains(t, serverLog.String(), "superfluous", "the status line is written once")
+}
+
+func TestListStreamEncoder_FieldEncryption(t *testing.T) {
+	cfg := &conf.FieldEncryptionConfig{
+		Enabled:     true,
+		Rules:       []*conf.FieldEncryptionRule{{Operation: "/api.v1.Export/*", Fields: []string{"name"}}},
+		Keys:        []*conf.FieldEncryptionKey{{Id: "k1", Key: base64.StdEncoding.EncodeToString(testFieldKeyOld)}},
+		ActiveKeyId: "k1",
+	}
+	policy := newFieldEncryptionPolicy(cfg)
+	require.NotNil(t, policy)
+	srv := listStreamServer(withListStreams(withFieldEncryption(policy, ListStreamEncoder), ResponseEncoder), func() any {
+		return NewListStream(func(yield func(*apipb.Api, error) bool) {
+			_ = yield(&apipb.Api{Name: "4111111111111111", Version: "v1"}, nil) && yield(&apipb.Api{Name: "5500000000000004"}, nil)
+		})
+	})
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(nhttp.MethodGet, "/export", nil))
+	items, trailer := readListStream(t, rec.Body.String())
+	require.Len(t, items, 2)
+	assert.Nil(t, trailer.Error)
+	for i, want := range []string{"4111111111111111", "5500000000000004"} {
+		var item struct {
+			Name string `json:"name"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(items[i]), &item))
+		require.True(t, strings.HasPrefix(item.Name, EncryptedFieldPrefix))
+		plain, err := DecryptField(item.Name, "name", map[string][]byte{"k1": testFieldKeyOld})
+		require.NoError(t, err)
+		assert.Equal(t, want, string(plain))
+	}
+}
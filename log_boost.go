@@ -0,0 +1,359 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	nhttp "net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	kratoslog "github.com/go-kratos/kratos/v2/log"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+const (
+	defaultLogBoostPath        = "/debug/log-boost"
+	defaultLogBoostDuration    = 10 * time.Minute
+	defaultLogBoostMaxDuration = time.Hour
+)
+
+// LogBoost raises logging verbosity for the requests it matches until it expires. Operation and TraceID
+// are exact values or prefixes ending in "*"; at least one must be set, and both must match when set.
+type LogBoost struct {
+	Operation string
+	TraceID   string
+	// ReplyPolicy renders request and reply bodies of boosted requests; empty selects "json".
+	ReplyPolicy string
+	// Level is the level of boosted request/response records; empty selects "info".
+	Level    string
+	Duration time.Duration
+}
+
+// logBoost is an active, resolved LogBoost.
+type logBoost struct {
+	operation string
+	traceID   string
+	policy    replyLogPolicy
+	level     kratoslog.Level
+	expires   time.Time
+}
+
+func (b *logBoost) matches(operation, traceID string) bool {
+	return wildcardMatches(b.operation, operation) && wildcardMatches(b.traceID, traceID)
+}
+
+// wildcardMatches matches exact values or prefixes ending in "*"; an empty pattern matches anything.
+func wildcardMatches(pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(value, prefix)
+	}
+	return pattern == value
+}
+
+// logBoostSet holds the active boosts. Readers load an immutable slice, so requests pay a single atomic
+// load while no boost is active.
+type logBoostSet struct {
+	mu     sync.Mutex
+	boosts atomic.Pointer[[]*logBoost]
+	now    func() time.Time
+}
+
+func (s *logBoostSet) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
+
+// match returns the first active boost matching the request, or nil.
+func (s *logBoostSet) match(operation, traceID string) *logBoost {
+	boosts := s.boosts.Load()
+	if boosts == nil || len(*boosts) == 0 {
+		return nil
+	}
+	now := s.clock()
+	var found *logBoost
+	expired := false
+	for _, b := range *boosts {
+		if !now.Before(b.expires) {
+			expired = true
+			continue
+		}
+		if found == nil && b.matches(operation, traceID) {
+			found = b
+		}
+	}
+	if expired {
+		s.prune()
+	}
+	return found
+}
+
+func (s *logBoostSet) add(b *logBoost) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	next := s.activeLocked()
+	next = append(next, b)
+	s.boosts.Store(&next)
+}
+
+// prune drops expired boosts, logging each revert.
+func (s *logBoostSet) prune() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	next := s.activeLocked()
+	s.boosts.Store(&next)
+}
+
+// activeLocked returns a copy of the unexpired boosts.
+func (s *logBoostSet) activeLocked() []*logBoost {
+	now := s.clock()
+	var active []*logBoost
+	if boosts := s.boosts.Load(); boosts != nil {
+		for _, b := range *boosts {
+			if now.Before(b.expires) {
+				active = append(active, b)
+				continue
+			}
+			log.Infof("Log boost for operation=%q trace_id=%q expired; logging reverted", b.operation, b.traceID)
+		}
+	}
+	return active
+}
+
+func (s *logBoostSet) list() []*logBoost {
+	s.prune()
+	if boosts := s.boosts.Load(); boosts != nil {
+		return *boosts
+	}
+	return nil
+}
+
+func (s *logBoostSet) clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.boosts.Store(nil)
+}
+
+// BoostLogging activates a log boost. Matching requests are logged with their request and reply bodies,
+// even when request logging is disabled, and bypass the level and sampling of the Lynx logger sink. The
+// boost reverts on its own after its duration (10m by default, capped by log_boost.max_duration).
+func (h *ServiceHttp) BoostLogging(boost LogBoost) error {
+	b, err := h.newLogBoost(boost)
+	if err != nil {
+		return err
+	}
+	h.logBoosts.add(b)
+	log.Infof("Log boost for operation=%q trace_id=%q active until %s", b.operation, b.traceID,
+		b.expires.Format(time.RFC3339))
+	return nil
+}
+
+// ClearLogBoosts removes every active log boost.
+func (h *ServiceHttp) ClearLogBoosts() {
+	h.logBoosts.clear()
+	log.Infof("Log boosts cleared")
+}
+
+func (h *ServiceHttp) newLogBoost(boost LogBoost) (*logBoost, error) {
+	if err := validateLogBoost(boost, h.logBoostMaxDuration()); err != nil {
+		return nil, err
+	}
+	b := &logBoost{
+		operation: strings.TrimSpace(boost.Operation),
+		traceID:   strings.TrimSpace(boost.TraceID),
+		policy:    replyLogPolicyJSON,
+		level:     kratoslog.LevelInfo,
+	}
+	if strings.TrimSpace(boost.ReplyPolicy) != "" {
+		b.policy, _ = parseReplyLogPolicy(boost.ReplyPolicy)
+	}
+	if lvl := strings.TrimSpace(boost.Level); lvl != "" {
+		b.level = kratoslog.ParseLevel(lvl)
+	}
+	d := boost.Duration
+	if d == 0 {
+		d = defaultLogBoostDuration
+	}
+	b.expires = h.logBoosts.clock().Add(d)
+	return b, nil
+}
+
+func (h *ServiceHttp) logBoostMaxDuration() time.Duration {
+	h.confMu.RLock()
+	defer h.confMu.RUnlock()
+	if d := h.conf.GetMonitoring().GetLogBoost().GetMaxDuration().AsDuration(); d > 0 {
+		return d
+	}
+	return defaultLogBoostMaxDuration
+}
+
+// matchLogBoost returns the active boost for a request, or nil.
+func (h *ServiceHttp) matchLogBoost(operation, traceID string) *logBoost {
+	if h == nil {
+		return nil
+	}
+	return h.logBoosts.match(operation, traceID)
+}
+
+// applyConfiguredLogBoosts activates the boosts listed in log_boost.boosts.
+func (h *ServiceHttp) applyConfiguredLogBoosts() {
+	for _, rule := range h.conf.GetMonitoring().GetLogBoost().GetBoosts() {
+		if err := h.BoostLogging(logBoostFromRule(rule)); err != nil {
+			log.Warnf("Skipping log boost for operation=%q trace_id=%q: %v", rule.Operation, rule.TraceId, err)
+		}
+	}
+}
+
+func logBoostFromRule(rule *conf.LogBoostRule) LogBoost {
+	return LogBoost{
+		Operation:   rule.GetOperation(),
+		TraceID:     rule.GetTraceId(),
+		ReplyPolicy: rule.GetReplyPolicy(),
+		Level:       rule.GetLevel(),
+		Duration:    rule.GetDuration().AsDuration(),
+	}
+}
+
+// mountLogBoost registers the admin endpoint when it is enabled.
+func (h *ServiceHttp) mountLogBoost() {
+	cfg := h.conf.GetMonitoring().GetLogBoost()
+	if !cfg.GetEndpointEnabled() {
+		return
+	}
+	path := strings.TrimSpace(cfg.GetPath())
+	if path == "" {
+		path = defaultLogBoostPath
+	}
+	h.server.HandlePrefix(path, &netHTTPToKratosHandlerAdapter{handler: h.logBoostHandler()})
+	log.Infof("Log boost endpoint mounted at %s", path)
+}
+
+// logBoostBody is the JSON form of a boost on the admin endpoint.
+type logBoostBody struct {
+	Operation   string `json:"operation,omitempty"`
+	TraceID     string `json:"trace_id,omitempty"`
+	ReplyPolicy string `json:"reply_policy,omitempty"`
+	Level       string `json:"level,omitempty"`
+	// Duration is a Go duration string such as "5m" in requests.
+	Duration  string `json:"duration,omitempty"`
+	ExpiresAt string `json:"expires_at,omitempty"`
+}
+
+// logBoostHandler lists (GET), adds (POST) and clears (DELETE) boosts.
+func (h *ServiceHttp) logBoostHandler() nhttp.Handler {
+	return nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case nhttp.MethodGet:
+		case nhttp.MethodPost:
+			var body logBoostBody
+			if err := json.NewDecoder(nhttp.MaxBytesReader(w, r.Body, 4096)).Decode(&body); err != nil {
+				writeLogBoostError(w, nhttp.StatusBadRequest, "invalid JSON body")
+				return
+			}
+			boost := LogBoost{Operation: body.Operation, TraceID: body.TraceID, ReplyPolicy: body.ReplyPolicy, Level: body.Level}
+			if body.Duration != "" {
+				d, err := time.ParseDuration(body.Duration)
+				if err != nil {
+					writeLogBoostError(w, nhttp.StatusBadRequest, "invalid duration")
+					return
+				}
+				boost.Duration = d
+			}
+			if err := h.BoostLogging(boost); err != nil {
+				writeLogBoostError(w, nhttp.StatusBadRequest, err.Error())
+				return
+			}
+		case nhttp.MethodDelete:
+			h.ClearLogBoosts()
+		default:
+			w.Header().Set("Allow", "GET, POST, DELETE")
+			writeLogBoostError(w, nhttp.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		active := h.logBoosts.list()
+		out := make([]logBoostBody, 0, len(active))
+		for _, b := range active {
+			out = append(out, logBoostBody{
+				Operation:   b.operation,
+				TraceID:     b.traceID,
+				ReplyPolicy: string(b.policy),
+				Level:       strings.ToLower(b.level.String()),
+				ExpiresAt:   b.expires.UTC().Format(time.RFC3339),
+			})
+		}
+		if err := json.NewEncoder(w).Encode(map[string]any{"code": nhttp.StatusOK, "data": out}); err != nil {
+			log.Errorf("Failed to encode log boost response: %v", err)
+		}
+	})
+}
+
+func writeLogBoostError(w nhttp.ResponseWriter, status int, message string) {
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{"code": status, "message": message})
+}
+
+// validateLogBoost checks selectors, names and the duration of a boost.
+func validateLogBoost(boost LogBoost, maxDuration time.Duration) error {
+	if strings.TrimSpace(boost.Operation) == "" && strings.TrimSpace(boost.TraceID) == "" {
+		return fmt.Errorf("operation or trace_id is required")
+	}
+	if strings.TrimSpace(boost.ReplyPolicy) != "" {
+		if _, err := parseReplyLogPolicy(boost.ReplyPolicy); err != nil {
+			return err
+		}
+	}
+	switch strings.ToLower(strings.TrimSpace(boost.Level)) {
+	case "", "debug", "info", "warn", "error":
+	default:
+		return fmt.Errorf("unknown level %q", boost.Level)
+	}
+	if boost.Duration < 0 || boost.Duration > maxDuration {
+		return fmt.Errorf("duration %v must be between 0 and %v", boost.Duration, maxDuration)
+	}
+	return nil
+}
+
+// validateLogBoostConfig rejects relative paths and invalid configured boosts.
+func validateLogBoostConfig(cfg *conf.LogBoostConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	if p := strings.TrimSpace(cfg.Path); p != "" && !strings.HasPrefix(p, "/") {
+		return fmt.Errorf("path %q must start with /", p)
+	}
+	maxDuration := defaultLogBoostMaxDuration
+	if cfg.MaxDuration != nil {
+		if maxDuration = cfg.MaxDuration.AsDuration(); maxDuration <= 0 {
+			return fmt.Errorf("max duration must be positive")
+		}
+	}
+	for i, rule := range cfg.Boosts {
+		if err := validateLogBoost(logBoostFromRule(rule), maxDuration); err != nil {
+			return fmt.Errorf("boost %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// logwCtx writes structured key/value pairs to the Lynx logger at level.
+func logwCtx(ctx context.Context, level kratoslog.Level, keyvals ...any) {
+	switch level {
+	case kratoslog.LevelDebug:
+		log.DebugwCtx(ctx, keyvals...)
+	case kratoslog.LevelWarn:
+		log.WarnwCtx(ctx, keyvals...)
+	case kratoslog.LevelError, kratoslog.LevelFatal:
+		log.ErrorwCtx(ctx, keyvals...)
+	default:
+		log.InfowCtx(ctx, keyvals...)
+	}
+}
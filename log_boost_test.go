@@ -0,0 +1,149 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	nhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	kratoslog "github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestWildcardMatches(t *testing.T) {
+	assert.True(t, wildcardMatches("", "/api.v1.Users/Get"))
+	assert.True(t, wildcardMatches("/api.v1.Users/Get", "/api.v1.Users/Get"))
+	assert.False(t, wildcardMatches("/api.v1.Users/Get", "/api.v1.Users/List"))
+	assert.True(t, wildcardMatches("/api.v1.Users/*", "/api.v1.Users/List"))
+	assert.True(t, wildcardMatches("4bf92f*", "4bf92f3577b34da6a3ce929d0e0e4736"))
+	assert.False(t, wildcardMatches("4bf92f*", "00f067aa0ba902b7"))
+}
+
+func TestBoostLogging_MatchAndExpire(t *testing.T) {
+	svc := NewServiceHttp()
+	now := time.Unix(1_700_000_000, 0)
+	svc.logBoosts.now = func() time.Time { return now }
+
+	assert.Nil(t, svc.matchLogBoost("/api.v1.Users/Get", "abc"))
+	require.NoError(t, svc.BoostLogging(LogBoost{Operation: "/api.v1.Users/*", Duration: time.Minute}))
+	require.NoError(t, svc.BoostLogging(LogBoost{TraceID: "abc*", Level: "warn", ReplyPolicy: "truncated"}))
+
+	b := svc.matchLogBoost("/api.v1.Users/Get", "none")
+	require.NotNil(t, b)
+	assert.Equal(t, replyLogPolicyJSON, b.policy)
+	assert.Equal(t, kratoslog.LevelInfo, b.level)
+
+	b = svc.matchLogBoost("/api.v1.Orders/Get", "abc123")
+	require.NotNil(t, b)
+	assert.Equal(t, replyLogPolicyTruncated, b.policy)
+	assert.Equal(t, kratoslog.LevelWarn, b.level)
+	assert.Nil(t, svc.matchLogBoost("/api.v1.Orders/Get", "def"))
+
+	// The operation boost reverts after its minute; the trace boost keeps its 10m default.
+	now = now.Add(2 * time.Minute)
+	assert.Nil(t, svc.matchLogBoost("/api.v1.Users/Get", "none"))
+	assert.Len(t, svc.logBoosts.list(), 1)
+
+	svc.ClearLogBoosts()
+	assert.Nil(t, svc.matchLogBoost("/api.v1.Orders/Get", "abc123"))
+}
+
+func TestBoostLogging_Validation(t *testing.T) {
+	svc := NewServiceHttp()
+	assert.Error(t, svc.BoostLogging(LogBoost{}))
+	assert.Error(t, svc.BoostLogging(LogBoost{Operation: "/op", ReplyPolicy: "everything"}))
+	assert.Error(t, svc.BoostLogging(LogBoost{Operation: "/op", Level: "trace"}))
+	assert.Error(t, svc.BoostLogging(LogBoost{Operation: "/op", Duration: 2 * time.Hour}))
+
+	svc.conf = &conf.Http{Monitoring: &conf.MonitoringConfig{LogBoost: &conf.LogBoostConfig{
+		MaxDuration: durationpb.New(3 * time.Hour),
+	}}}
+	assert.NoError(t, svc.BoostLogging(LogBoost{Operation: "/op", Duration: 2 * time.Hour}))
+}
+
+func TestValidateLogBoostConfig(t *testing.T) {
+	assert.NoError(t, validateLogBoostConfig(nil))
+	assert.NoError(t, validateLogBoostConfig(&conf.LogBoostConfig{
+		Boosts: []*conf.LogBoostRule{{Operation: "/api.v1.Users/*", Duration: durationpb.New(5 * time.Minute)}},
+	}))
+	assert.Error(t, validateLogBoostConfig(&conf.LogBoostConfig{Path: "debug"}))
+	assert.Error(t, validateLogBoostConfig(&conf.LogBoostConfig{MaxDuration: durationpb.New(0)}))
+	assert.Error(t, validateLogBoostConfig(&conf.LogBoostConfig{Boosts: []*conf.LogBoostRule{{Level: "info"}}}))
+	assert.Error(t, validateLogBoostConfig(&conf.LogBoostConfig{
+		Boosts: []*conf.LogBoostRule{{Operation: "/op", Duration: durationpb.New(2 * time.Hour)}},
+	}))
+}
+
+func TestLogBoost_LogsBoostedRequestsWithBodies(t *testing.T) {
+	svc := logSinkService(t, &conf.LogSinkConfig{Name: "capture", Level: "debug"})
+	svc.conf.Monitoring.EnableRequestLogging = false
+	svc.refreshMonitoringSnapshotLocked()
+	sink := &recordingSink{}
+	require.NoError(t, svc.RegisterLogSink("capture", sink))
+
+	call := func(operation string) {
+		tr := newFakeTransport(operation, nil)
+		ctx := transport.NewServerContext(context.Background(), tr)
+		req, _ := structpb.NewStruct(map[string]any{"name": "alice"})
+		_, err := TracerLogPackWithMetrics(svc)(func(ctx context.Context, req any) (any, error) {
+			return req, nil
+		})(ctx, req)
+		require.NoError(t, err)
+	}
+
+	call("/api.v1.Users/Update")
+	assert.Empty(t, sink.records, "request logging is disabled")
+
+	require.NoError(t, svc.BoostLogging(LogBoost{Operation: "/api.v1.Users/Update", Level: "debug"}))
+	call("/api.v1.Users/Update")
+	call("/api.v1.Users/Get")
+	require.Len(t, sink.records, 2)
+	for _, rec := range sink.records {
+		assert.Equal(t, true, rec["log_boost"])
+		assert.Contains(t, rec["body"], `"name":"alice"`)
+	}
+	assert.Equal(t, []kratoslog.Level{kratoslog.LevelDebug, kratoslog.LevelDebug}, sink.levels)
+}
+
+func TestLogBoostHandler(t *testing.T) {
+	svc := NewServiceHttp()
+	handler := svc.logBoostHandler()
+	do := func(method, body string) (*httptest.ResponseRecorder, []logBoostBody) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, defaultLogBoostPath, strings.NewReader(body)))
+		var env struct {
+			Data []logBoostBody `json:"data"`
+		}
+		_ = json.Unmarshal(rec.Body.Bytes(), &env)
+		return rec, env.Data
+	}
+
+	rec, boosts := do(nhttp.MethodPost, `{"operation":"/api.v1.Users/*","duration":"5m","level":"debug"}`)
+	require.Equal(t, nhttp.StatusOK, rec.Code)
+	require.Len(t, boosts, 1)
+	assert.Equal(t, "/api.v1.Users/*", boosts[0].Operation)
+	assert.Equal(t, "debug", boosts[0].Level)
+	assert.Equal(t, "json", boosts[0].ReplyPolicy)
+	assert.NotEmpty(t, boosts[0].ExpiresAt)
+
+	rec, _ = do(nhttp.MethodPost, `{"operation":"/op","duration":"soon"}`)
+	assert.Equal(t, nhttp.StatusBadRequest, rec.Code)
+	rec, _ = do(nhttp.MethodPost, `{}`)
+	assert.Equal(t, nhttp.StatusBadRequest, rec.Code)
+
+	_, boosts = do(nhttp.MethodGet, "")
+	assert.Len(t, boosts, 1)
+	_, boosts = do(nhttp.MethodDelete, "")
+	assert.Empty(t, boosts)
+
+	rec, _ = do(nhttp.MethodPut, "")
+	assert.Equal(t, nhttp.StatusMethodNotAllowed, rec.Code)
+}
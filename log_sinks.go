@@ -0,0 +1,153 @@
+package http
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+
+	kratoslog "github.com/go-kratos/kratos/v2/log"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+// lynxLogSink is the name of the built-in sink backed by the Lynx logger.
+const lynxLogSink = "lynx"
+
+// LogSink receives request/response log records as structured key/value pairs. Any Kratos log.Logger
+// satisfies it, so OTLP log exporters and Kafka producers can be plugged in through their Kratos adapters.
+type LogSink interface {
+	Log(level kratoslog.Level, keyvals ...any) error
+}
+
+// RegisterLogSink registers a sink under the name used in monitoring.log_sinks. Records only reach it
+// once a sink entry with that name is configured, so sinks can be registered unconditionally.
+func (h *ServiceHttp) RegisterLogSink(name string, sink LogSink) error {
+	name = strings.TrimSpace(name)
+	if name == "" || name == lynxLogSink {
+		return fmt.Errorf("invalid log sink name %q", name)
+	}
+	if sink == nil {
+		return fmt.Errorf("log sink %q: sink is nil", name)
+	}
+	h.logSinkMu.Lock()
+	defer h.logSinkMu.Unlock()
+	if _, dup := h.logSinks[name]; dup {
+		return fmt.Errorf("log sink %q already registered", name)
+	}
+	if h.logSinks == nil {
+		h.logSinks = make(map[string]LogSink)
+	}
+	h.logSinks[name] = sink
+	return nil
+}
+
+func (h *ServiceHttp) logSink(name string) (LogSink, bool) {
+	h.logSinkMu.RLock()
+	defer h.logSinkMu.RUnlock()
+	sink, ok := h.logSinks[name]
+	return sink, ok
+}
+
+// logSinkRoute is the resolved LogSinkConfig of one sink.
+type logSinkRoute struct {
+	name       string
+	level      kratoslog.Level
+	sampleRate float64
+}
+
+// accepts reports whether a record at level is forwarded. Records below warn are sampled.
+func (r logSinkRoute) accepts(level kratoslog.Level) bool {
+	if level < r.level {
+		return false
+	}
+	return level >= kratoslog.LevelWarn || r.sampleRate >= 1 || rand.Float64() < r.sampleRate
+}
+
+// logSinkRoutes is the resolved monitoring.log_sinks list. The Lynx logger receives every record unless
+// it is configured explicitly.
+type logSinkRoutes struct {
+	lynx  logSinkRoute
+	sinks []logSinkRoute
+}
+
+func newLogSinkRoutes(cfgs []*conf.LogSinkConfig) logSinkRoutes {
+	routes := logSinkRoutes{lynx: logSinkRoute{name: lynxLogSink, level: kratoslog.LevelDebug, sampleRate: 1}}
+	for _, cfg := range cfgs {
+		r := logSinkRoute{
+			name:       strings.TrimSpace(cfg.GetName()),
+			level:      kratoslog.LevelInfo,
+			sampleRate: cfg.GetSampleRate(),
+		}
+		if lvl := strings.TrimSpace(cfg.GetLevel()); lvl != "" {
+			r.level = kratoslog.ParseLevel(lvl)
+		}
+		if r.sampleRate <= 0 {
+			r.sampleRate = 1
+		}
+		if r.name == lynxLogSink {
+			routes.lynx = r
+			continue
+		}
+		routes.sinks = append(routes.sinks, r)
+	}
+	return routes
+}
+
+// lynxLogAccepts reports whether the Lynx logger takes a record at level.
+func lynxLogAccepts(service *ServiceHttp, level kratoslog.Level) bool {
+	if service == nil {
+		return true
+	}
+	return service.monitoringSnapshotOrDefault().logSinks.lynx.accepts(level)
+}
+
+// forwardLogRecord sends a structured record to the configured sinks other than the Lynx logger. Sinks
+// that are configured but not registered are skipped; sink failures are counted, not logged, so a broken
+// sink cannot flood the primary log.
+func forwardLogRecord(service *ServiceHttp, level kratoslog.Level, keyvals []any) {
+	if service == nil {
+		return
+	}
+	for _, r := range service.monitoringSnapshotOrDefault().logSinks.sinks {
+		sink, ok := service.logSink(r.name)
+		if !ok || !r.accepts(level) {
+			continue
+		}
+		if err := sink.Log(level, keyvals...); err != nil && service.logSinkErrors != nil {
+			service.logSinkErrors.WithLabelValues(r.name).Inc()
+		}
+	}
+}
+
+// validateLogSinkConfigs rejects unnamed or duplicate sinks, unknown levels and sample rates above 1.
+func validateLogSinkConfigs(cfgs []*conf.LogSinkConfig) error {
+	seen := make(map[string]struct{}, len(cfgs))
+	for _, cfg := range cfgs {
+		name := strings.TrimSpace(cfg.GetName())
+		if name == "" {
+			return fmt.Errorf("sink name is required")
+		}
+		if _, dup := seen[name]; dup {
+			return fmt.Errorf("sink %q is configured more than once", name)
+		}
+		seen[name] = struct{}{}
+		switch strings.ToLower(strings.TrimSpace(cfg.GetLevel())) {
+		case "", "debug", "info", "warn", "error":
+		default:
+			return fmt.Errorf("sink %q: unknown level %q", name, cfg.GetLevel())
+		}
+		if r := cfg.GetSampleRate(); r < 0 || r > 1 {
+			return fmt.Errorf("sink %q: sample rate must be between 0 and 1", name)
+		}
+	}
+	return nil
+}
+
+// warnUnregisteredLogSinks logs the sinks that are configured without a registered implementation.
+func (h *ServiceHttp) warnUnregisteredLogSinks() {
+	for _, r := range h.monitoringSnapshotOrDefault().logSinks.sinks {
+		if _, ok := h.logSink(r.name); !ok {
+			log.Warnf("Log sink %q is configured but not registered; its records are dropped", r.name)
+		}
+	}
+}
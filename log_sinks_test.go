@@ -0,0 +1,155 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	kratoslog "github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSink collects the records it receives.
+type recordingSink struct {
+	mu      sync.Mutex
+	records []map[string]any
+	levels  []kratoslog.Level
+	err     error
+}
+
+func (s *recordingSink) Log(level kratoslog.Level, keyvals ...any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec := make(map[string]any, len(keyvals)/2)
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		rec[keyvals[i].(string)] = keyvals[i+1]
+	}
+	s.records = append(s.records, rec)
+	s.levels = append(s.levels, level)
+	return s.err
+}
+
+func logSinkService(t *testing.T, sinks ...*conf.LogSinkConfig) *ServiceHttp {
+	t.Helper()
+	svc := NewServiceHttp()
+	svc.conf = &conf.Http{Monitoring: &conf.MonitoringConfig{
+		EnableRequestLogging: true,
+		EnableErrorLogging:   true,
+		LogSinks:             sinks,
+	}}
+	svc.refreshMonitoringSnapshotLocked()
+	return svc
+}
+
+func TestRegisterLogSink(t *testing.T) {
+	svc := NewServiceHttp()
+	sink := &recordingSink{}
+	require.NoError(t, svc.RegisterLogSink("otlp", sink))
+	assert.Error(t, svc.RegisterLogSink("otlp", sink))
+	assert.Error(t, svc.RegisterLogSink("lynx", sink))
+	assert.Error(t, svc.RegisterLogSink(" ", sink))
+	assert.Error(t, svc.RegisterLogSink("kafka", nil))
+}
+
+func TestLogSinks_ForwardRequestAndResponse(t *testing.T) {
+	svc := logSinkService(t,
+		&conf.LogSinkConfig{Name: "otlp"},
+		&conf.LogSinkConfig{Name: "kafka", Level: "error"},
+		&conf.LogSinkConfig{Name: "unregistered"},
+	)
+	otlp, kafka := &recordingSink{}, &recordingSink{}
+	require.NoError(t, svc.RegisterLogSink("otlp", otlp))
+	require.NoError(t, svc.RegisterLogSink("kafka", kafka))
+
+	tr := newFakeTransport("/api.v1.Users/Get", map[string]string{"Authorization": "secret"})
+	ctx := transport.NewServerContext(context.Background(), tr)
+	mw := TracerLogPackWithMetrics(svc)
+
+	_, err := mw(func(ctx context.Context, req any) (any, error) { return "ok", nil })(ctx, "req")
+	require.NoError(t, err)
+	_, err = mw(func(ctx context.Context, req any) (any, error) { return nil, errors.New("boom") })(ctx, "req")
+	require.Error(t, err)
+
+	require.Len(t, otlp.records, 4)
+	assert.Equal(t, "[HTTP Request]", otlp.records[0]["msg"])
+	assert.Equal(t, "/api.v1.Users/Get", otlp.records[0]["api"])
+	assert.Equal(t, "[HTTP Response]", otlp.records[1]["msg"])
+	assert.Equal(t, "success", otlp.records[1]["status"])
+	assert.Equal(t, []kratoslog.Level{kratoslog.LevelInfo, kratoslog.LevelInfo, kratoslog.LevelInfo, kratoslog.LevelError},
+		otlp.levels)
+	headers, ok := otlp.records[0]["headers"].(map[string]string)
+	require.True(t, ok)
+	assert.NotEqual(t, "secret", headers["Authorization"])
+
+	// The error-level sink only sees the failed response.
+	require.Len(t, kafka.records, 1)
+	assert.Equal(t, "boom", kafka.records[0]["error"])
+}
+
+func TestLogSinks_LegacyFormatStillForwardsStructuredRecords(t *testing.T) {
+	svc := logSinkService(t, &conf.LogSinkConfig{Name: "otlp"})
+	svc.conf.Monitoring.LegacyLogFormat = true
+	svc.refreshMonitoringSnapshotLocked()
+	sink := &recordingSink{}
+	require.NoError(t, svc.RegisterLogSink("otlp", sink))
+
+	logHTTPRequest(context.Background(), svc, httpLogRecord{api: "/op", clientIP: "10.0.0.1"}, newFakeHeader(nil), "req")
+	require.Len(t, sink.records, 1)
+	assert.Equal(t, "10.0.0.1", sink.records[0]["client_ip"])
+}
+
+func TestLogSinkRoute_Accepts(t *testing.T) {
+	routes := newLogSinkRoutes([]*conf.LogSinkConfig{
+		{Name: "lynx", Level: "warn"},
+		{Name: "sampled", Level: "debug", SampleRate: 0.000001},
+	})
+	assert.False(t, routes.lynx.accepts(kratoslog.LevelInfo))
+	assert.True(t, routes.lynx.accepts(kratoslog.LevelError))
+
+	require.Len(t, routes.sinks, 1)
+	sampled := routes.sinks[0]
+	kept := 0
+	for range 1000 {
+		if sampled.accepts(kratoslog.LevelInfo) {
+			kept++
+		}
+	}
+	assert.Less(t, kept, 10)
+	// Warnings and errors are never sampled out.
+	assert.True(t, sampled.accepts(kratoslog.LevelWarn))
+	assert.True(t, sampled.accepts(kratoslog.LevelError))
+
+	// Without configuration the Lynx logger takes everything.
+	assert.True(t, newLogSinkRoutes(nil).lynx.accepts(kratoslog.LevelDebug))
+	assert.True(t, lynxLogAccepts(nil, kratoslog.LevelDebug))
+}
+
+func TestLogSinks_CountsSinkErrors(t *testing.T) {
+	svc := logSinkService(t, &conf.LogSinkConfig{Name: "broken"})
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_log_sink_errors_total"}, []string{"sink"})
+	svc.logSinkErrors = counter
+	require.NoError(t, svc.RegisterLogSink("broken", &recordingSink{err: errors.New("unavailable")}))
+
+	forwardLogRecord(svc, kratoslog.LevelInfo, []any{"msg", "x"})
+	forwardLogRecord(svc, kratoslog.LevelError, []any{"msg", "y"})
+	assert.Equal(t, 2.0, testutil.ToFloat64(counter.WithLabelValues("broken")))
+}
+
+func TestValidateLogSinkConfigs(t *testing.T) {
+	assert.NoError(t, validateLogSinkConfigs(nil))
+	assert.NoError(t, validateLogSinkConfigs([]*conf.LogSinkConfig{
+		{Name: "lynx", Level: "WARN"},
+		{Name: "otlp", SampleRate: 0.5},
+	}))
+	assert.Error(t, validateLogSinkConfigs([]*conf.LogSinkConfig{{}}))
+	assert.Error(t, validateLogSinkConfigs([]*conf.LogSinkConfig{{Name: "a"}, {Name: "a"}}))
+	assert.Error(t, validateLogSinkConfigs([]*conf.LogSinkConfig{{Name: "a", Level: "verbose"}}))
+	assert.Error(t, validateLogSinkConfigs([]*conf.LogSinkConfig{{Name: "a", SampleRate: 1.5}}))
+	assert.Error(t, validateLogSinkConfigs([]*conf.LogSinkConfig{{Name: "a", SampleRate: -0.1}}))
+}
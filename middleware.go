@@ -4,6 +4,7 @@ package http
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -11,6 +12,8 @@ import (
 	"github.com/go-kratos/kratos/v2/middleware"
 	"github.com/go-kratos/kratos/v2/middleware/recovery"
 	"github.com/go-kratos/kratos/v2/middleware/tracing"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
 	"github.com/go-lynx/lynx-http/conf"
 	"github.com/go-lynx/lynx/log"
 	"google.golang.org/protobuf/proto"
@@ -45,25 +48,103 @@ func (h *ServiceHttp) buildMiddlewares() []middleware.Middleware {
 	// Order matters: middlewares execute outermost-first in the order appended.
 	// Tracing runs first so the span/trace ID is in context for logging, metrics, and the
 	// downstream handler; recovery sits after validation so panics in any layer are caught.
+	// Client detection runs before them so every layer, including metrics, sees the client.
+	if classifier := newClientClassifier(cfg.ClientInfo); classifier != nil {
+		middlewares = append(middlewares, clientInfoMiddleware(classifier))
+		log.Infof("Client info middleware enabled")
+	}
+	if policy := newClientVersionPolicy(cfg.ClientInfo); policy != nil {
+		middlewares = append(middlewares, clientVersionMiddleware(policy))
+		log.Infof("Minimum client version middleware enabled (%d platforms)", len(policy.minimums))
+	}
+
 	if middlewareCfg.EnableTracing {
 		middlewares = append(middlewares, tracing.Server(tracing.WithTracerName(currentLynxName())))
 		log.Infof("Tracing middleware enabled")
 	}
 
+	// Logging and metrics skip the routes in monitoring.excluded_routes, such as probes.
 	if middlewareCfg.EnableLogging {
-		middlewares = append(middlewares, h.loggingMiddleware())
+		middlewares = append(middlewares, h.skipExcludedRoutes(h.loggingMiddleware()))
 		log.Infof("Logging middleware enabled")
 	}
 
 	// Metrics: use either standalone metricsMiddleware or TracerLogPackWithMetrics to avoid duplicate metrics
 	if middlewareCfg.EnableTracing && middlewareCfg.EnableLogging && middlewareCfg.EnableMetrics {
-		middlewares = append(middlewares, TracerLogPackWithMetrics(h))
+		middlewares = append(middlewares, h.skipExcludedRoutes(TracerLogPackWithMetrics(h)))
 		log.Infof("TracerLogPackWithMetrics middleware enabled (tracing + logging + metrics)")
 	} else if middlewareCfg.EnableMetrics {
-		middlewares = append(middlewares, h.metricsMiddleware())
+		middlewares = append(middlewares, h.skipExcludedRoutes(h.metricsMiddleware()))
 		log.Infof("Metrics middleware enabled")
 	}
 
+	// Active request tracking sees the trace ID and gives the handler chain a cancellable context
+	if cfg.GetMonitoring().GetActiveRequests().GetEnabled() {
+		middlewares = append(middlewares, h.activeRequestsMiddleware())
+		log.Infof("Active request tracking enabled")
+	}
+
+	// Cost accounting wraps the handler chain so every layer below can record units
+	if cfg.Monitoring != nil {
+		if policy := newRequestCostPolicy(cfg.Monitoring.RequestCost); policy != nil {
+			middlewares = append(middlewares, h.requestCostMiddleware(policy))
+			log.Infof("Request cost accounting enabled")
+		}
+	}
+
+	// Deadline propagation narrows the context before any handler work, after metrics so rejections are counted
+	if policy := newDeadlinePolicy(middlewareCfg.DeadlinePropagation); policy != nil {
+		middlewares = append(middlewares, h.deadlinePropagationMiddleware(policy))
+		log.Infof("Deadline propagation middleware enabled (headers: %s)", strings.Join(policy.headers, ", "))
+	}
+
+	if policy := newRetryBudgetPolicy(middlewareCfg.RetryBudget); policy != nil {
+		middlewares = append(middlewares, h.retryBudgetMiddleware(policy))
+		log.Infof("Retry budget middleware enabled")
+	}
+
+	if policy := newRetryStormPolicy(middlewareCfg.RetryStorm); policy != nil {
+		middlewares = append(middlewares, h.retryStormMiddleware(policy))
+		log.Infof("Retry storm detection enabled (%d failures in %s)", policy.threshold, policy.window)
+	}
+
+	if policy := newSignedURLPolicy(cfg.SignedUrls); policy != nil {
+		middlewares = append(middlewares, signedURLMiddleware(policy))
+		log.Infof("Signed URL middleware enabled (%d operations)", len(policy.operations))
+	}
+
+	if policy := newSessionPolicy(cfg.Session); policy != nil {
+		middlewares = append(middlewares, h.sessionMiddleware(policy))
+		log.Infof("Session middleware enabled (store %s)", policy.store)
+	}
+
+	// Tenant extraction runs before virtual host middleware, so it can act on the tenant
+	if policy := newTenantPolicy(cfg.Tenant); policy != nil {
+		middlewares = append(middlewares, h.tenantMiddleware(policy))
+		log.Infof("Tenant middleware enabled (source %s)", policy.source)
+	}
+
+	// Residency checks run after tenant extraction, which provides the tenant whose home region is compared
+	if policy := newResidencyPolicy(cfg.Residency); policy != nil {
+		middlewares = append(middlewares, h.residencyMiddleware(policy))
+		log.Infof("Residency middleware enabled (region %s, enforcement %s)", policy.region, policy.enforcement)
+	}
+
+	// Virtual hosts restrict the routes and add the middleware of the host the request was sent to
+	if policy := newVirtualHostPolicy(cfg.VirtualHosts); policy != nil {
+		middlewares = append(middlewares, h.virtualHostMiddleware(policy))
+		log.Infof("Virtual host middleware enabled (%d hosts)", len(policy.hosts))
+	}
+
+	// Request filters normalize the decoded request before defaults and validation see it
+	middlewares = append(middlewares, h.requestFilterMiddleware())
+
+	// Defaults fill unset request fields before validation sees them
+	if policy := newRequestDefaultsPolicy(cfg.GetRequest().GetDefaults()); policy != nil {
+		middlewares = append(middlewares, requestDefaultsMiddleware(policy))
+		log.Infof("Request defaults middleware enabled (%d rules)", len(policy.rules))
+	}
+
 	if middlewareCfg.EnableValidation {
 		middlewares = append(middlewares, validate.ProtoValidate())
 		log.Infof("Validation middleware enabled")
@@ -74,17 +155,47 @@ func (h *ServiceHttp) buildMiddlewares() []middleware.Middleware {
 		log.Infof("Recovery middleware enabled")
 	}
 
+	// Deduplication runs inside recovery, so a panicking first request releases its duplicates,
+	// and before rate limiting, so collapsed duplicates do not consume tokens
+	if policy := newDedupPolicy(middlewareCfg.Dedup); policy != nil {
+		middlewares = append(middlewares, h.dedupMiddleware(policy))
+		log.Infof("Request deduplication middleware enabled (window %s)", policy.window)
+	}
+
+	// Coalescing also runs inside recovery and before rate limiting, so waiting requests do not consume tokens
+	if policy := newCoalescePolicy(middlewareCfg.GetCoalesce()); policy != nil {
+		middlewares = append(middlewares, h.coalesceMiddleware(policy))
+		log.Infof("Request coalescing middleware enabled")
+	}
+
 	if middlewareCfg.EnableRateLimit {
 		middlewares = append(middlewares, h.rateLimitMiddleware())
 		log.Infof("Rate limit middleware enabled")
 	}
 
+	if l := newFingerprintLimiter(cfg.GetSecurity().GetFingerprint()); l != nil {
+		middlewares = append(middlewares, h.fingerprintRateLimitMiddleware(l))
+		log.Infof("Per-fingerprint rate limit enabled (%.2f/s, burst %d)", float64(l.rate), l.burst)
+	}
+
 	// Concurrent request limit middleware (limits in-flight requests, not TCP connections)
 	if h.maxConnections > 0 || h.maxConcurrentRequests > 0 {
 		middlewares = append(middlewares, h.connectionLimitMiddleware())
 		log.Infof("Concurrent request limit middleware enabled")
 	}
 
+	// The resource guard sheds low-priority traffic while heap or goroutines are above their thresholds
+	if cfg.GetPerformance().GetResourceGuard().GetEnabled() {
+		middlewares = append(middlewares, h.resourceGuardMiddleware())
+		log.Infof("Resource guard middleware enabled")
+	}
+
+	// Per-group admission queues shed load with a busy error once their bounded backlog is full
+	if cfg.Performance != nil && len(cfg.Performance.AdmissionQueues) > 0 {
+		middlewares = append(middlewares, h.admissionQueueMiddleware())
+		log.Infof("Admission queue middleware enabled (%d groups)", len(cfg.Performance.AdmissionQueues))
+	}
+
 	// Circuit breaker middleware
 	middlewares = append(middlewares, h.circuitBreakerMiddleware())
 	log.Infof("Circuit breaker middleware enabled")
@@ -102,6 +213,31 @@ func (h *ServiceHttp) buildMiddlewares() []middleware.Middleware {
 		log.Infof("Control plane rate limit middleware enabled")
 	}
 
+	// Handler retries run inside rate limiting and the circuit breaker, so a retried request is admitted once
+	if policy := newHandlerRetryPolicy(middlewareCfg.GetHandlerRetry()); policy != nil {
+		middlewares = append(middlewares, h.handlerRetryMiddleware(policy))
+		log.Infof("Handler retry middleware enabled (%d operations, %d attempts)", len(policy.operations), policy.maxAttempts)
+	}
+
+	// Cache-Control and Vary headers are set on successful replies of the handler
+	if policy := newCacheControlPolicy(cfg.GetResponse().GetCacheControl()); policy != nil {
+		middlewares = append(middlewares, cacheControlMiddleware(policy))
+		log.Infof("Cache-Control middleware enabled (%d rules)", len(policy.rules))
+	}
+
+	// Mocks replace the handler, so every other middleware still runs for mocked operations
+	if policy := newMockPolicy(cfg.GetMock()); policy != nil {
+		middlewares = append(middlewares, h.mockMiddleware(policy))
+		log.Warnf("Mock middleware enabled (%d operations, %d responses); mocked operations do not run their handler",
+			len(policy.operations), len(policy.responses))
+	}
+
+	// Server-Timing measures the handler as the innermost middleware
+	if cfg.GetMonitoring().GetServerTiming().GetEnabled() {
+		middlewares = append(middlewares, serverTimingMiddleware())
+		log.Infof("Server-Timing middleware enabled")
+	}
+
 	// Custom middleware and ordering features will be implemented in future versions
 	// These features require additional protobuf definitions and implementation logic
 	// Tracked in enhancement request #HTTP-1234
@@ -182,7 +318,7 @@ func (h *ServiceHttp) recoveryMiddleware() middleware.Middleware {
 	)
 }
 
-// rateLimitMiddleware returns a rate limit middleware.
+// rateLimitMiddleware returns a rate limit middleware. Each request consumes the tokens of its route's cost rule.
 func (h *ServiceHttp) rateLimitMiddleware() middleware.Middleware {
 	return func(handler middleware.Handler) middleware.Handler {
 		return func(ctx context.Context, req any) (reply any, err error) {
@@ -193,9 +329,15 @@ func (h *ServiceHttp) rateLimitMiddleware() middleware.Middleware {
 				defer h.requestQueueLength.WithLabelValues(path).Dec()
 			}
 
-			if h.rateLimiter != nil && !h.rateLimiter.Allow() {
-				h.recordErrorMetric(method, path, "rate_limit_exceeded")
-				return nil, fmt.Errorf("rate limit exceeded")
+			if h.rateLimiter != nil {
+				now, tokens := time.Now(), h.rateLimitCosts.tokens(ctx)
+				allowed := h.rateLimiter.AllowN(now, tokens)
+				h.rateLimitHeaders.set(ctx, h.rateLimiter, now, tokens, allowed)
+				if !allowed {
+					h.recordErrorMetric(method, path, "rate_limit_exceeded")
+					h.emitRateLimitTripped(path)
+					return nil, fmt.Errorf("rate limit exceeded")
+				}
 			}
 			return handler(ctx, req)
 		}
@@ -228,28 +370,41 @@ func (h *ServiceHttp) metricsMiddleware() middleware.Middleware {
 				h.inflightRequests.WithLabelValues(path).Inc()
 				defer h.inflightRequests.WithLabelValues(path).Dec()
 			}
+			if h.otelMetrics != nil {
+				h.otelMetrics.addInflight(ctx, path, 1)
+				defer h.otelMetrics.addInflight(context.WithoutCancel(ctx), path, -1)
+			}
 
+			stats := h.startRequestStats()
 			reply, err = handler(ctx, req)
 
-			duration := time.Since(start).Seconds()
+			elapsed := time.Since(start)
+			duration := elapsed.Seconds()
+			stats.finish(path, elapsed)
+			stats.countHitters(ctx, path)
+			h.recordSLO(path, elapsed, err)
+			h.recordAnomaly(path, err)
 			if h.requestDuration != nil {
 				h.requestDuration.WithLabelValues(method, path).Observe(duration)
 			}
 
+			status := "success"
+			if err != nil {
+				status = "error"
+			}
 			if h.requestCounter != nil {
-				status := "success"
-				if err != nil {
-					status = "error"
-				}
 				h.requestCounter.WithLabelValues(method, path, status).Inc()
 			}
+			h.otelMetrics.recordRequest(ctx, method, path, status, duration)
 
-			// Response size is only measurable for proto replies.
-			if h.responseSize != nil && reply != nil {
+			// Response size is only measurable for proto replies, and skipped in perf mode.
+			if (h.responseSize != nil || h.otelMetrics != nil) && reply != nil && !h.monitoringSnapshotOrDefault().perfMode {
 				if msg, ok := reply.(proto.Message); ok {
-					if data, marshalErr := proto.Marshal(msg); marshalErr == nil {
-						h.responseSize.WithLabelValues(method, path).Observe(float64(len(data)))
+					size := proto.Size(msg)
+					if h.responseSize != nil {
+						h.responseSize.WithLabelValues(method, path).Observe(float64(size))
 					}
+					h.otelMetrics.recordResponseSize(ctx, method, path, size)
 				}
 			}
 
@@ -258,14 +413,57 @@ func (h *ServiceHttp) metricsMiddleware() middleware.Middleware {
 			}
 
 			if h.routeRequestCounter != nil && h.routeMetricsEnabled() {
-				status := "success"
-				if err != nil {
-					status = "error"
-				}
 				h.routeRequestCounter.WithLabelValues(route, method, status).Inc()
 			}
 
+			h.recordCallerMetrics(ctx, route, method, status, duration)
+			h.recordClientMetrics(ctx, status)
+
 			return reply, err
 		}
 	}
 }
+
+// routeExcluded reports whether the request's operation or path matches monitoring.excluded_routes.
+func (h *ServiceHttp) routeExcluded(ctx context.Context) bool {
+	patterns := h.monitoringSnapshotOrDefault().excludedRoutes
+	if len(patterns) == 0 {
+		return false
+	}
+	operation, path := requestRoute(ctx)
+	return routeMatchesAny(patterns, operation, path)
+}
+
+// requestRoute returns the operation and URL path of the request in ctx; either is "" when unknown.
+func requestRoute(ctx context.Context) (operation, path string) {
+	if tr, ok := transport.FromServerContext(ctx); ok {
+		operation = tr.Operation()
+	}
+	if req, ok := http.RequestFromServerContext(ctx); ok && req != nil {
+		path = req.URL.Path
+	}
+	return operation, path
+}
+
+// routeMatchesAny reports whether operation or path matches one of patterns.
+func routeMatchesAny(patterns []string, operation, path string) bool {
+	for _, pattern := range patterns {
+		if operation != "" && wildcardMatches(pattern, operation) || path != "" && wildcardMatches(pattern, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// skipExcludedRoutes bypasses m for requests to excluded routes.
+func (h *ServiceHttp) skipExcludedRoutes(m middleware.Middleware) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		next := m(handler)
+		return func(ctx context.Context, req any) (any, error) {
+			if h.routeExcluded(ctx) {
+				return handler(ctx, req)
+			}
+			return next(ctx, req)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package http
+
+import (
+	"context"
+	nhttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSkipExcludedRoutes(t *testing.T) {
+	h := &ServiceHttp{conf: &conf.Http{Monitoring: &conf.MonitoringConfig{
+		ExcludedRoutes: []string{"/grpc.health.v1.Health/*", " /healthz "},
+	}}}
+	var observed []string
+	observe := h.skipExcludedRoutes(func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			tr, _ := transport.FromServerContext(ctx)
+			observed = append(observed, tr.Operation())
+			return handler(ctx, req)
+		}
+	})
+	handler := observe(func(context.Context, any) (any, error) { return "ok", nil })
+
+	for _, op := range []string{"/grpc.health.v1.Health/Check", "/api.v1.Users/Get"} {
+		reply, err := handler(transport.NewServerContext(context.Background(), newFakeTransport(op, nil)), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", reply, "excluded routes still reach the handler")
+	}
+	assert.Equal(t, []string{"/api.v1.Users/Get"}, observed)
+
+	// Patterns also match the request path when the operation differs.
+	observed = nil
+	srv := http.NewServer(http.Middleware(observe))
+	srv.Route("/").GET("/healthz", func(ctx http.Context) error {
+		http.SetOperation(ctx, "/api.v1.Probe/Live")
+		_, err := ctx.Middleware(func(context.Context, any) (any, error) { return nil, nil })(ctx, nil)
+		return err
+	})
+	srv.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(nhttp.MethodGet, "/healthz", nil))
+	assert.Empty(t, observed)
+}
@@ -0,0 +1,226 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	nhttp "net/http"
+	"strings"
+	"sync"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// mockHeader marks mocked replies, so clients can tell them from real ones.
+const mockHeader = "X-Mock"
+
+// mockResponse is a resolved MockResponse.
+type mockResponse struct {
+	operation string
+	data      json.RawMessage
+	status    int
+	reason    string
+	message   string
+}
+
+// mockPolicy is the resolved MockConfig.
+type mockPolicy struct {
+	operations    []string
+	unimplemented bool
+	responses     []mockResponse
+	files         *protoregistry.Files
+
+	// replies caches the reply of each operation: a proto.Message cloned per request, json.RawMessage or error.
+	replies sync.Map
+}
+
+// newMockPolicy returns nil when mocking is disabled or nothing is mocked.
+func newMockPolicy(cfg *conf.MockConfig) *mockPolicy {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	p := &mockPolicy{unimplemented: cfg.GetUnimplemented(), files: protoregistry.GlobalFiles}
+	for _, op := range cfg.GetOperations() {
+		if op = strings.TrimSpace(op); op != "" {
+			p.operations = append(p.operations, op)
+		}
+	}
+	for _, r := range cfg.GetResponses() {
+		op := strings.TrimSpace(r.GetOperation())
+		if op == "" {
+			continue
+		}
+		res := mockResponse{operation: op, status: int(r.GetStatus()), reason: r.GetReason(), message: r.GetMessage()}
+		if data := strings.TrimSpace(r.GetData()); data != "" {
+			res.data = json.RawMessage(data)
+		}
+		p.responses = append(p.responses, res)
+	}
+	if len(p.operations) == 0 && len(p.responses) == 0 && !p.unimplemented {
+		return nil
+	}
+	return p
+}
+
+// response returns the first override matching the route, or nil.
+func (p *mockPolicy) response(operation, path string) *mockResponse {
+	for i := range p.responses {
+		if routeMatchesAny([]string{p.responses[i].operation}, operation, path) {
+			return &p.responses[i]
+		}
+	}
+	return nil
+}
+
+// mocked reports whether the route is mocked without running its handler.
+func (p *mockPolicy) mocked(operation, path string) bool {
+	return routeMatchesAny(p.operations, operation, path) || p.response(operation, path) != nil
+}
+
+// reply returns the mocked reply of operation, or the error of its override.
+func (p *mockPolicy) reply(operation, path string) (any, error) {
+	res := p.response(operation, path)
+	if res != nil && res.status >= nhttp.StatusBadRequest {
+		return nil, errors.New(res.status, res.reason, res.message)
+	}
+	cached, ok := p.replies.Load(operation)
+	if !ok {
+		var data json.RawMessage
+		if res != nil {
+			data = res.data
+		}
+		cached = p.build(operation, data)
+		cached, _ = p.replies.LoadOrStore(operation, cached)
+	}
+	switch v := cached.(type) {
+	case proto.Message:
+		return proto.Clone(v), nil
+	case error:
+		return nil, errors.InternalServer("MOCK_INVALID", v.Error())
+	default:
+		return v, nil
+	}
+}
+
+// build generates the reply of operation: the example of the output message of its proto method with the fields
+// of data replaced, or data as is for operations without a proto method.
+func (p *mockPolicy) build(operation string, data json.RawMessage) any {
+	md := mockMethod(p.files, operation)
+	if md == nil {
+		if data == nil {
+			return nil
+		}
+		return data
+	}
+	out := md.Output()
+	example, _ := examples{jsonNames: true}.message(out, nil, 0)
+	body, err := json.Marshal(example)
+	if err != nil {
+		return fmt.Errorf("mock of %s: %w", operation, err)
+	}
+	if data != nil {
+		if body, err = mergeMockData(out, body, data); err != nil {
+			log.Errorf("Invalid mock data for %s: %v", operation, err)
+			return fmt.Errorf("mock of %s: %w", operation, err)
+		}
+	}
+	var msg proto.Message
+	if mt, err := protoregistry.GlobalTypes.FindMessageByName(out.FullName()); err == nil {
+		msg = mt.New().Interface()
+	} else {
+		msg = dynamicpb.NewMessage(out)
+	}
+	if err := protojson.Unmarshal(body, msg); err != nil {
+		log.Errorf("Invalid mock data for %s: %v", operation, err)
+		return fmt.Errorf("mock of %s: %w", operation, err)
+	}
+	return msg
+}
+
+// mockMethod returns the proto method of an operation such as "/helloworld.v1.Greeter/SayHello", or nil.
+func mockMethod(files *protoregistry.Files, operation string) protoreflect.MethodDescriptor {
+	service, method, ok := strings.Cut(strings.TrimPrefix(operation, "/"), "/")
+	if !ok {
+		return nil
+	}
+	d, err := files.FindDescriptorByName(protoreflect.FullName(service + "." + method))
+	if err != nil {
+		return nil
+	}
+	md, _ := d.(protoreflect.MethodDescriptor)
+	return md
+}
+
+// mergeMockData replaces the fields of the example set in data, which may use JSON or proto field names.
+func mergeMockData(md protoreflect.MessageDescriptor, example []byte, data json.RawMessage) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("data must be a JSON object: %w", err)
+	}
+	merged := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(example, &merged); err != nil {
+		return nil, err
+	}
+	for name, value := range fields {
+		fd := md.Fields().ByJSONName(name)
+		if fd == nil {
+			fd = md.Fields().ByName(protoreflect.Name(name))
+		}
+		if fd == nil {
+			return nil, fmt.Errorf("unknown field %q of %s", name, md.FullName())
+		}
+		merged[fd.JSONName()] = value
+	}
+	return json.Marshal(merged)
+}
+
+// mockMiddleware replies to mocked operations with their mocked reply instead of running the handler. With
+// unimplemented set, handlers failing with 501 Not Implemented are replaced by the mock too.
+func (h *ServiceHttp) mockMiddleware(p *mockPolicy) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			operation, path := requestRoute(ctx)
+			if !p.mocked(operation, path) {
+				reply, err := handler(ctx, req)
+				if !p.unimplemented || errors.FromError(err).Code != nhttp.StatusNotImplemented {
+					return reply, err
+				}
+			}
+			if tr, ok := transport.FromServerContext(ctx); ok {
+				tr.ReplyHeader().Set(mockHeader, "true")
+			}
+			return p.reply(operation, path)
+		}
+	}
+}
+
+// validateMockConfig requires an operation and a JSON object or error status for every response.
+func validateMockConfig(cfg *conf.MockConfig) error {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	for i, r := range cfg.GetResponses() {
+		if strings.TrimSpace(r.GetOperation()) == "" {
+			return fmt.Errorf("responses[%d]: operation is required", i)
+		}
+		if s := r.GetStatus(); s != 0 && (s < nhttp.StatusBadRequest || s > 599) {
+			return fmt.Errorf("responses[%d]: status must be an error status between 400 and 599, got %d", i, s)
+		}
+		if data := strings.TrimSpace(r.GetData()); data != "" {
+			var fields map[string]json.RawMessage
+			if err := json.Unmarshal([]byte(data), &fields); err != nil {
+				return fmt.Errorf("responses[%d]: data must be a JSON object: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
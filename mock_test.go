@@ -0,0 +1,111 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx-http/httptesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/apipb"
+	"google.golang.org/protobuf/types/known/typepb"
+)
+
+func mockTestPolicy(t *testing.T, cfg *conf.MockConfig) *mockPolicy {
+	t.Helper()
+	cfg.Enabled = true
+	require.NoError(t, validateMockConfig(cfg))
+	p := newMockPolicy(cfg)
+	require.NotNil(t, p)
+	p.files = sdkTestFiles(t)
+	return p
+}
+
+func TestMockMiddleware_GeneratedReply(t *testing.T) {
+	h := NewServiceHttp()
+	p := mockTestPolicy(t, &conf.MockConfig{Operations: []string{"/catalog.v1.Apis/*"}})
+
+	res := httptesting.NewMiddlewareTester(t, h.mockMiddleware(p)).
+		Run(httptesting.Request{Operation: "/catalog.v1.Apis/GetApi", Path: "/v1/apis/x"}).
+		AssertNoError().AssertHandlerCalled(false).AssertReplyHeader(mockHeader, "true")
+	api, ok := res.Reply.(*apipb.Api)
+	require.True(t, ok, "replies have the Go type of the output message, %T", res.Reply)
+	assert.Equal(t, "name", api.GetName())
+	assert.Equal(t, "file_name", api.GetSourceContext().GetFileName())
+	require.Len(t, api.GetMethods(), 1)
+	assert.Equal(t, "request_type_url", api.GetMethods()[0].GetRequestTypeUrl())
+	assert.Equal(t, typepb.Syntax_SYNTAX_PROTO3, api.GetSyntax())
+
+	// Each request gets its own copy.
+	api.Name = "changed"
+	res = httptesting.NewMiddlewareTester(t, h.mockMiddleware(p)).
+		Run(httptesting.Request{Operation: "/catalog.v1.Apis/GetApi", Path: "/v1/apis/x"})
+	assert.Equal(t, "name", res.Reply.(*apipb.Api).GetName())
+
+	httptesting.NewMiddlewareTester(t, h.mockMiddleware(p)).
+		Run(httptesting.Request{Operation: "/other.v1.Users/Get", Path: "/v1/users/1"}).
+		AssertHandlerCalled(true).AssertReplyHeader(mockHeader, "")
+}
+
+func TestMockMiddleware_Responses(t *testing.T) {
+	h := NewServiceHttp()
+	p := mockTestPolicy(t, &conf.MockConfig{Responses: []*conf.MockResponse{
+		{Operation: "/catalog.v1.Apis/GetApi", Data: `{"name":"users","source_context":null,"methods":[],"syntax":"SYNTAX_PROTO2"}`},
+		{Operation: "/catalog.v1.Apis/UpdateSource", Status: 409, Reason: "API_LOCKED", Message: "locked"},
+		{Operation: "/catalog.v1.Apis/CreateApi", Data: `{"missing":1}`},
+		{Operation: "/reports/*", Data: `{"id":"r-1"}`},
+	}})
+	run := func(operation, path string) *httptesting.Result {
+		return httptesting.NewMiddlewareTester(t, h.mockMiddleware(p)).
+			Run(httptesting.Request{Operation: operation, Path: path}).AssertHandlerCalled(false)
+	}
+
+	api := run("/catalog.v1.Apis/GetApi", "/v1/apis/users").AssertNoError().Reply.(*apipb.Api)
+	assert.Equal(t, "users", api.GetName())
+	assert.Nil(t, api.GetSourceContext(), "null clears a field")
+	assert.Empty(t, api.GetMethods())
+	assert.Equal(t, typepb.Syntax_SYNTAX_PROTO2, api.GetSyntax())
+	assert.Equal(t, "version", api.GetVersion(), "fields left out of data keep their example")
+
+	run("/catalog.v1.Apis/UpdateSource", "/v1/apis/users/source").AssertError(409, "API_LOCKED")
+	run("/catalog.v1.Apis/CreateApi", "/v1/apis").AssertError(500, "MOCK_INVALID")
+
+	// Routes without a proto method reply with the data as is.
+	res := run("", "/reports/7").AssertNoError()
+	body, err := json.Marshal(res.Reply)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"r-1"}`, string(body))
+}
+
+func TestMockMiddleware_Unimplemented(t *testing.T) {
+	h := NewServiceHttp()
+	p := mockTestPolicy(t, &conf.MockConfig{Unimplemented: true})
+	tester := httptesting.NewMiddlewareTester(t, h.mockMiddleware(p)).WithHandler(func(context.Context, any) (any, error) {
+		return nil, status.Error(codes.Unimplemented, "method GetApi not implemented")
+	})
+	res := tester.Run(httptesting.Request{Operation: "/catalog.v1.Apis/GetApi", Path: "/v1/apis/x"}).
+		AssertNoError().AssertHandlerCalled(true).AssertReplyHeader(mockHeader, "true")
+	assert.IsType(t, &apipb.Api{}, res.Reply)
+
+	tester.WithHandler(func(context.Context, any) (any, error) {
+		return nil, kerrors.NotFound("API_NOT_FOUND", "missing")
+	}).Run(httptesting.Request{Operation: "/catalog.v1.Apis/GetApi", Path: "/v1/apis/x"}).
+		AssertError(404, "API_NOT_FOUND").AssertReplyHeader(mockHeader, "")
+}
+
+func TestValidateMockConfig(t *testing.T) {
+	require.NoError(t, validateMockConfig(nil))
+	require.NoError(t, validateMockConfig(&conf.MockConfig{Responses: []*conf.MockResponse{{Data: "["}}}), "disabled")
+	require.NoError(t, validateMockConfig(&conf.MockConfig{Enabled: true, Responses: []*conf.MockResponse{
+		{Operation: "/a/*", Data: `{"a":1}`}, {Operation: "/b", Status: 503},
+	}}))
+	assert.ErrorContains(t, validateMockConfig(&conf.MockConfig{Enabled: true, Responses: []*conf.MockResponse{{Data: "{}"}}}), "operation is required")
+	assert.ErrorContains(t, validateMockConfig(&conf.MockConfig{Enabled: true, Responses: []*conf.MockResponse{{Operation: "/a", Data: "[1]"}}}), "JSON object")
+	assert.ErrorContains(t, validateMockConfig(&conf.MockConfig{Enabled: true, Responses: []*conf.MockResponse{{Operation: "/a", Status: 200}}}), "error status")
+	assert.Nil(t, newMockPolicy(&conf.MockConfig{Enabled: true}), "nothing to mock")
+}
@@ -59,51 +59,97 @@ var (
 	httpRequestQueueLength   *prometheus.GaugeVec
 	httpRouteRequestCounter  *prometheus.CounterVec
 	httpRouteRequestDuration *prometheus.HistogramVec
+	httpCallerRequestCounter *prometheus.CounterVec
+	httpCallerDuration       *prometheus.HistogramVec
+	httpSLOBurnRate          *prometheus.GaugeVec
+	httpSLOBudgetRemaining   *prometheus.GaugeVec
+	httpAnomalies            *prometheus.CounterVec
+	httpHoneypotHits         *prometheus.CounterVec
+	httpHoneypotRejections   prometheus.Counter
+	httpAsyncTasks           *prometheus.CounterVec
+	httpConnStateTransitions *prometheus.CounterVec
+	httpConnStateCurrent     *prometheus.GaugeVec
+	httpSlowClientProtection *prometheus.CounterVec
+	httpSlowClientBannedIPs  prometheus.Gauge
+	httpAdmissionQueueDepth  *prometheus.GaugeVec
+	httpAdmissionRejections  *prometheus.CounterVec
+	httpProxyProtocolConns   *prometheus.CounterVec
+	httpAttemptDuration      *prometheus.HistogramVec
+	httpRetryBudgetRejects   *prometheus.CounterVec
+	httpDedupCollapsed       *prometheus.CounterVec
+	httpCoalescedRequests    *prometheus.CounterVec
+	httpHandlerRetries       *prometheus.CounterVec
+	httpHandlerRetryOutcomes *prometheus.CounterVec
+	httpMultiStatusReplies   *prometheus.CounterVec
+	httpDependencyErrors     *prometheus.CounterVec
+	httpRetryStorms          *prometheus.CounterVec
+	httpRetryStormRejects    *prometheus.CounterVec
+	httpSessionEvents        *prometheus.CounterVec
+	httpSessionsActive       prometheus.Gauge
+	httpRequestCostUnits     *prometheus.CounterVec
+	httpRequestCostTotal     *prometheus.CounterVec
+	httpOversizedResponses   *prometheus.CounterVec
+	httpUnsupportedMediaType *prometheus.CounterVec
+	httpGraphQLOperations    *prometheus.CounterVec
+	httpGraphQLResolver      *prometheus.HistogramVec
+	httpLogSinkErrors        *prometheus.CounterVec
+	httpClientAppRequests    *prometheus.CounterVec
+	httpStuckRequests        *prometheus.CounterVec
+	httpResourceGuardShed    *prometheus.CounterVec
+	httpPodInfo              *prometheus.GaugeVec
+	httpVirtualHostRequests  *prometheus.CounterVec
+	httpCompressionCacheReqs *prometheus.CounterVec
+	httpCompressionCacheSize prometheus.Gauge
+	httpResidencyRequests    *prometheus.CounterVec
+	// httpHistogramOptions records the bucket layout the global histograms were registered with.
+	httpHistogramOptions histogramOptions
 )
 
 // ensureGlobalMetrics initializes metrics and registers them once in the unified registry.
-func ensureGlobalMetrics() {
+// Histogram buckets come from the first caller; later callers with a different layout get a warning,
+// since registered collectors cannot change buckets without a process restart.
+func ensureGlobalMetrics(hist histogramOptions) {
+	registered := true
 	metricsInitOnce.Do(func() {
+		registered = false
+		httpHistogramOptions = hist
 		httpRequestCounter = prometheus.NewCounterVec(
 			prometheus.CounterOpts{
 				Namespace: "lynx",
 				Subsystem: "http",
-				Name:      "requests_total",
-				Help:      "Total number of HTTP requests",
+				Name:      requestsTotalDef.name,
+				Help:      requestsTotalDef.help,
 			},
 			[]string{"method", "path", "status"},
 		)
 
 		httpRequestDuration = prometheus.NewHistogramVec(
-			prometheus.HistogramOpts{
+			hist.histogramOpts(prometheus.HistogramOpts{
 				Namespace: "lynx",
 				Subsystem: "http",
-				Name:      "request_duration_seconds",
-				Help:      "HTTP request duration in seconds",
-				Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
-			},
+				Name:      requestDurationDef.name,
+				Help:      requestDurationDef.help,
+			}, hist.durationBuckets),
 			[]string{"method", "path"},
 		)
 
 		httpResponseSize = prometheus.NewHistogramVec(
-			prometheus.HistogramOpts{
+			hist.histogramOpts(prometheus.HistogramOpts{
 				Namespace: "lynx",
 				Subsystem: "http",
-				Name:      "response_size_bytes",
-				Help:      "HTTP response size in bytes",
-				Buckets:   prometheus.ExponentialBuckets(100, 10, 8),
-			},
+				Name:      responseSizeDef.name,
+				Help:      responseSizeDef.help,
+			}, hist.responseSizeBuckets),
 			[]string{"method", "path"},
 		)
 
 		httpRequestSize = prometheus.NewHistogramVec(
-			prometheus.HistogramOpts{
+			hist.histogramOpts(prometheus.HistogramOpts{
 				Namespace: "lynx",
 				Subsystem: "http",
-				Name:      "request_size_bytes",
-				Help:      "HTTP request size in bytes",
-				Buckets:   prometheus.ExponentialBuckets(100, 10, 8),
-			},
+				Name:      requestSizeDef.name,
+				Help:      requestSizeDef.help,
+			}, hist.requestSizeBuckets),
 			[]string{"method", "path"},
 		)
 
@@ -111,8 +157,8 @@ func ensureGlobalMetrics() {
 			prometheus.CounterOpts{
 				Namespace: "lynx",
 				Subsystem: "http",
-				Name:      "errors_total",
-				Help:      "Total number of HTTP errors",
+				Name:      errorsTotalDef.name,
+				Help:      errorsTotalDef.help,
 			},
 			[]string{"method", "path", "error_type"},
 		)
@@ -131,8 +177,8 @@ func ensureGlobalMetrics() {
 			prometheus.GaugeOpts{
 				Namespace: "lynx",
 				Subsystem: "http",
-				Name:      "inflight_requests",
-				Help:      "Number of HTTP requests currently being served",
+				Name:      inflightDef.name,
+				Help:      inflightDef.help,
 			},
 			[]string{"path"},
 		)
@@ -179,16 +225,426 @@ func ensureGlobalMetrics() {
 		)
 
 		httpRouteRequestDuration = prometheus.NewHistogramVec(
-			prometheus.HistogramOpts{
+			hist.histogramOpts(prometheus.HistogramOpts{
 				Namespace: "lynx",
 				Subsystem: "http",
 				Name:      "route_request_duration_seconds",
 				Help:      "HTTP request duration per route in seconds",
-				Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
-			},
+			}, hist.durationBuckets),
 			[]string{"route", "method"},
 		)
 
+		httpCallerRequestCounter = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "caller_requests_total",
+				Help:      "Total number of requests per caller service and route",
+			},
+			[]string{"caller", "route", "method", "status"},
+		)
+
+		httpCallerDuration = prometheus.NewHistogramVec(
+			hist.histogramOpts(prometheus.HistogramOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "caller_request_duration_seconds",
+				Help:      "HTTP request duration per caller service and route in seconds",
+			}, hist.durationBuckets),
+			[]string{"caller", "route"},
+		)
+
+		httpSLOBurnRate = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "slo_burn_rate",
+				Help:      "SLO error budget burn rate per operation, objective and window",
+			},
+			[]string{"operation", "objective", "window"},
+		)
+
+		httpSLOBudgetRemaining = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "slo_error_budget_remaining",
+				Help:      "Fraction of the SLO error budget left over the longest burn window",
+			},
+			[]string{"operation", "objective"},
+		)
+
+		httpAnomalies = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "anomalies_total",
+				Help:      "Total number of intervals whose request count or error rate deviated from the baseline",
+			},
+			[]string{"route", "signal", "direction"},
+		)
+
+		httpHoneypotHits = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "honeypot_hits_total",
+				Help:      "Total number of requests to honeypot routes by configured route",
+			},
+			[]string{"route"},
+		)
+
+		httpHoneypotRejections = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "honeypot_rejections_total",
+				Help:      "Total number of requests rejected because their client was flagged by the honeypot",
+			},
+		)
+
+		httpAsyncTasks = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "async_tasks_total",
+				Help:      "Total number of async tasks by kind and outcome (accepted, rejected, succeeded, failed)",
+			},
+			[]string{"kind", "outcome"},
+		)
+
+		httpConnStateTransitions = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "connection_state_transitions_total",
+				Help:      "Total number of connection state transitions (new, active, idle, hijacked, closed)",
+			},
+			[]string{"state"},
+		)
+
+		httpConnStateCurrent = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "connections",
+				Help:      "Number of open connections by state (new, active, idle)",
+			},
+			[]string{"state"},
+		)
+
+		httpSlowClientProtection = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "slow_client_protections_total",
+				Help:      "Total number of triggered slow client protections by reason",
+			},
+			[]string{"reason"},
+		)
+
+		httpSlowClientBannedIPs = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "slow_client_banned_ips",
+				Help:      "Number of client IPs currently banned by slow client protection",
+			},
+		)
+
+		httpAdmissionQueueDepth = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "admission_queue_depth",
+				Help:      "Number of requests waiting in an admission queue",
+			},
+			[]string{"group"},
+		)
+
+		httpAdmissionRejections = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "admission_rejections_total",
+				Help:      "Total number of requests shed by admission queues",
+			},
+			[]string{"group", "reason"},
+		)
+
+		httpProxyProtocolConns = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "proxy_protocol_connections_total",
+				Help:      "Total number of accepted connections by PROXY protocol header outcome",
+			},
+			[]string{"result"},
+		)
+
+		httpAttemptDuration = prometheus.NewHistogramVec(
+			hist.histogramOpts(prometheus.HistogramOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "attempt_request_duration_seconds",
+				Help:      "HTTP request duration per route split by first attempt and retry in seconds",
+			}, hist.durationBuckets),
+			[]string{"route", "attempt"},
+		)
+
+		httpRetryBudgetRejects = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "retry_budget_rejections_total",
+				Help:      "Total number of retries rejected for exceeding the attempt budget",
+			},
+			[]string{"route"},
+		)
+
+		httpDedupCollapsed = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "deduplicated_requests_total",
+				Help:      "Total number of duplicate requests answered with the reply of an identical earlier request",
+			},
+			[]string{"route"},
+		)
+
+		httpCoalescedRequests = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "coalesced_requests_total",
+				Help:      "Total number of requests answered with the reply of an identical concurrent request",
+			},
+			[]string{"route"},
+		)
+
+		httpHandlerRetries = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "handler_retries_total",
+				Help:      "Total number of handler runs repeated after a transient error, by error reason",
+			},
+			[]string{"route", "reason"},
+		)
+
+		httpHandlerRetryOutcomes = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "handler_retry_outcomes_total",
+				Help:      "Total number of retried requests by outcome (recovered, exhausted, failed, canceled)",
+			},
+			[]string{"route", "outcome"},
+		)
+
+		httpMultiStatusReplies = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "multi_status_replies_total",
+				Help:      "Total number of multi-status replies by outcome (success, partial, failure)",
+			},
+			[]string{"route", "outcome"},
+		)
+
+		httpDependencyErrors = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "dependency_errors_total",
+				Help:      "Total number of error responses attributed to a downstream dependency",
+			},
+			[]string{"route", "dependency"},
+		)
+
+		httpRetryStorms = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "retry_storms_total",
+				Help:      "Total number of detected retry storms by action (alert, block)",
+			},
+			[]string{"route", "action"},
+		)
+
+		httpRetryStormRejects = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "retry_storm_rejections_total",
+				Help:      "Total number of requests rejected while their client was blocked for a retry storm",
+			},
+			[]string{"route"},
+		)
+
+		httpSessionEvents = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "sessions_total",
+				Help:      "Total number of session lifecycle events (created, destroyed, expired_idle, expired_absolute)",
+			},
+			[]string{"event"},
+		)
+
+		httpSessionsActive = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "sessions_active",
+				Help:      "Number of live sessions in stores that can count them",
+			},
+		)
+
+		httpGraphQLOperations = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "graphql_operations_total",
+				Help:      "Total number of GraphQL operations by operation type and result",
+			},
+			[]string{"type", "result"},
+		)
+
+		httpGraphQLResolver = prometheus.NewHistogramVec(
+			hist.histogramOpts(prometheus.HistogramOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "graphql_resolver_duration_seconds",
+				Help:      "GraphQL resolver duration per field in seconds",
+			}, hist.durationBuckets),
+			[]string{"field", "result"},
+		)
+
+		httpLogSinkErrors = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "log_sink_errors_total",
+				Help:      "Total number of request log records a log sink failed to accept",
+			},
+			[]string{"sink"},
+		)
+
+		httpClientAppRequests = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "client_app_requests_total",
+				Help:      "Total number of requests per client platform and app version",
+			},
+			[]string{"platform", "app_version", "status"},
+		)
+
+		httpRequestCostUnits = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "request_cost_units_total",
+				Help:      "Total cost units recorded by handlers per route and unit",
+			},
+			[]string{"route", "unit"},
+		)
+
+		httpRequestCostTotal = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "request_cost_total",
+				Help:      "Total weighted request cost per route",
+			},
+			[]string{"route"},
+		)
+
+		httpOversizedResponses = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "oversized_responses_total",
+				Help:      "Total number of replies over the response size limit per route and action",
+			},
+			[]string{"route", "action"},
+		)
+
+		httpUnsupportedMediaType = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "unsupported_media_type_total",
+				Help:      "Total number of request bodies rejected for their Content-Type per route",
+			},
+			[]string{"route"},
+		)
+
+		httpStuckRequests = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "stuck_requests_total",
+				Help:      "Total number of requests reported by the stuck request watchdog per route",
+			},
+			[]string{"route"},
+		)
+
+		httpResourceGuardShed = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "resource_guard_shed_total",
+				Help:      "Total number of requests shed by the resource guard per route and exceeded resource",
+			},
+			[]string{"route", "resource"},
+		)
+
+		httpPodInfo = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "pod_info",
+				Help:      "Kubernetes pod, namespace and node of the server from the Downward API, always 1",
+			},
+			[]string{"pod", "namespace", "node"},
+		)
+
+		httpVirtualHostRequests = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "virtual_host_requests_total",
+				Help:      "Total number of requests per virtual host, route and status",
+			},
+			[]string{"vhost", "route", "status"},
+		)
+		httpCompressionCacheReqs = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "compression_cache_requests_total",
+				Help:      "Total number of compressed body cache lookups by result (hit, miss)",
+			},
+			[]string{"result"},
+		)
+		httpCompressionCacheSize = prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "lynx",
+			Subsystem: "http",
+			Name:      "compression_cache_bytes",
+			Help:      "Bytes of compressed bodies held by the compression cache",
+		})
+		httpResidencyRequests = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lynx",
+				Subsystem: "http",
+				Name:      "residency_requests_total",
+				Help:      "Total number of tenant requests per serving region, home region, route and action",
+			},
+			[]string{"region", "home_region", "route", "action"},
+		)
+
 		// Register with the unified registry to avoid duplicate registrations across instances.
 		metrics.MustRegister(
 			httpRequestCounter,
@@ -203,27 +659,129 @@ func ensureGlobalMetrics() {
 			httpRequestQueueLength,
 			httpRouteRequestCounter,
 			httpRouteRequestDuration,
+			httpCallerRequestCounter,
+			httpCallerDuration,
+			httpSLOBurnRate,
+			httpSLOBudgetRemaining,
+			httpAnomalies,
+			httpHoneypotHits,
+			httpHoneypotRejections,
+			httpAsyncTasks,
+			httpConnStateTransitions,
+			httpConnStateCurrent,
+			httpSlowClientProtection,
+			httpSlowClientBannedIPs,
+			httpAdmissionQueueDepth,
+			httpAdmissionRejections,
+			httpProxyProtocolConns,
+			httpAttemptDuration,
+			httpRetryBudgetRejects,
+			httpDedupCollapsed,
+			httpCoalescedRequests,
+			httpHandlerRetries,
+			httpHandlerRetryOutcomes,
+			httpMultiStatusReplies,
+			httpDependencyErrors,
+			httpRetryStorms,
+			httpRetryStormRejects,
+			httpSessionEvents,
+			httpSessionsActive,
+			httpRequestCostUnits,
+			httpRequestCostTotal,
+			httpOversizedResponses,
+			httpUnsupportedMediaType,
+			httpGraphQLOperations,
+			httpGraphQLResolver,
+			httpLogSinkErrors,
+			httpClientAppRequests,
+			httpStuckRequests,
+			httpResourceGuardShed,
+			httpPodInfo,
+			httpVirtualHostRequests,
+			httpCompressionCacheReqs,
+			httpCompressionCacheSize,
+			httpResidencyRequests,
 		)
 	})
+	if registered && !hist.equal(httpHistogramOptions) {
+		log.Warnf("HTTP histogram bucket configuration changed after metrics registration; new buckets apply after restart")
+	}
 }
 
 func (h *ServiceHttp) initMetrics() {
+	h.confMu.RLock()
+	var (
+		histCfg *conf.HistogramConfig
+		otelCfg *conf.OtelMetricsConfig
+	)
+	if h.conf != nil && h.conf.Monitoring != nil {
+		histCfg = h.conf.Monitoring.Histograms
+		otelCfg = h.conf.Monitoring.OtelMetrics
+	}
+	h.confMu.RUnlock()
+
+	hist := histogramOptionsFromConfig(histCfg)
 	// Ensure global metrics are initialized and registered once.
-	ensureGlobalMetrics()
+	ensureGlobalMetrics(hist)
 
 	// Reuse the same set of collectors for each instance.
-	h.requestCounter = httpRequestCounter
-	h.requestDuration = httpRequestDuration
-	h.responseSize = httpResponseSize
-	h.requestSize = httpRequestSize
-	h.errorCounter = httpErrorCounter
+	// With OpenTelemetry as the only exporter the request collectors stay nil and are skipped at record time.
+	if !h.initOtelMetrics(otelCfg, hist) {
+		h.requestCounter = httpRequestCounter
+		h.requestDuration = httpRequestDuration
+		h.responseSize = httpResponseSize
+		h.requestSize = httpRequestSize
+		h.errorCounter = httpErrorCounter
+		h.inflightRequests = httpInflight
+	}
 	h.healthCheckTotal = httpHealthCheckTot
-	h.inflightRequests = httpInflight
 	h.activeConnections = httpActiveConnections
 	h.connectionPoolUsage = httpConnectionPoolUsage
 	h.requestQueueLength = httpRequestQueueLength
 	h.routeRequestCounter = httpRouteRequestCounter
 	h.routeRequestDuration = httpRouteRequestDuration
+	h.callerRequestCounter = httpCallerRequestCounter
+	h.callerRequestDuration = httpCallerDuration
+	h.sloBurnRate = httpSLOBurnRate
+	h.sloBudgetRemaining = httpSLOBudgetRemaining
+	h.anomalies = httpAnomalies
+	h.honeypotHits = httpHoneypotHits
+	h.honeypotRejections = httpHoneypotRejections
+	h.asyncTaskCounter = httpAsyncTasks
+	h.connStateTransitions = httpConnStateTransitions
+	h.connStateCurrent = httpConnStateCurrent
+	h.slowClientProtections = httpSlowClientProtection
+	h.slowClientBannedIPs = httpSlowClientBannedIPs
+	h.admissionQueueDepth = httpAdmissionQueueDepth
+	h.admissionRejections = httpAdmissionRejections
+	h.proxyProtocolConns = httpProxyProtocolConns
+	h.attemptRequestDuration = httpAttemptDuration
+	h.retryBudgetRejections = httpRetryBudgetRejects
+	h.dedupCollapsed = httpDedupCollapsed
+	h.coalescedRequests = httpCoalescedRequests
+	h.handlerRetries = httpHandlerRetries
+	h.handlerRetryOutcomes = httpHandlerRetryOutcomes
+	h.multiStatusReplies = httpMultiStatusReplies
+	h.dependencyErrors = httpDependencyErrors
+	h.retryStorms = httpRetryStorms
+	h.retryStormRejections = httpRetryStormRejects
+	h.sessionEvents = httpSessionEvents
+	h.sessionsActive = httpSessionsActive
+	h.requestCostUnits = httpRequestCostUnits
+	h.requestCostTotal = httpRequestCostTotal
+	h.oversizedResponses = httpOversizedResponses
+	h.unsupportedContentTypes = httpUnsupportedMediaType
+	h.graphQLOperations = httpGraphQLOperations
+	h.graphQLResolverDuration = httpGraphQLResolver
+	h.logSinkErrors = httpLogSinkErrors
+	h.clientAppRequests = httpClientAppRequests
+	h.stuckRequests = httpStuckRequests
+	h.resourceGuardShed = httpResourceGuardShed
+	h.podInfo = httpPodInfo
+	h.virtualHostRequests = httpVirtualHostRequests
+	h.compressionCacheRequests = httpCompressionCacheReqs
+	h.compressionCacheBytes = httpCompressionCacheSize
+	h.residencyRequests = httpResidencyRequests
 
 	h.reconfigureMetricsLoop()
 }
@@ -328,6 +886,10 @@ func (h *ServiceHttp) healthCheckHandler() nhttp.Handler {
 
 		// Execute runtime health check (includes port connectivity)
 		err := h.CheckRuntimeHealth()
+		if err == nil {
+			// Draining or degraded servers may ask load balancers to route elsewhere.
+			err = h.healthHintError()
+		}
 
 		var response map[string]any
 		var statusCode int
@@ -0,0 +1,190 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	nhttp "net/http"
+	"sync"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+)
+
+const (
+	defaultPartialSuccessCode = 207
+
+	multiStatusSuccess = "success"
+	multiStatusPartial = "partial"
+	multiStatusFailure = "failure"
+)
+
+// MultiStatus is the reply of a batch-style operation whose items succeed or fail independently. Returned from
+// a handler, it is written as {"code":207,"data":{"items":[...],"succeeded":1,"failed":1}}: each item carries
+// its own envelope code, and the top-level code is the success code when every item succeeded, the shared code
+// when every item failed with the same one, and response.multi_status.partial_success_code otherwise. It is
+// safe for concurrent use.
+type MultiStatus struct {
+	mu    sync.Mutex
+	items []multiStatusEntry
+}
+
+type multiStatusEntry struct {
+	id   string
+	data any
+	err  error
+}
+
+// NewMultiStatus returns an empty multi-status reply.
+func NewMultiStatus() *MultiStatus {
+	return &MultiStatus{}
+}
+
+// Add records the outcome of the item identified by id: data when err is nil, otherwise the error, which is
+// mapped to a body code like errors returned by handlers. Items keep the order they were added in.
+func (m *MultiStatus) Add(id string, data any, err error) *MultiStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.items = append(m.items, multiStatusEntry{id: id, data: data, err: err})
+	return m
+}
+
+// Succeeded records a successful item.
+func (m *MultiStatus) Succeeded(id string, data any) *MultiStatus {
+	return m.Add(id, data, nil)
+}
+
+// Failed records a failed item.
+func (m *MultiStatus) Failed(id string, err error) *MultiStatus {
+	if err == nil {
+		err = errors.New(errors.UnknownCode, errors.UnknownReason, "")
+	}
+	return m.Add(id, nil, err)
+}
+
+// MultiStatusItem is one item of a multi-status reply.
+type MultiStatusItem struct {
+	ID   string `json:"id"`
+	Code int    `json:"code"`
+	// Reason is the Kratos error reason of failed items.
+	Reason string `json:"reason,omitempty"`
+	// Data is the JSON-encoded result of succeeded items.
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// MultiStatusReply is the data of a multi-status envelope, as returned by DecodeMultiStatus.
+type MultiStatusReply struct {
+	// Code is the top-level envelope code.
+	Code      int               `json:"-"`
+	Items     []MultiStatusItem `json:"items"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+}
+
+// Partial reports whether some, but not all, items failed.
+func (r *MultiStatusReply) Partial() bool {
+	return r.Succeeded > 0 && r.Failed > 0
+}
+
+// multiStatusReply resolves the item codes of m and the top-level code, returning the outcome for metrics.
+func (h *ServiceHttp) multiStatusReply(m *MultiStatus, success, partial int, protoJSON *protoJSONEncoder) (*MultiStatusReply, string, error) {
+	m.mu.Lock()
+	items := append([]multiStatusEntry(nil), m.items...)
+	m.mu.Unlock()
+
+	reply := &MultiStatusReply{Items: make([]MultiStatusItem, 0, len(items))}
+	failedCode := 0
+	for _, entry := range items {
+		item := MultiStatusItem{ID: entry.id, Code: success}
+		if entry.err != nil {
+			item.Code = h.responseBodyCodeFromError(entry.err)
+			item.Reason = errors.FromError(entry.err).Reason
+			if reply.Failed == 0 {
+				failedCode = item.Code
+			} else if failedCode != item.Code {
+				failedCode = partial
+			}
+			reply.Failed++
+		} else {
+			if !shouldOmitSuccessData(entry.data) {
+				// Marshalled like the data of the standard envelope.
+				data, err := protoJSON.marshal(entry.data)
+				if err != nil {
+					return nil, "", fmt.Errorf("item %q: %w", entry.id, err)
+				}
+				item.Data = data
+			}
+			reply.Succeeded++
+		}
+		reply.Items = append(reply.Items, item)
+	}
+	switch {
+	case reply.Failed == 0:
+		reply.Code = success
+		return reply, multiStatusSuccess, nil
+	case reply.Succeeded == 0:
+		reply.Code = failedCode
+		return reply, multiStatusFailure, nil
+	default:
+		reply.Code = partial
+		return reply, multiStatusPartial, nil
+	}
+}
+
+// withMultiStatus writes *MultiStatus replies in the envelope with their resolved top-level code, and hands
+// other replies to encode. Item data is encoded with protoJSON when set.
+func (h *ServiceHttp) withMultiStatus(cfg *conf.MultiStatusConfig, success int, protoJSON *protoJSONEncoder, encode http.EncodeResponseFunc) http.EncodeResponseFunc {
+	partial := int(cfg.GetPartialSuccessCode())
+	if partial == 0 {
+		partial = defaultPartialSuccessCode
+	}
+	return func(w http.ResponseWriter, r *http.Request, data any) error {
+		m, ok := data.(*MultiStatus)
+		if !ok {
+			return encode(w, r, data)
+		}
+		reply, outcome, err := h.multiStatusReply(m, success, partial, protoJSON)
+		if err != nil {
+			return errors.InternalServer("MULTI_STATUS_ENCODE", err.Error())
+		}
+		if h.multiStatusReplies != nil {
+			_, route := requestMetadata(r.Context())
+			h.multiStatusReplies.WithLabelValues(route, outcome).Inc()
+		}
+		return encodeResponse(w, r, reply, reply.Code)
+	}
+}
+
+// DecodeMultiStatus reads and closes the response body and decodes a multi-status envelope, whatever its
+// top-level code. Envelopes without items are checked like DecodeResponse envelopes, so a request rejected as a
+// whole returns its error.
+func DecodeMultiStatus(res *nhttp.Response, opts ...DecodeOption) (*MultiStatusReply, error) {
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	var env struct {
+		Code     int               `json:"code"`
+		Data     *MultiStatusReply `json:"data"`
+		Warnings []Warning         `json:"warnings"`
+	}
+	if err := json.Unmarshal(body, &env); err == nil && env.Data != nil && env.Data.Items != nil {
+		o := newDecodeOptions(opts)
+		if o.warnings != nil {
+			*o.warnings = env.Warnings
+		}
+		env.Data.Code = env.Code
+		return env.Data, nil
+	}
+	return decodeEnvelope[*MultiStatusReply](res, body, opts)
+}
+
+// validateMultiStatusConfig rejects reserved partial success codes.
+func validateMultiStatusConfig(cfg *conf.MultiStatusConfig) error {
+	if code := cfg.GetPartialSuccessCode(); code < 0 || code == BodyCodeSystemFailure {
+		return fmt.Errorf("partial_success_code %d is reserved", code)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"io"
+	nhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/apipb"
+)
+
+func TestMultiStatus(t *testing.T) {
+	h := &ServiceHttp{conf: &conf.Http{Response: &conf.ResponseConfig{}}}
+	h.multiStatusReplies = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_multi_status_replies_total"}, []string{"route", "outcome"})
+	h.ErrorCodeMapper = func(se *kerrors.Error) int {
+		if se.Reason == "OUT_OF_STOCK" {
+			return 300001
+		}
+		return defaultErrorCode(se)
+	}
+	var reply *MultiStatus
+	srv := listStreamServer(h.responseEncoder(), func() any { return reply })
+	get := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(nhttp.MethodGet, "/export", nil))
+		return rec
+	}
+	outOfStock := kerrors.Conflict("OUT_OF_STOCK", "sku-2 is sold out")
+
+	reply = NewMultiStatus().Succeeded("sku-1", &apipb.Api{Name: "reserved"}).Failed("sku-2", outOfStock)
+	rec := get()
+	assert.Equal(t, nhttp.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"code":207,"data":{"items":[
+		{"id":"sku-1","code":200,"data":{"name":"reserved"}},
+		{"id":"sku-2","code":300001,"reason":"OUT_OF_STOCK"}],"succeeded":1,"failed":1}}`, rec.Body.String())
+	decoded, err := DecodeMultiStatus(&nhttp.Response{Header: rec.Header(), Body: io.NopCloser(rec.Body)})
+	require.NoError(t, err)
+	assert.Equal(t, 207, decoded.Code)
+	assert.True(t, decoded.Partial())
+	assert.Equal(t, "OUT_OF_STOCK", decoded.Items[1].Reason)
+
+	// Replies whose items all succeeded, or all failed alike, take the items' code.
+	reply = NewMultiStatus().Succeeded("sku-1", nil)
+	assert.JSONEq(t, `{"code":200,"data":{"items":[{"id":"sku-1","code":200}],"succeeded":1,"failed":0}}`, get().Body.String())
+	reply = NewMultiStatus().Failed("sku-1", outOfStock).Failed("sku-2", outOfStock)
+	assert.True(t, strings.HasPrefix(get().Body.String(), `{"code":300001,`))
+	reply = NewMultiStatus().Failed("sku-1", outOfStock).Failed("sku-2", kerrors.NotFound("SKU_NOT_FOUND", ""))
+	assert.True(t, strings.HasPrefix(get().Body.String(), `{"code":207,`))
+
+	for outcome, want := range map[string]float64{multiStatusPartial: 1, multiStatusSuccess: 1, multiStatusFailure: 2} {
+		assert.Equal(t, want, testutil.ToFloat64(h.multiStatusReplies.WithLabelValues("/api.v1.Export/List", outcome)), outcome)
+	}
+
+	// The partial success code is configurable, and follows a configured success code.
+	h.conf.Response = &conf.ResponseConfig{
+		Codes:       &conf.EnvelopeCodesConfig{SuccessCode: new(int32)},
+		MultiStatus: &conf.MultiStatusConfig{PartialSuccessCode: 1},
+	}
+	srv = listStreamServer(h.responseEncoder(), func() any { return reply })
+	reply = NewMultiStatus().Succeeded("sku-1", nil).Failed("sku-2", outOfStock)
+	assert.True(t, strings.HasPrefix(get().Body.String(), `{"code":1,"data":{"items":[{"id":"sku-1","code":0}`))
+}
+
+func TestDecodeMultiStatus_Error(t *testing.T) {
+	_, err := DecodeMultiStatus(envelopeResponse(nhttp.StatusOK, `{"code":403}`))
+	assert.Equal(t, 403, int(kerrors.Code(err)))
+}
+
+func TestValidateMultiStatusConfig(t *testing.T) {
+	require.NoError(t, validateMultiStatusConfig(nil))
+	require.NoError(t, validateMultiStatusConfig(&conf.MultiStatusConfig{PartialSuccessCode: 207}))
+	assert.ErrorContains(t, validateMultiStatusConfig(&conf.MultiStatusConfig{PartialSuccessCode: BodyCodeSystemFailure}), "reserved")
+	assert.ErrorContains(t, validateEnvelopeCodesConfig(&conf.Http{Response: &conf.ResponseConfig{
+		MultiStatus: &conf.MultiStatusConfig{PartialSuccessCode: 200},
+	}}), "is the success code")
+}
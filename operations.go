@@ -0,0 +1,365 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	nhttp "net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/encoding"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+const (
+	defaultOperationsPath         = "/operations"
+	defaultOperationTTL           = 24 * time.Hour
+	defaultMaxOperations          = 10000
+	memoryOperationStoreName      = "memory"
+	memoryOperationSweepInterval  = time.Minute
+	operationNamePrefix           = "operations/"
+	operationGetOperation         = "get"
+	operationNotFoundReason       = "OPERATION_NOT_FOUND"
+	operationStoreFullReason      = "OPERATION_STORE_FULL"
+	operationStoreMissingReason   = "OPERATION_STORE_UNAVAILABLE"
+	operationStoreFailedReason    = "OPERATION_STORE_FAILED"
+	operationEncodeFailedReason   = "OPERATION_ENCODE_FAILED"
+	operationAlreadyDoneReason    = "OPERATION_ALREADY_DONE"
+	operationsNotConfiguredReason = "OPERATIONS_DISABLED"
+)
+
+var (
+	// ErrOperationNotFound is returned by OperationStore.Get for unknown or expired operations.
+	ErrOperationNotFound = stderrors.New("operation not found")
+	// ErrOperationStoreFull is returned by OperationStore.Save when the store cannot take more operations.
+	ErrOperationStoreFull = stderrors.New("operation store full")
+)
+
+// Operation is a long-running operation in the google.longrunning shape. Handlers return the operation they
+// create, and clients poll GET <operations.path>/<id> until done is true.
+type Operation struct {
+	// Name is "operations/<id>".
+	Name     string          `json:"name"`
+	Done     bool            `json:"done"`
+	Metadata json.RawMessage `json:"metadata,omitempty"`
+	// Result is the JSON-encoded result of an operation done without error.
+	Result json.RawMessage `json:"result,omitempty"`
+	// Error is the error of an operation that failed.
+	Error *OperationError `json:"error,omitempty"`
+	// Owner is the "sub" claim of the creator; only the same subject can read the operation.
+	Owner      string    `json:"owner,omitempty"`
+	CreateTime time.Time `json:"create_time"`
+	UpdateTime time.Time `json:"update_time"`
+}
+
+// ID is the operation's ID, the last segment of its name.
+func (o *Operation) ID() string {
+	return strings.TrimPrefix(o.Name, operationNamePrefix)
+}
+
+// OperationError is the error of a failed operation: the body code and the Kratos reason, as in error envelopes.
+type OperationError struct {
+	Code   int    `json:"code"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// OperationStore persists operations by name. Save creates or replaces an operation.
+type OperationStore interface {
+	Save(ctx context.Context, op Operation) error
+	Get(ctx context.Context, name string) (Operation, error)
+}
+
+// RegisterOperationStore registers a store under the name used in operations.store. "memory" is built in.
+func (h *ServiceHttp) RegisterOperationStore(name string, store OperationStore) error {
+	name = strings.TrimSpace(name)
+	if name == "" || name == memoryOperationStoreName {
+		return fmt.Errorf("invalid operation store name %q", name)
+	}
+	if store == nil {
+		return fmt.Errorf("operation store %q: store is nil", name)
+	}
+	h.operationStoreMu.Lock()
+	defer h.operationStoreMu.Unlock()
+	if _, dup := h.operationStores[name]; dup {
+		return fmt.Errorf("operation store %q already registered", name)
+	}
+	if h.operationStores == nil {
+		h.operationStores = make(map[string]OperationStore)
+	}
+	h.operationStores[name] = store
+	return nil
+}
+
+// operationStore returns the configured store, creating the built-in memory store on first use.
+func (h *ServiceHttp) operationStore() (OperationStore, string, bool) {
+	h.confMu.RLock()
+	cfg := h.conf.GetOperations()
+	h.confMu.RUnlock()
+	name := strings.TrimSpace(cfg.GetStore())
+	if name == "" {
+		name = memoryOperationStoreName
+	}
+	h.operationStoreMu.RLock()
+	store, ok := h.operationStores[name]
+	h.operationStoreMu.RUnlock()
+	if ok || name != memoryOperationStoreName {
+		return store, name, ok
+	}
+	h.operationStoreMu.Lock()
+	defer h.operationStoreMu.Unlock()
+	if store, ok = h.operationStores[name]; !ok {
+		store = newMemoryOperationStore(cfg)
+		if h.operationStores == nil {
+			h.operationStores = make(map[string]OperationStore)
+		}
+		h.operationStores[name] = store
+	}
+	return store, name, true
+}
+
+// storeOperation saves op in the configured store and maps store failures to Kratos errors.
+func (h *ServiceHttp) storeOperation(ctx context.Context, op Operation) error {
+	store, name, ok := h.operationStore()
+	if !ok {
+		return errors.ServiceUnavailable(operationStoreMissingReason, fmt.Sprintf("operation store %q is not registered", name))
+	}
+	if err := store.Save(ctx, op); err != nil {
+		if stderrors.Is(err, ErrOperationStoreFull) {
+			return errors.ServiceUnavailable(operationStoreFullReason, "operation store is full")
+		}
+		return errors.InternalServer(operationStoreFailedReason, err.Error())
+	}
+	return nil
+}
+
+// loadOperation reads an operation from the configured store.
+func (h *ServiceHttp) loadOperation(ctx context.Context, name string) (Operation, error) {
+	store, storeName, ok := h.operationStore()
+	if !ok {
+		return Operation{}, errors.ServiceUnavailable(operationStoreMissingReason, fmt.Sprintf("operation store %q is not registered", storeName))
+	}
+	op, err := store.Get(ctx, name)
+	if stderrors.Is(err, ErrOperationNotFound) {
+		return Operation{}, errors.NotFound(operationNotFoundReason, "operation not found")
+	}
+	if err != nil {
+		return Operation{}, errors.InternalServer(operationStoreFailedReason, err.Error())
+	}
+	return op, nil
+}
+
+// CreateOperation stores a new pending operation with metadata, encoded with the JSON codec, and returns it for
+// the handler to reply with. The subject of ctx's auth claims becomes the owner.
+func (h *ServiceHttp) CreateOperation(ctx context.Context, metadata any) (*Operation, error) {
+	if !h.conf.GetOperations().GetEnabled() {
+		return nil, errors.InternalServer(operationsNotConfiguredReason, "operations are not enabled")
+	}
+	raw, err := encodeOperationValue(metadata)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	op := Operation{Name: operationNamePrefix + newOperationID(), Metadata: raw, CreateTime: now, UpdateTime: now}
+	if claims, ok := AuthClaimsFromContext(ctx); ok {
+		op.Owner = claims.Subject()
+	}
+	if err := h.storeOperation(ctx, op); err != nil {
+		return nil, err
+	}
+	return &op, nil
+}
+
+// UpdateOperationMetadata replaces the metadata of an unfinished operation, for example its progress.
+func (h *ServiceHttp) UpdateOperationMetadata(ctx context.Context, name string, metadata any) error {
+	raw, err := encodeOperationValue(metadata)
+	if err != nil {
+		return err
+	}
+	return h.updateOperation(ctx, name, func(op *Operation) { op.Metadata = raw })
+}
+
+// FinishOperation marks an operation done with result, or with err when it is not nil. err is mapped to a body
+// code like errors returned by handlers; its message is not exposed.
+func (h *ServiceHttp) FinishOperation(ctx context.Context, name string, result any, err error) error {
+	if err != nil {
+		opErr := &OperationError{Code: h.responseBodyCodeFromError(err), Reason: errors.FromError(err).Reason}
+		return h.updateOperation(ctx, name, func(op *Operation) { op.Done, op.Error = true, opErr })
+	}
+	raw, encErr := encodeOperationValue(result)
+	if encErr != nil {
+		return encErr
+	}
+	return h.updateOperation(ctx, name, func(op *Operation) { op.Done, op.Result = true, raw })
+}
+
+// updateOperation applies fn to a stored operation that is not done yet. It reads and saves the operation, so
+// each operation is expected to be updated from one goroutine.
+func (h *ServiceHttp) updateOperation(ctx context.Context, name string, fn func(*Operation)) error {
+	op, err := h.loadOperation(ctx, name)
+	if err != nil {
+		return err
+	}
+	if op.Done {
+		return errors.Conflict(operationAlreadyDoneReason, "operation is already done")
+	}
+	fn(&op)
+	op.UpdateTime = time.Now()
+	return h.storeOperation(ctx, op)
+}
+
+func encodeOperationValue(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if raw, ok := v.(json.RawMessage); ok {
+		return raw, nil
+	}
+	data, err := encoding.GetCodec("json").Marshal(v)
+	if err != nil {
+		return nil, errors.InternalServer(operationEncodeFailedReason, err.Error())
+	}
+	return data, nil
+}
+
+// newOperationID returns a random 128-bit operation ID.
+func newOperationID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// mountOperations registers the operations route on the server.
+func (h *ServiceHttp) mountOperations() {
+	path := strings.TrimSuffix(strings.TrimSpace(h.conf.GetOperations().GetPath()), "/")
+	if path == "" {
+		path = defaultOperationsPath
+	}
+	h.server.Route("/").GET(path+"/{id}", h.getOperationHandler(path))
+	log.Infof("Operations route mounted at %s/{id}", path)
+}
+
+// getOperationHandler reports an operation under the operation "<path>/get". Operations of another subject are
+// reported as not found.
+func (h *ServiceHttp) getOperationHandler(path string) http.HandlerFunc {
+	return func(ctx http.Context) error {
+		name := operationNamePrefix + ctx.Vars().Get("id")
+		http.SetOperation(ctx, path+"/"+operationGetOperation)
+		reply, err := ctx.Middleware(func(c context.Context, _ any) (any, error) {
+			op, err := h.loadOperation(c, name)
+			if err != nil {
+				return nil, err
+			}
+			if op.Owner != "" {
+				if claims, ok := AuthClaimsFromContext(c); !ok || claims.Subject() != op.Owner {
+					return nil, errors.NotFound(operationNotFoundReason, "operation not found")
+				}
+			}
+			return &op, nil
+		})(ctx, name)
+		if err != nil {
+			return err
+		}
+		return ctx.Result(nhttp.StatusOK, reply)
+	}
+}
+
+// memoryOperationStore keeps operations in process memory. Operations are lost on restart and not shared between
+// instances.
+type memoryOperationStore struct {
+	ttl           time.Duration
+	maxOperations int
+	now           func() time.Time
+
+	mu         sync.Mutex
+	operations map[string]memoryOperation
+	nextSweep  time.Time
+}
+
+type memoryOperation struct {
+	op Operation
+	// expires is zero until the operation is done.
+	expires time.Time
+}
+
+func newMemoryOperationStore(cfg *conf.OperationsConfig) *memoryOperationStore {
+	s := &memoryOperationStore{
+		ttl:           durationOrDefault(cfg.GetTtl().AsDuration(), defaultOperationTTL),
+		maxOperations: defaultMaxOperations,
+		now:           time.Now,
+		operations:    make(map[string]memoryOperation),
+	}
+	if cfg.GetMaxOperations() > 0 {
+		s.maxOperations = int(cfg.GetMaxOperations())
+	}
+	return s
+}
+
+// Save stores op; done operations expire after the TTL.
+func (s *memoryOperationStore) Save(_ context.Context, op Operation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if now.After(s.nextSweep) {
+		for name, o := range s.operations {
+			if !o.expires.IsZero() && !now.Before(o.expires) {
+				delete(s.operations, name)
+			}
+		}
+		s.nextSweep = now.Add(memoryOperationSweepInterval)
+	}
+	if _, ok := s.operations[op.Name]; !ok && len(s.operations) >= s.maxOperations {
+		return ErrOperationStoreFull
+	}
+	stored := memoryOperation{op: op}
+	if op.Done {
+		stored.expires = now.Add(s.ttl)
+	}
+	s.operations[op.Name] = stored
+	return nil
+}
+
+// Get returns the stored operation.
+func (s *memoryOperationStore) Get(_ context.Context, name string) (Operation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.operations[name]
+	if !ok || !o.expires.IsZero() && !s.now().Before(o.expires) {
+		return Operation{}, ErrOperationNotFound
+	}
+	return o.op, nil
+}
+
+// warnUnregisteredOperationStore logs a configured store that no RegisterOperationStore call provided.
+func (h *ServiceHttp) warnUnregisteredOperationStore() {
+	if !h.conf.GetOperations().GetEnabled() {
+		return
+	}
+	if _, name, ok := h.operationStore(); !ok {
+		log.Warnf("Operation store %q is configured but not registered; creating operations will fail", name)
+	}
+}
+
+// validateOperationsConfig checks the path and rejects negative limits.
+func validateOperationsConfig(cfg *conf.OperationsConfig) error {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	if p := strings.TrimSpace(cfg.Path); p != "" && !strings.HasPrefix(p, "/") {
+		return fmt.Errorf("path %q must start with /", p)
+	}
+	if cfg.Ttl != nil && cfg.Ttl.AsDuration() < 0 {
+		return fmt.Errorf("ttl cannot be negative")
+	}
+	if cfg.MaxOperations < 0 {
+		return fmt.Errorf("max_operations cannot be negative")
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	nhttp "net/http"
+	"sync"
+	"testing"
+	"time"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type reportProgress struct {
+	Percent int `json:"percent"`
+}
+
+func TestOperations_TestService(t *testing.T) {
+	svc, err := NewTestService(&conf.Http{Operations: &conf.OperationsConfig{Enabled: true}})
+	require.NoError(t, err)
+	defer func() { _ = svc.Close() }()
+	proceed := make(chan error)
+	svc.Server().Route("/").POST("/reports", func(ctx http.Context) error {
+		op, err := svc.CreateOperation(ctx, reportProgress{})
+		if err != nil {
+			return err
+		}
+		go func() {
+			bg := context.Background()
+			assert.NoError(t, svc.UpdateOperationMetadata(bg, op.Name, reportProgress{Percent: 50}))
+			proceed <- nil
+			if err := <-proceed; err != nil {
+				assert.NoError(t, svc.FinishOperation(bg, op.Name, nil, err))
+				return
+			}
+			assert.NoError(t, svc.FinishOperation(bg, op.Name, map[string]string{"url": "/files/report.csv"}, nil))
+			assert.Error(t, svc.FinishOperation(bg, op.Name, nil, nil))
+			proceed <- nil
+		}()
+		return ctx.Result(nhttp.StatusOK, op)
+	})
+	create := func() *Operation {
+		res, err := svc.PostJSON("/reports", nil)
+		require.NoError(t, err)
+		var op Operation
+		require.NoError(t, res.Decode(&op))
+		assert.False(t, op.Done)
+		assert.JSONEq(t, `{"percent":0}`, string(op.Metadata))
+		<-proceed
+		return &op
+	}
+	get := func(op *Operation) *Operation {
+		res, err := svc.Get("/operations/" + op.ID())
+		require.NoError(t, err)
+		var got Operation
+		require.NoError(t, res.Decode(&got))
+		return &got
+	}
+
+	op := create()
+	running := get(op)
+	assert.Equal(t, op.Name, running.Name)
+	assert.False(t, running.Done)
+	assert.JSONEq(t, `{"percent":50}`, string(running.Metadata))
+	proceed <- nil
+	<-proceed
+	done := get(op)
+	assert.True(t, done.Done)
+	assert.JSONEq(t, `{"url":"/files/report.csv"}`, string(done.Result))
+	assert.Nil(t, done.Error)
+
+	op = create()
+	proceed <- kerrors.ServiceUnavailable("WAREHOUSE_DOWN", "warehouse unreachable")
+	require.Eventually(t, func() bool { return get(op).Done }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, &OperationError{Code: 503, Reason: "WAREHOUSE_DOWN"}, get(op).Error)
+
+	res, err := svc.Get("/operations/unknown")
+	require.NoError(t, err)
+	assert.Equal(t, 404, res.Code())
+}
+
+// mapOperationStore is a registered store shared by several instances.
+type mapOperationStore struct {
+	mu  sync.Mutex
+	ops map[string][]byte
+}
+
+func (s *mapOperationStore) Save(_ context.Context, op Operation) error {
+	data, err := json.Marshal(op)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ops[op.Name] = data
+	return nil
+}
+
+func (s *mapOperationStore) Get(_ context.Context, name string) (Operation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.ops[name]
+	if !ok {
+		return Operation{}, ErrOperationNotFound
+	}
+	var op Operation
+	return op, json.Unmarshal(data, &op)
+}
+
+func TestOperations_RegisteredStore(t *testing.T) {
+	svc, err := NewTestService(&conf.Http{Operations: &conf.OperationsConfig{Enabled: true, Path: "/v1/operations", Store: "shared"}})
+	require.NoError(t, err)
+	defer func() { _ = svc.Close() }()
+	store := &mapOperationStore{ops: make(map[string][]byte)}
+	require.NoError(t, svc.RegisterOperationStore("shared", store))
+	require.Error(t, svc.RegisterOperationStore("shared", store))
+
+	op, err := svc.CreateOperation(WithAuthClaims(context.Background(), AuthClaims{"sub": "user-7"}), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "user-7", op.Owner)
+	require.Contains(t, store.ops, op.Name)
+
+	// Operations created by a subject are hidden from other callers.
+	res, err := svc.Get("/v1/operations/" + op.ID())
+	require.NoError(t, err)
+	assert.Equal(t, 404, res.Code())
+}
+
+func TestMemoryOperationStore(t *testing.T) {
+	s := newMemoryOperationStore(&conf.OperationsConfig{Ttl: durationpb.New(time.Minute), MaxOperations: 2})
+	now := time.Unix(1_700_000_000, 0)
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	require.NoError(t, s.Save(ctx, Operation{Name: "operations/a"}))
+	require.NoError(t, s.Save(ctx, Operation{Name: "operations/b", Done: true}))
+	assert.ErrorIs(t, s.Save(ctx, Operation{Name: "operations/c"}), ErrOperationStoreFull)
+	require.NoError(t, s.Save(ctx, Operation{Name: "operations/a", Done: true}))
+
+	// Done operations expire after the TTL and make room.
+	now = now.Add(2 * time.Minute)
+	_, err := s.Get(ctx, "operations/b")
+	assert.ErrorIs(t, err, ErrOperationNotFound)
+	require.NoError(t, s.Save(ctx, Operation{Name: "operations/c"}))
+}
+
+func TestValidateOperationsConfig(t *testing.T) {
+	require.NoError(t, validateOperationsConfig(nil))
+	require.NoError(t, validateOperationsConfig(&conf.OperationsConfig{Enabled: true, Path: "/v1/operations"}))
+	assert.Error(t, validateOperationsConfig(&conf.OperationsConfig{Enabled: true, Path: "operations"}))
+	assert.Error(t, validateOperationsConfig(&conf.OperationsConfig{Enabled: true, Ttl: durationpb.New(-time.Second)}))
+	assert.Error(t, validateOperationsConfig(&conf.OperationsConfig{Enabled: true, MaxOperations: -1}))
+}
@@ -0,0 +1,151 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const defaultOtelMeterName = "github.com/go-lynx/lynx-http"
+
+// instrumentDef is the shared definition of a request metric. The Prometheus collectors and the
+// OpenTelemetry instruments are both built from it so the two export paths stay in step.
+type instrumentDef struct {
+	// name is the Prometheus metric name under the lynx_http_ prefix; OpenTelemetry uses lynx.http.<name>.
+	name string
+	help string
+	unit string
+}
+
+func (d instrumentDef) otelName() string {
+	return "lynx.http." + d.name
+}
+
+var (
+	requestsTotalDef   = instrumentDef{name: "requests_total", help: "Total number of HTTP requests", unit: "{request}"}
+	requestDurationDef = instrumentDef{name: "request_duration_seconds", help: "HTTP request duration in seconds", unit: "s"}
+	responseSizeDef    = instrumentDef{name: "response_size_bytes", help: "HTTP response size in bytes", unit: "By"}
+	requestSizeDef     = instrumentDef{name: "request_size_bytes", help: "HTTP request size in bytes", unit: "By"}
+	errorsTotalDef     = instrumentDef{name: "errors_total", help: "Total number of HTTP errors", unit: "{error}"}
+	inflightDef        = instrumentDef{name: "inflight_requests", help: "Number of HTTP requests currently being served", unit: "{request}"}
+)
+
+// otelInstruments records the request metrics through the OpenTelemetry metrics API.
+// All methods are no-ops on a nil receiver so call sites need no enablement checks.
+type otelInstruments struct {
+	requests     metric.Int64Counter
+	duration     metric.Float64Histogram
+	requestSize  metric.Int64Histogram
+	responseSize metric.Int64Histogram
+	errors       metric.Int64Counter
+	inflight     metric.Int64UpDownCounter
+}
+
+// newOtelInstruments creates the instruments on mp (the global provider when nil), reusing the
+// configured histogram buckets as explicit bucket boundaries.
+func newOtelInstruments(mp metric.MeterProvider, meterName string, hist histogramOptions) (*otelInstruments, error) {
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+	if strings.TrimSpace(meterName) == "" {
+		meterName = defaultOtelMeterName
+	}
+	meter := mp.Meter(meterName)
+
+	var (
+		o   otelInstruments
+		err error
+	)
+	if o.requests, err = meter.Int64Counter(requestsTotalDef.otelName(),
+		metric.WithDescription(requestsTotalDef.help), metric.WithUnit(requestsTotalDef.unit)); err != nil {
+		return nil, fmt.Errorf("create %s: %w", requestsTotalDef.otelName(), err)
+	}
+	if o.duration, err = meter.Float64Histogram(requestDurationDef.otelName(),
+		metric.WithDescription(requestDurationDef.help), metric.WithUnit(requestDurationDef.unit),
+		metric.WithExplicitBucketBoundaries(hist.durationBuckets...)); err != nil {
+		return nil, fmt.Errorf("create %s: %w", requestDurationDef.otelName(), err)
+	}
+	if o.requestSize, err = meter.Int64Histogram(requestSizeDef.otelName(),
+		metric.WithDescription(requestSizeDef.help), metric.WithUnit(requestSizeDef.unit),
+		metric.WithExplicitBucketBoundaries(hist.requestSizeBuckets...)); err != nil {
+		return nil, fmt.Errorf("create %s: %w", requestSizeDef.otelName(), err)
+	}
+	if o.responseSize, err = meter.Int64Histogram(responseSizeDef.otelName(),
+		metric.WithDescription(responseSizeDef.help), metric.WithUnit(responseSizeDef.unit),
+		metric.WithExplicitBucketBoundaries(hist.responseSizeBuckets...)); err != nil {
+		return nil, fmt.Errorf("create %s: %w", responseSizeDef.otelName(), err)
+	}
+	if o.errors, err = meter.Int64Counter(errorsTotalDef.otelName(),
+		metric.WithDescription(errorsTotalDef.help), metric.WithUnit(errorsTotalDef.unit)); err != nil {
+		return nil, fmt.Errorf("create %s: %w", errorsTotalDef.otelName(), err)
+	}
+	if o.inflight, err = meter.Int64UpDownCounter(inflightDef.otelName(),
+		metric.WithDescription(inflightDef.help), metric.WithUnit(inflightDef.unit)); err != nil {
+		return nil, fmt.Errorf("create %s: %w", inflightDef.otelName(), err)
+	}
+	return &o, nil
+}
+
+func (o *otelInstruments) recordRequest(ctx context.Context, method, path, status string, seconds float64) {
+	if o == nil {
+		return
+	}
+	o.requests.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("method", method), attribute.String("path", path), attribute.String("status", status)))
+	o.duration.Record(ctx, seconds, metric.WithAttributes(
+		attribute.String("method", method), attribute.String("path", path)))
+}
+
+func (o *otelInstruments) recordRequestSize(ctx context.Context, method, path string, size int) {
+	if o == nil {
+		return
+	}
+	o.requestSize.Record(ctx, int64(size), metric.WithAttributes(
+		attribute.String("method", method), attribute.String("path", path)))
+}
+
+func (o *otelInstruments) recordResponseSize(ctx context.Context, method, path string, size int) {
+	if o == nil {
+		return
+	}
+	o.responseSize.Record(ctx, int64(size), metric.WithAttributes(
+		attribute.String("method", method), attribute.String("path", path)))
+}
+
+func (o *otelInstruments) recordError(ctx context.Context, method, path, errorType string) {
+	if o == nil {
+		return
+	}
+	o.errors.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("method", method), attribute.String("path", path), attribute.String("error_type", errorType)))
+}
+
+func (o *otelInstruments) addInflight(ctx context.Context, path string, delta int64) {
+	if o == nil {
+		return
+	}
+	o.inflight.Add(ctx, delta, metric.WithAttributes(attribute.String("path", path)))
+}
+
+// initOtelMetrics creates the OpenTelemetry instruments when enabled in configuration.
+// It reports whether the Prometheus request metrics should be skipped.
+func (h *ServiceHttp) initOtelMetrics(cfg *conf.OtelMetricsConfig, hist histogramOptions) (disablePrometheus bool) {
+	h.otelMetrics = nil
+	if cfg == nil || !cfg.Enabled {
+		return false
+	}
+	instruments, err := newOtelInstruments(h.MeterProvider, cfg.MeterName, hist)
+	if err != nil {
+		log.Warnf("Failed to create OpenTelemetry HTTP metrics, keeping Prometheus only: %v", err)
+		return false
+	}
+	h.otelMetrics = instruments
+	log.Infof("OpenTelemetry HTTP metrics enabled (prometheus=%v)", !cfg.DisablePrometheus)
+	return cfg.DisablePrometheus
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// countingMeterProvider records Int64Counter additions by instrument name; other instruments are no-ops.
+type countingMeterProvider struct {
+	noop.MeterProvider
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+func (p *countingMeterProvider) Meter(string, ...metric.MeterOption) metric.Meter {
+	return countingMeter{provider: p}
+}
+
+type countingMeter struct {
+	noop.Meter
+	provider *countingMeterProvider
+}
+
+func (m countingMeter) Int64Counter(name string, _ ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	return countingCounter{name: name, provider: m.provider}, nil
+}
+
+type countingCounter struct {
+	noop.Int64Counter
+	name     string
+	provider *countingMeterProvider
+}
+
+func (c countingCounter) Add(_ context.Context, incr int64, _ ...metric.AddOption) {
+	c.provider.mu.Lock()
+	defer c.provider.mu.Unlock()
+	if c.provider.counts == nil {
+		c.provider.counts = make(map[string]int64)
+	}
+	c.provider.counts[c.name] += incr
+}
+
+func TestOtelInstruments_NilSafe(t *testing.T) {
+	var o *otelInstruments
+	assert.NotPanics(t, func() {
+		o.recordRequest(context.Background(), "GET", "/p", "success", 0.1)
+		o.recordRequestSize(context.Background(), "GET", "/p", 10)
+		o.recordResponseSize(context.Background(), "GET", "/p", 10)
+		o.recordError(context.Background(), "GET", "/p", "error")
+		o.addInflight(context.Background(), "/p", 1)
+	})
+}
+
+func TestInitMetrics_OtelOnly(t *testing.T) {
+	provider := &countingMeterProvider{}
+	svc := NewServiceHttp()
+	svc.MeterProvider = provider
+	svc.conf = &conf.Http{Monitoring: &conf.MonitoringConfig{
+		OtelMetrics: &conf.OtelMetricsConfig{Enabled: true, DisablePrometheus: true},
+	}}
+	svc.initMetrics()
+	defer svc.stopMetricsLoop()
+
+	require.NotNil(t, svc.otelMetrics)
+	assert.Nil(t, svc.requestCounter)
+	assert.Nil(t, svc.errorCounter)
+	assert.NotNil(t, svc.healthCheckTotal)
+
+	svc.otelMetrics.recordRequest(context.Background(), "GET", "/p", "success", 0.01)
+	svc.recordErrorMetric("GET", "/p", "handler_error")
+
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	assert.Equal(t, int64(1), provider.counts[requestsTotalDef.otelName()])
+	assert.Equal(t, int64(1), provider.counts[errorsTotalDef.otelName()])
+}
+
+func TestInitMetrics_OtelAlongsidePrometheus(t *testing.T) {
+	svc := NewServiceHttp()
+	svc.MeterProvider = noop.NewMeterProvider()
+	svc.conf = &conf.Http{Monitoring: &conf.MonitoringConfig{
+		OtelMetrics: &conf.OtelMetricsConfig{Enabled: true},
+	}}
+	svc.initMetrics()
+	defer svc.stopMetricsLoop()
+
+	assert.NotNil(t, svc.otelMetrics)
+	assert.NotNil(t, svc.requestCounter)
+}
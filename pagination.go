@@ -0,0 +1,193 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	nhttp "net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+)
+
+const (
+	// Query parameters read by ParsePageRequest and written into Link headers.
+	PageQueryParam     = "page"
+	PageSizeQueryParam = "page_size"
+	CursorQueryParam   = "cursor"
+
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// PageRequest is the pagination input of a list endpoint.
+type PageRequest struct {
+	Page     int
+	PageSize int
+	Cursor   string
+}
+
+// Offset is the number of items before the requested page.
+func (p PageRequest) Offset() int {
+	return (p.Page - 1) * p.PageSize
+}
+
+// ParsePageRequest reads page, page_size and cursor from the query string. Missing or invalid values
+// default to page 1 and 20 items; page_size is capped at 100.
+func ParsePageRequest(r *nhttp.Request) PageRequest {
+	q := r.URL.Query()
+	p := PageRequest{Page: 1, PageSize: defaultPageSize, Cursor: q.Get(CursorQueryParam)}
+	if n, err := strconv.Atoi(q.Get(PageQueryParam)); err == nil && n > 0 {
+		p.Page = n
+	}
+	if n, err := strconv.Atoi(q.Get(PageSizeQueryParam)); err == nil && n > 0 {
+		p.PageSize = min(n, maxPageSize)
+	}
+	return p
+}
+
+// Page is a paged list result. Returned as a reply, ResponseEncoder writes the pagination fields next to
+// data in the envelope and adds a Link header with the neighbouring pages.
+type Page[T any] struct {
+	Items      []T
+	Page       int
+	PageSize   int
+	Total      int64
+	NextCursor string
+}
+
+// NewPage returns an offset-paginated result; total is the number of items across all pages.
+func NewPage[T any](items []T, req PageRequest, total int64) *Page[T] {
+	return &Page[T]{Items: items, Page: req.Page, PageSize: req.PageSize, Total: total}
+}
+
+// NewCursorPage returns a cursor-paginated result; an empty nextCursor marks the last page.
+func NewCursorPage[T any](items []T, pageSize int, nextCursor string) *Page[T] {
+	return &Page[T]{Items: items, PageSize: pageSize, NextCursor: nextCursor}
+}
+
+func (p *Page[T]) pageInfo() pageInfo {
+	return pageInfo{Page: p.Page, PageSize: p.PageSize, Total: p.Total, NextCursor: p.NextCursor}
+}
+
+func (p *Page[T]) pageItems() any {
+	if p.Items == nil {
+		return []T{}
+	}
+	return p.Items
+}
+
+// pager is implemented by every Page instantiation so the encoder can handle them without generics.
+type pager interface {
+	pageInfo() pageInfo
+	pageItems() any
+}
+
+type pageInfo struct {
+	Page       int    `json:"page,omitempty"`
+	PageSize   int    `json:"page_size,omitempty"`
+	Total      int64  `json:"total,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// pagedResponse is the envelope for Page replies: the standard {code,data} plus pagination fields.
+type pagedResponse struct {
+	Code     int       `json:"code"`
+	Data     any       `json:"data"`
+	Warnings []Warning `json:"warnings,omitempty"`
+	pageInfo
+}
+
+// encodePage writes a Page reply with its Link header.
+func encodePage(w http.ResponseWriter, r *http.Request, p pager, code int) error {
+	info := p.pageInfo()
+	if link := pageLinkHeader(r.URL, info); link != "" {
+		w.Header().Set("Link", link)
+	}
+	res := &pagedResponse{Code: code, Data: p.pageItems(), Warnings: Warnings(r.Context()), pageInfo: info}
+	body, contentType, err := marshalEnvelope(r, res)
+	if err != nil {
+		w.WriteHeader(nhttp.StatusInternalServerError)
+		return err
+	}
+	w.Header().Set("Content-Type", contentType)
+	_, err = w.Write(body)
+	return err
+}
+
+// pageLink is one navigation link of a paged reply.
+type pageLink struct {
+	rel  string
+	href string
+}
+
+// pageLinkHeader builds an RFC 8288 Link header from pageLinks.
+func pageLinkHeader(u *url.URL, info pageInfo) string {
+	links := pageLinks(u, info)
+	parts := make([]string, 0, len(links))
+	for _, l := range links {
+		parts = append(parts, fmt.Sprintf("<%s>; rel=%q", l.href, l.rel))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// pageLinks returns links relative to the request URL: next for cursors, and first, prev, next and last
+// for offset pages.
+func pageLinks(u *url.URL, info pageInfo) []pageLink {
+	if u == nil {
+		return nil
+	}
+	link := func(rel string, set map[string]string, del string) pageLink {
+		ref := *u
+		q := ref.Query()
+		for k, v := range set {
+			q.Set(k, v)
+		}
+		if del != "" {
+			q.Del(del)
+		}
+		ref.RawQuery = q.Encode()
+		return pageLink{rel: rel, href: ref.RequestURI()}
+	}
+
+	if info.NextCursor != "" {
+		return []pageLink{link("next", map[string]string{CursorQueryParam: info.NextCursor}, PageQueryParam)}
+	}
+	if info.Page < 1 || info.PageSize < 1 {
+		return nil
+	}
+	size := strconv.Itoa(info.PageSize)
+	at := func(rel string, page int64) pageLink {
+		return link(rel, map[string]string{PageQueryParam: strconv.FormatInt(page, 10), PageSizeQueryParam: size}, CursorQueryParam)
+	}
+	last := max((info.Total+int64(info.PageSize)-1)/int64(info.PageSize), 1)
+	page := int64(info.Page)
+	links := []pageLink{at("first", 1)}
+	if page > 1 {
+		links = append(links, at("prev", min(page-1, last)))
+	}
+	if page < last {
+		links = append(links, at("next", page+1))
+	}
+	return append(links, at("last", last))
+}
+
+// DecodePage reads and closes the response body and unwraps a paged envelope into a Page.
+func DecodePage[T any](res *nhttp.Response, opts ...DecodeOption) (*Page[T], error) {
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	items, err := decodeEnvelope[[]T](res, body, opts)
+	if err != nil {
+		return nil, err
+	}
+	var info pageInfo
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, err
+	}
+	return &Page[T]{Items: items, Page: info.Page, PageSize: info.PageSize, Total: info.Total, NextCursor: info.NextCursor}, nil
+}
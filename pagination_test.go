@@ -0,0 +1,66 @@
+package http
+
+import (
+	"encoding/json"
+	nhttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePageRequest(t *testing.T) {
+	r := httptest.NewRequest(nhttp.MethodGet, "/users?page=3&page_size=500&cursor=abc", nil)
+	p := ParsePageRequest(r)
+	assert.Equal(t, PageRequest{Page: 3, PageSize: maxPageSize, Cursor: "abc"}, p)
+	assert.Equal(t, 200, p.Offset())
+
+	p = ParsePageRequest(httptest.NewRequest(nhttp.MethodGet, "/users?page=-1&page_size=x", nil))
+	assert.Equal(t, PageRequest{Page: 1, PageSize: defaultPageSize}, p)
+}
+
+func TestResponseEncoder_Page(t *testing.T) {
+	r := httptest.NewRequest(nhttp.MethodGet, "/users?page=2&page_size=10&sort=name", nil)
+	w := httptest.NewRecorder()
+	page := NewPage([]decodeUser{{Name: "a"}}, PageRequest{Page: 2, PageSize: 10}, 35)
+	require.NoError(t, ResponseEncoder(w, r, page))
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, float64(200), body["code"])
+	assert.Equal(t, float64(2), body["page"])
+	assert.Equal(t, float64(10), body["page_size"])
+	assert.Equal(t, float64(35), body["total"])
+	assert.Len(t, body["data"], 1)
+
+	assert.Equal(t, `</users?page=1&page_size=10&sort=name>; rel="first", `+
+		`</users?page=1&page_size=10&sort=name>; rel="prev", `+
+		`</users?page=3&page_size=10&sort=name>; rel="next", `+
+		`</users?page=4&page_size=10&sort=name>; rel="last"`, w.Header().Get("Link"))
+}
+
+func TestResponseEncoder_CursorPage(t *testing.T) {
+	r := httptest.NewRequest(nhttp.MethodGet, "/events?cursor=old&page_size=50", nil)
+	w := httptest.NewRecorder()
+	require.NoError(t, ResponseEncoder(w, r, NewCursorPage[decodeUser](nil, 50, "next-token")))
+
+	assert.JSONEq(t, `{"code":200,"data":[],"page_size":50,"next_cursor":"next-token"}`, w.Body.String())
+	assert.Equal(t, `</events?cursor=next-token&page_size=50>; rel="next"`, w.Header().Get("Link"))
+}
+
+func TestPageLinkHeader_LastPage(t *testing.T) {
+	r := httptest.NewRequest(nhttp.MethodGet, "/users", nil)
+	link := pageLinkHeader(r.URL, pageInfo{Page: 1, PageSize: 10, Total: 0})
+	assert.Equal(t, `</users?page=1&page_size=10>; rel="first", </users?page=1&page_size=10>; rel="last"`, link)
+	assert.Empty(t, pageLinkHeader(r.URL, pageInfo{}))
+}
+
+func TestDecodePage(t *testing.T) {
+	res := envelopeResponse(200, `{"code":200,"data":[{"name":"a"},{"name":"b"}],"page":1,"page_size":2,"total":7}`)
+	page, err := DecodePage[decodeUser](res)
+	require.NoError(t, err)
+	assert.Len(t, page.Items, 2)
+	assert.Equal(t, 1, page.Page)
+	assert.Equal(t, int64(7), page.Total)
+}
@@ -0,0 +1,502 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	nhttp "net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/encoding"
+	"github.com/go-kratos/kratos/v2/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+const (
+	// MergePatchMediaType is the Content-Type of JSON Merge Patch (RFC 7396) bodies.
+	MergePatchMediaType = "application/merge-patch+json"
+	// JSONPatchMediaType is the Content-Type of JSON Patch (RFC 6902) bodies.
+	JSONPatchMediaType = "application/json-patch+json"
+
+	// invalidPatchReason is the Kratos error reason for patches that cannot be applied.
+	invalidPatchReason = "INVALID_PATCH"
+	// unsupportedPatchReason is the Kratos error reason for PATCH bodies of another media type.
+	unsupportedPatchReason = "UNSUPPORTED_PATCH_TYPE"
+	// preconditionFailedReason is the Kratos error reason for If-Match mismatches.
+	preconditionFailedReason = "PRECONDITION_FAILED"
+	// preconditionRequiredReason is the Kratos error reason for missing If-Match headers.
+	preconditionRequiredReason = "PRECONDITION_REQUIRED"
+)
+
+func init() {
+	// Register the patch media types so request binding accepts them; both are JSON documents.
+	encoding.RegisterCodec(patchCodec{name: "merge-patch+json"})
+	encoding.RegisterCodec(patchCodec{name: "json-patch+json"})
+}
+
+// patchCodec decodes patch bodies like the JSON codec.
+type patchCodec struct {
+	name string
+}
+
+func (c patchCodec) Marshal(v any) ([]byte, error) { return encoding.GetCodec("json").Marshal(v) }
+func (c patchCodec) Unmarshal(data []byte, v any) error {
+	return encoding.GetCodec("json").Unmarshal(data, v)
+}
+func (c patchCodec) Name() string { return c.name }
+
+// PatchOperation is one JSON Patch operation.
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Patch is a PATCH request body with its media type.
+type Patch struct {
+	MediaType string
+	Body      []byte
+}
+
+// ReadPatch reads a JSON Merge Patch or JSON Patch body from r. Other media types are rejected with 415.
+func ReadPatch(r *nhttp.Request) (*Patch, error) {
+	mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mt != MergePatchMediaType && mt != JSONPatchMediaType {
+		return nil, errors.New(nhttp.StatusUnsupportedMediaType, unsupportedPatchReason,
+			fmt.Sprintf("PATCH bodies must be %s or %s", MergePatchMediaType, JSONPatchMediaType))
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, errors.BadRequest(invalidPatchReason, err.Error())
+	}
+	return &Patch{MediaType: mt, Body: body}, nil
+}
+
+// ApplyTo applies the patch to msg according to its media type; see ApplyMergePatch and ApplyJSONPatch.
+func (p *Patch) ApplyTo(msg proto.Message) (*fieldmaskpb.FieldMask, error) {
+	if p.MediaType == JSONPatchMediaType {
+		return ApplyJSONPatch(msg, p.Body)
+	}
+	return ApplyMergePatch(msg, p.Body)
+}
+
+// ApplyMergePatch applies a JSON Merge Patch to msg and returns the mask of the fields it touched, in proto
+// field names, ready to pass to an update that honours FieldMask. Keys may use proto or JSON names; null
+// clears a field. msg is left unchanged when the patch is invalid.
+func ApplyMergePatch(msg proto.Message, patch []byte) (*fieldmaskpb.FieldMask, error) {
+	p, err := decodePatchJSON(patch)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := p.(map[string]any)
+	if !ok {
+		return nil, errors.BadRequest(invalidPatchReason, "merge patch must be a JSON object")
+	}
+	doc, err := messageDocument(msg)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	if err := mergeMessagePatch(doc, obj, msg.ProtoReflect().Descriptor(), "", &paths); err != nil {
+		return nil, errors.BadRequest(invalidPatchReason, err.Error())
+	}
+	if err := replaceMessage(msg, doc); err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	return &fieldmaskpb.FieldMask{Paths: paths}, nil
+}
+
+// ApplyJSONPatch applies a JSON Patch to msg and returns the mask of the fields the operations touched;
+// changes inside repeated and map fields mask the whole field. Pointers may use proto or JSON names. msg is
+// left unchanged when any operation fails.
+func ApplyJSONPatch(msg proto.Message, patch []byte) (*fieldmaskpb.FieldMask, error) {
+	var ops []PatchOperation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, errors.BadRequest(invalidPatchReason, "JSON patch must be an array of operations")
+	}
+	obj, err := messageDocument(msg)
+	if err != nil {
+		return nil, err
+	}
+	var doc any = obj
+	md := msg.ProtoReflect().Descriptor()
+	var paths []string
+	addPath := func(p string) {
+		for _, existing := range paths {
+			if existing == p {
+				return
+			}
+		}
+		paths = append(paths, p)
+	}
+	for i, op := range ops {
+		doc, err = applyPatchOperation(doc, op, md, addPath)
+		if err != nil {
+			return nil, errors.BadRequest(invalidPatchReason, fmt.Sprintf("operation %d (%s %s): %v", i, op.Op, op.Path, err))
+		}
+	}
+	if obj, _ = doc.(map[string]any); obj == nil {
+		return nil, errors.BadRequest(invalidPatchReason, "patched document is not an object")
+	}
+	if err := replaceMessage(msg, obj); err != nil {
+		return nil, err
+	}
+	return &fieldmaskpb.FieldMask{Paths: paths}, nil
+}
+
+func decodePatchJSON(b []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, errors.BadRequest(invalidPatchReason, "malformed patch: "+err.Error())
+	}
+	return v, nil
+}
+
+// messageDocument renders msg as a generic JSON object with every field present, so patches can address
+// fields that hold their zero value.
+func messageDocument(msg proto.Message) (map[string]any, error) {
+	b, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	v, err := decodePatchJSON(b)
+	if err != nil {
+		return nil, err
+	}
+	return v.(map[string]any), nil
+}
+
+// replaceMessage decodes doc into a fresh message and only then overwrites msg.
+func replaceMessage(msg proto.Message, doc map[string]any) error {
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return errors.BadRequest(invalidPatchReason, err.Error())
+	}
+	out := msg.ProtoReflect().New().Interface()
+	if err := protojson.Unmarshal(b, out); err != nil {
+		return errors.BadRequest(invalidPatchReason, err.Error())
+	}
+	proto.Reset(msg)
+	proto.Merge(msg, out)
+	return nil
+}
+
+// patchableMessage returns the message type patches may descend into, or nil for fields whose JSON form is
+// not an object of fields (scalars, lists, maps and well-known types).
+func patchableMessage(fd protoreflect.FieldDescriptor) protoreflect.MessageDescriptor {
+	if fd.IsList() || fd.IsMap() || fd.Message() == nil {
+		return nil
+	}
+	if strings.HasPrefix(string(fd.Message().FullName()), "google.protobuf.") {
+		return nil
+	}
+	return fd.Message()
+}
+
+// mergeMessagePatch merges patch into doc following RFC 7396, normalizing keys to JSON names and
+// recording the proto path of every field it sets or clears.
+func mergeMessagePatch(doc, patch map[string]any, md protoreflect.MessageDescriptor, prefix string, paths *[]string) error {
+	for key, value := range patch {
+		fd := lookupField(md, key)
+		if fd == nil {
+			return fmt.Errorf("unknown field %q in %s", prefix+key, md.FullName())
+		}
+		name := fd.JSONName()
+		path := prefix + string(fd.Name())
+		sub, isObj := value.(map[string]any)
+		if sm := patchableMessage(fd); sm != nil && isObj {
+			target, _ := doc[name].(map[string]any)
+			if target == nil {
+				target = make(map[string]any)
+			}
+			if err := mergeMessagePatch(target, sub, sm, path+".", paths); err != nil {
+				return err
+			}
+			doc[name] = target
+			continue
+		}
+		*paths = append(*paths, path)
+		if value == nil {
+			delete(doc, name)
+			continue
+		}
+		if fd.IsMap() && isObj {
+			target, _ := doc[name].(map[string]any)
+			doc[name] = mergeJSONPatch(target, sub)
+			continue
+		}
+		doc[name] = value
+	}
+	return nil
+}
+
+// mergeJSONPatch is the plain RFC 7396 merge used inside proto maps.
+func mergeJSONPatch(target any, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = make(map[string]any)
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = mergeJSONPatch(t[k], v)
+	}
+	return t
+}
+
+// resolvePatchPointer parses a JSON pointer, normalizes message field names to JSON names and returns the
+// proto path of the outermost field it enters that can be masked.
+func resolvePatchPointer(ptr string, md protoreflect.MessageDescriptor) ([]string, string, error) {
+	if ptr == "" {
+		return nil, "", fmt.Errorf("the whole document cannot be patched")
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		return nil, "", fmt.Errorf("pointer %q must start with /", ptr)
+	}
+	tokens := strings.Split(ptr[1:], "/")
+	var mask []string
+	masking := true
+	for i, tok := range tokens {
+		tok = strings.ReplaceAll(strings.ReplaceAll(tok, "~1", "/"), "~0", "~")
+		tokens[i] = tok
+		if md == nil {
+			// Inside a list, map or scalar the pointer addresses plain JSON.
+			continue
+		}
+		fd := lookupField(md, tok)
+		if fd == nil {
+			return nil, "", fmt.Errorf("unknown field %q in %s", tok, md.FullName())
+		}
+		tokens[i] = fd.JSONName()
+		if masking {
+			mask = append(mask, string(fd.Name()))
+		}
+		md = patchableMessage(fd)
+		if md == nil {
+			masking = false
+		}
+	}
+	return tokens, strings.Join(mask, "."), nil
+}
+
+func applyPatchOperation(doc any, op PatchOperation, md protoreflect.MessageDescriptor, addPath func(string)) (any, error) {
+	tokens, mask, err := resolvePatchPointer(op.Path, md)
+	if err != nil {
+		return nil, err
+	}
+	var value any
+	if op.Op == "add" || op.Op == "replace" || op.Op == "test" {
+		if op.Value == nil {
+			return nil, fmt.Errorf("value is required")
+		}
+		if value, err = decodePatchJSON(op.Value); err != nil {
+			return nil, err
+		}
+	}
+	switch op.Op {
+	case "test":
+		cur, err := jsonPointerGet(doc, tokens)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(cur, value) {
+			return nil, fmt.Errorf("test failed")
+		}
+		return doc, nil
+	case "add":
+		addPath(mask)
+		return jsonPointerAdd(doc, tokens, value)
+	case "remove":
+		addPath(mask)
+		doc, _, err = jsonPointerRemove(doc, tokens)
+		return doc, err
+	case "replace":
+		addPath(mask)
+		if doc, _, err = jsonPointerRemove(doc, tokens); err != nil {
+			return nil, err
+		}
+		return jsonPointerAdd(doc, tokens, value)
+	case "move", "copy":
+		from, fromMask, err := resolvePatchPointer(op.From, md)
+		if err != nil {
+			return nil, fmt.Errorf("from: %w", err)
+		}
+		if op.Op == "move" {
+			if strings.HasPrefix(op.Path+"/", op.From+"/") {
+				return nil, fmt.Errorf("cannot move a value into itself")
+			}
+			addPath(fromMask)
+			if doc, value, err = jsonPointerRemove(doc, from); err != nil {
+				return nil, err
+			}
+		} else if value, err = jsonPointerGet(doc, from); err != nil {
+			return nil, err
+		}
+		addPath(mask)
+		return jsonPointerAdd(doc, tokens, value)
+	default:
+		return nil, fmt.Errorf("unsupported op %q", op.Op)
+	}
+}
+
+func jsonPointerGet(node any, tokens []string) (any, error) {
+	for _, tok := range tokens {
+		switch n := node.(type) {
+		case map[string]any:
+			v, ok := n[tok]
+			if !ok {
+				return nil, fmt.Errorf("path not found at %q", tok)
+			}
+			node = v
+		case []any:
+			i, err := patchArrayIndex(tok, len(n), false)
+			if err != nil {
+				return nil, err
+			}
+			node = n[i]
+		default:
+			return nil, fmt.Errorf("path not found at %q", tok)
+		}
+	}
+	return node, nil
+}
+
+// jsonPointerAdd returns node with value added at tokens, inserting into arrays.
+func jsonPointerAdd(node any, tokens []string, value any) (any, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	tok, rest := tokens[0], tokens[1:]
+	switch n := node.(type) {
+	case map[string]any:
+		if len(rest) == 0 {
+			n[tok] = value
+			return n, nil
+		}
+		child, ok := n[tok]
+		if !ok {
+			return nil, fmt.Errorf("path not found at %q", tok)
+		}
+		updated, err := jsonPointerAdd(child, rest, value)
+		if err != nil {
+			return nil, err
+		}
+		n[tok] = updated
+		return n, nil
+	case []any:
+		if len(rest) == 0 {
+			i, err := patchArrayIndex(tok, len(n), true)
+			if err != nil {
+				return nil, err
+			}
+			return append(n[:i], append([]any{value}, n[i:]...)...), nil
+		}
+		i, err := patchArrayIndex(tok, len(n), false)
+		if err != nil {
+			return nil, err
+		}
+		updated, err := jsonPointerAdd(n[i], rest, value)
+		if err != nil {
+			return nil, err
+		}
+		n[i] = updated
+		return n, nil
+	default:
+		return nil, fmt.Errorf("path not found at %q", tok)
+	}
+}
+
+// jsonPointerRemove returns node without the value at tokens, and the removed value.
+func jsonPointerRemove(node any, tokens []string) (any, any, error) {
+	tok, rest := tokens[0], tokens[1:]
+	switch n := node.(type) {
+	case map[string]any:
+		child, ok := n[tok]
+		if !ok {
+			return nil, nil, fmt.Errorf("path not found at %q", tok)
+		}
+		if len(rest) == 0 {
+			delete(n, tok)
+			return n, child, nil
+		}
+		updated, removed, err := jsonPointerRemove(child, rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		n[tok] = updated
+		return n, removed, nil
+	case []any:
+		i, err := patchArrayIndex(tok, len(n), false)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(rest) == 0 {
+			removed := n[i]
+			return append(n[:i], n[i+1:]...), removed, nil
+		}
+		updated, removed, err := jsonPointerRemove(n[i], rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		n[i] = updated
+		return n, removed, nil
+	default:
+		return nil, nil, fmt.Errorf("path not found at %q", tok)
+	}
+}
+
+// patchArrayIndex parses an array index; "-" and length are valid only when inserting.
+func patchArrayIndex(tok string, length int, insert bool) (int, error) {
+	if insert && tok == "-" {
+		return length, nil
+	}
+	i, err := strconv.Atoi(tok)
+	if err != nil || i < 0 || (tok != "0" && strings.HasPrefix(tok, "0")) {
+		return 0, fmt.Errorf("invalid array index %q", tok)
+	}
+	if i > length || (!insert && i == length) {
+		return 0, fmt.Errorf("array index %d out of range", i)
+	}
+	return i, nil
+}
+
+// CheckIfMatch evaluates the If-Match precondition of r against the current entity tag of the resource,
+// for conditional updates. Without the header it passes unless required, in which case it fails with 428;
+// a mismatch fails with 412. Weak tags never match, as RFC 9110 requires strong comparison.
+func CheckIfMatch(r *nhttp.Request, etag string, required bool) error {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" {
+		if required {
+			return errors.New(nhttp.StatusPreconditionRequired, preconditionRequiredReason, "If-Match header is required")
+		}
+		return nil
+	}
+	if etag != "" && !strings.HasPrefix(etag, `"`) && !strings.HasPrefix(etag, "W/") {
+		etag = strconv.Quote(etag)
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if (candidate == "*" && etag != "") || (candidate == etag && !strings.HasPrefix(etag, "W/")) {
+			return nil
+		}
+	}
+	return errors.New(nhttp.StatusPreconditionFailed, preconditionFailedReason, "resource has been modified")
+}
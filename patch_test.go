@@ -0,0 +1,136 @@
+package http
+
+import (
+	nhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func patchSample() *conf.Http {
+	cfg := fieldFilterSample()
+	cfg.Monitoring.BodyLogging = &conf.BodyLoggingConfig{RouteReplyPolicies: map[string]string{"/a": "full", "/b": "none"}}
+	return cfg
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	msg := patchSample()
+	mask, err := ApplyMergePatch(msg, []byte(`{
+		"addr": ":9090",
+		"network": null,
+		"timeout": "3s",
+		"monitoring": {"metrics_path": "/m", "slo": {"enabled": false}, "bodyLogging": {"routeReplyPolicies": {"/b": null, "/c": "headers"}}}
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"addr", "monitoring.body_logging.route_reply_policies", "monitoring.metrics_path",
+		"monitoring.slo.enabled", "network", "timeout"}, mask.Paths)
+
+	assert.Equal(t, ":9090", msg.Addr)
+	assert.Empty(t, msg.Network)
+	assert.Equal(t, 3*time.Second, msg.Timeout.AsDuration())
+	assert.Equal(t, "/m", msg.Monitoring.MetricsPath)
+	assert.True(t, msg.Monitoring.EnableMetrics, "untouched fields are kept")
+	assert.False(t, msg.Monitoring.Slo.Enabled)
+	assert.Len(t, msg.Monitoring.Slo.Objectives, 2)
+	assert.Equal(t, map[string]string{"/a": "full", "/c": "headers"}, msg.Monitoring.BodyLogging.RouteReplyPolicies)
+}
+
+func TestApplyMergePatch_Invalid(t *testing.T) {
+	msg := patchSample()
+	before := proto.Clone(msg)
+	for _, patch := range []string{`[1]`, `{"nope":1}`, `{"addr":1}`, `{"monitoring":{"bogus":true}}`, `{`} {
+		_, err := ApplyMergePatch(msg, []byte(patch))
+		assert.Equal(t, invalidPatchReason, errors.FromError(err).Reason, patch)
+	}
+	assert.True(t, proto.Equal(before, msg), "failed patches leave the message unchanged")
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	msg := patchSample()
+	mask, err := ApplyJSONPatch(msg, []byte(`[
+		{"op":"test","path":"/addr","value":":8080"},
+		{"op":"replace","path":"/addr","value":":9090"},
+		{"op":"remove","path":"/monitoring/slo/objectives/0"},
+		{"op":"add","path":"/monitoring/slo/objectives/-","value":{"operation":"/c"}},
+		{"op":"copy","from":"/monitoring/metrics_path","path":"/monitoring/healthPath"},
+		{"op":"move","from":"/network","path":"/monitoring/metricsPath"}
+	]`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"addr", "monitoring.slo.objectives", "monitoring.health_path", "network", "monitoring.metrics_path"}, mask.Paths)
+
+	assert.Equal(t, ":9090", msg.Addr)
+	require.Len(t, msg.Monitoring.Slo.Objectives, 2)
+	assert.Equal(t, "/b", msg.Monitoring.Slo.Objectives[0].Operation)
+	assert.Equal(t, "/c", msg.Monitoring.Slo.Objectives[1].Operation)
+	assert.Equal(t, "/metrics", msg.Monitoring.HealthPath)
+	assert.Empty(t, msg.Network)
+	assert.Equal(t, "tcp", msg.Monitoring.MetricsPath)
+}
+
+func TestApplyJSONPatch_Invalid(t *testing.T) {
+	msg := patchSample()
+	before := proto.Clone(msg)
+	for _, patch := range []string{
+		`{}`,
+		`[{"op":"replace","path":"/addr","value":":1"},{"op":"test","path":"/addr","value":"x"}]`,
+		`[{"op":"add","path":"/unknown","value":1}]`,
+		`[{"op":"remove","path":"/monitoring/slo/objectives/9"}]`,
+		`[{"op":"add","path":"/monitoring/slo/objectives/01","value":{}}]`,
+		`[{"op":"move","from":"/monitoring","path":"/monitoring/slo"}]`,
+		`[{"op":"replace","path":"","value":{}}]`,
+		`[{"op":"frobnicate","path":"/addr"}]`,
+	} {
+		_, err := ApplyJSONPatch(msg, []byte(patch))
+		assert.Equal(t, invalidPatchReason, errors.FromError(err).Reason, patch)
+	}
+	assert.True(t, proto.Equal(before, msg))
+}
+
+func TestReadPatch(t *testing.T) {
+	r := httptest.NewRequest(nhttp.MethodPatch, "/config", strings.NewReader(`[{"op":"remove","path":"/addr"}]`))
+	r.Header.Set("Content-Type", JSONPatchMediaType)
+	p, err := ReadPatch(r)
+	require.NoError(t, err)
+	msg := patchSample()
+	mask, err := p.ApplyTo(msg)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"addr"}, mask.Paths)
+	assert.Empty(t, msg.Addr)
+
+	r = httptest.NewRequest(nhttp.MethodPatch, "/config", strings.NewReader(`{}`))
+	r.Header.Set("Content-Type", "application/json")
+	_, err = ReadPatch(r)
+	assert.Equal(t, int32(nhttp.StatusUnsupportedMediaType), errors.FromError(err).Code)
+}
+
+func TestPatchCodecBinding(t *testing.T) {
+	r := httptest.NewRequest(nhttp.MethodPatch, "/config", strings.NewReader(`{"addr":":1"}`))
+	r.Header.Set("Content-Type", MergePatchMediaType+"; charset=utf-8")
+	var msg conf.Http
+	require.NoError(t, http.DefaultRequestDecoder(r, &msg))
+	assert.Equal(t, ":1", msg.Addr)
+}
+
+func TestCheckIfMatch(t *testing.T) {
+	r := httptest.NewRequest(nhttp.MethodPatch, "/config", nil)
+	assert.NoError(t, CheckIfMatch(r, "v1", false))
+	assert.Equal(t, int32(nhttp.StatusPreconditionRequired), errors.FromError(CheckIfMatch(r, "v1", true)).Code)
+
+	r.Header.Set("If-Match", `"v0", "v1"`)
+	assert.NoError(t, CheckIfMatch(r, "v1", true))
+	assert.NoError(t, CheckIfMatch(r, `"v1"`, true))
+	assert.Equal(t, int32(nhttp.StatusPreconditionFailed), errors.FromError(CheckIfMatch(r, "v2", true)).Code)
+	assert.Error(t, CheckIfMatch(r, `W/"v1"`, true), "weak tags never match")
+
+	r.Header.Set("If-Match", "*")
+	assert.NoError(t, CheckIfMatch(r, "v9", true))
+	assert.Error(t, CheckIfMatch(r, "", true), "* fails when the resource does not exist")
+}
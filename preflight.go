@@ -0,0 +1,274 @@
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/gorilla/mux"
+)
+
+// Preflight runs the startup checks without starting the server: configuration, listen address, TLS
+// certificate, built-in endpoint routes and operation patterns. Every failed check is reported in the
+// returned error, joined, so a broken deployment is fixed in one round instead of one restart per mistake.
+// Start runs it before anything is initialized.
+func (h *ServiceHttp) Preflight() error {
+	h.confMu.RLock()
+	defer h.confMu.RUnlock()
+	if h.conf == nil {
+		return fmt.Errorf("HTTP configuration not initialized")
+	}
+
+	var errs []error
+	if err := h.validateConfigLocked(); err != nil {
+		errs = append(errs, fmt.Errorf("configuration: %w", err))
+	}
+	if err := h.checkListenAddress(); err != nil {
+		errs = append(errs, fmt.Errorf("listen address: %w", err))
+	}
+	if h.conf.GetTlsEnable() {
+		if err := checkServerCertificate(time.Now()); err != nil {
+			errs = append(errs, fmt.Errorf("TLS certificate: %w", err))
+		}
+		for _, err := range checkVirtualHostCertificates(h.conf.GetVirtualHosts(), time.Now()) {
+			errs = append(errs, fmt.Errorf("TLS certificate: %w", err))
+		}
+	}
+	for _, err := range h.checkEndpointRoutes() {
+		errs = append(errs, fmt.Errorf("routes: %w", err))
+	}
+	for _, err := range checkOperationPatterns(h.conf) {
+		errs = append(errs, fmt.Errorf("patterns: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// checkListenAddress binds and releases the configured address, so a port taken by another process fails
+// startup here rather than when the server starts serving. Injected listeners, systemd sockets, unix
+// sockets and port 0 are not checked.
+func (h *ServiceHttp) checkListenAddress() error {
+	if h.listener != nil || h.conf.GetSystemd().GetSocketActivation() {
+		return nil
+	}
+	network := h.conf.Network
+	if network == "" {
+		network = "tcp"
+	}
+	if !strings.HasPrefix(network, "tcp") {
+		return nil
+	}
+	if _, port, err := net.SplitHostPort(h.conf.Addr); err != nil || port == "0" {
+		// Malformed addresses are reported by the configuration check.
+		return nil
+	}
+	lis, err := net.Listen(network, h.conf.Addr)
+	if err != nil {
+		return fmt.Errorf("%s is not available: %w", h.conf.Addr, err)
+	}
+	return lis.Close()
+}
+
+// checkServerCertificate parses the certificate provider's key pair and rejects certificates outside their
+// validity period at now. tlsLoad only parses the pair on the first handshake.
+func checkServerCertificate(now time.Time) error {
+	app := currentLynxApp()
+	if app == nil {
+		return fmt.Errorf("lynx app not initialized")
+	}
+	provider := app.Certificate()
+	if provider == nil {
+		return fmt.Errorf("certificate provider not configured")
+	}
+	return checkKeyPair(provider.GetCertificate(), provider.GetPrivateKey(), now)
+}
+
+func checkKeyPair(certPEM, keyPEM []byte, now time.Time) error {
+	if len(certPEM) == 0 || len(keyPEM) == 0 {
+		return fmt.Errorf("certificate or private key is empty")
+	}
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return fmt.Errorf("invalid key pair: %w", err)
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("invalid certificate: %w", err)
+	}
+	if now.Before(leaf.NotBefore) {
+		return fmt.Errorf("certificate %q is not valid before %s", leaf.Subject.CommonName, leaf.NotBefore.Format(time.RFC3339))
+	}
+	if now.After(leaf.NotAfter) {
+		return fmt.Errorf("certificate %q expired at %s", leaf.Subject.CommonName, leaf.NotAfter.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// builtinEndpoint is an endpoint the plugin mounts on the server.
+type builtinEndpoint struct {
+	name   string
+	path   string
+	prefix bool
+}
+
+// builtinEndpoints returns the enabled built-in endpoints in mount order. The caller holds confMu.
+func (h *ServiceHttp) builtinEndpoints() []builtinEndpoint {
+	snap, _ := h.monitoringSnapshot.Load().(*monitoringSnapshot)
+	if snap == nil {
+		snap = monitoringSnapshotForConfig(h.conf)
+	}
+	var endpoints []builtinEndpoint
+	if snap.enableMetrics {
+		endpoints = append(endpoints, builtinEndpoint{"metrics", snap.metricsPath, true})
+	}
+	endpoints = append(endpoints, builtinEndpoint{"health", snap.healthPath, true})
+	if path := snap.statsPath; path != "" {
+		endpoints = append(endpoints, builtinEndpoint{"stats", path, true})
+	}
+	if h.GraphQL != nil {
+		endpoints = append(endpoints, builtinEndpoint{"graphql", configuredPath(h.conf.GetGraphql().GetPath(), defaultGraphQLPath), false})
+	}
+	if cfg := h.conf.GetJsonrpc(); cfg.GetEnabled() {
+		endpoints = append(endpoints, builtinEndpoint{"jsonrpc", configuredPath(cfg.GetPath(), defaultJSONRPCPath), false})
+	}
+	if cfg := h.conf.GetBatch(); cfg.GetEnabled() {
+		endpoints = append(endpoints, builtinEndpoint{"batch", newBatchSettings(cfg).path, false})
+	}
+	if cfg := h.conf.GetMonitoring().GetLogBoost(); cfg.GetEndpointEnabled() {
+		endpoints = append(endpoints, builtinEndpoint{"log boost", configuredPath(cfg.GetPath(), defaultLogBoostPath), true})
+	}
+	if cfg := h.conf.GetMonitoring().GetActiveRequests(); cfg.GetEnabled() && cfg.GetEndpointEnabled() {
+		endpoints = append(endpoints, builtinEndpoint{"active requests", configuredPath(cfg.GetPath(), defaultActiveRequestsPath), true})
+	}
+	endpoints = append(endpoints, kubernetesEndpoints(h.conf.GetKubernetes())...)
+	endpoints = append(endpoints, grpcParityEndpoints(h.conf.GetGrpcParity())...)
+	if cfg := h.conf.GetCollectionExport(); cfg.GetEnabled() {
+		endpoints = append(endpoints, builtinEndpoint{"collection export", configuredPath(cfg.GetPath(), defaultCollectionExportPath), false})
+	}
+	return endpoints
+}
+
+func configuredPath(path, fallback string) string {
+	if path = strings.TrimSpace(path); path != "" {
+		return path
+	}
+	return fallback
+}
+
+// checkEndpointRoutes reports built-in endpoint paths the router rejects, e.g. templates with a regular
+// expression that does not compile, and endpoints another one hides: prefix endpoints match every path that
+// starts with them, "/health" included "/healthz".
+func (h *ServiceHttp) checkEndpointRoutes() []error {
+	endpoints := h.builtinEndpoints()
+	router := mux.NewRouter()
+	var errs []error
+	for i, e := range endpoints {
+		route := router.NewRoute()
+		if e.prefix {
+			route.PathPrefix(e.path)
+		} else {
+			route.Path(e.path)
+		}
+		if err := route.GetError(); err != nil {
+			errs = append(errs, fmt.Errorf("%s endpoint path %q: %w", e.name, e.path, err))
+			continue
+		}
+		for _, other := range endpoints[:i] {
+			if e.path == other.path || (other.prefix && strings.HasPrefix(e.path, other.path)) {
+				errs = append(errs, fmt.Errorf("%s endpoint %q is shadowed by the %s endpoint %q", e.name, e.path, other.name, other.path))
+			} else if e.prefix && strings.HasPrefix(other.path, e.path) {
+				errs = append(errs, fmt.Errorf("%s endpoint %q overlaps the %s endpoint %q", e.name, e.path, other.name, other.path))
+			}
+		}
+	}
+	return errs
+}
+
+// checkOperationPatterns reports operation patterns with a "*" before their end. Matchers only support a
+// trailing "*", so such a pattern silently matches nothing.
+func checkOperationPatterns(cfg *conf.Http) []error {
+	patterns := map[string][]string{
+		"signed_urls.operations":                     cfg.GetSignedUrls().GetOperations(),
+		"client_info.exempt_operations":              cfg.GetClientInfo().GetExemptOperations(),
+		"request.decode_errors.strict_operations":    cfg.GetRequest().GetDecodeErrors().GetStrictOperations(),
+		"response.size_limit.exempt_operations":      cfg.GetResponse().GetSizeLimit().GetExemptOperations(),
+		"monitoring.excluded_routes":                 cfg.GetMonitoring().GetExcludedRoutes(),
+		"monitoring.unmatched_paths.suppress":        cfg.GetMonitoring().GetUnmatchedPaths().GetSuppress(),
+		"performance.resource_guard.shed_operations": cfg.GetPerformance().GetResourceGuard().GetShedOperations(),
+	}
+	for i, rule := range cfg.GetRequest().GetDefaults() {
+		patterns[fmt.Sprintf("request.defaults[%d].operation", i)] = []string{rule.GetOperation()}
+	}
+	for i, rule := range cfg.GetResponse().GetCacheControl().GetRules() {
+		patterns[fmt.Sprintf("response.cache_control.rules[%d].operation", i)] = []string{rule.GetOperation()}
+	}
+	patterns["middleware.coalesce.exempt_operations"] = cfg.GetMiddleware().GetCoalesce().GetExemptOperations()
+	patterns["middleware.retry_storm.exempt_operations"] = cfg.GetMiddleware().GetRetryStorm().GetExemptOperations()
+	patterns["middleware.handler_retry.operations"] = cfg.GetMiddleware().GetHandlerRetry().GetOperations()
+	for i, rule := range cfg.GetResponse().GetFieldEncryption().GetRules() {
+		patterns[fmt.Sprintf("response.field_encryption.rules[%d].operation", i)] = []string{rule.GetOperation()}
+	}
+	patterns["response.signing.exempt_operations"] = cfg.GetResponse().GetSigning().GetExemptOperations()
+	patterns["response.compression.exempt_operations"] = cfg.GetResponse().GetCompression().GetExemptOperations()
+	for i, rule := range cfg.GetResponse().GetExport().GetRules() {
+		patterns[fmt.Sprintf("response.export.rules[%d].operation", i)] = []string{rule.GetOperation()}
+	}
+	patterns["response.protobuf.operations"] = cfg.GetResponse().GetProtobuf().GetOperations()
+	for i, rule := range cfg.GetRequest().GetContentTypes().GetRules() {
+		patterns[fmt.Sprintf("request.content_types.rules[%d].operation", i)] = []string{rule.GetOperation()}
+	}
+	for i, boost := range cfg.GetMonitoring().GetLogBoost().GetBoosts() {
+		patterns[fmt.Sprintf("monitoring.log_boost.boosts[%d].operation", i)] = []string{boost.GetOperation()}
+	}
+	for i, objective := range cfg.GetMonitoring().GetSlo().GetObjectives() {
+		patterns[fmt.Sprintf("monitoring.slo.objectives[%d].operation", i)] = []string{objective.GetOperation()}
+	}
+	for i, route := range cfg.GetSecurity().GetHoneypot().GetRoutes() {
+		patterns[fmt.Sprintf("security.honeypot.routes[%d].path", i)] = []string{route.GetPath()}
+	}
+	patterns["security.rate_limit.headers.exempt_operations"] = cfg.GetSecurity().GetRateLimit().GetHeaders().GetExemptOperations()
+	for i, cost := range cfg.GetSecurity().GetRateLimit().GetCosts() {
+		patterns[fmt.Sprintf("security.rate_limit.costs[%d].operation", i)] = []string{cost.GetOperation()}
+	}
+	patterns["monitoring.anomaly.operations"] = cfg.GetMonitoring().GetAnomaly().GetOperations()
+	for i, q := range cfg.GetPerformance().GetAdmissionQueues() {
+		patterns[fmt.Sprintf("performance.admission_queues[%d].operations", i)] = q.GetOperations()
+	}
+	patterns["tenant.exempt_operations"] = cfg.GetTenant().GetExemptOperations()
+	patterns["residency.exempt_operations"] = cfg.GetResidency().GetExemptOperations()
+	for i, v := range cfg.GetVirtualHosts().GetHosts() {
+		patterns[fmt.Sprintf("virtual_hosts.hosts[%d].operations", i)] = v.GetOperations()
+	}
+	patterns["mock.operations"] = cfg.GetMock().GetOperations()
+	for i, r := range cfg.GetMock().GetResponses() {
+		patterns[fmt.Sprintf("mock.responses[%d].operation", i)] = []string{r.GetOperation()}
+	}
+
+	var errs []error
+	for _, field := range slices.Sorted(maps.Keys(patterns)) {
+		for _, p := range patterns[field] {
+			if strings.Contains(strings.TrimSuffix(strings.TrimSpace(p), "*"), "*") {
+				errs = append(errs, fmt.Errorf("%s: %q has a \"*\" before its end; only a trailing \"*\" is supported", field, p))
+			}
+		}
+	}
+	return errs
+}
+
+// validateCIDRs reports every entry of cidrs that is not a network in CIDR notation.
+func validateCIDRs(cidrs []string) error {
+	var errs []error
+	for _, cidr := range cidrs {
+		if _, err := netip.ParsePrefix(strings.TrimSpace(cidr)); err != nil {
+			errs = append(errs, fmt.Errorf("invalid trusted CIDR %q: %w", cidr, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreflight_ReportsEveryProblem(t *testing.T) {
+	taken, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer taken.Close()
+
+	h := NewServiceHttp()
+	h.conf = &conf.Http{
+		Network: "tcp",
+		Addr:    taken.Addr().String(),
+		Monitoring: &conf.MonitoringConfig{
+			HealthPath:     "/debug",
+			ActiveRequests: &conf.ActiveRequestsConfig{Enabled: true, EndpointEnabled: true},
+			ExcludedRoutes: []string{"/api.v1.*/Health", "/healthz*"},
+		},
+		Batch:         &conf.BatchConfig{Enabled: true, Path: "/batch/{id:[}"},
+		ProxyProtocol: &conf.ProxyProtocolConfig{Enabled: true, TrustedCidrs: []string{"10.0.0.0/33", "10.0.0.0/8", "lb"}},
+	}
+	h.setDefaultConfig()
+
+	err = h.Preflight()
+	require.Error(t, err)
+	msg := err.Error()
+	assert.Contains(t, msg, `configuration: invalid PROXY protocol configuration: invalid trusted CIDR "10.0.0.0/33"`)
+	assert.Contains(t, msg, `invalid trusted CIDR "lb"`, "every invalid CIDR is reported")
+	assert.Contains(t, msg, "listen address: "+taken.Addr().String()+" is not available")
+	assert.Contains(t, msg, `routes: batch endpoint path "/batch/{id:[}"`)
+	assert.Contains(t, msg, `routes: active requests endpoint "/debug/requests" is shadowed by the health endpoint "/debug"`)
+	assert.Contains(t, msg, `patterns: monitoring.excluded_routes: "/api.v1.*/Health"`)
+	assert.NotContains(t, msg, "/healthz*")
+}
+
+func TestPreflight_Passes(t *testing.T) {
+	h := NewServiceHttp()
+	h.conf = &conf.Http{Network: "tcp", Addr: "127.0.0.1:0", Jsonrpc: &conf.JSONRPCConfig{Enabled: true}}
+	h.setDefaultConfig()
+	assert.NoError(t, h.Preflight())
+}
+
+func TestCheckKeyPair(t *testing.T) {
+	now := time.Now()
+	certPEM, keyPEM := selfSignedPair(t, now.Add(-time.Hour), now.Add(time.Hour))
+	assert.NoError(t, checkKeyPair(certPEM, keyPEM, now))
+	assert.ErrorContains(t, checkKeyPair(certPEM, keyPEM, now.Add(2*time.Hour)), "expired at")
+	assert.ErrorContains(t, checkKeyPair(certPEM, keyPEM, now.Add(-2*time.Hour)), "not valid before")
+	assert.ErrorContains(t, checkKeyPair(certPEM, []byte("junk"), now), "invalid key pair")
+	assert.ErrorContains(t, checkKeyPair(nil, keyPEM, now), "empty")
+}
+
+func selfSignedPair(t *testing.T, notBefore, notAfter time.Time) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "preflight"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
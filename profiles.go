@@ -0,0 +1,207 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-lynx/lynx-http/conf"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Configuration profiles, the environment presets selected by the profile setting.
+const (
+	// ProfileDev favours debugging: long timeouts, decode error details, no rate limiting or circuit breaking.
+	ProfileDev = "dev"
+	// ProfileStaging is ProfileProd with decode error details, for diagnosing integration failures.
+	ProfileStaging = "staging"
+	// ProfileProd enables rate limiting, slow client protection, tight server timeouts and drain hints for
+	// load balancers.
+	ProfileProd = "prod"
+)
+
+// profilePreset returns the preset of profile; unknown profiles are an error.
+func profilePreset(profile string) (*conf.Http, error) {
+	switch profile {
+	case ProfileDev:
+		return &conf.Http{
+			Timeout:    durationpb.New(60 * time.Second),
+			Monitoring: presetMonitoring(),
+			Middleware: &conf.MiddlewareConfig{
+				EnableTracing:    true,
+				EnableLogging:    true,
+				EnableMetrics:    true,
+				EnableRecovery:   true,
+				EnableValidation: true,
+			},
+			Security:       &conf.SecurityConfig{RateLimit: &conf.RateLimitConfig{Enabled: false}},
+			CircuitBreaker: &conf.CircuitBreakerConfig{Enabled: false},
+			Request:        &conf.RequestConfig{DecodeErrors: &conf.DecodeErrorConfig{ExposeDetail: true}},
+		}, nil
+	case ProfileStaging:
+		preset := prodPreset()
+		preset.Request = &conf.RequestConfig{DecodeErrors: &conf.DecodeErrorConfig{ExposeDetail: true}}
+		return preset, nil
+	case ProfileProd:
+		return prodPreset(), nil
+	}
+	return nil, fmt.Errorf("unknown profile %q, valid options: %s, %s, %s", profile, ProfileDev, ProfileStaging, ProfileProd)
+}
+
+func prodPreset() *conf.Http {
+	return &conf.Http{
+		Timeout:    durationpb.New(10 * time.Second),
+		Monitoring: presetMonitoring(),
+		Middleware: &conf.MiddlewareConfig{
+			EnableTracing:    true,
+			EnableLogging:    true,
+			EnableMetrics:    true,
+			EnableRecovery:   true,
+			EnableValidation: true,
+			EnableRateLimit:  true,
+		},
+		Security: &conf.SecurityConfig{
+			MaxRequestSize:       10 << 20,
+			RateLimit:            &conf.RateLimitConfig{Enabled: true, RatePerSecond: 100, BurstLimit: 200},
+			SlowClientProtection: &conf.SlowClientProtectionConfig{Enabled: true},
+		},
+		Performance: &conf.PerformanceConfig{
+			ReadHeaderTimeout: durationpb.New(5 * time.Second),
+			IdleTimeout:       durationpb.New(60 * time.Second),
+		},
+		GracefulShutdown: &conf.GracefulShutdownConfig{ShutdownTimeout: durationpb.New(30 * time.Second)},
+		LoadBalancerHints: &conf.LoadBalancerHintsConfig{
+			Enabled:                true,
+			DrainDelay:             durationpb.New(5 * time.Second),
+			FailHealthWhenDraining: true,
+		},
+	}
+}
+
+// presetMonitoring is the monitoring every profile starts from, the same as without a profile.
+func presetMonitoring() *conf.MonitoringConfig {
+	return &conf.MonitoringConfig{
+		EnableMetrics:           true,
+		EnableRequestLogging:    true,
+		EnableErrorLogging:      true,
+		EnableRouteMetrics:      true,
+		EnableConnectionMetrics: true,
+		EnableQueueMetrics:      true,
+		EnableErrorTypeMetrics:  true,
+		MetricsPath:             defaultMetricsPath,
+		HealthPath:              defaultHealthPath,
+	}
+}
+
+// loadConfig decodes the configuration at v. With a known profile, the decoded settings are applied on top
+// of its preset, so every setting present in the source, false and 0 included, overrides the preset.
+func loadConfig(v config.Value) (*conf.Http, error) {
+	var raw map[string]any
+	if err := v.Scan(&raw); err != nil {
+		return nil, err
+	}
+	if profile, _ := raw["profile"].(string); knownProfile(profile) {
+		return configWithProfile(profile, raw)
+	}
+	c := &conf.Http{}
+	if err := v.Scan(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// applyProfile returns c on top of the preset of its profile, or c itself without a known profile. Fields of
+// c at their zero value cannot be told apart from unset ones, so they keep the preset's value.
+func applyProfile(c *conf.Http) (*conf.Http, error) {
+	if !knownProfile(c.GetProfile()) {
+		return c, nil
+	}
+	raw, err := configMap(c)
+	if err != nil {
+		return nil, err
+	}
+	return configWithProfile(c.GetProfile(), raw)
+}
+
+// configWithProfile decodes raw, a configuration in its JSON form, on top of the preset of profile.
+func configWithProfile(profile string, raw map[string]any) (*conf.Http, error) {
+	preset, err := profilePreset(profile)
+	if err != nil {
+		return nil, err
+	}
+	base, err := configMap(preset)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(mergeConfigMaps(base, raw, preset.ProtoReflect().Descriptor()))
+	if err != nil {
+		return nil, err
+	}
+	c := &conf.Http{}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// configMap returns m in its JSON form, keyed by proto field names.
+func configMap(m proto.Message) (map[string]any, error) {
+	data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// mergeConfigMaps sets every key of over on base and returns base. Keys are normalized to the proto field
+// names of md, so "maxRequestSize" replaces "max_request_size"; nested messages are merged field by field,
+// while lists, maps and scalars replace the base value.
+func mergeConfigMaps(base, over map[string]any, md protoreflect.MessageDescriptor) map[string]any {
+	for key, value := range over {
+		fd := md.Fields().ByName(protoreflect.Name(key))
+		if fd == nil {
+			fd = md.Fields().ByJSONName(key)
+		}
+		if fd == nil {
+			base[key] = value
+			continue
+		}
+		name := string(fd.Name())
+		child, isMap := value.(map[string]any)
+		baseChild, baseIsMap := base[name].(map[string]any)
+		if isMap && baseIsMap && fd.Message() != nil && !fd.IsList() && !fd.IsMap() {
+			base[name] = mergeConfigMaps(baseChild, child, fd.Message())
+			continue
+		}
+		base[name] = value
+	}
+	return base
+}
+
+// knownProfile reports whether profile names a preset. Unknown profiles are left for validation to report.
+func knownProfile(profile string) bool {
+	_, err := profilePreset(profile)
+	return err == nil
+}
+
+// validateProfile requires a known profile and, under ProfileProd, rejects settings exposing internals.
+func validateProfile(c *conf.Http) error {
+	if c.GetProfile() == "" {
+		return nil
+	}
+	if _, err := profilePreset(c.GetProfile()); err != nil {
+		return err
+	}
+	if c.GetProfile() == ProfileProd && c.GetRequest().GetDecodeErrors().GetExposeDetail() {
+		return fmt.Errorf("the %s profile does not allow request.decode_errors.expose_detail", ProfileProd)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// yamlSource is a config source serving one YAML document.
+type yamlSource string
+
+func (s yamlSource) Load() ([]*config.KeyValue, error) {
+	return []*config.KeyValue{{Key: "config.yaml", Value: []byte(s), Format: "yaml"}}, nil
+}
+
+func (s yamlSource) Watch() (config.Watcher, error) {
+	return &idleWatcher{stop: make(chan struct{})}, nil
+}
+
+// idleWatcher reports no changes until stopped.
+type idleWatcher struct{ stop chan struct{} }
+
+func (w *idleWatcher) Next() ([]*config.KeyValue, error) {
+	<-w.stop
+	return nil, context.Canceled
+}
+
+func (w *idleWatcher) Stop() error {
+	close(w.stop)
+	return nil
+}
+
+func loadTestConfig(t *testing.T, doc string) *conf.Http {
+	t.Helper()
+	cfg := config.New(config.WithSource(yamlSource(doc)))
+	require.NoError(t, cfg.Load())
+	t.Cleanup(func() { _ = cfg.Close() })
+	c, err := loadConfig(cfg.Value(confPrefix))
+	require.NoError(t, err)
+	return c
+}
+
+func TestLoadConfig_Profile(t *testing.T) {
+	c := loadTestConfig(t, `
+lynx:
+  http:
+    profile: prod
+    addr: ":9090"
+    middleware:
+      enable_rate_limit: false
+    security:
+      maxRequestSize: 1024
+`)
+	assert.Equal(t, ":9090", c.Addr)
+	assert.Equal(t, 10*time.Second, c.Timeout.AsDuration(), "from the preset")
+	assert.True(t, c.Middleware.EnableTracing, "preset fields of a nested message are kept")
+	assert.False(t, c.Middleware.EnableRateLimit, "an explicit false overrides the preset")
+	assert.Equal(t, int64(1024), c.Security.MaxRequestSize, "JSON names override proto names")
+	assert.True(t, c.Security.RateLimit.Enabled)
+	assert.True(t, c.Security.SlowClientProtection.Enabled)
+	assert.True(t, c.LoadBalancerHints.FailHealthWhenDraining)
+
+	c = loadTestConfig(t, `
+lynx:
+  http:
+    addr: ":9090"
+`)
+	assert.Nil(t, c.Middleware, "no preset without a profile")
+
+	c = loadTestConfig(t, `
+lynx:
+  http:
+    profile: qa
+`)
+	assert.Equal(t, "qa", c.Profile, "unknown profiles are left for validation")
+	assert.ErrorContains(t, validateProfile(c), `unknown profile "qa"`)
+}
+
+func TestConfigure_Profile(t *testing.T) {
+	h := NewServiceHttp()
+	require.NoError(t, h.Configure(&conf.Http{Profile: ProfileDev}))
+	assert.Equal(t, time.Minute, h.conf.Timeout.AsDuration())
+	assert.False(t, h.conf.Middleware.EnableRateLimit)
+	assert.False(t, h.conf.CircuitBreaker.Enabled)
+	assert.True(t, h.conf.Request.DecodeErrors.ExposeDetail)
+
+	require.NoError(t, h.Configure(&conf.Http{Profile: ProfileStaging}))
+	assert.True(t, h.conf.Security.RateLimit.Enabled)
+	assert.True(t, h.conf.Request.DecodeErrors.ExposeDetail)
+
+	err := h.Configure(&conf.Http{Profile: ProfileProd,
+		Request: &conf.RequestConfig{DecodeErrors: &conf.DecodeErrorConfig{ExposeDetail: true}}})
+	assert.ErrorContains(t, err, "does not allow request.decode_errors.expose_detail")
+	assert.ErrorContains(t, h.Configure(&conf.Http{Profile: "qa"}), "unknown profile")
+}
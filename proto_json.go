@@ -0,0 +1,327 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// protoJSONEncoder encodes the proto messages found in reply data with protojson. The envelope is not a proto
+// message, so without it encoding/json writes them as plain Go structs: a Timestamp as {"seconds":..}, a
+// StringValue as {"value":..} and an Any as its raw bytes, none of which protojson clients can read back.
+type protoJSONEncoder struct {
+	opts protojson.MarshalOptions
+}
+
+func newProtoJSONEncoder(cfg *conf.ProtoJSONConfig) (*protoJSONEncoder, error) {
+	if !cfg.GetEnabled() {
+		return nil, nil
+	}
+	resolver, err := protoJSONResolver(cfg.GetDescriptorSets())
+	if err != nil {
+		return nil, err
+	}
+	return &protoJSONEncoder{opts: protojson.MarshalOptions{
+		UseProtoNames:   !cfg.GetUseJsonNames(),
+		EmitUnpopulated: cfg.GetEmitUnpopulated(),
+		UseEnumNumbers:  cfg.GetUseEnumNumbers(),
+		Resolver:        resolver,
+	}}, nil
+}
+
+// protoTypeResolver resolves message and extension types, e.g. protoregistry.GlobalTypes.
+type protoTypeResolver interface {
+	protoregistry.MessageTypeResolver
+	protoregistry.ExtensionTypeResolver
+}
+
+// protoJSONResolver resolves the type URLs of Any values from the types compiled into the binary, then from
+// the messages of the descriptor set files.
+func protoJSONResolver(files []string) (protoTypeResolver, error) {
+	resolvers := protoTypeResolvers{protoregistry.GlobalTypes}
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var set descriptorpb.FileDescriptorSet
+		if err := proto.Unmarshal(b, &set); err != nil {
+			return nil, fmt.Errorf("descriptor set %s: %w", file, err)
+		}
+		descriptors, err := protodesc.NewFiles(&set)
+		if err != nil {
+			return nil, fmt.Errorf("descriptor set %s: %w", file, err)
+		}
+		resolvers = append(resolvers, dynamicpb.NewTypes(descriptors))
+	}
+	if len(resolvers) == 1 {
+		return protoregistry.GlobalTypes, nil
+	}
+	return resolvers, nil
+}
+
+// protoTypeResolvers returns the first type found by its resolvers.
+type protoTypeResolvers []protoTypeResolver
+
+func (rs protoTypeResolvers) FindMessageByName(name protoreflect.FullName) (protoreflect.MessageType, error) {
+	return firstFound(rs, func(r protoTypeResolver) (protoreflect.MessageType, error) { return r.FindMessageByName(name) })
+}
+
+func (rs protoTypeResolvers) FindMessageByURL(url string) (protoreflect.MessageType, error) {
+	return firstFound(rs, func(r protoTypeResolver) (protoreflect.MessageType, error) { return r.FindMessageByURL(url) })
+}
+
+func (rs protoTypeResolvers) FindExtensionByName(field protoreflect.FullName) (protoreflect.ExtensionType, error) {
+	return firstFound(rs, func(r protoTypeResolver) (protoreflect.ExtensionType, error) { return r.FindExtensionByName(field) })
+}
+
+func (rs protoTypeResolvers) FindExtensionByNumber(message protoreflect.FullName, field protoreflect.FieldNumber) (protoreflect.ExtensionType, error) {
+	return firstFound(rs, func(r protoTypeResolver) (protoreflect.ExtensionType, error) {
+		return r.FindExtensionByNumber(message, field)
+	})
+}
+
+func firstFound[T any](rs protoTypeResolvers, find func(protoTypeResolver) (T, error)) (T, error) {
+	for _, r := range rs {
+		if t, err := find(r); !errors.Is(err, protoregistry.NotFound) {
+			return t, err
+		}
+	}
+	var zero T
+	return zero, protoregistry.NotFound
+}
+
+// marshal encodes v like the data of the JSON envelope. A nil encoder uses encoding/json alone.
+func (e *protoJSONEncoder) marshal(v any) ([]byte, error) {
+	if e != nil {
+		converted, _, err := e.convert(v)
+		if err != nil {
+			return nil, err
+		}
+		v = converted
+	}
+	return json.Marshal(v)
+}
+
+// convert replaces the proto messages in v with their protojson encoding: v itself, or the values held in its
+// maps, slices, arrays, pointers and exported struct fields. Structs holding messages become ordered objects
+// keyed as encoding/json would; values without messages are returned as they are, reporting false.
+func (e *protoJSONEncoder) convert(v any) (any, bool, error) {
+	switch v := v.(type) {
+	case nil, json.Marshaler:
+		return v, false, nil
+	case proto.Message:
+		b, err := e.opts.Marshal(v)
+		if err != nil {
+			return nil, false, err
+		}
+		return json.RawMessage(b), true, nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return v, false, nil
+		}
+		return e.convert(rv.Elem().Interface())
+	case reflect.Map:
+		return e.convertMap(rv)
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 || (rv.Kind() == reflect.Slice && rv.IsNil()) {
+			// Bytes stay base64 strings.
+			return v, false, nil
+		}
+		out := make([]any, rv.Len())
+		changed := false
+		for i := range out {
+			c, ok, err := e.convert(rv.Index(i).Interface())
+			if err != nil {
+				return nil, false, fmt.Errorf("[%d]: %w", i, err)
+			}
+			out[i], changed = c, changed || ok
+		}
+		if !changed {
+			return v, false, nil
+		}
+		return out, true, nil
+	case reflect.Struct:
+		return e.convertStruct(rv)
+	default:
+		return v, false, nil
+	}
+}
+
+func (e *protoJSONEncoder) convertMap(rv reflect.Value) (any, bool, error) {
+	if rv.IsNil() {
+		return rv.Interface(), false, nil
+	}
+	out := reflect.MakeMapWithSize(reflect.MapOf(rv.Type().Key(), reflect.TypeFor[any]()), rv.Len())
+	changed := false
+	for iter := rv.MapRange(); iter.Next(); {
+		c, ok, err := e.convert(iter.Value().Interface())
+		if err != nil {
+			return nil, false, fmt.Errorf("[%v]: %w", iter.Key().Interface(), err)
+		}
+		if c == nil {
+			out.SetMapIndex(iter.Key(), reflect.Zero(out.Type().Elem()))
+		} else {
+			out.SetMapIndex(iter.Key(), reflect.ValueOf(c))
+		}
+		changed = changed || ok
+	}
+	if !changed {
+		return rv.Interface(), false, nil
+	}
+	return out.Interface(), true, nil
+}
+
+// convertStruct rebuilds a struct holding messages as an ordered object, honouring the name, "-", omitempty
+// and omitzero parts of json tags. Structs with embedded fields are left to encoding/json.
+func (e *protoJSONEncoder) convertStruct(rv reflect.Value) (any, bool, error) {
+	t := rv.Type()
+	obj := make(jsonObject, 0, t.NumField())
+	changed := false
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			return rv.Interface(), false, nil
+		}
+		tag := f.Tag.Get("json")
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = f.Name
+		}
+		fv := rv.Field(i)
+		if hasTagOption(opts, "omitempty") && isEmptyJSONValue(fv) || hasTagOption(opts, "omitzero") && fv.IsZero() {
+			continue
+		}
+		c, ok, err := e.convert(fv.Interface())
+		if err != nil {
+			return nil, false, fmt.Errorf("%s: %w", name, err)
+		}
+		obj = append(obj, jsonField{name: name, value: c})
+		changed = changed || ok
+	}
+	if !changed {
+		return rv.Interface(), false, nil
+	}
+	return obj, true, nil
+}
+
+func hasTagOption(opts, option string) bool {
+	for opts != "" {
+		var o string
+		o, opts, _ = strings.Cut(opts, ",")
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmptyJSONValue reports whether encoding/json leaves v out under omitempty.
+func isEmptyJSONValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	default:
+		return false
+	}
+}
+
+// jsonObject is a JSON object keeping the order of its fields.
+type jsonObject []jsonField
+
+type jsonField struct {
+	name  string
+	value any
+}
+
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(f.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// convertedPage is a page whose items went through a protoJSONEncoder.
+type convertedPage struct {
+	pager
+	items any
+}
+
+func (p convertedPage) pageItems() any { return p.items }
+
+// withProtoJSON converts the proto messages in the data of JSON replies before encode writes the envelope.
+// Replies in other codecs are left as they are.
+func withProtoJSON(e *protoJSONEncoder, encode http.EncodeResponseFunc) http.EncodeResponseFunc {
+	return func(w http.ResponseWriter, r *http.Request, data any) error {
+		if codec, _ := http.CodecForRequest(r, "Accept"); codec != nil && codec.Name() != "json" {
+			return encode(w, r, data)
+		}
+		var err error
+		if p, ok := data.(pager); ok {
+			var items any
+			if items, _, err = e.convert(p.pageItems()); err == nil {
+				data = convertedPage{pager: p, items: items}
+			}
+		} else if !shouldOmitSuccessData(data) {
+			data, _, err = e.convert(data)
+		}
+		if err != nil {
+			return errors.InternalServer("PROTO_JSON_ENCODE", err.Error())
+		}
+		return encode(w, r, data)
+	}
+}
+
+// validateProtoJSONConfig loads the descriptor sets, which must describe valid, self-contained files.
+func validateProtoJSONConfig(cfg *conf.ProtoJSONConfig) error {
+	for i, file := range cfg.GetDescriptorSets() {
+		if strings.TrimSpace(file) == "" {
+			return fmt.Errorf("descriptor_sets[%d] is empty", i)
+		}
+	}
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	_, err := protoJSONResolver(cfg.GetDescriptorSets())
+	return err
+}
@@ -0,0 +1,155 @@
+package http
+
+import (
+	"encoding/json"
+	nhttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/apipb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func protoJSONService(cfg *conf.ProtoJSONConfig) *ServiceHttp {
+	cfg.Enabled = true
+	return &ServiceHttp{conf: &conf.Http{Response: &conf.ResponseConfig{ProtoJson: cfg}}}
+}
+
+func encodeProtoJSON(t *testing.T, h *ServiceHttp, data any) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	require.NoError(t, h.responseEncoder()(w, httptest.NewRequest(nhttp.MethodGet, "/api", nil), data))
+	return w.Body.String()
+}
+
+func TestResponseEncoder_ProtoJSONWellKnownTypes(t *testing.T) {
+	h := protoJSONService(&conf.ProtoJSONConfig{})
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	fields, err := structpb.NewStruct(map[string]any{"tier": "gold", "score": 1.5})
+	require.NoError(t, err)
+	api, err := anypb.New(&apipb.Api{Name: "users"})
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"code":200,"data":"2026-01-02T03:04:05Z"}`, encodeProtoJSON(t, h, timestamppb.New(at)))
+	assert.JSONEq(t, `{"code":200,"data":"lynx"}`, encodeProtoJSON(t, h, wrapperspb.String("lynx")))
+
+	// Messages nested in maps, slices and structs are converted in place.
+	data := map[string]any{
+		"created": timestamppb.New(at),
+		"ttl":     []any{durationpb.New(90 * time.Second), "plain"},
+		"limit":   wrapperspb.Int64(10),
+		"fields":  fields,
+		"api":     api,
+		"name":    "users",
+	}
+	assert.JSONEq(t, `{"code":200,"data":{
+		"created":"2026-01-02T03:04:05Z",
+		"ttl":["90s","plain"],
+		"limit":"10",
+		"fields":{"tier":"gold","score":1.5},
+		"api":{"@type":"type.googleapis.com/google.protobuf.Api","name":"users"},
+		"name":"users"}}`, encodeProtoJSON(t, h, data))
+
+	result := &FanOutResult{Results: map[string]any{"user": &apipb.Api{Name: "users", Version: "v1"}, "count": 3}}
+	assert.Equal(t, `{"code":200,"data":{"results":{"count":3,"user":{"name":"users","version":"v1"}}}}`,
+		encodeProtoJSON(t, h, result))
+
+	page := NewPage([]*wrapperspb.BoolValue{wrapperspb.Bool(true)}, PageRequest{Page: 1, PageSize: 10}, 1)
+	assert.JSONEq(t, `{"code":200,"data":[true],"page":1,"page_size":10,"total":1}`, encodeProtoJSON(t, h, page))
+}
+
+func TestResponseEncoder_ProtoJSONOptions(t *testing.T) {
+	api := &apipb.Api{Name: "users", SourceContext: nil, Syntax: 1}
+
+	assert.JSONEq(t, `{"code":200,"data":{"name":"users","syntax":"SYNTAX_PROTO3"}}`,
+		encodeProtoJSON(t, protoJSONService(&conf.ProtoJSONConfig{}), api))
+	assert.JSONEq(t, `{"code":200,"data":{"name":"users","syntax":1}}`,
+		encodeProtoJSON(t, protoJSONService(&conf.ProtoJSONConfig{UseEnumNumbers: true}), api))
+	api = &apipb.Api{Name: "users", SourceContext: nil}
+	body := encodeProtoJSON(t, protoJSONService(&conf.ProtoJSONConfig{UseJsonNames: true, EmitUnpopulated: true}), api)
+	var env struct{ Data map[string]any }
+	require.NoError(t, json.Unmarshal([]byte(body), &env))
+	assert.Contains(t, env.Data, "sourceContext")
+
+	// Disabled, encoding/json keeps writing messages as Go structs.
+	plain := &ServiceHttp{conf: &conf.Http{}}
+	assert.JSONEq(t, `{"code":200,"data":{"value":"lynx"}}`, encodeProtoJSON(t, plain, wrapperspb.String("lynx")))
+}
+
+func TestResponseEncoder_ProtoJSONDescriptorSets(t *testing.T) {
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("widget.proto"),
+		Package: proto.String("shop.v1"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Widget"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:     proto.String("name"),
+				JsonName: proto.String("name"),
+				Number:   proto.Int32(1),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			}},
+		}},
+	}
+	set, err := proto.Marshal(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{file}})
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "widget.binpb")
+	require.NoError(t, os.WriteFile(path, set, 0o600))
+
+	value := protowire.AppendString(protowire.AppendTag(nil, 1, protowire.BytesType), "gear")
+	widget := &anypb.Any{TypeUrl: "type.googleapis.com/shop.v1.Widget", Value: value}
+
+	h := protoJSONService(&conf.ProtoJSONConfig{DescriptorSets: []string{path}})
+	assert.JSONEq(t, `{"code":200,"data":{"@type":"type.googleapis.com/shop.v1.Widget","name":"gear"}}`,
+		encodeProtoJSON(t, h, widget))
+
+	// Unknown type URLs fail the reply instead of writing a partial object.
+	w := httptest.NewRecorder()
+	err = protoJSONService(&conf.ProtoJSONConfig{}).responseEncoder()(w, httptest.NewRequest(nhttp.MethodGet, "/api", nil), widget)
+	assert.Error(t, err)
+
+	require.NoError(t, validateProtoJSONConfig(&conf.ProtoJSONConfig{Enabled: true, DescriptorSets: []string{path}}))
+	assert.Error(t, validateProtoJSONConfig(&conf.ProtoJSONConfig{Enabled: true, DescriptorSets: []string{filepath.Join(t.TempDir(), "missing")}}))
+	assert.Error(t, validateProtoJSONConfig(&conf.ProtoJSONConfig{DescriptorSets: []string{" "}}))
+}
+
+func TestResponseEncoder_ProtoJSONRoundTrip(t *testing.T) {
+	encode := protoJSONService(&conf.ProtoJSONConfig{}).responseEncoder()
+	var reply any = NewMultiStatus().Succeeded("a", durationpb.New(time.Minute))
+	srv := httptest.NewServer(nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		_ = encode(w, r, reply)
+	}))
+	defer srv.Close()
+
+	res, err := nhttp.Get(srv.URL)
+	require.NoError(t, err)
+	multi, err := DecodeMultiStatus(res)
+	require.NoError(t, err)
+	require.Len(t, multi.Items, 1)
+	var ttl durationpb.Duration
+	require.NoError(t, protojson.Unmarshal(multi.Items[0].Data, &ttl))
+	assert.Equal(t, time.Minute, ttl.AsDuration())
+
+	// Top-level messages decode back through the protojson codec.
+	reply = wrapperspb.String("lynx")
+	res, err = nhttp.Get(srv.URL)
+	require.NoError(t, err)
+	value, err := DecodeResponse[*wrapperspb.StringValue](res)
+	require.NoError(t, err)
+	assert.Equal(t, "lynx", value.GetValue())
+}
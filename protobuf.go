@@ -0,0 +1,301 @@
+package http
+
+import (
+	"fmt"
+	"mime"
+	nhttp "net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+const (
+	// ProtobufContentType is the media type of binary protobuf replies. Its messageType parameter names the
+	// encoded message, e.g. application/x-protobuf; messageType="api.v1.User".
+	ProtobufContentType = "application/x-protobuf"
+
+	// ProtoEnvelopeType is the message type of enveloped protobuf replies, the binary form of the JSON envelope:
+	//
+	//	message Response {
+	//	  int32 code = 1;
+	//	  string message = 2;
+	//	  google.protobuf.Any data = 3;
+	//	  repeated Warning warnings = 4; // message Warning { string code = 1; string message = 2; }
+	//	}
+	ProtoEnvelopeType = "lynx.http.Response"
+
+	// protobufAccept is the Accept header of clients asking for protobuf replies.
+	protobufAccept = ProtobufContentType + ", application/json;q=0.9"
+
+	protoEnvelopeCodeField    protowire.Number = 1
+	protoEnvelopeMessageField protowire.Number = 2
+	protoEnvelopeDataField    protowire.Number = 3
+	protoEnvelopeWarningField protowire.Number = 4
+	protoWarningCodeField     protowire.Number = 1
+	protoWarningMessageField  protowire.Number = 2
+)
+
+// protobufPolicy answers proto replies in binary protobuf for requests accepting it.
+type protobufPolicy struct {
+	envelope    bool
+	successCode int
+	operations  []string
+}
+
+func newProtobufPolicy(cfg *conf.ProtobufResponseConfig, successCode int) *protobufPolicy {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	return &protobufPolicy{envelope: cfg.GetEnvelope(), successCode: successCode, operations: cfg.GetOperations()}
+}
+
+// applies reports whether r accepts protobuf and its operation may answer in it.
+func (p *protobufPolicy) applies(r *http.Request) bool {
+	if !acceptsProtobuf(r) {
+		return false
+	}
+	if len(p.operations) == 0 {
+		return true
+	}
+	var operation string
+	if tr, ok := transport.FromServerContext(r.Context()); ok {
+		operation = tr.Operation()
+	}
+	return routeMatchesAny(p.operations, operation, r.URL.Path)
+}
+
+// acceptsProtobuf reports whether the Accept header of r names the protobuf media type. Wildcards do not
+// count: browsers and tools sending */* keep getting JSON.
+func acceptsProtobuf(r *nhttp.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || (mediaType != ProtobufContentType && mediaType != "application/protobuf") {
+			continue
+		}
+		if q, err := strconv.ParseFloat(params["q"], 64); err == nil && q <= 0 {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// withProtobuf writes proto replies of requests the policy applies to as binary protobuf, skipping protojson.
+// Other replies, pages among them, go to encode.
+func withProtobuf(p *protobufPolicy, encode http.EncodeResponseFunc) http.EncodeResponseFunc {
+	return func(w http.ResponseWriter, r *http.Request, data any) error {
+		msg, ok := data.(proto.Message)
+		if (!ok && !(p.envelope && data == nil)) || !p.applies(r) {
+			return encode(w, r, data)
+		}
+		var (
+			body        []byte
+			messageType string
+			err         error
+		)
+		if p.envelope {
+			body, err = marshalProtoEnvelope(int32(p.successCode), "", msg, Warnings(r.Context()))
+			messageType = ProtoEnvelopeType
+		} else {
+			body, err = proto.Marshal(msg)
+			messageType = string(msg.ProtoReflect().Descriptor().FullName())
+		}
+		if err != nil {
+			w.WriteHeader(nhttp.StatusInternalServerError)
+			return err
+		}
+		// Written by hand: mime.FormatMediaType would lowercase the parameter name.
+		w.Header().Set("Content-Type", ProtobufContentType+`; messageType="`+messageType+`"`)
+		_, err = w.Write(body)
+		return err
+	}
+}
+
+// marshalProtoEnvelope encodes a ProtoEnvelopeType message. Data is left out when msg is nil or empty, like
+// the JSON envelope leaves out empty replies.
+func marshalProtoEnvelope(code int32, message string, msg proto.Message, warnings []Warning) ([]byte, error) {
+	var b []byte
+	if code != 0 {
+		b = protowire.AppendTag(b, protoEnvelopeCodeField, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(int64(code)))
+	}
+	if message != "" {
+		b = protowire.AppendTag(b, protoEnvelopeMessageField, protowire.BytesType)
+		b = protowire.AppendString(b, message)
+	}
+	if msg != nil && proto.Size(msg) > 0 {
+		data, err := anypb.New(msg)
+		if err != nil {
+			return nil, err
+		}
+		encoded, err := proto.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+		b = protowire.AppendTag(b, protoEnvelopeDataField, protowire.BytesType)
+		b = protowire.AppendBytes(b, encoded)
+	}
+	for _, w := range warnings {
+		var wb []byte
+		wb = protowire.AppendTag(wb, protoWarningCodeField, protowire.BytesType)
+		wb = protowire.AppendString(wb, w.Code)
+		if w.Message != "" {
+			wb = protowire.AppendTag(wb, protoWarningMessageField, protowire.BytesType)
+			wb = protowire.AppendString(wb, w.Message)
+		}
+		b = protowire.AppendTag(b, protoEnvelopeWarningField, protowire.BytesType)
+		b = protowire.AppendBytes(b, wb)
+	}
+	return b, nil
+}
+
+// protoEnvelope is a decoded ProtoEnvelopeType message.
+type protoEnvelope struct {
+	code     int32
+	message  string
+	data     *anypb.Any
+	warnings []Warning
+}
+
+// unmarshalProtoEnvelope decodes a ProtoEnvelopeType message, skipping unknown fields.
+func unmarshalProtoEnvelope(b []byte) (protoEnvelope, error) {
+	var env protoEnvelope
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return env, protowire.ParseError(n)
+		}
+		b = b[n:]
+		switch {
+		case num == protoEnvelopeCodeField && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return env, protowire.ParseError(n)
+			}
+			env.code, b = int32(v), b[n:]
+		case num == protoEnvelopeMessageField && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(b)
+			if n < 0 {
+				return env, protowire.ParseError(n)
+			}
+			env.message, b = v, b[n:]
+		case num == protoEnvelopeDataField && typ == protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return env, protowire.ParseError(n)
+			}
+			env.data = &anypb.Any{}
+			if err := proto.Unmarshal(v, env.data); err != nil {
+				return env, err
+			}
+			b = b[n:]
+		case num == protoEnvelopeWarningField && typ == protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return env, protowire.ParseError(n)
+			}
+			w, err := unmarshalProtoWarning(v)
+			if err != nil {
+				return env, err
+			}
+			env.warnings, b = append(env.warnings, w), b[n:]
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return env, protowire.ParseError(n)
+			}
+			b = b[n:]
+		}
+	}
+	return env, nil
+}
+
+// unmarshalProtoWarning decodes a Warning message of the envelope, skipping unknown fields.
+func unmarshalProtoWarning(b []byte) (Warning, error) {
+	var w Warning
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return w, protowire.ParseError(n)
+		}
+		b = b[n:]
+		if (num == protoWarningCodeField || num == protoWarningMessageField) && typ == protowire.BytesType {
+			v, n := protowire.ConsumeString(b)
+			if n < 0 {
+				return w, protowire.ParseError(n)
+			}
+			if num == protoWarningCodeField {
+				w.Code = v
+			} else {
+				w.Message = v
+			}
+			b = b[n:]
+			continue
+		}
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return w, protowire.ParseError(n)
+		}
+		b = b[n:]
+	}
+	return w, nil
+}
+
+// protobufMessageType returns the messageType parameter of a protobuf Content-Type, false for other types.
+// mime.ParseMediaType lowercases parameter names.
+func protobufMessageType(contentType string) (string, bool) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || (mediaType != ProtobufContentType && mediaType != "application/protobuf") {
+		return "", false
+	}
+	return params["messagetype"], true
+}
+
+// unwrapProtobuf validates a protobuf reply of messageType and returns the encoded message it carries and
+// that message's type, nil when there is none. Enveloped replies are checked like JSON envelopes.
+func unwrapProtobuf(messageType string, body []byte, o decodeOptions) ([]byte, string, error) {
+	if messageType != ProtoEnvelopeType {
+		if len(body) == 0 {
+			return nil, "", nil
+		}
+		return body, messageType, nil
+	}
+	env, err := unmarshalProtoEnvelope(body)
+	if err != nil {
+		return nil, "", errors.New(nhttp.StatusBadGateway, invalidEnvelopeReason, "malformed response envelope").WithCause(err)
+	}
+	if err := checkEnvelopeCode(int(env.code), env.message, o); err != nil {
+		return nil, "", err
+	}
+	if o.warnings != nil {
+		*o.warnings = env.warnings
+	}
+	if env.data == nil {
+		return nil, "", nil
+	}
+	return env.data.GetValue(), string(env.data.MessageName()), nil
+}
+
+// unmarshalProtobufData decodes a protobuf reply of messageType into v, which must be a message of that type.
+func unmarshalProtobufData(data []byte, messageType string, v any) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return errors.New(nhttp.StatusBadGateway, invalidEnvelopeReason,
+			fmt.Sprintf("protobuf reply needs a proto.Message target, got %T", v))
+	}
+	if name := string(msg.ProtoReflect().Descriptor().FullName()); messageType != "" && name != messageType {
+		return errors.New(nhttp.StatusBadGateway, invalidEnvelopeReason,
+			fmt.Sprintf("protobuf reply is a %s, not a %s", messageType, name))
+	}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return errors.New(nhttp.StatusBadGateway, invalidEnvelopeReason, "malformed response data").WithCause(err)
+	}
+	return nil
+}
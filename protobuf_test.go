@@ -0,0 +1,182 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	nhttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/apipb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func protobufService(cfg *conf.ProtobufResponseConfig) *ServiceHttp {
+	cfg.Enabled = true
+	return &ServiceHttp{conf: &conf.Http{Response: &conf.ResponseConfig{Protobuf: cfg}}}
+}
+
+func protobufRequest(path, accept string) *nhttp.Request {
+	r := httptest.NewRequest(nhttp.MethodGet, path, nil)
+	r.Header.Set("Accept", accept)
+	return r
+}
+
+func TestResponseEncoder_Protobuf(t *testing.T) {
+	enc := protobufService(&conf.ProtobufResponseConfig{Operations: []string{"/internal/*"}}).responseEncoder()
+	reply := &apipb.Api{Name: "users", Version: "v1"}
+
+	w := httptest.NewRecorder()
+	require.NoError(t, enc(w, protobufRequest("/internal/api", ProtobufContentType), reply))
+	assert.Equal(t, `application/x-protobuf; messageType="google.protobuf.Api"`, w.Header().Get("Content-Type"))
+	var got apipb.Api
+	require.NoError(t, proto.Unmarshal(w.Body.Bytes(), &got))
+	assert.True(t, proto.Equal(reply, &got))
+
+	// JSON callers, other operations and replies that are not proto messages keep the JSON envelope.
+	for _, r := range []*nhttp.Request{
+		protobufRequest("/internal/api", "*/*"),
+		protobufRequest("/internal/api", "application/x-protobuf;q=0, application/json"),
+		protobufRequest("/public/api", ProtobufContentType),
+	} {
+		w = httptest.NewRecorder()
+		require.NoError(t, enc(w, r, reply))
+		assert.JSONEq(t, `{"code":200,"data":{"name":"users","version":"v1"}}`, w.Body.String())
+	}
+	w = httptest.NewRecorder()
+	require.NoError(t, enc(w, protobufRequest("/internal/api", ProtobufContentType), map[string]string{"name": "users"}))
+	assert.JSONEq(t, `{"code":200,"data":{"name":"users"}}`, w.Body.String())
+}
+
+func TestResponseEncoder_ProtobufEnvelope(t *testing.T) {
+	enc := protobufService(&conf.ProtobufResponseConfig{Envelope: true}).responseEncoder()
+
+	w := httptest.NewRecorder()
+	require.NoError(t, enc(w, protobufRequest("/api", "application/protobuf"), wrapperspb.String("lynx")))
+	assert.Equal(t, `application/x-protobuf; messageType="lynx.http.Response"`, w.Header().Get("Content-Type"))
+	env, err := unmarshalProtoEnvelope(w.Body.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, int32(nhttp.StatusOK), env.code)
+	got, err := env.data.UnmarshalNew()
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(wrapperspb.String("lynx"), got))
+
+	// Empty replies leave out the data, like the JSON envelope.
+	w = httptest.NewRecorder()
+	require.NoError(t, enc(w, protobufRequest("/api", ProtobufContentType), nil))
+	env, err = unmarshalProtoEnvelope(w.Body.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, int32(nhttp.StatusOK), env.code)
+	assert.Nil(t, env.data)
+}
+
+func TestDecodeEnvelope_Protobuf(t *testing.T) {
+	response := func(messageType string, body []byte) *nhttp.Response {
+		contentType := ProtobufContentType + `; messageType="` + messageType + `"`
+		return &nhttp.Response{Header: nhttp.Header{"Content-Type": {contentType}}, Body: io.NopCloser(bytes.NewReader(body))}
+	}
+	raw, err := proto.Marshal(wrapperspb.String("lynx"))
+	require.NoError(t, err)
+	enveloped, err := marshalProtoEnvelope(nhttp.StatusOK, "", wrapperspb.String("lynx"), nil)
+	require.NoError(t, err)
+
+	var out wrapperspb.StringValue
+	require.NoError(t, DecodeEnvelope(response("google.protobuf.StringValue", raw), &out))
+	assert.Equal(t, "lynx", out.GetValue())
+	typed, err := DecodeResponse[*wrapperspb.StringValue](response(ProtoEnvelopeType, enveloped))
+	require.NoError(t, err)
+	assert.Equal(t, "lynx", typed.GetValue())
+
+	// Envelope codes are checked like JSON envelope codes.
+	failed, err := marshalProtoEnvelope(100004, "user not found", nil, nil)
+	require.NoError(t, err)
+	err = DecodeEnvelope(response(ProtoEnvelopeType, failed), &out)
+	assert.Equal(t, 100004, int(errors.Code(err)))
+	assert.Equal(t, "user not found", errors.FromError(err).Message)
+
+	// The target must be a message of the encoded type.
+	err = DecodeEnvelope(response(ProtoEnvelopeType, enveloped), &wrapperspb.Int64Value{})
+	assert.Equal(t, invalidEnvelopeReason, errors.Reason(err))
+	var plain struct{ Value string }
+	err = DecodeEnvelope(response("google.protobuf.StringValue", raw), &plain)
+	assert.Equal(t, invalidEnvelopeReason, errors.Reason(err))
+	err = DecodeEnvelope(response(ProtoEnvelopeType, []byte{0xff}), &out)
+	assert.Equal(t, invalidEnvelopeReason, errors.Reason(err))
+}
+
+func TestNewKratosClient_Protobuf(t *testing.T) {
+	h := protobufService(&conf.ProtobufResponseConfig{Envelope: true})
+	encode := h.responseEncoder()
+	var accept string
+	srv := httptest.NewServer(nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		accept = r.Header.Get("Accept")
+		_ = encode(w, r, wrapperspb.String("lynx"))
+	}))
+	defer srv.Close()
+
+	client, err := NewKratosClient(context.Background(), ClientConfig{Target: "protobuf-client", Endpoint: srv.URL, Protobuf: true})
+	require.NoError(t, err)
+	defer client.Close()
+
+	var out wrapperspb.StringValue
+	require.NoError(t, client.Invoke(context.Background(), nhttp.MethodGet, "/user", nil, &out))
+	assert.Equal(t, "lynx", out.GetValue())
+	assert.Equal(t, protobufAccept, accept)
+}
+
+func TestResponseEncoder_Codecs(t *testing.T) {
+	// The proto codec gets the protobuf envelope for proto and empty data, without enabling response.protobuf.
+	w := httptest.NewRecorder()
+	require.NoError(t, ResponseEncoder(w, protobufRequest("/api", "application/proto"), wrapperspb.String("lynx")))
+	assert.Equal(t, `application/x-protobuf; messageType="lynx.http.Response"`, w.Header().Get("Content-Type"))
+	env, err := unmarshalProtoEnvelope(w.Body.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, int32(nhttp.StatusOK), env.code)
+	got, err := env.data.UnmarshalNew()
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(wrapperspb.String("lynx"), got))
+
+	w = httptest.NewRecorder()
+	EncodeErrorFunc(w, protobufRequest("/api", "application/proto"), errors.NotFound("USER_NOT_FOUND", "user not found"))
+	env, err = unmarshalProtoEnvelope(w.Body.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, int32(nhttp.StatusNotFound), env.code)
+
+	// Data the proto codec cannot carry and pages get the JSON envelope, which always carries the code.
+	for _, c := range []struct {
+		accept string
+		data   any
+		want   string
+	}{
+		{"application/proto", map[string]string{"name": "users"}, `{"code":200,"data":{"name":"users"}}`},
+		{"application/proto", NewPage([]string{"a"}, PageRequest{Page: 1, PageSize: 10}, 1), `{"code":200,"data":["a"],"page":1,"page_size":10,"total":1}`},
+		{"application/unknown", []int{1, 2}, `{"code":200,"data":[1,2]}`},
+		{"application/json", &apipb.Api{Name: "users"}, `{"code":200,"data":{"name":"users"}}`},
+	} {
+		w = httptest.NewRecorder()
+		require.NoError(t, ResponseEncoder(w, protobufRequest("/api", c.accept), c.data), c.accept)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"), c.accept)
+		assert.JSONEq(t, c.want, w.Body.String(), c.accept)
+	}
+	w = httptest.NewRecorder()
+	require.NoError(t, successEncoder(0)(w, protobufRequest("/api", "application/json"), "ok"))
+	assert.JSONEq(t, `{"code":0,"data":"ok"}`, w.Body.String())
+
+	// Other registered codecs marshal the envelope with the negotiated codec.
+	w = httptest.NewRecorder()
+	require.NoError(t, ResponseEncoder(w, protobufRequest("/api", "application/xml"), "users"))
+	assert.Equal(t, "application/xml", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "<Code>200</Code>")
+	assert.Contains(t, w.Body.String(), "<Data>users</Data>")
+
+	w = httptest.NewRecorder()
+	require.NoError(t, ResponseEncoder(w, protobufRequest("/api", "application/yaml"), []int{1, 2}))
+	assert.Equal(t, "application/yaml", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "code: 200")
+}
@@ -0,0 +1,350 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-lynx/lynx-http/conf"
+)
+
+const (
+	defaultProxyHeaderTimeout = 5 * time.Second
+	// proxyV1MaxLength is the longest valid v1 header line, CRLF included.
+	proxyV1MaxLength = 107
+
+	proxyResultV1        = "v1"
+	proxyResultV2        = "v2"
+	proxyResultLocal     = "local"
+	proxyResultAbsent    = "absent"
+	proxyResultUntrusted = "untrusted"
+	proxyResultInvalid   = "invalid"
+)
+
+var proxyV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+var errProxyHeaderMissing = errors.New("PROXY header required")
+
+// proxyClientAddrKey carries the client address announced by a PROXY header in the connection context.
+type proxyClientAddrKey struct{}
+
+// proxyConn replaces the peer address with the one from the PROXY header. Reads go through the
+// buffered reader that consumed the header.
+type proxyConn struct {
+	net.Conn
+	r      *bufio.Reader
+	remote net.Addr // nil when the header did not carry a source address
+}
+
+func (c *proxyConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
+
+func (c *proxyConn) RemoteAddr() net.Addr {
+	if c.remote != nil {
+		return c.remote
+	}
+	return c.Conn.RemoteAddr()
+}
+
+// proxyListener reads PROXY headers on accepted connections before handing them to the server.
+// Headers are read off the accept path, so a client that stalls cannot block other connections,
+// and connection hooks already see the resolved client address.
+type proxyListener struct {
+	net.Listener
+	trusted  []netip.Prefix
+	required bool
+	timeout  time.Duration
+	onResult func(result string)
+
+	conns     chan net.Conn
+	errs      chan error
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newProxyListener(inner net.Listener, cfg *conf.ProxyProtocolConfig, onResult func(string)) *proxyListener {
+	l := &proxyListener{
+		Listener: inner,
+		required: cfg.GetRequired(),
+		timeout:  defaultProxyHeaderTimeout,
+		onResult: onResult,
+		conns:    make(chan net.Conn),
+		errs:     make(chan error),
+		done:     make(chan struct{}),
+	}
+	if d := cfg.GetHeaderTimeout().AsDuration(); d > 0 {
+		l.timeout = d
+	}
+	for _, cidr := range cfg.GetTrustedCidrs() {
+		if prefix, err := netip.ParsePrefix(strings.TrimSpace(cidr)); err == nil {
+			l.trusted = append(l.trusted, prefix)
+		}
+	}
+	go l.acceptLoop()
+	return l
+}
+
+func (l *proxyListener) acceptLoop() {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			select {
+			case l.errs <- err:
+			case <-l.done:
+				return
+			}
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			continue
+		}
+		go l.handshake(conn)
+	}
+}
+
+func (l *proxyListener) handshake(conn net.Conn) {
+	if !l.isTrusted(conn.RemoteAddr()) {
+		l.result(proxyResultUntrusted)
+		l.deliver(conn)
+		return
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(l.timeout))
+	pc, result, err := readProxyHeader(conn, l.required)
+	_ = conn.SetReadDeadline(time.Time{})
+	if err != nil {
+		l.result(proxyResultInvalid)
+		_ = conn.Close()
+		return
+	}
+	l.result(result)
+	l.deliver(pc)
+}
+
+func (l *proxyListener) deliver(conn net.Conn) {
+	select {
+	case l.conns <- conn:
+	case <-l.done:
+		_ = conn.Close()
+	}
+}
+
+func (l *proxyListener) result(result string) {
+	if l.onResult != nil {
+		l.onResult(result)
+	}
+}
+
+func (l *proxyListener) isTrusted(addr net.Addr) bool {
+	ap, err := netip.ParseAddrPort(addr.String())
+	if err != nil {
+		return false
+	}
+	ip := ap.Addr().Unmap()
+	for _, prefix := range l.trusted {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Accept returns the next connection whose header has been processed.
+func (l *proxyListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case err := <-l.errs:
+		return nil, err
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *proxyListener) Close() error {
+	l.closeOnce.Do(func() { close(l.done) })
+	return l.Listener.Close()
+}
+
+// readProxyHeader consumes a v1 or v2 PROXY header if the connection starts with one.
+func readProxyHeader(conn net.Conn, required bool) (*proxyConn, string, error) {
+	pc := &proxyConn{Conn: conn, r: bufio.NewReader(conn)}
+	first, err := pc.r.Peek(1)
+	if err != nil {
+		return nil, "", err
+	}
+	switch first[0] {
+	case 'P':
+		if prefix, err := pc.r.Peek(6); err == nil && string(prefix) == "PROXY " {
+			pc.remote, err = parseProxyV1(pc.r)
+			if err != nil {
+				return nil, "", err
+			}
+			return pc, proxyResultV1, nil
+		}
+	case proxyV2Signature[0]:
+		if sig, err := pc.r.Peek(len(proxyV2Signature)); err == nil && bytes.Equal(sig, proxyV2Signature) {
+			local, remote, err := parseProxyV2(pc.r)
+			if err != nil {
+				return nil, "", err
+			}
+			pc.remote = remote
+			if local {
+				return pc, proxyResultLocal, nil
+			}
+			return pc, proxyResultV2, nil
+		}
+	}
+	if required {
+		return nil, "", errProxyHeaderMissing
+	}
+	return pc, proxyResultAbsent, nil
+}
+
+// parseProxyV1 parses "PROXY TCP4|TCP6|UNKNOWN src dst sport dport\r\n". UNKNOWN yields a nil address.
+func parseProxyV1(r *bufio.Reader) (net.Addr, error) {
+	line, err := r.ReadSlice('\n')
+	if err != nil {
+		return nil, fmt.Errorf("read PROXY v1 header: %w", err)
+	}
+	if len(line) > proxyV1MaxLength || !bytes.HasSuffix(line, []byte("\r\n")) {
+		return nil, errors.New("malformed PROXY v1 header")
+	}
+	fields := strings.Fields(string(line[:len(line)-2]))
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil, nil
+	}
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return nil, errors.New("malformed PROXY v1 header")
+	}
+	ip, err := netip.ParseAddr(fields[2])
+	if err != nil || ip.Is4() != (fields[1] == "TCP4") {
+		return nil, fmt.Errorf("invalid PROXY v1 source address %q", fields[2])
+	}
+	port, err := strconv.ParseUint(fields[4], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PROXY v1 source port %q", fields[4])
+	}
+	return net.TCPAddrFromAddrPort(netip.AddrPortFrom(ip, uint16(port))), nil
+}
+
+// parseProxyV2 parses the binary header. LOCAL commands (load balancer health checks) and non-IP
+// address families keep the peer address.
+func parseProxyV2(r *bufio.Reader) (local bool, remote net.Addr, err error) {
+	var hdr [16]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return false, nil, fmt.Errorf("read PROXY v2 header: %w", err)
+	}
+	if hdr[12]>>4 != 2 {
+		return false, nil, fmt.Errorf("unsupported PROXY protocol version %d", hdr[12]>>4)
+	}
+	payload := make([]byte, binary.BigEndian.Uint16(hdr[14:16]))
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return false, nil, fmt.Errorf("read PROXY v2 addresses: %w", err)
+	}
+	switch hdr[12] & 0x0f {
+	case 0x0:
+		return true, nil, nil
+	case 0x1:
+	default:
+		return false, nil, fmt.Errorf("unsupported PROXY v2 command %d", hdr[12]&0x0f)
+	}
+
+	switch hdr[13] >> 4 {
+	case 0x1: // AF_INET
+		if len(payload) < 12 {
+			return false, nil, errors.New("short PROXY v2 IPv4 address block")
+		}
+		ip := netip.AddrFrom4([4]byte(payload[0:4]))
+		return false, net.TCPAddrFromAddrPort(netip.AddrPortFrom(ip, binary.BigEndian.Uint16(payload[8:10]))), nil
+	case 0x2: // AF_INET6
+		if len(payload) < 36 {
+			return false, nil, errors.New("short PROXY v2 IPv6 address block")
+		}
+		ip := netip.AddrFrom16([16]byte(payload[0:16]))
+		return false, net.TCPAddrFromAddrPort(netip.AddrPortFrom(ip, binary.BigEndian.Uint16(payload[32:34]))), nil
+	default:
+		return false, nil, nil
+	}
+}
+
+// proxyConnContext exposes a PROXY-announced client address to request handlers.
+func proxyConnContext(ctx context.Context, conn net.Conn) context.Context {
+	if tc, ok := conn.(*tls.Conn); ok {
+		conn = tc.NetConn()
+	}
+	if pc, ok := conn.(*proxyConn); ok && pc.remote != nil {
+		return context.WithValue(ctx, proxyClientAddrKey{}, remoteHost(pc.remote.String()))
+	}
+	return ctx
+}
+
+// serverConn unwraps the TLS and PROXY protocol layers of a server connection. Server hooks receive a *tls.Conn
+// on TLS listeners.
+func serverConn(conn net.Conn) net.Conn {
+	if tc, ok := conn.(*tls.Conn); ok {
+		conn = tc.NetConn()
+	}
+	if pc, ok := conn.(*proxyConn); ok {
+		conn = pc.Conn
+	}
+	return conn
+}
+
+// acceptedConn unwraps a server connection to the accepted one, such as a *net.TCPConn.
+func acceptedConn(conn net.Conn) net.Conn {
+	conn = serverConn(conn)
+	if tc, ok := conn.(*timeoutConn); ok {
+		conn = tc.Conn
+	}
+	return conn
+}
+
+func proxyClientAddr(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	addr, _ := ctx.Value(proxyClientAddrKey{}).(string)
+	return addr
+}
+
+// wrapProxyProtocol wraps the listener when PROXY protocol is enabled.
+func (h *ServiceHttp) wrapProxyProtocol(lis net.Listener) net.Listener {
+	cfg := h.conf.GetProxyProtocol()
+	if !cfg.GetEnabled() {
+		return lis
+	}
+	return newProxyListener(lis, cfg, func(result string) {
+		if h.proxyProtocolConns != nil {
+			h.proxyProtocolConns.WithLabelValues(result).Inc()
+		}
+	})
+}
+
+// validateProxyProtocolConfig requires at least one trusted network when PROXY protocol is enabled.
+func validateProxyProtocolConfig(cfg *conf.ProxyProtocolConfig) error {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	if len(cfg.TrustedCidrs) == 0 {
+		return fmt.Errorf("at least one trusted CIDR is required")
+	}
+	if err := validateCIDRs(cfg.TrustedCidrs); err != nil {
+		return err
+	}
+	if cfg.HeaderTimeout != nil && cfg.HeaderTimeout.AsDuration() < 0 {
+		return fmt.Errorf("header timeout cannot be negative")
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"io"
+	"net"
+	nhttp "net/http"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pipeWith returns the server end of a pipe whose client end writes data.
+func pipeWith(t *testing.T, data []byte) net.Conn {
+	t.Helper()
+	server, client := net.Pipe()
+	t.Cleanup(func() { _ = server.Close(); _ = client.Close() })
+	go func() { _, _ = client.Write(data) }()
+	return server
+}
+
+func proxyV2Header(cmd byte, fam byte, addrs []byte) []byte {
+	hdr := append([]byte{}, proxyV2Signature...)
+	hdr = append(hdr, 0x20|cmd, fam, 0, 0)
+	binary.BigEndian.PutUint16(hdr[14:16], uint16(len(addrs)))
+	return append(hdr, addrs...)
+}
+
+func TestReadProxyHeader(t *testing.T) {
+	ipv4 := []byte{203, 0, 113, 7, 10, 0, 0, 1, 0x30, 0x39, 0x1f, 0x90}
+	tests := []struct {
+		name     string
+		data     []byte
+		required bool
+		result   string
+		remote   string
+		wantErr  bool
+	}{
+		{name: "v1 tcp4", data: []byte("PROXY TCP4 203.0.113.7 10.0.0.1 12345 8080\r\n"), result: proxyResultV1, remote: "203.0.113.7:12345"},
+		{name: "v1 tcp6", data: []byte("PROXY TCP6 2001:db8::1 2001:db8::2 443 8080\r\n"), result: proxyResultV1, remote: "[2001:db8::1]:443"},
+		{name: "v1 unknown", data: []byte("PROXY UNKNOWN\r\n"), result: proxyResultV1},
+		{name: "v1 malformed", data: []byte("PROXY TCP4 nope 10.0.0.1 1 2\r\n"), wantErr: true},
+		{name: "v1 family mismatch", data: []byte("PROXY TCP6 203.0.113.7 10.0.0.1 1 2\r\n"), wantErr: true},
+		{name: "v2 ipv4", data: proxyV2Header(0x1, 0x11, ipv4), result: proxyResultV2, remote: "203.0.113.7:12345"},
+		{name: "v2 local", data: proxyV2Header(0x0, 0x00, nil), result: proxyResultLocal},
+		{name: "v2 short", data: proxyV2Header(0x1, 0x11, ipv4[:6]), wantErr: true},
+		{name: "absent", data: []byte("GET / HTTP/1.1\r\n"), result: proxyResultAbsent},
+		{name: "absent required", data: []byte("GET / HTTP/1.1\r\n"), required: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := pipeWith(t, append(tt.data, "GET / HTTP/1.1\r\n"...))
+			pc, result, err := readProxyHeader(conn, tt.required)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.result, result)
+			if tt.remote != "" {
+				assert.Equal(t, tt.remote, pc.RemoteAddr().String())
+			} else {
+				assert.Equal(t, conn.RemoteAddr(), pc.RemoteAddr())
+			}
+
+			// The request bytes after the header are still readable.
+			line, err := bufio.NewReader(pc).ReadString('\n')
+			require.NoError(t, err)
+			assert.Equal(t, "GET / HTTP/1.1\r\n", line)
+		})
+	}
+}
+
+func serveProxyListener(t *testing.T, cfg *conf.ProxyProtocolConfig, results chan<- string) string {
+	t.Helper()
+	inner, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	lis := newProxyListener(inner, cfg, func(r string) { results <- r })
+	srv := &nhttp.Server{
+		ConnContext: proxyConnContext,
+		Handler: nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+			_, _ = io.WriteString(w, getClientIP(r.Context(), nil))
+		}),
+	}
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(func() { _ = srv.Close() })
+	return inner.Addr().String()
+}
+
+func roundTrip(t *testing.T, addr string, preamble string) *nhttp.Response {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	_, err = io.WriteString(conn, preamble+"GET / HTTP/1.1\r\nHost: test\r\nConnection: close\r\n\r\n")
+	require.NoError(t, err)
+	resp, err := nhttp.ReadResponse(bufio.NewReader(conn), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func TestProxyListener_TrustedPeer(t *testing.T) {
+	results := make(chan string, 4)
+	addr := serveProxyListener(t, &conf.ProxyProtocolConfig{Enabled: true, TrustedCidrs: []string{"127.0.0.0/8"}}, results)
+
+	resp := roundTrip(t, addr, "PROXY TCP4 198.51.100.9 127.0.0.1 40000 80\r\n")
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, nhttp.StatusOK, resp.StatusCode)
+	assert.Equal(t, "198.51.100.9", string(body))
+	assert.Equal(t, proxyResultV1, <-results)
+}
+
+func TestProxyListener_UntrustedPeerIgnored(t *testing.T) {
+	results := make(chan string, 4)
+	addr := serveProxyListener(t, &conf.ProxyProtocolConfig{Enabled: true, TrustedCidrs: []string{"10.0.0.0/8"}}, results)
+
+	resp := roundTrip(t, addr, "PROXY TCP4 198.51.100.9 127.0.0.1 40000 80\r\n")
+	assert.Equal(t, nhttp.StatusBadRequest, resp.StatusCode, "a forged header is not honoured")
+	assert.Equal(t, proxyResultUntrusted, <-results)
+}
+
+func TestProxyListener_TLS(t *testing.T) {
+	inner, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	lis := newProxyListener(inner, &conf.ProxyProtocolConfig{Enabled: true, TrustedCidrs: []string{"127.0.0.0/8"}}, nil)
+	certPEM, keyPEM := selfSignedPair(t, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+
+	// As with ServeTLS, the server hooks receive *tls.Conn wrapping the PROXY protocol connection.
+	accepted := make(chan net.Conn, 1)
+	srv := &nhttp.Server{
+		ConnContext: proxyConnContext,
+		ConnState: func(conn net.Conn, state nhttp.ConnState) {
+			if state == nhttp.StateNew {
+				accepted <- acceptedConn(conn)
+			}
+		},
+		Handler: nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+			_, _ = io.WriteString(w, proxyClientAddr(r.Context()))
+		}),
+	}
+	go func() { _ = srv.Serve(tls.NewListener(lis, &tls.Config{Certificates: []tls.Certificate{cert}})) }()
+	t.Cleanup(func() { _ = srv.Close() })
+
+	raw, err := net.Dial("tcp", inner.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = raw.Close() })
+	_, err = io.WriteString(raw, "PROXY TCP4 198.51.100.9 127.0.0.1 40000 443\r\n")
+	require.NoError(t, err)
+	conn := tls.Client(raw, &tls.Config{InsecureSkipVerify: true})
+	_, err = io.WriteString(conn, "GET / HTTP/1.1\r\nHost: test\r\nConnection: close\r\n\r\n")
+	require.NoError(t, err)
+	resp, err := nhttp.ReadResponse(bufio.NewReader(conn), nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "198.51.100.9", string(body))
+	assert.IsType(t, &net.TCPConn{}, <-accepted, "TCP settings reach the accepted connection")
+}
+
+func TestGetClientIP_ProxyProtocolPrecedence(t *testing.T) {
+	ctx := context.WithValue(context.Background(), proxyClientAddrKey{}, "198.51.100.9")
+	h := newFakeHeader(map[string]string{"X-Forwarded-For": "1.2.3.4"})
+	assert.Equal(t, "198.51.100.9", getClientIP(ctx, h))
+	assert.Equal(t, "1.2.3.4", getClientIP(context.Background(), h))
+}
+
+func TestValidateProxyProtocolConfig(t *testing.T) {
+	assert.NoError(t, validateProxyProtocolConfig(nil))
+	assert.NoError(t, validateProxyProtocolConfig(&conf.ProxyProtocolConfig{Enabled: true, TrustedCidrs: []string{"10.0.0.0/8"}}))
+	assert.Error(t, validateProxyProtocolConfig(&conf.ProxyProtocolConfig{Enabled: true}))
+	assert.Error(t, validateProxyProtocolConfig(&conf.ProxyProtocolConfig{Enabled: true, TrustedCidrs: []string{"10.0.0.1"}}))
+}
@@ -0,0 +1,165 @@
+package http
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/encoding/form"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// InvalidQueryParameterReason is the reason of errors for query parameters that do not fit the field
+	// they bind to.
+	InvalidQueryParameterReason = "INVALID_QUERY_PARAMETER"
+
+	defaultInvalidQueryParameterCode = 400
+)
+
+// QueryOption configures BindQuery.
+type QueryOption func(*queryOptions)
+
+type queryOptions struct {
+	commaLists bool
+	code       int
+}
+
+// WithCommaLists splits list parameters on commas, binding ?ids=1,2,3 like ?ids=1&ids=2&ids=3.
+func WithCommaLists() QueryOption {
+	return func(o *queryOptions) { o.commaLists = true }
+}
+
+// WithQueryErrorCode sets the code of INVALID_QUERY_PARAMETER errors, 400 by default.
+func WithQueryErrorCode(code int) QueryOption {
+	return func(o *queryOptions) { o.code = code }
+}
+
+func queryOptionsForConfig(cfg *conf.QueryConfig) []QueryOption {
+	var opts []QueryOption
+	if cfg.GetCommaLists() {
+		opts = append(opts, WithCommaLists())
+	}
+	if cfg.GetCode() != 0 {
+		opts = append(opts, WithQueryErrorCode(int(cfg.GetCode())))
+	}
+	return opts
+}
+
+// BindQuery binds query parameters into msg the way the server binds them for generated handlers. Keys are
+// field names or JSON names, with dots for nested fields; unknown keys are ignored. Lists bind from
+// repeated keys, enums by name or number, timestamps from RFC3339 values and durations like "1.5s". A value
+// that does not fit its field fails with INVALID_QUERY_PARAMETER, whose metadata names the parameter and
+// the expected type but not the value.
+func BindQuery(values url.Values, msg proto.Message, opts ...QueryOption) error {
+	o := queryOptions{code: defaultInvalidQueryParameterCode}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	md := msg.ProtoReflect().Descriptor()
+	// Sorted, so the first invalid parameter reported does not depend on map order.
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		vals := values[key]
+		fd := queryField(md, key)
+		if o.commaLists && fd != nil && fd.IsList() {
+			vals = splitCommaValues(vals)
+		}
+		if err := form.DecodeValues(msg, url.Values{key: vals}); err != nil {
+			expected := "a valid value"
+			if fd != nil {
+				expected = expectedQueryValue(fd, len(vals))
+			}
+			return errors.New(o.code, InvalidQueryParameterReason, fmt.Sprintf("query parameter %q expects %s", key, expected)).
+				WithMetadata(map[string]string{"parameter": key, "expected": expected}).
+				WithCause(err)
+		}
+	}
+	return nil
+}
+
+// queryField resolves a query key to the field it binds, or nil.
+func queryField(md protoreflect.MessageDescriptor, key string) protoreflect.FieldDescriptor {
+	var fd protoreflect.FieldDescriptor
+	for _, name := range strings.Split(strings.TrimSuffix(key, "[]"), ".") {
+		if md == nil {
+			return nil
+		}
+		if fd = md.Fields().ByName(protoreflect.Name(name)); fd == nil {
+			fd = md.Fields().ByJSONName(name)
+		}
+		if fd == nil {
+			return nil
+		}
+		md = fd.Message()
+	}
+	return fd
+}
+
+func splitCommaValues(vals []string) []string {
+	out := make([]string, 0, len(vals))
+	for _, v := range vals {
+		for part := range strings.SplitSeq(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
+
+// expectedQueryValue describes the values fd accepts, for error messages.
+func expectedQueryValue(fd protoreflect.FieldDescriptor, n int) string {
+	if !fd.IsList() && !fd.IsMap() && n > 1 {
+		return "a single value"
+	}
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		return fmt.Sprintf("a %s name or number", fd.Enum().Name())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		switch fd.Message().FullName() {
+		case "google.protobuf.Timestamp":
+			return "an RFC3339 timestamp"
+		case "google.protobuf.Duration":
+			return "a duration such as 1.5s"
+		}
+		return string(fd.Message().Name())
+	case protoreflect.BytesKind:
+		return "base64 bytes"
+	}
+	return fd.Kind().String()
+}
+
+// requestQueryDecoder binds query parameters with BindQuery and the configured conventions.
+func (h *ServiceHttp) requestQueryDecoder() http.DecodeRequestFunc {
+	opts := queryOptionsForConfig(h.conf.GetRequest().GetQuery())
+	return func(r *http.Request, v any) error {
+		msg, ok := v.(proto.Message)
+		if !ok {
+			return http.DefaultRequestQuery(r, v)
+		}
+		return BindQuery(r.URL.Query(), msg, opts...)
+	}
+}
+
+// invalidQueryParameterData returns the envelope data of an INVALID_QUERY_PARAMETER error, or nil for
+// other errors.
+func invalidQueryParameterData(se *errors.Error) map[string]string {
+	if se == nil || se.Reason != InvalidQueryParameterReason {
+		return nil
+	}
+	return map[string]string{"parameter": se.Metadata["parameter"], "expected": se.Metadata["expected"]}
+}
+
+// validateQueryConfig rejects reserved error codes.
+func validateQueryConfig(cfg *conf.QueryConfig) error {
+	if code := cfg.GetCode(); code < 0 || code == 200 || code == BodyCodeSystemFailure {
+		return fmt.Errorf("code %d is reserved", code)
+	}
+	return nil
+}
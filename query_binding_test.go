@@ -0,0 +1,179 @@
+package http
+
+import (
+	"encoding/json"
+	nhttp "net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/typepb"
+)
+
+// queryTestMessage describes:
+//
+//	message ListRequest {
+//	  repeated int64 ids = 1;
+//	  google.protobuf.Syntax syntax = 2;
+//	  google.protobuf.Timestamp since = 3;
+//	  int32 page_size = 4;
+//	  repeated string tags = 5;
+//	}
+func queryTestMessage(t *testing.T) protoreflect.MessageDescriptor {
+	t.Helper()
+	field := func(name string, num int32, label descriptorpb.FieldDescriptorProto_Label, typ descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(num),
+			Label:  label.Enum(),
+			Type:   typ.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+	opt := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	rep := descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+	fdp := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("querytest/query.proto"),
+		Package:    proto.String("querytest"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/type.proto", "google/protobuf/timestamp.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("ListRequest"), Field: []*descriptorpb.FieldDescriptorProto{
+				field("ids", 1, rep, descriptorpb.FieldDescriptorProto_TYPE_INT64, ""),
+				field("syntax", 2, opt, descriptorpb.FieldDescriptorProto_TYPE_ENUM, ".google.protobuf.Syntax"),
+				field("since", 3, opt, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Timestamp"),
+				field("page_size", 4, opt, descriptorpb.FieldDescriptorProto_TYPE_INT32, ""),
+				field("tags", 5, rep, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+			}},
+		},
+	}
+	fd, err := protodesc.NewFile(fdp, protoregistry.GlobalFiles)
+	require.NoError(t, err)
+	return fd.Messages().ByName("ListRequest")
+}
+
+func TestBindQuery(t *testing.T) {
+	md := queryTestMessage(t)
+	bind := func(query string, opts ...QueryOption) (*dynamicpb.Message, error) {
+		values, err := url.ParseQuery(query)
+		require.NoError(t, err)
+		msg := dynamicpb.NewMessage(md)
+		return msg, BindQuery(values, msg, opts...)
+	}
+	get := func(msg *dynamicpb.Message, name string) protoreflect.Value {
+		return msg.Get(md.Fields().ByName(protoreflect.Name(name)))
+	}
+	ids := func(msg *dynamicpb.Message) []int64 {
+		var out []int64
+		list := get(msg, "ids").List()
+		for i := range list.Len() {
+			out = append(out, list.Get(i).Int())
+		}
+		return out
+	}
+
+	msg, err := bind("ids=1&ids=2&syntax=SYNTAX_PROTO3&since=2026-03-01T10:00:00Z&pageSize=20")
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, ids(msg))
+	assert.Equal(t, protoreflect.EnumNumber(typepb.Syntax_SYNTAX_PROTO3), get(msg, "syntax").Enum())
+	since := get(msg, "since").Message().Interface()
+	assert.True(t, time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC).Equal(timestampTime(since)))
+	assert.Equal(t, int64(20), get(msg, "page_size").Int())
+
+	msg, err = bind("syntax=2")
+	require.NoError(t, err, "enums bind by number too")
+	assert.Equal(t, protoreflect.EnumNumber(typepb.Syntax_SYNTAX_EDITIONS), get(msg, "syntax").Enum())
+
+	msg, err = bind("ids=1,2,3&ids=4&tags=a,%20b", WithCommaLists())
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3, 4}, ids(msg))
+	assert.Equal(t, 2, get(msg, "tags").List().Len())
+	_, err = bind("ids=1,2")
+	assert.Error(t, err, "commas are literal without WithCommaLists")
+
+	tests := []struct {
+		query, parameter, expected string
+	}{
+		{"page_size=ten", "page_size", "int32"},
+		{"syntax=PROTO4", "syntax", "a Syntax name or number"},
+		{"since=yesterday", "since", "an RFC3339 timestamp"},
+		{"page_size=1&page_size=2", "page_size", "a single value"},
+	}
+	for _, tt := range tests {
+		_, err := bind(tt.query, WithQueryErrorCode(42200))
+		se := errors.FromError(err)
+		require.NotNil(t, se, tt.query)
+		assert.Equal(t, InvalidQueryParameterReason, se.Reason)
+		assert.Equal(t, int32(42200), se.Code)
+		assert.Equal(t, tt.parameter, se.Metadata["parameter"], tt.query)
+		assert.Equal(t, tt.expected, se.Metadata["expected"], tt.query)
+		assert.NotContains(t, se.Message, "ten")
+	}
+
+	_, err = bind("unknown=1")
+	assert.NoError(t, err, "unknown parameters are ignored")
+}
+
+func timestampTime(m proto.Message) time.Time {
+	ts := &timestamppb.Timestamp{}
+	b, _ := proto.Marshal(m)
+	_ = proto.Unmarshal(b, ts)
+	return ts.AsTime()
+}
+
+func TestRequestQueryDecoder_Envelope(t *testing.T) {
+	md := queryTestMessage(t)
+	h := &ServiceHttp{conf: &conf.Http{Request: &conf.RequestConfig{Query: &conf.QueryConfig{CommaLists: true}}}}
+	srv := http.NewServer(
+		http.RequestQueryDecoder(h.requestQueryDecoder()),
+		http.ErrorEncoder(h.enhancedErrorEncoder),
+		http.ResponseEncoder(ResponseEncoder),
+	)
+	var bound *dynamicpb.Message
+	srv.Route("/").GET("/items", func(ctx http.Context) error {
+		msg := dynamicpb.NewMessage(md)
+		if err := ctx.BindQuery(msg); err != nil {
+			return err
+		}
+		bound = msg
+		return ctx.Result(nhttp.StatusOK, nil)
+	})
+	get := func(query string) map[string]any {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(nhttp.MethodGet, "/items?"+query, nil))
+		require.Equal(t, nhttp.StatusOK, rec.Code)
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		return body
+	}
+
+	assert.Equal(t, float64(200), get("ids=1,2")["code"])
+	assert.Equal(t, 2, bound.Get(md.Fields().ByName("ids")).List().Len())
+
+	assert.Equal(t, map[string]any{
+		"code": float64(400),
+		"data": map[string]any{"parameter": "pageSize", "expected": "int32"},
+	}, get("pageSize=x"))
+}
+
+func TestValidateQueryConfig(t *testing.T) {
+	assert.NoError(t, validateQueryConfig(nil))
+	assert.NoError(t, validateQueryConfig(&conf.QueryConfig{Code: 42200}))
+	assert.Error(t, validateQueryConfig(&conf.QueryConfig{Code: BodyCodeSystemFailure}))
+}
@@ -0,0 +1,65 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-lynx/lynx-http/conf"
+)
+
+// rateLimitCost is a resolved RateLimitCost.
+type rateLimitCost struct {
+	operation string
+	tokens    int
+}
+
+// rateLimitCostPolicy resolves the tokens a request consumes from the rate limiter.
+type rateLimitCostPolicy struct {
+	rules []rateLimitCost
+}
+
+// newRateLimitCostPolicy returns nil without cost rules, so every request consumes one token.
+func newRateLimitCostPolicy(cfg *conf.RateLimitConfig) *rateLimitCostPolicy {
+	p := &rateLimitCostPolicy{}
+	for _, c := range cfg.GetCosts() {
+		if op := strings.TrimSpace(c.GetOperation()); op != "" && c.GetTokens() > 0 {
+			p.rules = append(p.rules, rateLimitCost{operation: op, tokens: int(c.GetTokens())})
+		}
+	}
+	if len(p.rules) == 0 {
+		return nil
+	}
+	return p
+}
+
+// tokens returns the tokens of the first rule matching the request's operation or path, or 1.
+func (p *rateLimitCostPolicy) tokens(ctx context.Context) int {
+	if p == nil {
+		return 1
+	}
+	operation, path := requestRoute(ctx)
+	for _, rule := range p.rules {
+		if routeMatchesAny([]string{rule.operation}, operation, path) {
+			return rule.tokens
+		}
+	}
+	return 1
+}
+
+// validateRateLimitCosts checks the cost rules against the burst of the limiter; a route costing more tokens than
+// the burst would be rejected forever.
+func validateRateLimitCosts(costs []*conf.RateLimitCost, burst int) error {
+	for i, c := range costs {
+		if strings.TrimSpace(c.GetOperation()) == "" {
+			return fmt.Errorf("costs[%d]: operation is required", i)
+		}
+		if c.GetTokens() < 1 {
+			return fmt.Errorf("costs[%d]: tokens must be at least 1, got %d", i, c.GetTokens())
+		}
+		if int(c.GetTokens()) > burst {
+			return fmt.Errorf("costs[%d]: tokens %d exceed the burst limit %d", i, c.GetTokens(), burst)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package http
+
+import (
+	"context"
+	nhttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx-http/httptesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitCosts(t *testing.T) {
+	h := &ServiceHttp{conf: &conf.Http{Security: &conf.SecurityConfig{RateLimit: &conf.RateLimitConfig{
+		Enabled: true, RatePerSecond: 1, BurstLimit: 10,
+		Costs: []*conf.RateLimitCost{
+			{Operation: "/api.v1.Search/*", Tokens: 5},
+			{Operation: "/v1/reports/*", Tokens: 3},
+			{Operation: "/api.v1.Search/Suggest", Tokens: 1},
+			{Operation: " ", Tokens: 2},
+		},
+	}}}}
+	h.initSecurityDefaults()
+	require.NotNil(t, h.rateLimitCosts)
+	require.Len(t, h.rateLimitCosts.rules, 3)
+
+	tester := httptesting.NewMiddlewareTester(t, h.rateLimitMiddleware())
+	search := httptesting.Request{Operation: "/api.v1.Search/Query"}
+	get := httptesting.Request{Operation: "/api.v1.Users/Get"}
+
+	// A search consumes 5 of the 10 tokens and a get one: one search and five gets drain the bucket.
+	tester.Run(search).AssertHandlerCalled(true)
+	for range 5 {
+		tester.Run(get).AssertHandlerCalled(true)
+	}
+	tester.Run(search).AssertHandlerCalled(false)
+	assert.InDelta(t, 0, h.rateLimiter.Tokens(), 0.5)
+
+	// Rules match the operation or the request path, first match wins.
+	ctx := func(operation, path string) context.Context {
+		return transport.NewServerContext(context.Background(),
+			httptesting.NewTransport(operation, httptest.NewRequest(nhttp.MethodGet, path, nil)))
+	}
+	assert.Equal(t, 3, h.rateLimitCosts.tokens(ctx("/api.v1.Reports/Get", "/v1/reports/42")))
+	assert.Equal(t, 5, h.rateLimitCosts.tokens(ctx("/api.v1.Search/Suggest", "/v1/search/suggest")))
+	assert.Equal(t, 1, h.rateLimitCosts.tokens(ctx("/api.v1.Users/Get", "/v1/users/7")))
+
+	// Without rules every request consumes one token.
+	h.conf.Security.RateLimit.Costs = nil
+	h.initSecurityDefaults()
+	assert.Nil(t, h.rateLimitCosts)
+	assert.Equal(t, 1, h.rateLimitCosts.tokens(ctx("/api.v1.Search/Query", "/v1/search")))
+}
+
+func TestValidateRateLimitCosts(t *testing.T) {
+	require.NoError(t, validateRateLimitCosts(nil, 200))
+	require.NoError(t, validateRateLimitCosts([]*conf.RateLimitCost{{Operation: "/api.v1.Search/*", Tokens: 200}}, 200))
+	assert.Error(t, validateRateLimitCosts([]*conf.RateLimitCost{{Operation: "/api.v1.Search/*", Tokens: 201}}, 200))
+	assert.Error(t, validateRateLimitCosts([]*conf.RateLimitCost{{Operation: "/api.v1.Search/*"}}, 200))
+	assert.Error(t, validateRateLimitCosts([]*conf.RateLimitCost{{Tokens: 5}}, 200))
+
+	h := &ServiceHttp{conf: &conf.Http{Security: &conf.SecurityConfig{RateLimit: &conf.RateLimitConfig{
+		Enabled: true, BurstLimit: 4, Costs: []*conf.RateLimitCost{{Operation: "/api.v1.Search/*", Tokens: 5}},
+	}}}}
+	h.initSecurityDefaults()
+	assert.ErrorContains(t, h.validateConfigLocked(), "invalid rate limit configuration")
+}
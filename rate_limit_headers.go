@@ -0,0 +1,98 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/conf"
+	"golang.org/x/time/rate"
+)
+
+const (
+	rateLimitHeaderStyleX     = "x"
+	rateLimitHeaderStyleDraft = "draft"
+	rateLimitHeaderStyleBoth  = "both"
+
+	retryAfterHeader = "Retry-After"
+)
+
+// rateLimitHeadersPolicy is the resolved RateLimitHeadersConfig.
+type rateLimitHeadersPolicy struct {
+	// prefixes are the header name prefixes written, "X-RateLimit-" and/or "RateLimit-".
+	prefixes []string
+	exempt   []string
+}
+
+// newRateLimitHeadersPolicy returns nil when quota headers are disabled.
+func newRateLimitHeadersPolicy(cfg *conf.RateLimitHeadersConfig) *rateLimitHeadersPolicy {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	p := &rateLimitHeadersPolicy{}
+	switch strings.ToLower(strings.TrimSpace(cfg.GetStyle())) {
+	case rateLimitHeaderStyleX:
+		p.prefixes = []string{"X-RateLimit-"}
+	case rateLimitHeaderStyleDraft:
+		p.prefixes = []string{"RateLimit-"}
+	default:
+		p.prefixes = []string{"X-RateLimit-", "RateLimit-"}
+	}
+	for _, op := range cfg.GetExemptOperations() {
+		if op = strings.TrimSpace(op); op != "" {
+			p.exempt = append(p.exempt, op)
+		}
+	}
+	return p
+}
+
+// set writes the quota of limiter at now to the reply headers. tokens is the price of the request; when it was
+// rejected, Retry-After tells the client when that many tokens are available.
+func (p *rateLimitHeadersPolicy) set(ctx context.Context, limiter *rate.Limiter, now time.Time, tokens int, allowed bool) {
+	if p == nil || limiter == nil {
+		return
+	}
+	tr, ok := transport.FromServerContext(ctx)
+	if !ok {
+		return
+	}
+	if operation, path := requestRoute(ctx); routeMatchesAny(p.exempt, operation, path) {
+		return
+	}
+	left := limiter.TokensAt(now)
+	burst := limiter.Burst()
+	header := tr.ReplyHeader()
+	for _, prefix := range p.prefixes {
+		header.Set(prefix+"Limit", strconv.Itoa(burst))
+		header.Set(prefix+"Remaining", strconv.Itoa(max(int(math.Floor(left)), 0)))
+		header.Set(prefix+"Reset", strconv.Itoa(rateLimitWait(limiter, float64(burst)-left)))
+	}
+	if !allowed {
+		header.Set(retryAfterHeader, strconv.Itoa(max(rateLimitWait(limiter, float64(tokens)-left), 1)))
+	}
+}
+
+// rateLimitWait returns the whole seconds limiter needs to refill missing tokens.
+func rateLimitWait(limiter *rate.Limiter, missing float64) int {
+	if missing <= 0 || limiter.Limit() <= 0 {
+		return 0
+	}
+	return int(math.Ceil(missing / float64(limiter.Limit())))
+}
+
+func validateRateLimitHeadersConfig(cfg *conf.RateLimitHeadersConfig) error {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	switch strings.ToLower(strings.TrimSpace(cfg.GetStyle())) {
+	case "", rateLimitHeaderStyleX, rateLimitHeaderStyleDraft, rateLimitHeaderStyleBoth:
+		return nil
+	default:
+		return fmt.Errorf("headers.style must be %q, %q or %q, got %q",
+			rateLimitHeaderStyleX, rateLimitHeaderStyleDraft, rateLimitHeaderStyleBoth, cfg.GetStyle())
+	}
+}
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx-http/httptesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitHeaders(t *testing.T) {
+	h := &ServiceHttp{conf: &conf.Http{Security: &conf.SecurityConfig{RateLimit: &conf.RateLimitConfig{
+		Enabled: true, RatePerSecond: 2, BurstLimit: 10,
+		Costs:   []*conf.RateLimitCost{{Operation: "/api.v1.Search/*", Tokens: 6}},
+		Headers: &conf.RateLimitHeadersConfig{Enabled: true, ExemptOperations: []string{"/partner/*"}},
+	}}}}
+	h.initSecurityDefaults()
+	require.NotNil(t, h.rateLimitHeaders)
+	tester := httptesting.NewMiddlewareTester(t, h.rateLimitMiddleware())
+
+	res := tester.Run(httptesting.Request{Operation: "/api.v1.Search/Query"}).AssertHandlerCalled(true)
+	res.AssertReplyHeader("X-RateLimit-Limit", "10").
+		AssertReplyHeader("X-RateLimit-Remaining", "4").
+		AssertReplyHeader("X-RateLimit-Reset", "3").
+		AssertReplyHeader("RateLimit-Remaining", "4").
+		AssertReplyHeader("Retry-After", "")
+
+	// A second search needs 6 tokens while 4 are left: one more second at 2 tokens/s.
+	tester.Run(httptesting.Request{Operation: "/api.v1.Search/Query"}).AssertHandlerCalled(false).
+		AssertReplyHeader("X-RateLimit-Remaining", "4").
+		AssertReplyHeader("Retry-After", "1")
+
+	// Exempt routes are limited without exposing the quota.
+	tester.Run(httptesting.Request{Operation: "/api.v1.Partner/Get", Path: "/partner/orders"}).AssertHandlerCalled(true).
+		AssertReplyHeader("X-RateLimit-Limit", "").
+		AssertReplyHeader("RateLimit-Limit", "")
+
+	h.conf.Security.RateLimit.Headers.Style = "draft"
+	h.initSecurityDefaults()
+	tester = httptesting.NewMiddlewareTester(t, h.rateLimitMiddleware())
+	tester.Run(httptesting.Request{Operation: "/api.v1.Users/Get"}).
+		AssertReplyHeader("RateLimit-Limit", "10").
+		AssertReplyHeader("RateLimit-Remaining", "9").
+		AssertReplyHeader("X-RateLimit-Limit", "")
+
+	// Disabled headers leave replies untouched.
+	h.conf.Security.RateLimit.Headers = nil
+	h.initSecurityDefaults()
+	assert.Nil(t, h.rateLimitHeaders)
+	httptesting.NewMiddlewareTester(t, h.rateLimitMiddleware()).Run(httptesting.Request{Operation: "/api.v1.Users/Get"}).
+		AssertReplyHeader("X-RateLimit-Limit", "")
+}
+
+func TestValidateRateLimitHeadersConfig(t *testing.T) {
+	require.NoError(t, validateRateLimitHeadersConfig(nil))
+	require.NoError(t, validateRateLimitHeadersConfig(&conf.RateLimitHeadersConfig{Enabled: true, Style: "X"}))
+	assert.Error(t, validateRateLimitHeadersConfig(&conf.RateLimitHeadersConfig{Enabled: true, Style: "github"}))
+}
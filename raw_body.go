@@ -0,0 +1,117 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	nhttp "net/http"
+	"sync"
+
+	"github.com/go-lynx/lynx-http/conf"
+)
+
+// defaultRawBodyMaxBytes caps the captured body when raw_body_max_bytes is unset.
+const defaultRawBodyMaxBytes = 64 << 10
+
+// rawBodyKey stores the *rawBodyCapture of a request in its context.
+type rawBodyKey struct{}
+
+// RawBody is the captured request body.
+type RawBody struct {
+	// Bytes holds the body as sent by the client, up to request.raw_body_max_bytes.
+	Bytes []byte
+	// Complete reports that the body was read to the end within the size cap, so Bytes is exactly what the
+	// client sent. Signature checks must reject incomplete bodies.
+	Complete bool
+}
+
+// RawRequestBody returns the raw bytes of the current request body read so far, when
+// request.capture_raw_body is enabled. Kratos handlers decode the body before running the middleware
+// chain, so middleware such as HMAC verification sees the whole body.
+func RawRequestBody(ctx context.Context) (RawBody, bool) {
+	c, ok := ctx.Value(rawBodyKey{}).(*rawBodyCapture)
+	if !ok {
+		return RawBody{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return RawBody{Bytes: bytes.Clone(c.buf.Bytes()), Complete: c.eof && !c.truncated}, true
+}
+
+// rawBodyCapture tees the request body into a capped buffer as it is read.
+type rawBodyCapture struct {
+	body io.ReadCloser
+	max  int
+
+	mu        sync.Mutex
+	buf       bytes.Buffer
+	truncated bool
+	eof       bool
+}
+
+func (c *rawBodyCapture) Read(p []byte) (int, error) {
+	n, err := c.body.Read(p)
+	c.mu.Lock()
+	room := c.max - c.buf.Len()
+	if n > room {
+		c.truncated = true
+	}
+	if keep := min(n, room); keep > 0 {
+		c.buf.Write(p[:keep])
+	}
+	if errors.Is(err, io.EOF) {
+		c.eof = true
+	}
+	c.mu.Unlock()
+	return n, err
+}
+
+func (c *rawBodyCapture) Close() error {
+	return c.body.Close()
+}
+
+// rawBodyFilter wraps request bodies in a rawBodyCapture limited to maxBytes.
+func rawBodyFilter(maxBytes int) func(nhttp.Handler) nhttp.Handler {
+	return func(next nhttp.Handler) nhttp.Handler {
+		return nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+			if r.Body == nil || r.Body == nhttp.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+			c := &rawBodyCapture{body: r.Body, max: maxBytes}
+			r = r.WithContext(context.WithValue(r.Context(), rawBodyKey{}, c))
+			r.Body = c
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rawBodyMaxBytes returns the configured capture cap.
+func rawBodyMaxBytes(cfg *conf.RequestConfig) int {
+	if n := cfg.GetRawBodyMaxBytes(); n > 0 {
+		return int(n)
+	}
+	return defaultRawBodyMaxBytes
+}
+
+// validateRequestConfig rejects a negative capture cap.
+func validateRequestConfig(cfg *conf.RequestConfig) error {
+	if cfg.GetRawBodyMaxBytes() < 0 {
+		return fmt.Errorf("raw body max bytes cannot be negative")
+	}
+	if err := validateContentTypeConfig(cfg.GetContentTypes()); err != nil {
+		return fmt.Errorf("content_types: %w", err)
+	}
+	if err := validateDecodeErrorConfig(cfg.GetDecodeErrors()); err != nil {
+		return fmt.Errorf("decode_errors: %w", err)
+	}
+	if err := validateQueryConfig(cfg.GetQuery()); err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+	if err := validateRequestDefaults(cfg.GetDefaults()); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"context"
+	nhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRawRequestBody_AfterDecoding(t *testing.T) {
+	var seen RawBody
+	capture := func(next middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			seen, _ = RawRequestBody(ctx)
+			return next(ctx, req)
+		}
+	}
+	srv := http.NewServer(http.Middleware(capture), http.Filter(rawBodyFilter(1024)))
+	srv.Route("/").POST("/sign", func(ctx http.Context) error {
+		var in map[string]any
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		_, err := ctx.Middleware(func(context.Context, any) (any, error) { return nil, nil })(ctx, &in)
+		return err
+	})
+
+	body := `{"amount": 10,  "to":"x"}`
+	r := httptest.NewRequest(nhttp.MethodPost, "/sign", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	srv.ServeHTTP(httptest.NewRecorder(), r)
+
+	assert.Equal(t, body, string(seen.Bytes), "whitespace is preserved byte for byte")
+	assert.True(t, seen.Complete)
+}
+
+func TestRawRequestBody_Cap(t *testing.T) {
+	var seen RawBody
+	var ok bool
+	handler := rawBodyFilter(4)(nhttp.HandlerFunc(func(_ nhttp.ResponseWriter, r *nhttp.Request) {
+		buf := make([]byte, 3)
+		_, _ = r.Body.Read(buf)
+		seen, ok = RawRequestBody(r.Context())
+		assert.False(t, seen.Complete, "body not read to the end yet")
+		_, _ = r.Body.Read(make([]byte, 16))
+		_, _ = r.Body.Read(make([]byte, 16))
+		seen, ok = RawRequestBody(r.Context())
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(nhttp.MethodPost, "/", strings.NewReader("abcdefgh")))
+	require.True(t, ok)
+	assert.Equal(t, "abcd", string(seen.Bytes))
+	assert.False(t, seen.Complete)
+
+	handler = rawBodyFilter(4)(nhttp.HandlerFunc(func(_ nhttp.ResponseWriter, r *nhttp.Request) {
+		_, _ = r.Body.Read(make([]byte, 16))
+		_, _ = r.Body.Read(make([]byte, 16))
+		seen, ok = RawRequestBody(r.Context())
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(nhttp.MethodPost, "/", strings.NewReader("abcd")))
+	assert.Equal(t, "abcd", string(seen.Bytes))
+	assert.True(t, seen.Complete, "a body exactly at the cap is complete")
+}
+
+func TestRawRequestBody_Unavailable(t *testing.T) {
+	_, ok := RawRequestBody(context.Background())
+	assert.False(t, ok)
+	assert.Equal(t, defaultRawBodyMaxBytes, rawBodyMaxBytes(nil))
+	assert.Error(t, validateRequestConfig(&conf.RequestConfig{RawBodyMaxBytes: -1}))
+}
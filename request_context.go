@@ -0,0 +1,147 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader is the request header read by RequestIDFromContext.
+const RequestIDHeader = "X-Request-Id"
+
+// contextKeyNames holds the names of all keys created with NewContextKey.
+var contextKeyNames sync.Map
+
+// ContextKey is a typed request context key. Keys are compared by identity, so two keys never collide even
+// if their types match; names are unique and only used for diagnostics.
+type ContextKey[T any] struct {
+	name string
+}
+
+// NewContextKey creates a context key for values of type T. Create keys once, in package variables; it
+// panics if name is already taken, which catches two middleware claiming the same request value.
+func NewContextKey[T any](name string) *ContextKey[T] {
+	return newContextKeyIn[T](&contextKeyNames, name)
+}
+
+// newContextKeyIn creates a context key whose name is registered in names.
+func newContextKeyIn[T any](names *sync.Map, name string) *ContextKey[T] {
+	if _, taken := names.LoadOrStore(name, struct{}{}); taken {
+		panic(fmt.Sprintf("context key %q already registered", name))
+	}
+	return &ContextKey[T]{name: name}
+}
+
+// Name returns the name the key was created with.
+func (k *ContextKey[T]) Name() string { return k.name }
+
+// WithValue returns a copy of ctx carrying v under k.
+func (k *ContextKey[T]) WithValue(ctx context.Context, v T) context.Context {
+	return context.WithValue(ctx, k, v)
+}
+
+// Value returns the value stored under k.
+func (k *ContextKey[T]) Value(ctx context.Context) (T, bool) {
+	v, ok := ctx.Value(k).(T)
+	return v, ok
+}
+
+// AuthClaims are the claims of an authenticated caller, as set by authentication middleware.
+type AuthClaims map[string]any
+
+// Subject returns the "sub" claim.
+func (c AuthClaims) Subject() string {
+	s, _ := c["sub"].(string)
+	return s
+}
+
+// Keys of the request metadata shared by the plugin's and applications' middleware.
+var (
+	// ClientIPKey overrides the client IP derived from the connection and forwarding headers.
+	ClientIPKey = NewContextKey[string]("lynx.http.client_ip")
+	// RequestIDKey overrides the request ID read from X-Request-Id.
+	RequestIDKey = NewContextKey[string]("lynx.http.request_id")
+	// AuthClaimsKey carries the claims of the authenticated caller.
+	AuthClaimsKey = NewContextKey[AuthClaims]("lynx.http.auth_claims")
+	// TenantKey carries the tenant the request acts for.
+	TenantKey = NewContextKey[string]("lynx.http.tenant")
+	// RouteTemplateKey overrides the route template of the matched route.
+	RouteTemplateKey = NewContextKey[string]("lynx.http.route_template")
+	// FingerprintKey carries the fingerprint of the request's client, set by security.fingerprint.
+	FingerprintKey = NewContextKey[string]("lynx.http.fingerprint")
+	// HoneypotFlaggedKey marks requests of clients flagged by security.honeypot in monitor mode.
+	HoneypotFlaggedKey = NewContextKey[bool]("lynx.http.honeypot_flagged")
+)
+
+// ClientIPFromContext returns the client IP of the current request: the value set under ClientIPKey, the
+// address from a trusted PROXY protocol header, or the first address of the forwarding headers.
+func ClientIPFromContext(ctx context.Context) (string, bool) {
+	if ip, ok := ClientIPKey.Value(ctx); ok {
+		return ip, true
+	}
+	tr, ok := transport.FromServerContext(ctx)
+	if !ok {
+		return "", false
+	}
+	if ip := getClientIP(ctx, tr.RequestHeader()); ip != "unknown" {
+		first, _, _ := strings.Cut(ip, ",")
+		return strings.TrimSpace(first), true
+	}
+	return "", false
+}
+
+// RequestIDFromContext returns the ID of the current request: the value set under RequestIDKey, the
+// X-Request-Id header, or the trace ID.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	if id, ok := RequestIDKey.Value(ctx); ok {
+		return id, true
+	}
+	if tr, ok := transport.FromServerContext(ctx); ok {
+		if id := strings.TrimSpace(tr.RequestHeader().Get(RequestIDHeader)); id != "" {
+			return id, true
+		}
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		return sc.TraceID().String(), true
+	}
+	return "", false
+}
+
+// WithAuthClaims returns a copy of ctx carrying the caller's claims; authentication middleware calls it.
+func WithAuthClaims(ctx context.Context, claims AuthClaims) context.Context {
+	return AuthClaimsKey.WithValue(ctx, claims)
+}
+
+// AuthClaimsFromContext returns the claims set with WithAuthClaims.
+func AuthClaimsFromContext(ctx context.Context) (AuthClaims, bool) {
+	return AuthClaimsKey.Value(ctx)
+}
+
+// WithTenant returns a copy of ctx carrying the tenant of the request.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return TenantKey.WithValue(ctx, tenant)
+}
+
+// TenantFromContext returns the tenant set with WithTenant.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	return TenantKey.Value(ctx)
+}
+
+// RouteTemplateFromContext returns the template of the matched route, e.g. "/v1/users/{id}": the value set
+// under RouteTemplateKey or the template of the HTTP transport.
+func RouteTemplateFromContext(ctx context.Context) (string, bool) {
+	if tmpl, ok := RouteTemplateKey.Value(ctx); ok {
+		return tmpl, true
+	}
+	if tr, ok := transport.FromServerContext(ctx); ok {
+		if ht, ok := tr.(http.Transporter); ok && ht.PathTemplate() != "" {
+			return ht.PathTemplate(), true
+		}
+	}
+	return "", false
+}
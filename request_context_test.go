@@ -0,0 +1,77 @@
+package http
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/httptesting"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextKey(t *testing.T) {
+	// A fresh registry per run, so the test can be repeated without the names already being taken.
+	var names sync.Map
+	key := newContextKeyIn[int](&names, "test.request_context.count")
+	assert.Equal(t, "test.request_context.count", key.Name())
+
+	_, ok := key.Value(context.Background())
+	assert.False(t, ok)
+	v, ok := key.Value(key.WithValue(context.Background(), 3))
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+
+	other := newContextKeyIn[int](&names, "test.request_context.other")
+	_, ok = other.Value(key.WithValue(context.Background(), 3))
+	assert.False(t, ok, "keys of the same type do not collide")
+
+	assert.Panics(t, func() { newContextKeyIn[string](&names, "test.request_context.count") })
+	assert.Panics(t, func() { NewContextKey[string](RequestIDKey.Name()) }, "NewContextKey uses the package registry")
+}
+
+func TestRequestContextAccessors(t *testing.T) {
+	ctx := transport.NewServerContext(context.Background(), newFakeTransport("/api.v1.Users/Get", map[string]string{
+		"X-Forwarded-For": "203.0.113.7, 10.0.0.1",
+		RequestIDHeader:   "req-42",
+	}))
+
+	ip, ok := ClientIPFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "203.0.113.7", ip)
+	ip, _ = ClientIPFromContext(ClientIPKey.WithValue(ctx, "198.51.100.1"))
+	assert.Equal(t, "198.51.100.1", ip)
+
+	id, ok := RequestIDFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "req-42", id)
+	_, ok = RequestIDFromContext(context.Background())
+	assert.False(t, ok)
+
+	_, ok = AuthClaimsFromContext(ctx)
+	assert.False(t, ok)
+	claims, ok := AuthClaimsFromContext(WithAuthClaims(ctx, AuthClaims{"sub": "user-1"}))
+	assert.True(t, ok)
+	assert.Equal(t, "user-1", claims.Subject())
+
+	tenant, ok := TenantFromContext(WithTenant(ctx, "acme"))
+	assert.True(t, ok)
+	assert.Equal(t, "acme", tenant)
+
+	_, ok = RouteTemplateFromContext(ctx)
+	assert.False(t, ok, "non-HTTP transports have no template")
+	tmpl, _ := RouteTemplateFromContext(RouteTemplateKey.WithValue(ctx, "/v1/users/{id}"))
+	assert.Equal(t, "/v1/users/{id}", tmpl)
+}
+
+func TestRouteTemplateFromContext_HTTPTransport(t *testing.T) {
+	res := httptesting.NewMiddlewareTester(t).
+		WithHandler(func(ctx context.Context, _ any) (any, error) {
+			tmpl, _ := RouteTemplateFromContext(ctx)
+			ip, _ := ClientIPFromContext(ctx)
+			return tmpl + " " + ip, nil
+		}).
+		Run(httptesting.Request{PathTemplate: "/v1/users/{id}", Header: map[string]string{"X-Real-IP": "198.51.100.9"}}).
+		AssertNoError()
+	assert.Equal(t, "/v1/users/{id} 198.51.100.9", res.Reply)
+}
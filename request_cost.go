@@ -0,0 +1,147 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+const (
+	// RequestCostHeader carries the weighted total cost of a request.
+	RequestCostHeader = "X-Request-Cost"
+	// RequestCostUnitsHeader carries the recorded units, e.g. "bytes=2048, db_queries=3".
+	RequestCostUnitsHeader = "X-Request-Cost-Units"
+)
+
+// requestCostKey stores the *requestCosts of a request in its context.
+type requestCostKey struct{}
+
+// requestCosts accumulates the cost units of one request.
+type requestCosts struct {
+	mu    sync.Mutex
+	units map[string]float64
+}
+
+// AddRequestCost adds amount of unit (for example "db_queries" or "bytes_scanned") to the cost of the current
+// request. It does nothing unless monitoring.request_cost is enabled.
+func AddRequestCost(ctx context.Context, unit string, amount float64) {
+	c, ok := ctx.Value(requestCostKey{}).(*requestCosts)
+	if !ok || unit == "" || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.units[unit] += amount
+}
+
+// RequestCosts returns the units recorded so far for the current request.
+func RequestCosts(ctx context.Context) map[string]float64 {
+	c, ok := ctx.Value(requestCostKey{}).(*requestCosts)
+	if !ok {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return maps.Clone(c.units)
+}
+
+// requestCostPolicy is the resolved RequestCostConfig.
+type requestCostPolicy struct {
+	logCosts bool
+	headers  bool
+	weights  map[string]float64
+}
+
+// newRequestCostPolicy returns nil when cost accounting is disabled.
+func newRequestCostPolicy(cfg *conf.RequestCostConfig) *requestCostPolicy {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	return &requestCostPolicy{logCosts: cfg.LogCosts, headers: cfg.ResponseHeaders, weights: cfg.Weights}
+}
+
+// total is the weighted sum of units.
+func (p *requestCostPolicy) total(units map[string]float64) float64 {
+	var sum float64
+	for unit, amount := range units {
+		weight, ok := p.weights[unit]
+		if !ok {
+			weight = 1
+		}
+		sum += amount * weight
+	}
+	return sum
+}
+
+// formatRequestCostUnits renders units sorted by name.
+func formatRequestCostUnits(units map[string]float64) string {
+	parts := make([]string, 0, len(units))
+	for _, unit := range slices.Sorted(maps.Keys(units)) {
+		parts = append(parts, unit+"="+strconv.FormatFloat(units[unit], 'f', -1, 64))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// requestCostMiddleware collects the cost units recorded by the handler, counts them per route and unit, and
+// optionally logs them and returns them in response headers.
+func (h *ServiceHttp) requestCostMiddleware(p *requestCostPolicy) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			costs := &requestCosts{units: make(map[string]float64)}
+			reply, err := handler(context.WithValue(ctx, requestCostKey{}, costs), req)
+
+			costs.mu.Lock()
+			units := maps.Clone(costs.units)
+			costs.mu.Unlock()
+			if len(units) == 0 {
+				return reply, err
+			}
+			_, route := requestMetadata(ctx)
+			total := p.total(units)
+			if h.requestCostUnits != nil {
+				for unit, amount := range units {
+					h.requestCostUnits.WithLabelValues(route, unit).Add(amount)
+				}
+			}
+			if h.requestCostTotal != nil {
+				h.requestCostTotal.WithLabelValues(route).Add(total)
+			}
+			if p.logCosts {
+				log.InfowCtx(ctx, "msg", "[HTTP Cost]", "api", route, "cost", total, "units", units)
+			}
+			if p.headers {
+				if tr, ok := transport.FromServerContext(ctx); ok {
+					tr.ReplyHeader().Set(RequestCostHeader, strconv.FormatFloat(total, 'f', -1, 64))
+					tr.ReplyHeader().Set(RequestCostUnitsHeader, formatRequestCostUnits(units))
+				}
+			}
+			return reply, err
+		}
+	}
+}
+
+// validateRequestCostConfig rejects negative or non-finite weights.
+func validateRequestCostConfig(cfg *conf.RequestCostConfig) error {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	for unit, weight := range cfg.Weights {
+		if unit == "" {
+			return fmt.Errorf("weights contains an empty unit")
+		}
+		if weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+			return fmt.Errorf("weight of %q must be a non-negative number", unit)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestCostMiddleware(t *testing.T) {
+	h := &ServiceHttp{
+		requestCostUnits: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "cost_units"}, []string{"route", "unit"}),
+		requestCostTotal: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "cost_total"}, []string{"route"}),
+	}
+	policy := newRequestCostPolicy(&conf.RequestCostConfig{
+		Enabled:         true,
+		ResponseHeaders: true,
+		Weights:         map[string]float64{"bytes_scanned": 0.001},
+	})
+	require.NotNil(t, policy)
+
+	tr := newFakeTransport("/api.v1.Search/Query", nil)
+	_, err := h.requestCostMiddleware(policy)(func(ctx context.Context, _ any) (any, error) {
+		AddRequestCost(ctx, "db_queries", 2)
+		AddRequestCost(ctx, "db_queries", 1)
+		AddRequestCost(ctx, "bytes_scanned", 2048)
+		AddRequestCost(ctx, "", 5)
+		assert.Equal(t, map[string]float64{"db_queries": 3, "bytes_scanned": 2048}, RequestCosts(ctx))
+		return "ok", nil
+	})(transport.NewServerContext(context.Background(), tr), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "5.048", tr.ReplyHeader().Get(RequestCostHeader))
+	assert.Equal(t, "bytes_scanned=2048, db_queries=3", tr.ReplyHeader().Get(RequestCostUnitsHeader))
+	assert.Equal(t, float64(3), testutil.ToFloat64(h.requestCostUnits.WithLabelValues("/api.v1.Search/Query", "db_queries")))
+	assert.InDelta(t, 5.048, testutil.ToFloat64(h.requestCostTotal.WithLabelValues("/api.v1.Search/Query")), 1e-9)
+
+	// Requests that record nothing leave no headers.
+	tr = newFakeTransport("/api.v1.Search/Query", nil)
+	_, err = h.requestCostMiddleware(policy)(func(context.Context, any) (any, error) { return nil, nil })(transport.NewServerContext(context.Background(), tr), nil)
+	require.NoError(t, err)
+	assert.Empty(t, tr.ReplyHeader().Get(RequestCostHeader))
+}
+
+func TestRequestCost_DisabledIsNoop(t *testing.T) {
+	assert.Nil(t, newRequestCostPolicy(&conf.RequestCostConfig{}))
+	AddRequestCost(context.Background(), "db_queries", 1)
+	assert.Nil(t, RequestCosts(context.Background()))
+
+	assert.Error(t, validateRequestCostConfig(&conf.RequestCostConfig{Enabled: true, Weights: map[string]float64{"db": -1}}))
+	assert.NoError(t, validateRequestCostConfig(&conf.RequestCostConfig{Enabled: true, Weights: map[string]float64{"db": 2}}))
+}
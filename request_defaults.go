@@ -0,0 +1,190 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/encoding/form"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// requestDefault fills one field, from a fixed value or from a request header.
+type requestDefault struct {
+	field  string
+	value  string
+	header string
+}
+
+// requestDefaultsRule is a resolved RequestDefaultsRule.
+type requestDefaultsRule struct {
+	operation string
+	defaults  []requestDefault
+}
+
+// requestDefaultsPolicy is the resolved request.defaults.
+type requestDefaultsPolicy struct {
+	rules []requestDefaultsRule
+}
+
+// newRequestDefaultsPolicy returns nil when no defaults are configured.
+func newRequestDefaultsPolicy(rules []*conf.RequestDefaultsRule) *requestDefaultsPolicy {
+	p := &requestDefaultsPolicy{}
+	for _, rule := range rules {
+		op := strings.TrimSpace(rule.GetOperation())
+		if op == "" {
+			continue
+		}
+		r := requestDefaultsRule{operation: op}
+		for field, value := range rule.GetValues() {
+			r.defaults = append(r.defaults, requestDefault{field: strings.TrimSpace(field), value: value})
+		}
+		for field, header := range rule.GetHeaders() {
+			r.defaults = append(r.defaults, requestDefault{field: strings.TrimSpace(field), header: strings.TrimSpace(header)})
+		}
+		// Fixed values first, then by field, so application order does not depend on map order.
+		slices.SortFunc(r.defaults, func(a, b requestDefault) int {
+			if (a.header == "") != (b.header == "") {
+				if a.header == "" {
+					return -1
+				}
+				return 1
+			}
+			return strings.Compare(a.field, b.field)
+		})
+		if len(r.defaults) > 0 {
+			p.rules = append(p.rules, r)
+		}
+	}
+	if len(p.rules) == 0 {
+		return nil
+	}
+	return p
+}
+
+// apply fills the unset fields of msg from the rules matching operation.
+func (p *requestDefaultsPolicy) apply(ctx context.Context, operation string, header transport.Header, msg proto.Message) {
+	m := msg.ProtoReflect()
+	for _, rule := range p.rules {
+		if !wildcardMatches(rule.operation, operation) {
+			continue
+		}
+		for _, d := range rule.defaults {
+			if isFieldSet(m, d.field) {
+				continue
+			}
+			value := d.value
+			if d.header != "" {
+				if header == nil {
+					continue
+				}
+				value = header.Get(d.header)
+				if strings.EqualFold(d.header, "Accept-Language") {
+					value = preferredLanguage(value)
+				}
+				if value == "" {
+					continue
+				}
+			}
+			if err := form.DecodeValues(msg, url.Values{d.field: {value}}); err != nil && d.header == "" {
+				// Header values come from clients; only misconfigured fixed values are worth a warning.
+				log.WarnwCtx(ctx, "msg", "[HTTP Defaults] invalid default", "api", operation, "field", d.field, "error", err)
+			}
+		}
+	}
+}
+
+// isFieldSet reports whether the field at path, dot-separated proto or JSON names, is set in m. Unknown
+// fields count as set, so no default is attempted for them.
+func isFieldSet(m protoreflect.Message, path string) bool {
+	names := strings.Split(path, ".")
+	for i, name := range names {
+		fields := m.Descriptor().Fields()
+		fd := fields.ByName(protoreflect.Name(name))
+		if fd == nil {
+			fd = fields.ByJSONName(name)
+		}
+		if fd == nil {
+			return true
+		}
+		if !m.Has(fd) {
+			return false
+		}
+		if i == len(names)-1 {
+			return true
+		}
+		if fd.Message() == nil || fd.IsList() || fd.IsMap() {
+			return true
+		}
+		m = m.Get(fd).Message()
+	}
+	return true
+}
+
+// preferredLanguage returns the language tag with the highest weight in an Accept-Language header, or ""
+// when there is none or only "*".
+func preferredLanguage(header string) string {
+	best, bestQ := "", 0.0
+	for part := range strings.SplitSeq(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > bestQ {
+			best, bestQ = tag, q
+		}
+	}
+	return best
+}
+
+// requestDefaultsMiddleware fills unset request fields before the handler, so handlers stop repeating
+// "if req.PageSize == 0" checks.
+func requestDefaultsMiddleware(p *requestDefaultsPolicy) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			msg, ok := req.(proto.Message)
+			if !ok {
+				return handler(ctx, req)
+			}
+			if tr, ok := transport.FromServerContext(ctx); ok {
+				p.apply(ctx, tr.Operation(), tr.RequestHeader(), msg)
+			}
+			return handler(ctx, req)
+		}
+	}
+}
+
+// validateRequestDefaults rejects rules without an operation or without defaults.
+func validateRequestDefaults(rules []*conf.RequestDefaultsRule) error {
+	for i, rule := range rules {
+		if strings.TrimSpace(rule.GetOperation()) == "" {
+			return fmt.Errorf("defaults[%d]: operation is required", i)
+		}
+		if len(rule.GetValues()) == 0 && len(rule.GetHeaders()) == 0 {
+			return fmt.Errorf("defaults[%d]: values or headers are required", i)
+		}
+		for field, header := range rule.GetHeaders() {
+			if strings.TrimSpace(header) == "" {
+				return fmt.Errorf("defaults[%d]: header of %q is empty", i, field)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx-http/httptesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestDefaultsMiddleware(t *testing.T) {
+	policy := newRequestDefaultsPolicy([]*conf.RequestDefaultsRule{
+		{
+			Operation: "/api.v1.Servers/Create",
+			Values:    map[string]string{"addr": ":8080", "request.rawBodyMaxBytes": "1024", "timeout": "5s"},
+			Headers:   map[string]string{"network": "Accept-Language"},
+		},
+		{Operation: "/api.v1.Servers/*", Values: map[string]string{"addr": ":9090", "tls_auth_type": "2"}},
+	})
+	require.NotNil(t, policy)
+	tester := httptesting.NewMiddlewareTester(t, requestDefaultsMiddleware(policy))
+
+	msg := &conf.Http{}
+	tester.Run(httptesting.Request{
+		Operation: "/api.v1.Servers/Create",
+		Header:    map[string]string{"Accept-Language": "fr;q=0.5, de-CH, *;q=0.1"},
+		Message:   msg,
+	}).AssertNoError().AssertHandlerCalled(true)
+	assert.Equal(t, ":8080", msg.Addr, "the first matching rule wins")
+	assert.Equal(t, int64(1024), msg.GetRequest().GetRawBodyMaxBytes())
+	assert.Equal(t, "5s", msg.GetTimeout().AsDuration().String())
+	assert.Equal(t, "de-CH", msg.Network)
+	assert.Equal(t, int32(2), msg.TlsAuthType)
+
+	msg = &conf.Http{Addr: ":1", Request: &conf.RequestConfig{RawBodyMaxBytes: 7}}
+	tester.Run(httptesting.Request{Operation: "/api.v1.Servers/Create", Message: msg}).AssertNoError()
+	assert.Equal(t, ":1", msg.Addr, "set fields are kept")
+	assert.Equal(t, int64(7), msg.Request.RawBodyMaxBytes)
+	assert.Empty(t, msg.Network, "no header, no default")
+
+	msg = &conf.Http{}
+	tester.Run(httptesting.Request{Operation: "/api.v1.Users/Create", Message: msg}).AssertNoError()
+	assert.Empty(t, msg.Addr, "other operations are untouched")
+}
+
+func TestPreferredLanguage(t *testing.T) {
+	assert.Equal(t, "en-US", preferredLanguage("en-US,en;q=0.9"))
+	assert.Equal(t, "de", preferredLanguage("fr;q=0.4, de;q=0.8"))
+	assert.Equal(t, "", preferredLanguage("*"))
+	assert.Equal(t, "", preferredLanguage(""))
+}
+
+func TestValidateRequestDefaults(t *testing.T) {
+	assert.NoError(t, validateRequestDefaults(nil))
+	assert.NoError(t, validateRequestDefaults([]*conf.RequestDefaultsRule{{Operation: "/a", Values: map[string]string{"x": "1"}}}))
+	assert.Error(t, validateRequestDefaults([]*conf.RequestDefaultsRule{{Values: map[string]string{"x": "1"}}}))
+	assert.Error(t, validateRequestDefaults([]*conf.RequestDefaultsRule{{Operation: "/a"}}))
+	assert.Error(t, validateRequestDefaults([]*conf.RequestDefaultsRule{{Operation: "/a", Headers: map[string]string{"x": " "}}}))
+	assert.Nil(t, newRequestDefaultsPolicy(nil))
+}
@@ -0,0 +1,196 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// RequestFilter transforms a decoded request in place before validation and the handler. An error fails
+// the request and is written by the error encoder.
+type RequestFilter func(ctx context.Context, req any) error
+
+// registeredRequestFilter is a filter and the operations it applies to.
+type registeredRequestFilter struct {
+	pattern string
+	filter  RequestFilter
+}
+
+// RegisterRequestFilter adds a filter for operations matching pattern: an exact operation, a prefix ending
+// in "*", or "" for every route. Filters run in registration order after decoding, before request defaults
+// and validation, so normalization is written once instead of in every handler.
+func (h *ServiceHttp) RegisterRequestFilter(pattern string, filter RequestFilter) error {
+	if filter == nil {
+		return fmt.Errorf("request filter for %q is nil", pattern)
+	}
+	h.requestFilterMu.Lock()
+	defer h.requestFilterMu.Unlock()
+	h.requestFilters = append(h.requestFilters, registeredRequestFilter{pattern: strings.TrimSpace(pattern), filter: filter})
+	return nil
+}
+
+// matchingRequestFilters returns the filters registered for operation.
+func (h *ServiceHttp) matchingRequestFilters(operation string) []RequestFilter {
+	h.requestFilterMu.RLock()
+	defer h.requestFilterMu.RUnlock()
+	var filters []RequestFilter
+	for _, f := range h.requestFilters {
+		if wildcardMatches(f.pattern, operation) {
+			filters = append(filters, f.filter)
+		}
+	}
+	return filters
+}
+
+// requestFilterMiddleware runs the registered filters of the request's operation on the decoded request.
+func (h *ServiceHttp) requestFilterMiddleware() middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			var operation string
+			if tr, ok := transport.FromServerContext(ctx); ok {
+				operation = tr.Operation()
+			}
+			for _, filter := range h.matchingRequestFilters(operation) {
+				if err := filter(ctx, req); err != nil {
+					return nil, err
+				}
+			}
+			return handler(ctx, req)
+		}
+	}
+}
+
+// MapStrings returns a RequestFilter that replaces every string field of proto requests, including those of
+// nested messages, lists and map values, with fn of it. Other requests are left alone.
+func MapStrings(fn func(string) string) RequestFilter {
+	return func(_ context.Context, req any) error {
+		if msg, ok := req.(proto.Message); ok {
+			mapMessageStrings(msg.ProtoReflect(), fn)
+		}
+		return nil
+	}
+}
+
+// TrimStrings is a RequestFilter that trims leading and trailing white space from every string field.
+func TrimStrings(ctx context.Context, req any) error {
+	return MapStrings(strings.TrimSpace)(ctx, req)
+}
+
+// StripZeroWidth is a RequestFilter that removes zero-width characters, often pasted along with
+// identifiers, from every string field.
+func StripZeroWidth(ctx context.Context, req any) error {
+	return MapStrings(stripZeroWidth)(ctx, req)
+}
+
+// LowercaseFields returns a RequestFilter that lowercases the named string fields, e.g. "email" or
+// "user.email"; fields are named by proto or JSON name, with dots for nested fields.
+func LowercaseFields(fields ...string) RequestFilter {
+	return func(_ context.Context, req any) error {
+		msg, ok := req.(proto.Message)
+		if !ok {
+			return nil
+		}
+		for _, path := range fields {
+			m, fd := resolveSetField(msg.ProtoReflect(), path)
+			if fd == nil || fd.Kind() != protoreflect.StringKind || fd.IsMap() {
+				continue
+			}
+			if fd.IsList() {
+				list := m.Mutable(fd).List()
+				for i := range list.Len() {
+					list.Set(i, protoreflect.ValueOfString(strings.ToLower(list.Get(i).String())))
+				}
+				continue
+			}
+			m.Set(fd, protoreflect.ValueOfString(strings.ToLower(m.Get(fd).String())))
+		}
+		return nil
+	}
+}
+
+// resolveSetField returns the message holding the field at path and its descriptor, or a nil descriptor
+// when the path is unknown or a message along it is unset.
+func resolveSetField(m protoreflect.Message, path string) (protoreflect.Message, protoreflect.FieldDescriptor) {
+	names := strings.Split(path, ".")
+	for i, name := range names {
+		fields := m.Descriptor().Fields()
+		fd := fields.ByName(protoreflect.Name(name))
+		if fd == nil {
+			fd = fields.ByJSONName(name)
+		}
+		if fd == nil || !m.Has(fd) {
+			return nil, nil
+		}
+		if i == len(names)-1 {
+			return m, fd
+		}
+		if fd.Message() == nil || fd.IsList() || fd.IsMap() {
+			return nil, nil
+		}
+		m = m.Mutable(fd).Message()
+	}
+	return nil, nil
+}
+
+func mapMessageStrings(m protoreflect.Message, fn func(string) string) {
+	// Collect first: the message must not change while Range iterates it.
+	var fields []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		fields = append(fields, fd)
+		return true
+	})
+	for _, fd := range fields {
+		switch {
+		case fd.IsList():
+			list := m.Mutable(fd).List()
+			for i := range list.Len() {
+				switch fd.Kind() {
+				case protoreflect.StringKind:
+					list.Set(i, protoreflect.ValueOfString(fn(list.Get(i).String())))
+				case protoreflect.MessageKind, protoreflect.GroupKind:
+					mapMessageStrings(list.Get(i).Message(), fn)
+				}
+			}
+		case fd.IsMap():
+			mp := m.Mutable(fd).Map()
+			var keys []protoreflect.MapKey
+			mp.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+				keys = append(keys, k)
+				return true
+			})
+			for _, k := range keys {
+				switch fd.MapValue().Kind() {
+				case protoreflect.StringKind:
+					mp.Set(k, protoreflect.ValueOfString(fn(mp.Get(k).String())))
+				case protoreflect.MessageKind, protoreflect.GroupKind:
+					mapMessageStrings(mp.Get(k).Message(), fn)
+				}
+			}
+		case fd.Kind() == protoreflect.StringKind:
+			m.Set(fd, protoreflect.ValueOfString(fn(m.Get(fd).String())))
+		case fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind:
+			mapMessageStrings(m.Mutable(fd).Message(), fn)
+		}
+	}
+}
+
+// zeroWidthChars are zero-width spaces and joiners, the word joiner and the byte order mark.
+const zeroWidthChars = "\u200b\u200c\u200d\u2060\ufeff"
+
+// stripZeroWidth removes zeroWidthChars from s.
+func stripZeroWidth(s string) string {
+	if !strings.ContainsAny(s, zeroWidthChars) {
+		return s
+	}
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(zeroWidthChars, r) {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,48 @@
+package http
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx-http/httptesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestFilters(t *testing.T) {
+	h := &ServiceHttp{}
+	require.Error(t, h.RegisterRequestFilter("", nil))
+	require.NoError(t, h.RegisterRequestFilter("", StripZeroWidth))
+	require.NoError(t, h.RegisterRequestFilter("/api.v1.Servers/*", TrimStrings))
+	require.NoError(t, h.RegisterRequestFilter("/api.v1.Servers/Create", LowercaseFields("network", "request.defaults")))
+	require.NoError(t, h.RegisterRequestFilter("/api.v1.Servers/Delete", func(context.Context, any) error {
+		return errors.Forbidden("READ_ONLY", "")
+	}))
+	tester := httptesting.NewMiddlewareTester(t, h.requestFilterMiddleware())
+
+	msg := &conf.Http{
+		Addr:    "  :8080\u200b ",
+		Network: " TCP",
+		Request: &conf.RequestConfig{Defaults: []*conf.RequestDefaultsRule{
+			{Operation: " /a ", Values: map[string]string{"k": " v\ufeff"}},
+		}},
+	}
+	tester.Run(httptesting.Request{Operation: "/api.v1.Servers/Create", Message: msg}).
+		AssertNoError().AssertHandlerCalled(true)
+	assert.Equal(t, ":8080", msg.Addr)
+	assert.Equal(t, "tcp", msg.Network, "filters run in registration order")
+	assert.Equal(t, "/a", msg.Request.Defaults[0].Operation, "nested messages and lists are filtered")
+	assert.Equal(t, "v", msg.Request.Defaults[0].Values["k"], "map values are filtered")
+
+	msg = &conf.Http{Addr: " :1\u200d"}
+	tester.Run(httptesting.Request{Operation: "/api.v1.Users/Create", Message: msg}).AssertNoError()
+	assert.Equal(t, " :1", msg.Addr, "only matching filters run")
+
+	tester.Run(httptesting.Request{Operation: "/api.v1.Servers/Delete", Message: &conf.Http{}}).
+		AssertError(403, "READ_ONLY").AssertHandlerCalled(false)
+
+	tester.Run(httptesting.Request{Operation: "/api.v1.Servers/Create", Message: map[string]any{"a": " b"}}).
+		AssertNoError()
+}
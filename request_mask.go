@@ -0,0 +1,193 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	nhttp "net/http"
+	"sort"
+	"sync"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+const fieldMaskFullName = "google.protobuf.FieldMask"
+
+// requestFieldMaskKey stores the *requestFieldMask of a request in its context.
+type requestFieldMaskKey struct{}
+
+// requestFieldMask holds the paths present in the decoded body of a request.
+type requestFieldMask struct {
+	mu    sync.Mutex
+	paths []string
+	set   bool
+}
+
+// RequestFieldMask returns the fields present in the JSON body of the current request, as proto paths
+// relative to the decoded message. It is available when request.populate_field_masks is enabled and lets
+// handlers that bind the body to a sub-message (body: "user") tell omitted fields from zero values.
+func RequestFieldMask(ctx context.Context) (*fieldmaskpb.FieldMask, bool) {
+	m, ok := ctx.Value(requestFieldMaskKey{}).(*requestFieldMask)
+	if !ok {
+		return nil, false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.set {
+		return nil, false
+	}
+	return &fieldmaskpb.FieldMask{Paths: append([]string(nil), m.paths...)}, true
+}
+
+// fieldMaskFilter gives each request a holder for the field mask recorded by the request decoder.
+func fieldMaskFilter(next nhttp.Handler) nhttp.Handler {
+	return nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		ctx := context.WithValue(r.Context(), requestFieldMaskKey{}, &requestFieldMask{})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// requestDecoder returns a decoder that works like the Kratos default one but classifies failures by
+// cause, extended to populate field masks and to check Content-Types when enabled.
+func (h *ServiceHttp) requestDecoder() http.DecodeRequestFunc {
+	decode := newDecodeErrorPolicy(h.conf.GetRequest().GetDecodeErrors()).decode
+	if h.conf.GetRequest().GetPopulateFieldMasks() {
+		decode = withFieldMasks(decode)
+	}
+	if p := newContentTypePolicy(h.conf.GetRequest().GetContentTypes()); p != nil {
+		decode = h.withContentTypeCheck(p, decode)
+	}
+	return decode
+}
+
+// withFieldMasks decodes the body with decode and then fills unset FieldMask fields of the message from
+// the JSON keys present in the body. A mask next to a single resource message that the body sets (as in
+// UpdateUserRequest{user, update_mask}) lists paths relative to that resource; otherwise paths are
+// relative to the message itself. Masks sent in the body or query are kept.
+func withFieldMasks(decode http.DecodeRequestFunc) http.DecodeRequestFunc {
+	return func(r *nhttp.Request, v any) error {
+		if err := decode(r, v); err != nil {
+			return err
+		}
+		return populateRequestFieldMasks(r, v)
+	}
+}
+
+func populateRequestFieldMasks(r *nhttp.Request, v any) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return nil
+	}
+	codec, ok := http.CodecForRequest(r, "Content-Type")
+	if !ok || (codec.Name() != "json" && codec.Name() != "merge-patch+json") {
+		return nil
+	}
+	// The decoder leaves a fresh copy of the body in place.
+	data, err := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(data))
+	if err != nil || len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	var body map[string]any
+	if json.Unmarshal(data, &body) != nil {
+		return nil
+	}
+
+	m := msg.ProtoReflect()
+	paths := presentFieldPaths(body, m.Descriptor(), "")
+	if holder, ok := r.Context().Value(requestFieldMaskKey{}).(*requestFieldMask); ok {
+		holder.mu.Lock()
+		holder.paths, holder.set = paths, true
+		holder.mu.Unlock()
+	}
+	populateFieldMasks(m, body)
+	return nil
+}
+
+// presentFieldPaths lists the proto paths of the keys present in body, descending into message fields.
+// Unknown keys are skipped, as the decoder discards them.
+func presentFieldPaths(body map[string]any, md protoreflect.MessageDescriptor, prefix string) []string {
+	var paths []string
+	for key, value := range body {
+		fd := lookupField(md, key)
+		if fd == nil {
+			continue
+		}
+		path := prefix + string(fd.Name())
+		if sm := patchableMessage(fd); sm != nil {
+			if sub, ok := value.(map[string]any); ok && len(sub) > 0 {
+				paths = append(paths, presentFieldPaths(sub, sm, path+".")...)
+				continue
+			}
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func isFieldMaskField(fd protoreflect.FieldDescriptor) bool {
+	return !fd.IsList() && fd.Message() != nil && fd.Message().FullName() == fieldMaskFullName
+}
+
+func populateFieldMasks(m protoreflect.Message, body map[string]any) {
+	md := m.Descriptor()
+	var masks []protoreflect.FieldDescriptor
+	var resource protoreflect.FieldDescriptor
+	resources := 0
+	for i := range md.Fields().Len() {
+		fd := md.Fields().Get(i)
+		if isFieldMaskField(fd) {
+			masks = append(masks, fd)
+			continue
+		}
+		if patchableMessage(fd) == nil {
+			continue
+		}
+		if _, ok := body[fd.JSONName()]; !ok {
+			if _, ok = body[string(fd.Name())]; !ok {
+				continue
+			}
+		}
+		resource = fd
+		resources++
+	}
+	if len(masks) == 0 {
+		return
+	}
+
+	var paths []string
+	if resources == 1 {
+		sub, _ := body[resource.JSONName()].(map[string]any)
+		if sub == nil {
+			sub, _ = body[string(resource.Name())].(map[string]any)
+		}
+		paths = presentFieldPaths(sub, resource.Message(), "")
+	} else {
+		for _, p := range presentFieldPaths(body, md, "") {
+			if !isMaskPath(p, masks) {
+				paths = append(paths, p)
+			}
+		}
+	}
+	for _, fd := range masks {
+		if m.Has(fd) {
+			continue
+		}
+		mask := &fieldmaskpb.FieldMask{Paths: append([]string(nil), paths...)}
+		m.Set(fd, protoreflect.ValueOfMessage(mask.ProtoReflect()))
+	}
+}
+
+func isMaskPath(path string, masks []protoreflect.FieldDescriptor) bool {
+	for _, fd := range masks {
+		if path == string(fd.Name()) {
+			return true
+		}
+	}
+	return false
+}
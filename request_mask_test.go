@@ -0,0 +1,122 @@
+package http
+
+import (
+	"context"
+	nhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// maskTestFile describes:
+//
+//	message User { string name = 1; Address address = 2; int32 age = 3; }
+//	message Address { string city = 1; string zip = 2; }
+//	message UpdateUserRequest { User user = 1; google.protobuf.FieldMask update_mask = 2; }
+func maskTestFile(t *testing.T) protoreflect.FileDescriptor {
+	t.Helper()
+	field := func(name string, num int32, typ descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(num),
+			Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:   typ.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+	str := descriptorpb.FieldDescriptorProto_TYPE_STRING
+	msg := descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
+	fdp := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("masktest/mask.proto"),
+		Package:    proto.String("masktest"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/field_mask.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("User"), Field: []*descriptorpb.FieldDescriptorProto{
+				field("name", 1, str, ""),
+				field("address", 2, msg, ".masktest.Address"),
+				field("age", 3, descriptorpb.FieldDescriptorProto_TYPE_INT32, ""),
+			}},
+			{Name: proto.String("Address"), Field: []*descriptorpb.FieldDescriptorProto{
+				field("city", 1, str, ""),
+				field("zip", 2, str, ""),
+			}},
+			{Name: proto.String("UpdateUserRequest"), Field: []*descriptorpb.FieldDescriptorProto{
+				field("user", 1, msg, ".masktest.User"),
+				field("update_mask", 2, msg, ".google.protobuf.FieldMask"),
+			}},
+		},
+	}
+	fd, err := protodesc.NewFile(fdp, protoregistry.GlobalFiles)
+	require.NoError(t, err)
+	return fd
+}
+
+func decodeWithMasks(t *testing.T, md protoreflect.MessageDescriptor, body string) (*dynamicpb.Message, *nhttp.Request) {
+	t.Helper()
+	r := httptest.NewRequest(nhttp.MethodPatch, "/users/1", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	var req *nhttp.Request
+	fieldMaskFilter(nhttp.HandlerFunc(func(_ nhttp.ResponseWriter, r *nhttp.Request) { req = r })).
+		ServeHTTP(httptest.NewRecorder(), r)
+	msg := dynamicpb.NewMessage(md)
+	require.NoError(t, withFieldMasks(newDecodeErrorPolicy(nil).decode)(req, msg))
+	return msg, req
+}
+
+func maskPaths(t *testing.T, m *dynamicpb.Message, field string) []string {
+	t.Helper()
+	fd := m.Descriptor().Fields().ByName(protoreflect.Name(field))
+	if !m.Has(fd) {
+		return nil
+	}
+	var mask fieldmaskpb.FieldMask
+	b, err := proto.Marshal(m.Get(fd).Message().Interface())
+	require.NoError(t, err)
+	require.NoError(t, proto.Unmarshal(b, &mask))
+	return mask.Paths
+}
+
+func TestFieldMaskRequestDecoder_Resource(t *testing.T) {
+	md := maskTestFile(t).Messages().ByName("UpdateUserRequest")
+
+	msg, req := decodeWithMasks(t, md, `{"user":{"name":"","address":{"city":"Oslo"},"unknown":1}}`)
+	assert.Equal(t, []string{"address.city", "name"}, maskPaths(t, msg, "update_mask"))
+	mask, ok := RequestFieldMask(req.Context())
+	require.True(t, ok)
+	assert.Equal(t, []string{"user.address.city", "user.name"}, mask.Paths)
+
+	// An explicit mask is kept.
+	msg, _ = decodeWithMasks(t, md, `{"user":{"name":"a","age":3},"updateMask":"age"}`)
+	assert.Equal(t, []string{"age"}, maskPaths(t, msg, "update_mask"))
+}
+
+func TestFieldMaskRequestDecoder_SubMessage(t *testing.T) {
+	md := maskTestFile(t).Messages().ByName("User")
+	_, req := decodeWithMasks(t, md, `{"age":0,"address":{}}`)
+	mask, ok := RequestFieldMask(req.Context())
+	require.True(t, ok)
+	assert.Equal(t, []string{"address", "age"}, mask.Paths)
+}
+
+func TestRequestFieldMask_Unavailable(t *testing.T) {
+	_, ok := RequestFieldMask(context.Background())
+	assert.False(t, ok)
+
+	h := &ServiceHttp{conf: &conf.Http{}}
+	assert.NotNil(t, h.requestDecoder())
+}
@@ -0,0 +1,298 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	nhttp "net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+const (
+	// WrongRegionReason is the Kratos error reason for requests of tenants homed in another region, with
+	// residency.enforcement set to "reject".
+	WrongRegionReason = "WRONG_REGION"
+	// RegionRedirectReason is the Kratos error reason for requests of tenants homed in another region, with
+	// residency.enforcement set to "redirect". The Location header points at the home region.
+	RegionRedirectReason = "REGION_REDIRECT"
+
+	residencyResolverUnavailableReason = "RESIDENCY_RESOLVER_UNAVAILABLE"
+
+	residencyEnforcementOff      = "off"
+	residencyEnforcementReject   = "reject"
+	residencyEnforcementRedirect = "redirect"
+
+	// tenantRegionAttribute is the Tenant attribute read as the tenant's home region.
+	tenantRegionAttribute = "region"
+
+	defaultRegionEnv    = "LYNX_REGION"
+	defaultRegionHeader = "X-Served-Region"
+
+	// Values of the "action" label of lynx_http_residency_requests_total.
+	residencyActionLocal      = "local"
+	residencyActionRemote     = "remote"
+	residencyActionRejected   = "rejected"
+	residencyActionRedirected = "redirected"
+	residencyActionUnknown    = "unknown"
+)
+
+// ResidencyResolver returns the home region of a tenant, or "" when it has none. Errors fail enforced
+// requests with 503.
+type ResidencyResolver interface {
+	HomeRegion(ctx context.Context, tenant *Tenant) (string, error)
+}
+
+// ResidencyResolverFunc adapts a function to ResidencyResolver.
+type ResidencyResolverFunc func(ctx context.Context, tenant *Tenant) (string, error)
+
+// HomeRegion calls f.
+func (f ResidencyResolverFunc) HomeRegion(ctx context.Context, tenant *Tenant) (string, error) {
+	return f(ctx, tenant)
+}
+
+// ServingRegionKey carries the region serving the request, and HomeRegionKey the home region of its tenant
+// when it is known.
+var (
+	ServingRegionKey = NewContextKey[string]("lynx.http.serving_region")
+	HomeRegionKey    = NewContextKey[string]("lynx.http.home_region")
+)
+
+// ServingRegionFromContext returns the region serving the request.
+func ServingRegionFromContext(ctx context.Context) (string, bool) {
+	return ServingRegionKey.Value(ctx)
+}
+
+// HomeRegionFromContext returns the home region of the request's tenant.
+func HomeRegionFromContext(ctx context.Context) (string, bool) {
+	return HomeRegionKey.Value(ctx)
+}
+
+// RegisterResidencyResolver registers a resolver under name for residency.resolver to select.
+func (h *ServiceHttp) RegisterResidencyResolver(name string, resolver ResidencyResolver) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("invalid residency resolver name %q", name)
+	}
+	if resolver == nil {
+		return fmt.Errorf("residency resolver %q: resolver is nil", name)
+	}
+	h.residencyResolverMu.Lock()
+	defer h.residencyResolverMu.Unlock()
+	if _, dup := h.residencyResolvers[name]; dup {
+		return fmt.Errorf("residency resolver %q already registered", name)
+	}
+	if h.residencyResolvers == nil {
+		h.residencyResolvers = make(map[string]ResidencyResolver)
+	}
+	h.residencyResolvers[name] = resolver
+	return nil
+}
+
+func (h *ServiceHttp) residencyResolver(name string) (ResidencyResolver, bool) {
+	h.residencyResolverMu.RLock()
+	defer h.residencyResolverMu.RUnlock()
+	resolver, ok := h.residencyResolvers[name]
+	return resolver, ok
+}
+
+// residencyPolicy is the resolved ResidencyConfig.
+type residencyPolicy struct {
+	region        string
+	header        string
+	enforcement   string
+	resolver      string
+	tenantRegions map[string]string
+	endpoints     map[string]string
+	exempt        []string
+}
+
+// newResidencyPolicy returns nil when residency is disabled.
+func newResidencyPolicy(cfg *conf.ResidencyConfig) *residencyPolicy {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	p := &residencyPolicy{
+		region:        servingRegion(cfg),
+		header:        configuredPath(cfg.GetHeader(), defaultRegionHeader),
+		enforcement:   strings.ToLower(configuredPath(cfg.GetEnforcement(), residencyEnforcementOff)),
+		resolver:      strings.TrimSpace(cfg.GetResolver()),
+		tenantRegions: cfg.GetTenantRegions(),
+		endpoints:     cfg.GetRegionEndpoints(),
+	}
+	for _, op := range cfg.GetExemptOperations() {
+		if op = strings.TrimSpace(op); op != "" {
+			p.exempt = append(p.exempt, op)
+		}
+	}
+	return p
+}
+
+// servingRegion returns the configured region, or the value of region_env.
+func servingRegion(cfg *conf.ResidencyConfig) string {
+	if region := strings.TrimSpace(cfg.GetRegion()); region != "" {
+		return region
+	}
+	return strings.TrimSpace(os.Getenv(configuredPath(cfg.GetRegionEnv(), defaultRegionEnv)))
+}
+
+// homeRegion returns the home region of tenant from tenant_regions, the tenant's region attribute or the
+// registered resolver, in that order.
+func (h *ServiceHttp) homeRegion(ctx context.Context, p *residencyPolicy, tenant *Tenant) (string, error) {
+	if region := p.tenantRegions[tenant.ID]; region != "" {
+		return region, nil
+	}
+	if region := tenant.Attributes[tenantRegionAttribute]; region != "" {
+		return region, nil
+	}
+	if p.resolver == "" {
+		return "", nil
+	}
+	resolver, ok := h.residencyResolver(p.resolver)
+	if !ok {
+		return "", fmt.Errorf("residency resolver %q is not registered", p.resolver)
+	}
+	return resolver.HomeRegion(ctx, tenant)
+}
+
+// requestTenant returns the tenant set by the tenant middleware, or one with the ID set by WithTenant.
+func requestTenant(ctx context.Context) (*Tenant, bool) {
+	if tenant, ok := ResolvedTenantFromContext(ctx); ok && tenant != nil {
+		return tenant, true
+	}
+	if id, ok := TenantFromContext(ctx); ok && id != "" {
+		return &Tenant{ID: id}, true
+	}
+	return nil, false
+}
+
+// residencyMiddleware tags requests with the serving region and, when enforcement is on, rejects or
+// redirects requests of tenants homed in another region.
+func (h *ServiceHttp) residencyMiddleware(p *residencyPolicy) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			var operation, path string
+			r, _ := http.RequestFromServerContext(ctx)
+			if r != nil {
+				path = r.URL.Path
+			}
+			tr, hasTransport := transport.FromServerContext(ctx)
+			if hasTransport {
+				operation = tr.Operation()
+				tr.ReplyHeader().Set(p.header, p.region)
+			}
+			ctx = ServingRegionKey.WithValue(ctx, p.region)
+
+			tenant, ok := requestTenant(ctx)
+			if !ok || routeMatchesAny(p.exempt, operation, path) {
+				return handler(ctx, req)
+			}
+			home, err := h.homeRegion(ctx, p, tenant)
+			if err != nil {
+				log.WarnfCtx(ctx, "Failed to resolve the home region of tenant %q: %v", tenant.ID, err)
+				if p.enforcement != residencyEnforcementOff {
+					return nil, errors.New(nhttp.StatusServiceUnavailable, residencyResolverUnavailableReason,
+						"residency resolution unavailable").WithCause(err)
+				}
+			}
+			if home == "" {
+				h.recordResidency(ctx, p.region, "", residencyActionUnknown)
+				return handler(ctx, req)
+			}
+			ctx = HomeRegionKey.WithValue(ctx, home)
+			if home == p.region || p.enforcement == residencyEnforcementOff {
+				action := residencyActionLocal
+				if home != p.region {
+					action = residencyActionRemote
+				}
+				h.recordResidency(ctx, p.region, home, action)
+				return handler(ctx, req)
+			}
+
+			if p.enforcement == residencyEnforcementRedirect {
+				if location := regionLocation(p.endpoints[home], r); location != "" {
+					h.recordResidency(ctx, p.region, home, residencyActionRedirected)
+					if hasTransport {
+						tr.ReplyHeader().Set("Location", location)
+					}
+					return nil, errors.New(nhttp.StatusTemporaryRedirect, RegionRedirectReason,
+						fmt.Sprintf("tenant is served in region %s", home)).
+						WithMetadata(map[string]string{"region": home, "location": location})
+				}
+			}
+			h.recordResidency(ctx, p.region, home, residencyActionRejected)
+			return nil, errors.New(nhttp.StatusMisdirectedRequest, WrongRegionReason,
+				fmt.Sprintf("tenant is served in region %s", home)).
+				WithMetadata(map[string]string{"region": home})
+		}
+	}
+}
+
+// regionLocation returns the URL of the request on the region endpoint base, or "" without an endpoint.
+func regionLocation(base string, r *http.Request) string {
+	if base == "" || r == nil {
+		return ""
+	}
+	u := strings.TrimSuffix(base, "/") + r.URL.EscapedPath()
+	if r.URL.RawQuery != "" {
+		u += "?" + r.URL.RawQuery
+	}
+	return u
+}
+
+func (h *ServiceHttp) recordResidency(ctx context.Context, region, home, action string) {
+	if h.residencyRequests == nil {
+		return
+	}
+	_, route := requestMetadata(ctx)
+	h.residencyRequests.WithLabelValues(region, home, route, action).Inc()
+}
+
+// wrongRegionData returns the error data of WRONG_REGION and REGION_REDIRECT errors: the home region and, for
+// redirects, the URL to repeat the request at.
+func wrongRegionData(se *errors.Error) map[string]string {
+	if se == nil || se.Reason != WrongRegionReason && se.Reason != RegionRedirectReason {
+		return nil
+	}
+	data := make(map[string]string, 2)
+	for _, key := range []string{"region", "location"} {
+		if v := se.Metadata[key]; v != "" {
+			data[key] = v
+		}
+	}
+	return data
+}
+
+// validateResidencyConfig requires a serving region, a known enforcement mode and absolute region endpoints.
+func validateResidencyConfig(cfg *conf.ResidencyConfig) error {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	if servingRegion(cfg) == "" {
+		return fmt.Errorf("region is required: set region or the %s environment variable",
+			configuredPath(cfg.GetRegionEnv(), defaultRegionEnv))
+	}
+	switch mode := strings.ToLower(configuredPath(cfg.GetEnforcement(), residencyEnforcementOff)); mode {
+	case residencyEnforcementOff, residencyEnforcementReject, residencyEnforcementRedirect:
+	default:
+		return fmt.Errorf("unknown enforcement %q, valid options: %s, %s, %s", mode,
+			residencyEnforcementOff, residencyEnforcementReject, residencyEnforcementRedirect)
+	}
+	if header := cfg.GetHeader(); strings.ContainsAny(header, " :\t") {
+		return fmt.Errorf("invalid header %q", header)
+	}
+	for region, endpoint := range cfg.GetRegionEndpoints() {
+		u, err := url.Parse(endpoint)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("region_endpoints[%s]: %q is not an absolute URL", region, endpoint)
+		}
+	}
+	return nil
+}
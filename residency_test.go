@@ -0,0 +1,136 @@
+package http
+
+import (
+	"context"
+	"errors"
+	nhttp "net/http"
+	"testing"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx-http/httptesting"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withTenantID sets the tenant ID the way application authentication middleware would.
+func withTenantID(id string) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			if id != "" {
+				ctx = WithTenant(ctx, id)
+			}
+			return handler(ctx, req)
+		}
+	}
+}
+
+func TestResidencyMiddleware(t *testing.T) {
+	h := NewServiceHttp()
+	h.residencyRequests = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_residency_total"},
+		[]string{"region", "home_region", "route", "action"})
+	p := newResidencyPolicy(&conf.ResidencyConfig{
+		Enabled:          true,
+		Region:           "eu-west-1",
+		Enforcement:      "reject",
+		TenantRegions:    map[string]string{"acme": "eu-west-1", "globex": "us-east-1"},
+		ExemptOperations: []string{"/api.v1.Health/*"},
+	})
+	run := func(tenant, operation string) *httptesting.Result {
+		return httptesting.NewMiddlewareTester(t, withTenantID(tenant), h.residencyMiddleware(p)).
+			Run(httptesting.Request{Operation: operation})
+	}
+
+	res := run("acme", "/api.v1.Orders/List").AssertNoError()
+	assert.Equal(t, "eu-west-1", res.Transport.ReplyHeader().Get(defaultRegionHeader))
+	region, _ := ServingRegionFromContext(res.HandlerContext)
+	assert.Equal(t, "eu-west-1", region)
+	home, _ := HomeRegionFromContext(res.HandlerContext)
+	assert.Equal(t, "eu-west-1", home)
+
+	res = run("globex", "/api.v1.Orders/List").AssertError(nhttp.StatusMisdirectedRequest, WrongRegionReason)
+	assert.Equal(t, map[string]string{"region": "us-east-1"}, wrongRegionData(kerrors.FromError(res.Err)))
+	assert.Equal(t, "eu-west-1", res.Transport.ReplyHeader().Get(defaultRegionHeader), "rejections are tagged too")
+
+	run("globex", "/api.v1.Health/Check").AssertNoError()
+	run("", "/api.v1.Orders/List").AssertNoError()
+	run("initech", "/api.v1.Orders/List").AssertNoError()
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(h.residencyRequests.WithLabelValues("eu-west-1", "eu-west-1", "/api.v1.Orders/List", "local")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(h.residencyRequests.WithLabelValues("eu-west-1", "us-east-1", "/api.v1.Orders/List", "rejected")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(h.residencyRequests.WithLabelValues("eu-west-1", "", "/api.v1.Orders/List", "unknown")))
+}
+
+func TestResidencyMiddleware_Redirect(t *testing.T) {
+	h := NewServiceHttp()
+	require.NoError(t, h.RegisterResidencyResolver("directory", ResidencyResolverFunc(func(_ context.Context, tenant *Tenant) (string, error) {
+		if tenant.ID == "down" {
+			return "", errors.New("connection refused")
+		}
+		return "us-east-1", nil
+	})))
+	assert.ErrorContains(t, h.RegisterResidencyResolver("directory", ResidencyResolverFunc(nil)), "already registered")
+
+	p := newResidencyPolicy(&conf.ResidencyConfig{
+		Enabled:         true,
+		Region:          "eu-west-1",
+		Header:          "X-Region",
+		Enforcement:     "redirect",
+		Resolver:        "directory",
+		RegionEndpoints: map[string]string{"us-east-1": "https://us.api.example.com/"},
+	})
+	run := func(tenant string) *httptesting.Result {
+		return httptesting.NewMiddlewareTester(t, withTenantID(tenant), h.residencyMiddleware(p)).
+			Run(httptesting.Request{Operation: "/api.v1.Orders/List", Path: "/v1/orders?page=2"})
+	}
+
+	res := run("globex").AssertError(nhttp.StatusTemporaryRedirect, RegionRedirectReason)
+	assert.Equal(t, "https://us.api.example.com/v1/orders?page=2", res.Transport.ReplyHeader().Get("Location"))
+	assert.Equal(t, "eu-west-1", res.Transport.ReplyHeader().Get("X-Region"))
+	assert.Equal(t, map[string]string{"region": "us-east-1", "location": "https://us.api.example.com/v1/orders?page=2"},
+		wrongRegionData(kerrors.FromError(res.Err)))
+
+	run("down").AssertError(nhttp.StatusServiceUnavailable, residencyResolverUnavailableReason)
+
+	// Regions without an endpoint are rejected instead.
+	p.endpoints = nil
+	run("globex").AssertError(nhttp.StatusMisdirectedRequest, WrongRegionReason)
+}
+
+func TestResidencyMiddleware_Off(t *testing.T) {
+	p := newResidencyPolicy(&conf.ResidencyConfig{Enabled: true, Region: "eu-west-1"})
+	res := httptesting.NewMiddlewareTester(t, NewServiceHttp().residencyMiddleware(p)).
+		WithHandler(func(ctx context.Context, _ any) (any, error) { return nil, nil }).
+		Run(httptesting.Request{Operation: "/api.v1.Orders/List"})
+	res.AssertNoError()
+
+	// The tenant's region attribute is a home region, and "off" serves tenants homed elsewhere.
+	tenantCtx := func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			tenant := &Tenant{ID: "globex", Attributes: map[string]string{"region": "us-east-1"}}
+			return handler(ResolvedTenantKey.WithValue(ctx, tenant), req)
+		}
+	}
+	res = httptesting.NewMiddlewareTester(t, tenantCtx, NewServiceHttp().residencyMiddleware(p)).
+		Run(httptesting.Request{Operation: "/api.v1.Orders/List"}).AssertNoError()
+	home, _ := HomeRegionFromContext(res.HandlerContext)
+	assert.Equal(t, "us-east-1", home)
+}
+
+func TestValidateResidencyConfig(t *testing.T) {
+	require.NoError(t, validateResidencyConfig(nil))
+	require.NoError(t, validateResidencyConfig(&conf.ResidencyConfig{Enabled: true, Region: "eu-west-1"}))
+	t.Setenv("TEST_REGION", "ap-south-1")
+	require.NoError(t, validateResidencyConfig(&conf.ResidencyConfig{Enabled: true, RegionEnv: "TEST_REGION"}))
+	assert.Equal(t, "ap-south-1", newResidencyPolicy(&conf.ResidencyConfig{Enabled: true, RegionEnv: "TEST_REGION"}).region)
+
+	assert.ErrorContains(t, validateResidencyConfig(&conf.ResidencyConfig{Enabled: true, RegionEnv: "UNSET_TEST_REGION"}),
+		"UNSET_TEST_REGION")
+	assert.ErrorContains(t, validateResidencyConfig(&conf.ResidencyConfig{Enabled: true, Region: "eu", Enforcement: "block"}),
+		"unknown enforcement")
+	assert.ErrorContains(t, validateResidencyConfig(&conf.ResidencyConfig{Enabled: true, Region: "eu",
+		RegionEndpoints: map[string]string{"us": "us.api.example.com"}}), "not an absolute URL")
+}
@@ -0,0 +1,237 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"runtime/metrics"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+const (
+	defaultResourceGuardInterval = time.Second
+
+	// resourceGuardResumeRatio is the share of each threshold usage must fall below before shedding stops,
+	// so the guard does not flap around a threshold.
+	resourceGuardResumeRatio = 0.9
+
+	resourceHeap       = "heap"
+	resourceGoroutines = "goroutines"
+
+	heapObjectsMetric = "/memory/classes/heap/objects:bytes"
+	goroutinesMetric  = "/sched/goroutines:goroutines"
+)
+
+// resourceGuard sheds low-priority operations while heap usage or the goroutine count is too high.
+type resourceGuard struct {
+	maxHeap       uint64
+	maxGoroutines uint64
+	interval      time.Duration
+	operations    []string
+	groups        map[string]bool
+	queues        *admissionQueues
+	// read samples heap bytes in use and the goroutine count.
+	read func() (heap, goroutines uint64)
+
+	// exceeded is the resource over its threshold, "" while usage is normal.
+	exceeded atomic.Value
+}
+
+// newResourceGuard returns nil when the guard is disabled or has no threshold.
+func newResourceGuard(cfg *conf.ResourceGuardConfig, queues []*conf.AdmissionQueueConfig) *resourceGuard {
+	if !cfg.GetEnabled() || (cfg.GetMaxHeapBytes() == 0 && cfg.GetMaxGoroutines() == 0) {
+		return nil
+	}
+	g := &resourceGuard{
+		maxHeap:       cfg.GetMaxHeapBytes(),
+		maxGoroutines: uint64(cfg.GetMaxGoroutines()),
+		interval:      defaultResourceGuardInterval,
+		groups:        make(map[string]bool),
+		queues:        newAdmissionQueues(queues),
+		read:          readRuntimeUsage,
+	}
+	if d := cfg.GetCheckInterval().AsDuration(); d > 0 {
+		g.interval = d
+	}
+	for _, op := range cfg.GetShedOperations() {
+		if op = strings.TrimSpace(op); op != "" {
+			g.operations = append(g.operations, op)
+		}
+	}
+	for _, name := range cfg.GetShedAdmissionGroups() {
+		g.groups[strings.TrimSpace(name)] = true
+	}
+	g.exceeded.Store("")
+	return g
+}
+
+// readRuntimeUsage reads heap bytes in use and the goroutine count without stopping the world.
+func readRuntimeUsage() (heap, goroutines uint64) {
+	samples := []metrics.Sample{{Name: heapObjectsMetric}, {Name: goroutinesMetric}}
+	metrics.Read(samples)
+	if samples[0].Value.Kind() == metrics.KindUint64 {
+		heap = samples[0].Value.Uint64()
+	}
+	if samples[1].Value.Kind() == metrics.KindUint64 {
+		goroutines = samples[1].Value.Uint64()
+	}
+	return heap, goroutines
+}
+
+// sample updates the guard state from current usage and logs transitions.
+func (g *resourceGuard) sample() {
+	heap, goroutines := g.read()
+	previous := g.current()
+	next := ""
+	switch {
+	case g.maxHeap > 0 && heap > g.maxHeap:
+		next = resourceHeap
+	case g.maxGoroutines > 0 && goroutines > g.maxGoroutines:
+		next = resourceGoroutines
+	case previous != "" && !g.belowResume(heap, goroutines):
+		// Keep shedding until usage is clearly back to normal.
+		next = previous
+	}
+	if next == previous {
+		return
+	}
+	g.exceeded.Store(next)
+	if next != "" {
+		log.Warnw("msg", "[HTTP Resource Guard] shedding low-priority traffic", "resource", next,
+			"heap_bytes", heap, "max_heap_bytes", g.maxHeap,
+			"goroutines", goroutines, "max_goroutines", g.maxGoroutines)
+		return
+	}
+	log.Infow("msg", "[HTTP Resource Guard] usage back to normal, shedding stopped",
+		"heap_bytes", heap, "goroutines", goroutines)
+}
+
+func (g *resourceGuard) belowResume(heap, goroutines uint64) bool {
+	if g.maxHeap > 0 && float64(heap) >= float64(g.maxHeap)*resourceGuardResumeRatio {
+		return false
+	}
+	if g.maxGoroutines > 0 && float64(goroutines) >= float64(g.maxGoroutines)*resourceGuardResumeRatio {
+		return false
+	}
+	return true
+}
+
+// current returns the exceeded resource, "" while usage is normal.
+func (g *resourceGuard) current() string {
+	return g.exceeded.Load().(string)
+}
+
+// lowPriority reports whether operation is shed under pressure.
+func (g *resourceGuard) lowPriority(operation string) bool {
+	for _, pattern := range g.operations {
+		if wildcardMatches(pattern, operation) {
+			return true
+		}
+	}
+	if q := g.queues.queueFor(operation); q != nil {
+		return g.groups[q.name]
+	}
+	return false
+}
+
+// startResourceGuard samples usage every interval until ctx is cancelled.
+func (h *ServiceHttp) startResourceGuard(ctx context.Context) {
+	h.stopResourceGuard()
+	perf := h.conf.GetPerformance()
+	g := newResourceGuard(perf.GetResourceGuard(), perf.GetAdmissionQueues())
+	if g == nil {
+		return
+	}
+	g.sample()
+	h.resourceGuard.Store(g)
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	h.resourceGuardCancel = cancel
+	h.resourceGuardDone = done
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(g.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				g.sample()
+			}
+		}
+	}()
+	log.Infof("Resource guard enabled (max heap %d bytes, max goroutines %d, every %s)",
+		g.maxHeap, g.maxGoroutines, g.interval)
+}
+
+func (h *ServiceHttp) stopResourceGuard() {
+	if h.resourceGuardCancel != nil {
+		h.resourceGuardCancel()
+		h.resourceGuardCancel = nil
+	}
+	if h.resourceGuardDone != nil {
+		<-h.resourceGuardDone
+		h.resourceGuardDone = nil
+	}
+	h.resourceGuard.Store(nil)
+}
+
+// resourceGuardMiddleware sheds low-priority requests with a busy error while the guard reports pressure.
+// Other requests pay one atomic load.
+func (h *ServiceHttp) resourceGuardMiddleware() middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			g := h.resourceGuard.Load()
+			if g == nil {
+				return handler(ctx, req)
+			}
+			resource := g.current()
+			if resource == "" {
+				return handler(ctx, req)
+			}
+			method, operation := requestMetadata(ctx)
+			if !g.lowPriority(operation) {
+				return handler(ctx, req)
+			}
+			if h.resourceGuardShed != nil {
+				h.resourceGuardShed.WithLabelValues(operation, resource).Inc()
+			}
+			h.recordErrorMetric(method, operation, "resource_guard_"+resource)
+			return nil, errors.ServiceUnavailable(busyReason, fmt.Sprintf("server busy: %s pressure", resource))
+		}
+	}
+}
+
+// validateResourceGuardConfig requires a threshold, something to shed and known admission groups.
+func validateResourceGuardConfig(cfg *conf.ResourceGuardConfig, queues []*conf.AdmissionQueueConfig) error {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	if cfg.GetMaxHeapBytes() == 0 && cfg.GetMaxGoroutines() == 0 {
+		return fmt.Errorf("max heap bytes or max goroutines is required")
+	}
+	if cfg.GetCheckInterval().AsDuration() < 0 {
+		return fmt.Errorf("check interval cannot be negative")
+	}
+	if len(cfg.GetShedOperations()) == 0 && len(cfg.GetShedAdmissionGroups()) == 0 {
+		return fmt.Errorf("shed operations or shed admission groups are required")
+	}
+	known := make(map[string]bool, len(queues))
+	for _, q := range queues {
+		known[strings.TrimSpace(q.GetName())] = true
+	}
+	for _, name := range cfg.GetShedAdmissionGroups() {
+		if !known[strings.TrimSpace(name)] {
+			return fmt.Errorf("admission group %q is not configured", name)
+		}
+	}
+	return nil
+}
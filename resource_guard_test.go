@@ -0,0 +1,76 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx-http/httptesting"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceGuard(t *testing.T) {
+	queues := []*conf.AdmissionQueueConfig{
+		{Name: "reports", Operations: []string{"/api.v1.Reports/*"}, MaxConcurrency: 1},
+	}
+	g := newResourceGuard(&conf.ResourceGuardConfig{
+		Enabled:             true,
+		MaxHeapBytes:        1000,
+		MaxGoroutines:       100,
+		ShedOperations:      []string{"/api.v1.Search/*"},
+		ShedAdmissionGroups: []string{"reports"},
+	}, queues)
+	require.NotNil(t, g)
+	var heap, goroutines uint64 = 500, 10
+	g.read = func() (uint64, uint64) { return heap, goroutines }
+
+	h := &ServiceHttp{resourceGuardShed: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "shed"}, []string{"route", "resource"})}
+	h.resourceGuard.Store(g)
+	tester := httptesting.NewMiddlewareTester(t, h.resourceGuardMiddleware())
+
+	g.sample()
+	tester.Run(httptesting.Request{Operation: "/api.v1.Search/Query"}).AssertNoError().AssertHandlerCalled(true)
+
+	goroutines = 150
+	g.sample()
+	assert.Equal(t, resourceGoroutines, g.current())
+	tester.Run(httptesting.Request{Operation: "/api.v1.Search/Query"}).
+		AssertError(503, busyReason).AssertHandlerCalled(false)
+	tester.Run(httptesting.Request{Operation: "/api.v1.Reports/Export"}).AssertError(503, busyReason)
+	tester.Run(httptesting.Request{Operation: "/api.v1.Orders/Create"}).AssertNoError().AssertHandlerCalled(true)
+	assert.Equal(t, float64(1), testutil.ToFloat64(h.resourceGuardShed.WithLabelValues("/api.v1.Search/Query", resourceGoroutines)))
+
+	goroutines = 95
+	g.sample()
+	assert.Equal(t, resourceGoroutines, g.current(), "shedding continues until usage is below 90% of the threshold")
+
+	goroutines = 50
+	heap = 2000
+	g.sample()
+	assert.Equal(t, resourceHeap, g.current())
+
+	heap = 800
+	g.sample()
+	assert.Empty(t, g.current())
+	tester.Run(httptesting.Request{Operation: "/api.v1.Search/Query"}).AssertNoError()
+}
+
+func TestResourceGuard_ReadsRuntimeUsage(t *testing.T) {
+	heap, goroutines := readRuntimeUsage()
+	assert.Positive(t, heap)
+	assert.Positive(t, goroutines)
+}
+
+func TestValidateResourceGuardConfig(t *testing.T) {
+	queues := []*conf.AdmissionQueueConfig{{Name: "reports"}}
+	assert.NoError(t, validateResourceGuardConfig(nil, nil))
+	assert.Nil(t, newResourceGuard(&conf.ResourceGuardConfig{}, nil))
+	assert.Error(t, validateResourceGuardConfig(&conf.ResourceGuardConfig{Enabled: true, ShedOperations: []string{"*"}}, nil))
+	assert.Error(t, validateResourceGuardConfig(&conf.ResourceGuardConfig{Enabled: true, MaxGoroutines: 1}, nil))
+	assert.Error(t, validateResourceGuardConfig(&conf.ResourceGuardConfig{Enabled: true, MaxGoroutines: 1,
+		ShedAdmissionGroups: []string{"exports"}}, queues))
+	assert.NoError(t, validateResourceGuardConfig(&conf.ResourceGuardConfig{Enabled: true, MaxHeapBytes: 1,
+		ShedAdmissionGroups: []string{"reports"}}, queues))
+}
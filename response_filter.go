@@ -0,0 +1,145 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	nhttp "net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+)
+
+// EncodedResponse is a successful reply after encoding, before it is written. Filters may change any field.
+type EncodedResponse struct {
+	// Status is the status set by the encoder, or 0 when the handler's status is kept.
+	Status int
+	// Header is the reply header; changes are written with the response.
+	Header nhttp.Header
+	// Body is the encoded body.
+	Body []byte
+}
+
+// ResponseFilter transforms an encoded response. An error fails the request and is written by the error
+// encoder instead.
+type ResponseFilter func(r *nhttp.Request, res *EncodedResponse) error
+
+// registeredResponseFilter is a filter and the operations it applies to.
+type registeredResponseFilter struct {
+	pattern string
+	filter  ResponseFilter
+}
+
+// RegisterResponseFilter adds a filter for operations matching pattern: an exact operation, a prefix ending
+// in "*", or "" for every route. Filters run in registration order on successful replies after encoding,
+// so body transformations work with any encoder without forking ResponseEncoder.
+func (h *ServiceHttp) RegisterResponseFilter(pattern string, filter ResponseFilter) error {
+	if filter == nil {
+		return fmt.Errorf("response filter for %q is nil", pattern)
+	}
+	h.responseFilterMu.Lock()
+	defer h.responseFilterMu.Unlock()
+	h.responseFilters = append(h.responseFilters, registeredResponseFilter{pattern: strings.TrimSpace(pattern), filter: filter})
+	return nil
+}
+
+// matchingResponseFilters returns the filters registered for operation.
+func (h *ServiceHttp) matchingResponseFilters(operation string) []ResponseFilter {
+	h.responseFilterMu.RLock()
+	defer h.responseFilterMu.RUnlock()
+	var filters []ResponseFilter
+	for _, f := range h.responseFilters {
+		if wildcardMatches(f.pattern, operation) {
+			filters = append(filters, f.filter)
+		}
+	}
+	return filters
+}
+
+// bufferedResponseWriter captures the status and body written by an encoder. Headers go to the real writer,
+// which has not sent them yet.
+type bufferedResponseWriter struct {
+	w      nhttp.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponseWriter) Header() nhttp.Header         { return b.w.Header() }
+func (b *bufferedResponseWriter) WriteHeader(status int)       { b.status = status }
+func (b *bufferedResponseWriter) Write(p []byte) (int, error)  { return b.body.Write(p) }
+func (b *bufferedResponseWriter) Unwrap() nhttp.ResponseWriter { return b.w }
+
+// withResponseFilters runs the registered filters of the request's operation on the output of encode.
+// Requests without matching filters are encoded straight to the client.
+func (h *ServiceHttp) withResponseFilters(encode http.EncodeResponseFunc) http.EncodeResponseFunc {
+	return func(w http.ResponseWriter, r *http.Request, data any) error {
+		var operation string
+		if tr, ok := transport.FromServerContext(r.Context()); ok {
+			operation = tr.Operation()
+		}
+		filters := h.matchingResponseFilters(operation)
+		if len(filters) == 0 {
+			return encode(w, r, data)
+		}
+		buf := &bufferedResponseWriter{w: w}
+		if err := encode(buf, r, data); err != nil {
+			return err
+		}
+		res := &EncodedResponse{Status: buf.status, Header: w.Header(), Body: buf.body.Bytes()}
+		for _, filter := range filters {
+			if err := filter(r, res); err != nil {
+				return err
+			}
+		}
+		if res.Header.Get("Content-Length") != "" {
+			res.Header.Set("Content-Length", strconv.Itoa(len(res.Body)))
+		}
+		if res.Status != 0 {
+			w.WriteHeader(res.Status)
+		}
+		_, err := w.Write(res.Body)
+		return err
+	}
+}
+
+// StripJSONNulls is a ResponseFilter that removes null object fields from JSON bodies.
+func StripJSONNulls(_ *nhttp.Request, res *EncodedResponse) error {
+	if ct := res.Header.Get("Content-Type"); ct != "" && !strings.Contains(ct, "json") {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(res.Body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		// Not JSON: leave the body alone.
+		return nil
+	}
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(stripNulls(v)); err != nil {
+		return err
+	}
+	res.Body = bytes.TrimSuffix(out.Bytes(), []byte("\n"))
+	return nil
+}
+
+func stripNulls(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, child := range t {
+			if child == nil {
+				delete(t, k)
+				continue
+			}
+			t[k] = stripNulls(child)
+		}
+	case []any:
+		for i, child := range t {
+			t[i] = stripNulls(child)
+		}
+	}
+	return v
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	nhttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseFilters(t *testing.T) {
+	h := &ServiceHttp{}
+	h.server = http.NewServer(
+		http.ResponseEncoder(h.withResponseFilters(ResponseEncoder)),
+		http.ErrorEncoder(h.enhancedErrorEncoder),
+	)
+	serve := func(operation string, status int) http.HandlerFunc {
+		return func(ctx http.Context) error {
+			http.SetOperation(ctx, operation)
+			return ctx.Result(status, map[string]any{"name": "alice", "nickname": nil, "tags": []any{map[string]any{"x": nil}}})
+		}
+	}
+	route := h.server.Route("/")
+	route.GET("/users", serve("/api.v1.Users/List", nhttp.StatusOK))
+	route.POST("/users", serve("/api.v1.Users/Create", nhttp.StatusCreated))
+	route.GET("/orders", serve("/api.v1.Orders/List", nhttp.StatusOK))
+
+	require.Error(t, h.RegisterResponseFilter("", nil))
+	require.NoError(t, h.RegisterResponseFilter("/api.v1.Users/*", StripJSONNulls))
+	require.NoError(t, h.RegisterResponseFilter("/api.v1.Users/*", func(r *nhttp.Request, res *EncodedResponse) error {
+		res.Header.Set("X-Body-Bytes", "filtered")
+		return nil
+	}))
+	require.NoError(t, h.RegisterResponseFilter("/api.v1.Orders/List", func(*nhttp.Request, *EncodedResponse) error {
+		return errors.Forbidden("ENCRYPTION_KEY_MISSING", "")
+	}))
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.server.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		return rec
+	}
+
+	rec := do(nhttp.MethodGet, "/users")
+	assert.Equal(t, nhttp.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"code":200,"data":{"name":"alice","tags":[{}]}}`, rec.Body.String())
+	assert.Equal(t, "filtered", rec.Header().Get("X-Body-Bytes"))
+
+	rec = do(nhttp.MethodPost, "/users")
+	assert.Equal(t, nhttp.StatusCreated, rec.Code, "the handler's status is kept")
+
+	rec = do(nhttp.MethodGet, "/orders")
+	assert.JSONEq(t, `{"code":403}`, rec.Body.String())
+}
+
+func TestStripJSONNulls_LeavesNonJSONAlone(t *testing.T) {
+	res := &EncodedResponse{Header: nhttp.Header{"Content-Type": {"text/plain"}}, Body: []byte(`{"a":null}`)}
+	require.NoError(t, StripJSONNulls(nil, res))
+	assert.Equal(t, `{"a":null}`, string(res.Body))
+
+	res = &EncodedResponse{Header: nhttp.Header{}, Body: []byte(`<html>`)}
+	require.NoError(t, StripJSONNulls(nil, res))
+	assert.Equal(t, `<html>`, string(res.Body))
+
+	res = &EncodedResponse{Header: nhttp.Header{}, Body: []byte(`{"a":1.50,"b":null,"c":"<x>"}`)}
+	require.NoError(t, StripJSONNulls(nil, res))
+	assert.Equal(t, `{"a":1.50,"c":"<x>"}`, string(res.Body))
+}
@@ -0,0 +1,214 @@
+package http
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+)
+
+const (
+	// ResponseSignatureHeader is the default reply header carrying response signatures.
+	ResponseSignatureHeader = "X-Response-Signature"
+
+	// SignatureAlgorithmHMACSHA256 and SignatureAlgorithmEd25519 are the response signature algorithms.
+	SignatureAlgorithmHMACSHA256 = "hmac-sha256"
+	SignatureAlgorithmEd25519    = "ed25519"
+
+	minResponseSigningHMACKeyBytes = 32
+)
+
+// ResponseSignature is a parsed response signature header.
+type ResponseSignature struct {
+	KeyID     string
+	Algorithm string
+	Signature []byte
+}
+
+// String renders the signature as a header value: keyid="...", alg="...", sig="<base64>". keyid is omitted
+// when empty.
+func (s ResponseSignature) String() string {
+	var b strings.Builder
+	if s.KeyID != "" {
+		b.WriteString("keyid=" + strconv.Quote(s.KeyID) + ", ")
+	}
+	b.WriteString("alg=" + strconv.Quote(s.Algorithm))
+	b.WriteString(", sig=" + strconv.Quote(base64.StdEncoding.EncodeToString(s.Signature)))
+	return b.String()
+}
+
+// ParseResponseSignature parses a response signature header value.
+func ParseResponseSignature(value string) (ResponseSignature, error) {
+	var s ResponseSignature
+	for _, part := range strings.Split(value, ",") {
+		name, quoted, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return ResponseSignature{}, fmt.Errorf("malformed signature parameter %q", part)
+		}
+		v, err := strconv.Unquote(quoted)
+		if err != nil {
+			return ResponseSignature{}, fmt.Errorf("malformed signature parameter %q", part)
+		}
+		switch name {
+		case "keyid":
+			s.KeyID = v
+		case "alg":
+			s.Algorithm = v
+		case "sig":
+			if s.Signature, err = base64.StdEncoding.DecodeString(v); err != nil {
+				return ResponseSignature{}, fmt.Errorf("malformed signature: %w", err)
+			}
+		}
+	}
+	if s.Algorithm == "" || len(s.Signature) == 0 {
+		return ResponseSignature{}, fmt.Errorf("signature requires alg and sig")
+	}
+	return s, nil
+}
+
+// Verify checks the signature over body. key is the HMAC key as []byte for hmac-sha256, or an
+// ed25519.PublicKey for ed25519.
+func (s ResponseSignature) Verify(body []byte, key any) error {
+	switch s.Algorithm {
+	case SignatureAlgorithmHMACSHA256:
+		secret, ok := key.([]byte)
+		if !ok {
+			return fmt.Errorf("hmac-sha256 signatures need a []byte key, got %T", key)
+		}
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(body)
+		if !hmac.Equal(mac.Sum(nil), s.Signature) {
+			return fmt.Errorf("signature mismatch")
+		}
+	case SignatureAlgorithmEd25519:
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return fmt.Errorf("ed25519 signatures need an ed25519.PublicKey, got %T", key)
+		}
+		if !ed25519.Verify(pub, body, s.Signature) {
+			return fmt.Errorf("signature mismatch")
+		}
+	default:
+		return fmt.Errorf("unknown signature algorithm %q", s.Algorithm)
+	}
+	return nil
+}
+
+// responseSigningPolicy is the resolved ResponseSigningConfig.
+type responseSigningPolicy struct {
+	header string
+	keyID  string
+	alg    string
+	sign   func(body []byte) []byte
+	exempt []string
+}
+
+// newResponseSigningPolicy returns nil when signing is disabled or misconfigured; validation reports the latter.
+func newResponseSigningPolicy(cfg *conf.ResponseSigningConfig) *responseSigningPolicy {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	sign, alg, err := responseSigner(cfg)
+	if err != nil {
+		return nil
+	}
+	p := &responseSigningPolicy{
+		header: ResponseSignatureHeader,
+		keyID:  strings.TrimSpace(cfg.GetKeyId()),
+		alg:    alg,
+		sign:   sign,
+	}
+	if v := strings.TrimSpace(cfg.GetHeader()); v != "" {
+		p.header = v
+	}
+	for _, op := range cfg.GetExemptOperations() {
+		if op = strings.TrimSpace(op); op != "" {
+			p.exempt = append(p.exempt, op)
+		}
+	}
+	return p
+}
+
+// responseSigner returns the signing function of the configured algorithm and key.
+func responseSigner(cfg *conf.ResponseSigningConfig) (func([]byte) []byte, string, error) {
+	alg := strings.ToLower(strings.TrimSpace(cfg.GetAlgorithm()))
+	if alg == "" {
+		alg = SignatureAlgorithmHMACSHA256
+	}
+	switch alg {
+	case SignatureAlgorithmHMACSHA256:
+		secret := []byte(cfg.GetKey())
+		if len(secret) < minResponseSigningHMACKeyBytes {
+			return nil, "", fmt.Errorf("hmac-sha256 key must be at least %d bytes", minResponseSigningHMACKeyBytes)
+		}
+		return func(body []byte) []byte {
+			mac := hmac.New(sha256.New, secret)
+			mac.Write(body)
+			return mac.Sum(nil)
+		}, alg, nil
+	case SignatureAlgorithmEd25519:
+		raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(cfg.GetKey()))
+		if err != nil {
+			return nil, "", fmt.Errorf("ed25519 key is not base64: %w", err)
+		}
+		var priv ed25519.PrivateKey
+		switch len(raw) {
+		case ed25519.SeedSize:
+			priv = ed25519.NewKeyFromSeed(raw)
+		case ed25519.PrivateKeySize:
+			priv = ed25519.PrivateKey(raw)
+		default:
+			return nil, "", fmt.Errorf("ed25519 key must be a %d-byte seed or a %d-byte private key",
+				ed25519.SeedSize, ed25519.PrivateKeySize)
+		}
+		return func(body []byte) []byte { return ed25519.Sign(priv, body) }, alg, nil
+	}
+	return nil, "", fmt.Errorf("unknown algorithm %q, valid options: %s, %s", alg,
+		SignatureAlgorithmHMACSHA256, SignatureAlgorithmEd25519)
+}
+
+// withResponseSigning adds the signature of the body written by encode to the reply headers.
+func withResponseSigning(p *responseSigningPolicy, encode http.EncodeResponseFunc) http.EncodeResponseFunc {
+	return func(w http.ResponseWriter, r *http.Request, data any) error {
+		var operation string
+		if tr, ok := transport.FromServerContext(r.Context()); ok {
+			operation = tr.Operation()
+		}
+		if routeMatchesAny(p.exempt, operation, r.URL.Path) {
+			return encode(w, r, data)
+		}
+		buf := &bufferedResponseWriter{w: w}
+		if err := encode(buf, r, data); err != nil {
+			return err
+		}
+		body := buf.body.Bytes()
+		sig := ResponseSignature{KeyID: p.keyID, Algorithm: p.alg, Signature: p.sign(body)}
+		w.Header().Set(p.header, sig.String())
+		if buf.status != 0 {
+			w.WriteHeader(buf.status)
+		}
+		_, err := w.Write(body)
+		return err
+	}
+}
+
+// validateResponseSigningConfig checks the algorithm, the key and the header name.
+func validateResponseSigningConfig(cfg *conf.ResponseSigningConfig) error {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	if _, _, err := responseSigner(cfg); err != nil {
+		return err
+	}
+	if strings.ContainsAny(cfg.GetHeader(), " :\t") {
+		return fmt.Errorf("invalid header %q", cfg.GetHeader())
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	nhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSigningSecret = "0123456789abcdef0123456789abcdef"
+
+func signedServer(t *testing.T, cfg *conf.ResponseSigningConfig) func(path string) *httptest.ResponseRecorder {
+	t.Helper()
+	policy := newResponseSigningPolicy(cfg)
+	require.NotNil(t, policy)
+	srv := http.NewServer(http.ResponseEncoder(withResponseSigning(policy, ResponseEncoder)))
+	serve := func(operation string) http.HandlerFunc {
+		return func(ctx http.Context) error {
+			http.SetOperation(ctx, operation)
+			return ctx.Result(nhttp.StatusOK, map[string]string{"id": "42"})
+		}
+	}
+	route := srv.Route("/")
+	route.GET("/orders", serve("/api.v1.Orders/Get"))
+	route.GET("/health", serve("/api.v1.Health/Check"))
+	return func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(nhttp.MethodGet, path, nil))
+		return rec
+	}
+}
+
+func TestResponseSigning_HMAC(t *testing.T) {
+	do := signedServer(t, &conf.ResponseSigningConfig{
+		Enabled:          true,
+		Key:              testSigningSecret,
+		KeyId:            "2026-10",
+		ExemptOperations: []string{"/api.v1.Health/*"},
+	})
+
+	rec := do("/orders")
+	header := rec.Header().Get(ResponseSignatureHeader)
+	require.True(t, strings.HasPrefix(header, `keyid="2026-10", alg="hmac-sha256", sig="`), header)
+	sig, err := ParseResponseSignature(header)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10", sig.KeyID)
+	require.NoError(t, sig.Verify(rec.Body.Bytes(), []byte(testSigningSecret)))
+	assert.ErrorContains(t, sig.Verify(append(rec.Body.Bytes(), ' '), []byte(testSigningSecret)), "mismatch")
+	assert.ErrorContains(t, sig.Verify(rec.Body.Bytes(), "secret"), "[]byte key")
+
+	assert.Empty(t, do("/health").Header().Get(ResponseSignatureHeader), "exempt operations")
+}
+
+func TestResponseSigning_Ed25519(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	do := signedServer(t, &conf.ResponseSigningConfig{
+		Enabled:   true,
+		Algorithm: "Ed25519",
+		Key:       base64.StdEncoding.EncodeToString(priv.Seed()),
+		Header:    "Signature",
+	})
+
+	rec := do("/orders")
+	sig, err := ParseResponseSignature(rec.Header().Get("Signature"))
+	require.NoError(t, err)
+	assert.Empty(t, sig.KeyID)
+	require.NoError(t, sig.Verify(rec.Body.Bytes(), pub))
+	other, _, _ := ed25519.GenerateKey(nil)
+	assert.Error(t, sig.Verify(rec.Body.Bytes(), other))
+}
+
+func TestParseResponseSignature(t *testing.T) {
+	_, err := ParseResponseSignature(`alg="hmac-sha256"`)
+	assert.ErrorContains(t, err, "requires alg and sig")
+	_, err = ParseResponseSignature(`alg=hmac-sha256, sig="AA=="`)
+	assert.ErrorContains(t, err, "malformed")
+	_, err = ParseResponseSignature(`alg="ed25519", sig="***"`)
+	assert.ErrorContains(t, err, "malformed signature")
+}
+
+func TestValidateResponseSigningConfig(t *testing.T) {
+	assert.Nil(t, newResponseSigningPolicy(&conf.ResponseSigningConfig{Enabled: true, Key: "short"}))
+	require.NoError(t, validateResponseSigningConfig(nil))
+	require.NoError(t, validateResponseSigningConfig(&conf.ResponseSigningConfig{Enabled: true, Key: testSigningSecret}))
+	assert.ErrorContains(t, validateResponseSigningConfig(&conf.ResponseSigningConfig{Enabled: true, Key: "short"}), "at least 32 bytes")
+	assert.ErrorContains(t, validateResponseSigningConfig(&conf.ResponseSigningConfig{
+		Enabled: true, Algorithm: "rsa", Key: testSigningSecret}), "unknown algorithm")
+	assert.ErrorContains(t, validateResponseSigningConfig(&conf.ResponseSigningConfig{
+		Enabled: true, Algorithm: "ed25519", Key: base64.StdEncoding.EncodeToString([]byte("short"))}), "32-byte seed")
+	assert.ErrorContains(t, validateResponseSigningConfig(&conf.ResponseSigningConfig{
+		Enabled: true, Key: testSigningSecret, Header: "X Signature"}), "invalid header")
+}
@@ -0,0 +1,146 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	nhttp "net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+const (
+	// ResponseTooLargeReason is the reason of errors replacing replies over response.size_limit.max_bytes.
+	ResponseTooLargeReason = "RESPONSE_TOO_LARGE"
+
+	defaultResponseTooLargeCode = 413
+)
+
+// responseSizePolicy is the resolved ResponseSizeLimitConfig.
+type responseSizePolicy struct {
+	maxBytes int64
+	replace  bool
+	code     int32
+	exempt   []string
+}
+
+// newResponseSizePolicy returns nil when no limit is configured.
+func newResponseSizePolicy(cfg *conf.ResponseSizeLimitConfig) *responseSizePolicy {
+	if cfg == nil || cfg.MaxBytes <= 0 {
+		return nil
+	}
+	p := &responseSizePolicy{maxBytes: cfg.MaxBytes, replace: cfg.Replace, code: defaultResponseTooLargeCode}
+	if cfg.Code != 0 {
+		p.code = cfg.Code
+	}
+	for _, op := range cfg.ExemptOperations {
+		if op = strings.TrimSpace(op); op != "" {
+			p.exempt = append(p.exempt, op)
+		}
+	}
+	return p
+}
+
+func (p *responseSizePolicy) exempted(operation string) bool {
+	for _, pattern := range p.exempt {
+		if wildcardMatches(pattern, operation) {
+			return true
+		}
+	}
+	return false
+}
+
+// countingResponseWriter counts the body bytes written through it.
+type countingResponseWriter struct {
+	nhttp.ResponseWriter
+	n int64
+}
+
+func (c *countingResponseWriter) Write(p []byte) (int, error) {
+	n, err := c.ResponseWriter.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *countingResponseWriter) Unwrap() nhttp.ResponseWriter { return c.ResponseWriter }
+
+// withResponseSizeLimit reports successful replies of encode larger than the policy's limit. With replace, the
+// reply is buffered and an oversized one is failed with RESPONSE_TOO_LARGE; otherwise it is streamed and
+// reported after it is written.
+func (h *ServiceHttp) withResponseSizeLimit(p *responseSizePolicy, encode http.EncodeResponseFunc) http.EncodeResponseFunc {
+	return func(w http.ResponseWriter, r *http.Request, data any) error {
+		var operation string
+		if tr, ok := transport.FromServerContext(r.Context()); ok {
+			operation = tr.Operation()
+		}
+		if p.exempted(operation) {
+			return encode(w, r, data)
+		}
+		if !p.replace {
+			cw := &countingResponseWriter{ResponseWriter: w}
+			err := encode(cw, r, data)
+			if cw.n > p.maxBytes {
+				h.reportOversizedResponse(r.Context(), p, operation, cw.n, "logged")
+			}
+			return err
+		}
+
+		buf := &bufferedResponseWriter{w: w}
+		if err := encode(buf, r, data); err != nil {
+			return err
+		}
+		if size := int64(buf.body.Len()); size > p.maxBytes {
+			h.reportOversizedResponse(r.Context(), p, operation, size, "replaced")
+			w.Header().Del("Content-Length")
+			return errors.New(int(p.code), ResponseTooLargeReason, "response exceeds the size limit").
+				WithMetadata(map[string]string{"max_bytes": strconv.FormatInt(p.maxBytes, 10)})
+		}
+		if buf.status != 0 {
+			w.WriteHeader(buf.status)
+		}
+		_, err := w.Write(buf.body.Bytes())
+		return err
+	}
+}
+
+// reportOversizedResponse logs and counts a reply over the size limit.
+func (h *ServiceHttp) reportOversizedResponse(ctx context.Context, p *responseSizePolicy, operation string, size int64, action string) {
+	if h.oversizedResponses != nil {
+		h.oversizedResponses.WithLabelValues(operation, action).Inc()
+	}
+	log.WarnwCtx(ctx, "msg", "[HTTP Response Size]", "api", operation, "caller", h.requestCaller(ctx),
+		"size", size, "max_bytes", p.maxBytes, "action", action)
+}
+
+// requestCaller identifies who sent the request for logs: the caller service when caller metrics are enabled,
+// otherwise the client IP.
+func (h *ServiceHttp) requestCaller(ctx context.Context) string {
+	if l := h.monitoringSnapshotOrDefault().callers; l != nil {
+		if caller := l.callerIdentity(ctx); caller != "" {
+			return caller
+		}
+	}
+	if tr, ok := transport.FromServerContext(ctx); ok {
+		return getClientIP(ctx, tr.RequestHeader())
+	}
+	return ""
+}
+
+// validateResponseSizeLimitConfig rejects negative limits and reserved error codes.
+func validateResponseSizeLimitConfig(cfg *conf.ResponseSizeLimitConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	if cfg.MaxBytes < 0 {
+		return fmt.Errorf("max_bytes must not be negative")
+	}
+	if cfg.Code < 0 || cfg.Code == 200 || cfg.Code == BodyCodeSystemFailure {
+		return fmt.Errorf("code %d is reserved", cfg.Code)
+	}
+	return nil
+}
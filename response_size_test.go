@@ -0,0 +1,73 @@
+package http
+
+import (
+	nhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseSizeLimit(t *testing.T) {
+	for _, replace := range []bool{false, true} {
+		h := &ServiceHttp{
+			oversizedResponses: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "oversized"}, []string{"route", "action"}),
+		}
+		policy := newResponseSizePolicy(&conf.ResponseSizeLimitConfig{
+			MaxBytes:         100,
+			Replace:          replace,
+			ExemptOperations: []string{"/api.v1.Reports/*"},
+		})
+		require.NotNil(t, policy)
+		h.server = http.NewServer(
+			http.ResponseEncoder(h.withResponseSizeLimit(policy, ResponseEncoder)),
+			http.ErrorEncoder(h.enhancedErrorEncoder),
+		)
+		serve := func(operation string, n int) http.HandlerFunc {
+			return func(ctx http.Context) error {
+				http.SetOperation(ctx, operation)
+				return ctx.Result(nhttp.StatusOK, map[string]string{"items": strings.Repeat("x", n)})
+			}
+		}
+		route := h.server.Route("/")
+		route.GET("/small", serve("/api.v1.Items/Get", 10))
+		route.GET("/large", serve("/api.v1.Items/List", 500))
+		route.GET("/export", serve("/api.v1.Reports/Export", 500))
+
+		do := func(path string) *httptest.ResponseRecorder {
+			rec := httptest.NewRecorder()
+			h.server.ServeHTTP(rec, httptest.NewRequest(nhttp.MethodGet, path, nil))
+			return rec
+		}
+
+		rec := do("/small")
+		assert.JSONEq(t, `{"code":200,"data":{"items":"xxxxxxxxxx"}}`, rec.Body.String())
+
+		rec = do("/large")
+		if replace {
+			assert.JSONEq(t, `{"code":413}`, rec.Body.String())
+			assert.Equal(t, float64(1), testutil.ToFloat64(h.oversizedResponses.WithLabelValues("/api.v1.Items/List", "replaced")))
+		} else {
+			assert.Equal(t, nhttp.StatusOK, rec.Code)
+			assert.Greater(t, rec.Body.Len(), 500)
+			assert.Equal(t, float64(1), testutil.ToFloat64(h.oversizedResponses.WithLabelValues("/api.v1.Items/List", "logged")))
+		}
+
+		rec = do("/export")
+		assert.Equal(t, nhttp.StatusOK, rec.Code, "exempt operations are not limited")
+		assert.Equal(t, 1, testutil.CollectAndCount(h.oversizedResponses))
+	}
+}
+
+func TestValidateResponseSizeLimitConfig(t *testing.T) {
+	assert.Nil(t, newResponseSizePolicy(&conf.ResponseSizeLimitConfig{}))
+	assert.NoError(t, validateResponseSizeLimitConfig(&conf.ResponseSizeLimitConfig{MaxBytes: 1 << 20}))
+	assert.Error(t, validateResponseSizeLimitConfig(&conf.ResponseSizeLimitConfig{MaxBytes: -1}))
+	assert.Error(t, validateResponseSizeLimitConfig(&conf.ResponseSizeLimitConfig{MaxBytes: 1, Code: 200}))
+}
@@ -0,0 +1,167 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/conf"
+)
+
+const (
+	// RetryAttemptHeader carries the 1-based attempt number of a request.
+	RetryAttemptHeader = "X-Retry-Attempt"
+	// RetryMaxAttemptsHeader carries the caller's attempt budget; the server echoes its effective budget.
+	RetryMaxAttemptsHeader = "X-Retry-Max-Attempts"
+
+	// retryBudgetExceededReason is the Kratos error reason for retries beyond the attempt budget.
+	retryBudgetExceededReason = "RETRY_BUDGET_EXCEEDED"
+
+	attemptFirst = "first"
+	attemptRetry = "retry"
+)
+
+// retryAttemptKey stores the retryAttempt parsed from the inbound request in the request context.
+type retryAttemptKey struct{}
+
+// outboundRetryAttemptKey stores the retryAttempt set with WithRetryAttempt for outbound calls. It is separate
+// from retryAttemptKey so calls made while serving a retried request do not inherit its attempt.
+type outboundRetryAttemptKey struct{}
+
+type retryAttempt struct {
+	attempt     int
+	maxAttempts int
+}
+
+// RetryAttemptFromContext returns the attempt number and the caller's attempt budget (0 when not
+// declared) of the current request. ok is false when retry budgets are disabled.
+func RetryAttemptFromContext(ctx context.Context) (attempt, maxAttempts int, ok bool) {
+	a, ok := ctx.Value(retryAttemptKey{}).(retryAttempt)
+	return a.attempt, a.maxAttempts, ok
+}
+
+// WithRetryAttempt marks an outbound call as the given attempt out of maxAttempts, for the
+// RetryBudgetPropagation client middleware to emit.
+func WithRetryAttempt(ctx context.Context, attempt, maxAttempts int) context.Context {
+	return context.WithValue(ctx, outboundRetryAttemptKey{}, retryAttempt{attempt: attempt, maxAttempts: maxAttempts})
+}
+
+// retryBudgetPolicy is the immutable view of RetryBudgetConfig used by the middleware.
+type retryBudgetPolicy struct {
+	maxAttempts       int
+	attemptHeader     string
+	maxAttemptsHeader string
+}
+
+func newRetryBudgetPolicy(cfg *conf.RetryBudgetConfig) *retryBudgetPolicy {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	p := &retryBudgetPolicy{
+		maxAttempts:       int(cfg.MaxAttempts),
+		attemptHeader:     RetryAttemptHeader,
+		maxAttemptsHeader: RetryMaxAttemptsHeader,
+	}
+	if v := strings.TrimSpace(cfg.AttemptHeader); v != "" {
+		p.attemptHeader = v
+	}
+	if v := strings.TrimSpace(cfg.MaxAttemptsHeader); v != "" {
+		p.maxAttemptsHeader = v
+	}
+	return p
+}
+
+// parse reads the attempt headers; missing or malformed values count as a first attempt without a budget.
+func (p *retryBudgetPolicy) parse(header transport.Header) retryAttempt {
+	a := retryAttempt{attempt: 1}
+	if n, err := strconv.Atoi(strings.TrimSpace(header.Get(p.attemptHeader))); err == nil && n > 1 {
+		a.attempt = n
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(header.Get(p.maxAttemptsHeader))); err == nil && n > 0 {
+		a.maxAttempts = n
+	}
+	return a
+}
+
+// budget is the effective attempt limit: the smaller of the server cap and the caller's budget, 0 for none.
+func (p *retryBudgetPolicy) budget(a retryAttempt) int {
+	switch {
+	case p.maxAttempts > 0 && a.maxAttempts > 0:
+		return min(p.maxAttempts, a.maxAttempts)
+	case p.maxAttempts > 0:
+		return p.maxAttempts
+	default:
+		return a.maxAttempts
+	}
+}
+
+// retryBudgetMiddleware rejects attempts beyond the budget and records handler latency split by first
+// attempt and retry.
+func (h *ServiceHttp) retryBudgetMiddleware(p *retryBudgetPolicy) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			tr, ok := transport.FromServerContext(ctx)
+			if !ok {
+				return handler(ctx, req)
+			}
+			a := p.parse(tr.RequestHeader())
+			_, route := requestMetadata(ctx)
+			budget := p.budget(a)
+			if budget > 0 {
+				tr.ReplyHeader().Set(p.maxAttemptsHeader, strconv.Itoa(budget))
+			}
+			if budget > 0 && a.attempt > budget {
+				if h.retryBudgetRejections != nil {
+					h.retryBudgetRejections.WithLabelValues(route).Inc()
+				}
+				return nil, errors.New(429, retryBudgetExceededReason,
+					fmt.Sprintf("attempt %d exceeds the retry budget of %d attempts", a.attempt, budget))
+			}
+
+			start := time.Now()
+			reply, err := handler(context.WithValue(ctx, retryAttemptKey{}, a), req)
+			if h.attemptRequestDuration != nil {
+				kind := attemptFirst
+				if a.attempt > 1 {
+					kind = attemptRetry
+				}
+				h.attemptRequestDuration.WithLabelValues(route, kind).Observe(time.Since(start).Seconds())
+			}
+			return reply, err
+		}
+	}
+}
+
+// RetryBudgetPropagation is a client middleware that emits the attempt headers set with WithRetryAttempt. The
+// attempt of the request being served is not propagated.
+func RetryBudgetPropagation() middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			if tr, ok := transport.FromClientContext(ctx); ok {
+				if a, ok := ctx.Value(outboundRetryAttemptKey{}).(retryAttempt); ok && a.attempt > 0 {
+					tr.RequestHeader().Set(RetryAttemptHeader, strconv.Itoa(a.attempt))
+					if a.maxAttempts > 0 {
+						tr.RequestHeader().Set(RetryMaxAttemptsHeader, strconv.Itoa(a.maxAttempts))
+					}
+				}
+			}
+			return handler(ctx, req)
+		}
+	}
+}
+
+// validateRetryBudgetConfig rejects a negative attempt cap.
+func validateRetryBudgetConfig(cfg *conf.RetryBudgetConfig) error {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	if cfg.MaxAttempts < 0 {
+		return fmt.Errorf("max attempts cannot be negative")
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryBudgetPolicy_Budget(t *testing.T) {
+	p := newRetryBudgetPolicy(&conf.RetryBudgetConfig{Enabled: true, MaxAttempts: 3})
+	assert.Equal(t, 3, p.budget(retryAttempt{attempt: 1}))
+	assert.Equal(t, 2, p.budget(retryAttempt{attempt: 1, maxAttempts: 2}))
+	assert.Equal(t, 3, p.budget(retryAttempt{attempt: 1, maxAttempts: 5}))
+
+	p = newRetryBudgetPolicy(&conf.RetryBudgetConfig{Enabled: true})
+	assert.Equal(t, 0, p.budget(retryAttempt{attempt: 4}))
+	assert.Equal(t, 5, p.budget(retryAttempt{attempt: 4, maxAttempts: 5}))
+
+	assert.Nil(t, newRetryBudgetPolicy(&conf.RetryBudgetConfig{}))
+}
+
+func TestRetryBudgetMiddleware(t *testing.T) {
+	svc := NewServiceHttp()
+	svc.initMetrics()
+	mw := svc.retryBudgetMiddleware(newRetryBudgetPolicy(&conf.RetryBudgetConfig{Enabled: true, MaxAttempts: 3}))
+	const route = "/api.v1.RetryBudget/Get"
+
+	call := func(headers map[string]string) (*fakeTransport, int, error) {
+		tr := newFakeTransport(route, headers)
+		var seen int
+		_, err := mw(func(ctx context.Context, _ any) (any, error) {
+			seen, _, _ = RetryAttemptFromContext(ctx)
+			return "ok", nil
+		})(transport.NewServerContext(context.Background(), tr), nil)
+		return tr, seen, err
+	}
+
+	tr, attempt, err := call(nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempt)
+	assert.Equal(t, "3", tr.ReplyHeader().Get(RetryMaxAttemptsHeader))
+
+	_, attempt, err = call(map[string]string{RetryAttemptHeader: "2", RetryMaxAttemptsHeader: "5"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempt)
+
+	_, _, err = call(map[string]string{RetryAttemptHeader: "3", RetryMaxAttemptsHeader: "2"})
+	require.Error(t, err, "the caller's smaller budget applies")
+	assert.Equal(t, 429, int(errors.Code(err)))
+	assert.Equal(t, retryBudgetExceededReason, errors.Reason(err))
+
+	_, _, err = call(map[string]string{RetryAttemptHeader: "4"})
+	require.Error(t, err, "the server cap applies")
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(svc.retryBudgetRejections.WithLabelValues(route)))
+	assert.Equal(t, uint64(1), histogramCount(t, svc.attemptRequestDuration, route, attemptFirst))
+	assert.Equal(t, uint64(1), histogramCount(t, svc.attemptRequestDuration, route, attemptRetry))
+}
+
+func histogramCount(t *testing.T, vec *prometheus.HistogramVec, labels ...string) uint64 {
+	t.Helper()
+	var m dto.Metric
+	require.NoError(t, vec.WithLabelValues(labels...).(prometheus.Metric).Write(&m))
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestRetryBudgetPropagation(t *testing.T) {
+	tr := newFakeTransport("/api.v1.RetryBudget/Get", nil)
+	ctx := WithRetryAttempt(transport.NewClientContext(context.Background(), tr), 2, 3)
+	_, err := RetryBudgetPropagation()(func(context.Context, any) (any, error) { return nil, nil })(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "2", tr.RequestHeader().Get(RetryAttemptHeader))
+	assert.Equal(t, "3", tr.RequestHeader().Get(RetryMaxAttemptsHeader))
+
+	// A call made while serving a retried request is a first attempt of its own.
+	served := context.WithValue(context.Background(), retryAttemptKey{}, retryAttempt{attempt: 3, maxAttempts: 3})
+	tr = newFakeTransport("/api.v1.RetryBudget/Get", nil)
+	_, err = RetryBudgetPropagation()(func(context.Context, any) (any, error) { return nil, nil })(
+		transport.NewClientContext(served, tr), nil)
+	require.NoError(t, err)
+	assert.Empty(t, tr.RequestHeader().Get(RetryAttemptHeader))
+	assert.Empty(t, tr.RequestHeader().Get(RetryMaxAttemptsHeader))
+	attempt, _, ok := RetryAttemptFromContext(WithRetryAttempt(context.Background(), 2, 3))
+	assert.False(t, ok, "outbound attempts are not the inbound one")
+	assert.Zero(t, attempt)
+}
+
+func TestValidateRetryBudgetConfig(t *testing.T) {
+	assert.NoError(t, validateRetryBudgetConfig(&conf.RetryBudgetConfig{Enabled: true, MaxAttempts: 3}))
+	assert.Error(t, validateRetryBudgetConfig(&conf.RetryBudgetConfig{Enabled: true, MaxAttempts: -1}))
+}
@@ -0,0 +1,211 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+const (
+	defaultRetryStormThreshold     = 20
+	defaultRetryStormWindow        = 10 * time.Second
+	defaultRetryStormBlockDuration = 30 * time.Second
+	defaultRetryStormMaxTracked    = 10000
+
+	// retryStormReason is the Kratos error reason for requests rejected while their client is blocked.
+	retryStormReason = "RETRY_STORM"
+
+	retryStormActionAlert = "alert"
+	retryStormActionBlock = "block"
+)
+
+// retryStormKey identifies a client failing on an operation.
+type retryStormKey struct {
+	client    string
+	operation string
+}
+
+// retryStormEntry counts the failures of a client and operation pair in a fixed window.
+type retryStormEntry struct {
+	windowStart  time.Time
+	failures     int
+	blockedUntil time.Time
+}
+
+// retryStormPolicy is the resolved RetryStormConfig together with the tracked pairs. It is built per
+// middleware chain, so a reconfiguration forgets running windows and blocks.
+type retryStormPolicy struct {
+	threshold     int
+	window        time.Duration
+	block         bool
+	blockDuration time.Duration
+	maxTracked    int
+	exempt        []string
+	now           func() time.Time
+
+	mu      sync.Mutex
+	entries map[retryStormKey]*retryStormEntry
+}
+
+// newRetryStormPolicy returns nil when retry storm detection is disabled.
+func newRetryStormPolicy(cfg *conf.RetryStormConfig) *retryStormPolicy {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	p := &retryStormPolicy{
+		threshold:     defaultRetryStormThreshold,
+		window:        defaultRetryStormWindow,
+		block:         cfg.Block,
+		blockDuration: defaultRetryStormBlockDuration,
+		maxTracked:    defaultRetryStormMaxTracked,
+		now:           time.Now,
+		entries:       make(map[retryStormKey]*retryStormEntry),
+	}
+	if cfg.Threshold > 0 {
+		p.threshold = int(cfg.Threshold)
+	}
+	if d := cfg.GetWindow().AsDuration(); d > 0 {
+		p.window = d
+	}
+	if d := cfg.GetBlockDuration().AsDuration(); d > 0 {
+		p.blockDuration = d
+	}
+	if cfg.MaxTracked > 0 {
+		p.maxTracked = int(cfg.MaxTracked)
+	}
+	for _, op := range cfg.GetExemptOperations() {
+		if op = strings.TrimSpace(op); op != "" {
+			p.exempt = append(p.exempt, op)
+		}
+	}
+	return p
+}
+
+// blocked reports whether k is rejected for a storm.
+func (p *retryStormPolicy) blocked(k retryStormKey) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	e := p.entries[k]
+	return e != nil && p.now().Before(e.blockedUntil)
+}
+
+// fail counts a failure of k and reports the failures of the window when they reach the threshold, once per
+// window. A storm that blocks starts a new window, so the client has to storm again to be blocked again.
+func (p *retryStormPolicy) fail(k retryStormKey) (failures int, storm bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	e := p.entries[k]
+	if e == nil {
+		if len(p.entries) >= p.maxTracked {
+			p.pruneLocked(now)
+			if len(p.entries) >= p.maxTracked {
+				return 0, false
+			}
+		}
+		e = &retryStormEntry{windowStart: now}
+		p.entries[k] = e
+	}
+	if now.Sub(e.windowStart) >= p.window {
+		e.windowStart, e.failures = now, 0
+	}
+	e.failures++
+	if e.failures != p.threshold {
+		return e.failures, false
+	}
+	if p.block {
+		e.blockedUntil = now.Add(p.blockDuration)
+		e.windowStart, e.failures = now, 0
+	}
+	return p.threshold, true
+}
+
+// pruneLocked drops pairs whose window and block have passed.
+func (p *retryStormPolicy) pruneLocked(now time.Time) {
+	for k, e := range p.entries {
+		if now.Sub(e.windowStart) >= p.window && !now.Before(e.blockedUntil) {
+			delete(p.entries, k)
+		}
+	}
+}
+
+// retryStormMiddleware counts handler failures per client and operation, logs one aggregated warning per
+// storm and, when blocking, rejects the storming client's requests to the operation with 429 RETRY_STORM.
+func (h *ServiceHttp) retryStormMiddleware(p *retryStormPolicy) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			tr, ok := transport.FromServerContext(ctx)
+			if !ok {
+				return handler(ctx, req)
+			}
+			path := ""
+			if r, ok := http.RequestFromServerContext(ctx); ok && r != nil {
+				path = r.URL.Path
+			}
+			if routeMatchesAny(p.exempt, tr.Operation(), path) {
+				return handler(ctx, req)
+			}
+			method, route := requestMetadata(ctx)
+			k := retryStormKey{client: h.requestCaller(ctx), operation: route}
+			if p.blocked(k) {
+				h.recordErrorMetric(method, route, "retry_storm")
+				if h.retryStormRejections != nil {
+					h.retryStormRejections.WithLabelValues(route).Inc()
+				}
+				return nil, errors.New(429, retryStormReason,
+					fmt.Sprintf("too many failing requests to %s; retry after %s", route, p.blockDuration))
+			}
+
+			reply, err := handler(ctx, req)
+			if err == nil {
+				return reply, nil
+			}
+			if failures, storm := p.fail(k); storm {
+				action := retryStormActionAlert
+				if p.block {
+					action = retryStormActionBlock
+				}
+				if h.retryStorms != nil {
+					h.retryStorms.WithLabelValues(route, action).Inc()
+				}
+				log.WarnwCtx(ctx, "msg", "[HTTP Retry Storm]", "api", route, "caller", k.client,
+					"failures", failures, "window", p.window.String(), "action", action, "reason", errors.Reason(err))
+			}
+			return reply, err
+		}
+	}
+}
+
+// validateRetryStormConfig rejects negative thresholds, durations and limits and empty exempt operations.
+func validateRetryStormConfig(cfg *conf.RetryStormConfig) error {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	if cfg.Threshold < 0 {
+		return fmt.Errorf("threshold cannot be negative")
+	}
+	if cfg.GetWindow().AsDuration() < 0 {
+		return fmt.Errorf("window cannot be negative")
+	}
+	if cfg.GetBlockDuration().AsDuration() < 0 {
+		return fmt.Errorf("block duration cannot be negative")
+	}
+	if cfg.MaxTracked < 0 {
+		return fmt.Errorf("max tracked cannot be negative")
+	}
+	for i, op := range cfg.GetExemptOperations() {
+		if strings.TrimSpace(op) == "" {
+			return fmt.Errorf("exempt_operations[%d] is empty", i)
+		}
+	}
+	return nil
+}
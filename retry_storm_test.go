@@ -0,0 +1,107 @@
+package http
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestRetryStormPolicy_Fail(t *testing.T) {
+	p := newRetryStormPolicy(&conf.RetryStormConfig{Enabled: true, Threshold: 3, Window: durationpb.New(time.Second), MaxTracked: 1})
+	now := time.Unix(1_700_000_000, 0)
+	p.now = func() time.Time { return now }
+	k := retryStormKey{client: "10.0.0.1", operation: "/api.v1.Orders/Create"}
+
+	for i := 1; i < 3; i++ {
+		n, storm := p.fail(k)
+		assert.Equal(t, i, n)
+		assert.False(t, storm)
+	}
+	n, storm := p.fail(k)
+	assert.Equal(t, 3, n)
+	assert.True(t, storm)
+	// A storm is reported once per window.
+	_, storm = p.fail(k)
+	assert.False(t, storm)
+	assert.False(t, p.blocked(k))
+
+	// The table is full until the window passes.
+	other := retryStormKey{client: "10.0.0.2", operation: k.operation}
+	n, _ = p.fail(other)
+	assert.Zero(t, n)
+	now = now.Add(time.Second)
+	n, _ = p.fail(other)
+	assert.Equal(t, 1, n)
+
+	assert.Nil(t, newRetryStormPolicy(&conf.RetryStormConfig{Threshold: 3}))
+}
+
+func TestRetryStormMiddleware(t *testing.T) {
+	h := &ServiceHttp{conf: &conf.Http{}}
+	h.retryStorms = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_retry_storms_total"}, []string{"route", "action"})
+	h.retryStormRejections = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_retry_storm_rejections_total"}, []string{"route"})
+	p := newRetryStormPolicy(&conf.RetryStormConfig{
+		Enabled: true, Threshold: 2, Block: true, BlockDuration: durationpb.New(time.Minute),
+		ExemptOperations: []string{"/api.v1.Health/*"},
+	})
+	now := time.Unix(1_700_000_000, 0)
+	p.now = func() time.Time { return now }
+	mw := h.retryStormMiddleware(p)
+
+	var calls int
+	call := func(operation, ip string, fail bool) error {
+		ctx := transport.NewServerContext(context.Background(), newFakeTransport(operation, map[string]string{"X-Real-IP": ip}))
+		_, err := mw(func(context.Context, any) (any, error) {
+			calls++
+			if fail {
+				return nil, kerrors.ServiceUnavailable("DB_DOWN", "")
+			}
+			return "ok", nil
+		})(ctx, nil)
+		return err
+	}
+	const route = "/api.v1.Orders/Create"
+
+	require.Error(t, call(route, "10.0.0.1", true))
+	require.NoError(t, call(route, "10.0.0.1", false))
+	require.Error(t, call(route, "10.0.0.1", true))
+	assert.Equal(t, 1.0, testutil.ToFloat64(h.retryStorms.WithLabelValues(route, retryStormActionBlock)))
+
+	// The storming client is rejected without running the handler; other clients and operations are not.
+	calls = 0
+	err := call(route, "10.0.0.1", false)
+	assert.Equal(t, 429, kerrors.Code(err))
+	assert.Equal(t, retryStormReason, kerrors.Reason(err))
+	assert.Zero(t, calls)
+	assert.Equal(t, 1.0, testutil.ToFloat64(h.retryStormRejections.WithLabelValues(route)))
+	require.NoError(t, call(route, "10.0.0.2", false))
+	require.NoError(t, call("/api.v1.Orders/List", "10.0.0.1", false))
+
+	// Exempt operations are never counted.
+	for range 3 {
+		require.Error(t, call("/api.v1.Health/Check", "10.0.0.3", true))
+	}
+	assert.Equal(t, 1, testutil.CollectAndCount(h.retryStorms))
+
+	now = now.Add(time.Minute)
+	require.NoError(t, call(route, "10.0.0.1", false))
+}
+
+func TestValidateRetryStormConfig(t *testing.T) {
+	require.NoError(t, validateRetryStormConfig(nil))
+	require.NoError(t, validateRetryStormConfig(&conf.RetryStormConfig{Enabled: true, Threshold: 5, ExemptOperations: []string{"/health"}}))
+	assert.Error(t, validateRetryStormConfig(&conf.RetryStormConfig{Enabled: true, Threshold: -1}))
+	assert.Error(t, validateRetryStormConfig(&conf.RetryStormConfig{Enabled: true, Window: durationpb.New(-time.Second)}))
+	assert.Error(t, validateRetryStormConfig(&conf.RetryStormConfig{Enabled: true, BlockDuration: durationpb.New(-time.Second)}))
+	assert.Error(t, validateRetryStormConfig(&conf.RetryStormConfig{Enabled: true, MaxTracked: -1}))
+	assert.Error(t, validateRetryStormConfig(&conf.RetryStormConfig{Enabled: true, ExemptOperations: []string{" "}}))
+}
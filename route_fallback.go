@@ -0,0 +1,119 @@
+package http
+
+import (
+	"fmt"
+	"mime"
+	nhttp "net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+// SetNotFoundHandler replaces the response for paths without a route. The 404 metric is still recorded before
+// the handler runs. A nil handler restores the configured response.
+func (h *ServiceHttp) SetNotFoundHandler(handler nhttp.Handler) {
+	h.fallbackMu.Lock()
+	defer h.fallbackMu.Unlock()
+	h.notFoundOverride = handler
+}
+
+// SetMethodNotAllowedHandler replaces the response for routes without a handler for the method. The 405 metric
+// is still recorded and the Allow header is set before the handler runs. A nil handler restores the configured
+// response.
+func (h *ServiceHttp) SetMethodNotAllowedHandler(handler nhttp.Handler) {
+	h.fallbackMu.Lock()
+	defer h.fallbackMu.Unlock()
+	h.methodNotAllowedOverride = handler
+}
+
+// fallbackHandlers returns the handlers set with SetNotFoundHandler and SetMethodNotAllowedHandler.
+func (h *ServiceHttp) fallbackHandlers() (notFound, methodNotAllowed nhttp.Handler) {
+	h.fallbackMu.RLock()
+	defer h.fallbackMu.RUnlock()
+	return h.notFoundOverride, h.methodNotAllowedOverride
+}
+
+// routingConfig returns the current routing configuration, which may be nil.
+func (h *ServiceHttp) routingConfig() *conf.RoutingConfig {
+	h.confMu.RLock()
+	defer h.confMu.RUnlock()
+	return h.conf.GetRouting()
+}
+
+// writeFallbackResponse writes a 404 or 405 response: the configured replacement, or status with the built-in
+// JSON body when none applies.
+func writeFallbackResponse(w nhttp.ResponseWriter, r *nhttp.Request, cfg *conf.FallbackResponse, status int) {
+	body := []byte(`{"code":` + strconv.Itoa(status) + `}`)
+	contentType := "application/json"
+	if cfg != nil && !fallbackExcluded(cfg, r.URL.Path) {
+		switch {
+		case cfg.Redirect != "":
+			redirect := nhttp.StatusFound
+			if cfg.Status >= 300 && cfg.Status < 400 {
+				redirect = int(cfg.Status)
+			}
+			nhttp.Redirect(w, r, cfg.Redirect, redirect)
+			return
+		case cfg.File != "" && (r.Method == nhttp.MethodGet || r.Method == nhttp.MethodHead):
+			data, err := os.ReadFile(cfg.File)
+			if err != nil {
+				log.Errorf("Failed to read fallback file %s: %v", cfg.File, err)
+				break
+			}
+			contentType = mime.TypeByExtension(filepath.Ext(cfg.File))
+			if contentType == "" {
+				contentType = nhttp.DetectContentType(data)
+			}
+			body, status = data, nhttp.StatusOK
+		case cfg.Body != "":
+			body = []byte(cfg.Body)
+			if cfg.ContentType != "" {
+				contentType = cfg.ContentType
+			}
+		}
+		if cfg.Status != 0 && cfg.Redirect == "" {
+			status = int(cfg.Status)
+		}
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(status)
+	if r.Method != nhttp.MethodHead {
+		_, _ = w.Write(body)
+	}
+}
+
+// fallbackExcluded reports whether path keeps the built-in response.
+func fallbackExcluded(cfg *conf.FallbackResponse, path string) bool {
+	for _, prefix := range cfg.ExcludePrefixes {
+		if prefix != "" && strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateFallbackResponse checks a FallbackResponse of the routing configuration.
+func validateFallbackResponse(cfg *conf.FallbackResponse) error {
+	if cfg == nil {
+		return nil
+	}
+	if cfg.Status != 0 && (cfg.Status < 100 || cfg.Status > 599) {
+		return fmt.Errorf("status %d is not a valid HTTP status", cfg.Status)
+	}
+	if cfg.Redirect != "" && cfg.Status != 0 && (cfg.Status < 300 || cfg.Status > 399) {
+		return fmt.Errorf("status %d is not a redirect status", cfg.Status)
+	}
+	if cfg.File != "" {
+		if info, err := os.Stat(cfg.File); err != nil {
+			return fmt.Errorf("file: %w", err)
+		} else if info.IsDir() {
+			return fmt.Errorf("file %s is a directory", cfg.File)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package http
+
+import (
+	nhttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFallbackTestService(t *testing.T, routing *conf.RoutingConfig) *ServiceHttp {
+	t.Helper()
+	h := &ServiceHttp{
+		conf:         &conf.Http{Routing: routing},
+		errorCounter: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "errors"}, []string{"method", "path", "type"}),
+	}
+	h.server = http.NewServer(
+		http.NotFoundHandler(h.notFoundHandler()),
+		http.MethodNotAllowedHandler(h.methodNotAllowedHandler()),
+	)
+	h.server.Route("/").POST("/api/users", func(ctx http.Context) error { return ctx.String(nhttp.StatusOK, "ok") })
+	return h
+}
+
+func serveFallback(h *ServiceHttp, method, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.server.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec
+}
+
+func TestRouteFallback_Defaults(t *testing.T) {
+	h := newFallbackTestService(t, nil)
+
+	rec := serveFallback(h, nhttp.MethodGet, "/missing")
+	assert.Equal(t, nhttp.StatusNotFound, rec.Code)
+	assert.JSONEq(t, `{"code":404}`, rec.Body.String())
+
+	rec = serveFallback(h, nhttp.MethodGet, "/api/users")
+	assert.Equal(t, nhttp.StatusMethodNotAllowed, rec.Code)
+	assert.JSONEq(t, `{"code":405}`, rec.Body.String())
+	assert.Equal(t, float64(1), testutil.ToFloat64(h.errorCounter.WithLabelValues("GET", "unmatched", "method_not_allowed")))
+}
+
+func TestRouteFallback_Configured(t *testing.T) {
+	index := filepath.Join(t.TempDir(), "index.html")
+	require.NoError(t, os.WriteFile(index, []byte("<html>app</html>"), 0o600))
+
+	h := newFallbackTestService(t, &conf.RoutingConfig{
+		NotFound:         &conf.FallbackResponse{File: index, ExcludePrefixes: []string{"/api/"}},
+		MethodNotAllowed: &conf.FallbackResponse{Status: 400, Body: "wrong method", ContentType: "text/plain"},
+	})
+	require.NoError(t, validateFallbackResponse(h.conf.Routing.NotFound))
+
+	rec := serveFallback(h, nhttp.MethodGet, "/settings/profile")
+	assert.Equal(t, nhttp.StatusOK, rec.Code)
+	assert.Equal(t, "<html>app</html>", rec.Body.String())
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/html")
+
+	rec = serveFallback(h, nhttp.MethodGet, "/api/missing")
+	assert.Equal(t, nhttp.StatusNotFound, rec.Code, "excluded prefixes keep the built-in response")
+	assert.JSONEq(t, `{"code":404}`, rec.Body.String())
+
+	rec = serveFallback(h, nhttp.MethodPost, "/settings/profile")
+	assert.Equal(t, nhttp.StatusNotFound, rec.Code, "files are only served to GET and HEAD")
+
+	rec = serveFallback(h, nhttp.MethodGet, "/api/users")
+	assert.Equal(t, 400, rec.Code)
+	assert.Equal(t, "wrong method", rec.Body.String())
+	assert.Equal(t, "POST, OPTIONS", rec.Header().Get("Allow"))
+	assert.Equal(t, float64(2), testutil.ToFloat64(h.errorCounter.WithLabelValues("GET", "unmatched", "not_found")),
+		"replaced responses are still counted")
+
+	h.conf.Routing.NotFound = &conf.FallbackResponse{Redirect: "/"}
+	rec = serveFallback(h, nhttp.MethodGet, "/old")
+	assert.Equal(t, nhttp.StatusFound, rec.Code)
+	assert.Equal(t, "/", rec.Header().Get("Location"))
+}
+
+func TestRouteFallback_Overrides(t *testing.T) {
+	h := newFallbackTestService(t, nil)
+	h.SetNotFoundHandler(nhttp.HandlerFunc(func(w nhttp.ResponseWriter, _ *nhttp.Request) {
+		w.WriteHeader(nhttp.StatusGone)
+	}))
+
+	assert.Equal(t, nhttp.StatusGone, serveFallback(h, nhttp.MethodGet, "/missing").Code)
+	assert.Equal(t, float64(1), testutil.ToFloat64(h.errorCounter.WithLabelValues("GET", "unmatched", "not_found")))
+
+	h.SetNotFoundHandler(nil)
+	assert.Equal(t, nhttp.StatusNotFound, serveFallback(h, nhttp.MethodGet, "/missing").Code)
+}
+
+func TestValidateFallbackResponse(t *testing.T) {
+	assert.NoError(t, validateFallbackResponse(&conf.FallbackResponse{Redirect: "/", Status: 301}))
+	assert.Error(t, validateFallbackResponse(&conf.FallbackResponse{Redirect: "/", Status: 200}))
+	assert.Error(t, validateFallbackResponse(&conf.FallbackResponse{Status: 1000}))
+	assert.Error(t, validateFallbackResponse(&conf.FallbackResponse{File: filepath.Join(t.TempDir(), "missing.html")}))
+}
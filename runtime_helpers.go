@@ -36,8 +36,38 @@ type monitoringSnapshot struct {
 	enableConnectionMetrics bool
 	enableQueueMetrics      bool
 	enableErrorTypeMetrics  bool
+	legacyLogFormat         bool
 	metricsPath             string
 	healthPath              string
+	// replyLogPolicy and routeReplyLogPolicies drive reply body rendering in request logs.
+	replyLogPolicy        replyLogPolicy
+	routeReplyLogPolicies map[string]replyLogPolicy
+	bodyLog               bodyLogOptions
+	// callers is nil unless caller metrics are enabled.
+	callers *callerLabeler
+	// slo is nil unless SLO tracking is enabled.
+	slo *sloTracker
+	// stats and statsPath back the JSON stats endpoint; stats is nil unless it is enabled.
+	stats     *requestStats
+	statsPath string
+	// logSinks routes request log records to the Lynx logger and the configured sinks.
+	logSinks logSinkRoutes
+	// headerLog selects and redacts the headers written to request logs.
+	headerLog headerLogFilter
+	// unmatched labels and samples 404 and 405 requests; it is never nil.
+	unmatched *unmatchedPathLabeler
+	// excludedRoutes are operation or path patterns that skip request logging and metrics.
+	excludedRoutes []string
+	// perfMode leaves bodies out of request logs and skips size histograms (performance.perf_mode).
+	perfMode bool
+	// podLogFields are the Downward API pod labels added to request logs.
+	podLogFields []any
+	// errorChain is nil unless error chain capture is enabled.
+	errorChain *errorChainOptions
+	// errorClasses is nil unless error classification is enabled.
+	errorClasses *errorClassifier
+	// anomaly is nil unless anomaly detection is enabled.
+	anomaly *anomalyDetector
 }
 
 func currentLynxApp() *lynx.LynxApp {
@@ -67,6 +97,7 @@ func (h *ServiceHttp) monitoringConfigOrDefault() *conf.MonitoringConfig {
 		EnableConnectionMetrics: snap.enableConnectionMetrics,
 		EnableQueueMetrics:      snap.enableQueueMetrics,
 		EnableErrorTypeMetrics:  snap.enableErrorTypeMetrics,
+		LegacyLogFormat:         snap.legacyLogFormat,
 		MetricsPath:             snap.metricsPath,
 		HealthPath:              snap.healthPath,
 	}
@@ -114,6 +145,10 @@ func defaultMonitoringSnapshot() *monitoringSnapshot {
 		enableErrorTypeMetrics:  true,
 		metricsPath:             defaultMetricsPath,
 		healthPath:              defaultHealthPath,
+		replyLogPolicy:          replyLogPolicyTypeName,
+		bodyLog:                 defaultBodyLogOptions,
+		headerLog:               defaultHeaderLogFilter,
+		unmatched:               newUnmatchedPathLabeler(nil),
 	}
 }
 
@@ -129,6 +164,7 @@ func monitoringSnapshotFromConfig(cfg *conf.MonitoringConfig) *monitoringSnapsho
 		enableConnectionMetrics: cfg.EnableConnectionMetrics,
 		enableQueueMetrics:      cfg.EnableQueueMetrics,
 		enableErrorTypeMetrics:  cfg.EnableErrorTypeMetrics,
+		legacyLogFormat:         cfg.LegacyLogFormat,
 		metricsPath:             strings.TrimSpace(cfg.MetricsPath),
 		healthPath:              strings.TrimSpace(cfg.HealthPath),
 	}
@@ -138,6 +174,47 @@ func monitoringSnapshotFromConfig(cfg *conf.MonitoringConfig) *monitoringSnapsho
 	if snap.healthPath == "" {
 		snap.healthPath = defaultHealthPath
 	}
+	snap.replyLogPolicy, snap.routeReplyLogPolicies = replyLogPoliciesFromConfig(cfg.BodyLogging)
+	snap.bodyLog = bodyLogOptionsFromConfig(cfg.BodyLogging)
+	snap.callers = newCallerLabeler(cfg.CallerMetrics)
+	snap.slo = newSLOTracker(cfg.Slo)
+	snap.stats = newRequestStats(cfg.StatsEndpoint)
+	snap.logSinks = newLogSinkRoutes(cfg.LogSinks)
+	snap.headerLog = headerLogFilterFromConfig(cfg.HeaderLogging)
+	snap.unmatched = newUnmatchedPathLabeler(cfg.UnmatchedPaths)
+	snap.errorChain = newErrorChainOptions(cfg.ErrorChain)
+	snap.errorClasses = newErrorClassifier(cfg.ErrorClasses)
+	snap.anomaly = newAnomalyDetector(cfg.Anomaly)
+	if snap.errorClasses != nil {
+		if snap.slo != nil {
+			snap.slo.unavailable = snap.errorClasses.alerting
+		}
+		if snap.anomaly != nil {
+			snap.anomaly.isError = snap.errorClasses.alerting
+		}
+	}
+	for _, pattern := range cfg.ExcludedRoutes {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			snap.excludedRoutes = append(snap.excludedRoutes, pattern)
+		}
+	}
+	if snap.stats != nil {
+		snap.statsPath = strings.TrimSpace(cfg.StatsEndpoint.Path)
+		if snap.statsPath == "" {
+			snap.statsPath = defaultStatsPath
+		}
+	}
+	return snap
+}
+
+// monitoringSnapshotForConfig builds the snapshot of c with the performance.perf_mode preset applied.
+func monitoringSnapshotForConfig(c *conf.Http) *monitoringSnapshot {
+	snap := monitoringSnapshotFromConfig(c.GetMonitoring())
+	if c.GetPerformance().GetPerfMode() {
+		snap.perfMode = true
+		snap.replyLogPolicy, snap.routeReplyLogPolicies = replyLogPolicyOff, nil
+	}
+	snap.podLogFields = podInfoFromEnv(c.GetKubernetes()).logFields()
 	return snap
 }
 
@@ -149,7 +226,7 @@ func (h *ServiceHttp) refreshMonitoringSnapshotLocked() {
 		h.monitoringSnapshot.Store(defaultMonitoringSnapshot())
 		return
 	}
-	h.monitoringSnapshot.Store(monitoringSnapshotFromConfig(h.conf.Monitoring))
+	h.monitoringSnapshot.Store(monitoringSnapshotForConfig(h.conf))
 }
 
 func (h *ServiceHttp) monitoringSnapshotOrDefault() *monitoringSnapshot {
@@ -162,7 +239,7 @@ func (h *ServiceHttp) monitoringSnapshotOrDefault() *monitoringSnapshot {
 	h.confMu.RLock()
 	var snap *monitoringSnapshot
 	if h.conf != nil {
-		snap = monitoringSnapshotFromConfig(h.conf.Monitoring)
+		snap = monitoringSnapshotForConfig(h.conf)
 	}
 	h.confMu.RUnlock()
 	if snap == nil {
@@ -192,6 +269,10 @@ func (h *ServiceHttp) errorLoggingEnabled() bool {
 	return h.monitoringSnapshotOrDefault().enableErrorLogging
 }
 
+func (h *ServiceHttp) legacyLogFormatEnabled() bool {
+	return h.monitoringSnapshotOrDefault().legacyLogFormat
+}
+
 func requestLoggingEnabled(service *ServiceHttp) bool {
 	if service == nil {
 		return true
@@ -206,6 +287,13 @@ func errorLoggingEnabled(service *ServiceHttp) bool {
 	return service.errorLoggingEnabled()
 }
 
+func legacyLogFormatEnabled(service *ServiceHttp) bool {
+	if service == nil {
+		return false
+	}
+	return service.legacyLogFormatEnabled()
+}
+
 func (h *ServiceHttp) metricsEndpointEnabled() bool {
 	return h.monitoringSnapshotOrDefault().enableMetrics
 }
@@ -235,13 +323,16 @@ func (h *ServiceHttp) errorTypeMetricsEnabled() bool {
 }
 
 func (h *ServiceHttp) recordErrorMetric(method, path, errorType string) {
-	if h == nil || h.errorCounter == nil {
+	if h == nil || (h.errorCounter == nil && h.otelMetrics == nil) {
 		return
 	}
 	if !h.errorTypeMetricsEnabled() {
 		errorType = "error"
 	}
-	h.errorCounter.WithLabelValues(method, path, errorType).Inc()
+	if h.errorCounter != nil {
+		h.errorCounter.WithLabelValues(method, path, errorType).Inc()
+	}
+	h.otelMetrics.recordError(context.Background(), method, path, errorType)
 }
 
 func requestMetadata(ctx context.Context) (method, path string) {
@@ -260,20 +351,9 @@ func requestMetadata(ctx context.Context) (method, path string) {
 	return method, path
 }
 
+// sanitizeHeaders returns every header with the built-in sensitive ones redacted.
 func sanitizeHeaders(header transport.Header) map[string]string {
-	if header == nil {
-		return nil
-	}
-	headers := make(map[string]string, len(header.Keys()))
-	for _, key := range header.Keys() {
-		value := header.Get(key)
-		if _, sensitive := sensitiveHeaderKeys[strings.ToLower(key)]; sensitive {
-			headers[key] = "<redacted>"
-			continue
-		}
-		headers[key] = value
-	}
-	return headers
+	return defaultHeaderLogFilter.sanitize(header)
 }
 
 func summarizePayload(payload any) string {
@@ -331,6 +411,7 @@ func (h *ServiceHttp) ensureCircuitBreaker() *CircuitBreaker {
 
 	if h.circuitBreaker == nil || h.circuitBreaker.config != cfg {
 		h.circuitBreaker = NewCircuitBreaker(cfg)
+		h.circuitBreaker.onOpen = h.emitCircuitOpened
 	}
 	return h.circuitBreaker
 }
@@ -364,11 +445,12 @@ func (h *ServiceHttp) reconfigureMetricsLoop() {
 			parentCtx = context.Background()
 		}
 		h.metricsCtx, h.metricsCancel = context.WithCancel(parentCtx)
+		loopCtx := h.metricsCtx
 		done := make(chan struct{})
 		h.metricsLoopDone = done
 		go func() {
 			defer close(done)
-			h.updateConnectionPoolMetrics(h.metricsCtx)
+			h.updateConnectionPoolMetrics(loopCtx)
 		}()
 	}
 }
@@ -0,0 +1,137 @@
+package http
+
+import (
+	"context"
+	nhttp "net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport/http"
+)
+
+const (
+	serverTimingHeader = "Server-Timing"
+
+	serverTimingHandler = "handler"
+	serverTimingEncode  = "encode"
+	serverTimingTotal   = "total"
+)
+
+// serverTimingKey stores the *serverTimings of a request in its context.
+type serverTimingKey struct{}
+
+// serverTimings collects the phase durations of one request.
+type serverTimings struct {
+	start time.Time
+
+	mu      sync.Mutex
+	names   []string
+	entries map[string]time.Duration
+}
+
+func (t *serverTimings) add(name string, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.entries[name]; !ok {
+		t.names = append(t.names, name)
+	}
+	t.entries[name] += d
+}
+
+// header renders the recorded phases followed by the total time so far, in milliseconds.
+func (t *serverTimings) header() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var b strings.Builder
+	for _, name := range t.names {
+		writeServerTiming(&b, name, t.entries[name])
+	}
+	writeServerTiming(&b, serverTimingTotal, time.Since(t.start))
+	return b.String()
+}
+
+func writeServerTiming(b *strings.Builder, name string, d time.Duration) {
+	if b.Len() > 0 {
+		b.WriteString(", ")
+	}
+	b.WriteString(name)
+	b.WriteString(";dur=")
+	b.WriteString(strconv.FormatFloat(float64(d.Microseconds())/1000, 'f', -1, 64))
+}
+
+// RecordServerTiming adds d to the named phase of the current request's Server-Timing header, for phases such
+// as authentication or database calls. Repeated names are summed. It does nothing unless
+// monitoring.server_timing is enabled.
+func RecordServerTiming(ctx context.Context, name string, d time.Duration) {
+	if t, ok := ctx.Value(serverTimingKey{}).(*serverTimings); ok {
+		t.add(serverTimingName(name), d)
+	}
+}
+
+// StartServerTiming starts timing the named phase; call the returned function when it ends.
+func StartServerTiming(ctx context.Context, name string) (stop func()) {
+	start := time.Now()
+	return func() { RecordServerTiming(ctx, name, time.Since(start)) }
+}
+
+// serverTimingName keeps metric names to HTTP token characters.
+func serverTimingName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("!#$%&'*+-.^_`|~", r) {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// serverTimingFilter starts the request's timings before routing, so the encoders see them in the request
+// context.
+func serverTimingFilter(next nhttp.Handler) nhttp.Handler {
+	return nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		t := &serverTimings{start: time.Now(), entries: make(map[string]time.Duration)}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), serverTimingKey{}, t)))
+	})
+}
+
+// serverTimingMiddleware is the innermost middleware and records the handler phase.
+func serverTimingMiddleware() middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			defer StartServerTiming(ctx, serverTimingHandler)()
+			return handler(ctx, req)
+		}
+	}
+}
+
+// setServerTimingHeader writes the Server-Timing header of r, if it is being timed.
+func setServerTimingHeader(r *http.Request, header nhttp.Header) {
+	if t, ok := r.Context().Value(serverTimingKey{}).(*serverTimings); ok {
+		header.Set(serverTimingHeader, t.header())
+	}
+}
+
+// withServerTiming buffers the output of encode to add the encode phase to the Server-Timing header, which
+// must be set before the body is written.
+func withServerTiming(encode http.EncodeResponseFunc) http.EncodeResponseFunc {
+	return func(w http.ResponseWriter, r *http.Request, data any) error {
+		t, ok := r.Context().Value(serverTimingKey{}).(*serverTimings)
+		if !ok {
+			return encode(w, r, data)
+		}
+		start := time.Now()
+		buf := &bufferedResponseWriter{w: w}
+		if err := encode(buf, r, data); err != nil {
+			return err
+		}
+		t.add(serverTimingEncode, time.Since(start))
+		w.Header().Set(serverTimingHeader, t.header())
+		if buf.status != 0 {
+			w.WriteHeader(buf.status)
+		}
+		_, err := w.Write(buf.body.Bytes())
+		return err
+	}
+}
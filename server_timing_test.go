@@ -0,0 +1,66 @@
+package http
+
+import (
+	"context"
+	nhttp "net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerTiming_Header(t *testing.T) {
+	h := &ServiceHttp{}
+	h.server = http.NewServer(
+		http.Filter(serverTimingFilter),
+		http.Middleware(serverTimingMiddleware()),
+		http.ResponseEncoder(withServerTiming(ResponseEncoder)),
+		http.ErrorEncoder(h.enhancedErrorEncoder),
+	)
+	serve := func(fail bool) http.HandlerFunc {
+		return func(ctx http.Context) error {
+			out, err := ctx.Middleware(func(ctx context.Context, _ any) (any, error) {
+				stop := StartServerTiming(ctx, "auth")
+				time.Sleep(time.Millisecond)
+				stop()
+				RecordServerTiming(ctx, "db query", 2*time.Millisecond)
+				RecordServerTiming(ctx, "db query", 3*time.Millisecond)
+				if fail {
+					return nil, errors.NotFound("NOT_FOUND", "")
+				}
+				return map[string]string{"ok": "1"}, nil
+			})(ctx, nil)
+			if err != nil {
+				return err
+			}
+			return ctx.Result(nhttp.StatusOK, out)
+		}
+	}
+	h.server.Route("/").GET("/ok", serve(false))
+	h.server.Route("/").GET("/fail", serve(true))
+
+	rec := httptest.NewRecorder()
+	h.server.ServeHTTP(rec, httptest.NewRequest(nhttp.MethodGet, "/ok", nil))
+	assert.Regexp(t, regexp.MustCompile(`^auth;dur=[\d.]+, db_query;dur=5, handler;dur=[\d.]+, encode;dur=[\d.]+, total;dur=[\d.]+$`),
+		rec.Header().Get(serverTimingHeader))
+	assert.JSONEq(t, `{"code":200,"data":{"ok":"1"}}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	h.server.ServeHTTP(rec, httptest.NewRequest(nhttp.MethodGet, "/fail", nil))
+	assert.Regexp(t, regexp.MustCompile(`^auth;dur=[\d.]+, db_query;dur=5, handler;dur=[\d.]+, total;dur=[\d.]+$`),
+		rec.Header().Get(serverTimingHeader))
+}
+
+func TestServerTiming_DisabledIsNoop(t *testing.T) {
+	ctx := context.Background()
+	RecordServerTiming(ctx, "auth", time.Second)
+	StartServerTiming(ctx, "auth")()
+
+	rec := httptest.NewRecorder()
+	_ = withServerTiming(ResponseEncoder)(rec, httptest.NewRequest(nhttp.MethodGet, "/", nil), "x")
+	assert.Empty(t, rec.Header().Get(serverTimingHeader))
+}
@@ -0,0 +1,420 @@
+package http
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	stderrors "errors"
+	"fmt"
+	nhttp "net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+const (
+	defaultSessionCookieName      = "lynx_session"
+	defaultSessionIdleTimeout     = 30 * time.Minute
+	defaultSessionAbsoluteTimeout = 24 * time.Hour
+	minSessionSecretBytes         = 32
+	// sessionExpiryGrace keeps records in the store past their expiry, so expirations are seen and counted.
+	sessionExpiryGrace = time.Minute
+
+	// sessionStoreUnavailableReason is the Kratos error reason when the session store fails.
+	sessionStoreUnavailableReason = "SESSION_STORE_UNAVAILABLE"
+
+	sessionEventCreated         = "created"
+	sessionEventDestroyed       = "destroyed"
+	sessionEventExpiredIdle     = "expired_idle"
+	sessionEventExpiredAbsolute = "expired_absolute"
+)
+
+// sessionKey stores the *Session of a request in its context.
+type sessionKey struct{}
+
+// Session is the cookie session of the current request. Changes are saved when the handler returns; a new
+// session is only stored, and its cookie only issued, once a value is set.
+type Session struct {
+	mu  sync.Mutex
+	id  string
+	rec SessionRecord
+	// previousID is the ID replaced by Regenerate, deleted from the store on save.
+	previousID string
+	dirty      bool
+	destroyed  bool
+}
+
+// SessionFromContext returns the session of the current request when sessions are enabled.
+func SessionFromContext(ctx context.Context) (*Session, bool) {
+	s, ok := ctx.Value(sessionKey{}).(*Session)
+	return s, ok
+}
+
+// ID returns the session ID, or "" for a session that has not been saved yet.
+func (s *Session) ID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.id
+}
+
+// Get returns a session value.
+func (s *Session) Get(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.rec.Values[key]
+	return v, ok
+}
+
+// Set stores a session value.
+func (s *Session) Set(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.rec.Values == nil {
+		s.rec.Values = make(map[string]string)
+	}
+	s.rec.Values[key] = value
+	s.dirty = true
+}
+
+// Delete removes a session value.
+func (s *Session) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.rec.Values[key]; ok {
+		delete(s.rec.Values, key)
+		s.dirty = true
+	}
+}
+
+// Destroy ends the session: it is deleted from the store and the cookie is cleared (logout).
+func (s *Session) Destroy() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.destroyed = true
+}
+
+// Regenerate moves the session to a new ID, keeping its values. Call it when privileges change, such as
+// at login, to prevent session fixation.
+func (s *Session) Regenerate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.id != "" {
+		s.previousID = s.id
+	}
+	s.id = ""
+	s.dirty = true
+}
+
+// sessionCookieCodec encrypts and authenticates session IDs with AES-GCM. The first key encrypts; every
+// key decrypts.
+type sessionCookieCodec struct {
+	aeads []cipher.AEAD
+	// name binds cookies to the cookie name.
+	name []byte
+}
+
+func newSessionCookieCodec(name string, secrets ...string) (*sessionCookieCodec, error) {
+	c := &sessionCookieCodec{name: []byte(name)}
+	for _, secret := range secrets {
+		key := sha256.Sum256([]byte(secret))
+		block, err := aes.NewCipher(key[:])
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		c.aeads = append(c.aeads, aead)
+	}
+	return c, nil
+}
+
+func (c *sessionCookieCodec) encode(id string) string {
+	aead := c.aeads[0]
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(id)+aead.Overhead())
+	_, _ = rand.Read(nonce)
+	return base64.RawURLEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(id), c.name))
+}
+
+func (c *sessionCookieCodec) decode(value string) (string, bool) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return "", false
+	}
+	for _, aead := range c.aeads {
+		if len(data) < aead.NonceSize() {
+			continue
+		}
+		if id, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], c.name); err == nil {
+			return string(id), true
+		}
+	}
+	return "", false
+}
+
+// newSessionID returns a random 256-bit session ID.
+func newSessionID() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// sessionPolicy is the resolved SessionConfig.
+type sessionPolicy struct {
+	codec    *sessionCookieCodec
+	cookie   nhttp.Cookie
+	idle     time.Duration
+	absolute time.Duration
+	store    string
+	now      func() time.Time
+}
+
+// newSessionPolicy returns nil when sessions are disabled or misconfigured; validation reports the latter.
+func newSessionPolicy(cfg *conf.SessionConfig) *sessionPolicy {
+	if cfg == nil || !cfg.Enabled || len(cfg.Secret) < minSessionSecretBytes {
+		return nil
+	}
+	p := &sessionPolicy{
+		cookie: nhttp.Cookie{
+			Name:     defaultSessionCookieName,
+			Path:     "/",
+			Domain:   strings.TrimSpace(cfg.CookieDomain),
+			Secure:   !cfg.AllowInsecureCookie,
+			HttpOnly: true,
+			SameSite: sessionSameSite(cfg.SameSite),
+		},
+		idle:     defaultSessionIdleTimeout,
+		absolute: defaultSessionAbsoluteTimeout,
+		store:    memorySessionStoreName,
+		now:      time.Now,
+	}
+	if v := strings.TrimSpace(cfg.CookieName); v != "" {
+		p.cookie.Name = v
+	}
+	if v := strings.TrimSpace(cfg.CookiePath); v != "" {
+		p.cookie.Path = v
+	}
+	if d := cfg.GetIdleTimeout().AsDuration(); d > 0 {
+		p.idle = d
+	}
+	if d := cfg.GetAbsoluteTimeout().AsDuration(); d > 0 {
+		p.absolute = d
+	}
+	if v := strings.TrimSpace(cfg.Store); v != "" {
+		p.store = v
+	}
+	codec, err := newSessionCookieCodec(p.cookie.Name, append([]string{cfg.Secret}, cfg.PreviousSecrets...)...)
+	if err != nil {
+		return nil
+	}
+	p.codec = codec
+	return p
+}
+
+func sessionSameSite(v string) nhttp.SameSite {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "strict":
+		return nhttp.SameSiteStrictMode
+	case "none":
+		return nhttp.SameSiteNoneMode
+	default:
+		return nhttp.SameSiteLaxMode
+	}
+}
+
+// loadSession resolves the session of a request from its cookie. Unknown, expired and undecryptable cookies
+// start a new session.
+func (h *ServiceHttp) loadSession(ctx context.Context, p *sessionPolicy, store SessionStore, header transport.Header, now time.Time) (*Session, error) {
+	for _, line := range header.Values("Cookie") {
+		cookies, err := nhttp.ParseCookie(line)
+		if err != nil {
+			continue
+		}
+		for _, c := range cookies {
+			if c.Name != p.cookie.Name {
+				continue
+			}
+			id, ok := p.codec.decode(c.Value)
+			if !ok {
+				continue
+			}
+			rec, err := store.Load(ctx, id)
+			if stderrors.Is(err, ErrSessionNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			var expired string
+			switch {
+			case !now.Before(rec.CreatedAt.Add(p.absolute)):
+				expired = sessionEventExpiredAbsolute
+			case !now.Before(rec.LastSeen.Add(p.idle)):
+				expired = sessionEventExpiredIdle
+			}
+			if expired != "" {
+				if err := store.Delete(ctx, id); err != nil {
+					return nil, err
+				}
+				h.recordSessionEvent(ctx, store, expired)
+				continue
+			}
+			return &Session{id: id, rec: rec}, nil
+		}
+	}
+	return &Session{rec: SessionRecord{Values: map[string]string{}, CreatedAt: now, LastSeen: now}}, nil
+}
+
+// saveSession persists the session after the handler and issues or clears the cookie. Unmodified sessions
+// are written back at most every tenth of the idle timeout to keep them alive.
+func (h *ServiceHttp) saveSession(ctx context.Context, p *sessionPolicy, store SessionStore, reply transport.Header, s *Session, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.previousID != "" {
+		if err := store.Delete(ctx, s.previousID); err != nil {
+			return err
+		}
+	}
+	if s.destroyed {
+		if s.id == "" {
+			return nil
+		}
+		if err := store.Delete(ctx, s.id); err != nil {
+			return err
+		}
+		cookie := p.cookie
+		cookie.MaxAge = -1
+		reply.Add("Set-Cookie", cookie.String())
+		h.recordSessionEvent(ctx, store, sessionEventDestroyed)
+		return nil
+	}
+	if s.id == "" && !s.dirty {
+		return nil
+	}
+	if s.id != "" && !s.dirty && now.Sub(s.rec.LastSeen) < p.idle/10 {
+		return nil
+	}
+	remaining := s.rec.CreatedAt.Add(p.absolute).Sub(now)
+	issue := s.id == ""
+	if issue {
+		s.id = newSessionID()
+	}
+	s.rec.LastSeen = now
+	if err := store.Save(ctx, s.id, s.rec, min(p.idle, remaining)+sessionExpiryGrace); err != nil {
+		return err
+	}
+	if issue {
+		cookie := p.cookie
+		cookie.Value = p.codec.encode(s.id)
+		cookie.MaxAge = int(remaining.Seconds())
+		reply.Add("Set-Cookie", cookie.String())
+		if s.previousID == "" {
+			h.recordSessionEvent(ctx, store, sessionEventCreated)
+		}
+	}
+	return nil
+}
+
+// recordSessionEvent counts a session lifecycle event and refreshes the active session gauge.
+func (h *ServiceHttp) recordSessionEvent(ctx context.Context, store SessionStore, event string) {
+	if h.sessionEvents != nil {
+		h.sessionEvents.WithLabelValues(event).Inc()
+	}
+	if counter, ok := store.(ActiveSessionCounter); ok && h.sessionsActive != nil {
+		if n, err := counter.ActiveSessions(ctx); err == nil {
+			h.sessionsActive.Set(float64(n))
+		}
+	}
+}
+
+// sessionMiddleware loads the cookie session before the handler and saves it afterwards.
+func (h *ServiceHttp) sessionMiddleware(p *sessionPolicy) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			tr, ok := transport.FromServerContext(ctx)
+			if !ok {
+				return handler(ctx, req)
+			}
+			store, ok := h.sessionStore(p.store)
+			if !ok {
+				log.ErrorfCtx(ctx, "Session store %q is not registered", p.store)
+				return nil, errors.ServiceUnavailable(sessionStoreUnavailableReason, "session store unavailable")
+			}
+			now := p.now()
+			s, err := h.loadSession(ctx, p, store, tr.RequestHeader(), now)
+			if err != nil {
+				log.ErrorfCtx(ctx, "Failed to load session: %v", err)
+				return nil, errors.ServiceUnavailable(sessionStoreUnavailableReason, "session store unavailable")
+			}
+			reply, err := handler(context.WithValue(ctx, sessionKey{}, s), req)
+			if saveErr := h.saveSession(ctx, p, store, tr.ReplyHeader(), s, now); saveErr != nil {
+				log.ErrorfCtx(ctx, "Failed to save session: %v", saveErr)
+				if err == nil {
+					return nil, errors.ServiceUnavailable(sessionStoreUnavailableReason, "session store unavailable")
+				}
+			}
+			return reply, err
+		}
+	}
+}
+
+// warnUnregisteredSessionStore reports a configured session store that has not been registered.
+func (h *ServiceHttp) warnUnregisteredSessionStore() {
+	h.confMu.RLock()
+	p := newSessionPolicy(h.conf.GetSession())
+	h.confMu.RUnlock()
+	if p == nil {
+		return
+	}
+	if _, ok := h.sessionStore(p.store); !ok {
+		log.Warnf("Session store %q is configured but not registered; session requests will fail", p.store)
+	}
+}
+
+// validateSessionConfig requires strong keys, known SameSite modes and consistent timeouts.
+func validateSessionConfig(cfg *conf.SessionConfig) error {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	if len(cfg.Secret) < minSessionSecretBytes {
+		return fmt.Errorf("secret must be at least %d bytes", minSessionSecretBytes)
+	}
+	for _, secret := range cfg.PreviousSecrets {
+		if len(secret) < minSessionSecretBytes {
+			return fmt.Errorf("previous secrets must be at least %d bytes", minSessionSecretBytes)
+		}
+	}
+	switch strings.ToLower(strings.TrimSpace(cfg.SameSite)) {
+	case "", "lax", "strict":
+	case "none":
+		if cfg.AllowInsecureCookie {
+			return fmt.Errorf("same_site none requires a secure cookie")
+		}
+	default:
+		return fmt.Errorf("unknown same_site mode %q", cfg.SameSite)
+	}
+	if cfg.GetIdleTimeout().AsDuration() < 0 || cfg.GetAbsoluteTimeout().AsDuration() < 0 {
+		return fmt.Errorf("timeouts cannot be negative")
+	}
+	idle, absolute := defaultSessionIdleTimeout, defaultSessionAbsoluteTimeout
+	if d := cfg.GetIdleTimeout().AsDuration(); d > 0 {
+		idle = d
+	}
+	if d := cfg.GetAbsoluteTimeout().AsDuration(); d > 0 {
+		absolute = d
+	}
+	if idle > absolute {
+		return fmt.Errorf("idle timeout %s exceeds absolute timeout %s", idle, absolute)
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memorySessionStoreName is the built-in in-process store, used when session.store is unset.
+const memorySessionStoreName = "memory"
+
+// memorySessionSweepInterval is how often the in-memory store drops expired sessions.
+const memorySessionSweepInterval = time.Minute
+
+// ErrSessionNotFound is returned by SessionStore.Load for unknown or expired sessions.
+var ErrSessionNotFound = errors.New("session not found")
+
+// SessionRecord is the stored state of a session.
+type SessionRecord struct {
+	Values    map[string]string `json:"values"`
+	CreatedAt time.Time         `json:"created_at"`
+	LastSeen  time.Time         `json:"last_seen"`
+}
+
+// SessionStore persists sessions by ID. Save replaces the record and sets its time to live; stores may
+// drop records once it has passed.
+type SessionStore interface {
+	Load(ctx context.Context, id string) (SessionRecord, error)
+	Save(ctx context.Context, id string, rec SessionRecord, ttl time.Duration) error
+	Delete(ctx context.Context, id string) error
+}
+
+// ActiveSessionCounter is implemented by stores that can count their live sessions. The
+// lynx_http_sessions_active gauge is only reported for such stores.
+type ActiveSessionCounter interface {
+	ActiveSessions(ctx context.Context) (int, error)
+}
+
+// RegisterSessionStore registers a store under the name used in session.store. "memory" is built in.
+func (h *ServiceHttp) RegisterSessionStore(name string, store SessionStore) error {
+	name = strings.TrimSpace(name)
+	if name == "" || name == memorySessionStoreName {
+		return fmt.Errorf("invalid session store name %q", name)
+	}
+	if store == nil {
+		return fmt.Errorf("session store %q: store is nil", name)
+	}
+	h.sessionStoreMu.Lock()
+	defer h.sessionStoreMu.Unlock()
+	if _, dup := h.sessionStores[name]; dup {
+		return fmt.Errorf("session store %q already registered", name)
+	}
+	if h.sessionStores == nil {
+		h.sessionStores = make(map[string]SessionStore)
+	}
+	h.sessionStores[name] = store
+	return nil
+}
+
+// sessionStore returns the named store, creating the built-in memory store on first use so sessions
+// survive middleware rebuilds.
+func (h *ServiceHttp) sessionStore(name string) (SessionStore, bool) {
+	h.sessionStoreMu.RLock()
+	store, ok := h.sessionStores[name]
+	h.sessionStoreMu.RUnlock()
+	if ok || name != memorySessionStoreName {
+		return store, ok
+	}
+	h.sessionStoreMu.Lock()
+	defer h.sessionStoreMu.Unlock()
+	if store, ok = h.sessionStores[name]; !ok {
+		store = NewMemorySessionStore()
+		if h.sessionStores == nil {
+			h.sessionStores = make(map[string]SessionStore)
+		}
+		h.sessionStores[name] = store
+	}
+	return store, true
+}
+
+// MemorySessionStore keeps sessions in process memory. Sessions are lost on restart and not shared between
+// instances; use a shared store such as NewRedisSessionStore behind a load balancer.
+type MemorySessionStore struct {
+	mu        sync.Mutex
+	sessions  map[string]memorySession
+	nextSweep time.Time
+	now       func() time.Time
+}
+
+type memorySession struct {
+	rec     SessionRecord
+	expires time.Time
+}
+
+// NewMemorySessionStore returns an empty in-memory store.
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{sessions: make(map[string]memorySession), now: time.Now}
+}
+
+// Load returns a copy of the stored record.
+func (m *MemorySessionStore) Load(_ context.Context, id string) (SessionRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[id]
+	if !ok || !m.now().Before(s.expires) {
+		return SessionRecord{}, ErrSessionNotFound
+	}
+	rec := s.rec
+	rec.Values = maps.Clone(rec.Values)
+	return rec, nil
+}
+
+// Save stores a copy of rec for ttl.
+func (m *MemorySessionStore) Save(_ context.Context, id string, rec SessionRecord, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	if now.After(m.nextSweep) {
+		for k, s := range m.sessions {
+			if !now.Before(s.expires) {
+				delete(m.sessions, k)
+			}
+		}
+		m.nextSweep = now.Add(memorySessionSweepInterval)
+	}
+	rec.Values = maps.Clone(rec.Values)
+	m.sessions[id] = memorySession{rec: rec, expires: now.Add(ttl)}
+	return nil
+}
+
+// Delete removes a session.
+func (m *MemorySessionStore) Delete(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, id)
+	return nil
+}
+
+// ActiveSessions counts unexpired sessions.
+func (m *MemorySessionStore) ActiveSessions(context.Context) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now, n := m.now(), 0
+	for _, s := range m.sessions {
+		if now.Before(s.expires) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// RedisSessionClient is the subset of a Redis client used by the Redis session store. Adapt the
+// application's client (for example go-redis or the Lynx Redis plugin) to it; Get reports a missing key
+// with found == false rather than an error.
+type RedisSessionClient interface {
+	Get(ctx context.Context, key string) (value []byte, found bool, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Del(ctx context.Context, key string) error
+}
+
+// redisSessionStore stores JSON-encoded records under prefix+ID with the session TTL as key expiry.
+type redisSessionStore struct {
+	client RedisSessionClient
+	prefix string
+}
+
+// NewRedisSessionStore returns a store keeping sessions in Redis under keyPrefix (default "lynx:session:").
+func NewRedisSessionStore(client RedisSessionClient, keyPrefix string) SessionStore {
+	if keyPrefix == "" {
+		keyPrefix = "lynx:session:"
+	}
+	return &redisSessionStore{client: client, prefix: keyPrefix}
+}
+
+func (r *redisSessionStore) Load(ctx context.Context, id string) (SessionRecord, error) {
+	data, found, err := r.client.Get(ctx, r.prefix+id)
+	if err != nil {
+		return SessionRecord{}, fmt.Errorf("load session: %w", err)
+	}
+	if !found {
+		return SessionRecord{}, ErrSessionNotFound
+	}
+	var rec SessionRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return SessionRecord{}, fmt.Errorf("decode session: %w", err)
+	}
+	return rec, nil
+}
+
+func (r *redisSessionStore) Save(ctx context.Context, id string, rec SessionRecord, ttl time.Duration) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encode session: %w", err)
+	}
+	return r.client.Set(ctx, r.prefix+id, data, ttl)
+}
+
+func (r *redisSessionStore) Delete(ctx context.Context, id string) error {
+	return r.client.Del(ctx, r.prefix+id)
+}
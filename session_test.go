@@ -0,0 +1,281 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	nhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+var (
+	sessionSecret    = strings.Repeat("s", 32)
+	sessionOldSecret = strings.Repeat("p", 32)
+)
+
+func TestSessionCookieCodec(t *testing.T) {
+	c, err := newSessionCookieCodec("sid", sessionSecret)
+	require.NoError(t, err)
+	value := c.encode("abc")
+	id, ok := c.decode(value)
+	require.True(t, ok)
+	assert.Equal(t, "abc", id)
+	assert.NotEqual(t, value, c.encode("abc"), "nonces differ per cookie")
+
+	_, ok = c.decode(value[:len(value)-2] + "AA")
+	assert.False(t, ok)
+	other, _ := newSessionCookieCodec("other", sessionSecret)
+	_, ok = other.decode(value)
+	assert.False(t, ok, "cookies are bound to their name")
+
+	old, _ := newSessionCookieCodec("sid", sessionOldSecret)
+	rotated, _ := newSessionCookieCodec("sid", sessionSecret, sessionOldSecret)
+	id, ok = rotated.decode(old.encode("legacy"))
+	require.True(t, ok)
+	assert.Equal(t, "legacy", id)
+}
+
+// sessionTestServer serves /login, /me and /logout behind the session middleware.
+func sessionTestServer(t *testing.T, h *ServiceHttp, p *sessionPolicy) func(path, cookie string) (string, *nhttp.Cookie) {
+	t.Helper()
+	h.server = http.NewServer(
+		http.Middleware(h.sessionMiddleware(p)),
+		http.ErrorEncoder(h.enhancedErrorEncoder),
+		http.ResponseEncoder(ResponseEncoder),
+	)
+	serve := func(fn func(*Session) string) http.HandlerFunc {
+		return func(ctx http.Context) error {
+			out, err := ctx.Middleware(func(ctx context.Context, _ any) (any, error) {
+				s, ok := SessionFromContext(ctx)
+				require.True(t, ok)
+				return map[string]string{"user": fn(s)}, nil
+			})(ctx, nil)
+			if err != nil {
+				return err
+			}
+			return ctx.Result(nhttp.StatusOK, out)
+		}
+	}
+	route := h.server.Route("/")
+	route.GET("/login", serve(func(s *Session) string {
+		s.Regenerate()
+		s.Set("user", "alice")
+		return "alice"
+	}))
+	route.GET("/me", serve(func(s *Session) string {
+		user, _ := s.Get("user")
+		return user
+	}))
+	route.GET("/logout", serve(func(s *Session) string {
+		s.Destroy()
+		return ""
+	}))
+
+	return func(path, cookie string) (string, *nhttp.Cookie) {
+		req := httptest.NewRequest(nhttp.MethodGet, path, nil)
+		if cookie != "" {
+			req.Header.Set("Cookie", p.cookie.Name+"="+cookie)
+		}
+		rec := httptest.NewRecorder()
+		h.server.ServeHTTP(rec, req)
+		var body struct {
+			Data struct {
+				User string `json:"user"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body), rec.Body.String())
+		var set *nhttp.Cookie
+		if cookies := rec.Result().Cookies(); len(cookies) > 0 {
+			set = cookies[0]
+		}
+		return body.Data.User, set
+	}
+}
+
+func TestSessionMiddleware_Lifecycle(t *testing.T) {
+	h := &ServiceHttp{}
+	events := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_session_events_total"}, []string{"event"})
+	active := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_sessions_active"})
+	h.sessionEvents, h.sessionsActive = events, active
+	p := newSessionPolicy(&conf.SessionConfig{Enabled: true, Secret: sessionSecret})
+	do := sessionTestServer(t, h, p)
+
+	user, set := do("/me", "")
+	assert.Empty(t, user)
+	assert.Nil(t, set, "anonymous sessions are not stored")
+
+	_, set = do("/login", "")
+	require.NotNil(t, set)
+	assert.Equal(t, defaultSessionCookieName, set.Name)
+	assert.True(t, set.HttpOnly)
+	assert.True(t, set.Secure)
+	assert.Equal(t, nhttp.SameSiteLaxMode, set.SameSite)
+	assert.Equal(t, int(defaultSessionAbsoluteTimeout.Seconds()), set.MaxAge)
+	assert.Equal(t, 1.0, testutil.ToFloat64(events.WithLabelValues(sessionEventCreated)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(active))
+
+	user, set = do("/me", set.Value)
+	assert.Equal(t, "alice", user)
+	assert.Nil(t, set, "known sessions keep their cookie")
+
+	// Logging in again moves the session to a new ID and retires the old cookie.
+	_, first := do("/login", "")
+	_, second := do("/login", first.Value)
+	require.NotNil(t, second)
+	user, _ = do("/me", first.Value)
+	assert.Empty(t, user)
+	user, _ = do("/me", second.Value)
+	assert.Equal(t, "alice", user)
+
+	_, cleared := do("/logout", second.Value)
+	require.NotNil(t, cleared)
+	assert.Equal(t, -1, cleared.MaxAge)
+	user, _ = do("/me", second.Value)
+	assert.Empty(t, user)
+	assert.Equal(t, 1.0, testutil.ToFloat64(events.WithLabelValues(sessionEventDestroyed)))
+
+	user, _ = do("/me", "garbage")
+	assert.Empty(t, user)
+}
+
+func TestSessionMiddleware_Expiry(t *testing.T) {
+	h := &ServiceHttp{}
+	events := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_session_expiry_total"}, []string{"event"})
+	h.sessionEvents = events
+	store := NewMemorySessionStore()
+	require.NoError(t, h.RegisterSessionStore("shared", store))
+	p := newSessionPolicy(&conf.SessionConfig{
+		Enabled:         true,
+		Secret:          sessionSecret,
+		Store:           "shared",
+		IdleTimeout:     durationpb.New(10 * time.Minute),
+		AbsoluteTimeout: durationpb.New(time.Hour),
+	})
+	now := time.Unix(1_700_000_000, 0)
+	p.now = func() time.Time { return now }
+	store.now = p.now
+	do := sessionTestServer(t, h, p)
+
+	_, set := do("/login", "")
+	require.NotNil(t, set)
+
+	// Activity keeps the session alive past the idle timeout, up to the absolute timeout.
+	for range 6 {
+		now = now.Add(9 * time.Minute)
+		user, _ := do("/me", set.Value)
+		require.Equal(t, "alice", user)
+	}
+	now = now.Add(6*time.Minute + 30*time.Second)
+	user, _ := do("/me", set.Value)
+	assert.Empty(t, user)
+	assert.Equal(t, 1.0, testutil.ToFloat64(events.WithLabelValues(sessionEventExpiredAbsolute)))
+
+	_, set = do("/login", "")
+	now = now.Add(10 * time.Minute)
+	user, _ = do("/me", set.Value)
+	assert.Empty(t, user)
+	assert.Equal(t, 1.0, testutil.ToFloat64(events.WithLabelValues(sessionEventExpiredIdle)))
+}
+
+func TestSessionMiddleware_UnregisteredStore(t *testing.T) {
+	h := &ServiceHttp{}
+	p := newSessionPolicy(&conf.SessionConfig{Enabled: true, Secret: sessionSecret, Store: "redis"})
+	h.server = http.NewServer(http.Middleware(h.sessionMiddleware(p)), http.ErrorEncoder(h.enhancedErrorEncoder))
+	h.server.Route("/").GET("/me", func(ctx http.Context) error {
+		_, err := ctx.Middleware(func(context.Context, any) (any, error) { return nil, nil })(ctx, nil)
+		return err
+	})
+	rec := httptest.NewRecorder()
+	h.server.ServeHTTP(rec, httptest.NewRequest(nhttp.MethodGet, "/me", nil))
+	assert.Contains(t, rec.Body.String(), `"code":503`)
+}
+
+// fakeRedisClient is an in-memory RedisSessionClient.
+type fakeRedisClient struct {
+	data map[string][]byte
+	ttls map[string]time.Duration
+}
+
+func (f *fakeRedisClient) Get(_ context.Context, key string) ([]byte, bool, error) {
+	v, ok := f.data[key]
+	return v, ok, nil
+}
+
+func (f *fakeRedisClient) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	f.data[key], f.ttls[key] = value, ttl
+	return nil
+}
+
+func (f *fakeRedisClient) Del(_ context.Context, key string) error {
+	delete(f.data, key)
+	return nil
+}
+
+func TestRedisSessionStore(t *testing.T) {
+	client := &fakeRedisClient{data: map[string][]byte{}, ttls: map[string]time.Duration{}}
+	store := NewRedisSessionStore(client, "")
+	ctx := context.Background()
+
+	_, err := store.Load(ctx, "id")
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+
+	created := time.Unix(1_700_000_000, 0).UTC()
+	require.NoError(t, store.Save(ctx, "id", SessionRecord{Values: map[string]string{"user": "alice"}, CreatedAt: created}, time.Minute))
+	assert.Equal(t, time.Minute, client.ttls["lynx:session:id"])
+	rec, err := store.Load(ctx, "id")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", rec.Values["user"])
+	assert.True(t, created.Equal(rec.CreatedAt))
+
+	require.NoError(t, store.Delete(ctx, "id"))
+	_, err = store.Load(ctx, "id")
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+}
+
+func TestRegisterSessionStore(t *testing.T) {
+	h := &ServiceHttp{}
+	assert.Error(t, h.RegisterSessionStore("memory", NewMemorySessionStore()))
+	assert.Error(t, h.RegisterSessionStore("", NewMemorySessionStore()))
+	assert.Error(t, h.RegisterSessionStore("redis", nil))
+	require.NoError(t, h.RegisterSessionStore("redis", NewMemorySessionStore()))
+	assert.Error(t, h.RegisterSessionStore("redis", NewMemorySessionStore()))
+
+	memory, ok := h.sessionStore("memory")
+	require.True(t, ok)
+	again, _ := h.sessionStore("memory")
+	assert.Same(t, memory, again)
+	_, ok = h.sessionStore("missing")
+	assert.False(t, ok)
+}
+
+func TestValidateSessionConfig(t *testing.T) {
+	valid := func() *conf.SessionConfig { return &conf.SessionConfig{Enabled: true, Secret: sessionSecret} }
+	assert.NoError(t, validateSessionConfig(nil))
+	assert.NoError(t, validateSessionConfig(valid()))
+
+	weak := valid()
+	weak.Secret = "short"
+	assert.Error(t, validateSessionConfig(weak))
+
+	sameSite := valid()
+	sameSite.SameSite = "sometimes"
+	assert.Error(t, validateSessionConfig(sameSite))
+
+	insecureNone := valid()
+	insecureNone.SameSite, insecureNone.AllowInsecureCookie = "none", true
+	assert.Error(t, validateSessionConfig(insecureNone))
+
+	timeouts := valid()
+	timeouts.IdleTimeout = durationpb.New(48 * time.Hour)
+	assert.Error(t, validateSessionConfig(timeouts))
+}
@@ -0,0 +1,240 @@
+package http
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+)
+
+const (
+	// SignedURLExpiredReason is the Kratos error reason for signed URLs past their expiry.
+	SignedURLExpiredReason = "SIGNED_URL_EXPIRED"
+	// SignedURLInvalidReason is the Kratos error reason for missing or wrong URL signatures.
+	SignedURLInvalidReason = "SIGNED_URL_INVALID"
+
+	defaultSignedURLExpiresParam   = "expires"
+	defaultSignedURLSignatureParam = "signature"
+	minSignedURLSecretBytes        = 32
+)
+
+// URLSigner mints and verifies time-limited signed URLs. The signature is an HMAC-SHA256 over the path,
+// the query parameters sorted by name and the expiry, so any change to them invalidates the URL.
+type URLSigner struct {
+	// keys[0] signs; every key verifies.
+	keys           [][]byte
+	expiresParam   string
+	signatureParam string
+	now            func() time.Time
+}
+
+// URLSignerOption customizes a URLSigner.
+type URLSignerOption func(*URLSigner)
+
+// WithVerificationKeys adds keys accepted by Verify but not used for signing, for key rotation.
+func WithVerificationKeys(keys ...[]byte) URLSignerOption {
+	return func(s *URLSigner) {
+		for _, k := range keys {
+			if len(k) > 0 {
+				s.keys = append(s.keys, k)
+			}
+		}
+	}
+}
+
+// WithSignedURLParams renames the expiry and signature query parameters.
+func WithSignedURLParams(expires, signature string) URLSignerOption {
+	return func(s *URLSigner) {
+		if expires != "" {
+			s.expiresParam = expires
+		}
+		if signature != "" {
+			s.signatureParam = signature
+		}
+	}
+}
+
+// NewURLSigner returns a signer using key, which must be at least 32 bytes.
+func NewURLSigner(key []byte, opts ...URLSignerOption) (*URLSigner, error) {
+	if len(key) < minSignedURLSecretBytes {
+		return nil, fmt.Errorf("signing key must be at least %d bytes", minSignedURLSecretBytes)
+	}
+	s := &URLSigner{
+		keys:           [][]byte{key},
+		expiresParam:   defaultSignedURLExpiresParam,
+		signatureParam: defaultSignedURLSignatureParam,
+		now:            time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// Sign returns rawURL with an expiry ttl from now and its signature appended to the query. Scheme and host
+// are kept but not signed.
+func (s *URLSigner) Sign(rawURL string, ttl time.Duration) (string, error) {
+	if ttl <= 0 {
+		return "", fmt.Errorf("signed URL ttl must be positive")
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("parse URL: %w", err)
+	}
+	q := u.Query()
+	q.Del(s.signatureParam)
+	q.Set(s.expiresParam, strconv.FormatInt(s.now().Add(ttl).Unix(), 10))
+	q.Set(s.signatureParam, base64.RawURLEncoding.EncodeToString(s.mac(s.keys[0], u.EscapedPath(), q)))
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// Verify checks the signature and expiry of u. It returns a SignedURLInvalidReason or
+// SignedURLExpiredReason error (403), or nil when the URL is valid.
+func (s *URLSigner) Verify(u *url.URL) error {
+	q := u.Query()
+	sig, err := base64.RawURLEncoding.DecodeString(q.Get(s.signatureParam))
+	if err != nil || len(sig) != sha256.Size {
+		return errors.Forbidden(SignedURLInvalidReason, "missing or malformed URL signature")
+	}
+	expires, err := strconv.ParseInt(q.Get(s.expiresParam), 10, 64)
+	if err != nil {
+		return errors.Forbidden(SignedURLInvalidReason, "missing or malformed URL expiry")
+	}
+	q.Del(s.signatureParam)
+	valid := false
+	for _, key := range s.keys {
+		if hmac.Equal(sig, s.mac(key, u.EscapedPath(), q)) {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return errors.Forbidden(SignedURLInvalidReason, "URL signature mismatch")
+	}
+	// Check expiry only after the signature, so a tampered expiry is reported as invalid.
+	if !s.now().Before(time.Unix(expires, 0)) {
+		return errors.Forbidden(SignedURLExpiredReason, "signed URL expired")
+	}
+	return nil
+}
+
+// mac is the HMAC of the path and the canonical (sorted) query without the signature.
+func (s *URLSigner) mac(key []byte, path string, q url.Values) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(path))
+	mac.Write([]byte{'?'})
+	mac.Write([]byte(q.Encode()))
+	return mac.Sum(nil)
+}
+
+// signedURLPolicy is the resolved SignedURLConfig.
+type signedURLPolicy struct {
+	signer     *URLSigner
+	operations []string
+}
+
+// newSignedURLPolicy returns nil when signature checks are disabled or misconfigured; validation reports
+// the latter.
+func newSignedURLPolicy(cfg *conf.SignedURLConfig) *signedURLPolicy {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	previous := make([][]byte, 0, len(cfg.PreviousSecrets))
+	for _, secret := range cfg.PreviousSecrets {
+		previous = append(previous, []byte(secret))
+	}
+	signer, err := NewURLSigner([]byte(cfg.Secret),
+		WithVerificationKeys(previous...),
+		WithSignedURLParams(strings.TrimSpace(cfg.ExpiresParam), strings.TrimSpace(cfg.SignatureParam)))
+	if err != nil {
+		return nil
+	}
+	return &signedURLPolicy{signer: signer, operations: cfg.Operations}
+}
+
+// requires reports whether operation is one of the signed operations.
+func (p *signedURLPolicy) requires(operation string) bool {
+	for _, pattern := range p.operations {
+		if wildcardMatches(pattern, operation) {
+			return true
+		}
+	}
+	return false
+}
+
+// URLSigner returns the signer built from signed_urls, or nil when signed URLs are not configured. Use it
+// to mint links that the plugin's own middleware accepts.
+func (h *ServiceHttp) URLSigner() *URLSigner {
+	h.confMu.RLock()
+	defer h.confMu.RUnlock()
+	if p := newSignedURLPolicy(h.conf.GetSignedUrls()); p != nil {
+		return p.signer
+	}
+	return nil
+}
+
+// signedURLMiddleware rejects requests to signed operations whose URL signature is missing, wrong or expired.
+func signedURLMiddleware(p *signedURLPolicy) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			tr, ok := transport.FromServerContext(ctx)
+			if !ok || !p.requires(tr.Operation()) {
+				return handler(ctx, req)
+			}
+			r, ok := http.RequestFromServerContext(ctx)
+			if !ok || r == nil {
+				return nil, errors.Forbidden(SignedURLInvalidReason, "missing URL signature")
+			}
+			if err := p.signer.Verify(r.URL); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}
+	}
+}
+
+// validateSignedURLConfig requires strong keys and at least one signed operation.
+func validateSignedURLConfig(cfg *conf.SignedURLConfig) error {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	if len(cfg.Secret) < minSignedURLSecretBytes {
+		return fmt.Errorf("secret must be at least %d bytes", minSignedURLSecretBytes)
+	}
+	for _, secret := range cfg.PreviousSecrets {
+		if len(secret) < minSignedURLSecretBytes {
+			return fmt.Errorf("previous secrets must be at least %d bytes", minSignedURLSecretBytes)
+		}
+	}
+	if len(cfg.Operations) == 0 {
+		return fmt.Errorf("at least one operation is required")
+	}
+	for _, op := range cfg.Operations {
+		if strings.TrimSpace(op) == "" {
+			return fmt.Errorf("operations contains an empty pattern")
+		}
+	}
+	expires, signature := defaultSignedURLExpiresParam, defaultSignedURLSignatureParam
+	if v := strings.TrimSpace(cfg.ExpiresParam); v != "" {
+		expires = v
+	}
+	if v := strings.TrimSpace(cfg.SignatureParam); v != "" {
+		signature = v
+	}
+	if expires == signature {
+		return fmt.Errorf("expires and signature parameters must differ")
+	}
+	return nil
+}
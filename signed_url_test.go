@@ -0,0 +1,143 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	nhttp "net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	signedURLKey    = strings.Repeat("k", 32)
+	signedURLOldKey = strings.Repeat("o", 32)
+)
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+	return u
+}
+
+func TestURLSigner_SignAndVerify(t *testing.T) {
+	_, err := NewURLSigner([]byte("short"))
+	assert.Error(t, err)
+
+	s, err := NewURLSigner([]byte(signedURLKey))
+	require.NoError(t, err)
+	now := time.Unix(1_700_000_000, 0)
+	s.now = func() time.Time { return now }
+
+	signed, err := s.Sign("https://cdn.example.com/files/report.pdf?b=2&a=1", 5*time.Minute)
+	require.NoError(t, err)
+	u := mustParseURL(t, signed)
+	assert.Equal(t, "1700000300", u.Query().Get("expires"))
+	assert.NoError(t, s.Verify(u))
+
+	tampered := mustParseURL(t, strings.Replace(signed, "b=2", "b=3", 1))
+	assert.Equal(t, SignedURLInvalidReason, errors.Reason(s.Verify(tampered)))
+	otherPath := mustParseURL(t, strings.Replace(signed, "report.pdf", "secret.pdf", 1))
+	assert.Equal(t, SignedURLInvalidReason, errors.Reason(s.Verify(otherPath)))
+	assert.Equal(t, SignedURLInvalidReason, errors.Reason(s.Verify(mustParseURL(t, "/files/report.pdf?a=1"))))
+
+	now = now.Add(5 * time.Minute)
+	err = s.Verify(u)
+	assert.Equal(t, SignedURLExpiredReason, errors.Reason(err))
+	assert.Equal(t, 403, int(errors.Code(err)))
+
+	_, err = s.Sign("/files/report.pdf", 0)
+	assert.Error(t, err)
+}
+
+func TestURLSigner_RotationAndParams(t *testing.T) {
+	old, err := NewURLSigner([]byte(signedURLOldKey), WithSignedURLParams("exp", "sig"))
+	require.NoError(t, err)
+	signed, err := old.Sign("/callbacks/payment", time.Minute)
+	require.NoError(t, err)
+	assert.Contains(t, signed, "sig=")
+
+	current, err := NewURLSigner([]byte(signedURLKey), WithSignedURLParams("exp", "sig"))
+	require.NoError(t, err)
+	assert.Error(t, current.Verify(mustParseURL(t, signed)))
+
+	rotated, err := NewURLSigner([]byte(signedURLKey), WithSignedURLParams("exp", "sig"),
+		WithVerificationKeys([]byte(signedURLOldKey)))
+	require.NoError(t, err)
+	assert.NoError(t, rotated.Verify(mustParseURL(t, signed)))
+}
+
+func TestSignedURLMiddleware_Envelope(t *testing.T) {
+	cfg := &conf.SignedURLConfig{Enabled: true, Secret: signedURLKey, Operations: []string{"/api.v1.Files/*"}}
+	h := &ServiceHttp{conf: &conf.Http{SignedUrls: cfg}}
+	h.server = http.NewServer(
+		http.Middleware(signedURLMiddleware(newSignedURLPolicy(cfg))),
+		http.ErrorEncoder(h.enhancedErrorEncoder),
+		http.ResponseEncoder(ResponseEncoder),
+	)
+	serve := func(operation string) http.HandlerFunc {
+		return func(ctx http.Context) error {
+			http.SetOperation(ctx, operation)
+			out, err := ctx.Middleware(func(context.Context, any) (any, error) {
+				return map[string]string{"ok": "1"}, nil
+			})(ctx, nil)
+			if err != nil {
+				return err
+			}
+			return ctx.Result(nhttp.StatusOK, out)
+		}
+	}
+	route := h.server.Route("/")
+	route.GET("/files/{name}", serve("/api.v1.Files/Download"))
+	route.GET("/health", serve("/api.v1.Health/Check"))
+
+	code := func(target string) float64 {
+		rec := httptest.NewRecorder()
+		h.server.ServeHTTP(rec, httptest.NewRequest(nhttp.MethodGet, target, nil))
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		return body["code"].(float64)
+	}
+
+	signer := h.URLSigner()
+	require.NotNil(t, signer)
+	signed, err := signer.Sign("/files/report.pdf?v=1", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, float64(200), code(signed))
+	assert.Equal(t, float64(403), code("/files/report.pdf?v=1"))
+	assert.Equal(t, float64(403), code(strings.Replace(signed, "v=1", "v=2", 1)))
+	assert.Equal(t, float64(200), code("/health"))
+}
+
+func TestValidateSignedURLConfig(t *testing.T) {
+	valid := func() *conf.SignedURLConfig {
+		return &conf.SignedURLConfig{Enabled: true, Secret: signedURLKey, Operations: []string{"/api.v1.Files/*"}}
+	}
+	assert.NoError(t, validateSignedURLConfig(nil))
+	assert.NoError(t, validateSignedURLConfig(valid()))
+
+	weak := valid()
+	weak.Secret = "secret"
+	assert.Error(t, validateSignedURLConfig(weak))
+
+	weakPrevious := valid()
+	weakPrevious.PreviousSecrets = []string{"old"}
+	assert.Error(t, validateSignedURLConfig(weakPrevious))
+
+	noOps := valid()
+	noOps.Operations = nil
+	assert.Error(t, validateSignedURLConfig(noOps))
+
+	sameParam := valid()
+	sameParam.ExpiresParam = "signature"
+	assert.Error(t, validateSignedURLConfig(sameParam))
+}
@@ -0,0 +1,276 @@
+package http
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+const (
+	sloObjectiveAvailability = "availability"
+	sloObjectiveLatency      = "latency"
+	sloWildcardOperation     = "*"
+
+	// Request outcomes are aggregated into one-minute buckets, so windows are whole minutes up to a day.
+	sloBucketWidth               = time.Minute
+	minSLOWindow                 = time.Minute
+	maxSLOWindow                 = 24 * time.Hour
+	defaultSLOEvaluationInterval = 10 * time.Second
+)
+
+// defaultSLOBurnWindows follows the common fast/slow multi-window burn-rate alerting pair.
+var defaultSLOBurnWindows = []sloBurnWindow{
+	{window: time.Hour, threshold: 14.4},
+	{window: 6 * time.Hour, threshold: 6},
+}
+
+type sloBurnWindow struct {
+	window    time.Duration
+	threshold float64
+}
+
+// label renders the window for metric labels and logs ("5m", "1h", "6h").
+func (w sloBurnWindow) label() string {
+	if w.window%time.Hour == 0 {
+		return fmt.Sprintf("%dh", int64(w.window/time.Hour))
+	}
+	return fmt.Sprintf("%dm", int64(w.window/time.Minute))
+}
+
+type sloBucket struct {
+	minute      int64
+	total       uint64
+	unavailable uint64
+	slow        uint64
+}
+
+type sloObjective struct {
+	operation          string
+	availabilityTarget float64
+	latencyThreshold   time.Duration
+	latencyTarget      float64
+
+	mu       sync.Mutex
+	buckets  []sloBucket // ring indexed by minute, sized to the longest window
+	lastEval time.Time
+}
+
+// sloBurn is one evaluated burn rate, published as a gauge and optionally logged.
+type sloBurn struct {
+	operation string
+	objective string
+	window    sloBurnWindow
+	rate      float64
+	// budgetWindow marks the longest window, whose burn also drives the remaining-budget gauge.
+	budgetWindow bool
+}
+
+// sloTracker keeps per-objective request outcomes and evaluates burn rates at most once per interval.
+// It is built per configuration and cached in the monitoring snapshot.
+type sloTracker struct {
+	// unavailable reports whether an error spends the error budget; nil uses sloUnavailable.
+	unavailable func(error) bool
+	windows     []sloBurnWindow
+	interval    time.Duration
+	logAlerts   bool
+	objectives  map[string]*sloObjective
+	wildcard    *sloObjective
+	now         func() time.Time
+}
+
+// newSLOTracker returns nil when SLO tracking is disabled or no objective is configured.
+func newSLOTracker(cfg *conf.SLOConfig) *sloTracker {
+	if cfg == nil || !cfg.Enabled || len(cfg.Objectives) == 0 {
+		return nil
+	}
+	t := &sloTracker{
+		windows:    defaultSLOBurnWindows,
+		interval:   defaultSLOEvaluationInterval,
+		logAlerts:  cfg.LogAlerts,
+		objectives: make(map[string]*sloObjective, len(cfg.Objectives)),
+		now:        time.Now,
+	}
+	if len(cfg.BurnWindows) > 0 {
+		t.windows = make([]sloBurnWindow, 0, len(cfg.BurnWindows))
+		for _, w := range cfg.BurnWindows {
+			t.windows = append(t.windows, sloBurnWindow{window: w.GetWindow().AsDuration(), threshold: w.AlertThreshold})
+		}
+	}
+	if cfg.EvaluationInterval != nil && cfg.EvaluationInterval.AsDuration() > 0 {
+		t.interval = cfg.EvaluationInterval.AsDuration()
+	}
+
+	var longest time.Duration
+	for _, w := range t.windows {
+		longest = max(longest, w.window)
+	}
+	slots := int(longest / sloBucketWidth)
+	for _, o := range cfg.Objectives {
+		obj := &sloObjective{
+			operation:          strings.TrimSpace(o.Operation),
+			availabilityTarget: o.AvailabilityTarget,
+			latencyThreshold:   o.GetLatencyThreshold().AsDuration(),
+			latencyTarget:      o.LatencyTarget,
+			buckets:            make([]sloBucket, slots),
+		}
+		if obj.operation == sloWildcardOperation {
+			t.wildcard = obj
+			continue
+		}
+		t.objectives[obj.operation] = obj
+	}
+	return t
+}
+
+func (t *sloTracker) objectiveFor(operation string) *sloObjective {
+	if obj, ok := t.objectives[operation]; ok {
+		return obj
+	}
+	return t.wildcard
+}
+
+// record counts one request against the operation's objective. When the evaluation interval has
+// elapsed it returns the freshly computed burn rates; otherwise it returns nil.
+func (t *sloTracker) record(operation string, duration time.Duration, err error) []sloBurn {
+	obj := t.objectiveFor(operation)
+	if obj == nil {
+		return nil
+	}
+	now := t.now()
+	minute := now.Unix() / int64(sloBucketWidth/time.Second)
+
+	obj.mu.Lock()
+	defer obj.mu.Unlock()
+	b := &obj.buckets[minute%int64(len(obj.buckets))]
+	if b.minute != minute {
+		*b = sloBucket{minute: minute}
+	}
+	b.total++
+	unavailable := sloUnavailable
+	if t.unavailable != nil {
+		unavailable = t.unavailable
+	}
+	if unavailable(err) {
+		b.unavailable++
+	}
+	if obj.latencyThreshold > 0 && duration > obj.latencyThreshold {
+		b.slow++
+	}
+
+	if now.Sub(obj.lastEval) < t.interval {
+		return nil
+	}
+	obj.lastEval = now
+	return obj.evaluateLocked(minute, t.windows)
+}
+
+func (o *sloObjective) evaluateLocked(minute int64, windows []sloBurnWindow) []sloBurn {
+	var (
+		burns   = make([]sloBurn, 0, 2*len(windows))
+		longest time.Duration
+	)
+	for _, w := range windows {
+		longest = max(longest, w.window)
+	}
+	for _, w := range windows {
+		from := minute - int64(w.window/sloBucketWidth)
+		var total, unavailable, slow uint64
+		for _, b := range o.buckets {
+			if b.minute > from && b.minute <= minute {
+				total += b.total
+				unavailable += b.unavailable
+				slow += b.slow
+			}
+		}
+		if o.availabilityTarget > 0 {
+			burns = append(burns, sloBurn{operation: o.operation, objective: sloObjectiveAvailability, window: w,
+				rate: burnRate(unavailable, total, o.availabilityTarget), budgetWindow: w.window == longest})
+		}
+		if o.latencyTarget > 0 {
+			burns = append(burns, sloBurn{operation: o.operation, objective: sloObjectiveLatency, window: w,
+				rate: burnRate(slow, total, o.latencyTarget), budgetWindow: w.window == longest})
+		}
+	}
+	return burns
+}
+
+// burnRate is the observed bad ratio divided by the ratio the objective allows; 1 spends the budget
+// exactly over the window.
+func burnRate(bad, total uint64, target float64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return (float64(bad) / float64(total)) / (1 - target)
+}
+
+// sloUnavailable reports whether an error counts against availability. Client and business errors
+// (codes below 500) are the caller's problem and do not spend the error budget.
+func sloUnavailable(err error) bool {
+	return err != nil && errors.FromError(err).Code >= 500
+}
+
+// recordSLO feeds a finished request into the SLO tracker and publishes burn rates when they are recomputed.
+func (h *ServiceHttp) recordSLO(operation string, duration time.Duration, err error) {
+	tracker := h.monitoringSnapshotOrDefault().slo
+	if tracker == nil {
+		return
+	}
+	for _, burn := range tracker.record(operation, duration, err) {
+		window := burn.window.label()
+		if h.sloBurnRate != nil {
+			h.sloBurnRate.WithLabelValues(burn.operation, burn.objective, window).Set(burn.rate)
+		}
+		if burn.budgetWindow && h.sloBudgetRemaining != nil {
+			h.sloBudgetRemaining.WithLabelValues(burn.operation, burn.objective).Set(1 - burn.rate)
+		}
+		if tracker.logAlerts && burn.rate > burn.window.threshold {
+			log.Warnf("SLO burn rate alert: operation=%s objective=%s window=%s burn_rate=%.2f threshold=%.2f",
+				burn.operation, burn.objective, window, burn.rate, burn.window.threshold)
+		}
+	}
+}
+
+// validateSLOConfig checks objective targets and burn windows.
+func validateSLOConfig(cfg *conf.SLOConfig) error {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	seen := make(map[string]struct{}, len(cfg.Objectives))
+	for i, o := range cfg.Objectives {
+		op := strings.TrimSpace(o.Operation)
+		if op == "" {
+			return fmt.Errorf("objective %d: operation is required", i)
+		}
+		if _, dup := seen[op]; dup {
+			return fmt.Errorf("objective %s: duplicate operation", op)
+		}
+		seen[op] = struct{}{}
+		if o.AvailabilityTarget < 0 || o.AvailabilityTarget >= 1 || o.LatencyTarget < 0 || o.LatencyTarget >= 1 {
+			return fmt.Errorf("objective %s: targets must be in [0, 1)", op)
+		}
+		if o.AvailabilityTarget == 0 && o.LatencyTarget == 0 {
+			return fmt.Errorf("objective %s: at least one of availability_target or latency_target is required", op)
+		}
+		if o.LatencyTarget > 0 && o.GetLatencyThreshold().AsDuration() <= 0 {
+			return fmt.Errorf("objective %s: latency_threshold is required with latency_target", op)
+		}
+	}
+	for _, w := range cfg.BurnWindows {
+		d := w.GetWindow().AsDuration()
+		if d < minSLOWindow || d > maxSLOWindow || d%sloBucketWidth != 0 {
+			return fmt.Errorf("burn window %v must be whole minutes between %v and %v", d, minSLOWindow, maxSLOWindow)
+		}
+		if w.AlertThreshold <= 0 {
+			return fmt.Errorf("burn window %v: alert threshold must be positive", d)
+		}
+	}
+	if cfg.EvaluationInterval != nil && cfg.EvaluationInterval.AsDuration() < 0 {
+		return fmt.Errorf("evaluation interval cannot be negative")
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package http
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func testSLOConfig() *conf.SLOConfig {
+	return &conf.SLOConfig{
+		Enabled: true,
+		Objectives: []*conf.SLOObjective{
+			{Operation: "/api.v1.Users/Get", AvailabilityTarget: 0.99,
+				LatencyThreshold: durationpb.New(100 * time.Millisecond), LatencyTarget: 0.9},
+			{Operation: sloWildcardOperation, AvailabilityTarget: 0.9},
+		},
+		BurnWindows: []*conf.SLOBurnWindow{
+			{Window: durationpb.New(5 * time.Minute), AlertThreshold: 10},
+			{Window: durationpb.New(time.Hour), AlertThreshold: 2},
+		},
+	}
+}
+
+func TestNewSLOTracker_Disabled(t *testing.T) {
+	assert.Nil(t, newSLOTracker(nil))
+	assert.Nil(t, newSLOTracker(&conf.SLOConfig{Enabled: true}))
+}
+
+func TestSLOTracker_BurnRates(t *testing.T) {
+	tracker := newSLOTracker(testSLOConfig())
+	require.NotNil(t, tracker)
+	now := time.Unix(1_700_000_000, 0)
+	tracker.now = func() time.Time { return now }
+
+	// The first request triggers an evaluation; later ones are throttled by the interval.
+	require.NotEmpty(t, tracker.record("/api.v1.Users/Get", time.Millisecond, nil))
+	for i := 0; i < 8; i++ {
+		assert.Nil(t, tracker.record("/api.v1.Users/Get", time.Millisecond, nil))
+	}
+	tracker.record("/api.v1.Users/Get", time.Second, errors.InternalServer("BOOM", "boom"))
+
+	now = now.Add(defaultSLOEvaluationInterval)
+	burns := tracker.record("/api.v1.Users/Get", time.Millisecond, errors.NotFound("MISSING", "missing"))
+	require.Len(t, burns, 4)
+
+	rates := map[string]float64{}
+	for _, b := range burns {
+		rates[b.objective+"/"+b.window.label()] = b.rate
+		assert.Equal(t, b.window.window == time.Hour, b.budgetWindow)
+	}
+	// 1 server error and 1 slow request out of 11; the 404 does not spend availability budget.
+	assert.InDelta(t, (1.0/11)/0.01, rates["availability/5m"], 1e-9)
+	assert.InDelta(t, (1.0/11)/0.1, rates["latency/1h"], 1e-9)
+
+	// Operations without their own objective fall back to the wildcard.
+	wildcard := tracker.record("/api.v1.Orders/List", 0, nil)
+	require.Len(t, wildcard, 2)
+	assert.Equal(t, sloWildcardOperation, wildcard[0].operation)
+	assert.Equal(t, 0.0, wildcard[0].rate)
+}
+
+func TestSLOTracker_OldBucketsExpire(t *testing.T) {
+	tracker := newSLOTracker(testSLOConfig())
+	now := time.Unix(1_700_000_000, 0)
+	tracker.now = func() time.Time { return now }
+
+	tracker.record("/api.v1.Users/Get", 0, errors.InternalServer("BOOM", "boom"))
+	now = now.Add(10 * time.Minute)
+	burns := tracker.record("/api.v1.Users/Get", 0, nil)
+	for _, b := range burns {
+		if b.objective == sloObjectiveAvailability && b.window.label() == "5m" {
+			assert.Equal(t, 0.0, b.rate)
+		}
+		if b.objective == sloObjectiveAvailability && b.window.label() == "1h" {
+			assert.InDelta(t, 0.5/0.01, b.rate, 1e-9)
+		}
+	}
+}
+
+func TestRecordSLO_PublishesGauges(t *testing.T) {
+	svc := NewServiceHttp()
+	svc.conf = &conf.Http{Monitoring: &conf.MonitoringConfig{EnableMetrics: true, Slo: &conf.SLOConfig{
+		Enabled:    true,
+		LogAlerts:  true,
+		Objectives: []*conf.SLOObjective{{Operation: "/slo.v1.Test/Publish", AvailabilityTarget: 0.5}},
+	}}}
+	svc.refreshMonitoringSnapshotLocked()
+	svc.initMetrics()
+	defer svc.stopMetricsLoop()
+
+	svc.recordSLO("/slo.v1.Test/Publish", 0, errors.ServiceUnavailable("DOWN", "down"))
+	assert.Equal(t, 2.0, testutil.ToFloat64(svc.sloBurnRate.WithLabelValues("/slo.v1.Test/Publish", sloObjectiveAvailability, "1h")))
+	assert.Equal(t, -1.0, testutil.ToFloat64(svc.sloBudgetRemaining.WithLabelValues("/slo.v1.Test/Publish", sloObjectiveAvailability)))
+}
+
+func TestTracerLogPackWithMetrics_RecordsSLO(t *testing.T) {
+	svc := NewServiceHttp()
+	svc.conf = &conf.Http{Monitoring: &conf.MonitoringConfig{EnableMetrics: true, Slo: &conf.SLOConfig{
+		Enabled:    true,
+		Objectives: []*conf.SLOObjective{{Operation: "/slo.v1.Test/Tracer", AvailabilityTarget: 0.9}},
+	}}}
+	svc.refreshMonitoringSnapshotLocked()
+	svc.initMetrics()
+	defer svc.stopMetricsLoop()
+
+	ctx := transport.NewServerContext(context.Background(), newFakeTransport("/slo.v1.Test/Tracer", nil))
+	_, err := TracerLogPackWithMetrics(svc)(func(context.Context, any) (any, error) {
+		return nil, errors.InternalServer("BOOM", "boom")
+	})(ctx, nil)
+	require.Error(t, err)
+	assert.InDelta(t, 10.0, testutil.ToFloat64(svc.sloBurnRate.WithLabelValues("/slo.v1.Test/Tracer", sloObjectiveAvailability, "6h")), 1e-9)
+}
+
+func TestValidateSLOConfig(t *testing.T) {
+	require.NoError(t, validateSLOConfig(nil))
+	require.NoError(t, validateSLOConfig(testSLOConfig()))
+
+	bad := []*conf.SLOConfig{
+		{Enabled: true, Objectives: []*conf.SLOObjective{{AvailabilityTarget: 0.9}}},
+		{Enabled: true, Objectives: []*conf.SLOObjective{{Operation: "/op", AvailabilityTarget: 1}}},
+		{Enabled: true, Objectives: []*conf.SLOObjective{{Operation: "/op"}}},
+		{Enabled: true, Objectives: []*conf.SLOObjective{{Operation: "/op", LatencyTarget: 0.9}}},
+		{Enabled: true, Objectives: []*conf.SLOObjective{{Operation: "/op", AvailabilityTarget: 0.9}, {Operation: "/op", AvailabilityTarget: 0.9}}},
+		{Enabled: true, BurnWindows: []*conf.SLOBurnWindow{{Window: durationpb.New(48 * time.Hour), AlertThreshold: 1}}},
+		{Enabled: true, BurnWindows: []*conf.SLOBurnWindow{{Window: durationpb.New(time.Hour)}}},
+	}
+	for i, cfg := range bad {
+		assert.Error(t, validateSLOConfig(cfg), "case %d", i)
+	}
+}
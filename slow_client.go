@@ -0,0 +1,419 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	nhttp "net/http"
+	"net/netip"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+const (
+	defaultMaxConnsPerIP   = 100
+	defaultBodyReadTimeout = 30 * time.Second
+	defaultBodyRateGrace   = 5 * time.Second
+	defaultBanThreshold    = 10
+	defaultBanWindow       = time.Minute
+	defaultBanDuration     = 5 * time.Minute
+
+	// Reasons reported on lynx_http_slow_client_protections_total.
+	slowClientReasonSlowHeader  = "slow_header"
+	slowClientReasonSlowBody    = "slow_body"
+	slowClientReasonBodyTimeout = "body_timeout"
+	slowClientReasonConnLimit   = "conn_limit"
+	slowClientReasonBanned      = "banned"
+)
+
+// errSlowRequestBody is returned from request body reads that fall below the configured minimum rate.
+var errSlowRequestBody = errors.New("request body upload too slow")
+
+type guardedConn struct {
+	ip     string
+	opened time.Time
+	active bool
+}
+
+type slowClientOffender struct {
+	windowStart time.Time
+	violations  int
+	bannedUntil time.Time
+}
+
+// slowClientGuard enforces per-IP connection caps, request body time/rate limits and temporary bans.
+// Connection admission runs in the net/http ConnState hook; body limits run in a net/http filter.
+type slowClientGuard struct {
+	maxConnsPerIP   int
+	bodyReadTimeout time.Duration
+	minBodyRate     int64
+	bodyRateGrace   time.Duration
+	banThreshold    int
+	banWindow       time.Duration
+	banDuration     time.Duration
+	trusted         []netip.Prefix
+	// onProtect is invoked for every triggered protection, with the reason label.
+	onProtect func(reason string)
+	// onBansChanged reports the number of currently banned IPs.
+	onBansChanged func(banned int)
+	now           func() time.Time
+
+	mu         sync.Mutex
+	connsPerIP map[string]int
+	conns      map[net.Conn]*guardedConn
+	offenders  map[string]*slowClientOffender
+}
+
+// newSlowClientGuard returns nil when protection is disabled. Configuration is assumed validated.
+func newSlowClientGuard(cfg *conf.SlowClientProtectionConfig) *slowClientGuard {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	g := &slowClientGuard{
+		maxConnsPerIP:   defaultMaxConnsPerIP,
+		bodyReadTimeout: durationOrDefault(cfg.BodyReadTimeout.AsDuration(), defaultBodyReadTimeout),
+		minBodyRate:     cfg.MinBodyRate,
+		bodyRateGrace:   durationOrDefault(cfg.BodyRateGrace.AsDuration(), defaultBodyRateGrace),
+		banThreshold:    defaultBanThreshold,
+		banWindow:       durationOrDefault(cfg.BanWindow.AsDuration(), defaultBanWindow),
+		banDuration:     durationOrDefault(cfg.BanDuration.AsDuration(), defaultBanDuration),
+		now:             time.Now,
+		connsPerIP:      make(map[string]int),
+		conns:           make(map[net.Conn]*guardedConn),
+		offenders:       make(map[string]*slowClientOffender),
+	}
+	if cfg.MaxConnsPerIp > 0 {
+		g.maxConnsPerIP = int(cfg.MaxConnsPerIp)
+	}
+	if cfg.BanThreshold > 0 {
+		g.banThreshold = int(cfg.BanThreshold)
+	}
+	for _, cidr := range cfg.TrustedCidrs {
+		if prefix, err := netip.ParsePrefix(strings.TrimSpace(cidr)); err == nil {
+			g.trusted = append(g.trusted, prefix)
+		}
+	}
+	return g
+}
+
+func durationOrDefault(d, def time.Duration) time.Duration {
+	if d > 0 {
+		return d
+	}
+	return def
+}
+
+func (g *slowClientGuard) isTrusted(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range g.trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteHost strips the port from a remote address.
+func remoteHost(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// connState admits, tracks and releases connections. Rejected connections are closed immediately.
+func (g *slowClientGuard) connState(conn net.Conn, state nhttp.ConnState) {
+	switch state {
+	case nhttp.StateNew:
+		ip := remoteHost(conn.RemoteAddr().String())
+		if reason := g.admit(conn, ip); reason != "" {
+			g.protect(reason)
+			_ = conn.Close()
+		}
+	case nhttp.StateActive:
+		g.mu.Lock()
+		if c, ok := g.conns[conn]; ok {
+			c.active = true
+		}
+		g.mu.Unlock()
+	case nhttp.StateHijacked, nhttp.StateClosed:
+		g.release(conn)
+	}
+}
+
+// admit registers a new connection and returns a non-empty reason when it must be refused.
+func (g *slowClientGuard) admit(conn net.Conn, ip string) string {
+	trusted := g.isTrusted(ip)
+	now := g.now()
+
+	g.mu.Lock()
+	expired := -1
+	if !trusted {
+		if o, ok := g.offenders[ip]; ok && !o.bannedUntil.IsZero() {
+			if now.Before(o.bannedUntil) {
+				g.mu.Unlock()
+				return slowClientReasonBanned
+			}
+			// The ban has lapsed; refresh the banned count so the gauge drops.
+			o.bannedUntil = time.Time{}
+			expired = g.pruneLocked(now)
+		}
+		if g.maxConnsPerIP > 0 && g.connsPerIP[ip] >= g.maxConnsPerIP {
+			g.mu.Unlock()
+			g.bansChanged(expired)
+			return slowClientReasonConnLimit
+		}
+	}
+	g.connsPerIP[ip]++
+	g.conns[conn] = &guardedConn{ip: ip, opened: now}
+	g.mu.Unlock()
+	g.bansChanged(expired)
+	return ""
+}
+
+// bansChanged reports the banned count; negative values mean nothing changed.
+func (g *slowClientGuard) bansChanged(banned int) {
+	if banned >= 0 && g.onBansChanged != nil {
+		g.onBansChanged(banned)
+	}
+}
+
+func (g *slowClientGuard) release(conn net.Conn) {
+	g.mu.Lock()
+	c, ok := g.conns[conn]
+	if !ok {
+		g.mu.Unlock()
+		return
+	}
+	delete(g.conns, conn)
+	if g.connsPerIP[c.ip]--; g.connsPerIP[c.ip] <= 0 {
+		delete(g.connsPerIP, c.ip)
+	}
+	g.mu.Unlock()
+
+	// A connection that never produced a request and whose read hit a deadline was cut off by the header
+	// timeout; connections the client closed itself, such as browser preconnects, are not violations.
+	if !c.active && headerTimedOut(conn) {
+		g.violation(c.ip, slowClientReasonSlowHeader)
+	}
+}
+
+// timeoutConn records whether a read of the connection hit its deadline. Until the first request the only read
+// deadline is the server's header timeout (or the TLS handshake timeout derived from it).
+type timeoutConn struct {
+	net.Conn
+	timedOut atomic.Bool
+}
+
+func (c *timeoutConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	if err != nil && errors.Is(err, os.ErrDeadlineExceeded) {
+		c.timedOut.Store(true)
+	}
+	return n, err
+}
+
+// timeoutListener wraps accepted connections in timeoutConn, so slow client protection can tell connections
+// cut off by the header timeout from those the client closed.
+type timeoutListener struct {
+	net.Listener
+}
+
+func (l timeoutListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &timeoutConn{Conn: conn}, nil
+}
+
+// headerTimedOut reports whether a read of conn, as passed to the ConnState hook, hit its deadline. It is false
+// for connections not accepted through timeoutListener.
+func headerTimedOut(conn net.Conn) bool {
+	tc, ok := serverConn(conn).(*timeoutConn)
+	return ok && tc.timedOut.Load()
+}
+
+// violation records a protection triggered by ip and bans it once the threshold is reached in the window.
+func (g *slowClientGuard) violation(ip, reason string) {
+	g.protect(reason)
+	if g.isTrusted(ip) {
+		return
+	}
+	now := g.now()
+
+	g.mu.Lock()
+	o, ok := g.offenders[ip]
+	if !ok {
+		o = &slowClientOffender{windowStart: now}
+		g.offenders[ip] = o
+	}
+	if now.Sub(o.windowStart) > g.banWindow {
+		o.windowStart, o.violations = now, 0
+	}
+	o.violations++
+	banned := g.banThreshold > 0 && o.violations >= g.banThreshold && !now.Before(o.bannedUntil)
+	if banned {
+		o.bannedUntil = now.Add(g.banDuration)
+		o.violations = 0
+	}
+	active := g.pruneLocked(now)
+	g.mu.Unlock()
+
+	if banned {
+		log.Warnf("Temporarily banning client %s for %v after repeated slow client violations (last: %s)", ip, g.banDuration, reason)
+		g.bansChanged(active)
+	}
+}
+
+// ban bans ip for d, unless it is trusted, on behalf of other protections such as the honeypot. Longer bans
+// in place are kept.
+func (g *slowClientGuard) ban(ip string, d time.Duration) {
+	if ip == "" || g.isTrusted(ip) {
+		return
+	}
+	now := g.now()
+
+	g.mu.Lock()
+	o, ok := g.offenders[ip]
+	if !ok {
+		o = &slowClientOffender{windowStart: now}
+		g.offenders[ip] = o
+	}
+	if until := now.Add(d); until.After(o.bannedUntil) {
+		o.bannedUntil = until
+	}
+	active := g.pruneLocked(now)
+	g.mu.Unlock()
+	g.bansChanged(active)
+}
+
+// pruneLocked drops offenders whose ban and violation window have both expired and returns the number of active bans.
+func (g *slowClientGuard) pruneLocked(now time.Time) int {
+	active := 0
+	for ip, o := range g.offenders {
+		switch {
+		case now.Before(o.bannedUntil):
+			active++
+		case now.Sub(o.windowStart) > g.banWindow:
+			delete(g.offenders, ip)
+		}
+	}
+	return active
+}
+
+func (g *slowClientGuard) protect(reason string) {
+	if g.onProtect != nil {
+		g.onProtect(reason)
+	}
+}
+
+// filter bounds request body reads by time and, optionally, by minimum upload rate.
+func (g *slowClientGuard) filter(next nhttp.Handler) nhttp.Handler {
+	return nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		if r.Body != nil && r.Body != nhttp.NoBody {
+			if g.bodyReadTimeout > 0 {
+				if err := nhttp.NewResponseController(w).SetReadDeadline(g.now().Add(g.bodyReadTimeout)); err != nil {
+					log.Debugf("Failed to set request body read deadline: %v", err)
+				}
+			}
+			r.Body = &guardedBody{ReadCloser: r.Body, guard: g, ip: remoteHost(r.RemoteAddr), start: g.now()}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// guardedBody reports body read timeouts and enforces the minimum upload rate.
+type guardedBody struct {
+	io.ReadCloser
+	guard    *slowClientGuard
+	ip       string
+	start    time.Time
+	read     int64
+	reported bool
+}
+
+func (b *guardedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	if err != nil {
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			b.report(slowClientReasonBodyTimeout)
+		}
+		return n, err
+	}
+	if g := b.guard; g.minBodyRate > 0 {
+		if elapsed := g.now().Sub(b.start); elapsed > g.bodyRateGrace &&
+			float64(b.read)/elapsed.Seconds() < float64(g.minBodyRate) {
+			b.report(slowClientReasonSlowBody)
+			return n, errSlowRequestBody
+		}
+	}
+	return n, nil
+}
+
+func (b *guardedBody) report(reason string) {
+	if !b.reported {
+		b.reported = true
+		b.guard.violation(b.ip, reason)
+	}
+}
+
+// validateSlowClientProtectionConfig rejects negative limits and malformed trusted networks.
+func validateSlowClientProtectionConfig(cfg *conf.SlowClientProtectionConfig) error {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	if cfg.MinBodyRate < 0 {
+		return fmt.Errorf("min body rate cannot be negative")
+	}
+	for name, d := range map[string]time.Duration{
+		"body read timeout": cfg.BodyReadTimeout.AsDuration(),
+		"body rate grace":   cfg.BodyRateGrace.AsDuration(),
+		"ban window":        cfg.BanWindow.AsDuration(),
+		"ban duration":      cfg.BanDuration.AsDuration(),
+	} {
+		if d < 0 {
+			return fmt.Errorf("%s cannot be negative", name)
+		}
+	}
+	if err := validateCIDRs(cfg.TrustedCidrs); err != nil {
+		return err
+	}
+	return nil
+}
+
+// initSlowClientGuard builds the guard from security configuration and wires it to the protection metrics.
+func (h *ServiceHttp) initSlowClientGuard() {
+	var cfg *conf.SlowClientProtectionConfig
+	if h.conf != nil && h.conf.Security != nil {
+		cfg = h.conf.Security.SlowClientProtection
+	}
+	h.slowClientGuard = newSlowClientGuard(cfg)
+	if h.slowClientGuard == nil {
+		return
+	}
+	h.slowClientGuard.onProtect = func(reason string) {
+		if h.slowClientProtections != nil {
+			h.slowClientProtections.WithLabelValues(reason).Inc()
+		}
+	}
+	h.slowClientGuard.onBansChanged = func(banned int) {
+		if h.slowClientBannedIPs != nil {
+			h.slowClientBannedIPs.Set(float64(banned))
+		}
+	}
+	log.Infof("Slow client protection enabled (max %d connections per IP, body read timeout %v)",
+		h.slowClientGuard.maxConnsPerIP, h.slowClientGuard.bodyReadTimeout)
+}
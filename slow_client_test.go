@@ -0,0 +1,203 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"net"
+	nhttp "net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// addrConn is a net.Conn stub with a fixed remote address.
+type addrConn struct {
+	net.Conn
+	remote string
+	closed bool
+}
+
+func (c *addrConn) RemoteAddr() net.Addr {
+	addr, _ := net.ResolveTCPAddr("tcp", c.remote)
+	return addr
+}
+
+func (c *addrConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+type slowClientRecorder struct {
+	mu      sync.Mutex
+	reasons []string
+	banned  int
+}
+
+func newTestSlowClientGuard(t *testing.T, cfg *conf.SlowClientProtectionConfig) (*slowClientGuard, *slowClientRecorder, *time.Time) {
+	t.Helper()
+	cfg.Enabled = true
+	g := newSlowClientGuard(cfg)
+	require.NotNil(t, g)
+	rec := &slowClientRecorder{}
+	g.onProtect = func(reason string) {
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		rec.reasons = append(rec.reasons, reason)
+	}
+	g.onBansChanged = func(n int) { rec.banned = n }
+	now := time.Unix(1_700_000_000, 0)
+	g.now = func() time.Time { return now }
+	return g, rec, &now
+}
+
+func TestSlowClientGuard_ConnLimit(t *testing.T) {
+	g, rec, _ := newTestSlowClientGuard(t, &conf.SlowClientProtectionConfig{MaxConnsPerIp: 2, TrustedCidrs: []string{"10.0.0.0/8"}})
+
+	a, b, c := &addrConn{remote: "1.2.3.4:1"}, &addrConn{remote: "1.2.3.4:2"}, &addrConn{remote: "1.2.3.4:3"}
+	g.connState(a, nhttp.StateNew)
+	g.connState(b, nhttp.StateNew)
+	g.connState(c, nhttp.StateNew)
+	assert.False(t, b.closed)
+	assert.True(t, c.closed)
+	assert.Equal(t, []string{slowClientReasonConnLimit}, rec.reasons)
+
+	// Closing a connection frees a slot; the rejected one was never tracked.
+	g.connState(c, nhttp.StateClosed)
+	g.connState(a, nhttp.StateActive)
+	g.connState(a, nhttp.StateClosed)
+	d := &addrConn{remote: "1.2.3.4:4"}
+	g.connState(d, nhttp.StateNew)
+	assert.False(t, d.closed)
+
+	// Trusted networks are not capped.
+	for i := 0; i < 5; i++ {
+		conn := &addrConn{remote: "10.1.1.1:1000"}
+		g.connState(conn, nhttp.StateNew)
+		assert.False(t, conn.closed)
+	}
+}
+
+func TestSlowClientGuard_SlowHeaderBan(t *testing.T) {
+	g, rec, now := newTestSlowClientGuard(t, &conf.SlowClientProtectionConfig{
+		BanThreshold: 2,
+		BanDuration:  durationpb.New(time.Minute),
+	})
+
+	// Connections the client closed before sending a request, such as preconnects, are not violations.
+	preconnect := &timeoutConn{Conn: &addrConn{remote: "5.6.7.8:1"}}
+	g.connState(preconnect, nhttp.StateNew)
+	*now = now.Add(time.Minute)
+	g.connState(preconnect, nhttp.StateClosed)
+	assert.Empty(t, rec.reasons)
+
+	for i := 0; i < 2; i++ {
+		conn := &timeoutConn{Conn: &addrConn{remote: "5.6.7.8:1"}}
+		g.connState(conn, nhttp.StateNew)
+		conn.timedOut.Store(true) // cut off by the header timeout without a request
+		g.connState(conn, nhttp.StateClosed)
+	}
+	assert.Equal(t, []string{slowClientReasonSlowHeader, slowClientReasonSlowHeader}, rec.reasons)
+	assert.Equal(t, 1, rec.banned)
+
+	banned := &addrConn{remote: "5.6.7.8:2"}
+	g.connState(banned, nhttp.StateNew)
+	assert.True(t, banned.closed)
+	assert.Equal(t, slowClientReasonBanned, rec.reasons[len(rec.reasons)-1])
+
+	// Connections that served a request are not violations, and the ban lapses.
+	*now = now.Add(2 * time.Minute)
+	ok := &timeoutConn{Conn: &addrConn{remote: "5.6.7.8:3"}}
+	g.connState(ok, nhttp.StateNew)
+	assert.False(t, ok.Conn.(*addrConn).closed)
+	assert.Equal(t, 0, rec.banned)
+	g.connState(ok, nhttp.StateActive)
+	ok.timedOut.Store(true) // e.g. an idle keep-alive connection
+	g.connState(ok, nhttp.StateClosed)
+	assert.Len(t, rec.reasons, 3)
+}
+
+func TestTimeoutListener(t *testing.T) {
+	inner, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	lis := timeoutListener{Listener: inner}
+	defer func() { _ = lis.Close() }()
+	accept := func() (client, server net.Conn) {
+		client, err := net.Dial("tcp", lis.Addr().String())
+		require.NoError(t, err)
+		server, err = lis.Accept()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = client.Close(); _ = server.Close() })
+		return client, server
+	}
+
+	// A read cut off by its deadline is recorded.
+	_, server := accept()
+	require.NoError(t, server.SetReadDeadline(time.Now().Add(10*time.Millisecond)))
+	_, err = server.Read(make([]byte, 1))
+	require.Error(t, err)
+	assert.True(t, headerTimedOut(server))
+	assert.IsType(t, &net.TCPConn{}, acceptedConn(server))
+
+	// A connection the client closed is not.
+	client, server := accept()
+	require.NoError(t, client.Close())
+	require.NoError(t, server.SetReadDeadline(time.Now().Add(time.Second)))
+	_, err = server.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, io.EOF)
+	assert.False(t, headerTimedOut(server))
+}
+
+// trickleReader returns one byte per read while advancing the fake clock.
+type trickleReader struct {
+	data []byte
+	tick func()
+}
+
+func (r *trickleReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	r.tick()
+	p[0], r.data = r.data[0], r.data[1:]
+	return 1, nil
+}
+
+func TestSlowClientGuard_SlowBody(t *testing.T) {
+	g, rec, now := newTestSlowClientGuard(t, &conf.SlowClientProtectionConfig{
+		MinBodyRate:   100,
+		BodyRateGrace: durationpb.New(time.Second),
+	})
+
+	var readErr error
+	handler := g.filter(nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		_, readErr = io.ReadAll(r.Body)
+	}))
+
+	body := &trickleReader{data: bytes.Repeat([]byte("x"), 10), tick: func() { *now = now.Add(500 * time.Millisecond) }}
+	req := httptest.NewRequest(nhttp.MethodPost, "/upload", body)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.ErrorIs(t, readErr, errSlowRequestBody)
+	assert.Equal(t, []string{slowClientReasonSlowBody}, rec.reasons)
+
+	// A fast body passes untouched.
+	rec.reasons = nil
+	req = httptest.NewRequest(nhttp.MethodPost, "/upload", bytes.NewReader(bytes.Repeat([]byte("x"), 1024)))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.NoError(t, readErr)
+	assert.Empty(t, rec.reasons)
+}
+
+func TestValidateSlowClientProtectionConfig(t *testing.T) {
+	require.NoError(t, validateSlowClientProtectionConfig(nil))
+	require.NoError(t, validateSlowClientProtectionConfig(&conf.SlowClientProtectionConfig{TrustedCidrs: []string{"bad"}}))
+	require.Error(t, validateSlowClientProtectionConfig(&conf.SlowClientProtectionConfig{Enabled: true, TrustedCidrs: []string{"bad"}}))
+	require.Error(t, validateSlowClientProtectionConfig(&conf.SlowClientProtectionConfig{Enabled: true, MinBodyRate: -1}))
+	require.Error(t, validateSlowClientProtectionConfig(&conf.SlowClientProtectionConfig{Enabled: true, BanWindow: durationpb.New(-time.Second)}))
+	require.NoError(t, validateSlowClientProtectionConfig(&conf.SlowClientProtectionConfig{Enabled: true, TrustedCidrs: []string{"10.0.0.0/8", "::1/128"}}))
+}
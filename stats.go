@@ -0,0 +1,293 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	nhttp "net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+const (
+	defaultStatsPath   = "/debug/stats"
+	defaultStatsWindow = time.Minute
+	minStatsWindow     = time.Second
+	maxStatsWindow     = 10 * time.Minute
+	defaultStatsTopN   = 10
+	// maxStatsOperations bounds per-operation tracking; requests to further operations still count
+	// towards QPS and percentiles but are left out of the slow-operation ranking.
+	maxStatsOperations = 256
+
+	// The latency digest uses log-spaced buckets: 50µs growing by 10% per bucket, up to ~200s.
+	statsDigestMin     = 50 * time.Microsecond
+	statsDigestGrowth  = 1.1
+	statsDigestBuckets = 160
+)
+
+type statsSlot struct {
+	second int64
+	count  uint64
+	digest [statsDigestBuckets]uint32
+}
+
+type statsOpSlot struct {
+	second int64
+	count  uint64
+	sum    time.Duration
+	max    time.Duration
+}
+
+// requestStats keeps per-second request counts and latency digests over a sliding window for the
+// stats endpoint. It is built per configuration and cached in the monitoring snapshot.
+type requestStats struct {
+	window   time.Duration
+	topN     int
+	inflight atomic.Int64
+	now      func() time.Time
+	// hitters is nil unless heavy hitter tracking is enabled.
+	hitters *heavyHitters
+
+	mu    sync.Mutex
+	slots []statsSlot
+	ops   map[string][]statsOpSlot
+}
+
+// newRequestStats returns nil when the stats endpoint is disabled.
+func newRequestStats(cfg *conf.StatsEndpointConfig) *requestStats {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	s := &requestStats{
+		window: defaultStatsWindow,
+		topN:   defaultStatsTopN,
+		now:    time.Now,
+		ops:    make(map[string][]statsOpSlot),
+	}
+	if cfg.Window != nil && cfg.Window.AsDuration() > 0 {
+		s.window = min(max(cfg.Window.AsDuration().Truncate(time.Second), minStatsWindow), maxStatsWindow)
+	}
+	if cfg.TopN > 0 {
+		s.topN = int(cfg.TopN)
+	}
+	s.slots = make([]statsSlot, s.windowSeconds())
+	s.hitters = newHeavyHitters(cfg.HeavyHitters, s.window)
+	return s
+}
+
+func (s *requestStats) windowSeconds() int64 {
+	return int64(s.window / time.Second)
+}
+
+// start marks a request as in flight. It is nil-safe so callers can use the snapshot value directly.
+func (s *requestStats) start() {
+	if s != nil {
+		s.inflight.Add(1)
+	}
+}
+
+// finish records a completed request started with start.
+func (s *requestStats) finish(operation string, elapsed time.Duration) {
+	if s == nil {
+		return
+	}
+	s.inflight.Add(-1)
+
+	second := s.now().Unix()
+	idx := second % s.windowSeconds()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	slot := &s.slots[idx]
+	if slot.second != second {
+		*slot = statsSlot{second: second}
+	}
+	slot.count++
+	slot.digest[digestBucket(elapsed)]++
+
+	ops, ok := s.ops[operation]
+	if !ok {
+		if len(s.ops) >= maxStatsOperations {
+			return
+		}
+		ops = make([]statsOpSlot, s.windowSeconds())
+		s.ops[operation] = ops
+	}
+	op := &ops[idx]
+	if op.second != second {
+		*op = statsOpSlot{second: second}
+	}
+	op.count++
+	op.sum += elapsed
+	op.max = max(op.max, elapsed)
+}
+
+func digestBucket(d time.Duration) int {
+	if d <= statsDigestMin {
+		return 0
+	}
+	i := int(math.Ceil(math.Log(float64(d)/float64(statsDigestMin)) / math.Log(statsDigestGrowth)))
+	return min(i, statsDigestBuckets-1)
+}
+
+// digestUpperBound is the upper latency bound of bucket i; percentiles report it, so they err high by at most 10%.
+func digestUpperBound(i int) time.Duration {
+	return time.Duration(float64(statsDigestMin) * math.Pow(statsDigestGrowth, float64(i)))
+}
+
+// statsReport is the JSON document served by the stats endpoint.
+type statsReport struct {
+	Time           string               `json:"time"`
+	WindowSeconds  int64                `json:"window_seconds"`
+	Requests       uint64               `json:"requests"`
+	QPS            float64              `json:"qps"`
+	Inflight       int64                `json:"inflight"`
+	LatencyMs      statsLatency         `json:"latency_ms"`
+	SlowOperations []statsSlowOperation `json:"slow_operations"`
+	HeavyHitters   *statsHeavyHitters   `json:"heavy_hitters,omitempty"`
+}
+
+type statsLatency struct {
+	P50 float64 `json:"p50"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+}
+
+type statsSlowOperation struct {
+	Operation string  `json:"operation"`
+	Requests  uint64  `json:"requests"`
+	AvgMs     float64 `json:"avg_ms"`
+	MaxMs     float64 `json:"max_ms"`
+}
+
+// report aggregates the slots inside the window into a statsReport.
+func (s *requestStats) report() statsReport {
+	now := s.now()
+	from := now.Unix() - s.windowSeconds()
+	rep := statsReport{
+		Time:           now.Format(time.RFC3339),
+		WindowSeconds:  s.windowSeconds(),
+		Inflight:       s.inflight.Load(),
+		SlowOperations: []statsSlowOperation{},
+	}
+
+	var digest [statsDigestBuckets]uint64
+	s.mu.Lock()
+	for i := range s.slots {
+		slot := &s.slots[i]
+		if slot.second <= from {
+			continue
+		}
+		rep.Requests += slot.count
+		for b, n := range slot.digest {
+			digest[b] += uint64(n)
+		}
+	}
+	for operation, ops := range s.ops {
+		agg := statsSlowOperation{Operation: operation}
+		var sum, maxElapsed time.Duration
+		for _, op := range ops {
+			if op.second <= from {
+				continue
+			}
+			agg.Requests += op.count
+			sum += op.sum
+			maxElapsed = max(maxElapsed, op.max)
+		}
+		if agg.Requests == 0 {
+			continue
+		}
+		agg.AvgMs = durationMs(sum / time.Duration(agg.Requests))
+		agg.MaxMs = durationMs(maxElapsed)
+		rep.SlowOperations = append(rep.SlowOperations, agg)
+	}
+	s.mu.Unlock()
+
+	rep.QPS = float64(rep.Requests) / s.window.Seconds()
+	rep.LatencyMs = statsLatency{
+		P50: digestPercentile(&digest, rep.Requests, 0.50),
+		P95: digestPercentile(&digest, rep.Requests, 0.95),
+		P99: digestPercentile(&digest, rep.Requests, 0.99),
+	}
+	slices.SortFunc(rep.SlowOperations, func(a, b statsSlowOperation) int {
+		if a.AvgMs != b.AvgMs {
+			if a.AvgMs > b.AvgMs {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Operation, b.Operation)
+	})
+	if len(rep.SlowOperations) > s.topN {
+		rep.SlowOperations = rep.SlowOperations[:s.topN]
+	}
+	if s.hitters != nil {
+		rep.HeavyHitters = s.hitters.report(now)
+	}
+	return rep
+}
+
+func digestPercentile(digest *[statsDigestBuckets]uint64, total uint64, q float64) float64 {
+	if total == 0 {
+		return 0
+	}
+	rank := uint64(math.Ceil(q * float64(total)))
+	var seen uint64
+	for i, n := range digest {
+		seen += n
+		if seen >= rank {
+			return durationMs(digestUpperBound(i))
+		}
+	}
+	return durationMs(digestUpperBound(statsDigestBuckets - 1))
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// startRequestStats marks a request in flight and returns the stats to finish it on; nil when disabled.
+func (h *ServiceHttp) startRequestStats() *requestStats {
+	stats := h.monitoringSnapshotOrDefault().stats
+	stats.start()
+	return stats
+}
+
+func (h *ServiceHttp) statsEndpointPath() string {
+	return h.monitoringSnapshotOrDefault().statsPath
+}
+
+// statsHandler serves the current request stats as JSON.
+func (h *ServiceHttp) statsHandler() nhttp.Handler {
+	return nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		stats := h.monitoringSnapshotOrDefault().stats
+		if stats == nil {
+			w.WriteHeader(nhttp.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code": 404}`))
+			return
+		}
+		if err := json.NewEncoder(w).Encode(stats.report()); err != nil {
+			log.Errorf("Failed to encode stats response: %v", err)
+		}
+	})
+}
+
+// validateStatsEndpointConfig checks the stats window bounds and the heavy hitter limits.
+func validateStatsEndpointConfig(cfg *conf.StatsEndpointConfig) error {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	if cfg.Window != nil {
+		if d := cfg.Window.AsDuration(); d < minStatsWindow || d > maxStatsWindow {
+			return fmt.Errorf("stats window %v must be between %v and %v", d, minStatsWindow, maxStatsWindow)
+		}
+	}
+	return validateHeavyHittersConfig(cfg.HeavyHitters)
+}
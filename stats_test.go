@@ -0,0 +1,104 @@
+package http
+
+import (
+	"encoding/json"
+	nhttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestNewRequestStats_Defaults(t *testing.T) {
+	assert.Nil(t, newRequestStats(nil))
+	assert.Nil(t, newRequestStats(&conf.StatsEndpointConfig{}))
+
+	s := newRequestStats(&conf.StatsEndpointConfig{Enabled: true})
+	require.NotNil(t, s)
+	assert.Equal(t, defaultStatsWindow, s.window)
+	assert.Equal(t, defaultStatsTopN, s.topN)
+
+	// A nil collector is safe to use from the middleware.
+	var disabled *requestStats
+	assert.NotPanics(t, func() {
+		disabled.start()
+		disabled.finish("/op", time.Millisecond)
+	})
+}
+
+func TestDigestBucket(t *testing.T) {
+	assert.Equal(t, 0, digestBucket(0))
+	assert.Equal(t, 0, digestBucket(statsDigestMin))
+	assert.Equal(t, statsDigestBuckets-1, digestBucket(time.Hour))
+	for _, d := range []time.Duration{time.Millisecond, 37 * time.Millisecond, 2 * time.Second} {
+		i := digestBucket(d)
+		assert.LessOrEqual(t, d, digestUpperBound(i)+1)
+		assert.Greater(t, d, digestUpperBound(i-1))
+	}
+}
+
+func TestRequestStats_Report(t *testing.T) {
+	s := newRequestStats(&conf.StatsEndpointConfig{Enabled: true, Window: durationpb.New(10 * time.Second), TopN: 1})
+	now := time.Unix(1_700_000_000, 0)
+	s.now = func() time.Time { return now }
+
+	for i := 0; i < 98; i++ {
+		s.start()
+		s.finish("/fast", time.Millisecond)
+	}
+	s.start()
+	s.finish("/slow", 500*time.Millisecond)
+	s.start()
+	s.finish("/slow", 300*time.Millisecond)
+	s.start() // still in flight
+
+	rep := s.report()
+	assert.Equal(t, uint64(100), rep.Requests)
+	assert.InDelta(t, 10.0, rep.QPS, 1e-9)
+	assert.Equal(t, int64(1), rep.Inflight)
+	assert.InDelta(t, 1.0, rep.LatencyMs.P50, 0.1)
+	assert.InDelta(t, 1.0, rep.LatencyMs.P95, 0.1)
+	// The 99th of 100 requests is the 300ms one; bucket bounds err high by at most 10%.
+	assert.InDelta(t, 315.0, rep.LatencyMs.P99, 15)
+	require.Len(t, rep.SlowOperations, 1)
+	assert.Equal(t, "/slow", rep.SlowOperations[0].Operation)
+	assert.Equal(t, 400.0, rep.SlowOperations[0].AvgMs)
+	assert.Equal(t, 500.0, rep.SlowOperations[0].MaxMs)
+
+	// Requests older than the window drop out.
+	now = now.Add(11 * time.Second)
+	rep = s.report()
+	assert.Zero(t, rep.Requests)
+	assert.Empty(t, rep.SlowOperations)
+}
+
+func TestStatsHandler(t *testing.T) {
+	svc := NewServiceHttp()
+	rec := httptest.NewRecorder()
+	svc.statsHandler().ServeHTTP(rec, httptest.NewRequest(nhttp.MethodGet, defaultStatsPath, nil))
+	assert.Equal(t, nhttp.StatusNotFound, rec.Code)
+	assert.Empty(t, svc.statsEndpointPath())
+
+	svc.conf = &conf.Http{Monitoring: &conf.MonitoringConfig{StatsEndpoint: &conf.StatsEndpointConfig{Enabled: true}}}
+	svc.refreshMonitoringSnapshotLocked()
+	assert.Equal(t, defaultStatsPath, svc.statsEndpointPath())
+	svc.startRequestStats().finish("/op", time.Millisecond)
+
+	rec = httptest.NewRecorder()
+	svc.statsHandler().ServeHTTP(rec, httptest.NewRequest(nhttp.MethodGet, defaultStatsPath, nil))
+	require.Equal(t, nhttp.StatusOK, rec.Code)
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, 1.0, body["requests"])
+	assert.Contains(t, body, "latency_ms")
+	assert.Contains(t, body, "slow_operations")
+}
+
+func TestValidateStatsEndpointConfig(t *testing.T) {
+	require.NoError(t, validateStatsEndpointConfig(&conf.StatsEndpointConfig{Enabled: true}))
+	require.Error(t, validateStatsEndpointConfig(&conf.StatsEndpointConfig{Enabled: true, Window: durationpb.New(time.Hour)}))
+}
@@ -0,0 +1,188 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+const (
+	// RequestStuckReason is the reason of errors returned by requests cancelled by the stuck request
+	// watchdog.
+	RequestStuckReason = "REQUEST_STUCK"
+
+	// stuckRequestLabel is the goroutine label carrying the active request ID.
+	stuckRequestLabel = "lynx_http_request"
+
+	defaultStuckTimeoutMultiplier = 2
+	defaultStuckCheckInterval     = 5 * time.Second
+	defaultStuckMaxStackBytes     = 64 << 10
+)
+
+// errRequestStuck is the cancellation cause of requests cancelled by the watchdog.
+var errRequestStuck = stderrors.New("request cancelled by the stuck request watchdog")
+
+// stuckRequestPolicy is the resolved StuckRequestsConfig.
+type stuckRequestPolicy struct {
+	multiplier    float64
+	interval      time.Duration
+	cancel        bool
+	maxStackBytes int
+}
+
+// newStuckRequestPolicy returns nil when the watchdog is disabled or requests are not tracked.
+func newStuckRequestPolicy(cfg *conf.ActiveRequestsConfig) *stuckRequestPolicy {
+	stuck := cfg.GetStuck()
+	if !cfg.GetEnabled() || !stuck.GetEnabled() {
+		return nil
+	}
+	p := &stuckRequestPolicy{
+		multiplier:    defaultStuckTimeoutMultiplier,
+		interval:      defaultStuckCheckInterval,
+		cancel:        stuck.GetCancel(),
+		maxStackBytes: defaultStuckMaxStackBytes,
+	}
+	if stuck.GetTimeoutMultiplier() > 0 {
+		p.multiplier = stuck.GetTimeoutMultiplier()
+	}
+	if d := stuck.GetCheckInterval().AsDuration(); d > 0 {
+		p.interval = d
+	}
+	if stuck.GetMaxStackBytes() > 0 {
+		p.maxStackBytes = int(stuck.GetMaxStackBytes())
+	}
+	return p
+}
+
+// startStuckRequestWatchdog checks the active requests every interval until ctx is cancelled.
+func (h *ServiceHttp) startStuckRequestWatchdog(ctx context.Context) {
+	h.stopStuckRequestWatchdog()
+	p := newStuckRequestPolicy(h.conf.GetMonitoring().GetActiveRequests())
+	if p == nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	h.stuckWatchdogCancel = cancel
+	h.stuckWatchdogDone = done
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				h.checkStuckRequests(p, now)
+			}
+		}
+	}()
+	log.Infof("Stuck request watchdog enabled (%gx timeout, every %s)", p.multiplier, p.interval)
+}
+
+func (h *ServiceHttp) stopStuckRequestWatchdog() {
+	if h.stuckWatchdogCancel != nil {
+		h.stuckWatchdogCancel()
+		h.stuckWatchdogCancel = nil
+	}
+	if h.stuckWatchdogDone != nil {
+		<-h.stuckWatchdogDone
+		h.stuckWatchdogDone = nil
+	}
+}
+
+// checkStuckRequests reports, once each, the requests running longer than the multiple of their timeout.
+// Requests without a timeout are never stuck.
+func (h *ServiceHttp) checkStuckRequests(p *stuckRequestPolicy, now time.Time) {
+	var stuck []*activeRequest
+	h.activeRequests.mu.Lock()
+	for _, r := range h.activeRequests.requests {
+		if r.stuck || r.timeout <= 0 {
+			continue
+		}
+		if now.Sub(r.info.StartedAt) > time.Duration(float64(r.timeout)*p.multiplier) {
+			r.stuck = true
+			stuck = append(stuck, r)
+		}
+	}
+	h.activeRequests.mu.Unlock()
+
+	for _, r := range stuck {
+		if h.stuckRequests != nil {
+			h.stuckRequests.WithLabelValues(r.info.Operation).Inc()
+		}
+		log.Warnw("msg", "[HTTP Stuck Request]",
+			"id", r.info.ID,
+			"api", r.info.Operation,
+			"path", r.info.Path,
+			"client_ip", r.info.ClientIP,
+			"trace_id", r.info.TraceID,
+			"running", now.Sub(r.info.StartedAt).Round(time.Millisecond).String(),
+			"timeout", r.timeout.String(),
+			"cancelled", p.cancel,
+			"stacks", requestStacks(r.info.ID, p.maxStackBytes))
+		if p.cancel {
+			r.cancel(errRequestStuck)
+		}
+	}
+}
+
+// requestStacks returns the stacks of the goroutines labelled with the request ID: the handler and the
+// goroutines it started, in the grouped format of the goroutine profile, cut at maxBytes.
+func requestStacks(id string, maxBytes int) string {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		return fmt.Sprintf("<unavailable: %v>", err)
+	}
+	label := strconv.Quote(stuckRequestLabel) + ":" + strconv.Quote(id)
+	var out strings.Builder
+	// Records are separated by blank lines; the first one is the profile header.
+	for record := range strings.SplitSeq(buf.String(), "\n\n") {
+		if !strings.Contains(record, label) {
+			continue
+		}
+		if out.Len() > 0 {
+			out.WriteString("\n\n")
+		}
+		out.WriteString(strings.TrimSpace(record))
+		if out.Len() >= maxBytes {
+			break
+		}
+	}
+	if out.Len() == 0 {
+		return "<no goroutines found>"
+	}
+	s := out.String()
+	if len(s) > maxBytes {
+		s = s[:maxBytes] + "\n<truncated>"
+	}
+	return s
+}
+
+// validateStuckRequestsConfig requires request tracking and rejects negative settings.
+func validateStuckRequestsConfig(cfg *conf.ActiveRequestsConfig) error {
+	stuck := cfg.GetStuck()
+	if !stuck.GetEnabled() {
+		return nil
+	}
+	if !cfg.GetEnabled() {
+		return fmt.Errorf("the watchdog requires active_requests.enabled")
+	}
+	if stuck.GetTimeoutMultiplier() < 0 {
+		return fmt.Errorf("timeout multiplier cannot be negative")
+	}
+	if stuck.GetCheckInterval().AsDuration() < 0 {
+		return fmt.Errorf("check interval cannot be negative")
+	}
+	return nil
+}
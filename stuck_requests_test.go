@@ -0,0 +1,93 @@
+package http
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// waitForStuckTestLock blocks like a handler waiting on a lock that is never released.
+func waitForStuckTestLock(ctx context.Context, locked chan<- struct{}) {
+	close(locked)
+	<-ctx.Done()
+}
+
+func TestStuckRequestWatchdog(t *testing.T) {
+	active := &conf.ActiveRequestsConfig{Enabled: true, Stuck: &conf.StuckRequestsConfig{Enabled: true, Cancel: true}}
+	h := &ServiceHttp{
+		conf:          &conf.Http{Timeout: durationpb.New(time.Second), Monitoring: &conf.MonitoringConfig{ActiveRequests: active}},
+		stuckRequests: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "stuck"}, []string{"route"}),
+	}
+	policy := newStuckRequestPolicy(active)
+	require.NotNil(t, policy)
+
+	locked := make(chan struct{})
+	done := make(chan error, 1)
+	handler := h.activeRequestsMiddleware()(func(ctx context.Context, _ any) (any, error) {
+		// The stuck work runs in a goroutine the handler started; it inherits the request label.
+		go waitForStuckTestLock(ctx, locked)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	go func() {
+		ctx := transport.NewServerContext(context.Background(), newFakeTransport("/api.v1.Reports/Export", nil))
+		_, err := handler(ctx, nil)
+		done <- err
+	}()
+	<-locked
+
+	active1 := h.ActiveRequests()
+	require.Len(t, active1, 1)
+	stacks := requestStacks(active1[0].ID, defaultStuckMaxStackBytes)
+	assert.Contains(t, stacks, "waitForStuckTestLock")
+	assert.NotContains(t, requestStacks("no-such-request", defaultStuckMaxStackBytes), "waitForStuckTestLock")
+	assert.LessOrEqual(t, len(requestStacks(active1[0].ID, 16)), 16+len("\n<truncated>"))
+
+	h.checkStuckRequests(policy, time.Now().Add(1500*time.Millisecond))
+	assert.Equal(t, float64(0), testutil.ToFloat64(h.stuckRequests.WithLabelValues("/api.v1.Reports/Export")),
+		"within twice the timeout")
+
+	h.checkStuckRequests(policy, time.Now().Add(3*time.Second))
+	h.checkStuckRequests(policy, time.Now().Add(4*time.Second))
+	assert.Equal(t, float64(1), testutil.ToFloat64(h.stuckRequests.WithLabelValues("/api.v1.Reports/Export")),
+		"reported once")
+
+	err := <-done
+	assert.Equal(t, RequestStuckReason, errors.Reason(err))
+	assert.Equal(t, 504, errors.Code(err))
+}
+
+func TestStuckRequestWatchdog_UsesContextDeadline(t *testing.T) {
+	h := &ServiceHttp{conf: &conf.Http{Timeout: durationpb.New(time.Hour)}}
+	policy := &stuckRequestPolicy{multiplier: 2, maxStackBytes: 1024}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, _ = h.activeRequestsMiddleware()(func(context.Context, any) (any, error) {
+		h.checkStuckRequests(policy, time.Now().Add(time.Second))
+		h.activeRequests.mu.Lock()
+		defer h.activeRequests.mu.Unlock()
+		for _, r := range h.activeRequests.requests {
+			assert.True(t, r.stuck, "the context deadline wins over the server timeout")
+		}
+		return nil, nil
+	})(ctx, nil)
+}
+
+func TestValidateStuckRequestsConfig(t *testing.T) {
+	assert.NoError(t, validateStuckRequestsConfig(nil))
+	assert.Error(t, validateStuckRequestsConfig(&conf.ActiveRequestsConfig{Stuck: &conf.StuckRequestsConfig{Enabled: true}}))
+	assert.Error(t, validateStuckRequestsConfig(&conf.ActiveRequestsConfig{Enabled: true,
+		Stuck: &conf.StuckRequestsConfig{Enabled: true, TimeoutMultiplier: -1}}))
+	assert.NoError(t, validateStuckRequestsConfig(&conf.ActiveRequestsConfig{Enabled: true,
+		Stuck: &conf.StuckRequestsConfig{Enabled: true}}))
+	assert.Nil(t, newStuckRequestPolicy(&conf.ActiveRequestsConfig{Stuck: &conf.StuckRequestsConfig{Enabled: true}}))
+}
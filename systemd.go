@@ -0,0 +1,160 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-lynx/lynx/log"
+)
+
+// listenFDsStart is the first file descriptor systemd passes to socket-activated services (SD_LISTEN_FDS_START).
+const listenFDsStart = 3
+
+// systemdListener returns the socket systemd passed to this process, or nil when the process was not
+// socket activated. With a name it selects the socket with that FileDescriptorName=.
+func systemdListener(name string) (net.Listener, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || n <= 0 {
+		return nil, nil
+	}
+	var names []string
+	if v := os.Getenv("LISTEN_FDNAMES"); v != "" {
+		names = strings.Split(v, ":")
+	}
+	files := make([]*os.File, n)
+	for i := range files {
+		files[i] = os.NewFile(uintptr(listenFDsStart+i), fmt.Sprintf("LISTEN_FD_%d", listenFDsStart+i))
+	}
+	return inheritedListener(files, names, name)
+}
+
+// inheritedListener builds a listener from the selected inherited file. The file itself is left open:
+// other sockets in the set may belong to other servers in the same process.
+func inheritedListener(files []*os.File, names []string, name string) (net.Listener, error) {
+	idx := 0
+	if name != "" {
+		idx = -1
+		for i, n := range names {
+			if n == name && i < len(files) {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return nil, fmt.Errorf("no inherited socket named %q (have %q)", name, strings.Join(names, ":"))
+		}
+	}
+	lis, err := net.FileListener(files[idx])
+	if err != nil {
+		return nil, fmt.Errorf("inherited socket %d is not a listener: %w", listenFDsStart+idx, err)
+	}
+	return lis, nil
+}
+
+// sdNotify sends a state notification to the service manager. It reports false without error when
+// NOTIFY_SOCKET is not set, i.e. the process does not run under a Type=notify unit.
+func sdNotify(state string) (bool, error) {
+	addr := os.Getenv("NOTIFY_SOCKET")
+	if addr == "" {
+		return false, nil
+	}
+	if strings.HasPrefix(addr, "@") {
+		// Abstract namespace socket.
+		addr = "\x00" + addr[1:]
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: addr, Net: "unixgram"})
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// sdWatchdogInterval returns how often to send WATCHDOG=1: half the WatchdogSec= of the unit, or 0
+// when the watchdog is not enabled for this process.
+func sdWatchdogInterval() time.Duration {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	if v := os.Getenv("WATCHDOG_PID"); v != "" {
+		if pid, err := strconv.Atoi(v); err != nil || pid != os.Getpid() {
+			return 0
+		}
+	}
+	return time.Duration(usec) * time.Microsecond / 2
+}
+
+// systemdNotify sends state when notifications are enabled; failures are logged, never fatal.
+func (h *ServiceHttp) systemdNotify(state string) {
+	h.confMu.RLock()
+	enabled := h.conf != nil && h.conf.GetSystemd().GetNotify()
+	h.confMu.RUnlock()
+	if !enabled {
+		return
+	}
+	if _, err := sdNotify(state); err != nil {
+		log.Warnf("Failed to notify systemd (%s): %v", strings.SplitN(state, "\n", 2)[0], err)
+	}
+}
+
+// startSystemdWatchdog pings the systemd watchdog while the runtime health check passes, so a wedged
+// server is restarted by the service manager. It stops when ctx is cancelled.
+func (h *ServiceHttp) startSystemdWatchdog(ctx context.Context) {
+	h.stopSystemdWatchdog()
+	if h.conf == nil || !h.conf.GetSystemd().GetWatchdog() {
+		return
+	}
+	interval := sdWatchdogInterval()
+	if interval <= 0 {
+		log.Warnf("systemd watchdog enabled but WATCHDOG_USEC is not set for this process; not sending keep-alives")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	h.watchdogCancel = cancel
+	h.watchdogDone = done
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := h.CheckRuntimeHealth(); err != nil {
+					log.Warnf("Skipping systemd watchdog keep-alive: %v", err)
+					continue
+				}
+				if _, err := sdNotify("WATCHDOG=1"); err != nil {
+					log.Warnf("Failed to send systemd watchdog keep-alive: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+func (h *ServiceHttp) stopSystemdWatchdog() {
+	if h.watchdogCancel != nil {
+		h.watchdogCancel()
+		h.watchdogCancel = nil
+	}
+	if h.watchdogDone != nil {
+		<-h.watchdogDone
+		h.watchdogDone = nil
+	}
+}
@@ -0,0 +1,87 @@
+package http
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func listenerFile(t *testing.T) (*os.File, string) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = lis.Close() })
+	f, err := lis.(*net.TCPListener).File()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+	return f, lis.Addr().String()
+}
+
+func TestInheritedListener_SelectsByName(t *testing.T) {
+	first, firstAddr := listenerFile(t)
+	second, secondAddr := listenerFile(t)
+	files := []*os.File{first, second}
+	names := []string{"grpc", "http"}
+
+	lis, err := inheritedListener(files, names, "")
+	require.NoError(t, err)
+	assert.Equal(t, firstAddr, lis.Addr().String())
+	_ = lis.Close()
+
+	lis, err = inheritedListener(files, names, "http")
+	require.NoError(t, err)
+	assert.Equal(t, secondAddr, lis.Addr().String())
+	_ = lis.Close()
+
+	_, err = inheritedListener(files, names, "admin")
+	assert.Error(t, err)
+}
+
+func TestSystemdListener_NotActivated(t *testing.T) {
+	t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()+1))
+	t.Setenv("LISTEN_FDS", "1")
+	lis, err := systemdListener("")
+	assert.NoError(t, err)
+	assert.Nil(t, lis)
+}
+
+func TestSdNotify(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	sent, err := sdNotify("READY=1")
+	assert.NoError(t, err)
+	assert.False(t, sent)
+
+	path := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	require.NoError(t, err)
+	defer conn.Close()
+	t.Setenv("NOTIFY_SOCKET", path)
+
+	sent, err = sdNotify("READY=1")
+	require.NoError(t, err)
+	assert.True(t, sent)
+
+	buf := make([]byte, 64)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "READY=1", string(buf[:n]))
+}
+
+func TestSdWatchdogInterval(t *testing.T) {
+	t.Setenv("WATCHDOG_USEC", "")
+	assert.Zero(t, sdWatchdogInterval())
+
+	t.Setenv("WATCHDOG_USEC", "4000000")
+	t.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()))
+	assert.Equal(t, 2*time.Second, sdWatchdogInterval())
+
+	t.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()+1))
+	assert.Zero(t, sdWatchdogInterval())
+}
@@ -0,0 +1,338 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net"
+	nhttp "net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+const (
+	// TenantRequiredReason is the Kratos error reason for requests without a tenant identifier when
+	// tenant.required is set.
+	TenantRequiredReason = "TENANT_REQUIRED"
+	// TenantNotFoundReason is the Kratos error reason for identifiers the resolver does not know.
+	TenantNotFoundReason = "TENANT_NOT_FOUND"
+
+	tenantResolverUnavailableReason = "TENANT_RESOLVER_UNAVAILABLE"
+
+	tenantSourceSubdomain = "subdomain"
+	tenantSourcePath      = "path"
+
+	defaultTenantPathPrefix = "/"
+	defaultTenantBaggageKey = "tenant.id"
+	defaultTenantCacheTTL   = time.Minute
+	defaultTenantCacheSize  = 10000
+)
+
+// Tenant is the tenant a request was resolved to.
+type Tenant struct {
+	// ID identifies the tenant, e.g. its database key.
+	ID string
+	// Identifier is the subdomain or path segment the tenant was resolved from.
+	Identifier string
+	// Attributes carries resolver-specific data such as the tenant's plan.
+	Attributes map[string]string
+}
+
+// TenantResolver maps an identifier from the request to its tenant. Unknown identifiers return nil and no
+// error; errors fail the request with 503.
+type TenantResolver interface {
+	ResolveTenant(ctx context.Context, identifier string) (*Tenant, error)
+}
+
+// TenantResolverFunc adapts a function to TenantResolver.
+type TenantResolverFunc func(ctx context.Context, identifier string) (*Tenant, error)
+
+// ResolveTenant calls f.
+func (f TenantResolverFunc) ResolveTenant(ctx context.Context, identifier string) (*Tenant, error) {
+	return f(ctx, identifier)
+}
+
+// StaticTenantResolver resolves the identifiers in tenants to the mapped tenant IDs.
+func StaticTenantResolver(tenants map[string]string) TenantResolver {
+	return TenantResolverFunc(func(_ context.Context, identifier string) (*Tenant, error) {
+		id, ok := tenants[identifier]
+		if !ok {
+			return nil, nil
+		}
+		return &Tenant{ID: id, Identifier: identifier}, nil
+	})
+}
+
+// identityTenantResolver resolves every identifier to the tenant with that ID.
+var identityTenantResolver = TenantResolverFunc(func(_ context.Context, identifier string) (*Tenant, error) {
+	return &Tenant{ID: identifier, Identifier: identifier}, nil
+})
+
+// CachedTenantResolver caches the results of a resolver, unknown identifiers included, so lookups such as
+// database queries run once per identifier and TTL. Errors are not cached.
+type CachedTenantResolver struct {
+	resolver TenantResolver
+	ttl      time.Duration
+	size     int
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cachedTenant
+}
+
+type cachedTenant struct {
+	tenant  *Tenant
+	expires time.Time
+}
+
+// NewCachedTenantResolver returns resolver with its results cached for ttl, keeping at most size of them.
+func NewCachedTenantResolver(resolver TenantResolver, ttl time.Duration, size int) *CachedTenantResolver {
+	return &CachedTenantResolver{
+		resolver: resolver,
+		ttl:      ttl,
+		size:     size,
+		now:      time.Now,
+		entries:  make(map[string]cachedTenant),
+	}
+}
+
+// ResolveTenant returns the cached result for identifier, resolving it on a miss.
+func (c *CachedTenantResolver) ResolveTenant(ctx context.Context, identifier string) (*Tenant, error) {
+	now := c.now()
+	c.mu.Lock()
+	e, ok := c.entries[identifier]
+	c.mu.Unlock()
+	if ok && now.Before(e.expires) {
+		return e.tenant, nil
+	}
+	tenant, err := c.resolver.ResolveTenant(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= c.size {
+		c.evictLocked(now)
+	}
+	c.entries[identifier] = cachedTenant{tenant: tenant, expires: now.Add(c.ttl)}
+	return tenant, nil
+}
+
+// evictLocked drops the expired entries, or an arbitrary one when none has expired.
+func (c *CachedTenantResolver) evictLocked(now time.Time) {
+	for k, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	for k := range c.entries {
+		if len(c.entries) < c.size {
+			return
+		}
+		delete(c.entries, k)
+	}
+}
+
+// RegisterTenantResolver registers a resolver under the name used in tenant.resolver.
+func (h *ServiceHttp) RegisterTenantResolver(name string, resolver TenantResolver) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("invalid tenant resolver name %q", name)
+	}
+	if resolver == nil {
+		return fmt.Errorf("tenant resolver %q: resolver is nil", name)
+	}
+	h.tenantResolverMu.Lock()
+	defer h.tenantResolverMu.Unlock()
+	if _, dup := h.tenantResolvers[name]; dup {
+		return fmt.Errorf("tenant resolver %q already registered", name)
+	}
+	if h.tenantResolvers == nil {
+		h.tenantResolvers = make(map[string]TenantResolver)
+	}
+	h.tenantResolvers[name] = resolver
+	return nil
+}
+
+func (h *ServiceHttp) tenantResolver(name string) (TenantResolver, bool) {
+	h.tenantResolverMu.RLock()
+	defer h.tenantResolverMu.RUnlock()
+	resolver, ok := h.tenantResolvers[name]
+	return resolver, ok
+}
+
+// ResolvedTenantKey carries the tenant resolved by the tenant middleware, which also sets its ID with
+// WithTenant.
+var ResolvedTenantKey = NewContextKey[*Tenant]("lynx.http.resolved_tenant")
+
+// ResolvedTenantFromContext returns the tenant resolved by the tenant middleware.
+func ResolvedTenantFromContext(ctx context.Context) (*Tenant, bool) {
+	return ResolvedTenantKey.Value(ctx)
+}
+
+// tenantPolicy is the parsed tenant configuration.
+type tenantPolicy struct {
+	source      string
+	baseDomains []string
+	pathPrefix  string
+	required    bool
+	static      map[string]string
+	resolver    string
+	cacheTTL    time.Duration
+	cacheSize   int
+	baggageKey  string
+	exempt      []string
+}
+
+// newTenantPolicy returns the policy of cfg, or nil when tenant extraction is disabled.
+func newTenantPolicy(cfg *conf.TenantConfig) *tenantPolicy {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	p := &tenantPolicy{
+		source:     configuredPath(cfg.GetSource(), tenantSourceSubdomain),
+		pathPrefix: configuredPath(cfg.GetPathPrefix(), defaultTenantPathPrefix),
+		required:   cfg.GetRequired(),
+		static:     cfg.GetStatic(),
+		resolver:   strings.TrimSpace(cfg.GetResolver()),
+		cacheTTL:   defaultTenantCacheTTL,
+		cacheSize:  defaultTenantCacheSize,
+		baggageKey: configuredPath(cfg.GetBaggageKey(), defaultTenantBaggageKey),
+		exempt:     cfg.GetExemptOperations(),
+	}
+	for _, domain := range cfg.GetBaseDomains() {
+		p.baseDomains = append(p.baseDomains, normalizeHost(domain))
+	}
+	if !strings.HasSuffix(p.pathPrefix, "/") {
+		p.pathPrefix += "/"
+	}
+	if cfg.GetCacheTtl() != nil {
+		p.cacheTTL = cfg.GetCacheTtl().AsDuration()
+	}
+	if cfg.GetCacheSize() > 0 {
+		p.cacheSize = int(cfg.GetCacheSize())
+	}
+	return p
+}
+
+// identifier extracts the tenant identifier of a request to host and path, or "".
+func (p *tenantPolicy) identifier(host, path string) string {
+	if p.source == tenantSourcePath {
+		rest, ok := strings.CutPrefix(path, p.pathPrefix)
+		if !ok {
+			return ""
+		}
+		segment, _, _ := strings.Cut(rest, "/")
+		return segment
+	}
+	host = normalizeHost(host)
+	for _, base := range p.baseDomains {
+		if sub, ok := strings.CutSuffix(host, "."+base); ok {
+			return sub[strings.LastIndex(sub, ".")+1:]
+		}
+	}
+	if len(p.baseDomains) > 0 || strings.Count(host, ".") < 2 || net.ParseIP(host) != nil {
+		return ""
+	}
+	label, _, _ := strings.Cut(host, ".")
+	return label
+}
+
+// newTenantResolver returns the resolver of p: the registered one, cached, else the static map, else identity.
+func (h *ServiceHttp) newTenantResolver(p *tenantPolicy) TenantResolver {
+	if p.resolver != "" {
+		resolver, ok := h.tenantResolver(p.resolver)
+		if !ok {
+			log.Warnf("Tenant resolver %q is configured but not registered; tenant requests will fail", p.resolver)
+			return TenantResolverFunc(func(context.Context, string) (*Tenant, error) {
+				return nil, fmt.Errorf("tenant resolver %q not registered", p.resolver)
+			})
+		}
+		if p.cacheTTL <= 0 {
+			return resolver
+		}
+		return NewCachedTenantResolver(resolver, p.cacheTTL, p.cacheSize)
+	}
+	if len(p.static) > 0 {
+		return StaticTenantResolver(p.static)
+	}
+	return identityTenantResolver
+}
+
+// tenantMiddleware resolves the tenant of each request and adds it to the context and the baggage.
+func (h *ServiceHttp) tenantMiddleware(p *tenantPolicy) middleware.Middleware {
+	resolver := h.newTenantResolver(p)
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			var host, path, operation string
+			if r, ok := http.RequestFromServerContext(ctx); ok && r != nil {
+				host, path = r.Host, r.URL.Path
+			}
+			if tr, ok := transport.FromServerContext(ctx); ok {
+				operation = tr.Operation()
+			}
+			if routeMatchesAny(p.exempt, operation, path) {
+				return handler(ctx, req)
+			}
+
+			identifier := p.identifier(host, path)
+			if identifier == "" {
+				if p.required {
+					return nil, errors.BadRequest(TenantRequiredReason, "tenant identifier is required")
+				}
+				return handler(ctx, req)
+			}
+			tenant, err := resolver.ResolveTenant(ctx, identifier)
+			if err != nil {
+				log.WarnfCtx(ctx, "Failed to resolve tenant %q: %v", identifier, err)
+				return nil, errors.New(nhttp.StatusServiceUnavailable, tenantResolverUnavailableReason,
+					"tenant resolution unavailable").WithCause(err)
+			}
+			if tenant == nil {
+				return nil, errors.NotFound(TenantNotFoundReason, fmt.Sprintf("unknown tenant %q", identifier))
+			}
+			ctx = WithTenant(ResolvedTenantKey.WithValue(ctx, tenant), tenant.ID)
+			if member, err := baggage.NewMemberRaw(p.baggageKey, tenant.ID); err == nil {
+				if bag, err := baggage.FromContext(ctx).SetMember(member); err == nil {
+					ctx = baggage.ContextWithBaggage(ctx, bag)
+				}
+			}
+			return handler(ctx, req)
+		}
+	}
+}
+
+// validateTenantConfig requires a known source, a rooted path prefix, a baggage key without separators and
+// non-negative cache settings.
+func validateTenantConfig(cfg *conf.TenantConfig) error {
+	if !cfg.GetEnabled() {
+		return nil
+	}
+	switch source := configuredPath(cfg.GetSource(), tenantSourceSubdomain); source {
+	case tenantSourceSubdomain, tenantSourcePath:
+	default:
+		return fmt.Errorf("unknown source %q, valid options: %s, %s", source, tenantSourceSubdomain, tenantSourcePath)
+	}
+	if prefix := configuredPath(cfg.GetPathPrefix(), defaultTenantPathPrefix); !strings.HasPrefix(prefix, "/") {
+		return fmt.Errorf("path_prefix %q must start with /", prefix)
+	}
+	// Keys are sent in the baggage header, where separators and white space end them.
+	if key := configuredPath(cfg.GetBaggageKey(), defaultTenantBaggageKey); strings.ContainsAny(key, " \t,;=\"") {
+		return fmt.Errorf("invalid baggage_key %q: separators and white space are not allowed", key)
+	}
+	if cfg.GetCacheTtl() != nil && cfg.GetCacheTtl().AsDuration() < 0 {
+		return fmt.Errorf("cache_ttl must not be negative")
+	}
+	if cfg.GetCacheSize() < 0 {
+		return fmt.Errorf("cache_size must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package http
+
+import (
+	"context"
+	"errors"
+	nhttp "net/http"
+	"testing"
+	"time"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx-http/httptesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+func TestTenantIdentifier(t *testing.T) {
+	p := newTenantPolicy(&conf.TenantConfig{Enabled: true})
+	assert.Equal(t, "acme", p.identifier("ACME.example.com:8443", "/"))
+	assert.Equal(t, "", p.identifier("example.com", "/"))
+	assert.Equal(t, "", p.identifier("127.0.0.1:8080", "/"), "IP addresses are not subdomains")
+
+	p = newTenantPolicy(&conf.TenantConfig{Enabled: true, BaseDomains: []string{"apps.example.com"}})
+	assert.Equal(t, "acme", p.identifier("eu.acme.apps.example.com", "/"))
+	assert.Equal(t, "", p.identifier("acme.other.com", "/"))
+
+	p = newTenantPolicy(&conf.TenantConfig{Enabled: true, Source: "path", PathPrefix: "/t"})
+	assert.Equal(t, "acme", p.identifier("example.com", "/t/acme/orders"))
+	assert.Equal(t, "", p.identifier("example.com", "/v1/orders"))
+}
+
+func TestTenantMiddleware(t *testing.T) {
+	h := NewServiceHttp()
+	p := newTenantPolicy(&conf.TenantConfig{
+		Enabled:          true,
+		Required:         true,
+		Static:           map[string]string{"acme": "tenant-1"},
+		ExemptOperations: []string{"/api.v1.Health/*"},
+	})
+	tester := httptesting.NewMiddlewareTester(t, h.tenantMiddleware(p))
+
+	res := tester.Run(httptesting.Request{Operation: "/api.v1.Orders/List", Path: "http://acme.example.com/v1/orders"})
+	res.AssertNoError()
+	id, ok := TenantFromContext(res.HandlerContext)
+	assert.True(t, ok)
+	assert.Equal(t, "tenant-1", id)
+	tenant, ok := ResolvedTenantFromContext(res.HandlerContext)
+	require.True(t, ok)
+	assert.Equal(t, "acme", tenant.Identifier)
+	assert.Equal(t, "tenant-1", baggage.FromContext(res.HandlerContext).Member(defaultTenantBaggageKey).Value())
+
+	tester.Run(httptesting.Request{Operation: "/api.v1.Orders/List", Path: "http://globex.example.com/v1/orders"}).
+		AssertError(nhttp.StatusNotFound, TenantNotFoundReason)
+	tester.Run(httptesting.Request{Operation: "/api.v1.Orders/List", Path: "http://example.com/v1/orders"}).
+		AssertError(nhttp.StatusBadRequest, TenantRequiredReason)
+	tester.Run(httptesting.Request{Operation: "/api.v1.Health/Check", Path: "http://example.com/health"}).
+		AssertNoError()
+}
+
+func TestTenantMiddleware_RegisteredResolver(t *testing.T) {
+	h := NewServiceHttp()
+	var lookups int
+	require.NoError(t, h.RegisterTenantResolver("db", TenantResolverFunc(func(_ context.Context, identifier string) (*Tenant, error) {
+		lookups++
+		switch identifier {
+		case "acme":
+			return &Tenant{ID: "42", Identifier: identifier, Attributes: map[string]string{"plan": "pro"}}, nil
+		case "down":
+			return nil, errors.New("connection refused")
+		}
+		return nil, nil
+	})))
+	assert.ErrorContains(t, h.RegisterTenantResolver("db", StaticTenantResolver(nil)), "already registered")
+
+	p := newTenantPolicy(&conf.TenantConfig{Enabled: true, Source: "path", Resolver: "db"})
+	tester := httptesting.NewMiddlewareTester(t, h.tenantMiddleware(p))
+	for range 3 {
+		res := tester.Run(httptesting.Request{Operation: "/api.v1.Orders/List", Path: "/acme/orders"})
+		res.AssertNoError()
+		tenant, _ := ResolvedTenantFromContext(res.HandlerContext)
+		assert.Equal(t, "pro", tenant.Attributes["plan"])
+	}
+	tester.Run(httptesting.Request{Operation: "/api.v1.Orders/List", Path: "/globex/orders"}).
+		AssertError(nhttp.StatusNotFound, TenantNotFoundReason)
+	tester.Run(httptesting.Request{Operation: "/api.v1.Orders/List", Path: "/globex/orders"}).
+		AssertError(nhttp.StatusNotFound, TenantNotFoundReason)
+	assert.Equal(t, 2, lookups, "hits and misses are cached")
+
+	tester.Run(httptesting.Request{Operation: "/api.v1.Orders/List", Path: "/down/orders"}).
+		AssertError(nhttp.StatusServiceUnavailable, tenantResolverUnavailableReason)
+	tester.Run(httptesting.Request{Operation: "/api.v1.Orders/List", Path: "/down/orders"})
+	assert.Equal(t, 4, lookups, "errors are not cached")
+}
+
+func TestCachedTenantResolver(t *testing.T) {
+	var lookups int
+	c := NewCachedTenantResolver(TenantResolverFunc(func(_ context.Context, identifier string) (*Tenant, error) {
+		lookups++
+		return &Tenant{ID: identifier}, nil
+	}), time.Minute, 2)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	for _, id := range []string{"a", "b", "a", "c", "d"} {
+		_, err := c.ResolveTenant(context.Background(), id)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 4, lookups)
+	assert.Len(t, c.entries, 2, "bounded by size")
+
+	now = now.Add(2 * time.Minute)
+	_, _ = c.ResolveTenant(context.Background(), "d")
+	assert.Equal(t, 5, lookups, "expired")
+}
+
+func TestValidateTenantConfig(t *testing.T) {
+	require.NoError(t, validateTenantConfig(nil))
+	require.NoError(t, validateTenantConfig(&conf.TenantConfig{Enabled: true}))
+	assert.ErrorContains(t, validateTenantConfig(&conf.TenantConfig{Enabled: true, Source: "header"}), "unknown source")
+	assert.ErrorContains(t, validateTenantConfig(&conf.TenantConfig{Enabled: true, PathPrefix: "t/"}), "must start with /")
+	assert.ErrorContains(t, validateTenantConfig(&conf.TenantConfig{Enabled: true, BaggageKey: "tenant id"}), "invalid baggage_key")
+	assert.ErrorContains(t, validateTenantConfig(&conf.TenantConfig{Enabled: true, CacheSize: -1}), "must not be negative")
+}
@@ -0,0 +1,173 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	nhttp "net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+	"google.golang.org/protobuf/proto"
+)
+
+// TestService is a ServiceHttp started in-process on an ephemeral port, for integration tests that go
+// through the real filters, middleware chain and encoders. Register handlers on Server() after creating it.
+type TestService struct {
+	*ServiceHttp
+	baseURL string
+	client  *nhttp.Client
+	done    chan error
+}
+
+// NewTestService starts a service with cfg applied the way the plugin applies loaded configuration:
+// defaults are filled in and the result is validated. The service listens on an ephemeral port on
+// 127.0.0.1 instead of the configured address; cfg itself is not modified. Call Close when done.
+func NewTestService(cfg *conf.Http) (*TestService, error) {
+	h := NewServiceHttp()
+	h.conf = &conf.Http{}
+	if cfg != nil {
+		h.conf = proto.Clone(cfg).(*conf.Http)
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen: %w", err)
+	}
+	h.listener = lis
+	h.conf.Network, h.conf.Addr = "tcp", lis.Addr().String()
+	h.setDefaultConfig()
+	h.refreshMonitoringSnapshotLocked()
+	if err := h.validateConfig(); err != nil {
+		_ = lis.Close()
+		return nil, fmt.Errorf("HTTP configuration validation failed: %w", err)
+	}
+	if err := h.StartupTasks(); err != nil {
+		_ = lis.Close()
+		return nil, err
+	}
+	endpoint, err := h.server.Endpoint()
+	if err != nil {
+		_ = h.CleanupTasks()
+		return nil, err
+	}
+
+	s := &TestService{
+		ServiceHttp: h,
+		baseURL:     endpoint.String(),
+		client:      &nhttp.Client{Timeout: 30 * time.Second},
+		done:        make(chan error, 1),
+	}
+	if h.conf.GetTlsEnable() {
+		// Test certificates are rarely issued for 127.0.0.1.
+		s.client.Transport = &nhttp.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	}
+	go func() {
+		s.done <- h.server.Start(context.Background())
+	}()
+	return s, nil
+}
+
+// Server returns the Kratos server, on which services and handlers are registered.
+func (s *TestService) Server() *http.Server { return s.server }
+
+// URL returns the base URL of the service, e.g. "http://127.0.0.1:41235".
+func (s *TestService) URL() string { return s.baseURL }
+
+// Client returns the client used by Do, which trusts the service's certificate when TLS is enabled.
+func (s *TestService) Client() *nhttp.Client { return s.client }
+
+// Close shuts the service down the way the plugin does on stop.
+func (s *TestService) Close() error {
+	if err := s.CleanupTasks(); err != nil {
+		return err
+	}
+	if err := <-s.done; err != nil {
+		log.Warnf("Test HTTP service stopped with error: %v", err)
+		return err
+	}
+	return nil
+}
+
+// Do sends r, whose URL may be relative to the service, and reads the whole response.
+func (s *TestService) Do(r *nhttp.Request) (*TestResponse, error) {
+	if r.URL.Host == "" {
+		base, err := url.Parse(s.baseURL)
+		if err != nil {
+			return nil, err
+		}
+		r.URL = base.ResolveReference(r.URL)
+		r.Host = r.URL.Host
+	}
+	res, err := s.client.Do(r)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &TestResponse{StatusCode: res.StatusCode, Header: res.Header, Body: body}, nil
+}
+
+// Get sends a GET request for path.
+func (s *TestService) Get(path string) (*TestResponse, error) {
+	r, err := nhttp.NewRequest(nhttp.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.Do(r)
+}
+
+// PostJSON sends body, marshalled to JSON, to path.
+func (s *TestService) PostJSON(path string, body any) (*TestResponse, error) {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	r, err := nhttp.NewRequest(nhttp.MethodPost, path, bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	r.Header.Set("Content-Type", "application/json")
+	return s.Do(r)
+}
+
+// TestResponse is a response read by TestService.
+type TestResponse struct {
+	StatusCode int
+	Header     nhttp.Header
+	Body       []byte
+}
+
+// Code returns the code of the response envelope, 0 when the body is not an envelope. Successful
+// responses carry 200; errors carry their business code even though the HTTP status is usually 200.
+func (r *TestResponse) Code() int {
+	var env envelope
+	_ = json.Unmarshal(r.Body, &env)
+	return env.Code
+}
+
+// Message returns the message of the response envelope.
+func (r *TestResponse) Message() string {
+	var env envelope
+	_ = json.Unmarshal(r.Body, &env)
+	return env.Message
+}
+
+// Decode unwraps the envelope into v like DecodeEnvelope: a code other than 200 is returned as a Kratos
+// error carrying it.
+func (r *TestResponse) Decode(v any, opts ...DecodeOption) error {
+	data, err := unwrapEnvelope(r.Body, newDecodeOptions(opts))
+	if err != nil || data == nil || v == nil {
+		return err
+	}
+	return http.CodecForResponse(&nhttp.Response{Header: r.Header}).Unmarshal(data, v)
+}
@@ -0,0 +1,46 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTestService(t *testing.T) {
+	cfg := &conf.Http{Addr: ":9999", Monitoring: &conf.MonitoringConfig{HealthPath: "/health"}}
+	svc, err := NewTestService(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, ":9999", cfg.Addr, "the caller's config is left untouched")
+
+	r := svc.Server().Route("/")
+	r.GET("/hello/{name}", func(ctx http.Context) error {
+		return ctx.Result(200, map[string]string{"greeting": "hello " + ctx.Vars().Get("name")})
+	})
+	r.POST("/orders", func(ctx http.Context) error {
+		return errors.Conflict("ORDER_EXISTS", "order already exists")
+	})
+
+	res, err := svc.Get("/hello/lynx")
+	require.NoError(t, err)
+	assert.Equal(t, 200, res.Code())
+	var out struct {
+		Greeting string `json:"greeting"`
+	}
+	require.NoError(t, res.Decode(&out))
+	assert.Equal(t, "hello lynx", out.Greeting)
+
+	res, err = svc.PostJSON("/orders", map[string]int{"id": 1})
+	require.NoError(t, err)
+	assert.Equal(t, 409, res.Code())
+	assert.Equal(t, 409, errors.Code(res.Decode(nil)))
+
+	res, err = svc.Get("/health")
+	require.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode, string(res.Body))
+
+	require.NoError(t, svc.Close())
+}
@@ -1,9 +1,11 @@
 package http
 
 import (
+	"bytes"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"sync/atomic"
 
 	"github.com/go-kratos/kratos/v2/transport/http"
 	"github.com/go-lynx/lynx/log"
@@ -46,22 +48,22 @@ func (h *ServiceHttp) tlsLoad() (http.ServerOption, error) {
 		log.Warnf("No root CA certificate provided, client certificate verification will be disabled")
 	}
 
+	// Virtual hosts with their own certificate are served by SNI server name
+	vhostCerts, err := loadVirtualHostCertificates(h.conf.GetVirtualHosts())
+	if err != nil {
+		return nil, err
+	}
+
 	// Use GetCertificate callback for hot reload: file watch and auto rotation update certs without restart
 	tlsConfig := &tls.Config{
-		GetCertificate: func(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
-			certPEM := certProvider.GetCertificate()
-			keyPEM := certProvider.GetPrivateKey()
-			if len(certPEM) == 0 || len(keyPEM) == 0 {
-				return nil, fmt.Errorf("server certificate or private key not provided")
-			}
-			cert, err := tls.X509KeyPair(certPEM, keyPEM)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse X509 key pair: %w", err)
-			}
-			return &cert, nil
-		},
-		ServerName: currentLynxName(),
-		ClientAuth: tls.ClientAuthType(h.conf.GetTlsAuthType()),
+		GetCertificate: sniCertificateGetter(vhostCerts, h.certificateGetter(certProvider)),
+		ServerName:     currentLynxName(),
+		ClientAuth:     tls.ClientAuthType(h.conf.GetTlsAuthType()),
+	}
+
+	// Fingerprints read the JA3 hash of the connection's ClientHello
+	if fingerprintUsesJA3(h.conf.GetSecurity().GetFingerprint()) {
+		tlsConfig.GetConfigForClient = h.captureClientHello
 	}
 
 	// Only set ClientCAs if we have a valid certificate pool
@@ -72,3 +74,45 @@ func (h *ServiceHttp) tlsLoad() (http.ServerOption, error) {
 	log.Infof("TLS configuration created successfully with client auth type: %d", h.conf.GetTlsAuthType())
 	return http.TLSConfig(tlsConfig), nil
 }
+
+// keyPairSource hands out the current server certificate and private key in PEM form.
+type keyPairSource interface {
+	GetCertificate() []byte
+	GetPrivateKey() []byte
+}
+
+// certificateGetter returns the GetCertificate callback serving the key pair of source. The pair is parsed
+// again only when source hands out new PEM data, which also emits EventCertificateReloaded.
+func (h *ServiceHttp) certificateGetter(source keyPairSource) func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	var served atomic.Pointer[servedCertificate]
+	return func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		certPEM := source.GetCertificate()
+		keyPEM := source.GetPrivateKey()
+		if len(certPEM) == 0 || len(keyPEM) == 0 {
+			return nil, fmt.Errorf("server certificate or private key not provided")
+		}
+		if cur := served.Load(); cur != nil && cur.matches(certPEM, keyPEM) {
+			return cur.cert, nil
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse X509 key pair: %w", err)
+		}
+		next := &servedCertificate{certPEM: certPEM, keyPEM: keyPEM, cert: &cert}
+		if prev := served.Swap(next); prev != nil && !prev.matches(certPEM, keyPEM) {
+			h.emitCertificateReloaded(&cert)
+		}
+		return &cert, nil
+	}
+}
+
+// servedCertificate is the key pair last parsed from the certificate provider, so handshakes only parse it
+// again once the provider hands out new PEM data.
+type servedCertificate struct {
+	certPEM, keyPEM []byte
+	cert            *tls.Certificate
+}
+
+func (c *servedCertificate) matches(certPEM, keyPEM []byte) bool {
+	return bytes.Equal(c.certPEM, certPEM) && bytes.Equal(c.keyPEM, keyPEM)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	kratoslog "github.com/go-kratos/kratos/v2/log"
 	"github.com/go-kratos/kratos/v2/middleware"
 	"github.com/go-kratos/kratos/v2/transport"
 	"github.com/go-lynx/lynx/log"
@@ -19,6 +20,7 @@ const (
 	traceIDNone     = "none"
 	spanIDNone      = "none"
 
+	// Legacy printf-style formats, used only when MonitoringConfig.legacy_log_format is set.
 	httpRequestLogFormat  = "[HTTP Request] api=%s endpoint=%s client-ip=%s headers=%s body=%s"
 	httpResponseLogFormat = "[HTTP Response] api=%s endpoint=%s duration=%v error=%v headers=%s body=%s"
 )
@@ -60,8 +62,12 @@ func traceIDAndSpanIDFromSpan(span trace.SpanContext) (traceID, spanID string) {
 	return traceIDNone, spanIDNone
 }
 
-// getClientIP returns the client IP address.
-func getClientIP(header transport.Header) string {
+// getClientIP returns the client IP address. An address announced by a trusted PROXY protocol header
+// takes precedence over forwarding headers, which the client controls.
+func getClientIP(ctx context.Context, header transport.Header) string {
+	if ip := proxyClientAddr(ctx); ip != "" {
+		return ip
+	}
 	for _, key := range []string{"X-Forwarded-For", "X-Real-IP"} {
 		if ip := header.Get(key); ip != "" {
 			return ip
@@ -70,6 +76,139 @@ func getClientIP(header transport.Header) string {
 	return "unknown"
 }
 
+// httpLogRecord carries the per-request identifiers shared by the request and response log entries.
+type httpLogRecord struct {
+	api      string
+	endpoint string
+	clientIP string
+	traceID  string
+	spanID   string
+	// boost is the active log boost matching the request, if any.
+	boost *logBoost
+}
+
+// logHTTPRequest emits the inbound request entry. Structured fields are the default so log pipelines can
+// index api/endpoint/client_ip/trace_id without regex parsing; MonitoringConfig.legacy_log_format restores
+// the printf-style line. Extra sinks from monitoring.log_sinks always receive the structured record.
+// Boosted requests are always logged structured, with the body rendered by the boost policy.
+func logHTTPRequest(ctx context.Context, service *ServiceHttp, rec httpLogRecord, header transport.Header, req any) {
+	body := omittedLogBody
+	if service == nil || !service.monitoringSnapshotOrDefault().perfMode {
+		body = summarizePayload(req)
+	}
+	level := kratoslog.LevelInfo
+	if rec.boost != nil {
+		body = formatReplyForLogWith(rec.boost.policy, service.monitoringSnapshotOrDefault().bodyLog, req)
+		level = rec.boost.level
+	}
+	keyvals := []any{
+		"msg", "[HTTP Request]",
+		"api", rec.api,
+		"endpoint", rec.endpoint,
+		"client_ip", rec.clientIP,
+		"trace_id", rec.traceID,
+		"span_id", rec.spanID,
+		"headers", logHeaders(service, header),
+		"body", body,
+	}
+	keyvals = append(keyvals, service.monitoringSnapshotOrDefault().podLogFields...)
+	keyvals = append(keyvals, fingerprintLogFields(ctx)...)
+	keyvals = append(keyvals, honeypotLogFields(ctx)...)
+	if rec.boost != nil {
+		keyvals = append(keyvals, "log_boost", true)
+	}
+	defer forwardLogRecord(service, level, keyvals)
+	if rec.boost != nil {
+		logwCtx(ctx, level, keyvals...)
+		return
+	}
+	if !lynxLogAccepts(service, level) {
+		return
+	}
+	if legacyLogFormatEnabled(service) {
+		headersStr := fmt.Sprintf("%#v", logHeaders(service, header))
+		log.InfofCtx(ctx, httpRequestLogFormat, rec.api, rec.endpoint, rec.clientIP, headersStr, body)
+		return
+	}
+	log.InfowCtx(ctx, keyvals...)
+}
+
+// logHTTPResponse emits the completion entry. Failures go to the error level (when error logging is enabled)
+// with the error detail fields appended; everything else is logged at info when request logging is enabled.
+func logHTTPResponse(ctx context.Context, service *ServiceHttp, rec httpLogRecord, duration time.Duration,
+	header transport.Header, reply any, err error) {
+	logError := err != nil && (errorLoggingEnabled(service) || rec.boost != nil)
+	if !logError && !requestLoggingEnabled(service) && rec.boost == nil {
+		return
+	}
+
+	var respBody string
+	level := kratoslog.LevelInfo
+	if rec.boost != nil {
+		respBody = formatReplyForLogWith(rec.boost.policy, service.monitoringSnapshotOrDefault().bodyLog, reply)
+		level = rec.boost.level
+	} else {
+		respBody = replyLogBody(service, rec.api, reply)
+	}
+	status := "success"
+	if err != nil {
+		status = "error"
+	}
+	keyvals := []any{
+		"msg", "[HTTP Response]",
+		"api", rec.api,
+		"endpoint", rec.endpoint,
+		"client_ip", rec.clientIP,
+		"duration_ms", float64(duration.Microseconds()) / 1000,
+		"status", status,
+		"code", errorCodeForLog(err),
+		"trace_id", rec.traceID,
+		"span_id", rec.spanID,
+		"headers", logHeaders(service, header),
+		"body", respBody,
+	}
+	keyvals = append(keyvals, service.monitoringSnapshotOrDefault().podLogFields...)
+	keyvals = append(keyvals, fingerprintLogFields(ctx)...)
+	keyvals = append(keyvals, honeypotLogFields(ctx)...)
+	if logError {
+		level = errorLogLevel(service, err)
+		keyvals = append(keyvals, errorLogFields(err)...)
+		keyvals = append(keyvals, errorChainLogFields(service, err)...)
+	}
+	if rec.boost != nil {
+		keyvals = append(keyvals, "log_boost", true)
+	}
+	defer forwardLogRecord(service, level, keyvals)
+	if rec.boost != nil {
+		logwCtx(ctx, level, keyvals...)
+		return
+	}
+	if !lynxLogAccepts(service, level) {
+		return
+	}
+
+	if legacyLogFormatEnabled(service) {
+		respHeadersStr := fmt.Sprintf("%#v", logHeaders(service, header))
+		if logError {
+			legacy := []any{
+				"msg", "[HTTP Response]",
+				"api", rec.api,
+				"endpoint", rec.endpoint,
+				"duration", duration,
+				"headers", respHeadersStr,
+				"body", respBody,
+			}
+			legacy = append(legacy, errorLogFields(err)...)
+			legacy = append(legacy, errorChainLogFields(service, err)...)
+			logwCtx(ctx, errorLogLevel(service, err), legacy...)
+			return
+		}
+		log.InfofCtx(ctx, httpResponseLogFormat, rec.api, rec.endpoint, duration, err, respHeadersStr, respBody)
+		return
+	}
+	logwCtx(ctx, level, keyvals...)
+}
+
 // TracerLogPack returns middleware that adds trace IDs and Content-Type headers to the response.
 // It extracts trace from context (or from request headers like W3C traceparent if not yet in context) and sets "Trace-Id" and "Span-Id" in response headers. Invalid/empty span is returned as "none".
 func TracerLogPack() middleware.Middleware {
@@ -93,7 +232,7 @@ func TracerLogPack() middleware.Middleware {
 			traceID, spanID := traceIDAndSpanIDFromSpan(span)
 
 			endpoint := tr.Endpoint()
-			clientIP := getClientIP(tr.RequestHeader())
+			clientIP := getClientIP(ctx, tr.RequestHeader())
 			api := tr.Operation()
 
 			defer func() {
@@ -105,32 +244,16 @@ func TracerLogPack() middleware.Middleware {
 				}
 			}()
 
+			rec := httpLogRecord{api: api, endpoint: endpoint, clientIP: clientIP, traceID: traceID, spanID: spanID}
+
 			// Log the request
 			if requestLoggingEnabled(nil) {
-				headersStr := fmt.Sprintf("%#v", sanitizeHeaders(tr.RequestHeader()))
-				log.InfofCtx(ctx, httpRequestLogFormat, api, endpoint, clientIP, headersStr, summarizePayload(req))
+				logHTTPRequest(ctx, nil, rec, tr.RequestHeader(), req)
 			}
 
 			reply, err = handler(ctx, req)
 
-			duration := time.Since(start)
-			respHeadersStr := fmt.Sprintf("%#v", sanitizeHeaders(tr.ReplyHeader()))
-			respBody := summarizePayload(reply)
-			if err != nil && errorLoggingEnabled(nil) {
-				keyvals := []any{
-					"msg", "[HTTP Response]",
-					"api", api,
-					"endpoint", endpoint,
-					"duration", duration,
-					"headers", respHeadersStr,
-					"body", respBody,
-				}
-				keyvals = append(keyvals, errorLogFields(err)...)
-				log.ErrorwCtx(ctx, keyvals...)
-			} else if requestLoggingEnabled(nil) {
-				log.InfofCtx(ctx, httpResponseLogFormat,
-					api, endpoint, duration, err, respHeadersStr, respBody)
-			}
+			logHTTPResponse(ctx, nil, rec, time.Since(start), tr.ReplyHeader(), reply, err)
 
 			return reply, err
 		}
@@ -159,7 +282,7 @@ func TracerLogPackWithMetrics(service *ServiceHttp) middleware.Middleware {
 			traceID, spanID := traceIDAndSpanIDFromSpan(span)
 
 			endpoint := tr.Endpoint()
-			clientIP := getClientIP(tr.RequestHeader())
+			clientIP := getClientIP(ctx, tr.RequestHeader())
 			api := tr.Operation()
 			method, metricPath := requestMetadata(ctx)
 
@@ -172,63 +295,69 @@ func TracerLogPackWithMetrics(service *ServiceHttp) middleware.Middleware {
 				}
 			}()
 
-			if service.requestLoggingEnabled() {
-				headersStr := fmt.Sprintf("%#v", sanitizeHeaders(tr.RequestHeader()))
-				log.InfofCtx(ctx, httpRequestLogFormat, api, endpoint, clientIP, headersStr, summarizePayload(req))
+			rec := httpLogRecord{api: api, endpoint: endpoint, clientIP: clientIP, traceID: traceID, spanID: spanID,
+				boost: service.matchLogBoost(api, traceID)}
+			if requestLoggingEnabled(service) || rec.boost != nil {
+				logHTTPRequest(ctx, service, rec, tr.RequestHeader(), req)
 			}
 
 			if service != nil && service.inflightRequests != nil {
 				service.inflightRequests.WithLabelValues(api).Inc()
 				defer service.inflightRequests.WithLabelValues(api).Dec()
 			}
+			if service != nil && service.otelMetrics != nil {
+				service.otelMetrics.addInflight(ctx, api, 1)
+				defer service.otelMetrics.addInflight(context.WithoutCancel(ctx), api, -1)
+			}
 
-			if service != nil && service.requestSize != nil {
+			measureSizes := service != nil && !service.monitoringSnapshotOrDefault().perfMode
+			if measureSizes && (service.requestSize != nil || service.otelMetrics != nil) {
+				// proto.Size walks the message without the allocation of a second marshal.
 				if msg, ok := req.(proto.Message); ok {
-					if data, e := proto.Marshal(msg); e == nil {
-						service.requestSize.WithLabelValues(method, metricPath).Observe(float64(len(data)))
+					size := proto.Size(msg)
+					if service.requestSize != nil {
+						service.requestSize.WithLabelValues(method, metricPath).Observe(float64(size))
 					}
+					service.otelMetrics.recordRequestSize(ctx, method, metricPath, size)
 				}
 			}
 
+			var stats *requestStats
+			if service != nil {
+				stats = service.startRequestStats()
+			}
 			reply, err = handler(ctx, req)
 
 			duration := time.Since(start)
-			respHeadersStr := fmt.Sprintf("%#v", sanitizeHeaders(tr.ReplyHeader()))
-			respBody := summarizePayload(reply)
-			if err != nil && service.errorLoggingEnabled() {
-				keyvals := []any{
-					"msg", "[HTTP Response]",
-					"api", api,
-					"endpoint", endpoint,
-					"duration", duration,
-					"headers", respHeadersStr,
-					"body", respBody,
-				}
-				keyvals = append(keyvals, errorLogFields(err)...)
-				log.ErrorwCtx(ctx, keyvals...)
-			} else if service.requestLoggingEnabled() {
-				log.InfofCtx(ctx, httpResponseLogFormat,
-					api, endpoint, duration, err, respHeadersStr, respBody)
-			}
+			logHTTPResponse(ctx, service, rec, duration, tr.ReplyHeader(), reply, err)
 
 			if service != nil {
+				stats.finish(metricPath, duration)
+				stats.countHitters(ctx, metricPath)
+				service.recordSLO(metricPath, duration, err)
+				service.recordAnomaly(metricPath, err)
 				if service.requestDuration != nil {
 					service.requestDuration.WithLabelValues(method, metricPath).Observe(duration.Seconds())
 				}
 
+				status := "success"
+				if err != nil {
+					status = "error"
+				}
 				if service.requestCounter != nil {
-					status := "success"
-					if err != nil {
-						status = "error"
-					}
 					service.requestCounter.WithLabelValues(method, metricPath, status).Inc()
 				}
+				service.otelMetrics.recordRequest(ctx, method, metricPath, status, duration.Seconds())
+				service.recordCallerMetrics(ctx, metricPath, method, status, duration.Seconds())
+				service.recordClientMetrics(ctx, status)
 
-				if service.responseSize != nil && reply != nil {
+				if measureSizes && (service.responseSize != nil || service.otelMetrics != nil) && reply != nil {
 					if msg, ok := reply.(proto.Message); ok {
-						if data, marshalErr := proto.Marshal(msg); marshalErr == nil {
-							service.responseSize.WithLabelValues(method, metricPath).Observe(float64(len(data)))
+						size := proto.Size(msg)
+						if service.responseSize != nil {
+							service.responseSize.WithLabelValues(method, metricPath).Observe(float64(size))
 						}
+						service.otelMetrics.recordResponseSize(ctx, method, metricPath, size)
 					}
 				}
 
@@ -0,0 +1,115 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// fakeTransport implements transport.Transporter for middleware tests.
+type fakeTransport struct {
+	operation   string
+	endpoint    string
+	reqHeader   *fakeHeader
+	replyHeader *fakeHeader
+}
+
+func newFakeTransport(operation string, reqHeaders map[string]string) *fakeTransport {
+	return &fakeTransport{
+		operation:   operation,
+		endpoint:    "http://127.0.0.1:8080",
+		reqHeader:   newFakeHeader(reqHeaders),
+		replyHeader: newFakeHeader(nil),
+	}
+}
+
+func (f *fakeTransport) Kind() transport.Kind            { return transport.KindHTTP }
+func (f *fakeTransport) Endpoint() string                { return f.endpoint }
+func (f *fakeTransport) Operation() string               { return f.operation }
+func (f *fakeTransport) RequestHeader() transport.Header { return f.reqHeader }
+func (f *fakeTransport) ReplyHeader() transport.Header   { return f.replyHeader }
+
+func TestLegacyLogFormatEnabled_FromSnapshot(t *testing.T) {
+	assert.False(t, legacyLogFormatEnabled(nil))
+
+	svc := NewServiceHttp()
+	svc.conf = &conf.Http{Monitoring: &conf.MonitoringConfig{LegacyLogFormat: true}}
+	svc.refreshMonitoringSnapshotLocked()
+	assert.True(t, legacyLogFormatEnabled(svc))
+
+	svc.conf.Monitoring.LegacyLogFormat = false
+	svc.refreshMonitoringSnapshotLocked()
+	assert.False(t, legacyLogFormatEnabled(svc))
+}
+
+func TestTracerLogPackWithMetrics_StructuredAndLegacy(t *testing.T) {
+	for _, legacy := range []bool{false, true} {
+		svc := NewServiceHttp()
+		svc.conf = &conf.Http{Monitoring: &conf.MonitoringConfig{
+			EnableRequestLogging: true,
+			EnableErrorLogging:   true,
+			LegacyLogFormat:      legacy,
+		}}
+		svc.refreshMonitoringSnapshotLocked()
+
+		tr := newFakeTransport("/api.v1.Users/Get", map[string]string{"X-Real-IP": "10.0.0.1"})
+		ctx := transport.NewServerContext(context.Background(), tr)
+		mw := TracerLogPackWithMetrics(svc)
+
+		_, err := mw(func(ctx context.Context, req any) (any, error) {
+			return "ok", nil
+		})(ctx, "req")
+		require.NoError(t, err)
+		assert.Equal(t, traceIDNone, tr.replyHeader.Get("Trace-Id"))
+
+		_, err = mw(func(ctx context.Context, req any) (any, error) {
+			return nil, errors.New("boom")
+		})(ctx, "req")
+		require.Error(t, err)
+	}
+}
+
+func TestTracerLogPackWithMetrics_PerfMode(t *testing.T) {
+	reply, err := structpb.NewStruct(map[string]any{"id": "usr_1"})
+	require.NoError(t, err)
+	for _, perfMode := range []bool{false, true} {
+		svc := benchService(perfMode, "json")
+		_, err := TracerLogPackWithMetrics(svc)(func(context.Context, any) (any, error) {
+			return reply, nil
+		})(transport.NewServerContext(context.Background(), newFakeTransport("/api.v1.Users/Get", nil)), reply)
+		require.NoError(t, err)
+
+		if perfMode {
+			assert.Equal(t, 0, testutil.CollectAndCount(svc.responseSize), "perf mode skips size histograms")
+			assert.Equal(t, omittedLogBody, replyLogBody(svc, "/api.v1.Users/Get", reply))
+		} else {
+			assert.Equal(t, 1, testutil.CollectAndCount(svc.responseSize))
+			assert.Equal(t, `{"id":"usr_1"}`, replyLogBody(svc, "/api.v1.Users/Get", reply))
+		}
+	}
+}
+
+func TestMetricsMiddleware_PerfMode(t *testing.T) {
+	reply, err := structpb.NewStruct(map[string]any{"id": "usr_1"})
+	require.NoError(t, err)
+	for _, perfMode := range []bool{false, true} {
+		svc := benchService(perfMode, "")
+		_, err := svc.metricsMiddleware()(func(context.Context, any) (any, error) {
+			return reply, nil
+		})(transport.NewServerContext(context.Background(), newFakeTransport("/api.v1.Users/Get", nil)), reply)
+		require.NoError(t, err)
+
+		if perfMode {
+			assert.Equal(t, 0, testutil.CollectAndCount(svc.responseSize), "perf mode skips size histograms")
+		} else {
+			assert.Equal(t, 1, testutil.CollectAndCount(svc.responseSize))
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	nhttp "net/http"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"google.golang.org/protobuf/proto"
+)
+
+// TypedHandler handles a request decoded into TReq and replies with TResp.
+type TypedHandler[TReq, TResp any] func(ctx context.Context, req *TReq) (*TResp, error)
+
+// validator is implemented by requests that check themselves, such as protoc-gen-validate messages.
+type validator interface {
+	Validate() error
+}
+
+// Register registers handler for method and path on srv the way generated proto handlers are registered, for
+// endpoints without a proto service. The request is decoded into TReq with the server's decoders: GET, HEAD
+// and DELETE requests from the query, others from the body, and both from the path variables. It then runs
+// through the server middleware chain, under the path template as its operation. Requests that are not proto
+// messages and have a Validate() error method are validated after the request filters and defaults, failing
+// with 400 VALIDATOR; proto requests are left to the validation middleware. The reply is written in the
+// standard envelope, and errors go through the error encoder like those of any handler:
+//
+//	http.Register(srv, "GET", "/v1/users/{id}", func(ctx context.Context, req *GetUserRequest) (*User, error) {
+//		return users.Get(ctx, req.ID)
+//	})
+func Register[TReq, TResp any](srv *http.Server, method, path string, handler TypedHandler[TReq, TResp]) {
+	method = strings.ToUpper(method)
+	fromQuery := method == nhttp.MethodGet || method == nhttp.MethodHead || method == nhttp.MethodDelete
+	hasVars := strings.Contains(path, "{")
+	srv.Route("/").Handle(method, path, func(ctx http.Context) error {
+		var in TReq
+		if fromQuery {
+			if err := ctx.BindQuery(&in); err != nil {
+				return err
+			}
+		} else if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		if hasVars {
+			if err := ctx.BindVars(&in); err != nil {
+				return err
+			}
+		}
+		h := ctx.Middleware(func(ctx context.Context, req any) (any, error) {
+			in := req.(*TReq)
+			if err := validateTypedRequest(in); err != nil {
+				return nil, err
+			}
+			return handler(ctx, in)
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		return ctx.Result(nhttp.StatusOK, out)
+	})
+}
+
+// validateTypedRequest runs the Validate method of requests that are not proto messages.
+func validateTypedRequest(req any) error {
+	if _, ok := req.(proto.Message); ok {
+		return nil
+	}
+	v, ok := req.(validator)
+	if !ok {
+		return nil
+	}
+	if err := v.Validate(); err != nil {
+		return errors.BadRequest("VALIDATOR", fmt.Sprintf("invalid request: %v", err)).WithCause(err)
+	}
+	return nil
+}
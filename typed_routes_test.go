@@ -0,0 +1,101 @@
+package http
+
+import (
+	"context"
+	"errors"
+	nhttp "net/http"
+	"strings"
+	"testing"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/sourcecontextpb"
+)
+
+type typedUserRequest struct {
+	ID      int64  `json:"id"`
+	Name    string `json:"name"`
+	Verbose bool   `json:"verbose"`
+}
+
+func (r *typedUserRequest) Validate() error {
+	if r.Name == "" && r.ID == 0 {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+type typedUser struct {
+	ID      int64  `json:"id"`
+	Name    string `json:"name"`
+	Verbose bool   `json:"verbose,omitempty"`
+}
+
+func TestRegister(t *testing.T) {
+	svc, err := NewTestService(nil)
+	require.NoError(t, err)
+	defer func() { _ = svc.Close() }()
+
+	Register(svc.Server(), "get", "/v1/users/{id}", func(_ context.Context, req *typedUserRequest) (*typedUser, error) {
+		if req.ID == 404 {
+			return nil, kerrors.NotFound("USER_NOT_FOUND", "no such user")
+		}
+		return &typedUser{ID: req.ID, Name: "ada", Verbose: req.Verbose}, nil
+	})
+	Register(svc.Server(), "POST", "/v1/users", func(_ context.Context, req *typedUserRequest) (*typedUser, error) {
+		return &typedUser{ID: 7, Name: req.Name}, nil
+	})
+
+	res, err := svc.Get("/v1/users/42?verbose=true")
+	require.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	var user typedUser
+	require.NoError(t, res.Decode(&user))
+	assert.Equal(t, typedUser{ID: 42, Name: "ada", Verbose: true}, user)
+
+	// Errors are written by the error encoder.
+	res, err = svc.Get("/v1/users/404")
+	require.NoError(t, err)
+	assert.Equal(t, 404, res.Code())
+
+	res, err = svc.PostJSON("/v1/users", map[string]any{"name": "grace"})
+	require.NoError(t, err)
+	var created typedUser
+	require.NoError(t, res.Decode(&created))
+	assert.Equal(t, typedUser{ID: 7, Name: "grace"}, created)
+
+	res, err = svc.PostJSON("/v1/users", map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, 400, res.Code(), "Validate rejects the request")
+
+	res, err = svc.PostJSON("/v1/users", "not an object")
+	require.NoError(t, err)
+	assert.Equal(t, 400, res.Code())
+}
+
+func TestRegister_ProtoMessages(t *testing.T) {
+	svc, err := NewTestService(nil)
+	require.NoError(t, err)
+	defer func() { _ = svc.Close() }()
+
+	Register(svc.Server(), "PUT", "/v1/sources/{file_name}", func(_ context.Context, req *sourcecontextpb.SourceContext) (*sourcecontextpb.SourceContext, error) {
+		return &sourcecontextpb.SourceContext{FileName: "copy of " + req.GetFileName()}, nil
+	})
+	req, err := nhttp.NewRequest(nhttp.MethodPut, "/v1/sources/a.proto", strings.NewReader(`{"file_name":"ignored"}`))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	res, err := svc.Do(req)
+	require.NoError(t, err)
+	var out sourcecontextpb.SourceContext
+	require.NoError(t, res.Decode(&out))
+	assert.Equal(t, "copy of a.proto", out.GetFileName(), "path variables are bound after the body")
+}
+
+func TestValidateTypedRequest(t *testing.T) {
+	require.NoError(t, validateTypedRequest(&typedUserRequest{Name: "ada"}))
+	require.NoError(t, validateTypedRequest(&typedUser{}), "requests without Validate")
+	err := validateTypedRequest(&typedUserRequest{})
+	assert.Equal(t, "VALIDATOR", kerrors.Reason(err))
+	assert.Equal(t, 400, int(kerrors.Code(err)))
+}
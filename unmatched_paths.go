@@ -0,0 +1,102 @@
+package http
+
+import (
+	nhttp "net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx/log"
+)
+
+const (
+	// unmatchedPathLabel is the path label of 404 and 405 requests beyond the tracked paths.
+	unmatchedPathLabel = "unmatched"
+	// maxUnmatchedPathLength bounds a tracked path label.
+	maxUnmatchedPathLength = 128
+)
+
+// unmatchedPathLabeler maps the raw paths of 404 and 405 requests to a bounded set of metric labels and
+// samples their logs. Like callerLabeler, it lives in the monitoring snapshot, so tracked paths start over
+// after a reconfiguration.
+type unmatchedPathLabeler struct {
+	trackedPaths int
+	suppress     []string
+	logEvery     uint64
+
+	logged atomic.Uint64
+
+	mu   sync.RWMutex
+	seen map[string]struct{}
+}
+
+// newUnmatchedPathLabeler never returns nil: without configuration every path is reported as "unmatched".
+func newUnmatchedPathLabeler(cfg *conf.UnmatchedPathsConfig) *unmatchedPathLabeler {
+	l := &unmatchedPathLabeler{logEvery: 1, seen: make(map[string]struct{})}
+	if cfg == nil {
+		return l
+	}
+	l.trackedPaths = int(cfg.TrackedPaths)
+	if cfg.LogEvery > 0 {
+		l.logEvery = uint64(cfg.LogEvery)
+	}
+	for _, pattern := range cfg.Suppress {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			l.suppress = append(l.suppress, pattern)
+		}
+	}
+	return l
+}
+
+// suppressed reports whether path is neither counted nor logged.
+func (l *unmatchedPathLabeler) suppressed(path string) bool {
+	for _, pattern := range l.suppress {
+		if wildcardMatches(pattern, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// label returns the metric label of path: the path itself while fewer than trackedPaths distinct paths have
+// been seen, "unmatched" afterwards.
+func (l *unmatchedPathLabeler) label(path string) string {
+	if l.trackedPaths <= 0 || len(path) > maxUnmatchedPathLength {
+		return unmatchedPathLabel
+	}
+	l.mu.RLock()
+	_, known := l.seen[path]
+	l.mu.RUnlock()
+	if known {
+		return path
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, known = l.seen[path]; known {
+		return path
+	}
+	if len(l.seen) >= l.trackedPaths {
+		return unmatchedPathLabel
+	}
+	l.seen[path] = struct{}{}
+	return path
+}
+
+// sampleLog reports whether this request is one of the logged ones.
+func (l *unmatchedPathLabeler) sampleLog() bool {
+	return (l.logged.Add(1)-1)%l.logEvery == 0
+}
+
+// recordUnmatchedRequest counts a 404 or 405 request under its bounded path label and logs its raw path when
+// sampled. Suppressed paths are skipped.
+func (h *ServiceHttp) recordUnmatchedRequest(r *nhttp.Request, status int, errorType string) {
+	l := h.monitoringSnapshotOrDefault().unmatched
+	if l.suppressed(r.URL.Path) {
+		return
+	}
+	h.recordErrorMetric(r.Method, l.label(r.URL.Path), errorType)
+	if l.sampleLog() {
+		log.Warnf("%d %s: %s %s", status, strings.ReplaceAll(errorType, "_", " "), r.Method, r.URL.Path)
+	}
+}
@@ -0,0 +1,56 @@
+package http
+
+import (
+	nhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmatchedPathLabeler(t *testing.T) {
+	l := newUnmatchedPathLabeler(&conf.UnmatchedPathsConfig{
+		TrackedPaths: 2,
+		Suppress:     []string{"/wp-admin*", " /.env "},
+		LogEvery:     3,
+	})
+
+	assert.Equal(t, "/old-api", l.label("/old-api"))
+	assert.Equal(t, "/favicon.ico", l.label("/favicon.ico"))
+	assert.Equal(t, "unmatched", l.label("/random-1"), "beyond tracked_paths")
+	assert.Equal(t, "/old-api", l.label("/old-api"))
+	assert.Equal(t, "unmatched", newUnmatchedPathLabeler(nil).label("/old-api"))
+	assert.Equal(t, "unmatched", newUnmatchedPathLabeler(&conf.UnmatchedPathsConfig{TrackedPaths: 10}).label("/"+strings.Repeat("x", 200)))
+
+	assert.True(t, l.suppressed("/wp-admin/setup.php"))
+	assert.True(t, l.suppressed("/.env"))
+	assert.False(t, l.suppressed("/.env.local"))
+
+	var logged int
+	for range 7 {
+		if l.sampleLog() {
+			logged++
+		}
+	}
+	assert.Equal(t, 3, logged)
+}
+
+func TestRecordUnmatchedRequest(t *testing.T) {
+	h := &ServiceHttp{
+		conf: &conf.Http{Monitoring: &conf.MonitoringConfig{
+			EnableErrorTypeMetrics: true,
+			UnmatchedPaths:         &conf.UnmatchedPathsConfig{Suppress: []string{"/wp-*"}},
+		}},
+		errorCounter: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "errors"}, []string{"method", "path", "type"}),
+	}
+	handler := h.notFoundHandler()
+	for _, path := range []string{"/a", "/b", "/wp-login.php"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(nhttp.MethodGet, path, nil))
+	}
+	assert.Equal(t, float64(2), testutil.ToFloat64(h.errorCounter.WithLabelValues("GET", "unmatched", "not_found")))
+	assert.Equal(t, 1, testutil.CollectAndCount(h.errorCounter), "suppressed paths are not counted")
+}
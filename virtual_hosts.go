@@ -0,0 +1,300 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	nhttp "net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+)
+
+const (
+	// MisdirectedRequestReason is the Kratos error reason for requests to hosts no virtual host serves, with
+	// virtual_hosts.reject_unknown_hosts set.
+	MisdirectedRequestReason = "MISDIRECTED_REQUEST"
+
+	routeNotFoundReason = "NOT_FOUND"
+
+	// defaultVirtualHostLabel is the metrics label of requests no virtual host serves.
+	defaultVirtualHostLabel = "default"
+)
+
+type virtualHostKey struct{}
+
+// VirtualHostFromContext returns the name of the virtual host serving the request, if one does.
+func VirtualHostFromContext(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(virtualHostKey{}).(string)
+	return name, ok
+}
+
+// virtualHost is a configured virtual host.
+type virtualHost struct {
+	name string
+	// domains are lower-case host names, or "*." followed by a parent domain.
+	domains []string
+	// operations are the operation or path patterns served; empty serves every route.
+	operations []string
+}
+
+// serves reports whether the host serves the route with operation or path.
+func (v *virtualHost) serves(operation, path string) bool {
+	return len(v.operations) == 0 || routeMatchesAny(v.operations, operation, path)
+}
+
+func (v *virtualHost) matches(host string) bool {
+	for _, domain := range v.domains {
+		if domainMatches(domain, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// virtualHostPolicy is the parsed virtual_hosts configuration.
+type virtualHostPolicy struct {
+	hosts         []*virtualHost
+	rejectUnknown bool
+}
+
+// newVirtualHostPolicy returns the policy of cfg, or nil without virtual hosts.
+func newVirtualHostPolicy(cfg *conf.VirtualHostsConfig) *virtualHostPolicy {
+	if len(cfg.GetHosts()) == 0 {
+		return nil
+	}
+	p := &virtualHostPolicy{rejectUnknown: cfg.GetRejectUnknownHosts()}
+	for _, hc := range cfg.GetHosts() {
+		v := &virtualHost{name: strings.TrimSpace(hc.GetName())}
+		for _, domain := range hc.GetDomains() {
+			v.domains = append(v.domains, normalizeHost(domain))
+		}
+		for _, op := range hc.GetOperations() {
+			if op = strings.TrimSpace(op); op != "" {
+				v.operations = append(v.operations, op)
+			}
+		}
+		p.hosts = append(p.hosts, v)
+	}
+	return p
+}
+
+// match returns the first virtual host serving host, or nil.
+func (p *virtualHostPolicy) match(host string) *virtualHost {
+	host = normalizeHost(host)
+	for _, v := range p.hosts {
+		if v.matches(host) {
+			return v
+		}
+	}
+	return nil
+}
+
+// normalizeHost lower-cases host and strips its port, IPv6 brackets and trailing dot.
+func normalizeHost(host string) string {
+	host = strings.TrimSpace(host)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
+
+// domainMatches reports whether host is pattern, or a subdomain of its parent when pattern starts with "*.".
+func domainMatches(pattern, host string) bool {
+	if parent, ok := strings.CutPrefix(pattern, "*."); ok {
+		return strings.HasSuffix(host, "."+parent)
+	}
+	return pattern == host
+}
+
+// UseVirtualHostMiddleware adds middleware run for requests served by the virtual host name, after the
+// server's own middleware up to sessions and before request filters. Middleware runs in registration order.
+func (h *ServiceHttp) UseVirtualHostMiddleware(name string, mws ...middleware.Middleware) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("virtual host name is empty")
+	}
+	for _, m := range mws {
+		if m == nil {
+			return fmt.Errorf("middleware for virtual host %q is nil", name)
+		}
+	}
+	h.virtualHostMu.Lock()
+	defer h.virtualHostMu.Unlock()
+	if h.virtualHostMiddlewares == nil {
+		h.virtualHostMiddlewares = make(map[string][]middleware.Middleware)
+	}
+	h.virtualHostMiddlewares[name] = append(h.virtualHostMiddlewares[name], mws...)
+	return nil
+}
+
+func (h *ServiceHttp) middlewaresOfVirtualHost(name string) []middleware.Middleware {
+	h.virtualHostMu.RLock()
+	defer h.virtualHostMu.RUnlock()
+	return h.virtualHostMiddlewares[name]
+}
+
+// virtualHostMiddleware resolves the virtual host of the request from its Host header, rejects routes the host
+// does not serve, and runs the host's middleware.
+func (h *ServiceHttp) virtualHostMiddleware(p *virtualHostPolicy) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (reply any, err error) {
+			var host, path string
+			if r, ok := http.RequestFromServerContext(ctx); ok && r != nil {
+				host, path = r.Host, r.URL.Path
+			}
+			var operation string
+			if tr, ok := transport.FromServerContext(ctx); ok {
+				operation = tr.Operation()
+			}
+			_, route := requestMetadata(ctx)
+
+			v := p.match(host)
+			if v == nil {
+				if p.rejectUnknown {
+					err = errors.New(nhttp.StatusMisdirectedRequest, MisdirectedRequestReason,
+						fmt.Sprintf("host %q is not served here", normalizeHost(host)))
+				} else {
+					reply, err = handler(ctx, req)
+				}
+				h.recordVirtualHostRequest(defaultVirtualHostLabel, route, err)
+				return reply, err
+			}
+			defer func() { h.recordVirtualHostRequest(v.name, route, err) }()
+			if !v.serves(operation, path) {
+				return nil, errors.NotFound(routeNotFoundReason, "route not found")
+			}
+			ctx = context.WithValue(ctx, virtualHostKey{}, v.name)
+			if mws := h.middlewaresOfVirtualHost(v.name); len(mws) > 0 {
+				return middleware.Chain(mws...)(handler)(ctx, req)
+			}
+			return handler(ctx, req)
+		}
+	}
+}
+
+func (h *ServiceHttp) recordVirtualHostRequest(vhost, route string, err error) {
+	if h.virtualHostRequests == nil {
+		return
+	}
+	status := "success"
+	if err != nil {
+		status = "error"
+	}
+	h.virtualHostRequests.WithLabelValues(vhost, route, status).Inc()
+}
+
+// virtualHostCertificate is the certificate presented for the domains of a virtual host.
+type virtualHostCertificate struct {
+	domains []string
+	cert    *tls.Certificate
+}
+
+// loadVirtualHostCertificates reads the certificates of the virtual hosts that have one.
+func loadVirtualHostCertificates(cfg *conf.VirtualHostsConfig) ([]virtualHostCertificate, error) {
+	var certs []virtualHostCertificate
+	for _, hc := range cfg.GetHosts() {
+		if hc.GetTlsCertFile() == "" {
+			continue
+		}
+		cert, err := tls.LoadX509KeyPair(hc.GetTlsCertFile(), hc.GetTlsKeyFile())
+		if err != nil {
+			return nil, fmt.Errorf("virtual host %q: %w", hc.GetName(), err)
+		}
+		vc := virtualHostCertificate{cert: &cert}
+		for _, domain := range hc.GetDomains() {
+			vc.domains = append(vc.domains, normalizeHost(domain))
+		}
+		certs = append(certs, vc)
+	}
+	return certs, nil
+}
+
+// sniCertificateGetter serves the certificate of the virtual host whose domains match the SNI server name,
+// and falls back to get for other names and clients sending none.
+func sniCertificateGetter(certs []virtualHostCertificate,
+	get func(*tls.ClientHelloInfo) (*tls.Certificate, error)) func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if len(certs) == 0 {
+		return get
+	}
+	return func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		if hello != nil && hello.ServerName != "" {
+			name := normalizeHost(hello.ServerName)
+			for _, vc := range certs {
+				for _, domain := range vc.domains {
+					if domainMatches(domain, name) {
+						return vc.cert, nil
+					}
+				}
+			}
+		}
+		return get(hello)
+	}
+}
+
+// checkVirtualHostCertificates reports virtual host certificates that cannot be read, do not match their key
+// or are outside their validity period at now.
+func checkVirtualHostCertificates(cfg *conf.VirtualHostsConfig, now time.Time) []error {
+	var errs []error
+	for _, hc := range cfg.GetHosts() {
+		if hc.GetTlsCertFile() == "" {
+			continue
+		}
+		certPEM, err := os.ReadFile(hc.GetTlsCertFile())
+		if err == nil {
+			var keyPEM []byte
+			if keyPEM, err = os.ReadFile(hc.GetTlsKeyFile()); err == nil {
+				err = checkKeyPair(certPEM, keyPEM, now)
+			}
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("virtual host %q: %w", hc.GetName(), err))
+		}
+	}
+	return errs
+}
+
+// validateVirtualHostsConfig requires unique names and domains, domain patterns that are host names or
+// "*." wildcards, and certificates only with TLS enabled and with their key.
+func validateVirtualHostsConfig(cfg *conf.VirtualHostsConfig, tlsEnabled bool) error {
+	names := make(map[string]struct{})
+	domains := make(map[string]string)
+	for i, hc := range cfg.GetHosts() {
+		name := strings.TrimSpace(hc.GetName())
+		if name == "" {
+			return fmt.Errorf("hosts[%d]: name is required", i)
+		}
+		if _, dup := names[name]; dup {
+			return fmt.Errorf("hosts[%d]: duplicate name %q", i, name)
+		}
+		names[name] = struct{}{}
+		if len(hc.GetDomains()) == 0 {
+			return fmt.Errorf("virtual host %q: at least one domain is required", name)
+		}
+		for _, domain := range hc.GetDomains() {
+			d := normalizeHost(domain)
+			if d == "" || strings.Contains(strings.TrimPrefix(d, "*."), "*") || strings.ContainsAny(d, "/ ") {
+				return fmt.Errorf("virtual host %q: invalid domain %q", name, domain)
+			}
+			if other, dup := domains[d]; dup {
+				return fmt.Errorf("virtual host %q: domain %q is already served by %q", name, domain, other)
+			}
+			domains[d] = name
+		}
+		if (hc.GetTlsCertFile() == "") != (hc.GetTlsKeyFile() == "") {
+			return fmt.Errorf("virtual host %q: tls_cert_file and tls_key_file must be set together", name)
+		}
+		if hc.GetTlsCertFile() != "" && !tlsEnabled {
+			return fmt.Errorf("virtual host %q: tls_cert_file requires tls_enable", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	nhttp "net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/go-lynx/lynx-http/httptesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVirtualHostMiddleware(t *testing.T) {
+	h := NewServiceHttp()
+	policy := newVirtualHostPolicy(&conf.VirtualHostsConfig{Hosts: []*conf.VirtualHostConfig{
+		{Name: "tenant-a", Domains: []string{"api.tenant-a.com", "*.tenant-a.com"}, Operations: []string{"/api.v1.Orders/*"}},
+		{Name: "tenant-b", Domains: []string{"API.tenant-b.com"}},
+	}})
+	var calls []string
+	require.NoError(t, h.UseVirtualHostMiddleware("tenant-a", func(next middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			name, _ := VirtualHostFromContext(ctx)
+			calls = append(calls, name)
+			return next(ctx, req)
+		}
+	}))
+	tester := httptesting.NewMiddlewareTester(t, h.virtualHostMiddleware(policy))
+
+	res := tester.Run(httptesting.Request{Operation: "/api.v1.Orders/List", Path: "http://eu.tenant-a.com:8443/v1/orders"})
+	res.AssertNoError()
+	name, ok := VirtualHostFromContext(res.HandlerContext)
+	assert.True(t, ok)
+	assert.Equal(t, "tenant-a", name)
+	assert.Equal(t, []string{"tenant-a"}, calls)
+
+	tester.Run(httptesting.Request{Operation: "/api.v1.Users/Get", Path: "http://api.tenant-a.com/v1/users/1"}).
+		AssertError(nhttp.StatusNotFound, routeNotFoundReason).AssertHandlerCalled(false)
+
+	res = tester.Run(httptesting.Request{Operation: "/api.v1.Users/Get", Path: "http://api.tenant-b.com/v1/users/1"})
+	res.AssertNoError()
+	name, _ = VirtualHostFromContext(res.HandlerContext)
+	assert.Equal(t, "tenant-b", name)
+	assert.Len(t, calls, 1, "tenant-a middleware does not run for tenant-b")
+
+	res = tester.Run(httptesting.Request{Operation: "/api.v1.Users/Get", Path: "http://tenant-a.com/v1/users/1"})
+	res.AssertNoError()
+	_, ok = VirtualHostFromContext(res.HandlerContext)
+	assert.False(t, ok, "the wildcard does not match the parent domain")
+
+	policy.rejectUnknown = true
+	tester.Run(httptesting.Request{Operation: "/api.v1.Users/Get", Path: "http://tenant-a.com/v1/users/1"}).
+		AssertError(nhttp.StatusMisdirectedRequest, MisdirectedRequestReason).AssertHandlerCalled(false)
+}
+
+func TestVirtualHostSNI(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	certPEM, keyPEM := selfSignedPair(t, now.Add(-time.Hour), now.Add(time.Hour))
+	certFile, keyFile := filepath.Join(dir, "a.crt"), filepath.Join(dir, "a.key")
+	require.NoError(t, os.WriteFile(certFile, certPEM, 0o600))
+	require.NoError(t, os.WriteFile(keyFile, keyPEM, 0o600))
+
+	cfg := &conf.VirtualHostsConfig{Hosts: []*conf.VirtualHostConfig{
+		{Name: "tenant-a", Domains: []string{"*.tenant-a.com"}, TlsCertFile: certFile, TlsKeyFile: keyFile},
+		{Name: "tenant-b", Domains: []string{"api.tenant-b.com"}},
+	}}
+	certs, err := loadVirtualHostCertificates(cfg)
+	require.NoError(t, err)
+	fallback := &tls.Certificate{}
+	get := sniCertificateGetter(certs, func(*tls.ClientHelloInfo) (*tls.Certificate, error) { return fallback, nil })
+
+	cert, err := get(&tls.ClientHelloInfo{ServerName: "API.tenant-a.com"})
+	require.NoError(t, err)
+	assert.Same(t, certs[0].cert, cert)
+	cert, err = get(&tls.ClientHelloInfo{ServerName: "api.tenant-b.com"})
+	require.NoError(t, err)
+	assert.Same(t, fallback, cert)
+	cert, err = get(&tls.ClientHelloInfo{})
+	require.NoError(t, err)
+	assert.Same(t, fallback, cert)
+
+	assert.Empty(t, checkVirtualHostCertificates(cfg, now))
+	errs := checkVirtualHostCertificates(cfg, now.Add(2*time.Hour))
+	require.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], `virtual host "tenant-a": certificate "preflight" expired`)
+}
+
+func TestValidateVirtualHostsConfig(t *testing.T) {
+	host := func(name string, domains ...string) *conf.VirtualHostConfig {
+		return &conf.VirtualHostConfig{Name: name, Domains: domains}
+	}
+	validate := func(tlsEnabled bool, hosts ...*conf.VirtualHostConfig) error {
+		return validateVirtualHostsConfig(&conf.VirtualHostsConfig{Hosts: hosts}, tlsEnabled)
+	}
+	require.NoError(t, validateVirtualHostsConfig(nil, false))
+	require.NoError(t, validate(false, host("a", "a.com", "*.a.com"), host("b", "b.com")))
+	assert.ErrorContains(t, validate(false, host("", "a.com")), "name is required")
+	assert.ErrorContains(t, validate(false, host("a", "a.com"), host("a", "b.com")), `duplicate name "a"`)
+	assert.ErrorContains(t, validate(false, host("a")), "at least one domain")
+	assert.ErrorContains(t, validate(false, host("a", "api.*.com")), "invalid domain")
+	assert.ErrorContains(t, validate(false, host("a", "a.com"), host("b", "A.com")), `already served by "a"`)
+
+	withCert := host("a", "a.com")
+	withCert.TlsCertFile = "a.crt"
+	assert.ErrorContains(t, validate(true, withCert), "must be set together")
+	withCert.TlsKeyFile = "a.key"
+	assert.ErrorContains(t, validate(false, withCert), "requires tls_enable")
+	require.NoError(t, validate(true, withCert))
+}
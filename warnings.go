@@ -0,0 +1,99 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	nhttp "net/http"
+	"sync"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+)
+
+const (
+	// WarningDeprecatedParameter marks a request that used a deprecated parameter.
+	WarningDeprecatedParameter = "DEPRECATED_PARAMETER"
+	// WarningPartialData marks a reply missing data that could not be loaded.
+	WarningPartialData = "PARTIAL_DATA"
+
+	defaultMaxWarnings = 20
+	maxMaxWarnings     = 1000
+)
+
+// Warning is a non-fatal notice attached to a successful reply.
+type Warning struct {
+	// Code identifies the kind of notice, e.g. WarningDeprecatedParameter.
+	Code string `json:"code"`
+	// Message describes it for developers.
+	Message string `json:"message,omitempty"`
+}
+
+// warningsKey stores the *responseWarnings of a request in its context.
+type warningsKey struct{}
+
+// responseWarnings collects the warnings of one request.
+type responseWarnings struct {
+	max int
+
+	mu       sync.Mutex
+	warnings []Warning
+}
+
+func (w *responseWarnings) add(warning Warning) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, existing := range w.warnings {
+		if existing == warning {
+			return
+		}
+	}
+	if len(w.warnings) < w.max {
+		w.warnings = append(w.warnings, warning)
+	}
+}
+
+func (w *responseWarnings) list() []Warning {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]Warning(nil), w.warnings...)
+}
+
+// AddWarning attaches a notice to the successful reply of the current request, e.g.
+// AddWarning(ctx, WarningDeprecatedParameter, `"limit" is deprecated, use "page_size"`). Repeated warnings are
+// listed once. It does nothing unless response.warnings is enabled, and errors carry no warnings.
+func AddWarning(ctx context.Context, code, message string) {
+	if w, ok := ctx.Value(warningsKey{}).(*responseWarnings); ok {
+		w.add(Warning{Code: code, Message: message})
+	}
+}
+
+// Warnings returns the warnings attached to the current request so far.
+func Warnings(ctx context.Context) []Warning {
+	if w, ok := ctx.Value(warningsKey{}).(*responseWarnings); ok {
+		return w.list()
+	}
+	return nil
+}
+
+// warningsFilter gives each request a warning list before routing, so handlers and the encoders share it
+// through the request context.
+func warningsFilter(cfg *conf.WarningsConfig) http.FilterFunc {
+	limit := int(cfg.GetMaxWarnings())
+	if limit <= 0 {
+		limit = defaultMaxWarnings
+	}
+	return func(next nhttp.Handler) nhttp.Handler {
+		return nhttp.HandlerFunc(func(w nhttp.ResponseWriter, r *nhttp.Request) {
+			warnings := &responseWarnings{max: limit}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), warningsKey{}, warnings)))
+		})
+	}
+}
+
+// validateWarningsConfig bounds max_warnings.
+func validateWarningsConfig(cfg *conf.WarningsConfig) error {
+	if n := cfg.GetMaxWarnings(); n < 0 || n > maxMaxWarnings {
+		return fmt.Errorf("max_warnings must be between 0 and %d", maxMaxWarnings)
+	}
+	return nil
+}
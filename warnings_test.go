@@ -0,0 +1,83 @@
+package http
+
+import (
+	"context"
+	nhttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/go-lynx/lynx-http/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/apipb"
+)
+
+// warningsServer serves GET /users, whose handler attaches two warnings, through encode.
+func warningsServer(cfg *conf.WarningsConfig, encode http.EncodeResponseFunc) *http.Server {
+	opts := []http.ServerOption{http.ResponseEncoder(encode)}
+	if cfg.GetEnabled() {
+		opts = append(opts, http.Filter(warningsFilter(cfg)))
+	}
+	srv := http.NewServer(opts...)
+	srv.Route("/").GET("/users", func(ctx http.Context) error {
+		h := ctx.Middleware(func(ctx context.Context, _ any) (any, error) {
+			AddWarning(ctx, WarningDeprecatedParameter, `"limit" is deprecated, use "page_size"`)
+			AddWarning(ctx, WarningPartialData, "avatars unavailable")
+			AddWarning(ctx, WarningDeprecatedParameter, `"limit" is deprecated, use "page_size"`)
+			return &apipb.Api{Name: "users"}, nil
+		})
+		reply, err := h(ctx, nil)
+		if err != nil {
+			return err
+		}
+		return ctx.Result(nhttp.StatusOK, reply)
+	})
+	return srv
+}
+
+func TestWarnings(t *testing.T) {
+	get := func(srv *http.Server, accept string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(nhttp.MethodGet, "/users", nil)
+		req.Header.Set("Accept", accept)
+		srv.ServeHTTP(rec, req)
+		return rec
+	}
+
+	srv := warningsServer(&conf.WarningsConfig{Enabled: true}, ResponseEncoder)
+	rec := get(srv, "application/json")
+	assert.JSONEq(t, `{"code":200,"data":{"name":"users"},"warnings":[
+		{"code":"DEPRECATED_PARAMETER","message":"\"limit\" is deprecated, use \"page_size\""},
+		{"code":"PARTIAL_DATA","message":"avatars unavailable"}]}`, rec.Body.String())
+	var warnings []Warning
+	api, err := DecodeBody[*apipb.Api]("application/json", rec.Body.Bytes(), WithWarnings(&warnings))
+	require.NoError(t, err)
+	assert.Equal(t, "users", api.GetName())
+	assert.Equal(t, []Warning{
+		{Code: WarningDeprecatedParameter, Message: `"limit" is deprecated, use "page_size"`},
+		{Code: WarningPartialData, Message: "avatars unavailable"},
+	}, warnings)
+
+	// Protobuf envelopes carry them too.
+	encode := withProtobuf(newProtobufPolicy(&conf.ProtobufResponseConfig{Enabled: true, Envelope: true}, nhttp.StatusOK), ResponseEncoder)
+	rec = get(warningsServer(&conf.WarningsConfig{Enabled: true, MaxWarnings: 1}, encode), ProtobufContentType)
+	warnings = nil
+	api, err = DecodeBody[*apipb.Api](rec.Header().Get("Content-Type"), rec.Body.Bytes(), WithWarnings(&warnings))
+	require.NoError(t, err)
+	assert.Equal(t, "users", api.GetName())
+	assert.Equal(t, []Warning{{Code: WarningDeprecatedParameter, Message: `"limit" is deprecated, use "page_size"`}}, warnings,
+		"max_warnings caps the list")
+
+	// Without the filter, AddWarning does nothing.
+	rec = get(warningsServer(&conf.WarningsConfig{}, ResponseEncoder), "application/json")
+	assert.JSONEq(t, `{"code":200,"data":{"name":"users"}}`, rec.Body.String())
+	assert.Nil(t, Warnings(context.Background()))
+}
+
+func TestValidateWarningsConfig(t *testing.T) {
+	require.NoError(t, validateWarningsConfig(nil))
+	require.NoError(t, validateWarningsConfig(&conf.WarningsConfig{Enabled: true, MaxWarnings: 50}))
+	assert.ErrorContains(t, validateWarningsConfig(&conf.WarningsConfig{MaxWarnings: -1}), "max_warnings")
+	assert.ErrorContains(t, validateWarningsConfig(&conf.WarningsConfig{MaxWarnings: 1001}), "max_warnings")
+}